@@ -46,6 +46,7 @@ var imageCmd = &cobra.Command{
 func init() {
 	imageCmd.Flags().StringVar(&outputArg, "output", "/out", "The output path")
 	imageCmd.Flags().BoolVar(&tarToStdout, "tar-to-stdout", false, "Tar output and send to stdout")
+	imageCmd.Flags().StringVar(&options.ImageCache, "image-cache", "", "The path to the directory with image archives to bake into the image cache partition")
 	rootCmd.AddCommand(imageCmd)
 }
 
@@ -62,9 +63,19 @@ func runImageCmd() (err error) {
 
 	log.Printf("creating image for %s", p.Name())
 
+	var imageCacheSize uint64
+
+	if options.ImageCache != "" {
+		if imageCacheSize, err = install.ImageCacheSize(options.ImageCache); err != nil {
+			return err
+		}
+
+		log.Printf("reserving %d MiB for the image cache", imageCacheSize/install.MiB)
+	}
+
 	log.Print("creating RAW disk")
 
-	img, err := pkg.CreateRawDisk()
+	img, err := pkg.CreateRawDisk(imageCacheSize / install.MiB)
 	if err != nil {
 		return err
 	}
@@ -5,6 +5,7 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -12,13 +13,15 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"text/template"
 
 	"github.com/spf13/cobra"
 
 	"github.com/talos-systems/talos/cmd/installer/pkg"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
-var cfg = []byte(`set default=0
+var cfgTemplate = template.Must(template.New("grub.cfg").Parse(`set default=0
 set timeout=0
 
 insmod all_video
@@ -29,9 +32,11 @@ terminal_output console
 menuentry "Talos ISO" {
 	set gfxmode=auto
 	set gfxpayload=text
-	linux /boot/vmlinuz init_on_alloc=1 init_on_free=1 slab_nomerge pti=on panic=0 consoleblank=0 printk.devkmsg=on earlyprintk=ttyS0 console=tty0 console=ttyS0 talos.platform=metal
+	linux /boot/vmlinuz init_on_alloc=1 init_on_free=1 slab_nomerge pti=on panic=0 consoleblank=0 printk.devkmsg=on earlyprintk=ttyS0 console=tty0 console=ttyS0 talos.platform=metal{{ range .ExtraKernelArgs }} {{ . }}{{ end }}
 	initrd /boot/initramfs.xz
-}`)
+}`))
+
+var isoConfigFile string
 
 // isoCmd represents the iso command.
 var isoCmd = &cobra.Command{
@@ -48,6 +53,7 @@ var isoCmd = &cobra.Command{
 func init() {
 	isoCmd.Flags().StringVar(&outputArg, "output", "/out", "The output path")
 	isoCmd.Flags().BoolVar(&tarToStdout, "tar-to-stdout", false, "Tar output and send to stdout")
+	isoCmd.Flags().StringVar(&isoConfigFile, "config-file", "", "The path to the machine config to embed into the ISO")
 	rootCmd.AddCommand(isoCmd)
 }
 
@@ -89,15 +95,45 @@ func runISOCmd() error {
 		}
 	}
 
+	extraKernelArgs := options.ExtraKernelArgs
+	volumeLabel := ""
+
+	if isoConfigFile != "" {
+		log.Printf("embedding machine config %s", isoConfigFile)
+
+		config, err := ioutil.ReadFile(isoConfigFile)
+		if err != nil {
+			return err
+		}
+
+		// metal platform reads the config from the filesystem labeled as metal-iso
+		if err = ioutil.WriteFile(filepath.Join("/mnt", filepath.Base(constants.ConfigPath)), config, 0o600); err != nil {
+			return err
+		}
+
+		extraKernelArgs = append(extraKernelArgs, fmt.Sprintf("%s=%s", constants.KernelParamConfig, constants.MetalConfigISOLabel))
+		volumeLabel = constants.MetalConfigISOLabel
+	}
+
 	log.Println("creating grub.cfg")
 
+	var cfg bytes.Buffer
+
+	if err := cfgTemplate.Execute(&cfg, struct {
+		ExtraKernelArgs []string
+	}{
+		ExtraKernelArgs: extraKernelArgs,
+	}); err != nil {
+		return err
+	}
+
 	cfgPath := "/mnt/boot/grub/grub.cfg"
 
 	if err := os.MkdirAll(filepath.Dir(cfgPath), 0o755); err != nil {
 		return err
 	}
 
-	if err := ioutil.WriteFile(cfgPath, cfg, 0o666); err != nil {
+	if err := ioutil.WriteFile(cfgPath, cfg.Bytes(), 0o666); err != nil {
 		return err
 	}
 
@@ -105,7 +141,7 @@ func runISOCmd() error {
 
 	out := fmt.Sprintf("/tmp/talos-%s.iso", runtime.GOARCH)
 
-	if err := pkg.CreateISO(out, "/mnt"); err != nil {
+	if err := pkg.CreateISO(out, "/mnt", volumeLabel); err != nil {
 		return err
 	}
 
@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/talos-systems/talos/cmd/installer/pkg/install"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
@@ -42,4 +43,11 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&options.Upgrade, "upgrade", false, "Indicates that the install is being performed by an upgrade")
 	rootCmd.PersistentFlags().BoolVar(&options.Force, "force", false, "Indicates that the install should forcefully format the partition")
 	rootCmd.PersistentFlags().BoolVar(&options.Zero, "zero", false, "Indicates that the install should write zeros to the disk before installing")
+	rootCmd.PersistentFlags().StringVar(&options.EphemeralDisk, "ephemeral-disk", "", "The path to the disk for the EPHEMERAL partition (defaults to the install disk)")
+	rootCmd.PersistentFlags().Uint64Var(&options.EphemeralSize, "ephemeral-size", 0, "The size of the EPHEMERAL partition in bytes (defaults to the rest of the disk)")
+	rootCmd.PersistentFlags().StringVar(&options.SerialConsole, "serial-console", "", "The serial console device (e.g. ttyS0), replaces the serial consoles of the platform")
+	rootCmd.PersistentFlags().IntVar(&options.SerialBaudRate, "serial-baud-rate", constants.DefaultSerialBaudRate, "The serial console baud rate")
+	rootCmd.PersistentFlags().BoolVar(&options.DisableVGA, "disable-vga", false, "Disable the console output to the VGA console")
+	rootCmd.PersistentFlags().StringVar(&options.BootloaderType, "bootloader-type", bootloader.TypeAuto, "The bootloader to install (auto, grub, sd-boot), auto keeps the bootloader on upgrade and picks sd-boot for UEFI installs")
+	rootCmd.PersistentFlags().BoolVar(&options.EnrollSecureBootKeys, "enroll-secureboot-keys", false, "Enroll the SecureBoot keys of the installer image if the firmware is in setup mode")
 }
@@ -0,0 +1,161 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/talos-systems/talos/cmd/installer/pkg/install"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/sdboot"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/platform"
+	"github.com/talos-systems/talos/internal/pkg/secureboot"
+	"github.com/talos-systems/talos/pkg/version"
+)
+
+var secureBootKeysArg string
+
+// secureBootCmd represents the secureboot command.
+var secureBootCmd = &cobra.Command{
+	Use:   "secureboot",
+	Short: "Builds the signed SecureBoot assets of the installer image",
+	Long: `Builds the unified kernel image of the installer image kernel and initramfs, and signs it
+and systemd-boot with the db key. If PK and KEK keys are present, the key enrollment updates
+are created as well.
+
+The keys directory should contain PEM-encoded db.crt and db.key, optionally PK.crt, PK.key, KEK.crt and KEK.key.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runSecureBootCmd(); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	secureBootCmd.Flags().StringVar(&secureBootKeysArg, "keys", "", "The path to the directory with the SecureBoot keys")
+	rootCmd.AddCommand(secureBootCmd)
+}
+
+// nolint: gocyclo
+func runSecureBootCmd() error {
+	if secureBootKeysArg == "" {
+		return fmt.Errorf("--keys is required")
+	}
+
+	db, err := secureboot.LoadSigner(filepath.Join(secureBootKeysArg, "db.crt"), filepath.Join(secureBootKeysArg, "db.key"))
+	if err != nil {
+		return err
+	}
+
+	if options.Platform == "" {
+		options.Platform = "metal"
+	}
+
+	p, err := platform.NewPlatform(options.Platform)
+	if err != nil {
+		return err
+	}
+
+	cmdline, err := install.Cmdline(p, options)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(sdboot.SecureBootAssetsDir, 0o755); err != nil {
+		return err
+	}
+
+	log.Printf("building unified kernel image with kernel command line %q", cmdline.String())
+
+	unsigned := filepath.Join(sdboot.SecureBootAssetsDir, "unsigned.efi")
+
+	if err = sdboot.BuildImage(version.Tag, cmdline.String(), "/usr/install/vmlinuz", "/usr/install/initramfs.xz", unsigned); err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer os.Remove(unsigned)
+
+	for src, dst := range map[string]string{
+		unsigned:                    sdboot.SignedImageAssetPath,
+		sdboot.SystemdBootAssetPath: sdboot.SignedSystemdBootAssetPath,
+	} {
+		if err = signPE(db, src, dst); err != nil {
+			return err
+		}
+	}
+
+	if _, err = os.Stat(filepath.Join(secureBootKeysArg, "PK.key")); os.IsNotExist(err) {
+		log.Printf("PK key is not present, skipping the key enrollment updates")
+
+		return nil
+	}
+
+	pk, err := secureboot.LoadSigner(filepath.Join(secureBootKeysArg, "PK.crt"), filepath.Join(secureBootKeysArg, "PK.key"))
+	if err != nil {
+		return err
+	}
+
+	kek, err := secureboot.LoadSigner(filepath.Join(secureBootKeysArg, "KEK.crt"), filepath.Join(secureBootKeysArg, "KEK.key"))
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now()
+
+	for _, v := range []struct {
+		path   string
+		name   string
+		vendor secureboot.GUID
+		// signer signs the update, enrolled is the certificate being enrolled
+		signer   *secureboot.Signer
+		enrolled *secureboot.Signer
+	}{
+		{sdboot.PKAuthAssetPath, secureboot.PK, secureboot.GlobalVariableGUID, pk, pk},
+		{sdboot.KEKAuthAssetPath, secureboot.KEK, secureboot.GlobalVariableGUID, pk, kek},
+		{sdboot.DBAuthAssetPath, secureboot.DB, secureboot.ImageSecurityDatabaseGUID, kek, db},
+	} {
+		list := &secureboot.SignatureDatabase{Certificates: []*x509.Certificate{v.enrolled.Certificate}}
+
+		var update []byte
+
+		update, err = v.signer.SignVariable(v.name, v.vendor, list.Marshal(secureboot.OwnerGUID), timestamp)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("writing %s", v.path)
+
+		if err = ioutil.WriteFile(v.path, update, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func signPE(signer *secureboot.Signer, src, dst string) error {
+	image, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	signed, err := signer.SignPE(image)
+	if err != nil {
+		return fmt.Errorf("error signing %s: %w", src, err)
+	}
+
+	log.Printf("writing %s", dst)
+
+	return ioutil.WriteFile(dst, signed, 0o644)
+}
@@ -0,0 +1,74 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package install
+
+import (
+	"fmt"
+	"log"
+	"os"
+	goruntime "runtime"
+	"strings"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/sdboot"
+	"github.com/talos-systems/talos/internal/pkg/secureboot"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// selectBootloader picks the bootloader type to install.
+//
+// Upgrades keep the installed bootloader, as the bootloaders use different partition
+// layouts and fallback to the current installation requires it to be bootable.
+// Fresh installs use systemd-boot when booted in UEFI mode and grub otherwise.
+func selectBootloader(seq runtime.Sequence, installed string, opts *Options) (string, error) {
+	requested := opts.BootloaderType
+	if requested == "" {
+		requested = bootloader.TypeAuto
+	}
+
+	if seq == runtime.SequenceUpgrade && installed != "" {
+		if requested != bootloader.TypeAuto && requested != installed {
+			return "", fmt.Errorf("bootloader %q can't be replaced with %q on upgrade, reinstall is required", installed, requested)
+		}
+
+		return installed, nil
+	}
+
+	if requested != bootloader.TypeAuto {
+		return requested, nil
+	}
+
+	if sdbootSupported(opts) {
+		return bootloader.TypeSDBoot, nil
+	}
+
+	return bootloader.TypeGrub, nil
+}
+
+// sdbootSupported checks whether systemd-boot can boot the installation.
+//
+// Disk images (installed to loop devices) should boot both in BIOS and UEFI mode,
+// so they are always installed with grub.
+func sdbootSupported(opts *Options) bool {
+	if goruntime.GOARCH != "amd64" || opts.Board != constants.BoardNone || strings.HasPrefix(opts.Disk, "/dev/loop") {
+		return false
+	}
+
+	for _, asset := range []string{sdboot.SystemdBootAssetPath, sdboot.SystemdStubAssetPath} {
+		if _, err := os.Stat(asset); err != nil {
+			return false
+		}
+	}
+
+	state, err := secureboot.GetState()
+	if err != nil {
+		log.Printf("warning: failed to read EFI state: %s", err)
+
+		return false
+	}
+
+	return state.EFI
+}
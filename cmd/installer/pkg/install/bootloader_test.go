@@ -0,0 +1,76 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package install
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+func TestSelectBootloader(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		seq       runtime.Sequence
+		installed string
+		requested string
+		expected  string
+		err       string
+	}{
+		{
+			name:      "upgrade keeps installed",
+			seq:       runtime.SequenceUpgrade,
+			installed: bootloader.TypeSDBoot,
+			expected:  bootloader.TypeSDBoot,
+		},
+		{
+			name:      "upgrade with explicit type",
+			seq:       runtime.SequenceUpgrade,
+			installed: bootloader.TypeGrub,
+			requested: bootloader.TypeGrub,
+			expected:  bootloader.TypeGrub,
+		},
+		{
+			name:      "upgrade replacing bootloader",
+			seq:       runtime.SequenceUpgrade,
+			installed: bootloader.TypeGrub,
+			requested: bootloader.TypeSDBoot,
+			err:       "bootloader \"grub\" can't be replaced with \"sd-boot\" on upgrade, reinstall is required",
+		},
+		{
+			name:      "reinstall",
+			seq:       runtime.SequenceInstall,
+			installed: bootloader.TypeGrub,
+			requested: bootloader.TypeSDBoot,
+			expected:  bootloader.TypeSDBoot,
+		},
+		{
+			name:     "auto without systemd-boot assets",
+			seq:      runtime.SequenceInstall,
+			expected: bootloader.TypeGrub,
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			typ, err := selectBootloader(tt.seq, tt.installed, &Options{
+				Disk:           "/dev/sda",
+				Board:          constants.BoardNone,
+				BootloaderType: tt.requested,
+			})
+
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, typ)
+			}
+		})
+	}
+}
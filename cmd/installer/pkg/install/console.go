@@ -0,0 +1,79 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package install
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/talos-systems/go-procfs/procfs"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/options"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+var (
+	vgaConsoleRegexp      = regexp.MustCompile(`^tty[0-9]+$`)
+	pcSerialConsoleRegexp = regexp.MustCompile(`^ttyS([0-9]+)$`)
+)
+
+// Console returns the console kernel parameter based on the console parameter of the platform.
+//
+// Serial console replaces the serial consoles of the platform, and it goes last,
+// so that it becomes the primary console (/dev/console).
+func (opts *Options) Console(platform *procfs.Parameter) *procfs.Parameter {
+	console := procfs.NewParameter("console")
+
+	for idx := 0; platform.Get(idx) != nil; idx++ {
+		value := *platform.Get(idx)
+		device := strings.SplitN(value, ",", 2)[0]
+
+		if vgaConsoleRegexp.MatchString(device) {
+			if opts.DisableVGA {
+				continue
+			}
+		} else if opts.SerialConsole != "" {
+			continue
+		}
+
+		console.Append(value)
+	}
+
+	if opts.SerialConsole != "" {
+		console.Append(fmt.Sprintf("%s,%dn8", opts.SerialConsole, opts.baudRate()))
+	}
+
+	return console
+}
+
+// GrubSerial returns the serial terminal settings for grub.
+//
+// Grub supports only PC serial ports, so other serial consoles are ignored.
+func (opts *Options) GrubSerial() *options.Serial {
+	matches := pcSerialConsoleRegexp.FindStringSubmatch(opts.SerialConsole)
+	if matches == nil {
+		return nil
+	}
+
+	unit, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return nil
+	}
+
+	return &options.Serial{
+		Unit:  unit,
+		Speed: opts.baudRate(),
+	}
+}
+
+func (opts *Options) baudRate() int {
+	if opts.SerialBaudRate == 0 {
+		return constants.DefaultSerialBaudRate
+	}
+
+	return opts.SerialBaudRate
+}
@@ -0,0 +1,58 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package install_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/talos-systems/go-procfs/procfs"
+
+	"github.com/talos-systems/talos/cmd/installer/pkg/install"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/options"
+)
+
+func TestConsole(t *testing.T) {
+	platform := procfs.NewParameter("console").Append("ttyS0").Append("tty0")
+
+	for _, tt := range []struct {
+		name     string
+		options  install.Options
+		expected string
+		serial   *options.Serial
+	}{
+		{
+			name:     "serial",
+			options:  install.Options{SerialConsole: "ttyS1"},
+			expected: "console=tty0 console=ttyS1,115200n8",
+			serial:   &options.Serial{Unit: 1, Speed: 115200},
+		},
+		{
+			name:     "serial without vga",
+			options:  install.Options{SerialConsole: "ttyS0", SerialBaudRate: 9600, DisableVGA: true},
+			expected: "console=ttyS0,9600n8",
+			serial:   &options.Serial{Unit: 0, Speed: 9600},
+		},
+		{
+			name:     "arm serial",
+			options:  install.Options{SerialConsole: "ttyAMA0", SerialBaudRate: 115200},
+			expected: "console=tty0 console=ttyAMA0,115200n8",
+		},
+		{
+			name:     "no vga",
+			options:  install.Options{DisableVGA: true},
+			expected: "console=ttyS0",
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, procfs.Parameters{tt.options.Console(platform)}.String())
+			assert.Equal(t, tt.serial, tt.options.GrubSerial())
+		})
+	}
+
+	assert.Equal(t, "console=ttyS1,115200n8", procfs.Parameters{(&install.Options{SerialConsole: "ttyS1"}).Console(nil)}.String())
+}
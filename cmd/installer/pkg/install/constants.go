@@ -23,6 +23,7 @@ type FileSystemType = string
 const (
 	FilesystemTypeNone FileSystemType = "none"
 	FilesystemTypeXFS  FileSystemType = "xfs"
+	FilesystemTypeExt4 FileSystemType = "ext4"
 	FilesystemTypeVFAT FileSystemType = "vfat"
 )
 
@@ -35,4 +36,8 @@ const (
 	BootSize     = 300 * MiB
 	MetaSize     = 1 * MiB
 	StateSize    = 100 * MiB
+
+	// EFISDBootSize is the size of the EFI partition with systemd-boot, which holds
+	// unified kernel images for both current and next installations.
+	EFISDBootSize = 512 * MiB
 )
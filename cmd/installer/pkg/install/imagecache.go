@@ -0,0 +1,96 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package install
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/talos-systems/go-blockdevice/blockdevice/probe"
+
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// ImageCacheOverhead is the space reserved for the filesystem metadata on the image cache partition.
+const ImageCacheOverhead = 64 * MiB
+
+// ImageCacheAssets builds the list of image archives to copy to the image cache partition.
+//
+// Every file with the .tar extension in the directory is treated as an image archive
+// (as produced by `docker save` or `ctr images export`).
+func ImageCacheAssets(dir string) ([]*Asset, error) {
+	files, err := imageCacheFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	assets := make([]*Asset, 0, len(files))
+
+	for _, file := range files {
+		assets = append(assets, &Asset{
+			Source:      filepath.Join(dir, file.Name()),
+			Destination: filepath.Join(constants.ImageCacheMountPoint, file.Name()),
+		})
+	}
+
+	return assets, nil
+}
+
+// ImageCacheSize calculates the size of the image cache partition for the image archives in the directory.
+//
+// Size is rounded up to MiB.
+func ImageCacheSize(dir string) (uint64, error) {
+	files, err := imageCacheFiles(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var size uint64
+
+	for _, file := range files {
+		size += uint64(file.Size())
+	}
+
+	// leave 10% of slack for filesystem allocation
+	size += size/10 + ImageCacheOverhead
+
+	return (size + MiB - 1) / MiB * MiB, nil
+}
+
+func imageCachePartitionExists(disk string) bool {
+	dev, err := probe.DevForFileSystemLabel(disk, constants.ImageCachePartitionLabel)
+	if err != nil {
+		return false
+	}
+
+	//nolint: errcheck
+	dev.Close()
+
+	return true
+}
+
+func imageCacheFiles(dir string) ([]os.FileInfo, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading image cache directory: %w", err)
+	}
+
+	archives := []os.FileInfo{}
+
+	for _, file := range files {
+		if file.Mode().IsRegular() && strings.HasSuffix(file.Name(), ".tar") {
+			archives = append(archives, file)
+		}
+	}
+
+	if len(archives) == 0 {
+		return nil, fmt.Errorf("no image archives found in %q", dir)
+	}
+
+	return archives, nil
+}
@@ -0,0 +1,52 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package install_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/cmd/installer/pkg/install"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+func TestImageCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	_, err = install.ImageCacheAssets(dir)
+	assert.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "etcd.tar"), make([]byte, 10*install.MiB), 0o644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "kubelet.tar"), make([]byte, 5*install.MiB+1), 0o644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "SHA256SUMS"), []byte("checksums"), 0o644))
+
+	assets, err := install.ImageCacheAssets(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*install.Asset{
+		{
+			Source:      filepath.Join(dir, "etcd.tar"),
+			Destination: filepath.Join(constants.ImageCacheMountPoint, "etcd.tar"),
+		},
+		{
+			Source:      filepath.Join(dir, "kubelet.tar"),
+			Destination: filepath.Join(constants.ImageCacheMountPoint, "kubelet.tar"),
+		},
+	}, assets)
+
+	size, err := install.ImageCacheSize(dir)
+	require.NoError(t, err)
+
+	// 15 MiB + 1 byte, 10% slack, overhead, rounded up to MiB
+	assert.EqualValues(t, 81*install.MiB, size)
+}
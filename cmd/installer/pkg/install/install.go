@@ -6,8 +6,9 @@ package install
 
 import (
 	"fmt"
+	"io/ioutil"
 	"log"
-	"path/filepath"
+	goruntime "runtime"
 
 	"github.com/talos-systems/go-blockdevice/blockdevice/probe"
 	"github.com/talos-systems/go-procfs/procfs"
@@ -18,6 +19,7 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/adv"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/grub"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/options"
 	"github.com/talos-systems/talos/internal/pkg/mount"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 	"github.com/talos-systems/talos/pkg/machinery/kernel"
@@ -35,25 +37,30 @@ type Options struct {
 	Upgrade         bool
 	Force           bool
 	Zero            bool
+	ImageCache      string
+	EphemeralDisk   string
+	EphemeralSize   uint64
+	SerialConsole   string
+	SerialBaudRate  int
+	DisableVGA      bool
+	BootloaderType  string
+
+	EnrollSecureBootKeys bool
 }
 
-// Install installs Talos.
-func Install(p runtime.Platform, seq runtime.Sequence, opts *Options) (err error) {
-	cmdline := procfs.NewCmdline("")
-	cmdline.Append(constants.KernelParamPlatform, p.Name())
-
-	if opts.ConfigSource != "" {
-		cmdline.Append(constants.KernelParamConfig, opts.ConfigSource)
+// EphemeralDevice returns the disk for the EPHEMERAL partition.
+func (opts *Options) EphemeralDevice() string {
+	if opts.EphemeralDisk == "" {
+		return opts.Disk
 	}
 
-	cmdline.SetAll(p.KernelArgs().Strings())
-
-	// first defaults, then extra kernel args to allow extra kernel args to override defaults
-	if err = cmdline.AppendAll(kernel.DefaultArgs); err != nil {
-		return err
-	}
+	return opts.EphemeralDisk
+}
 
-	if err = cmdline.AppendAll(opts.ExtraKernelArgs); err != nil {
+// Install installs Talos.
+func Install(p runtime.Platform, seq runtime.Sequence, opts *Options) (err error) {
+	cmdline, err := Cmdline(p, opts)
+	if err != nil {
 		return err
 	}
 
@@ -71,6 +78,33 @@ func Install(p runtime.Platform, seq runtime.Sequence, opts *Options) (err error
 	return nil
 }
 
+// Cmdline builds the kernel command line of the installation for the platform.
+func Cmdline(p runtime.Platform, opts *Options) (*procfs.Cmdline, error) {
+	cmdline := procfs.NewCmdline("")
+	cmdline.Append(constants.KernelParamPlatform, p.Name())
+
+	if opts.ConfigSource != "" {
+		cmdline.Append(constants.KernelParamConfig, opts.ConfigSource)
+	}
+
+	cmdline.SetAll(p.KernelArgs().Strings())
+
+	if opts.SerialConsole != "" || opts.DisableVGA {
+		cmdline.Set("console", opts.Console(cmdline.Get("console")))
+	}
+
+	// first defaults, then extra kernel args to allow extra kernel args to override defaults
+	if err := cmdline.AppendAll(kernel.DefaultArgs); err != nil {
+		return nil, err
+	}
+
+	if err := cmdline.AppendAll(opts.ExtraKernelArgs); err != nil {
+		return nil, err
+	}
+
+	return cmdline, nil
+}
+
 // Installer represents the installer logic. It serves as the entrypoint to all
 // installation methods.
 type Installer struct {
@@ -92,12 +126,9 @@ func NewInstaller(cmdline *procfs.Cmdline, seq runtime.Sequence, opts *Options)
 	i = &Installer{
 		cmdline: cmdline,
 		options: opts,
-		bootloader: &grub.Grub{
-			BootDisk: opts.Disk,
-		},
 	}
 
-	if err = i.probeBootPartition(); err != nil {
+	if err = i.probeBootPartition(seq); err != nil {
 		return nil, err
 	}
 
@@ -109,8 +140,12 @@ func NewInstaller(cmdline *procfs.Cmdline, seq runtime.Sequence, opts *Options)
 	return i, nil
 }
 
-// Verify existence of boot partition.
-func (i *Installer) probeBootPartition() error {
+// Verify existence of boot partition and pick the bootloader.
+//
+// nolint: gocyclo
+func (i *Installer) probeBootPartition(seq runtime.Sequence) error {
+	installed := ""
+
 	// there's no reason to discover boot partition if the disk is about to be wiped
 	if !i.options.Zero {
 		if dev, err := probe.DevForFileSystemLabel(i.options.Disk, constants.BootPartitionLabel); err != nil {
@@ -131,6 +166,25 @@ func (i *Installer) probeBootPartition() error {
 				log.Printf("warning: failed to mount boot partition %q: %s", dev.Path, err)
 			} else {
 				defer mount.Unmount(mountpoints) //nolint: errcheck
+
+				// systemd-boot config is stored on the EFI partition
+				if efiDev, err := probe.DevForFileSystemLabel(i.options.Disk, constants.EFIPartitionLabel); err == nil {
+					//nolint: errcheck
+					defer efiDev.Close()
+
+					efiMountpoints := mount.NewMountPoints()
+
+					efiMountpoint := mount.NewMountPoint(efiDev.Path, constants.EFIMountPoint, efiDev.SuperBlock.Type(), unix.MS_NOATIME|unix.MS_RDONLY, "")
+					efiMountpoints.Set(constants.EFIPartitionLabel, efiMountpoint)
+
+					if err := mount.Mount(efiMountpoints); err != nil {
+						log.Printf("warning: failed to mount EFI partition %q: %s", efiDev.Path, err)
+					} else {
+						defer mount.Unmount(efiMountpoints) //nolint: errcheck
+					}
+				}
+
+				installed = bootloader.Probe()
 			}
 		}
 
@@ -149,6 +203,16 @@ func (i *Installer) probeBootPartition() error {
 
 	var err error
 
+	if i.options.BootloaderType, err = selectBootloader(seq, installed, i.options); err != nil {
+		return err
+	}
+
+	if i.bootloader, err = bootloader.New(i.options.BootloaderType); err != nil {
+		return err
+	}
+
+	log.Printf("using %s bootloader", i.options.BootloaderType)
+
 	// anyways run the Labels() to get the defaults initialized
 	i.Current, i.Next, err = i.bootloader.Labels()
 
@@ -173,6 +237,10 @@ func (i *Installer) Install(seq runtime.Sequence) (err error) {
 		i.cmdline.SetAll(b.KernelArgs().Strings())
 	}
 
+	if err = i.verifySecureBoot(seq); err != nil {
+		return err
+	}
+
 	if err = i.manifest.Execute(); err != nil {
 		return err
 	}
@@ -212,32 +280,27 @@ func (i *Installer) Install(seq runtime.Sequence) (err error) {
 		return nil
 	}
 
-	i.cmdline.Append("initrd", filepath.Join("/", i.Next, constants.InitramfsAsset))
-
-	grubcfg := &grub.Cfg{
-		Default: i.Next,
-		Labels: []*grub.Label{
-			{
-				Root:   i.Next,
-				Initrd: filepath.Join("/", i.Next, constants.InitramfsAsset),
-				Kernel: filepath.Join("/", i.Next, constants.KernelAsset),
-				Append: i.cmdline.String(),
-			},
-		},
+	bootloaderOptions := options.InstallOptions{
+		BootDisk: i.options.Disk,
+		Arch:     goruntime.GOARCH,
+		Version:  version.Tag,
+		Current:  i.Current,
+		Next:     i.Next,
+		Cmdline:  i.cmdline.String(),
+		Serial:   i.options.GrubSerial(),
 	}
 
 	if i.Current != "" {
-		grubcfg.Fallback = i.Current
-
-		grubcfg.Labels = append(grubcfg.Labels, &grub.Label{
-			Root:   i.Current,
-			Initrd: filepath.Join("/", i.Current, constants.InitramfsAsset),
-			Kernel: filepath.Join("/", i.Current, constants.KernelAsset),
-			Append: procfs.ProcCmdline().String(),
-		})
+		if bootloaderOptions.CurrentCmdline, err = i.currentCmdline(); err != nil {
+			return err
+		}
 	}
 
-	if err = i.bootloader.Install(i.Current, grubcfg, seq); err != nil {
+	if err = i.bootloader.Install(bootloaderOptions); err != nil {
+		return err
+	}
+
+	if err = i.enrollSecureBootKeys(seq); err != nil {
 		return err
 	}
 
@@ -277,3 +340,21 @@ func (i *Installer) Install(seq runtime.Sequence) (err error) {
 
 	return nil
 }
+
+// currentCmdline returns the kernel command line of the current installation.
+//
+// Extra kernel arguments are appended by grub on every boot, so they are stripped
+// to avoid baking them into the bootloader config.
+func (i *Installer) currentCmdline() (string, error) {
+	cmdline, err := ioutil.ReadFile("/proc/cmdline")
+	if err != nil {
+		return "", err
+	}
+
+	cmdlineExtra, err := grub.ReadCmdlineExtra(grub.CmdlineExtra)
+	if err != nil {
+		return "", err
+	}
+
+	return procfs.NewCmdline(grub.StripCmdlineExtra(string(cmdline), cmdlineExtra)).String(), nil
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/board"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader"
 	"github.com/talos-systems/talos/internal/pkg/mount"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
@@ -80,6 +81,10 @@ func NewManifest(label string, sequence runtime.Sequence, bootPartitionFound boo
 
 	// Verify that the target device(s) can satisfy the requested options.
 
+	if err = VerifyMultipath(opts); err != nil {
+		return nil, err
+	}
+
 	if sequence != runtime.SequenceUpgrade {
 		if err = VerifyEphemeralPartition(opts); err != nil {
 			return nil, fmt.Errorf("failed to prepare ephemeral partition: %w", err)
@@ -105,6 +110,14 @@ func NewManifest(label string, sequence runtime.Sequence, bootPartitionFound boo
 	}
 
 	efiTarget := EFITarget(opts.Disk, nil)
+
+	if opts.BootloaderType == bootloader.TypeSDBoot {
+		// unified kernel images are stored on the EFI partition
+		efiTarget = EFITarget(opts.Disk, &Target{
+			PreserveContents: bootPartitionFound,
+		})
+		efiTarget.Size = EFISDBootSize
+	}
 	biosTarget := BIOSTarget(opts.Disk, nil)
 
 	var bootTarget *Target
@@ -140,17 +153,62 @@ func NewManifest(label string, sequence runtime.Sequence, bootPartitionFound boo
 		},
 	})
 
-	ephemeralTarget := EphemeralTarget(opts.Disk, nil)
+	var imageCacheTarget *Target
 
-	if opts.Force {
-		ephemeralTarget.Force = true
-	} else {
+	if opts.ImageCache != "" {
+		var (
+			size   uint64
+			assets []*Asset
+		)
+
+		if size, err = ImageCacheSize(opts.ImageCache); err != nil {
+			return nil, err
+		}
+
+		if assets, err = ImageCacheAssets(opts.ImageCache); err != nil {
+			return nil, err
+		}
+
+		imageCacheTarget = ImageCacheTarget(opts.Disk, size, &Target{
+			Assets: assets,
+		})
+	}
+
+	var ephemeralTarget *Target
+
+	switch {
+	case opts.Force:
+		ephemeralTarget = EphemeralTarget(opts.EphemeralDevice(), nil)
+		ephemeralTarget.Size = opts.EphemeralSize
+
+		if opts.EphemeralDevice() != opts.Disk {
+			manifest.Devices[opts.EphemeralDevice()] = Device{
+				Device: opts.EphemeralDevice(),
+
+				ResetPartitionTable: true,
+				Zero:                opts.Zero,
+			}
+		}
+	case opts.EphemeralDevice() == opts.Disk:
+		ephemeralTarget = EphemeralTarget(opts.Disk, &Target{
+			Skip: true,
+		})
 		ephemeralTarget.Force = false
-		ephemeralTarget.Skip = true
 		stateTarget.Size = 0 // expand previous partition to cover whatever space is available
+	default:
+		// EPHEMERAL partition on the separate disk is kept as is
+	}
+
+	if !opts.Force {
+		// keep the image cache baked into the disk image on upgrades
+		if imageCacheTarget == nil && imageCachePartitionExists(opts.Disk) {
+			imageCacheTarget = ImageCacheTarget(opts.Disk, 0, &Target{
+				Skip: true,
+			})
+		}
 	}
 
-	for _, target := range []*Target{efiTarget, biosTarget, bootTarget, metaTarget, stateTarget, ephemeralTarget} {
+	for _, target := range []*Target{efiTarget, biosTarget, bootTarget, metaTarget, stateTarget, imageCacheTarget, ephemeralTarget} {
 		if target == nil {
 			continue
 		}
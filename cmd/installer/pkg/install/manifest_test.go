@@ -13,6 +13,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/talos-systems/go-blockdevice/blockdevice"
 	"github.com/talos-systems/go-blockdevice/blockdevice/loopback"
@@ -53,6 +55,41 @@ func TestManifestSuite(t *testing.T) {
 	suite.Run(t, new(manifestSuite))
 }
 
+func TestNewManifestEphemeralDisk(t *testing.T) {
+	manifest, err := install.NewManifest("A", runtime.SequenceInstall, false, &install.Options{
+		Disk:          "/dev/nonexistent-a",
+		EphemeralDisk: "/dev/nonexistent-b",
+		EphemeralSize: 100 * install.MiB,
+		Bootloader:    true,
+		Force:         true,
+		Board:         constants.BoardNone,
+	})
+	require.NoError(t, err)
+
+	require.Contains(t, manifest.Devices, "/dev/nonexistent-b")
+	assert.True(t, manifest.Devices["/dev/nonexistent-b"].ResetPartitionTable)
+
+	require.Len(t, manifest.Targets["/dev/nonexistent-b"], 1)
+	assert.Equal(t, constants.EphemeralPartitionLabel, manifest.Targets["/dev/nonexistent-b"][0].Label)
+	assert.EqualValues(t, 100*install.MiB, manifest.Targets["/dev/nonexistent-b"][0].Size)
+
+	for _, target := range manifest.Targets["/dev/nonexistent-a"] {
+		assert.NotEqual(t, constants.EphemeralPartitionLabel, target.Label)
+	}
+
+	// upgrade with preserve keeps the EPHEMERAL disk intact
+	manifest, err = install.NewManifest("B", runtime.SequenceUpgrade, true, &install.Options{
+		Disk:          "/dev/nonexistent-a",
+		EphemeralDisk: "/dev/nonexistent-b",
+		Bootloader:    true,
+		Board:         constants.BoardNone,
+	})
+	require.NoError(t, err)
+
+	assert.NotContains(t, manifest.Devices, "/dev/nonexistent-b")
+	assert.NotContains(t, manifest.Targets, "/dev/nonexistent-b")
+}
+
 func (suite *manifestSuite) SetupTest() {
 	suite.skipIfNotRoot()
 
@@ -0,0 +1,167 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package install
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/sdboot"
+	"github.com/talos-systems/talos/internal/pkg/secureboot"
+)
+
+// verifySecureBoot verifies that the installation is going to boot with SecureBoot.
+//
+// With SecureBoot enforced (or about to be enforced after the key enrollment), the signed
+// unified kernel image and systemd-boot should chain up to db, otherwise the machine fails
+// to boot after the install or upgrade, so the install is refused before the disk is touched.
+func (i *Installer) verifySecureBoot(seq runtime.Sequence) error {
+	// disk images are built on the build host, its SecureBoot state is not relevant
+	if seq != runtime.SequenceInstall && seq != runtime.SequenceUpgrade {
+		return nil
+	}
+
+	state, err := secureboot.GetState()
+	if err != nil {
+		return fmt.Errorf("error reading SecureBoot state: %w", err)
+	}
+
+	enroll := i.options.EnrollSecureBootKeys && state.SetupMode
+
+	if !state.Enabled && !enroll {
+		return nil
+	}
+
+	if i.options.BootloaderType != bootloader.TypeSDBoot {
+		return fmt.Errorf("SecureBoot requires %s bootloader, got %s", bootloader.TypeSDBoot, i.options.BootloaderType)
+	}
+
+	if !sdboot.SignedAssetsPresent() {
+		return errors.New("SecureBoot is enabled, but the installer image doesn't contain the signed boot assets")
+	}
+
+	var db *secureboot.SignatureDatabase
+
+	if enroll {
+		if db, err = verifyEnrollmentKeys(); err != nil {
+			return err
+		}
+	} else if db, err = secureboot.ReadSignatureDatabase(secureboot.DB); err != nil {
+		return err
+	}
+
+	dbx, err := secureboot.ReadSignatureDatabase(secureboot.DBX)
+	if err != nil {
+		return err
+	}
+
+	return verifySignedAssets(db, dbx)
+}
+
+// verifySignedAssets verifies the signed boot assets against the signature databases.
+func verifySignedAssets(db, dbx *secureboot.SignatureDatabase) error {
+	for _, asset := range []string{sdboot.SignedSystemdBootAssetPath, sdboot.SignedImageAssetPath} {
+		image, err := ioutil.ReadFile(asset)
+		if err != nil {
+			return err
+		}
+
+		if err = secureboot.VerifyPE(image, db, dbx); err != nil {
+			return fmt.Errorf("%s fails SecureBoot verification: %w", asset, err)
+		}
+	}
+
+	return nil
+}
+
+// verifyEnrollmentKeys verifies the chain of the key enrollment updates: PK is self-signed,
+// KEK is signed by PK, and db is signed by KEK. It returns db which is going to be enrolled.
+func verifyEnrollmentKeys() (*secureboot.SignatureDatabase, error) {
+	pk, kek, db, err := readEnrollmentKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	return VerifyEnrollmentKeys(pk, kek, db)
+}
+
+// VerifyEnrollmentKeys verifies the chain of the key enrollment updates.
+func VerifyEnrollmentKeys(pk, kek, db []byte) (*secureboot.SignatureDatabase, error) {
+	pkDB, err := secureboot.ParseVariableUpdate(pk)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing PK: %w", err)
+	}
+
+	if _, err = secureboot.VerifyVariable(secureboot.PK, secureboot.GlobalVariableGUID, pk, pkDB); err != nil {
+		return nil, fmt.Errorf("error verifying PK: %w", err)
+	}
+
+	kekData, err := secureboot.VerifyVariable(secureboot.KEK, secureboot.GlobalVariableGUID, kek, pkDB)
+	if err != nil {
+		return nil, fmt.Errorf("error verifying KEK: %w", err)
+	}
+
+	kekDB, err := secureboot.ParseSignatureDatabase(kekData)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing KEK: %w", err)
+	}
+
+	dbData, err := secureboot.VerifyVariable(secureboot.DB, secureboot.ImageSecurityDatabaseGUID, db, kekDB)
+	if err != nil {
+		return nil, fmt.Errorf("error verifying db: %w", err)
+	}
+
+	return secureboot.ParseSignatureDatabase(dbData)
+}
+
+func readEnrollmentKeys() (pk, kek, db []byte, err error) {
+	if pk, err = ioutil.ReadFile(sdboot.PKAuthAssetPath); err != nil {
+		return nil, nil, nil, fmt.Errorf("installer image doesn't contain the SecureBoot keys: %w", err)
+	}
+
+	if kek, err = ioutil.ReadFile(sdboot.KEKAuthAssetPath); err != nil {
+		return nil, nil, nil, fmt.Errorf("installer image doesn't contain the SecureBoot keys: %w", err)
+	}
+
+	if db, err = ioutil.ReadFile(sdboot.DBAuthAssetPath); err != nil {
+		return nil, nil, nil, fmt.Errorf("installer image doesn't contain the SecureBoot keys: %w", err)
+	}
+
+	return pk, kek, db, nil
+}
+
+// enrollSecureBootKeys enrolls the SecureBoot keys of the installer image if the firmware is in setup mode.
+//
+// Keys are enrolled after the signed boot assets are installed, so that the firmware
+// enforces SecureBoot only when the installation is bootable.
+func (i *Installer) enrollSecureBootKeys(seq runtime.Sequence) error {
+	if !i.options.EnrollSecureBootKeys || (seq != runtime.SequenceInstall && seq != runtime.SequenceUpgrade) {
+		return nil
+	}
+
+	state, err := secureboot.GetState()
+	if err != nil {
+		return fmt.Errorf("error reading SecureBoot state: %w", err)
+	}
+
+	if !state.SetupMode {
+		log.Printf("firmware is not in SecureBoot setup mode, skipping the key enrollment")
+
+		return nil
+	}
+
+	pk, kek, db, err := readEnrollmentKeys()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("enrolling SecureBoot keys, SecureBoot is enforced on the next boot")
+
+	return secureboot.EnrollKeys(db, kek, pk)
+}
@@ -14,6 +14,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/talos-systems/go-blockdevice/blockdevice"
 	"github.com/talos-systems/go-blockdevice/blockdevice/partition/gpt"
 	"github.com/talos-systems/go-blockdevice/blockdevice/util"
 	"golang.org/x/sys/unix"
@@ -141,6 +142,20 @@ func StateTarget(device string, extra *Target) *Target {
 	return target.enhance(extra)
 }
 
+// ImageCacheTarget builds the image cache target.
+func ImageCacheTarget(device string, size uint64, extra *Target) *Target {
+	target := &Target{
+		Device:         device,
+		Label:          constants.ImageCachePartitionLabel,
+		PartitionType:  LinuxFilesystemData,
+		FileSystemType: FilesystemTypeXFS,
+		Size:           size,
+		Force:          true,
+	}
+
+	return target.enhance(extra)
+}
+
 // EphemeralTarget builds the default ephemeral target.
 func EphemeralTarget(device string, extra *Target) *Target {
 	target := &Target{
@@ -211,11 +226,34 @@ func (t *Target) Format() error {
 		return makefs.VFAT(t.PartitionName, opts...)
 	case FilesystemTypeXFS:
 		return makefs.XFS(t.PartitionName, opts...)
+	case FilesystemTypeExt4:
+		return makefs.Ext4(t.PartitionName, opts...)
 	default:
 		return fmt.Errorf("unsupported filesystem type: %q", t.FileSystemType)
 	}
 }
 
+// Wipe overwrites the contents of the partition.
+func (t *Target) Wipe() error {
+	log.Printf("wiping %q", t.PartitionName)
+
+	bd, err := blockdevice.Open(t.PartitionName)
+	if err != nil {
+		return err
+	}
+
+	defer bd.Close() //nolint: errcheck
+
+	method, err := bd.Wipe()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("wiped %q with %q", t.PartitionName, method)
+
+	return bd.Close()
+}
+
 // Save copies the assets to the bootloader partition.
 func (t *Target) Save() (err error) {
 	for _, asset := range t.Assets {
@@ -10,9 +10,24 @@ import (
 
 	"github.com/talos-systems/go-blockdevice/blockdevice/probe"
 
+	"github.com/talos-systems/talos/internal/pkg/disk"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
+// VerifyMultipath verifies that the install disks are not the paths of the device-mapper multipath devices.
+//
+// Writing to a single path bypasses the multipath device (and the path failover), and the partitions
+// can't be created on the multipath device itself, as device-mapper devices don't support partitions.
+func VerifyMultipath(opts *Options) (err error) {
+	for _, device := range []string{opts.Disk, opts.EphemeralDevice()} {
+		if holder := disk.MultipathHolder(device); holder != "" {
+			return fmt.Errorf("target install device %s is a path of the multipath device %s, installing onto multipath devices is not supported", device, holder)
+		}
+	}
+
+	return nil
+}
+
 // VerifyEphemeralPartition verifies the supplied data device options.
 func VerifyEphemeralPartition(opts *Options) (err error) {
 	if opts.Disk == "" {
@@ -23,7 +38,7 @@ func VerifyEphemeralPartition(opts *Options) (err error) {
 		return nil
 	}
 
-	if err = VerifyDiskAvailability(opts.Disk, constants.EphemeralPartitionLabel); err != nil {
+	if err = VerifyDiskAvailability(opts.EphemeralDevice(), constants.EphemeralPartitionLabel); err != nil {
 		return fmt.Errorf("failed to verify disk availability: %w", err)
 	}
 
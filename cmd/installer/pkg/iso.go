@@ -11,13 +11,21 @@ import (
 )
 
 // CreateISO creates an iso by invoking the `grub-mkrescue` command.
-func CreateISO(iso, dir string) (err error) {
-	_, err = cmd.Run(
-		"grub-mkrescue",
+//
+// If the volume label is not empty, it is set as the ISO filesystem label.
+func CreateISO(iso, dir, volumeLabel string) (err error) {
+	args := []string{
 		"--compress=xz",
-		"--output="+iso,
+		"--output=" + iso,
 		dir,
-	)
+	}
+
+	if volumeLabel != "" {
+		// arguments after "--" are passed to xorriso
+		args = append(args, "--", "-volid", volumeLabel)
+	}
+
+	_, err = cmd.Run("grub-mkrescue", args...)
 
 	if err != nil {
 		return fmt.Errorf("failed to create ISO: %w", err)
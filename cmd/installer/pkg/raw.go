@@ -16,10 +16,12 @@ const (
 )
 
 // CreateRawDisk creates a raw disk by invoking the `dd` command.
-func CreateRawDisk() (img string, err error) {
+//
+// Argument extraSize specifies the size (in MiB) to add on top of RAWDiskSize.
+func CreateRawDisk(extraSize uint64) (img string, err error) {
 	img = "/tmp/disk.raw"
 
-	seek := fmt.Sprintf("seek=%d", RAWDiskSize)
+	seek := fmt.Sprintf("seek=%d", RAWDiskSize+extraSize)
 
 	if _, err = cmd.Run("dd", "if=/dev/zero", "of="+img, "bs=1M", "count=0", seek); err != nil {
 		return "", fmt.Errorf("failed to create RAW disk: %w", err)
@@ -0,0 +1,197 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mgmt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/mgmt/airgap"
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/mgmt/helpers"
+	"github.com/talos-systems/talos/pkg/cli"
+	"github.com/talos-systems/talos/pkg/images"
+)
+
+var genBundleCmdFlags struct {
+	output     string
+	withImages bool
+}
+
+// genBundleCmd represents the gen bundle command.
+var genBundleCmd = &cobra.Command{
+	Use:   "bundle <cluster name> <cluster endpoint>",
+	Short: "Generates an air-gapped installation bundle for Talos cluster",
+	Long: `The bundle is a gzipped tarball which contains machine configs, talosconfig,
+the list of pinned images used by the cluster and (optionally) image archives
+pulled via the local Docker daemon. SHA256 checksums of all bundle entries are
+stored in the SHA256SUMS file at the root of the bundle.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateClusterEndpoint(args[1]); err != nil {
+			return err
+		}
+
+		return cli.WithContext(context.Background(), func(ctx context.Context) error {
+			return genBundle(ctx, args)
+		})
+	},
+}
+
+func genBundle(ctx context.Context, args []string) error {
+	configBundle, err := newConfigBundle(args)
+	if err != nil {
+		return err
+	}
+
+	output := genBundleCmdFlags.output
+	if output == "" {
+		output = args[0] + "-bundle.tar.gz"
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("error creating bundle: %w", err)
+	}
+
+	//nolint: errcheck
+	defer f.Close()
+
+	bw := airgap.NewWriter(f)
+
+	if err = bw.WriteConfigs(configBundle, args[0]); err != nil {
+		return err
+	}
+
+	imageList := airgap.Images(configBundle.InitCfg)
+
+	if genBundleCmdFlags.withImages {
+		if imageList, err = writeImages(ctx, bw, imageList); err != nil {
+			return err
+		}
+	}
+
+	if err = bw.WriteImageList(imageList); err != nil {
+		return err
+	}
+
+	if err = bw.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("created %s\n", output)
+
+	return f.Close()
+}
+
+// writeImages pulls the images via Docker, saves each one of them into the bundle
+// and returns the list of images pinned to the pulled digests.
+func writeImages(ctx context.Context, bw *airgap.Writer, imageList []string) ([]string, error) {
+	cli, err := client.NewEnvClient()
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint: errcheck
+	defer cli.Close()
+
+	tmpDir, err := ioutil.TempDir("", "talos-bundle")
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint: errcheck
+	defer os.RemoveAll(tmpDir)
+
+	pinned := make([]string, 0, len(imageList))
+
+	for _, image := range imageList {
+		fmt.Fprintf(os.Stderr, "pulling %s\n", image)
+
+		var reader io.ReadCloser
+
+		if reader, err = cli.ImagePull(ctx, image, types.ImagePullOptions{}); err != nil {
+			return nil, fmt.Errorf("error pulling %q: %w", image, err)
+		}
+
+		_, err = io.Copy(ioutil.Discard, reader)
+		reader.Close() //nolint: errcheck
+
+		if err != nil {
+			return nil, fmt.Errorf("error pulling %q: %w", image, err)
+		}
+
+		var inspect types.ImageInspect
+
+		if inspect, _, err = cli.ImageInspectWithRaw(ctx, image); err != nil {
+			return nil, fmt.Errorf("error inspecting %q: %w", image, err)
+		}
+
+		pinned = append(pinned, airgap.PinnedImage(image, inspect.RepoDigests))
+
+		archivePath := filepath.Join(tmpDir, "image.tar")
+
+		if err = saveImage(ctx, cli, image, archivePath); err != nil {
+			return nil, err
+		}
+
+		if err = bw.WriteFile(airgap.ImageArchiveName(image), archivePath); err != nil {
+			return nil, err
+		}
+
+		if err = os.Remove(archivePath); err != nil {
+			return nil, err
+		}
+	}
+
+	return pinned, nil
+}
+
+func saveImage(ctx context.Context, cli *client.Client, image, path string) error {
+	reader, err := cli.ImageSave(ctx, []string{image})
+	if err != nil {
+		return fmt.Errorf("error saving %q: %w", image, err)
+	}
+
+	//nolint: errcheck
+	defer reader.Close()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer f.Close()
+
+	if _, err = io.Copy(f, reader); err != nil {
+		return fmt.Errorf("error saving %q: %w", image, err)
+	}
+
+	return f.Close()
+}
+
+func init() {
+	genCmd.AddCommand(genBundleCmd)
+	genBundleCmd.Flags().StringVar(&installDisk, "install-disk", "/dev/sda", "the disk to install to")
+	genBundleCmd.Flags().StringVar(&installImage, "install-image", helpers.DefaultImage(images.DefaultInstallerImageRepository), "the image used to perform an installation")
+	genBundleCmd.Flags().StringSliceVar(&additionalSANs, "additional-sans", []string{}, "additional Subject-Alt-Names for the APIServer certificate")
+	genBundleCmd.Flags().StringVar(&dnsDomain, "dns-domain", "cluster.local", "the dns domain to use for cluster")
+	genBundleCmd.Flags().StringVar(&architecture, "arch", runtime.GOARCH, "the architecture of the cluster")
+	genBundleCmd.Flags().StringVar(&kubernetesVersion, "kubernetes-version", "", "desired kubernetes version to run")
+	genBundleCmd.Flags().StringSliceVar(&registryMirrors, "registry-mirror", []string{}, "list of registry mirrors to use in format: <registry host>=<mirror URL>")
+	genBundleCmd.Flags().BoolVarP(&persistConfig, "persist", "p", true, "the desired persist value for configs")
+	genBundleCmd.Flags().MarkDeprecated("persist", "machine configuration is always persisted") //nolint: errcheck
+	genBundleCmd.Flags().StringVarP(&genBundleCmdFlags.output, "output", "o", "", "bundle file path (defaults to <cluster name>-bundle.tar.gz)")
+	genBundleCmd.Flags().BoolVar(&genBundleCmdFlags.withImages, "with-images", false, "pull images via the local Docker daemon and include image archives in the bundle")
+}
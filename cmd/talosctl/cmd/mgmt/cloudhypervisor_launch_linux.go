@@ -0,0 +1,27 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mgmt
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/talos-systems/talos/pkg/provision/providers/cloudhypervisor"
+)
+
+// cloudHypervisorLaunchCmd represents the cloud-hypervisor-launch command.
+var cloudHypervisorLaunchCmd = &cobra.Command{
+	Use:    "cloud-hypervisor-launch",
+	Short:  "Internal command used by Cloud Hypervisor provisioner",
+	Long:   ``,
+	Args:   cobra.NoArgs,
+	Hidden: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cloudhypervisor.Launch()
+	},
+}
+
+func init() {
+	addCommand(cloudHypervisorLaunchCmd)
+}
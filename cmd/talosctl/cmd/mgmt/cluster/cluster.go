@@ -6,11 +6,18 @@
 package cluster
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
 
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/mgmt/clusterspec"
 	clientconfig "github.com/talos-systems/talos/pkg/machinery/client/config"
+	"github.com/talos-systems/talos/pkg/provision"
+	"github.com/talos-systems/talos/pkg/provision/access"
 )
 
 // Cmd represents the cluster command.
@@ -18,9 +25,23 @@ var Cmd = &cobra.Command{
 	Use:   "cluster",
 	Short: "A collection of commands for managing local docker-based or firecracker-based clusters",
 	Long:  ``,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if specPath == "" {
+			return nil
+		}
+
+		spec, err := clusterspec.Load(specPath)
+		if err != nil {
+			return err
+		}
+
+		return spec.Apply(cmd)
+	},
 }
 
 var (
+	talosconfig     string
+	specPath        string
 	provisionerName string
 	stateDir        string
 	clusterName     string
@@ -36,7 +57,50 @@ func init() {
 		defaultCNIDir = filepath.Join(talosDir, "cni")
 	}
 
+	defaultTalosConfig, err := clientconfig.GetDefaultPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to find default Talos config path: %s", err)
+	}
+
+	Cmd.PersistentFlags().StringVar(&talosconfig, "talosconfig", defaultTalosConfig, "The path to the Talos configuration file")
 	Cmd.PersistentFlags().StringVar(&provisionerName, "provisioner", "docker", "Talos cluster provisioner to use")
 	Cmd.PersistentFlags().StringVar(&stateDir, "state", defaultStateDir, "directory path to store cluster state")
 	Cmd.PersistentFlags().StringVar(&clusterName, "name", "talos-default", "the name of the cluster")
+	Cmd.PersistentFlags().StringVar(&specPath, "from", "", "YAML cluster spec file to read the flags from (flags set on the command line take precedence)")
+}
+
+// newClusterAccess builds cluster access for the existing cluster using the talosconfig context named after the cluster.
+func newClusterAccess(cluster provision.Cluster, opts ...provision.Option) (*access.Adapter, error) {
+	cfg, err := clientconfig.Open(talosconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	contextName := cluster.Info().ClusterName
+
+	if _, ok := cfg.Contexts[contextName]; !ok {
+		return nil, fmt.Errorf("context %q is not defined in talosconfig %q", contextName, talosconfig)
+	}
+
+	cfg.Context = contextName
+
+	return access.NewAdapter(cluster, append([]provision.Option{provision.WithTalosConfig(cfg)}, opts...)...), nil
+}
+
+// reflectExisting returns the cluster if it was already created, or nil otherwise.
+func reflectExisting(ctx context.Context, provisioner provision.Provisioner) (provision.Cluster, error) {
+	cluster, err := provisioner.Reflect(ctx, clusterName, stateDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if len(cluster.Info().Nodes) == 0 {
+		return nil, nil
+	}
+
+	return cluster, nil
 }
@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"math/big"
 	"net"
 	"os"
@@ -19,8 +20,10 @@ import (
 	"time"
 
 	humanize "github.com/dustin/go-humanize"
+	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/spf13/cobra"
 	talosnet "github.com/talos-systems/net"
+	yaml "gopkg.in/yaml.v3"
 	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/talos-systems/talos/cmd/talosctl/pkg/mgmt/helpers"
@@ -30,6 +33,8 @@ import (
 	"github.com/talos-systems/talos/pkg/images"
 	clientconfig "github.com/talos-systems/talos/pkg/machinery/client/config"
 	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/config/configpatcher"
+	"github.com/talos-systems/talos/pkg/machinery/config/encoder"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/bundle"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
@@ -42,7 +47,6 @@ import (
 )
 
 var (
-	talosconfig             string
 	nodeImage               string
 	nodeInstallImage        string
 	registryMirrors         []string
@@ -83,6 +87,13 @@ var (
 	crashdumpOnFailure      bool
 	skipKubeconfig          bool
 	skipInjectingConfig     bool
+	existingNetwork         string
+	skipDHCP                bool
+	nodeIPs                 []string
+	nodeMACs                []string
+	configPatch             string
+	configPatchControlPlane string
+	configPatchWorker       string
 )
 
 // createCmd represents the cluster up command.
@@ -125,14 +136,14 @@ func create(ctx context.Context) (err error) {
 		return err
 	}
 
-	// Set starting ip at 2nd ip in range, ex: 192.168.0.2
-	ips := make([]net.IP, masters+workers)
+	ips, err := getNodeIPs(cidr)
+	if err != nil {
+		return err
+	}
 
-	for i := range ips {
-		ips[i], err = talosnet.NthIPInNetwork(cidr, i+2)
-		if err != nil {
-			return err
-		}
+	macs, err := getNodeMACs()
+	if err != nil {
+		return err
 	}
 
 	// Parse nameservers
@@ -145,6 +156,19 @@ func create(ctx context.Context) (err error) {
 		}
 	}
 
+	if skipDHCP && provisionerName != "qemu" && provisionerName != "cloud-hypervisor" {
+		return fmt.Errorf("skip-dhcp flag only supported with qemu and cloud-hypervisor provisioners")
+	}
+
+	if len(nodeMACs) > 0 && provisionerName == "firecracker" {
+		return fmt.Errorf("node-macs flag is not supported with firecracker provisioner")
+	}
+
+	networkName := clusterName
+	if existingNetwork != "" {
+		networkName = existingNetwork
+	}
+
 	provisioner, err := providers.Factory(ctx, provisionerName)
 	if err != nil {
 		return err
@@ -152,16 +176,32 @@ func create(ctx context.Context) (err error) {
 
 	defer provisioner.Close() //nolint: errcheck
 
+	// create is idempotent when the cluster is described with the spec
+	if specPath != "" {
+		var existing provision.Cluster
+
+		existing, err = reflectExisting(ctx, provisioner)
+		if err != nil {
+			return err
+		}
+
+		if existing != nil {
+			return checkExisting(ctx, existing)
+		}
+	}
+
 	// Craft cluster and node requests
 	request := provision.ClusterRequest{
 		Name: clusterName,
 
 		Network: provision.NetworkRequest{
-			Name:        clusterName,
+			Name:        networkName,
 			CIDR:        *cidr,
 			GatewayAddr: gatewayIP,
 			MTU:         networkMTU,
 			Nameservers: nameserverIPs,
+			Existing:    existingNetwork != "",
+			SkipDHCP:    skipDHCP,
 			CNI: provision.CNIConfig{
 				BinPath:  cniBinPath,
 				ConfDir:  cniConfDir,
@@ -293,6 +333,10 @@ func create(ctx context.Context) (err error) {
 		return err
 	}
 
+	if err = patchConfigs(configBundle); err != nil {
+		return err
+	}
+
 	if skipInjectingConfig {
 		types := []machine.Type{machine.TypeControlPlane, machine.TypeJoin}
 
@@ -300,7 +344,7 @@ func create(ctx context.Context) (err error) {
 			types = append([]machine.Type{machine.TypeInit}, types...)
 		}
 
-		if err = configBundle.Write(".", types...); err != nil {
+		if err = configBundle.Write(".", encoder.CommentsAll, types...); err != nil {
 			return err
 		}
 	}
@@ -316,6 +360,7 @@ func create(ctx context.Context) (err error) {
 			Name:     fmt.Sprintf("%s-master-%d", clusterName, i+1),
 			Type:     machine.TypeControlPlane,
 			IP:       ips[i],
+			MAC:      macs[i],
 			Memory:   memory,
 			NanoCPUs: nanoCPUs,
 			Disks:    disks,
@@ -353,6 +398,7 @@ func create(ctx context.Context) (err error) {
 				Name:     name,
 				Type:     machine.TypeJoin,
 				IP:       ips[masters+i-1],
+				MAC:      macs[masters+i-1],
 				Memory:   memory,
 				NanoCPUs: nanoCPUs,
 				Disks:    disks,
@@ -388,7 +434,133 @@ func create(ctx context.Context) (err error) {
 		return err
 	}
 
-	return showCluster(cluster)
+	versions, err := nodeVersions(ctx, clusterAccess)
+	if err != nil {
+		cli.Warning("failed to fetch Talos versions of the nodes: %s", err)
+	}
+
+	return showCluster(cluster, versions)
+}
+
+// checkExisting verifies that the existing cluster matches the requested number of nodes.
+func checkExisting(ctx context.Context, cluster provision.Cluster) error {
+	var existingMasters, existingWorkers int
+
+	for _, node := range cluster.Info().Nodes {
+		if node.Type == machine.TypeJoin {
+			existingWorkers++
+		} else {
+			existingMasters++
+		}
+	}
+
+	if existingMasters != masters || existingWorkers != workers {
+		return fmt.Errorf("cluster %q already exists with %d masters and %d workers, destroy it to create the cluster with %d masters and %d workers",
+			clusterName, existingMasters, existingWorkers, masters, workers)
+	}
+
+	fmt.Printf("cluster %q already exists\n", clusterName)
+
+	clusterAccess, err := newClusterAccess(cluster)
+	if err != nil {
+		return err
+	}
+
+	defer clusterAccess.Close() //nolint: errcheck
+
+	versions, err := nodeVersions(ctx, clusterAccess)
+	if err != nil {
+		cli.Warning("failed to fetch Talos versions of the nodes: %s", err)
+	}
+
+	return showCluster(cluster, versions)
+}
+
+// loadConfigPatch parses JSON 6902 patch passed inline or as @file.
+func loadConfigPatch(patch string) (jsonpatch.Patch, error) {
+	if patch == "" {
+		return nil, nil
+	}
+
+	data := []byte(patch)
+
+	if strings.HasPrefix(patch, "@") {
+		var err error
+
+		data, err = ioutil.ReadFile(patch[1:])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return jsonpatch.DecodePatch(data)
+}
+
+// patchConfigs applies config patches to the machine configs in the bundle.
+func patchConfigs(configBundle *v1alpha1.ConfigBundle) error {
+	patches := map[string]jsonpatch.Patch{}
+
+	for _, flag := range []struct {
+		name  string
+		value string
+	}{
+		{"config-patch", configPatch},
+		{"config-patch-control-plane", configPatchControlPlane},
+		{"config-patch-worker", configPatchWorker},
+	} {
+		patch, err := loadConfigPatch(flag.value)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %w", flag.name, err)
+		}
+
+		patches[flag.name] = patch
+	}
+
+	for _, target := range []struct {
+		cfg     **v1alpha1.Config
+		patches []jsonpatch.Patch
+	}{
+		{&configBundle.InitCfg, []jsonpatch.Patch{patches["config-patch"], patches["config-patch-control-plane"]}},
+		{&configBundle.ControlPlaneCfg, []jsonpatch.Patch{patches["config-patch"], patches["config-patch-control-plane"]}},
+		{&configBundle.JoinCfg, []jsonpatch.Patch{patches["config-patch"], patches["config-patch-worker"]}},
+	} {
+		if *target.cfg == nil {
+			continue
+		}
+
+		data, err := (*target.cfg).Bytes()
+		if err != nil {
+			return err
+		}
+
+		patched := false
+
+		for _, patch := range target.patches {
+			if patch == nil {
+				continue
+			}
+
+			if data, err = configpatcher.JSON6902(data, patch); err != nil {
+				return err
+			}
+
+			patched = true
+		}
+
+		if !patched {
+			continue
+		}
+
+		cfg := &v1alpha1.Config{}
+
+		if err = yaml.Unmarshal(data, cfg); err != nil {
+			return fmt.Errorf("error decoding patched config: %w", err)
+		}
+
+		*target.cfg = cfg
+	}
+
+	return nil
 }
 
 func postCreate(ctx context.Context, clusterAccess *access.Adapter) error {
@@ -499,6 +671,70 @@ func parseCPUShare() (int64, error) {
 	return nano.Num().Int64(), nil
 }
 
+// getNodeIPs returns IPs of the nodes (masters first, then workers).
+//
+// IPs are allocated from the CIDR starting with the 2nd IP in range (ex: 192.168.0.2),
+// unless the IPs are set explicitly.
+func getNodeIPs(cidr *net.IPNet) ([]net.IP, error) {
+	ips := make([]net.IP, masters+workers)
+
+	if len(nodeIPs) == 0 {
+		for i := range ips {
+			var err error
+
+			ips[i], err = talosnet.NthIPInNetwork(cidr, i+2)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return ips, nil
+	}
+
+	if len(nodeIPs) != len(ips) {
+		return nil, fmt.Errorf("number of node IPs (%d) doesn't match the number of nodes (%d)", len(nodeIPs), len(ips))
+	}
+
+	for i := range ips {
+		ips[i] = net.ParseIP(nodeIPs[i])
+		if ips[i] == nil {
+			return nil, fmt.Errorf("failed parsing node IP %q", nodeIPs[i])
+		}
+
+		if !cidr.Contains(ips[i]) {
+			return nil, fmt.Errorf("node IP %q is not in the cluster network %s", nodeIPs[i], cidr)
+		}
+	}
+
+	return ips, nil
+}
+
+// getNodeMACs returns MAC addresses of the nodes (masters first, then workers).
+//
+// If MAC addresses are not set explicitly, the list contains nil MACs, so that the provisioner picks them.
+func getNodeMACs() ([]net.HardwareAddr, error) {
+	macs := make([]net.HardwareAddr, masters+workers)
+
+	if len(nodeMACs) == 0 {
+		return macs, nil
+	}
+
+	if len(nodeMACs) != len(macs) {
+		return nil, fmt.Errorf("number of node MAC addresses (%d) doesn't match the number of nodes (%d)", len(nodeMACs), len(macs))
+	}
+
+	for i := range macs {
+		var err error
+
+		macs[i], err = net.ParseMAC(nodeMACs[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing node MAC address %q: %w", nodeMACs[i], err)
+		}
+	}
+
+	return macs, nil
+}
+
 func getDisks() ([]*provision.Disk, error) {
 	// should have at least a single primary disk
 	disks := []*provision.Disk{
@@ -562,12 +798,6 @@ func trimVersion(version string) string {
 }
 
 func init() {
-	defaultTalosConfig, err := clientconfig.GetDefaultPath()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to find default Talos config path: %s", err)
-	}
-
-	createCmd.Flags().StringVar(&talosconfig, "talosconfig", defaultTalosConfig, "The path to the Talos configuration file")
 	createCmd.Flags().StringVar(&nodeImage, "image", helpers.DefaultImage(images.DefaultTalosImageRepository), "the image to use")
 	createCmd.Flags().StringVar(&nodeInstallImage, "install-image", helpers.DefaultImage(images.DefaultInstallerImageRepository), "the installer image to use")
 	createCmd.Flags().StringVar(&nodeVmlinuzPath, "vmlinuz-path", helpers.ArtifactPath(constants.KernelAssetWithArch), "the compressed kernel image to use")
@@ -614,5 +844,12 @@ func init() {
 	createCmd.Flags().BoolVar(&crashdumpOnFailure, "crashdump", false, "print debug crashdump to stderr when cluster startup fails")
 	createCmd.Flags().BoolVar(&skipKubeconfig, "skip-kubeconfig", false, "skip merging kubeconfig from the created cluster")
 	createCmd.Flags().BoolVar(&skipInjectingConfig, "skip-injecting-config", false, "skip injecting config from embedded metadata server, write config files to current directory")
+	createCmd.Flags().StringVar(&existingNetwork, "existing-network", "", "attach nodes to the existing network instead of creating one (Docker network name, or bridge interface name with the gateway address assigned for VMs)")
+	createCmd.Flags().BoolVar(&skipDHCP, "skip-dhcp", false, "don't run DHCP server for the nodes, the network should provide DHCP on its own (QEMU and Cloud Hypervisor provisioners only)")
+	createCmd.Flags().StringSliceVar(&nodeIPs, "node-ips", nil, "list of node IPs (masters first, then workers), allocated from the CIDR by default")
+	createCmd.Flags().StringSliceVar(&nodeMACs, "node-macs", nil, "list of node MAC addresses (masters first, then workers), random by default")
+	createCmd.Flags().StringVar(&configPatch, "config-patch", "", "JSON 6902 patch (inline or @file) to apply to the machine config of each node")
+	createCmd.Flags().StringVar(&configPatchControlPlane, "config-patch-control-plane", "", "JSON 6902 patch (inline or @file) to apply to the machine config of control plane nodes")
+	createCmd.Flags().StringVar(&configPatchWorker, "config-patch-worker", "", "JSON 6902 patch (inline or @file) to apply to the machine config of worker nodes")
 	Cmd.AddCommand(createCmd)
 }
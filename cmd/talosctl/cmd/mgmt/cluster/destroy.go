@@ -6,6 +6,9 @@ package cluster
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -34,6 +37,13 @@ func destroy(ctx context.Context) error {
 
 	cluster, err := provisioner.Reflect(ctx, clusterName, stateDir)
 	if err != nil {
+		// destroy is idempotent when the cluster is described with the spec
+		if specPath != "" && errors.Is(err, os.ErrNotExist) {
+			fmt.Printf("cluster %q doesn't exist\n", clusterName)
+
+			return nil
+		}
+
 		return err
 	}
 
@@ -0,0 +1,128 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/talos-systems/talos/pkg/cli"
+	"github.com/talos-systems/talos/pkg/cluster/check"
+	clusterapi "github.com/talos-systems/talos/pkg/machinery/api/cluster"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
+	"github.com/talos-systems/talos/pkg/provision"
+	"github.com/talos-systems/talos/pkg/provision/access"
+	"github.com/talos-systems/talos/pkg/provision/providers"
+)
+
+var healthCmdFlags struct {
+	clusterWaitTimeout time.Duration
+	forceEndpoint      string
+	runOnServer        bool
+}
+
+// healthCmd represents the cluster health command.
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Check health of a local provisioned kubernetes cluster",
+	Long: `Runs the cluster health checks against the local cluster.
+
+Node IPs and roles are taken from the cluster state, so there's no need to specify them.
+By default the checks run on the first control plane node using the cluster health API,
+with --server=false the checks run in talosctl.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cli.WithContext(context.Background(), health)
+	},
+}
+
+func health(ctx context.Context) error {
+	provisioner, err := providers.Factory(ctx, provisionerName)
+	if err != nil {
+		return err
+	}
+
+	defer provisioner.Close() //nolint: errcheck
+
+	cluster, err := provisioner.Reflect(ctx, clusterName, stateDir)
+	if err != nil {
+		return err
+	}
+
+	clusterAccess, err := newClusterAccess(cluster, provision.WithEndpoint(healthCmdFlags.forceEndpoint))
+	if err != nil {
+		return err
+	}
+
+	defer clusterAccess.Close() //nolint: errcheck
+
+	if healthCmdFlags.runOnServer {
+		return healthOnServer(ctx, clusterAccess)
+	}
+
+	checkCtx, checkCtxCancel := context.WithTimeout(ctx, healthCmdFlags.clusterWaitTimeout)
+	defer checkCtxCancel()
+
+	return check.Wait(checkCtx, clusterAccess, append(check.DefaultClusterChecks(), check.ExtraClusterChecks()...), check.StderrReporter())
+}
+
+func healthOnServer(ctx context.Context, clusterAccess *access.Adapter) error {
+	controlPlaneNodes := append(clusterAccess.NodesByType(machine.TypeInit), clusterAccess.NodesByType(machine.TypeControlPlane)...)
+	if len(controlPlaneNodes) == 0 {
+		return fmt.Errorf("cluster %q has no control plane nodes", clusterName)
+	}
+
+	c, err := clusterAccess.Client()
+	if err != nil {
+		return err
+	}
+
+	healthClient, err := c.ClusterHealthCheck(client.WithNodes(ctx, controlPlaneNodes[0]), healthCmdFlags.clusterWaitTimeout, &clusterapi.ClusterInfo{
+		ControlPlaneNodes: controlPlaneNodes,
+		WorkerNodes:       clusterAccess.NodesByType(machine.TypeJoin),
+		ForceEndpoint:     healthCmdFlags.forceEndpoint,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = healthClient.CloseSend(); err != nil {
+		return err
+	}
+
+	for {
+		var msg *clusterapi.HealthCheckProgress
+
+		msg, err = healthClient.Recv()
+		if err != nil {
+			if err == io.EOF || status.Code(err) == codes.Canceled {
+				return nil
+			}
+
+			return err
+		}
+
+		if msg.GetMetadata().GetError() != "" {
+			return fmt.Errorf("healthcheck error: %s", msg.GetMetadata().GetError())
+		}
+
+		fmt.Fprintln(os.Stderr, msg.GetMessage())
+	}
+}
+
+func init() {
+	healthCmd.Flags().DurationVar(&healthCmdFlags.clusterWaitTimeout, "wait-timeout", 20*time.Minute, "timeout to wait for the cluster to be ready")
+	healthCmd.Flags().StringVar(&healthCmdFlags.forceEndpoint, "k8s-endpoint", "", "use endpoint instead of kubeconfig default")
+	healthCmd.Flags().BoolVar(&healthCmdFlags.runOnServer, "server", true, "run server-side check")
+	Cmd.AddCommand(healthCmd)
+}
@@ -10,12 +10,15 @@ import (
 	"os"
 	"sort"
 	"text/tabwriter"
+	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 
 	"github.com/talos-systems/talos/pkg/cli"
+	"github.com/talos-systems/talos/pkg/machinery/client"
 	"github.com/talos-systems/talos/pkg/provision"
+	"github.com/talos-systems/talos/pkg/provision/access"
 	"github.com/talos-systems/talos/pkg/provision/providers"
 )
 
@@ -23,8 +26,11 @@ import (
 var showCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Shows info about a local provisioned kubernetes cluster",
-	Long:  ``,
-	Args:  cobra.NoArgs,
+	Long: `Shows the provisioner details and the nodes of the local cluster.
+
+Talos versions of the nodes are fetched using the talosconfig context named after the cluster,
+nodes which can't be reached are shown without the version.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return cli.WithContext(context.Background(), show)
 	},
@@ -43,13 +49,60 @@ func show(ctx context.Context) error {
 		return err
 	}
 
-	return showCluster(cluster)
+	var versions map[string]string
+
+	clusterAccess, err := newClusterAccess(cluster)
+	if err == nil {
+		defer clusterAccess.Close() //nolint: errcheck
+
+		versions, err = nodeVersions(ctx, clusterAccess)
+	}
+
+	if err != nil {
+		cli.Warning("failed to fetch Talos versions of the nodes: %s", err)
+	}
+
+	return showCluster(cluster, versions)
 }
 
-func showCluster(cluster provision.Cluster) error {
+// versionTimeout limits the time spent on fetching node versions, as some nodes might be down.
+const versionTimeout = 10 * time.Second
+
+// nodeVersions fetches Talos versions of the cluster nodes indexed by the node IP.
+//
+// Versions are returned for the nodes which responded, even if some nodes failed.
+func nodeVersions(ctx context.Context, clusterAccess *access.Adapter) (map[string]string, error) {
+	c, err := clusterAccess.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, versionTimeout)
+	defer cancel()
+
+	resp, err := c.Version(client.WithNodes(ctx, clusterAccess.Nodes()...))
+	if resp == nil {
+		return nil, err
+	}
+
+	versions := map[string]string{}
+
+	for _, msg := range resp.Messages {
+		versions[msg.GetMetadata().GetHostname()] = msg.GetVersion().GetTag()
+	}
+
+	return versions, err
+}
+
+func showCluster(cluster provision.Cluster, versions map[string]string) error {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
 	fmt.Fprintf(w, "PROVISIONER\t%s\n", cluster.Provisioner())
 	fmt.Fprintf(w, "NAME\t%s\n", cluster.Info().ClusterName)
+
+	if statePath, err := cluster.StatePath(); err == nil {
+		fmt.Fprintf(w, "STATE DIRECTORY\t%s\n", statePath)
+	}
+
 	fmt.Fprintf(w, "NETWORK NAME\t%s\n", cluster.Info().Network.Name)
 
 	ones, _ := cluster.Info().Network.CIDR.Mask.Size()
@@ -65,7 +118,7 @@ func showCluster(cluster provision.Cluster) error {
 
 	w = tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
 
-	fmt.Fprintf(w, "NAME\tTYPE\tIP\tCPU\tRAM\tDISK\n")
+	fmt.Fprintf(w, "NAME\tTYPE\tIP\tVERSION\tCPU\tRAM\tDISK\n")
 
 	nodes := cluster.Info().Nodes
 	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
@@ -86,10 +139,16 @@ func showCluster(cluster provision.Cluster) error {
 			disk = humanize.Bytes(node.DiskSize)
 		}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+		version := "-"
+		if v, ok := versions[node.PrivateIP.String()]; ok && v != "" {
+			version = v
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 			node.Name,
 			node.Type,
 			node.PrivateIP,
+			version,
 			cpus,
 			mem,
 			disk,
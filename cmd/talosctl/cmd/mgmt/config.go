@@ -19,6 +19,9 @@ import (
 
 	"github.com/talos-systems/talos/cmd/talosctl/pkg/mgmt/helpers"
 	"github.com/talos-systems/talos/pkg/images"
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/config/encoder"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/bundle"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
@@ -31,13 +34,20 @@ var (
 	architecture      string
 	dnsDomain         string
 	kubernetesVersion string
+	talosVersion      string
 	installDisk       string
 	installImage      string
 	outputDir         string
 	registryMirrors   []string
 	persistConfig     bool
+	outputTypes       []string
+	withDocs          bool
+	withExamples      bool
 )
 
+// talosconfigOutputType is the output type for the client configuration.
+const talosconfigOutputType = "talosconfig"
+
 // genConfigCmd represents the gen config command.
 var genConfigCmd = &cobra.Command{
 	Use:   "config <cluster name> <cluster endpoint>",
@@ -48,33 +58,8 @@ var genConfigCmd = &cobra.Command{
 	setup, usually involving a load balancer, use the IP and port of the load balancer.`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Validate url input to ensure it has https:// scheme before we attempt to gen
-		u, err := url.Parse(args[1])
-		if err != nil {
-			if !strings.Contains(args[1], "/") {
-				// not a URL, could be just host:port
-				u = &url.URL{
-					Host: args[1],
-				}
-			} else {
-				return fmt.Errorf("failed to parse the cluster endpoint URL: %w", err)
-			}
-		}
-
-		if u.Scheme == "" {
-			if u.Port() == "" {
-				return fmt.Errorf("no scheme and port specified for the cluster endpoint URL\ntry: %q", fixControlPlaneEndpoint(u))
-			}
-
-			return fmt.Errorf("no scheme specified for the cluster endpoint URL\ntry: %q", fixControlPlaneEndpoint(u))
-		}
-
-		if u.Scheme != "https" {
-			return fmt.Errorf("the control plane endpoint URL should have scheme https://\ntry: %q", fixControlPlaneEndpoint(u))
-		}
-
-		if err = talosnet.ValidateEndpointURI(args[1]); err != nil {
-			return fmt.Errorf("error validating the cluster endpoint URL: %w", err)
+		if err := validateClusterEndpoint(args[1]); err != nil {
+			return err
 		}
 
 		switch configVersion {
@@ -86,6 +71,40 @@ var genConfigCmd = &cobra.Command{
 	},
 }
 
+// validateClusterEndpoint ensures that the cluster endpoint is a valid https:// URL.
+func validateClusterEndpoint(endpoint string) error {
+	// Validate url input to ensure it has https:// scheme before we attempt to gen
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		if !strings.Contains(endpoint, "/") {
+			// not a URL, could be just host:port
+			u = &url.URL{
+				Host: endpoint,
+			}
+		} else {
+			return fmt.Errorf("failed to parse the cluster endpoint URL: %w", err)
+		}
+	}
+
+	if u.Scheme == "" {
+		if u.Port() == "" {
+			return fmt.Errorf("no scheme and port specified for the cluster endpoint URL\ntry: %q", fixControlPlaneEndpoint(u))
+		}
+
+		return fmt.Errorf("no scheme specified for the cluster endpoint URL\ntry: %q", fixControlPlaneEndpoint(u))
+	}
+
+	if u.Scheme != "https" {
+		return fmt.Errorf("the control plane endpoint URL should have scheme https://\ntry: %q", fixControlPlaneEndpoint(u))
+	}
+
+	if err = talosnet.ValidateEndpointURI(endpoint); err != nil {
+		return fmt.Errorf("error validating the cluster endpoint URL: %w", err)
+	}
+
+	return nil
+}
+
 func fixControlPlaneEndpoint(u *url.URL) *url.URL {
 	// handle the case when the hostname/IP is given without the port, it parses as URL Path
 	if u.Scheme == "" && u.Host == "" && u.Path != "" {
@@ -104,6 +123,43 @@ func fixControlPlaneEndpoint(u *url.URL) *url.URL {
 
 //nolint: gocyclo
 func genV1Alpha1Config(args []string) error {
+	var (
+		machineTypes    []machine.Type
+		withTalosconfig bool
+	)
+
+	for _, outputType := range outputTypes {
+		switch outputType {
+		case talosconfigOutputType:
+			withTalosconfig = true
+		case "worker":
+			machineTypes = append(machineTypes, machine.TypeJoin)
+		default:
+			t, err := machine.ParseType(outputType)
+			if err != nil || t == machine.TypeUnknown {
+				return fmt.Errorf("unknown output type %q, expected one of: init, controlplane, join (worker), %s", outputType, talosconfigOutputType)
+			}
+
+			machineTypes = append(machineTypes, t)
+		}
+	}
+
+	toStdout := outputDir == "-"
+
+	if toStdout && len(outputTypes) != 1 {
+		return fmt.Errorf("exactly one output type should be specified when writing to stdout")
+	}
+
+	commentsFlags := encoder.CommentsDisabled
+
+	if withDocs {
+		commentsFlags |= encoder.CommentsDocs
+	}
+
+	if withExamples {
+		commentsFlags |= encoder.CommentsExamples
+	}
+
 	// If output dir isn't specified, set to the current working dir
 	var err error
 	if outputDir == "" {
@@ -113,23 +169,89 @@ func genV1Alpha1Config(args []string) error {
 		}
 	}
 
-	// Create dir path, ignoring "already exists" messages
-	if err = os.MkdirAll(outputDir, os.ModePerm); err != nil && !os.IsExist(err) {
-		return fmt.Errorf("failed to create output dir: %w", err)
+	if !toStdout {
+		// Create dir path, ignoring "already exists" messages
+		if err = os.MkdirAll(outputDir, os.ModePerm); err != nil && !os.IsExist(err) {
+			return fmt.Errorf("failed to create output dir: %w", err)
+		}
+	}
+
+	configBundle, err := newConfigBundle(args, bundle.WithVerbose(!toStdout))
+	if err != nil {
+		return err
+	}
+
+	if toStdout && len(machineTypes) == 1 {
+		var data []byte
+
+		data, err = configBundle.Serialize(commentsFlags, machineTypes[0])
+		if err != nil {
+			return err
+		}
+
+		_, err = os.Stdout.Write(data)
+
+		return err
+	}
+
+	if !toStdout {
+		if err = configBundle.Write(outputDir, commentsFlags, machineTypes...); err != nil {
+			return err
+		}
+	}
+
+	if !withTalosconfig {
+		return nil
+	}
+
+	// We set the default endpoint to localhost for configs generated, with expectation user will tweak later
+	configBundle.TalosConfig().Contexts[args[0]].Endpoints = []string{"127.0.0.1"}
+
+	data, err := yaml.Marshal(configBundle.TalosConfig())
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %+v", err)
+	}
+
+	if toStdout {
+		_, err = os.Stdout.Write(data)
+
+		return err
+	}
+
+	fullFilePath := filepath.Join(outputDir, "talosconfig")
+
+	if err = ioutil.WriteFile(fullFilePath, data, 0o644); err != nil {
+		return fmt.Errorf("%w", err)
 	}
 
+	fmt.Printf("created %s\n", fullFilePath)
+
+	return nil
+}
+
+// newConfigBundle generates v1alpha1 config bundle from command line flags.
+func newConfigBundle(args []string, opts ...bundle.Option) (*v1alpha1.ConfigBundle, error) {
 	var genOptions []generate.GenOption //nolint: prealloc
 
 	for _, registryMirror := range registryMirrors {
 		components := strings.SplitN(registryMirror, "=", 2)
 		if len(components) != 2 {
-			return fmt.Errorf("invalid registry mirror spec: %q", registryMirror)
+			return nil, fmt.Errorf("invalid registry mirror spec: %q", registryMirror)
 		}
 
 		genOptions = append(genOptions, generate.WithRegistryMirror(components[0], components[1]))
 	}
 
-	configBundle, err := bundle.NewConfigBundle(
+	if talosVersion != "" {
+		versionContract, err := config.ParseContractFromVersion(talosVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid talos-version: %w", err)
+		}
+
+		genOptions = append(genOptions, generate.WithVersionContract(versionContract))
+	}
+
+	configBundle, err := bundle.NewConfigBundle(append([]bundle.Option{
 		bundle.WithInputOptions(
 			&bundle.InputOptions{
 				ClusterName: args[0],
@@ -145,32 +267,12 @@ func genV1Alpha1Config(args []string) error {
 				),
 			},
 		),
-	)
+	}, opts...)...)
 	if err != nil {
-		return fmt.Errorf("failed to generate config bundle: %w", err)
+		return nil, fmt.Errorf("failed to generate config bundle: %w", err)
 	}
 
-	if err = configBundle.Write(outputDir, machine.TypeInit, machine.TypeControlPlane, machine.TypeJoin); err != nil {
-		return err
-	}
-
-	// We set the default endpoint to localhost for configs generated, with expectation user will tweak later
-	configBundle.TalosConfig().Contexts[args[0]].Endpoints = []string{"127.0.0.1"}
-
-	data, err := yaml.Marshal(configBundle.TalosConfig())
-	if err != nil {
-		return fmt.Errorf("failed to marshal config: %+v", err)
-	}
-
-	fullFilePath := filepath.Join(outputDir, "talosconfig")
-
-	if err = ioutil.WriteFile(fullFilePath, data, 0o644); err != nil {
-		return fmt.Errorf("%w", err)
-	}
-
-	fmt.Printf("created %s\n", fullFilePath)
-
-	return nil
+	return configBundle, nil
 }
 
 func init() {
@@ -182,7 +284,12 @@ func init() {
 	genConfigCmd.Flags().StringVar(&architecture, "arch", runtime.GOARCH, "the architecture of the cluster")
 	genConfigCmd.Flags().StringVar(&configVersion, "version", "v1alpha1", "the desired machine config version to generate")
 	genConfigCmd.Flags().StringVar(&kubernetesVersion, "kubernetes-version", "", "desired kubernetes version to run")
-	genConfigCmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "destination to output generated files")
+	genConfigCmd.Flags().StringVar(&talosVersion, "talos-version", "", "the desired Talos version to generate config for (backwards compatibility, e.g. v0.7)")
+	genConfigCmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "destination to output generated files, use '-' to write to stdout (requires single --output-types value)")
+	genConfigCmd.Flags().StringSliceVarP(&outputTypes, "output-types", "t", []string{"init", "controlplane", "join", talosconfigOutputType}, "types of outputs to be generated: init, controlplane, join (worker), talosconfig")
+	genConfigCmd.Flags().BoolVar(&withDocs, "with-docs", true, "render documentation comments in the generated machine configs")
+	genConfigCmd.Flags().BoolVar(&withExamples, "with-examples", true, "render commented out examples in the generated machine configs")
 	genConfigCmd.Flags().StringSliceVar(&registryMirrors, "registry-mirror", []string{}, "list of registry mirrors to use in format: <registry host>=<mirror URL>")
 	genConfigCmd.Flags().BoolVarP(&persistConfig, "persist", "p", true, "the desired persist value for configs")
+	genConfigCmd.Flags().MarkDeprecated("persist", "machine configuration is always persisted") //nolint: errcheck
 }
@@ -29,6 +29,7 @@ var (
 	key          string
 	name         string
 	organization string
+	role         string
 	rsa          bool
 )
 
@@ -127,6 +128,10 @@ var csrCmd = &cobra.Command{
 		opts = append(opts, x509.IPAddresses(ips))
 		opts = append(opts, x509.NotAfter(time.Now().Add(time.Duration(crtHours)*time.Hour)))
 
+		if role != "" {
+			opts = append(opts, x509.Organization(role))
+		}
+
 		csr, err := x509.NewCertificateSigningRequest(keyEC, opts...)
 		if err != nil {
 			return fmt.Errorf("error generating CSR: %s", err)
@@ -265,6 +270,7 @@ func init() {
 	cli.Should(cobra.MarkFlagRequired(csrCmd.Flags(), "key"))
 	csrCmd.Flags().StringVar(&ip, "ip", "", "generate the certificate for this IP address")
 	cli.Should(cobra.MarkFlagRequired(csrCmd.Flags(), "ip"))
+	csrCmd.Flags().StringVar(&role, "role", "", "the role of the client (used in Talos API access rules)")
 
 	genCmd.AddCommand(caCmd, keypairCmd, keyCmd, csrCmd, crtCmd)
 	addCommand(genCmd)
@@ -0,0 +1,60 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mgmt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/mgmt/helpers"
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/mgmt/imager"
+	"github.com/talos-systems/talos/pkg/cli"
+	"github.com/talos-systems/talos/pkg/images"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+var genImageCmdFlags struct {
+	options   imager.Options
+	outputDir string
+}
+
+// genImageCmd represents the gen image command.
+var genImageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Generates Talos disk image for cloud and metal platforms",
+	Long: fmt.Sprintf(`Builds Talos disk image for the platform using the installer container image via Docker,
+and writes the image artifacts to the output directory.
+
+Supported platforms: %s.
+
+Kernel arguments, config URL and container images to pre-load into the image cache are baked into the image.
+The installer runs in a privileged container, as it uses loop devices to build the image.`, strings.Join(imager.Platforms, ", ")),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := os.MkdirAll(genImageCmdFlags.outputDir, 0o755); err != nil {
+			return err
+		}
+
+		return cli.WithContext(context.Background(), func(ctx context.Context) error {
+			return imager.Generate(ctx, genImageCmdFlags.options, genImageCmdFlags.outputDir, os.Stderr)
+		})
+	},
+}
+
+func init() {
+	genImageCmd.Flags().StringVar(&genImageCmdFlags.options.Platform, "platform", "", fmt.Sprintf("the platform to build the image for (%s)", strings.Join(imager.Platforms, ", ")))
+	genImageCmd.Flags().StringVar(&genImageCmdFlags.options.Board, "board", constants.BoardNone, "the SBC board to build the image for (metal platform only)")
+	genImageCmd.Flags().StringVar(&genImageCmdFlags.options.InstallerImage, "install-image", helpers.DefaultImage(images.DefaultInstallerImageRepository), "the installer image used to build the image")
+	genImageCmd.Flags().StringVar(&genImageCmdFlags.options.ConfigSource, "config", "", "the value of "+constants.KernelParamConfig+" kernel argument to bake into the image")
+	genImageCmd.Flags().StringArrayVar(&genImageCmdFlags.options.ExtraKernelArgs, "extra-kernel-arg", nil, "extra argument to pass to the kernel")
+	genImageCmd.Flags().StringVar(&genImageCmdFlags.options.ImageCache, "image-cache", "", "the path to the directory with container image archives to bake into the image cache partition")
+	genImageCmd.Flags().StringVarP(&genImageCmdFlags.outputDir, "output-dir", "o", ".", "the directory to write the image artifacts to")
+	cli.Should(genImageCmd.MarkFlagRequired("platform"))
+	genCmd.AddCommand(genImageCmd)
+}
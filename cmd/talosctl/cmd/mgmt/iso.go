@@ -0,0 +1,65 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mgmt
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/mgmt/helpers"
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/mgmt/imager"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/pkg/cli"
+	"github.com/talos-systems/talos/pkg/images"
+	"github.com/talos-systems/talos/pkg/machinery/config/configloader"
+)
+
+var genISOCmdFlags struct {
+	options   imager.ISOOptions
+	outputDir string
+}
+
+// genISOCmd represents the gen iso command.
+var genISOCmd = &cobra.Command{
+	Use:   "iso",
+	Short: "Generates Talos boot ISO with embedded machine config",
+	Long: `Builds Talos boot ISO using the installer container image via Docker,
+and writes the ISO to the output directory.
+
+If the machine config is specified, it is embedded into the ISO, and Talos reads the config from the ISO on boot,
+so that metal machines can be installed unattended from a single artifact.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if genISOCmdFlags.options.ConfigFile != "" {
+			config, err := configloader.NewFromFile(genISOCmdFlags.options.ConfigFile)
+			if err != nil {
+				return err
+			}
+
+			if err = config.Validate(runtime.ModeMetal); err != nil {
+				return fmt.Errorf("error validating machine config: %w", err)
+			}
+		}
+
+		if err := os.MkdirAll(genISOCmdFlags.outputDir, 0o755); err != nil {
+			return err
+		}
+
+		return cli.WithContext(context.Background(), func(ctx context.Context) error {
+			return imager.GenerateISO(ctx, genISOCmdFlags.options, genISOCmdFlags.outputDir, os.Stderr)
+		})
+	},
+}
+
+func init() {
+	genISOCmd.Flags().StringVar(&genISOCmdFlags.options.ConfigFile, "config", "", "the path to the machine config file to embed into the ISO")
+	genISOCmd.Flags().StringArrayVar(&genISOCmdFlags.options.ExtraKernelArgs, "extra-kernel-arg", nil, "extra argument to pass to the kernel")
+	genISOCmd.Flags().StringVar(&genISOCmdFlags.options.InstallerImage, "install-image", helpers.DefaultImage(images.DefaultInstallerImageRepository), "the installer image used to build the ISO")
+	genISOCmd.Flags().StringVarP(&genISOCmdFlags.outputDir, "output-dir", "o", ".", "the directory to write the ISO to")
+	genCmd.AddCommand(genISOCmd)
+}
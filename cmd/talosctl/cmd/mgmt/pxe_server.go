@@ -0,0 +1,81 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mgmt
+
+import (
+	"context"
+	"fmt"
+	"net"
+	stdruntime "runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/mgmt/helpers"
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/mgmt/pxe"
+	"github.com/talos-systems/talos/pkg/cli"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+var pxeServerCmdFlags struct {
+	addr            string
+	httpPort        int
+	tftpPort        int
+	arch            string
+	vmlinuzPath     string
+	initramfsPath   string
+	extraKernelArgs []string
+	configDir       string
+	tftpRoot        string
+}
+
+// pxeServerCmd represents the pxe-server command.
+var pxeServerCmd = &cobra.Command{
+	Use:   "pxe-server",
+	Short: "Serve Talos boot assets and machine configs to bare-metal machines over iPXE",
+	Long: `Runs HTTP server which serves Talos kernel, initramfs, iPXE boot scripts and machine configs,
+and TFTP server which serves iPXE binaries to chainload iPXE from the legacy PXE firmware.
+
+Machines should be pointed to the iPXE boot script http://<addr>:<http-port>/boot.ipxe by the DHCP server
+(or to the iPXE binary in the TFTP root, which in turn should load the boot script).
+Each machine receives the config from the config directory named after its MAC address (e.g. 52-54-00-12-34-56.yaml),
+or default.yaml if there's no config for the MAC address.
+
+DHCP server is not included, it should be provided by the network.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		addr := net.ParseIP(pxeServerCmdFlags.addr)
+		if addr == nil {
+			return fmt.Errorf("failed to parse listen address %q", pxeServerCmdFlags.addr)
+		}
+
+		server := &pxe.Server{
+			Addr:            addr,
+			HTTPPort:        pxeServerCmdFlags.httpPort,
+			TFTPPort:        pxeServerCmdFlags.tftpPort,
+			KernelPath:      strings.ReplaceAll(pxeServerCmdFlags.vmlinuzPath, constants.ArchVariable, pxeServerCmdFlags.arch),
+			InitramfsPath:   strings.ReplaceAll(pxeServerCmdFlags.initramfsPath, constants.ArchVariable, pxeServerCmdFlags.arch),
+			ExtraKernelArgs: pxeServerCmdFlags.extraKernelArgs,
+			ConfigDir:       pxeServerCmdFlags.configDir,
+			TFTPRoot:        pxeServerCmdFlags.tftpRoot,
+		}
+
+		return cli.WithContext(context.Background(), server.Serve)
+	},
+}
+
+func init() {
+	pxeServerCmd.Flags().StringVar(&pxeServerCmdFlags.addr, "addr", "", "IP address to listen on, machines should be able to reach the server on this address")
+	pxeServerCmd.Flags().IntVar(&pxeServerCmdFlags.httpPort, "http-port", 8080, "HTTP server port")
+	pxeServerCmd.Flags().IntVar(&pxeServerCmdFlags.tftpPort, "tftp-port", 69, "TFTP server port, 0 disables TFTP server")
+	pxeServerCmd.Flags().StringVar(&pxeServerCmdFlags.arch, "arch", stdruntime.GOARCH, "machine architecture")
+	pxeServerCmd.Flags().StringVar(&pxeServerCmdFlags.vmlinuzPath, "vmlinuz-path", helpers.ArtifactPath(constants.KernelAssetWithArch), "the compressed kernel image to serve")
+	pxeServerCmd.Flags().StringVar(&pxeServerCmdFlags.initramfsPath, "initrd-path", helpers.ArtifactPath(constants.InitramfsAssetWithArch), "the initramfs image to serve")
+	pxeServerCmd.Flags().StringSliceVar(&pxeServerCmdFlags.extraKernelArgs, "extra-kernel-args", nil, "additional kernel arguments")
+	pxeServerCmd.Flags().StringVar(&pxeServerCmdFlags.configDir, "config-dir", ".", "directory with machine configs named after the machine MAC address")
+	pxeServerCmd.Flags().StringVar(&pxeServerCmdFlags.tftpRoot, "tftp-root", "", "directory with iPXE binaries to serve over TFTP (e.g. undionly.kpxe, ipxe.efi)")
+	cli.Should(pxeServerCmd.MarkFlagRequired("addr"))
+	addCommand(pxeServerCmd)
+}
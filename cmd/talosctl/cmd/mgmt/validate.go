@@ -6,12 +6,15 @@ package mgmt
 
 import (
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
 	"github.com/talos-systems/talos/pkg/cli"
 	"github.com/talos-systems/talos/pkg/machinery/config/configloader"
+	"github.com/talos-systems/talos/pkg/machinery/config/secretref"
 )
 
 var (
@@ -23,10 +26,22 @@ var (
 var validateCmd = &cobra.Command{
 	Use:   "validate",
 	Short: "Validate config",
-	Long:  ``,
-	Args:  cobra.NoArgs,
+	Long: `Validate the config for the mode.
+
+Secret references in the config (ref+env://NAME, ref+sops://file#/path) are resolved before validation.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config, err := configloader.NewFromFile(validateConfigArg)
+		source, err := ioutil.ReadFile(validateConfigArg)
+		if err != nil {
+			return err
+		}
+
+		source, err = secretref.Resolve(source, secretref.WithBaseDir(filepath.Dir(validateConfigArg)))
+		if err != nil {
+			return fmt.Errorf("failed to resolve secret references: %w", err)
+		}
+
+		config, err := configloader.NewFromBytes(source)
 		if err != nil {
 			return err
 		}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/talos-systems/talos/cmd/talosctl/cmd/mgmt"
 	"github.com/talos-systems/talos/cmd/talosctl/cmd/talos"
+	"github.com/talos-systems/talos/pkg/cli"
 	"github.com/talos-systems/talos/pkg/machinery/client/config"
 )
 
@@ -24,6 +25,9 @@ var rootCmd = &cobra.Command{
 	SilenceErrors:     true,
 	SilenceUsage:      true,
 	DisableAutoGenTag: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return talos.CheckOutputFormat(cmd)
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -39,6 +43,15 @@ func Execute() error {
 	rootCmd.PersistentFlags().StringSliceVarP(&talos.Nodes, "nodes", "n", []string{}, "target the specified nodes")
 	rootCmd.PersistentFlags().StringSliceVarP(&talos.Endpoints, "endpoints", "e", []string{}, "override default endpoints in Talos configuration")
 
+	cli.Should(rootCmd.RegisterFlagCompletionFunc("nodes", talos.CompleteNodes))
+	cli.Should(rootCmd.RegisterFlagCompletionFunc("context", talos.CompleteContexts))
+
+	// mgmt commands use -o for their own flags, so the output format flag is only set on the commands talking to the Talos API
+	for _, cmd := range talos.Commands {
+		cmd.PersistentFlags().VarP(&talos.OutputFormat, "output", "o", "output format: table, wide, json or yaml")
+		cli.Should(cmd.RegisterFlagCompletionFunc("output", talos.CompleteOutputFormats))
+	}
+
 	cmd, err := rootCmd.ExecuteC()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
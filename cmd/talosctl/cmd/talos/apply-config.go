@@ -9,13 +9,20 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 	"github.com/talos-systems/crypto/x509"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 
 	"github.com/talos-systems/talos/internal/pkg/tui/installer"
 	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/client"
+	"github.com/talos-systems/talos/pkg/machinery/config/secretref"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
 var applyConfigCmdFlags struct {
@@ -24,20 +31,51 @@ var applyConfigCmdFlags struct {
 	insecure         bool
 	interactive      bool
 	noReboot         bool
+	dryRun           bool
+	token            string
+	mode             string
 }
 
+// apply-config modes.
+const (
+	applyModeReboot      = "reboot"
+	applyModeNoReboot    = "no-reboot"
+	applyModeInteractive = "interactive"
+)
+
 // applyConfigCmd represents the applyConfiguration command.
 var applyConfigCmd = &cobra.Command{
 	Use:   "apply-config",
 	Short: "Apply a new configuration to a node",
-	Long:  ``,
-	Args:  cobra.NoArgs,
+	Long: `Apply a new configuration to a node.
+
+Mode "reboot" (default) applies the configuration and reboots the node, mode "no-reboot" applies
+the configuration on the next reboot. Mode "interactive" connects to the node in maintenance mode
+(usually with --insecure), shows detected disks and network interfaces, and generates and applies
+the configuration based on the user input.
+
+With --dry-run the configuration is validated and the node reports the configuration changes and
+the actions which would be taken, nothing is changed on the node.
+
+Secret references in the configuration (ref+env://NAME, ref+sops://file#/path) are resolved before the
+configuration is sent to the node. Relative SOPS file paths are resolved against the configuration file directory.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var (
 			cfgBytes []byte
 			e        error
 		)
 
+		switch applyConfigCmdFlags.mode {
+		case applyModeReboot:
+		case applyModeNoReboot:
+			applyConfigCmdFlags.noReboot = true
+		case applyModeInteractive:
+			applyConfigCmdFlags.interactive = true
+		default:
+			return fmt.Errorf("unknown mode %q, expected one of: %s, %s, %s", applyConfigCmdFlags.mode, applyModeReboot, applyModeNoReboot, applyModeInteractive)
+		}
+
 		if applyConfigCmdFlags.filename != "" {
 			cfgBytes, e = ioutil.ReadFile(applyConfigCmdFlags.filename)
 			if e != nil {
@@ -47,14 +85,27 @@ var applyConfigCmd = &cobra.Command{
 			if len(cfgBytes) < 1 {
 				return fmt.Errorf("no configuration data read")
 			}
+
+			cfgBytes, e = secretref.Resolve(cfgBytes, secretref.WithBaseDir(filepath.Dir(applyConfigCmdFlags.filename)))
+			if e != nil {
+				return fmt.Errorf("failed to resolve secret references: %w", e)
+			}
 		} else if !applyConfigCmdFlags.interactive {
 			return fmt.Errorf("no filename supplied for configuration")
 		}
 
+		if applyConfigCmdFlags.dryRun && applyConfigCmdFlags.interactive {
+			return fmt.Errorf("dry run is not supported in interactive mode")
+		}
+
 		withClient := func(f func(context.Context, *client.Client) error) error {
 			if applyConfigCmdFlags.insecure {
 				ctx := context.Background()
 
+				if applyConfigCmdFlags.token != "" {
+					ctx = metadata.AppendToOutgoingContext(ctx, constants.MaintenanceTokenMetadataKey, applyConfigCmdFlags.token)
+				}
+
 				if len(Nodes) != 1 {
 					return fmt.Errorf("insecure mode requires one and only one node, got %d", len(Nodes))
 				}
@@ -133,13 +184,31 @@ var applyConfigCmd = &cobra.Command{
 				return install.Run(conn)
 			}
 
-			if _, err := c.ApplyConfiguration(ctx, &machineapi.ApplyConfigurationRequest{
+			var remotePeer peer.Peer
+
+			resp, err := c.ApplyConfiguration(ctx, &machineapi.ApplyConfigurationRequest{
 				Data:     cfgBytes,
 				NoReboot: applyConfigCmdFlags.noReboot,
-			}); err != nil {
+				DryRun:   applyConfigCmdFlags.dryRun,
+			}, grpc.Peer(&remotePeer))
+			if err != nil {
 				return fmt.Errorf("error applying new configuration: %s", err)
 			}
 
+			if applyConfigCmdFlags.dryRun {
+				defaultNode := client.AddrFromPeer(&remotePeer)
+
+				for _, msg := range resp.Messages {
+					node := defaultNode
+
+					if msg.Metadata != nil {
+						node = msg.Metadata.Hostname
+					}
+
+					printDryRun(os.Stdout, node, msg.DryRun, true)
+				}
+			}
+
 			return nil
 		})
 	},
@@ -151,6 +220,12 @@ func init() {
 	applyConfigCmd.Flags().StringSliceVar(&applyConfigCmdFlags.certFingerprints, "cert-fingerprint", nil, "list of server certificate fingeprints to accept (defaults to no check)")
 	applyConfigCmd.Flags().BoolVar(&applyConfigCmdFlags.interactive, "interactive", false, "apply the config using text based interactive mode")
 	applyConfigCmd.Flags().BoolVar(&applyConfigCmdFlags.noReboot, "no-reboot", false, "apply the config only after the reboot")
+	applyConfigCmd.Flags().StringVarP(&applyConfigCmdFlags.mode, "mode", "m", applyModeReboot, fmt.Sprintf("apply mode: %s, %s or %s", applyModeReboot, applyModeNoReboot, applyModeInteractive))
+	applyConfigCmd.Flags().BoolVar(&applyConfigCmdFlags.dryRun, "dry-run", false, "validate the config and show the changes without applying it")
+	applyConfigCmd.Flags().StringVar(&applyConfigCmdFlags.token, "token", "", "one-time token required by the maintenance service (see talos.maintenance.token kernel argument)")
+
+	applyConfigCmd.Flags().MarkDeprecated("interactive", "use --mode=interactive") //nolint: errcheck
+	applyConfigCmd.Flags().MarkDeprecated("no-reboot", "use --mode=no-reboot")     //nolint: errcheck
 
 	addCommand(applyConfigCmd)
 }
@@ -0,0 +1,182 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"github.com/talos-systems/talos/pkg/attestation"
+	"github.com/talos-systems/talos/pkg/cli"
+	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+)
+
+var attestCmdFlags struct {
+	pcrs           []uint
+	akFingerprints []string
+	events         bool
+}
+
+// attestCmd represents the attest command.
+var attestCmd = &cobra.Command{
+	Use:   "attest",
+	Short: "Verify TPM attestation evidence of the nodes",
+	Long: `Requests the TPM quote of the PCR values and the log of boot measurements from the nodes,
+and verifies that the quote is fresh, signed by the node attestation key and matches the measurement log.
+
+The attestation key is generated by the node TPM and it is stable until the TPM is cleared. Pass the fingerprints
+of the known attestation keys with --ak-fingerprint to verify that the evidence comes from the enrolled nodes.
+
+The command fails if the evidence of any node can't be verified.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			nonce := make([]byte, 32)
+
+			if _, err := rand.Read(nonce); err != nil {
+				return err
+			}
+
+			pcrs := make([]uint32, 0, len(attestCmdFlags.pcrs))
+			for _, pcr := range attestCmdFlags.pcrs {
+				pcrs = append(pcrs, uint32(pcr))
+			}
+
+			var remotePeer peer.Peer
+
+			resp, err := c.Attest(ctx, nonce, pcrs, grpc.Peer(&remotePeer))
+			if err != nil {
+				if resp == nil {
+					return fmt.Errorf("error getting attestation evidence: %w", err)
+				}
+
+				cli.Warning("%s", err)
+			}
+
+			failed := 0
+
+			results := make([]error, len(resp.Messages))
+
+			for i, msg := range resp.Messages {
+				results[i] = attestVerify(msg, nonce)
+
+				if results[i] != nil {
+					failed++
+				}
+			}
+
+			if err = renderResponse(&remotePeer, resp, func() error {
+				return attestRender(&remotePeer, resp, results)
+			}); err != nil {
+				return err
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("attestation of %d node(s) failed", failed)
+			}
+
+			return nil
+		})
+	},
+}
+
+// attestEvidence converts the API response to the attestation evidence.
+func attestEvidence(msg *machineapi.Attest) *attestation.Evidence {
+	evidence := &attestation.Evidence{
+		Quote:     msg.Quote,
+		Signature: msg.Signature,
+		AKPublic:  msg.AkPublic,
+		PCRs:      map[int][]byte{},
+	}
+
+	for _, pcr := range msg.Pcrs {
+		evidence.PCRs[int(pcr.Pcr)] = pcr.Value
+	}
+
+	for _, event := range msg.Events {
+		evidence.Events = append(evidence.Events, attestation.Event{
+			PCR:         int(event.Pcr),
+			Type:        event.Type,
+			Digest:      event.Digest,
+			Description: event.Description,
+		})
+	}
+
+	return evidence
+}
+
+func attestVerify(msg *machineapi.Attest, nonce []byte) error {
+	if err := attestation.Verify(attestEvidence(msg), nonce); err != nil {
+		return err
+	}
+
+	if len(attestCmdFlags.akFingerprints) == 0 {
+		return nil
+	}
+
+	fingerprint := attestation.AKFingerprint(msg.AkPublic)
+
+	for _, expected := range attestCmdFlags.akFingerprints {
+		if expected == fingerprint {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown attestation key %s", fingerprint)
+}
+
+func attestRender(remotePeer *peer.Peer, resp *machineapi.AttestResponse, results []error) error {
+	defaultNode := client.AddrFromPeer(remotePeer)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+
+	if attestCmdFlags.events {
+		fmt.Fprintln(w, "NODE\tPCR\tTYPE\tDIGEST\tDESCRIPTION")
+	} else {
+		fmt.Fprintln(w, "NODE\tAK FINGERPRINT\tSTATUS")
+	}
+
+	for i, msg := range resp.Messages {
+		node := defaultNode
+
+		if msg.Metadata != nil {
+			node = msg.Metadata.Hostname
+		}
+
+		if attestCmdFlags.events {
+			for _, event := range msg.Events {
+				fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", node, event.Pcr, event.Type, hex.EncodeToString(event.Digest), event.Description)
+			}
+
+			continue
+		}
+
+		status := "OK"
+
+		if results[i] != nil {
+			status = fmt.Sprintf("FAILED: %s", results[i])
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\n", node, attestation.AKFingerprint(msg.AkPublic), status)
+	}
+
+	return w.Flush()
+}
+
+func init() {
+	attestCmd.Flags().UintSliceVar(&attestCmdFlags.pcrs, "pcrs", nil, "PCRs to quote (defaults to PCRs 0-7 and the Talos measurement PCR 11)")
+	attestCmd.Flags().StringSliceVar(&attestCmdFlags.akFingerprints, "ak-fingerprint", nil, "fingerprints of the trusted attestation keys")
+	attestCmd.Flags().BoolVar(&attestCmdFlags.events, "events", false, "display the log of boot measurements")
+	addCommand(withStructuredOutput(attestCmd))
+}
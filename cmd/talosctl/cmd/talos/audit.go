@@ -0,0 +1,141 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/output"
+	"github.com/talos-systems/talos/pkg/cli"
+	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+)
+
+var auditCmdFlags struct {
+	tailEvents int32
+}
+
+// auditCmd represents the audit command.
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the log of the mutating API calls",
+	Long: `Shows the log of the mutating API calls recorded by the nodes: the client certificate, the method,
+the digest of the request and the result of the call.
+
+Every recorded call includes the hash of the previous one, so the log modified on the node fails the verification.
+The command fails if the log of any node can't be verified.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			var remotePeer peer.Peer
+
+			resp, err := c.AuditLog(ctx, auditCmdFlags.tailEvents, grpc.Peer(&remotePeer))
+			if err != nil {
+				if resp == nil {
+					return fmt.Errorf("error getting audit log: %w", err)
+				}
+
+				cli.Warning("%s", err)
+			}
+
+			if err = renderResponse(&remotePeer, resp, func() error {
+				return auditRender(&remotePeer, resp, OutputFormat == output.Wide)
+			}); err != nil {
+				return err
+			}
+
+			defaultNode := client.AddrFromPeer(&remotePeer)
+
+			failed := 0
+
+			for _, msg := range resp.Messages {
+				if msg.VerificationError != "" {
+					node := defaultNode
+
+					if msg.Metadata != nil {
+						node = msg.Metadata.Hostname
+					}
+
+					cli.Warning("%s: audit log verification failed: %s", node, msg.VerificationError)
+
+					failed++
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("audit log verification of %d node(s) failed", failed)
+			}
+
+			return nil
+		})
+	},
+}
+
+func auditRender(remotePeer *peer.Peer, resp *machineapi.AuditLogResponse, wide bool) error {
+	defaultNode := client.AddrFromPeer(remotePeer)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+
+	if wide {
+		fmt.Fprintln(w, "NODE\tSEQ\tTIME\tSUBJECT\tFINGERPRINT\tMETHOD\tREQUEST DIGEST\tRESULT")
+	} else {
+		fmt.Fprintln(w, "NODE\tSEQ\tTIME\tSUBJECT\tMETHOD\tRESULT")
+	}
+
+	for _, msg := range resp.Messages {
+		node := defaultNode
+
+		if msg.Metadata != nil {
+			node = msg.Metadata.Hostname
+		}
+
+		for _, event := range msg.Events {
+			timestamp, _ := ptypes.Timestamp(event.Timestamp) //nolint: errcheck
+
+			if wide {
+				fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n", node, event.Sequence, timestamp.Local().Format(time.RFC3339), auditSubject(event), event.Fingerprint,
+					event.Method, event.RequestDigest, auditResult(event))
+
+				continue
+			}
+
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", node, event.Sequence, timestamp.Local().Format(time.RFC3339), auditSubject(event), event.Method, auditResult(event))
+		}
+	}
+
+	return w.Flush()
+}
+
+// auditSubject describes the client which made the call.
+func auditSubject(event *machineapi.AuditEvent) string {
+	if event.GetSubject() == "" {
+		return "<unknown>"
+	}
+
+	return event.GetSubject()
+}
+
+// auditResult describes the result of the call.
+func auditResult(event *machineapi.AuditEvent) string {
+	if event.GetError() == "" {
+		return event.GetCode()
+	}
+
+	return fmt.Sprintf("%s: %s", event.GetCode(), event.GetError())
+}
+
+func init() {
+	auditCmd.Flags().Int32VarP(&auditCmdFlags.tailEvents, "tail", "", 0, "number of the latest calls to show, 0 shows all stored calls")
+	addCommand(withStructuredOutput(auditCmd))
+}
@@ -0,0 +1,149 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"github.com/talos-systems/talos/pkg/cli"
+	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+)
+
+var certificatesCmdFlags struct {
+	within       time.Duration
+	onlyExpiring bool
+}
+
+// certificatesCmd represents the certificates command.
+var certificatesCmd = &cobra.Command{
+	Use:     "certificates",
+	Aliases: []string{"certs"},
+	Short:   "Report certificate expiration dates",
+	Long: `Collects certificates used by Talos and Kubernetes on the nodes (apid, trustd, kubelet, etcd, control plane
+and the certificates in Kubernetes PKI directories) and reports their expiration dates.
+
+Certificates which expire within the --within window are flagged, and the command fails if any certificate is flagged.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			var remotePeer peer.Peer
+
+			resp, err := c.Certificates(ctx, grpc.Peer(&remotePeer))
+			if err != nil {
+				if resp == nil {
+					return fmt.Errorf("error getting certificates: %w", err)
+				}
+
+				cli.Warning("%s", err)
+			}
+
+			return renderResponse(&remotePeer, resp, func() error {
+				return certificatesRender(&remotePeer, resp)
+			})
+		})
+	},
+}
+
+type certificateRow struct {
+	node     string
+	cert     *machineapi.Certificate
+	notAfter time.Time
+}
+
+// certificateRows returns the certificates from the response sorted by the expiration time.
+func certificateRows(remotePeer *peer.Peer, resp *machineapi.CertificatesResponse) ([]certificateRow, error) {
+	defaultNode := client.AddrFromPeer(remotePeer)
+
+	var rows []certificateRow
+
+	for _, msg := range resp.Messages {
+		node := defaultNode
+
+		if msg.Metadata != nil {
+			node = msg.Metadata.Hostname
+		}
+
+		for _, cert := range msg.Certificates {
+			notAfter, err := ptypes.Timestamp(cert.NotAfter)
+			if err != nil {
+				return nil, err
+			}
+
+			rows = append(rows, certificateRow{
+				node:     node,
+				cert:     cert,
+				notAfter: notAfter,
+			})
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].notAfter.Before(rows[j].notAfter)
+	})
+
+	return rows, nil
+}
+
+func certificatesRender(remotePeer *peer.Peer, resp *machineapi.CertificatesResponse) error {
+	rows, err := certificateRows(remotePeer, resp)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tSOURCE\tSUBJECT\tCA\tNOT AFTER\tEXPIRES\tSTATUS")
+
+	flagged := 0
+
+	for _, row := range rows {
+		status := "OK"
+
+		switch {
+		case row.notAfter.Before(now):
+			status = "EXPIRED"
+		case row.notAfter.Before(now.Add(certificatesCmdFlags.within)):
+			status = "EXPIRING"
+		}
+
+		if status != "OK" {
+			flagged++
+		} else if certificatesCmdFlags.onlyExpiring {
+			continue
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\t%s\t%s\n",
+			row.node, row.cert.Source, row.cert.Subject, row.cert.IsCa, row.notAfter.Format(time.RFC3339), humanize.Time(row.notAfter), status)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if flagged > 0 {
+		return fmt.Errorf("%d certificate(s) expired or expire within %s", flagged, certificatesCmdFlags.within)
+	}
+
+	return nil
+}
+
+func init() {
+	certificatesCmd.Flags().DurationVar(&certificatesCmdFlags.within, "within", 30*24*time.Hour, "flag certificates which expire within the duration")
+	certificatesCmd.Flags().BoolVar(&certificatesCmdFlags.onlyExpiring, "only-expiring", false, "display only expired and expiring certificates")
+	addCommand(withStructuredOutput(certificatesCmd))
+}
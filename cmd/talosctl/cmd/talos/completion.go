@@ -0,0 +1,205 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/output"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+	"github.com/talos-systems/talos/pkg/machinery/client/config"
+)
+
+// completionTimeout limits the time spent on the API calls building the dynamic completions,
+// so that the shell doesn't hang if the nodes are not reachable.
+const completionTimeout = 5 * time.Second
+
+// completeWithClient runs the API calls to build the dynamic completions.
+//
+// Errors are ignored, as there's no way to report them to the user during completion,
+// so the completions are built from the data which was fetched successfully.
+func completeWithClient(action func(ctx context.Context, c *client.Client) []string) []string {
+	var completions []string
+
+	//nolint: errcheck
+	WithClientNoNodes(func(ctx context.Context, c *client.Client) error {
+		ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+		defer cancel()
+
+		completions = action(ctx, c)
+
+		return nil
+	})
+
+	return completions
+}
+
+// withCompletionNodes sets the nodes from the flags or from the config context on the request context.
+func withCompletionNodes(ctx context.Context, c *client.Client) context.Context {
+	nodes := Nodes
+
+	if len(nodes) == 0 {
+		if configContext := c.GetConfigContext(); configContext != nil {
+			nodes = configContext.Nodes
+		}
+	}
+
+	if len(nodes) == 0 {
+		return ctx
+	}
+
+	return client.WithNodes(ctx, nodes...)
+}
+
+// uniqueSorted removes duplicate and empty strings and sorts the list.
+func uniqueSorted(items []string) []string {
+	seen := map[string]struct{}{}
+	result := make([]string, 0, len(items))
+
+	for _, item := range items {
+		if item == "" {
+			continue
+		}
+
+		if _, ok := seen[item]; ok {
+			continue
+		}
+
+		seen[item] = struct{}{}
+
+		result = append(result, item)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// CompleteNodes completes --nodes flag with the nodes and endpoints of the talosconfig context,
+// and with the addresses of the cluster members discovered by the nodes.
+//
+// As --nodes accepts a comma-separated list, only the last element of the list is completed.
+func CompleteNodes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var candidates []string
+
+	if cfg, err := config.Open(Talosconfig); err == nil {
+		contextName := cfg.Context
+		if Cmdcontext != "" {
+			contextName = Cmdcontext
+		}
+
+		if configContext, ok := cfg.Contexts[contextName]; ok {
+			candidates = append(candidates, configContext.Nodes...)
+			candidates = append(candidates, configContext.Endpoints...)
+		}
+	}
+
+	candidates = append(candidates, completeWithClient(func(ctx context.Context, c *client.Client) []string {
+		// errors for some of the nodes are ignored, as the response still contains the members reported by other nodes
+		resp, _ := c.ClusterMembers(ctx)
+		if resp == nil {
+			return nil
+		}
+
+		var addresses []string
+
+		for _, msg := range resp.Messages {
+			for _, member := range msg.Members {
+				addresses = append(addresses, member.Addresses...)
+			}
+		}
+
+		return addresses
+	})...)
+
+	var prefix string
+
+	if idx := strings.LastIndex(toComplete, ","); idx >= 0 {
+		prefix = toComplete[:idx+1]
+	}
+
+	listed := map[string]struct{}{}
+
+	for _, node := range strings.Split(prefix, ",") {
+		listed[node] = struct{}{}
+	}
+
+	completions := []string{}
+
+	for _, candidate := range uniqueSorted(candidates) {
+		if _, ok := listed[candidate]; ok {
+			continue
+		}
+
+		completions = append(completions, prefix+candidate)
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteContexts completes talosconfig context names.
+func CompleteContexts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := config.Open(Talosconfig)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	contexts := make([]string, 0, len(cfg.Contexts))
+
+	for name := range cfg.Contexts {
+		contexts = append(contexts, name)
+	}
+
+	sort.Strings(contexts)
+
+	return contexts, cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteOutputFormats completes --output flag.
+func CompleteOutputFormats(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	formats := make([]string, len(output.Formats))
+
+	for i := range output.Formats {
+		formats[i] = string(output.Formats[i])
+	}
+
+	return formats, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeServiceIDs completes the IDs of the services running on the nodes.
+func completeServiceIDs() []string {
+	return uniqueSorted(completeWithClient(func(ctx context.Context, c *client.Client) []string {
+		resp, _ := c.ServiceList(withCompletionNodes(ctx, c))
+		if resp == nil {
+			return nil
+		}
+
+		var ids []string
+
+		for _, msg := range resp.Messages {
+			for _, svc := range msg.Services {
+				ids = append(ids, svc.Id)
+			}
+		}
+
+		return ids
+	}))
+}
+
+// completeServiceArgs completes the service ID as the first argument.
+//
+// Kubernetes containers (--kubernetes) are not completed.
+func completeServiceArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 || kubernetes {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return completeServiceIDs(), cobra.ShellCompDirectiveNoFileComp
+}
@@ -5,6 +5,7 @@
 package talos
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
@@ -12,10 +13,16 @@ import (
 	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/spf13/cobra"
 
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/helpers"
 	"github.com/talos-systems/talos/pkg/cli"
+	"github.com/talos-systems/talos/pkg/grpc/middleware/authz"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/client"
 	clientconfig "github.com/talos-systems/talos/pkg/machinery/client/config"
 )
 
@@ -83,6 +90,13 @@ var configContextCmd = &cobra.Command{
 	Aliases: []string{"use-context"},
 	Long:    ``,
 	Args:    cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return CompleteContexts(cmd, args, toComplete)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		context := args[0]
 
@@ -263,8 +277,166 @@ var configMergeCmd = &cobra.Command{
 	},
 }
 
+var configNewCmdFlags struct {
+	roles  []string
+	crtTTL time.Duration
+}
+
+// configNewCmd represents the config new command.
+var configNewCmd = &cobra.Command{
+	Use:   "new [<path>]",
+	Short: "Generate a new client configuration file",
+	Long: `Generates a new client configuration file with a fresh client certificate signed by the Talos CA.
+
+The certificate is issued by the node (which should be a control plane node), endpoints and nodes
+are copied from the current context. Default path is "talosconfig" in the current directory.
+
+Client certificates with the admin role can be renewed in place with "talosctl config renew".`,
+	Args: cobra.RangeArgs(0, 1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := "talosconfig"
+		if len(args) > 0 {
+			path = args[0]
+		}
+
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists", path)
+		}
+
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			if len(Nodes) != 1 {
+				return fmt.Errorf("client configuration should be generated by a single node")
+			}
+
+			resp, err := c.GenerateClientConfiguration(ctx, &machine.GenerateClientConfigurationRequest{
+				Roles:  configNewCmdFlags.roles,
+				CrtTtl: ptypes.DurationProto(configNewCmdFlags.crtTTL),
+			})
+			if err != nil {
+				return fmt.Errorf("error generating client configuration: %w", err)
+			}
+
+			config, err := clientconfig.FromBytes(resp.Messages[0].Talosconfig)
+			if err != nil {
+				return fmt.Errorf("error parsing generated client configuration: %w", err)
+			}
+
+			configContext := c.GetConfigContext()
+
+			for _, newContext := range config.Contexts {
+				newContext.Endpoints = configContext.Endpoints
+				newContext.Nodes = configContext.Nodes
+			}
+
+			if err = config.Save(path); err != nil {
+				return fmt.Errorf("error writing config: %w", err)
+			}
+
+			return nil
+		})
+	},
+}
+
+var configRenewCmdFlags struct {
+	force bool
+}
+
+// configRenewCmd represents the config renew command.
+var configRenewCmd = &cobra.Command{
+	Use:   "renew",
+	Short: "Renew the client certificate of the current context",
+	Long: `Renews the client certificate of the current context with the same roles and lifetime,
+the talosconfig is updated in place.
+
+The certificate is issued by the node (which should be a control plane node). Only the certificates
+with the admin role can be renewed. The certificate is renewed once less than a third of its lifetime is left,
+use --force to renew it earlier.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := openConfigAndContext(Cmdcontext)
+		if err != nil {
+			return err
+		}
+
+		contextName := Cmdcontext
+		if contextName == "" {
+			contextName = cfg.Context
+		}
+
+		configContext := cfg.Contexts[contextName]
+
+		if configContext.Crt == "" {
+			return fmt.Errorf("context %q has no client certificate", contextName)
+		}
+
+		crt, err := helpers.ClientCertificate(configContext)
+		if err != nil {
+			return fmt.Errorf("error decoding client certificate: %w", err)
+		}
+
+		if !configRenewCmdFlags.force && !helpers.ClientCertificateNeedsRenewal(crt, time.Now()) {
+			fmt.Printf("client certificate expires at %s, renewal is not required yet\n", crt.NotAfter)
+
+			return nil
+		}
+
+		roles := crt.Subject.Organization
+
+		if !authz.IsAdmin(roles) {
+			return fmt.Errorf("client certificate with roles %v can't be renewed, request a new talosconfig from the cluster administrator", roles)
+		}
+
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			if len(Nodes) != 1 {
+				return fmt.Errorf("client certificate should be renewed by a single node")
+			}
+
+			resp, err := c.GenerateClientConfiguration(ctx, &machine.GenerateClientConfigurationRequest{
+				Roles:  roles,
+				CrtTtl: ptypes.DurationProto(crt.NotAfter.Sub(crt.NotBefore)),
+			})
+			if err != nil {
+				return fmt.Errorf("error renewing client certificate: %w", err)
+			}
+
+			configContext.Crt = base64.StdEncoding.EncodeToString(resp.Messages[0].Crt)
+			configContext.Key = base64.StdEncoding.EncodeToString(resp.Messages[0].Key)
+
+			if err = cfg.Save(Talosconfig); err != nil {
+				return fmt.Errorf("error writing config: %w", err)
+			}
+
+			fmt.Printf("client certificate expiring at %s was renewed\n", crt.NotAfter)
+
+			return nil
+		})
+	},
+}
+
+// warnClientCertificateExpiry warns about the client certificate of the current context which should be renewed.
+func warnClientCertificateExpiry(c *client.Client) {
+	configContext := c.GetConfigContext()
+	if configContext == nil || configContext.Crt == "" {
+		return
+	}
+
+	crt, err := helpers.ClientCertificate(configContext)
+	if err != nil || !helpers.ClientCertificateNeedsRenewal(crt, time.Now()) {
+		return
+	}
+
+	if authz.IsAdmin(crt.Subject.Organization) {
+		fmt.Fprintf(os.Stderr, "warning: client certificate expires at %s, run \"talosctl config renew\" to renew it\n", crt.NotAfter)
+	} else {
+		fmt.Fprintf(os.Stderr, "warning: client certificate expires at %s, request a new talosconfig from the cluster administrator\n", crt.NotAfter)
+	}
+}
+
 func init() {
-	configCmd.AddCommand(configContextCmd, configEndpointCmd, configNodeCmd, configAddCmd, configGenerateCmd, configMergeCmd, configGetContexts)
+	configCmd.AddCommand(configContextCmd, configEndpointCmd, configNodeCmd, configAddCmd, configGenerateCmd, configMergeCmd, configGetContexts, configNewCmd, configRenewCmd)
+	configRenewCmd.Flags().BoolVar(&configRenewCmdFlags.force, "force", false, "renew the certificate even if it doesn't expire soon")
+	configNewCmd.Flags().StringSliceVar(&configNewCmdFlags.roles, "roles", nil, "roles (subject organizations) in the client certificate")
+	configNewCmd.Flags().DurationVar(&configNewCmdFlags.crtTTL, "crt-ttl", 24*time.Hour, "client certificate TTL")
 	configAddCmd.Flags().StringVar(&ca, "ca", "", "the path to the CA certificate")
 	configAddCmd.Flags().StringVar(&crt, "crt", "", "the path to the certificate")
 	configAddCmd.Flags().StringVar(&key, "key", "", "the path to the key")
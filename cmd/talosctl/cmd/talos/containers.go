@@ -11,12 +11,16 @@ import (
 	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	criconstants "github.com/containerd/cri/pkg/constants"
+	"github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/peer"
 
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/output"
 	"github.com/talos-systems/talos/pkg/cli"
 	"github.com/talos-systems/talos/pkg/machinery/api/common"
 	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
@@ -55,14 +59,21 @@ var containersCmd = &cobra.Command{
 				cli.Warning("%s", err)
 			}
 
-			return containerRender(&remotePeer, resp)
+			return renderResponse(&remotePeer, resp, func() error {
+				return containerRender(&remotePeer, resp, OutputFormat == output.Wide)
+			})
 		})
 	},
 }
 
-func containerRender(remotePeer *peer.Peer, resp *machineapi.ContainersResponse) error {
+func containerRender(remotePeer *peer.Peer, resp *machineapi.ContainersResponse, wide bool) error {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(w, "NODE\tNAMESPACE\tID\tIMAGE\tPID\tSTATUS")
+
+	if wide {
+		fmt.Fprintln(w, "NODE\tNAMESPACE\tID\tIMAGE\tPID\tSTATUS\tRESTARTS\tCREATED\tCPU\tMEMORY\tSANDBOX")
+	} else {
+		fmt.Fprintln(w, "NODE\tNAMESPACE\tID\tIMAGE\tPID\tSTATUS")
+	}
 
 	defaultNode := client.AddrFromPeer(remotePeer)
 
@@ -86,7 +97,23 @@ func containerRender(remotePeer *peer.Peer, resp *machineapi.ContainersResponse)
 				node = resp.Metadata.Hostname
 			}
 
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", node, p.Namespace, display, p.Image, p.Pid, p.Status)
+			if !wide {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", node, p.Namespace, display, p.Image, p.Pid, p.Status)
+
+				continue
+			}
+
+			created := ""
+
+			if p.CreatedAt != nil {
+				if ts, err := ptypes.Timestamp(p.CreatedAt); err == nil {
+					created = time.Since(ts).Round(time.Second).String() + " ago"
+				}
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%d\t%s\t%s\t%s\t%s\n",
+				node, p.Namespace, display, p.Image, p.Pid, p.Status, p.RestartCount, created,
+				time.Duration(p.CpuUsage).Round(time.Millisecond), humanize.Bytes(p.MemoryUsage), p.PodSandboxId)
 		}
 	}
 
@@ -96,5 +123,5 @@ func containerRender(remotePeer *peer.Peer, resp *machineapi.ContainersResponse)
 func init() {
 	containersCmd.Flags().BoolVarP(&kubernetes, "kubernetes", "k", false, "use the k8s.io containerd namespace")
 	containersCmd.Flags().BoolVarP(&useCRI, "use-cri", "c", false, "use the CRI driver")
-	addCommand(containersCmd)
+	addCommand(withStructuredOutput(containersCmd))
 }
@@ -13,6 +13,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/talos-systems/talos/cmd/talosctl/cmd/talos/dashboard/data"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/client"
 )
 
@@ -254,7 +255,7 @@ func (source *APISource) gather() *data.Data {
 			return nil
 		},
 		func() error {
-			resp, err := source.MachineClient.Processes(source.ctx, &empty.Empty{})
+			resp, err := source.MachineClient.Processes(source.ctx, &machine.ProcessesRequest{})
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,127 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"github.com/talos-systems/talos/pkg/cli"
+	storageapi "github.com/talos-systems/talos/pkg/machinery/api/storage"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+)
+
+var disksCmdFlags struct {
+	partitions bool
+}
+
+// disksCmd represents the disks command.
+var disksCmd = &cobra.Command{
+	Use:     "disks",
+	Aliases: []string{"disk"},
+	Short:   "List disks",
+	Long:    `List block devices with their model and size, or with --partitions the partitions with the filesystem type, label, UUID and mount point.`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			var remotePeer peer.Peer
+
+			if disksCmdFlags.partitions {
+				resp, err := c.Partitions(ctx, grpc.Peer(&remotePeer))
+				if err != nil {
+					if resp == nil {
+						return fmt.Errorf("error getting partitions: %s", err)
+					}
+
+					cli.Warning("%s", err)
+				}
+
+				return renderResponse(&remotePeer, resp, func() error {
+					return partitionsRender(&remotePeer, resp)
+				})
+			}
+
+			resp, err := c.Disks(ctx, grpc.Peer(&remotePeer))
+			if err != nil {
+				if resp == nil {
+					return fmt.Errorf("error getting disks: %s", err)
+				}
+
+				cli.Warning("%s", err)
+			}
+
+			return renderResponse(&remotePeer, resp, func() error {
+				return disksRender(&remotePeer, resp)
+			})
+		})
+	},
+}
+
+func disksRender(remotePeer *peer.Peer, resp *storageapi.DisksResponse) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tDEV\tMODEL\tSERIAL\tTRANSPORT\tSIZE\tPATHS")
+
+	defaultNode := client.AddrFromPeer(remotePeer)
+
+	for _, msg := range resp.Messages {
+		node := defaultNode
+
+		if msg.Metadata != nil {
+			node = msg.Metadata.Hostname
+		}
+
+		for _, disk := range msg.Disks {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				node, disk.DeviceName, orDash(disk.Model), orDash(disk.Serial), orDash(disk.Transport), humanize.Bytes(disk.Size),
+				orDash(strings.Join(disk.Paths, ",")))
+		}
+	}
+
+	return w.Flush()
+}
+
+func partitionsRender(remotePeer *peer.Peer, resp *storageapi.PartitionsResponse) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tDEV\tLABEL\tSIZE\tFILESYSTEM\tFS LABEL\tFS UUID\tENCRYPTED\tMOUNTED ON")
+
+	defaultNode := client.AddrFromPeer(remotePeer)
+
+	for _, msg := range resp.Messages {
+		node := defaultNode
+
+		if msg.Metadata != nil {
+			node = msg.Metadata.Hostname
+		}
+
+		for _, part := range msg.Partitions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%t\t%s\n",
+				node, part.DeviceName, orDash(part.Label), humanize.Bytes(part.Size), orDash(part.FilesystemType),
+				orDash(part.FilesystemLabel), orDash(part.FilesystemUuid), part.Encrypted, orDash(part.MountPoint))
+		}
+	}
+
+	return w.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+func init() {
+	disksCmd.Flags().BoolVarP(&disksCmdFlags.partitions, "partitions", "p", false, "list partitions")
+	addCommand(withStructuredOutput(disksCmd))
+}
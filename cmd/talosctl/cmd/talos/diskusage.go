@@ -16,6 +16,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/output"
 	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/client"
 )
@@ -51,6 +52,14 @@ var duCmd = &cobra.Command{
 				return fmt.Errorf("error fetching logs: %s", err)
 			}
 
+			var ow *output.Writer
+
+			if OutputFormat.Structured() {
+				if ow, err = newOutputWriter(); err != nil {
+					return err
+				}
+			}
+
 			addedHeader := false
 			defaultNode := client.RemotePeer(stream.Context())
 
@@ -96,6 +105,20 @@ var duCmd = &cobra.Command{
 					node = info.Metadata.Hostname
 				}
 
+				if info.Metadata != nil && info.Metadata.Error != "" {
+					fmt.Fprintf(os.Stderr, "%s: %s\n", node, info.Metadata.Error)
+
+					continue
+				}
+
+				if ow != nil {
+					if err = ow.WriteMessage(node, info); err != nil {
+						return err
+					}
+
+					continue
+				}
+
 				if !addedHeader {
 					if multipleNodes {
 						fmt.Fprintln(w, "NODE\tSIZE\tNAME")
@@ -105,12 +128,6 @@ var duCmd = &cobra.Command{
 					addedHeader = true
 				}
 
-				if info.Metadata != nil && info.Metadata.Error != "" {
-					fmt.Fprintf(os.Stderr, "%s: %s\n", node, info.Metadata.Error)
-
-					continue
-				}
-
 				if multipleNodes {
 					pattern = "%s\t%s\t%s\n"
 					args = append([]interface{}{node}, args...)
@@ -127,5 +144,5 @@ func init() {
 	duCmd.Flags().BoolVarP(&all, "all", "a", false, "write counts for all files, not just directories")
 	duCmd.Flags().Int64VarP(&threshold, "threshold", "t", 0, "threshold exclude entries smaller than SIZE if positive, or entries greater than SIZE if negative")
 	duCmd.Flags().Int32VarP(&recursionDepth, "depth", "d", 0, "maximum recursion depth")
-	addCommand(duCmd)
+	addCommand(withStructuredOutput(duCmd))
 }
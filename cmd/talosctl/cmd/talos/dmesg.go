@@ -6,6 +6,7 @@ package talos
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -14,10 +15,15 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/client"
 )
 
-var dmesgTail bool
+var dmesgCmdFlags struct {
+	tail      bool
+	tailLines int32
+	json      bool
+}
 
 // dmesgCmd represents the dmesg command.
 var dmesgCmd = &cobra.Command{
@@ -27,7 +33,12 @@ var dmesgCmd = &cobra.Command{
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return WithClient(func(ctx context.Context, c *client.Client) error {
-			stream, err := c.Dmesg(ctx, follow, dmesgTail)
+			stream, err := c.DmesgGeneric(ctx, &machineapi.DmesgRequest{
+				Follow:     follow,
+				Tail:       dmesgCmdFlags.tail,
+				TailLines:  dmesgCmdFlags.tailLines,
+				Structured: dmesgCmdFlags.json,
+			})
 			if err != nil {
 				return fmt.Errorf("error getting dmesg: %w", err)
 			}
@@ -53,9 +64,19 @@ var dmesgCmd = &cobra.Command{
 					}
 				}
 
-				if resp.Bytes != nil {
-					fmt.Printf("%s: %s", node, resp.Bytes)
+				if resp.Bytes == nil {
+					continue
+				}
+
+				if dmesgCmdFlags.json {
+					if err = dmesgPrintJSON(node, resp.Bytes); err != nil {
+						return err
+					}
+
+					continue
 				}
+
+				fmt.Printf("%s: %s", node, resp.Bytes)
 			}
 
 			return nil
@@ -63,8 +84,23 @@ var dmesgCmd = &cobra.Command{
 	},
 }
 
+// dmesgPrintJSON prints structured kernel log message as a JSON line with the node added.
+func dmesgPrintJSON(node string, data []byte) error {
+	var msg map[string]interface{}
+
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return fmt.Errorf("error decoding kernel log message: %w", err)
+	}
+
+	msg["node"] = node
+
+	return json.NewEncoder(os.Stdout).Encode(msg)
+}
+
 func init() {
 	addCommand(dmesgCmd)
 	dmesgCmd.Flags().BoolVarP(&follow, "follow", "f", false, "specify if the kernel log should be streamed")
-	dmesgCmd.Flags().BoolVarP(&dmesgTail, "tail", "", false, "specify if only new messages should be sent (makes sense only when combined with --follow)")
+	dmesgCmd.Flags().BoolVarP(&dmesgCmdFlags.tail, "tail", "", false, "specify if only new messages should be sent (makes sense only when combined with --follow)")
+	dmesgCmd.Flags().Int32VarP(&dmesgCmdFlags.tailLines, "tail-lines", "", 0, "number of the last messages to show, 0 shows all messages")
+	dmesgCmd.Flags().BoolVarP(&dmesgCmdFlags.json, "json", "", false, "print each message as a JSON object with facility, priority and timestamp fields")
 }
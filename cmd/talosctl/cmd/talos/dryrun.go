@@ -0,0 +1,56 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
+)
+
+// printDryRun prints the changes and the actions reported by the node for the dry run request.
+func printDryRun(w io.Writer, node string, dryRun *machineapi.DryRun, showDiff bool) {
+	fmt.Fprintf(w, "NODE: %s\n", node)
+
+	if dryRun == nil {
+		fmt.Fprintln(w, "dry run is not supported by the node")
+
+		return
+	}
+
+	if len(dryRun.ConfigChanges) > 0 {
+		fmt.Fprintln(w, "config changes:")
+
+		for _, change := range dryRun.ConfigChanges {
+			fmt.Fprintf(w, "  %-8s %s\n", change.Operation, change.Path)
+		}
+	}
+
+	if showDiff && dryRun.ConfigDiff != "" {
+		fmt.Fprintln(w, "config diff:")
+
+		for _, line := range strings.SplitAfter(strings.TrimSuffix(dryRun.ConfigDiff, "\n"), "\n") {
+			fmt.Fprintf(w, "  %s", line)
+		}
+
+		fmt.Fprintln(w)
+	}
+
+	if len(dryRun.Actions) == 0 {
+		fmt.Fprintln(w, "no changes")
+
+		return
+	}
+
+	fmt.Fprintln(w, "actions:")
+
+	for _, action := range dryRun.Actions {
+		fmt.Fprintf(w, "  - %s\n", action)
+	}
+
+	fmt.Fprintf(w, "reboot required: %v\n", dryRun.RebootRequired)
+}
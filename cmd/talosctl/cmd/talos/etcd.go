@@ -10,9 +10,15 @@ import (
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
+	humanize "github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
 
+	"github.com/talos-systems/talos/pkg/cli"
 	"github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/client"
 )
@@ -56,50 +62,129 @@ var etcdMemberListCmd = &cobra.Command{
 	Long:  ``,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return WithClient(func(ctx context.Context, c *client.Client) error {
+			var remotePeer peer.Peer
+
 			response, err := c.MachineClient.EtcdMemberList(ctx, &machine.EtcdMemberListRequest{
 				QueryLocal: true,
-			})
+			}, grpc.Peer(&remotePeer))
 			if err != nil {
 				return fmt.Errorf("error getting members: %w", err)
 			}
 
-			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-			node := ""
-			pattern := "%s\t"
+			return renderResponse(&remotePeer, response, func() error {
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+				node := ""
+				pattern := "%s\t"
 
-			for i, message := range response.Messages {
-				if message.Metadata != nil && message.Metadata.Hostname != "" {
-					node = message.Metadata.Hostname
-				}
+				for i, message := range response.Messages {
+					if message.Metadata != nil && message.Metadata.Hostname != "" {
+						node = message.Metadata.Hostname
+					}
 
-				if len(message.Members) == 0 {
-					continue
-				}
+					if len(message.Members) == 0 {
+						continue
+					}
+
+					if i == 0 {
+						if node != "" {
+							fmt.Fprintln(w, "NODE\tMEMBERS")
+							pattern = "%s\t%s\n"
+						} else {
+							fmt.Fprintln(w, "MEMBERS")
+						}
+					}
 
-				if i == 0 {
+					args := []interface{}{strings.Join(message.Members, ",")}
 					if node != "" {
-						fmt.Fprintln(w, "NODE\tMEMBERS")
-						pattern = "%s\t%s\n"
-					} else {
-						fmt.Fprintln(w, "MEMBERS")
+						args = append([]interface{}{node}, args...)
 					}
-				}
 
-				args := []interface{}{strings.Join(message.Members, ",")}
-				if node != "" {
-					args = append([]interface{}{node}, args...)
+					fmt.Fprintf(w, pattern, args...)
+
 				}
 
-				fmt.Fprintf(w, pattern, args...)
+				return w.Flush()
+			})
+		})
+	},
+}
+
+var etcdStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Get the status of the periodic etcd maintenance",
+	Long: `Maintenance is run by the control plane node hosting the etcd leader,
+so the other nodes report the members without defragmenting them.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			var remotePeer peer.Peer
+
+			resp, err := c.EtcdMaintenanceStatus(ctx, grpc.Peer(&remotePeer))
+			if err != nil {
+				if resp == nil {
+					return fmt.Errorf("error getting etcd maintenance status: %w", err)
+				}
 
+				cli.Warning("%s", err)
 			}
 
-			return w.Flush()
+			return renderResponse(&remotePeer, resp, func() error {
+				return etcdStatusRender(&remotePeer, resp)
+			})
 		})
 	},
 }
 
+func etcdStatusRender(remotePeer *peer.Peer, resp *machine.EtcdMaintenanceStatusResponse) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tMEMBER\tLEADER\tDB SIZE\tIN USE\tLAST DEFRAGMENTED\tERROR")
+
+	defaultNode := client.AddrFromPeer(remotePeer)
+
+	for _, msg := range resp.Messages {
+		node := defaultNode
+
+		if msg.Metadata != nil {
+			node = msg.Metadata.Hostname
+		}
+
+		switch {
+		case !msg.Enabled:
+			cli.Warning("%s: etcd maintenance is disabled", node)
+		case msg.LastRun == nil:
+			cli.Warning("%s: etcd maintenance hasn't run yet", node)
+		case msg.Error != "":
+			cli.Warning("%s: etcd maintenance failed: %s", node, msg.Error)
+		}
+
+		if len(msg.Alarms) > 0 {
+			cli.Warning("%s: active etcd alarms: %s", node, strings.Join(msg.Alarms, ", "))
+		}
+
+		for _, member := range msg.Members {
+			lastDefragmented := "never"
+
+			if member.LastDefragmented != nil {
+				t, _ := ptypes.Timestamp(member.LastDefragmented) //nolint: errcheck
+				lastDefragmented = t.Format(time.RFC3339)
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%v\t%s\t%s\t%s\t%s\n",
+				node,
+				member.Name,
+				member.Leader,
+				humanize.Bytes(uint64(member.DbSize)),
+				humanize.Bytes(uint64(member.DbSizeInUse)),
+				lastDefragmented,
+				member.Error,
+			)
+		}
+	}
+
+	return w.Flush()
+}
+
 func init() {
-	etcdCmd.AddCommand(etcdLeaveCmd, etcdForfeitLeadershipCmd, etcdMemberListCmd)
+	etcdCmd.AddCommand(etcdLeaveCmd, etcdForfeitLeadershipCmd, withStructuredOutput(etcdMemberListCmd), withStructuredOutput(etcdStatusCmd))
 	addCommand(etcdCmd)
 }
@@ -11,8 +11,13 @@ import (
 	"text/tabwriter"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/spf13/cobra"
 
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/output"
+	"github.com/talos-systems/talos/pkg/cli"
+	"github.com/talos-systems/talos/pkg/machinery/api/common"
 	"github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/client"
 )
@@ -30,8 +35,21 @@ var eventsCmd = &cobra.Command{
 	Long:  ``,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return WithClient(func(ctx context.Context, c *client.Client) error {
+			var ow *output.Writer
+
+			if OutputFormat.Structured() {
+				var err error
+
+				if ow, err = newOutputWriter(); err != nil {
+					return err
+				}
+			}
+
 			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-			fmt.Fprintln(w, "NODE\tID\tEVENT\tSOURCE\tMESSAGE")
+
+			if ow == nil {
+				fmt.Fprintln(w, "NODE\tID\tEVENT\tSOURCE\tMESSAGE")
+			}
 
 			opts := []client.EventsOptionFunc{}
 
@@ -63,6 +81,14 @@ var eventsCmd = &cobra.Command{
 						return
 					}
 
+					if ow != nil {
+						if err := eventRenderStructured(ow, event); err != nil {
+							cli.Warning("%s", err)
+						}
+
+						continue
+					}
+
 					format := "%s\t%s\t%s\t%s\t%s\n"
 
 					var args []interface{}
@@ -76,11 +102,20 @@ var eventsCmd = &cobra.Command{
 							args = append(args, msg.GetAction().String())
 						}
 					case *machine.PhaseEvent:
-						args = []interface{}{msg.GetPhase(), msg.GetAction().String()}
+						message := msg.GetAction().String()
+						if msg.GetTotal() > 0 {
+							message = fmt.Sprintf("%s: %s %d/%d", message, msg.GetSequence(), msg.GetNumber(), msg.GetTotal())
+						}
+
+						args = []interface{}{msg.GetPhase(), eventProgressMessage(message, msg.GetDuration(), msg.GetError())}
 					case *machine.TaskEvent:
-						args = []interface{}{msg.GetTask(), msg.GetAction().String()}
+						args = []interface{}{msg.GetTask(), eventProgressMessage(msg.GetAction().String(), msg.GetDuration(), msg.GetError())}
 					case *machine.ServiceStateEvent:
 						args = []interface{}{msg.GetService(), fmt.Sprintf("%s: %s", msg.GetAction(), msg.GetMessage())}
+					case *machine.ConfigLoadErrorEvent:
+						args = []interface{}{"error", msg.GetError()}
+					case *machine.AuditEvent:
+						args = []interface{}{msg.GetMethod(), fmt.Sprintf("%s by %s", auditResult(msg), auditSubject(msg))}
 					default:
 						// We haven't implemented the handling of this event yet.
 						continue
@@ -97,8 +132,38 @@ var eventsCmd = &cobra.Command{
 	},
 }
 
+// eventRenderStructured writes the event with the payload converted using the protobuf JSON mapping.
+func eventRenderStructured(w *output.Writer, event client.Event) error {
+	payload, err := output.Object("", event.Payload)
+	if err != nil {
+		return err
+	}
+
+	return w.Write(map[string]interface{}{
+		"node":    event.Node,
+		"id":      event.ID,
+		"type":    event.TypeURL,
+		"payload": payload,
+	})
+}
+
+// eventProgressMessage appends phase or task run time and error to the message.
+func eventProgressMessage(message string, d *duration.Duration, eventErr *common.Error) string {
+	if d != nil {
+		if runTime, err := ptypes.Duration(d); err == nil {
+			message = fmt.Sprintf("%s, %s", message, runTime)
+		}
+	}
+
+	if eventErr != nil {
+		message = fmt.Sprintf("%s, error: %s", message, eventErr.GetMessage())
+	}
+
+	return message
+}
+
 func init() {
-	addCommand(eventsCmd)
+	addCommand(withStructuredOutput(eventsCmd))
 	eventsCmd.Flags().Int32Var(&eventsCmdFlags.tailEvents, "tail", 0, "show specified number of past events (use -1 to show full history, default is to show no history)")
 	eventsCmd.Flags().DurationVar(&eventsCmdFlags.tailDuration, "duration", 0, "show events for the past duration interval (one second resolution, default is to show no history)")
 	eventsCmd.Flags().StringVar(&eventsCmdFlags.tailID, "since", "", "show events after the specified event ID (default is to show no history)")
@@ -0,0 +1,329 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"github.com/talos-systems/talos/internal/pkg/hardware"
+	"github.com/talos-systems/talos/pkg/cli"
+	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+)
+
+// getResource describes a resource type which can be fetched with `talosctl get`.
+type getResource struct {
+	aliases []string
+	get     func(ctx context.Context, c *client.Client) error
+}
+
+var getResources = map[string]getResource{
+	"bmc": {
+		aliases: []string{"ipmi"},
+		get:     getBMC,
+	},
+	"certificates": {
+		aliases: []string{"certificate", "certs", "cert"},
+		get:     getCertificates,
+	},
+	"hardware": {
+		aliases: []string{"hw", "inventory"},
+		get:     getHardware,
+	},
+	"manifests": {
+		aliases: []string{"manifest"},
+		get:     getManifests,
+	},
+	"members": {
+		aliases: []string{"member", "clustermembers"},
+		get:     getMembers,
+	},
+}
+
+func findGetResource(name string) (getResource, bool) {
+	name = strings.ToLower(name)
+
+	for resourceType, resource := range getResources {
+		if resourceType == name {
+			return resource, true
+		}
+
+		for _, alias := range resource.aliases {
+			if alias == name {
+				return resource, true
+			}
+		}
+	}
+
+	return getResource{}, false
+}
+
+// getCmd represents the get command.
+var getCmd = &cobra.Command{
+	Use:   "get <type>",
+	Short: "Get a specific resource or list of resources",
+	Long: `Supported resource types:
+
+  bmc           BMC network configuration (via IPMI)
+  certificates  certificates used by Talos and Kubernetes with their expiration dates
+  hardware      hardware inventory: SMBIOS data, memory modules, PCI devices, NUMA and CPU topology
+  manifests     Talos-owned bootstrap manifests with their sync status (use -o yaml to see the rendered objects)
+  members       cluster members found by the cluster discovery`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
+		}
+
+		types := make([]string, 0, len(getResources))
+
+		for resourceType := range getResources {
+			types = append(types, resourceType)
+		}
+
+		return types, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resource, ok := findGetResource(args[0])
+		if !ok {
+			return fmt.Errorf("unsupported resource type %q", args[0])
+		}
+
+		return WithClient(resource.get)
+	},
+}
+
+func getMembers(ctx context.Context, c *client.Client) error {
+	var remotePeer peer.Peer
+
+	resp, err := c.ClusterMembers(ctx, grpc.Peer(&remotePeer))
+	if err != nil {
+		if resp == nil {
+			return fmt.Errorf("error getting cluster members: %w", err)
+		}
+
+		cli.Warning("%s", err)
+	}
+
+	return renderResponse(&remotePeer, resp, func() error {
+		return membersRender(&remotePeer, resp)
+	})
+}
+
+func membersRender(remotePeer *peer.Peer, resp *machineapi.ClusterMembersResponse) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tID\tHOSTNAME\tMACHINE TYPE\tADDRESSES\tREGISTRIES")
+
+	defaultNode := client.AddrFromPeer(remotePeer)
+
+	for _, msg := range resp.Messages {
+		node := defaultNode
+
+		if msg.Metadata != nil {
+			node = msg.Metadata.Hostname
+		}
+
+		for _, member := range msg.Members {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				node, member.Id, member.Hostname, member.MachineType, strings.Join(member.Addresses, ","), strings.Join(member.Registries, ","))
+		}
+	}
+
+	return w.Flush()
+}
+
+func getManifests(ctx context.Context, c *client.Client) error {
+	var remotePeer peer.Peer
+
+	resp, err := c.Manifests(ctx, grpc.Peer(&remotePeer))
+	if err != nil {
+		if resp == nil {
+			return fmt.Errorf("error getting manifests: %w", err)
+		}
+
+		cli.Warning("%s", err)
+	}
+
+	return renderResponse(&remotePeer, resp, func() error {
+		return manifestsRender(&remotePeer, resp)
+	})
+}
+
+func manifestsRender(remotePeer *peer.Peer, resp *machineapi.ManifestsResponse) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tMANIFEST\tKIND\tNAMESPACE\tNAME\tSTATE\tLAST SYNC\tERROR")
+
+	defaultNode := client.AddrFromPeer(remotePeer)
+
+	for _, msg := range resp.Messages {
+		node := defaultNode
+
+		if msg.Metadata != nil {
+			node = msg.Metadata.Hostname
+		}
+
+		for _, object := range msg.Objects {
+			lastSync := ""
+
+			if t, err := ptypes.Timestamp(object.LastSync); err == nil {
+				lastSync = humanize.Time(t)
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				node, object.Manifest, object.Kind, object.Namespace, object.Name, object.State, lastSync, object.Error)
+		}
+	}
+
+	return w.Flush()
+}
+
+func getCertificates(ctx context.Context, c *client.Client) error {
+	var remotePeer peer.Peer
+
+	resp, err := c.Certificates(ctx, grpc.Peer(&remotePeer))
+	if err != nil {
+		if resp == nil {
+			return fmt.Errorf("error getting certificates: %w", err)
+		}
+
+		cli.Warning("%s", err)
+	}
+
+	return renderResponse(&remotePeer, resp, func() error {
+		return getCertificatesRender(&remotePeer, resp)
+	})
+}
+
+func getCertificatesRender(remotePeer *peer.Peer, resp *machineapi.CertificatesResponse) error {
+	rows, err := certificateRows(remotePeer, resp)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tSOURCE\tSUBJECT\tNOT AFTER\tEXPIRES")
+
+	for _, row := range rows {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			row.node, row.cert.Source, row.cert.Subject, row.notAfter.Format(time.RFC3339), humanize.Time(row.notAfter))
+	}
+
+	return w.Flush()
+}
+
+func getBMC(ctx context.Context, c *client.Client) error {
+	var remotePeer peer.Peer
+
+	resp, err := c.BMCInfo(ctx, grpc.Peer(&remotePeer))
+	if err != nil {
+		if resp == nil {
+			return fmt.Errorf("error getting BMC info: %w", err)
+		}
+
+		cli.Warning("%s", err)
+	}
+
+	return renderResponse(&remotePeer, resp, func() error {
+		return bmcRender(&remotePeer, resp)
+	})
+}
+
+func bmcRender(remotePeer *peer.Peer, resp *machineapi.BMCInfoResponse) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tCHANNEL\tIP SOURCE\tADDRESS\tNETMASK\tGATEWAY\tMAC")
+
+	defaultNode := client.AddrFromPeer(remotePeer)
+
+	for _, msg := range resp.Messages {
+		node := defaultNode
+
+		if msg.Metadata != nil {
+			node = msg.Metadata.Hostname
+		}
+
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			node, msg.Channel, msg.IpSource, msg.Address, msg.Netmask, msg.Gateway, msg.Mac)
+	}
+
+	return w.Flush()
+}
+
+func getHardware(ctx context.Context, c *client.Client) error {
+	var remotePeer peer.Peer
+
+	resp, err := c.HardwareInventory(ctx, grpc.Peer(&remotePeer))
+	if err != nil {
+		if resp == nil {
+			return fmt.Errorf("error getting hardware inventory: %w", err)
+		}
+
+		cli.Warning("%s", err)
+	}
+
+	return renderResponse(&remotePeer, resp, func() error {
+		return hardwareRender(&remotePeer, resp)
+	})
+}
+
+func hardwareRender(remotePeer *peer.Peer, resp *machineapi.HardwareInventoryResponse) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tKIND\tID\tDETAILS")
+
+	defaultNode := client.AddrFromPeer(remotePeer)
+
+	for _, msg := range resp.Messages {
+		node := defaultNode
+
+		if msg.Metadata != nil {
+			node = msg.Metadata.Hostname
+		}
+
+		if msg.Smbios != nil {
+			fmt.Fprintf(w, "%s\tsystem\t%s\t%s %s (serial %s)\n",
+				node, msg.Smbios.Uuid, msg.Smbios.System.GetManufacturer(), msg.Smbios.System.GetProductName(), msg.Smbios.System.GetSerialNumber())
+			fmt.Fprintf(w, "%s\tbaseboard\t\t%s %s (serial %s)\n",
+				node, msg.Smbios.Baseboard.GetManufacturer(), msg.Smbios.Baseboard.GetProductName(), msg.Smbios.Baseboard.GetSerialNumber())
+			fmt.Fprintf(w, "%s\tbios\t\t%s %s (%s), SMBIOS %s\n",
+				node, msg.Smbios.BiosVendor, msg.Smbios.BiosVersion, msg.Smbios.BiosReleaseDate, msg.Smbios.Version)
+		}
+
+		for _, module := range msg.MemoryModules {
+			fmt.Fprintf(w, "%s\tmemory\t%s\t%s %s %d MT/s, %s %s (serial %s)\n",
+				node, module.Locator, humanize.IBytes(module.Size), module.Type, module.Speed, module.Manufacturer, module.PartNumber, module.SerialNumber)
+		}
+
+		for _, device := range msg.PciDevices {
+			fmt.Fprintf(w, "%s\tpci\t%s\tclass %s, vendor %s, device %s, driver %q, parent %q, numa %d\n",
+				node, device.Address, device.Class, device.VendorId, device.DeviceId, device.Driver, device.Parent, device.NumaNode)
+		}
+
+		for _, numaNode := range msg.NumaNodes {
+			fmt.Fprintf(w, "%s\tnuma\t%d\tcpus %s, memory %s\n",
+				node, numaNode.Id, hardware.FormatCPUList(numaNode.Cpus), humanize.IBytes(numaNode.MemoryTotal))
+		}
+
+		for _, cpu := range msg.Cpus {
+			fmt.Fprintf(w, "%s\tcpu\t%d\tpackage %d, core %d, numa %d\n",
+				node, cpu.Id, cpu.PackageId, cpu.CoreId, cpu.NumaNode)
+		}
+	}
+
+	return w.Flush()
+}
+
+func init() {
+	addCommand(withStructuredOutput(getCmd))
+}
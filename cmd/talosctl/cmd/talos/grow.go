@@ -0,0 +1,37 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/talos-systems/talos/pkg/machinery/client"
+)
+
+// growCmd represents the grow command.
+var growCmd = &cobra.Command{
+	Use:   "grow",
+	Short: "Grow the EPHEMERAL partition to the end of the system disk",
+	Long: `Grow the EPHEMERAL partition and its filesystem online to take the space added to the system disk (e.g. after the cloud volume was resized).
+
+Talos grows the partition automatically when the kernel reports the disk resize, this command triggers it manually.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			if _, err := c.Grow(ctx); err != nil {
+				return fmt.Errorf("error executing grow: %s", err)
+			}
+
+			return nil
+		})
+	},
+}
+
+func init() {
+	addCommand(growCmd)
+}
@@ -0,0 +1,157 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	criconstants "github.com/containerd/cri/pkg/constants"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"github.com/talos-systems/talos/pkg/cli"
+	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// imageCmd represents the image command.
+var imageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Manage container images",
+	Long:  `Manage the container images in the system containerd namespace, or with --kubernetes in the k8s.io namespace used by the CRI.`,
+}
+
+// imageListCmd represents the image ls command.
+var imageListCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List container images",
+	Long:    ``,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			var remotePeer peer.Peer
+
+			resp, err := c.ImageList(ctx, imageNamespace(), grpc.Peer(&remotePeer))
+			if err != nil {
+				if resp == nil {
+					return fmt.Errorf("error listing images: %s", err)
+				}
+
+				cli.Warning("%s", err)
+			}
+
+			return renderResponse(&remotePeer, resp, func() error {
+				return imageRender(&remotePeer, resp)
+			})
+		})
+	},
+}
+
+// imagePullCmd represents the image pull command.
+var imagePullCmd = &cobra.Command{
+	Use:   "pull <image>",
+	Short: "Pull a container image",
+	Long:  `Pull the container image into the containerd namespace, e.g. to pre-pull the images before the upgrade.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			var remotePeer peer.Peer
+
+			resp, err := c.ImagePull(ctx, imageNamespace(), args[0], grpc.Peer(&remotePeer))
+			if err != nil {
+				if resp == nil {
+					return fmt.Errorf("error pulling image: %s", err)
+				}
+
+				cli.Warning("%s", err)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "NODE\tIMAGE\tDIGEST")
+
+			defaultNode := client.AddrFromPeer(&remotePeer)
+
+			for _, msg := range resp.Messages {
+				node := defaultNode
+
+				if msg.Metadata != nil {
+					node = msg.Metadata.Hostname
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\n", node, msg.Name, msg.Digest)
+			}
+
+			return w.Flush()
+		})
+	},
+}
+
+// imageRemoveCmd represents the image rm command.
+var imageRemoveCmd = &cobra.Command{
+	Use:     "rm <image>",
+	Aliases: []string{"remove"},
+	Short:   "Remove a container image",
+	Long:    `Remove the container image from the containerd namespace, the image content is garbage collected once no other image references it.`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			if err := c.ImageRemove(ctx, imageNamespace(), args[0]); err != nil {
+				return fmt.Errorf("error removing image: %s", err)
+			}
+
+			return nil
+		})
+	},
+}
+
+func imageNamespace() string {
+	if kubernetes {
+		return criconstants.K8sContainerdNamespace
+	}
+
+	return constants.SystemContainerdNamespace
+}
+
+func imageRender(remotePeer *peer.Peer, resp *machineapi.ImageListResponse) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tNAMESPACE\tIMAGE\tDIGEST\tSIZE\tCREATED")
+
+	defaultNode := client.AddrFromPeer(remotePeer)
+
+	for _, msg := range resp.Messages {
+		node := defaultNode
+
+		if msg.Metadata != nil {
+			node = msg.Metadata.Hostname
+		}
+
+		for _, img := range msg.Images {
+			created := "-"
+
+			if createdAt, err := ptypes.Timestamp(img.CreatedAt); err == nil {
+				created = createdAt.Format(time.RFC3339)
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", node, img.Namespace, img.Name, img.Digest, humanize.Bytes(img.Size), created)
+		}
+	}
+
+	return w.Flush()
+}
+
+func init() {
+	imageCmd.PersistentFlags().BoolVarP(&kubernetes, "kubernetes", "k", false, "use the k8s.io containerd namespace")
+	imageCmd.AddCommand(withStructuredOutput(imageListCmd), imagePullCmd, imageRemoveCmd)
+	addCommand(imageCmd)
+}
@@ -38,7 +38,9 @@ var interfacesCmd = &cobra.Command{
 				cli.Warning("%s", err)
 			}
 
-			return intersRender(&remotePeer, resp)
+			return renderResponse(&remotePeer, resp, func() error {
+				return intersRender(&remotePeer, resp)
+			})
 		})
 	},
 }
@@ -67,5 +69,5 @@ func intersRender(remotePeer *peer.Peer, resp *networkapi.InterfacesResponse) er
 }
 
 func init() {
-	addCommand(interfacesCmd)
+	addCommand(withStructuredOutput(interfacesCmd))
 }
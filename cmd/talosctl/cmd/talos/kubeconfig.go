@@ -13,21 +13,28 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/helpers"
 	"github.com/talos-systems/talos/internal/pkg/kubeconfig"
 	"github.com/talos-systems/talos/pkg/machinery/client"
+	clientconfig "github.com/talos-systems/talos/pkg/machinery/client/config"
 )
 
-var (
-	force            bool
-	forceContextName string
-	merge            bool
-)
+var kubeconfigCmdFlags struct {
+	force               bool
+	forceContextName    string
+	merge               bool
+	clusterNameTemplate string
+	contextNameTemplate string
+	execCredentials     bool
+	execCrtTTL          time.Duration
+}
 
 // kubeconfigCmd represents the kubeconfig command.
 var kubeconfigCmd = &cobra.Command{
@@ -35,7 +42,17 @@ var kubeconfigCmd = &cobra.Command{
 	Short: "Download the admin kubeconfig from the node",
 	Long: `Download the admin kubeconfig from the node.
 If merge flag is defined, config will be merged with ~/.kube/config or [local-path] if specified.
-Otherwise kubeconfig will be written to PWD or [local-path] if specified.`,
+Otherwise kubeconfig will be written to PWD or [local-path] if specified.
+
+Merge keeps unrelated clusters, users and contexts of the existing kubeconfig intact.
+On conflicts, the new entries are renamed, or the existing entries are overwritten with --force.
+
+Cluster and context names are generated from the templates, available fields are .Cluster and .User.
+Users are always named as <user>@<cluster>, so that the users of different clusters don't clash.
+
+With --exec-credentials, the admin client certificate is not embedded into the kubeconfig.
+Instead, Kubernetes clients invoke 'talosctl kubernetes-credentials' to fetch short-lived certificates
+from the node using the current Talos client configuration.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return WithClient(func(ctx context.Context, c *client.Client) error {
@@ -49,7 +66,7 @@ Otherwise kubeconfig will be written to PWD or [local-path] if specified.`,
 				// no path given, use defaults
 				var err error
 
-				if merge {
+				if kubeconfigCmdFlags.merge {
 					localPath, err = kubeconfig.DefaultPath()
 					if err != nil {
 						return err
@@ -82,12 +99,12 @@ Otherwise kubeconfig will be written to PWD or [local-path] if specified.`,
 			}
 
 			_, err = os.Stat(localPath)
-			if err == nil && !(force || merge) {
+			if err == nil && !(kubeconfigCmdFlags.force || kubeconfigCmdFlags.merge) {
 				return fmt.Errorf("kubeconfig file already exists, use --force to overwrite: %q", localPath)
 			} else if err != nil {
 				if os.IsNotExist(err) {
 					// merge doesn't make sense if target path doesn't exist
-					merge = false
+					kubeconfigCmdFlags.merge = false
 				} else {
 					return fmt.Errorf("error checking path %q: %w", localPath, err)
 				}
@@ -116,8 +133,33 @@ Otherwise kubeconfig will be written to PWD or [local-path] if specified.`,
 				return err
 			}
 
-			if merge {
-				return extractAndMerge(data, localPath)
+			config, err := clientcmd.Load(data)
+			if err != nil {
+				return fmt.Errorf("error parsing kubeconfig: %w", err)
+			}
+
+			if kubeconfigCmdFlags.execCredentials {
+				var execArgs []string
+
+				execArgs, err = kubernetesCredentialsArgs()
+				if err != nil {
+					return err
+				}
+
+				kubeconfig.UseExecCredentials(config, "talosctl", execArgs)
+			}
+
+			if err = kubeconfig.Rename(config, kubeconfigCmdFlags.clusterNameTemplate, kubeconfigCmdFlags.contextNameTemplate); err != nil {
+				return err
+			}
+
+			if kubeconfigCmdFlags.merge {
+				return mergeKubeconfig(config, localPath)
+			}
+
+			data, err = clientcmd.Write(*config)
+			if err != nil {
+				return err
 			}
 
 			return ioutil.WriteFile(localPath, data, 0o640)
@@ -125,12 +167,43 @@ Otherwise kubeconfig will be written to PWD or [local-path] if specified.`,
 	},
 }
 
-func extractAndMerge(data []byte, localPath string) error {
-	config, err := clientcmd.Load(data)
+// kubernetesCredentialsArgs builds the arguments of the exec credential plugin
+// which fetches the credentials with the current Talos client configuration.
+func kubernetesCredentialsArgs() ([]string, error) {
+	talosconfig, err := filepath.Abs(Talosconfig)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	contextName := Cmdcontext
+
+	if contextName == "" {
+		var cfg *clientconfig.Config
+
+		cfg, err = clientconfig.Open(talosconfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open config file %q: %w", talosconfig, err)
+		}
+
+		contextName = cfg.Context
+	}
+
+	args := []string{
+		"kubernetes-credentials",
+		"--talosconfig", talosconfig,
+		"--context", contextName,
+		"--nodes", Nodes[0],
+		"--crt-ttl", kubeconfigCmdFlags.execCrtTTL.String(),
 	}
 
+	for _, endpoint := range Endpoints {
+		args = append(args, "--endpoints", endpoint)
+	}
+
+	return args, nil
+}
+
+func mergeKubeconfig(config *clientcmdapi.Config, localPath string) error {
 	merger, err := kubeconfig.Load(localPath)
 	if err != nil {
 		return err
@@ -140,10 +213,10 @@ func extractAndMerge(data []byte, localPath string) error {
 
 	err = merger.Merge(config, kubeconfig.MergeOptions{
 		ActivateContext:  true,
-		ForceContextName: forceContextName,
+		ForceContextName: kubeconfigCmdFlags.forceContextName,
 		OutputWriter:     os.Stdout,
 		ConflictHandler: func(component kubeconfig.ConfigComponent, name string) (kubeconfig.ConflictDecision, error) {
-			if force {
+			if kubeconfigCmdFlags.force {
 				return kubeconfig.OverwriteDecision, nil
 			}
 
@@ -183,8 +256,12 @@ func askOverwriteOrRename(prompt string) (kubeconfig.ConflictDecision, error) {
 }
 
 func init() {
-	kubeconfigCmd.Flags().BoolVarP(&force, "force", "f", false, "Force overwrite of kubeconfig if already present, force overwrite on kubeconfig merge")
-	kubeconfigCmd.Flags().StringVar(&forceContextName, "force-context-name", "", "Force context name for kubeconfig merge")
-	kubeconfigCmd.Flags().BoolVarP(&merge, "merge", "m", true, "Merge with existing kubeconfig")
+	kubeconfigCmd.Flags().BoolVarP(&kubeconfigCmdFlags.force, "force", "f", false, "Force overwrite of kubeconfig if already present, force overwrite on kubeconfig merge")
+	kubeconfigCmd.Flags().StringVar(&kubeconfigCmdFlags.forceContextName, "force-context-name", "", "Force context name for kubeconfig merge")
+	kubeconfigCmd.Flags().BoolVarP(&kubeconfigCmdFlags.merge, "merge", "m", true, "Merge with existing kubeconfig")
+	kubeconfigCmd.Flags().StringVar(&kubeconfigCmdFlags.clusterNameTemplate, "cluster-name-template", kubeconfig.DefaultClusterNameTemplate, "Template for the cluster name")
+	kubeconfigCmd.Flags().StringVar(&kubeconfigCmdFlags.contextNameTemplate, "context-name-template", kubeconfig.DefaultContextNameTemplate, "Template for the context name")
+	kubeconfigCmd.Flags().BoolVar(&kubeconfigCmdFlags.execCredentials, "exec-credentials", false, "Fetch short-lived credentials with talosctl instead of embedding the client certificate")
+	kubeconfigCmd.Flags().DurationVar(&kubeconfigCmdFlags.execCrtTTL, "exec-crt-ttl", time.Hour, "Client certificate TTL for the fetched credentials")
 	addCommand(kubeconfigCmd)
 }
@@ -0,0 +1,82 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientauthenticationv1beta1 "k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/helpers"
+	"github.com/talos-systems/talos/internal/pkg/kubeconfig"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+)
+
+var kubernetesCredentialsCmdFlags struct {
+	crtTTL time.Duration
+}
+
+// kubernetesCredentialsCmd represents the kubernetes-credentials command.
+var kubernetesCredentialsCmd = &cobra.Command{
+	Use:   "kubernetes-credentials",
+	Short: "Fetch short-lived Kubernetes admin credentials from the node",
+	Long: `Fetch short-lived Kubernetes admin credentials from the node.
+
+Credentials are printed in the Kubernetes exec credential plugin format.
+The command is invoked by the Kubernetes clients for the kubeconfig generated with 'talosctl kubeconfig --exec-credentials'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			if err := helpers.FailIfMultiNodes(ctx, "kubernetes-credentials"); err != nil {
+				return err
+			}
+
+			resp, err := c.KubernetesCredentials(ctx, &machine.KubernetesCredentialsRequest{
+				CrtTtl: ptypes.DurationProto(kubernetesCredentialsCmdFlags.crtTTL),
+			})
+			if err != nil {
+				return fmt.Errorf("error fetching Kubernetes credentials: %w", err)
+			}
+
+			if len(resp.GetMessages()) != 1 {
+				return fmt.Errorf("unexpected number of responses: %d", len(resp.GetMessages()))
+			}
+
+			credentials := resp.GetMessages()[0]
+
+			expiration, err := ptypes.Timestamp(credentials.GetExpiration())
+			if err != nil {
+				return err
+			}
+
+			execCredential := clientauthenticationv1beta1.ExecCredential{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: kubeconfig.ExecCredentialAPIVersion,
+					Kind:       "ExecCredential",
+				},
+				Status: &clientauthenticationv1beta1.ExecCredentialStatus{
+					ExpirationTimestamp:   &metav1.Time{Time: expiration},
+					ClientCertificateData: string(credentials.GetCrt()),
+					ClientKeyData:         string(credentials.GetKey()),
+				},
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(execCredential)
+		})
+	},
+}
+
+func init() {
+	kubernetesCredentialsCmd.Flags().DurationVar(&kubernetesCredentialsCmdFlags.crtTTL, "crt-ttl", time.Hour, "Client certificate TTL")
+	addCommand(kubernetesCredentialsCmd)
+}
@@ -18,6 +18,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/output"
 	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/client"
 )
@@ -77,6 +78,15 @@ var lsCmd = &cobra.Command{
 
 			defaultNode := client.RemotePeer(stream.Context())
 
+			if OutputFormat.Structured() {
+				ow, e := newOutputWriter()
+				if e != nil {
+					return e
+				}
+
+				return lsRenderStructured(ow, stream, defaultNode)
+			}
+
 			if !long {
 				w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
 				fmt.Fprintln(w, "NODE\tNAME")
@@ -192,6 +202,40 @@ var lsCmd = &cobra.Command{
 	},
 }
 
+func lsRenderStructured(w *output.Writer, stream machineapi.MachineService_ListClient, defaultNode string) error {
+	for {
+		info, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF || status.Code(err) == codes.Canceled {
+				return nil
+			}
+
+			return fmt.Errorf("error streaming results: %s", err)
+		}
+
+		node := defaultNode
+		if info.Metadata != nil && info.Metadata.Hostname != "" {
+			node = info.Metadata.Hostname
+		}
+
+		if info.Metadata != nil && info.Metadata.Error != "" {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", node, info.Metadata.Error)
+
+			continue
+		}
+
+		if info.Error != "" {
+			fmt.Fprintf(os.Stderr, "%s: error reading file %s: %s\n", node, info.Name, info.Error)
+
+			continue
+		}
+
+		if err = w.WriteMessage(node, info); err != nil {
+			return err
+		}
+	}
+}
+
 func init() {
 	typesHelp := strings.Join([]string{
 		"filter by specified types:",
@@ -205,5 +249,5 @@ func init() {
 	lsCmd.Flags().BoolVarP(&humanizeFlag, "humanize", "H", false, "humanize size and time in the output")
 	lsCmd.Flags().Int32VarP(&recursionDepth, "depth", "d", 0, "maximum recursion depth")
 	lsCmd.Flags().StringSliceVarP(&types, "type", "t", nil, typesHelp)
-	addCommand(lsCmd)
+	addCommand(withStructuredOutput(lsCmd))
 }
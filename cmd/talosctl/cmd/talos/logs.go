@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"sync"
 
 	criconstants "github.com/containerd/cri/pkg/constants"
@@ -31,10 +32,16 @@ var (
 
 // logsCmd represents the logs command.
 var logsCmd = &cobra.Command{
-	Use:   "logs <service name>",
+	Use:   "logs <service name | namespace/pod[:container]>",
 	Short: "Retrieve logs for a service",
-	Long:  ``,
-	Args:  cobra.ExactArgs(1),
+	Long: `Retrieve logs for a service or a Kubernetes container.
+
+With --kubernetes, the container can be specified as 'namespace/pod[:container]':
+the pod is resolved via the CRI and the logs are read directly on the node, so they
+are available even if the Kubernetes API server is down. Container name might be omitted
+if the pod has a single container.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeServiceArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return WithClient(func(ctx context.Context, c *client.Client) error {
 			var namespace string
@@ -45,7 +52,7 @@ var logsCmd = &cobra.Command{
 			}
 
 			driver := common.ContainerDriver_CONTAINERD
-			if useCRI {
+			if useCRI || (kubernetes && strings.Contains(args[0], "/")) {
 				driver = common.ContainerDriver_CRI
 			}
 
@@ -0,0 +1,227 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/helpers"
+	"github.com/talos-systems/talos/pkg/cli"
+	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+)
+
+var machineconfigRollbackCmdFlags struct {
+	mode string
+}
+
+// machineconfigCmd represents the machineconfig command.
+var machineconfigCmd = &cobra.Command{
+	Use:   "machineconfig",
+	Short: "Inspect and roll back the machine configuration history",
+	Long: `Inspect and roll back the machine configuration history.
+
+Nodes keep the last versions of the stored machine configuration on the STATE partition.
+Every applied configuration (including the rollback) is recorded as the latest version.`,
+}
+
+var machineconfigHistoryCmd = &cobra.Command{
+	Use:   "history [<version>]",
+	Short: "List the machine configuration versions, or print the configuration of the version",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			if len(args) == 0 {
+				var remotePeer peer.Peer
+
+				resp, err := c.ConfigHistory(ctx, grpc.Peer(&remotePeer))
+				if err != nil {
+					if resp == nil {
+						return fmt.Errorf("error getting config history: %w", err)
+					}
+
+					cli.Warning("%s", err)
+				}
+
+				return renderResponse(&remotePeer, resp, func() error {
+					return machineconfigHistoryRender(&remotePeer, resp)
+				})
+			}
+
+			if OutputFormat.Structured() {
+				return fmt.Errorf("output format %q is not supported when printing the machine configuration", OutputFormat)
+			}
+
+			if err := helpers.FailIfMultiNodes(ctx, "machineconfig history"); err != nil {
+				return err
+			}
+
+			data, err := machineconfigVersion(ctx, c, args[0])
+			if err != nil {
+				return err
+			}
+
+			_, err = os.Stdout.Write(data)
+
+			return err
+		})
+	},
+}
+
+var machineconfigDiffCmd = &cobra.Command{
+	Use:   "diff <from-version> [<to-version>]",
+	Short: "Show the difference between the machine configuration versions",
+	Long: `Show the difference between the machine configuration versions.
+
+If the second version is not specified, the latest version is used.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			if err := helpers.FailIfMultiNodes(ctx, "machineconfig diff"); err != nil {
+				return err
+			}
+
+			to := "latest"
+			if len(args) > 1 {
+				to = args[1]
+			}
+
+			fromData, err := machineconfigVersion(ctx, c, args[0])
+			if err != nil {
+				return err
+			}
+
+			toData, err := machineconfigVersion(ctx, c, to)
+			if err != nil {
+				return err
+			}
+
+			return difflib.WriteUnifiedDiff(os.Stdout, difflib.UnifiedDiff{
+				A:        difflib.SplitLines(string(fromData)),
+				B:        difflib.SplitLines(string(toData)),
+				FromFile: "version " + args[0],
+				ToFile:   "version " + to,
+				Context:  3,
+			})
+		})
+	},
+}
+
+var machineconfigRollbackCmd = &cobra.Command{
+	Use:   "rollback <version>",
+	Short: "Roll back the machine configuration to the version from the history",
+	Long: `Roll back the machine configuration to the version from the history.
+
+Mode "reboot" (default) applies the configuration and reboots the node, mode "no-reboot" applies
+the configuration on the next reboot.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("error parsing version %q: %w", args[0], err)
+		}
+
+		var noReboot bool
+
+		switch machineconfigRollbackCmdFlags.mode {
+		case applyModeReboot:
+		case applyModeNoReboot:
+			noReboot = true
+		default:
+			return fmt.Errorf("unknown mode %q, expected one of: %s, %s", machineconfigRollbackCmdFlags.mode, applyModeReboot, applyModeNoReboot)
+		}
+
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			if _, err := c.ConfigRollback(ctx, &machineapi.ConfigRollbackRequest{
+				Version:  version,
+				NoReboot: noReboot,
+			}); err != nil {
+				return fmt.Errorf("error rolling back configuration: %w", err)
+			}
+
+			return nil
+		})
+	},
+}
+
+func machineconfigHistoryRender(remotePeer *peer.Peer, resp *machineapi.ConfigHistoryResponse) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tVERSION\tTIMESTAMP\tSIZE")
+
+	defaultNode := client.AddrFromPeer(remotePeer)
+
+	for _, msg := range resp.Messages {
+		node := defaultNode
+
+		if msg.Metadata != nil {
+			node = msg.Metadata.Hostname
+		}
+
+		for _, version := range msg.Versions {
+			timestamp, err := ptypes.Timestamp(version.Timestamp)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", node, version.Version, timestamp.Format(time.RFC3339), humanize.Bytes(uint64(len(version.Data))))
+		}
+	}
+
+	return w.Flush()
+}
+
+// machineconfigVersion returns the machine configuration of the version, "latest" selects the latest version.
+func machineconfigVersion(ctx context.Context, c *client.Client, version string) ([]byte, error) {
+	resp, err := c.ConfigHistory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting config history: %w", err)
+	}
+
+	if len(resp.GetMessages()) != 1 {
+		return nil, fmt.Errorf("unexpected number of responses: %d", len(resp.GetMessages()))
+	}
+
+	versions := resp.GetMessages()[0].GetVersions()
+
+	if version == "latest" {
+		if len(versions) == 0 {
+			return nil, fmt.Errorf("config history is empty")
+		}
+
+		return versions[0].GetData(), nil
+	}
+
+	number, err := strconv.ParseUint(version, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing version %q: %w", version, err)
+	}
+
+	for _, v := range versions {
+		if v.GetVersion() == number {
+			return v.GetData(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("config version %d is not found in the history", number)
+}
+
+func init() {
+	machineconfigRollbackCmd.Flags().StringVarP(&machineconfigRollbackCmdFlags.mode, "mode", "m", applyModeReboot, fmt.Sprintf("apply mode: %s or %s", applyModeReboot, applyModeNoReboot))
+
+	machineconfigCmd.AddCommand(withStructuredOutput(machineconfigHistoryCmd), machineconfigDiffCmd, machineconfigRollbackCmd)
+	addCommand(machineconfigCmd)
+}
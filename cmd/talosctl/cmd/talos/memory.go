@@ -41,11 +41,13 @@ var memoryCmd = &cobra.Command{
 				cli.Warning("%s", err)
 			}
 
-			if verbose {
-				return verboseRender(&remotePeer, resp)
-			}
+			return renderResponse(&remotePeer, resp, func() error {
+				if verbose {
+					return verboseRender(&remotePeer, resp)
+				}
 
-			return briefRender(&remotePeer, resp)
+				return briefRender(&remotePeer, resp)
+			})
 		})
 	},
 }
@@ -146,5 +148,5 @@ func verboseRender(remotePeer *peer.Peer, resp *machineapi.MemoryResponse) error
 
 func init() {
 	memoryCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "display extended memory statistics")
-	addCommand(memoryCmd)
+	addCommand(withStructuredOutput(memoryCmd))
 }
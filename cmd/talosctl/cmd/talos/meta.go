@@ -0,0 +1,92 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/adv"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+)
+
+// metaKeys maps well-known META key names to the keys.
+var metaKeys = map[string]uint32{
+	"extra-kernel-args": adv.ExtraKernelArgs,
+}
+
+// metaCmd represents the meta command.
+var metaCmd = &cobra.Command{
+	Use:   "meta",
+	Short: "Write and delete keys in the META partition",
+	Long: `Write and delete keys in the META partition.
+
+Keys are specified either by name or as a number (e.g. 0xc). Supported keys:
+
+  extra-kernel-args (0xc)   extra kernel arguments appended by the bootloader on boot
+
+Changes to the extra kernel arguments take effect on the next boot.`,
+}
+
+var metaWriteCmd = &cobra.Command{
+	Use:   "write <key> <value>",
+	Short: "Write a key to the META partition",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := parseMetaKey(args[0])
+		if err != nil {
+			return err
+		}
+
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			if _, err := c.MetaWrite(ctx, key, []byte(args[1])); err != nil {
+				return fmt.Errorf("error writing META key: %w", err)
+			}
+
+			return nil
+		})
+	},
+}
+
+var metaDeleteCmd = &cobra.Command{
+	Use:   "delete <key>",
+	Short: "Delete a key from the META partition",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := parseMetaKey(args[0])
+		if err != nil {
+			return err
+		}
+
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			if _, err := c.MetaDelete(ctx, key); err != nil {
+				return fmt.Errorf("error deleting META key: %w", err)
+			}
+
+			return nil
+		})
+	},
+}
+
+func parseMetaKey(s string) (uint32, error) {
+	if key, ok := metaKeys[s]; ok {
+		return key, nil
+	}
+
+	key, err := strconv.ParseUint(s, 0, 8)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing META key %q: %w", s, err)
+	}
+
+	return uint32(key), nil
+}
+
+func init() {
+	metaCmd.AddCommand(metaWriteCmd, metaDeleteCmd)
+	addCommand(metaCmd)
+}
@@ -20,12 +20,16 @@ import (
 	"github.com/talos-systems/talos/pkg/machinery/client"
 )
 
+var mountsCmdFlags struct {
+	options bool
+}
+
 // mountsCmd represents the mounts command.
 var mountsCmd = &cobra.Command{
 	Use:     "mounts",
 	Aliases: []string{"mount"},
 	Short:   "List mounts",
-	Long:    ``,
+	Long:    `List mounted filesystems with their size, usage and type (similar to df).`,
 	Args:    cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return WithClient(func(ctx context.Context, c *client.Client) error {
@@ -40,14 +44,22 @@ var mountsCmd = &cobra.Command{
 				cli.Warning("%s", err)
 			}
 
-			return mountsRender(&remotePeer, resp)
+			return renderResponse(&remotePeer, resp, func() error {
+				return mountsRender(&remotePeer, resp)
+			})
 		})
 	},
 }
 
 func mountsRender(remotePeer *peer.Peer, resp *machineapi.MountsResponse) error {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(w, "NODE\tFILESYSTEM\tSIZE(GB)\tUSED(GB)\tAVAILABLE(GB)\tPERCENT USED\tMOUNTED ON")
+	header := "NODE\tFILESYSTEM\tTYPE\tSIZE(GB)\tUSED(GB)\tAVAILABLE(GB)\tPERCENT USED\tMOUNTED ON"
+
+	if mountsCmdFlags.options {
+		header += "\tOPTIONS"
+	}
+
+	fmt.Fprintln(w, header)
 
 	defaultNode := client.AddrFromPeer(remotePeer)
 
@@ -65,8 +77,21 @@ func mountsRender(remotePeer *peer.Peer, resp *machineapi.MountsResponse) error
 				node = msg.Metadata.Hostname
 			}
 
-			fmt.Fprintf(w, "%s\t%s\t%.02f\t%.02f\t%.02f\t%.02f%%\t%s\n",
-				node, r.Filesystem, float64(r.Size)*1e-9, float64(r.Size-r.Available)*1e-9, float64(r.Available)*1e-9, percentAvailable, r.MountedOn)
+			used := r.Used
+
+			// older nodes don't report used space
+			if used == 0 {
+				used = r.Size - r.Available
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%.02f\t%.02f\t%.02f\t%.02f%%\t%s",
+				node, r.Filesystem, r.Type, float64(r.Size)*1e-9, float64(used)*1e-9, float64(r.Available)*1e-9, percentAvailable, r.MountedOn)
+
+			if mountsCmdFlags.options {
+				fmt.Fprintf(w, "\t%s", r.Options)
+			}
+
+			fmt.Fprintln(w)
 		}
 	}
 
@@ -74,5 +99,6 @@ func mountsRender(remotePeer *peer.Peer, resp *machineapi.MountsResponse) error
 }
 
 func init() {
-	addCommand(mountsCmd)
+	mountsCmd.Flags().BoolVar(&mountsCmdFlags.options, "options", false, "display mount options")
+	addCommand(withStructuredOutput(mountsCmd))
 }
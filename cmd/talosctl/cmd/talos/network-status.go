@@ -0,0 +1,73 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"github.com/talos-systems/talos/pkg/cli"
+	networkapi "github.com/talos-systems/talos/pkg/machinery/api/network"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+)
+
+// networkStatusCmd represents the network-status command.
+var networkStatusCmd = &cobra.Command{
+	Use:   "network-status",
+	Short: "Show effective hostname and resolvers",
+	Long: `Show the effective hostname and DNS resolvers, and where they came from.
+
+Source is one of: config (machine configuration), kernel (kernel argument), platform (cloud metadata),
+dhcp, static (address configuration) or default.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			var remotePeer peer.Peer
+
+			resp, err := c.NetworkStatus(ctx, grpc.Peer(&remotePeer))
+			if err != nil {
+				if resp == nil {
+					return fmt.Errorf("error getting network status: %w", err)
+				}
+
+				cli.Warning("%s", err)
+			}
+
+			return renderResponse(&remotePeer, resp, func() error {
+				return networkStatusRender(&remotePeer, resp)
+			})
+		})
+	},
+}
+
+func networkStatusRender(remotePeer *peer.Peer, resp *networkapi.StatusResponse) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tHOSTNAME\tSOURCE\tRESOLVERS\tSOURCE")
+
+	defaultNode := client.AddrFromPeer(remotePeer)
+
+	for _, msg := range resp.Messages {
+		node := defaultNode
+
+		if msg.Metadata != nil {
+			node = msg.Metadata.Hostname
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", node, msg.Hostname, msg.HostnameSource, strings.Join(msg.Resolvers, ","), msg.ResolversSource)
+	}
+
+	return w.Flush()
+}
+
+func init() {
+	addCommand(withStructuredOutput(networkStatusCmd))
+}
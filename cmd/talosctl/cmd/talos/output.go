@@ -0,0 +1,67 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/output"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+)
+
+// OutputFormat is the output format set with the --output flag.
+var OutputFormat = output.Table
+
+// structuredOutputAnnotation marks the commands which support structured output formats.
+const structuredOutputAnnotation = "talosctl.structured-output"
+
+// withStructuredOutput marks the command as supporting structured output formats.
+func withStructuredOutput(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+
+	cmd.Annotations[structuredOutputAnnotation] = "true"
+
+	return cmd
+}
+
+// CheckOutputFormat verifies that the command supports the output format set with the --output flag.
+func CheckOutputFormat(cmd *cobra.Command) error {
+	if !OutputFormat.Structured() {
+		return nil
+	}
+
+	if _, ok := cmd.Annotations[structuredOutputAnnotation]; !ok {
+		return fmt.Errorf("output format %q is not supported by %s", OutputFormat, cmd.CommandPath())
+	}
+
+	return nil
+}
+
+// newOutputWriter initializes the writer for the structured output format set with the --output flag.
+func newOutputWriter() (*output.Writer, error) {
+	return output.NewWriter(os.Stdout, OutputFormat)
+}
+
+// renderResponse writes the API response in the structured output format if one was requested,
+// otherwise it renders the response as a table with renderTable.
+func renderResponse(remotePeer *peer.Peer, resp proto.Message, renderTable func() error) error {
+	if !OutputFormat.Structured() {
+		return renderTable()
+	}
+
+	w, err := newOutputWriter()
+	if err != nil {
+		return err
+	}
+
+	return w.WriteResponse(client.AddrFromPeer(remotePeer), resp)
+}
@@ -0,0 +1,119 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"github.com/talos-systems/talos/pkg/cli"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+)
+
+// scheduleFlags are the flags to delay the disruptive action.
+type scheduleFlags struct {
+	notBefore      string
+	windowStart    string
+	windowDuration string
+}
+
+func (flags *scheduleFlags) add(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&flags.notBefore, "not-before", "", "perform the action not before the specified time (RFC3339 timestamp)")
+	cmd.Flags().StringVar(&flags.windowStart, "maintenance-window-start", "", "perform the action only within the daily maintenance window starting at the specified time (HH:MM, UTC)")
+	cmd.Flags().StringVar(&flags.windowDuration, "maintenance-window-duration", "1h", "duration of the daily maintenance window")
+}
+
+// schedule builds the action schedule, nil is returned if the action shouldn't be delayed.
+func (flags *scheduleFlags) schedule() (*machine.ActionSchedule, error) {
+	if flags.notBefore == "" && flags.windowStart == "" {
+		return nil, nil
+	}
+
+	schedule := &machine.ActionSchedule{}
+
+	if flags.notBefore != "" {
+		notBefore, err := time.Parse(time.RFC3339, flags.notBefore)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing --not-before: %w", err)
+		}
+
+		if schedule.NotBefore, err = ptypes.TimestampProto(notBefore); err != nil {
+			return nil, err
+		}
+	}
+
+	if flags.windowStart != "" {
+		schedule.MaintenanceWindow = &machine.MaintenanceWindow{
+			Start:    flags.windowStart,
+			Duration: flags.windowDuration,
+		}
+	}
+
+	return schedule, nil
+}
+
+// pendingActionsCmd represents the pending-actions command.
+var pendingActionsCmd = &cobra.Command{
+	Use:   "pending-actions",
+	Short: "List actions scheduled to be performed on the node",
+	Long: `Reboot, reset and upgrade can be delayed with the --not-before and --maintenance-window-* flags.
+Pending actions are kept in memory, so they are lost if the node reboots.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			var remotePeer peer.Peer
+
+			resp, err := c.PendingActions(ctx, grpc.Peer(&remotePeer))
+			if err != nil {
+				if resp == nil {
+					return fmt.Errorf("error getting pending actions: %w", err)
+				}
+
+				cli.Warning("%s", err)
+			}
+
+			return renderResponse(&remotePeer, resp, func() error {
+				return pendingActionsRender(&remotePeer, resp)
+			})
+		})
+	},
+}
+
+func pendingActionsRender(remotePeer *peer.Peer, resp *machine.PendingActionsResponse) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tID\tACTION\tCREATED\tRUN AT")
+
+	defaultNode := client.AddrFromPeer(remotePeer)
+
+	for _, msg := range resp.Messages {
+		node := defaultNode
+
+		if msg.Metadata != nil {
+			node = msg.Metadata.Hostname
+		}
+
+		for _, action := range msg.Actions {
+			createdAt, _ := ptypes.Timestamp(action.CreatedAt) //nolint: errcheck
+			runAt, _ := ptypes.Timestamp(action.RunAt)         //nolint: errcheck
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", node, action.Id, action.Action, createdAt.Format(time.RFC3339), runAt.Format(time.RFC3339))
+		}
+	}
+
+	return w.Flush()
+}
+
+func init() {
+	addCommand(withStructuredOutput(pendingActionsCmd))
+}
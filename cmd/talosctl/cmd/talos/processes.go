@@ -44,12 +44,18 @@ var processesCmd = &cobra.Command{
 
 			switch {
 			case watchProcesses:
+				if OutputFormat.Structured() {
+					return fmt.Errorf("output format %q is not supported with --watch", OutputFormat)
+				}
+
 				if err = ui.Init(); err != nil {
 					return fmt.Errorf("failed to initialize termui: %w", err)
 				}
 				defer ui.Close()
 
 				processesUI(ctx, c)
+			case OutputFormat.Structured():
+				return processesRenderStructured(ctx, c)
 			default:
 				var output string
 				output, err = processesOutput(ctx, c)
@@ -69,7 +75,7 @@ var processesCmd = &cobra.Command{
 func init() {
 	processesCmd.Flags().StringVarP(&sortMethod, "sort", "s", "rss", "Column to sort output by. [rss|cpu]")
 	processesCmd.Flags().BoolVarP(&watchProcesses, "watch", "w", false, "Stream running processes")
-	addCommand(processesCmd)
+	addCommand(withStructuredOutput(processesCmd))
 }
 
 func processesUI(ctx context.Context, c *client.Client) {
@@ -169,6 +175,39 @@ var cpu = func(p1, p2 *machineapi.ProcessInfo) bool {
 	return p1.CpuTime > p2.CpuTime
 }
 
+func sortProcesses(procs []*machineapi.ProcessInfo) {
+	switch sortMethod {
+	case "cpu":
+		by(cpu).sort(procs)
+	default:
+		by(rss).sort(procs)
+	}
+}
+
+func processesRenderStructured(ctx context.Context, c *client.Client) error {
+	var remotePeer peer.Peer
+
+	resp, err := c.Processes(ctx, grpc.Peer(&remotePeer))
+	if err != nil {
+		if resp == nil {
+			return fmt.Errorf("error getting processes: %w", err)
+		}
+
+		cli.Warning("%s", err)
+	}
+
+	for _, msg := range resp.Messages {
+		sortProcesses(msg.Processes)
+	}
+
+	w, err := newOutputWriter()
+	if err != nil {
+		return err
+	}
+
+	return w.WriteResponse(client.AddrFromPeer(&remotePeer), resp)
+}
+
 //nolint: gocyclo
 func processesOutput(ctx context.Context, c *client.Client) (output string, err error) {
 	var remotePeer peer.Peer
@@ -191,12 +230,7 @@ func processesOutput(ctx context.Context, c *client.Client) (output string, err
 	for _, msg := range resp.Messages {
 		procs := msg.Processes
 
-		switch sortMethod {
-		case "cpu":
-			by(cpu).sort(procs)
-		default:
-			by(rss).sort(procs)
-		}
+		sortProcesses(procs)
 
 		var args string
 
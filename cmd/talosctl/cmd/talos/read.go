@@ -6,58 +6,127 @@ package talos
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"sync"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/helpers"
 	"github.com/talos-systems/talos/pkg/machinery/client"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
+var readCmdFlags struct {
+	offset   int64
+	retries  int
+	redacted bool
+}
+
+// readMachineConfigArg is the special path which reads the machine configuration.
+const readMachineConfigArg = "machineconfig"
+
 // readCmd represents the read command.
 var readCmd = &cobra.Command{
 	Use:   "read <path>",
 	Short: "Read a file on the machine",
-	Long:  ``,
-	Args:  cobra.ExactArgs(1),
+	Long: `Read a file on the machine and write it to stdout.
+
+If the connection is lost, the transfer is resumed from the last received byte.
+Interrupted transfers can also be continued manually with the --offset flag, e.g.:
+
+    talosctl read --offset $(stat -c %s core.dump) /var/lib/core.dump >> core.dump
+
+The machine configuration is read with "talosctl read machineconfig". Secrets are stripped from the configuration
+unless the client has the os:admin role; --redacted strips the secrets anyway, e.g. to share the configuration
+in a bug report.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return WithClient(func(ctx context.Context, c *client.Client) error {
 			if err := helpers.FailIfMultiNodes(ctx, "read"); err != nil {
 				return err
 			}
 
-			r, errCh, err := c.Read(ctx, args[0])
-			if err != nil {
-				return fmt.Errorf("error reading file: %w", err)
+			if args[0] == readMachineConfigArg {
+				return readMachineConfig(ctx, c)
 			}
 
-			defer r.Close() //nolint: errcheck
+			if readCmdFlags.redacted {
+				return fmt.Errorf("--redacted is supported only with %q", readMachineConfigArg)
+			}
+
+			offset := readCmdFlags.offset
 
-			var wg sync.WaitGroup
+			for attempt := 0; ; attempt++ {
+				n, err := readFile(ctx, c, args[0], offset)
+				offset += n
 
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				for err := range errCh {
-					fmt.Fprintln(os.Stderr, err.Error())
+				if err == nil {
+					return nil
 				}
-			}()
 
-			defer wg.Wait()
+				if status.Code(errors.Unwrap(err)) != codes.Unavailable || attempt >= readCmdFlags.retries {
+					return err
+				}
 
-			_, err = io.Copy(os.Stdout, r)
-			if err != nil {
-				return fmt.Errorf("error reading: %w", err)
+				fmt.Fprintf(os.Stderr, "transfer interrupted at offset %d, resuming: %s\n", offset, err)
 			}
-
-			return r.Close()
 		})
 	},
 }
 
+func readMachineConfig(ctx context.Context, c *client.Client) error {
+	resp, err := c.MachineConfigRead(ctx, readCmdFlags.redacted)
+	if err != nil {
+		return fmt.Errorf("error reading machine config: %w", err)
+	}
+
+	msg := resp.Messages[0]
+
+	if msg.Redacted && !readCmdFlags.redacted {
+		fmt.Fprintf(os.Stderr, "secrets are redacted: %s role is required to read them\n", constants.RoleAdmin)
+	}
+
+	_, err = os.Stdout.Write(msg.Data)
+
+	return err
+}
+
+func readFile(ctx context.Context, c *client.Client, path string, offset int64) (int64, error) {
+	r, errCh, err := c.ReadFromOffset(ctx, path, offset)
+	if err != nil {
+		return 0, fmt.Errorf("error reading file: %w", err)
+	}
+
+	defer r.Close() //nolint: errcheck
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for err := range errCh {
+			fmt.Fprintln(os.Stderr, err.Error())
+		}
+	}()
+
+	defer wg.Wait()
+
+	n, err := io.Copy(os.Stdout, r)
+	if err != nil {
+		return n, fmt.Errorf("error reading: %w", err)
+	}
+
+	return n, r.Close()
+}
+
 func init() {
+	readCmd.Flags().Int64Var(&readCmdFlags.offset, "offset", 0, "offset in bytes to start reading the file from")
+	readCmd.Flags().IntVar(&readCmdFlags.retries, "retries", 5, "number of times to resume the transfer if the connection is lost")
+	readCmd.Flags().BoolVar(&readCmdFlags.redacted, "redacted", false, "strip the secrets from the machine configuration")
 	addCommand(readCmd)
 }
@@ -10,26 +10,53 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/client"
 )
 
+var rebootCmdFlags struct {
+	force bool
+	scheduleFlags
+	rolloutFlags
+}
+
 // rebootCmd represents the reboot command.
 var rebootCmd = &cobra.Command{
 	Use:   "reboot",
 	Short: "Reboot a node",
-	Long:  ``,
-	Args:  cobra.NoArgs,
+	Long: `Reboot a node.
+
+With --concurrency the nodes are rebooted in batches of the specified size. With --rolling
+each batch of nodes should come back and become healthy before the next batch is rebooted.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		schedule, err := rebootCmdFlags.schedule()
+		if err != nil {
+			return err
+		}
+
+		if rebootCmdFlags.rolling && schedule != nil {
+			return fmt.Errorf("rolling reboot can't be scheduled")
+		}
+
 		return WithClient(func(ctx context.Context, c *client.Client) error {
-			if err := c.Reboot(ctx); err != nil {
-				return fmt.Errorf("error executing reboot: %s", err)
-			}
+			return rebootCmdFlags.rolloutFlags.run(ctx, c, true, func(ctx context.Context) error {
+				if err := c.RebootGeneric(ctx, &machine.RebootRequest{
+					Force:    rebootCmdFlags.force,
+					Schedule: schedule,
+				}); err != nil {
+					return fmt.Errorf("error executing reboot: %s", err)
+				}
 
-			return nil
+				return nil
+			})
 		})
 	},
 }
 
 func init() {
+	rebootCmd.Flags().BoolVar(&rebootCmdFlags.force, "force", false, "skip cordon and drain of the node")
+	rebootCmdFlags.scheduleFlags.add(rebootCmd)
+	rebootCmdFlags.rolloutFlags.add(rebootCmd)
 	addCommand(rebootCmd)
 }
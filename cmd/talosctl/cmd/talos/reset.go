@@ -6,43 +6,118 @@ package talos
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/spf13/cobra"
+	"github.com/talos-systems/go-retry/retry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/client"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
 var resetCmdFlags struct {
 	graceful           bool
 	reboot             bool
+	maintenance        bool
+	wait               bool
+	waitTimeout        time.Duration
 	systemLabelsToWipe []string
+	wipeMode           string
+	scheduleFlags
 }
 
 // resetCmd represents the reset command.
 var resetCmd = &cobra.Command{
 	Use:   "reset",
 	Short: "Reset a node",
-	Long:  ``,
-	Args:  cobra.NoArgs,
+	Long: `Reset a node.
+
+With --wait the progress of the reset is streamed from the node until it shuts down or reboots.
+
+With --maintenance the node reboots into the maintenance mode after resetting, so that it can
+be re-provisioned immediately with "talosctl apply-config --insecure". As the request is stored
+in the META partition, only the STATE and EPHEMERAL partitions are wiped by default. Combined
+with --wait, the command returns once the node is in the maintenance mode.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		schedule, err := resetCmdFlags.schedule()
+		if err != nil {
+			return err
+		}
+
+		if resetCmdFlags.wait && schedule != nil {
+			return fmt.Errorf("scheduled reset can't be waited for")
+		}
+
+		wipeMode, ok := machine.ResetRequest_WipeMode_value[strings.ToUpper(resetCmdFlags.wipeMode)]
+		if !ok {
+			return fmt.Errorf("unsupported wipe mode %q", resetCmdFlags.wipeMode)
+		}
+
+		systemLabelsToWipe := resetCmdFlags.systemLabelsToWipe
+
+		if resetCmdFlags.maintenance {
+			resetCmdFlags.reboot = true
+
+			if len(systemLabelsToWipe) == 0 {
+				systemLabelsToWipe = []string{constants.StatePartitionLabel, constants.EphemeralPartitionLabel}
+			}
+		}
+
 		return WithClient(func(ctx context.Context, c *client.Client) error {
 			var systemPartitionsToWipe []*machine.ResetPartitionSpec
 
-			for _, label := range resetCmdFlags.systemLabelsToWipe {
+			for _, label := range systemLabelsToWipe {
 				systemPartitionsToWipe = append(systemPartitionsToWipe, &machine.ResetPartitionSpec{
 					Label: label,
 					Wipe:  true,
 				})
 			}
 
-			if err := c.ResetGeneric(ctx, &machine.ResetRequest{
+			req := &machine.ResetRequest{
 				Graceful:               resetCmdFlags.graceful,
 				Reboot:                 resetCmdFlags.reboot,
 				SystemPartitionsToWipe: systemPartitionsToWipe,
-			}); err != nil {
-				return fmt.Errorf("error executing reset: %s", err)
+				Schedule:               schedule,
+				WipeMode:               machine.ResetRequest_WipeMode(wipeMode),
+				Maintenance:            resetCmdFlags.maintenance,
+			}
+
+			if !resetCmdFlags.wait {
+				if err := c.ResetGeneric(ctx, req); err != nil {
+					return fmt.Errorf("error executing reset: %s", err)
+				}
+
+				return nil
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, resetCmdFlags.waitTimeout)
+			defer cancel()
+
+			if err := resetWait(ctx, c, req); err != nil {
+				return err
+			}
+
+			if !resetCmdFlags.maintenance {
+				return nil
+			}
+
+			for _, node := range Nodes {
+				if err := waitForMaintenance(node); err != nil {
+					return fmt.Errorf("error waiting for node %q to enter maintenance mode: %w", node, err)
+				}
+
+				fmt.Printf("%s: node is in maintenance mode\n", node)
 			}
 
 			return nil
@@ -50,9 +125,110 @@ var resetCmd = &cobra.Command{
 	},
 }
 
+// resetWait resets the nodes and prints the progress until the nodes shut down or reboot.
+func resetWait(ctx context.Context, c *client.Client, req *machine.ResetRequest) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tPHASE\tTASK\tSTATUS")
+
+	var (
+		started = map[string]bool{}
+		failed  []string
+	)
+
+	err := c.ResetWatch(ctx, req, func(ch <-chan client.Event) {
+		for event := range ch {
+			started[event.Node] = true
+
+			switch msg := event.Payload.(type) {
+			case *machine.SequenceEvent:
+				if msg.GetError() != nil {
+					failed = append(failed, fmt.Sprintf("%s: %s", event.Node, msg.GetError().GetMessage()))
+				}
+			case *machine.PhaseEvent:
+				message := fmt.Sprintf("%s %d/%d", msg.GetAction(), msg.GetNumber(), msg.GetTotal())
+
+				fmt.Fprintf(w, "%s\t%s\t\t%s\n", event.Node, msg.GetPhase(), eventProgressMessage(message, msg.GetDuration(), msg.GetError()))
+			case *machine.TaskEvent:
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", event.Node, msg.GetPhase(), msg.GetTask(), eventProgressMessage(msg.GetAction().String(), msg.GetDuration(), msg.GetError()))
+			}
+
+			w.Flush() //nolint: errcheck
+		}
+	})
+
+	if len(failed) > 0 {
+		return fmt.Errorf("reset failed:\n%s", strings.Join(failed, "\n"))
+	}
+
+	var errs []error
+
+	if multiErr, ok := err.(*multierror.Error); ok {
+		errs = multiErr.Errors
+	} else if err != nil {
+		errs = []error{err}
+	}
+
+	var resetErr *multierror.Error
+
+	for _, err := range errs {
+		// the node closes the connection once it shuts down or reboots
+		var nodeErr *client.NodeError
+
+		if errors.As(err, &nodeErr) {
+			if started[nodeErr.Node] && status.Code(nodeErr.Err) == codes.Unavailable {
+				continue
+			}
+		} else if len(started) > 0 && status.Code(err) == codes.Unavailable {
+			continue
+		}
+
+		resetErr = multierror.Append(resetErr, err)
+	}
+
+	if resetErr.ErrorOrNil() != nil {
+		return fmt.Errorf("error executing reset: %w", resetErr)
+	}
+
+	return nil
+}
+
+// waitForMaintenance waits for the maintenance service to be available on the node.
+func waitForMaintenance(node string) error {
+	return retry.Constant(resetCmdFlags.waitTimeout, retry.WithUnits(5*time.Second)).Retry(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		c, err := client.New(ctx, client.WithTLSConfig(&tls.Config{
+			InsecureSkipVerify: true,
+		}), client.WithEndpoints(node))
+		if err != nil {
+			return retry.ExpectedError(err)
+		}
+
+		//nolint: errcheck
+		defer c.Close()
+
+		_, err = c.Disks(ctx)
+
+		// maintenance service might require the token, while the regular node API rejects
+		// the connection without the client certificate
+		switch status.Code(err) { //nolint: exhaustive
+		case codes.OK, codes.Unauthenticated, codes.PermissionDenied:
+			return nil
+		default:
+			return retry.ExpectedError(err)
+		}
+	})
+}
+
 func init() {
 	resetCmd.Flags().BoolVar(&resetCmdFlags.graceful, "graceful", true, "if true, attempt to cordon/drain node and leave etcd (if applicable)")
 	resetCmd.Flags().BoolVar(&resetCmdFlags.reboot, "reboot", false, "if true, reboot the node after resetting instead of shutting down")
+	resetCmd.Flags().BoolVar(&resetCmdFlags.maintenance, "maintenance", false, "reboot the node into the maintenance mode after resetting (implies --reboot)")
+	resetCmd.Flags().BoolVar(&resetCmdFlags.wait, "wait", false, "stream the reset progress and wait for the node to shut down or reboot (or to enter the maintenance mode)")
+	resetCmd.Flags().DurationVar(&resetCmdFlags.waitTimeout, "wait-timeout", 30*time.Minute, "timeout to wait for the reset to finish")
 	resetCmd.Flags().StringSliceVar(&resetCmdFlags.systemLabelsToWipe, "system-labels-to-wipe", nil, "if set, just wipe selected system disk partitions by label but keep other partitions intact")
+	resetCmd.Flags().StringVar(&resetCmdFlags.wipeMode, "wipe-mode", "fast", "wipe mode: fast (re-create the partition table or filesystems) or secure (also overwrite the data)")
+	resetCmdFlags.scheduleFlags.add(resetCmd)
 	addCommand(resetCmd)
 }
@@ -18,10 +18,11 @@ import (
 
 // restartCmd represents the restart command.
 var restartCmd = &cobra.Command{
-	Use:   "restart <id>",
-	Short: "Restart a process",
-	Long:  ``,
-	Args:  cobra.ExactArgs(1),
+	Use:               "restart <id>",
+	Short:             "Restart a process",
+	Long:              ``,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeServiceArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return WithClient(func(ctx context.Context, c *client.Client) error {
 			var namespace string
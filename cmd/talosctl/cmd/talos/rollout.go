@@ -0,0 +1,220 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/cobra"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/helpers"
+	"github.com/talos-systems/talos/pkg/cluster"
+	"github.com/talos-systems/talos/pkg/cluster/check"
+	"github.com/talos-systems/talos/pkg/conditions"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+)
+
+// rolloutFlags control running the operation against many nodes in batches.
+type rolloutFlags struct {
+	concurrency int
+	rolling     bool
+	timeout     time.Duration
+}
+
+func (flags *rolloutFlags) add(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&flags.concurrency, "concurrency", 0, "number of nodes to run the operation on at once (defaults to all the nodes, or to 1 with --rolling)")
+	cmd.Flags().BoolVar(&flags.rolling, "rolling", false, "wait for the nodes to become healthy before proceeding with the next batch of nodes")
+	cmd.Flags().DurationVar(&flags.timeout, "rolling-timeout", 20*time.Minute, "timeout to wait for the nodes of the batch to become healthy")
+}
+
+func (flags *rolloutFlags) enabled() bool {
+	return flags.concurrency > 0 || flags.rolling
+}
+
+// run performs the operation against the nodes in batches.
+//
+// With --rolling, nodes of each batch should become healthy before the next batch is started.
+// If the operation reboots the nodes, the nodes should also reboot to be considered healthy.
+func (flags *rolloutFlags) run(ctx context.Context, c *client.Client, reboots bool, operation func(ctx context.Context) error) error {
+	if !flags.enabled() {
+		return operation(ctx)
+	}
+
+	concurrency := flags.concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	batches := helpers.Batches(Nodes, concurrency)
+
+	for i, batch := range batches {
+		fmt.Fprintf(os.Stderr, "batch %d/%d: %s\n", i+1, len(batches), strings.Join(batch, ", "))
+
+		batchCtx := client.WithNodes(ctx, batch...)
+
+		var bootIDs map[string]string
+
+		if flags.rolling && reboots {
+			bootIDs = map[string]string{}
+
+			for _, node := range batch {
+				bootID, err := readBootID(client.WithNodes(ctx, node), c)
+				if err != nil {
+					return fmt.Errorf("error reading boot ID of node %q: %w", node, err)
+				}
+
+				bootIDs[node] = bootID
+			}
+		}
+
+		if err := operation(batchCtx); err != nil {
+			return fmt.Errorf("batch %d/%d failed: %w", i+1, len(batches), err)
+		}
+
+		if !flags.rolling {
+			continue
+		}
+
+		if err := flags.waitHealthy(ctx, c, batch, bootIDs); err != nil {
+			return fmt.Errorf("batch %d/%d failed to become healthy: %w", i+1, len(batches), err)
+		}
+	}
+
+	return nil
+}
+
+// waitHealthy waits for the nodes to reboot (if bootIDs are set), finish booting and report all the services as healthy.
+func (flags *rolloutFlags) waitHealthy(ctx context.Context, c *client.Client, nodes []string, bootIDs map[string]string) error {
+	clientProvider := &cluster.ConfigClientProvider{
+		DefaultClient: c,
+	}
+	defer clientProvider.Close() //nolint: errcheck
+
+	state := struct {
+		cluster.ClientProvider
+		cluster.K8sProvider
+		cluster.Info
+	}{
+		ClientProvider: clientProvider,
+		K8sProvider: &cluster.KubernetesClient{
+			ClientProvider: clientProvider,
+		},
+		Info: &clusterNodes{
+			WorkerNodes: nodes,
+		},
+	}
+
+	var checks []check.ClusterCheck
+
+	if bootIDs != nil {
+		checks = append(checks, func(cluster check.ClusterInfo) conditions.Condition {
+			return conditions.PollingCondition("nodes to reboot", func(ctx context.Context) error {
+				return nodesRebootedAssertion(ctx, c, bootIDs)
+			}, flags.timeout, 5*time.Second)
+		})
+	}
+
+	checks = append(checks,
+		func(cluster check.ClusterInfo) conditions.Condition {
+			return conditions.PollingCondition("apid to be ready", func(ctx context.Context) error {
+				return check.ApidReadyAssertion(ctx, cluster)
+			}, flags.timeout, 5*time.Second)
+		},
+		func(cluster check.ClusterInfo) conditions.Condition {
+			return conditions.PollingCondition("nodes to finish boot sequence", func(ctx context.Context) error {
+				return check.AllNodesBootedAssertion(ctx, cluster)
+			}, flags.timeout, 5*time.Second)
+		},
+		func(cluster check.ClusterInfo) conditions.Condition {
+			return conditions.PollingCondition("all services to be healthy", func(ctx context.Context) error {
+				return servicesHealthyAssertion(ctx, c, nodes)
+			}, flags.timeout, 5*time.Second)
+		},
+	)
+
+	checkCtx, checkCtxCancel := context.WithTimeout(ctx, flags.timeout)
+	defer checkCtxCancel()
+
+	return check.Wait(checkCtx, &state, checks, check.StderrReporter())
+}
+
+func nodesRebootedAssertion(ctx context.Context, c *client.Client, bootIDs map[string]string) error {
+	for node, bootID := range bootIDs {
+		currentBootID, err := readBootID(client.WithNodes(ctx, node), c)
+		if err != nil {
+			return err
+		}
+
+		if currentBootID == bootID {
+			return fmt.Errorf("%s: node is not rebooted yet", node)
+		}
+	}
+
+	return nil
+}
+
+func servicesHealthyAssertion(ctx context.Context, c *client.Client, nodes []string) error {
+	resp, err := c.ServiceList(client.WithNodes(ctx, nodes...))
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Messages) != len(nodes) {
+		return fmt.Errorf("expected a response with %d node(s), got %d", len(nodes), len(resp.Messages))
+	}
+
+	var multiErr *multierror.Error
+
+	for _, msg := range resp.Messages {
+		node := msg.GetMetadata().GetHostname()
+
+		for _, svc := range msg.Services {
+			switch svc.GetState() {
+			case "Finished", "Skipped":
+			case "Running":
+				if !svc.GetHealth().GetUnknown() && !svc.GetHealth().GetHealthy() {
+					multiErr = multierror.Append(multiErr, fmt.Errorf("%s: service %q is not healthy: %s", node, svc.GetId(), svc.GetHealth().GetLastMessage()))
+				}
+			default:
+				multiErr = multierror.Append(multiErr, fmt.Errorf("%s: service %q is %s", node, svc.GetId(), svc.GetState()))
+			}
+		}
+	}
+
+	return multiErr.ErrorOrNil()
+}
+
+func readBootID(ctx context.Context, c *client.Client) (string, error) {
+	reader, errCh, err := c.Read(ctx, "/proc/sys/kernel/random/boot_id")
+	if err != nil {
+		return "", err
+	}
+
+	defer reader.Close() //nolint: errcheck
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = io.Copy(ioutil.Discard, reader); err != nil {
+		return "", err
+	}
+
+	for err = range errCh {
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return strings.TrimSpace(string(body)), reader.Close()
+}
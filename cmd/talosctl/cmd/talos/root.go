@@ -58,6 +58,8 @@ func WithClientNoNodes(action func(context.Context, *client.Client) error) error
 		// nolint: errcheck
 		defer c.Close()
 
+		warnClientCertificateExpiry(c)
+
 		return action(ctx, c)
 	})
 }
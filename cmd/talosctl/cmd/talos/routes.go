@@ -37,7 +37,9 @@ var routesCmd = &cobra.Command{
 				cli.Warning("%s", err)
 			}
 
-			return routesRender(&remotePeer, resp)
+			return renderResponse(&remotePeer, resp, func() error {
+				return routesRender(&remotePeer, resp)
+			})
 		})
 	},
 }
@@ -64,5 +66,5 @@ func routesRender(remotePeer *peer.Peer, resp *networkapi.RoutesResponse) error
 }
 
 func init() {
-	addCommand(routesCmd)
+	addCommand(withStructuredOutput(routesCmd))
 }
@@ -0,0 +1,114 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"github.com/talos-systems/talos/pkg/cli"
+	"github.com/talos-systems/talos/pkg/machinery/api/common"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+)
+
+// sequenceCmd represents the sequence command.
+var sequenceCmd = &cobra.Command{
+	Use:   "sequence",
+	Short: "Pause and resume the sequences (boot, upgrade, etc.)",
+	Long: `Pause and resume the sequences (boot, upgrade, etc.).
+
+Paused sequence stops before its next phase, the running phase is not interrupted.
+Sequences started while paused stop before the first phase. Pause and resume are reported
+as the sequence events ("talosctl events").
+
+Shutdown, reboot, reset, bootstrap and recover sequences are never paused. Pause is not preserved across reboots.`,
+}
+
+var sequencePauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pause the sequences before their next phase",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			var remotePeer peer.Peer
+
+			resp, err := c.SequencePause(ctx, grpc.Peer(&remotePeer))
+			if err != nil {
+				if resp == nil {
+					return fmt.Errorf("error pausing sequences: %w", err)
+				}
+
+				cli.Warning("%s", err)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "NODE\tSTATUS")
+
+			for _, msg := range resp.Messages {
+				status := "paused"
+				if !msg.Paused {
+					status = "already paused"
+				}
+
+				fmt.Fprintf(w, "%s\t%s\n", sequenceNode(&remotePeer, msg.Metadata), status)
+			}
+
+			return w.Flush()
+		})
+	},
+}
+
+var sequenceResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume the paused sequences",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			var remotePeer peer.Peer
+
+			resp, err := c.SequenceResume(ctx, grpc.Peer(&remotePeer))
+			if err != nil {
+				if resp == nil {
+					return fmt.Errorf("error resuming sequences: %w", err)
+				}
+
+				cli.Warning("%s", err)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "NODE\tSTATUS")
+
+			for _, msg := range resp.Messages {
+				status := "resumed"
+				if !msg.Resumed {
+					status = "not paused"
+				}
+
+				fmt.Fprintf(w, "%s\t%s\n", sequenceNode(&remotePeer, msg.Metadata), status)
+			}
+
+			return w.Flush()
+		})
+	},
+}
+
+func sequenceNode(remotePeer *peer.Peer, metadata *common.Metadata) string {
+	if metadata != nil {
+		return metadata.Hostname
+	}
+
+	return client.AddrFromPeer(remotePeer)
+}
+
+func init() {
+	sequenceCmd.AddCommand(sequencePauseCmd, sequenceResumeCmd)
+	addCommand(sequenceCmd)
+}
@@ -21,15 +21,31 @@ import (
 	"github.com/talos-systems/talos/pkg/machinery/client"
 )
 
+var serviceRolloutFlags rolloutFlags
+
 // serviceCmd represents the service command.
 var serviceCmd = &cobra.Command{
-	Use:     "service [<id> [start|stop|restart|status]]",
+	Use:     "service [<id> [start|stop|restart|reload|status]]",
 	Aliases: []string{"services"},
 	Short:   "Retrieve the state of a service (or all services), control service state",
 	Long: `Service control command. If run without arguments, lists all the services and their state.
 If service ID is specified, default action 'status' is executed which shows status of a single list service.
-With actions 'start', 'stop', 'restart', service state is updated respectively.`,
+With actions 'start', 'stop', 'restart', service state is updated respectively.
+Action 'reload' reloads service configuration without a restart, if supported by the service.
+
+With action 'restart', --concurrency restarts the service on the nodes in batches of the specified size,
+and --rolling waits for the services on each batch of nodes to become healthy before proceeding.`,
 	Args: cobra.MaximumNArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		switch len(args) {
+		case 0:
+			return completeServiceIDs(), cobra.ShellCompDirectiveNoFileComp
+		case 1:
+			return []string{"start", "stop", "restart", "reload", "status"}, cobra.ShellCompDirectiveNoFileComp
+		default:
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		action := "status"
 		serviceID := ""
@@ -42,6 +58,14 @@ With actions 'start', 'stop', 'restart', service state is updated respectively.`
 			action = args[1]
 		}
 
+		if OutputFormat.Structured() && action != "status" {
+			return fmt.Errorf("output format %q is only supported for the 'status' action", OutputFormat)
+		}
+
+		if serviceRolloutFlags.enabled() && action != "restart" {
+			return fmt.Errorf("--concurrency and --rolling are only supported for the 'restart' action")
+		}
+
 		return WithClient(func(ctx context.Context, c *client.Client) error {
 			switch action {
 			case "status":
@@ -55,7 +79,11 @@ With actions 'start', 'stop', 'restart', service state is updated respectively.`
 			case "stop":
 				return serviceStop(ctx, c, serviceID)
 			case "restart":
-				return serviceRestart(ctx, c, serviceID)
+				return serviceRolloutFlags.run(ctx, c, false, func(ctx context.Context) error {
+					return serviceRestart(ctx, c, serviceID)
+				})
+			case "reload":
+				return serviceReload(ctx, c, serviceID)
 			default:
 				return fmt.Errorf("unsupported service action: %q", action)
 			}
@@ -75,26 +103,28 @@ func serviceList(ctx context.Context, c *client.Client) error {
 		cli.Warning("%s", err)
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(w, "NODE\tSERVICE\tSTATE\tHEALTH\tLAST CHANGE\tLAST EVENT")
+	return renderResponse(&remotePeer, resp, func() error {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "NODE\tSERVICE\tSTATE\tHEALTH\tLAST CHANGE\tLAST EVENT")
 
-	defaultNode := client.AddrFromPeer(&remotePeer)
+		defaultNode := client.AddrFromPeer(&remotePeer)
 
-	for _, msg := range resp.Messages {
-		for _, s := range msg.Services {
-			svc := serviceInfoWrapper{s}
+		for _, msg := range resp.Messages {
+			for _, s := range msg.Services {
+				svc := serviceInfoWrapper{s}
 
-			node := defaultNode
+				node := defaultNode
 
-			if msg.Metadata != nil {
-				node = msg.Metadata.Hostname
-			}
+				if msg.Metadata != nil {
+					node = msg.Metadata.Hostname
+				}
 
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s ago\t%s\n", node, svc.Id, svc.State, svc.HealthStatus(), svc.LastUpdated(), svc.LastEvent())
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s ago\t%s\n", node, svc.Id, svc.State, svc.HealthStatus(), svc.LastUpdated(), svc.LastEvent())
+			}
 		}
-	}
 
-	return w.Flush()
+		return w.Flush()
+	})
 }
 
 func serviceInfo(ctx context.Context, c *client.Client, id string) error {
@@ -109,10 +139,18 @@ func serviceInfo(ctx context.Context, c *client.Client, id string) error {
 		cli.Warning("%s", err)
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	if len(services) == 0 {
+		return fmt.Errorf("service %q is not registered on any nodes", id)
+	}
 
 	defaultNode := client.AddrFromPeer(&remotePeer)
 
+	if OutputFormat.Structured() {
+		return serviceInfoRenderStructured(defaultNode, services)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+
 	for _, s := range services {
 		node := defaultNode
 
@@ -143,11 +181,28 @@ func serviceInfo(ctx context.Context, c *client.Client, id string) error {
 		}
 	}
 
-	if len(services) == 0 {
-		return fmt.Errorf("service %q is not registered on any nodes", id)
+	return w.Flush()
+}
+
+func serviceInfoRenderStructured(defaultNode string, services []client.ServiceInfo) error {
+	w, err := newOutputWriter()
+	if err != nil {
+		return err
 	}
 
-	return w.Flush()
+	for _, s := range services {
+		node := defaultNode
+
+		if s.Metadata != nil {
+			node = s.Metadata.Hostname
+		}
+
+		if err = w.WriteMessage(node, s.Service); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func serviceStart(ctx context.Context, c *client.Client, id string) error {
@@ -240,6 +295,36 @@ func serviceRestart(ctx context.Context, c *client.Client, id string) error {
 	return w.Flush()
 }
 
+func serviceReload(ctx context.Context, c *client.Client, id string) error {
+	var remotePeer peer.Peer
+
+	resp, err := c.ServiceReload(ctx, id, grpc.Peer(&remotePeer))
+	if err != nil {
+		if resp == nil {
+			return fmt.Errorf("error reloading service: %w", err)
+		}
+
+		cli.Warning("%s", err)
+	}
+
+	defaultNode := client.AddrFromPeer(&remotePeer)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tRESPONSE")
+
+	for _, msg := range resp.Messages {
+		node := defaultNode
+
+		if msg.Metadata != nil {
+			node = msg.Metadata.Hostname
+		}
+
+		fmt.Fprintf(w, "%s\t%s\n", node, msg.Resp)
+	}
+
+	return w.Flush()
+}
+
 type serviceInfoWrapper struct {
 	*machineapi.ServiceInfo
 }
@@ -276,5 +361,6 @@ func (svc serviceInfoWrapper) HealthStatus() string {
 }
 
 func init() {
-	addCommand(serviceCmd)
+	serviceRolloutFlags.add(serviceCmd)
+	addCommand(withStructuredOutput(serviceCmd))
 }
@@ -10,9 +10,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/client"
 )
 
+var shutdownCmdFlags struct {
+	force bool
+}
+
 // shutdownCmd represents the shutdown command.
 var shutdownCmd = &cobra.Command{
 	Use:   "shutdown",
@@ -21,7 +26,9 @@ var shutdownCmd = &cobra.Command{
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return WithClient(func(ctx context.Context, c *client.Client) error {
-			if err := c.Shutdown(ctx); err != nil {
+			if err := c.ShutdownGeneric(ctx, &machine.ShutdownRequest{
+				Force: shutdownCmdFlags.force,
+			}); err != nil {
 				return fmt.Errorf("error executing shutdown: %s", err)
 			}
 
@@ -31,5 +38,6 @@ var shutdownCmd = &cobra.Command{
 }
 
 func init() {
+	shutdownCmd.Flags().BoolVar(&shutdownCmdFlags.force, "force", false, "skip cordon and drain of the node")
 	addCommand(shutdownCmd)
 }
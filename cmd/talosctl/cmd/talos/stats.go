@@ -54,7 +54,9 @@ var statsCmd = &cobra.Command{
 				cli.Warning("%s", err)
 			}
 
-			return statsRender(&remotePeer, resp)
+			return renderResponse(&remotePeer, resp, func() error {
+				return statsRender(&remotePeer, resp)
+			})
 		})
 	},
 }
@@ -96,5 +98,5 @@ func statsRender(remotePeer *peer.Peer, resp *machineapi.StatsResponse) error {
 func init() {
 	statsCmd.Flags().BoolVarP(&kubernetes, "kubernetes", "k", false, "use the k8s.io containerd namespace")
 	statsCmd.Flags().BoolVarP(&useCRI, "use-cri", "c", false, "use the CRI driver")
-	addCommand(statsCmd)
+	addCommand(withStructuredOutput(statsCmd))
 }
@@ -53,38 +53,45 @@ var timeCmd = &cobra.Command{
 				cli.Warning("%s", err)
 			}
 
-			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-			fmt.Fprintln(w, "NODE\tNTP-SERVER\tNODE-TIME\tNTP-SERVER-TIME")
+			return renderResponse(&remotePeer, resp, func() error {
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+				fmt.Fprintln(w, "NODE\tNTP-SERVER\tSOURCE\tNODE-TIME\tNTP-SERVER-TIME")
 
-			defaultNode := client.AddrFromPeer(&remotePeer)
+				defaultNode := client.AddrFromPeer(&remotePeer)
 
-			var localtime, remotetime time.Time
-			for _, msg := range resp.Messages {
-				node := defaultNode
+				var localtime, remotetime time.Time
+				for _, msg := range resp.Messages {
+					node := defaultNode
 
-				if msg.Metadata != nil {
-					node = msg.Metadata.Hostname
-				}
+					if msg.Metadata != nil {
+						node = msg.Metadata.Hostname
+					}
 
-				localtime, err = ptypes.Timestamp(msg.Localtime)
-				if err != nil {
-					return fmt.Errorf("error parsing local time: %w", err)
-				}
+					localtime, err = ptypes.Timestamp(msg.Localtime)
+					if err != nil {
+						return fmt.Errorf("error parsing local time: %w", err)
+					}
 
-				remotetime, err = ptypes.Timestamp(msg.Remotetime)
-				if err != nil {
-					return fmt.Errorf("error parsing remote time: %w", err)
-				}
+					remotetime, err = ptypes.Timestamp(msg.Remotetime)
+					if err != nil {
+						return fmt.Errorf("error parsing remote time: %w", err)
+					}
 
-				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", node, msg.Server, localtime.String(), remotetime.String())
-			}
+					source := msg.ServerSource
+					if source == "" {
+						source = "-"
+					}
+
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", node, msg.Server, source, localtime.String(), remotetime.String())
+				}
 
-			return w.Flush()
+				return w.Flush()
+			})
 		})
 	},
 }
 
 func init() {
 	timeCmd.Flags().StringVarP(&timeCmdFlags.ntpServer, "check", "c", "", "checks server time against specified ntp server")
-	addCommand(timeCmd)
+	addCommand(withStructuredOutput(timeCmd))
 }
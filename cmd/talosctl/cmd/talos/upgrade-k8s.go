@@ -21,8 +21,10 @@ import (
 var upgradeK8sCmd = &cobra.Command{
 	Use:   "upgrade-k8s",
 	Short: "Upgrade Kubernetes control plane in the Talos cluster.",
-	Long:  `Command runs upgrade of Kubernetes control plane components between specified versions. Pod-checkpointer is handled in a special way to speed up kube-apisever upgrades.`,
-	Args:  cobra.NoArgs,
+	Long: `Command runs upgrade of Kubernetes control plane components between specified versions. Pod-checkpointer is handled in a special way to speed up kube-apisever upgrades.
+
+Images of the target version are pulled on all the nodes before any component is updated, so the upgrade is aborted early if the images can't be pulled.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return WithClient(upgradeKubernetes)
 	},
@@ -47,8 +49,10 @@ func upgradeKubernetes(ctx context.Context, c *client.Client) error {
 	defer clientProvider.Close() //nolint: errcheck
 
 	state := struct {
+		cluster.ClientProvider
 		cluster.K8sProvider
 	}{
+		ClientProvider: clientProvider,
 		K8sProvider: &cluster.KubernetesClient{
 			ClientProvider: clientProvider,
 			ForceEndpoint:  healthCmdFlags.forceEndpoint,
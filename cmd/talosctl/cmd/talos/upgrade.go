@@ -16,21 +16,30 @@ import (
 	"google.golang.org/grpc/peer"
 
 	"github.com/talos-systems/talos/pkg/cli"
+	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/client"
 )
 
 var (
-	upgradeImage string
-	preserve     bool
-	stage        bool
+	upgradeImage  string
+	preserve      bool
+	stage         bool
+	upgradeForce  bool
+	upgradeDryRun bool
+
+	upgradeScheduleFlags scheduleFlags
+	upgradeRolloutFlags  rolloutFlags
 )
 
 // upgradeCmd represents the processes command.
 var upgradeCmd = &cobra.Command{
 	Use:   "upgrade",
 	Short: "Upgrade Talos on the target node",
-	Long:  ``,
-	Args:  cobra.NoArgs,
+	Long: `Upgrade Talos on the target node.
+
+With --concurrency the nodes are upgraded in batches of the specified size. With --rolling
+each batch of nodes should come back and become healthy before the next batch is upgraded.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return upgrade()
 	},
@@ -40,39 +49,75 @@ func init() {
 	upgradeCmd.Flags().StringVarP(&upgradeImage, "image", "i", "", "the container image to use for performing the install")
 	upgradeCmd.Flags().BoolVarP(&preserve, "preserve", "p", false, "preserve data")
 	upgradeCmd.Flags().BoolVarP(&stage, "stage", "s", false, "stage the upgrade to perform it after a reboot")
+	upgradeCmd.Flags().BoolVar(&upgradeForce, "force", false, "skip cordon and drain of the node")
+	upgradeCmd.Flags().BoolVar(&upgradeDryRun, "dry-run", false, "validate the upgrade and show the actions without performing it")
+	upgradeScheduleFlags.add(upgradeCmd)
+	upgradeRolloutFlags.add(upgradeCmd)
 	addCommand(upgradeCmd)
 }
 
 func upgrade() error {
+	schedule, err := upgradeScheduleFlags.schedule()
+	if err != nil {
+		return err
+	}
+
+	if upgradeRolloutFlags.enabled() && (upgradeDryRun || schedule != nil) {
+		return fmt.Errorf("upgrade in batches can't be scheduled or run in dry run mode")
+	}
+
 	return WithClient(func(ctx context.Context, c *client.Client) error {
-		var remotePeer peer.Peer
-
-		// TODO: See if we can validate version and prevent starting upgrades to
-		// an unknown version
-		resp, err := c.Upgrade(ctx, upgradeImage, preserve, stage, grpc.Peer(&remotePeer))
-		if err != nil {
-			if resp == nil {
-				return fmt.Errorf("error performing upgrade: %s", err)
+		return upgradeRolloutFlags.run(ctx, c, true, func(ctx context.Context) error {
+			var remotePeer peer.Peer
+
+			// TODO: See if we can validate version and prevent starting upgrades to
+			// an unknown version
+			resp, err := c.UpgradeGeneric(ctx, &machineapi.UpgradeRequest{
+				Image:    upgradeImage,
+				Preserve: preserve,
+				Stage:    stage,
+				Force:    upgradeForce,
+				Schedule: schedule,
+				DryRun:   upgradeDryRun,
+			}, grpc.Peer(&remotePeer))
+			if err != nil {
+				if resp == nil || upgradeRolloutFlags.enabled() {
+					return fmt.Errorf("error performing upgrade: %s", err)
+				}
+
+				cli.Warning("%s", err)
 			}
 
-			cli.Warning("%s", err)
-		}
+			defaultNode := client.AddrFromPeer(&remotePeer)
 
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "NODE\tACK\tSTARTED")
+			if upgradeDryRun {
+				for _, msg := range resp.Messages {
+					node := defaultNode
 
-		defaultNode := client.AddrFromPeer(&remotePeer)
+					if msg.Metadata != nil {
+						node = msg.Metadata.Hostname
+					}
 
-		for _, msg := range resp.Messages {
-			node := defaultNode
+					printDryRun(os.Stdout, node, msg.DryRun, false)
+				}
 
-			if msg.Metadata != nil {
-				node = msg.Metadata.Hostname
+				return nil
 			}
 
-			fmt.Fprintf(w, "%s\t%s\t%s\t\n", node, msg.Ack, time.Now())
-		}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "NODE\tACK\tSTARTED")
+
+			for _, msg := range resp.Messages {
+				node := defaultNode
+
+				if msg.Metadata != nil {
+					node = msg.Metadata.Hostname
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\t\n", node, msg.Ack, time.Now())
+			}
 
-		return w.Flush()
+			return w.Flush()
+		})
 	})
 }
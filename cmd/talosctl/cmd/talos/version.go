@@ -10,8 +10,12 @@ import (
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/output"
 	"github.com/talos-systems/talos/pkg/cli"
 	"github.com/talos-systems/talos/pkg/machinery/client"
 	"github.com/talos-systems/talos/pkg/version"
@@ -29,6 +33,10 @@ var versionCmd = &cobra.Command{
 	Long:  ``,
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if OutputFormat.Structured() {
+			return versionRenderStructured()
+		}
+
 		fmt.Println("Client:")
 		if shortVersion {
 			version.PrintShortVersion()
@@ -46,10 +54,15 @@ var versionCmd = &cobra.Command{
 		return WithClient(func(ctx context.Context, c *client.Client) error {
 			var remotePeer peer.Peer
 
-			resp, err := c.Version(ctx, grpc.Peer(&remotePeer))
+			resp, err := c.SystemInfo(ctx, grpc.Peer(&remotePeer))
+			if status.Code(err) == codes.Unimplemented {
+				// fall back to the Version API for the nodes which don't support SystemInfo
+				return printServerVersion(ctx, c)
+			}
+
 			if err != nil {
 				if resp == nil {
-					return fmt.Errorf("error getting version: %s", err)
+					return fmt.Errorf("error getting system info: %s", err)
 				}
 				cli.Warning("%s", err)
 			}
@@ -65,7 +78,11 @@ var versionCmd = &cobra.Command{
 
 				fmt.Printf("\t%s:        %s\n", "NODE", node)
 
-				version.PrintLongVersionFromExisting(msg.Version)
+				if shortVersion {
+					version.PrintLongVersionFromExisting(msg.Version)
+				} else {
+					version.PrintSystemInfo(msg)
+				}
 			}
 
 			return nil
@@ -73,9 +90,100 @@ var versionCmd = &cobra.Command{
 	},
 }
 
+func printServerVersion(ctx context.Context, c *client.Client) error {
+	var remotePeer peer.Peer
+
+	resp, err := c.Version(ctx, grpc.Peer(&remotePeer))
+	if err != nil {
+		if resp == nil {
+			return fmt.Errorf("error getting version: %s", err)
+		}
+		cli.Warning("%s", err)
+	}
+
+	defaultNode := client.AddrFromPeer(&remotePeer)
+
+	for _, msg := range resp.Messages {
+		node := defaultNode
+
+		if msg.Metadata != nil {
+			node = msg.Metadata.Hostname
+		}
+
+		fmt.Printf("\t%s:        %s\n", "NODE", node)
+
+		version.PrintLongVersionFromExisting(msg.Version)
+	}
+
+	return nil
+}
+
+// versionRenderStructured writes the client version and the versions of the nodes as a single object.
+func versionRenderStructured() error {
+	clientVersion, err := output.Object("", version.NewVersion())
+	if err != nil {
+		return err
+	}
+
+	obj := map[string]interface{}{
+		"client": clientVersion,
+	}
+
+	if !clientOnly {
+		if err = WithClient(func(ctx context.Context, c *client.Client) error {
+			var remotePeer peer.Peer
+
+			resp, e := serverVersionResponse(ctx, c, &remotePeer)
+			if e != nil {
+				return e
+			}
+
+			obj["servers"], e = output.ResponseObjects(client.AddrFromPeer(&remotePeer), resp)
+
+			return e
+		}); err != nil {
+			return err
+		}
+	}
+
+	w, err := newOutputWriter()
+	if err != nil {
+		return err
+	}
+
+	return w.Write(obj)
+}
+
+// serverVersionResponse returns SystemInfo response, or Version response for the nodes which don't support SystemInfo.
+func serverVersionResponse(ctx context.Context, c *client.Client, remotePeer *peer.Peer) (proto.Message, error) {
+	systemInfo, err := c.SystemInfo(ctx, grpc.Peer(remotePeer))
+	if status.Code(err) != codes.Unimplemented {
+		if err != nil {
+			if systemInfo == nil {
+				return nil, fmt.Errorf("error getting system info: %s", err)
+			}
+
+			cli.Warning("%s", err)
+		}
+
+		return systemInfo, nil
+	}
+
+	resp, err := c.Version(ctx, grpc.Peer(remotePeer))
+	if err != nil {
+		if resp == nil {
+			return nil, fmt.Errorf("error getting version: %s", err)
+		}
+
+		cli.Warning("%s", err)
+	}
+
+	return resp, nil
+}
+
 func init() {
 	versionCmd.Flags().BoolVar(&shortVersion, "short", false, "Print the short version")
 	versionCmd.Flags().BoolVar(&clientOnly, "client", false, "Print client version only")
 
-	addCommand(versionCmd)
+	addCommand(withStructuredOutput(versionCmd))
 }
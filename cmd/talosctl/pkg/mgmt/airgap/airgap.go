@@ -0,0 +1,228 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package airgap implements assembling of the air-gapped installation bundle for `talosctl gen bundle`.
+package airgap
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	bootkubeimages "github.com/talos-systems/talos/internal/app/bootkube/images"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+)
+
+// Bundle entry names.
+const (
+	ChecksumsName = "SHA256SUMS"
+	ImageListName = "images.txt"
+	ImagesDir     = "images/"
+)
+
+// Images returns sorted list of images required to install and run the cluster.
+//
+// Empty image references (e.g. install image is not set) are skipped.
+func Images(cfg *v1alpha1.Config) []string {
+	list := bootkubeimages.List(cfg)
+
+	imageList := make([]string, 0, 11)
+
+	for _, image := range []string{
+		list.Flannel,
+		list.FlannelCNI,
+		list.CoreDNS,
+		list.Etcd,
+		list.KubeAPIServer,
+		list.KubeControllerManager,
+		list.KubeScheduler,
+		list.KubeProxy,
+		list.Kubelet,
+		list.PodCheckpointer,
+		cfg.Machine().Install().Image(),
+	} {
+		if image != "" {
+			imageList = append(imageList, image)
+		}
+	}
+
+	sort.Strings(imageList)
+
+	return imageList
+}
+
+var imageArchiveNameRe = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// ImageArchiveName returns the name of the bundle entry for the image archive.
+func ImageArchiveName(image string) string {
+	return ImagesDir + imageArchiveNameRe.ReplaceAllString(image, "_") + ".tar"
+}
+
+// PinnedImage returns the image reference pinned to the digest from the list of repository digests.
+//
+// If there are no repository digests, image reference is returned as is.
+func PinnedImage(image string, repoDigests []string) string {
+	if len(repoDigests) == 0 {
+		return image
+	}
+
+	idx := strings.Index(repoDigests[0], "@")
+	if idx == -1 {
+		return image
+	}
+
+	return image + repoDigests[0][idx:]
+}
+
+// Writer writes entries to the gzipped bundle tarball recording checksums.
+type Writer struct {
+	gzw       *gzip.Writer
+	tw        *tar.Writer
+	checksums map[string]string
+}
+
+// NewWriter initializes new Writer.
+func NewWriter(w io.Writer) *Writer {
+	gzw := gzip.NewWriter(w)
+
+	return &Writer{
+		gzw:       gzw,
+		tw:        tar.NewWriter(gzw),
+		checksums: map[string]string{},
+	}
+}
+
+// WriteBytes writes the entry to the bundle.
+func (bw *Writer) WriteBytes(name string, data []byte) error {
+	sum := sha256.Sum256(data)
+
+	bw.checksums[name] = hex.EncodeToString(sum[:])
+
+	if err := bw.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	_, err := bw.tw.Write(data)
+
+	return err
+}
+
+// WriteFile writes the contents of the file as the entry to the bundle.
+func (bw *Writer) WriteFile(name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if err = bw.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    st.Size(),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+
+	if _, err = io.Copy(io.MultiWriter(bw.tw, hash), f); err != nil {
+		return err
+	}
+
+	bw.checksums[name] = hex.EncodeToString(hash.Sum(nil))
+
+	return nil
+}
+
+// WriteConfigs writes machine configs and talosconfig to the bundle.
+//
+// talosconfig endpoints are set to localhost, with expectation user will tweak them later.
+func (bw *Writer) WriteConfigs(configBundle *v1alpha1.ConfigBundle, clusterName string) error {
+	for _, entry := range []struct {
+		name string
+		cfg  *v1alpha1.Config
+	}{
+		{"init.yaml", configBundle.InitCfg},
+		{"controlplane.yaml", configBundle.ControlPlaneCfg},
+		{"join.yaml", configBundle.JoinCfg},
+	} {
+		data, err := entry.cfg.Bytes()
+		if err != nil {
+			return err
+		}
+
+		if err = bw.WriteBytes(entry.name, data); err != nil {
+			return err
+		}
+	}
+
+	talosContext, ok := configBundle.TalosConfig().Contexts[clusterName]
+	if !ok {
+		return fmt.Errorf("context %q is missing in talosconfig", clusterName)
+	}
+
+	talosContext.Endpoints = []string{"127.0.0.1"}
+
+	talosconfig, err := yaml.Marshal(configBundle.TalosConfig())
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	return bw.WriteBytes("talosconfig", talosconfig)
+}
+
+// WriteImageList writes the list of images to the bundle.
+func (bw *Writer) WriteImageList(imageList []string) error {
+	return bw.WriteBytes(ImageListName, []byte(strings.Join(imageList, "\n")+"\n"))
+}
+
+// Close writes checksums of all the entries in sha256sum format and finalizes the bundle.
+func (bw *Writer) Close() error {
+	names := make([]string, 0, len(bw.checksums))
+
+	for name := range bw.checksums {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var sb strings.Builder
+
+	for _, name := range names {
+		fmt.Fprintf(&sb, "%s  %s\n", bw.checksums[name], name)
+	}
+
+	if err := bw.WriteBytes(ChecksumsName, []byte(sb.String())); err != nil {
+		return err
+	}
+
+	if err := bw.tw.Close(); err != nil {
+		return err
+	}
+
+	return bw.gzw.Close()
+}
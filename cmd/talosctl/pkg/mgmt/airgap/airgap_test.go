@@ -0,0 +1,344 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package airgap_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/mgmt/airgap"
+	"github.com/talos-systems/talos/pkg/machinery/client/config"
+	"github.com/talos-systems/talos/pkg/machinery/config/configloader"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/bundle"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+)
+
+func sha256sum(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// readBundle returns the entries of the bundle in the order of appearance.
+func readBundle(t *testing.T, r io.Reader) (names []string, contents map[string][]byte) {
+	gzr, err := gzip.NewReader(r)
+	require.NoError(t, err)
+
+	tr := tar.NewReader(gzr)
+	contents = map[string][]byte{}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+
+		data, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+
+		names = append(names, hdr.Name)
+		contents[hdr.Name] = data
+	}
+
+	return names, contents
+}
+
+func newConfigBundle(t *testing.T, kubeVersion string, opts ...generate.GenOption) *v1alpha1.ConfigBundle {
+	configBundle, err := bundle.NewConfigBundle(bundle.WithInputOptions(&bundle.InputOptions{
+		ClusterName: "test",
+		Endpoint:    "https://10.5.0.1:6443",
+		KubeVersion: kubeVersion,
+		GenOptions:  opts,
+	}))
+	require.NoError(t, err)
+
+	return configBundle
+}
+
+func TestPinnedImage(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		image       string
+		repoDigests []string
+		expected    string
+	}{
+		{
+			name:     "no digests",
+			image:    "k8s.gcr.io/etcd:3.4.14",
+			expected: "k8s.gcr.io/etcd:3.4.14",
+		},
+		{
+			name:        "digest",
+			image:       "k8s.gcr.io/etcd:3.4.14",
+			repoDigests: []string{"k8s.gcr.io/etcd@sha256:abcd", "mirror.local/etcd@sha256:ef01"},
+			expected:    "k8s.gcr.io/etcd:3.4.14@sha256:abcd",
+		},
+		{
+			name:        "malformed digest",
+			image:       "k8s.gcr.io/etcd:3.4.14",
+			repoDigests: []string{"sha256:abcd"},
+			expected:    "k8s.gcr.io/etcd:3.4.14",
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, airgap.PinnedImage(tt.image, tt.repoDigests))
+		})
+	}
+}
+
+func TestImageArchiveName(t *testing.T) {
+	for _, tt := range []struct {
+		image    string
+		expected string
+	}{
+		{
+			image:    "k8s.gcr.io/etcd:3.4.14",
+			expected: "images/k8s.gcr.io_etcd_3.4.14.tar",
+		},
+		{
+			image:    "ghcr.io/talos-systems/installer:v0.8.0",
+			expected: "images/ghcr.io_talos-systems_installer_v0.8.0.tar",
+		},
+		{
+			image:    "registry.local:5000/coredns@sha256:abcd",
+			expected: "images/registry.local_5000_coredns_sha256_abcd.tar",
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.image, func(t *testing.T) {
+			assert.Equal(t, tt.expected, airgap.ImageArchiveName(tt.image))
+		})
+	}
+}
+
+func TestImages(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		kubeVersion string
+		opts        []generate.GenOption
+		expectedLen int
+		contains    []string
+	}{
+		{
+			name:        "no install image",
+			expectedLen: 10,
+		},
+		{
+			name:        "install image",
+			opts:        []generate.GenOption{generate.WithInstallImage("registry.local/installer:v0.8.0")},
+			expectedLen: 11,
+			contains:    []string{"registry.local/installer:v0.8.0"},
+		},
+		{
+			name:        "kubernetes version",
+			kubeVersion: "1.19.4",
+			opts:        []generate.GenOption{generate.WithInstallImage("registry.local/installer:v0.8.0")},
+			expectedLen: 11,
+			contains: []string{
+				"registry.local/installer:v0.8.0",
+				fmt.Sprintf("k8s.gcr.io/kube-apiserver-%s:v1.19.4", runtime.GOARCH),
+				"ghcr.io/talos-systems/kubelet:v1.19.4",
+			},
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			configBundle := newConfigBundle(t, tt.kubeVersion, tt.opts...)
+
+			imageList := airgap.Images(configBundle.InitCfg)
+
+			assert.Len(t, imageList, tt.expectedLen)
+			assert.True(t, sort.StringsAreSorted(imageList))
+			assert.NotContains(t, imageList, "")
+
+			for _, image := range tt.contains {
+				assert.Contains(t, imageList, image)
+			}
+
+			assert.Contains(t, imageList, configBundle.InitCfg.Cluster().Etcd().Image())
+			assert.Contains(t, imageList, configBundle.InitCfg.Cluster().APIServer().Image())
+			assert.Contains(t, imageList, configBundle.InitCfg.Machine().Kubelet().Image())
+		})
+	}
+}
+
+func TestWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	archive := []byte("image archive")
+	archivePath := filepath.Join(dir, "image.tar")
+
+	require.NoError(t, ioutil.WriteFile(archivePath, archive, 0o644))
+
+	imageList := []string{"k8s.gcr.io/etcd:3.4.14@sha256:abcd", "k8s.gcr.io/pause:3.2"}
+
+	for _, tt := range []struct {
+		name     string
+		write    func(*airgap.Writer) error
+		expected map[string][]byte
+	}{
+		{
+			name: "empty",
+			write: func(*airgap.Writer) error {
+				return nil
+			},
+			expected: map[string][]byte{},
+		},
+		{
+			name: "image list",
+			write: func(bw *airgap.Writer) error {
+				return bw.WriteImageList(imageList)
+			},
+			expected: map[string][]byte{
+				"images.txt": []byte("k8s.gcr.io/etcd:3.4.14@sha256:abcd\nk8s.gcr.io/pause:3.2\n"),
+			},
+		},
+		{
+			name: "image archive",
+			write: func(bw *airgap.Writer) error {
+				if err := bw.WriteFile(airgap.ImageArchiveName("k8s.gcr.io/etcd:3.4.14"), archivePath); err != nil {
+					return err
+				}
+
+				return bw.WriteImageList(imageList[:1])
+			},
+			expected: map[string][]byte{
+				"images/k8s.gcr.io_etcd_3.4.14.tar": archive,
+				"images.txt":                        []byte("k8s.gcr.io/etcd:3.4.14@sha256:abcd\n"),
+			},
+		},
+		{
+			name: "missing file",
+			write: func(bw *airgap.Writer) error {
+				return bw.WriteFile("images/missing.tar", filepath.Join(dir, "missing.tar"))
+			},
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			bw := airgap.NewWriter(&buf)
+
+			err := tt.write(bw)
+			if tt.expected == nil {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			require.NoError(t, bw.Close())
+
+			names, contents := readBundle(t, &buf)
+
+			// checksums are always the last entry
+			require.NotEmpty(t, names)
+			assert.Equal(t, airgap.ChecksumsName, names[len(names)-1])
+
+			// checksums are sorted by the entry name
+			entries := append([]string(nil), names[:len(names)-1]...)
+			sort.Strings(entries)
+
+			var expectedSums strings.Builder
+
+			for _, name := range entries {
+				assert.Equal(t, tt.expected[name], contents[name], name)
+
+				expectedSums.WriteString(fmt.Sprintf("%s  %s\n", sha256sum(tt.expected[name]), name))
+			}
+
+			assert.Len(t, names, len(tt.expected)+1)
+			assert.Equal(t, expectedSums.String(), string(contents[airgap.ChecksumsName]))
+		})
+	}
+}
+
+func TestWriteConfigs(t *testing.T) {
+	configBundle := newConfigBundle(t, "")
+
+	for _, tt := range []struct {
+		name        string
+		clusterName string
+		expectedErr string
+	}{
+		{
+			name:        "cluster context",
+			clusterName: "test",
+		},
+		{
+			name:        "missing context",
+			clusterName: "other",
+			expectedErr: `context "other" is missing in talosconfig`,
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			bw := airgap.NewWriter(&buf)
+
+			err := bw.WriteConfigs(configBundle, tt.clusterName)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, bw.Close())
+
+			names, contents := readBundle(t, &buf)
+
+			assert.Equal(t, []string{"init.yaml", "controlplane.yaml", "join.yaml", "talosconfig", airgap.ChecksumsName}, names)
+
+			for name, machineType := range map[string]string{
+				"init.yaml":         "init",
+				"controlplane.yaml": "controlplane",
+				"join.yaml":         "join",
+			} {
+				cfg, err := configloader.NewFromBytes(contents[name])
+				require.NoError(t, err, name)
+
+				assert.Equal(t, machineType, cfg.Machine().Type().String(), name)
+			}
+
+			var talosconfig config.Config
+
+			require.NoError(t, yaml.Unmarshal(contents["talosconfig"], &talosconfig))
+			require.Contains(t, talosconfig.Contexts, tt.clusterName)
+			assert.Equal(t, []string{"127.0.0.1"}, talosconfig.Contexts[tt.clusterName].Endpoints)
+		})
+	}
+}
@@ -0,0 +1,194 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package clusterspec implements declarative cluster spec for `talosctl cluster` commands.
+package clusterspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Spec describes the cluster to be provisioned.
+//
+// Each field of the spec maps to the `talosctl cluster` command flag,
+// fields which are not set keep the flag defaults.
+type Spec struct {
+	Name              string `yaml:"name"`
+	Provisioner       string `yaml:"provisioner"`
+	KubernetesVersion string `yaml:"kubernetesVersion"`
+	Image             string `yaml:"image"`
+	InstallImage      string `yaml:"installImage"`
+
+	Network       Network       `yaml:"network"`
+	Nodes         Nodes         `yaml:"nodes"`
+	ConfigPatches ConfigPatches `yaml:"configPatches"`
+}
+
+// Network describes the cluster network.
+type Network struct {
+	CIDR        string   `yaml:"cidr"`
+	MTU         int      `yaml:"mtu"`
+	Nameservers []string `yaml:"nameservers"`
+	Existing    string   `yaml:"existing"`
+	SkipDHCP    bool     `yaml:"skipDHCP"`
+}
+
+// Nodes describes the cluster nodes.
+//
+// Resources are the same for each node.
+type Nodes struct {
+	Masters   *int     `yaml:"masters"`
+	Workers   *int     `yaml:"workers"`
+	CPUs      string   `yaml:"cpus"`
+	Memory    int      `yaml:"memory"`
+	Disk      int      `yaml:"disk"`
+	UserDisks []string `yaml:"userDisks"`
+	IPs       []string `yaml:"ips"`
+	MACs      []string `yaml:"macs"`
+}
+
+// ConfigPatches describes JSON 6902 patches applied to the generated machine configs.
+type ConfigPatches struct {
+	All          []interface{} `yaml:"all"`
+	ControlPlane []interface{} `yaml:"controlplane"`
+	Worker       []interface{} `yaml:"worker"`
+}
+
+// Flag is a command flag value set by the spec.
+type Flag struct {
+	Name   string
+	Values []string
+}
+
+// Load reads the spec from the YAML file.
+func Load(path string) (*Spec, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close() //nolint: errcheck
+
+	var spec Spec
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+
+	if err = decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("error decoding cluster spec %q: %w", path, err)
+	}
+
+	return &spec, nil
+}
+
+// Flags returns the flag values set by the spec.
+func (spec *Spec) Flags() ([]Flag, error) {
+	var flags []Flag
+
+	str := func(name, value string) {
+		if value != "" {
+			flags = append(flags, Flag{Name: name, Values: []string{value}})
+		}
+	}
+
+	integer := func(name string, value int) {
+		if value != 0 {
+			str(name, strconv.Itoa(value))
+		}
+	}
+
+	slice := func(name string, values []string) {
+		if values != nil {
+			flags = append(flags, Flag{Name: name, Values: values})
+		}
+	}
+
+	str("name", spec.Name)
+	str("provisioner", spec.Provisioner)
+	str("kubernetes-version", spec.KubernetesVersion)
+	str("image", spec.Image)
+	str("install-image", spec.InstallImage)
+
+	str("cidr", spec.Network.CIDR)
+	integer("mtu", spec.Network.MTU)
+	slice("nameservers", spec.Network.Nameservers)
+	str("existing-network", spec.Network.Existing)
+
+	if spec.Network.SkipDHCP {
+		str("skip-dhcp", "true")
+	}
+
+	if spec.Nodes.Masters != nil {
+		str("masters", strconv.Itoa(*spec.Nodes.Masters))
+	}
+
+	if spec.Nodes.Workers != nil {
+		str("workers", strconv.Itoa(*spec.Nodes.Workers))
+	}
+
+	str("cpus", spec.Nodes.CPUs)
+	integer("memory", spec.Nodes.Memory)
+	integer("disk", spec.Nodes.Disk)
+	slice("user-disk", spec.Nodes.UserDisks)
+	slice("node-ips", spec.Nodes.IPs)
+	slice("node-macs", spec.Nodes.MACs)
+
+	for _, patch := range []struct {
+		name string
+		ops  []interface{}
+	}{
+		{"config-patch", spec.ConfigPatches.All},
+		{"config-patch-control-plane", spec.ConfigPatches.ControlPlane},
+		{"config-patch-worker", spec.ConfigPatches.Worker},
+	} {
+		if len(patch.ops) == 0 {
+			continue
+		}
+
+		b, err := json.Marshal(patch.ops)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding %s: %w", patch.name, err)
+		}
+
+		str(patch.name, string(b))
+	}
+
+	return flags, nil
+}
+
+// Apply sets the command flags from the spec.
+//
+// Flags set explicitly on the command line take precedence over the spec,
+// flags which are not defined for the command are skipped.
+func (spec *Spec) Apply(cmd *cobra.Command) error {
+	flags, err := spec.Flags()
+	if err != nil {
+		return err
+	}
+
+	for _, f := range flags {
+		flag := cmd.Flags().Lookup(f.Name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+
+		if sliceValue, ok := flag.Value.(interface{ Replace([]string) error }); ok {
+			err = sliceValue.Replace(f.Values)
+		} else {
+			err = flag.Value.Set(f.Values[0])
+		}
+
+		if err != nil {
+			return fmt.Errorf("error setting %q from cluster spec: %w", f.Name, err)
+		}
+	}
+
+	return nil
+}
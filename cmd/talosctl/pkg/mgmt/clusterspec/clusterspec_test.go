@@ -0,0 +1,99 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package clusterspec_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/mgmt/clusterspec"
+)
+
+const specYAML = `name: ci
+provisioner: qemu
+network:
+  cidr: 172.20.0.0/24
+  nameservers:
+    - 172.20.0.1
+nodes:
+  masters: 3
+  workers: 0
+  memory: 4096
+configPatches:
+  worker:
+    - op: add
+      path: /machine/kubelet/extraArgs
+      value:
+        node-labels: role=worker
+`
+
+func loadSpec(t *testing.T, contents string) (*clusterspec.Spec, error) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	path := filepath.Join(dir, "cluster.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0o644))
+
+	return clusterspec.Load(path)
+}
+
+func TestFlags(t *testing.T) {
+	spec, err := loadSpec(t, specYAML)
+	require.NoError(t, err)
+
+	flags, err := spec.Flags()
+	require.NoError(t, err)
+
+	assert.Equal(t, []clusterspec.Flag{
+		{Name: "name", Values: []string{"ci"}},
+		{Name: "provisioner", Values: []string{"qemu"}},
+		{Name: "cidr", Values: []string{"172.20.0.0/24"}},
+		{Name: "nameservers", Values: []string{"172.20.0.1"}},
+		{Name: "masters", Values: []string{"3"}},
+		{Name: "workers", Values: []string{"0"}},
+		{Name: "memory", Values: []string{"4096"}},
+		{Name: "config-patch-worker", Values: []string{`[{"op":"add","path":"/machine/kubelet/extraArgs","value":{"node-labels":"role=worker"}}]`}},
+	}, flags)
+}
+
+func TestLoadUnknownField(t *testing.T) {
+	_, err := loadSpec(t, "nodes:\n  count: 3\n")
+	assert.Error(t, err)
+}
+
+func TestApply(t *testing.T) {
+	spec, err := loadSpec(t, specYAML)
+	require.NoError(t, err)
+
+	var (
+		name        string
+		masters     int
+		workers     int
+		nameservers []string
+	)
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringVar(&name, "name", "talos-default", "")
+	cmd.Flags().IntVar(&masters, "masters", 1, "")
+	cmd.Flags().IntVar(&workers, "workers", 1, "")
+	cmd.Flags().StringSliceVar(&nameservers, "nameservers", []string{"8.8.8.8", "1.1.1.1"}, "")
+
+	require.NoError(t, cmd.Flags().Parse([]string{"--name", "override"}))
+
+	require.NoError(t, spec.Apply(cmd))
+
+	assert.Equal(t, "override", name)
+	assert.Equal(t, 3, masters)
+	assert.Equal(t, 0, workers)
+	assert.Equal(t, []string{"172.20.0.1"}, nameservers)
+}
@@ -0,0 +1,225 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package imager builds Talos disk images for cloud and metal platforms with the installer container.
+package imager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	"github.com/talos-systems/talos/pkg/archiver"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// Platforms is a list of platforms supported by the installer image command.
+var Platforms = []string{"aws", "azure", "digital-ocean", "gcp", "metal", "openstack", "vmware"}
+
+// imageCachePath is the path the image cache directory is mounted to in the installer container.
+const imageCachePath = "/image-cache"
+
+// Options describe the image to build.
+type Options struct {
+	// InstallerImage is the installer container image reference.
+	InstallerImage string
+
+	Platform string
+	Board    string
+
+	// ConfigSource is the value of talos.config kernel argument baked into the image.
+	ConfigSource string
+	// ExtraKernelArgs are appended to the kernel command line of the image.
+	ExtraKernelArgs []string
+	// ImageCache is the path to the directory with container image archives to bake into the image.
+	ImageCache string
+}
+
+// Validate the options.
+func (o *Options) Validate() error {
+	found := false
+
+	for _, platform := range Platforms {
+		if platform == o.Platform {
+			found = true
+
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("unsupported platform %q, expected one of %q", o.Platform, Platforms)
+	}
+
+	if o.Board != "" && o.Board != constants.BoardNone && o.Platform != "metal" {
+		return fmt.Errorf("board is supported only with metal platform")
+	}
+
+	if _, err := reference.ParseNormalizedNamed(o.InstallerImage); err != nil {
+		return fmt.Errorf("error parsing installer image reference %q: %w", o.InstallerImage, err)
+	}
+
+	return nil
+}
+
+// Args returns installer command line arguments to build the image.
+func (o *Options) Args() []string {
+	args := []string{"image", "--platform", o.Platform, "--tar-to-stdout"}
+
+	if o.Board != "" && o.Board != constants.BoardNone {
+		args = append(args, "--board", o.Board)
+	}
+
+	if o.ConfigSource != "" {
+		args = append(args, "--config", o.ConfigSource)
+	}
+
+	for _, arg := range o.ExtraKernelArgs {
+		args = append(args, "--extra-kernel-arg", arg)
+	}
+
+	if o.ImageCache != "" {
+		args = append(args, "--image-cache", imageCachePath)
+	}
+
+	return args
+}
+
+// Generate builds the image with the installer container and extracts the artifacts into the output directory.
+//
+// Installer container runs privileged with host /dev mounted, as it uses loop devices to build the image.
+func Generate(ctx context.Context, opts Options, outputDir string, logWriter io.Writer) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	hostConfig := &container.HostConfig{
+		Privileged: true,
+		Binds:      []string{"/dev:/dev"},
+	}
+
+	if opts.ImageCache != "" {
+		imageCache, err := filepath.Abs(opts.ImageCache)
+		if err != nil {
+			return err
+		}
+
+		hostConfig.Binds = append(hostConfig.Binds, imageCache+":"+imageCachePath+":ro")
+	}
+
+	fmt.Fprintf(logWriter, "building %s image with %s\n", opts.Platform, opts.InstallerImage)
+
+	return runInstaller(ctx, opts.InstallerImage, opts.Args(), hostConfig, outputDir, logWriter)
+}
+
+// runInstaller runs the installer container and extracts the artifacts it writes to stdout into the output directory.
+//
+//nolint: gocyclo
+func runInstaller(ctx context.Context, image string, args []string, hostConfig *container.HostConfig, outputDir string, logWriter io.Writer) error {
+	cli, err := client.NewEnvClient()
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer cli.Close()
+
+	if err = ensureImage(ctx, cli, image, logWriter); err != nil {
+		return err
+	}
+
+	resp, err := cli.ContainerCreate(ctx, &container.Config{
+		Image:        image,
+		Cmd:          args,
+		AttachStdout: true,
+		AttachStderr: true,
+	}, hostConfig, nil, "")
+	if err != nil {
+		return fmt.Errorf("error creating installer container: %w", err)
+	}
+
+	//nolint: errcheck
+	defer cli.ContainerRemove(context.Background(), resp.ID, types.ContainerRemoveOptions{Force: true})
+
+	attach, err := cli.ContainerAttach(ctx, resp.ID, types.ContainerAttachOptions{
+		Stream: true,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return fmt.Errorf("error attaching to installer container: %w", err)
+	}
+
+	defer attach.Close()
+
+	if err = cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		return fmt.Errorf("error starting installer container: %w", err)
+	}
+
+	// installer writes tar.gz of the artifacts to stdout, and logs to stderr
+	pr, pw := io.Pipe()
+
+	go func() {
+		_, copyErr := stdcopy.StdCopy(pw, logWriter, attach.Reader)
+		pw.CloseWithError(copyErr) //nolint: errcheck
+	}()
+
+	untarErr := archiver.UntarGz(ctx, pr, outputDir)
+
+	// drain the rest of the output, so that the installer doesn't block on the write
+	io.Copy(ioutil.Discard, pr) //nolint: errcheck
+
+	exitCode, err := cli.ContainerWait(ctx, resp.ID)
+	if err != nil {
+		return fmt.Errorf("error waiting for installer container: %w", err)
+	}
+
+	if exitCode != 0 {
+		return fmt.Errorf("installer exited with code %d", exitCode)
+	}
+
+	if untarErr != nil {
+		return fmt.Errorf("error extracting image artifacts: %w", untarErr)
+	}
+
+	return nil
+}
+
+func ensureImage(ctx context.Context, cli *client.Client, image string, logWriter io.Writer) error {
+	_, _, err := cli.ImageInspectWithRaw(ctx, image)
+	if err == nil {
+		return nil
+	}
+
+	if !client.IsErrImageNotFound(err) {
+		return err
+	}
+
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(logWriter, "downloading", ref.String())
+
+	reader, err := cli.ImagePull(ctx, ref.String(), types.ImagePullOptions{})
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer reader.Close()
+
+	_, err = io.Copy(ioutil.Discard, reader)
+
+	return err
+}
@@ -0,0 +1,120 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package imager_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/mgmt/imager"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+func TestValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		options imager.Options
+		err     string
+	}{
+		{
+			name: "aws",
+			options: imager.Options{
+				InstallerImage: "ghcr.io/talos-systems/installer:v0.8.0",
+				Platform:       "aws",
+				Board:          constants.BoardNone,
+			},
+		},
+		{
+			name: "rpi",
+			options: imager.Options{
+				InstallerImage: "ghcr.io/talos-systems/installer:v0.8.0",
+				Platform:       "metal",
+				Board:          "rpi_4",
+			},
+		},
+		{
+			name: "unknown platform",
+			options: imager.Options{
+				InstallerImage: "ghcr.io/talos-systems/installer:v0.8.0",
+				Platform:       "packet",
+			},
+			err: "unsupported platform \"packet\"",
+		},
+		{
+			name: "board on cloud",
+			options: imager.Options{
+				InstallerImage: "ghcr.io/talos-systems/installer:v0.8.0",
+				Platform:       "gcp",
+				Board:          "rpi_4",
+			},
+			err: "board is supported only with metal platform",
+		},
+		{
+			name: "invalid image",
+			options: imager.Options{
+				InstallerImage: "Installer",
+				Platform:       "gcp",
+			},
+			err: "error parsing installer image reference",
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+			}
+		})
+	}
+}
+
+func TestArgs(t *testing.T) {
+	options := imager.Options{
+		Platform: "aws",
+		Board:    constants.BoardNone,
+	}
+
+	assert.Equal(t, []string{"image", "--platform", "aws", "--tar-to-stdout"}, options.Args())
+
+	options = imager.Options{
+		Platform:        "metal",
+		Board:           "rpi_4",
+		ConfigSource:    "http://10.5.0.1/config.yaml",
+		ExtraKernelArgs: []string{"console=ttyS0", "talos.dashboard.disabled=1"},
+		ImageCache:      "_out/images",
+	}
+
+	assert.Equal(t, []string{
+		"image", "--platform", "metal", "--tar-to-stdout",
+		"--board", "rpi_4",
+		"--config", "http://10.5.0.1/config.yaml",
+		"--extra-kernel-arg", "console=ttyS0",
+		"--extra-kernel-arg", "talos.dashboard.disabled=1",
+		"--image-cache", "/image-cache",
+	}, options.Args())
+}
+
+func TestISOArgs(t *testing.T) {
+	options := imager.ISOOptions{}
+
+	assert.Equal(t, []string{"iso", "--tar-to-stdout"}, options.Args())
+
+	options = imager.ISOOptions{
+		ConfigFile:      "_out/controlplane.yaml",
+		ExtraKernelArgs: []string{"console=ttyS0"},
+	}
+
+	assert.Equal(t, []string{
+		"iso", "--tar-to-stdout",
+		"--config-file", "/config.yaml",
+		"--extra-kernel-arg", "console=ttyS0",
+	}, options.Args())
+}
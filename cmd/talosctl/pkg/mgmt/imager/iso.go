@@ -0,0 +1,78 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package imager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types/container"
+)
+
+// isoConfigPath is the path the machine config is mounted to in the installer container.
+const isoConfigPath = "/config.yaml"
+
+// ISOOptions describe the boot ISO to build.
+type ISOOptions struct {
+	// InstallerImage is the installer container image reference.
+	InstallerImage string
+
+	// ConfigFile is the path to the machine config to embed into the ISO.
+	ConfigFile string
+	// ExtraKernelArgs are appended to the kernel command line of the ISO.
+	ExtraKernelArgs []string
+}
+
+// Validate the options.
+func (o *ISOOptions) Validate() error {
+	if _, err := reference.ParseNormalizedNamed(o.InstallerImage); err != nil {
+		return fmt.Errorf("error parsing installer image reference %q: %w", o.InstallerImage, err)
+	}
+
+	return nil
+}
+
+// Args returns installer command line arguments to build the ISO.
+func (o *ISOOptions) Args() []string {
+	args := []string{"iso", "--tar-to-stdout"}
+
+	if o.ConfigFile != "" {
+		args = append(args, "--config-file", isoConfigPath)
+	}
+
+	for _, arg := range o.ExtraKernelArgs {
+		args = append(args, "--extra-kernel-arg", arg)
+	}
+
+	return args
+}
+
+// GenerateISO builds the boot ISO with the installer container and extracts it into the output directory.
+//
+// If the machine config is embedded, the ISO boots Talos with the config read from the ISO itself,
+// so that machines can be installed without network boot or config server.
+func GenerateISO(ctx context.Context, opts ISOOptions, outputDir string, logWriter io.Writer) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	hostConfig := &container.HostConfig{}
+
+	if opts.ConfigFile != "" {
+		configFile, err := filepath.Abs(opts.ConfigFile)
+		if err != nil {
+			return err
+		}
+
+		hostConfig.Binds = append(hostConfig.Binds, configFile+":"+isoConfigPath+":ro")
+	}
+
+	fmt.Fprintf(logWriter, "building ISO with %s\n", opts.InstallerImage)
+
+	return runInstaller(ctx, opts.InstallerImage, opts.Args(), hostConfig, outputDir, logWriter)
+}
@@ -0,0 +1,330 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package pxe implements iPXE boot server for bare-metal Talos nodes.
+//
+// Server serves Talos kernel, initramfs, iPXE scripts and per-MAC machine configs
+// over HTTP, and iPXE binaries over TFTP to chainload iPXE from the legacy PXE firmware.
+package pxe
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pin/tftp"
+	"github.com/talos-systems/go-procfs/procfs"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/talos-systems/talos/pkg/machinery/kernel"
+)
+
+// Well-known HTTP paths.
+const (
+	BootScriptPath = "/boot.ipxe"
+	NodeScriptPath = "/ipxe/"
+	ConfigPath     = "/config/"
+	KernelPath     = "/vmlinuz"
+	InitramfsPath  = "/initramfs.xz"
+)
+
+// DefaultConfigName is the name of the machine config served to the nodes without per-MAC config.
+const DefaultConfigName = "default.yaml"
+
+// Server is iPXE boot server.
+type Server struct {
+	// Addr is the IP address to listen on, it is also used in the URLs passed to the nodes.
+	Addr net.IP
+	// HTTPPort is the port for HTTP server.
+	HTTPPort int
+	// TFTPPort is the port for TFTP server, TFTP server is disabled if zero.
+	TFTPPort int
+
+	// KernelPath and InitramfsPath are paths to Talos boot assets.
+	KernelPath    string
+	InitramfsPath string
+	// ExtraKernelArgs are appended to the default kernel command line.
+	ExtraKernelArgs []string
+
+	// ConfigDir contains machine configs named after node MAC address (<mac>.yaml).
+	ConfigDir string
+	// TFTPRoot contains iPXE binaries (e.g. undionly.kpxe, ipxe.efi) served over TFTP.
+	TFTPRoot string
+}
+
+var bootScriptTemplate = template.Must(template.New("boot").Parse(`#!ipxe
+chain http://{{ .Host }}{{ .NodeScriptPath }}${mac:hexhyp}
+`))
+
+var nodeScriptTemplate = template.Must(template.New("node").Parse(`#!ipxe
+kernel http://{{ .Host }}{{ .KernelPath }} {{ .Cmdline }}
+initrd http://{{ .Host }}{{ .InitramfsPath }}
+boot
+`))
+
+func (s *Server) host() string {
+	return net.JoinHostPort(s.Addr.String(), strconv.Itoa(s.HTTPPort))
+}
+
+// BootScript returns iPXE script which chainloads node-specific script from the HTTP server.
+func (s *Server) BootScript() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := bootScriptTemplate.Execute(&buf, struct {
+		Host           string
+		NodeScriptPath string
+	}{
+		Host:           s.host(),
+		NodeScriptPath: NodeScriptPath,
+	}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// NodeScript returns iPXE script which boots Talos on the node with the specified MAC address.
+func (s *Server) NodeScript(mac net.HardwareAddr) ([]byte, error) {
+	cmdline := procfs.NewCmdline("")
+
+	cmdline.SetAll(kernel.DefaultArgs)
+
+	cmdline.Append("talos.platform", "metal")
+	cmdline.Append("talos.config", fmt.Sprintf("http://%s%s%s", s.host(), ConfigPath, formatMAC(mac)))
+
+	if err := cmdline.AppendAll(s.ExtraKernelArgs); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	if err := nodeScriptTemplate.Execute(&buf, struct {
+		Host          string
+		KernelPath    string
+		InitramfsPath string
+		Cmdline       string
+	}{
+		Host:          s.host(),
+		KernelPath:    KernelPath,
+		InitramfsPath: InitramfsPath,
+		Cmdline:       cmdline.String(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Config returns path to the machine config for the node with the specified MAC address.
+//
+// Config files are looked up in the config directory on each request, so that configs can be added
+// while the server is running. MAC addresses in file names might be in any format accepted by net.ParseMAC.
+// If there's no config for the MAC address, DefaultConfigName is used.
+func (s *Server) Config(mac net.HardwareAddr) (string, error) {
+	if s.ConfigDir == "" {
+		return "", os.ErrNotExist
+	}
+
+	files, err := ioutil.ReadDir(s.ConfigDir)
+	if err != nil {
+		return "", err
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".yaml" {
+			continue
+		}
+
+		fileMAC, parseErr := net.ParseMAC(strings.TrimSuffix(file.Name(), ".yaml"))
+		if parseErr != nil {
+			continue
+		}
+
+		if bytes.Equal(fileMAC, mac) {
+			return filepath.Join(s.ConfigDir, file.Name()), nil
+		}
+	}
+
+	path := filepath.Join(s.ConfigDir, DefaultConfigName)
+
+	if _, err = os.Stat(path); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// Handler returns HTTP handler of the server.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(BootScriptPath, func(w http.ResponseWriter, req *http.Request) {
+		script, err := s.BootScript()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Write(script) //nolint: errcheck
+	})
+
+	mux.HandleFunc(NodeScriptPath, func(w http.ResponseWriter, req *http.Request) {
+		mac, err := net.ParseMAC(strings.TrimPrefix(req.URL.Path, NodeScriptPath))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		script, err := s.NodeScript(mac)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		log.Printf("booting node %s", mac)
+
+		w.Write(script) //nolint: errcheck
+	})
+
+	mux.HandleFunc(ConfigPath, func(w http.ResponseWriter, req *http.Request) {
+		mac, err := net.ParseMAC(strings.TrimPrefix(req.URL.Path, ConfigPath))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		path, err := s.Config(mac)
+		if err != nil {
+			log.Printf("no config for node %s: %s", mac, err)
+
+			http.NotFound(w, req)
+
+			return
+		}
+
+		log.Printf("serving config %q to node %s", path, mac)
+
+		http.ServeFile(w, req, path)
+	})
+
+	mux.HandleFunc(KernelPath, func(w http.ResponseWriter, req *http.Request) {
+		http.ServeFile(w, req, s.KernelPath)
+	})
+
+	mux.HandleFunc(InitramfsPath, func(w http.ResponseWriter, req *http.Request) {
+		http.ServeFile(w, req, s.InitramfsPath)
+	})
+
+	return mux
+}
+
+// tftpReadHandler serves iPXE binaries from the TFTP root and the boot script.
+func (s *Server) tftpReadHandler(filename string, rf io.ReaderFrom) error {
+	filename = strings.TrimPrefix(filepath.Clean("/"+filename), "/")
+
+	if filename == strings.TrimPrefix(BootScriptPath, "/") {
+		script, err := s.BootScript()
+		if err != nil {
+			return err
+		}
+
+		_, err = rf.ReadFrom(bytes.NewReader(script))
+
+		return err
+	}
+
+	if s.TFTPRoot == "" {
+		return os.ErrNotExist
+	}
+
+	f, err := os.Open(filepath.Join(s.TFTPRoot, filename))
+	if err != nil {
+		log.Printf("TFTP: failed to serve %q: %s", filename, err)
+
+		return err
+	}
+
+	defer f.Close() //nolint: errcheck
+
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if transfer, ok := rf.(tftp.OutgoingTransfer); ok {
+		transfer.SetSize(st.Size())
+	}
+
+	log.Printf("TFTP: serving %q", filename)
+
+	_, err = rf.ReadFrom(f)
+
+	return err
+}
+
+// Serve runs the HTTP and TFTP servers until the context is canceled.
+func (s *Server) Serve(ctx context.Context) error {
+	eg, ctx := errgroup.WithContext(ctx)
+
+	httpServer := &http.Server{
+		Addr:    s.host(),
+		Handler: s.Handler(),
+	}
+
+	eg.Go(func() error {
+		log.Printf("serving HTTP on %s, iPXE boot script URL is http://%s%s", httpServer.Addr, s.host(), BootScriptPath)
+
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return err
+		}
+
+		return nil
+	})
+
+	eg.Go(func() error {
+		<-ctx.Done()
+
+		return httpServer.Shutdown(context.Background())
+	})
+
+	if s.TFTPPort != 0 {
+		tftpServer := tftp.NewServer(s.tftpReadHandler, nil)
+		tftpAddr := net.JoinHostPort(s.Addr.String(), strconv.Itoa(s.TFTPPort))
+
+		eg.Go(func() error {
+			log.Printf("serving TFTP on %s", tftpAddr)
+
+			return tftpServer.ListenAndServe(tftpAddr)
+		})
+
+		eg.Go(func() error {
+			<-ctx.Done()
+
+			tftpServer.Shutdown()
+
+			return nil
+		})
+	}
+
+	return eg.Wait()
+}
+
+// formatMAC formats MAC address in the same way as iPXE ${mac:hexhyp}.
+func formatMAC(mac net.HardwareAddr) string {
+	return strings.ReplaceAll(mac.String(), ":", "-")
+}
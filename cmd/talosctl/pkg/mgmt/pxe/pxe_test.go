@@ -0,0 +1,115 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package pxe_test
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/mgmt/pxe"
+)
+
+type PXESuite struct {
+	suite.Suite
+
+	dir    string
+	server *pxe.Server
+}
+
+func (suite *PXESuite) SetupTest() {
+	var err error
+
+	suite.dir, err = ioutil.TempDir("", "talos")
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(os.Mkdir(filepath.Join(suite.dir, "configs"), 0o755))
+
+	for name, contents := range map[string]string{
+		"vmlinuz":                        "kernel",
+		"initramfs.xz":                   "initramfs",
+		"configs/52-54-00-12-34-56.yaml": "controlplane",
+		"configs/52:54:00:12:34:57.yaml": "worker",
+	} {
+		suite.Require().NoError(ioutil.WriteFile(filepath.Join(suite.dir, name), []byte(contents), 0o644))
+	}
+
+	suite.server = &pxe.Server{
+		Addr:            net.ParseIP("10.5.0.1"),
+		HTTPPort:        8080,
+		KernelPath:      filepath.Join(suite.dir, "vmlinuz"),
+		InitramfsPath:   filepath.Join(suite.dir, "initramfs.xz"),
+		ExtraKernelArgs: []string{"console=ttyS0"},
+		ConfigDir:       filepath.Join(suite.dir, "configs"),
+	}
+}
+
+func (suite *PXESuite) TearDownTest() {
+	suite.Require().NoError(os.RemoveAll(suite.dir))
+}
+
+func (suite *PXESuite) get(path string) (int, string) {
+	w := httptest.NewRecorder()
+
+	suite.server.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+	return w.Code, w.Body.String()
+}
+
+func (suite *PXESuite) TestBootScript() {
+	code, body := suite.get("/boot.ipxe")
+	suite.Assert().Equal(http.StatusOK, code)
+	suite.Assert().Equal("#!ipxe\nchain http://10.5.0.1:8080/ipxe/${mac:hexhyp}\n", body)
+}
+
+func (suite *PXESuite) TestNodeScript() {
+	code, body := suite.get("/ipxe/52-54-00-12-34-56")
+	suite.Assert().Equal(http.StatusOK, code)
+	suite.Assert().Contains(body, "kernel http://10.5.0.1:8080/vmlinuz ")
+	suite.Assert().Contains(body, " talos.platform=metal talos.config=http://10.5.0.1:8080/config/52-54-00-12-34-56 console=ttyS0\n")
+	suite.Assert().Contains(body, "initrd http://10.5.0.1:8080/initramfs.xz\nboot\n")
+
+	code, _ = suite.get("/ipxe/foo")
+	suite.Assert().Equal(http.StatusBadRequest, code)
+}
+
+func (suite *PXESuite) TestConfig() {
+	code, body := suite.get("/config/52-54-00-12-34-56")
+	suite.Assert().Equal(http.StatusOK, code)
+	suite.Assert().Equal("controlplane", body)
+
+	code, body = suite.get("/config/52-54-00-12-34-57")
+	suite.Assert().Equal(http.StatusOK, code)
+	suite.Assert().Equal("worker", body)
+
+	code, _ = suite.get("/config/52-54-00-12-34-58")
+	suite.Assert().Equal(http.StatusNotFound, code)
+
+	suite.Require().NoError(ioutil.WriteFile(filepath.Join(suite.dir, "configs", pxe.DefaultConfigName), []byte("default"), 0o644))
+
+	code, body = suite.get("/config/52-54-00-12-34-58")
+	suite.Assert().Equal(http.StatusOK, code)
+	suite.Assert().Equal("default", body)
+}
+
+func (suite *PXESuite) TestAssets() {
+	code, body := suite.get("/vmlinuz")
+	suite.Assert().Equal(http.StatusOK, code)
+	suite.Assert().Equal("kernel", body)
+
+	code, body = suite.get("/initramfs.xz")
+	suite.Assert().Equal(http.StatusOK, code)
+	suite.Assert().Equal("initramfs", body)
+}
+
+func TestPXESuite(t *testing.T) {
+	suite.Run(t, new(PXESuite))
+}
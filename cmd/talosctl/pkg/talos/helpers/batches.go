@@ -0,0 +1,27 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package helpers
+
+// Batches splits the list of nodes into the batches of the specified size.
+//
+// If size is not positive, all the nodes are returned as a single batch.
+func Batches(nodes []string, size int) [][]string {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	if size <= 0 || size > len(nodes) {
+		size = len(nodes)
+	}
+
+	batches := make([][]string, 0, (len(nodes)+size-1)/size)
+
+	for len(nodes) > size {
+		batches = append(batches, nodes[:size:size])
+		nodes = nodes[size:]
+	}
+
+	return append(batches, nodes)
+}
@@ -0,0 +1,36 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package helpers_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/helpers"
+)
+
+func TestBatches(t *testing.T) {
+	nodes := []string{"10.5.0.2", "10.5.0.3", "10.5.0.4", "10.5.0.5", "10.5.0.6"}
+
+	assert.Nil(t, helpers.Batches(nil, 2))
+
+	assert.Equal(t, [][]string{nodes}, helpers.Batches(nodes, 0))
+	assert.Equal(t, [][]string{nodes}, helpers.Batches(nodes, 10))
+
+	assert.Equal(t, [][]string{
+		{"10.5.0.2", "10.5.0.3"},
+		{"10.5.0.4", "10.5.0.5"},
+		{"10.5.0.6"},
+	}, helpers.Batches(nodes, 2))
+
+	assert.Equal(t, [][]string{
+		{"10.5.0.2"},
+		{"10.5.0.3"},
+		{"10.5.0.4"},
+		{"10.5.0.5"},
+		{"10.5.0.6"},
+	}, helpers.Batches(nodes, 1))
+}
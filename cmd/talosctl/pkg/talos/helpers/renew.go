@@ -0,0 +1,43 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package helpers
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"time"
+
+	"github.com/talos-systems/talos/pkg/machinery/client/config"
+)
+
+// ClientCertificate decodes the client certificate of the talosconfig context.
+func ClientCertificate(configContext *config.Context) (*x509.Certificate, error) {
+	crtPEM, err := base64.StdEncoding.DecodeString(configContext.Crt)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(crtPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode client certificate")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// ClientCertificateNeedsRenewal checks whether less than a third of the certificate lifetime is left.
+//
+// Expired certificates can't be used to request a new one, so they are never renewed.
+func ClientCertificateNeedsRenewal(crt *x509.Certificate, now time.Time) bool {
+	if now.After(crt.NotAfter) {
+		return false
+	}
+
+	lifetime := crt.NotAfter.Sub(crt.NotBefore)
+
+	return crt.NotAfter.Sub(now) < lifetime/3
+}
@@ -0,0 +1,54 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package helpers_test
+
+import (
+	stdx509 "crypto/x509"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talos-systems/crypto/x509"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/helpers"
+	"github.com/talos-systems/talos/pkg/machinery/client/config"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+)
+
+func TestClientCertificateNeedsRenewal(t *testing.T) {
+	now := time.Now()
+
+	crt := &stdx509.Certificate{
+		NotBefore: now.Add(-60 * 24 * time.Hour),
+		NotAfter:  now.Add(30 * 24 * time.Hour),
+	}
+
+	assert.False(t, helpers.ClientCertificateNeedsRenewal(crt, now))
+	assert.True(t, helpers.ClientCertificateNeedsRenewal(crt, now.Add(5*24*time.Hour)))
+	assert.False(t, helpers.ClientCertificateNeedsRenewal(crt, now.Add(31*24*time.Hour)))
+}
+
+func TestClientCertificate(t *testing.T) {
+	ca, err := generate.NewTalosCA(time.Now())
+	require.NoError(t, err)
+
+	cert, err := generate.NewClientCertificateAndKey(time.Now(), &x509.PEMEncodedCertificateAndKey{
+		Crt: ca.CrtPEM,
+		Key: ca.KeyPEM,
+	}, []string{"os:reader"}, time.Hour)
+	require.NoError(t, err)
+
+	crt, err := helpers.ClientCertificate(&config.Context{
+		Crt: base64.StdEncoding.EncodeToString(cert.Crt),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"os:reader"}, crt.Subject.Organization)
+
+	_, err = helpers.ClientCertificate(&config.Context{Crt: "Zm9v"})
+	assert.Error(t, err)
+}
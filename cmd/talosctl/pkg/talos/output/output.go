@@ -0,0 +1,225 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package output implements structured (JSON and YAML) output of the talosctl commands.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the output format of the command.
+type Format string
+
+// Output formats.
+const (
+	// Table is the default human-readable output.
+	Table Format = "table"
+	// Wide is the table output with additional columns (if the command supports it).
+	Wide Format = "wide"
+	// JSON writes each object as a single line of JSON.
+	JSON Format = "json"
+	// YAML writes each object as a separate YAML document.
+	YAML Format = "yaml"
+)
+
+// Formats is the list of the supported output formats.
+var Formats = []Format{Table, Wide, JSON, YAML}
+
+// String implements pflag.Value.
+func (f *Format) String() string {
+	return string(*f)
+}
+
+// Set implements pflag.Value.
+func (f *Format) Set(value string) error {
+	for _, format := range Formats {
+		if string(format) == value {
+			*f = format
+
+			return nil
+		}
+	}
+
+	formats := make([]string, len(Formats))
+
+	for i := range Formats {
+		formats[i] = string(Formats[i])
+	}
+
+	return fmt.Errorf("unsupported output format %q, supported formats: %s", value, strings.Join(formats, ", "))
+}
+
+// Type implements pflag.Value.
+func (f *Format) Type() string {
+	return "format"
+}
+
+// Structured returns true if the format is machine-readable.
+func (f Format) Structured() bool {
+	return f == JSON || f == YAML
+}
+
+// Writer writes objects in the structured output format.
+type Writer struct {
+	out    io.Writer
+	format Format
+	count  int
+}
+
+// NewWriter initializes Writer.
+func NewWriter(out io.Writer, format Format) (*Writer, error) {
+	if !format.Structured() {
+		return nil, fmt.Errorf("output format %q is not structured", format)
+	}
+
+	return &Writer{
+		out:    out,
+		format: format,
+	}, nil
+}
+
+// Write marshals the object and writes it to the output.
+//
+// JSON objects are written one per line, YAML objects are written as separate documents.
+func (w *Writer) Write(obj interface{}) error {
+	defer func() { w.count++ }()
+
+	if w.format == JSON {
+		return json.NewEncoder(w.out).Encode(obj)
+	}
+
+	if w.count > 0 {
+		if _, err := io.WriteString(w.out, "---\n"); err != nil {
+			return err
+		}
+	}
+
+	// documents are encoded one by one, so that the output isn't delayed until the encoder is closed
+	enc := yaml.NewEncoder(w.out)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(obj); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// WriteResponse writes each message of the (possibly proxied) API response as a separate object.
+//
+// See ResponseObjects for the details.
+func (w *Writer) WriteResponse(defaultNode string, resp proto.Message) error {
+	objs, err := ResponseObjects(defaultNode, resp)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objs {
+		if err = w.Write(obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteMessage writes a single message with the "node" field set.
+func (w *Writer) WriteMessage(node string, msg proto.Message) error {
+	obj, err := Object(node, msg)
+	if err != nil {
+		return err
+	}
+
+	return w.Write(obj)
+}
+
+// Object converts the message to the generic object using the protobuf JSON mapping.
+//
+// The message metadata is dropped, and the "node" field is set if node is not empty.
+func Object(node string, msg proto.Message) (map[string]interface{}, error) {
+	b, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj map[string]interface{}
+
+	if err = json.Unmarshal(b, &obj); err != nil {
+		return nil, err
+	}
+
+	delete(obj, "metadata")
+
+	if node != "" {
+		obj["node"] = node
+	}
+
+	return obj, nil
+}
+
+// ResponseObjects converts each message of the (possibly proxied) API response to the generic object.
+//
+// The message metadata is replaced with the "node" field which is set to the node hostname from the metadata,
+// or to defaultNode if the response wasn't proxied.
+// If the response doesn't follow the "messages" convention, it is converted as a single object.
+func ResponseObjects(defaultNode string, resp proto.Message) ([]map[string]interface{}, error) {
+	m := resp.ProtoReflect()
+
+	field := m.Descriptor().Fields().ByName("messages")
+	if field == nil || !field.IsList() || field.Message() == nil {
+		obj, err := Object(defaultNode, resp)
+		if err != nil {
+			return nil, err
+		}
+
+		return []map[string]interface{}{obj}, nil
+	}
+
+	messages := m.Get(field).List()
+	objs := make([]map[string]interface{}, 0, messages.Len())
+
+	for i := 0; i < messages.Len(); i++ {
+		msg := messages.Get(i).Message()
+
+		node := defaultNode
+
+		if hostname := metadataHostname(msg); hostname != "" {
+			node = hostname
+		}
+
+		obj, err := Object(node, msg.Interface())
+		if err != nil {
+			return nil, err
+		}
+
+		objs = append(objs, obj)
+	}
+
+	return objs, nil
+}
+
+func metadataHostname(msg protoreflect.Message) string {
+	field := msg.Descriptor().Fields().ByName("metadata")
+	if field == nil || field.Message() == nil || !msg.Has(field) {
+		return ""
+	}
+
+	metadata := msg.Get(field).Message()
+
+	hostname := metadata.Descriptor().Fields().ByName("hostname")
+	if hostname == nil || hostname.Kind() != protoreflect.StringKind {
+		return ""
+	}
+
+	return metadata.Get(hostname).String()
+}
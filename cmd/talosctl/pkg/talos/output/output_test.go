@@ -0,0 +1,102 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/output"
+	"github.com/talos-systems/talos/pkg/machinery/api/common"
+	networkapi "github.com/talos-systems/talos/pkg/machinery/api/network"
+)
+
+func routesResponse() *networkapi.RoutesResponse {
+	return &networkapi.RoutesResponse{
+		Messages: []*networkapi.Routes{
+			{
+				Metadata: &common.Metadata{
+					Hostname: "10.5.0.2",
+				},
+				Routes: []*networkapi.Route{
+					{
+						Interface:   "eth0",
+						Destination: "0.0.0.0/0",
+						Gateway:     "10.5.0.1",
+						Metric:      1024,
+					},
+				},
+			},
+			{
+				Routes: []*networkapi.Route{
+					{
+						Interface:   "eth0",
+						Destination: "10.5.0.0/24",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestFormatSet(t *testing.T) {
+	var format output.Format
+
+	require.NoError(t, format.Set("yaml"))
+	assert.Equal(t, output.YAML, format)
+	assert.True(t, format.Structured())
+
+	require.NoError(t, format.Set("wide"))
+	assert.Equal(t, output.Wide, format)
+	assert.False(t, format.Structured())
+
+	assert.EqualError(t, format.Set("xml"), `unsupported output format "xml", supported formats: table, wide, json, yaml`)
+	assert.Equal(t, output.Wide, format)
+}
+
+func TestNewWriterTable(t *testing.T) {
+	_, err := output.NewWriter(&bytes.Buffer{}, output.Table)
+	assert.Error(t, err)
+}
+
+func TestWriteResponseJSON(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := output.NewWriter(&buf, output.JSON)
+	require.NoError(t, err)
+
+	require.NoError(t, w.WriteResponse("10.5.0.3", routesResponse()))
+
+	assert.Equal(t,
+		`{"node":"10.5.0.2","routes":[{"destination":"0.0.0.0/0","family":"AF_UNSPEC","flags":0,"gateway":"10.5.0.1","interface":"eth0","metric":1024,"protocol":"RTPROT_UNSPEC","scope":0,"source":""}]}`+"\n"+
+			`{"node":"10.5.0.3","routes":[{"destination":"10.5.0.0/24","family":"AF_UNSPEC","flags":0,"gateway":"","interface":"eth0","metric":0,"protocol":"RTPROT_UNSPEC","scope":0,"source":""}]}`+"\n",
+		buf.String())
+}
+
+func TestWriteResponseYAML(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := output.NewWriter(&buf, output.YAML)
+	require.NoError(t, err)
+
+	require.NoError(t, w.Write(map[string]string{"client": "v0.8.0"}))
+	require.NoError(t, w.WriteMessage("", &networkapi.Route{Interface: "eth0"}))
+
+	assert.Equal(t, `client: v0.8.0
+---
+destination: ""
+family: AF_UNSPEC
+flags: 0
+gateway: ""
+interface: eth0
+metric: 0
+protocol: RTPROT_UNSPEC
+scope: 0
+source: ""
+`, buf.String())
+}
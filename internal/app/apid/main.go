@@ -7,18 +7,22 @@ package main
 import (
 	"flag"
 	"log"
+	"net"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/talos-systems/grpc-proxy/proxy"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
 
 	apidbackend "github.com/talos-systems/talos/internal/app/apid/pkg/backend"
 	"github.com/talos-systems/talos/internal/app/apid/pkg/director"
 	"github.com/talos-systems/talos/internal/app/apid/pkg/provider"
 	"github.com/talos-systems/talos/pkg/grpc/factory"
+	"github.com/talos-systems/talos/pkg/grpc/middleware/authz"
 	"github.com/talos-systems/talos/pkg/grpc/proxy/backend"
 	"github.com/talos-systems/talos/pkg/machinery/config/configloader"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
@@ -65,6 +69,15 @@ func main() {
 
 	router := director.NewRouter(backendFactory.Get, localBackend)
 
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			backendFactory.CloseIdle(constants.ApidBackendIdleTimeout)
+		}
+	}()
+
 	// all existing streaming methods
 	for _, methodName := range []string{
 		"/machine.MachineService/Copy",
@@ -77,6 +90,7 @@ func main() {
 		"/machine.MachineService/Read",
 		"/os.OSService/Dmesg",
 		"/cluster.ClusterService/HealthCheck",
+		"/storage.StorageService/Watch",
 	} {
 		router.RegisterStreamedRegex("^" + regexp.QuoteMeta(methodName) + "$")
 	}
@@ -84,6 +98,30 @@ func main() {
 	// register future pattern: method should have suffix "Stream"
 	router.RegisterStreamedRegex("Stream$")
 
+	rules := config.Machine().Features().RBAC().Rules()
+	authzRules := make([]authz.Rule, len(rules))
+
+	for i, rule := range rules {
+		authzRules[i] = authz.Rule{
+			Roles:   rule.Roles(),
+			Methods: rule.Methods(),
+			Nodes:   rule.Nodes(),
+		}
+	}
+
+	policyRules := config.Machine().Features().APIAuthorizationPolicy().Rules()
+	authzPolicies := make([]authz.Policy, len(policyRules))
+
+	for i, rule := range policyRules {
+		authzPolicies[i] = authz.Policy{
+			Methods:  rule.Methods(),
+			Subjects: rule.AllowedSubjects(),
+			Roles:    rule.AllowedRoles(),
+		}
+	}
+
+	authorizer := authz.NewAuthorizer(authzRules, authzPolicies, localAddresses, log.New(log.Writer(), "", log.Flags()))
+
 	var errGroup errgroup.Group
 
 	errGroup.Go(func() error {
@@ -91,10 +129,17 @@ func main() {
 			router,
 			factory.Port(constants.ApidPort),
 			factory.WithDefaultLog(),
+			factory.WithUnaryInterceptor(authorizer.UnaryInterceptor()),
+			factory.WithStreamInterceptor(authorizer.StreamInterceptor()),
 			factory.ServerOptions(
 				grpc.Creds(
 					credentials.NewTLS(serverTLSConfig),
 				),
+				// allow keepalive pings from other apid instances
+				grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+					MinTime:             constants.ApidBackendKeepaliveTime / 2,
+					PermitWithoutStream: true,
+				}),
 				grpc.CustomCodec(proxy.Codec()),
 				grpc.UnknownServiceHandler(
 					proxy.TransparentHandler(
@@ -122,7 +167,49 @@ func main() {
 		)
 	})
 
+	if localAPIAccess := config.Machine().Features().LocalAPIAccess(); localAPIAccess.Enabled() {
+		localAuthorizer := authz.NewLocalAuthorizer(authzRules, authzPolicies, localAddresses, log.New(log.Writer(), "", log.Flags()))
+
+		errGroup.Go(func() error {
+			return factory.ListenAndServe(
+				router,
+				factory.Network("unix"),
+				factory.SocketPath(constants.LocalAPISocketPath),
+				factory.SocketPermissions(0o660, localAPIAccess.GroupID()),
+				factory.WithDefaultLog(),
+				factory.WithUnaryInterceptor(localAuthorizer.UnaryInterceptor()),
+				factory.WithStreamInterceptor(localAuthorizer.StreamInterceptor()),
+				factory.ServerOptions(
+					grpc.CustomCodec(proxy.Codec()),
+					grpc.UnknownServiceHandler(
+						proxy.TransparentHandler(
+							router.Director,
+							proxy.WithStreamedDetector(router.StreamedDetector),
+						)),
+				),
+			)
+		})
+	}
+
 	if err := errGroup.Wait(); err != nil {
 		log.Fatalf("listen: %v", err)
 	}
 }
+
+// localAddresses returns the list of IP addresses of the node.
+func localAddresses() ([]string, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(addrs))
+
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok && !ipnet.IP.IsLoopback() {
+			result = append(result, ipnet.IP.String())
+		}
+	}
+
+	return result, nil
+}
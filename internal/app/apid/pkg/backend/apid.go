@@ -8,11 +8,13 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/talos-systems/grpc-proxy/proxy"
 	"github.com/talos-systems/net"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protowire"
@@ -25,12 +27,16 @@ import (
 // APID backend performs proxying to another apid instance.
 //
 // Backend authenticates itself using given grpc credentials.
+// Single connection is shared by all the requests to the backend, requests are
+// multiplexed as HTTP/2 streams.
 type APID struct {
 	target string
 	creds  credentials.TransportCredentials
 
-	mu   sync.Mutex
-	conn *grpc.ClientConn
+	mu       sync.Mutex
+	conn     *grpc.ClientConn
+	inflight int
+	lastUsed time.Time
 }
 
 // NewAPID creates new instance of APID backend.
@@ -69,6 +75,8 @@ func (a *APID) GetConnection(ctx context.Context) (context.Context, *grpc.Client
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
+	a.lastUsed = time.Now()
+
 	if a.conn != nil {
 		return outCtx, a.conn, nil
 	}
@@ -79,11 +87,64 @@ func (a *APID) GetConnection(ctx context.Context) (context.Context, *grpc.Client
 		fmt.Sprintf("%s:%d", net.FormatAddress(a.target), constants.ApidPort),
 		grpc.WithTransportCredentials(a.creds),
 		grpc.WithCodec(proxy.Codec()), //nolint: staticcheck
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                constants.ApidBackendKeepaliveTime,
+			Timeout:             constants.ApidBackendKeepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithStreamInterceptor(a.trackStream),
 	)
 
 	return outCtx, a.conn, err
 }
 
+// trackStream keeps track of the streams in flight, so that the connection is not closed while in use.
+func (a *APID) trackStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	a.mu.Lock()
+	a.inflight++
+	a.mu.Unlock()
+
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		a.release()
+
+		return nil, err
+	}
+
+	go func() {
+		<-stream.Context().Done()
+
+		a.release()
+	}()
+
+	return stream, nil
+}
+
+func (a *APID) release() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.inflight--
+	a.lastUsed = time.Now()
+}
+
+// CloseIfIdle closes the connection if it wasn't used for the specified duration.
+//
+// Connection is re-established on the next call to GetConnection.
+func (a *APID) CloseIfIdle(timeout time.Duration) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.conn == nil || a.inflight > 0 || time.Since(a.lastUsed) < timeout {
+		return false
+	}
+
+	a.conn.Close() //nolint: errcheck
+	a.conn = nil
+
+	return true
+}
+
 // AppendInfo is called to enhance response from the backend with additional data.
 //
 // AppendInfo enhances upstream response with node metadata (target).
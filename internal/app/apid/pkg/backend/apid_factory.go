@@ -6,7 +6,10 @@ package backend
 
 import (
 	"crypto/tls"
+	"log"
+	"net"
 	"sync"
+	"time"
 
 	"github.com/talos-systems/grpc-proxy/proxy"
 	"google.golang.org/grpc/credentials"
@@ -14,7 +17,7 @@ import (
 
 // APIDFactory caches connection to apid instances by target.
 //
-// TODO: need to clean up idle connections from time to time.
+// Idle connections are closed with CloseIdle.
 type APIDFactory struct {
 	cache sync.Map
 	creds credentials.TransportCredentials
@@ -33,6 +36,11 @@ func NewAPIDFactory(config *tls.Config) *APIDFactory {
 //
 // Get performs caching of backends.
 func (factory *APIDFactory) Get(target string) (proxy.Backend, error) {
+	// use canonical form of IP addresses, so that the connection is shared
+	if ip := net.ParseIP(target); ip != nil {
+		target = ip.String()
+	}
+
 	b, ok := factory.cache.Load(target)
 	if ok {
 		return b.(proxy.Backend), nil
@@ -53,3 +61,14 @@ func (factory *APIDFactory) Get(target string) (proxy.Backend, error) {
 
 	return backend, nil
 }
+
+// CloseIdle closes connections to the backends which were not used for the specified duration.
+func (factory *APIDFactory) CloseIdle(timeout time.Duration) {
+	factory.cache.Range(func(key, value interface{}) bool {
+		if value.(*APID).CloseIfIdle(timeout) {
+			log.Printf("closed idle connection to %s", key)
+		}
+
+		return true
+	})
+}
@@ -40,6 +40,13 @@ func (suite *APIDFactorySuite) TestGet() {
 
 	_, err = suite.f.Get("127.0.0.2:50000")
 	suite.Require().Error(err)
+
+	b4, err := suite.f.Get("fd00::1")
+	suite.Require().NoError(err)
+
+	b5, err := suite.f.Get("fd00:0:0::1")
+	suite.Require().NoError(err)
+	suite.Require().Equal(b4, b5)
 }
 
 func (suite *APIDFactorySuite) TestGetConcurrent() {
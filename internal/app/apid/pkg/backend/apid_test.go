@@ -9,6 +9,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -63,6 +64,25 @@ func (suite *APIDSuite) TestGetConnection() {
 	suite.Assert().Equal([]string{"127.0.0.2"}, mdOut2.Get("proxyfrom"))
 }
 
+func (suite *APIDSuite) TestCloseIfIdle() {
+	b, err := backend.NewAPID("127.0.0.1", credentials.NewTLS(&tls.Config{}))
+	suite.Require().NoError(err)
+
+	suite.Assert().False(b.CloseIfIdle(0)) // no connection yet
+
+	_, conn1, err := b.GetConnection(context.Background())
+	suite.Require().NoError(err)
+
+	suite.Assert().False(b.CloseIfIdle(time.Hour))
+	suite.Assert().True(b.CloseIfIdle(0))
+
+	_, conn2, err := b.GetConnection(context.Background())
+	suite.Require().NoError(err)
+	suite.Assert().NotEqual(conn1, conn2) // connection is re-established
+
+	b.Close()
+}
+
 func (suite *APIDSuite) TestAppendInfoUnary() {
 	reply := &common.DataResponse{
 		Messages: []*common.Data{
@@ -43,10 +43,15 @@ func NewTLSConfig(config config.Provider, endpoints []string) (*TLSConfig, error
 		}
 	}
 
+	joinThrottle := config.Cluster().JoinThrottle()
+
 	generator, err := gen.NewRemoteGenerator(
 		config.Machine().Security().Token(),
 		endpoints,
 		constants.TrustdPort,
+		gen.WithRetryInterval(joinThrottle.RetryInterval(), joinThrottle.MaxRetryInterval()),
+		gen.WithRetryJitter(joinThrottle.RetryJitter()),
+		gen.WithRetryTimeout(joinThrottle.RetryTimeout()),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create remote certificate genertor: %w", err)
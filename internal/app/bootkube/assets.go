@@ -6,7 +6,9 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/pem"
 	"errors"
 	"fmt"
@@ -26,6 +28,7 @@ import (
 	tnet "github.com/talos-systems/net"
 
 	"github.com/talos-systems/talos/internal/app/bootkube/images"
+	"github.com/talos-systems/talos/internal/pkg/controlplane"
 	"github.com/talos-systems/talos/pkg/machinery/config"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
@@ -124,11 +127,19 @@ func generateAssets(config config.Provider) (err error) {
 		return fmt.Errorf("failed to calculate DNS service IP: %w", err)
 	}
 
+	if serviceIPs := config.Cluster().CoreDNS().ServiceIPs(); len(serviceIPs) > 0 {
+		dnsServiceIPs = make([]net.IP, 0, len(serviceIPs))
+
+		for _, serviceIP := range serviceIPs {
+			dnsServiceIPs = append(dnsServiceIPs, net.ParseIP(serviceIP))
+		}
+	}
+
 	images := images.List(config)
 
 	conf := asset.Config{
 		ClusterName:                config.Cluster().Name(),
-		APIServerExtraArgs:         config.Cluster().APIServer().ExtraArgs(),
+		APIServerExtraArgs:         apiServerExtraArgs(config),
 		ControllerManagerExtraArgs: config.Cluster().ControllerManager().ExtraArgs(),
 		ProxyMode:                  config.Cluster().Proxy().Mode(),
 		ProxyExtraArgs:             config.Cluster().Proxy().ExtraArgs(),
@@ -156,19 +167,61 @@ func generateAssets(config config.Provider) (err error) {
 		ClusterDomain:              config.Cluster().Network().DNSDomain(),
 	}
 
+	if config.Cluster().ExternalCloudProvider().Enabled() {
+		conf.CloudProvider = "external"
+	}
+
 	if err = asset.Render(constants.AssetsDirectory, conf); err != nil {
 		return err
 	}
 
+	if err = removeDisabledAssets(config); err != nil {
+		return err
+	}
+
+	if config.Cluster().APIServer().DisablePodSecurityPolicy() {
+		if err = disablePodSecurityPolicy(); err != nil {
+			return err
+		}
+	}
+
+	if config.Cluster().CoreDNS().Enabled() {
+		if err = customizeCoreDNS(config.Cluster().CoreDNS()); err != nil {
+			return fmt.Errorf("failed to customize CoreDNS manifests: %w", err)
+		}
+	}
+
+	if config.Cluster().Network().CNI().Name() == constants.DefaultCNI {
+		if err = customizeFlannel(config.Cluster().Network().CNI().Flannel()); err != nil {
+			return fmt.Errorf("failed to customize Flannel manifests: %w", err)
+		}
+	}
+
+	if err = handOverManifests(); err != nil {
+		return fmt.Errorf("failed to hand over Talos manifests: %w", err)
+	}
+
 	// If "custom" is the CNI, we expect the user to supply one or more urls that point to CNI yamls
 	if config.Cluster().Network().CNI().Name() == constants.CustomCNI {
-		if err = fetchManifests(config.Cluster().Network().CNI().URLs(), map[string]string{}); err != nil {
+		if err = fetchManifests(config.Cluster().Network().CNI().URLs(), map[string]string{}, nil, nil); err != nil {
+			return err
+		}
+	}
+
+	if len(config.Cluster().ExternalCloudProvider().ManifestURLs()) > 0 {
+		if err = fetchManifests(config.Cluster().ExternalCloudProvider().ManifestURLs(), map[string]string{}, nil, nil); err != nil {
 			return err
 		}
 	}
 
 	if len(config.Cluster().ExtraManifestURLs()) > 0 {
-		if err = fetchManifests(config.Cluster().ExtraManifestURLs(), config.Cluster().ExtraManifestHeaderMap()); err != nil {
+		var data *manifestTemplateData
+
+		if config.Cluster().ExtraManifestTemplates() {
+			data = newManifestTemplateData(config)
+		}
+
+		if err = fetchManifests(config.Cluster().ExtraManifestURLs(), config.Cluster().ExtraManifestHeaderMap(), config.Cluster().ExtraManifestChecksumMap(), data); err != nil {
 			return err
 		}
 	}
@@ -176,6 +229,22 @@ func generateAssets(config config.Provider) (err error) {
 	return nil
 }
 
+// apiServerExtraArgs returns the API server extra args with the service account settings applied.
+//
+// Extra args are rendered after the default args in the manifest, so they take precedence.
+func apiServerExtraArgs(config config.Provider) map[string]string {
+	apiServer := config.Cluster().APIServer()
+
+	if apiServer.ServiceAccountIssuer() == "" && apiServer.ServiceAccountJWKSURI() == "" && len(apiServer.ServiceAccountExtraAudiences()) == 0 {
+		return apiServer.ExtraArgs()
+	}
+
+	args := controlplane.ServiceAccountArgs(config.Cluster())
+	args.Merge(apiServer.ExtraArgs())
+
+	return args
+}
+
 func altNamesFromURLs(urls []string) *tlsutil.AltNames {
 	var an tlsutil.AltNames
 
@@ -193,8 +262,101 @@ func altNamesFromURLs(urls []string) *tlsutil.AltNames {
 	return &an
 }
 
+// removeDisabledAssets removes the rendered manifests of the components disabled in the config,
+// so that bootkube doesn't create them.
+func removeDisabledAssets(config config.Provider) error {
+	var disabled []string
+
+	if !config.Cluster().Proxy().Enabled() {
+		disabled = append(disabled,
+			asset.AssetPathProxy,
+			asset.AssetPathProxySA,
+			asset.AssetPathProxyRoleBinding,
+		)
+	}
+
+	if !config.Cluster().CoreDNS().Enabled() {
+		disabled = append(disabled,
+			asset.AssetPathCoreDNSClusterRoleBinding,
+			asset.AssetPathCoreDNSClusterRole,
+			asset.AssetPathCoreDNSConfig,
+			asset.AssetPathCoreDNSDeployment,
+			asset.AssetPathCoreDNSSA,
+			asset.AssetPathCoreDNSSvc,
+			asset.AssetPathCoreDNSv6Svc,
+		)
+	}
+
+	if config.Cluster().APIServer().DisablePodSecurityPolicy() {
+		disabled = append(disabled, asset.AssetPathPodSecurityPolicy)
+	}
+
+	for _, p := range disabled {
+		if err := os.Remove(filepath.Join(constants.AssetsDirectory, p)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeSelfHostedAssets removes the rendered manifests of the self-hosted control plane,
+// as the control plane is run as static pods managed by machined.
+func removeSelfHostedAssets() error {
+	for _, p := range []string{
+		asset.AssetPathAPIServerSecret,
+		asset.AssetPathAPIServer,
+		asset.AssetPathControllerManager,
+		asset.AssetPathControllerManagerSA,
+		asset.AssetPathControllerManagerRB,
+		asset.AssetPathControllerManagerSecret,
+		asset.AssetPathControllerManagerDisruption,
+		asset.AssetPathScheduler,
+		asset.AssetPathSchedulerDisruption,
+		asset.AssetPathCheckpointer,
+		asset.AssetPathCheckpointerSA,
+		asset.AssetPathCheckpointerRole,
+		asset.AssetPathCheckpointerRoleBinding,
+		asset.AssetPathCheckpointerClusterRole,
+		asset.AssetPathCheckpointerClusterRoleBinding,
+		asset.AssetPathBootstrapManifests,
+	} {
+		if err := os.RemoveAll(filepath.Join(constants.AssetsDirectory, p)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// disablePodSecurityPolicy removes PodSecurityPolicy from the admission plugins of the API server manifests.
+//
+// Without the default policies in place, the admission plugin would reject all the pods.
+func disablePodSecurityPolicy() error {
+	const admissionPluginsFlag = "--enable-admission-plugins="
+
+	for _, p := range []string{asset.AssetPathAPIServer, asset.AssetPathBootstrapAPIServer} {
+		manifest := filepath.Join(constants.AssetsDirectory, p)
+
+		contents, err := ioutil.ReadFile(manifest)
+		if err != nil {
+			return err
+		}
+
+		updated := strings.ReplaceAll(string(contents), admissionPluginsFlag+"PodSecurityPolicy,", admissionPluginsFlag)
+
+		if err = ioutil.WriteFile(manifest, []byte(updated), 0o600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // fetchManifests will lay down manifests in the provided urls to the bootkube assets directory.
-func fetchManifests(urls []string, headers map[string]string) error {
+//
+// The manifests are verified against the checksums (if set for the URL) and rendered as templates if the data is not nil.
+func fetchManifests(urls []string, headers, checksums map[string]string, data *manifestTemplateData) error {
 	ctx := context.Background()
 
 	var result *multierror.Error
@@ -244,11 +406,48 @@ func fetchManifests(urls []string, headers map[string]string) error {
 
 			continue
 		}
+
+		if err = processManifest(client.Dst, checksums[url], data); err != nil {
+			result = multierror.Append(result, fmt.Errorf("error processing manifest %q: %w", url, err))
+
+			continue
+		}
 	}
 
 	return result.ErrorOrNil()
 }
 
+// processManifest verifies the checksum of the downloaded manifest and renders it as a template.
+func processManifest(p, checksum string, data *manifestTemplateData) error {
+	if checksum == "" && data == nil {
+		return nil
+	}
+
+	contents, err := ioutil.ReadFile(p)
+	if err != nil {
+		return err
+	}
+
+	if checksum != "" {
+		sum := sha256.Sum256(contents)
+
+		if actual := hex.EncodeToString(sum[:]); actual != strings.ToLower(checksum) {
+			return fmt.Errorf("checksum mismatch: expected %s, got %s", checksum, actual)
+		}
+	}
+
+	if data == nil {
+		return nil
+	}
+
+	rendered, err := renderManifest(filepath.Base(p), contents, data)
+	if err != nil {
+		return fmt.Errorf("error rendering template: %w", err)
+	}
+
+	return ioutil.WriteFile(p, rendered, 0o600)
+}
+
 func splitCIDRs(cidrList string) (out []*net.IPNet, err error) {
 	for _, podCIDR := range strings.Split(cidrList, ",") {
 		_, cidr, err := net.ParseCIDR(podCIDR)
@@ -0,0 +1,77 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/talos-systems/bootkube-plugin/pkg/asset"
+
+	"github.com/talos-systems/talos/pkg/machinery/config"
+)
+
+// customizeCoreDNS applies the CoreDNS settings from the config to the rendered CoreDNS manifests.
+func customizeCoreDNS(coreDNS config.CoreDNS) error {
+	if replicas := coreDNS.Replicas(); replicas > 0 {
+		if err := patchManifest(asset.AssetPathCoreDNSDeployment, func(obj map[string]interface{}) error {
+			if obj["kind"] != "Deployment" {
+				return nil
+			}
+
+			spec, ok := obj["spec"].(map[string]interface{})
+			if !ok {
+				return errors.New("CoreDNS deployment has no spec")
+			}
+
+			spec["replicas"] = replicas
+
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	snippet := corefileSnippet(coreDNS)
+	if snippet == "" {
+		return nil
+	}
+
+	return patchManifest(asset.AssetPathCoreDNSConfig, func(obj map[string]interface{}) error {
+		if obj["kind"] != "ConfigMap" {
+			return nil
+		}
+
+		data, ok := obj["data"].(map[string]interface{})
+		if !ok {
+			return errors.New("CoreDNS config map has no data")
+		}
+
+		corefile, ok := data["Corefile"].(string)
+		if !ok {
+			return errors.New("CoreDNS config map has no Corefile")
+		}
+
+		data["Corefile"] = strings.TrimRight(corefile, "\n") + "\n" + snippet
+
+		return nil
+	})
+}
+
+// corefileSnippet renders the forward zones as the server blocks followed by the extra Corefile.
+func corefileSnippet(coreDNS config.CoreDNS) string {
+	var sb strings.Builder
+
+	for _, zone := range coreDNS.ForwardZones() {
+		fmt.Fprintf(&sb, "%s:53 {\n    errors\n    cache 30\n    forward . %s\n}\n", strings.TrimSuffix(zone.Zone(), "."), strings.Join(zone.Upstreams(), " "))
+	}
+
+	if extra := strings.TrimSpace(coreDNS.ExtraCorefile()); extra != "" {
+		sb.WriteString(extra + "\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,164 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/talos-systems/bootkube-plugin/pkg/asset"
+
+	"github.com/talos-systems/talos/pkg/machinery/config"
+)
+
+// customizeFlannel applies the Flannel settings from the config to the rendered Flannel manifests.
+//
+// The backend is set in the flanneld network config, the MTU is passed to the CNI plugin delegate,
+// and the interface selection and the extra args are appended to the flanneld args.
+func customizeFlannel(flannel config.FlannelCNI) error {
+	if err := patchManifest(asset.AssetPathFlannelCfg, func(obj map[string]interface{}) error {
+		if obj["kind"] != "ConfigMap" {
+			return nil
+		}
+
+		data, ok := obj["data"].(map[string]interface{})
+		if !ok {
+			return errors.New("flannel config map has no data")
+		}
+
+		if err := patchJSON(data, "net-conf.json", func(netConf map[string]interface{}) {
+			backend, ok := netConf["Backend"].(map[string]interface{})
+			if !ok {
+				backend = map[string]interface{}{}
+				netConf["Backend"] = backend
+			}
+
+			backend["Type"] = flannel.Backend()
+		}); err != nil {
+			return err
+		}
+
+		if flannel.MTU() == 0 {
+			return nil
+		}
+
+		return patchJSON(data, "cni-conf.json", func(cniConf map[string]interface{}) {
+			plugins, _ := cniConf["plugins"].([]interface{}) //nolint: errcheck
+
+			for _, p := range plugins {
+				plugin, ok := p.(map[string]interface{})
+				if !ok || plugin["type"] != "flannel" {
+					continue
+				}
+
+				delegate, ok := plugin["delegate"].(map[string]interface{})
+				if !ok {
+					delegate = map[string]interface{}{}
+					plugin["delegate"] = delegate
+				}
+
+				delegate["mtu"] = flannel.MTU()
+			}
+		})
+	}); err != nil {
+		return err
+	}
+
+	args := flannelArgs(flannel)
+	if len(args) == 0 {
+		return nil
+	}
+
+	return patchManifest(asset.AssetPathFlannel, func(obj map[string]interface{}) error {
+		if obj["kind"] != "DaemonSet" {
+			return nil
+		}
+
+		for _, c := range podContainers(obj) {
+			container, ok := c.(map[string]interface{})
+			if !ok || container["name"] != "kube-flannel" {
+				continue
+			}
+
+			existing, _ := container["args"].([]interface{}) //nolint: errcheck
+
+			for _, arg := range args {
+				existing = append(existing, arg)
+			}
+
+			container["args"] = existing
+
+			return nil
+		}
+
+		return errors.New("flannel daemon set has no kube-flannel container")
+	})
+}
+
+// flannelArgs returns the flanneld args for the interface selection and the extra args.
+func flannelArgs(flannel config.FlannelCNI) []string {
+	var args []string
+
+	for _, iface := range flannel.Interfaces() {
+		args = append(args, "--iface="+iface)
+	}
+
+	for _, expr := range flannel.InterfaceRegexps() {
+		args = append(args, "--iface-regex="+expr)
+	}
+
+	extraArgs := flannel.ExtraArgs()
+
+	keys := make([]string, 0, len(extraArgs))
+
+	for k := range extraArgs {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		args = append(args, fmt.Sprintf("--%s=%s", k, extraArgs[k]))
+	}
+
+	return args
+}
+
+// podContainers returns the containers of the pod template of the workload object.
+func podContainers(obj map[string]interface{}) []interface{} {
+	spec, _ := obj["spec"].(map[string]interface{})          //nolint: errcheck
+	template, _ := spec["template"].(map[string]interface{}) //nolint: errcheck
+	podSpec, _ := template["spec"].(map[string]interface{})  //nolint: errcheck
+	containers, _ := podSpec["containers"].([]interface{})   //nolint: errcheck
+
+	return containers
+}
+
+// patchJSON updates the JSON document stored under the key of the config map data.
+func patchJSON(data map[string]interface{}, key string, patch func(doc map[string]interface{})) error {
+	contents, ok := data[key].(string)
+	if !ok {
+		return fmt.Errorf("flannel config map has no %q", key)
+	}
+
+	var doc map[string]interface{}
+
+	if err := json.Unmarshal([]byte(contents), &doc); err != nil {
+		return fmt.Errorf("error decoding %q: %w", key, err)
+	}
+
+	patch(doc)
+
+	updated, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	data[key] = string(updated)
+
+	return nil
+}
@@ -32,6 +32,10 @@ func List(config config.Provider) asset.ImageVersions {
 	// Allow for overriding by users via config data
 	images.CoreDNS = config.Cluster().CoreDNS().Image()
 
+	if config.Cluster().Network().CNI().Flannel().Image() != "" {
+		images.Flannel = config.Cluster().Network().CNI().Flannel().Image()
+	}
+
 	if config.Cluster().PodCheckpointer().Image() != "" {
 		images.PodCheckpointer = config.Cluster().PodCheckpointer().Image()
 	}
@@ -10,15 +10,21 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/kubernetes-sigs/bootkube/pkg/bootkube"
 	"github.com/kubernetes-sigs/bootkube/pkg/util"
+	"github.com/talos-systems/bootkube-plugin/pkg/asset"
+	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/talos-systems/talos/internal/pkg/controlplane"
 	"github.com/talos-systems/talos/pkg/machinery/config/configloader"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
+const createAssetsTimeout = 20 * time.Minute
+
 var (
 	strict        *bool
 	recover       *bool
@@ -46,7 +52,15 @@ func run() error {
 		return err
 	}
 
+	staticPods := controlplane.Enabled(config.Cluster())
+
 	if *recover {
+		if staticPods {
+			log.Printf("control plane is running as static pods, nothing to recover")
+
+			return nil
+		}
+
 		if err = recoverAssets(config); err != nil {
 			return fmt.Errorf("error recovering assets: %w", err)
 		}
@@ -56,6 +70,10 @@ func run() error {
 		}
 	}
 
+	if staticPods {
+		return createAssets()
+	}
+
 	if err = os.MkdirAll(constants.ManifestsDirectory, 0o644); err != nil {
 		return err
 	}
@@ -111,6 +129,27 @@ func run() error {
 	return nil
 }
 
+// createAssets creates the rendered manifests using the control plane running as static pods.
+//
+// The bootstrap control plane is not started, and the self-hosted control plane manifests are skipped.
+func createAssets() error {
+	defer func() {
+		if err := os.RemoveAll(constants.AssetsDirectory); err != nil {
+			log.Printf("failed to cleanup bootkube assets dir %s", constants.AssetsDirectory)
+		}
+	}()
+
+	if err := removeSelfHostedAssets(); err != nil {
+		return err
+	}
+
+	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: filepath.Join(constants.AssetsDirectory, asset.AssetPathAdminKubeConfig)},
+		&clientcmd.ConfigOverrides{})
+
+	return bootkube.CreateAssets(kubeConfig, filepath.Join(constants.AssetsDirectory, asset.AssetPathManifests), createAssetsTimeout, *strict)
+}
+
 func cleanupManifests(wildcard string) error {
 	bootstrapWildcard := filepath.Join(constants.ManifestsDirectory, wildcard)
 
@@ -0,0 +1,142 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/talos-systems/bootkube-plugin/pkg/asset"
+	"gopkg.in/yaml.v3"
+
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// talosManifests are the rendered manifests owned by Talos.
+//
+// These manifests are not applied by bootkube, they are handed over to the manifest sync service
+// which keeps them reconciled with the cluster.
+var talosManifests = []string{
+	asset.AssetPathCoreDNSClusterRoleBinding,
+	asset.AssetPathCoreDNSClusterRole,
+	asset.AssetPathCoreDNSConfig,
+	asset.AssetPathCoreDNSDeployment,
+	asset.AssetPathCoreDNSSA,
+	asset.AssetPathCoreDNSSvc,
+	asset.AssetPathCoreDNSv6Svc,
+	asset.AssetPathProxy,
+	asset.AssetPathProxySA,
+	asset.AssetPathProxyRoleBinding,
+	asset.AssetPathFlannel,
+	asset.AssetPathFlannelCfg,
+	asset.AssetPathFlannelClusterRole,
+	asset.AssetPathFlannelClusterRoleBinding,
+	asset.AssetPathFlannelSA,
+	asset.AssetPathPodSecurityPolicy,
+	asset.AssetPathKubeSystemSARoleBinding,
+	asset.AssetPathCSRBootstrapRoleBinding,
+	asset.AssetPathCSRApproverRoleBinding,
+	asset.AssetPathCSRRenewalRoleBinding,
+}
+
+// handOverManifests moves the Talos-owned manifests from the assets directory to the directory
+// watched by the manifest sync service.
+//
+// Manifests removed as disabled in the config or not rendered for the CNI in use are skipped.
+// Every manifest is written to a hidden file first and renamed, so that the service never sees
+// a partially written manifest.
+func handOverManifests() error {
+	if err := os.MkdirAll(constants.TalosManifestsDirectory, 0o700); err != nil {
+		return err
+	}
+
+	for _, p := range talosManifests {
+		src := filepath.Join(constants.AssetsDirectory, p)
+
+		contents, err := ioutil.ReadFile(src)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return err
+		}
+
+		name := filepath.Base(p)
+		tmp := filepath.Join(constants.TalosManifestsDirectory, "."+name)
+
+		if err = ioutil.WriteFile(tmp, contents, 0o600); err != nil {
+			return err
+		}
+
+		if err = os.Rename(tmp, filepath.Join(constants.TalosManifestsDirectory, name)); err != nil {
+			return err
+		}
+
+		if err = os.Remove(src); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// patchManifest updates the objects of the rendered manifest in the assets directory in place.
+//
+// The patch function is called for every object of the (multi-document) manifest.
+func patchManifest(p string, patch func(obj map[string]interface{}) error) error {
+	p = filepath.Join(constants.AssetsDirectory, p)
+
+	contents, err := ioutil.ReadFile(p)
+	if err != nil {
+		return err
+	}
+
+	var objects []map[string]interface{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(contents))
+
+	for {
+		var obj map[string]interface{}
+
+		if err = decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return err
+		}
+
+		if len(obj) == 0 {
+			continue
+		}
+
+		if err = patch(obj); err != nil {
+			return err
+		}
+
+		objects = append(objects, obj)
+	}
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+
+	for _, obj := range objects {
+		if err = encoder.Encode(obj); err != nil {
+			return err
+		}
+	}
+
+	if err = encoder.Close(); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(p, buf.Bytes(), 0o600)
+}
@@ -0,0 +1,88 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"text/template"
+
+	"github.com/talos-systems/talos/pkg/machinery/config"
+)
+
+// manifestTemplateData is the data available to the extra manifest templates.
+type manifestTemplateData struct {
+	ClusterName    string
+	Endpoint       string
+	DNSDomain      string
+	PodSubnets     []string
+	ServiceSubnets []string
+
+	// NodeLabels are the labels of the bootstrap node (kubelet `node-labels` extra arg).
+	NodeLabels map[string]string
+
+	// KubernetesCA and AggregatorCA are the base64-encoded CA certificates (the private keys are never exposed).
+	KubernetesCA string
+	AggregatorCA string
+
+	// Values are the user values from `cluster.extraManifestValues`.
+	Values map[string]string
+}
+
+var manifestTemplateFuncs = template.FuncMap{
+	"b64enc": func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	},
+	"join": strings.Join,
+}
+
+func newManifestTemplateData(config config.Provider) *manifestTemplateData {
+	data := &manifestTemplateData{
+		ClusterName:    config.Cluster().Name(),
+		Endpoint:       config.Cluster().Endpoint().String(),
+		DNSDomain:      config.Cluster().Network().DNSDomain(),
+		PodSubnets:     strings.Split(config.Cluster().Network().PodCIDR(), ","),
+		ServiceSubnets: strings.Split(config.Cluster().Network().ServiceCIDR(), ","),
+		NodeLabels:     map[string]string{},
+		Values:         config.Cluster().ExtraManifestValueMap(),
+	}
+
+	if labels := config.Machine().Kubelet().ExtraArgs()["node-labels"]; labels != "" {
+		for _, label := range strings.Split(labels, ",") {
+			parts := strings.SplitN(label, "=", 2)
+
+			if len(parts) == 2 {
+				data.NodeLabels[parts[0]] = parts[1]
+			}
+		}
+	}
+
+	if ca := config.Cluster().CA(); ca != nil {
+		data.KubernetesCA = base64.StdEncoding.EncodeToString(ca.Crt)
+	}
+
+	if ca := config.Cluster().AggregatorCA(); ca != nil {
+		data.AggregatorCA = base64.StdEncoding.EncodeToString(ca.Crt)
+	}
+
+	return data
+}
+
+// renderManifest renders the manifest as a Go template, referencing missing keys is an error.
+func renderManifest(name string, contents []byte, data *manifestTemplateData) ([]byte, error) {
+	tmpl, err := template.New(name).Funcs(manifestTemplateFuncs).Option("missingkey=error").Parse(string(contents))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
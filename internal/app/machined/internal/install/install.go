@@ -23,6 +23,7 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
 	"github.com/talos-systems/talos/internal/pkg/containers/image"
 	"github.com/talos-systems/talos/internal/pkg/kmsg"
+	"github.com/talos-systems/talos/internal/pkg/secureboot"
 	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/config"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
@@ -51,12 +52,19 @@ func RunInstallerContainer(disk, platform, ref string, reg config.Registries, op
 
 	if !options.Pull {
 		img, err = client.GetImage(ctx, ref)
+
+		if err == nil {
+			// image which is already present is verified as well, as it might have been pulled without the verification
+			if err = image.Verify(ctx, reg, client, img, image.WithVerification(options.ImageVerification)); err != nil {
+				return err
+			}
+		}
 	}
 
 	if img == nil || err != nil && errdefs.IsNotFound(err) {
 		log.Printf("pulling %q", ref)
 
-		img, err = image.Pull(ctx, reg, client, ref)
+		img, err = image.Pull(ctx, reg, client, ref, image.WithVerification(options.ImageVerification))
 	}
 
 	if err != nil {
@@ -67,6 +75,11 @@ func RunInstallerContainer(disk, platform, ref string, reg config.Registries, op
 		{Type: "bind", Destination: "/dev", Source: "/dev", Options: []string{"rbind", "rshared", "rw"}},
 	}
 
+	// the installer verifies the SecureBoot state and enrolls the SecureBoot keys via the EFI variables
+	if _, err = os.Stat(secureboot.EFIVarsPath); err == nil {
+		mounts = append(mounts, specs.Mount{Type: "bind", Destination: secureboot.EFIVarsPath, Source: secureboot.EFIVarsPath, Options: []string{"rbind", "rw"}})
+	}
+
 	// TODO(andrewrynhard): To handle cases when the newer version changes the
 	// platform name, this should be determined in the installer container.
 	config := constants.ConfigNone
@@ -93,6 +106,26 @@ func RunInstallerContainer(disk, platform, ref string, reg config.Registries, op
 		args = append(args, "--board="+*c)
 	}
 
+	if options.EphemeralDisk != "" && options.EphemeralDisk != disk {
+		args = append(args, "--ephemeral-disk="+options.EphemeralDisk)
+	}
+
+	if options.EphemeralSize != 0 {
+		args = append(args, "--ephemeral-size="+strconv.FormatUint(options.EphemeralSize, 10))
+	}
+
+	if options.SerialConsole != "" {
+		args = append(args, "--serial-console="+options.SerialConsole, "--serial-baud-rate="+strconv.Itoa(options.SerialBaudRate))
+	}
+
+	if options.DisableVGA {
+		args = append(args, "--disable-vga")
+	}
+
+	if options.EnrollSecureBootKeys {
+		args = append(args, "--enroll-secureboot-keys")
+	}
+
 	for _, arg := range options.ExtraKernelArgs {
 		args = append(args, []string{"--extra-kernel-arg", arg}...)
 	}
@@ -162,6 +195,29 @@ func OptionsFromUpgradeRequest(r runtime.Runtime, in *machineapi.UpgradeRequest)
 		WithPull(false),
 		WithUpgrade(true),
 		WithForce(!in.GetPreserve()),
-		WithExtraKernelArgs(r.Config().Machine().Install().ExtraKernelArgs()),
+		WithExtraKernelArgs(ExtraKernelArgs(r.Config().Machine())),
+		WithEphemeralDisk(r.Config().Machine().Install().EphemeralDisk()),
+		WithEphemeralSize(r.Config().Machine().Install().EphemeralSize()),
+		WithConsole(r.Config().Machine().Install().Console()),
+		WithEnrollSecureBootKeys(r.Config().Machine().Install().EnrollSecureBootKeys()),
+	}
+}
+
+// ExtraKernelArgs returns the extra kernel arguments from the install config and the CPU isolation kernel config.
+func ExtraKernelArgs(cfg config.MachineConfig) []string {
+	args := append([]string{}, cfg.Install().ExtraKernelArgs()...)
+
+	if cpus := cfg.Kernel().IsolatedCPUs(); cpus != "" {
+		args = append(args, "isolcpus="+cpus)
 	}
+
+	if cpus := cfg.Kernel().NohzFullCPUs(); cpus != "" {
+		args = append(args, "nohz_full="+cpus, "rcu_nocbs="+cpus)
+	}
+
+	if cpus := cfg.Kernel().IRQAffinityCPUs(); cpus != "" {
+		args = append(args, "irqaffinity="+cpus)
+	}
+
+	return args
 }
@@ -4,6 +4,8 @@
 
 package install
 
+import "github.com/talos-systems/talos/pkg/machinery/config"
+
 // Option is a functional option.
 type Option func(o *Options) error
 
@@ -14,6 +16,17 @@ type Options struct {
 	Upgrade         bool
 	Zero            bool
 	ExtraKernelArgs []string
+	EphemeralDisk   string
+	EphemeralSize   uint64
+	SerialConsole   string
+	SerialBaudRate  int
+	DisableVGA      bool
+
+	EnrollSecureBootKeys bool
+
+	// ImageVerification is not persisted with staged install options,
+	// as the staged image is verified when it's pulled.
+	ImageVerification config.ImageVerification `json:"-"`
 }
 
 // DefaultInstallOptions returns default options.
@@ -87,3 +100,50 @@ func WithExtraKernelArgs(s []string) Option {
 		return nil
 	}
 }
+
+// WithEphemeralDisk sets the disk for the EPHEMERAL partition.
+func WithEphemeralDisk(disk string) Option {
+	return func(o *Options) error {
+		o.EphemeralDisk = disk
+
+		return nil
+	}
+}
+
+// WithEphemeralSize sets the size of the EPHEMERAL partition.
+func WithEphemeralSize(size uint64) Option {
+	return func(o *Options) error {
+		o.EphemeralSize = size
+
+		return nil
+	}
+}
+
+// WithConsole sets the console output options.
+func WithConsole(c config.InstallConsole) Option {
+	return func(o *Options) error {
+		o.SerialConsole = c.Serial()
+		o.SerialBaudRate = c.BaudRate()
+		o.DisableVGA = c.DisableVGA()
+
+		return nil
+	}
+}
+
+// WithEnrollSecureBootKeys sets the SecureBoot key enrollment flag.
+func WithEnrollSecureBootKeys(b bool) Option {
+	return func(o *Options) error {
+		o.EnrollSecureBootKeys = b
+
+		return nil
+	}
+}
+
+// WithImageVerification sets the installer image signature verification config.
+func WithImageVerification(v config.ImageVerification) Option {
+	return func(o *Options) error {
+		o.ImageVerification = v
+
+		return nil
+	}
+}
@@ -0,0 +1,124 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"log"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/talos-systems/talos/internal/pkg/measure"
+	"github.com/talos-systems/talos/internal/pkg/tpm2"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// maxNonceSize is the maximum nonce size accepted by the TPM for the quote.
+const maxNonceSize = 32
+
+// defaultAttestPCRs are the PCRs quoted if none are requested: firmware and bootloader PCRs
+// and the PCR Talos measures the boot into.
+var defaultAttestPCRs = []int{0, 1, 2, 3, 4, 5, 6, 7, constants.MeasurementPCR}
+
+// Attest implements the machine.MachineServer interface.
+//
+// nolint: gocyclo
+func (s *Server) Attest(ctx context.Context, in *machine.AttestRequest) (*machine.AttestResponse, error) {
+	if len(in.GetNonce()) == 0 || len(in.GetNonce()) > maxNonceSize {
+		return nil, status.Errorf(codes.InvalidArgument, "nonce should be 1 to %d bytes long", maxNonceSize)
+	}
+
+	pcrs := append([]int(nil), defaultAttestPCRs...)
+
+	if len(in.GetPcrs()) > 0 {
+		pcrs = make([]int, 0, len(in.GetPcrs()))
+
+		for _, pcr := range in.GetPcrs() {
+			if pcr > 23 {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid PCR %d", pcr)
+			}
+
+			pcrs = append(pcrs, int(pcr))
+		}
+	}
+
+	t, err := tpm2.Open()
+	if err != nil {
+		if errors.Is(err, tpm2.ErrNotFound) {
+			return nil, status.Error(codes.FailedPrecondition, "TPM is not available")
+		}
+
+		return nil, err
+	}
+
+	//nolint: errcheck
+	defer t.Close()
+
+	handle, akPublic, err := t.CreateAttestationKey()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if e := t.FlushContext(handle); e != nil {
+			log.Printf("failed to flush attestation key: %s", e)
+		}
+	}()
+
+	quote, signature, err := t.Quote(handle, in.GetNonce(), pcrs)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := t.PCRRead(pcrs)
+	if err != nil {
+		return nil, err
+	}
+
+	akPublicDER, err := x509.MarshalPKIXPublicKey(akPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := measure.ReadLog(constants.MeasurementLogPath)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &machine.Attest{
+		Quote:     quote,
+		Signature: signature,
+		AkPublic:  akPublicDER,
+	}
+
+	sort.Ints(pcrs)
+
+	for _, pcr := range pcrs {
+		reply.Pcrs = append(reply.Pcrs, &machine.PCRValue{
+			Pcr:   uint32(pcr),
+			Value: values[pcr],
+		})
+	}
+
+	for _, event := range events {
+		reply.Events = append(reply.Events, &machine.MeasurementEvent{
+			Pcr:         uint32(event.PCR),
+			Type:        event.Type,
+			Digest:      event.Digest,
+			Description: event.Description,
+		})
+	}
+
+	return &machine.AttestResponse{
+		Messages: []*machine.Attest{
+			reply,
+		},
+	}, nil
+}
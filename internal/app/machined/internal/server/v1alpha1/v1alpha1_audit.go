@@ -0,0 +1,41 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+
+	"github.com/talos-systems/talos/internal/pkg/audit"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+)
+
+// AuditLog implements the machine.MachineServer interface.
+func (s *Server) AuditLog(ctx context.Context, in *machine.AuditLogRequest) (*machine.AuditLogResponse, error) {
+	entries, err := audit.Default.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &machine.AuditLog{}
+
+	// verify the whole chain, tail only limits the response
+	if err = audit.Verify(entries); err != nil {
+		reply.VerificationError = err.Error()
+	}
+
+	if tail := int(in.GetTailEvents()); tail > 0 && tail < len(entries) {
+		entries = entries[len(entries)-tail:]
+	}
+
+	reply.Events = make([]*machine.AuditEvent, len(entries))
+
+	for i := range entries {
+		reply.Events[i] = entries[i].Proto()
+	}
+
+	return &machine.AuditLogResponse{
+		Messages: []*machine.AuditLog{reply},
+	}, nil
+}
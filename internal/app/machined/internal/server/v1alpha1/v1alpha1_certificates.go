@@ -0,0 +1,267 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/talos-systems/talos/internal/pkg/controlplane"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	clientconfig "github.com/talos-systems/talos/pkg/machinery/client/config"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+	machinetype "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// certificateEndpoint is a local TLS endpoint which presents a certificate.
+type certificateEndpoint struct {
+	name string
+	port int
+}
+
+// Certificates implements the machine.MachineServer interface.
+//
+// Certificates are collected from the Kubernetes and kubelet PKI directories, from the control plane
+// static pods secrets, from the machine config (Talos CA), and from the TLS endpoints of the services
+// running on the node.
+func (s *Server) Certificates(ctx context.Context, in *empty.Empty) (*machine.CertificatesResponse, error) {
+	var certs []*machine.Certificate
+
+	cfg := s.Controller.Runtime().Config()
+
+	if cfg != nil {
+		if ca := cfg.Machine().Security().CA(); ca != nil {
+			certs = append(certs, certificatesFromPEM("config: machine.ca", ca.Crt)...)
+		}
+	}
+
+	for _, dir := range []string{constants.DefaultCertificatesDir, constants.KubeletPKIDir} {
+		// nolint: errcheck
+		filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || !info.Mode().IsRegular() {
+				return nil
+			}
+
+			if ext := filepath.Ext(path); ext != ".crt" && ext != ".pem" {
+				return nil
+			}
+
+			contents, err := ioutil.ReadFile(path)
+			if err != nil {
+				log.Printf("failed to read certificate %q: %s", path, err)
+
+				return nil
+			}
+
+			certs = append(certs, certificatesFromPEM(path, contents)...)
+
+			return nil
+		})
+	}
+
+	issued, err := controlplane.IssuedCertificates()
+	if err != nil {
+		log.Printf("failed to read control plane certificates: %s", err)
+	}
+
+	for _, cert := range issued {
+		certs = append(certs, certificateInfo(cert.Source, cert.Certificate))
+	}
+
+	endpoints := []certificateEndpoint{
+		{"apid", constants.ApidPort},
+		{"kubelet", constants.KubeletPort},
+	}
+
+	if cfg != nil && cfg.Machine().Type() != machinetype.TypeJoin {
+		endpoints = append(endpoints,
+			certificateEndpoint{"trustd", constants.TrustdPort},
+			certificateEndpoint{"kube-apiserver", cfg.Cluster().LocalAPIServerPort()},
+		)
+	}
+
+	for _, endpoint := range endpoints {
+		cert, err := peerCertificate(ctx, net.JoinHostPort("127.0.0.1", strconv.Itoa(endpoint.port)))
+		if err != nil {
+			// service might be not running
+			continue
+		}
+
+		certs = append(certs, certificateInfo(endpoint.name, cert))
+	}
+
+	reply := &machine.CertificatesResponse{
+		Messages: []*machine.Certificates{
+			{
+				Certificates: certs,
+			},
+		},
+	}
+
+	return reply, nil
+}
+
+// GenerateClientConfiguration implements the machine.MachineServer interface.
+//
+// New client certificate is signed with the Talos CA, so the certificates can be issued
+// only on the control plane nodes which have the CA key.
+// Certificates can be issued only to the clients with the admin role, and the impersonator role
+// (which is reserved for apid) can't be requested.
+func (s *Server) GenerateClientConfiguration(ctx context.Context, in *machine.GenerateClientConfigurationRequest) (*machine.GenerateClientConfigurationResponse, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, role := range in.GetRoles() {
+		if role == constants.RoleImpersonator {
+			return nil, status.Errorf(codes.InvalidArgument, "%s role can't be requested", constants.RoleImpersonator)
+		}
+	}
+
+	cfg := s.Controller.Runtime().Config()
+
+	if cfg.Machine().Type() == machinetype.TypeJoin {
+		return nil, status.Error(codes.FailedPrecondition, "client configuration can't be generated on worker nodes")
+	}
+
+	ca := cfg.Machine().Security().CA()
+	if ca == nil || len(ca.Key) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "Talos CA key is not available")
+	}
+
+	crtTTL, err := ptypes.Duration(in.GetCrtTtl())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid certificate TTL: %s", err)
+	}
+
+	if crtTTL <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "certificate TTL should be positive")
+	}
+
+	cert, err := generate.NewClientCertificateAndKey(time.Now(), ca, in.GetRoles(), crtTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	talosconfig, err := clientconfig.NewConfig(cfg.Cluster().Name(), nil, ca.Crt, cert).Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &machine.GenerateClientConfigurationResponse{
+		Messages: []*machine.GenerateClientConfiguration{
+			{
+				Ca:          ca.Crt,
+				Crt:         cert.Crt,
+				Key:         cert.Key,
+				Talosconfig: talosconfig,
+			},
+		},
+	}
+
+	return reply, nil
+}
+
+// peerCertificate returns the certificate presented by the TLS endpoint.
+//
+// Certificate is not verified, as only certificate metadata is reported.
+func peerCertificate(ctx context.Context, address string) (*x509.Certificate, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var cert *x509.Certificate
+
+	dialer := &net.Dialer{}
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint: errcheck
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline) //nolint: errcheck
+	}
+
+	tlsConn := tls.Client(conn, &tls.Config{
+		InsecureSkipVerify: true, //nolint: gosec
+		// capture the certificate even if the handshake fails later (e.g. if client certificate is required)
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) > 0 {
+				cert, _ = x509.ParseCertificate(rawCerts[0]) //nolint: errcheck
+			}
+
+			return nil
+		},
+	})
+
+	err = tlsConn.Handshake()
+
+	if cert != nil {
+		return cert, nil
+	}
+
+	return nil, err
+}
+
+func certificatesFromPEM(source string, contents []byte) []*machine.Certificate {
+	var certs []*machine.Certificate
+
+	for {
+		var block *pem.Block
+
+		block, contents = pem.Decode(contents)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			log.Printf("failed to parse certificate %q: %s", source, err)
+
+			continue
+		}
+
+		certs = append(certs, certificateInfo(source, cert))
+	}
+
+	return certs
+}
+
+func certificateInfo(source string, cert *x509.Certificate) *machine.Certificate {
+	notBefore, _ := ptypes.TimestampProto(cert.NotBefore) //nolint: errcheck
+	notAfter, _ := ptypes.TimestampProto(cert.NotAfter)   //nolint: errcheck
+
+	return &machine.Certificate{
+		Source:    source,
+		Subject:   strings.TrimSpace(cert.Subject.String()),
+		Issuer:    strings.TrimSpace(cert.Issuer.String()),
+		NotBefore: notBefore,
+		NotAfter:  notAfter,
+		IsCa:      cert.IsCA,
+	}
+}
@@ -0,0 +1,61 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"log"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/talos-systems/talos/internal/pkg/discovery"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// ClusterMembers implements the machine.MachineServer interface.
+//
+// Members are fetched from the discovery registries enabled in the machine config.
+func (s *Server) ClusterMembers(ctx context.Context, in *empty.Empty) (*machine.ClusterMembersResponse, error) {
+	cfg := s.Controller.Runtime().Config()
+
+	if cfg == nil || !cfg.Cluster().Discovery().Enabled() {
+		return nil, status.Error(codes.FailedPrecondition, "cluster discovery is not enabled")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, constants.DiscoveryRequestTimeout)
+	defer cancel()
+
+	members, err := discovery.Members(ctx, discovery.Registries(cfg)...)
+	if err != nil {
+		if len(members) == 0 {
+			return nil, err
+		}
+
+		log.Printf("error listing cluster members: %s", err)
+	}
+
+	reply := &machine.ClusterMembersResponse{
+		Messages: []*machine.ClusterMembers{
+			{
+				Members: make([]*machine.ClusterMember, 0, len(members)),
+			},
+		},
+	}
+
+	for _, member := range members {
+		reply.Messages[0].Members = append(reply.Messages[0].Members, &machine.ClusterMember{
+			Id:          member.ID,
+			Hostname:    member.Hostname,
+			MachineType: member.MachineType,
+			Addresses:   member.Addresses,
+			Registries:  member.Registries,
+		})
+	}
+
+	return reply, nil
+}
@@ -0,0 +1,158 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/talos-systems/talos/internal/pkg/configdiff"
+	"github.com/talos-systems/talos/internal/pkg/etcd"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/config/encoder"
+	"github.com/talos-systems/talos/pkg/machinery/config/redact"
+	machinetype "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
+)
+
+// applyConfigurationDryRun validates the configuration and reports the changes without applying it.
+func (s *Server) applyConfigurationDryRun(ctx context.Context, in *machine.ApplyConfigurationRequest) (*machine.DryRun, error) {
+	cfg, err := s.Controller.Runtime().ValidateConfig(in.GetData())
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cfg.ApplyDynamicConfig(ctx, s.Controller.Runtime().State().Platform()); err != nil {
+		return nil, err
+	}
+
+	current, err := s.Controller.Runtime().Config().EncodeBytes(encoder.WithComments(encoder.CommentsDisabled))
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := cfg.EncodeBytes(encoder.WithComments(encoder.CommentsDisabled))
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := configdiff.Diff(current, updated)
+	if err != nil {
+		return nil, err
+	}
+
+	// the unified diff includes the unchanged lines around the changes, so it's built from the redacted
+	// configurations to keep the secrets (both current and new) on the node
+	redactedCurrent, err := redact.Redact(current)
+	if err != nil {
+		return nil, err
+	}
+
+	redactedUpdated, err := redact.Redact(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	redactedDiff, err := configdiff.Diff(redactedCurrent, redactedUpdated)
+	if err != nil {
+		return nil, err
+	}
+
+	dryRun := &machine.DryRun{
+		ConfigDiff: redactedDiff.Diff,
+	}
+
+	for _, change := range diff.Changes {
+		dryRun.ConfigChanges = append(dryRun.ConfigChanges, &machine.ConfigChange{
+			Path:      change.Path,
+			Operation: string(change.Operation),
+		})
+	}
+
+	if len(diff.Changes) == 0 {
+		return dryRun, nil
+	}
+
+	dryRun.Actions = append(dryRun.Actions, "store the machine configuration")
+
+	if in.GetNoReboot() {
+		dryRun.Actions = append(dryRun.Actions, "apply the configuration changes on the next reboot")
+	} else {
+		dryRun.Actions = append(dryRun.Actions, "stop all services", "reboot")
+		dryRun.RebootRequired = true
+	}
+
+	return dryRun, nil
+}
+
+// upgradeDryRun validates the upgrade request and reports the actions without performing them.
+//
+// The installer image is expected to be validated by the caller.
+func (s *Server) upgradeDryRun(ctx context.Context, in *machine.UpgradeRequest) (*machine.DryRun, error) {
+	now := time.Now()
+
+	runAt, err := scheduledRunAt(now, in.GetSchedule())
+	if err != nil {
+		return nil, err
+	}
+
+	controlPlane := s.Controller.Runtime().Config().Machine().Type() != machinetype.TypeJoin
+
+	if controlPlane {
+		client, err := etcd.NewClientFromControlPlaneIPs(ctx, s.Controller.Runtime().Config().Cluster().CA(), s.Controller.Runtime().Config().Cluster().Endpoint())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create etcd client: %w", err)
+		}
+
+		//nolint: errcheck
+		defer client.Close()
+
+		if err = client.ValidateForUpgrade(ctx, s.Controller.Runtime().Config(), in.GetPreserve()); err != nil {
+			return nil, fmt.Errorf("error validating etcd for upgrade: %w", err)
+		}
+	}
+
+	dryRun := &machine.DryRun{
+		RebootRequired: true,
+	}
+
+	if runAt.After(now) {
+		dryRun.Actions = append(dryRun.Actions, fmt.Sprintf("schedule the upgrade at %s", runAt.Format(time.RFC3339)))
+	}
+
+	if in.GetStage() {
+		dryRun.Actions = append(dryRun.Actions, fmt.Sprintf("stage the upgrade to %q", in.GetImage()))
+	} else if !in.GetForce() {
+		dryRun.Actions = append(dryRun.Actions, "cordon and drain the node")
+	}
+
+	if !in.GetPreserve() && controlPlane {
+		dryRun.Actions = append(dryRun.Actions, "leave the etcd cluster")
+	}
+
+	if in.GetStage() {
+		dryRun.Actions = append(dryRun.Actions, "stop all services", "reboot", fmt.Sprintf("install %q", in.GetImage()))
+	} else {
+		if in.GetPreserve() {
+			dryRun.Actions = append(dryRun.Actions, "stop all pods")
+		} else {
+			dryRun.Actions = append(dryRun.Actions, "remove all pods")
+		}
+
+		dryRun.Actions = append(dryRun.Actions, "stop services", fmt.Sprintf("install %q", in.GetImage()))
+	}
+
+	if in.GetPreserve() {
+		dryRun.Actions = append(dryRun.Actions, "preserve the EPHEMERAL partition")
+	} else {
+		dryRun.Actions = append(dryRun.Actions, "wipe the EPHEMERAL partition")
+	}
+
+	if !in.GetStage() {
+		dryRun.Actions = append(dryRun.Actions, "reboot")
+	}
+
+	return dryRun, nil
+}
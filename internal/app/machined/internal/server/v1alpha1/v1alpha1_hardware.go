@@ -0,0 +1,159 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"log"
+	"net"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/talos-systems/go-smbios/smbios"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/talos-systems/talos/internal/pkg/bmc"
+	"github.com/talos-systems/talos/internal/pkg/hardware"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// HardwareInventory implements the machine.MachineServer interface.
+//
+// SMBIOS data is optional, as SMBIOS tables might be unavailable (e.g. in container mode).
+func (s *Server) HardwareInventory(ctx context.Context, in *empty.Empty) (*machine.HardwareInventoryResponse, error) {
+	inventory := &machine.HardwareInventory{}
+
+	if smbiosInfo, err := smbios.New(); err == nil {
+		inventory.Smbios = smbiosInventory(smbiosInfo)
+
+		for _, module := range hardware.MemoryModules(smbiosInfo) {
+			inventory.MemoryModules = append(inventory.MemoryModules, &machine.MemoryModule{
+				Locator:      module.Locator,
+				BankLocator:  module.BankLocator,
+				Size:         module.Size,
+				Type:         module.Type,
+				Speed:        module.Speed,
+				Manufacturer: module.Manufacturer,
+				SerialNumber: module.SerialNumber,
+				PartNumber:   module.PartNumber,
+			})
+		}
+	} else {
+		log.Printf("error reading SMBIOS: %s", err)
+	}
+
+	devices, err := hardware.PCIDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, device := range devices {
+		inventory.PciDevices = append(inventory.PciDevices, &machine.PCIDevice{
+			Address:           device.Address,
+			Parent:            device.Parent,
+			Class:             device.Class,
+			VendorId:          device.VendorID,
+			DeviceId:          device.DeviceID,
+			SubsystemVendorId: device.SubsystemVendorID,
+			SubsystemDeviceId: device.SubsystemDeviceID,
+			Driver:            device.Driver,
+			NumaNode:          device.NUMANode,
+		})
+	}
+
+	nodes, err := hardware.NUMANodes()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, node := range nodes {
+		inventory.NumaNodes = append(inventory.NumaNodes, &machine.NUMANode{
+			Id:          node.ID,
+			Cpus:        node.CPUs,
+			MemoryTotal: node.MemoryTotal,
+		})
+	}
+
+	cpus, err := hardware.CPUs(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cpu := range cpus {
+		inventory.Cpus = append(inventory.Cpus, &machine.CPUTopology{
+			Id:        cpu.ID,
+			PackageId: cpu.PackageID,
+			CoreId:    cpu.CoreID,
+			NumaNode:  cpu.NUMANode,
+		})
+	}
+
+	return &machine.HardwareInventoryResponse{
+		Messages: []*machine.HardwareInventory{inventory},
+	}, nil
+}
+
+// BMCInfo implements the machine.MachineServer interface.
+//
+// BMC is queried via the in-band IPMI interface on the LAN channel from the machine config.
+func (s *Server) BMCInfo(ctx context.Context, in *empty.Empty) (*machine.BMCInfoResponse, error) {
+	channel := uint8(constants.DefaultBMCChannel)
+
+	if cfg := s.Controller.Runtime().Config(); cfg != nil {
+		channel = cfg.Machine().BMC().Channel()
+	}
+
+	ipmiDev, err := bmc.Open()
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "IPMI device is not available: %s", err)
+	}
+
+	// nolint: errcheck
+	defer ipmiDev.Close()
+
+	lanConfig, err := bmc.GetLANConfig(ipmiDev, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &machine.BMCInfoResponse{
+		Messages: []*machine.BMCInfo{
+			{
+				Channel:  uint32(lanConfig.Channel),
+				IpSource: lanConfig.IPSource,
+				Address:  lanConfig.Address.String(),
+				Netmask:  net.IP(lanConfig.Netmask).String(),
+				Gateway:  lanConfig.Gateway.String(),
+				Mac:      lanConfig.MAC.String(),
+			},
+		},
+	}, nil
+}
+
+func smbiosInventory(s *smbios.Smbios) *machine.SMBIOSInfo {
+	info := &machine.SMBIOSInfo{
+		Version: s.Version,
+		System: &machine.HardwareInfo{
+			Manufacturer: s.SystemInformation().Manufacturer(),
+			ProductName:  s.SystemInformation().ProductName(),
+			SerialNumber: s.SystemInformation().SerialNumber(),
+		},
+		Baseboard: &machine.HardwareInfo{
+			Manufacturer: s.BaseboardInformation().Manufacturer(),
+			ProductName:  s.BaseboardInformation().Product(),
+			SerialNumber: s.BaseboardInformation().SerialNumber(),
+		},
+		BiosVendor:      s.BIOSInformation().Vendor(),
+		BiosVersion:     s.BIOSInformation().Version(),
+		BiosReleaseDate: s.BIOSInformation().ReleaseDate(),
+	}
+
+	if uuid, err := s.SystemInformation().UUID(); err == nil {
+		info.Uuid = uuid.String()
+	}
+
+	return info
+}
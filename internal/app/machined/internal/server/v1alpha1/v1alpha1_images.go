@@ -0,0 +1,166 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/reference/docker"
+	criconstants "github.com/containerd/cri/pkg/constants"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/talos-systems/talos/internal/pkg/containers/image"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// ImageList implements the machine.MachineServer interface.
+func (s *Server) ImageList(ctx context.Context, in *machine.ImageListRequest) (*machine.ImageListResponse, error) {
+	client, ctx, err := imageClient(ctx, in.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint: errcheck
+	defer client.Close()
+
+	list, err := client.ImageService().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]*machine.ImageInfo, 0, len(list))
+
+	for _, img := range list {
+		info := &machine.ImageInfo{
+			Namespace: in.Namespace,
+			Name:      img.Name,
+			Digest:    img.Target.Digest.String(),
+		}
+
+		// size is not known if the image content is not fully fetched
+		if size, err := containerd.NewImage(client, img).Size(ctx); err == nil {
+			info.Size = uint64(size)
+		}
+
+		if !img.CreatedAt.IsZero() {
+			// nolint: errcheck
+			info.CreatedAt, _ = ptypes.TimestampProto(img.CreatedAt)
+		}
+
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+
+	return &machine.ImageListResponse{
+		Messages: []*machine.ImageList{
+			{
+				Images: infos,
+			},
+		},
+	}, nil
+}
+
+// ImagePull implements the machine.MachineServer interface.
+func (s *Server) ImagePull(ctx context.Context, in *machine.ImagePullRequest) (*machine.ImagePullResponse, error) {
+	ref, err := normalizeImageReference(in.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	client, ctx, err := imageClient(ctx, in.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint: errcheck
+	defer client.Close()
+
+	cfg := s.Controller.Runtime().Config().Machine()
+
+	img, err := image.Pull(ctx, cfg.Registries(), client, ref, image.WithVerification(cfg.Features().ImageVerification()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &machine.ImagePullResponse{
+		Messages: []*machine.ImagePull{
+			{
+				Name:   img.Name(),
+				Digest: img.Target().Digest.String(),
+			},
+		},
+	}, nil
+}
+
+// ImageRemove implements the machine.MachineServer interface.
+func (s *Server) ImageRemove(ctx context.Context, in *machine.ImageRemoveRequest) (*machine.ImageRemoveResponse, error) {
+	ref, err := normalizeImageReference(in.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	client, ctx, err := imageClient(ctx, in.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint: errcheck
+	defer client.Close()
+
+	if err = client.ImageService().Delete(ctx, ref, images.SynchronousDelete()); err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, status.Errorf(codes.NotFound, "image %q not found", ref)
+		}
+
+		return nil, err
+	}
+
+	return &machine.ImageRemoveResponse{
+		Messages: []*machine.ImageRemove{
+			{},
+		},
+	}, nil
+}
+
+// imageClient connects to the containerd instance which manages the images of the namespace.
+func imageClient(ctx context.Context, namespace string) (*containerd.Client, context.Context, error) {
+	var addr string
+
+	switch namespace {
+	case constants.SystemContainerdNamespace:
+		addr = constants.SystemContainerdAddress
+	case criconstants.K8sContainerdNamespace:
+		addr = constants.ContainerdAddress
+	default:
+		return nil, nil, status.Errorf(codes.InvalidArgument, "unsupported namespace %q", namespace)
+	}
+
+	client, err := containerd.New(addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error connecting to containerd: %w", err)
+	}
+
+	return client, namespaces.WithNamespace(ctx, namespace), nil
+}
+
+// normalizeImageReference expands the short image reference (e.g. `alpine`) the way CRI does (`docker.io/library/alpine:latest`).
+func normalizeImageReference(ref string) (string, error) {
+	named, err := docker.ParseDockerRef(ref)
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "invalid image reference %q: %s", ref, err)
+	}
+
+	return named.String(), nil
+}
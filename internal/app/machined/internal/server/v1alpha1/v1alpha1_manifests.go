@@ -0,0 +1,44 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+)
+
+// Manifests implements the machine.MachineServer interface.
+//
+// Objects are reported as seen by the last run of the manifest sync service.
+func (s *Server) Manifests(ctx context.Context, in *empty.Empty) (*machine.ManifestsResponse, error) {
+	report := s.Controller.Runtime().State().Cluster().Manifests().Report()
+
+	reply := &machine.ManifestsResponse{
+		Messages: []*machine.Manifests{
+			{
+				Objects: make([]*machine.ManifestObject, 0, len(report.Objects)),
+			},
+		},
+	}
+
+	for _, object := range report.Objects {
+		reply.Messages[0].Objects = append(reply.Messages[0].Objects, &machine.ManifestObject{
+			Manifest:   object.Manifest,
+			ApiVersion: object.APIVersion,
+			Kind:       object.Kind,
+			Namespace:  object.Namespace,
+			Name:       object.Name,
+			State:      object.State,
+			Error:      object.Error,
+			LastSync:   timestampProto(object.LastSync),
+			Contents:   string(object.Contents),
+		})
+	}
+
+	return reply, nil
+}
@@ -0,0 +1,126 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/adv"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/grub"
+	"github.com/talos-systems/talos/internal/pkg/mount"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// userMetaKeys is the set of META keys which can be modified via the API.
+//
+// Other keys are managed by Talos itself.
+var userMetaKeys = map[uint32]struct{}{
+	adv.ExtraKernelArgs: {},
+}
+
+// MetaWrite implements the machine.MachineServer interface.
+func (s *Server) MetaWrite(ctx context.Context, in *machine.MetaWriteRequest) (*machine.MetaWriteResponse, error) {
+	if err := s.checkSupported(runtime.Meta); err != nil {
+		return nil, err
+	}
+
+	if err := updateMeta(in.GetKey(), string(in.GetValue())); err != nil {
+		return nil, err
+	}
+
+	return &machine.MetaWriteResponse{
+		Messages: []*machine.MetaWrite{
+			{},
+		},
+	}, nil
+}
+
+// MetaDelete implements the machine.MachineServer interface.
+func (s *Server) MetaDelete(ctx context.Context, in *machine.MetaDeleteRequest) (*machine.MetaDeleteResponse, error) {
+	if err := s.checkSupported(runtime.Meta); err != nil {
+		return nil, err
+	}
+
+	if err := updateMeta(in.GetKey(), ""); err != nil {
+		return nil, err
+	}
+
+	return &machine.MetaDeleteResponse{
+		Messages: []*machine.MetaDelete{
+			{},
+		},
+	}, nil
+}
+
+// updateMeta sets the META key to the value, empty value deletes the key.
+//
+// nolint: gocyclo
+func updateMeta(key uint32, value string) error {
+	if _, ok := userMetaKeys[key]; !ok {
+		return status.Errorf(codes.InvalidArgument, "META key 0x%x can't be modified", key)
+	}
+
+	if key == adv.ExtraKernelArgs && value != "" {
+		if _, err := grub.EncodeCmdlineExtra(value); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	if key == adv.ExtraKernelArgs {
+		// grub can't read META, so extra kernel arguments are mirrored to the boot partition
+		for _, label := range []string{constants.BootPartitionLabel, constants.EFIPartitionLabel} {
+			label := label
+
+			if err := mount.SystemPartitionMount(label); err != nil {
+				return fmt.Errorf("error mounting %s partition: %w", label, err)
+			}
+
+			defer func() {
+				if e := mount.SystemPartitionUnmount(label); e != nil {
+					log.Printf("failed unmounting %s partition: %s", label, e)
+				}
+			}()
+		}
+
+		// unified kernel images embed the kernel command line
+		if value != "" && bootloader.Probe() != bootloader.TypeGrub {
+			return status.Errorf(codes.FailedPrecondition, "extra kernel arguments are not supported with %s bootloader", bootloader.Probe())
+		}
+	}
+
+	meta, err := bootloader.NewMeta()
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer meta.Close()
+
+	if value == "" {
+		meta.ADV.DeleteTag(uint8(key))
+	} else if !meta.ADV.SetTag(uint8(key), value) {
+		return fmt.Errorf("failed to set META key 0x%x", key)
+	}
+
+	if err = meta.Write(); err != nil {
+		return err
+	}
+
+	log.Printf("META key 0x%x updated", key)
+
+	if key != adv.ExtraKernelArgs {
+		return nil
+	}
+
+	return grub.WriteCmdlineExtra(grub.CmdlineExtra, value)
+}
@@ -0,0 +1,102 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/talos-systems/talos/internal/pkg/schedule"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+)
+
+// scheduleAction delays the action according to the schedule.
+//
+// If the action is due already, it is not scheduled and the caller should perform it
+// immediately. Otherwise run is called once the action is due.
+func (s *Server) scheduleAction(name string, in *machine.ActionSchedule, run func(context.Context) error) (*schedule.Action, error) {
+	now := time.Now()
+
+	runAt, err := scheduledRunAt(now, in)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runAt.After(now) {
+		return nil, nil
+	}
+
+	action := s.scheduler.Schedule(name, runAt, func() {
+		log.Printf("running scheduled %s", name)
+
+		if err := run(context.Background()); err != nil {
+			log.Printf("scheduled %s failed: %s", name, err)
+		}
+	})
+
+	log.Printf("%s scheduled at %s", name, action.RunAt)
+
+	return &action, nil
+}
+
+// scheduledRunAt returns the time the action with the schedule is due at.
+func scheduledRunAt(now time.Time, in *machine.ActionSchedule) (time.Time, error) {
+	if in == nil {
+		return now, nil
+	}
+
+	var (
+		notBefore time.Time
+		window    *schedule.Window
+		err       error
+	)
+
+	if in.GetNotBefore() != nil {
+		if notBefore, err = ptypes.Timestamp(in.GetNotBefore()); err != nil {
+			return time.Time{}, status.Errorf(codes.InvalidArgument, "invalid not before timestamp: %s", err)
+		}
+	}
+
+	if in.GetMaintenanceWindow() != nil {
+		if window, err = schedule.ParseWindow(in.GetMaintenanceWindow().GetStart(), in.GetMaintenanceWindow().GetDuration()); err != nil {
+			return time.Time{}, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	return schedule.Next(now, notBefore, window), nil
+}
+
+// PendingActions implements the machine.MachineServer interface.
+func (s *Server) PendingActions(ctx context.Context, in *empty.Empty) (*machine.PendingActionsResponse, error) {
+	pending := s.scheduler.Pending()
+
+	reply := &machine.PendingActionsResponse{
+		Messages: []*machine.PendingActions{
+			{
+				Actions: make([]*machine.PendingAction, 0, len(pending)),
+			},
+		},
+	}
+
+	for _, action := range pending {
+		createdAt, _ := ptypes.TimestampProto(action.CreatedAt) //nolint: errcheck
+		runAt, _ := ptypes.TimestampProto(action.RunAt)         //nolint: errcheck
+
+		reply.Messages[0].Actions = append(reply.Messages[0].Actions, &machine.PendingAction{
+			Id:        action.ID,
+			Action:    action.Name,
+			CreatedAt: createdAt,
+			RunAt:     runAt,
+		})
+	}
+
+	return reply, nil
+}
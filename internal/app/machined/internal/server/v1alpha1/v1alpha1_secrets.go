@@ -0,0 +1,203 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/talos-systems/talos/pkg/grpc/middleware/authz"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/config/redact"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// MachineConfigRead implements the machine.MachineServer interface.
+func (s *Server) MachineConfigRead(ctx context.Context, in *machine.MachineConfigReadRequest) (*machine.MachineConfigReadResponse, error) {
+	data, err := s.Controller.Runtime().Config().Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	redacted := in.GetRedacted() || !isAdmin(ctx)
+
+	if redacted {
+		if data, err = redact.Redact(data); err != nil {
+			return nil, err
+		}
+	}
+
+	return &machine.MachineConfigReadResponse{
+		Messages: []*machine.MachineConfigRead{
+			{
+				Data:     data,
+				Redacted: redacted,
+			},
+		},
+	}, nil
+}
+
+// isAdmin checks whether the client has access to the secrets.
+//
+// Client roles are passed by apid in the metadata.
+func isAdmin(ctx context.Context) bool {
+	return authz.IsAdmin(authz.RolesFromMetadata(ctx))
+}
+
+// requireAdmin denies access to the methods which return secrets for the clients without the admin role.
+func requireAdmin(ctx context.Context) error {
+	if isAdmin(ctx) {
+		return nil
+	}
+
+	return status.Errorf(codes.PermissionDenied, "access requires %s role", constants.RoleAdmin)
+}
+
+// secretPaths are the paths with the secrets: the STATE partition with the machine configuration,
+// Kubernetes and etcd PKI, kubeconfigs and etcd data.
+var secretPaths = []string{
+	constants.StateMountPoint,
+	constants.DefaultCertificatesDir,
+	constants.KubernetesControlPlaneSecretsDir,
+	constants.AssetsDirectory,
+	constants.EncryptionConfigPath,
+	constants.RecoveryKubeconfig,
+	constants.KubeletKubeconfig,
+	constants.KubeletBootstrapKubeconfig,
+	constants.KubeletPKIDir,
+	constants.EtcdDataPath,
+}
+
+// secretProcPatterns match the process environment and memory, which might contain the secrets.
+var secretProcPatterns = []string{
+	"/proc/kcore",
+	"/proc/*/environ",
+	"/proc/*/mem",
+	"/proc/*/task/*/environ",
+	"/proc/*/task/*/mem",
+}
+
+// maxSymlinks limits the number of symlinks followed while resolving the path.
+const maxSymlinks = 255
+
+// resolvePath makes the path absolute and resolves the symlinks in it the same way the kernel does.
+//
+// Unlike filepath.EvalSymlinks, the path doesn't have to exist, and `..` is applied after resolving
+// the symlink preceding it.
+func resolvePath(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+
+		path = wd + "/" + path
+	}
+
+	resolved := "/"
+	components := strings.Split(path, "/")
+	links := 0
+
+	for len(components) > 0 {
+		component := components[0]
+		components = components[1:]
+
+		switch component {
+		case "", ".":
+			continue
+		case "..":
+			resolved = filepath.Dir(resolved)
+
+			continue
+		}
+
+		next := filepath.Join(resolved, component)
+
+		target, err := os.Readlink(next)
+		if err != nil {
+			// not a symlink or doesn't exist
+			resolved = next
+
+			continue
+		}
+
+		links++
+
+		if links > maxSymlinks {
+			return "", fmt.Errorf("too many levels of symbolic links in %q", path)
+		}
+
+		if filepath.IsAbs(target) {
+			resolved = "/"
+		}
+
+		components = append(strings.Split(target, "/"), components...)
+	}
+
+	return resolved, nil
+}
+
+func isSecretPath(path string) bool {
+	for _, secretPath := range secretPaths {
+		if path == secretPath || strings.HasPrefix(path, secretPath+"/") {
+			return true
+		}
+	}
+
+	for _, pattern := range secretProcPatterns {
+		if matched, _ := filepath.Match(pattern, path); matched { //nolint: errcheck
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsSecretPath checks whether the directory contains any of the secret paths.
+func containsSecretPath(path string) bool {
+	if path == "/" {
+		return true
+	}
+
+	for _, secretPath := range secretPaths {
+		if strings.HasPrefix(secretPath, path+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkSecretPath denies access to the paths with the secrets and to the raw devices the partitions
+// can be read from for the clients without the admin role.
+//
+// If recursive is set, the directories containing the secret paths are denied as well.
+func checkSecretPath(ctx context.Context, path string, recursive bool) error {
+	if isAdmin(ctx) {
+		return nil
+	}
+
+	// resolve the symlinks, so that the path can't be accessed via a link (e.g. /proc/1/root)
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if isSecretPath(resolved) || (recursive && containsSecretPath(resolved)) {
+		return status.Errorf(codes.PermissionDenied, "access to %q requires %s role", path, constants.RoleAdmin)
+	}
+
+	if info, err := os.Stat(resolved); err == nil && info.Mode()&os.ModeDevice != 0 {
+		return status.Errorf(codes.PermissionDenied, "access to device %q requires %s role", path, constants.RoleAdmin)
+	}
+
+	return nil
+}
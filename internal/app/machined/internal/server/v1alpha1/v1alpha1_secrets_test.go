@@ -0,0 +1,113 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+func roleContext(roles ...string) context.Context {
+	md := metadata.MD{}
+	md.Append(constants.APIAuthzRoleMetadataKey, roles...)
+
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func TestCheckSecretPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	readerCtx := roleContext("os:reader")
+	adminCtx := roleContext(constants.RoleAdmin)
+
+	for _, secretPath := range []string{
+		constants.StateMountPoint,
+		constants.StateMountPoint + "/config.yaml",
+		constants.DefaultCertificatesDir,
+		constants.DefaultCertificatesDir + "/ca.key",
+		constants.EtcdPKIPath + "/ca.key",
+		constants.KubernetesControlPlaneSecretsDir,
+		constants.KubernetesControlPlaneSecretsDir + "/kube-apiserver/service-account.key",
+		constants.AssetsDirectory,
+		constants.EncryptionConfigPath,
+		constants.RecoveryKubeconfig,
+		constants.KubeletKubeconfig,
+		constants.KubeletBootstrapKubeconfig,
+		constants.KubeletPKIDir,
+		constants.KubeletPKIDir + "/kubelet.key",
+		constants.EtcdDataPath,
+		constants.EtcdDataPath + "/member/snap/db",
+		"/proc/1/environ",
+		"/proc/self/mem",
+		"/proc/1/task/1/environ",
+		"/proc/kcore",
+	} {
+		secretPath := secretPath
+
+		t.Run(secretPath, func(t *testing.T) {
+			for _, recursive := range []bool{false, true} {
+				assert.Equal(t, codes.PermissionDenied, status.Code(checkSecretPath(readerCtx, secretPath, recursive)))
+				assert.NoError(t, checkSecretPath(adminCtx, secretPath, recursive))
+			}
+
+			// the path is resolved the way the kernel does it
+			assert.Equal(t, codes.PermissionDenied, status.Code(checkSecretPath(readerCtx, "/var/../"+secretPath, false)))
+
+			link := filepath.Join(dir, filepath.Base(secretPath))
+			require.NoError(t, os.RemoveAll(link))
+			require.NoError(t, os.Symlink(secretPath, link))
+
+			// symlinks pointing to the secrets are denied even if the target doesn't exist
+			assert.Equal(t, codes.PermissionDenied, status.Code(checkSecretPath(readerCtx, link, false)))
+			assert.Equal(t, codes.PermissionDenied, status.Code(checkSecretPath(readerCtx, link+"/../"+filepath.Base(secretPath), false)))
+
+			parentLink := filepath.Join(dir, "parent")
+			require.NoError(t, os.RemoveAll(parentLink))
+			require.NoError(t, os.Symlink(filepath.Dir(secretPath), parentLink))
+
+			assert.Equal(t, codes.PermissionDenied, status.Code(checkSecretPath(readerCtx, filepath.Join(parentLink, filepath.Base(secretPath)), false)))
+		})
+	}
+
+	// `..` is applied after resolving the symlink
+	nested := filepath.Join(dir, "nested")
+	require.NoError(t, os.Symlink(constants.DefaultCertificatesDir+"/etcd", nested))
+	assert.Equal(t, codes.PermissionDenied, status.Code(checkSecretPath(readerCtx, nested+"/../ca.key", false)))
+
+	loop := filepath.Join(dir, "loop")
+	require.NoError(t, os.Symlink(loop, loop))
+	assert.Equal(t, codes.InvalidArgument, status.Code(checkSecretPath(readerCtx, loop, false)))
+
+	for _, path := range []string{
+		"/var/log/machined.log",
+		"/etc/kubernetes/manifests",
+		"/proc/1/status",
+		dir,
+	} {
+		assert.NoError(t, checkSecretPath(readerCtx, path, false), path)
+	}
+
+	// directories containing the secrets can't be copied
+	for _, path := range []string{"/", "/etc", "/etc/kubernetes", "/system", "/var/lib"} {
+		assert.NoError(t, checkSecretPath(readerCtx, path, false), path)
+		assert.Equal(t, codes.PermissionDenied, status.Code(checkSecretPath(readerCtx, path, true)), path)
+	}
+
+	assert.NoError(t, checkSecretPath(readerCtx, "/var/log", true))
+}
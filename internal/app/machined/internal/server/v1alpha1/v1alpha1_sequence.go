@@ -0,0 +1,50 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"log"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+)
+
+// SequencePause implements the machine.MachineServer interface.
+//
+// Running sequence is paused before its next phase, sequences started later are paused before the first phase.
+func (s *Server) SequencePause(ctx context.Context, in *empty.Empty) (*machine.SequencePauseResponse, error) {
+	paused := s.Controller.Pause()
+
+	if paused {
+		log.Printf("sequences paused")
+	}
+
+	return &machine.SequencePauseResponse{
+		Messages: []*machine.SequencePause{
+			{
+				Paused: paused,
+			},
+		},
+	}, nil
+}
+
+// SequenceResume implements the machine.MachineServer interface.
+func (s *Server) SequenceResume(ctx context.Context, in *empty.Empty) (*machine.SequenceResumeResponse, error) {
+	resumed := s.Controller.Resume()
+
+	if resumed {
+		log.Printf("sequences resumed")
+	}
+
+	return &machine.SequenceResumeResponse{
+		Messages: []*machine.SequenceResume{
+			{
+				Resumed: resumed,
+			},
+		},
+	}, nil
+}
@@ -14,10 +14,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -27,22 +28,29 @@ import (
 	"github.com/containerd/containerd/namespaces"
 	"github.com/containerd/containerd/oci"
 	criconstants "github.com/containerd/cri/pkg/constants"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/prometheus/procfs"
 	"github.com/rs/xid"
+	"github.com/talos-systems/go-blockdevice/blockdevice"
 	"github.com/talos-systems/go-blockdevice/blockdevice/partition/gpt"
+	"github.com/talos-systems/go-smbios/smbios"
 	"go.etcd.io/etcd/clientv3/concurrency"
 	"golang.org/x/sys/unix"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	installer "github.com/talos-systems/talos/cmd/installer/pkg/install"
 	"github.com/talos-systems/talos/internal/app/machined/internal/install"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/adv"
-	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/grub"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system"
+	storaged "github.com/talos-systems/talos/internal/app/storaged"
+	"github.com/talos-systems/talos/internal/pkg/configstore"
 	"github.com/talos-systems/talos/internal/pkg/configuration"
 	"github.com/talos-systems/talos/internal/pkg/containers"
 	taloscontainerd "github.com/talos-systems/talos/internal/pkg/containers/containerd"
@@ -52,13 +60,17 @@ import (
 	"github.com/talos-systems/talos/internal/pkg/kmsg"
 	"github.com/talos-systems/talos/internal/pkg/kubeconfig"
 	"github.com/talos-systems/talos/internal/pkg/mount"
+	"github.com/talos-systems/talos/internal/pkg/schedule"
+	"github.com/talos-systems/talos/internal/pkg/secureboot"
 	"github.com/talos-systems/talos/pkg/archiver"
 	"github.com/talos-systems/talos/pkg/chunker"
 	"github.com/talos-systems/talos/pkg/chunker/stream"
 	"github.com/talos-systems/talos/pkg/machinery/api/cluster"
 	"github.com/talos-systems/talos/pkg/machinery/api/common"
 	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/api/storage"
 	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/config/redact"
 	machinetype "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 	"github.com/talos-systems/talos/pkg/version"
@@ -77,7 +89,8 @@ const OSPathSeparator = string(os.PathSeparator)
 type Server struct {
 	Controller runtime.Controller
 
-	server *grpc.Server
+	server    *grpc.Server
+	scheduler schedule.Scheduler
 }
 
 func (s *Server) checkSupported(feature runtime.ModeCapability) error {
@@ -96,10 +109,26 @@ func (s *Server) Register(obj *grpc.Server) {
 
 	machine.RegisterMachineServiceServer(obj, s)
 	cluster.RegisterClusterServiceServer(obj, s)
+	storage.RegisterStorageServiceServer(obj, &storaged.Server{})
 }
 
 // ApplyConfiguration implements machine.MachineService.
 func (s *Server) ApplyConfiguration(ctx context.Context, in *machine.ApplyConfigurationRequest) (reply *machine.ApplyConfigurationResponse, err error) {
+	if in.GetDryRun() {
+		dryRun, err := s.applyConfigurationDryRun(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+
+		return &machine.ApplyConfigurationResponse{
+			Messages: []*machine.ApplyConfiguration{
+				{
+					DryRun: dryRun,
+				},
+			},
+		}, nil
+	}
+
 	if !in.NoReboot {
 		if err = s.Controller.Runtime().SetConfig(in.GetData()); err != nil {
 			return nil, err
@@ -125,20 +154,92 @@ func (s *Server) ApplyConfiguration(ctx context.Context, in *machine.ApplyConfig
 			return nil, err
 		}
 
-		var b []byte
+		if err = configstore.Default.Store(ctx, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	reply = &machine.ApplyConfigurationResponse{
+		Messages: []*machine.ApplyConfiguration{
+			{},
+		},
+	}
+
+	return reply, nil
+}
+
+// ConfigHistory implements the machine.MachineServer interface.
+func (s *Server) ConfigHistory(ctx context.Context, in *empty.Empty) (*machine.ConfigHistoryResponse, error) {
+	history := configstore.Default.History
+
+	admin := isAdmin(ctx)
+
+	versions, err := history.List()
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &machine.ConfigHistoryResponse{
+		Messages: []*machine.ConfigHistory{
+			{},
+		},
+	}
 
-		b, err = cfg.Bytes()
+	for _, version := range versions {
+		data, err := history.Get(version.Number)
 		if err != nil {
+			if os.IsNotExist(err) {
+				// version was pruned concurrently
+				continue
+			}
+
 			return nil, err
 		}
 
-		if err = ioutil.WriteFile(constants.ConfigPath, b, 0o600); err != nil {
+		if !admin {
+			if data, err = redact.Redact(data); err != nil {
+				return nil, err
+			}
+		}
+
+		timestamp, err := ptypes.TimestampProto(version.Timestamp)
+		if err != nil {
 			return nil, err
 		}
+
+		reply.Messages[0].Versions = append(reply.Messages[0].Versions, &machine.ConfigVersion{
+			Version:   version.Number,
+			Timestamp: timestamp,
+			Data:      data,
+		})
+	}
+
+	return reply, nil
+}
+
+// ConfigRollback implements the machine.MachineServer interface.
+//
+// Configuration version from the history is applied the same way as the new configuration,
+// so the rollback is recorded as the latest version.
+func (s *Server) ConfigRollback(ctx context.Context, in *machine.ConfigRollbackRequest) (*machine.ConfigRollbackResponse, error) {
+	data, err := configstore.Default.History.Get(in.GetVersion())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, status.Errorf(codes.NotFound, "config version %d is not found in the history", in.GetVersion())
+		}
+
+		return nil, err
 	}
 
-	reply = &machine.ApplyConfigurationResponse{
-		Messages: []*machine.ApplyConfiguration{
+	if _, err = s.ApplyConfiguration(ctx, &machine.ApplyConfigurationRequest{
+		Data:     data,
+		NoReboot: in.GetNoReboot(),
+	}); err != nil {
+		return nil, err
+	}
+
+	reply := &machine.ConfigRollbackResponse{
+		Messages: []*machine.ConfigRollback{
 			{},
 		},
 	}
@@ -149,19 +250,41 @@ func (s *Server) ApplyConfiguration(ctx context.Context, in *machine.ApplyConfig
 // GenerateConfiguration implements the machine.MachineServer interface.
 // nolint:gocyclo
 func (s *Server) GenerateConfiguration(ctx context.Context, in *machine.GenerateConfigurationRequest) (reply *machine.GenerateConfigurationResponse, err error) {
+	if err = requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
 	return configuration.Generate(ctx, in)
 }
 
 // Reboot implements the machine.MachineServer interface.
 //
 // nolint: dupl
-func (s *Server) Reboot(ctx context.Context, in *empty.Empty) (reply *machine.RebootResponse, err error) {
+func (s *Server) Reboot(ctx context.Context, in *machine.RebootRequest) (reply *machine.RebootResponse, err error) {
 	log.Printf("reboot via API received")
 
-	if err := s.checkSupported(runtime.Reboot); err != nil {
+	if err = s.checkSupported(runtime.Reboot); err != nil {
 		return nil, err
 	}
 
+	var action *schedule.Action
+
+	if action, err = s.scheduleAction("reboot", in.GetSchedule(), func(ctx context.Context) error {
+		_, err := s.Reboot(ctx, &machine.RebootRequest{Force: in.GetForce()})
+
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if action != nil {
+		return &machine.RebootResponse{
+			Messages: []*machine.Reboot{
+				{},
+			},
+		}, nil
+	}
+
 	go func() {
 		if err := s.Controller.Run(runtime.SequenceReboot, in); err != nil {
 			log.Println("reboot failed:", err)
@@ -194,21 +317,26 @@ func (s *Server) Rollback(ctx context.Context, in *machine.RollbackRequest) (*ma
 	}
 
 	if err := func() error {
-		if err := mount.SystemPartitionMount(constants.BootPartitionLabel); err != nil {
-			return fmt.Errorf("error mounting boot partition: %w", err)
-		}
+		for _, label := range []string{constants.BootPartitionLabel, constants.EFIPartitionLabel} {
+			label := label
 
-		defer func() {
-			if err := mount.SystemPartitionUnmount(constants.BootPartitionLabel); err != nil {
-				log.Printf("failed unmounting boot partition: %s", err)
+			if err := mount.SystemPartitionMount(label); err != nil {
+				return fmt.Errorf("error mounting %s partition: %w", label, err)
 			}
-		}()
 
-		grub := &grub.Grub{
-			BootDisk: s.Controller.Runtime().Config().Machine().Install().Disk(),
+			defer func() {
+				if err := mount.SystemPartitionUnmount(label); err != nil {
+					log.Printf("failed unmounting %s partition: %s", label, err)
+				}
+			}()
 		}
 
-		_, next, err := grub.Labels()
+		b, err := bootloader.New(bootloader.Probe())
+		if err != nil {
+			return err
+		}
+
+		_, next, err := b.Labels()
 		if err != nil {
 			return err
 		}
@@ -217,7 +345,7 @@ func (s *Server) Rollback(ctx context.Context, in *machine.RollbackRequest) (*ma
 			return fmt.Errorf("cannot rollback to %q, label does not exist", next)
 		}
 
-		if err := grub.Default(next); err != nil {
+		if err := b.Default(next); err != nil {
 			return fmt.Errorf("failed to revert bootloader: %v", err)
 		}
 
@@ -279,7 +407,7 @@ func (s *Server) Bootstrap(ctx context.Context, in *machine.BootstrapRequest) (r
 // Shutdown implements the machine.MachineServer interface.
 //
 // nolint: dupl
-func (s *Server) Shutdown(ctx context.Context, in *empty.Empty) (reply *machine.ShutdownResponse, err error) {
+func (s *Server) Shutdown(ctx context.Context, in *machine.ShutdownRequest) (reply *machine.ShutdownResponse, err error) {
 	log.Printf("shutdown via API received")
 
 	if err = s.checkSupported(runtime.Shutdown); err != nil {
@@ -321,10 +449,51 @@ func (s *Server) Upgrade(ctx context.Context, in *machine.UpgradeRequest) (reply
 
 	log.Printf("validating %q", in.GetImage())
 
-	if err = pullAndValidateInstallerImage(ctx, s.Controller.Runtime().Config().Machine().Registries(), in.GetImage()); err != nil {
+	if err = pullAndValidateInstallerImage(ctx, s.Controller.Runtime().Config().Machine().Registries(), s.Controller.Runtime().Config().Machine().Features().ImageVerification(), in.GetImage()); err != nil {
 		return nil, fmt.Errorf("error validating installer image %q: %w", in.GetImage(), err)
 	}
 
+	if in.GetDryRun() {
+		dryRun, err := s.upgradeDryRun(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+
+		return &machine.UpgradeResponse{
+			Messages: []*machine.Upgrade{
+				{
+					Ack:    "Upgrade dry run completed",
+					DryRun: dryRun,
+				},
+			},
+		}, nil
+	}
+
+	var action *schedule.Action
+
+	if action, err = s.scheduleAction("upgrade", in.GetSchedule(), func(ctx context.Context) error {
+		_, err := s.Upgrade(ctx, &machine.UpgradeRequest{
+			Image:    in.GetImage(),
+			Preserve: in.GetPreserve(),
+			Stage:    in.GetStage(),
+			Force:    in.GetForce(),
+		})
+
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if action != nil {
+		return &machine.UpgradeResponse{
+			Messages: []*machine.Upgrade{
+				{
+					Ack: fmt.Sprintf("Upgrade scheduled at %s", action.RunAt.Format(time.RFC3339)),
+				},
+			},
+		}, nil
+	}
+
 	if s.Controller.Runtime().Config().Machine().Type() != machinetype.TypeJoin {
 		client, err := etcd.NewClientFromControlPlaneIPs(ctx, s.Controller.Runtime().Config().Cluster().CA(), s.Controller.Runtime().Config().Cluster().Endpoint())
 		if err != nil {
@@ -351,17 +520,6 @@ func (s *Server) Upgrade(ctx context.Context, in *machine.UpgradeRequest) (reply
 	}
 
 	if in.GetStage() {
-		meta, err := bootloader.NewMeta()
-		if err != nil {
-			return nil, fmt.Errorf("error reading meta: %w", err)
-		}
-		// nolint: errcheck
-		defer meta.Close()
-
-		if !meta.ADV.SetTag(adv.StagedUpgradeImageRef, in.GetImage()) {
-			return nil, fmt.Errorf("error adding staged upgrade image ref tag")
-		}
-
 		opts := install.DefaultInstallOptions()
 		if err = opts.Apply(install.OptionsFromUpgradeRequest(s.Controller.Runtime(), in)...); err != nil {
 			return nil, fmt.Errorf("error applying install options: %w", err)
@@ -372,12 +530,8 @@ func (s *Server) Upgrade(ctx context.Context, in *machine.UpgradeRequest) (reply
 			return nil, fmt.Errorf("error serializing install options: %s", err)
 		}
 
-		if !meta.ADV.SetTag(adv.StagedUpgradeInstallOptions, string(serialized)) {
-			return nil, fmt.Errorf("error adding staged upgrade install options tag")
-		}
-
-		if err = meta.Write(); err != nil {
-			return nil, fmt.Errorf("error writing meta: %w", err)
+		if err = stageUpgrade(in.GetImage(), serialized); err != nil {
+			return nil, err
 		}
 
 		go func() {
@@ -424,6 +578,30 @@ func (s *Server) Upgrade(ctx context.Context, in *machine.UpgradeRequest) (reply
 	return reply, nil
 }
 
+// stageUpgrade records the staged upgrade image and install options in META.
+func stageUpgrade(image string, installOptions []byte) error {
+	meta, err := bootloader.NewMeta()
+	if err != nil {
+		return fmt.Errorf("error reading meta: %w", err)
+	}
+	// nolint: errcheck
+	defer meta.Close()
+
+	if !meta.ADV.SetTag(adv.StagedUpgradeImageRef, image) {
+		return fmt.Errorf("error adding staged upgrade image ref tag")
+	}
+
+	if !meta.ADV.SetTag(adv.StagedUpgradeInstallOptions, string(installOptions)) {
+		return fmt.Errorf("error adding staged upgrade install options tag")
+	}
+
+	if err = meta.Write(); err != nil {
+		return fmt.Errorf("error writing meta: %w", err)
+	}
+
+	return nil
+}
+
 // ResetOptions implements runtime.ResetOptions interface.
 type ResetOptions struct {
 	*machine.ResetRequest
@@ -452,6 +630,36 @@ func (opt *ResetOptions) GetSystemDiskTargets() []runtime.PartitionTarget {
 func (s *Server) Reset(ctx context.Context, in *machine.ResetRequest) (reply *machine.ResetResponse, err error) {
 	log.Printf("reset request received")
 
+	if in.GetMaintenance() {
+		if err = validateResetToMaintenance(in); err != nil {
+			return nil, err
+		}
+	}
+
+	var action *schedule.Action
+
+	if action, err = s.scheduleAction("reset", in.GetSchedule(), func(ctx context.Context) error {
+		_, err := s.Reset(ctx, &machine.ResetRequest{
+			Graceful:               in.GetGraceful(),
+			Reboot:                 in.GetReboot(),
+			SystemPartitionsToWipe: in.GetSystemPartitionsToWipe(),
+			WipeMode:               in.GetWipeMode(),
+			Maintenance:            in.GetMaintenance(),
+		})
+
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if action != nil {
+		return &machine.ResetResponse{
+			Messages: []*machine.Reset{
+				{},
+			},
+		}, nil
+	}
+
 	opts := ResetOptions{
 		ResetRequest: in,
 	}
@@ -469,6 +677,8 @@ func (s *Server) Reset(ctx context.Context, in *machine.ResetRequest) (reply *ma
 		for _, spec := range in.GetSystemPartitionsToWipe() {
 			var target *installer.Target
 
+			targetPT := pt
+
 			switch spec.Label {
 			case constants.EFIPartitionLabel:
 				target = installer.EFITarget(bd.Device().Name(), nil)
@@ -481,12 +691,23 @@ func (s *Server) Reset(ctx context.Context, in *machine.ResetRequest) (reply *ma
 			case constants.StatePartitionLabel:
 				target = installer.StateTarget(bd.Device().Name(), nil)
 			case constants.EphemeralPartitionLabel:
-				target = installer.EphemeralTarget(bd.Device().Name(), nil)
+				disk := s.Controller.Runtime().Config().Machine().Install().EphemeralDisk()
+				if disk == "" {
+					disk = bd.Device().Name()
+				}
+
+				target = installer.EphemeralTarget(disk, nil)
+
+				if disk != bd.Device().Name() {
+					if targetPT, err = readPartitionTable(disk); err != nil {
+						return nil, fmt.Errorf("error reading partition table: %w", err)
+					}
+				}
 			default:
 				return nil, fmt.Errorf("label %q is not supported", spec.Label)
 			}
 
-			_, err = target.Locate(pt)
+			_, err = target.Locate(targetPT)
 			if err != nil {
 				return nil, fmt.Errorf("failed location partition with label %q: %w", spec.Label, err)
 			}
@@ -518,6 +739,101 @@ func (s *Server) Reset(ctx context.Context, in *machine.ResetRequest) (reply *ma
 	return reply, nil
 }
 
+// ResetStream resets the node and streams the events of the reset sequence.
+//
+// The stream finishes when the reset sequence fails, otherwise the connection is closed
+// once the node shuts down or reboots.
+func (s *Server) ResetStream(in *machine.ResetRequest, srv machine.MachineService_ResetStreamServer) error {
+	if in.GetSchedule() != nil {
+		return status.Error(codes.InvalidArgument, "scheduled reset can't be streamed")
+	}
+
+	ctx, cancel := context.WithCancel(srv.Context())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+
+	if err := s.Controller.Runtime().Events().Watch(func(events <-chan runtime.Event) {
+		errCh <- func() error {
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case event, ok := <-events:
+					if !ok {
+						return nil
+					}
+
+					if !isResetEvent(event) {
+						continue
+					}
+
+					msg, err := event.ToMachineEvent()
+					if err != nil {
+						return err
+					}
+
+					if err = srv.Send(msg); err != nil {
+						return err
+					}
+
+					if sequenceEvent, ok := event.Payload.(*machine.SequenceEvent); ok && sequenceEvent.GetError() != nil {
+						return nil
+					}
+				}
+			}
+		}()
+	}); err != nil {
+		return err
+	}
+
+	if _, err := s.Reset(ctx, in); err != nil {
+		cancel()
+
+		<-errCh
+
+		return err
+	}
+
+	return <-errCh
+}
+
+func isResetEvent(event runtime.Event) bool {
+	sequence := runtime.SequenceReset.String()
+
+	switch payload := event.Payload.(type) {
+	case *machine.SequenceEvent:
+		return payload.GetSequence() == sequence
+	case *machine.PhaseEvent:
+		return payload.GetSequence() == sequence
+	case *machine.TaskEvent:
+		return payload.GetSequence() == sequence
+	default:
+		return false
+	}
+}
+
+// validateResetToMaintenance checks that the node can boot into the maintenance mode after the reset.
+//
+// The maintenance mode request is stored in the META partition, so the partition should be preserved.
+func validateResetToMaintenance(in *machine.ResetRequest) error {
+	if !in.GetReboot() {
+		return status.Error(codes.InvalidArgument, "reset to maintenance mode requires reboot")
+	}
+
+	if len(in.GetSystemPartitionsToWipe()) == 0 {
+		return status.Error(codes.InvalidArgument, "reset to maintenance mode requires the list of system partitions to wipe")
+	}
+
+	for _, spec := range in.GetSystemPartitionsToWipe() {
+		if spec.GetLabel() == constants.MetaPartitionLabel && spec.GetWipe() {
+			return status.Errorf(codes.InvalidArgument, "reset to maintenance mode requires %s partition to be preserved", constants.MetaPartitionLabel)
+		}
+	}
+
+	return nil
+}
+
 // Recover recovers the control plane.
 //
 // nolint: dupl
@@ -549,6 +865,29 @@ func (s *Server) Recover(ctx context.Context, in *machine.RecoverRequest) (reply
 	return reply, nil
 }
 
+// Grow implements the machine.MachineServer interface.
+//
+// Grow resizes the EPHEMERAL partition and the filesystem to take the space added to the disk.
+func (s *Server) Grow(ctx context.Context, in *empty.Empty) (reply *machine.GrowResponse, err error) {
+	log.Printf("grow request received")
+
+	if err = s.checkSupported(runtime.Grow); err != nil {
+		return nil, err
+	}
+
+	if err = s.Controller.Run(runtime.SequenceGrow, in); err != nil {
+		return nil, err
+	}
+
+	reply = &machine.GrowResponse{
+		Messages: []*machine.Grow{
+			{},
+		},
+	}
+
+	return reply, nil
+}
+
 // ServiceList returns list of the registered services and their status.
 func (s *Server) ServiceList(ctx context.Context, in *empty.Empty) (result *machine.ServiceListResponse, err error) {
 	services := system.Services(s.Controller.Runtime()).List()
@@ -622,6 +961,24 @@ func (s *Server) ServiceRestart(ctx context.Context, in *machine.ServiceRestartR
 	return reply, err
 }
 
+// ServiceReload implements the machine.MachineServer interface and reloads
+// configuration of a service running on Talos.
+func (s *Server) ServiceReload(ctx context.Context, in *machine.ServiceReloadRequest) (reply *machine.ServiceReloadResponse, err error) {
+	if err = system.Services(s.Controller.Runtime()).APIReload(ctx, in.Id); err != nil {
+		return &machine.ServiceReloadResponse{}, err
+	}
+
+	reply = &machine.ServiceReloadResponse{
+		Messages: []*machine.ServiceReload{
+			{
+				Resp: fmt.Sprintf("Service %q reloaded", in.Id),
+			},
+		},
+	}
+
+	return reply, err
+}
+
 // Copy implements the machine.MachineServer interface and copies data out of Talos node.
 func (s *Server) Copy(req *machine.CopyRequest, obj machine.MachineService_CopyServer) error {
 	path := req.RootPath
@@ -631,6 +988,10 @@ func (s *Server) Copy(req *machine.CopyRequest, obj machine.MachineService_CopyS
 		return fmt.Errorf("path is not absolute %v", path)
 	}
 
+	if err := checkSecretPath(obj.Context(), path, true); err != nil {
+		return err
+	}
+
 	pr, pw := io.Pipe()
 
 	errCh := make(chan error, 1)
@@ -652,6 +1013,8 @@ func (s *Server) Copy(req *machine.CopyRequest, obj machine.MachineService_CopyS
 		if err != nil {
 			ctxCancel()
 		}
+
+		stream.Release(data)
 	}
 
 	archiveErr := <-errCh
@@ -684,6 +1047,18 @@ func (s *Server) List(req *machine.ListRequest, obj machine.MachineService_ListS
 		req.Root = "/"
 	}
 
+	if err := checkSecretPath(obj.Context(), req.Root, false); err != nil {
+		return err
+	}
+
+	// contents of the secret paths are skipped while listing the parent directories
+	admin := isAdmin(obj.Context())
+
+	resolvedRoot, err := resolvePath(req.Root)
+	if err != nil {
+		return err
+	}
+
 	var opts []archiver.WalkerOption
 
 	if req.Recurse {
@@ -717,6 +1092,10 @@ func (s *Server) List(req *machine.ListRequest, obj machine.MachineService_ListS
 	}
 
 	for fi := range files {
+		if !admin && isSecretPath(filepath.Join(resolvedRoot, fi.RelPath)) {
+			continue
+		}
+
 		if fi.Error != nil {
 			err = obj.Send(&machine.FileInfo{
 				Name:         fi.FullPath,
@@ -944,12 +1323,14 @@ func (s *Server) Mounts(ctx context.Context, in *empty.Empty) (reply *machine.Mo
 	for scanner.Scan() {
 		fields := strings.Fields(scanner.Text())
 
-		if len(fields) < 2 {
+		if len(fields) < 4 {
 			continue
 		}
 
 		filesystem := fields[0]
 		mountpoint := fields[1]
+		fstype := fields[2]
+		options := fields[3]
 
 		f, err := os.Stat(mountpoint)
 		if err != nil {
@@ -970,12 +1351,16 @@ func (s *Server) Mounts(ctx context.Context, in *empty.Empty) (reply *machine.Mo
 
 		totalSize := uint64(stat.Bsize) * stat.Blocks
 		totalAvail := uint64(stat.Bsize) * stat.Bavail
+		totalUsed := uint64(stat.Bsize) * (stat.Blocks - stat.Bfree)
 
 		stat := &machine.MountStat{
 			Filesystem: filesystem,
 			Size:       totalSize,
 			Available:  totalAvail,
+			Used:       totalUsed,
 			MountedOn:  mountpoint,
+			Type:       fstype,
+			Options:    options,
 		}
 
 		stats = append(stats, stat)
@@ -998,27 +1383,87 @@ func (s *Server) Mounts(ctx context.Context, in *empty.Empty) (reply *machine.Mo
 
 // Version implements the machine.MachineServer interface.
 func (s *Server) Version(ctx context.Context, in *empty.Empty) (reply *machine.VersionResponse, err error) {
-	var platform *machine.PlatformInfo
-
-	if s.Controller.Runtime().State().Platform() != nil {
-		platform = &machine.PlatformInfo{
-			Name: s.Controller.Runtime().State().Platform().Name(),
-			Mode: s.Controller.Runtime().State().Platform().Mode().String(),
-		}
-	}
-
 	return &machine.VersionResponse{
 		Messages: []*machine.Version{
 			{
 				Version:  version.NewVersion(),
-				Platform: platform,
+				Platform: s.platformInfo(),
 			},
 		},
 	}, nil
 }
 
+// SystemInfo implements the machine.MachineServer interface.
+//
+// Hardware information is collected on the best-effort basis, as SMBIOS and EFI variables
+// might be unavailable (e.g. in container mode).
+func (s *Server) SystemInfo(ctx context.Context, in *empty.Empty) (reply *machine.SystemInfoResponse, err error) {
+	info := &machine.SystemInfo{
+		Platform: s.platformInfo(),
+		Version:  version.NewVersion(),
+	}
+
+	if info.Hostname, err = os.Hostname(); err != nil {
+		return nil, err
+	}
+
+	var uname unix.Utsname
+
+	if err = unix.Uname(&uname); err != nil {
+		return nil, err
+	}
+
+	info.KernelVersion = unix.ByteSliceToString(uname.Release[:])
+
+	if smbiosInfo, e := smbios.New(); e == nil {
+		systemInformation := smbiosInfo.SystemInformation()
+
+		if uuid, e := systemInformation.UUID(); e == nil {
+			info.MachineId = uuid.String()
+		}
+
+		info.Hardware = &machine.HardwareInfo{
+			Manufacturer: systemInformation.Manufacturer(),
+			ProductName:  systemInformation.ProductName(),
+			SerialNumber: systemInformation.SerialNumber(),
+		}
+	} else {
+		log.Printf("error reading SMBIOS: %s", e)
+	}
+
+	if state, e := secureboot.GetState(); e == nil {
+		info.SecureBoot = &machine.SecureBootInfo{
+			Efi:       state.EFI,
+			Enabled:   state.Enabled,
+			SetupMode: state.SetupMode,
+		}
+	} else {
+		log.Printf("error reading SecureBoot state: %s", e)
+	}
+
+	return &machine.SystemInfoResponse{
+		Messages: []*machine.SystemInfo{info},
+	}, nil
+}
+
+func (s *Server) platformInfo() *machine.PlatformInfo {
+	platform := s.Controller.Runtime().State().Platform()
+	if platform == nil {
+		return nil
+	}
+
+	return &machine.PlatformInfo{
+		Name: platform.Name(),
+		Mode: platform.Mode().String(),
+	}
+}
+
 // Kubeconfig implements the machine.MachineServer interface.
 func (s *Server) Kubeconfig(empty *empty.Empty, obj machine.MachineService_KubeconfigServer) error {
+	if err := requireAdmin(obj.Context()); err != nil {
+		return err
+	}
+
 	var b bytes.Buffer
 
 	if err := kubeconfig.GenerateAdmin(s.Controller.Runtime().Config().Cluster(), &b); err != nil {
@@ -1057,6 +1502,53 @@ func (s *Server) Kubeconfig(empty *empty.Empty, obj machine.MachineService_Kubec
 	})
 }
 
+// KubernetesCredentials implements the machine.MachineServer interface.
+//
+// Short-lived admin client certificate is issued, so that kubeconfig doesn't have to embed long-lived credentials.
+func (s *Server) KubernetesCredentials(ctx context.Context, in *machine.KubernetesCredentialsRequest) (*machine.KubernetesCredentialsResponse, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	cfg := s.Controller.Runtime().Config()
+
+	if cfg.Machine().Type() == machinetype.TypeJoin {
+		return nil, status.Error(codes.FailedPrecondition, "Kubernetes credentials can't be generated on worker nodes")
+	}
+
+	crtTTL, err := ptypes.Duration(in.GetCrtTtl())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid certificate TTL: %s", err)
+	}
+
+	cert, err := kubeconfig.GenerateAdminCertificate(cfg.Cluster(), crtTTL)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	crt, err := cert.GetCert()
+	if err != nil {
+		return nil, err
+	}
+
+	expiration, err := ptypes.TimestampProto(crt.NotAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &machine.KubernetesCredentialsResponse{
+		Messages: []*machine.KubernetesCredentials{
+			{
+				Crt:        cert.Crt,
+				Key:        cert.Key,
+				Expiration: expiration,
+			},
+		},
+	}
+
+	return reply, nil
+}
+
 // Logs provides a service or container logs can be requested and the contents of the
 // log file are streamed in chunks.
 // nolint: gocyclo
@@ -1100,6 +1592,8 @@ func (s *Server) Logs(req *machine.LogsRequest, l machine.MachineService_LogsSer
 		if err = l.Send(&common.Data{Bytes: data}); err != nil {
 			return
 		}
+
+		stream.Release(data)
 	}
 
 	return nil
@@ -1113,13 +1607,21 @@ func k8slogs(ctx context.Context, req *machine.LogsRequest) (chunker.Chunker, io
 	// nolint: errcheck
 	defer inspector.Close()
 
-	container, err := inspector.Container(req.Id)
+	id := req.Id
+
+	if resolver, ok := inspector.(containers.PodContainerResolver); ok {
+		if id, err = resolver.ResolvePodContainer(id); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	container, err := inspector.Container(id)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	if container == nil {
-		return nil, nil, fmt.Errorf("container %q not found", req.Id)
+		return nil, nil, fmt.Errorf("container %q not found", id)
 	}
 
 	return container.GetLogChunker(ctx, req.Follow, int(req.TailLines))
@@ -1147,6 +1649,10 @@ func getContainerInspector(ctx context.Context, namespace string, driver common.
 
 // Read implements the read API.
 func (s *Server) Read(in *machine.ReadRequest, srv machine.MachineService_ReadServer) (err error) {
+	if err = checkSecretPath(srv.Context(), in.Path, false); err != nil {
+		return err
+	}
+
 	stat, err := os.Stat(in.Path)
 	if err != nil {
 		return err
@@ -1154,6 +1660,10 @@ func (s *Server) Read(in *machine.ReadRequest, srv machine.MachineService_ReadSe
 
 	switch mode := stat.Mode(); {
 	case mode.IsRegular():
+		if in.Offset < 0 || in.Offset > stat.Size() {
+			return status.Errorf(codes.InvalidArgument, "offset %d is out of range for file of size %d", in.Offset, stat.Size())
+		}
+
 		f, err := os.OpenFile(in.Path, os.O_RDONLY, 0)
 		if err != nil {
 			return err
@@ -1161,6 +1671,10 @@ func (s *Server) Read(in *machine.ReadRequest, srv machine.MachineService_ReadSe
 
 		defer f.Close() //nolint: errcheck
 
+		if _, err = f.Seek(in.Offset, io.SeekStart); err != nil {
+			return err
+		}
+
 		ctx, cancel := context.WithCancel(srv.Context())
 		defer cancel()
 
@@ -1172,6 +1686,8 @@ func (s *Server) Read(in *machine.ReadRequest, srv machine.MachineService_ReadSe
 			if err != nil {
 				cancel()
 			}
+
+			stream.Release(data)
 		}
 
 		return nil
@@ -1234,7 +1750,7 @@ func (s *Server) Events(req *machine.EventsRequest, l machine.MachineService_Eve
 	return <-errCh
 }
 
-func pullAndValidateInstallerImage(ctx context.Context, reg config.Registries, ref string) error {
+func pullAndValidateInstallerImage(ctx context.Context, reg config.Registries, verification config.ImageVerification, ref string) error {
 	// Pull down specified installer image early so we can bail if it doesn't exist in the upstream registry
 	containerdctx := namespaces.WithNamespace(ctx, constants.SystemContainerdNamespace)
 
@@ -1243,7 +1759,7 @@ func pullAndValidateInstallerImage(ctx context.Context, reg config.Registries, r
 		return err
 	}
 
-	img, err := image.Pull(containerdctx, reg, client, ref)
+	img, err := image.Pull(containerdctx, reg, client, ref, image.WithVerification(verification))
 	if err != nil {
 		return err
 	}
@@ -1327,23 +1843,29 @@ func (s *Server) Containers(ctx context.Context, in *machine.ContainersRequest)
 
 	for _, pod := range pods {
 		for _, container := range pod.Containers {
-			container := &machine.ContainerInfo{
-				Namespace: in.Namespace,
-				Id:        container.Display,
-				PodId:     pod.Name,
-				Name:      container.Name,
-				Image:     container.Image,
-				Pid:       container.Pid,
-				Status:    container.Status,
-			}
-			containers = append(containers, container)
+			containers = append(containers, containerInfo(in.Namespace, pod, container))
 		}
 	}
 
+	sort.Slice(containers, func(i, j int) bool { return containers[i].Id < containers[j].Id })
+
+	if in.PageToken != "" {
+		idx := sort.Search(len(containers), func(i int) bool { return containers[i].Id > in.PageToken })
+		containers = containers[idx:]
+	}
+
+	var nextPageToken string
+
+	if in.Limit > 0 && len(containers) > int(in.Limit) {
+		containers = containers[:in.Limit]
+		nextPageToken = containers[len(containers)-1].Id
+	}
+
 	reply = &machine.ContainersResponse{
 		Messages: []*machine.Container{
 			{
-				Containers: containers,
+				Containers:    containers,
+				NextPageToken: nextPageToken,
 			},
 		},
 	}
@@ -1351,6 +1873,46 @@ func (s *Server) Containers(ctx context.Context, in *machine.ContainersRequest)
 	return reply, nil
 }
 
+func containerInfo(namespace string, pod *containers.Pod, container *containers.Container) *machine.ContainerInfo {
+	info := &machine.ContainerInfo{
+		Namespace:    namespace,
+		Id:           container.Display,
+		PodId:        pod.Name,
+		Name:         container.Name,
+		Image:        container.Image,
+		ImageDigest:  container.Digest,
+		Pid:          container.Pid,
+		Status:       container.Status,
+		PodSandboxId: container.PodSandboxID,
+		Env:          container.Env,
+	}
+
+	// restart count is not known for some containers
+	if restartCount, err := strconv.Atoi(container.RestartCount); err == nil {
+		info.RestartCount = int32(restartCount)
+	}
+
+	if !container.CreatedAt.IsZero() {
+		// nolint: errcheck
+		info.CreatedAt, _ = ptypes.TimestampProto(container.CreatedAt)
+	}
+
+	if container.Metrics != nil {
+		info.MemoryUsage = container.Metrics.MemoryUsage
+		info.CpuUsage = container.Metrics.CPUUsage
+	}
+
+	for _, mount := range container.Mounts {
+		info.Mounts = append(info.Mounts, &machine.ContainerMount{
+			Source:      mount.Source,
+			Destination: mount.Destination,
+			Type:        mount.Type,
+		})
+	}
+
+	return info
+}
+
 // Stats implements the machine.MachineServer interface.
 // nolint: gocyclo
 func (s *Server) Stats(ctx context.Context, in *machine.StatsRequest) (reply *machine.StatsResponse, err error) {
@@ -1439,6 +2001,47 @@ func (s *Server) Restart(ctx context.Context, in *machine.RestartRequest) (*mach
 func (s *Server) Dmesg(req *machine.DmesgRequest, srv machine.MachineService_DmesgServer) error {
 	ctx := srv.Context()
 
+	send := func(packet kmsg.Packet) error {
+		if packet.Err != nil {
+			return srv.Send(&common.Data{
+				Metadata: &common.Metadata{
+					Error: packet.Err.Error(),
+				},
+			})
+		}
+
+		msg := packet.Message
+
+		if req.Structured {
+			b, err := json.Marshal(msg)
+			if err != nil {
+				return err
+			}
+
+			return srv.Send(&common.Data{
+				Bytes: append(b, '\n'),
+			})
+		}
+
+		return srv.Send(&common.Data{
+			Bytes: []byte(fmt.Sprintf("%s: %7s: [%s]: %s", msg.Facility, msg.Priority, msg.Timestamp.Format(time.RFC3339Nano), msg.Message)),
+		})
+	}
+
+	lastSequence := int64(-1)
+
+	if req.TailLines > 0 && !req.Tail {
+		var err error
+
+		if lastSequence, err = dmesgTail(ctx, int(req.TailLines), send); err != nil {
+			return err
+		}
+
+		if !req.Follow {
+			return nil
+		}
+	}
+
 	var options []kmsg.Option
 
 	if req.Follow {
@@ -1468,35 +2071,80 @@ func (s *Server) Dmesg(req *machine.DmesgRequest, srv machine.MachineService_Dme
 				return nil
 			}
 
-			if packet.Err != nil {
-				err = srv.Send(&common.Data{
-					Metadata: &common.Metadata{
-						Error: packet.Err.Error(),
-					},
-				})
-			} else {
-				msg := packet.Message
-				err = srv.Send(&common.Data{
-					Bytes: []byte(fmt.Sprintf("%s: %7s: [%s]: %s", msg.Facility, msg.Priority, msg.Timestamp.Format(time.RFC3339Nano), msg.Message)),
-				})
+			if packet.Err == nil && packet.Message.SequenceNumber <= lastSequence {
+				// already sent as part of the tail
+				continue
 			}
 
-			if err != nil {
+			if err = send(packet); err != nil {
 				return err
 			}
 		}
 	}
 }
 
+// dmesgTail sends last n kernel log messages, it returns the sequence number of the last message sent.
+func dmesgTail(ctx context.Context, n int, send func(kmsg.Packet) error) (int64, error) {
+	reader, err := kmsg.NewReader()
+	if err != nil {
+		return 0, fmt.Errorf("error opening /dev/kmsg reader: %w", err)
+	}
+	defer reader.Close() //nolint: errcheck
+
+	lastSequence := int64(-1)
+	packets := make([]kmsg.Packet, 0, n+1)
+
+	for packet := range reader.Scan(ctx) {
+		if packet.Err == nil {
+			lastSequence = packet.Message.SequenceNumber
+		}
+
+		packets = append(packets, packet)
+
+		if len(packets) > n {
+			copy(packets, packets[1:])
+			packets = packets[:n]
+		}
+	}
+
+	for _, packet := range packets {
+		if err = send(packet); err != nil {
+			return 0, err
+		}
+	}
+
+	return lastSequence, ctx.Err()
+}
+
 // Processes implements the machine.MachineServer interface.
-func (s *Server) Processes(ctx context.Context, in *empty.Empty) (reply *machine.ProcessesResponse, err error) {
+//
+// Processes are returned ordered by PID, page token is the PID of the last returned process.
+//
+//nolint: gocyclo
+func (s *Server) Processes(ctx context.Context, in *machine.ProcessesRequest) (reply *machine.ProcessesResponse, err error) {
 	procs, err := procfs.AllProcs()
 	if err != nil {
 		return nil, err
 	}
 
+	sort.Slice(procs, func(i, j int) bool { return procs[i].PID < procs[j].PID })
+
+	if in.PageToken != "" {
+		var lastPID int
+
+		lastPID, err = strconv.Atoi(in.PageToken)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token %q", in.PageToken)
+		}
+
+		idx := sort.Search(len(procs), func(i int) bool { return procs[i].PID > lastPID })
+		procs = procs[idx:]
+	}
+
 	processes := make([]*machine.ProcessInfo, 0, len(procs))
 
+	var nextPageToken string
+
 	var (
 		command    string
 		executable string
@@ -1505,6 +2153,12 @@ func (s *Server) Processes(ctx context.Context, in *empty.Empty) (reply *machine
 	)
 
 	for _, proc := range procs {
+		if in.Limit > 0 && len(processes) == int(in.Limit) {
+			nextPageToken = strconv.Itoa(int(processes[len(processes)-1].Pid))
+
+			break
+		}
+
 		// due to race condition, reading process info might fail if process has already terminated
 		command, err = proc.Comm()
 		if err != nil {
@@ -1545,7 +2199,8 @@ func (s *Server) Processes(ctx context.Context, in *empty.Empty) (reply *machine
 	reply = &machine.ProcessesResponse{
 		Messages: []*machine.Process{
 			{
-				Processes: processes,
+				Processes:     processes,
+				NextPageToken: nextPageToken,
 			},
 		},
 	}
@@ -1715,6 +2370,49 @@ func (s *Server) EtcdForfeitLeadership(ctx context.Context, in *machine.EtcdForf
 	return reply, nil
 }
 
+// EtcdMaintenanceStatus implements the machine.MachineServer interface.
+func (s *Server) EtcdMaintenanceStatus(ctx context.Context, in *empty.Empty) (*machine.EtcdMaintenanceStatusResponse, error) {
+	report := s.Controller.Runtime().State().Cluster().EtcdMaintenance().Report()
+
+	status := &machine.EtcdMaintenanceStatus{
+		Enabled: s.Controller.Runtime().Config().Cluster().Etcd().Maintenance().Enabled(),
+		LastRun: timestampProto(report.LastRun),
+		Skipped: report.Skipped,
+		Members: make([]*machine.EtcdMemberMaintenanceStatus, 0, len(report.Members)),
+		Alarms:  report.Alarms,
+		Error:   report.Error,
+	}
+
+	for _, member := range report.Members {
+		status.Members = append(status.Members, &machine.EtcdMemberMaintenanceStatus{
+			Id:               member.ID,
+			Name:             member.Name,
+			Leader:           member.Leader,
+			DbSize:           member.DBSize,
+			DbSizeInUse:      member.DBSizeInUse,
+			LastDefragmented: timestampProto(member.LastDefragmented),
+			Error:            member.Error,
+		})
+	}
+
+	return &machine.EtcdMaintenanceStatusResponse{
+		Messages: []*machine.EtcdMaintenanceStatus{
+			status,
+		},
+	}, nil
+}
+
+// timestampProto converts the time to the protobuf timestamp, zero time is converted to nil.
+func timestampProto(t time.Time) *timestamp.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	ts, _ := ptypes.TimestampProto(t) //nolint: errcheck
+
+	return ts
+}
+
 func upgradeMutex(c *etcd.Client) (*concurrency.Mutex, error) {
 	sess, err := concurrency.NewSession(c.Client,
 		concurrency.WithTTL(MinimumEtcdUpgradeLeaseLockSeconds),
@@ -1727,3 +2425,16 @@ func upgradeMutex(c *etcd.Client) (*concurrency.Mutex, error) {
 
 	return mu, nil
 }
+
+// readPartitionTable reads the partition table of the disk.
+func readPartitionTable(disk string) (*gpt.GPT, error) {
+	bd, err := blockdevice.Open(disk)
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint: errcheck
+	defer bd.Close()
+
+	return bd.PartitionTable()
+}
@@ -159,6 +159,13 @@ func main() {
 		handle(err)
 	}
 
+	// Grow the EPHEMERAL partition on the disk resize.
+	go func() {
+		if e := c.WatchDiskResize(); e != nil {
+			log.Printf("WARNING: disk resize events will be ignored: %+v", e)
+		}
+	}()
+
 	// Watch and handle runtime events.
 	_ = c.Runtime().Events().Watch(func(events <-chan runtime.Event) { //nolint: errcheck
 		for {
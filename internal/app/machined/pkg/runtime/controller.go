@@ -51,4 +51,6 @@ type Controller interface {
 	Runtime() Runtime
 	Sequencer() Sequencer
 	Run(Sequence, interface{}, ...ControllerOption) error
+	Pause() bool
+	Resume() bool
 }
@@ -11,6 +11,14 @@ type LoggingManager interface {
 	ServiceLog(service string) LogHandler
 }
 
+// PersistentLoggingManager is a LoggingManager which can keep a persistent copy of the logs.
+type PersistentLoggingManager interface {
+	// EnablePersistence starts copying the logs to size-capped rotated files in the directory.
+	EnablePersistence(directory string, maxSize int64, maxFiles int) error
+	// DisablePersistence stops copying the logs and closes the files.
+	DisablePersistence() error
+}
+
 // LogOptions for LogHandler.Reader.
 type LogOptions struct {
 	Follow    bool
@@ -7,6 +7,8 @@ package logging
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
+	"path/filepath"
 	"sync"
 
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
@@ -25,8 +27,46 @@ const (
 )
 
 // CircularBufferLoggingManager implements logging to circular fixed size buffer.
+//
+// Optionally, copy of the logs is written to the rotated files (see EnablePersistence).
 type CircularBufferLoggingManager struct {
 	buffers sync.Map
+
+	// persistenceMu serializes writes to the buffers while persistence is configured
+	persistenceMu sync.Mutex
+	persistence   *logPersistence
+}
+
+type logPersistence struct {
+	directory string
+	maxSize   int64
+	maxFiles  int
+
+	files map[string]*RotatingFile
+}
+
+func (p *logPersistence) write(id string, data []byte) error {
+	file := p.files[id]
+	if file == nil {
+		file = NewRotatingFile(filepath.Join(p.directory, id+".log"), p.maxSize, p.maxFiles)
+		p.files[id] = file
+	}
+
+	_, err := file.Write(data)
+
+	return err
+}
+
+func (p *logPersistence) close() error {
+	var err error
+
+	for _, file := range p.files {
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
 }
 
 // NewCircularBufferLoggingManager initializes new CircularBufferLoggingManager.
@@ -42,6 +82,88 @@ func (manager *CircularBufferLoggingManager) ServiceLog(id string) runtime.LogHa
 	}
 }
 
+// EnablePersistence implements runtime.PersistentLoggingManager interface.
+//
+// Contents of the existing buffers is written to the files first, so that logs written
+// before persistence was enabled are not lost.
+func (manager *CircularBufferLoggingManager) EnablePersistence(directory string, maxSize int64, maxFiles int) error {
+	manager.persistenceMu.Lock()
+	defer manager.persistenceMu.Unlock()
+
+	if manager.persistence != nil {
+		if err := manager.persistence.close(); err != nil {
+			return err
+		}
+	}
+
+	persistence := &logPersistence{
+		directory: directory,
+		maxSize:   maxSize,
+		maxFiles:  maxFiles,
+		files:     map[string]*RotatingFile{},
+	}
+
+	var err error
+
+	manager.buffers.Range(func(key, value interface{}) bool {
+		r := value.(*circular.Buffer).GetReader()
+		defer r.Close() //nolint: errcheck
+
+		var data []byte
+
+		if data, err = ioutil.ReadAll(r); err != nil {
+			return false
+		}
+
+		err = persistence.write(key.(string), data)
+
+		return err == nil
+	})
+
+	if err != nil {
+		persistence.close() //nolint: errcheck
+
+		return fmt.Errorf("error writing persistent logs: %w", err)
+	}
+
+	manager.persistence = persistence
+
+	return nil
+}
+
+// DisablePersistence implements runtime.PersistentLoggingManager interface.
+func (manager *CircularBufferLoggingManager) DisablePersistence() error {
+	manager.persistenceMu.Lock()
+	defer manager.persistenceMu.Unlock()
+
+	if manager.persistence == nil {
+		return nil
+	}
+
+	err := manager.persistence.close()
+	manager.persistence = nil
+
+	return err
+}
+
+func (manager *CircularBufferLoggingManager) write(id string, buf *circular.Buffer, p []byte) (int, error) {
+	manager.persistenceMu.Lock()
+	defer manager.persistenceMu.Unlock()
+
+	n, err := buf.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	if manager.persistence != nil {
+		// errors are ignored: persistent copy is best effort, and the buffer already has the logs;
+		// also this might be machined own log, so logging the error here would recurse
+		manager.persistence.write(id, p) //nolint: errcheck
+	}
+
+	return n, nil
+}
+
 func (manager *CircularBufferLoggingManager) getBuffer(id string, create bool) (*circular.Buffer, error) {
 	buf, ok := manager.buffers.Load(id)
 	if !ok {
@@ -70,11 +192,17 @@ type circularHandler struct {
 	buf *circular.Buffer
 }
 
-type nopCloser struct {
-	io.Writer
+type circularWriter struct {
+	manager *CircularBufferLoggingManager
+	id      string
+	buf     *circular.Buffer
+}
+
+func (w *circularWriter) Write(p []byte) (int, error) {
+	return w.manager.write(w.id, w.buf, p)
 }
 
-func (c nopCloser) Close() error {
+func (w *circularWriter) Close() error {
 	return nil
 }
 
@@ -90,7 +218,11 @@ func (handler *circularHandler) Writer() (io.WriteCloser, error) {
 		}
 	}
 
-	return nopCloser{handler.buf}, nil
+	return &circularWriter{
+		manager: handler.manager,
+		id:      handler.id,
+		buf:     handler.buf,
+	}, nil
 }
 
 // Reader implements runtime.LogHandler interface.
@@ -0,0 +1,111 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package logging
+
+import (
+	"fmt"
+	"os"
+)
+
+// RotatingFile is a size-capped log file which is rotated once it grows over the limit.
+//
+// Rotated files are renamed to <path>.1, <path>.2, etc., files over the limit are removed.
+// RotatingFile is not safe for concurrent use.
+type RotatingFile struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	f    *os.File
+	size int64
+}
+
+// NewRotatingFile initializes new RotatingFile.
+//
+// File is opened on the first write.
+func NewRotatingFile(path string, maxSize int64, maxFiles int) *RotatingFile {
+	return &RotatingFile{
+		path:     path,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+	}
+}
+
+// Write implements io.Writer.
+func (file *RotatingFile) Write(p []byte) (int, error) {
+	if file.f == nil {
+		if err := file.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if file.size > 0 && file.size+int64(len(p)) > file.maxSize {
+		if err := file.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := file.f.Write(p)
+	file.size += int64(n)
+
+	return n, err
+}
+
+// Close implements io.Closer.
+func (file *RotatingFile) Close() error {
+	if file.f == nil {
+		return nil
+	}
+
+	err := file.f.Close()
+	file.f = nil
+
+	return err
+}
+
+func (file *RotatingFile) open() error {
+	f, err := os.OpenFile(file.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	st, err := f.Stat()
+	if err != nil {
+		f.Close() //nolint: errcheck
+
+		return err
+	}
+
+	file.f = f
+	file.size = st.Size()
+
+	return nil
+}
+
+func (file *RotatingFile) rotate() error {
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	for i := file.maxFiles; i > 1; i-- {
+		if err := os.Rename(fmt.Sprintf("%s.%d", file.path, i-1), fmt.Sprintf("%s.%d", file.path, i)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	var err error
+
+	if file.maxFiles > 0 {
+		err = os.Rename(file.path, file.path+".1")
+	} else {
+		err = os.Remove(file.path)
+	}
+
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return file.open()
+}
@@ -0,0 +1,99 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package logging_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/logging"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	path := filepath.Join(dir, "test.log")
+
+	file := logging.NewRotatingFile(path, 10, 2)
+
+	for _, line := range []string{"line1\n", "line2\n", "line3\n", "line4\n"} {
+		_, err = file.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, file.Close())
+
+	for name, expected := range map[string]string{
+		path:        "line4\n",
+		path + ".1": "line3\n",
+		path + ".2": "line2\n",
+	} {
+		contents, err := ioutil.ReadFile(name)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, string(contents))
+	}
+
+	assert.NoFileExists(t, path+".3")
+
+	// rotated file size is not reset on reopen
+	file = logging.NewRotatingFile(path, 10, 2)
+
+	_, err = file.Write([]byte("line5\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, file.Close())
+
+	contents, err := ioutil.ReadFile(path + ".1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "line4\n", string(contents))
+}
+
+func TestCircularBufferLoggingManagerPersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	manager := logging.NewCircularBufferLoggingManager()
+
+	w, err := manager.ServiceLog("test").Writer()
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("before\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, manager.EnablePersistence(dir, 1024, 1))
+
+	_, err = w.Write([]byte("after\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, manager.DisablePersistence())
+
+	_, err = w.Write([]byte("disabled\n"))
+	require.NoError(t, err)
+
+	contents, err := ioutil.ReadFile(filepath.Join(dir, "test.log"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "before\nafter\n", string(contents))
+
+	r, err := manager.ServiceLog("test").Reader()
+	require.NoError(t, err)
+
+	contents, err = ioutil.ReadAll(r)
+	require.NoError(t, err)
+
+	assert.Equal(t, "before\nafter\ndisabled\n", string(contents))
+}
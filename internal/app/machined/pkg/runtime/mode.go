@@ -32,6 +32,10 @@ const (
 	Shutdown
 	// Upgrade node upgrade.
 	Upgrade
+	// Grow system disk partition grow.
+	Grow
+	// Meta META partition access.
+	Meta
 )
 
 const (
@@ -78,7 +82,7 @@ func (m Mode) capabilities() uint64 {
 		// metal
 		all,
 		// container
-		all ^ uint64(Reboot|Shutdown|Upgrade|Rollback),
+		all ^ uint64(Reboot|Shutdown|Upgrade|Rollback|Grow|Meta),
 		// cloud
 		all,
 	}[m]
@@ -36,6 +36,8 @@ const (
 	SequenceReboot
 	// SequenceRecover is the recover sequence.
 	SequenceRecover
+	// SequenceGrow is the grow sequence.
+	SequenceGrow
 	// SequenceNoop is the noop sequence.
 	SequenceNoop
 )
@@ -52,12 +54,13 @@ const (
 	reset              = "reset"
 	reboot             = "reboot"
 	recover            = "recover"
+	grow               = "grow"
 	noop               = "noop"
 )
 
 // String returns the string representation of a `Sequence`.
 func (s Sequence) String() string {
-	return [...]string{applyConfiguration, boot, bootstrap, initialize, install, shutdown, upgrade, stageUpgrade, reset, reboot, recover, noop}[s]
+	return [...]string{applyConfiguration, boot, bootstrap, initialize, install, shutdown, upgrade, stageUpgrade, reset, reboot, recover, grow, noop}[s]
 }
 
 // ParseSequence returns a `Sequence` that matches the specified string.
@@ -87,6 +90,8 @@ func ParseSequence(s string) (seq Sequence, err error) {
 		seq = SequenceReboot
 	case recover:
 		seq = SequenceRecover
+	case grow:
+		seq = SequenceGrow
 	case noop:
 		seq = SequenceNoop
 	default:
@@ -101,12 +106,15 @@ type ResetOptions interface {
 	GetGraceful() bool
 	GetReboot() bool
 	GetSystemDiskTargets() []PartitionTarget
+	GetWipeMode() machine.ResetRequest_WipeMode
+	GetMaintenance() bool
 }
 
 // PartitionTarget provides interface to the disk partition.
 type PartitionTarget interface {
 	fmt.Stringer
 	Format() error
+	Wipe() error
 }
 
 // Sequencer describes the set of sequences required for the lifecycle
@@ -115,12 +123,13 @@ type Sequencer interface {
 	ApplyConfiguration(Runtime, *machine.ApplyConfigurationRequest) []Phase
 	Boot(Runtime) []Phase
 	Bootstrap(Runtime) []Phase
+	Grow(Runtime) []Phase
 	Initialize(Runtime) []Phase
 	Install(Runtime) []Phase
-	Reboot(Runtime) []Phase
+	Reboot(Runtime, *machine.RebootRequest) []Phase
 	Recover(Runtime, *machine.RecoverRequest) []Phase
 	Reset(Runtime, ResetOptions) []Phase
-	Shutdown(Runtime) []Phase
+	Shutdown(Runtime, *machine.ShutdownRequest) []Phase
 	StageUpgrade(Runtime, *machine.UpgradeRequest) []Phase
 	Upgrade(Runtime, *machine.UpgradeRequest) []Phase
 }
@@ -57,6 +57,11 @@ func TestSequence_String(t *testing.T) {
 			s:    runtime.SequenceRecover,
 			want: "recover",
 		},
+		{
+			name: "grow",
+			s:    runtime.SequenceGrow,
+			want: "grow",
+		},
 	}
 
 	for _, tt := range tests {
@@ -127,6 +132,12 @@ func TestParseSequence(t *testing.T) {
 			wantSeq: runtime.SequenceRecover,
 			wantErr: false,
 		},
+		{
+			name:    "grow",
+			args:    args{"grow"},
+			wantSeq: runtime.SequenceGrow,
+			wantErr: false,
+		},
 		{
 			name:    "invalid",
 			args:    args{"invalid"},
@@ -7,6 +7,8 @@ package runtime
 import (
 	"github.com/talos-systems/go-blockdevice/blockdevice/probe"
 
+	"github.com/talos-systems/talos/internal/pkg/etcd/maintenance"
+	"github.com/talos-systems/talos/internal/pkg/manifests"
 	"github.com/talos-systems/talos/pkg/machinery/config"
 )
 
@@ -31,7 +33,11 @@ type MachineState interface {
 	IsInstallStaged() bool
 	StagedInstallImageRef() string
 	StagedInstallOptions() []byte
+	IsMaintenanceRequested() bool
 }
 
 // ClusterState defines the cluster state.
-type ClusterState interface{}
+type ClusterState interface {
+	EtcdMaintenance() *maintenance.Status
+	Manifests() *manifests.Status
+}
@@ -32,4 +32,12 @@ const (
 	StagedUpgradeImageRef
 	// StagedUpgradeInstallOptions stores JSON-serialized install.Options.
 	StagedUpgradeInstallOptions
+	// MachineConfig stores the machine configuration (config storage backend `meta`).
+	MachineConfig
+	// SequenceProgress stores the last completed phase of the sequence (serialized machine.PhaseEvent).
+	SequenceProgress
+	// Maintenance requests the maintenance mode on the next boot.
+	Maintenance
+	// ExtraKernelArgs stores extra kernel arguments appended by the bootloader on boot.
+	ExtraKernelArgs
 )
@@ -5,12 +5,52 @@
 package bootloader
 
 import (
-	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"fmt"
+	"os"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/grub"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/options"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/sdboot"
 )
 
 // Bootloader describes a bootloader.
 type Bootloader interface {
-	Labels() (string, string, error)
-	Install(string, interface{}, runtime.Sequence) error
-	Default(string) error
+	// Labels returns the label of the current installation (if any) and the label
+	// to install the next one to.
+	Labels() (current, next string, err error)
+	// Install installs the bootloader and makes the next installation the default one.
+	Install(options.InstallOptions) error
+	// Default sets the default installation to boot.
+	Default(label string) error
+}
+
+// Bootloader types.
+const (
+	TypeAuto   = "auto"
+	TypeGrub   = "grub"
+	TypeSDBoot = "sd-boot"
+)
+
+// New returns the bootloader of the specified type.
+func New(typ string) (Bootloader, error) {
+	switch typ {
+	case TypeGrub:
+		return &grub.Grub{}, nil
+	case TypeSDBoot:
+		return &sdboot.SDBoot{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported bootloader type %q", typ)
+	}
+}
+
+// Probe returns the type of the installed bootloader.
+//
+// Boot and EFI partitions are expected to be mounted. Installations without
+// systemd-boot loader config are booted with grub.
+func Probe() string {
+	if _, err := os.Stat(sdboot.LoaderConfig); err == nil {
+		return TypeSDBoot
+	}
+
+	return TypeGrub
 }
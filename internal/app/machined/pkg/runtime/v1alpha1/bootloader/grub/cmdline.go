@@ -0,0 +1,102 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package grub
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// Grub environment block format, see grub-editenv(1).
+const (
+	envBlockHeader = "# GRUB Environment Block\n"
+	envBlockSize   = 1024
+)
+
+// EncodeCmdlineExtra encodes extra kernel arguments as grub environment block.
+func EncodeCmdlineExtra(args string) ([]byte, error) {
+	if strings.ContainsAny(args, "\n\\") {
+		return nil, fmt.Errorf("extra kernel arguments should not contain newlines or backslashes")
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString(envBlockHeader)
+	buf.WriteString(CmdlineExtraVariable + "=" + args + "\n")
+
+	if buf.Len() > envBlockSize {
+		return nil, fmt.Errorf("extra kernel arguments are too long: %d bytes, max %d bytes", len(args), envBlockSize-len(envBlockHeader)-len(CmdlineExtraVariable)-2)
+	}
+
+	buf.Write(bytes.Repeat([]byte{'#'}, envBlockSize-buf.Len()))
+
+	return buf.Bytes(), nil
+}
+
+// DecodeCmdlineExtra decodes extra kernel arguments from grub environment block.
+func DecodeCmdlineExtra(b []byte) (string, error) {
+	if !bytes.HasPrefix(b, []byte(envBlockHeader)) {
+		return "", fmt.Errorf("invalid grub environment block")
+	}
+
+	for _, line := range strings.Split(string(b[len(envBlockHeader):]), "\n") {
+		if strings.HasPrefix(line, CmdlineExtraVariable+"=") {
+			return strings.TrimPrefix(line, CmdlineExtraVariable+"="), nil
+		}
+	}
+
+	return "", nil
+}
+
+// WriteCmdlineExtra writes extra kernel arguments to be appended by grub on boot.
+//
+// Empty arguments remove the file.
+func WriteCmdlineExtra(path, args string) error {
+	if args == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		return nil
+	}
+
+	b, err := EncodeCmdlineExtra(args)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, b, 0o600)
+}
+
+// ReadCmdlineExtra reads extra kernel arguments appended by grub on boot.
+//
+// Missing file is not an error.
+func ReadCmdlineExtra(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return DecodeCmdlineExtra(b)
+}
+
+// StripCmdlineExtra removes extra kernel arguments appended by grub from the kernel command line.
+func StripCmdlineExtra(cmdline, args string) string {
+	cmdline = strings.TrimSpace(cmdline)
+
+	if args == "" {
+		return cmdline
+	}
+
+	return strings.TrimSpace(strings.TrimSuffix(cmdline, " "+args))
+}
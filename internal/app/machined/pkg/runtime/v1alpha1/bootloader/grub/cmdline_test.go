@@ -0,0 +1,68 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package grub_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/grub"
+)
+
+func TestEncodeCmdlineExtra(t *testing.T) {
+	b, err := grub.EncodeCmdlineExtra("console=ttyS0 talos.debug")
+	require.NoError(t, err)
+
+	assert.Len(t, b, 1024)
+	assert.True(t, strings.HasPrefix(string(b), "# GRUB Environment Block\ntalos_cmdline_extra=console=ttyS0 talos.debug\n###"))
+
+	args, err := grub.DecodeCmdlineExtra(b)
+	require.NoError(t, err)
+	assert.Equal(t, "console=ttyS0 talos.debug", args)
+
+	_, err = grub.EncodeCmdlineExtra("console=ttyS0\ntalos.debug")
+	assert.Error(t, err)
+
+	_, err = grub.EncodeCmdlineExtra(strings.Repeat("a", 1024))
+	assert.Error(t, err)
+}
+
+func TestWriteCmdlineExtra(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	path := filepath.Join(dir, "cmdline.extra")
+
+	args, err := grub.ReadCmdlineExtra(path)
+	require.NoError(t, err)
+	assert.Empty(t, args)
+
+	require.NoError(t, grub.WriteCmdlineExtra(path, "talos.debug"))
+
+	args, err = grub.ReadCmdlineExtra(path)
+	require.NoError(t, err)
+	assert.Equal(t, "talos.debug", args)
+
+	require.NoError(t, grub.WriteCmdlineExtra(path, ""))
+
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	require.NoError(t, grub.WriteCmdlineExtra(path, ""))
+}
+
+func TestStripCmdlineExtra(t *testing.T) {
+	assert.Equal(t, "console=tty0 talos.platform=metal", grub.StripCmdlineExtra("console=tty0 talos.platform=metal console=ttyS0\n", "console=ttyS0"))
+	assert.Equal(t, "console=tty0 talos.platform=metal", grub.StripCmdlineExtra("console=tty0 talos.platform=metal \n", ""))
+	assert.Equal(t, "console=tty0 talos.platform=metal", grub.StripCmdlineExtra("console=tty0 talos.platform=metal", "talos.debug"))
+}
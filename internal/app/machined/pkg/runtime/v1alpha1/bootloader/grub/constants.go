@@ -18,4 +18,10 @@ const (
 
 	// GrubDeviceMap is the path to the grub device map.
 	GrubDeviceMap = constants.BootMountPoint + "/grub/device.map"
+
+	// CmdlineExtra is the path to the grub environment block with extra kernel arguments.
+	CmdlineExtra = constants.BootMountPoint + "/cmdline.extra"
+
+	// CmdlineExtraVariable is the grub environment variable which holds extra kernel arguments.
+	CmdlineExtraVariable = "talos_cmdline_extra"
 )
@@ -14,14 +14,14 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
-	goruntime "runtime"
 	"strings"
 	"text/template"
 
 	"github.com/talos-systems/go-blockdevice/blockdevice/probe"
 	"github.com/talos-systems/go-blockdevice/blockdevice/util"
+	"github.com/talos-systems/go-procfs/procfs"
 
-	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/options"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
@@ -29,6 +29,7 @@ import (
 type Cfg struct {
 	Default  string
 	Fallback string
+	Serial   *options.Serial
 	Labels   []*Label
 }
 
@@ -48,23 +49,32 @@ set timeout=0
 
 insmod all_video
 
+{{ with .Serial -}}
+serial --unit={{ .Unit }} --speed={{ .Speed }}
+terminal_input serial console
+terminal_output serial console
+{{- else -}}
 terminal_input console
 terminal_output console
+{{- end }}
+
+set talos_cmdline_extra=""
+if [ -f /cmdline.extra ]; then
+  load_env --file /cmdline.extra talos_cmdline_extra
+fi
 
 {{ range $label := .Labels -}}
 menuentry "{{ $label.Root }}" {
   set gfxmode=auto
   set gfxpayload=text
-  linux {{ $label.Kernel }} {{ $label.Append }}
+  linux {{ $label.Kernel }} {{ $label.Append }} ${talos_cmdline_extra}
   initrd {{ $label.Initrd }}
 }
 {{ end }}
 `
 
 // Grub represents the grub bootloader.
-type Grub struct {
-	BootDisk string
-}
+type Grub struct{}
 
 // Labels implements the Bootloader interface.
 func (g *Grub) Labels() (current, next string, err error) {
@@ -108,17 +118,39 @@ func (g *Grub) Labels() (current, next string, err error) {
 // specified kernel parameters.
 //
 // nolint: gocyclo
-func (g *Grub) Install(fallback string, config interface{}, sequence runtime.Sequence) (err error) {
-	grubcfg, ok := config.(*Cfg)
-	if !ok {
-		return errors.New("expected a grub config")
+func (g *Grub) Install(opts options.InstallOptions) (err error) {
+	cmdline := procfs.NewCmdline(opts.Cmdline)
+	cmdline.Append("initrd", filepath.Join("/", opts.Next, constants.InitramfsAsset))
+
+	grubcfg := &Cfg{
+		Default: opts.Next,
+		Serial:  opts.Serial,
+		Labels: []*Label{
+			{
+				Root:   opts.Next,
+				Initrd: filepath.Join("/", opts.Next, constants.InitramfsAsset),
+				Kernel: filepath.Join("/", opts.Next, constants.KernelAsset),
+				Append: cmdline.String(),
+			},
+		},
+	}
+
+	if opts.Current != "" {
+		grubcfg.Fallback = opts.Current
+
+		grubcfg.Labels = append(grubcfg.Labels, &Label{
+			Root:   opts.Current,
+			Initrd: filepath.Join("/", opts.Current, constants.InitramfsAsset),
+			Kernel: filepath.Join("/", opts.Current, constants.KernelAsset),
+			Append: opts.CurrentCmdline,
+		})
 	}
 
 	if err = writeCfg(GrubConfig, grubcfg); err != nil {
 		return err
 	}
 
-	dev, err := probe.DevForFileSystemLabel(g.BootDisk, constants.BootPartitionLabel)
+	dev, err := probe.DevForFileSystemLabel(opts.BootDisk, constants.BootPartitionLabel)
 	if err != nil {
 		return fmt.Errorf("failed to probe boot partition: %w", err)
 	}
@@ -138,7 +170,7 @@ func (g *Grub) Install(fallback string, config interface{}, sequence runtime.Seq
 	// default: run for GRUB default platform
 	platforms := []string{""}
 
-	if goruntime.GOARCH == "amd64" && loopDevice {
+	if opts.Arch == "amd64" && loopDevice {
 		// building cloud image for amd64, install both BIOS & UEFI GRUB
 		platforms = []string{"x86_64-efi", "i386-pc"}
 	}
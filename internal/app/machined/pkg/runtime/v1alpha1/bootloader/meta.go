@@ -8,16 +8,22 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync"
 
 	"github.com/talos-systems/go-blockdevice/blockdevice/probe"
 
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/adv"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/adv/syslinux"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/adv/talos"
-	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/grub"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
+// metaMu serializes the access to the META partition.
+//
+// Every writer reads the whole ADV, updates some tags and writes it back, so concurrent
+// writers would overwrite each other's updates.
+var metaMu sync.Mutex
+
 // Meta represents the meta reader.
 type Meta struct {
 	*os.File
@@ -26,7 +32,18 @@ type Meta struct {
 }
 
 // NewMeta initializes and returns a `Meta`.
+//
+// META partition is locked until the Meta is closed, so it should be closed as soon as the tags are read
+// or written, and never held across the network calls.
 func NewMeta() (meta *Meta, err error) {
+	metaMu.Lock()
+
+	defer func() {
+		if err != nil {
+			metaMu.Unlock()
+		}
+	}()
+
 	var f *os.File
 
 	f, err = probe.GetPartitionWithName(constants.MetaPartitionLabel)
@@ -36,12 +53,16 @@ func NewMeta() (meta *Meta, err error) {
 
 	adv, err := talos.NewADV(f)
 	if adv == nil && err != nil {
+		f.Close() //nolint: errcheck
+
 		// if adv is not nil, but err is nil, it might be missing ADV, ignore it
 		return nil, err
 	}
 
 	legacyAdv, err := syslinux.NewADV(f)
 	if err != nil {
+		f.Close() //nolint: errcheck
+
 		return nil, err
 	}
 
@@ -52,6 +73,14 @@ func NewMeta() (meta *Meta, err error) {
 	}, nil
 }
 
+// Close closes the META partition and releases the lock.
+func (m *Meta) Close() error {
+	defer metaMu.Unlock()
+
+	return m.File.Close()
+}
+
+// Write writes the ADVs back to the META partition.
 func (m *Meta) Write() error {
 	serialized, err := m.ADV.Bytes()
 	if err != nil {
@@ -108,9 +137,12 @@ func (m *Meta) Revert() (err error) {
 		return nil
 	}
 
-	g := &grub.Grub{}
+	b, err := New(Probe())
+	if err != nil {
+		return err
+	}
 
-	if err = g.Default(label); err != nil {
+	if err = b.Default(label); err != nil {
 		return err
 	}
 
@@ -0,0 +1,35 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package options provides bootloader-agnostic install options.
+package options
+
+// InstallOptions configures the bootloader installation.
+type InstallOptions struct {
+	// BootDisk is the path to the disk the bootloader is installed to.
+	BootDisk string
+	// Arch is the architecture of the installation (GOARCH).
+	Arch string
+	// Version is the Talos version being installed.
+	Version string
+
+	// Current is the label of the current installation, empty for the fresh install.
+	Current string
+	// CurrentCmdline is the kernel command line of the current installation.
+	CurrentCmdline string
+
+	// Next is the label of the installation being installed.
+	Next string
+	// Cmdline is the kernel command line of the installation being installed.
+	Cmdline string
+
+	// Serial configures the bootloader serial terminal, if set.
+	Serial *Serial
+}
+
+// Serial represents the serial terminal settings.
+type Serial struct {
+	Unit  int
+	Speed int
+}
@@ -0,0 +1,56 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package sdboot
+
+import "github.com/talos-systems/talos/pkg/machinery/constants"
+
+const (
+	// BootA is a bootloader label.
+	BootA = "A"
+
+	// BootB is a bootloader label.
+	BootB = "B"
+
+	// LoaderConfig is the path to the systemd-boot loader config.
+	LoaderConfig = constants.EFIMountPoint + "/loader/loader.conf"
+
+	// LinuxDir is the path to the directory with unified kernel images.
+	LinuxDir = constants.EFIMountPoint + "/EFI/Linux"
+
+	// BootDir is the path to the directory with the removable media boot entry.
+	BootDir = constants.EFIMountPoint + "/EFI/BOOT"
+
+	// SystemdBootAssetPath is the path to the systemd-boot EFI binary in the installer image.
+	SystemdBootAssetPath = "/usr/install/systemd-boot.efi"
+
+	// SystemdStubAssetPath is the path to the systemd-stub EFI binary in the installer image.
+	SystemdStubAssetPath = "/usr/install/systemd-stub.efi"
+
+	// SecureBootAssetsDir is the path to the directory with the signed SecureBoot assets in the installer image.
+	SecureBootAssetsDir = "/usr/install/secureboot"
+
+	// SignedImageAssetPath is the path to the signed unified kernel image in the installer image.
+	SignedImageAssetPath = SecureBootAssetsDir + "/Talos.efi"
+
+	// SignedSystemdBootAssetPath is the path to the signed systemd-boot EFI binary in the installer image.
+	SignedSystemdBootAssetPath = SecureBootAssetsDir + "/systemd-boot.efi"
+
+	// PKAuthAssetPath is the path to the PK enrollment update in the installer image.
+	PKAuthAssetPath = SecureBootAssetsDir + "/PK.auth"
+
+	// KEKAuthAssetPath is the path to the KEK enrollment update in the installer image.
+	KEKAuthAssetPath = SecureBootAssetsDir + "/KEK.auth"
+
+	// DBAuthAssetPath is the path to the db enrollment update in the installer image.
+	DBAuthAssetPath = SecureBootAssetsDir + "/db.auth"
+)
+
+// Section offsets of the unified kernel image as recommended for systemd-stub.
+const (
+	osrelVMA   = 0x20000
+	cmdlineVMA = 0x30000
+	linuxVMA   = 0x2000000
+	initrdVMA  = 0x3000000
+)
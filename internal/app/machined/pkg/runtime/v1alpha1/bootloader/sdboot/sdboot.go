@@ -0,0 +1,359 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package sdboot implements systemd-boot bootloader with unified kernel images.
+package sdboot
+
+import (
+	"bufio"
+	"bytes"
+	"debug/pe"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/options"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+const osReleaseTemplate = `NAME="Talos"
+ID=talos
+VERSION_ID={{ .Version }}
+PRETTY_NAME="Talos ({{ .Version }})"
+`
+
+// SDBoot represents the systemd-boot bootloader.
+//
+// Each installation is booted from the unified kernel image (UKI) on the EFI
+// system partition: kernel, initramfs and kernel command line bundled into
+// a single EFI binary. Default installation is set in the loader config.
+type SDBoot struct{}
+
+// Labels implements the Bootloader interface.
+func (s *SDBoot) Labels() (current, next string, err error) {
+	var b []byte
+
+	if b, err = ioutil.ReadFile(LoaderConfig); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			next = BootA
+
+			return current, next, nil
+		}
+
+		return "", "", err
+	}
+
+	if current, err = parseDefault(b); err != nil {
+		return "", "", err
+	}
+
+	switch current {
+	case BootA:
+		next = BootB
+	case BootB:
+		next = BootA
+	default:
+		return "", "", fmt.Errorf("unknown systemd-boot entry: %q", current)
+	}
+
+	return current, next, nil
+}
+
+// Install implements the Bootloader interface. It builds the unified kernel image
+// for the next installation and makes it the default one.
+//
+// Kernel and initramfs are expected to be already installed to the boot partition.
+// If the installer image contains the signed SecureBoot assets, the signed unified kernel
+// image and systemd-boot are installed instead.
+func (s *SDBoot) Install(opts options.InstallOptions) (err error) {
+	efiName, err := efiBootName(opts.Arch)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(LinuxDir, 0o700); err != nil {
+		return err
+	}
+
+	signed := SignedAssetsPresent()
+	bootAsset := SystemdBootAssetPath
+
+	if signed {
+		bootAsset = SignedSystemdBootAssetPath
+
+		err = installSignedImage(opts)
+	} else {
+		err = buildImage(opts)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err = removeStaleImages(opts.Current, opts.Next); err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(BootDir, 0o700); err != nil {
+		return err
+	}
+
+	log.Printf("installing systemd-boot to %s", BootDir)
+
+	if err = copyFile(bootAsset, filepath.Join(BootDir, efiName)); err != nil {
+		return err
+	}
+
+	return writeLoaderConfig(LoaderConfig, opts.Next)
+}
+
+// Default implements the Bootloader interface.
+func (s *SDBoot) Default(label string) (err error) {
+	if _, err = os.Stat(imagePath(label)); err != nil {
+		return fmt.Errorf("failed to find unified kernel image for %q: %w", label, err)
+	}
+
+	return writeLoaderConfig(LoaderConfig, label)
+}
+
+// imageName returns the name of the unified kernel image for the label.
+func imageName(label string) string {
+	return "Talos-" + label + ".efi"
+}
+
+func imagePath(label string) string {
+	return filepath.Join(LinuxDir, imageName(label))
+}
+
+// efiBootName returns the name of the removable media boot entry for the architecture.
+func efiBootName(arch string) (string, error) {
+	switch arch {
+	case "amd64":
+		return "BOOTX64.EFI", nil
+	default:
+		return "", fmt.Errorf("systemd-boot is not supported on %q", arch)
+	}
+}
+
+// parseDefault returns the label of the default entry in the loader config.
+func parseDefault(b []byte) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		if len(fields) != 2 || fields[0] != "default" {
+			continue
+		}
+
+		label := strings.TrimSuffix(strings.TrimPrefix(fields[1], "Talos-"), ".efi")
+
+		if imageName(label) != fields[1] {
+			return "", fmt.Errorf("unexpected default entry: %q", fields[1])
+		}
+
+		return label, nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("failed to find default")
+}
+
+// renderLoaderConfig renders the loader config with the label as the default entry.
+//
+// Timeout is set to zero: the menu is skipped unless a key is pressed during the boot.
+func renderLoaderConfig(label string) []byte {
+	return []byte(fmt.Sprintf("default %s\ntimeout 0\neditor no\n", imageName(label)))
+}
+
+func writeLoaderConfig(path, label string) (err error) {
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	log.Printf("writing %s to disk", path)
+
+	return ioutil.WriteFile(path, renderLoaderConfig(label), 0o600)
+}
+
+// buildImage bundles the kernel, initramfs and the kernel command line of the next
+// installation with systemd-stub into the unified kernel image.
+func buildImage(opts options.InstallOptions) error {
+	return BuildImage(opts.Version, opts.Cmdline,
+		filepath.Join(constants.BootMountPoint, opts.Next, constants.KernelAsset),
+		filepath.Join(constants.BootMountPoint, opts.Next, constants.InitramfsAsset),
+		imagePath(opts.Next))
+}
+
+// BuildImage bundles the kernel, initramfs and the kernel command line with systemd-stub
+// into the unified kernel image.
+func BuildImage(version, cmdline, kernel, initramfs, output string) (err error) {
+	tmpDir, err := ioutil.TempDir("", "talos-uki")
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer os.RemoveAll(tmpDir)
+
+	var osRelease bytes.Buffer
+
+	if err = template.Must(template.New("os-release").Parse(osReleaseTemplate)).Execute(&osRelease, struct{ Version string }{version}); err != nil {
+		return err
+	}
+
+	osReleasePath := filepath.Join(tmpDir, "os-release")
+	if err = ioutil.WriteFile(osReleasePath, osRelease.Bytes(), 0o600); err != nil {
+		return err
+	}
+
+	cmdlinePath := filepath.Join(tmpDir, "cmdline")
+	if err = ioutil.WriteFile(cmdlinePath, []byte(cmdline), 0o600); err != nil {
+		return err
+	}
+
+	sections := []struct {
+		name string
+		path string
+		vma  uint64
+	}{
+		{".osrel", osReleasePath, osrelVMA},
+		{".cmdline", cmdlinePath, cmdlineVMA},
+		{".linux", kernel, linuxVMA},
+		{".initrd", initramfs, initrdVMA},
+	}
+
+	args := []string{}
+
+	for _, section := range sections {
+		args = append(args,
+			"--add-section", fmt.Sprintf("%s=%s", section.name, section.path),
+			"--change-section-vma", fmt.Sprintf("%s=0x%x", section.name, section.vma),
+		)
+	}
+
+	args = append(args, SystemdStubAssetPath, output)
+
+	log.Printf("executing: objcopy %s", strings.Join(args, " "))
+
+	cmd := exec.Command("objcopy", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("failed to build unified kernel image: %w", err)
+	}
+
+	return nil
+}
+
+// SignedAssetsPresent checks whether the installer image contains the signed SecureBoot assets.
+func SignedAssetsPresent() bool {
+	for _, asset := range []string{SignedImageAssetPath, SignedSystemdBootAssetPath} {
+		if _, err := os.Stat(asset); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// installSignedImage installs the signed unified kernel image for the next installation.
+//
+// The kernel command line is embedded into the signed image when the installer image is built,
+// so the kernel command line of the install options is not applied.
+func installSignedImage(opts options.InstallOptions) error {
+	cmdline, err := ImageCmdline(SignedImageAssetPath)
+	if err != nil {
+		return err
+	}
+
+	if cmdline != opts.Cmdline {
+		log.Printf("warning: using the kernel command line of the signed unified kernel image %q instead of %q", cmdline, opts.Cmdline)
+	}
+
+	return copyFile(SignedImageAssetPath, imagePath(opts.Next))
+}
+
+// ImageCmdline returns the kernel command line embedded into the unified kernel image.
+func ImageCmdline(path string) (string, error) {
+	f, err := pe.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	//nolint: errcheck
+	defer f.Close()
+
+	section := f.Section(".cmdline")
+	if section == nil {
+		return "", fmt.Errorf("unified kernel image %q doesn't have the kernel command line", path)
+	}
+
+	data, err := section.Data()
+	if err != nil {
+		return "", err
+	}
+
+	// section data is padded to the file alignment
+	return strings.TrimRight(string(data), "\x00\n "), nil
+}
+
+// removeStaleImages removes unified kernel images other than the current and next ones.
+func removeStaleImages(current, next string) error {
+	images, err := filepath.Glob(filepath.Join(LinuxDir, imageName("*")))
+	if err != nil {
+		return err
+	}
+
+	for _, image := range images {
+		name := filepath.Base(image)
+
+		if name == imageName(current) || name == imageName(next) {
+			continue
+		}
+
+		log.Printf("removing stale unified kernel image %s", image)
+
+		if err = os.Remove(image); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer out.Close()
+
+	if _, err = io.Copy(out, in); err != nil {
+		return err
+	}
+
+	return out.Close()
+}
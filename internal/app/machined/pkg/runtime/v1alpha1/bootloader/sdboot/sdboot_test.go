@@ -0,0 +1,83 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package sdboot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDefault(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		config   string
+		expected string
+		err      string
+	}{
+		{
+			name:     "rendered",
+			config:   string(renderLoaderConfig(BootB)),
+			expected: BootB,
+		},
+		{
+			name:     "whitespace",
+			config:   "# comment\ntimeout 3\n  default   Talos-A.efi\n",
+			expected: BootA,
+		},
+		{
+			name:   "foreign entry",
+			config: "default arch.conf\n",
+			err:    "unexpected default entry: \"arch.conf\"",
+		},
+		{
+			name:   "no default",
+			config: "timeout 0\n",
+			err:    "failed to find default",
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			label, err := parseDefault([]byte(tt.config))
+
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, label)
+			}
+		})
+	}
+}
+
+func TestWriteLoaderConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	path := filepath.Join(dir, "loader", "loader.conf")
+
+	require.NoError(t, writeLoaderConfig(path, BootA))
+
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "default Talos-A.efi\ntimeout 0\neditor no\n", string(b))
+}
+
+func TestEFIBootName(t *testing.T) {
+	name, err := efiBootName("amd64")
+	assert.NoError(t, err)
+	assert.Equal(t, "BOOTX64.EFI", name)
+
+	_, err = efiBootName("arm64")
+	assert.EqualError(t, err, "systemd-boot is not supported on \"arm64\"")
+}
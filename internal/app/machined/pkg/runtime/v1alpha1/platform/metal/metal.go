@@ -6,12 +6,16 @@ package metal
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net"
 	"net/url"
 	"path/filepath"
+	"strings"
 
 	"github.com/talos-systems/go-blockdevice/blockdevice/probe"
 	"github.com/talos-systems/go-procfs/procfs"
@@ -37,54 +41,189 @@ func (m *Metal) Name() string {
 }
 
 // Configuration implements the platform.Platform interface.
-//
-// nolint: gocyclo
 func (m *Metal) Configuration(ctx context.Context) ([]byte, error) {
 	var option *string
 	if option = procfs.ProcCmdline().Get(constants.KernelParamConfig).First(); option == nil {
 		return nil, errors.ErrNoConfigSource
 	}
 
-	log.Printf("fetching machine config from: %q", *option)
+	if *option == constants.MetalConfigISOLabel {
+		return readConfigFromISO()
+	}
 
-	u, err := url.Parse(*option)
+	downloadURL, err := PopulateURLParameters(*option, URLVariables)
 	if err != nil {
 		return nil, err
 	}
 
-	values := u.Query()
+	log.Printf("fetching machine config from: %q", downloadURL)
+
+	opts, err := downloadOptions(procfs.ProcCmdline())
+	if err != nil {
+		return nil, err
+	}
+
+	return download.Download(ctx, downloadURL, opts...)
+}
 
-	if len(values) > 0 {
-		for key := range values {
-			switch key {
-			case "uuid":
-				s, err := smbios.New()
-				if err != nil {
-					return nil, err
-				}
-
-				uuid, err := s.SystemInformation().UUID()
-				if err != nil {
-					return nil, err
-				}
-
-				values.Set("uuid", uuid.String())
-			default:
-				log.Printf("unsupported query parameter: %q", key)
+// URLVariables are the variables which can be used in the config URL.
+var URLVariables = map[string]func() (string, error){
+	"uuid": func() (string, error) {
+		s, err := smbios.New()
+		if err != nil {
+			return "", err
+		}
+
+		uuid, err := s.SystemInformation().UUID()
+		if err != nil {
+			return "", err
+		}
+
+		return uuid.String(), nil
+	},
+	"serial": func() (string, error) {
+		s, err := smbios.New()
+		if err != nil {
+			return "", err
+		}
+
+		return s.SystemInformation().SerialNumber(), nil
+	},
+	"mac": func() (string, error) {
+		ifaces, err := net.Interfaces()
+		if err != nil {
+			return "", err
+		}
+
+		for _, iface := range ifaces {
+			if iface.Flags&net.FlagLoopback == 0 && len(iface.HardwareAddr) > 0 {
+				return iface.HardwareAddr.String(), nil
 			}
 		}
 
+		return "", fmt.Errorf("no network interface with a hardware address found")
+	},
+}
+
+// PopulateURLParameters substitutes the variables in the config URL.
+//
+// Variables can be used in the URL as `${name}`, e.g. `https://example.com/config?mac=${mac}`.
+// For backwards compatibility the value of the `uuid` query parameter is always replaced with the system UUID.
+func PopulateURLParameters(downloadURL string, variables map[string]func() (string, error)) (string, error) {
+	for name, getter := range variables {
+		placeholder := "${" + name + "}"
+
+		if !strings.Contains(downloadURL, placeholder) {
+			continue
+		}
+
+		value, err := getter()
+		if err != nil {
+			return "", fmt.Errorf("error getting value for %q: %w", name, err)
+		}
+
+		downloadURL = strings.ReplaceAll(downloadURL, placeholder, url.QueryEscape(value))
+	}
+
+	u, err := url.Parse(downloadURL)
+	if err != nil {
+		return "", err
+	}
+
+	values := u.Query()
+
+	if _, ok := values["uuid"]; ok {
+		uuid, err := variables["uuid"]()
+		if err != nil {
+			return "", fmt.Errorf("error getting value for %q: %w", "uuid", err)
+		}
+
+		values.Set("uuid", uuid)
 		u.RawQuery = values.Encode()
+	}
+
+	return u.String(), nil
+}
 
-		*option = u.String()
+func downloadOptions(cmdline *procfs.Cmdline) ([]download.Option, error) {
+	var opts []download.Option
+
+	if param := cmdline.Get(constants.KernelParamConfigHeader); param != nil {
+		headers := map[string]string{}
+
+		for i := 0; param.Get(i) != nil; i++ {
+			header := strings.SplitN(*param.Get(i), ":", 2)
+			if len(header) != 2 {
+				return nil, fmt.Errorf("invalid %s value %q, expected Name:Value", constants.KernelParamConfigHeader, *param.Get(i))
+			}
+
+			headers[header[0]] = header[1]
+		}
+
+		opts = append(opts, download.WithHeaders(headers))
 	}
 
-	switch *option {
-	case constants.MetalConfigISOLabel:
-		return readConfigFromISO()
-	default:
-		return download.Download(ctx, *option)
+	tlsConfig, err := tlsConfig(cmdline)
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig != nil {
+		opts = append(opts, download.WithTLSConfig(tlsConfig))
+	}
+
+	return opts, nil
+}
+
+func tlsConfig(cmdline *procfs.Cmdline) (*tls.Config, error) {
+	decode := func(key string) ([]byte, error) {
+		value := cmdline.Get(key).First()
+		if value == nil {
+			return nil, nil
+		}
+
+		return base64.StdEncoding.DecodeString(*value)
+	}
+
+	ca, err := decode(constants.KernelParamConfigCA)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", constants.KernelParamConfigCA, err)
+	}
+
+	cert, err := decode(constants.KernelParamConfigCert)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", constants.KernelParamConfigCert, err)
+	}
+
+	key, err := decode(constants.KernelParamConfigKey)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", constants.KernelParamConfigKey, err)
 	}
+
+	if ca == nil && cert == nil && key == nil {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{}
+
+	if ca != nil {
+		cfg.RootCAs = x509.NewCertPool()
+
+		if !cfg.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in %s", constants.KernelParamConfigCA)
+		}
+	}
+
+	if cert != nil || key != nil {
+		clientCert, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("error loading config client certificate: %w", err)
+		}
+
+		cfg.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return cfg, nil
 }
 
 // Hostname implements the platform.Platform interface.
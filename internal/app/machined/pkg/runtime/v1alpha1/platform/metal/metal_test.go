@@ -4,11 +4,56 @@
 
 package metal_test
 
-import "testing"
+import (
+	"testing"
 
-func TestEmpty(t *testing.T) {
-	// added for accurate coverage estimation
-	//
-	// please remove it once any unit-test is added
-	// for this package
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/platform/metal"
+)
+
+func TestPopulateURLParameters(t *testing.T) {
+	variables := map[string]func() (string, error){
+		"uuid": func() (string, error) {
+			return "40dcbd19-3b10-444e-bfff-aaee44a51fda", nil
+		},
+		"serial": func() (string, error) {
+			return "SN 0001", nil
+		},
+		"mac": func() (string, error) {
+			return "52:54:00:12:34:56", nil
+		},
+	}
+
+	for _, tt := range []struct {
+		url      string
+		expected string
+	}{
+		{
+			url:      "http://example.com/config.yaml",
+			expected: "http://example.com/config.yaml",
+		},
+		{
+			url:      "http://example.com/config.yaml?uuid=",
+			expected: "http://example.com/config.yaml?uuid=40dcbd19-3b10-444e-bfff-aaee44a51fda",
+		},
+		{
+			url:      "http://example.com/${mac}/config.yaml?serial=${serial}",
+			expected: "http://example.com/52%3A54%3A00%3A12%3A34%3A56/config.yaml?serial=SN+0001",
+		},
+		{
+			url:      "http://example.com/config.yaml?uuid=&mac=${mac}",
+			expected: "http://example.com/config.yaml?mac=52%3A54%3A00%3A12%3A34%3A56&uuid=40dcbd19-3b10-444e-bfff-aaee44a51fda",
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.url, func(t *testing.T) {
+			actual, err := metal.PopulateURLParameters(tt.url, variables)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
 }
@@ -13,10 +13,12 @@ import (
 	"os/signal"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
@@ -35,6 +37,9 @@ type Controller struct {
 	s *Sequencer
 
 	semaphore int32
+
+	pauseMu sync.Mutex
+	resumed chan struct{}
 }
 
 // NewController intializes and returns a controller.
@@ -71,6 +76,8 @@ func NewController(b []byte) (*Controller, error) {
 		s: NewSequencer(),
 	}
 
+	ctlr.publishSavedProgress()
+
 	return ctlr, nil
 }
 
@@ -122,7 +129,7 @@ func (c *Controller) Run(seq runtime.Sequence, data interface{}, setters ...runt
 		return err
 	}
 
-	err = c.run(seq, phases, data)
+	err = c.run(seq, phases, data, pausable(seq, opts))
 	if err != nil {
 		c.Runtime().Events().Publish(&machine.SequenceEvent{
 			Sequence: seq.String(),
@@ -210,7 +217,81 @@ func (c *Controller) Unlock() bool {
 	return atomic.CompareAndSwapInt32(&c.semaphore, 1, 0)
 }
 
-func (c *Controller) run(seq runtime.Sequence, phases []runtime.Phase, data interface{}) error {
+// Pause implements the controller interface.
+//
+// Sequences are paused before their next phase, the running phase is not interrupted.
+// If the controller is already paused, a value of false will be returned.
+func (c *Controller) Pause() bool {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+
+	if c.resumed != nil {
+		return false
+	}
+
+	c.resumed = make(chan struct{})
+
+	return true
+}
+
+// Resume implements the controller interface.
+//
+// If the controller is not paused, a value of false will be returned.
+func (c *Controller) Resume() bool {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+
+	if c.resumed == nil {
+		return false
+	}
+
+	close(c.resumed)
+	c.resumed = nil
+
+	return true
+}
+
+// waitResumed blocks the sequence while the controller is paused.
+func (c *Controller) waitResumed(seq runtime.Sequence, phase string) {
+	c.pauseMu.Lock()
+	resumed := c.resumed
+	c.pauseMu.Unlock()
+
+	if resumed == nil {
+		return
+	}
+
+	log.Printf("%s sequence: paused before phase %s", seq.String(), phase)
+
+	c.Runtime().Events().Publish(&machine.SequenceEvent{
+		Sequence: seq.String(),
+		Action:   machine.SequenceEvent_PAUSE,
+	})
+
+	<-resumed
+
+	log.Printf("%s sequence: resumed", seq.String())
+
+	c.Runtime().Events().Publish(&machine.SequenceEvent{
+		Sequence: seq.String(),
+		Action:   machine.SequenceEvent_RESUME,
+	})
+}
+
+// pausable checks whether the sequence can be paused.
+//
+// Sequences which are run without the lock, shutdown, reboot and forced sequences are never paused,
+// so that the node can always be recovered or powered off.
+func pausable(seq runtime.Sequence, opts runtime.ControllerOptions) bool {
+	switch seq { //nolint: exhaustive
+	case runtime.SequenceBootstrap, runtime.SequenceReset, runtime.SequenceRecover, runtime.SequenceShutdown, runtime.SequenceReboot:
+		return false
+	default:
+		return !opts.Force
+	}
+}
+
+func (c *Controller) run(seq runtime.Sequence, phases []runtime.Phase, data interface{}, canPause bool) error {
 	c.Runtime().Events().Publish(&machine.SequenceEvent{
 		Sequence: seq.String(),
 		Action:   machine.SequenceEvent_START,
@@ -224,9 +305,10 @@ func (c *Controller) run(seq runtime.Sequence, phases []runtime.Phase, data inte
 	start := time.Now()
 
 	var (
-		number int
-		phase  runtime.Phase
-		err    error
+		number    int
+		phase     runtime.Phase
+		completed *machine.PhaseEvent
+		err       error
 	)
 
 	log.Printf("%s sequence: %d phase(s)", seq.String(), len(phases))
@@ -243,13 +325,22 @@ func (c *Controller) run(seq runtime.Sequence, phases []runtime.Phase, data inte
 		// Make the phase number human friendly.
 		number++
 
+		if canPause {
+			c.waitResumed(seq, phase.Name)
+		}
+
 		start := time.Now()
 
 		progress := fmt.Sprintf("%d/%d", number, len(phases))
 
 		log.Printf("phase %s (%s): %d tasks(s)", phase.Name, progress, len(phase.Tasks))
 
-		if err = c.runPhase(phase, seq, data); err != nil {
+		// The last phase might reboot or shut down the node, so the progress is saved before it.
+		if number == len(phases) && completed != nil {
+			c.saveProgress(completed)
+		}
+
+		if completed, err = c.runPhase(phase, number, len(phases), seq, data); err != nil {
 			log.Printf("phase %s (%s): failed", phase.Name, progress)
 
 			return fmt.Errorf("error running phase %d in %s sequence: %w", number, seq.String(), err)
@@ -258,19 +349,37 @@ func (c *Controller) run(seq runtime.Sequence, phases []runtime.Phase, data inte
 		log.Printf("phase %s (%s): done, %s", phase.Name, progress, time.Since(start))
 	}
 
+	if completed != nil {
+		c.saveProgress(completed)
+	}
+
 	return nil
 }
 
-func (c *Controller) runPhase(phase runtime.Phase, seq runtime.Sequence, data interface{}) error {
+func (c *Controller) runPhase(phase runtime.Phase, number, total int, seq runtime.Sequence, data interface{}) (event *machine.PhaseEvent, err error) {
 	c.Runtime().Events().Publish(&machine.PhaseEvent{
-		Phase:  phase.Name,
-		Action: machine.PhaseEvent_START,
+		Phase:    phase.Name,
+		Action:   machine.PhaseEvent_START,
+		Sequence: seq.String(),
+		Number:   int32(number),
+		Total:    int32(total),
 	})
 
-	defer c.Runtime().Events().Publish(&machine.PhaseEvent{
-		Phase:  phase.Name,
-		Action: machine.PhaseEvent_START,
-	})
+	start := time.Now()
+
+	defer func() {
+		event = &machine.PhaseEvent{
+			Phase:    phase.Name,
+			Action:   machine.PhaseEvent_STOP,
+			Sequence: seq.String(),
+			Number:   int32(number),
+			Total:    int32(total),
+			Duration: ptypes.DurationProto(time.Since(start)),
+			Error:    eventError(err),
+		}
+
+		c.Runtime().Events().Publish(event)
+	}()
 
 	var eg errgroup.Group
 
@@ -285,7 +394,7 @@ func (c *Controller) runPhase(phase runtime.Phase, seq runtime.Sequence, data in
 		eg.Go(func() error {
 			progress := fmt.Sprintf("%d/%d", number, len(phase.Tasks))
 
-			if err := c.runTask(progress, task, seq, data); err != nil {
+			if err := c.runTask(progress, phase.Name, task, seq, data); err != nil {
 				return fmt.Errorf("task %s: failed, %w", progress, err)
 			}
 
@@ -293,10 +402,11 @@ func (c *Controller) runPhase(phase runtime.Phase, seq runtime.Sequence, data in
 		})
 	}
 
-	return eg.Wait()
+	// event is set by the deferred function above
+	return nil, eg.Wait()
 }
 
-func (c *Controller) runTask(progress string, f runtime.TaskSetupFunc, seq runtime.Sequence, data interface{}) error {
+func (c *Controller) runTask(progress, phaseName string, f runtime.TaskSetupFunc, seq runtime.Sequence, data interface{}) error {
 	task, taskName := f(seq, data)
 	if task == nil {
 		return nil
@@ -305,8 +415,10 @@ func (c *Controller) runTask(progress string, f runtime.TaskSetupFunc, seq runti
 	start := time.Now()
 
 	c.Runtime().Events().Publish(&machine.TaskEvent{
-		Task:   taskName,
-		Action: machine.TaskEvent_START,
+		Task:     taskName,
+		Action:   machine.TaskEvent_START,
+		Sequence: seq.String(),
+		Phase:    phaseName,
 	})
 
 	var err error
@@ -321,10 +433,16 @@ func (c *Controller) runTask(progress string, f runtime.TaskSetupFunc, seq runti
 		log.Printf("task %s (%s): done, %s", taskName, progress, time.Since(start))
 	}()
 
-	defer c.Runtime().Events().Publish(&machine.TaskEvent{
-		Task:   taskName,
-		Action: machine.TaskEvent_STOP,
-	})
+	defer func() {
+		c.Runtime().Events().Publish(&machine.TaskEvent{
+			Task:     taskName,
+			Action:   machine.TaskEvent_STOP,
+			Sequence: seq.String(),
+			Phase:    phaseName,
+			Duration: ptypes.DurationProto(time.Since(start)),
+			Error:    eventError(err),
+		})
+	}()
 
 	logger := log.New(log.Writer(), fmt.Sprintf("[talos] task %s (%s): ", taskName, progress), log.Flags())
 
@@ -353,14 +471,22 @@ func (c *Controller) phases(seq runtime.Sequence, data interface{}) ([]runtime.P
 		phases = c.s.Boot(c.r)
 	case runtime.SequenceBootstrap:
 		phases = c.s.Bootstrap(c.r)
+	case runtime.SequenceGrow:
+		phases = c.s.Grow(c.r)
 	case runtime.SequenceInitialize:
 		phases = c.s.Initialize(c.r)
 	case runtime.SequenceInstall:
 		phases = c.s.Install(c.r)
 	case runtime.SequenceShutdown:
-		phases = c.s.Shutdown(c.r)
+		// shutdown might be triggered without a request (e.g. by a signal), zero request is used then
+		in, _ := data.(*machine.ShutdownRequest) //nolint: errcheck
+
+		phases = c.s.Shutdown(c.r, in)
 	case runtime.SequenceReboot:
-		phases = c.s.Reboot(c.r)
+		// reboot might be triggered by other requests (e.g. rollback), zero request is used then
+		in, _ := data.(*machine.RebootRequest) //nolint: errcheck
+
+		phases = c.s.Reboot(c.r, in)
 	case runtime.SequenceRecover:
 		var (
 			in *machine.RecoverRequest
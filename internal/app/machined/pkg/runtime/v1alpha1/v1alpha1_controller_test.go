@@ -9,8 +9,10 @@ import (
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
 )
 
 func TestNewController(t *testing.T) {
@@ -251,7 +253,7 @@ func TestController_run(t *testing.T) {
 				s:         tt.fields.s,
 				semaphore: tt.fields.semaphore,
 			}
-			if err := c.run(tt.args.seq, tt.args.phases, tt.args.data); (err != nil) != tt.wantErr {
+			if err := c.run(tt.args.seq, tt.args.phases, tt.args.data, true); (err != nil) != tt.wantErr {
 				t.Errorf("Controller.run() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -266,9 +268,11 @@ func TestController_runPhase(t *testing.T) {
 	}
 
 	type args struct {
-		phase runtime.Phase
-		seq   runtime.Sequence
-		data  interface{}
+		phase  runtime.Phase
+		number int
+		total  int
+		seq    runtime.Sequence
+		data   interface{}
 	}
 
 	tests := []struct {
@@ -287,7 +291,7 @@ func TestController_runPhase(t *testing.T) {
 				s:         tt.fields.s,
 				semaphore: tt.fields.semaphore,
 			}
-			if err := c.runPhase(tt.args.phase, tt.args.seq, tt.args.data); (err != nil) != tt.wantErr {
+			if _, err := c.runPhase(tt.args.phase, tt.args.number, tt.args.total, tt.args.seq, tt.args.data); (err != nil) != tt.wantErr {
 				t.Errorf("Controller.runPhase() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -302,10 +306,11 @@ func TestController_runTask(t *testing.T) {
 	}
 
 	type args struct {
-		n    int
-		f    runtime.TaskSetupFunc
-		seq  runtime.Sequence
-		data interface{}
+		n     int
+		phase string
+		f     runtime.TaskSetupFunc
+		seq   runtime.Sequence
+		data  interface{}
 	}
 
 	tests := []struct {
@@ -324,7 +329,7 @@ func TestController_runTask(t *testing.T) {
 				s:         tt.fields.s,
 				semaphore: tt.fields.semaphore,
 			}
-			if err := c.runTask(strconv.Itoa(tt.args.n), tt.args.f, tt.args.seq, tt.args.data); (err != nil) != tt.wantErr {
+			if err := c.runTask(strconv.Itoa(tt.args.n), tt.args.phase, tt.args.f, tt.args.seq, tt.args.data); (err != nil) != tt.wantErr {
 				t.Errorf("Controller.runTask() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -389,3 +394,85 @@ func Test_waitForUSBDelay(t *testing.T) {
 		})
 	}
 }
+
+func TestController_Pause(t *testing.T) {
+	c := &Controller{
+		r: NewRuntime(nil, nil, NewEvents(100, 10), nil),
+	}
+
+	actions := make(chan machine.SequenceEvent_Action, 10)
+
+	if err := c.Runtime().Events().Watch(func(events <-chan runtime.Event) {
+		for event := range events {
+			if msg, ok := event.Payload.(*machine.SequenceEvent); ok {
+				actions <- msg.Action
+			}
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// not paused
+	c.waitResumed(runtime.SequenceUpgrade, "phase")
+
+	if c.Resume() {
+		t.Error("Controller.Resume() = true for not paused controller")
+	}
+
+	if !c.Pause() {
+		t.Error("Controller.Pause() = false")
+	}
+
+	if c.Pause() {
+		t.Error("Controller.Pause() = true for paused controller")
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		c.waitResumed(runtime.SequenceUpgrade, "phase")
+
+		close(done)
+	}()
+
+	if action := <-actions; action != machine.SequenceEvent_PAUSE {
+		t.Errorf("unexpected action %s", action)
+	}
+
+	select {
+	case <-done:
+		t.Fatal("sequence wasn't paused")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if !c.Resume() {
+		t.Error("Controller.Resume() = false")
+	}
+
+	<-done
+
+	if action := <-actions; action != machine.SequenceEvent_RESUME {
+		t.Errorf("unexpected action %s", action)
+	}
+}
+
+func Test_pausable(t *testing.T) {
+	for _, tt := range []struct {
+		seq   runtime.Sequence
+		force bool
+		want  bool
+	}{
+		{runtime.SequenceBoot, false, true},
+		{runtime.SequenceUpgrade, false, true},
+		{runtime.SequenceUpgrade, true, false},
+		{runtime.SequenceShutdown, false, false},
+		{runtime.SequenceReboot, false, false},
+		{runtime.SequenceReset, false, false},
+		{runtime.SequenceBootstrap, false, false},
+		{runtime.SequenceRecover, false, false},
+	} {
+		if got := pausable(tt.seq, runtime.ControllerOptions{Force: tt.force}); got != tt.want {
+			t.Errorf("pausable(%s, %v) = %v, want %v", tt.seq, tt.force, got, tt.want)
+		}
+	}
+}
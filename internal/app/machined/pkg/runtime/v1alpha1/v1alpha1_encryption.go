@@ -0,0 +1,177 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/talos-systems/go-blockdevice/blockdevice"
+	"github.com/talos-systems/go-blockdevice/blockdevice/probe"
+	"golang.org/x/sys/unix"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/pkg/encryption"
+	"github.com/talos-systems/talos/internal/pkg/mount"
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+	"github.com/talos-systems/talos/pkg/makefs"
+)
+
+// ephemeralPartitionPath returns the path of the EPHEMERAL partition.
+//
+// Encrypted partition doesn't have the filesystem label, so it is located by the partition name.
+func ephemeralPartitionPath() (string, error) {
+	f, err := probe.GetPartitionWithName(constants.EphemeralPartitionLabel)
+	if err != nil {
+		return "", fmt.Errorf("failed to find %s partition: %w", constants.EphemeralPartitionLabel, err)
+	}
+
+	//nolint: errcheck
+	defer f.Close()
+
+	return f.Name(), nil
+}
+
+func encryptionKeys(cfg config.Encryption) []encryption.Key {
+	keys := []encryption.Key{}
+
+	for _, key := range cfg.Keys() {
+		if tpm := key.TPM(); tpm != nil {
+			keys = append(keys, encryption.NewTPMKey(key.Slot(), tpm.PCRs()))
+		}
+	}
+
+	return keys
+}
+
+// mountEncryptedEphemeral unlocks and mounts the encrypted EPHEMERAL partition.
+//
+// Partition which is not encrypted yet is wiped and encrypted. Partition which can't be unlocked
+// is never wiped, as the failure might be transient (e.g. PCR values changed by the firmware update).
+//
+//nolint: gocyclo
+func mountEncryptedEphemeral(logger *log.Logger, r runtime.Runtime, cfg config.Encryption) error {
+	path, err := ephemeralPartitionPath()
+	if err != nil {
+		return err
+	}
+
+	keys := encryptionKeys(cfg)
+
+	_, err = encryption.ReadHeader(path)
+
+	switch {
+	case errors.Is(err, encryption.ErrNotEncrypted):
+		logger.Printf("encrypting %s partition %q, the contents of the partition are erased", constants.EphemeralPartitionLabel, path)
+
+		if err = wipeAndEncrypt(path, keys); err != nil {
+			return fmt.Errorf("error encrypting %q: %w", path, err)
+		}
+	case err != nil:
+		return fmt.Errorf("error reading encryption header of %q: %w", path, err)
+	}
+
+	// EPHEMERAL partition with the fixed size is not grown
+	resize := r.Config().Machine().Install().EphemeralSize() == 0
+
+	if resize {
+		// partition is resized before it's mapped, so that the mapping covers the whole partition
+		if _, err = mount.NewMountPoint(path, constants.EphemeralMountPoint, "", 0, "").ResizePartition(); err != nil {
+			return fmt.Errorf("error resizing %q: %w", path, err)
+		}
+	}
+
+	mapped, err := encryption.Open(path, constants.EphemeralMapperName, keys)
+	if err != nil {
+		return fmt.Errorf("error unlocking %s partition: %w", constants.EphemeralPartitionLabel, err)
+	}
+
+	logger.Printf("unlocked %s partition %q", constants.EphemeralPartitionLabel, path)
+
+	// filesystem is created on the first boot after the encryption, or if that boot was interrupted
+	sb, err := probe.FileSystem(mapped)
+	if err != nil {
+		return err
+	}
+
+	if sb == nil {
+		if err = makefs.XFS(mapped, makefs.WithLabel(constants.EphemeralPartitionLabel), makefs.WithForce(true)); err != nil {
+			return fmt.Errorf("error creating filesystem on %q: %w", mapped, err)
+		}
+	}
+
+	var data string
+
+	if len(r.Config().Machine().EphemeralQuotas()) > 0 {
+		data = "prjquota"
+	}
+
+	mountpoint := mount.NewMountPoint(mapped, constants.EphemeralMountPoint, "xfs", unix.MS_NOATIME, data)
+
+	mountpoints := mount.NewMountPoints()
+	mountpoints.Set(constants.EphemeralPartitionLabel, mountpoint)
+
+	if err = mount.Mount(mountpoints); err != nil {
+		return err
+	}
+
+	if resize {
+		return mountpoint.GrowFilesystem()
+	}
+
+	return nil
+}
+
+// wipeAndEncrypt discards the previous contents of the partition (if supported by the device) and encrypts it.
+func wipeAndEncrypt(path string, keys []encryption.Key) error {
+	bd, err := blockdevice.Open(path)
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer bd.Close()
+
+	if err = bd.FastWipe(); err != nil {
+		return err
+	}
+
+	if err = bd.Close(); err != nil {
+		return err
+	}
+
+	return encryption.Format(path, keys)
+}
+
+// unmountEncryptedEphemeral unmounts the encrypted EPHEMERAL partition and removes the mapping.
+func unmountEncryptedEphemeral() error {
+	mountpoints := mount.NewMountPoints()
+	mountpoints.Set(constants.EphemeralPartitionLabel,
+		mount.NewMountPoint(encryption.MappedPath(constants.EphemeralMapperName), constants.EphemeralMountPoint, "xfs", 0, ""))
+
+	if err := mount.Unmount(mountpoints); err != nil {
+		return err
+	}
+
+	return encryption.Close(constants.EphemeralMapperName)
+}
+
+// ephemeralEncryptionError explains the failure to mount the EPHEMERAL partition
+// which was encrypted, but the encryption is no longer configured.
+func ephemeralEncryptionError(err error) error {
+	path, e := ephemeralPartitionPath()
+	if e != nil {
+		return err
+	}
+
+	if _, e = encryption.ReadHeader(path); e != nil {
+		return err
+	}
+
+	return fmt.Errorf("%s partition is encrypted, but the encryption is not configured, "+
+		"wipe the partition to disable the encryption: %w", constants.EphemeralPartitionLabel, err)
+}
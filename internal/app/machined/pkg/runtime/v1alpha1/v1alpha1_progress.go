@@ -0,0 +1,94 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha1
+
+import (
+	"log"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/adv"
+	"github.com/talos-systems/talos/pkg/machinery/api/common"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+)
+
+func eventError(err error) *common.Error {
+	if err == nil {
+		return nil
+	}
+
+	return &common.Error{
+		Code:    common.Code_FATAL,
+		Message: err.Error(),
+	}
+}
+
+// saveProgress records the last completed phase in the META partition.
+//
+// Progress is saved only at the sequence boundaries (before the last phase which might reboot
+// the node and at the end of the sequence) to avoid rewriting META after every phase.
+//
+// Progress is saved on the best effort basis: META partition might be missing
+// (e.g. before the install or after the reset).
+func (c *Controller) saveProgress(event *machine.PhaseEvent) {
+	if c.r.State().Platform().Mode() == runtime.ModeContainer {
+		return
+	}
+
+	serialized, err := proto.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	meta, err := bootloader.NewMeta()
+	if err != nil {
+		return
+	}
+
+	//nolint: errcheck
+	defer meta.Close()
+
+	if !meta.ADV.SetTag(adv.SequenceProgress, string(serialized)) {
+		log.Printf("failed to record sequence progress: no space left in META")
+
+		return
+	}
+
+	if err = meta.Write(); err != nil {
+		log.Printf("failed to record sequence progress: %s", err)
+	}
+}
+
+// publishSavedProgress publishes the last completed phase recorded before the reboot.
+//
+// This allows clients to track the progress of sequences which span a reboot (e.g. upgrade).
+func (c *Controller) publishSavedProgress() {
+	if c.r.State().Platform().Mode() == runtime.ModeContainer {
+		return
+	}
+
+	meta, err := bootloader.NewMeta()
+	if err != nil {
+		return
+	}
+
+	//nolint: errcheck
+	defer meta.Close()
+
+	serialized, ok := meta.ADV.ReadTag(adv.SequenceProgress)
+	if !ok {
+		return
+	}
+
+	var event machine.PhaseEvent
+
+	if err = proto.Unmarshal([]byte(serialized), &event); err != nil {
+		return
+	}
+
+	c.r.Events().Publish(&event)
+}
@@ -0,0 +1,105 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha1
+
+import (
+	"io/ioutil"
+	"log"
+	"path/filepath"
+
+	"github.com/talos-systems/go-blockdevice/blockdevice/probe"
+	"github.com/talos-systems/go-blockdevice/blockdevice/util"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/pkg/uevent"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// WatchDiskResize starts the disk resize listener. The listener will trigger
+// the grow sequence when the disk with the EPHEMERAL partition gets bigger
+// (e.g. the cloud volume is resized).
+func (c *Controller) WatchDiskResize() error {
+	if c.r.State().Platform().Mode() == runtime.ModeContainer {
+		return nil
+	}
+
+	listener, err := uevent.Listen(uevent.GroupKernel)
+	if err != nil {
+		return err
+	}
+
+	// nolint: errcheck
+	defer listener.Close()
+
+	for {
+		event, err := listener.Read()
+		if err != nil {
+			return err
+		}
+
+		if devpath := capacityChangedSCSIDevice(event); devpath != "" {
+			// SCSI disks report the capacity change, but the capacity is re-read only on rescan,
+			// rescan triggers the resize event for the disk
+			if err = ioutil.WriteFile(filepath.Join("/sys", devpath, "rescan"), []byte("1"), 0o200); err != nil {
+				log.Printf("failed to rescan SCSI device %q: %s", devpath, err)
+			}
+
+			continue
+		}
+
+		devname := resizedDisk(event)
+		if devname == "" {
+			continue
+		}
+
+		ephemeralDisk, err := ephemeralDisk()
+		if err != nil {
+			log.Printf("failed to find the EPHEMERAL partition: %s", err)
+
+			continue
+		}
+
+		if devname != ephemeralDisk {
+			continue
+		}
+
+		log.Printf("disk %q was resized", devname)
+
+		if err = c.Run(runtime.SequenceGrow, nil); err != nil {
+			log.Printf("grow failed: %s", err)
+		}
+	}
+}
+
+// capacityChangedSCSIDevice returns the sysfs path of the SCSI device which reported the capacity change.
+func capacityChangedSCSIDevice(event *uevent.Event) string {
+	if event.Action == "change" && event.Subsystem == "scsi" && event.Properties["SDEV_UA"] == "CAPACITY_DATA_HAS_CHANGED" {
+		return event.DevPath
+	}
+
+	return ""
+}
+
+// resizedDisk returns the name of the disk (e.g. `sda`) which capacity was changed.
+func resizedDisk(event *uevent.Event) string {
+	if event.Action == "change" && event.Subsystem == "block" && event.Properties["DEVTYPE"] == "disk" && event.Properties["RESIZE"] == "1" {
+		return event.Properties["DEVNAME"]
+	}
+
+	return ""
+}
+
+// ephemeralDisk returns the name of the disk with the EPHEMERAL partition.
+func ephemeralDisk() (string, error) {
+	dev, err := probe.GetDevWithFileSystemLabel(constants.EphemeralPartitionLabel)
+	if err != nil {
+		return "", err
+	}
+
+	// nolint: errcheck
+	defer dev.Close()
+
+	return util.DevnameFromPartname(dev.Path)
+}
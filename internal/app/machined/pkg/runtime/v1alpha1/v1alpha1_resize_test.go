@@ -0,0 +1,47 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//nolint: testpackage
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/talos-systems/talos/internal/pkg/uevent"
+)
+
+func TestResizedDisk(t *testing.T) {
+	assert.Equal(t, "vda", resizedDisk(&uevent.Event{
+		Action:     "change",
+		DevPath:    "/devices/pci0000:00/0000:00:04.0/virtio1/block/vda",
+		Subsystem:  "block",
+		Properties: map[string]string{"DEVNAME": "vda", "DEVTYPE": "disk", "RESIZE": "1"},
+	}))
+
+	// partition table was re-read, but the disk was not resized
+	assert.Empty(t, resizedDisk(&uevent.Event{
+		Action:     "change",
+		DevPath:    "/devices/pci0000:00/0000:00:04.0/virtio1/block/vda",
+		Subsystem:  "block",
+		Properties: map[string]string{"DEVNAME": "vda", "DEVTYPE": "disk"},
+	}))
+}
+
+func TestCapacityChangedSCSIDevice(t *testing.T) {
+	assert.Equal(t, "/devices/pci0000:00/0000:00:03.0/virtio0/host0/target0:0:1/0:0:1:0", capacityChangedSCSIDevice(&uevent.Event{
+		Action:     "change",
+		DevPath:    "/devices/pci0000:00/0000:00:03.0/virtio0/host0/target0:0:1/0:0:1:0",
+		Subsystem:  "scsi",
+		Properties: map[string]string{"SDEV_UA": "CAPACITY_DATA_HAS_CHANGED"},
+	}))
+
+	assert.Empty(t, capacityChangedSCSIDevice(&uevent.Event{
+		Action:     "change",
+		DevPath:    "/devices/pci0000:00/0000:00:03.0/virtio0/host0/target0:0:1/0:0:1:0",
+		Subsystem:  "scsi",
+		Properties: map[string]string{"SDEV_UA": "REPORTED_LUNS_DATA_HAS_CHANGED"},
+	}))
+}
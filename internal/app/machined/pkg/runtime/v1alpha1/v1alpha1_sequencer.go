@@ -6,6 +6,7 @@ package v1alpha1
 
 import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/pkg/controlplane"
 	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
 )
@@ -119,6 +120,12 @@ func (*Sequencer) Initialize(r runtime.Runtime) []runtime.Phase {
 		).Append(
 			"config",
 			LoadConfig,
+		).Append(
+			"consoleLogger",
+			SetupConsoleLogging,
+		).Append(
+			"measure",
+			MeasureBoot,
 		).AppendWhen(
 			r.State().Machine().Installed(),
 			"unmountSystem",
@@ -211,6 +218,10 @@ func (*Sequencer) Boot(r runtime.Runtime) []runtime.Phase {
 		r.State().Platform().Mode() != runtime.ModeContainer,
 		"ephemeral",
 		MountEphermeralPartition,
+	).AppendWhen(
+		r.State().Platform().Mode() != runtime.ModeContainer,
+		"imageCache",
+		MountImageCachePartition,
 	).AppendWhen(
 		r.State().Platform().Mode() != runtime.ModeContainer,
 		"verifyInstall",
@@ -218,6 +229,14 @@ func (*Sequencer) Boot(r runtime.Runtime) []runtime.Phase {
 	).Append(
 		"var",
 		SetupVarDirectory,
+	).AppendWhen(
+		r.State().Platform().Mode() != runtime.ModeContainer && len(r.Config().Machine().EphemeralQuotas()) > 0,
+		"ephemeralQuotas",
+		SetupEphemeralQuotas,
+	).AppendWhen(
+		r.State().Platform().Mode() != runtime.ModeContainer && r.Config().Machine().Logging().Persistence().Enabled(),
+		"logPersistence",
+		EnableLogPersistence,
 	).AppendWhen(
 		r.State().Platform().Mode() != runtime.ModeContainer,
 		"overlay",
@@ -226,6 +245,19 @@ func (*Sequencer) Boot(r runtime.Runtime) []runtime.Phase {
 		r.State().Platform().Mode() != runtime.ModeContainer,
 		"udevd",
 		StartUdevd,
+	).AppendWhen(
+		r.State().Platform().Mode() != runtime.ModeContainer && (len(r.Config().Machine().Kernel().Modules()) > 0 || r.Config().Machine().Features().NFSMounts().Enabled()),
+		"kernelModules",
+		LoadKernelModules,
+	).AppendWhen(
+		r.State().Platform().Mode() != runtime.ModeContainer && (r.Config().Machine().Kernel().IsolatedCPUs() != "" ||
+			r.Config().Machine().Kernel().NohzFullCPUs() != "" || r.Config().Machine().Kernel().IRQAffinityCPUs() != ""),
+		"cpuIsolation",
+		ConfigureCPUIsolation,
+	).AppendWhen(
+		r.State().Platform().Mode() != runtime.ModeContainer && r.Config().Machine().BMC().Configured(),
+		"bmc",
+		ConfigureBMC,
 	).AppendWhen(
 		r.State().Platform().Mode() != runtime.ModeContainer,
 		"userDisks",
@@ -238,6 +270,14 @@ func (*Sequencer) Boot(r runtime.Runtime) []runtime.Phase {
 		r.State().Platform().Mode() != runtime.ModeContainer,
 		"lvm",
 		ActivateLogicalVolumes,
+	).AppendWhen(
+		r.State().Platform().Mode() != runtime.ModeContainer,
+		"managementTunnel",
+		SetupManagementTunnel,
+	).AppendWhen(
+		r.Config().Machine().Type() != machine.TypeJoin && controlplane.Enabled(r.Config().Cluster()),
+		"staticPods",
+		RenderStaticPods,
 	).Append(
 		"startEverything",
 		StartAllServices,
@@ -277,10 +317,29 @@ func (*Sequencer) Bootstrap(r runtime.Runtime) []runtime.Phase {
 	return phases
 }
 
+// Grow is the grow sequence.
+func (*Sequencer) Grow(r runtime.Runtime) []runtime.Phase {
+	phases := PhaseList{}.AppendWhen(
+		r.State().Platform().Mode() != runtime.ModeContainer,
+		"grow",
+		GrowEphemeralPartition,
+	)
+
+	return phases
+}
+
 // Reboot is the reboot sequence.
-func (*Sequencer) Reboot(r runtime.Runtime) []runtime.Phase {
-	phases := PhaseList{}.AppendList(stopAllPhaselist(r)).
-		Append("reboot", Reboot)
+func (*Sequencer) Reboot(r runtime.Runtime, in *machineapi.RebootRequest) []runtime.Phase {
+	phases := PhaseList{}.AppendWhen(
+		r.State().Platform().Mode() != runtime.ModeContainer && !in.GetForce(),
+		"drain",
+		CordonAndDrainNode,
+	).AppendList(
+		stopAllPhaselist(r),
+	).Append(
+		"reboot",
+		Reboot,
+	)
 
 	return phases
 }
@@ -332,6 +391,10 @@ func (*Sequencer) Reset(r runtime.Runtime, in runtime.ResetOptions) []runtime.Ph
 			len(in.GetSystemDiskTargets()) > 0,
 			"resetSpec",
 			ResetSystemDiskSpec,
+		).AppendWhen(
+			in.GetMaintenance(),
+			"maintenance",
+			RequestMaintenance,
 		).AppendWhen(
 			in.GetReboot(),
 			"reboot",
@@ -347,10 +410,17 @@ func (*Sequencer) Reset(r runtime.Runtime, in runtime.ResetOptions) []runtime.Ph
 }
 
 // Shutdown is the shutdown sequence.
-func (*Sequencer) Shutdown(r runtime.Runtime) []runtime.Phase {
-	phases := PhaseList{}.
-		AppendList(stopAllPhaselist(r)).
-		Append("shutdown", Shutdown)
+func (*Sequencer) Shutdown(r runtime.Runtime, in *machineapi.ShutdownRequest) []runtime.Phase {
+	phases := PhaseList{}.AppendWhen(
+		r.State().Platform().Mode() != runtime.ModeContainer && !in.GetForce(),
+		"drain",
+		CordonAndDrainNode,
+	).AppendList(
+		stopAllPhaselist(r),
+	).Append(
+		"shutdown",
+		Shutdown,
+	)
 
 	return phases
 }
@@ -386,7 +456,8 @@ func (*Sequencer) Upgrade(r runtime.Runtime, in *machineapi.UpgradeRequest) []ru
 	case runtime.ModeContainer:
 		return nil
 	default:
-		phases = phases.Append(
+		phases = phases.AppendWhen(
+			!in.GetForce(),
 			"drain",
 			CordonAndDrainNode,
 		).AppendWhen(
@@ -410,6 +481,7 @@ func (*Sequencer) Upgrade(r runtime.Runtime, in *machineapi.UpgradeRequest) []ru
 			UnmountPodMounts,
 		).Append(
 			"unmountSystem",
+			UnmountImageCachePartition,
 			UnmountEphemeralPartition,
 			UnmountStatePartition,
 		).Append(
@@ -452,6 +524,7 @@ func stopAllPhaselist(r runtime.Runtime) PhaseList {
 			UnmountPodMounts,
 		).Append(
 			"unmountSystem",
+			UnmountImageCachePartition,
 			UnmountEphemeralPartition,
 			UnmountStatePartition,
 		).Append(
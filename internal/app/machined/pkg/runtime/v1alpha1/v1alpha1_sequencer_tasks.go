@@ -9,16 +9,20 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
@@ -33,6 +37,7 @@ import (
 	"github.com/talos-systems/go-retry/retry"
 	"go.etcd.io/etcd/clientv3"
 	"golang.org/x/sys/unix"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
 
 	installer "github.com/talos-systems/talos/cmd/installer/pkg/install"
@@ -40,7 +45,6 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/adv"
-	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/grub"
 	perrors "github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/platform/errors"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
@@ -48,18 +52,37 @@ import (
 	"github.com/talos-systems/talos/internal/app/maintenance"
 	"github.com/talos-systems/talos/internal/app/networkd/pkg/networkd"
 	"github.com/talos-systems/talos/internal/app/timed/pkg/ntp"
+	"github.com/talos-systems/talos/internal/pkg/bmc"
+	"github.com/talos-systems/talos/internal/pkg/configstore"
+	"github.com/talos-systems/talos/internal/pkg/console"
 	"github.com/talos-systems/talos/internal/pkg/containers/cri/containerd"
+	"github.com/talos-systems/talos/internal/pkg/controlplane"
 	"github.com/talos-systems/talos/internal/pkg/cri"
+	"github.com/talos-systems/talos/internal/pkg/discovery"
+	"github.com/talos-systems/talos/internal/pkg/encryption"
+	"github.com/talos-systems/talos/internal/pkg/environment"
 	"github.com/talos-systems/talos/internal/pkg/etcd"
+	"github.com/talos-systems/talos/internal/pkg/hardware"
+	"github.com/talos-systems/talos/internal/pkg/kernel/irq"
+	"github.com/talos-systems/talos/internal/pkg/kernel/kmod"
 	"github.com/talos-systems/talos/internal/pkg/kernel/kspp"
 	"github.com/talos-systems/talos/internal/pkg/kmsg"
 	"github.com/talos-systems/talos/internal/pkg/kubeconfig"
+	"github.com/talos-systems/talos/internal/pkg/measure"
+	"github.com/talos-systems/talos/internal/pkg/mgmttunnel"
 	"github.com/talos-systems/talos/internal/pkg/mount"
+	"github.com/talos-systems/talos/internal/pkg/nfs"
+	"github.com/talos-systems/talos/internal/pkg/quota"
+	"github.com/talos-systems/talos/internal/pkg/tpm2"
+	"github.com/talos-systems/talos/internal/pkg/volumes"
+	"github.com/talos-systems/talos/pkg/attestation"
 	"github.com/talos-systems/talos/pkg/cmd"
 	"github.com/talos-systems/talos/pkg/conditions"
+	"github.com/talos-systems/talos/pkg/download"
 	"github.com/talos-systems/talos/pkg/images"
 	"github.com/talos-systems/talos/pkg/kubernetes"
 	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/config"
 	"github.com/talos-systems/talos/pkg/machinery/config/configloader"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
@@ -104,6 +127,109 @@ func SetupLogger(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionF
 	}, "setupLogger"
 }
 
+// SetupConsoleLogging represents the SetupConsoleLogging task.
+//
+// Default console output (all machined logs echoed by the kernel) is kept
+// unless the machine config restricts it or redirects it to a specific device.
+//
+//nolint: gocyclo
+func SetupConsoleLogging(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		cfg := r.Config().Machine().Logging().Console()
+
+		if cfg.Mode() == constants.ConsoleModeFull && cfg.Device() == constants.DefaultConsoleDevice && cfg.RateLimit() == 0 {
+			return nil
+		}
+
+		// Talos messages are written to /dev/kmsg with the default (warning) level,
+		// so console log level 4 hides them, while keeping kernel errors visible.
+		consoleLogLevel := "4"
+
+		if cfg.Mode() == constants.ConsoleModeNone {
+			consoleLogLevel = "1"
+		}
+
+		if err = sysctl.WriteSystemProperty(&sysctl.SystemProperty{
+			Key:   "kernel.printk",
+			Value: consoleLogLevel,
+		}); err != nil {
+			return fmt.Errorf("failed to set console log level: %w", err)
+		}
+
+		if cfg.Mode() == constants.ConsoleModeNone {
+			return nil
+		}
+
+		f, err := os.OpenFile(cfg.Device(), os.O_WRONLY|unix.O_CLOEXEC|unix.O_NOCTTY, 0)
+		if err != nil {
+			return fmt.Errorf("failed to open console device: %w", err)
+		}
+
+		w := console.NewWriter(f, cfg.RateLimit())
+
+		if cfg.Mode() == constants.ConsoleModeFull {
+			var machinedLog io.WriteCloser
+
+			machinedLog, err = r.Logging().ServiceLog("machined").Writer()
+			if err != nil {
+				return err
+			}
+
+			return kmsg.SetupLogger(nil, "[talos]", io.MultiWriter(machinedLog, w))
+		}
+
+		critical := cfg.Mode() == constants.ConsoleModeCritical
+
+		return r.Events().Watch(func(events <-chan runtime.Event) {
+			for event := range events {
+				if line := formatConsoleEvent(event, critical); line != "" {
+					fmt.Fprintf(w, "[talos] %s\n", line) //nolint: errcheck
+				}
+			}
+		})
+	}, "setupConsoleLogging"
+}
+
+// formatConsoleEvent renders an event as a single console line.
+//
+// If critical is set, only failures are rendered.
+func formatConsoleEvent(event runtime.Event, critical bool) string {
+	switch msg := event.Payload.(type) {
+	case *machineapi.SequenceEvent:
+		if msg.Error != nil {
+			return fmt.Sprintf("sequence %s: %s", msg.Sequence, msg.Error.Message)
+		}
+
+		if !critical {
+			return fmt.Sprintf("sequence %s: %s", msg.Sequence, strings.ToLower(msg.Action.String()))
+		}
+	case *machineapi.PhaseEvent:
+		if msg.Error != nil {
+			return fmt.Sprintf("phase %s: %s", msg.Phase, msg.Error.Message)
+		}
+
+		if !critical {
+			return fmt.Sprintf("phase %s: %s", msg.Phase, strings.ToLower(msg.Action.String()))
+		}
+	case *machineapi.TaskEvent:
+		if msg.Error != nil {
+			return fmt.Sprintf("task %s: %s", msg.Task, msg.Error.Message)
+		}
+
+		if !critical {
+			return fmt.Sprintf("task %s: %s", msg.Task, strings.ToLower(msg.Action.String()))
+		}
+	case *machineapi.ServiceStateEvent:
+		if !critical || msg.Action == machineapi.ServiceStateEvent_FAILED {
+			return fmt.Sprintf("service %s: %s: %s", msg.Service, strings.ToLower(msg.Action.String()), msg.Message)
+		}
+	case *machineapi.ConfigLoadErrorEvent:
+		return fmt.Sprintf("config load error: %s", msg.Error)
+	}
+
+	return ""
+}
+
 // EnforceKSPPRequirements represents the EnforceKSPPRequirements task.
 func EnforceKSPPRequirements(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
@@ -443,12 +569,34 @@ func SetupDiscoveryNetwork(seq runtime.Sequence, data interface{}) (runtime.Task
 func LoadConfig(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
 		download := func() error {
-			var b []byte
+			var (
+				b []byte
+				e error
+			)
+
+			for attempt := 1; ; attempt++ {
+				b, e = fetchConfigAttempt(r)
+				if e == nil || errors.Is(e, perrors.ErrNoConfigSource) {
+					break
+				}
+
+				r.Events().Publish(&machineapi.ConfigLoadErrorEvent{
+					Error: e.Error(),
+				})
+
+				if attempt == constants.ConfigLoadAttempts {
+					return e
+				}
+
+				logger.Printf("failed to fetch config (attempt %d/%d): %s", attempt, constants.ConfigLoadAttempts, e)
 
-			fetchCtx, ctxCancel := context.WithTimeout(context.Background(), 70*time.Second)
-			defer ctxCancel()
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(constants.ConfigLoadRetryInterval):
+				}
+			}
 
-			b, e := fetchConfig(fetchCtx, r)
 			if errors.Is(e, perrors.ErrNoConfigSource) {
 				logger.Println("starting maintenance service")
 
@@ -471,20 +619,26 @@ func LoadConfig(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFu
 			return nil
 		}
 
-		cfg, err := configloader.NewFromFile(constants.ConfigPath)
-		if err != nil {
-			logger.Printf("downloading config")
+		if r.State().Machine().IsMaintenanceRequested() {
+			logger.Println("maintenance mode requested, starting maintenance service")
 
-			return download()
+			b, err := receiveConfigViaMaintenanceService(ctx, logger, r)
+			if err != nil {
+				return fmt.Errorf("failed to receive config via maintenance service: %w", err)
+			}
+
+			return r.SetConfig(b)
 		}
 
-		if !cfg.Persist() {
-			logger.Printf("found existing config, but persistence is disabled, downloading config")
+		cfg, err := configstore.Default.Load(ctx)
+		if err != nil {
+			logger.Printf("downloading config")
 
 			return download()
 		}
 
-		logger.Printf("persistence is enabled, using existing config on disk")
+		// stored config always takes precedence over the platform config source
+		logger.Printf("using existing config (%s backend)", cfg.Machine().Features().ConfigStorage().Backend())
 
 		b, err := cfg.Bytes()
 		if err != nil {
@@ -506,14 +660,7 @@ func SaveConfig(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFu
 			return err
 		}
 
-		var b []byte
-
-		b, err = r.Config().Bytes()
-		if err != nil {
-			return err
-		}
-
-		return ioutil.WriteFile(constants.ConfigPath, b, 0o600)
+		return configstore.Default.Store(ctx, r.Config())
 	}, "saveConfig"
 }
 
@@ -538,6 +685,13 @@ func singleTimeSync(ctx context.Context) (cancel context.CancelFunc) {
 	return
 }
 
+func fetchConfigAttempt(r runtime.Runtime) ([]byte, error) {
+	fetchCtx, ctxCancel := context.WithTimeout(context.Background(), constants.ConfigLoadTimeout)
+	defer ctxCancel()
+
+	return fetchConfig(fetchCtx, r)
+}
+
 func fetchConfig(ctx context.Context, r runtime.Runtime) (out []byte, err error) {
 	cancel := singleTimeSync(ctx)
 	defer cancel()
@@ -623,7 +777,13 @@ func ResetNetwork(seq runtime.Sequence, data interface{}) (runtime.TaskExecution
 // SetUserEnvVars represents the SetUserEnvVars task.
 func SetUserEnvVars(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
-		for key, val := range r.Config().Machine().Env() {
+		for _, env := range environment.Get(r.Config()) {
+			key, val := env, ""
+
+			if idx := strings.Index(env, "="); idx >= 0 {
+				key, val = env[:idx], env[idx+1:]
+			}
+
 			if err = os.Setenv(key, val); err != nil {
 				return fmt.Errorf("failed to set enivronment variable: %w", err)
 			}
@@ -704,6 +864,18 @@ func StartAllServices(seq runtime.Sequence, data interface{}) (runtime.TaskExecu
 			)
 		}
 
+		if r.Config().Cluster().Discovery().Enabled() {
+			svcs.Load(
+				&services.Discovery{},
+			)
+		}
+
+		if r.State().Platform().Mode() != runtime.ModeContainer && r.Config().Machine().Features().ISCSI().Enabled() {
+			svcs.Load(
+				&services.ISCSId{},
+			)
+		}
+
 		switch r.Config().Machine().Type() {
 		case machine.TypeInit:
 			svcs.Load(
@@ -721,17 +893,54 @@ func StartAllServices(seq runtime.Sequence, data interface{}) (runtime.TaskExecu
 			return fmt.Errorf("unexpected machine type: %s", r.Config().Machine().Type())
 		}
 
+		if r.Config().Machine().Type() != machine.TypeJoin && r.Config().Cluster().Etcd().Maintenance().Enabled() {
+			svcs.Load(
+				&services.EtcdMaintenance{},
+			)
+		}
+
+		if r.Config().Machine().Type() != machine.TypeJoin && controlplane.Enabled(r.Config().Cluster()) {
+			svcs.Load(
+				&services.ControlPlaneCerts{},
+			)
+		}
+
+		if r.Config().Machine().Type() != machine.TypeJoin {
+			svcs.Load(
+				&services.ManifestSync{},
+			)
+		}
+
+		if r.Config().Machine().Type() != machine.TypeJoin && r.Config().Machine().Kubelet().ServerCertRotation() && r.Config().Cluster().Discovery().Enabled() {
+			svcs.Load(
+				&services.KubeletCSRApprover{},
+			)
+		}
+
+		for _, spec := range r.Config().Machine().ExtensionServices() {
+			svcs.Load(
+				&services.Extension{Spec: spec},
+			)
+		}
+
 		system.Services(r).StartAll()
 
 		all := []conditions.Condition{}
 
-		logger.Printf("waiting for %d services", len(svcs.List()))
-
 		for _, svc := range svcs.List() {
-			cond := system.WaitForService(system.StateEventUp, svc.AsProto().GetId())
+			id := svc.AsProto().GetId()
+
+			// extension services are supplied by the operator and should not block the boot
+			if strings.HasPrefix(id, constants.ExtensionServicePrefix) {
+				continue
+			}
+
+			cond := system.WaitForService(system.StateEventUp, id)
 			all = append(all, cond)
 		}
 
+		logger.Printf("waiting for %d services", len(all))
+
 		ctx, cancel := context.WithTimeout(ctx, constants.BootkubeRunTimeout)
 
 		defer cancel()
@@ -764,11 +973,13 @@ func VerifyInstallation(seq runtime.Sequence, data interface{}) (runtime.TaskExe
 			next    string
 		)
 
-		grub := &grub.Grub{
-			BootDisk: r.Config().Machine().Install().Disk(),
+		var b bootloader.Bootloader
+
+		if b, err = bootloader.New(bootloader.Probe()); err != nil {
+			return err
 		}
 
-		current, next, err = grub.Labels()
+		current, next, err = b.Labels()
 		if err != nil {
 			return err
 		}
@@ -781,6 +992,106 @@ func VerifyInstallation(seq runtime.Sequence, data interface{}) (runtime.TaskExe
 	}, "verifyInstallation"
 }
 
+// MeasureBoot represents the MeasureBoot task.
+//
+// Boot assets of the installation, kernel command line and machine configuration are measured
+// into the TPM PCR, so that the node state can be attested. Measurement failures are not fatal,
+// as they are detected by the attestation.
+func MeasureBoot(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		t, err := tpm2.Open()
+		if err != nil {
+			if errors.Is(err, tpm2.ErrNotFound) {
+				logger.Println("TPM not found, skipping boot measurements")
+
+				return nil
+			}
+
+			logger.Printf("failed to open TPM, skipping boot measurements: %s", err)
+
+			return nil
+		}
+
+		//nolint: errcheck
+		defer t.Close()
+
+		m := measure.NewMeasurer(t, constants.MeasurementPCR)
+
+		defer func() {
+			if e := m.WriteLog(constants.MeasurementLogPath); e != nil {
+				logger.Printf("failed to write measurement log: %s", e)
+			}
+		}()
+
+		cmdline, err := ioutil.ReadFile("/proc/cmdline")
+		if err != nil {
+			return err
+		}
+
+		if r.State().Machine().Installed() {
+			if err = measureBootAssets(m, procfs.NewCmdline(string(cmdline))); err != nil {
+				logger.Printf("failed to measure boot assets: %s", err)
+
+				return nil
+			}
+		}
+
+		if err = m.Measure(attestation.EventCmdline, "/proc/cmdline", bytes.NewReader(cmdline)); err != nil {
+			logger.Printf("failed to measure kernel command line: %s", err)
+
+			return nil
+		}
+
+		cfg, err := r.Config().Bytes()
+		if err != nil {
+			return err
+		}
+
+		if err = m.Measure(attestation.EventConfig, "machine config", bytes.NewReader(cfg)); err != nil {
+			logger.Printf("failed to measure machine config: %s", err)
+
+			return nil
+		}
+
+		logger.Printf("measured %d boot events into PCR %d", len(m.Events), constants.MeasurementPCR)
+
+		return nil
+	}, "measureBoot"
+}
+
+func measureBootAssets(m *measure.Measurer, cmdline *procfs.Cmdline) error {
+	assets, err := measure.BootAssets(cmdline)
+	if err != nil {
+		return err
+	}
+
+	if len(assets) == 0 {
+		return nil
+	}
+
+	for _, label := range []string{constants.BootPartitionLabel, constants.EFIPartitionLabel} {
+		label := label
+
+		if err = mount.SystemPartitionMount(label, mount.WithReadOnly(true)); err != nil {
+			return err
+		}
+
+		defer func() {
+			if e := mount.SystemPartitionUnmount(label); e != nil {
+				log.Printf("failed unmounting %s partition: %s", label, e)
+			}
+		}()
+	}
+
+	for _, asset := range assets {
+		if err = m.MeasureFile(asset.Type, asset.Path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // MountOverlayFilesystems represents the MountOverlayFilesystems task.
 func MountOverlayFilesystems(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
@@ -826,6 +1137,146 @@ func SetupVarDirectory(seq runtime.Sequence, data interface{}) (runtime.TaskExec
 	}, "setupVarDirectory"
 }
 
+// EnableLogPersistence represents the EnableLogPersistence task.
+func EnableLogPersistence(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		manager, ok := r.Logging().(runtime.PersistentLoggingManager)
+		if !ok {
+			logger.Println("logging manager doesn't support persistence, skipping")
+
+			return nil
+		}
+
+		if err = os.MkdirAll(constants.PersistentLogPath, 0o700); err != nil {
+			return err
+		}
+
+		persistence := r.Config().Machine().Logging().Persistence()
+
+		return manager.EnablePersistence(constants.PersistentLogPath, persistence.MaxSize(), persistence.MaxFiles())
+	}, "enableLogPersistence"
+}
+
+// LoadKernelModules represents the LoadKernelModules task.
+//
+// Modules are loaded after udevd is started, so that device nodes of the modules are populated.
+// NFS client modules are loaded before the modules from the machine config, if the NFS client is enabled.
+func LoadKernelModules(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		if r.Config().Machine().Features().NFSMounts().Enabled() {
+			for _, module := range nfs.KernelModules {
+				if err = kmod.Load(module, nil); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, module := range r.Config().Machine().Kernel().Modules() {
+			if err = kmod.Load(module.Name(), module.Parameters()); err != nil {
+				return err
+			}
+
+			logger.Printf("loaded kernel module %q", module.Name())
+		}
+
+		return nil
+	}, "loadKernelModules"
+}
+
+// ConfigureCPUIsolation represents the ConfigureCPUIsolation task.
+//
+// IRQ affinity is applied on every boot, while the CPU isolation kernel arguments are applied on install and upgrade,
+// so the task warns if the running kernel was booted with the different arguments.
+func ConfigureCPUIsolation(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		cfg := r.Config().Machine().Kernel()
+
+		for _, arg := range []struct {
+			name  string
+			value string
+		}{
+			{"isolcpus", cfg.IsolatedCPUs()},
+			{"nohz_full", cfg.NohzFullCPUs()},
+			{"irqaffinity", cfg.IRQAffinityCPUs()},
+		} {
+			var current string
+
+			if val := procfs.ProcCmdline().Get(arg.name).First(); val != nil {
+				current = *val
+			}
+
+			if current != arg.value {
+				logger.Printf("WARNING: kernel argument %s=%q doesn't match the machine config %q, upgrade the node to apply it", arg.name, current, arg.value)
+			}
+		}
+
+		if cfg.IRQAffinityCPUs() == "" {
+			return nil
+		}
+
+		cpus, err := hardware.ParseCPUList(cfg.IRQAffinityCPUs())
+		if err != nil {
+			return err
+		}
+
+		skipped, err := irq.SetAffinity(cpus)
+		if err != nil {
+			return err
+		}
+
+		logger.Printf("set IRQ affinity to CPUs %s, %d interrupts can't be moved", cfg.IRQAffinityCPUs(), skipped)
+
+		return nil
+	}, "configureCPUIsolation"
+}
+
+// ConfigureBMC represents the ConfigureBMC task.
+//
+// Failure to configure the BMC doesn't abort the boot, as the machine might not have a BMC.
+func ConfigureBMC(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		cfg := r.Config().Machine().BMC()
+
+		ipmiDev, err := bmc.Open()
+		if err != nil {
+			logger.Printf("failed to open IPMI device, skipping BMC configuration: %s", err)
+
+			return nil
+		}
+
+		// nolint: errcheck
+		defer ipmiDev.Close()
+
+		if cfg.DHCP() {
+			err = bmc.SetDHCP(ipmiDev, cfg.Channel())
+		} else {
+			var (
+				address *net.IPNet
+				ip      net.IP
+				gateway net.IP
+			)
+
+			if ip, address, err = net.ParseCIDR(cfg.Address()); err != nil {
+				return err
+			}
+
+			address.IP = ip
+
+			if cfg.Gateway() != "" {
+				gateway = net.ParseIP(cfg.Gateway())
+			}
+
+			err = bmc.SetStatic(ipmiDev, cfg.Channel(), address, gateway)
+		}
+
+		if err != nil {
+			logger.Printf("failed to configure BMC: %s", err)
+		}
+
+		return nil
+	}, "configureBMC"
+}
+
 // MountUserDisks represents the MountUserDisks task.
 func MountUserDisks(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
@@ -833,6 +1284,10 @@ func MountUserDisks(seq runtime.Sequence, data interface{}) (runtime.TaskExecuti
 			return err
 		}
 
+		if err = setupVolumes(logger, r); err != nil {
+			return err
+		}
+
 		return mountDisks(r)
 	}, "mountUserDisks"
 }
@@ -890,10 +1345,11 @@ func partitionAndFormatDisks(logger *log.Logger, r runtime.Runtime) (err error)
 		for _, part := range disk.Partitions() {
 			extraTarget := &installer.Target{
 				Device:         disk.Device(),
+				Label:          part.Label(),
 				Size:           part.Size(),
 				Force:          true,
 				PartitionType:  installer.LinuxFilesystemData,
-				FileSystemType: installer.FilesystemTypeXFS,
+				FileSystemType: part.Filesystem(),
 			}
 
 			m.Targets[disk.Device()] = append(m.Targets[disk.Device()], extraTarget)
@@ -907,25 +1363,104 @@ func partitionAndFormatDisks(logger *log.Logger, r runtime.Runtime) (err error)
 	return nil
 }
 
-func mountDisks(r runtime.Runtime) (err error) {
-	mountpoints := mount.NewMountPoints()
-
-	for _, disk := range r.Config().Machine().Disks() {
-		for i, part := range disk.Partitions() {
-			var partname string
+// setupVolumes assembles RAID arrays, creates volume groups and logical volumes, and formats new logical volumes.
+func setupVolumes(logger *log.Logger, r runtime.Runtime) (err error) {
+	for _, raid := range r.Config().Machine().RAIDs() {
+		var created bool
 
-			partname, err = util.PartPath(disk.Device(), i+1)
-			if err != nil {
+		if created, err = volumes.EnsureRAID(raid.Name(), raid.Level(), raid.Devices()); err != nil {
+			return err
+		}
+
+		if created {
+			logger.Printf("created RAID array %q", volumes.RAIDDevice(raid.Name()))
+		}
+	}
+
+	for _, vg := range r.Config().Machine().VolumeGroups() {
+		if err = volumes.EnsureVolumeGroup(vg.Name(), vg.PhysicalVolumes()); err != nil {
+			return err
+		}
+
+		for _, lv := range vg.LogicalVolumes() {
+			var created, formatted bool
+
+			if created, err = volumes.EnsureLogicalVolume(vg.Name(), lv.Name(), lv.Size()); err != nil {
 				return err
 			}
 
-			if _, err = os.Stat(part.MountPoint()); errors.Is(err, os.ErrNotExist) {
-				if err = os.MkdirAll(part.MountPoint(), 0o700); err != nil {
+			device := volumes.LogicalVolumeDevice(vg.Name(), lv.Name())
+
+			if created {
+				logger.Printf("created logical volume %q", device)
+			}
+
+			if lv.Filesystem() == volumes.FilesystemNone {
+				continue
+			}
+
+			if formatted, err = volumes.HasFilesystem(device); err != nil {
+				return err
+			}
+
+			if !formatted {
+				logger.Printf("formatting logical volume %q as %q", device, lv.Filesystem())
+
+				if err = volumes.Format(device, lv.Filesystem()); err != nil {
 					return err
 				}
 			}
+		}
+	}
+
+	return nil
+}
 
-			mountpoints.Set(partname, mount.NewMountPoint(partname, part.MountPoint(), "xfs", unix.MS_NOATIME, ""))
+func mountDisks(r runtime.Runtime) (err error) {
+	mountpoints := mount.NewMountPoints()
+
+	addMountPoint := func(device, mountpoint, filesystem string, options []string, setters ...mount.Option) error {
+		if _, err = os.Stat(mountpoint); errors.Is(err, os.ErrNotExist) {
+			if err = os.MkdirAll(mountpoint, 0o700); err != nil {
+				return err
+			}
+		}
+
+		flags, data := volumes.ParseMountOptions(options)
+
+		mountpoints.Set(device, mount.NewMountPoint(device, mountpoint, filesystem, flags, data, setters...))
+
+		return nil
+	}
+
+	for _, disk := range r.Config().Machine().Disks() {
+		for i, part := range disk.Partitions() {
+			if part.Filesystem() == volumes.FilesystemNone {
+				continue
+			}
+
+			var partname string
+
+			partname, err = util.PartPath(disk.Device(), i+1)
+			if err != nil {
+				return err
+			}
+
+			if err = addMountPoint(partname, part.MountPoint(), part.Filesystem(), part.MountOptions(), mount.WithResize(part.Grow())); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, vg := range r.Config().Machine().VolumeGroups() {
+		for _, lv := range vg.LogicalVolumes() {
+			if lv.MountPoint() == "" {
+				continue
+			}
+
+			if err = addMountPoint(volumes.LogicalVolumeDevice(vg.Name(), lv.Name()), lv.MountPoint(), lv.Filesystem(), lv.MountOptions()); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -948,7 +1483,7 @@ func WriteUserFiles(seq runtime.Sequence, data interface{}) (runtime.TaskExecuti
 			return fmt.Errorf("error generating extra files: %w", err)
 		}
 
-		extra, err := containerd.GenerateRegistriesConfig(r.Config().Machine().Registries())
+		extra, err := containerd.GenerateCRIConfig(r.Config().Machine().Registries(), r.Config().Machine().CRI())
 		if err != nil {
 			return err
 		}
@@ -958,6 +1493,14 @@ func WriteUserFiles(seq runtime.Sequence, data interface{}) (runtime.TaskExecuti
 		for _, f := range files {
 			content := f.Content()
 
+			if f.ContentFrom().URL() != "" {
+				if content, err = fetchFileContent(ctx, f.ContentFrom()); err != nil {
+					result = multierror.Append(result, fmt.Errorf("error fetching contents of %q: %w", f.Path(), err))
+
+					continue
+				}
+			}
+
 			switch f.Op() {
 			case "create":
 				// Allow create at all times.
@@ -983,14 +1526,21 @@ func WriteUserFiles(seq runtime.Sequence, data interface{}) (runtime.TaskExecuti
 					continue
 				}
 
-				content = string(existingFileContents) + "\n" + f.Content()
+				content = string(existingFileContents) + "\n" + content
 			default:
 				result = multierror.Append(result, fmt.Errorf("unknown operation for file %q: %q", f.Path(), f.Op()))
 
 				continue
 			}
 
-			if filepath.Dir(f.Path()) == constants.ManifestsDirectory {
+			// Files in /var and in the overlay mounted directories are written in place.
+			if isWritableFilePath(f.Path()) {
+				if err = os.MkdirAll(filepath.Dir(f.Path()), 0o755); err != nil {
+					result = multierror.Append(result, err)
+
+					continue
+				}
+
 				if err = ioutil.WriteFile(f.Path(), []byte(content), f.Permissions()); err != nil {
 					result = multierror.Append(result, err)
 
@@ -1006,24 +1556,12 @@ func WriteUserFiles(seq runtime.Sequence, data interface{}) (runtime.TaskExecuti
 				continue
 			}
 
-			// Determine if supplied path is in /var or not.
-			// If not, we'll write it to /var anyways and bind mount below
-			p := f.Path()
-			inVar := true
-			parts := strings.Split(
-				strings.TrimLeft(f.Path(), "/"),
-				string(os.PathSeparator),
-			)
-
-			if parts[0] != "var" {
-				p = filepath.Join("/var", f.Path())
-				inVar = false
-			}
+			// Other paths (e.g. the generated CRI config) are read-only,
+			// the files are written to /var and bind mounted over the original ones.
+			p := filepath.Join("/var", f.Path())
 
-			// We do not want to support creating new files anywhere outside of
-			// /var. If a valid use case comes up, we can reconsider then.
-			if !inVar && f.Op() == "create" {
-				return fmt.Errorf("create operation not allowed outside of /var: %q", f.Path())
+			if f.Op() == "create" {
+				return fmt.Errorf("create operation not allowed outside of /var and %s: %q", strings.Join(constants.MachineFilesOverlayPaths, ", "), f.Path())
 			}
 
 			if err = os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
@@ -1044,10 +1582,8 @@ func WriteUserFiles(seq runtime.Sequence, data interface{}) (runtime.TaskExecuti
 				continue
 			}
 
-			if !inVar {
-				if err = unix.Mount(p, f.Path(), "", unix.MS_BIND|unix.MS_RDONLY, ""); err != nil {
-					result = multierror.Append(result, fmt.Errorf("failed to create bind mount for %s: %w", p, err))
-				}
+			if err = unix.Mount(p, f.Path(), "", unix.MS_BIND|unix.MS_RDONLY, ""); err != nil {
+				result = multierror.Append(result, fmt.Errorf("failed to create bind mount for %s: %w", p, err))
 			}
 		}
 
@@ -1069,6 +1605,33 @@ func doesNotExists(p string) (err error) {
 	return fmt.Errorf("file exists")
 }
 
+// isWritableFilePath returns true if the path is in /var or in one of the overlay mounted directories.
+func isWritableFilePath(p string) bool {
+	for _, dir := range append([]string{"/var"}, constants.MachineFilesOverlayPaths...) {
+		if strings.HasPrefix(filepath.Clean(p), dir+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fetchFileContent downloads the file contents and verifies the checksum.
+func fetchFileContent(ctx context.Context, source config.FileContentSource) (string, error) {
+	b, err := download.Download(ctx, source.URL(), download.WithHeaders(source.Headers()))
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+
+	if actual := hex.EncodeToString(sum[:]); actual != strings.ToLower(source.SHA256()) {
+		return "", fmt.Errorf("checksum mismatch: expected %s, got %s", source.SHA256(), actual)
+	}
+
+	return string(b), nil
+}
+
 func existsAndIsFile(p string) (err error) {
 	var info os.FileInfo
 
@@ -1103,6 +1666,13 @@ func WriteUserSysctls(seq runtime.Sequence, data interface{}) (runtime.TaskExecu
 	}, "writeUserSysctls"
 }
 
+// RenderStaticPods represents the RenderStaticPods task.
+func RenderStaticPods(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		return controlplane.Render(r.Config())
+	}, "renderStaticPods"
+}
+
 // UnmountOverlayFilesystems represents the UnmountOverlayFilesystems task.
 func UnmountOverlayFilesystems(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
@@ -1161,7 +1731,11 @@ func UnmountPodMounts(seq runtime.Sequence, data interface{}) (runtime.TaskExecu
 // UnmountSystemDiskBindMounts represents the UnmountSystemDiskBindMounts task.
 func UnmountSystemDiskBindMounts(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
-		devname := r.State().Machine().Disk().BlockDevice.Device().Name()
+		devnames := []string{r.State().Machine().Disk().BlockDevice.Device().Name()}
+
+		if disk := r.Config().Machine().Install().EphemeralDisk(); disk != "" {
+			devnames = append(devnames, disk)
+		}
 
 		f, err := os.Open("/proc/mounts")
 		if err != nil {
@@ -1181,12 +1755,18 @@ func UnmountSystemDiskBindMounts(seq runtime.Sequence, data interface{}) (runtim
 			device := fields[0]
 			mountpoint := fields[1]
 
-			if strings.HasPrefix(device, devname) {
+			for _, devname := range devnames {
+				if !strings.HasPrefix(device, devname) {
+					continue
+				}
+
 				logger.Printf("unmounting %s\n", mountpoint)
 
 				if err = unix.Unmount(mountpoint, 0); err != nil {
 					return fmt.Errorf("error unmounting %s: %w", mountpoint, err)
 				}
+
+				break
 			}
 		}
 
@@ -1194,12 +1774,21 @@ func UnmountSystemDiskBindMounts(seq runtime.Sequence, data interface{}) (runtim
 	}, "unmountSystemDiskBindMounts"
 }
 
-// CordonAndDrainNode represents the task for stop all containerd tasks in the
-// k8s.io namespace.
+// CordonAndDrainNode represents the task for cordoning the node and draining
+// the pods from it using the kubelet credentials.
+//
+// Drain is limited by the configured drain timeout, the sequence proceeds when
+// the timeout is reached.
 //
 //nolint: dupl
 func CordonAndDrainNode(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		if _, err = os.Stat(constants.KubeletKubeconfig); os.IsNotExist(err) {
+			logger.Printf("kubelet kubeconfig is missing, skipping node drain")
+
+			return nil
+		}
+
 		var hostname string
 
 		if hostname, err = os.Hostname(); err != nil {
@@ -1212,7 +1801,17 @@ func CordonAndDrainNode(seq runtime.Sequence, data interface{}) (runtime.TaskExe
 			return err
 		}
 
-		if err = kubeHelper.CordonAndDrain(hostname); err != nil {
+		drainCtx, drainCtxCancel := context.WithTimeout(ctx, r.Config().Machine().Kubelet().DrainTimeout())
+		defer drainCtxCancel()
+
+		if err = kubeHelper.CordonAndDrain(drainCtx, hostname); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				// drain timeout should not block the sequence
+				logger.Printf("node drain timed out, proceeding: %s", err)
+
+				return nil
+			}
+
 			return err
 		}
 
@@ -1317,7 +1916,51 @@ func stopAndRemoveAllPods(stopAction cri.StopAction) runtime.TaskExecutionFunc {
 // ResetSystemDisk represents the task to reset the system disk.
 func ResetSystemDisk(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
-		return r.State().Machine().Disk().BlockDevice.Reset()
+		in, ok := data.(runtime.ResetOptions)
+		if !ok {
+			return fmt.Errorf("unexpected runtime data")
+		}
+
+		bd := r.State().Machine().Disk().BlockDevice
+
+		if in.GetWipeMode() == machineapi.ResetRequest_SECURE {
+			var method string
+
+			if method, err = bd.Wipe(); err != nil {
+				return fmt.Errorf("failed wiping system disk: %w", err)
+			}
+
+			logger.Printf("wiped system disk with %q", method)
+		}
+
+		if err = bd.Reset(); err != nil {
+			return err
+		}
+
+		disk := r.Config().Machine().Install().EphemeralDisk()
+		if disk == "" || disk == bd.Device().Name() {
+			return nil
+		}
+
+		// EPHEMERAL partition is placed on the separate disk
+		if bd, err = blockdevice.Open(disk); err != nil {
+			return err
+		}
+
+		// nolint: errcheck
+		defer bd.Close()
+
+		if in.GetWipeMode() == machineapi.ResetRequest_SECURE {
+			var method string
+
+			if method, err = bd.Wipe(); err != nil {
+				return fmt.Errorf("failed wiping ephemeral disk: %w", err)
+			}
+
+			logger.Printf("wiped ephemeral disk with %q", method)
+		}
+
+		return bd.Reset()
 	}, "resetSystemDisk"
 }
 
@@ -1330,6 +1973,12 @@ func ResetSystemDiskSpec(seq runtime.Sequence, data interface{}) (runtime.TaskEx
 		}
 
 		for _, target := range in.GetSystemDiskTargets() {
+			if in.GetWipeMode() == machineapi.ResetRequest_SECURE {
+				if err = target.Wipe(); err != nil {
+					return fmt.Errorf("failed wiping partition %s: %w", target, err)
+				}
+			}
+
 			if err = target.Format(); err != nil {
 				return fmt.Errorf("failed wiping partition %s: %w", target, err)
 			}
@@ -1341,6 +1990,30 @@ func ResetSystemDiskSpec(seq runtime.Sequence, data interface{}) (runtime.TaskEx
 	}, "resetSystemDiskSpec"
 }
 
+// RequestMaintenance represents the task for requesting the maintenance mode on the next boot.
+func RequestMaintenance(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		meta, err := bootloader.NewMeta()
+		if err != nil {
+			return err
+		}
+		// nolint: errcheck
+		defer meta.Close()
+
+		if !meta.ADV.SetTag(adv.Maintenance, "1") {
+			return fmt.Errorf("error adding maintenance tag")
+		}
+
+		if err = meta.Write(); err != nil {
+			return err
+		}
+
+		logger.Printf("maintenance mode requested on the next boot")
+
+		return nil
+	}, "requestMaintenance"
+}
+
 // VerifyDiskAvailability represents the task for verifying that the system
 // disk is not in use.
 func VerifyDiskAvailability(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
@@ -1443,6 +2116,54 @@ func Upgrade(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc,
 	}, "upgrade"
 }
 
+// SetupManagementTunnel represents the SetupManagementTunnel task.
+//
+// Failure to establish the tunnel doesn't stop the boot, as the node might still be reachable directly.
+func SetupManagementTunnel(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		apiURL := mgmttunnel.APIURL(r.Config())
+		if apiURL == "" {
+			return nil
+		}
+
+		nodeID, err := discovery.Identity(constants.NodeIdentityPath)
+		if err != nil {
+			return err
+		}
+
+		privateKey, err := wgtypes.GeneratePrivateKey()
+		if err != nil {
+			return err
+		}
+
+		var tunnel *mgmttunnel.Tunnel
+
+		err = retry.Exponential(constants.ManagementTunnelProvisionTimeout, retry.WithUnits(time.Second), retry.WithJitter(time.Second), retry.WithErrorLogging(true)).Retry(func() error {
+			tunnel, err = mgmttunnel.Provision(ctx, apiURL, nodeID, privateKey.PublicKey())
+			if err != nil {
+				return retry.ExpectedError(err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			logger.Printf("failed to provision management tunnel, continuing without it: %s", err)
+
+			return nil
+		}
+
+		if err = mgmttunnel.Up(constants.ManagementTunnelInterface, privateKey, tunnel); err != nil {
+			logger.Printf("failed to set up management tunnel, continuing without it: %s", err)
+
+			return nil
+		}
+
+		logger.Printf("management tunnel is up, node address %s, server %s", tunnel.NodeAddress, tunnel.ServerEndpoint)
+
+		return nil
+	}, "setupManagementTunnel"
+}
+
 // LabelNodeAsMaster represents the LabelNodeAsMaster task.
 func LabelNodeAsMaster(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
@@ -1595,13 +2316,160 @@ func UnmountStatePartition(seq runtime.Sequence, data interface{}) (runtime.Task
 // MountEphermeralPartition mounts the ephemeral partition.
 func MountEphermeralPartition(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) error {
-		return mount.SystemPartitionMount(constants.EphemeralPartitionLabel)
+		if cfg := r.Config().Machine().SystemDiskEncryption().Get(constants.EphemeralPartitionLabel); cfg != nil {
+			return mountEncryptedEphemeral(logger, r, cfg)
+		}
+
+		// EPHEMERAL partition with the fixed size is not grown
+		err := mount.SystemPartitionMount(constants.EphemeralPartitionLabel,
+			mount.WithResize(r.Config().Machine().Install().EphemeralSize() == 0),
+			mount.WithProjectQuota(len(r.Config().Machine().EphemeralQuotas()) > 0),
+		)
+		if err != nil {
+			return ephemeralEncryptionError(err)
+		}
+
+		return nil
 	}, "mountEphermeralPartition"
 }
 
+// SetupEphemeralQuotas represents the SetupEphemeralQuotas task.
+func SetupEphemeralQuotas(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		quotas := r.Config().Machine().EphemeralQuotas()
+
+		// parent directories are assigned to the project first, so that they don't override nested quotas
+		sort.Slice(quotas, func(i, j int) bool { return quotas[i].Path() < quotas[j].Path() })
+
+		for _, q := range quotas {
+			path := filepath.Clean(q.Path())
+
+			if err = quota.SetProjectQuota(constants.EphemeralMountPoint, path, q.Limit()); err != nil {
+				return err
+			}
+
+			logger.Printf("set quota %d bytes for %q", q.Limit(), path)
+		}
+
+		return nil
+	}, "setupEphemeralQuotas"
+}
+
+// GrowEphemeralPartition represents the task to grow the EPHEMERAL partition and the filesystem to the end of the disk.
+//
+// EPHEMERAL is the last partition, so it takes all the space added by the disk resize.
+func GrowEphemeralPartition(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		if r.Config().Machine().Install().EphemeralSize() != 0 {
+			logger.Printf("skipping, EPHEMERAL partition has the fixed size")
+
+			return nil
+		}
+
+		if encryption.IsMapped(constants.EphemeralMapperName) {
+			logger.Printf("skipping, encrypted EPHEMERAL partition is grown on the next boot")
+
+			return nil
+		}
+
+		mountpoint, err := mount.SystemMountPointForLabel(constants.EphemeralPartitionLabel)
+		if err != nil {
+			return err
+		}
+
+		devname, err := util.DevnameFromPartname(mountpoint.Source())
+		if err != nil {
+			return err
+		}
+
+		if err = rescanDisk(devname); err != nil {
+			return fmt.Errorf("error rescanning %q: %w", devname, err)
+		}
+
+		resized, err := mountpoint.ResizePartition()
+		if err != nil {
+			return fmt.Errorf("error resizing %q: %w", mountpoint.Source(), err)
+		}
+
+		// the filesystem is grown even if the partition was not resized, as the previous attempt might have been interrupted
+		if err = mountpoint.GrowFilesystem(); err != nil {
+			return err
+		}
+
+		if resized {
+			logger.Printf("grew partition %q to the end of the disk", mountpoint.Source())
+		}
+
+		return nil
+	}, "growEphemeralPartition"
+}
+
+// rescanDisk makes the kernel re-read the capacity of the SCSI disk.
+//
+// Other disks (e.g. virtio, NVMe) pick up the new capacity automatically.
+func rescanDisk(devname string) error {
+	err := ioutil.WriteFile(filepath.Join("/sys/class/block", devname, "device/rescan"), []byte("1"), 0o200)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+// MountImageCachePartition mounts the image cache partition (if it exists).
+func MountImageCachePartition(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) error {
+		mountpoint, err := mount.SystemMountPointForLabel(constants.ImageCachePartitionLabel, mount.WithReadOnly(true))
+		if err != nil {
+			return err
+		}
+
+		if mountpoint == nil {
+			return nil
+		}
+
+		mountpoints := mount.NewMountPoints()
+		mountpoints.Set(constants.ImageCachePartitionLabel, mountpoint)
+
+		return mount.Mount(mountpoints)
+	}, "mountImageCachePartition"
+}
+
+// UnmountImageCachePartition unmounts the image cache partition (if it's mounted).
+func UnmountImageCachePartition(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) error {
+		mountpoint, err := mount.SystemMountPointForLabel(constants.ImageCachePartitionLabel)
+		if err != nil {
+			return err
+		}
+
+		if mountpoint == nil {
+			return nil
+		}
+
+		mounted, err := mountpoint.IsMounted()
+		if err != nil || !mounted {
+			return err
+		}
+
+		return mountpoint.Unmount()
+	}, "unmountImageCachePartition"
+}
+
 // UnmountEphemeralPartition unmounts the ephemeral partition.
 func UnmountEphemeralPartition(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		// persistent logs are kept open on the EPHEMERAL partition
+		if manager, ok := r.Logging().(runtime.PersistentLoggingManager); ok {
+			if err = manager.DisablePersistence(); err != nil {
+				return err
+			}
+		}
+
+		if encryption.IsMapped(constants.EphemeralMapperName) {
+			return unmountEncryptedEphemeral()
+		}
+
 		return mount.SystemPartitionUnmount(constants.EphemeralPartitionLabel)
 	}, "unmountEphemeralPartition"
 }
@@ -1623,7 +2491,12 @@ func Install(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc,
 				r.Config().Machine().Registries(),
 				install.WithForce(true),
 				install.WithZero(r.Config().Machine().Install().Zero()),
-				install.WithExtraKernelArgs(r.Config().Machine().Install().ExtraKernelArgs()),
+				install.WithExtraKernelArgs(install.ExtraKernelArgs(r.Config().Machine())),
+				install.WithEphemeralDisk(r.Config().Machine().Install().EphemeralDisk()),
+				install.WithEphemeralSize(r.Config().Machine().Install().EphemeralSize()),
+				install.WithConsole(r.Config().Machine().Install().Console()),
+				install.WithEnrollSecureBootKeys(r.Config().Machine().Install().EnrollSecureBootKeys()),
+				install.WithImageVerification(r.Config().Machine().Features().ImageVerification()),
 			)
 			if err != nil {
 				return err
@@ -186,7 +186,8 @@ func TestSequencer_Boot(t *testing.T) {
 
 func TestSequencer_Reboot(t *testing.T) {
 	type args struct {
-		r runtime.Runtime
+		r  runtime.Runtime
+		in *machine.RebootRequest
 	}
 
 	tests := []struct {
@@ -201,7 +202,7 @@ func TestSequencer_Reboot(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := &Sequencer{}
-			if got := s.Reboot(tt.args.r); !reflect.DeepEqual(got, tt.want) {
+			if got := s.Reboot(tt.args.r, tt.args.in); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Sequencer.Reboot() = %v, want %v", got, tt.want)
 			}
 		})
@@ -235,7 +236,8 @@ func TestSequencer_Reset(t *testing.T) {
 
 func TestSequencer_Shutdown(t *testing.T) {
 	type args struct {
-		r runtime.Runtime
+		r  runtime.Runtime
+		in *machine.ShutdownRequest
 	}
 
 	tests := []struct {
@@ -250,7 +252,7 @@ func TestSequencer_Shutdown(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := &Sequencer{}
-			if got := s.Shutdown(tt.args.r); !reflect.DeepEqual(got, tt.want) {
+			if got := s.Shutdown(tt.args.r, tt.args.in); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Sequencer.Shutdown() = %v, want %v", got, tt.want)
 			}
 		})
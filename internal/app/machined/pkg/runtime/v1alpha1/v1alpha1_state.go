@@ -14,6 +14,8 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/adv"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/platform"
+	"github.com/talos-systems/talos/internal/pkg/etcd/maintenance"
+	"github.com/talos-systems/talos/internal/pkg/manifests"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
@@ -31,10 +33,14 @@ type MachineState struct {
 	stagedInstall         bool
 	stagedInstallImageRef string
 	stagedInstallOptions  []byte
+
+	maintenanceRequested bool
 }
 
 // ClusterState represents the cluster's state.
 type ClusterState struct {
+	etcdMaintenance maintenance.Status
+	manifests       manifests.Status
 }
 
 // NewState initializes and returns the v1alpha1 state.
@@ -86,7 +92,13 @@ func (s *MachineState) probeDisk() error {
 
 	var dev *probe.ProbedBlockDevice
 
-	dev, err := probe.GetDevWithFileSystemLabel(constants.EphemeralPartitionLabel)
+	// EPHEMERAL partition might be placed on a separate disk, so the system disk is located
+	// by the STATE partition, EPHEMERAL is used as a fallback for the legacy partition layout
+	dev, err := probe.GetDevWithFileSystemLabel(constants.StatePartitionLabel)
+	if err != nil {
+		dev, err = probe.GetDevWithFileSystemLabel(constants.EphemeralPartitionLabel)
+	}
+
 	if err == nil {
 		s.disk = dev
 	}
@@ -122,6 +134,13 @@ func (s *MachineState) probeDisk() error {
 		s.stagedInstallOptions = []byte(stagedInstallOptions)
 	}
 
+	if _, ok := meta.ADV.ReadTag(adv.Maintenance); ok {
+		// clear the maintenance flag, so that the node boots normally once it's configured
+		meta.ADV.DeleteTag(adv.Maintenance)
+
+		s.maintenanceRequested = meta.Write() == nil
+	}
+
 	return nil
 }
 
@@ -162,3 +181,18 @@ func (s *MachineState) StagedInstallImageRef() string {
 func (s *MachineState) StagedInstallOptions() []byte {
 	return s.stagedInstallOptions
 }
+
+// EtcdMaintenance implements the ClusterState interface.
+func (s *ClusterState) EtcdMaintenance() *maintenance.Status {
+	return &s.etcdMaintenance
+}
+
+// Manifests implements the ClusterState interface.
+func (s *ClusterState) Manifests() *manifests.Status {
+	return &s.manifests
+}
+
+// IsMaintenanceRequested implements the machine state interface.
+func (s *MachineState) IsMaintenanceRequested() bool {
+	return s.maintenanceRequested
+}
@@ -114,6 +114,11 @@ func (state *State) Get() Status {
 func (state *State) AsProto() *machineapi.ServiceHealth {
 	status := state.Get()
 
+	return status.AsProto()
+}
+
+// AsProto returns protobuf-ready health status.
+func (status *Status) AsProto() *machineapi.ServiceHealth {
 	// nolint: errcheck
 	tspb, _ := ptypes.TimestampProto(status.LastChange)
 
@@ -236,13 +236,32 @@ func (c *containerdRunner) newContainerOpts(image containerd.Image, specOpts []o
 func (c *containerdRunner) newOCISpecOpts(image oci.Image) []oci.SpecOpts {
 	specOpts := []oci.SpecOpts{
 		oci.WithImageConfig(image),
-		oci.WithProcessArgs(c.args.ProcessArgs...),
+	}
+
+	// empty process args keep the entrypoint of the image
+	if len(c.args.ProcessArgs) > 0 {
+		specOpts = append(specOpts, oci.WithProcessArgs(c.args.ProcessArgs...))
+	}
+
+	specOpts = append(specOpts,
 		oci.WithEnv(c.opts.Env),
 		oci.WithHostHostsFile,
 		oci.WithHostResolvconf,
-	}
+	)
 	specOpts = append(specOpts, c.opts.OCISpecOpts...)
 
+	if c.opts.CgroupPath != "" {
+		specOpts = append(specOpts, oci.WithCgroup(c.opts.CgroupPath))
+	}
+
+	if c.opts.Resources != nil {
+		specOpts = append(specOpts, WithResources(c.opts.Resources))
+	}
+
+	if c.opts.OOMScoreAdj != 0 {
+		specOpts = append(specOpts, WithOOMScoreAdj(c.opts.OOMScoreAdj))
+	}
+
 	return specOpts
 }
 
@@ -32,3 +32,31 @@ func WithRootfsPropagation(rp string) oci.SpecOpts {
 		return nil
 	}
 }
+
+// WithResources sets the linux resource CPU and memory fields, overriding the ones set by the other options.
+func WithResources(resources *specs.LinuxResources) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *specs.Spec) error {
+		if s.Linux.Resources == nil {
+			s.Linux.Resources = &specs.LinuxResources{}
+		}
+
+		if resources.CPU != nil {
+			s.Linux.Resources.CPU = resources.CPU
+		}
+
+		if resources.Memory != nil {
+			s.Linux.Resources.Memory = resources.Memory
+		}
+
+		return nil
+	}
+}
+
+// WithOOMScoreAdj sets the OOM score adjustment of the process.
+func WithOOMScoreAdj(adj int) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *specs.Spec) error {
+		s.Process.OOMScoreAdj = &adj
+
+		return nil
+	}
+}
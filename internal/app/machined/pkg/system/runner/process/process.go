@@ -8,11 +8,16 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
+	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/containerd/cgroups"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
@@ -123,6 +128,10 @@ func (p *processRunner) run(eventSink events.Recorder) error {
 
 	eventSink(events.StateRunning, "Process %s started with PID %d", p, cmd.Process.Pid)
 
+	if err = p.applyResources(cmd.Process.Pid); err != nil {
+		eventSink(events.StateRunning, "Failed to apply resource limits to %s: %s", p, err)
+	}
+
 	waitCh := make(chan error)
 
 	go func() {
@@ -159,6 +168,33 @@ func (p *processRunner) run(eventSink events.Recorder) error {
 	return logCloser.Close()
 }
 
+// applyResources moves the process to the cgroup of the service and sets its OOM score adjustment.
+func (p *processRunner) applyResources(pid int) error {
+	if p.opts.CgroupPath != "" {
+		resources := p.opts.Resources
+		if resources == nil {
+			resources = &specs.LinuxResources{}
+		}
+
+		cg, err := cgroups.New(cgroups.V1, cgroups.StaticPath(p.opts.CgroupPath), resources)
+		if err != nil {
+			return fmt.Errorf("error creating cgroup %q: %w", p.opts.CgroupPath, err)
+		}
+
+		if err = cg.Add(cgroups.Process{Pid: pid}); err != nil {
+			return fmt.Errorf("error adding process to cgroup %q: %w", p.opts.CgroupPath, err)
+		}
+	}
+
+	if p.opts.OOMScoreAdj != 0 {
+		if err := ioutil.WriteFile(fmt.Sprintf("/proc/%d/oom_score_adj", pid), []byte(strconv.Itoa(p.opts.OOMScoreAdj)), 0o644); err != nil {
+			return fmt.Errorf("error setting OOM score adjustment: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (p *processRunner) String() string {
 	return fmt.Sprintf("Process(%q)", p.args.ProcessArgs)
 }
@@ -12,6 +12,7 @@ import (
 
 	"github.com/containerd/containerd"
 	"github.com/containerd/containerd/oci"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/logging"
@@ -56,6 +57,12 @@ type Options struct {
 	GracefulShutdownTimeout time.Duration
 	// Stdin is the process standard input.
 	Stdin io.ReadSeeker
+	// CgroupPath is the cgroup the process is placed in.
+	CgroupPath string
+	// Resources describes the cgroup resource limits of the process.
+	Resources *specs.LinuxResources
+	// OOMScoreAdj is the OOM score adjustment of the process, zero keeps the inherited value.
+	OOMScoreAdj int
 }
 
 // Option is the functional option func.
@@ -135,3 +142,24 @@ func WithStdin(stdin io.ReadSeeker) Option {
 		args.Stdin = stdin
 	}
 }
+
+// WithCgroupPath sets the cgroup path.
+func WithCgroupPath(path string) Option {
+	return func(args *Options) {
+		args.CgroupPath = path
+	}
+}
+
+// WithResources sets the cgroup resource limits.
+func WithResources(resources *specs.LinuxResources) Option {
+	return func(args *Options) {
+		args.Resources = resources
+	}
+}
+
+// WithOOMScoreAdj sets the OOM score adjustment.
+func WithOOMScoreAdj(adj int) Option {
+	return func(args *Options) {
+		args.OOMScoreAdj = adj
+	}
+}
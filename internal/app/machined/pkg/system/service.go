@@ -53,3 +53,8 @@ type APIStoppableService interface {
 type APIRestartableService interface {
 	APIRestartAllowed(runtime.Runtime) bool
 }
+
+// APIReloadableService is a service which allows to reload its configuration via API without a restart.
+type APIReloadableService interface {
+	APIReload(context.Context, runtime.Runtime) error
+}
@@ -134,6 +134,13 @@ func (svcrunner *ServiceRunner) healthUpdate(change health.StateChange) {
 	isUp := svcrunner.inStateLocked(StateEventUp)
 	svcrunner.mu.Unlock()
 
+	if svcrunner.runtime != nil {
+		msg := event.AsProto(svcrunner.id)
+		msg.Health = change.New.AsProto()
+
+		svcrunner.runtime.Events().Publish(msg)
+	}
+
 	if isUp {
 		svcrunner.notifyEvent(StateEventUp)
 	}
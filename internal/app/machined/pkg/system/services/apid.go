@@ -43,7 +43,7 @@ func (o *APID) ID(r runtime.Runtime) string {
 
 // PreFunc implements the Service interface.
 func (o *APID) PreFunc(ctx context.Context, r runtime.Runtime) error {
-	return image.Import(ctx, "/usr/images/apid.tar", "talos/apid")
+	return image.Import(ctx, "/usr/images/apid.tar", "talos/apid", image.WithVerification(r.Config().Machine().Features().ImageVerification()))
 }
 
 // PostFunc implements the Service interface.
@@ -122,6 +122,21 @@ func (o *APID) Runner(r runtime.Runtime) (runner.Runner, error) {
 		{Type: "bind", Destination: filepath.Dir(constants.APISocketPath), Source: filepath.Dir(constants.APISocketPath), Options: []string{"rbind", "rw"}},
 	}
 
+	if localAPIAccess := r.Config().Machine().Features().LocalAPIAccess(); localAPIAccess.Enabled() {
+		// the socket directory is exposed to the workloads via hostPath, so it should be traversable by the socket group
+		if err := os.MkdirAll(filepath.Dir(constants.LocalAPISocketPath), 0o750); err != nil {
+			return nil, err
+		}
+
+		if err := os.Chown(filepath.Dir(constants.LocalAPISocketPath), 0, localAPIAccess.GroupID()); err != nil {
+			return nil, err
+		}
+
+		mounts = append(mounts, specs.Mount{
+			Type: "bind", Destination: filepath.Dir(constants.LocalAPISocketPath), Source: filepath.Dir(constants.LocalAPISocketPath), Options: []string{"rbind", "rw"},
+		})
+	}
+
 	env := []string{}
 
 	for key, val := range r.Config().Machine().Env() {
@@ -153,6 +168,7 @@ func (o *APID) Runner(r runtime.Runtime) (runner.Runner, error) {
 		runner.WithContainerdAddress(constants.SystemContainerdAddress),
 		runner.WithContainerImage(image),
 		runner.WithEnv(env),
+		withServiceResources(r, "apid"),
 		runner.WithOCISpecOpts(
 			oci.WithHostNamespace(specs.NetworkNamespace),
 			oci.WithMounts(mounts),
@@ -24,6 +24,7 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/containerd"
 	"github.com/talos-systems/talos/internal/pkg/containers/image"
+	"github.com/talos-systems/talos/internal/pkg/environment"
 	"github.com/talos-systems/talos/internal/pkg/etcd"
 	"github.com/talos-systems/talos/pkg/conditions"
 	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
@@ -93,7 +94,7 @@ func (b *Bootkube) PreFunc(ctx context.Context, r runtime.Runtime) (err error) {
 		}
 	}
 
-	return image.Import(ctx, "/usr/images/bootkube.tar", "talos/bootkube")
+	return image.Import(ctx, "/usr/images/bootkube.tar", "talos/bootkube", image.WithVerification(r.Config().Machine().Features().ImageVerification()))
 }
 
 // PostFunc implements the Service interface.
@@ -164,10 +165,7 @@ func (b *Bootkube) Runner(r runtime.Runtime) (runner.Runner, error) {
 		},
 	}
 
-	env := []string{}
-	for key, val := range r.Config().Machine().Env() {
-		env = append(env, fmt.Sprintf("%s=%s", key, val))
-	}
+	env := environment.Get(r.Config())
 
 	// Set the required kubelet mounts.
 	mounts := []specs.Mount{
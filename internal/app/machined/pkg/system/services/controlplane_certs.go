@@ -0,0 +1,146 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// nolint: golint
+package services
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"go.etcd.io/etcd/clientv3/concurrency"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/goroutine"
+	"github.com/talos-systems/talos/internal/pkg/controlplane"
+	"github.com/talos-systems/talos/internal/pkg/etcd"
+	"github.com/talos-systems/talos/pkg/conditions"
+	"github.com/talos-systems/talos/pkg/kubernetes"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// controlPlaneCertsMain is an entrypoint for the control plane certificates service.
+//
+// Expiration of the certificates issued for the control plane static pods is checked periodically,
+// and the certificates are re-issued before they expire.
+func controlPlaneCertsMain(ctx context.Context, r runtime.Runtime, logWriter io.Writer) error {
+	logger := log.New(logWriter, "", log.LstdFlags)
+
+	ticker := time.NewTicker(constants.KubernetesControlPlaneCertCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		certs, err := controlplane.IssuedCertificates()
+
+		switch {
+		case err != nil:
+			logger.Printf("error reading control plane certificates: %s", err)
+		case controlplane.NeedsRotation(certs, time.Now()):
+			logger.Printf("certificate %q expires at %s, rotating control plane certificates", certs[0].Source, certs[0].Certificate.NotAfter)
+
+			if err = rotateControlPlaneCerts(ctx, r); err != nil {
+				logger.Printf("error rotating control plane certificates: %s", err)
+			} else {
+				logger.Printf("control plane certificates rotated")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// rotateControlPlaneCerts re-issues the certificates holding the etcd lock, so that the control plane
+// is restarted on a single node at a time.
+func rotateControlPlaneCerts(ctx context.Context, r runtime.Runtime) error {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+
+	client, err := etcd.NewClient([]string{"127.0.0.1:2379"})
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer client.Close()
+
+	sess, err := concurrency.NewSession(client.Client)
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer sess.Close()
+
+	mu := concurrency.NewMutex(sess, constants.EtcdTalosControlPlaneCertsMutex)
+
+	if err = mu.Lock(ctx); err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer mu.Unlock(context.Background())
+
+	// certificates might have been rotated while waiting for the lock
+	certs, err := controlplane.IssuedCertificates()
+	if err != nil {
+		return err
+	}
+
+	if !controlplane.NeedsRotation(certs, time.Now()) {
+		return nil
+	}
+
+	k8sClient, err := kubernetes.NewTemporaryClientFromPKI(r.Config().Cluster().CA(), r.Config().Cluster().Endpoint())
+	if err != nil {
+		return err
+	}
+
+	return controlplane.Rotate(r.Config(), k8sClient, strings.ToLower(hostname))
+}
+
+// ControlPlaneCerts implements the Service interface. It serves as the concrete type with
+// the required methods.
+type ControlPlaneCerts struct{}
+
+// ID implements the Service interface.
+func (c *ControlPlaneCerts) ID(r runtime.Runtime) string {
+	return "control-plane-certs"
+}
+
+// PreFunc implements the Service interface.
+func (c *ControlPlaneCerts) PreFunc(ctx context.Context, r runtime.Runtime) error {
+	return nil
+}
+
+// PostFunc implements the Service interface.
+func (c *ControlPlaneCerts) PostFunc(r runtime.Runtime, state events.ServiceState) (err error) {
+	return nil
+}
+
+// Condition implements the Service interface.
+func (c *ControlPlaneCerts) Condition(r runtime.Runtime) conditions.Condition {
+	return nil
+}
+
+// DependsOn implements the Service interface.
+func (c *ControlPlaneCerts) DependsOn(r runtime.Runtime) []string {
+	return []string{"etcd", "kubelet"}
+}
+
+// Runner implements the Service interface.
+func (c *ControlPlaneCerts) Runner(r runtime.Runtime) (runner.Runner, error) {
+	return goroutine.NewRunner(r, "control-plane-certs", controlPlaneCertsMain, runner.WithLoggingManager(r.Logging())), nil
+}
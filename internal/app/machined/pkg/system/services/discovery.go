@@ -0,0 +1,96 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// nolint: golint
+package services
+
+import (
+	"context"
+	"io"
+	"log"
+	"time"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/goroutine"
+	"github.com/talos-systems/talos/internal/pkg/discovery"
+	"github.com/talos-systems/talos/pkg/conditions"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// discoveryMain is an entrypoint for the discovery service.
+//
+// Node is registered in the discovery registries and the registration is refreshed periodically.
+func discoveryMain(ctx context.Context, r runtime.Runtime, logWriter io.Writer) error {
+	logger := log.New(logWriter, "", log.LstdFlags)
+
+	id, err := discovery.Identity(constants.NodeIdentityPath)
+	if err != nil {
+		return err
+	}
+
+	logger.Printf("node identity %s", id)
+
+	ticker := time.NewTicker(constants.DiscoveryRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err = discoveryRegister(ctx, r, id); err != nil {
+			logger.Printf("error registering node: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func discoveryRegister(ctx context.Context, r runtime.Runtime, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, constants.DiscoveryRequestTimeout)
+	defer cancel()
+
+	member, err := discovery.LocalMember(r.Config(), id)
+	if err != nil {
+		return err
+	}
+
+	return discovery.Register(ctx, member, discovery.Registries(r.Config())...)
+}
+
+// Discovery implements the Service interface. It serves as the concrete type with
+// the required methods.
+type Discovery struct{}
+
+// ID implements the Service interface.
+func (d *Discovery) ID(r runtime.Runtime) string {
+	return "discovery"
+}
+
+// PreFunc implements the Service interface.
+func (d *Discovery) PreFunc(ctx context.Context, r runtime.Runtime) error {
+	return nil
+}
+
+// PostFunc implements the Service interface.
+func (d *Discovery) PostFunc(r runtime.Runtime, state events.ServiceState) (err error) {
+	return nil
+}
+
+// Condition implements the Service interface.
+func (d *Discovery) Condition(r runtime.Runtime) conditions.Condition {
+	return nil
+}
+
+// DependsOn implements the Service interface.
+func (d *Discovery) DependsOn(r runtime.Runtime) []string {
+	return []string{"networkd"}
+}
+
+// Runner implements the Service interface.
+func (d *Discovery) Runner(r runtime.Runtime) (runner.Runner, error) {
+	return goroutine.NewRunner(r, "discovery", discoveryMain, runner.WithLoggingManager(r.Logging())), nil
+}
@@ -37,9 +37,12 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/containerd"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/restart"
 	"github.com/talos-systems/talos/internal/pkg/containers/image"
+	"github.com/talos-systems/talos/internal/pkg/environment"
 	"github.com/talos-systems/talos/internal/pkg/etcd"
+	"github.com/talos-systems/talos/internal/pkg/nodeip"
 	"github.com/talos-systems/talos/pkg/argsbuilder"
 	"github.com/talos-systems/talos/pkg/conditions"
+	"github.com/talos-systems/talos/pkg/machinery/config"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
@@ -83,7 +86,13 @@ func (e *Etcd) PreFunc(ctx context.Context, r runtime.Runtime) (err error) {
 
 	// Pull the image and unpack it.
 	containerdctx := namespaces.WithNamespace(ctx, constants.SystemContainerdNamespace)
-	if _, err = image.Pull(containerdctx, r.Config().Machine().Registries(), client, r.Config().Cluster().Etcd().Image()); err != nil {
+
+	if err = image.ImportCache(containerdctx, client); err != nil {
+		return err
+	}
+
+	if _, err = image.Pull(containerdctx, r.Config().Machine().Registries(), client, r.Config().Cluster().Etcd().Image(),
+		image.WithVerification(r.Config().Machine().Features().ImageVerification())); err != nil {
 		return fmt.Errorf("failed to pull image %q: %w", r.Config().Cluster().Etcd().Image(), err)
 	}
 
@@ -137,10 +146,7 @@ func (e *Etcd) Runner(r runtime.Runtime) (runner.Runner, error) {
 		{Type: "bind", Destination: constants.EtcdDataPath, Source: constants.EtcdDataPath, Options: []string{"rbind", "rw"}},
 	}
 
-	env := []string{}
-	for key, val := range r.Config().Machine().Env() {
-		env = append(env, fmt.Sprintf("%s=%s", key, val))
-	}
+	env := environment.Get(r.Config())
 
 	if goruntime.GOARCH == "arm64" {
 		env = append(env, "ETCD_UNSUPPORTED_ARCH=arm64")
@@ -154,6 +160,7 @@ func (e *Etcd) Runner(r runtime.Runtime) (runner.Runner, error) {
 		runner.WithContainerdAddress(constants.SystemContainerdAddress),
 		runner.WithContainerImage(r.Config().Cluster().Etcd().Image()),
 		runner.WithEnv(env),
+		withServiceResources(r, "etcd"),
 		runner.WithOCISpecOpts(
 			oci.WithHostNamespace(specs.NetworkNamespace),
 			oci.WithMounts(mounts),
@@ -402,23 +409,26 @@ func (e *Etcd) argsForInit(ctx context.Context, r runtime.Runtime) error {
 		if meta, err = bootloader.NewMeta(); err != nil {
 			return err
 		}
-		// nolint: errcheck
-		defer meta.Close()
 
 		_, upgraded = meta.LegacyADV.ReadTag(adv.Upgrade)
+
+		// META stays locked while it's open, so close it before making any network calls
+		if err = meta.Close(); err != nil {
+			return err
+		}
 	}
 
-	primaryAddr, listenAddress, err := primaryAndListenAddresses()
+	primaryAddr, listenPeerAddresses, listenClientAddresses, err := primaryAndListenAddresses(r.Config().Cluster().Etcd())
 	if err != nil {
 		return fmt.Errorf("failed to calculate etcd addresses: %w", err)
 	}
 
-	// TODO(scm): see issue #2121 and description below in argsForControlPlane.
+	// Listen addresses can't be overridden with extraArgs, see argsForControlPlane.
 	denyListArgs := argsbuilder.Args{
 		"name":                  hostname,
 		"data-dir":              constants.EtcdDataPath,
-		"listen-peer-urls":      "https://" + net.FormatAddress(listenAddress) + ":2380",
-		"listen-client-urls":    "https://" + net.FormatAddress(listenAddress) + ":2379",
+		"listen-peer-urls":      formatURLs(listenPeerAddresses, 2380),
+		"listen-client-urls":    formatURLs(listenClientAddresses, 2379),
 		"cert-file":             constants.KubernetesEtcdPeerCert,
 		"key-file":              constants.KubernetesEtcdPeerKey,
 		"trusted-ca-file":       constants.KubernetesEtcdCACert,
@@ -486,12 +496,9 @@ func (e *Etcd) argsForControlPlane(ctx context.Context, r runtime.Runtime) error
 		return err
 	}
 
-	// TODO(scm):  With the current setup, the listen (bind) address is
-	// essentially hard-coded because we need to calculate it before we process
-	// extraArgs (which may contain special overrides from the user.
-	// This needs to be refactored to allow greater binding flexibility.
-	// Issue #2121.
-	primaryAddr, listenAddress, err := primaryAndListenAddresses()
+	// The listen (bind) addresses are calculated before we process extraArgs,
+	// so they are configured with the etcd listen subnets instead of extraArgs.
+	primaryAddr, listenPeerAddresses, listenClientAddresses, err := primaryAndListenAddresses(r.Config().Cluster().Etcd())
 	if err != nil {
 		return fmt.Errorf("failed to calculate etcd addresses: %w", err)
 	}
@@ -499,8 +506,8 @@ func (e *Etcd) argsForControlPlane(ctx context.Context, r runtime.Runtime) error
 	denyListArgs := argsbuilder.Args{
 		"name":                  hostname,
 		"data-dir":              constants.EtcdDataPath,
-		"listen-peer-urls":      "https://" + net.FormatAddress(listenAddress) + ":2380",
-		"listen-client-urls":    "https://" + net.FormatAddress(listenAddress) + ":2379",
+		"listen-peer-urls":      formatURLs(listenPeerAddresses, 2380),
+		"listen-client-urls":    formatURLs(listenClientAddresses, 2379),
 		"cert-file":             constants.KubernetesEtcdPeerCert,
 		"key-file":              constants.KubernetesEtcdPeerKey,
 		"trusted-ca-file":       constants.KubernetesEtcdCACert,
@@ -581,28 +588,83 @@ func IsDirEmpty(name string) (bool, error) {
 }
 
 // primaryAndListenAddresses calculates the primary (advertised) and listen (bind) addresses for etcd.
-func primaryAndListenAddresses() (primary, listen string, err error) {
+//
+// nolint: gocyclo
+func primaryAndListenAddresses(etcdConfig config.Etcd) (primary string, listenPeer, listenClient []string, err error) {
 	ips, err := net.IPAddrs()
 	if err != nil {
-		return "", "", fmt.Errorf("failed to discover interface IP addresses: %w", err)
+		return "", nil, nil, fmt.Errorf("failed to discover interface IP addresses: %w", err)
 	}
 
 	if len(ips) == 0 {
-		return "", "", errors.New("no valid unicast IP addresses on any interface")
+		return "", nil, nil, errors.New("no valid unicast IP addresses on any interface")
+	}
+
+	advertisedIPs := ips
+
+	if subnets := etcdConfig.AdvertisedSubnets(); len(subnets) > 0 {
+		advertisedIPs, err = nodeip.FilterIPs(ips, subnets)
+		if err != nil {
+			return "", nil, nil, err
+		}
+
+		if len(advertisedIPs) == 0 {
+			return "", nil, nil, fmt.Errorf("no addresses match etcd advertised subnets %v", subnets)
+		}
+	}
+
+	primary = advertisedIPs[0].String()
+
+	subnets := etcdConfig.ListenSubnets()
+	if len(subnets) == 0 {
+		// Regardless of primary selected IP, we should be liberal with our listen
+		// address, for maximum compatibility.
+		listen := "0.0.0.0"
+		if net.IsIPv6(ips...) {
+			listen = "::"
+		}
+
+		return primary, []string{listen}, []string{listen}, nil
+	}
+
+	listenIPs, err := nodeip.FilterIPs(ips, subnets)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	if len(listenIPs) == 0 {
+		return "", nil, nil, fmt.Errorf("no addresses match etcd listen subnets %v", subnets)
 	}
 
-	// NOTE: we will later likely want to expose the primary IP selection to the
-	// user or build it with greater flexibility.  For now, this maintains
-	// previous behavior.
-	primary = ips[0].String()
+	listensOnPrimary := false
 
-	// Regardless of primary selected IP, we should be liberal with our listen
-	// address, for maximum compatibility.  Again, this should probably be
-	// exposed later for greater control.
-	listen = "0.0.0.0"
+	for _, ip := range listenIPs {
+		listenPeer = append(listenPeer, ip.String())
+
+		if ip.Equal(advertisedIPs[0]) {
+			listensOnPrimary = true
+		}
+	}
+
+	if !listensOnPrimary {
+		return "", nil, nil, fmt.Errorf("etcd advertised address %s doesn't match etcd listen subnets %v", primary, subnets)
+	}
+
+	// local clients (e.g. kube-apiserver) always connect over the loopback
+	listenClient = append([]string{"127.0.0.1"}, listenPeer...)
 	if net.IsIPv6(ips...) {
-		listen = "::"
+		listenClient = append(listenClient, "::1")
+	}
+
+	return primary, listenPeer, listenClient, nil
+}
+
+func formatURLs(addrs []string, port int) string {
+	urls := make([]string, len(addrs))
+
+	for i, addr := range addrs {
+		urls[i] = fmt.Sprintf("https://%s:%d", net.FormatAddress(addr), port)
 	}
 
-	return primary, listen, nil
+	return strings.Join(urls, ",")
 }
@@ -0,0 +1,103 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// nolint: golint
+package services
+
+import (
+	"context"
+	"io"
+	"log"
+	"time"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/goroutine"
+	"github.com/talos-systems/talos/internal/pkg/etcd"
+	"github.com/talos-systems/talos/internal/pkg/etcd/maintenance"
+	"github.com/talos-systems/talos/pkg/conditions"
+)
+
+// etcdMaintenanceMain is an entrypoint for the etcd maintenance service.
+//
+// Maintenance is run periodically, the report of the last run is kept in the cluster state.
+func etcdMaintenanceMain(ctx context.Context, r runtime.Runtime, logWriter io.Writer) error {
+	logger := log.New(logWriter, "", log.LstdFlags)
+
+	config := r.Config().Cluster().Etcd().Maintenance()
+	status := r.State().Cluster().EtcdMaintenance()
+
+	ticker := time.NewTicker(config.Interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		report, err := etcdMaintenanceRun(ctx, maintenance.NewSettings(config), status.Report())
+		if err != nil {
+			report.Error = err.Error()
+
+			logger.Printf("etcd maintenance failed: %s", err)
+		}
+
+		for _, member := range report.Members {
+			if member.LastDefragmented.After(report.LastRun) {
+				logger.Printf("defragmented etcd member %q, database size %d bytes", member.Name, member.DBSize)
+			}
+		}
+
+		status.Update(report)
+	}
+}
+
+func etcdMaintenanceRun(ctx context.Context, settings maintenance.Settings, previous maintenance.Report) (maintenance.Report, error) {
+	client, err := etcd.NewClient([]string{"127.0.0.1:2379"})
+	if err != nil {
+		return maintenance.Report{LastRun: time.Now()}, err
+	}
+
+	//nolint: errcheck
+	defer client.Close()
+
+	return maintenance.Run(ctx, client, settings, previous)
+}
+
+// EtcdMaintenance implements the Service interface. It serves as the concrete type with
+// the required methods.
+type EtcdMaintenance struct{}
+
+// ID implements the Service interface.
+func (e *EtcdMaintenance) ID(r runtime.Runtime) string {
+	return "etcd-maintenance"
+}
+
+// PreFunc implements the Service interface.
+func (e *EtcdMaintenance) PreFunc(ctx context.Context, r runtime.Runtime) error {
+	return nil
+}
+
+// PostFunc implements the Service interface.
+func (e *EtcdMaintenance) PostFunc(r runtime.Runtime, state events.ServiceState) (err error) {
+	return nil
+}
+
+// Condition implements the Service interface.
+func (e *EtcdMaintenance) Condition(r runtime.Runtime) conditions.Condition {
+	return nil
+}
+
+// DependsOn implements the Service interface.
+func (e *EtcdMaintenance) DependsOn(r runtime.Runtime) []string {
+	return []string{"etcd"}
+}
+
+// Runner implements the Service interface.
+func (e *EtcdMaintenance) Runner(r runtime.Runtime) (runner.Runner, error) {
+	return goroutine.NewRunner(r, "etcd-maintenance", etcdMaintenanceMain, runner.WithLoggingManager(r.Logging())), nil
+}
@@ -16,3 +16,7 @@ import (
 func TestEtcdInterfaces(t *testing.T) {
 	assert.Implements(t, (*system.HealthcheckedService)(nil), new(services.Etcd))
 }
+
+func TestEtcdMaintenanceInterfaces(t *testing.T) {
+	assert.Implements(t, (*system.Service)(nil), new(services.EtcdMaintenance))
+}
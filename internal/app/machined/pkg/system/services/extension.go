@@ -0,0 +1,147 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package services
+
+import (
+	"context"
+	"fmt"
+
+	containerdapi "github.com/containerd/containerd"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/oci"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/containerd"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/restart"
+	"github.com/talos-systems/talos/internal/pkg/containers/image"
+	"github.com/talos-systems/talos/internal/pkg/environment"
+	"github.com/talos-systems/talos/pkg/conditions"
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// Extension implements the Service interface for the operator-supplied system services.
+type Extension struct {
+	Spec config.ExtensionService
+}
+
+// ID implements the Service interface.
+func (e *Extension) ID(r runtime.Runtime) string {
+	return constants.ExtensionServicePrefix + e.Spec.Name()
+}
+
+// PreFunc implements the Service interface.
+func (e *Extension) PreFunc(ctx context.Context, r runtime.Runtime) error {
+	client, err := containerdapi.New(constants.SystemContainerdAddress)
+	if err != nil {
+		return err
+	}
+	// nolint: errcheck
+	defer client.Close()
+
+	containerdctx := namespaces.WithNamespace(ctx, constants.SystemContainerdNamespace)
+
+	if _, err = image.Pull(containerdctx, r.Config().Machine().Registries(), client, e.Spec.Image(),
+		image.WithVerification(r.Config().Machine().Features().ImageVerification())); err != nil {
+		return fmt.Errorf("failed to pull image %q: %w", e.Spec.Image(), err)
+	}
+
+	return nil
+}
+
+// PostFunc implements the Service interface.
+func (e *Extension) PostFunc(r runtime.Runtime, state events.ServiceState) (err error) {
+	return nil
+}
+
+// Condition implements the Service interface.
+func (e *Extension) Condition(r runtime.Runtime) conditions.Condition {
+	return nil
+}
+
+// DependsOn implements the Service interface.
+func (e *Extension) DependsOn(r runtime.Runtime) []string {
+	return append([]string{"containerd"}, e.Spec.DependsOn()...)
+}
+
+// Runner implements the Service interface.
+func (e *Extension) Runner(r runtime.Runtime) (runner.Runner, error) {
+	args := runner.Args{
+		ID:          e.ID(r),
+		ProcessArgs: e.Spec.Args(),
+	}
+
+	env := environment.Get(r.Config())
+
+	for key, val := range e.Spec.Env() {
+		env = append(env, fmt.Sprintf("%s=%s", key, val))
+	}
+
+	restartType := restart.Forever
+
+	switch e.Spec.Restart() {
+	case constants.ExtensionServiceRestartUntilSuccess:
+		restartType = restart.UntilSuccess
+	case constants.ExtensionServiceRestartNever:
+		restartType = restart.Once
+	}
+
+	return restart.New(containerd.NewRunner(
+		r.Config().Debug(),
+		&args,
+		runner.WithLoggingManager(r.Logging()),
+		runner.WithNamespace(constants.SystemContainerdNamespace),
+		runner.WithContainerdAddress(constants.SystemContainerdAddress),
+		runner.WithContainerImage(e.Spec.Image()),
+		runner.WithEnv(env),
+		withServiceResources(r, e.ID(r)),
+		runner.WithOCISpecOpts(extensionSpecOpts(e.Spec)...),
+	),
+		restart.WithType(restartType),
+	), nil
+}
+
+// APIStartAllowed implements the APIStartableService interface.
+func (e *Extension) APIStartAllowed(r runtime.Runtime) bool {
+	return true
+}
+
+// APIStopAllowed implements the APIStoppableService interface.
+func (e *Extension) APIStopAllowed(r runtime.Runtime) bool {
+	return true
+}
+
+// APIRestartAllowed implements the APIRestartableService interface.
+func (e *Extension) APIRestartAllowed(r runtime.Runtime) bool {
+	return true
+}
+
+// extensionSpecOpts builds the OCI spec options from the security settings of the extension service.
+func extensionSpecOpts(spec config.ExtensionService) []oci.SpecOpts {
+	security := spec.Security()
+
+	opts := []oci.SpecOpts{
+		oci.WithMounts(spec.Mounts()),
+	}
+
+	if security.HostNetwork() {
+		opts = append(opts, oci.WithHostNamespace(specs.NetworkNamespace))
+	}
+
+	if !security.WritableRootfs() {
+		opts = append(opts, oci.WithRootFSReadonly())
+	}
+
+	if security.Privileged() {
+		opts = append(opts, oci.WithPrivileged, oci.WithAllDevicesAllowed, oci.WithHostDevices)
+	} else if len(security.Capabilities()) > 0 {
+		opts = append(opts, oci.WithAddedCapabilities(security.Capabilities()))
+	}
+
+	return opts
+}
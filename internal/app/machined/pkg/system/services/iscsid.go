@@ -0,0 +1,149 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// nolint: golint
+package services
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/process"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/restart"
+	"github.com/talos-systems/talos/internal/pkg/discovery"
+	"github.com/talos-systems/talos/pkg/conditions"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// iscsidConfig is the iscsid configuration, sessions are established by the CSI drivers via iscsiadm.
+const iscsidConfig = `node.startup = manual
+node.session.timeo.replacement_timeout = 120
+`
+
+// ISCSId implements the Service interface. It serves as the concrete type with
+// the required methods.
+type ISCSId struct{}
+
+// ID implements the Service interface.
+func (i *ISCSId) ID(r runtime.Runtime) string {
+	return "iscsid"
+}
+
+// PreFunc implements the Service interface.
+//
+// PreFunc makes the open-iscsi directory writable (node database is stored there as well),
+// and writes the initiator name and the iscsid configuration.
+func (i *ISCSId) PreFunc(ctx context.Context, r runtime.Runtime) error {
+	if err := os.MkdirAll(constants.ISCSIPersistentConfigPath, 0o700); err != nil {
+		return err
+	}
+
+	if err := bindISCSIConfigPath(); err != nil {
+		return err
+	}
+
+	initiatorName := r.Config().Machine().Features().ISCSI().InitiatorName()
+
+	if initiatorName == "" {
+		id, err := discovery.Identity(constants.NodeIdentityPath)
+		if err != nil {
+			return err
+		}
+
+		initiatorName = DefaultISCSIInitiatorName(id)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(constants.ISCSIConfigPath, "initiatorname.iscsi"), []byte(fmt.Sprintf("InitiatorName=%s\n", initiatorName)), 0o600); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(constants.ISCSIConfigPath, "iscsid.conf"), []byte(iscsidConfig), 0o600)
+}
+
+// PostFunc implements the Service interface.
+func (i *ISCSId) PostFunc(r runtime.Runtime, state events.ServiceState) (err error) {
+	return nil
+}
+
+// Condition implements the Service interface.
+func (i *ISCSId) Condition(r runtime.Runtime) conditions.Condition {
+	return nil
+}
+
+// DependsOn implements the Service interface.
+func (i *ISCSId) DependsOn(r runtime.Runtime) []string {
+	return nil
+}
+
+// Runner implements the Service interface.
+func (i *ISCSId) Runner(r runtime.Runtime) (runner.Runner, error) {
+	args := &runner.Args{
+		ID: i.ID(r),
+		ProcessArgs: []string{
+			constants.ISCSIDBinary,
+			"-f",
+		},
+	}
+
+	return restart.New(process.NewRunner(
+		r.Config().Debug(),
+		args,
+		runner.WithLoggingManager(r.Logging()),
+		withServiceResources(r, "iscsid"),
+	),
+		restart.WithType(restart.Forever),
+	), nil
+}
+
+// APIStartAllowed implements the APIStartableService interface.
+func (i *ISCSId) APIStartAllowed(r runtime.Runtime) bool {
+	return true
+}
+
+// APIRestartAllowed implements the APIRestartableService interface.
+func (i *ISCSId) APIRestartAllowed(r runtime.Runtime) bool {
+	return true
+}
+
+// bindISCSIConfigPath bind mounts the persistent directory to the open-iscsi directory in the read-only rootfs.
+//
+// Bind mount is skipped if it already exists (e.g. when the service is restarted).
+func bindISCSIConfigPath() error {
+	var src, dst unix.Stat_t
+
+	if err := unix.Stat(constants.ISCSIPersistentConfigPath, &src); err != nil {
+		return err
+	}
+
+	if err := unix.Stat(constants.ISCSIConfigPath, &dst); err != nil {
+		return fmt.Errorf("open-iscsi is not available: %w", err)
+	}
+
+	if src.Dev == dst.Dev && src.Ino == dst.Ino {
+		return nil
+	}
+
+	if err := unix.Mount(constants.ISCSIPersistentConfigPath, constants.ISCSIConfigPath, "", unix.MS_BIND, ""); err != nil {
+		return fmt.Errorf("failed to create bind mount for %s: %w", constants.ISCSIConfigPath, err)
+	}
+
+	return nil
+}
+
+// DefaultISCSIInitiatorName returns the initiator name derived from the node identity.
+func DefaultISCSIInitiatorName(id string) string {
+	if len(id) > 12 {
+		id = id[:12]
+	}
+
+	return fmt.Sprintf("%s:%s", constants.ISCSIDefaultInitiatorNamePrefix, id)
+}
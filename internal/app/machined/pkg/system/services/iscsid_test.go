@@ -0,0 +1,23 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/services"
+)
+
+func TestISCSIdInterfaces(t *testing.T) {
+	assert.Implements(t, (*system.APIStartableService)(nil), new(services.ISCSId))
+	assert.Implements(t, (*system.APIRestartableService)(nil), new(services.ISCSId))
+}
+
+func TestDefaultISCSIInitiatorName(t *testing.T) {
+	assert.Equal(t, "iqn.2005-03.org.open-iscsi:0123456789ab", services.DefaultISCSIInitiatorName("0123456789abcdef0123456789abcdef"))
+}
@@ -10,10 +10,13 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
+	"log"
 	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	goruntime "runtime"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
@@ -25,8 +28,11 @@ import (
 	cni "github.com/containerd/go-cni"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	tnet "github.com/talos-systems/net"
+	"golang.org/x/sys/unix"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	kubeletconfigv1alpha1 "k8s.io/kubelet/config/v1alpha1"
 	kubeletconfig "k8s.io/kubelet/config/v1beta1"
 
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
@@ -36,8 +42,14 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/containerd"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/restart"
 	"github.com/talos-systems/talos/internal/pkg/containers/image"
+	"github.com/talos-systems/talos/internal/pkg/environment"
+	"github.com/talos-systems/talos/internal/pkg/hardware"
+	"github.com/talos-systems/talos/internal/pkg/nfs"
+	"github.com/talos-systems/talos/internal/pkg/nodeip"
+	"github.com/talos-systems/talos/internal/pkg/reserved"
 	"github.com/talos-systems/talos/pkg/argsbuilder"
 	"github.com/talos-systems/talos/pkg/conditions"
+	"github.com/talos-systems/talos/pkg/machinery/config"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
@@ -101,10 +113,22 @@ func (k *Kubelet) PreFunc(ctx context.Context, r runtime.Runtime) error {
 		return err
 	}
 
+	if err := validateKubeletTopology(r); err != nil {
+		return err
+	}
+
 	if err := writeKubeletConfig(r); err != nil {
 		return err
 	}
 
+	if err := writeNFSConfig(r); err != nil {
+		return err
+	}
+
+	if err := writeCredentialProviderConfig(r); err != nil {
+		return err
+	}
+
 	client, err := containerdapi.New(constants.ContainerdAddress)
 	if err != nil {
 		return err
@@ -115,7 +139,12 @@ func (k *Kubelet) PreFunc(ctx context.Context, r runtime.Runtime) error {
 	// Pull the image and unpack it.
 	containerdctx := namespaces.WithNamespace(ctx, "k8s.io")
 
-	_, err = image.Pull(containerdctx, r.Config().Machine().Registries(), client, r.Config().Machine().Kubelet().Image())
+	if err = image.ImportCache(containerdctx, client); err != nil {
+		return err
+	}
+
+	_, err = image.Pull(containerdctx, r.Config().Machine().Registries(), client, r.Config().Machine().Kubelet().Image(),
+		image.WithVerification(r.Config().Machine().Features().ImageVerification()))
 	if err != nil {
 		return err
 	}
@@ -170,16 +199,15 @@ func (k *Kubelet) Runner(r runtime.Runtime) (runner.Runner, error) {
 		{Type: "bind", Destination: "/var/log/pods", Source: "/var/log/pods", Options: []string{"rbind", "rshared", "rw"}},
 	}
 
+	mounts = append(mounts, nfsMounts(r)...)
+
 	// Add extra mounts.
 	// TODO(andrewrynhard): We should verify that the mount source is
 	// allowlisted. There is the potential that a user can expose
 	// sensitive information.
 	mounts = append(mounts, r.Config().Machine().Kubelet().ExtraMounts()...)
 
-	env := []string{}
-	for key, val := range r.Config().Machine().Env() {
-		env = append(env, fmt.Sprintf("%s=%s", key, val))
-	}
+	env := environment.Get(r.Config())
 
 	return restart.New(containerd.NewRunner(
 		r.Config().Debug(),
@@ -188,6 +216,7 @@ func (k *Kubelet) Runner(r runtime.Runtime) (runner.Runner, error) {
 		runner.WithNamespace(criconstants.K8sContainerdNamespace),
 		runner.WithContainerImage(r.Config().Machine().Kubelet().Image()),
 		runner.WithEnv(env),
+		withServiceResources(r, "kubelet"),
 		runner.WithOCISpecOpts(
 			containerd.WithRootfsPropagation("shared"),
 			oci.WithMounts(mounts),
@@ -235,7 +264,12 @@ func (k *Kubelet) HealthSettings(runtime.Runtime) *health.Settings {
 	return &settings
 }
 
-func newKubeletConfiguration(clusterDNS []string, dnsDomain string) *kubeletconfig.KubeletConfiguration {
+// APIRestartAllowed implements the APIRestartableService interface.
+func (k *Kubelet) APIRestartAllowed(runtime.Runtime) bool {
+	return true
+}
+
+func newKubeletConfiguration(clusterDNS []string, dnsDomain string, serverTLSBootstrap bool) *kubeletconfig.KubeletConfiguration {
 	f := false
 	t := true
 
@@ -248,6 +282,7 @@ func newKubeletConfiguration(clusterDNS []string, dnsDomain string) *kubeletconf
 		Address:            "0.0.0.0",
 		Port:               10250,
 		RotateCertificates: true,
+		ServerTLSBootstrap: serverTLSBootstrap,
 		Authentication: kubeletconfig.KubeletAuthentication{
 			X509: kubeletconfig.KubeletX509Authentication{
 				ClientCAFile: constants.KubernetesCACert,
@@ -278,10 +313,41 @@ func (k *Kubelet) args(r runtime.Runtime) ([]string, error) {
 		"config":                     "/etc/kubernetes/kubelet.yaml",
 		"dynamic-config-dir":         "/etc/kubernetes/kubelet",
 
-		"cert-dir":     "/var/lib/kubelet/pki",
+		"cert-dir":     constants.KubeletPKIDir,
 		"cni-conf-dir": cni.DefaultNetDir,
 	}
 
+	if r.Config().Cluster().ExternalCloudProvider().Enabled() {
+		denyListArgs["cloud-provider"] = "external"
+	}
+
+	if len(r.Config().Machine().Kubelet().CredentialProviders()) > 0 {
+		denyListArgs["image-credential-provider-config"] = constants.KubeletCredentialProviderConfig
+		denyListArgs["image-credential-provider-bin-dir"] = constants.KubeletCredentialProviderBinDir
+	}
+
+	if validSubnets := r.Config().Machine().Kubelet().NodeIP().ValidSubnets(); len(validSubnets) > 0 {
+		nodeIPs, err := pickNodeIPs(validSubnets)
+		if err != nil {
+			return nil, err
+		}
+
+		denyListArgs["node-ip"] = strings.Join(nodeIPs, ",")
+	}
+
+	if policy := r.Config().Machine().Kubelet().MemoryManager().Policy(); policy != "" {
+		denyListArgs["memory-manager-policy"] = policy
+
+		if policy == memoryManagerStaticPolicy {
+			reservedMemory, err := memoryManagerReservedMemory(r)
+			if err != nil {
+				return nil, err
+			}
+
+			denyListArgs["reserved-memory"] = reservedMemory
+		}
+	}
+
 	extraArgs := argsbuilder.Args(r.Config().Machine().Kubelet().ExtraArgs())
 
 	for k := range denyListArgs {
@@ -293,26 +359,34 @@ func (k *Kubelet) args(r runtime.Runtime) ([]string, error) {
 	return denyListArgs.Merge(extraArgs).Args(), nil
 }
 
-func writeKubeletConfig(r runtime.Runtime) error {
-	dnsServiceIPs := []string{}
+// pickNodeIPs returns the first IPv4 and IPv6 addresses of the host which match the subnets.
+func pickNodeIPs(validSubnets []string) ([]string, error) {
+	ips, err := tnet.IPAddrs()
+	if err != nil {
+		return nil, fmt.Errorf("error listing addresses: %w", err)
+	}
 
-	for _, cidr := range strings.Split(r.Config().Cluster().Network().ServiceCIDR(), ",") {
-		_, svcCIDR, err := net.ParseCIDR(cidr)
-		if err != nil {
-			return fmt.Errorf("failed to parse service CIDR %s: %v", cidr, err)
-		}
+	ips, err = nodeip.FilterIPs(ips, validSubnets)
+	if err != nil {
+		return nil, err
+	}
 
-		dnsIP, err := tnet.NthIPInNetwork(svcCIDR, 10)
-		if err != nil {
-			return fmt.Errorf("failed to calculate Nth IP in CIDR %s: %v", svcCIDR, err)
-		}
+	nodeIPs := nodeip.NodeIPs(ips)
+	if len(nodeIPs) == 0 {
+		return nil, fmt.Errorf("no addresses match kubelet node IP subnets %v", validSubnets)
+	}
 
-		dnsServiceIPs = append(dnsServiceIPs, dnsIP.String())
+	result := make([]string, len(nodeIPs))
+
+	for i := range nodeIPs {
+		result[i] = nodeIPs[i].String()
 	}
 
-	kubeletConfiguration := newKubeletConfiguration(dnsServiceIPs, r.Config().Cluster().Network().DNSDomain())
+	return result, nil
+}
 
-	serializer := json.NewSerializerWithOptions(
+func newSerializer() *json.Serializer {
+	return json.NewSerializerWithOptions(
 		json.DefaultMetaFactory,
 		nil,
 		nil,
@@ -322,10 +396,68 @@ func writeKubeletConfig(r runtime.Runtime) error {
 			Strict: true,
 		},
 	)
+}
+
+// kubeletDNSServiceIPs returns the cluster DNS IPs: the 10th IP of each service subnet unless pinned in the config.
+func kubeletDNSServiceIPs(r runtime.Runtime) ([]string, error) {
+	if serviceIPs := r.Config().Cluster().CoreDNS().ServiceIPs(); len(serviceIPs) > 0 {
+		return serviceIPs, nil
+	}
+
+	dnsServiceIPs := []string{}
+
+	for _, cidr := range strings.Split(r.Config().Cluster().Network().ServiceCIDR(), ",") {
+		_, svcCIDR, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse service CIDR %s: %v", cidr, err)
+		}
+
+		dnsIP, err := tnet.NthIPInNetwork(svcCIDR, 10)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate Nth IP in CIDR %s: %v", svcCIDR, err)
+		}
+
+		dnsServiceIPs = append(dnsServiceIPs, dnsIP.String())
+	}
+
+	return dnsServiceIPs, nil
+}
+
+func writeKubeletConfig(r runtime.Runtime) error {
+	dnsServiceIPs, err := kubeletDNSServiceIPs(r)
+	if err != nil {
+		return err
+	}
+
+	kubeletConfiguration := newKubeletConfiguration(dnsServiceIPs, r.Config().Cluster().Network().DNSDomain(), r.Config().Machine().Kubelet().ServerCertRotation())
+
+	systemReserved, kubeReserved, err := reservedResources(r)
+	if err != nil {
+		return err
+	}
+
+	kubeletConfiguration.SystemReserved = systemReserved
+	kubeletConfiguration.KubeReserved = kubeReserved
+	kubeletConfiguration.SystemReservedCgroup = constants.SystemServicesCgroup
+
+	kubeletConfiguration.CPUManagerPolicy = r.Config().Machine().Kubelet().CPUManagerPolicy()
+	kubeletConfiguration.ReservedSystemCPUs = r.Config().Machine().Kubelet().ReservedCPUs()
+	kubeletConfiguration.TopologyManagerPolicy = r.Config().Machine().Kubelet().TopologyManagerPolicy()
+	kubeletConfiguration.TopologyManagerScope = r.Config().Machine().Kubelet().TopologyManagerScope()
+
+	kubeletConfiguration.FeatureGates = map[string]bool{}
+
+	if len(r.Config().Machine().Kubelet().CredentialProviders()) > 0 {
+		kubeletConfiguration.FeatureGates["KubeletCredentialProviders"] = true
+	}
+
+	if r.Config().Machine().Kubelet().MemoryManager().Policy() == memoryManagerStaticPolicy {
+		kubeletConfiguration.FeatureGates["MemoryManager"] = true
+	}
 
 	var buf bytes.Buffer
 
-	if err := serializer.Encode(kubeletConfiguration, &buf); err != nil {
+	if err := newSerializer().Encode(kubeletConfiguration, &buf); err != nil {
 		return err
 	}
 
@@ -335,3 +467,257 @@ func writeKubeletConfig(r runtime.Runtime) error {
 
 	return nil
 }
+
+// reservedResources returns the resources reserved for the system and Kubernetes daemons:
+// the defaults based on the node size with the overrides from the machine config.
+func reservedResources(r runtime.Runtime) (systemReserved, kubeReserved map[string]string, err error) {
+	var info unix.Sysinfo_t
+
+	if err = unix.Sysinfo(&info); err != nil {
+		return nil, nil, fmt.Errorf("error reading system info: %w", err)
+	}
+
+	memory := uint64(info.Totalram) * uint64(info.Unit)
+
+	systemReserved = reserved.Merge(reserved.System(), r.Config().Machine().Kubelet().SystemReserved())
+	kubeReserved = reserved.Merge(reserved.Kube(goruntime.NumCPU(), memory), r.Config().Machine().Kubelet().KubeReserved())
+
+	return systemReserved, kubeReserved, nil
+}
+
+const (
+	memoryManagerStaticPolicy = "Static"
+
+	// evictionHardMemory is the default kubelet hard eviction threshold of the available memory.
+	evictionHardMemory = "100Mi"
+)
+
+// reservedMemoryTotal returns the memory the Static memory manager policy requires to be reserved on the NUMA nodes:
+// the system and Kubernetes reserved memory and the hard eviction threshold.
+func reservedMemoryTotal(r runtime.Runtime) (resource.Quantity, error) {
+	total := resource.MustParse(evictionHardMemory)
+
+	systemReserved, kubeReserved, err := reservedResources(r)
+	if err != nil {
+		return total, err
+	}
+
+	for _, reserved := range []map[string]string{systemReserved, kubeReserved} {
+		memory, ok := reserved["memory"]
+		if !ok {
+			continue
+		}
+
+		quantity, err := resource.ParseQuantity(memory)
+		if err != nil {
+			return total, fmt.Errorf("error parsing reserved memory %q: %w", memory, err)
+		}
+
+		total.Add(quantity)
+	}
+
+	return total, nil
+}
+
+// memoryManagerReservedMemory returns the value of the kubelet `--reserved-memory` flag.
+//
+// If the reserved memory is not configured, the total is reserved on the NUMA node 0.
+func memoryManagerReservedMemory(r runtime.Runtime) (string, error) {
+	reservations := r.Config().Machine().Kubelet().MemoryManager().ReservedMemory()
+
+	if len(reservations) == 0 {
+		total, err := reservedMemoryTotal(r)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("0:memory=%s", total.String()), nil
+	}
+
+	parts := make([]string, len(reservations))
+
+	for i, reservation := range reservations {
+		parts[i] = fmt.Sprintf("%d:memory=%s", reservation.NUMANode(), reservation.Memory())
+	}
+
+	return strings.Join(parts, ";"), nil
+}
+
+// validateKubeletTopology checks the kubelet resource managers configuration against the CPUs and NUMA nodes of the node.
+func validateKubeletTopology(r runtime.Runtime) error {
+	cfg := r.Config().Machine().Kubelet()
+
+	nodes, err := hardware.NUMANodes()
+	if err != nil {
+		return fmt.Errorf("error reading NUMA topology: %w", err)
+	}
+
+	cpus, err := hardware.CPUs(nodes)
+	if err != nil {
+		return fmt.Errorf("error reading CPU topology: %w", err)
+	}
+
+	if cfg.ReservedCPUs() != "" {
+		reserved, err := hardware.ParseCPUList(cfg.ReservedCPUs())
+		if err != nil {
+			return err
+		}
+
+		online := make(map[uint32]struct{}, len(cpus))
+
+		for _, cpu := range cpus {
+			online[cpu.ID] = struct{}{}
+		}
+
+		for _, cpu := range reserved {
+			if _, ok := online[cpu]; !ok {
+				return fmt.Errorf("kubelet reserved CPU %d is not present on the node", cpu)
+			}
+		}
+	}
+
+	// the kernel without NUMA support has the single node 0
+	numaNodes := map[uint32]struct{}{0: {}}
+
+	for _, node := range nodes {
+		numaNodes[node.ID] = struct{}{}
+	}
+
+	if policy := cfg.TopologyManagerPolicy(); (policy == "restricted" || policy == "single-numa-node") && len(numaNodes) == 1 {
+		log.Printf("WARNING: kubelet topology manager policy %q has no effect on the node with a single NUMA node", policy)
+	}
+
+	if cfg.MemoryManager().Policy() != memoryManagerStaticPolicy || len(cfg.MemoryManager().ReservedMemory()) == 0 {
+		return nil
+	}
+
+	var sum resource.Quantity
+
+	for _, reservation := range cfg.MemoryManager().ReservedMemory() {
+		if _, ok := numaNodes[reservation.NUMANode()]; !ok {
+			return fmt.Errorf("kubelet memory manager reserves memory on NUMA node %d which is not present on the node", reservation.NUMANode())
+		}
+
+		quantity, err := resource.ParseQuantity(reservation.Memory())
+		if err != nil {
+			return fmt.Errorf("error parsing reserved memory %q: %w", reservation.Memory(), err)
+		}
+
+		sum.Add(quantity)
+	}
+
+	total, err := reservedMemoryTotal(r)
+	if err != nil {
+		return err
+	}
+
+	if sum.Cmp(total) != 0 {
+		return fmt.Errorf("kubelet memory manager reserved memory %s should be equal to the system and Kubernetes reserved memory and the hard eviction threshold %s",
+			sum.String(), total.String())
+	}
+
+	return nil
+}
+
+// writeCredentialProviderConfig writes the CredentialProviderConfig of the kubelet exec credential provider plugins.
+func writeCredentialProviderConfig(r runtime.Runtime) error {
+	providers := r.Config().Machine().Kubelet().CredentialProviders()
+
+	if len(providers) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(constants.KubeletCredentialProviderBinDir, 0o755); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	if err := newSerializer().Encode(newCredentialProviderConfig(providers), &buf); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(constants.KubeletCredentialProviderConfig, buf.Bytes(), 0o600)
+}
+
+func newCredentialProviderConfig(providers []config.KubeletCredentialProvider) *kubeletconfigv1alpha1.CredentialProviderConfig {
+	cfg := &kubeletconfigv1alpha1.CredentialProviderConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "kubelet.config.k8s.io/v1alpha1",
+			Kind:       "CredentialProviderConfig",
+		},
+	}
+
+	for _, provider := range providers {
+		p := kubeletconfigv1alpha1.CredentialProvider{
+			Name:                 provider.Name(),
+			MatchImages:          provider.MatchImages(),
+			DefaultCacheDuration: &metav1.Duration{Duration: provider.DefaultCacheDuration()},
+			APIVersion:           provider.APIVersion(),
+			Args:                 provider.Args(),
+		}
+
+		names := make([]string, 0, len(provider.Env()))
+
+		for name := range provider.Env() {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			p.Env = append(p.Env, kubeletconfigv1alpha1.ExecEnvVar{
+				Name:  name,
+				Value: provider.Env()[name],
+			})
+		}
+
+		cfg.Providers = append(cfg.Providers, p)
+	}
+
+	return cfg
+}
+
+// writeNFSConfig writes the NFS client configuration mounted into the kubelet.
+func writeNFSConfig(r runtime.Runtime) error {
+	cfg := r.Config().Machine().Features().NFSMounts()
+
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	if cfg.IdmapdDomain() != "" {
+		if err := ioutil.WriteFile(constants.NFSIdmapdConfig, nfs.IdmapdConfig(cfg.IdmapdDomain()), 0o644); err != nil {
+			return err
+		}
+	}
+
+	if cfg.DefaultVersion() != "" {
+		if err := ioutil.WriteFile(constants.NFSMountConfig, nfs.MountConfig(cfg.DefaultVersion()), 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nfsMounts returns the kubelet mounts of the files written by writeNFSConfig.
+func nfsMounts(r runtime.Runtime) []specs.Mount {
+	cfg := r.Config().Machine().Features().NFSMounts()
+
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	var mounts []specs.Mount
+
+	if cfg.IdmapdDomain() != "" {
+		mounts = append(mounts, specs.Mount{Type: "bind", Destination: "/etc/idmapd.conf", Source: constants.NFSIdmapdConfig, Options: []string{"bind", "ro"}})
+	}
+
+	if cfg.DefaultVersion() != "" {
+		mounts = append(mounts, specs.Mount{Type: "bind", Destination: "/etc/nfsmount.conf", Source: constants.NFSMountConfig, Options: []string{"bind", "ro"}})
+	}
+
+	return mounts
+}
@@ -0,0 +1,129 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// nolint: golint
+package services
+
+import (
+	"context"
+	"io"
+	"log"
+	"time"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/goroutine"
+	"github.com/talos-systems/talos/internal/pkg/csrapprover"
+	"github.com/talos-systems/talos/internal/pkg/discovery"
+	"github.com/talos-systems/talos/pkg/conditions"
+	"github.com/talos-systems/talos/pkg/kubernetes"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// kubeletCSRApproverMain is an entrypoint for the kubelet CSR approver service.
+//
+// Pending kubelet serving certificate signing requests are approved if they match the identity
+// of the nodes published via the cluster discovery.
+func kubeletCSRApproverMain(ctx context.Context, r runtime.Runtime, logWriter io.Writer) error {
+	logger := log.New(logWriter, "", log.LstdFlags)
+
+	ticker := time.NewTicker(constants.KubeletCSRApproverInterval)
+	defer ticker.Stop()
+
+	var (
+		client        *kubernetes.Client
+		clientCreated time.Time
+	)
+
+	// last reported error per request, so that requests which can't be approved are not logged on every run
+	reported := map[string]string{}
+
+	membersFunc := func(ctx context.Context) ([]*discovery.Member, error) {
+		ctx, cancel := context.WithTimeout(ctx, constants.DiscoveryRequestTimeout)
+		defer cancel()
+
+		return discovery.Members(ctx, discovery.Registries(r.Config())...)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if client == nil || time.Since(clientCreated) > constants.KubeletCSRApproverClientTTL {
+			var err error
+
+			client, err = kubernetes.NewTemporaryClientFromPKI(r.Config().Cluster().CA(), r.Config().Cluster().Endpoint())
+			if err != nil {
+				logger.Printf("error building Kubernetes client: %s", err)
+
+				continue
+			}
+
+			clientCreated = time.Now()
+		}
+
+		results, err := csrapprover.Run(ctx, client.Clientset, membersFunc)
+		if err != nil {
+			logger.Printf("error approving kubelet serving certificates: %s", err)
+
+			continue
+		}
+
+		pending := map[string]string{}
+
+		for _, result := range results {
+			if result.Approved {
+				logger.Printf("approved kubelet serving certificate request %q", result.Name)
+
+				continue
+			}
+
+			pending[result.Name] = result.Error.Error()
+
+			if reported[result.Name] != pending[result.Name] {
+				logger.Printf("kubelet serving certificate request %q is not approved: %s", result.Name, result.Error)
+			}
+		}
+
+		reported = pending
+	}
+}
+
+// KubeletCSRApprover implements the Service interface. It serves as the concrete type with
+// the required methods.
+type KubeletCSRApprover struct{}
+
+// ID implements the Service interface.
+func (k *KubeletCSRApprover) ID(r runtime.Runtime) string {
+	return "kubelet-csr-approver"
+}
+
+// PreFunc implements the Service interface.
+func (k *KubeletCSRApprover) PreFunc(ctx context.Context, r runtime.Runtime) error {
+	return nil
+}
+
+// PostFunc implements the Service interface.
+func (k *KubeletCSRApprover) PostFunc(r runtime.Runtime, state events.ServiceState) (err error) {
+	return nil
+}
+
+// Condition implements the Service interface.
+func (k *KubeletCSRApprover) Condition(r runtime.Runtime) conditions.Condition {
+	return nil
+}
+
+// DependsOn implements the Service interface.
+func (k *KubeletCSRApprover) DependsOn(r runtime.Runtime) []string {
+	return []string{"kubelet"}
+}
+
+// Runner implements the Service interface.
+func (k *KubeletCSRApprover) Runner(r runtime.Runtime) (runner.Runner, error) {
+	return goroutine.NewRunner(r, "kubelet-csr-approver", kubeletCSRApproverMain, runner.WithLoggingManager(r.Logging())), nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/goroutine"
+	"github.com/talos-systems/talos/internal/pkg/audit"
 	"github.com/talos-systems/talos/pkg/conditions"
 	"github.com/talos-systems/talos/pkg/grpc/factory"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
@@ -25,12 +26,19 @@ type machinedService struct {
 
 // Main is an entrypoint the the API service.
 func (s *machinedService) Main(ctx context.Context, r runtime.Runtime, logWriter io.Writer) error {
+	recorder := &audit.Recorder{
+		Log:    audit.Default,
+		Events: r.Events(),
+	}
+
 	// Start the API server.
 	server := factory.NewServer(
 		&v1alpha1server.Server{
 			Controller: s.c,
 		},
 		factory.WithLog("machined ", logWriter),
+		factory.WithUnaryInterceptor(recorder.UnaryInterceptor()),
+		factory.WithStreamInterceptor(recorder.StreamInterceptor()),
 	)
 
 	listener, err := factory.NewListener(factory.Network("unix"), factory.SocketPath(constants.MachineSocketPath))
@@ -0,0 +1,164 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// nolint: golint
+package services
+
+import (
+	"context"
+	"io"
+	"log"
+	"time"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/goroutine"
+	"github.com/talos-systems/talos/internal/pkg/etcd"
+	"github.com/talos-systems/talos/internal/pkg/manifests"
+	"github.com/talos-systems/talos/pkg/conditions"
+	"github.com/talos-systems/talos/pkg/kubernetes"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// manifestSyncMain is an entrypoint for the manifest sync service.
+//
+// The Talos-owned bootstrap manifests rendered by bootkube are stored in etcd, and every control plane
+// node reconciles them with the cluster using the server-side apply. The report of the last sync is kept
+// in the cluster state.
+func manifestSyncMain(ctx context.Context, r runtime.Runtime, logWriter io.Writer) error {
+	logger := log.New(logWriter, "", log.LstdFlags)
+
+	status := r.State().Cluster().Manifests()
+
+	ticker := time.NewTicker(constants.ManifestSyncInterval)
+	defer ticker.Stop()
+
+	var (
+		syncer        *manifests.Syncer
+		syncerCreated time.Time
+	)
+
+	// last reported state per object, so that only the changes are logged
+	reported := map[string]string{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		report := manifests.Report{LastSync: time.Now()}
+
+		list, err := manifestSyncLoad(ctx)
+		if err != nil {
+			report.Error = err.Error()
+
+			logger.Printf("error loading manifests: %s", err)
+
+			status.Update(report)
+
+			continue
+		}
+
+		if len(list) == 0 {
+			// cluster is not bootstrapped yet
+			status.Update(report)
+
+			continue
+		}
+
+		// the client certificate is short-lived, so the syncer is re-created periodically
+		if syncer == nil || time.Since(syncerCreated) > constants.ManifestSyncClientTTL {
+			config, err := kubernetes.NewTemporaryRESTConfigFromPKI(r.Config().Cluster().CA(), r.Config().Cluster().Endpoint())
+			if err == nil {
+				syncer, err = manifests.NewSyncer(config)
+			}
+
+			if err != nil {
+				report.Error = err.Error()
+
+				logger.Printf("error building Kubernetes client: %s", err)
+
+				status.Update(report)
+
+				continue
+			}
+
+			syncerCreated = time.Now()
+		}
+
+		report.Objects = syncer.Sync(ctx, list)
+
+		current := map[string]string{}
+
+		for _, object := range report.Objects {
+			id := object.Manifest + "/" + object.Kind + "/" + object.Namespace + "/" + object.Name
+			current[id] = object.State + object.Error
+
+			switch {
+			case object.State == manifests.StateCreated, object.State == manifests.StateDrifted:
+				logger.Printf("%s %s %q from manifest %q", object.State, object.Kind, object.Name, object.Manifest)
+			case object.State == manifests.StateError && reported[id] != current[id]:
+				logger.Printf("error syncing %s %q from manifest %q: %s", object.Kind, object.Name, object.Manifest, object.Error)
+			}
+		}
+
+		reported = current
+
+		status.Update(report)
+	}
+}
+
+// manifestSyncLoad moves the manifests handed over by bootkube (if any) to etcd, and loads the manifests from etcd.
+func manifestSyncLoad(ctx context.Context) ([]manifests.Manifest, error) {
+	client, err := etcd.NewClient([]string{"127.0.0.1:2379"})
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint: errcheck
+	defer client.Close()
+
+	if err = manifests.Import(ctx, client.Client, constants.TalosManifestsDirectory); err != nil {
+		return nil, err
+	}
+
+	return manifests.Load(ctx, client.Client)
+}
+
+// ManifestSync implements the Service interface. It serves as the concrete type with
+// the required methods.
+type ManifestSync struct{}
+
+// ID implements the Service interface.
+func (m *ManifestSync) ID(r runtime.Runtime) string {
+	return "manifest-sync"
+}
+
+// PreFunc implements the Service interface.
+func (m *ManifestSync) PreFunc(ctx context.Context, r runtime.Runtime) error {
+	return nil
+}
+
+// PostFunc implements the Service interface.
+func (m *ManifestSync) PostFunc(r runtime.Runtime, state events.ServiceState) (err error) {
+	return nil
+}
+
+// Condition implements the Service interface.
+func (m *ManifestSync) Condition(r runtime.Runtime) conditions.Condition {
+	return nil
+}
+
+// DependsOn implements the Service interface.
+func (m *ManifestSync) DependsOn(r runtime.Runtime) []string {
+	return []string{"etcd"}
+}
+
+// Runner implements the Service interface.
+func (m *ManifestSync) Runner(r runtime.Runtime) (runner.Runner, error) {
+	return goroutine.NewRunner(r, "manifest-sync", manifestSyncMain, runner.WithLoggingManager(r.Logging())), nil
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/containerd"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/restart"
 	"github.com/talos-systems/talos/internal/pkg/containers/image"
+	"github.com/talos-systems/talos/internal/pkg/environment"
 	"github.com/talos-systems/talos/pkg/conditions"
 	"github.com/talos-systems/talos/pkg/grpc/dialer"
 	healthapi "github.com/talos-systems/talos/pkg/machinery/api/health"
@@ -45,7 +46,7 @@ func (n *Networkd) ID(r runtime.Runtime) string {
 
 // PreFunc implements the Service interface.
 func (n *Networkd) PreFunc(ctx context.Context, r runtime.Runtime) error {
-	return image.Import(ctx, "/usr/images/networkd.tar", "talos/networkd")
+	return image.Import(ctx, "/usr/images/networkd.tar", "talos/networkd", image.WithVerification(r.Config().Machine().Features().ImageVerification()))
 }
 
 // PostFunc implements the Service interface.
@@ -84,10 +85,7 @@ func (n *Networkd) Runner(r runtime.Runtime) (runner.Runner, error) {
 		{Type: "bind", Destination: filepath.Dir(constants.NetworkSocketPath), Source: filepath.Dir(constants.NetworkSocketPath), Options: []string{"rbind", "rw"}},
 	}
 
-	env := []string{}
-	for key, val := range r.Config().Machine().Env() {
-		env = append(env, fmt.Sprintf("%s=%s", key, val))
-	}
+	env := environment.Get(r.Config())
 
 	// This is really only here to support container runtime
 	if p, ok := os.LookupEnv("PLATFORM"); ok {
@@ -109,6 +107,7 @@ func (n *Networkd) Runner(r runtime.Runtime) (runner.Runner, error) {
 		runner.WithContainerdAddress(constants.SystemContainerdAddress),
 		runner.WithContainerImage(image),
 		runner.WithEnv(env),
+		withServiceResources(r, "networkd"),
 		runner.WithOCISpecOpts(
 			containerd.WithMemoryLimit(int64(1000000*32)),
 			oci.WithCapabilities([]string{
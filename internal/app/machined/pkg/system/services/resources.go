@@ -0,0 +1,84 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package services
+
+import (
+	"path/filepath"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// cpuPeriod is the CFS period used to convert the CPU limit to the quota.
+const cpuPeriod = 100000
+
+// withServiceResources places the service in the system services cgroup and applies the resource limits
+// and the OOM score adjustment of the service from the machine config.
+func withServiceResources(r runtime.Runtime, service string) runner.Option {
+	resources := r.Config().Machine().ServiceResources(service)
+
+	return func(opts *runner.Options) {
+		// in container mode the cgroups are managed by the host
+		if r.State().Platform().Mode() != runtime.ModeContainer {
+			runner.WithCgroupPath(filepath.Join(constants.SystemServicesCgroup, service))(opts)
+		}
+
+		runner.WithResources(linuxResources(resources))(opts)
+		runner.WithOOMScoreAdj(resources.OOMScoreAdj())(opts)
+	}
+}
+
+// linuxResources converts the config resources to the OCI resources, it returns nil if no limits are set.
+func linuxResources(resources config.ServiceResources) *specs.LinuxResources {
+	var (
+		cpu    *specs.LinuxCPU
+		memory *specs.LinuxMemory
+	)
+
+	if resources.CPUShares() > 0 || resources.CPULimit() > 0 {
+		cpu = &specs.LinuxCPU{}
+
+		if shares := resources.CPUShares(); shares > 0 {
+			cpu.Shares = &shares
+		}
+
+		if resources.CPULimit() > 0 {
+			period := uint64(cpuPeriod)
+			quota := int64(resources.CPULimit() * cpuPeriod)
+
+			cpu.Period = &period
+			cpu.Quota = &quota
+		}
+	}
+
+	if resources.MemoryLimit() > 0 || resources.MemoryReservation() > 0 {
+		memory = &specs.LinuxMemory{}
+
+		if resources.MemoryLimit() > 0 {
+			limit := int64(resources.MemoryLimit())
+
+			memory.Limit = &limit
+		}
+
+		if resources.MemoryReservation() > 0 {
+			reservation := int64(resources.MemoryReservation())
+
+			memory.Reservation = &reservation
+		}
+	}
+
+	if cpu == nil && memory == nil {
+		return nil
+	}
+
+	return &specs.LinuxResources{
+		CPU:    cpu,
+		Memory: memory,
+	}
+}
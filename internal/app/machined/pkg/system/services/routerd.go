@@ -39,7 +39,7 @@ func (o *Routerd) ID(r runtime.Runtime) string {
 
 // PreFunc implements the Service interface.
 func (o *Routerd) PreFunc(ctx context.Context, r runtime.Runtime) error {
-	return image.Import(ctx, "/usr/images/routerd.tar", "talos/routerd")
+	return image.Import(ctx, "/usr/images/routerd.tar", "talos/routerd", image.WithVerification(r.Config().Machine().Features().ImageVerification()))
 }
 
 // PostFunc implements the Service interface.
@@ -103,6 +103,7 @@ func (o *Routerd) Runner(r runtime.Runtime) (runner.Runner, error) {
 		runner.WithContainerdAddress(constants.SystemContainerdAddress),
 		runner.WithContainerImage(image),
 		runner.WithEnv(env),
+		withServiceResources(r, "routerd"),
 		runner.WithOCISpecOpts(
 			oci.WithMounts(mounts),
 		),
@@ -27,6 +27,7 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/containerd"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/restart"
 	"github.com/talos-systems/talos/internal/pkg/containers/image"
+	"github.com/talos-systems/talos/internal/pkg/environment"
 	"github.com/talos-systems/talos/pkg/conditions"
 	"github.com/talos-systems/talos/pkg/grpc/dialer"
 	healthapi "github.com/talos-systems/talos/pkg/machinery/api/health"
@@ -46,7 +47,7 @@ func (n *Timed) ID(r runtime.Runtime) string {
 
 // PreFunc implements the Service interface.
 func (n *Timed) PreFunc(ctx context.Context, r runtime.Runtime) error {
-	return image.Import(ctx, "/usr/images/timed.tar", "talos/timed")
+	return image.Import(ctx, "/usr/images/timed.tar", "talos/timed", image.WithVerification(r.Config().Machine().Features().ImageVerification()))
 }
 
 // PostFunc implements the Service interface.
@@ -86,10 +87,7 @@ func (n *Timed) Runner(r runtime.Runtime) (runner.Runner, error) {
 		{Type: "bind", Destination: filepath.Dir(constants.TimeSocketPath), Source: filepath.Dir(constants.TimeSocketPath), Options: []string{"rbind", "rw"}},
 	}
 
-	env := []string{}
-	for key, val := range r.Config().Machine().Env() {
-		env = append(env, fmt.Sprintf("%s=%s", key, val))
-	}
+	env := environment.Get(r.Config())
 
 	b, err := r.Config().Bytes()
 	if err != nil {
@@ -106,6 +104,7 @@ func (n *Timed) Runner(r runtime.Runtime) (runner.Runner, error) {
 		runner.WithContainerdAddress(constants.SystemContainerdAddress),
 		runner.WithContainerImage(image),
 		runner.WithEnv(env),
+		withServiceResources(r, "timed"),
 		runner.WithOCISpecOpts(
 			containerd.WithMemoryLimit(int64(1000000*32)),
 			oci.WithCapabilities([]string{
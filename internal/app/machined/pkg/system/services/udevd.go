@@ -6,7 +6,6 @@ package services
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
@@ -15,6 +14,7 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/process"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/restart"
+	"github.com/talos-systems/talos/internal/pkg/environment"
 	"github.com/talos-systems/talos/pkg/cmd"
 	"github.com/talos-systems/talos/pkg/conditions"
 )
@@ -67,21 +67,26 @@ func (c *Udevd) Runner(r runtime.Runtime) (runner.Runner, error) {
 		},
 	}
 
-	env := []string{}
-	for key, val := range r.Config().Machine().Env() {
-		env = append(env, fmt.Sprintf("%s=%s", key, val))
-	}
+	env := environment.Get(r.Config())
 
 	return restart.New(process.NewRunner(
 		r.Config().Debug(),
 		args,
 		runner.WithLoggingManager(r.Logging()),
 		runner.WithEnv(env),
+		withServiceResources(r, "udevd"),
 	),
 		restart.WithType(restart.Forever),
 	), nil
 }
 
+// APIReload implements the APIReloadableService interface.
+func (c *Udevd) APIReload(ctx context.Context, r runtime.Runtime) error {
+	_, err := cmd.RunContext(ctx, "/sbin/udevadm", "control", "--reload")
+
+	return err
+}
+
 // HealthFunc implements the HealthcheckedService interface.
 func (c *Udevd) HealthFunc(runtime.Runtime) health.Check {
 	return func(ctx context.Context) error {
@@ -349,7 +349,95 @@ func (s *singleton) IsRunning(id string) (Service, bool, error) {
 	return runner.service, running, nil
 }
 
+// dependencies returns transitive dependencies of the service, dependencies go before services which depend on them.
+func (s *singleton) dependencies(id string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		result  []string
+		visit   func(id string) error
+		visited = map[string]struct{}{}
+	)
+
+	visit = func(id string) error {
+		svcrunner, exists := s.state[id]
+		if !exists {
+			return fmt.Errorf("service %q not defined", id)
+		}
+
+		for _, dependency := range svcrunner.service.DependsOn(s.runtime) {
+			if _, ok := visited[dependency]; ok {
+				continue
+			}
+
+			visited[dependency] = struct{}{}
+
+			if _, exists := s.state[dependency]; !exists {
+				return fmt.Errorf("service %q depends on service %q which is not defined", id, dependency)
+			}
+
+			if err := visit(dependency); err != nil {
+				return err
+			}
+
+			result = append(result, dependency)
+		}
+
+		return nil
+	}
+
+	return result, visit(id)
+}
+
+// runningReverseDependencies returns sorted list of running services which depend (transitively) on the service.
+func (s *singleton) runningReverseDependencies(id string) []string {
+	s.mu.Lock()
+
+	reverseDependencies := make(map[string][]string)
+
+	for name, svcrunner := range s.state {
+		for _, dependency := range svcrunner.service.DependsOn(s.runtime) {
+			reverseDependencies[dependency] = append(reverseDependencies[dependency], name)
+		}
+	}
+
+	s.mu.Unlock()
+
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+
+	var result []string
+
+	visited := map[string]struct{}{id: {}}
+	queue := []string{id}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		for _, dependent := range reverseDependencies[name] {
+			if _, ok := visited[dependent]; ok {
+				continue
+			}
+
+			visited[dependent] = struct{}{}
+			queue = append(queue, dependent)
+
+			if _, running := s.running[dependent]; running {
+				result = append(result, dependent)
+			}
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
 // APIStart processes service start request from the API.
+//
+// Dependencies of the service which are not running are started as well.
 func (s *singleton) APIStart(ctx context.Context, id string) error {
 	service, running, err := s.IsRunning(id)
 	if err != nil {
@@ -362,13 +450,21 @@ func (s *singleton) APIStart(ctx context.Context, id string) error {
 	}
 
 	if svc, ok := service.(APIStartableService); ok && svc.APIStartAllowed(s.runtime) {
-		return s.Start(id)
+		dependencies, err := s.dependencies(id)
+		if err != nil {
+			return err
+		}
+
+		// services which are already running are skipped by Start()
+		return s.Start(append(dependencies, id)...)
 	}
 
 	return fmt.Errorf("service %q doesn't support start operation via API", id)
 }
 
 // APIStop processes services stop request from the API.
+//
+// Service can't be stopped while services which depend on it are running.
 func (s *singleton) APIStop(ctx context.Context, id string) error {
 	service, running, err := s.IsRunning(id)
 	if err != nil {
@@ -381,6 +477,10 @@ func (s *singleton) APIStop(ctx context.Context, id string) error {
 	}
 
 	if svc, ok := service.(APIStoppableService); ok && svc.APIStopAllowed(s.runtime) {
+		if dependents := s.runningReverseDependencies(id); len(dependents) > 0 {
+			return fmt.Errorf("service %q is required by running services %v", id, dependents)
+		}
+
 		return s.Stop(ctx, id)
 	}
 
@@ -388,6 +488,10 @@ func (s *singleton) APIStop(ctx context.Context, id string) error {
 }
 
 // APIRestart processes services restart request from the API.
+//
+// Running services which depend on the service and allow restart via API are restarted as well:
+// they are stopped before the service and started again once the service is up. Other dependents
+// (e.g. apid) are left running.
 func (s *singleton) APIRestart(ctx context.Context, id string) error {
 	service, running, err := s.IsRunning(id)
 	if err != nil {
@@ -400,12 +504,47 @@ func (s *singleton) APIRestart(ctx context.Context, id string) error {
 	}
 
 	if svc, ok := service.(APIRestartableService); ok && svc.APIRestartAllowed(s.runtime) {
-		if err := s.Stop(ctx, id); err != nil {
+		if _, err = s.dependencies(id); err != nil {
+			return err
+		}
+
+		serviceIDs := []string{id}
+
+		for _, dependent := range s.runningReverseDependencies(id) {
+			dependentService, _, err := s.IsRunning(dependent)
+			if err != nil {
+				return err
+			}
+
+			if svc, ok := dependentService.(APIRestartableService); ok && svc.APIRestartAllowed(s.runtime) {
+				serviceIDs = append(serviceIDs, dependent)
+			}
+		}
+
+		if err := s.StopWithRevDepenencies(ctx, serviceIDs...); err != nil {
 			return err
 		}
 
-		return s.Start(id)
+		return s.Start(serviceIDs...)
 	}
 
 	return fmt.Errorf("service %q doesn't support restart operation via API", id)
 }
+
+// APIReload processes services reload request from the API.
+func (s *singleton) APIReload(ctx context.Context, id string) error {
+	service, running, err := s.IsRunning(id)
+	if err != nil {
+		return err
+	}
+
+	if !running {
+		return fmt.Errorf("service %q is not running", id)
+	}
+
+	if svc, ok := service.(APIReloadableService); ok {
+		return svc.APIReload(ctx, s.runtime)
+	}
+
+	return fmt.Errorf("service %q doesn't support reload operation via API", id)
+}
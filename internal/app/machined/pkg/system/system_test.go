@@ -11,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/suite"
 
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system"
 )
 
@@ -18,6 +19,41 @@ type SystemServicesSuite struct {
 	suite.Suite
 }
 
+type MockAPIService struct {
+	MockService
+}
+
+func (m *MockAPIService) APIStartAllowed(runtime.Runtime) bool {
+	return true
+}
+
+func (m *MockAPIService) APIStopAllowed(runtime.Runtime) bool {
+	return true
+}
+
+func (suite *SystemServicesSuite) TestAPIDependencies() {
+	system.Services(nil).Load(
+		&MockAPIService{MockService{name: "apidep"}},
+		&MockAPIService{MockService{name: "apisvc", dependencies: []string{"apidep"}}},
+	)
+
+	suite.Require().NoError(system.Services(nil).APIStart(context.Background(), "apisvc"))
+
+	for _, id := range []string{"apidep", "apisvc"} {
+		_, running, err := system.Services(nil).IsRunning(id)
+		suite.Require().NoError(err)
+		suite.Assert().True(running, id)
+	}
+
+	suite.Require().EqualError(system.Services(nil).APIStop(context.Background(), "apidep"),
+		`service "apidep" is required by running services [apisvc]`)
+
+	suite.Require().NoError(system.Services(nil).APIStop(context.Background(), "apisvc"))
+	suite.Require().NoError(system.Services(nil).APIStop(context.Background(), "apidep"))
+
+	suite.Require().NoError(system.Services(nil).Unload(context.Background(), "apidep", "apisvc"))
+}
+
 func (suite *SystemServicesSuite) TestStartShutdown() {
 	system.Services(nil).LoadAndStart(
 		&MockService{name: "containerd"},
@@ -13,6 +13,7 @@ import (
 
 	ttls "github.com/talos-systems/crypto/tls"
 	"github.com/talos-systems/crypto/x509"
+	"github.com/talos-systems/go-procfs/procfs"
 	tnet "github.com/talos-systems/net"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -50,16 +51,27 @@ func Run(ctx context.Context, logger *log.Logger, r runtime.Runtime) ([]byte, er
 
 	s := server.New(r, logger, cfgCh)
 
-	// Start the server.
-	server := factory.NewServer(
-		s,
+	opts := []factory.Option{
 		factory.WithDefaultLog(),
 		factory.ServerOptions(
 			grpc.Creds(
 				credentials.NewTLS(tlsConfig),
 			),
 		),
-	)
+	}
+
+	token := procfs.ProcCmdline().Get(constants.KernelParamMaintenanceToken).First()
+	if token != nil {
+		authenticator := server.NewTokenAuthenticator(*token)
+
+		opts = append(opts,
+			factory.WithUnaryInterceptor(authenticator.UnaryInterceptor()),
+			factory.WithStreamInterceptor(authenticator.StreamInterceptor()),
+		)
+	}
+
+	// Start the server.
+	server := factory.NewServer(s, opts...)
 
 	listener, err := factory.NewListener(factory.Port(constants.ApidPort))
 	if err != nil {
@@ -92,10 +104,14 @@ func Run(ctx context.Context, logger *log.Logger, r runtime.Runtime) ([]byte, er
 	logger.Println("upload configuration using talosctl:")
 	logger.Printf("\ttalosctl apply-config --insecure --nodes %s --file <config.yaml>", firstIP)
 	logger.Println("or apply configuration using talosctl interactive installer:")
-	logger.Printf("\ttalosctl apply-config --insecure --nodes %s --interactive", firstIP)
+	logger.Printf("\ttalosctl apply-config --insecure --nodes %s --mode=interactive", firstIP)
 	logger.Println("optionally with node fingerprint check:")
 	logger.Printf("\ttalosctl apply-config --insecure --nodes %s --cert-fingerprint '%s' --file <config.yaml>", firstIP, certFingerprint)
 
+	if token != nil {
+		logger.Printf("the one-time token from the %s kernel argument is required, pass it with --token", constants.KernelParamMaintenanceToken)
+	}
+
 	select {
 	case cfg := <-cfgCh:
 		server.GracefulStop()
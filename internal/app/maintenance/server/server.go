@@ -27,7 +27,6 @@ import (
 type Server struct {
 	machine.UnimplementedMachineServiceServer
 	network.UnimplementedNetworkServiceServer
-	storaged.Server
 	runtime runtime.Runtime
 	cfgCh   chan []byte
 	logger  *log.Logger
@@ -47,7 +46,7 @@ func New(r runtime.Runtime, logger *log.Logger, cfgCh chan []byte) *Server {
 func (s *Server) Register(obj *grpc.Server) {
 	s.server = obj
 
-	storage.RegisterStorageServiceServer(obj, s)
+	storage.RegisterStorageServiceServer(obj, &storaged.Server{})
 	machine.RegisterMachineServiceServer(obj, s)
 	network.RegisterNetworkServiceServer(obj, s)
 }
@@ -67,6 +66,18 @@ func (s *Server) ApplyConfiguration(ctx context.Context, in *machine.ApplyConfig
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
 	}
 
+	if in.GetDryRun() {
+		return &machine.ApplyConfigurationResponse{
+			Messages: []*machine.ApplyConfiguration{
+				{
+					DryRun: &machine.DryRun{
+						Actions: []string{"leave maintenance mode", "continue the boot with the configuration"},
+					},
+				},
+			},
+		}, nil
+	}
+
 	reply = &machine.ApplyConfigurationResponse{
 		Messages: []*machine.ApplyConfiguration{
 			{},
@@ -0,0 +1,121 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// TokenAuthenticator requires every request to the maintenance service to carry the one-time token.
+//
+// The token is invalidated once the configuration is applied successfully.
+type TokenAuthenticator struct {
+	token string
+
+	mu      sync.Mutex
+	claimed bool
+	used    bool
+}
+
+// NewTokenAuthenticator initializes TokenAuthenticator.
+func NewTokenAuthenticator(token string) *TokenAuthenticator {
+	return &TokenAuthenticator{
+		token: token,
+	}
+}
+
+// UnaryInterceptor returns grpc.UnaryServerInterceptor which checks the token.
+//
+// Applying the configuration claims the token before the handler is called, so that
+// concurrent requests can't apply the configuration with the same token.
+// The claim is released if the configuration is not applied.
+func (a *TokenAuthenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		// dry run doesn't apply the configuration, so the token stays valid
+		applyReq, ok := req.(*machine.ApplyConfigurationRequest)
+		if !ok || applyReq.GetDryRun() {
+			if err := a.check(ctx); err != nil {
+				return nil, err
+			}
+
+			return handler(ctx, req)
+		}
+
+		if err := a.claim(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := handler(ctx, req)
+
+		a.mu.Lock()
+		a.claimed = false
+		a.used = err == nil
+		a.mu.Unlock()
+
+		return resp, err
+	}
+}
+
+// StreamInterceptor returns grpc.StreamServerInterceptor which checks the token.
+func (a *TokenAuthenticator) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.check(stream.Context()); err != nil {
+			return err
+		}
+
+		return handler(srv, stream)
+	}
+}
+
+func (a *TokenAuthenticator) check(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.checkLocked(ctx)
+}
+
+// claim checks the token and marks it as claimed, only one request can hold the claim.
+func (a *TokenAuthenticator) claim(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.checkLocked(ctx); err != nil {
+		return err
+	}
+
+	if a.claimed {
+		return status.Error(codes.Aborted, "maintenance token is being used by another request")
+	}
+
+	a.claimed = true
+
+	return nil
+}
+
+func (a *TokenAuthenticator) checkLocked(ctx context.Context) error {
+	if a.used {
+		return status.Error(codes.PermissionDenied, "maintenance token was already used")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	for _, token := range md.Get(constants.MaintenanceTokenMetadataKey) {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) == 1 {
+			return nil
+		}
+	}
+
+	return status.Error(codes.Unauthenticated, "maintenance token is missing or invalid")
+}
@@ -0,0 +1,157 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/talos-systems/talos/internal/app/maintenance/server"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+func TestTokenAuthenticator(t *testing.T) {
+	interceptor := server.NewTokenAuthenticator("secret").UnaryInterceptor()
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return req, nil
+	}
+
+	call := func(token string, req interface{}) codes.Code {
+		ctx := context.Background()
+
+		if token != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(constants.MaintenanceTokenMetadataKey, token))
+		}
+
+		_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{}, handler)
+
+		return status.Code(err)
+	}
+
+	assert.Equal(t, codes.Unauthenticated, call("", &empty.Empty{}))
+	assert.Equal(t, codes.Unauthenticated, call("wrong", &empty.Empty{}))
+	assert.Equal(t, codes.OK, call("secret", &empty.Empty{}))
+
+	// dry run doesn't invalidate the token
+	assert.Equal(t, codes.OK, call("secret", &machine.ApplyConfigurationRequest{DryRun: true}))
+	assert.Equal(t, codes.OK, call("secret", &machine.ApplyConfigurationRequest{DryRun: true}))
+
+	assert.Equal(t, codes.OK, call("secret", &machine.ApplyConfigurationRequest{}))
+
+	// token is invalidated once the config is applied
+	assert.Equal(t, codes.PermissionDenied, call("secret", &machine.ApplyConfigurationRequest{}))
+	assert.Equal(t, codes.PermissionDenied, call("secret", &empty.Empty{}))
+}
+
+type mockServerStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *mockServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestTokenAuthenticatorStream(t *testing.T) {
+	interceptor := server.NewTokenAuthenticator("secret").StreamInterceptor()
+
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		return nil
+	}
+
+	call := func(token string) codes.Code {
+		ctx := context.Background()
+
+		if token != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(constants.MaintenanceTokenMetadataKey, token))
+		}
+
+		return status.Code(interceptor(nil, &mockServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, handler))
+	}
+
+	assert.Equal(t, codes.Unauthenticated, call(""))
+	assert.Equal(t, codes.Unauthenticated, call("wrong"))
+	assert.Equal(t, codes.OK, call("secret"))
+}
+
+func TestTokenAuthenticatorConcurrentApply(t *testing.T) {
+	interceptor := server.NewTokenAuthenticator("secret").UnaryInterceptor()
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(constants.MaintenanceTokenMetadataKey, "secret"))
+
+	entered := make(chan struct{})
+	proceed := make(chan error)
+
+	blockingHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		entered <- struct{}{}
+
+		return req, <-proceed
+	}
+
+	apply := func(handler grpc.UnaryHandler) <-chan codes.Code {
+		ch := make(chan codes.Code, 1)
+
+		go func() {
+			_, err := interceptor(ctx, &machine.ApplyConfigurationRequest{}, &grpc.UnaryServerInfo{}, handler)
+
+			ch <- status.Code(err)
+		}()
+
+		return ch
+	}
+
+	failHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.InvalidArgument, "invalid config")
+	}
+
+	okHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return req, nil
+	}
+
+	// first apply claims the token
+	first := apply(blockingHandler)
+	<-entered
+
+	// concurrent applies can't use the claimed token
+	const concurrency = 10
+
+	results := make([]<-chan codes.Code, concurrency)
+
+	for i := range results {
+		results[i] = apply(okHandler)
+	}
+
+	for _, result := range results {
+		assert.Equal(t, codes.Aborted, <-result)
+	}
+
+	// other requests still pass while the token is claimed
+	_, err := interceptor(ctx, &empty.Empty{}, &grpc.UnaryServerInfo{}, okHandler)
+	assert.NoError(t, err)
+
+	// failed apply releases the claim
+	proceed <- status.Error(codes.InvalidArgument, "invalid config")
+	assert.Equal(t, codes.InvalidArgument, <-first)
+
+	assert.Equal(t, codes.InvalidArgument, <-apply(failHandler))
+
+	// successful apply invalidates the token
+	second := apply(blockingHandler)
+	<-entered
+	proceed <- nil
+	assert.Equal(t, codes.OK, <-second)
+
+	assert.Equal(t, codes.PermissionDenied, <-apply(okHandler))
+}
@@ -45,8 +45,30 @@ type Networkd struct {
 	hostname  string
 	resolvers []string
 
+	resolversSource string
+
 	sync.Mutex
-	ready bool
+	ready  bool
+	status Status
+}
+
+// Sources of the hostname and resolvers.
+//
+// Settings coming from the address methods use the method name (dhcp, static) as a source.
+const (
+	SourceConfig   = "config"
+	SourceKernel   = "kernel"
+	SourcePlatform = "platform"
+	SourceDefault  = "default"
+)
+
+// Status describes the effective hostname and resolvers, and where they came from.
+type Status struct {
+	Hostname       string
+	HostnameSource string
+
+	Resolvers       []string
+	ResolversSource string
 }
 
 // New takes the supplied configuration and creates an abstract representation
@@ -54,13 +76,15 @@ type Networkd struct {
 // nolint: gocyclo
 func New(config config.Provider) (*Networkd, error) {
 	var (
-		hostname  string
-		option    *string
-		result    *multierror.Error
-		resolvers []string
+		hostname        string
+		option          *string
+		result          *multierror.Error
+		resolvers       []string
+		resolversSource string
 	)
 
 	resolvers = []string{DefaultPrimaryResolver, DefaultSecondaryResolver}
+	resolversSource = SourceDefault
 
 	netconf := make(map[string][]nic.Option)
 
@@ -93,6 +117,7 @@ func New(config config.Provider) (*Networkd, error) {
 
 		if len(config.Machine().Network().Resolvers()) > 0 {
 			resolvers = config.Machine().Network().Resolvers()
+			resolversSource = SourceConfig
 		}
 	}
 
@@ -171,7 +196,7 @@ func New(config config.Provider) (*Networkd, error) {
 		}
 	}
 
-	return &Networkd{Interfaces: interfaces, Config: config, hostname: hostname, resolvers: resolvers}, result.ErrorOrNil()
+	return &Networkd{Interfaces: interfaces, Config: config, hostname: hostname, resolvers: resolvers, resolversSource: resolversSource}, result.ErrorOrNil()
 }
 
 // Configure handles the lifecycle for an interface. This includes creation,
@@ -197,6 +222,7 @@ func (n *Networkd) Configure() (err error) {
 	}
 
 	resolvers := []string{}
+	resolversSource := ""
 
 	for _, netif := range n.Interfaces {
 		for _, method := range netif.AddressMethod {
@@ -206,6 +232,7 @@ func (n *Networkd) Configure() (err error) {
 
 			for _, resolver := range method.Resolvers() {
 				resolvers = append(resolvers, resolver.String())
+				resolversSource = method.Name()
 			}
 		}
 	}
@@ -219,12 +246,18 @@ func (n *Networkd) Configure() (err error) {
 
 	if len(resolvers) == 0 {
 		resolvers = n.resolvers
+		resolversSource = n.resolversSource
 	}
 
 	if err = writeResolvConf(resolvers); err != nil {
 		return err
 	}
 
+	n.Lock()
+	n.status.Resolvers = resolvers
+	n.status.ResolversSource = resolversSource
+	n.Unlock()
+
 	n.SetReady()
 
 	return nil
@@ -255,11 +288,21 @@ func (n *Networkd) Reset() {
 // 4. DHCP
 // 5. Default with the format: talos-<ip addr>.
 func (n *Networkd) Hostname() (err error) {
-	hostname, domainname, address, err := n.decideHostname()
+	hostname, domainname, address, source, err := n.decideHostname()
 	if err != nil {
 		return err
 	}
 
+	n.Lock()
+	n.status.Hostname = hostname
+	n.status.HostnameSource = source
+
+	if domainname != "" {
+		n.status.Hostname += "." + domainname
+	}
+
+	n.Unlock()
+
 	if err = writeHosts(hostname, address, n.Config); err != nil {
 		return err
 	}
@@ -288,10 +331,11 @@ func (n *Networkd) Hostname() (err error) {
 }
 
 // nolint: gocyclo
-func (n *Networkd) decideHostname() (hostname, domainname string, address net.IP, err error) {
+func (n *Networkd) decideHostname() (hostname, domainname string, address net.IP, source string, err error) {
 	// Set hostname to default
 	address = net.ParseIP("127.0.1.1")
 	hostname = fmt.Sprintf("%s-%s", "talos", strings.ReplaceAll(address.String(), ".", "-"))
+	source = SourceDefault
 
 	// Sort interface names alphabetically so we can ensure parsing order
 	interfaceNames := make([]string, 0, len(n.Interfaces))
@@ -319,6 +363,7 @@ func (n *Networkd) decideHostname() (hostname, domainname string, address net.IP
 
 			if method.Hostname() != "" {
 				hostname = method.Hostname()
+				source = method.Name()
 
 				address = method.Address().IP
 
@@ -339,27 +384,30 @@ func (n *Networkd) decideHostname() (hostname, domainname string, address net.IP
 
 		if pHostname, err = p.Hostname(ctx); err == nil && string(pHostname) != "" {
 			hostname = string(pHostname)
+			source = SourcePlatform
 		}
 	}
 
 	// Kernel
 	if kHostname := procfs.ProcCmdline().Get(constants.KernelParamHostname).First(); kHostname != nil {
 		hostname = *kHostname
+		source = SourceKernel
 	}
 
 	// Allow user supplied hostname to win
 	if n.hostname != "" {
 		hostname = n.hostname
+		source = SourceConfig
 	}
 
 	hostParts := strings.Split(hostname, ".")
 
 	if len(hostParts[0]) > 63 {
-		return "", "", net.IP{}, fmt.Errorf("hostname length longer than max allowed (63): %s", hostParts[0])
+		return "", "", net.IP{}, "", fmt.Errorf("hostname length longer than max allowed (63): %s", hostParts[0])
 	}
 
 	if len(hostname) > 253 {
-		return "", "", net.IP{}, fmt.Errorf("hostname fqdn length longer than max allowed (253): %s", hostname)
+		return "", "", net.IP{}, "", fmt.Errorf("hostname fqdn length longer than max allowed (253): %s", hostname)
 	}
 
 	hostname = hostParts[0]
@@ -369,7 +417,15 @@ func (n *Networkd) decideHostname() (hostname, domainname string, address net.IP
 	}
 
 	// Only return the hostname portion of the name ( strip domain bits off )
-	return hostname, domainname, address, nil
+	return hostname, domainname, address, source, nil
+}
+
+// Status returns the effective hostname and resolvers, and where they came from.
+func (n *Networkd) Status() Status {
+	n.Lock()
+	defer n.Unlock()
+
+	return n.status
 }
 
 // Ready exposes the readiness state of networkd.
@@ -46,6 +46,7 @@ func (suite *NetworkdSuite) TestHostname() {
 		err          error
 		hostname     string
 		nwd          *Networkd
+		source       string
 		sampleConfig config.Provider
 	)
 
@@ -53,9 +54,10 @@ func (suite *NetworkdSuite) TestHostname() {
 	suite.Require().NoError(err)
 
 	// Default test
-	hostname, _, addr, err = nwd.decideHostname()
+	hostname, _, addr, source, err = nwd.decideHostname()
 	suite.Require().NoError(err)
 	suite.Assert().Equal("talos-127-0-1-1", hostname)
+	suite.Assert().Equal(SourceDefault, source)
 	suite.Assert().Equal(addr, net.ParseIP("127.0.1.1"))
 
 	// Static addressing tests
@@ -66,9 +68,10 @@ func (suite *NetworkdSuite) TestHostname() {
 	nwd, err = New(sampleConfig)
 	suite.Require().NoError(err)
 
-	hostname, _, addr, err = nwd.decideHostname()
+	hostname, _, addr, source, err = nwd.decideHostname()
 	suite.Require().NoError(err)
 	suite.Assert().Equal("myhostname", hostname)
+	suite.Assert().Equal(SourceConfig, source)
 	suite.Assert().Equal(addr, net.ParseIP("192.168.0.10"))
 
 	// Static for computed hostname ( talos-ip )
@@ -77,7 +80,7 @@ func (suite *NetworkdSuite) TestHostname() {
 	nwd, err = New(sampleConfig)
 	suite.Require().NoError(err)
 
-	hostname, _, addr, err = nwd.decideHostname()
+	hostname, _, addr, _, err = nwd.decideHostname()
 	suite.Require().NoError(err)
 	suite.Assert().Equal("talos-192-168-0-10", hostname)
 	suite.Assert().Equal(addr, net.ParseIP("192.168.0.10"))
@@ -89,7 +92,7 @@ func (suite *NetworkdSuite) TestHostname() {
 	suite.Require().NoError(err)
 
 	// nolint: dogsled
-	_, _, _, err = nwd.decideHostname()
+	_, _, _, _, err = nwd.decideHostname()
 	suite.Require().Error(err)
 
 	// Static for hostname vs domain name
@@ -98,7 +101,7 @@ func (suite *NetworkdSuite) TestHostname() {
 	nwd, err = New(sampleConfig)
 	suite.Require().NoError(err)
 
-	hostname, domainname, _, err = nwd.decideHostname()
+	hostname, domainname, _, _, err = nwd.decideHostname()
 	suite.Require().NoError(err)
 	suite.Assert().Equal("dadjokes", hostname)
 	suite.Assert().Equal("biz.dev.com.org.io", domainname)
@@ -121,9 +124,10 @@ func (suite *NetworkdSuite) TestHostname() {
 		},
 	}
 
-	hostname, _, addr, err = nwd.decideHostname()
+	hostname, _, addr, source, err = nwd.decideHostname()
 	suite.Require().NoError(err)
 	suite.Assert().Equal("evenbetterdadjokes", hostname)
+	suite.Assert().Equal("dhcp", source)
 	suite.Assert().Equal(addr.String(), "192.168.0.11")
 
 	// DHCP without OptionHostName
@@ -141,7 +145,7 @@ func (suite *NetworkdSuite) TestHostname() {
 		},
 	}
 
-	hostname, _, addr, err = nwd.decideHostname()
+	hostname, _, addr, _, err = nwd.decideHostname()
 	suite.Require().NoError(err)
 	suite.Assert().Equal("talos-192-168-0-11", hostname)
 	suite.Assert().Equal(addr, net.ParseIP("192.168.0.11"))
@@ -158,7 +162,7 @@ func (suite *NetworkdSuite) TestHostname() {
 		},
 	}
 
-	hostname, domainname, addr, err = nwd.decideHostname()
+	hostname, domainname, addr, _, err = nwd.decideHostname()
 	suite.Require().NoError(err)
 	suite.Assert().Equal("talos-192-168-0-11", hostname)
 	suite.Assert().Equal("domain.tld", domainname)
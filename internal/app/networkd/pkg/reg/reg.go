@@ -96,6 +96,22 @@ func (r *Registrator) Interfaces(ctx context.Context, in *empty.Empty) (reply *n
 	return networkd.GetDevices()
 }
 
+// Status returns the effective hostname and resolvers, and where they came from.
+func (r *Registrator) Status(ctx context.Context, in *empty.Empty) (reply *networkapi.StatusResponse, err error) {
+	status := r.Networkd.Status()
+
+	return &networkapi.StatusResponse{
+		Messages: []*networkapi.Status{
+			{
+				Hostname:        status.Hostname,
+				HostnameSource:  status.HostnameSource,
+				Resolvers:       status.Resolvers,
+				ResolversSource: status.ResolversSource,
+			},
+		},
+	}, nil
+}
+
 func toCIDR(family uint8, prefix net.IP, prefixLen int) string {
 	netLen := 32
 
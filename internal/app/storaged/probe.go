@@ -0,0 +1,132 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package internal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/talos-systems/go-blockdevice/blockdevice/filesystem/iso9660"
+	"github.com/talos-systems/go-blockdevice/blockdevice/filesystem/vfat"
+	"github.com/talos-systems/go-blockdevice/blockdevice/filesystem/xfs"
+	"github.com/talos-systems/go-blockdevice/blockdevice/probe"
+)
+
+// luksMagic is the LUKS header magic (both LUKS1 and LUKS2).
+var luksMagic = []byte("LUKS\xba\xbe")
+
+// ext superblock offsets.
+const (
+	extSuperBlockOffset = 1024
+	extMagic            = 0xef53
+
+	extFeatureCompatHasJournal = 0x4
+	extFeatureIncompatExtents  = 0x40
+)
+
+// filesystem describes the filesystem (or the encrypted volume) on the block device.
+type filesystem struct {
+	Type      string
+	Label     string
+	UUID      string
+	Encrypted bool
+}
+
+// probeFilesystem reads the superblock of the block device.
+//
+// Filesystem type is empty if the filesystem is not recognized.
+func probeFilesystem(path string) (fs filesystem, err error) {
+	sb, err := probe.FileSystem(path)
+	if err != nil {
+		return fs, err
+	}
+
+	switch sb := sb.(type) {
+	case *xfs.SuperBlock:
+		fs.Type = sb.Type()
+		fs.Label = trim(sb.Fname[:])
+		fs.UUID = formatUUID(sb.UUID[:])
+	case *vfat.SuperBlock:
+		fs.Type = sb.Type()
+		fs.Label = trim(sb.Label[:])
+		fs.UUID = fmt.Sprintf("%02X%02X-%02X%02X", sb.Serno[3], sb.Serno[2], sb.Serno[1], sb.Serno[0])
+	case *iso9660.SuperBlock:
+		fs.Type = sb.Type()
+		fs.Label = trim(sb.VolumeID[:])
+	default:
+		return probeRaw(path)
+	}
+
+	return fs, nil
+}
+
+// probeRaw recognizes the LUKS and ext2/3/4 superblocks.
+func probeRaw(path string) (fs filesystem, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return fs, err
+	}
+
+	// nolint: errcheck
+	defer f.Close()
+
+	buf := make([]byte, 2048)
+
+	if _, err = io.ReadFull(f, buf); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return fs, nil
+		}
+
+		return fs, err
+	}
+
+	if bytes.HasPrefix(buf, luksMagic) {
+		fs.Type = "crypto_LUKS"
+		fs.Encrypted = true
+		fs.UUID = trim(buf[168:208])
+
+		// LUKS2 header has the label
+		if binary.BigEndian.Uint16(buf[6:8]) == 2 {
+			fs.Label = trim(buf[24:72])
+		}
+
+		return fs, nil
+	}
+
+	ext := buf[extSuperBlockOffset:]
+
+	if binary.LittleEndian.Uint16(ext[0x38:0x3a]) == extMagic {
+		switch {
+		case binary.LittleEndian.Uint32(ext[0x60:0x64])&extFeatureIncompatExtents != 0:
+			fs.Type = "ext4"
+		case binary.LittleEndian.Uint32(ext[0x5c:0x60])&extFeatureCompatHasJournal != 0:
+			fs.Type = "ext3"
+		default:
+			fs.Type = "ext2"
+		}
+
+		fs.UUID = formatUUID(ext[0x68:0x78])
+		fs.Label = trim(ext[0x78:0x88])
+	}
+
+	return fs, nil
+}
+
+func trim(b []byte) string {
+	return string(bytes.Trim(b, " \x00"))
+}
+
+func formatUUID(b []byte) string {
+	u, err := uuid.FromBytes(b)
+	if err != nil {
+		return ""
+	}
+
+	return u.String()
+}
@@ -0,0 +1,72 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package internal
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeImage(t *testing.T, data []byte) string {
+	path := filepath.Join(t.TempDir(), "image")
+
+	image := make([]byte, 64*1024)
+	copy(image, data)
+
+	require.NoError(t, ioutil.WriteFile(path, image, 0o600))
+
+	return path
+}
+
+func TestProbeFilesystemExt4(t *testing.T) {
+	data := make([]byte, 2048)
+	sb := data[extSuperBlockOffset:]
+
+	binary.LittleEndian.PutUint16(sb[0x38:], extMagic)
+	binary.LittleEndian.PutUint32(sb[0x5c:], extFeatureCompatHasJournal)
+	binary.LittleEndian.PutUint32(sb[0x60:], extFeatureIncompatExtents)
+	copy(sb[0x68:], []byte{0x3b, 0x1d, 0x8a, 0x0e, 0x5c, 0x2f, 0x4e, 0x11, 0x9a, 0x31, 0x0c, 0x7d, 0x4f, 0x3b, 0x12, 0x5e})
+	copy(sb[0x78:], "data")
+
+	fs, err := probeFilesystem(writeImage(t, data))
+	require.NoError(t, err)
+
+	assert.Equal(t, filesystem{
+		Type:  "ext4",
+		Label: "data",
+		UUID:  "3b1d8a0e-5c2f-4e11-9a31-0c7d4f3b125e",
+	}, fs)
+}
+
+func TestProbeFilesystemLUKS(t *testing.T) {
+	data := make([]byte, 2048)
+
+	copy(data, luksMagic)
+	binary.BigEndian.PutUint16(data[6:], 2)
+	copy(data[24:], "secret")
+	copy(data[168:], "6c0d6b53-7a8c-4b8e-8f0e-2a1e3c5d7f90")
+
+	fs, err := probeFilesystem(writeImage(t, data))
+	require.NoError(t, err)
+
+	assert.Equal(t, filesystem{
+		Type:      "crypto_LUKS",
+		Label:     "secret",
+		UUID:      "6c0d6b53-7a8c-4b8e-8f0e-2a1e3c5d7f90",
+		Encrypted: true,
+	}, fs)
+}
+
+func TestProbeFilesystemUnknown(t *testing.T) {
+	fs, err := probeFilesystem(writeImage(t, nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, filesystem{}, fs)
+}
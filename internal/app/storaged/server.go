@@ -5,11 +5,21 @@
 package internal
 
 import (
+	"bufio"
 	"context"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/go-multierror"
+	"github.com/talos-systems/go-blockdevice/blockdevice"
 	"github.com/talos-systems/go-blockdevice/blockdevice/util"
+	"golang.org/x/sys/unix"
 
+	"github.com/talos-systems/talos/internal/pkg/disk"
 	"github.com/talos-systems/talos/pkg/machinery/api/storage"
 )
 
@@ -17,26 +27,258 @@ import (
 // TODO: this is not a full blown service yet, it's used as the common base in the machine and the maintenance services.
 type Server struct{}
 
-// Disks implements machine.MaintenanceService.
+// Disks implements storage.StorageService.
 func (s *Server) Disks(ctx context.Context, in *empty.Empty) (reply *storage.DisksResponse, err error) {
-	disks, err := util.GetDisks()
+	disks, err := disk.List()
 	if err != nil {
 		return nil, err
 	}
 
 	diskList := make([]*storage.Disk, len(disks))
 
-	for i, disk := range disks {
+	for i, d := range disks {
 		diskList[i] = &storage.Disk{
-			DeviceName: disk.DeviceName,
-			Model:      disk.Model,
-			Size:       disk.Size,
+			DeviceName: d.DeviceName,
+			Model:      d.Model,
+			Size:       d.Size,
+			Serial:     d.Serial,
+			Wwid:       d.WWID,
+			Transport:  d.Transport,
+			Namespace:  d.Namespace,
+			Paths:      d.Paths,
 		}
 	}
 
 	reply = &storage.DisksResponse{
-		Disks: diskList,
+		Messages: []*storage.Disks{
+			{
+				Disks: diskList,
+			},
+		},
 	}
 
 	return reply, nil
 }
+
+// Partitions implements storage.StorageService.
+func (s *Server) Partitions(ctx context.Context, in *empty.Empty) (reply *storage.PartitionsResponse, err error) {
+	disks, err := disk.List()
+	if err != nil {
+		return nil, err
+	}
+
+	mounts, err := readMounts()
+	if err != nil {
+		return nil, err
+	}
+
+	mountPoints := make(map[string]string, len(mounts))
+
+	for _, m := range mounts {
+		if _, ok := mountPoints[m.device]; !ok {
+			mountPoints[m.device] = m.mountPoint
+		}
+	}
+
+	var multiErr *multierror.Error
+
+	partitions := []*storage.Partition{}
+
+	for _, d := range disks {
+		diskPartitions, err := readPartitions(d.DeviceName)
+		if err != nil {
+			multiErr = multierror.Append(multiErr, err)
+
+			continue
+		}
+
+		for _, partition := range diskPartitions {
+			partition.MountPoint = mountPoints[partition.DeviceName]
+		}
+
+		partitions = append(partitions, diskPartitions...)
+	}
+
+	reply = &storage.PartitionsResponse{
+		Messages: []*storage.Partitions{
+			{
+				Partitions: partitions,
+			},
+		},
+	}
+
+	return reply, multiErr.ErrorOrNil()
+}
+
+// Mounts implements storage.StorageService.
+func (s *Server) Mounts(ctx context.Context, in *empty.Empty) (reply *storage.MountsResponse, err error) {
+	mounts, err := readMounts()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		stat     unix.Statfs_t
+		multiErr *multierror.Error
+	)
+
+	mountList := []*storage.Mount{}
+
+	for _, m := range mounts {
+		if !strings.HasPrefix(m.device, "/dev/") {
+			continue
+		}
+
+		if err := unix.Statfs(m.mountPoint, &stat); err != nil {
+			multiErr = multierror.Append(multiErr, err)
+
+			continue
+		}
+
+		mountList = append(mountList, &storage.Mount{
+			DeviceName:     m.device,
+			MountPoint:     m.mountPoint,
+			FilesystemType: m.fstype,
+			Options:        m.options,
+			Size:           uint64(stat.Bsize) * stat.Blocks,
+			Used:           uint64(stat.Bsize) * (stat.Blocks - stat.Bfree),
+			Available:      uint64(stat.Bsize) * stat.Bavail,
+		})
+	}
+
+	reply = &storage.MountsResponse{
+		Messages: []*storage.Mounts{
+			{
+				Mounts: mountList,
+			},
+		},
+	}
+
+	return reply, multiErr.ErrorOrNil()
+}
+
+// readPartitions reads the partition table of the disk and probes the filesystems on the partitions.
+//
+// Disks without the partition table have no partitions.
+func readPartitions(device string) ([]*storage.Partition, error) {
+	bd, err := blockdevice.Open(device)
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint: errcheck
+	defer bd.Close()
+
+	pt, err := bd.PartitionTable()
+	if err != nil {
+		if errors.Is(err, blockdevice.ErrMissingPartitionTable) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	blockSize := uint64(pt.Header().LogicalBlockSize)
+
+	var multiErr *multierror.Error
+
+	partitions := []*storage.Partition{}
+
+	for _, part := range pt.Partitions().Items() {
+		path, err := util.PartPath(device, int(part.Number))
+		if err != nil {
+			return nil, err
+		}
+
+		partition := &storage.Partition{
+			DeviceName: path,
+			Disk:       device,
+			Number:     uint32(part.Number),
+			Size:       part.Length() * blockSize,
+			Label:      part.Name,
+			Uuid:       part.ID.String(),
+		}
+
+		fs, err := probeFilesystem(path)
+		if err != nil {
+			multiErr = multierror.Append(multiErr, err)
+		}
+
+		partition.FilesystemType = fs.Type
+		partition.FilesystemLabel = fs.Label
+		partition.FilesystemUuid = fs.UUID
+		partition.Encrypted = fs.Encrypted
+
+		partitions = append(partitions, partition)
+	}
+
+	return partitions, multiErr.ErrorOrNil()
+}
+
+type mountEntry struct {
+	device     string
+	mountPoint string
+	fstype     string
+	options    string
+}
+
+// readMounts reads the mount table of the machined mount namespace.
+func readMounts() ([]mountEntry, error) {
+	f, err := os.Open("/proc/mounts")
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint: errcheck
+	defer f.Close()
+
+	return parseMounts(f)
+}
+
+func parseMounts(r io.Reader) ([]mountEntry, error) {
+	var mounts []mountEntry
+
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		if len(fields) < 4 {
+			continue
+		}
+
+		mounts = append(mounts, mountEntry{
+			device:     unescapeMountField(fields[0]),
+			mountPoint: unescapeMountField(fields[1]),
+			fstype:     fields[2],
+			options:    fields[3],
+		})
+	}
+
+	return mounts, scanner.Err()
+}
+
+// unescapeMountField decodes the octal escapes (e.g. `\040` for the space) used in /proc/mounts.
+func unescapeMountField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+
+				i += 3
+
+				continue
+			}
+		}
+
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,27 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMounts(t *testing.T) {
+	mounts, err := parseMounts(strings.NewReader(`proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0
+/dev/sda5 /var xfs rw,relatime,attr2,inode64,logbufs=8,logbsize=32k,prjquota 0 0
+/dev/sdb1 /var/mnt/my\040data ext4 rw,noatime 0 0
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []mountEntry{
+		{device: "proc", mountPoint: "/proc", fstype: "proc", options: "rw,nosuid,nodev,noexec,relatime"},
+		{device: "/dev/sda5", mountPoint: "/var", fstype: "xfs", options: "rw,relatime,attr2,inode64,logbufs=8,logbsize=32k,prjquota"},
+		{device: "/dev/sdb1", mountPoint: "/var/mnt/my data", fstype: "ext4", options: "rw,noatime"},
+	}, mounts)
+}
@@ -0,0 +1,113 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package internal
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/talos-systems/talos/internal/pkg/uevent"
+	"github.com/talos-systems/talos/pkg/machinery/api/storage"
+)
+
+var blockDeviceActions = map[string]storage.BlockDeviceEvent_Action{
+	"add":    storage.BlockDeviceEvent_ADD,
+	"remove": storage.BlockDeviceEvent_REMOVE,
+	"change": storage.BlockDeviceEvent_CHANGE,
+}
+
+// Watch implements storage.StorageService.
+//
+// Watch streams the block device events after they are processed by udevd.
+func (s *Server) Watch(in *empty.Empty, srv storage.StorageService_WatchServer) error {
+	listener, err := uevent.Listen(uevent.GroupUdev)
+	if err != nil {
+		return err
+	}
+
+	ctx := srv.Context()
+
+	go func() {
+		<-ctx.Done()
+
+		// nolint: errcheck
+		listener.Close()
+	}()
+
+	for {
+		event, err := listener.Read()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		msg := blockDeviceEvent(event)
+		if msg == nil {
+			continue
+		}
+
+		if err = srv.Send(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// blockDeviceEvent converts the udev event to the API message, nil is returned for the events which are not relevant.
+func blockDeviceEvent(event *uevent.Event) *storage.BlockDeviceEvent {
+	if event.Subsystem != "block" {
+		return nil
+	}
+
+	action, ok := blockDeviceActions[event.Action]
+	if !ok {
+		return nil
+	}
+
+	name := event.Properties["DEVNAME"]
+	if name == "" {
+		return nil
+	}
+
+	if !strings.HasPrefix(name, "/dev/") {
+		name = filepath.Join("/dev", name)
+	}
+
+	msg := &storage.BlockDeviceEvent{
+		Action:     action,
+		DeviceName: name,
+		DeviceType: event.Properties["DEVTYPE"],
+		Model:      event.Properties["ID_MODEL"],
+		Serial:     event.Properties["ID_SERIAL_SHORT"],
+	}
+
+	if action != storage.BlockDeviceEvent_REMOVE {
+		msg.Size = readSysfsSize(event.DevPath)
+	}
+
+	return msg
+}
+
+// readSysfsSize returns the size of the block device in bytes.
+func readSysfsSize(devpath string) uint64 {
+	contents, err := ioutil.ReadFile(filepath.Join("/sys", devpath, "size"))
+	if err != nil {
+		return 0
+	}
+
+	sectors, err := strconv.ParseUint(strings.TrimSpace(string(contents)), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	// sysfs reports the size in 512-byte sectors regardless of the logical block size
+	return sectors * 512
+}
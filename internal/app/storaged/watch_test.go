@@ -0,0 +1,48 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/talos-systems/talos/internal/pkg/uevent"
+	"github.com/talos-systems/talos/pkg/machinery/api/storage"
+)
+
+func TestBlockDeviceEvent(t *testing.T) {
+	assert.Equal(t, &storage.BlockDeviceEvent{
+		Action:     storage.BlockDeviceEvent_REMOVE,
+		DeviceName: "/dev/sdb1",
+		DeviceType: "partition",
+		Model:      "QEMU_HARDDISK",
+		Serial:     "QM00003",
+	}, blockDeviceEvent(&uevent.Event{
+		Action:    "remove",
+		DevPath:   "/devices/pci0000:00/0000:00:01.1/ata2/host1/target1:0:0/1:0:0:0/block/sdb/sdb1",
+		Subsystem: "block",
+		Properties: map[string]string{
+			"DEVNAME":         "/dev/sdb1",
+			"DEVTYPE":         "partition",
+			"ID_MODEL":        "QEMU_HARDDISK",
+			"ID_SERIAL_SHORT": "QM00003",
+		},
+	}))
+
+	assert.Nil(t, blockDeviceEvent(&uevent.Event{
+		Action:     "add",
+		DevPath:    "/devices/virtual/net/eth1",
+		Subsystem:  "net",
+		Properties: map[string]string{"INTERFACE": "eth1"},
+	}))
+
+	assert.Nil(t, blockDeviceEvent(&uevent.Event{
+		Action:     "bind",
+		DevPath:    "/devices/virtual/block/loop0",
+		Subsystem:  "block",
+		Properties: map[string]string{"DEVNAME": "loop0"},
+	}))
+}
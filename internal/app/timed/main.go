@@ -39,6 +39,7 @@ func main() {
 	}
 
 	server := DefaultServer
+	serverSource := "default"
 
 	config, err := configloader.NewFromStdin()
 	if err != nil {
@@ -49,6 +50,7 @@ func main() {
 	// Support for only a single time server currently
 	if len(config.Machine().Time().Servers()) >= 1 {
 		server = config.Machine().Time().Servers()[0]
+		serverSource = "config"
 	}
 
 	n, err := ntp.NewNTPClient(
@@ -66,9 +68,12 @@ func main() {
 		errch <- n.Daemon()
 	}()
 
+	registrator := reg.NewRegistrator(n)
+	registrator.ServerSource = serverSource
+
 	go func() {
 		errch <- factory.ListenAndServe(
-			reg.NewRegistrator(n),
+			registrator,
 			factory.Network("unix"),
 			factory.SocketPath(constants.TimeSocketPath),
 			factory.WithDefaultLog(),
@@ -22,6 +22,9 @@ import (
 // timeapi.Init interfaces.
 type Registrator struct {
 	Timed *ntp.NTP
+
+	// ServerSource describes where the time server setting came from (config, default).
+	ServerSource string
 }
 
 // NewRegistrator builds new Registrator instance.
@@ -46,7 +49,14 @@ func (r *Registrator) Time(ctx context.Context, in *empty.Empty) (reply *timeapi
 		return reply, err
 	}
 
-	return genProtobufTimeResponse(r.Timed.GetTime(), rt.Time, r.Timed.Server)
+	reply, err = genProtobufTimeResponse(r.Timed.GetTime(), rt.Time, r.Timed.Server)
+	if err != nil {
+		return reply, err
+	}
+
+	reply.Messages[0].ServerSource = r.ServerSource
+
+	return reply, nil
 }
 
 // TimeCheck issues a query to the specified ntp server and displays the results.
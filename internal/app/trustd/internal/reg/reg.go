@@ -6,6 +6,9 @@ package reg
 
 import (
 	"context"
+	stdlibx509 "crypto/x509"
+	"encoding/pem"
+	"errors"
 	"io/ioutil"
 	"log"
 	"os"
@@ -16,12 +19,19 @@ import (
 
 	securityapi "github.com/talos-systems/talos/pkg/machinery/api/security"
 	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
 // Registrator is the concrete type that implements the factory.Registrator and
 // securityapi.SecurityServer interfaces.
 type Registrator struct {
 	Config config.Provider
+
+	// Throttle limits the number of concurrent certificate requests, nil means no limit.
+	Throttle *Throttle
+
+	// TokenReviewer verifies Kubernetes service account tokens, nil disables service account certificates.
+	TokenReviewer TokenReviewer
 }
 
 // Register implements the factory.Registrator interface.
@@ -31,9 +41,21 @@ func (r *Registrator) Register(s *grpc.Server) {
 
 // Certificate implements the securityapi.SecurityServer interface.
 func (r *Registrator) Certificate(ctx context.Context, in *securityapi.CertificateRequest) (resp *securityapi.CertificateResponse, err error) {
+	if r.Throttle != nil {
+		var release func()
+
+		if release, err = r.Throttle.Acquire(ctx); err != nil {
+			log.Printf("certificate request throttled: %s (in flight %d)", err, r.Throttle.InFlight())
+
+			return nil, err
+		}
+
+		defer release()
+	}
+
 	// TODO: Verify that the request is coming from the IP addresss declared in
 	// the CSR.
-	signed, err := x509.NewCertificateFromCSRBytes(r.Config.Machine().Security().CA().Crt, r.Config.Machine().Security().CA().Key, in.Csr)
+	signed, err := signNodeCertificate(r.Config.Machine().Security().CA(), in.Csr)
 	if err != nil {
 		return
 	}
@@ -46,6 +68,56 @@ func (r *Registrator) Certificate(ctx context.Context, in *securityapi.Certifica
 	return resp, nil
 }
 
+// signNodeCertificate issues the node certificate for the CSR.
+//
+// Node certificates are used by apid to proxy the requests to other nodes on behalf of the clients,
+// so the certificates are issued with the impersonator role regardless of the requested subject.
+func signNodeCertificate(ca *x509.PEMEncodedCertificateAndKey, csrPEM []byte) (*x509.Certificate, error) {
+	caCrt, caKey, err := parseCA(ca)
+	if err != nil {
+		return nil, err
+	}
+
+	csrPemBlock, _ := pem.Decode(csrPEM)
+	if csrPemBlock == nil {
+		return nil, errors.New("failed to decode CSR PEM")
+	}
+
+	csr, err := stdlibx509.ParseCertificateRequest(csrPemBlock.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	csr.Subject.Organization = []string{constants.RoleImpersonator}
+
+	return x509.NewCertificateFromCSR(caCrt, caKey, csr)
+}
+
+// parseCA decodes the CA certificate and key.
+func parseCA(ca *x509.PEMEncodedCertificateAndKey) (*stdlibx509.Certificate, interface{}, error) {
+	caPemBlock, _ := pem.Decode(ca.Crt)
+	if caPemBlock == nil {
+		return nil, nil, errors.New("failed to decode CA certificate PEM")
+	}
+
+	caCrt, err := stdlibx509.ParseCertificate(caPemBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyPemBlock, _ := pem.Decode(ca.Key)
+	if keyPemBlock == nil {
+		return nil, nil, errors.New("failed to decode CA key PEM")
+	}
+
+	caKey, err := stdlibx509.ParsePKCS8PrivateKey(keyPemBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return caCrt, caKey, nil
+}
+
 // ReadFile implements the securityapi.SecurityServer interface.
 func (r *Registrator) ReadFile(ctx context.Context, in *securityapi.ReadFileRequest) (resp *securityapi.ReadFileResponse, err error) {
 	var b []byte
@@ -2,13 +2,116 @@
 // License, v. 2.0. If a copy of the MPL was not distributed with this
 // file, You can obtain one at http://mozilla.org/MPL/2.0/.
 
-package reg_test
+//nolint: testpackage
+package reg
 
-import "testing"
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	stdlibx509 "crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"testing"
+	"time"
 
-func TestEmpty(t *testing.T) {
-	// added for accurate coverage estimation
-	//
-	// please remove it once any unit-test is added
-	// for this package
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talos-systems/crypto/x509"
+
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+func TestSignNodeCertificate(t *testing.T) {
+	ca, err := x509.NewSelfSignedCertificateAuthority(x509.RSA(false), x509.Organization("talos"))
+	require.NoError(t, err)
+
+	csr, _, err := x509.NewEd25519CSRAndIdentity([]string{"talos-default-master-1"}, []net.IP{net.ParseIP("10.5.0.2")})
+	require.NoError(t, err)
+
+	signed, err := signNodeCertificate(&x509.PEMEncodedCertificateAndKey{
+		Crt: ca.CrtPEM,
+		Key: ca.KeyPEM,
+	}, csr.X509CertificateRequestPEM)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{constants.RoleImpersonator}, signed.X509Certificate.Subject.Organization)
+	assert.Equal(t, []string{"talos-default-master-1"}, signed.X509Certificate.DNSNames)
+	assert.NoError(t, signed.X509Certificate.CheckSignatureFrom(ca.Crt))
+}
+
+func TestSignServiceAccountCertificate(t *testing.T) {
+	ca, err := x509.NewSelfSignedCertificateAuthority(x509.RSA(false), x509.Organization("talos"))
+	require.NoError(t, err)
+
+	pemCA := &x509.PEMEncodedCertificateAndKey{
+		Crt: ca.CrtPEM,
+		Key: ca.KeyPEM,
+	}
+
+	for _, test := range []struct {
+		name          string
+		roles         []string
+		expectedError string
+	}{
+		{
+			name:  "allowed",
+			roles: []string{"os:reader"},
+		},
+		{
+			name:          "not allowed",
+			roles:         []string{"os:reader", "os:admin"},
+			expectedError: `role "os:admin" is not allowed`,
+		},
+		{
+			name:          "no roles",
+			expectedError: "at least one role should be requested",
+		},
+	} {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			_, key, err := ed25519.GenerateKey(rand.Reader)
+			require.NoError(t, err)
+
+			csrDER, err := stdlibx509.CreateCertificateRequest(rand.Reader, &stdlibx509.CertificateRequest{
+				Subject: pkix.Name{
+					CommonName:   "admin",
+					Organization: test.roles,
+				},
+				IPAddresses: []net.IP{net.ParseIP("10.5.0.2")},
+			}, key)
+			require.NoError(t, err)
+
+			csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+			signed, err := signServiceAccountCertificate(pemCA, csrPEM, "system:serviceaccount:kube-system:upgrader", []string{"os:reader"})
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, "system:serviceaccount:kube-system:upgrader", signed.X509Certificate.Subject.CommonName)
+			assert.Equal(t, test.roles, signed.X509Certificate.Subject.Organization)
+			assert.Empty(t, signed.X509Certificate.IPAddresses)
+			assert.True(t, signed.X509Certificate.NotAfter.Before(time.Now().Add(constants.ServiceAccountCertificateValidityDuration+time.Minute)))
+			assert.NoError(t, signed.X509Certificate.CheckSignatureFrom(ca.Crt))
+		})
+	}
+}
+
+func TestAuthorizeServiceAccount(t *testing.T) {
+	access := &v1alpha1.KubernetesTalosAPIAccessConfig{
+		AccessEnabled:                     true,
+		AccessAllowedRoles:                []string{"os:reader"},
+		AccessAllowedKubernetesNamespaces: []string{"kube-system"},
+	}
+
+	assert.NoError(t, authorizeServiceAccount(access, "system:serviceaccount:kube-system:upgrader"))
+	assert.EqualError(t, authorizeServiceAccount(access, "system:serviceaccount:default:upgrader"), `namespace "default" is not allowed`)
+	assert.EqualError(t, authorizeServiceAccount(access, "kubernetes-admin"), `"kubernetes-admin" is not a service account`)
 }
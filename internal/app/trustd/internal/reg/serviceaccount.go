@@ -0,0 +1,168 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package reg
+
+import (
+	"context"
+	stdlibx509 "crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/talos-systems/crypto/x509"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/talos-systems/talos/pkg/kubernetes"
+	securityapi "github.com/talos-systems/talos/pkg/machinery/api/security"
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// serviceAccountPrefix is the prefix of the Kubernetes service account user names.
+const serviceAccountPrefix = "system:serviceaccount:"
+
+// TokenReviewer verifies Kubernetes service account tokens.
+type TokenReviewer interface {
+	// Review returns the name of the user authenticated by the token.
+	Review(ctx context.Context, token string) (string, error)
+}
+
+// KubernetesTokenReviewer verifies the tokens with the Kubernetes TokenReview API.
+type KubernetesTokenReviewer struct {
+	Config config.Provider
+}
+
+// Review implements the TokenReviewer interface.
+func (r *KubernetesTokenReviewer) Review(ctx context.Context, token string) (string, error) {
+	client, err := kubernetes.NewTemporaryClientFromPKI(r.Config.Cluster().CA(), r.Config.Cluster().Endpoint())
+	if err != nil {
+		return "", err
+	}
+
+	review, err := client.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: []string{constants.ServiceAccountTokenAudience},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	if !review.Status.Authenticated {
+		return "", fmt.Errorf("token is not authenticated: %s", review.Status.Error)
+	}
+
+	return review.Status.User.Username, nil
+}
+
+// ServiceAccountCertificate implements the securityapi.SecurityServer interface.
+func (r *Registrator) ServiceAccountCertificate(ctx context.Context, in *securityapi.ServiceAccountCertificateRequest) (*securityapi.ServiceAccountCertificateResponse, error) {
+	access := r.Config.Machine().Features().KubernetesTalosAPIAccess()
+	if !access.Enabled() || r.TokenReviewer == nil {
+		return nil, status.Error(codes.FailedPrecondition, "Talos API access from Kubernetes service accounts is disabled")
+	}
+
+	username, err := r.TokenReviewer.Review(ctx, in.Token)
+	if err != nil {
+		log.Printf("service account token rejected: %s", err)
+
+		return nil, status.Error(codes.Unauthenticated, "service account token is not valid")
+	}
+
+	if err = authorizeServiceAccount(access, username); err != nil {
+		log.Printf("service account certificate denied for %q: %s", username, err)
+
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	signed, err := signServiceAccountCertificate(r.Config.Machine().Security().CA(), in.Csr, username, access.AllowedRoles())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	log.Printf("issued Talos API certificate for %q with roles %v", username, signed.X509Certificate.Subject.Organization)
+
+	return &securityapi.ServiceAccountCertificateResponse{
+		Ca:  r.Config.Machine().Security().CA().Crt,
+		Crt: signed.X509CertificatePEM,
+	}, nil
+}
+
+// authorizeServiceAccount checks that the service account is in one of the allowed namespaces.
+func authorizeServiceAccount(access config.KubernetesTalosAPIAccess, username string) error {
+	if !strings.HasPrefix(username, serviceAccountPrefix) {
+		return fmt.Errorf("%q is not a service account", username)
+	}
+
+	namespace := strings.SplitN(strings.TrimPrefix(username, serviceAccountPrefix), ":", 2)[0]
+
+	for _, allowed := range access.AllowedKubernetesNamespaces() {
+		if namespace == allowed {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("namespace %q is not allowed", namespace)
+}
+
+// signServiceAccountCertificate issues the short-lived client certificate for the service account.
+//
+// Requested roles are taken from the CSR subject organizations, all of them should be allowed.
+// The subject common name is always set to the service account user name.
+func signServiceAccountCertificate(ca *x509.PEMEncodedCertificateAndKey, csrPEM []byte, username string, allowedRoles []string) (*x509.Certificate, error) {
+	caCrt, caKey, err := parseCA(ca)
+	if err != nil {
+		return nil, err
+	}
+
+	csrPemBlock, _ := pem.Decode(csrPEM)
+	if csrPemBlock == nil {
+		return nil, errors.New("failed to decode CSR PEM")
+	}
+
+	csr, err := stdlibx509.ParseCertificateRequest(csrPemBlock.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := csr.Subject.Organization
+
+	// certificates without roles are not restricted by the rbac rules
+	if len(roles) == 0 {
+		return nil, errors.New("at least one role should be requested")
+	}
+
+	for _, role := range roles {
+		if !contains(allowedRoles, role) {
+			return nil, fmt.Errorf("role %q is not allowed", role)
+		}
+	}
+
+	csr.Subject.CommonName = username
+	csr.IPAddresses = nil
+	csr.DNSNames = nil
+
+	return x509.NewCertificateFromCSR(caCrt, caKey, csr,
+		x509.NotBefore(time.Now().Add(-time.Minute)),
+		x509.NotAfter(time.Now().Add(constants.ServiceAccountCertificateValidityDuration)),
+	)
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package reg
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Throttle limits the number of requests processed concurrently.
+//
+// Requests over the concurrency limit wait in the queue, requests over the
+// queue limit are rejected with codes.ResourceExhausted, so that the client retries later.
+//
+// Throttle implements prometheus.Collector to export the queue depth.
+type Throttle struct {
+	sem       chan struct{}
+	queued    int64
+	maxQueued int64
+	rejected  int64
+}
+
+var (
+	queueDepthDesc = prometheus.NewDesc("trustd_join_queue_depth", "Number of certificate requests waiting to be processed.", nil, nil)
+	inFlightDesc   = prometheus.NewDesc("trustd_join_in_flight", "Number of certificate requests being processed.", nil, nil)
+	rejectedDesc   = prometheus.NewDesc("trustd_join_rejected_total", "Number of certificate requests rejected because the queue is full.", nil, nil)
+)
+
+// NewThrottle initializes new Throttle.
+func NewThrottle(maxConcurrent, maxQueued int) *Throttle {
+	return &Throttle{
+		sem:       make(chan struct{}, maxConcurrent),
+		maxQueued: int64(maxQueued),
+	}
+}
+
+// Acquire waits for the request slot to become available.
+//
+// Returned function should be called to release the slot.
+func (t *Throttle) Acquire(ctx context.Context) (func(), error) {
+	select {
+	case t.sem <- struct{}{}:
+		return t.release, nil
+	default:
+	}
+
+	if atomic.AddInt64(&t.queued, 1) > t.maxQueued {
+		atomic.AddInt64(&t.queued, -1)
+		atomic.AddInt64(&t.rejected, 1)
+
+		return nil, status.Errorf(codes.ResourceExhausted, "too many pending requests (queue depth %d)", t.QueueDepth())
+	}
+
+	defer atomic.AddInt64(&t.queued, -1)
+
+	select {
+	case t.sem <- struct{}{}:
+		return t.release, nil
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// QueueDepth returns the number of requests waiting for the slot.
+func (t *Throttle) QueueDepth() int {
+	return int(atomic.LoadInt64(&t.queued))
+}
+
+// InFlight returns the number of requests being processed.
+func (t *Throttle) InFlight() int {
+	return len(t.sem)
+}
+
+// Describe implements prometheus.Collector.
+func (t *Throttle) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepthDesc
+	ch <- inFlightDesc
+	ch <- rejectedDesc
+}
+
+// Collect implements prometheus.Collector.
+func (t *Throttle) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, float64(t.QueueDepth()))
+	ch <- prometheus.MustNewConstMetric(inFlightDesc, prometheus.GaugeValue, float64(t.InFlight()))
+	ch <- prometheus.MustNewConstMetric(rejectedDesc, prometheus.CounterValue, float64(atomic.LoadInt64(&t.rejected)))
+}
+
+func (t *Throttle) release() {
+	<-t.sem
+}
@@ -0,0 +1,82 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package reg_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/talos-systems/talos/internal/app/trustd/internal/reg"
+)
+
+func TestThrottle(t *testing.T) {
+	throttle := reg.NewThrottle(1, 1)
+
+	release, err := throttle.Acquire(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, throttle.InFlight())
+
+	acquired := make(chan error, 1)
+
+	go func() {
+		r, e := throttle.Acquire(context.Background())
+		if e == nil {
+			r()
+		}
+
+		acquired <- e
+	}()
+
+	// wait for the second request to be queued
+	for throttle.QueueDepth() != 1 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// queue is full
+	_, err = throttle.Acquire(context.Background())
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	assert.NoError(t, testutil.CollectAndCompare(throttle, strings.NewReader(`
+# HELP trustd_join_in_flight Number of certificate requests being processed.
+# TYPE trustd_join_in_flight gauge
+trustd_join_in_flight 1
+# HELP trustd_join_queue_depth Number of certificate requests waiting to be processed.
+# TYPE trustd_join_queue_depth gauge
+trustd_join_queue_depth 1
+# HELP trustd_join_rejected_total Number of certificate requests rejected because the queue is full.
+# TYPE trustd_join_rejected_total counter
+trustd_join_rejected_total 1
+`)))
+
+	release()
+
+	require.NoError(t, <-acquired)
+	assert.Equal(t, 0, throttle.QueueDepth())
+	assert.Equal(t, 0, throttle.InFlight())
+}
+
+func TestThrottleCanceled(t *testing.T) {
+	throttle := reg.NewThrottle(1, 1)
+
+	release, err := throttle.Acquire(context.Background())
+	require.NoError(t, err)
+
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = throttle.Acquire(ctx)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Equal(t, 0, throttle.QueueDepth())
+}
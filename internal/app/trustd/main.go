@@ -5,10 +5,14 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	stdlibnet "net"
+	"net/http"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/talos-systems/crypto/tls"
 	"github.com/talos-systems/net"
 	"google.golang.org/grpc"
@@ -89,12 +93,44 @@ func main() {
 	}
 
 	creds := basic.NewTokenCredentials(config.Machine().Security().Token())
+	tokenInterceptor := creds.UnaryInterceptor()
+
+	joinThrottle := config.Cluster().JoinThrottle()
+
+	throttle := reg.NewThrottle(joinThrottle.MaxConcurrentRequests(), joinThrottle.MaxQueuedRequests())
+
+	prometheus.MustRegister(throttle)
+
+	go func() {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+
+		if metricsErr := http.ListenAndServe(constants.TrustdMetricsAddress, mux); metricsErr != nil {
+			log.Printf("failed to serve metrics: %v", metricsErr)
+		}
+	}()
+
+	registrator := &reg.Registrator{
+		Config:   config,
+		Throttle: throttle,
+	}
+
+	if config.Machine().Features().KubernetesTalosAPIAccess().Enabled() {
+		registrator.TokenReviewer = &reg.KubernetesTokenReviewer{Config: config}
+	}
 
 	err = factory.ListenAndServe(
-		&reg.Registrator{Config: config},
+		registrator,
 		factory.Port(constants.TrustdPort),
 		factory.WithDefaultLog(),
-		factory.WithUnaryInterceptor(creds.UnaryInterceptor()),
+		factory.WithUnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			// service account certificate requests are authenticated with the Kubernetes service account token
+			if info.FullMethod == "/securityapi.SecurityService/ServiceAccountCertificate" {
+				return handler(ctx, req)
+			}
+
+			return tokenInterceptor(ctx, req, info, handler)
+		}),
 		factory.ServerOptions(
 			grpc.Creds(
 				credentials.NewTLS(tlsConfig),
@@ -177,6 +177,42 @@ func (suite *ApplyConfigSuite) TestApplyNoReboot() {
 	suite.Require().NoError(err, "failed to apply deferred configuration (node %q): %w", node)
 }
 
+// TestApplyDryRun verifies the apply config API in dry run mode.
+func (suite *ApplyConfigSuite) TestApplyDryRun() {
+	suite.WaitForBootDone(suite.ctx)
+
+	node := suite.RandomDiscoveredNode()
+	nodeCtx := client.WithNodes(suite.ctx, node)
+
+	provider, err := suite.readConfigFromNode(nodeCtx)
+	suite.Require().NoError(err, "failed to read existing config from node %q", node)
+
+	provider.Machine().Sysctls()[applyConfigNoRebootTestSysctl] = applyConfigNoRebootTestSysctlVal
+
+	cfgDataOut, err := provider.Bytes()
+	suite.Require().NoError(err, "failed to marshal updated machine config data (node %q)", node)
+
+	resp, err := suite.Client.ApplyConfiguration(nodeCtx, &machineapi.ApplyConfigurationRequest{
+		Data:   cfgDataOut,
+		DryRun: true,
+	})
+	suite.Require().NoError(err, "failed to apply configuration in dry run mode (node %q)", node)
+	suite.Require().Len(resp.Messages, 1)
+
+	dryRun := resp.Messages[0].DryRun
+	suite.Require().NotNil(dryRun)
+
+	suite.Assert().NotEmpty(dryRun.ConfigChanges)
+	suite.Assert().Contains(dryRun.ConfigDiff, applyConfigNoRebootTestSysctl)
+	suite.Assert().True(dryRun.RebootRequired)
+
+	// verify that the configuration was not changed
+	newProvider, err := suite.readConfigFromNode(nodeCtx)
+	suite.Require().NoError(err, "failed to read configuration from node %q", node)
+
+	suite.Assert().NotContains(newProvider.Machine().Sysctls(), applyConfigNoRebootTestSysctl)
+}
+
 func (suite *ApplyConfigSuite) readConfigFromNode(nodeCtx context.Context) (config.Provider, error) {
 	// Load the current node machine config
 	cfgData := new(bytes.Buffer)
@@ -8,12 +8,14 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"io/ioutil"
 	"time"
 
 	"github.com/talos-systems/talos/internal/integration/base"
 	"github.com/talos-systems/talos/pkg/machinery/api/common"
+	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/client"
 )
 
@@ -118,6 +120,45 @@ DrainLoop:
 	suite.Assert().Greater(logCount, 10)
 }
 
+// TestTailLinesStructured verifies that last messages are returned in structured mode.
+func (suite *DmesgSuite) TestTailLinesStructured() {
+	dmesgStream, err := suite.Client.DmesgGeneric(
+		suite.ctx,
+		&machineapi.DmesgRequest{
+			TailLines:  5,
+			Structured: true,
+		},
+	)
+	suite.Require().NoError(err)
+
+	count := 0
+
+	for {
+		msg, err := dmesgStream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			suite.Require().NoError(err)
+		}
+
+		var message struct {
+			Facility string `json:"facility"`
+			Priority string `json:"priority"`
+			Message  string `json:"message"`
+		}
+
+		suite.Require().NoError(json.Unmarshal(msg.Bytes, &message))
+		suite.Assert().NotEmpty(message.Facility)
+		suite.Assert().NotEmpty(message.Priority)
+
+		count++
+	}
+
+	suite.Assert().Equal(5, count)
+}
+
 // TestClusterHasDmesg verifies that all the cluster nodes have dmesg.
 func (suite *DmesgSuite) TestClusterHasDmesg() {
 	nodes := suite.DiscoverNodes().Nodes()
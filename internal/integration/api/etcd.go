@@ -131,7 +131,7 @@ func (suite *EtcdSuite) TestEtcdLeaveCluster() {
 	// NB: Reboot the node so that it can rejoin the etcd cluster. This allows us
 	// to check the cluster health and catch any issues in rejoining.
 	suite.AssertRebooted(suite.ctx, node, func(nodeCtx context.Context) error {
-		_, err = suite.Client.MachineClient.Reboot(nodeCtx, &empty.Empty{})
+		_, err = suite.Client.MachineClient.Reboot(nodeCtx, &machineapi.RebootRequest{})
 
 		return err
 	}, 10*time.Minute)
@@ -242,6 +242,49 @@ func (suite *ResetSuite) TestResetWithSpecState() {
 	suite.Assert().Equal(preReset, postReset, "ephemeral partition was not reset")
 }
 
+// TestResetStream resets ephemeral partition on the node watching the reset progress.
+func (suite *ResetSuite) TestResetStream() {
+	if !suite.Capabilities().SupportsReboot {
+		suite.T().Skip("cluster doesn't support reboot (and reset)")
+	}
+
+	if suite.Cluster == nil {
+		suite.T().Skip("without full cluster state reset test is not reliable (can't wait for cluster readiness in between resets)")
+	}
+
+	node := suite.RandomDiscoveredNode()
+
+	suite.T().Log("Resetting node with spec=[EPHEMERAL] watching the progress", node)
+
+	var phases []string
+
+	suite.AssertRebooted(suite.ctx, node, func(nodeCtx context.Context) error {
+		err := suite.Client.ResetWatch(nodeCtx, &machineapi.ResetRequest{
+			Reboot:   true,
+			Graceful: true,
+			SystemPartitionsToWipe: []*machineapi.ResetPartitionSpec{
+				{
+					Label: constants.EphemeralPartitionLabel,
+					Wipe:  true,
+				},
+			},
+		}, func(ch <-chan client.Event) {
+			for event := range ch {
+				if msg, ok := event.Payload.(*machineapi.PhaseEvent); ok && msg.GetAction() == machineapi.PhaseEvent_START {
+					phases = append(phases, msg.GetPhase())
+				}
+			}
+		})
+
+		// the connection is closed when the node reboots
+		suite.T().Logf("reset watch finished: %v", err)
+
+		return nil
+	}, 5*time.Minute)
+
+	suite.Assert().Contains(phases, "resetSpec")
+}
+
 func init() {
 	allSuites = append(allSuites, new(ResetSuite))
 }
@@ -51,6 +51,19 @@ func (suite *VersionSuite) TestExpectedVersionMaster() {
 	suite.Assert().Equal(suite.Version, v.Messages[0].Version.Tag)
 }
 
+// TestSystemInfo verifies system info API.
+func (suite *VersionSuite) TestSystemInfo() {
+	info, err := suite.Client.SystemInfo(suite.ctx)
+	suite.Require().NoError(err)
+
+	suite.Require().Len(info.Messages, 1)
+
+	suite.Assert().Equal(suite.Version, info.Messages[0].Version.Tag)
+	suite.Assert().NotEmpty(info.Messages[0].Hostname)
+	suite.Assert().NotEmpty(info.Messages[0].KernelVersion)
+	suite.Assert().NotNil(info.Messages[0].Platform)
+}
+
 // TestSameVersionCluster verifies that all the nodes are on the same version.
 func (suite *VersionSuite) TestSameVersionCluster() {
 	nodes := suite.DiscoverNodes().Nodes()
@@ -0,0 +1,41 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build integration_cli
+
+package cli
+
+import (
+	"regexp"
+
+	"github.com/talos-systems/talos/internal/integration/base"
+)
+
+// CertificatesSuite verifies certificates command.
+type CertificatesSuite struct {
+	base.CLISuite
+}
+
+// SuiteName ...
+func (suite *CertificatesSuite) SuiteName() string {
+	return "cli.CertificatesSuite"
+}
+
+// TestSuccess verifies successful execution.
+func (suite *CertificatesSuite) TestSuccess() {
+	suite.RunCLI([]string{"certificates", "--within", "1h", "--nodes", suite.RandomDiscoveredNode()},
+		base.StdoutShouldMatch(regexp.MustCompile(`(?s)NOT AFTER.*\sapid\s.*OK`)),
+		base.StdoutShouldMatch(regexp.MustCompile(`\skubelet\s`)),
+	)
+}
+
+// TestOnlyExpiring verifies that valid certificates are filtered out.
+func (suite *CertificatesSuite) TestOnlyExpiring() {
+	suite.RunCLI([]string{"certificates", "--within", "1h", "--only-expiring", "--nodes", suite.RandomDiscoveredNode()},
+		base.StdoutShouldNotMatch(regexp.MustCompile(`\sapid\s`)))
+}
+
+func init() {
+	allSuites = append(allSuites, new(CertificatesSuite))
+}
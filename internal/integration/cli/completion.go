@@ -7,6 +7,8 @@
 package cli
 
 import (
+	"regexp"
+
 	"github.com/talos-systems/talos/internal/integration/base"
 )
 
@@ -24,6 +26,26 @@ func (suite *CompletionSuite) SuiteName() string {
 func (suite *CompletionSuite) TestSuccess() {
 	suite.RunCLI([]string{"completion", "bash"})
 	suite.RunCLI([]string{"completion", "zsh"})
+	suite.RunCLI([]string{"completion", "fish"})
+}
+
+// TestDynamicCompletion verifies completion of the service IDs and nodes.
+func (suite *CompletionSuite) TestDynamicCompletion() {
+	node := suite.RandomDiscoveredNode()
+
+	suite.RunCLI([]string{"__complete", "service", "--nodes", node, ""},
+		base.StdoutShouldMatch(regexp.MustCompile(`(?m)^apid$`)),
+		base.StdoutShouldMatch(regexp.MustCompile(`(?m)^machined$`)),
+	)
+
+	suite.RunCLI([]string{"__complete", "service", "apid", ""},
+		base.StdoutShouldMatch(regexp.MustCompile(`(?m)^restart$`)),
+	)
+
+	// last line of the output is the completion directive (":4")
+	suite.RunCLI([]string{"__complete", "version", "--nodes", ""},
+		base.StdoutShouldMatch(regexp.MustCompile(`(?m)^[^:\s]\S*$`)),
+	)
 }
 
 func init() {
@@ -14,6 +14,7 @@ import (
 
 	"github.com/talos-systems/talos/internal/integration/base"
 	clientconfig "github.com/talos-systems/talos/pkg/machinery/client/config"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
 )
 
 // TalosconfigSuite verifies dmesg command.
@@ -66,6 +67,68 @@ func (suite *TalosconfigSuite) TestMerge() {
 	suite.Require().NotNil(c.Contexts["foo-1"])
 }
 
+// TestNew checks talosctl config new.
+func (suite *TalosconfigSuite) TestNew() {
+	tempDir, err := ioutil.TempDir("", "talos")
+	defer os.RemoveAll(tempDir) //nolint: errcheck
+
+	suite.Require().NoError(err)
+
+	node := suite.RandomDiscoveredNode(machine.TypeControlPlane)
+
+	path := filepath.Join(tempDir, "talosconfig")
+
+	suite.RunCLI([]string{"config", "new", "--nodes", node, "--crt-ttl", "1h", path},
+		base.StdoutEmpty())
+
+	suite.Require().FileExists(path)
+
+	// new client configuration should be usable on its own
+	suite.RunCLI([]string{"--talosconfig", path, "--nodes", node, "version"},
+		base.StdoutShouldMatch(regexp.MustCompile(`Server:`)))
+
+	// existing file is not overwritten
+	suite.RunCLI([]string{"config", "new", "--nodes", node, path},
+		base.ShouldFail(),
+		base.StdoutEmpty(),
+		base.StderrNotEmpty(),
+		base.StderrShouldMatch(regexp.MustCompile(`already exists`)))
+}
+
+// TestRenew checks talosctl config renew.
+func (suite *TalosconfigSuite) TestRenew() {
+	tempDir, err := ioutil.TempDir("", "talos")
+	defer os.RemoveAll(tempDir) //nolint: errcheck
+
+	suite.Require().NoError(err)
+
+	node := suite.RandomDiscoveredNode(machine.TypeControlPlane)
+
+	path := filepath.Join(tempDir, "talosconfig")
+
+	suite.RunCLI([]string{"config", "new", "--nodes", node, "--crt-ttl", "1h", path},
+		base.StdoutEmpty())
+
+	before, err := clientconfig.Open(path)
+	suite.Require().NoError(err)
+
+	// fresh certificate is not renewed
+	suite.RunCLI([]string{"--talosconfig", path, "--nodes", node, "config", "renew"},
+		base.StdoutShouldMatch(regexp.MustCompile(`renewal is not required`)))
+
+	suite.RunCLI([]string{"--talosconfig", path, "--nodes", node, "config", "renew", "--force"},
+		base.StdoutShouldMatch(regexp.MustCompile(`was renewed`)))
+
+	after, err := clientconfig.Open(path)
+	suite.Require().NoError(err)
+
+	suite.Assert().NotEqual(before.Contexts[before.Context].Crt, after.Contexts[after.Context].Crt)
+
+	// renewed client configuration should be usable
+	suite.RunCLI([]string{"--talosconfig", path, "--nodes", node, "version"},
+		base.StdoutShouldMatch(regexp.MustCompile(`Server:`)))
+}
+
 func init() {
 	allSuites = append(allSuites, new(TalosconfigSuite))
 }
@@ -0,0 +1,39 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build integration_cli
+
+package cli
+
+import (
+	"regexp"
+
+	"github.com/talos-systems/talos/internal/integration/base"
+)
+
+// DisksSuite verifies disks command.
+type DisksSuite struct {
+	base.CLISuite
+}
+
+// SuiteName ...
+func (suite *DisksSuite) SuiteName() string {
+	return "cli.DisksSuite"
+}
+
+// TestSuccess verifies successful execution.
+func (suite *DisksSuite) TestSuccess() {
+	suite.RunCLI([]string{"disks", "--nodes", suite.RandomDiscoveredNode()},
+		base.StdoutShouldMatch(regexp.MustCompile(`(?s)DEV.*MODEL.*SIZE`)))
+}
+
+// TestPartitions verifies system partitions are listed.
+func (suite *DisksSuite) TestPartitions() {
+	suite.RunCLI([]string{"disks", "--partitions", "--nodes", suite.RandomDiscoveredNode()},
+		base.StdoutShouldMatch(regexp.MustCompile(`(?s)FILESYSTEM.*\sSTATE\s.*\sxfs\s.*/system/state`)))
+}
+
+func init() {
+	allSuites = append(allSuites, new(DisksSuite))
+}
@@ -7,6 +7,8 @@
 package cli
 
 import (
+	"regexp"
+
 	"github.com/talos-systems/talos/internal/integration/base"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
 )
@@ -33,6 +35,17 @@ func (suite *EtcdSuite) TestForfeitLeadership() {
 	)
 }
 
+// TestStatus etcd status should list the members.
+//
+// Maintenance is disabled by default, so there's a warning on stderr.
+func (suite *EtcdSuite) TestStatus() {
+	suite.RunCLI([]string{"etcd", "status", "--nodes", suite.RandomDiscoveredNode(machine.TypeControlPlane)},
+		base.StdoutShouldMatch(regexp.MustCompile(`NODE\s+MEMBER\s+LEADER`)),
+		base.StderrNotEmpty(),
+		base.StderrShouldMatch(regexp.MustCompile(`etcd maintenance (is disabled|hasn't run yet)`)),
+	)
+}
+
 func init() {
 	allSuites = append(allSuites, new(EtcdSuite))
 }
@@ -0,0 +1,55 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build integration_cli
+
+package cli
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/talos-systems/talos/internal/integration/base"
+)
+
+// GetSuite verifies get command.
+type GetSuite struct {
+	base.CLISuite
+}
+
+// SuiteName ...
+func (suite *GetSuite) SuiteName() string {
+	return "cli.GetSuite"
+}
+
+// TestMembers verifies that the node finds itself in the list of cluster members.
+func (suite *GetSuite) TestMembers() {
+	node := suite.RandomDiscoveredNode()
+
+	suite.RunAndWaitForMatch([]string{"get", "members", "--nodes", node},
+		regexp.MustCompile(`(?m)^\S+\s+[0-9a-f]{32}\s+\S+\s+(init|controlplane|join)\s`),
+		time.Minute,
+	)
+}
+
+// TestCertificates verifies that certificates are listed with their expiration dates.
+func (suite *GetSuite) TestCertificates() {
+	suite.RunCLI([]string{"get", "certificates", "--nodes", suite.RandomDiscoveredNode()},
+		base.StdoutShouldMatch(regexp.MustCompile(`NOT AFTER\s+EXPIRES`)),
+		base.StdoutShouldMatch(regexp.MustCompile(`\sapid\s`)),
+	)
+}
+
+// TestUnsupportedType verifies that unknown resource types are rejected.
+func (suite *GetSuite) TestUnsupportedType() {
+	suite.RunCLI([]string{"get", "nosuchresource", "--nodes", suite.RandomDiscoveredNode()},
+		base.ShouldFail(),
+		base.StdoutEmpty(),
+		base.StderrShouldMatch(regexp.MustCompile(`unsupported resource type "nosuchresource"`)),
+	)
+}
+
+func init() {
+	allSuites = append(allSuites, new(GetSuite))
+}
@@ -0,0 +1,33 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build integration_cli
+
+package cli
+
+import (
+	"regexp"
+
+	"github.com/talos-systems/talos/internal/integration/base"
+)
+
+// ImageSuite verifies image command.
+type ImageSuite struct {
+	base.CLISuite
+}
+
+// SuiteName ...
+func (suite *ImageSuite) SuiteName() string {
+	return "cli.ImageSuite"
+}
+
+// TestList verifies Kubernetes images are listed.
+func (suite *ImageSuite) TestList() {
+	suite.RunCLI([]string{"image", "ls", "--kubernetes", "--nodes", suite.RandomDiscoveredNode()},
+		base.StdoutShouldMatch(regexp.MustCompile(`(?s)IMAGE.*DIGEST.*k8s\.io\s+k8s\.gcr\.io/`)))
+}
+
+func init() {
+	allSuites = append(allSuites, new(ImageSuite))
+}
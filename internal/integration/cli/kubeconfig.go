@@ -126,6 +126,60 @@ func (suite *KubeconfigSuite) TestMergeOverwrite() {
 	suite.Require().Equal(len(config.Contexts), 1)
 }
 
+// TestMergeNameTemplates tests merge config into existing kubeconfig with custom cluster and context names.
+func (suite *KubeconfigSuite) TestMergeNameTemplates() {
+	tempDir, err := ioutil.TempDir("", "talos")
+	suite.Require().NoError(err)
+
+	defer os.RemoveAll(tempDir) //nolint: errcheck
+
+	path := filepath.Join(tempDir, "config")
+
+	suite.RunCLI([]string{"kubeconfig", "--nodes", suite.RandomDiscoveredNode(machine.TypeControlPlane), path},
+		base.StdoutEmpty())
+	suite.RunCLI([]string{"kubeconfig", "--nodes", suite.RandomDiscoveredNode(machine.TypeControlPlane),
+		"--cluster-name-template", "custom-{{ .Cluster }}", "--context-name-template", "{{ .Cluster }}", path},
+		base.StdoutEmpty())
+
+	config, err := clientcmd.LoadFromFile(path)
+	suite.Require().NoError(err)
+
+	suite.Require().Len(config.Contexts, 2)
+	suite.Require().Len(config.Clusters, 2)
+	suite.Require().Len(config.AuthInfos, 2)
+
+	context := config.Contexts[config.CurrentContext]
+	suite.Require().NotNil(context)
+	suite.Assert().Equal(config.CurrentContext, context.Cluster)
+	suite.Assert().Equal("admin@"+context.Cluster, context.AuthInfo)
+}
+
+// TestExecCredentials generates kubeconfig with the exec credential plugin.
+func (suite *KubeconfigSuite) TestExecCredentials() {
+	tempDir, err := ioutil.TempDir("", "talos")
+	suite.Require().NoError(err)
+
+	defer os.RemoveAll(tempDir) //nolint: errcheck
+
+	node := suite.RandomDiscoveredNode(machine.TypeControlPlane)
+
+	suite.RunCLI([]string{"kubeconfig", "--merge=false", "--exec-credentials", "--nodes", node, tempDir},
+		base.StdoutEmpty())
+
+	config, err := clientcmd.LoadFromFile(filepath.Join(tempDir, "kubeconfig"))
+	suite.Require().NoError(err)
+
+	for _, authInfo := range config.AuthInfos {
+		suite.Assert().Empty(authInfo.ClientKeyData)
+		suite.Require().NotNil(authInfo.Exec)
+		suite.Assert().Contains(authInfo.Exec.Args, "kubernetes-credentials")
+	}
+
+	suite.RunCLI([]string{"kubernetes-credentials", "--nodes", node, "--crt-ttl", "10m"},
+		base.StdoutShouldMatch(regexp.MustCompile(`"kind":"ExecCredential"`)),
+		base.StdoutShouldMatch(regexp.MustCompile(`"clientKeyData":"-----BEGIN`)))
+}
+
 func init() {
 	allSuites = append(allSuites, new(KubeconfigSuite))
 }
@@ -0,0 +1,46 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build integration_cli
+
+package cli
+
+import (
+	"regexp"
+
+	"github.com/talos-systems/talos/internal/integration/base"
+)
+
+// MachineconfigSuite verifies machineconfig command.
+type MachineconfigSuite struct {
+	base.CLISuite
+}
+
+// SuiteName ...
+func (suite *MachineconfigSuite) SuiteName() string {
+	return "cli.MachineconfigSuite"
+}
+
+// TestHistory verifies config history listing.
+func (suite *MachineconfigSuite) TestHistory() {
+	suite.RunCLI([]string{"machineconfig", "history", "--nodes", suite.RandomDiscoveredNode()},
+		base.StdoutShouldMatch(regexp.MustCompile(`NODE\s+VERSION\s+TIMESTAMP\s+SIZE`)),
+		base.StdoutShouldMatch(regexp.MustCompile(`\s+\d+\s+\S+\s+[\d.]+ [kK]?B`)))
+}
+
+// TestLatest verifies printing the latest config version.
+func (suite *MachineconfigSuite) TestLatest() {
+	suite.RunCLI([]string{"machineconfig", "history", "latest", "--nodes", suite.RandomDiscoveredNode()},
+		base.StdoutShouldMatch(regexp.MustCompile(`version: v1alpha1`)))
+}
+
+// TestDiffSame verifies that diff of the same version is empty.
+func (suite *MachineconfigSuite) TestDiffSame() {
+	suite.RunCLI([]string{"machineconfig", "diff", "latest", "latest", "--nodes", suite.RandomDiscoveredNode()},
+		base.StdoutEmpty())
+}
+
+func init() {
+	allSuites = append(allSuites, new(MachineconfigSuite))
+}
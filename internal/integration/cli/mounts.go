@@ -30,6 +30,12 @@ func (suite *MountsSuite) TestSuccess() {
 		base.StdoutShouldMatch(regexp.MustCompile(`(?s)FILESYSTEM.*`)))
 }
 
+// TestOptions verifies mount options are displayed.
+func (suite *MountsSuite) TestOptions() {
+	suite.RunCLI([]string{"mounts", "--options", "--nodes", suite.RandomDiscoveredNode()},
+		base.StdoutShouldMatch(regexp.MustCompile(`(?s)TYPE.*OPTIONS.*\s/system/state\s+\S*rw`)))
+}
+
 // TestUserDisksMounted verifies user disk mounts created.
 func (suite *MountsSuite) TestUserDisksMounted() {
 	paths := os.Getenv("USER_DISKS_MOUNTS")
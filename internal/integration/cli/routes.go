@@ -30,6 +30,15 @@ func (suite *RoutesSuite) TestSuccess() {
 	)
 }
 
+// TestStructuredOutput verifies routes output in YAML format.
+func (suite *RoutesSuite) TestStructuredOutput() {
+	suite.RunCLI([]string{"routes", "--nodes", suite.RandomDiscoveredNode(), "-o", "yaml"},
+		base.StdoutShouldMatch(regexp.MustCompile(`(?m)^node: `)),
+		base.StdoutShouldMatch(regexp.MustCompile(`destination: 127\.0\.0\.0/8`)),
+		base.StdoutShouldNotMatch(regexp.MustCompile(`GATEWAY`)),
+	)
+}
+
 func init() {
 	allSuites = append(allSuites, new(RoutesSuite))
 }
@@ -7,6 +7,8 @@
 package cli
 
 import (
+	"encoding/json"
+	"fmt"
 	"regexp"
 
 	"github.com/talos-systems/talos/internal/integration/base"
@@ -45,6 +47,49 @@ func (suite *VersionSuite) TestClient() {
 	)
 }
 
+// TestStructuredOutput verifies version output in JSON format.
+func (suite *VersionSuite) TestStructuredOutput() {
+	node := suite.RandomDiscoveredNode()
+
+	suite.RunCLI([]string{"version", "--nodes", node, "-o", "json"},
+		base.StdoutMatchFunc(func(stdout string) error {
+			var version struct {
+				Client struct {
+					Tag string `json:"tag"`
+				} `json:"client"`
+				Servers []struct {
+					Node    string `json:"node"`
+					Version struct {
+						Tag string `json:"tag"`
+					} `json:"version"`
+				} `json:"servers"`
+			}
+
+			if err := json.Unmarshal([]byte(stdout), &version); err != nil {
+				return err
+			}
+
+			if version.Client.Tag != suite.Version {
+				return fmt.Errorf("unexpected client version %q", version.Client.Tag)
+			}
+
+			if len(version.Servers) != 1 {
+				return fmt.Errorf("expected 1 server, got %d", len(version.Servers))
+			}
+
+			if version.Servers[0].Node != node {
+				return fmt.Errorf("unexpected node %q", version.Servers[0].Node)
+			}
+
+			if version.Servers[0].Version.Tag != suite.Version {
+				return fmt.Errorf("unexpected server version %q", version.Servers[0].Version.Tag)
+			}
+
+			return nil
+		}),
+	)
+}
+
 func init() {
 	allSuites = append(allSuites, new(VersionSuite))
 }
@@ -0,0 +1,276 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package audit implements the tamper-evident log of the mutating Talos API calls.
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// Default is the audit log on the STATE partition.
+var Default = &Log{
+	Path:    constants.AuditLogPath,
+	MaxSize: constants.AuditLogMaxSize,
+}
+
+// Entry is a record of the API call in the Log.
+//
+// Entries are chained: every entry includes the hash of the previous entry, so that
+// modification or removal of the entry in the middle of the log breaks the chain.
+type Entry struct {
+	Sequence      uint64    `json:"seq"`
+	Time          time.Time `json:"time"`
+	Subject       string    `json:"subject,omitempty"`
+	Fingerprint   string    `json:"fingerprint,omitempty"`
+	Roles         []string  `json:"roles,omitempty"`
+	Method        string    `json:"method"`
+	RequestDigest string    `json:"request_digest"`
+	Code          string    `json:"code"`
+	Error         string    `json:"error,omitempty"`
+	PreviousHash  string    `json:"prev,omitempty"`
+	Hash          string    `json:"hash,omitempty"`
+}
+
+// ComputeHash returns the hash of the entry contents (all fields except for the Hash).
+func (e Entry) ComputeHash() (string, error) {
+	e.Hash = ""
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Proto converts the entry to the API representation.
+func (e Entry) Proto() *machine.AuditEvent {
+	timestamp, _ := ptypes.TimestampProto(e.Time) //nolint: errcheck
+
+	return &machine.AuditEvent{
+		Sequence:      e.Sequence,
+		Timestamp:     timestamp,
+		Subject:       e.Subject,
+		Fingerprint:   e.Fingerprint,
+		Roles:         e.Roles,
+		Method:        e.Method,
+		RequestDigest: e.RequestDigest,
+		Code:          e.Code,
+		Error:         e.Error,
+		PreviousHash:  e.PreviousHash,
+		Hash:          e.Hash,
+	}
+}
+
+// Log stores the entries as JSON lines in the file.
+//
+// When the file grows over MaxSize, it is rotated to the file with the `.1` suffix,
+// the chain continues in the new file.
+type Log struct {
+	Path    string
+	MaxSize int64
+
+	mu     sync.Mutex
+	loaded bool
+	last   Entry
+}
+
+// Append records the entry as the latest entry of the log.
+//
+// Sequence, PreviousHash and Hash of the entry are filled in by the log.
+func (l *Log) Append(e *Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.loaded {
+		if err := l.load(); err != nil {
+			return err
+		}
+	}
+
+	if err := l.rotate(); err != nil {
+		return err
+	}
+
+	e.Sequence = l.last.Sequence + 1
+	e.PreviousHash = l.last.Hash
+
+	var err error
+
+	if e.Hash, err = e.ComputeHash(); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(l.Path), 0o700); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(l.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer f.Close()
+
+	if _, err = f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	if err = f.Sync(); err != nil {
+		return err
+	}
+
+	l.last = *e
+
+	return nil
+}
+
+// Read returns the stored entries, oldest first.
+func (l *Log) Read() ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var entries []Entry
+
+	for _, path := range []string{l.rotatedPath(), l.Path} {
+		fileEntries, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, fileEntries...)
+	}
+
+	return entries, nil
+}
+
+// Verify checks the hash chain of the entries.
+//
+// The oldest entry might point to the entry removed by the rotation, so its previous hash is not checked.
+func Verify(entries []Entry) error {
+	for i, e := range entries {
+		hash, err := e.ComputeHash()
+		if err != nil {
+			return err
+		}
+
+		if hash != e.Hash {
+			return fmt.Errorf("entry %d: hash mismatch", e.Sequence)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		prev := entries[i-1]
+
+		if e.Sequence != prev.Sequence+1 {
+			return fmt.Errorf("entry %d: sequence gap after entry %d", e.Sequence, prev.Sequence)
+		}
+
+		if e.PreviousHash != prev.Hash {
+			return fmt.Errorf("entry %d: previous hash mismatch", e.Sequence)
+		}
+	}
+
+	return nil
+}
+
+func (l *Log) rotatedPath() string {
+	return l.Path + ".1"
+}
+
+// load finds the latest stored entry to continue the chain.
+func (l *Log) load() error {
+	for _, path := range []string{l.Path, l.rotatedPath()} {
+		entries, err := readFile(path)
+		if err != nil {
+			return err
+		}
+
+		if len(entries) > 0 {
+			l.last = entries[len(entries)-1]
+
+			break
+		}
+	}
+
+	l.loaded = true
+
+	return nil
+}
+
+func (l *Log) rotate() error {
+	if l.MaxSize <= 0 {
+		return nil
+	}
+
+	st, err := os.Stat(l.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if st.Size() < l.MaxSize {
+		return nil
+	}
+
+	return os.Rename(l.Path, l.rotatedPath())
+}
+
+func readFile(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	//nolint: errcheck
+	defer f.Close()
+
+	var entries []Entry
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		var e Entry
+
+		if err = json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("error parsing %q line %d: %w", path, line, err)
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, scanner.Err()
+}
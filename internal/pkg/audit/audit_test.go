@@ -0,0 +1,167 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package audit_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/talos-systems/talos/internal/pkg/audit"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+)
+
+func appendEntries(t *testing.T, l *audit.Log, methods ...string) {
+	for _, method := range methods {
+		require.NoError(t, l.Append(&audit.Entry{
+			Time:   time.Now().UTC(),
+			Method: method,
+			Code:   codes.OK.String(),
+		}))
+	}
+}
+
+func TestLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	path := filepath.Join(dir, "audit.log")
+
+	l := &audit.Log{Path: path}
+
+	appendEntries(t, l, "/machine.MachineService/Reboot", "/machine.MachineService/Upgrade")
+
+	// the chain is continued after the restart
+	l = &audit.Log{Path: path}
+
+	appendEntries(t, l, "/machine.MachineService/Reset")
+
+	entries, err := l.Read()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	assert.NoError(t, audit.Verify(entries))
+
+	for i, e := range entries {
+		assert.EqualValues(t, i+1, e.Sequence)
+	}
+
+	assert.Empty(t, entries[0].PreviousHash)
+	assert.Equal(t, entries[1].Hash, entries[2].PreviousHash)
+	assert.Equal(t, "/machine.MachineService/Upgrade", entries[1].Method)
+
+	// modified entry breaks the chain
+	contents, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(strings.Replace(string(contents), "Upgrade", "Version", 1)), 0o600))
+
+	entries, err = l.Read()
+	require.NoError(t, err)
+
+	assert.EqualError(t, audit.Verify(entries), "entry 2: hash mismatch")
+
+	// removed entry breaks the chain
+	lines := strings.SplitAfter(string(contents), "\n")
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(lines[0]+lines[2]), 0o600))
+
+	entries, err = l.Read()
+	require.NoError(t, err)
+
+	assert.EqualError(t, audit.Verify(entries), "entry 3: sequence gap after entry 1")
+}
+
+func TestLogRotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	path := filepath.Join(dir, "audit.log")
+
+	l := &audit.Log{Path: path, MaxSize: 1}
+
+	appendEntries(t, l, "/machine.MachineService/Reboot", "/machine.MachineService/Upgrade", "/machine.MachineService/Reset")
+
+	// only one rotated file is kept
+	entries, err := l.Read()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.EqualValues(t, 2, entries[0].Sequence)
+	assert.NotEmpty(t, entries[0].PreviousHash)
+	assert.NoError(t, audit.Verify(entries))
+
+	_, err = os.Stat(path + ".1")
+	assert.NoError(t, err)
+}
+
+type publisher []proto.Message
+
+func (p *publisher) Publish(msg proto.Message) {
+	*p = append(*p, msg)
+}
+
+func TestRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	var events publisher
+
+	recorder := &audit.Recorder{
+		Log:    &audit.Log{Path: filepath.Join(dir, "audit.log")},
+		Events: &events,
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"talos-subject", "alice",
+		"talos-fingerprint", "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=",
+		"talos-role", "os:admin",
+	))
+
+	interceptor := recorder.UnaryInterceptor()
+
+	for _, method := range []string{"/machine.MachineService/Version", "/machine.MachineService/Reboot"} {
+		_, err = interceptor(ctx, &machine.RebootRequest{}, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, status.Error(codes.FailedPrecondition, "not now")
+			})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	}
+
+	entries, err := recorder.Log.Read()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	e := entries[0]
+
+	assert.Equal(t, "alice", e.Subject)
+	assert.Equal(t, "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=", e.Fingerprint)
+	assert.Equal(t, []string{"os:admin"}, e.Roles)
+	assert.Equal(t, "/machine.MachineService/Reboot", e.Method)
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", e.RequestDigest) // empty request
+	assert.Equal(t, "FailedPrecondition", e.Code)
+	assert.Equal(t, "not now", e.Error)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, e.Hash, events[0].(*machine.AuditEvent).Hash)
+}
@@ -0,0 +1,185 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/talos-systems/talos/pkg/grpc/middleware/authz"
+)
+
+// readOnlyMethods are not recorded in the audit log.
+//
+// Methods are audited unless listed here, so that the new methods are audited by default.
+var readOnlyMethods = map[string]struct{}{
+	"/cluster.ClusterService/HealthCheck":           {},
+	"/machine.MachineService/Attest":                {},
+	"/machine.MachineService/AuditLog":              {},
+	"/machine.MachineService/BMCInfo":               {},
+	"/machine.MachineService/Certificates":          {},
+	"/machine.MachineService/ClusterMembers":        {},
+	"/machine.MachineService/ConfigHistory":         {},
+	"/machine.MachineService/Containers":            {},
+	"/machine.MachineService/Copy":                  {},
+	"/machine.MachineService/CPUInfo":               {},
+	"/machine.MachineService/DiskStats":             {},
+	"/machine.MachineService/DiskUsage":             {},
+	"/machine.MachineService/Dmesg":                 {},
+	"/machine.MachineService/EtcdMaintenanceStatus": {},
+	"/machine.MachineService/EtcdMemberList":        {},
+	"/machine.MachineService/Events":                {},
+	"/machine.MachineService/GenerateConfiguration": {},
+	"/machine.MachineService/HardwareInventory":     {},
+	"/machine.MachineService/Hostname":              {},
+	"/machine.MachineService/ImageList":             {},
+	"/machine.MachineService/Kubeconfig":            {},
+	"/machine.MachineService/List":                  {},
+	"/machine.MachineService/LoadAvg":               {},
+	"/machine.MachineService/Logs":                  {},
+	"/machine.MachineService/MachineConfigRead":     {},
+	"/machine.MachineService/Manifests":             {},
+	"/machine.MachineService/Memory":                {},
+	"/machine.MachineService/Mounts":                {},
+	"/machine.MachineService/NetworkDeviceStats":    {},
+	"/machine.MachineService/PendingActions":        {},
+	"/machine.MachineService/Processes":             {},
+	"/machine.MachineService/Read":                  {},
+	"/machine.MachineService/ServiceList":           {},
+	"/machine.MachineService/Stats":                 {},
+	"/machine.MachineService/SystemInfo":            {},
+	"/machine.MachineService/SystemStat":            {},
+	"/machine.MachineService/Version":               {},
+	"/storage.StorageService/Disks":                 {},
+	"/storage.StorageService/Mounts":                {},
+	"/storage.StorageService/Partitions":            {},
+	"/storage.StorageService/Watch":                 {},
+}
+
+// IsMutating checks whether the method is recorded in the audit log.
+func IsMutating(fullMethod string) bool {
+	_, readOnly := readOnlyMethods[fullMethod]
+
+	return !readOnly
+}
+
+// Publisher publishes the recorded entries to the events stream.
+type Publisher interface {
+	Publish(proto.Message)
+}
+
+// Recorder records the mutating API calls in the Log and publishes them as events.
+//
+// Client identity is taken from the metadata, so the Recorder should be used only behind apid.
+type Recorder struct {
+	Log    *Log
+	Events Publisher
+}
+
+// UnaryInterceptor returns grpc UnaryServerInterceptor.
+func (r *Recorder) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !IsMutating(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		digest := sha256.New()
+		writeMessage(digest, req)
+
+		resp, err := handler(ctx, req)
+
+		r.record(ctx, info.FullMethod, digest, err)
+
+		return resp, err
+	}
+}
+
+// StreamInterceptor returns grpc StreamServerInterceptor.
+func (r *Recorder) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !IsMutating(info.FullMethod) {
+			return handler(srv, stream)
+		}
+
+		wrapped := &serverStream{
+			ServerStream: stream,
+			digest:       sha256.New(),
+		}
+
+		err := handler(srv, wrapped)
+
+		r.record(stream.Context(), info.FullMethod, wrapped.digest, err)
+
+		return err
+	}
+}
+
+func (r *Recorder) record(ctx context.Context, fullMethod string, digest hash.Hash, callErr error) {
+	identity := authz.IdentityFromMetadata(ctx)
+
+	st, _ := status.FromError(callErr)
+
+	entry := Entry{
+		Time:          time.Now().UTC(),
+		Subject:       identity.Subject,
+		Fingerprint:   identity.Fingerprint,
+		Roles:         identity.Roles,
+		Method:        fullMethod,
+		RequestDigest: hex.EncodeToString(digest.Sum(nil)),
+		Code:          st.Code().String(),
+	}
+
+	if callErr != nil {
+		entry.Error = st.Message()
+	}
+
+	// audit failure doesn't fail the call, as the call is already done
+	if err := r.Log.Append(&entry); err != nil {
+		log.Printf("error recording audit log entry for %s: %s", fullMethod, err)
+	}
+
+	if r.Events != nil {
+		r.Events.Publish(entry.Proto())
+	}
+}
+
+// serverStream computes the digest of the received messages.
+type serverStream struct {
+	grpc.ServerStream
+
+	digest hash.Hash
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	writeMessage(s.digest, m)
+
+	return nil
+}
+
+func writeMessage(h hash.Hash, m interface{}) {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return
+	}
+
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return
+	}
+
+	h.Write(b) //nolint: errcheck
+}
@@ -0,0 +1,180 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package bmc provides access to the BMC LAN configuration via the in-band IPMI interface (/dev/ipmi0).
+package bmc
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/u-root/u-root/pkg/ipmi"
+)
+
+const (
+	netFnTransport ipmi.NetFn = 0x0c
+
+	cmdSetLANConfig ipmi.Command = 0x01
+	cmdGetLANConfig ipmi.Command = 0x02
+)
+
+// LAN configuration parameters.
+const (
+	paramIPAddress       = 3
+	paramIPAddressSource = 4
+	paramMACAddress      = 5
+	paramSubnetMask      = 6
+	paramDefaultGateway  = 12
+)
+
+// IP address sources.
+const (
+	sourceUnspecified = 0
+	sourceStatic      = 1
+	sourceDHCP        = 2
+	sourceBIOS        = 3
+)
+
+// Transport sends IPMI requests to the BMC.
+//
+// Response includes the completion code as the first byte.
+type Transport interface {
+	SendRecv(netfn ipmi.NetFn, cmd ipmi.Command, data []byte) ([]byte, error)
+}
+
+// Open the IPMI device.
+func Open() (*ipmi.IPMI, error) {
+	return ipmi.Open(0)
+}
+
+// LANConfig is the network configuration of the BMC LAN channel.
+type LANConfig struct {
+	Channel uint8
+	// IPSource is one of static, dhcp, bios or unspecified.
+	IPSource string
+	Address  net.IP
+	Netmask  net.IPMask
+	Gateway  net.IP
+	MAC      net.HardwareAddr
+}
+
+// GetLANConfig reads the network configuration of the LAN channel.
+func GetLANConfig(t Transport, channel uint8) (*LANConfig, error) {
+	config := &LANConfig{
+		Channel: channel,
+	}
+
+	source, err := getParam(t, channel, paramIPAddressSource, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	switch source[0] & 0x0f {
+	case sourceUnspecified:
+		config.IPSource = "unspecified"
+	case sourceStatic:
+		config.IPSource = "static"
+	case sourceDHCP:
+		config.IPSource = "dhcp"
+	case sourceBIOS:
+		config.IPSource = "bios"
+	default:
+		config.IPSource = "other"
+	}
+
+	address, err := getParam(t, channel, paramIPAddress, net.IPv4len)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Address = net.IP(address)
+
+	netmask, err := getParam(t, channel, paramSubnetMask, net.IPv4len)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Netmask = net.IPMask(netmask)
+
+	gateway, err := getParam(t, channel, paramDefaultGateway, net.IPv4len)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Gateway = net.IP(gateway)
+
+	mac, err := getParam(t, channel, paramMACAddress, 6)
+	if err != nil {
+		return nil, err
+	}
+
+	config.MAC = net.HardwareAddr(mac)
+
+	return config, nil
+}
+
+// SetDHCP switches the LAN channel to DHCP.
+func SetDHCP(t Transport, channel uint8) error {
+	return setParam(t, channel, paramIPAddressSource, []byte{sourceDHCP})
+}
+
+// SetStatic configures the static address and the default gateway of the LAN channel.
+//
+// Gateway is optional.
+func SetStatic(t Transport, channel uint8, address *net.IPNet, gateway net.IP) error {
+	ip := address.IP.To4()
+	if ip == nil {
+		return fmt.Errorf("BMC address %s is not an IPv4 address", address)
+	}
+
+	if len(address.Mask) != net.IPv4len {
+		return fmt.Errorf("BMC address %s has invalid netmask", address)
+	}
+
+	if err := setParam(t, channel, paramIPAddressSource, []byte{sourceStatic}); err != nil {
+		return err
+	}
+
+	if err := setParam(t, channel, paramIPAddress, ip); err != nil {
+		return err
+	}
+
+	if err := setParam(t, channel, paramSubnetMask, address.Mask); err != nil {
+		return err
+	}
+
+	if gateway == nil {
+		return nil
+	}
+
+	if gateway.To4() == nil {
+		return fmt.Errorf("BMC gateway %s is not an IPv4 address", gateway)
+	}
+
+	return setParam(t, channel, paramDefaultGateway, gateway.To4())
+}
+
+// getParam reads the LAN configuration parameter.
+//
+// Response is: completion code, parameter revision, parameter data.
+func getParam(t Transport, channel, param uint8, size int) ([]byte, error) {
+	resp, err := t.SendRecv(netFnTransport, cmdGetLANConfig, []byte{channel, param, 0, 0})
+	if err != nil {
+		return nil, fmt.Errorf("error reading BMC LAN parameter %d: %w", param, err)
+	}
+
+	if len(resp) < 2+size {
+		return nil, fmt.Errorf("short response reading BMC LAN parameter %d: %d bytes", param, len(resp))
+	}
+
+	return resp[2 : 2+size], nil
+}
+
+func setParam(t Transport, channel, param uint8, data []byte) error {
+	if _, err := t.SendRecv(netFnTransport, cmdSetLANConfig, append([]byte{channel, param}, data...)); err != nil {
+		return fmt.Errorf("error setting BMC LAN parameter %d: %w", param, err)
+	}
+
+	return nil
+}
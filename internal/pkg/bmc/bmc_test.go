@@ -0,0 +1,87 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package bmc_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/u-root/u-root/pkg/ipmi"
+
+	"github.com/talos-systems/talos/internal/pkg/bmc"
+)
+
+// mockTransport keeps LAN parameters of a single channel.
+type mockTransport struct {
+	params map[byte][]byte
+}
+
+func (m *mockTransport) SendRecv(netfn ipmi.NetFn, cmd ipmi.Command, data []byte) ([]byte, error) {
+	switch cmd {
+	case 0x01:
+		m.params[data[1]] = append([]byte(nil), data[2:]...)
+
+		return []byte{0}, nil
+	case 0x02:
+		return append([]byte{0, 0x11}, m.params[data[1]]...), nil
+	}
+
+	return []byte{0xc1}, nil
+}
+
+func TestLANConfig(t *testing.T) {
+	transport := &mockTransport{
+		params: map[byte][]byte{
+			3:  {10, 0, 0, 5},
+			4:  {2},
+			5:  {0x52, 0x54, 0x00, 0x12, 0x34, 0x56},
+			6:  {255, 255, 255, 0},
+			12: {10, 0, 0, 1},
+		},
+	}
+
+	config, err := bmc.GetLANConfig(transport, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, "dhcp", config.IPSource)
+	assert.Equal(t, "10.0.0.5", config.Address.String())
+	assert.Equal(t, "ffffff00", config.Netmask.String())
+	assert.Equal(t, "10.0.0.1", config.Gateway.String())
+	assert.Equal(t, "52:54:00:12:34:56", config.MAC.String())
+
+	_, address, err := net.ParseCIDR("192.168.1.0/24")
+	require.NoError(t, err)
+
+	address.IP = net.ParseIP("192.168.1.10")
+
+	require.NoError(t, bmc.SetStatic(transport, 1, address, net.ParseIP("192.168.1.1")))
+
+	config, err = bmc.GetLANConfig(transport, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, "static", config.IPSource)
+	assert.Equal(t, "192.168.1.10", config.Address.String())
+	assert.Equal(t, "ffffff00", config.Netmask.String())
+	assert.Equal(t, "192.168.1.1", config.Gateway.String())
+
+	require.NoError(t, bmc.SetDHCP(transport, 1))
+
+	config, err = bmc.GetLANConfig(transport, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, "dhcp", config.IPSource)
+
+	_, address, err = net.ParseCIDR("fd00::1/64")
+	require.NoError(t, err)
+
+	assert.Error(t, bmc.SetStatic(transport, 1, address, nil))
+
+	delete(transport.params, 5)
+
+	_, err = bmc.GetLANConfig(transport, 1)
+	assert.Error(t, err)
+}
@@ -422,3 +422,40 @@ func (suite *CircularSuite) TestRegularSeek() {
 func TestCircularSuite(t *testing.T) {
 	suite.Run(t, new(CircularSuite))
 }
+
+func BenchmarkWrite(b *testing.B) {
+	buf, err := circular.NewBuffer(circular.WithInitialCapacity(16384), circular.WithMaxCapacity(1048576), circular.WithSafetyGap(2048))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	line := []byte("2021/01/01 00:00:00 machined: some log line\n")
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(line)))
+
+	for i := 0; i < b.N; i++ {
+		buf.Write(line) //nolint: errcheck
+	}
+}
+
+func BenchmarkRead(b *testing.B) {
+	buf, err := circular.NewBuffer(circular.WithInitialCapacity(16384), circular.WithMaxCapacity(1048576), circular.WithSafetyGap(2048))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	line := []byte("2021/01/01 00:00:00 machined: some log line\n")
+
+	for buf.Offset() < 2*1048576 {
+		buf.Write(line) //nolint: errcheck
+	}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err = io.Copy(ioutil.Discard, buf.GetReader()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package configdiff compares machine configurations.
+package configdiff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+)
+
+// Operation is the kind of the change.
+type Operation string
+
+// Change operations.
+const (
+	Added   Operation = "added"
+	Removed Operation = "removed"
+	Changed Operation = "changed"
+)
+
+// Change describes a change of a single configuration field.
+type Change struct {
+	// Path is the dot-separated path to the field, list items are referenced as `field[index]`.
+	Path      string
+	Operation Operation
+}
+
+// Result is the difference between two configurations.
+type Result struct {
+	// Changes are sorted by the path.
+	Changes []Change
+	// Diff is the unified diff of the configurations.
+	Diff string
+}
+
+// Diff compares the encoded machine configurations.
+func Diff(from, to []byte) (Result, error) {
+	var fromTree, toTree interface{}
+
+	if err := yaml.Unmarshal(from, &fromTree); err != nil {
+		return Result{}, fmt.Errorf("error decoding current config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(to, &toTree); err != nil {
+		return Result{}, fmt.Errorf("error decoding new config: %w", err)
+	}
+
+	var result Result
+
+	compare("", fromTree, toTree, &result.Changes)
+
+	sort.SliceStable(result.Changes, func(i, j int) bool {
+		return result.Changes[i].Path < result.Changes[j].Path
+	})
+
+	if len(result.Changes) == 0 {
+		return result, nil
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(from)),
+		B:        difflib.SplitLines(string(to)),
+		FromFile: "current",
+		ToFile:   "new",
+		Context:  3,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	result.Diff = diff
+
+	return result, nil
+}
+
+func compare(path string, from, to interface{}, changes *[]Change) {
+	switch {
+	case from == nil && to == nil:
+		return
+	case from == nil:
+		*changes = append(*changes, Change{Path: path, Operation: Added})
+
+		return
+	case to == nil:
+		*changes = append(*changes, Change{Path: path, Operation: Removed})
+
+		return
+	}
+
+	fromMap, fromIsMap := from.(map[string]interface{})
+	toMap, toIsMap := to.(map[string]interface{})
+
+	if fromIsMap && toIsMap {
+		keys := map[string]struct{}{}
+
+		for key := range fromMap {
+			keys[key] = struct{}{}
+		}
+
+		for key := range toMap {
+			keys[key] = struct{}{}
+		}
+
+		for key := range keys {
+			compare(join(path, key), fromMap[key], toMap[key], changes)
+		}
+
+		return
+	}
+
+	fromList, fromIsList := from.([]interface{})
+	toList, toIsList := to.([]interface{})
+
+	if fromIsList && toIsList {
+		for i := 0; i < len(fromList) || i < len(toList); i++ {
+			var fromItem, toItem interface{}
+
+			if i < len(fromList) {
+				fromItem = fromList[i]
+			}
+
+			if i < len(toList) {
+				toItem = toList[i]
+			}
+
+			compare(fmt.Sprintf("%s[%d]", path, i), fromItem, toItem, changes)
+		}
+
+		return
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, Change{Path: path, Operation: Changed})
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return strings.Join([]string{path, key}, ".")
+}
@@ -0,0 +1,68 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package configdiff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/configdiff"
+)
+
+const currentConfig = `version: v1alpha1
+machine:
+  type: join
+  certSANs:
+    - 10.5.0.2
+  network:
+    hostname: talos-1
+`
+
+func TestDiff(t *testing.T) {
+	result, err := configdiff.Diff([]byte(currentConfig), []byte(currentConfig))
+	require.NoError(t, err)
+	assert.Empty(t, result.Changes)
+	assert.Empty(t, result.Diff)
+
+	result, err = configdiff.Diff([]byte(currentConfig), []byte(`version: v1alpha1
+machine:
+  type: join
+  certSANs:
+    - 10.5.0.2
+    - 10.5.0.3
+  network:
+    nameservers:
+      - 1.1.1.1
+cluster:
+  clusterName: talos
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []configdiff.Change{
+		{Path: "cluster", Operation: configdiff.Added},
+		{Path: "machine.certSANs[1]", Operation: configdiff.Added},
+		{Path: "machine.network.hostname", Operation: configdiff.Removed},
+		{Path: "machine.network.nameservers", Operation: configdiff.Added},
+	}, result.Changes)
+
+	assert.Contains(t, result.Diff, "-    hostname: talos-1\n")
+	assert.Contains(t, result.Diff, "+    - 10.5.0.3\n")
+
+	result, err = configdiff.Diff([]byte(currentConfig), []byte(`version: v1alpha1
+machine:
+  type: controlplane
+  certSANs:
+    - 10.5.0.2
+  network:
+    hostname: talos-1
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []configdiff.Change{
+		{Path: "machine.type", Operation: configdiff.Changed},
+	}, result.Changes)
+}
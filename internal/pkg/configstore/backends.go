@@ -0,0 +1,166 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package configstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader/adv"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/platform/metal"
+	"github.com/talos-systems/talos/pkg/download"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// State stores the machine configuration as a file on the STATE partition.
+type State struct {
+	Path string
+}
+
+// Name implements the Backend interface.
+func (s *State) Name() string {
+	return constants.ConfigStorageBackendState
+}
+
+// Load implements the Backend interface.
+func (s *State) Load(ctx context.Context) ([]byte, error) {
+	return ioutil.ReadFile(s.Path)
+}
+
+// Store implements the Backend interface.
+//
+// Configuration is replaced atomically, so that a crash during the write doesn't leave the machine without the configuration.
+func (s *State) Store(ctx context.Context, b []byte) error {
+	return writeFileAtomic(s.Path, b, 0o600)
+}
+
+// Delete implements the Backend interface.
+func (s *State) Delete(ctx context.Context) error {
+	if err := os.Remove(s.Path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Meta stores the machine configuration in the ADV on the META partition.
+type Meta struct{}
+
+// Name implements the Backend interface.
+func (m *Meta) Name() string {
+	return constants.ConfigStorageBackendMeta
+}
+
+// Load implements the Backend interface.
+//
+// Missing META partition (e.g. in container mode or before the installation) is reported as no stored configuration.
+func (m *Meta) Load(ctx context.Context) ([]byte, error) {
+	meta, err := bootloader.NewMeta()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error reading meta: %s", os.ErrNotExist, err)
+	}
+
+	// nolint: errcheck
+	defer meta.Close()
+
+	val, ok := meta.ADV.ReadTag(adv.MachineConfig)
+	if !ok {
+		return nil, fmt.Errorf("%w: no machine config in meta", os.ErrNotExist)
+	}
+
+	return []byte(val), nil
+}
+
+// Store implements the Backend interface.
+func (m *Meta) Store(ctx context.Context, b []byte) error {
+	meta, err := bootloader.NewMeta()
+	if err != nil {
+		return fmt.Errorf("error reading meta: %w", err)
+	}
+
+	// nolint: errcheck
+	defer meta.Close()
+
+	if !meta.ADV.SetTag(adv.MachineConfig, string(b)) {
+		return fmt.Errorf("machine config is too large to be stored in meta (%d bytes)", len(b))
+	}
+
+	return meta.Write()
+}
+
+// Delete implements the Backend interface.
+func (m *Meta) Delete(ctx context.Context) error {
+	meta, err := bootloader.NewMeta()
+	if err != nil {
+		// nothing to delete
+		return nil
+	}
+
+	// nolint: errcheck
+	defer meta.Close()
+
+	if !meta.ADV.DeleteTag(adv.MachineConfig) {
+		return nil
+	}
+
+	return meta.Write()
+}
+
+// Remote fetches the machine configuration from the remote service.
+//
+// Fetched configuration is stored in the Cache, cached copy is used if the remote service is not available.
+// Remote service is the source of truth: configuration changes stored locally are replaced on the next fetch.
+type Remote struct {
+	URL   string
+	Cache Backend
+}
+
+// Name implements the Backend interface.
+func (r *Remote) Name() string {
+	return constants.ConfigStorageBackendRemote
+}
+
+// Load implements the Backend interface.
+func (r *Remote) Load(ctx context.Context) ([]byte, error) {
+	b, err := r.fetch(ctx)
+	if err != nil {
+		log.Printf("failed to fetch config from the remote service, using cached copy: %s", err)
+
+		return r.Cache.Load(ctx)
+	}
+
+	if err = r.Cache.Store(ctx, b); err != nil {
+		return nil, fmt.Errorf("error caching config: %w", err)
+	}
+
+	return b, nil
+}
+
+func (r *Remote) fetch(ctx context.Context) ([]byte, error) {
+	downloadURL, err := metal.PopulateURLParameters(r.URL, metal.URLVariables)
+	if err != nil {
+		return nil, err
+	}
+
+	return download.Download(ctx, downloadURL,
+		download.WithTimeout(constants.ConfigStorageRemoteTimeout),
+		download.WithErrorOnNotFound(errors.New("config not found on the remote service")),
+	)
+}
+
+// Store implements the Backend interface.
+func (r *Remote) Store(ctx context.Context, b []byte) error {
+	return r.Cache.Store(ctx, b)
+}
+
+// Delete implements the Backend interface.
+func (r *Remote) Delete(ctx context.Context) error {
+	return r.Cache.Delete(ctx)
+}
@@ -0,0 +1,139 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package configstore implements machine configuration storage backends.
+package configstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/config/configloader"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// Backend persists the machine configuration.
+type Backend interface {
+	// Name returns the backend name as used in the machine configuration.
+	Name() string
+	// Load returns the stored machine configuration.
+	//
+	// Error matching os.ErrNotExist is returned if there is no stored configuration.
+	Load(ctx context.Context) ([]byte, error)
+	// Store replaces the stored machine configuration.
+	Store(ctx context.Context, b []byte) error
+	// Delete removes the stored machine configuration, if any.
+	Delete(ctx context.Context) error
+}
+
+// Storage loads and stores the machine configuration using the backend selected in the configuration.
+//
+// Every stored configuration is recorded in the History, if it is set.
+type Storage struct {
+	State   Backend
+	Meta    Backend
+	History *History
+}
+
+// Default is the storage for the machine configuration on the system disk.
+var Default = &Storage{
+	State: &State{Path: constants.ConfigPath},
+	Meta:  &Meta{},
+	History: &History{
+		Path:        constants.ConfigHistoryPath,
+		MaxVersions: constants.ConfigHistoryMaxVersions,
+	},
+}
+
+// Load finds the stored machine configuration.
+//
+// META partition is checked first, as the configuration found on the STATE partition might be a stale copy
+// left before switching the backend. If the configuration selects the `remote` backend, it is refreshed
+// from the remote service.
+func (s *Storage) Load(ctx context.Context) (config.Provider, error) {
+	b, err := s.Meta.Load(ctx)
+	if err == nil {
+		return configloader.NewFromBytes(b)
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	b, err = s.State.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := configloader.NewFromBytes(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Machine().Features().ConfigStorage().Backend() != constants.ConfigStorageBackendRemote {
+		return cfg, nil
+	}
+
+	b, err = s.Backend(cfg).Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return configloader.NewFromBytes(b)
+}
+
+// Store persists the machine configuration using the backend selected in the configuration.
+//
+// Configuration stored by the other backends is removed, so that Load doesn't pick it up.
+func (s *Storage) Store(ctx context.Context, cfg config.Provider) error {
+	b, err := cfg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	backend := s.Backend(cfg)
+
+	if err = backend.Store(ctx, b); err != nil {
+		return fmt.Errorf("error storing config using %q backend: %w", backend.Name(), err)
+	}
+
+	stale := s.Meta
+	if backend == s.Meta {
+		stale = s.State
+	}
+
+	if err = stale.Delete(ctx); err != nil {
+		return fmt.Errorf("error removing config from %q backend: %w", stale.Name(), err)
+	}
+
+	if s.History != nil {
+		// configuration is already stored, so the history failure is not fatal
+		if _, err = s.History.Record(b); err != nil {
+			log.Printf("error recording config history: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// Backend returns the backend selected in the configuration.
+func (s *Storage) Backend(cfg config.Provider) Backend {
+	storage := cfg.Machine().Features().ConfigStorage()
+
+	switch storage.Backend() {
+	case constants.ConfigStorageBackendMeta:
+		return s.Meta
+	case constants.ConfigStorageBackendRemote:
+		return &Remote{
+			URL:   storage.URL(),
+			Cache: s.State,
+		}
+	default:
+		return s.State
+	}
+}
@@ -0,0 +1,124 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package configstore_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/configstore"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+func newConfigFunc(t *testing.T) func(hostname string, storage *v1alpha1.ConfigStorageConfig) *v1alpha1.Config {
+	secrets, err := generate.NewSecretsBundle(generate.NewClock())
+	require.NoError(t, err)
+
+	input, err := generate.NewInput("test", "https://10.5.0.1:6443", constants.DefaultKubernetesVersion, secrets)
+	require.NoError(t, err)
+
+	return func(hostname string, storage *v1alpha1.ConfigStorageConfig) *v1alpha1.Config {
+		cfg, err := generate.Config(machine.TypeJoin, input)
+		require.NoError(t, err)
+
+		cfg.MachineConfig.MachineNetwork = &v1alpha1.NetworkConfig{
+			NetworkHostname: hostname,
+		}
+		cfg.MachineConfig.MachineFeatures = &v1alpha1.FeaturesConfig{
+			FeaturesConfigStorage: storage,
+		}
+
+		return cfg
+	}
+}
+
+func TestStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	ctx := context.Background()
+	newConfig := newConfigFunc(t)
+
+	// META is replaced with a file for the test
+	storage := &configstore.Storage{
+		State:   &configstore.State{Path: filepath.Join(dir, "state.yaml")},
+		Meta:    &configstore.State{Path: filepath.Join(dir, "meta.yaml")},
+		History: &configstore.History{Path: filepath.Join(dir, "history")},
+	}
+
+	_, err = storage.Load(ctx)
+	assert.True(t, os.IsNotExist(err))
+
+	require.NoError(t, storage.Store(ctx, newConfig("state", nil)))
+	assert.FileExists(t, filepath.Join(dir, "state.yaml"))
+
+	cfg, err := storage.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "state", cfg.Machine().Network().Hostname())
+
+	require.NoError(t, storage.Store(ctx, newConfig("meta", &v1alpha1.ConfigStorageConfig{
+		StorageBackend: constants.ConfigStorageBackendMeta,
+	})))
+	assert.NoFileExists(t, filepath.Join(dir, "state.yaml"))
+
+	cfg, err = storage.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "meta", cfg.Machine().Network().Hostname())
+
+	versions, err := storage.History.List()
+	require.NoError(t, err)
+	assert.Len(t, versions, 2)
+
+	remoteAvailable := true
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !remoteAvailable {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		b, err := newConfig("remote", &v1alpha1.ConfigStorageConfig{
+			StorageBackend: constants.ConfigStorageBackendRemote,
+			StorageURL:     fmt.Sprintf("http://%s/config.yaml", r.Host),
+		}).Bytes()
+		require.NoError(t, err)
+
+		w.Write(b) //nolint: errcheck
+	}))
+
+	defer srv.Close()
+
+	require.NoError(t, storage.Store(ctx, newConfig("local", &v1alpha1.ConfigStorageConfig{
+		StorageBackend: constants.ConfigStorageBackendRemote,
+		StorageURL:     srv.URL + "/config.yaml",
+	})))
+	assert.NoFileExists(t, filepath.Join(dir, "meta.yaml"))
+
+	cfg, err = storage.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "remote", cfg.Machine().Network().Hostname())
+
+	// cached copy is used when the remote service is not available
+	remoteAvailable = false
+
+	cfg, err = storage.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "remote", cfg.Machine().Network().Hostname())
+}
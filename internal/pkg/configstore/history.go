@@ -0,0 +1,187 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package configstore
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const historyFileExt = ".yaml"
+
+// Version is a version of the machine configuration recorded in the History.
+type Version struct {
+	// Number increases monotonically with every recorded version.
+	Number    uint64
+	Timestamp time.Time
+}
+
+// History keeps the last versions of the machine configuration on the STATE partition.
+//
+// Every version is stored in a separate file named after the version number, so that
+// the history can be used to diff and roll back the configuration changes.
+type History struct {
+	Path        string
+	MaxVersions int
+}
+
+// List returns the recorded versions, latest version first.
+func (h *History) List() ([]Version, error) {
+	files, err := ioutil.ReadDir(h.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	versions := make([]Version, 0, len(files))
+
+	for _, file := range files {
+		if !file.Mode().IsRegular() || filepath.Ext(file.Name()) != historyFileExt {
+			continue
+		}
+
+		number, err := strconv.ParseUint(strings.TrimSuffix(file.Name(), historyFileExt), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		versions = append(versions, Version{
+			Number:    number,
+			Timestamp: file.ModTime(),
+		})
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Number > versions[j].Number
+	})
+
+	return versions, nil
+}
+
+// Get returns the machine configuration of the version.
+//
+// Error matching os.ErrNotExist is returned if the version is not in the history.
+func (h *History) Get(number uint64) ([]byte, error) {
+	return ioutil.ReadFile(h.path(number))
+}
+
+// Record adds the machine configuration to the history as the latest version.
+//
+// Configuration matching the latest version is not recorded again. Versions exceeding
+// MaxVersions are removed, oldest first.
+func (h *History) Record(b []byte) (Version, error) {
+	versions, err := h.List()
+	if err != nil {
+		return Version{}, err
+	}
+
+	if len(versions) > 0 {
+		var latest []byte
+
+		latest, err = h.Get(versions[0].Number)
+		if err != nil {
+			return Version{}, err
+		}
+
+		if bytes.Equal(latest, b) {
+			return versions[0], nil
+		}
+	}
+
+	if err = os.MkdirAll(h.Path, 0o700); err != nil {
+		return Version{}, err
+	}
+
+	version := Version{
+		Number: 1,
+	}
+
+	if len(versions) > 0 {
+		version.Number = versions[0].Number + 1
+	}
+
+	if err = writeFileAtomic(h.path(version.Number), b, 0o600); err != nil {
+		return Version{}, fmt.Errorf("error recording config version %d: %w", version.Number, err)
+	}
+
+	st, err := os.Stat(h.path(version.Number))
+	if err != nil {
+		return Version{}, err
+	}
+
+	version.Timestamp = st.ModTime()
+
+	versions = append([]Version{version}, versions...)
+
+	if h.MaxVersions > 0 && len(versions) > h.MaxVersions {
+		for _, stale := range versions[h.MaxVersions:] {
+			if err = os.Remove(h.path(stale.Number)); err != nil && !os.IsNotExist(err) {
+				return Version{}, err
+			}
+		}
+	}
+
+	return version, nil
+}
+
+func (h *History) path(number uint64) string {
+	return filepath.Join(h.Path, strconv.FormatUint(number, 10)+historyFileExt)
+}
+
+// writeFileAtomic replaces the file contents, so that either the old or the new contents
+// are found in the file after a crash or power loss.
+func writeFileAtomic(path string, b []byte, perm os.FileMode) error {
+	tmpFile, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmpFile.Name()) //nolint: errcheck
+
+	if _, err = tmpFile.Write(b); err != nil {
+		tmpFile.Close() //nolint: errcheck
+
+		return err
+	}
+
+	if err = tmpFile.Sync(); err != nil {
+		tmpFile.Close() //nolint: errcheck
+
+		return err
+	}
+
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Chmod(tmpFile.Name(), perm); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmpFile.Name(), path); err != nil {
+		return err
+	}
+
+	// sync the directory, so that the rename is persisted
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer dir.Close()
+
+	return dir.Sync()
+}
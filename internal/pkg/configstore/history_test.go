@@ -0,0 +1,66 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package configstore_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/configstore"
+)
+
+func TestHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	history := &configstore.History{
+		Path:        filepath.Join(dir, "history"),
+		MaxVersions: 3,
+	}
+
+	versions, err := history.List()
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+
+	for _, contents := range []string{"a", "b", "b", "c", "d"} {
+		_, err = history.Record([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	versions, err = history.List()
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+
+	numbers := make([]uint64, 0, len(versions))
+
+	for _, version := range versions {
+		numbers = append(numbers, version.Number)
+	}
+
+	// duplicate "b" is not recorded, "a" is pruned
+	assert.Equal(t, []uint64{4, 3, 2}, numbers)
+
+	b, err := history.Get(4)
+	require.NoError(t, err)
+	assert.Equal(t, "d", string(b))
+
+	b, err = history.Get(2)
+	require.NoError(t, err)
+	assert.Equal(t, "b", string(b))
+
+	_, err = history.Get(1)
+	assert.True(t, os.IsNotExist(err))
+
+	version, err := history.Record([]byte("d"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, version.Number)
+}
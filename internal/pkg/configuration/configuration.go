@@ -6,17 +6,17 @@ package configuration
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
 
+	"github.com/talos-systems/talos/internal/pkg/configstore"
 	"github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/config"
-	"github.com/talos-systems/talos/pkg/machinery/config/configloader"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
 	v1alpha1machine "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
-	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
 // Generate config for GenerateConfiguration grpc.
@@ -102,7 +102,7 @@ func Generate(ctx context.Context, in *machine.GenerateConfigurationRequest) (re
 			secrets       *generate.SecretsBundle
 		)
 
-		baseConfig, err = configloader.NewFromFile(constants.ConfigPath)
+		baseConfig, err = configstore.Default.Load(ctx)
 
 		clock := generate.NewClock()
 
@@ -111,7 +111,7 @@ func Generate(ctx context.Context, in *machine.GenerateConfigurationRequest) (re
 		}
 
 		switch {
-		case os.IsNotExist(err):
+		case errors.Is(err, os.ErrNotExist):
 			secrets, err = generate.NewSecretsBundle(clock)
 			if err != nil {
 				return nil, err
@@ -0,0 +1,83 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package console provides rate-limited output to the system console.
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// Writer writes complete lines to the underlying console device.
+//
+// If the rate limit is set, lines over the limit are dropped, and the number
+// of dropped lines is reported once the output is allowed again.
+type Writer struct {
+	mu sync.Mutex
+
+	w       io.Writer
+	limiter *rate.Limiter
+	dropped int
+}
+
+// NewWriter initializes a new Writer which writes at most linesPerSecond lines
+// per second to w.
+//
+// If linesPerSecond is zero, rate limiting is disabled.
+func NewWriter(w io.Writer, linesPerSecond int) *Writer {
+	writer := &Writer{
+		w: w,
+	}
+
+	if linesPerSecond > 0 {
+		writer.limiter = rate.NewLimiter(rate.Limit(linesPerSecond), linesPerSecond)
+	}
+
+	return writer
+}
+
+// Write implements io.Writer interface.
+//
+// Write never fails, as console output should never block or break the caller.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n := len(p)
+
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i == -1 {
+			i = len(p) - 1
+		}
+
+		line := p[:i+1]
+		p = p[i+1:]
+
+		if w.limiter != nil && !w.limiter.Allow() {
+			w.dropped++
+
+			continue
+		}
+
+		if w.dropped > 0 {
+			fmt.Fprintf(w.w, "[talos] %d line(s) suppressed by console rate limit\n", w.dropped) //nolint: errcheck
+
+			w.dropped = 0
+		}
+
+		w.w.Write(line) //nolint: errcheck
+
+		if line[len(line)-1] != '\n' {
+			w.w.Write([]byte{'\n'}) //nolint: errcheck
+		}
+	}
+
+	return n, nil
+}
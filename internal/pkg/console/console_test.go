@@ -0,0 +1,47 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package console_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/talos-systems/talos/internal/pkg/console"
+)
+
+func TestWriterUnlimited(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := console.NewWriter(&buf, 0)
+
+	n, err := w.Write([]byte("foo\nbar\nbaz"))
+	assert.NoError(t, err)
+	assert.Equal(t, 11, n)
+
+	assert.Equal(t, "foo\nbar\nbaz\n", buf.String())
+}
+
+func TestWriterRateLimit(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := console.NewWriter(&buf, 2)
+
+	for i := 0; i < 5; i++ {
+		_, err := w.Write([]byte("line\n"))
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, "line\nline\n", buf.String())
+
+	time.Sleep(time.Second)
+
+	_, err := w.Write([]byte("last\n"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "line\nline\n[talos] 3 line(s) suppressed by console rate limit\nlast\n", buf.String())
+}
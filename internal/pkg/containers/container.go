@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/talos-systems/talos/pkg/chunker"
 	"github.com/talos-systems/talos/pkg/chunker/file"
@@ -35,7 +36,18 @@ type Container struct {
 	LogPath      string
 	Metrics      *ContainerMetrics
 	Pid          uint32
-	IsPodSandbox bool // real container or just pod sandbox
+	IsPodSandbox bool   // real container or just pod sandbox
+	PodSandboxID string // ID of the CRI pod sandbox the container belongs to
+	CreatedAt    time.Time
+	Env          []string
+	Mounts       []ContainerMount
+}
+
+// ContainerMount represents a container mount.
+type ContainerMount struct {
+	Source      string
+	Destination string
+	Type        string
 }
 
 // ContainerMetrics represents container cgroup stats.
@@ -144,6 +144,19 @@ func (i *inspector) containerInfo(cntr containerd.Container, imageList map[strin
 
 	cp.Pid = task.Pid()
 	cp.Status = strings.ToUpper(string(status.Status))
+	cp.CreatedAt = info.CreatedAt
+
+	if spec.Process != nil {
+		cp.Env = spec.Process.Env
+	}
+
+	for _, mount := range spec.Mounts {
+		cp.Mounts = append(cp.Mounts, ctrs.ContainerMount{
+			Source:      mount.Source,
+			Destination: mount.Destination,
+			Type:        mount.Type,
+		})
+	}
 
 	var (
 		cname, cns string
@@ -202,6 +215,7 @@ func (i *inspector) containerInfo(cntr containerd.Container, imageList map[strin
 	if _, ok := spec.Annotations["io.kubernetes.cri.sandbox-log-directory"]; ok {
 		cp.Sandbox = spec.Annotations["io.kubernetes.cri.sandbox-log-directory"]
 		cp.IsPodSandbox = true
+		cp.PodSandboxID = cp.ID
 	} else if singleLookup && cns != "" && cname != "" {
 		// try to find matching infrastructure container and pull sandbox from it
 		query := fmt.Sprintf("labels.\"io.kubernetes.pod.namespace\"==%q,labels.\"io.kubernetes.pod.name\"==%q", cns, cname)
@@ -224,6 +238,10 @@ func (i *inspector) containerInfo(cntr containerd.Container, imageList map[strin
 		}
 	}
 
+	if sandboxID, ok := spec.Annotations["io.kubernetes.cri.sandbox-id"]; ok {
+		cp.PodSandboxID = sandboxID
+	}
+
 	// Typically on actual application containers inside the pod/sandbox
 	if _, ok := info.Labels["io.kubernetes.container.name"]; ok {
 		cp.Name = info.Labels["io.kubernetes.container.name"]
@@ -37,9 +37,26 @@ type Registry struct {
 	Configs map[string]RegistryConfig `toml:"configs"`
 }
 
+// RuntimeOptions represents the runc shim options of the runtime.
+type RuntimeOptions struct {
+	BinaryName string `toml:"BinaryName"`
+}
+
+// Runtime represents the CRI runtime handler.
+type Runtime struct {
+	RuntimeType string          `toml:"runtime_type"`
+	Options     *RuntimeOptions `toml:"options,omitempty"`
+}
+
+// ContainerdConfig represents the containerd options of the CRI plugin.
+type ContainerdConfig struct {
+	Runtimes map[string]Runtime `toml:"runtimes"`
+}
+
 // CRIConfig represents the CRI config.
 type CRIConfig struct {
-	Registry Registry `toml:"registry"`
+	Registry   Registry          `toml:"registry"`
+	Containerd *ContainerdConfig `toml:"containerd,omitempty"`
 }
 
 // PluginsConfig represents the CRI plugins config.
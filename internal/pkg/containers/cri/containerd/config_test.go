@@ -17,8 +17,9 @@ import (
 )
 
 type mockConfig struct {
-	mirrors map[string]*v1alpha1.RegistryMirrorConfig
-	config  map[string]*v1alpha1.RegistryConfig
+	mirrors  map[string]*v1alpha1.RegistryMirrorConfig
+	config   map[string]*v1alpha1.RegistryConfig
+	runtimes map[string]*v1alpha1.CRIRuntimeConfig
 }
 
 // Mirrors implements the Registries interface.
@@ -43,6 +44,17 @@ func (c *mockConfig) Config() map[string]config.RegistryConfig {
 	return registries
 }
 
+// Runtimes implements the CRI interface.
+func (c *mockConfig) Runtimes() map[string]config.CRIRuntime {
+	runtimes := make(map[string]config.CRIRuntime, len(c.runtimes))
+
+	for k, v := range c.runtimes {
+		runtimes[k] = v
+	}
+
+	return runtimes
+}
+
 type ConfigSuite struct {
 	suite.Suite
 }
@@ -74,7 +86,7 @@ func (suite *ConfigSuite) TestGenerateRegistriesConfig() {
 		},
 	}
 
-	files, err := containerd.GenerateRegistriesConfig(cfg)
+	files, err := containerd.GenerateCRIConfig(cfg, cfg)
 	suite.Require().NoError(err)
 	suite.Assert().Equal([]config.File{
 		&v1alpha1.MachineFile{
@@ -122,6 +134,43 @@ func (suite *ConfigSuite) TestGenerateRegistriesConfig() {
 	}, files)
 }
 
+func (suite *ConfigSuite) TestGenerateRuntimesConfig() {
+	cfg := &mockConfig{
+		runtimes: map[string]*v1alpha1.CRIRuntimeConfig{
+			"nvidia": {
+				RuntimeBinaryName: "/usr/local/bin/nvidia-container-runtime",
+			},
+			"kata": {
+				CRIRuntimeType: "io.containerd.kata.v2",
+			},
+		},
+	}
+
+	files, err := containerd.GenerateCRIConfig(cfg, cfg)
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]config.File{
+		&v1alpha1.MachineFile{
+			FileContent: `[plugins]
+  [plugins.cri]
+    [plugins.cri.registry]
+      [plugins.cri.registry.mirrors]
+      [plugins.cri.registry.configs]
+    [plugins.cri.containerd]
+      [plugins.cri.containerd.runtimes]
+        [plugins.cri.containerd.runtimes.kata]
+          runtime_type = "io.containerd.kata.v2"
+        [plugins.cri.containerd.runtimes.nvidia]
+          runtime_type = "io.containerd.runc.v2"
+          [plugins.cri.containerd.runtimes.nvidia.options]
+            BinaryName = "/usr/local/bin/nvidia-container-runtime"
+`,
+			FilePermissions: 0o644,
+			FilePath:        constants.CRIContainerdConfig,
+			FileOp:          "append",
+		},
+	}, files)
+}
+
 func TestConfigSuite(t *testing.T) {
 	suite.Run(t, new(ConfigSuite))
 }
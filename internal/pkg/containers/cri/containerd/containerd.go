@@ -17,10 +17,12 @@ import (
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
-// GenerateRegistriesConfig returns a list of extra files.
+// GenerateCRIConfig returns a list of extra files.
+//
+// Registries and additional runtime handlers are rendered into the single CRI plugin config.
 //
 //nolint: gocyclo
-func GenerateRegistriesConfig(r config.Registries) ([]config.File, error) {
+func GenerateCRIConfig(r config.Registries, c config.CRI) ([]config.File, error) {
 	caPath := filepath.Join(filepath.Dir(constants.CRIContainerdConfig), "ca")
 	clientPath := filepath.Join(filepath.Dir(constants.CRIContainerdConfig), "client")
 
@@ -37,7 +39,8 @@ func GenerateRegistriesConfig(r config.Registries) ([]config.File, error) {
 	for registryHost, hostConfig := range r.Config() {
 		cfg := RegistryConfig{}
 
-		if hostConfig.Auth() != nil {
+		// credentials from the provider are short-lived, so they are never written to the CRI config
+		if hostConfig.Auth() != nil && hostConfig.Auth().Provider() == nil {
 			cfg.Auth = &AuthConfig{
 				Username:      hostConfig.Auth().Username(),
 				Password:      hostConfig.Auth().Password(),
@@ -96,6 +99,26 @@ func GenerateRegistriesConfig(r config.Registries) ([]config.File, error) {
 		}
 	}
 
+	if c != nil && len(c.Runtimes()) > 0 {
+		ctrdCfg.Plugins.CRI.Containerd = &ContainerdConfig{
+			Runtimes: make(map[string]Runtime),
+		}
+
+		for name, runtime := range c.Runtimes() {
+			rt := Runtime{
+				RuntimeType: runtime.RuntimeType(),
+			}
+
+			if runtime.BinaryName() != "" {
+				rt.Options = &RuntimeOptions{
+					BinaryName: runtime.BinaryName(),
+				}
+			}
+
+			ctrdCfg.Plugins.CRI.Containerd.Runtimes[name] = rt
+		}
+	}
+
 	var buf bytes.Buffer
 
 	if err := toml.NewEncoder(&buf).Encode(&ctrdCfg); err != nil {
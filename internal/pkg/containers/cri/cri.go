@@ -8,6 +8,8 @@ package cri
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
 	"syscall"
 	"time"
@@ -137,11 +139,8 @@ func (i *inspector) Container(id string) (*ctrs.Container, error) {
 		return pod.Containers[0], nil
 	}
 
-	// request for a container
+	// request for a container, exited containers are included so that logs of crashing containers are available
 	containers, err := i.client.ListContainers(i.ctx, &runtimeapi.ContainerFilter{
-		State: &runtimeapi.ContainerStateValue{
-			State: runtimeapi.ContainerState_CONTAINER_RUNNING,
-		},
 		LabelSelector: map[string]string{
 			"io.kubernetes.pod.name":       pod,
 			"io.kubernetes.pod.namespace":  namespace,
@@ -156,7 +155,74 @@ func (i *inspector) Container(id string) (*ctrs.Container, error) {
 		return nil, nil
 	}
 
-	return i.buildContainer(containers[0])
+	return i.buildContainer(latestContainer(containers))
+}
+
+// ResolvePodContainer implements ctrs.PodContainerResolver.
+func (i *inspector) ResolvePodContainer(id string) (string, error) {
+	namespace, pod, name := parseContainerDisplay(id)
+	if pod == "" || name != "" {
+		return id, nil
+	}
+
+	containers, err := i.client.ListContainers(i.ctx, &runtimeapi.ContainerFilter{
+		LabelSelector: map[string]string{
+			"io.kubernetes.pod.name":      pod,
+			"io.kubernetes.pod.namespace": namespace,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	names := podContainerNames(containers)
+
+	switch len(names) {
+	case 0:
+		return "", fmt.Errorf("no containers found for pod %q", id)
+	case 1:
+		return id + ":" + names[0], nil
+	default:
+		return "", fmt.Errorf("pod %q has multiple containers, specify one of: %s", id, strings.Join(names, ", "))
+	}
+}
+
+// latestContainer picks the most recently created container.
+func latestContainer(containers []*runtimeapi.Container) *runtimeapi.Container {
+	latest := containers[0]
+
+	for _, container := range containers[1:] {
+		if container.CreatedAt > latest.CreatedAt {
+			latest = container
+		}
+	}
+
+	return latest
+}
+
+// podContainerNames returns sorted unique container names.
+func podContainerNames(containers []*runtimeapi.Container) []string {
+	names := []string{}
+
+	for _, container := range containers {
+		found := false
+
+		for _, name := range names {
+			if name == container.Metadata.Name {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			names = append(names, container.Metadata.Name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
 }
 
 func (i *inspector) buildPod(sandbox *runtimeapi.PodSandbox) (*ctrs.Pod, error) {
@@ -177,6 +243,8 @@ func (i *inspector) buildPod(sandbox *runtimeapi.PodSandbox) (*ctrs.Pod, error)
 				PodName:      podName,
 				Status:       sandboxStatus.State.String(),
 				IsPodSandbox: true,
+				PodSandboxID: sandbox.Id,
+				CreatedAt:    time.Unix(0, sandboxStatus.CreatedAt),
 				Metrics:      &ctrs.ContainerMetrics{
 					// assume pod sandbox uses zero
 				},
@@ -224,6 +292,16 @@ func (i *inspector) buildContainer(container *runtimeapi.Container) (*ctrs.Conta
 		RestartCount: container.Annotations["io.kubernetes.container.restartCount"],
 		Status:       container.State.String(),
 		LogPath:      containerStatus.LogPath,
+		PodSandboxID: container.PodSandboxId,
+		CreatedAt:    time.Unix(0, containerStatus.CreatedAt),
+	}
+
+	for _, mount := range containerStatus.Mounts {
+		ctr.Mounts = append(ctr.Mounts, ctrs.ContainerMount{
+			Source:      mount.HostPath,
+			Destination: mount.ContainerPath,
+			Type:        "bind",
+		})
 	}
 
 	if info, ok := containerInfo["info"]; ok {
@@ -235,6 +313,8 @@ func (i *inspector) buildContainer(container *runtimeapi.Container) (*ctrs.Conta
 					ctr.Pid = uint32(fpid)
 				}
 			}
+
+			ctr.Env = runtimeSpecEnv(verboseInfo)
 		}
 	}
 
@@ -344,3 +424,31 @@ func (i *inspector) Kill(id string, isPodSandbox bool, signal syscall.Signal) er
 
 	return i.client.StopContainer(i.ctx, id, 10)
 }
+
+// runtimeSpecEnv extracts process environment from the verbose container info.
+func runtimeSpecEnv(verboseInfo map[string]interface{}) []string {
+	runtimeSpec, ok := verboseInfo["runtimeSpec"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	process, ok := runtimeSpec["process"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	envList, ok := process["env"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	env := make([]string, 0, len(envList))
+
+	for _, v := range envList {
+		if s, ok := v.(string); ok {
+			env = append(env, s)
+		}
+	}
+
+	return env
+}
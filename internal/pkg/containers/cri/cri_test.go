@@ -253,6 +253,22 @@ func (suite *CRISuite) TestContainer() {
 	suite.Require().Nil(container)
 }
 
+func (suite *CRISuite) TestResolvePodContainer() {
+	resolver, ok := suite.inspector.(ctrs.PodContainerResolver)
+	suite.Require().True(ok)
+
+	id, err := resolver.ResolvePodContainer("kube-system/etcd-master-1")
+	suite.Require().NoError(err)
+	suite.Assert().Equal("kube-system/etcd-master-1:etcd", id)
+
+	id, err = resolver.ResolvePodContainer("kube-system/etcd-master-1:etcd2")
+	suite.Require().NoError(err)
+	suite.Assert().Equal("kube-system/etcd-master-1:etcd2", id)
+
+	_, err = resolver.ResolvePodContainer("kube-system/etcd-master-2")
+	suite.Require().Error(err)
+}
+
 func TestCRISuite(t *testing.T) {
 	if os.Getuid() != 0 {
 		t.Skip("can't run the test as non-root")
@@ -0,0 +1,92 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package image
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/namespaces"
+
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+var (
+	cacheImportedMu sync.Mutex
+	cacheImported   = map[string]struct{}{}
+)
+
+// ImportCache imports the image archives from the image cache partition.
+//
+// Images are imported once per containerd namespace, imported images are labeled
+// with constants.ImageCacheLabel, so that Pull uses them without hitting the registry.
+// If the image cache partition is not mounted, ImportCache does nothing.
+func ImportCache(ctx context.Context, client *containerd.Client) error {
+	namespace, err := namespaces.NamespaceRequired(ctx)
+	if err != nil {
+		return err
+	}
+
+	cacheImportedMu.Lock()
+	defer cacheImportedMu.Unlock()
+
+	if _, ok := cacheImported[namespace]; ok {
+		return nil
+	}
+
+	archives, err := filepath.Glob(filepath.Join(constants.ImageCacheMountPoint, "*.tar"))
+	if err != nil {
+		return err
+	}
+
+	for _, archive := range archives {
+		if err = importCacheArchive(ctx, client, archive); err != nil {
+			return err
+		}
+	}
+
+	cacheImported[namespace] = struct{}{}
+
+	return nil
+}
+
+func importCacheArchive(ctx context.Context, client *containerd.Client, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", path, err)
+	}
+
+	defer f.Close() //nolint: errcheck
+
+	imgs, err := client.Import(ctx, f)
+	if err != nil {
+		return fmt.Errorf("error importing %s: %w", path, err)
+	}
+
+	for _, img := range imgs {
+		if img.Labels == nil {
+			img.Labels = map[string]string{}
+		}
+
+		img.Labels[constants.ImageCacheLabel] = "true"
+
+		if _, err = client.ImageService().Update(ctx, img, "labels."+constants.ImageCacheLabel); err != nil {
+			return fmt.Errorf("error labeling %s: %w", img.Name, err)
+		}
+
+		log.Printf("unpacking cached image %s (%s)", img.Name, img.Target.Digest)
+
+		if err = containerd.NewImage(client, img).Unpack(ctx, containerd.DefaultSnapshotter); err != nil {
+			return fmt.Errorf("error unpacking %s: %w", img.Name, err)
+		}
+	}
+
+	return f.Close()
+}
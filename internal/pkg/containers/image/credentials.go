@@ -0,0 +1,217 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package image
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// kubelet credential provider protocol.
+const (
+	credentialProviderAPIVersion   = "credentialprovider.kubelet.k8s.io/v1alpha1"
+	credentialProviderRequestKind  = "CredentialProviderRequest"
+	credentialProviderResponseKind = "CredentialProviderResponse"
+)
+
+type credentialProviderRequest struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Image      string `json:"image"`
+}
+
+type credentialProviderResponse struct {
+	APIVersion    string                            `json:"apiVersion"`
+	Kind          string                            `json:"kind"`
+	CacheDuration *string                           `json:"cacheDuration,omitempty"`
+	Auth          map[string]credentialProviderAuth `json:"auth"`
+}
+
+type credentialProviderAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type cachedCredentials struct {
+	username, password string
+	refreshAt          time.Time
+}
+
+// CredentialCache runs registry credential providers and caches the returned credentials.
+//
+// Concurrent requests for the same provider and host share a single provider run.
+type CredentialCache struct {
+	mu    sync.Mutex
+	cache map[string]cachedCredentials
+
+	group singleflight.Group
+}
+
+// DefaultCredentialCache is used by the registry resolver.
+var DefaultCredentialCache = NewCredentialCache()
+
+// NewCredentialCache initializes empty CredentialCache.
+func NewCredentialCache() *CredentialCache {
+	return &CredentialCache{
+		cache: map[string]cachedCredentials{},
+	}
+}
+
+// Credentials returns registry credentials for the host, running the provider if there are no cached credentials.
+//
+// Cached credentials are refreshed once 80% of the cache duration returned by the provider passes,
+// so that the credentials are never used close to the expiration.
+func (c *CredentialCache) Credentials(ctx context.Context, provider config.RegistryCredentialProvider, host string) (string, string, error) {
+	key := strings.Join(append([]string{provider.Exec(), host}, provider.Args()...), "\x00")
+
+	c.mu.Lock()
+	cached, ok := c.cache[key]
+	c.mu.Unlock()
+
+	if ok && time.Now().Before(cached.refreshAt) {
+		return cached.username, cached.password, nil
+	}
+
+	// the provider might take a while to run, so it's run without holding the lock
+	v, err, _ := c.group.Do(key, func() (interface{}, error) {
+		return c.refresh(ctx, provider, host, key)
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	creds := v.(cachedCredentials) //nolint: errcheck
+
+	return creds.username, creds.password, nil
+}
+
+func (c *CredentialCache) refresh(ctx context.Context, provider config.RegistryCredentialProvider, host, key string) (cachedCredentials, error) {
+	resp, err := runCredentialProvider(ctx, provider, host)
+	if err != nil {
+		return cachedCredentials{}, fmt.Errorf("error running credential provider for %q: %w", host, err)
+	}
+
+	auth, ok := matchCredentialProviderAuth(resp.Auth, host)
+	if !ok {
+		return cachedCredentials{}, fmt.Errorf("credential provider returned no credentials for %q", host)
+	}
+
+	cacheDuration := constants.RegistryCredentialProviderDefaultCacheDuration
+
+	if resp.CacheDuration != nil {
+		cacheDuration, err = time.ParseDuration(*resp.CacheDuration)
+		if err != nil {
+			return cachedCredentials{}, fmt.Errorf("error parsing credential provider cache duration for %q: %w", host, err)
+		}
+	}
+
+	creds := cachedCredentials{
+		username:  auth.Username,
+		password:  auth.Password,
+		refreshAt: time.Now().Add(cacheDuration * 8 / 10),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cacheDuration > 0 {
+		c.cache[key] = creds
+	} else {
+		delete(c.cache, key)
+	}
+
+	return creds, nil
+}
+
+func runCredentialProvider(ctx context.Context, provider config.RegistryCredentialProvider, host string) (*credentialProviderResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, constants.RegistryCredentialProviderTimeout)
+	defer cancel()
+
+	req, err := json.Marshal(credentialProviderRequest{
+		APIVersion: credentialProviderAPIVersion,
+		Kind:       credentialProviderRequestKind,
+		Image:      host,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, provider.Exec(), provider.Args()...)
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = os.Environ()
+
+	for k, v := range provider.Env() {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	if err = cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var resp credentialProviderResponse
+
+	if err = json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	if resp.APIVersion != credentialProviderAPIVersion || resp.Kind != credentialProviderResponseKind {
+		return nil, fmt.Errorf("unexpected response %s/%s", resp.APIVersion, resp.Kind)
+	}
+
+	return &resp, nil
+}
+
+// matchCredentialProviderAuth finds the credentials for the host.
+//
+// Keys in the auth map might contain globs in the host segments (e.g. `*.dkr.ecr.*.amazonaws.com`),
+// exact matches take precedence.
+func matchCredentialProviderAuth(auths map[string]credentialProviderAuth, host string) (credentialProviderAuth, bool) {
+	if auth, ok := auths[host]; ok {
+		return auth, true
+	}
+
+	hostSegments := strings.Split(host, ".")
+
+	for pattern, auth := range auths {
+		patternSegments := strings.Split(pattern, ".")
+
+		if len(patternSegments) != len(hostSegments) {
+			continue
+		}
+
+		matched := true
+
+		for i := range patternSegments {
+			if ok, _ := path.Match(patternSegments[i], hostSegments[i]); !ok {
+				matched = false
+
+				break
+			}
+		}
+
+		if matched {
+			return auth, true
+		}
+	}
+
+	return credentialProviderAuth{}, false
+}
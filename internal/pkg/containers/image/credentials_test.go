@@ -0,0 +1,89 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package image_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/containers/image"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+)
+
+const credentialProviderScript = `#!/bin/sh
+cat > "$TEST_DIR/request.json"
+echo run >> "$TEST_DIR/runs"
+cat <<EOF
+{"apiVersion":"credentialprovider.kubelet.k8s.io/v1alpha1","kind":"CredentialProviderResponse","cacheKeyType":"Registry",
+"cacheDuration":"$CACHE_DURATION","auth":{"*.dkr.ecr.*.amazonaws.com":{"username":"AWS","password":"token"}}}
+EOF
+`
+
+func TestCredentialCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	exec := filepath.Join(dir, "provider")
+	require.NoError(t, ioutil.WriteFile(exec, []byte(credentialProviderScript), 0o755))
+
+	runs := func() int {
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "runs"))
+		require.NoError(t, err)
+
+		return strings.Count(string(contents), "run")
+	}
+
+	const host = "123456789012.dkr.ecr.us-east-1.amazonaws.com"
+
+	for _, tt := range []struct {
+		cacheDuration string
+		expectedRuns  int
+	}{
+		{"12h0m0s", 1},
+		{"0s", 3},
+	} {
+		require.NoError(t, os.RemoveAll(filepath.Join(dir, "runs")))
+
+		cache := image.NewCredentialCache()
+		provider := &v1alpha1.RegistryCredentialProviderConfig{
+			ProviderExec: exec,
+			ProviderEnv: map[string]string{
+				"TEST_DIR":       dir,
+				"CACHE_DURATION": tt.cacheDuration,
+			},
+		}
+
+		for i := 0; i < 3; i++ {
+			user, pass, err := cache.Credentials(context.Background(), provider, host)
+			require.NoError(t, err)
+			assert.Equal(t, "AWS", user)
+			assert.Equal(t, "token", pass)
+		}
+
+		assert.Equal(t, tt.expectedRuns, runs())
+	}
+
+	request, err := ioutil.ReadFile(filepath.Join(dir, "request.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"apiVersion":"credentialprovider.kubelet.k8s.io/v1alpha1","kind":"CredentialProviderRequest","image":"`+host+`"}`, string(request))
+
+	_, _, err = image.NewCredentialCache().Credentials(context.Background(), &v1alpha1.RegistryCredentialProviderConfig{
+		ProviderExec: exec,
+		ProviderEnv: map[string]string{
+			"TEST_DIR":       dir,
+			"CACHE_DURATION": "1h",
+		},
+	}, "ghcr.io")
+	assert.EqualError(t, err, `credential provider returned no credentials for "ghcr.io"`)
+}
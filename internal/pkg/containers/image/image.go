@@ -7,11 +7,14 @@ package image
 import (
 	"context"
 	"fmt"
+	"log"
 	"os"
 	"time"
 
 	"github.com/containerd/containerd"
 	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/remotes"
 	"github.com/talos-systems/go-retry/retry"
 
 	containerdrunner "github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/containerd"
@@ -32,9 +35,46 @@ const (
 	ImportRetryJitter   = time.Second
 )
 
+// PullOption is a functional option for Pull.
+type PullOption func(*PullOptions)
+
+// PullOptions configures image pull.
+type PullOptions struct {
+	Verification config.ImageVerification
+}
+
+// WithVerification enables image signature verification if it's configured.
+func WithVerification(v config.ImageVerification) PullOption {
+	return func(o *PullOptions) {
+		o.Verification = v
+	}
+}
+
 // Pull is a convenience function that wraps the containerd image pull func with
 // retry functionality.
-func Pull(ctx context.Context, reg config.Registries, client *containerd.Client, ref string) (img containerd.Image, err error) {
+//
+// Images imported from the image cache are used without contacting the registry, their signatures
+// are verified against the signatures imported from the image cache.
+// If signature verification is enabled, the image is removed when the signature is not valid.
+func Pull(ctx context.Context, reg config.Registries, client *containerd.Client, ref string, opts ...PullOption) (img containerd.Image, err error) {
+	var options PullOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if img, err = client.GetImage(ctx, ref); err == nil {
+		if _, cached := img.Labels()[constants.ImageCacheLabel]; cached {
+			log.Printf("using image %q from the image cache", ref)
+
+			if err = verify(ctx, client, &localResolver{client: client}, options.Verification, img); err != nil {
+				return nil, err
+			}
+
+			return img, nil
+		}
+	}
+
 	resolver := NewResolver(reg)
 
 	err = retry.Exponential(PullTimeout, retry.WithUnits(PullRetryInterval), retry.WithErrorLogging(true)).Retry(func() error {
@@ -55,14 +95,69 @@ func Pull(ctx context.Context, reg config.Registries, client *containerd.Client,
 		return nil, err
 	}
 
+	if err = verify(ctx, client, resolver, options.Verification, img); err != nil {
+		return nil, err
+	}
+
 	return img, nil
 }
 
+// Verify checks the signature of the image which is already present in the image store.
+//
+// Signatures found in the image store (imported from the image cache or the system image archives) are used first,
+// other signatures are fetched from the registries.
+// If the signature is not valid, the image is removed from the image store.
+func Verify(ctx context.Context, reg config.Registries, client *containerd.Client, img containerd.Image, opts ...PullOption) error {
+	var options PullOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return verify(ctx, client, &localResolver{client: client, fallback: NewResolver(reg)}, options.Verification, img)
+}
+
+func verify(ctx context.Context, client *containerd.Client, resolver remotes.Resolver, cfg config.ImageVerification, img containerd.Image) error {
+	if cfg == nil || !cfg.Enabled() {
+		return nil
+	}
+
+	verifier, err := NewVerifier(cfg)
+	if err != nil {
+		return err
+	}
+
+	if !verifier.Matches(img.Name()) {
+		return nil
+	}
+
+	if err = verifier.Verify(ctx, resolver, img.Name(), img.Target()); err != nil {
+		if deleteErr := client.ImageService().Delete(ctx, img.Name()); deleteErr != nil {
+			log.Printf("failed to remove image %q: %s", img.Name(), deleteErr)
+		}
+
+		return fmt.Errorf("image %q signature verification failed: %w", img.Name(), err)
+	}
+
+	log.Printf("verified signature of image %q", img.Name())
+
+	return nil
+}
+
 // Import is a convenience function that wraps containerd image import with retries.
-func Import(ctx context.Context, imagePath, indexName string) error {
+//
+// If signature verification is enabled, the imported image is verified against the signatures
+// imported from the same archive, as the system images are not published to any registry.
+func Import(ctx context.Context, imagePath, indexName string, opts ...PullOption) error {
+	var options PullOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	importer := containerdrunner.NewImporter(constants.SystemContainerdNamespace, containerdrunner.WithContainerdAddress(constants.SystemContainerdAddress))
 
-	return retry.Exponential(ImportTimeout, retry.WithUnits(ImportRetryInterval), retry.WithJitter(ImportRetryJitter), retry.WithErrorLogging(true)).Retry(func() error {
+	err := retry.Exponential(ImportTimeout, retry.WithUnits(ImportRetryInterval), retry.WithJitter(ImportRetryJitter), retry.WithErrorLogging(true)).Retry(func() error {
 		err := retry.ExpectedError(importer.Import(ctx, &containerdrunner.ImportRequest{
 			Path: imagePath,
 			Options: []containerd.ImportOpt{
@@ -76,4 +171,28 @@ func Import(ctx context.Context, imagePath, indexName string) error {
 
 		return retry.ExpectedError(err)
 	})
+	if err != nil {
+		return err
+	}
+
+	if options.Verification == nil || !options.Verification.Enabled() {
+		return nil
+	}
+
+	client, err := containerd.New(constants.SystemContainerdAddress)
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer client.Close()
+
+	ctx = namespaces.WithNamespace(ctx, constants.SystemContainerdNamespace)
+
+	img, err := client.GetImage(ctx, indexName)
+	if err != nil {
+		return err
+	}
+
+	return verify(ctx, client, &localResolver{client: client}, options.Verification, img)
 }
@@ -0,0 +1,71 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package image
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/remotes"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// localResolver resolves the references from the containerd image store and fetches the blobs
+// from the containerd content store.
+//
+// References which are not found in the image store are passed to the fallback resolver (if set).
+type localResolver struct {
+	client   *containerd.Client
+	fallback remotes.Resolver
+}
+
+// Resolve implements remotes.Resolver.
+func (r *localResolver) Resolve(ctx context.Context, ref string) (string, ocispec.Descriptor, error) {
+	img, err := r.client.ImageService().Get(ctx, ref)
+	if err != nil {
+		if errdefs.IsNotFound(err) && r.fallback != nil {
+			return r.fallback.Resolve(ctx, ref)
+		}
+
+		return "", ocispec.Descriptor{}, err
+	}
+
+	return ref, img.Target, nil
+}
+
+// Fetcher implements remotes.Resolver.
+func (r *localResolver) Fetcher(ctx context.Context, ref string) (remotes.Fetcher, error) {
+	if _, err := r.client.ImageService().Get(ctx, ref); err != nil {
+		if errdefs.IsNotFound(err) && r.fallback != nil {
+			return r.fallback.Fetcher(ctx, ref)
+		}
+
+		return nil, err
+	}
+
+	return remotes.FetcherFunc(func(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+		ra, err := r.client.ContentStore().ReaderAt(ctx, desc)
+		if err != nil {
+			return nil, err
+		}
+
+		return struct {
+			io.Reader
+			io.Closer
+		}{
+			Reader: content.NewReader(ra),
+			Closer: ra,
+		}, nil
+	}), nil
+}
+
+// Pusher implements remotes.Resolver.
+func (r *localResolver) Pusher(ctx context.Context, ref string) (remotes.Pusher, error) {
+	return nil, errors.New("pushing to the local image store is not supported")
+}
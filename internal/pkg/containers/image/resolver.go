@@ -5,6 +5,7 @@
 package image
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"net"
@@ -127,6 +128,10 @@ func PrepareAuth(auth config.RegistryAuthConfig, host, expectedHost string) (str
 		return "", "", nil
 	}
 
+	if auth.Provider() != nil {
+		return DefaultCredentialCache.Credentials(context.Background(), auth.Provider(), host)
+	}
+
 	if auth.Username() != "" {
 		return auth.Username(), auth.Password(), nil
 	}
@@ -0,0 +1,225 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package image
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/containerd/containerd/reference/docker"
+	"github.com/containerd/containerd/remotes"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/talos-systems/talos/pkg/machinery/config"
+)
+
+// cosign signature layout.
+const (
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	cosignSignatureTagSuffix  = ".sig"
+	cosignSignatureType       = "cosign container image signature"
+)
+
+// maxSignatureBlobSize limits the size of the signature manifest and payload.
+const maxSignatureBlobSize = 1024 * 1024
+
+// Verifier checks cosign signatures of the images.
+type Verifier struct {
+	keys   []crypto.PublicKey
+	images []string
+}
+
+// NewVerifier initializes Verifier from the machine configuration.
+func NewVerifier(cfg config.ImageVerification) (*Verifier, error) {
+	keys, err := ParsePublicKeys(cfg.PublicKeys())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Verifier{
+		keys:   keys,
+		images: cfg.Images(),
+	}, nil
+}
+
+// ParsePublicKeys parses the list of PEM-encoded public keys.
+func ParsePublicKeys(pems []string) ([]crypto.PublicKey, error) {
+	keys := make([]crypto.PublicKey, 0, len(pems))
+
+	for i, p := range pems {
+		block, _ := pem.Decode([]byte(p))
+		if block == nil {
+			return nil, fmt.Errorf("failed to decode public key %d", i)
+		}
+
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key %d: %w", i, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// Matches checks whether the image reference should be verified.
+func (v *Verifier) Matches(ref string) bool {
+	if len(v.images) == 0 {
+		return true
+	}
+
+	for _, image := range v.images {
+		if strings.HasSuffix(image, "*") {
+			if strings.HasPrefix(ref, strings.TrimSuffix(image, "*")) {
+				return true
+			}
+
+			continue
+		}
+
+		if ref == image {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Verify fetches the cosign signature of the image and checks it against the trusted keys.
+//
+// Argument target is the descriptor of the image manifest (or index) the reference resolves to.
+func (v *Verifier) Verify(ctx context.Context, resolver remotes.Resolver, ref string, target ocispec.Descriptor) error {
+	named, err := docker.ParseDockerRef(ref)
+	if err != nil {
+		return err
+	}
+
+	sigRef := named.Name() + ":" + strings.Replace(target.Digest.String(), ":", "-", 1) + cosignSignatureTagSuffix
+
+	name, desc, err := resolver.Resolve(ctx, sigRef)
+	if err != nil {
+		return fmt.Errorf("failed to resolve signature %q: %w", sigRef, err)
+	}
+
+	fetcher, err := resolver.Fetcher(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	var manifest ocispec.Manifest
+
+	manifestData, err := fetchBlob(ctx, fetcher, desc)
+	if err != nil {
+		return fmt.Errorf("failed to fetch signature manifest: %w", err)
+	}
+
+	if err = json.Unmarshal(manifestData, &manifest); err != nil {
+		return fmt.Errorf("failed to decode signature manifest: %w", err)
+	}
+
+	for _, layer := range manifest.Layers {
+		signature, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+
+		var payload []byte
+
+		payload, err = fetchBlob(ctx, fetcher, layer)
+		if err != nil {
+			return fmt.Errorf("failed to fetch signature payload: %w", err)
+		}
+
+		if err = VerifyPayload(payload, signature, target.Digest.String(), v.keys); err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no valid signature found for %q", ref)
+}
+
+// VerifyPayload checks cosign simple signing payload signature and verifies that
+// the payload refers to the expected image digest.
+func VerifyPayload(payload []byte, signature, digest string, keys []crypto.PublicKey) error {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	hash := sha256.Sum256(payload)
+
+	verified := false
+
+	for _, key := range keys {
+		if ecdsaKey, ok := key.(*ecdsa.PublicKey); ok && ecdsa.VerifyASN1(ecdsaKey, hash[:], sig) {
+			verified = true
+
+			break
+		}
+	}
+
+	if !verified {
+		return errors.New("signature is not verified by any of the trusted keys")
+	}
+
+	var simpleSigning struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+			Type string `json:"type"`
+		} `json:"critical"`
+	}
+
+	if err = json.Unmarshal(payload, &simpleSigning); err != nil {
+		return fmt.Errorf("failed to decode signature payload: %w", err)
+	}
+
+	if simpleSigning.Critical.Type != cosignSignatureType {
+		return fmt.Errorf("unexpected signature type %q", simpleSigning.Critical.Type)
+	}
+
+	if simpleSigning.Critical.Image.DockerManifestDigest != digest {
+		return fmt.Errorf("signature is for digest %q, expected %q", simpleSigning.Critical.Image.DockerManifestDigest, digest)
+	}
+
+	return nil
+}
+
+func fetchBlob(ctx context.Context, fetcher remotes.Fetcher, desc ocispec.Descriptor) ([]byte, error) {
+	if desc.Size > maxSignatureBlobSize {
+		return nil, fmt.Errorf("blob %s is too big: %d bytes", desc.Digest, desc.Size)
+	}
+
+	r, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint: errcheck
+	defer r.Close()
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if actual := fmt.Sprintf("sha256:%x", sha256.Sum256(data)); actual != desc.Digest.String() {
+		return nil, fmt.Errorf("blob digest mismatch: expected %s, got %s", desc.Digest, actual)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,72 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package image_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/containers/image"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+)
+
+const testDigest = "sha256:6f5b4c6cf8c6b9be8e8cd7ec0d7c38fb3eea4b6b8b0c1d8c2dc1b3a0b1e36a28"
+
+func TestVerifyPayload(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"ghcr.io/talos-systems/installer"},"image":{"docker-manifest-digest":"` +
+		testDigest + `"},"type":"cosign container image signature"},"optional":null}`)
+
+	hash := sha256.Sum256(payload)
+
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	require.NoError(t, err)
+
+	signature := base64.StdEncoding.EncodeToString(sig)
+
+	assert.NoError(t, image.VerifyPayload(payload, signature, testDigest, []crypto.PublicKey{&otherKey.PublicKey, &key.PublicKey}))
+	assert.Error(t, image.VerifyPayload(payload, signature, testDigest, []crypto.PublicKey{&otherKey.PublicKey}))
+	assert.Error(t, image.VerifyPayload(payload, signature, "sha256:0000", []crypto.PublicKey{&key.PublicKey}))
+	assert.Error(t, image.VerifyPayload(append(payload, ' '), signature, testDigest, []crypto.PublicKey{&key.PublicKey}))
+}
+
+func TestVerifierMatches(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	verifier, err := image.NewVerifier(&v1alpha1.ImageVerificationConfig{
+		VerificationPublicKeys: []string{string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))},
+		VerificationImages:     []string{"ghcr.io/talos-systems/*", "k8s.gcr.io/etcd:3.4.14"},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, verifier.Matches("ghcr.io/talos-systems/installer:v0.8.0"))
+	assert.True(t, verifier.Matches("k8s.gcr.io/etcd:3.4.14"))
+	assert.False(t, verifier.Matches("k8s.gcr.io/etcd:3.4.13"))
+	assert.False(t, verifier.Matches("docker.io/library/nginx:latest"))
+
+	_, err = image.NewVerifier(&v1alpha1.ImageVerificationConfig{
+		VerificationPublicKeys: []string{"not a key"},
+	})
+	assert.Error(t, err)
+}
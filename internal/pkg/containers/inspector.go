@@ -21,3 +21,11 @@ type Inspector interface {
 	// Kill sends signal to container's process
 	Kill(ID string, isPodSandbox bool, signal syscall.Signal) error
 }
+
+// PodContainerResolver is implemented by inspectors which can resolve pod name to the name of the pod container.
+type PodContainerResolver interface {
+	// ResolvePodContainer returns container ID in the form 'namespace/pod:container'.
+	//
+	// If the container name is missing from the ID, the only container of the pod is picked.
+	ResolvePodContainer(id string) (string, error)
+}
@@ -0,0 +1,205 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package controlplane
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/talos-systems/go-retry/retry"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/talos-systems/talos/pkg/kubernetes"
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// caSecrets are the CA certificates copied from the machine configuration, they are not re-issued.
+var caSecrets = map[string]bool{
+	"ca.crt":             true,
+	"front-proxy-ca.crt": true,
+	"etcd-client-ca.crt": true,
+}
+
+// IssuedCertificate is a certificate issued for a control plane component.
+type IssuedCertificate struct {
+	// Source is the path to the secret which holds the certificate.
+	Source      string
+	Certificate *x509.Certificate
+}
+
+// IssuedCertificates returns the certificates issued for the control plane components.
+//
+// Certificates are read from the component secrets: certificate files and client certificates embedded
+// into the kubeconfigs.
+func IssuedCertificates() ([]IssuedCertificate, error) {
+	return issuedCertificates(constants.KubernetesControlPlaneSecretsDir)
+}
+
+func issuedCertificates(secretsDir string) ([]IssuedCertificate, error) {
+	var result []IssuedCertificate
+
+	for _, component := range Components {
+		dir := filepath.Join(secretsDir, component)
+
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		for _, file := range files {
+			path := filepath.Join(dir, file.Name())
+
+			var certs []*x509.Certificate
+
+			switch {
+			case file.Name() == "kubeconfig":
+				certs, err = kubeconfigCertificates(path)
+			case filepath.Ext(path) == ".crt" && !caSecrets[file.Name()]:
+				certs, err = fileCertificates(path)
+			default:
+				continue
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("error reading certificates from %q: %w", path, err)
+			}
+
+			for _, cert := range certs {
+				result = append(result, IssuedCertificate{
+					Source:      path,
+					Certificate: cert,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Certificate.NotAfter.Before(result[j].Certificate.NotAfter)
+	})
+
+	return result, nil
+}
+
+func fileCertificates(path string) ([]*x509.Certificate, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCertificates(contents)
+}
+
+func kubeconfigCertificates(path string) ([]*x509.Certificate, error) {
+	kubeconfig, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []*x509.Certificate
+
+	for _, authInfo := range kubeconfig.AuthInfos {
+		parsed, err := parseCertificates(authInfo.ClientCertificateData)
+		if err != nil {
+			return nil, err
+		}
+
+		certs = append(certs, parsed...)
+	}
+
+	return certs, nil
+}
+
+func parseCertificates(contents []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	for {
+		var block *pem.Block
+
+		block, contents = pem.Decode(contents)
+		if block == nil {
+			return certs, nil
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		certs = append(certs, cert)
+	}
+}
+
+// NeedsRotation returns true if any of the issued certificates expires soon.
+func NeedsRotation(certs []IssuedCertificate, now time.Time) bool {
+	for _, cert := range certs {
+		if cert.Certificate.NotAfter.Before(now.Add(constants.KubernetesControlPlaneCertRenewBefore)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Rotate re-issues the control plane certificates and waits for the static pods to be restarted with the new secrets.
+//
+// Static pods of the node are restarted by the kubelet, so the caller should make sure that the nodes
+// are rotated one at a time to keep the control plane available.
+func Rotate(config config.Provider, client *kubernetes.Client, nodeName string) error {
+	secrets, err := render(config, constants.KubernetesControlPlaneSecretsDir, constants.ManifestsDirectory, constants.KubernetesControlPlaneRunUser)
+	if err != nil {
+		return err
+	}
+
+	return retry.Constant(constants.KubernetesControlPlaneRotationTimeout, retry.WithUnits(5*time.Second)).Retry(func() error {
+		for _, component := range Components {
+			if err := checkStaticPod(client, component+"-"+nodeName, secrets.version(component)); err != nil {
+				return retry.ExpectedError(err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// checkStaticPod verifies that the mirror pod runs with the expected secrets version and it is ready.
+func checkStaticPod(client *kubernetes.Client, name, version string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pod, err := client.CoreV1().Pods(metav1.NamespaceSystem).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if pod.Annotations[SecretsVersionAnnotation] != version {
+		return fmt.Errorf("pod %q is not restarted yet", name)
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("pod %q is not ready", name)
+}
@@ -0,0 +1,129 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package controlplane renders Kubernetes control plane components as static pods.
+package controlplane
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// Control plane components rendered as static pods.
+const (
+	KubeAPIServer         = "kube-apiserver"
+	KubeControllerManager = "kube-controller-manager"
+	KubeScheduler         = "kube-scheduler"
+)
+
+// Components is the list of the control plane components.
+var Components = []string{KubeAPIServer, KubeControllerManager, KubeScheduler}
+
+// Enabled returns true if the control plane should be run as static pods.
+//
+// Clusters bootstrapped with the self-hosted control plane don't have the aggregator CA
+// and the service account key in the machine configuration, so they keep running the
+// control plane managed by bootkube and pod-checkpointer.
+func Enabled(config config.ClusterConfig) bool {
+	return config.AggregatorCA() != nil && config.ServiceAccountKey() != nil
+}
+
+// Render writes the secrets and the static pod manifests of the control plane components.
+//
+// Manifests are only rewritten if they changed, which makes kubelet restart the pods.
+func Render(config config.Provider) error {
+	_, err := render(config, constants.KubernetesControlPlaneSecretsDir, constants.ManifestsDirectory, constants.KubernetesControlPlaneRunUser)
+
+	return err
+}
+
+func render(config config.Provider, secretsDir, manifestsDir string, uid int) (secrets, error) {
+	secrets, err := generateSecrets(config.Cluster())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, component := range Components {
+		if err = writeSecrets(filepath.Join(secretsDir, component), secrets[component], uid); err != nil {
+			return nil, fmt.Errorf("error writing %s secrets: %w", component, err)
+		}
+	}
+
+	pods, err := staticPods(config.Cluster(), secretsDir, secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.MkdirAll(manifestsDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	serializer := newSerializer()
+
+	for _, pod := range pods {
+		var buf bytes.Buffer
+
+		if err = serializer.Encode(pod, &buf); err != nil {
+			return nil, fmt.Errorf("error encoding %s manifest: %w", pod.Name, err)
+		}
+
+		if err = writeIfChanged(filepath.Join(manifestsDir, manifestName(pod.Name)), buf.Bytes()); err != nil {
+			return nil, fmt.Errorf("error writing %s manifest: %w", pod.Name, err)
+		}
+	}
+
+	return secrets, nil
+}
+
+func manifestName(component string) string {
+	return fmt.Sprintf("talos-%s.yaml", component)
+}
+
+func writeSecrets(dir string, files map[string][]byte, uid int) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	if err := os.Chown(dir, uid, -1); err != nil {
+		return err
+	}
+
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+
+		if err := ioutil.WriteFile(path, contents, 0o400); err != nil {
+			return err
+		}
+
+		if err := os.Chown(path, uid, -1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeIfChanged replaces the file atomically if the contents differ.
+//
+// Temporary file name starts with a dot, so it's ignored by the kubelet.
+func writeIfChanged(path string, contents []byte) error {
+	existing, err := ioutil.ReadFile(path)
+	if err == nil && bytes.Equal(existing, contents) {
+		return nil
+	}
+
+	tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path))
+
+	if err = ioutil.WriteFile(tmp, contents, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,331 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//nolint: testpackage
+package controlplane
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/talos-systems/talos/pkg/argsbuilder"
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+type ControlPlaneSuite struct {
+	suite.Suite
+
+	input *generate.Input
+	dir   string
+}
+
+func (suite *ControlPlaneSuite) SetupSuite() {
+	secrets, err := generate.NewSecretsBundle(generate.NewClock())
+	suite.Require().NoError(err)
+
+	suite.input, err = generate.NewInput("test", "https://10.5.0.1:6443", constants.DefaultKubernetesVersion, secrets)
+	suite.Require().NoError(err)
+}
+
+func (suite *ControlPlaneSuite) SetupTest() {
+	var err error
+
+	suite.dir, err = ioutil.TempDir("", "talos")
+	suite.Require().NoError(err)
+}
+
+func (suite *ControlPlaneSuite) TearDownTest() {
+	suite.Require().NoError(os.RemoveAll(suite.dir))
+}
+
+func (suite *ControlPlaneSuite) config() *v1alpha1.Config {
+	cfg, err := generate.Config(machine.TypeInit, suite.input)
+	suite.Require().NoError(err)
+
+	return cfg
+}
+
+func (suite *ControlPlaneSuite) TestEnabled() {
+	suite.Assert().True(Enabled(suite.config().Cluster()))
+
+	input := *suite.input
+	input.VersionContract = config.TalosVersion0_7
+
+	cfg, err := generate.Config(machine.TypeInit, &input)
+	suite.Require().NoError(err)
+
+	suite.Assert().False(Enabled(cfg.Cluster()))
+}
+
+func (suite *ControlPlaneSuite) TestSecrets() {
+	cfg := suite.config()
+
+	secrets, err := generateSecrets(cfg.Cluster())
+	suite.Require().NoError(err)
+
+	for _, component := range Components {
+		suite.Require().NoError(writeSecrets(filepath.Join(suite.dir, component), secrets[component], os.Getuid()))
+	}
+
+	for _, component := range []string{KubeControllerManager, KubeScheduler} {
+		kubeconfig, err := clientcmd.LoadFromFile(filepath.Join(suite.dir, component, "kubeconfig"))
+		suite.Require().NoError(err)
+
+		suite.Assert().NoError(clientcmd.ConfirmUsable(*kubeconfig, "system:"+component+"@test"))
+		suite.Assert().Equal("https://127.0.0.1:6443", kubeconfig.Clusters["test"].Server)
+	}
+
+	_, err = ioutil.ReadFile(filepath.Join(suite.dir, KubeAPIServer, "service-account.pub"))
+	suite.Require().NoError(err)
+
+	// certificates are re-issued on each render, so the pods are restarted
+	reissued, err := generateSecrets(cfg.Cluster())
+	suite.Require().NoError(err)
+
+	for _, component := range Components {
+		suite.Assert().NotEqual(secrets.version(component), reissued.version(component))
+	}
+}
+
+func (suite *ControlPlaneSuite) TestStaticPods() {
+	cfg := suite.config()
+
+	secrets, err := generateSecrets(cfg.Cluster())
+	suite.Require().NoError(err)
+
+	pods, err := staticPods(cfg.Cluster(), suite.dir, secrets)
+	suite.Require().NoError(err)
+	suite.Require().Len(pods, len(Components))
+
+	for i, component := range Components {
+		pod := pods[i]
+
+		suite.Assert().Equal(component, pod.Name)
+		suite.Assert().Equal(component, pod.Labels["k8s-app"])
+		suite.Assert().Equal(secrets.version(component), pod.Annotations[SecretsVersionAnnotation])
+		suite.Assert().Equal(filepath.Join(suite.dir, component), pod.Spec.Volumes[0].HostPath.Path)
+		suite.Assert().Equal([]string{"/go-runner", "/usr/local/bin/" + component}, pod.Spec.Containers[0].Command[:2])
+	}
+}
+
+func (suite *ControlPlaneSuite) TestWriteIfChanged() {
+	path := filepath.Join(suite.dir, manifestName(KubeAPIServer))
+
+	suite.Require().NoError(writeIfChanged(path, []byte("foo")))
+
+	st1, err := os.Stat(path)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(writeIfChanged(path, []byte("foo")))
+
+	st2, err := os.Stat(path)
+	suite.Require().NoError(err)
+
+	suite.Assert().True(os.SameFile(st1, st2))
+
+	suite.Require().NoError(writeIfChanged(path, []byte("bar")))
+
+	contents, err := ioutil.ReadFile(path)
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal([]byte("bar"), contents)
+}
+
+func (suite *ControlPlaneSuite) TestAPIServerArgs() {
+	cfg := suite.config()
+
+	args, err := apiServerArgs(cfg.Cluster())
+	suite.Require().NoError(err)
+
+	suite.Assert().Contains(args, "--enable-admission-plugins=PodSecurityPolicy,"+admissionPlugins)
+	suite.Assert().Contains(args, "--etcd-servers=https://127.0.0.1:2379")
+	suite.Assert().True(sort.StringsAreSorted(args))
+
+	cfg.ClusterConfig.APIServerConfig = &v1alpha1.APIServerConfig{
+		DisablePodSecurityPolicyConfig: true,
+		ExtraArgsConfig: map[string]string{
+			"feature-gates": "EphemeralContainers=true",
+		},
+	}
+
+	args, err = apiServerArgs(cfg.Cluster())
+	suite.Require().NoError(err)
+
+	suite.Assert().Contains(args, "--enable-admission-plugins="+admissionPlugins)
+	suite.Assert().Contains(args, "--feature-gates=EphemeralContainers=true")
+
+	cfg.ClusterConfig.APIServerConfig.ExtraArgsConfig["tls-cert-file"] = "/etc/cert"
+
+	_, err = apiServerArgs(cfg.Cluster())
+	suite.Assert().Equal(argsbuilder.NewDenylistError("tls-cert-file"), err)
+}
+
+func (suite *ControlPlaneSuite) TestExternalCloudProvider() {
+	cfg := suite.config()
+
+	args, err := controllerManagerArgs(cfg.Cluster())
+	suite.Require().NoError(err)
+
+	suite.Assert().NotContains(args, "--cloud-provider=external")
+
+	cfg.ClusterConfig.ExternalCloudProviderConfig = &v1alpha1.ExternalCloudProviderConfig{
+		ExternalEnabled: true,
+	}
+
+	args, err = controllerManagerArgs(cfg.Cluster())
+	suite.Require().NoError(err)
+
+	suite.Assert().Contains(args, "--cloud-provider=external")
+
+	args, err = apiServerArgs(cfg.Cluster())
+	suite.Require().NoError(err)
+
+	suite.Assert().Contains(args, "--cloud-provider=external")
+}
+
+func (suite *ControlPlaneSuite) TestServiceAccountArgs() {
+	cfg := suite.config()
+
+	suite.Assert().Equal(argsbuilder.Args{
+		"service-account-issuer": "https://10.5.0.1:6443",
+		"api-audiences":          "https://10.5.0.1:6443",
+	}, ServiceAccountArgs(cfg.Cluster()))
+
+	cfg.ClusterConfig.APIServerConfig = &v1alpha1.APIServerConfig{
+		ServiceAccountIssuerConfig:         "https://oidc.example.com",
+		ServiceAccountJWKSURIConfig:        "https://oidc.example.com/openid/v1/jwks",
+		ServiceAccountExtraAudiencesConfig: []string{"sts.amazonaws.com"},
+	}
+
+	suite.Assert().Equal(argsbuilder.Args{
+		"service-account-issuer":   "https://oidc.example.com",
+		"service-account-jwks-uri": "https://oidc.example.com/openid/v1/jwks",
+		"api-audiences":            "https://oidc.example.com,sts.amazonaws.com",
+	}, ServiceAccountArgs(cfg.Cluster()))
+}
+
+func (suite *ControlPlaneSuite) TestSchedulerConfig() {
+	cfg := suite.config()
+
+	args, err := schedulerArgs(cfg.Cluster())
+	suite.Require().NoError(err)
+
+	suite.Assert().Contains(args, "--kubeconfig=/etc/kubernetes/secrets/kubeconfig")
+	suite.Assert().NotContains(args, "--config=/etc/kubernetes/secrets/config.yaml")
+
+	cfg.ClusterConfig.SchedulerConfig = &v1alpha1.SchedulerConfig{
+		SchedulerConfigConfig: map[string]interface{}{
+			"apiVersion": "kubescheduler.config.k8s.io/v1beta1",
+			"kind":       "KubeSchedulerConfiguration",
+			"clientConnection": map[string]interface{}{
+				"qps": 100,
+			},
+		},
+	}
+
+	args, err = schedulerArgs(cfg.Cluster())
+	suite.Require().NoError(err)
+
+	suite.Assert().Contains(args, "--config=/etc/kubernetes/secrets/config.yaml")
+	suite.Assert().NotContains(args, "--kubeconfig=/etc/kubernetes/secrets/kubeconfig")
+
+	secrets, err := generateSecrets(cfg.Cluster())
+	suite.Require().NoError(err)
+
+	var schedulerConfig map[string]interface{}
+
+	suite.Require().NoError(yaml.Unmarshal(secrets[KubeScheduler]["config.yaml"], &schedulerConfig))
+
+	suite.Assert().Equal("KubeSchedulerConfiguration", schedulerConfig["kind"])
+	suite.Assert().Equal(false, schedulerConfig["enableProfiling"])
+	suite.Assert().Equal(map[string]interface{}{
+		"kubeconfig": "/etc/kubernetes/secrets/kubeconfig",
+		"qps":        100,
+	}, schedulerConfig["clientConnection"])
+
+	cfg.ClusterConfig.SchedulerConfig.ExtraArgsConfig = map[string]string{
+		"config": "/etc/config.yaml",
+	}
+
+	_, err = schedulerArgs(cfg.Cluster())
+	suite.Assert().Equal(argsbuilder.NewDenylistError("config"), err)
+}
+
+func (suite *ControlPlaneSuite) TestControllerManagerExtraConfig() {
+	cfg := suite.config()
+
+	cfg.ClusterConfig.ControllerManagerConfig = &v1alpha1.ControllerManagerConfig{
+		ExtraConfigConfig: map[string]string{
+			"cloud.conf": "[Global]\n",
+		},
+	}
+
+	secrets, err := generateSecrets(cfg.Cluster())
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal([]byte("[Global]\n"), secrets[KubeControllerManager]["cloud.conf"])
+
+	cfg.ClusterConfig.ControllerManagerConfig.ExtraConfigConfig["kubeconfig"] = "foo"
+
+	_, err = generateSecrets(cfg.Cluster())
+	suite.Assert().Error(err)
+}
+
+func (suite *ControlPlaneSuite) TestIssuedCertificates() {
+	certs, err := issuedCertificates(suite.dir)
+	suite.Require().NoError(err)
+	suite.Assert().Empty(certs)
+
+	cfg := suite.config()
+
+	secrets, err := generateSecrets(cfg.Cluster())
+	suite.Require().NoError(err)
+
+	for _, component := range Components {
+		suite.Require().NoError(writeSecrets(filepath.Join(suite.dir, component), secrets[component], os.Getuid()))
+	}
+
+	certs, err = issuedCertificates(suite.dir)
+	suite.Require().NoError(err)
+
+	sources := make([]string, 0, len(certs))
+
+	for _, cert := range certs {
+		suite.Assert().False(cert.Certificate.IsCA)
+
+		sources = append(sources, cert.Source)
+	}
+
+	sort.Strings(sources)
+
+	suite.Assert().Equal([]string{
+		filepath.Join(suite.dir, KubeAPIServer, "apiserver-kubelet-client.crt"),
+		filepath.Join(suite.dir, KubeAPIServer, "apiserver.crt"),
+		filepath.Join(suite.dir, KubeAPIServer, "etcd-client.crt"),
+		filepath.Join(suite.dir, KubeAPIServer, "front-proxy-client.crt"),
+		filepath.Join(suite.dir, KubeControllerManager, "kubeconfig"),
+		filepath.Join(suite.dir, KubeScheduler, "kubeconfig"),
+	}, sources)
+
+	now := time.Now()
+
+	suite.Assert().False(NeedsRotation(certs, now))
+	suite.Assert().True(NeedsRotation(certs, now.Add(constants.KubernetesControlPlaneCertLifetime-constants.KubernetesControlPlaneCertRenewBefore)))
+}
+
+func TestControlPlaneSuite(t *testing.T) {
+	suite.Run(t, new(ControlPlaneSuite))
+}
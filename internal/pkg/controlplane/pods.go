@@ -0,0 +1,347 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package controlplane
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/talos-systems/talos/pkg/argsbuilder"
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// SecretsVersionAnnotation is the annotation of the static pod which holds the version of the secrets.
+const SecretsVersionAnnotation = "talos.dev/secrets-version"
+
+const (
+	secretsMountPath = "/etc/kubernetes/secrets"
+	sslCertsPath     = "/etc/ssl/certs"
+
+	admissionPlugins = "NamespaceLifecycle,LimitRanger,ServiceAccount,PersistentVolumeClaimResize,DefaultStorageClass," +
+		"DefaultTolerationSeconds,MutatingAdmissionWebhook,ValidatingAdmissionWebhook,ResourceQuota,Priority,NodeRestriction"
+
+	tlsCipherSuites = "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256," +
+		"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305," +
+		"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,TLS_RSA_WITH_AES_256_GCM_SHA384,TLS_RSA_WITH_AES_128_GCM_SHA256"
+)
+
+func secretPath(name string) string {
+	return filepath.Join(secretsMountPath, name)
+}
+
+func staticPods(config config.ClusterConfig, secretsDir string, secrets secrets) ([]*corev1.Pod, error) {
+	apiServer, err := apiServerArgs(config)
+	if err != nil {
+		return nil, err
+	}
+
+	controllerManager, err := controllerManagerArgs(config)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduler, err := schedulerArgs(config)
+	if err != nil {
+		return nil, err
+	}
+
+	apiServerPod := staticPod(KubeAPIServer, config.APIServer().Image(), apiServer, secretsDir, secrets.version(KubeAPIServer))
+	apiServerPod.Spec.Containers[0].Env = []corev1.EnvVar{
+		{
+			Name: "POD_IP",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "status.podIP",
+				},
+			},
+		},
+	}
+
+	controllerManagerPod := staticPod(KubeControllerManager, config.ControllerManager().Image(), controllerManager, secretsDir, secrets.version(KubeControllerManager))
+	controllerManagerPod.Spec.Containers[0].LivenessProbe = healthzProbe(10252)
+
+	schedulerPod := staticPod(KubeScheduler, config.Scheduler().Image(), scheduler, secretsDir, secrets.version(KubeScheduler))
+	schedulerPod.Spec.Containers[0].LivenessProbe = healthzProbe(10251)
+
+	return []*corev1.Pod{apiServerPod, controllerManagerPod, schedulerPod}, nil
+}
+
+func staticPod(component, image string, args []string, secretsDir, secretsVersion string) *corev1.Pod {
+	runAsNonRoot := true
+	runAsUser := int64(constants.KubernetesControlPlaneRunUser)
+
+	return &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Pod",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      component,
+			Namespace: "kube-system",
+			Labels: map[string]string{
+				"tier":    "control-plane",
+				"k8s-app": component,
+			},
+			Annotations: map[string]string{
+				SecretsVersionAnnotation: secretsVersion,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:    component,
+					Image:   image,
+					Command: append([]string{"/go-runner", "/usr/local/bin/" + component}, args...),
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      "secrets",
+							MountPath: secretsMountPath,
+							ReadOnly:  true,
+						},
+						{
+							Name:      "ssl-certs-host",
+							MountPath: sslCertsPath,
+							ReadOnly:  true,
+						},
+					},
+				},
+			},
+			HostNetwork:       true,
+			PriorityClassName: "system-cluster-critical",
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot: &runAsNonRoot,
+				RunAsUser:    &runAsUser,
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "secrets",
+					VolumeSource: corev1.VolumeSource{
+						HostPath: &corev1.HostPathVolumeSource{
+							Path: filepath.Join(secretsDir, component),
+						},
+					},
+				},
+				{
+					Name: "ssl-certs-host",
+					VolumeSource: corev1.VolumeSource{
+						HostPath: &corev1.HostPathVolumeSource{
+							Path: sslCertsPath,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func healthzProbe(port int) *corev1.Probe {
+	return &corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/healthz",
+				Port: intstr.FromInt(port),
+			},
+		},
+		InitialDelaySeconds: 15,
+		TimeoutSeconds:      15,
+	}
+}
+
+func apiServerArgs(config config.ClusterConfig) ([]string, error) {
+	enabledAdmissionPlugins := admissionPlugins
+	if !config.APIServer().DisablePodSecurityPolicy() {
+		enabledAdmissionPlugins = "PodSecurityPolicy," + enabledAdmissionPlugins
+	}
+
+	args := argsbuilder.Args{
+		"enable-admission-plugins":           enabledAdmissionPlugins,
+		"advertise-address":                  "$(POD_IP)",
+		"allow-privileged":                   "true",
+		"anonymous-auth":                     "false",
+		"authorization-mode":                 "Node,RBAC",
+		"bind-address":                       "0.0.0.0",
+		"client-ca-file":                     secretPath("ca.crt"),
+		"requestheader-client-ca-file":       secretPath("front-proxy-ca.crt"),
+		"requestheader-allowed-names":        "front-proxy-client",
+		"requestheader-extra-headers-prefix": "X-Remote-Extra-",
+		"requestheader-group-headers":        "X-Remote-Group",
+		"requestheader-username-headers":     "X-Remote-User",
+		"proxy-client-cert-file":             secretPath("front-proxy-client.crt"),
+		"proxy-client-key-file":              secretPath("front-proxy-client.key"),
+		"enable-bootstrap-token-auth":        "true",
+		"tls-cipher-suites":                  tlsCipherSuites,
+		"encryption-provider-config":         secretPath("encryptionconfig.yaml"),
+		"audit-policy-file":                  secretPath("auditpolicy.yaml"),
+		"audit-log-path":                     "-",
+		"audit-log-maxage":                   "30",
+		"audit-log-maxbackup":                "3",
+		"audit-log-maxsize":                  "50",
+		"profiling":                          "false",
+		"etcd-cafile":                        secretPath("etcd-client-ca.crt"),
+		"etcd-certfile":                      secretPath("etcd-client.crt"),
+		"etcd-keyfile":                       secretPath("etcd-client.key"),
+		"etcd-servers":                       "https://127.0.0.1:2379",
+		"insecure-port":                      "0",
+		"kubelet-client-certificate":         secretPath("apiserver-kubelet-client.crt"),
+		"kubelet-client-key":                 secretPath("apiserver-kubelet-client.key"),
+		"secure-port":                        strconv.Itoa(config.LocalAPIServerPort()),
+		"service-account-key-file":           secretPath("service-account.pub"),
+		"service-account-signing-key-file":   secretPath("service-account.key"),
+		"service-cluster-ip-range":           config.Network().ServiceCIDR(),
+		"tls-cert-file":                      secretPath("apiserver.crt"),
+		"tls-private-key-file":               secretPath("apiserver.key"),
+		"kubelet-preferred-address-types":    "InternalIP,ExternalIP,Hostname",
+	}
+
+	args.Merge(ServiceAccountArgs(config))
+
+	denyList := []string{
+		"etcd-servers",
+		"client-ca-file",
+		"requestheader-client-ca-file",
+		"proxy-client-cert-file",
+		"proxy-client-key-file",
+		"etcd-cafile",
+		"etcd-certfile",
+		"etcd-keyfile",
+		"kubelet-client-certificate",
+		"kubelet-client-key",
+		"secure-port",
+		"service-account-key-file",
+		"service-account-signing-key-file",
+		"tls-cert-file",
+		"tls-private-key-file",
+	}
+
+	if config.ExternalCloudProvider().Enabled() {
+		args["cloud-provider"] = "external"
+
+		denyList = append(denyList, "cloud-provider")
+	}
+
+	return mergeArgs(args, config.APIServer().ExtraArgs(), denyList)
+}
+
+// ServiceAccountArgs returns the API server args for the service account token issuer and audiences.
+//
+// The issuer defaults to the control plane endpoint, and the issuer is always accepted as an audience.
+func ServiceAccountArgs(config config.ClusterConfig) argsbuilder.Args {
+	issuer := config.APIServer().ServiceAccountIssuer()
+	if issuer == "" {
+		issuer = config.Endpoint().String()
+	}
+
+	args := argsbuilder.Args{
+		"service-account-issuer": issuer,
+		"api-audiences":          strings.Join(append([]string{issuer}, config.APIServer().ServiceAccountExtraAudiences()...), ","),
+	}
+
+	if jwksURI := config.APIServer().ServiceAccountJWKSURI(); jwksURI != "" {
+		args["service-account-jwks-uri"] = jwksURI
+	}
+
+	return args
+}
+
+func controllerManagerArgs(config config.ClusterConfig) ([]string, error) {
+	args := argsbuilder.Args{
+		"use-service-account-credentials":  "true",
+		"allocate-node-cidrs":              "true",
+		"cluster-cidr":                     config.Network().PodCIDR(),
+		"service-cluster-ip-range":         config.Network().ServiceCIDR(),
+		"cluster-signing-cert-file":        secretPath("ca.crt"),
+		"cluster-signing-key-file":         secretPath("ca.key"),
+		"configure-cloud-routes":           "false",
+		"kubeconfig":                       secretPath("kubeconfig"),
+		"authentication-kubeconfig":        secretPath("kubeconfig"),
+		"authorization-kubeconfig":         secretPath("kubeconfig"),
+		"leader-elect":                     "true",
+		"root-ca-file":                     secretPath("ca.crt"),
+		"service-account-private-key-file": secretPath("service-account.key"),
+		"profiling":                        "false",
+	}
+
+	denyList := []string{
+		"kubeconfig",
+		"authentication-kubeconfig",
+		"authorization-kubeconfig",
+		"cluster-signing-cert-file",
+		"cluster-signing-key-file",
+		"root-ca-file",
+		"service-account-private-key-file",
+	}
+
+	if config.ExternalCloudProvider().Enabled() {
+		args["cloud-provider"] = "external"
+
+		denyList = append(denyList, "cloud-provider")
+	}
+
+	return mergeArgs(args, config.ControllerManager().ExtraArgs(), denyList)
+}
+
+func schedulerArgs(config config.ClusterConfig) ([]string, error) {
+	args := argsbuilder.Args{
+		"authentication-kubeconfig": secretPath("kubeconfig"),
+		"authorization-kubeconfig":  secretPath("kubeconfig"),
+	}
+
+	// component config replaces the flags, see schedulerConfig
+	if config.Scheduler().Config() != nil {
+		args["config"] = secretPath("config.yaml")
+	} else {
+		args.Merge(argsbuilder.Args{
+			"kubeconfig":   secretPath("kubeconfig"),
+			"leader-elect": "true",
+			"profiling":    "false",
+		})
+	}
+
+	return mergeArgs(args, config.Scheduler().ExtraArgs(), []string{
+		"config",
+		"kubeconfig",
+		"authentication-kubeconfig",
+		"authorization-kubeconfig",
+	})
+}
+
+// mergeArgs applies extra args on top of the defaults, args pointing to the secrets can't be overridden.
+//
+// Args are sorted to keep the manifest stable across renders.
+func mergeArgs(args argsbuilder.Args, extraArgs map[string]string, denyList []string) ([]string, error) {
+	for _, k := range denyList {
+		if _, ok := extraArgs[k]; ok {
+			return nil, argsbuilder.NewDenylistError(k)
+		}
+	}
+
+	args.Merge(extraArgs)
+
+	result := args.Args()
+	sort.Strings(result)
+
+	return result, nil
+}
+
+func newSerializer() *json.Serializer {
+	return json.NewSerializerWithOptions(
+		json.DefaultMetaFactory,
+		nil,
+		nil,
+		json.SerializerOptions{
+			Yaml:   true,
+			Pretty: true,
+			Strict: true,
+		},
+	)
+}
@@ -0,0 +1,318 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package controlplane
+
+import (
+	"bytes"
+	"crypto/sha256"
+	stdlibx509 "crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/talos-systems/crypto/x509"
+	tnet "github.com/talos-systems/net"
+	"gopkg.in/yaml.v3"
+
+	"github.com/talos-systems/talos/internal/pkg/kubeconfig"
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// secrets is a set of files per component: component -> file name -> contents.
+type secrets map[string]map[string][]byte
+
+// version returns the hash of the component secrets.
+//
+// Certificates are re-issued on each render, so the hash changes and the pods get restarted
+// to pick up the new secrets.
+func (s secrets) version(component string) string {
+	files := s[component]
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	h := sha256.New()
+
+	for _, name := range names {
+		h.Write([]byte(name)) //nolint: errcheck
+		h.Write(files[name])  //nolint: errcheck
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+const encryptionConfigTemplate = `apiVersion: v1
+kind: EncryptionConfig
+resources:
+- resources:
+  - secrets
+  providers:
+  - aescbc:
+      keys:
+      - name: key1
+        secret: {{ .AESCBCEncryptionSecret }}
+  - identity: {}
+`
+
+const auditPolicy = `apiVersion: audit.k8s.io/v1beta1
+kind: Policy
+rules:
+- level: Metadata
+`
+
+// nolint: gocyclo
+func generateSecrets(config config.ClusterConfig) (secrets, error) {
+	k8sCA, err := config.CA().GetCert()
+	if err != nil {
+		return nil, fmt.Errorf("error getting Kubernetes CA certificate: %w", err)
+	}
+
+	k8sKey, err := config.CA().GetRSAKey()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Kubernetes key: %w", err)
+	}
+
+	aggregatorCA, err := config.AggregatorCA().GetCert()
+	if err != nil {
+		return nil, fmt.Errorf("error getting aggregator CA certificate: %w", err)
+	}
+
+	aggregatorKey, err := config.AggregatorCA().GetRSAKey()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing aggregator CA key: %w", err)
+	}
+
+	etcdCA, err := config.Etcd().CA().GetCert()
+	if err != nil {
+		return nil, fmt.Errorf("error getting etcd CA certificate: %w", err)
+	}
+
+	etcdKey, err := config.Etcd().CA().GetRSAKey()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing etcd CA key: %w", err)
+	}
+
+	serviceAccountPub, err := publicKeyPEM(config.ServiceAccountKey())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing service account key: %w", err)
+	}
+
+	notAfter := x509.NotAfter(time.Now().Add(constants.KubernetesControlPlaneCertLifetime))
+
+	dnsNames, ips, err := apiServerSANs(config)
+	if err != nil {
+		return nil, err
+	}
+
+	apiServer, err := x509.NewCertficateAndKey(k8sCA, k8sKey,
+		x509.RSA(true),
+		x509.CommonName("kube-apiserver"),
+		x509.Organization("kube-master"),
+		x509.DNSNames(dnsNames),
+		x509.IPAddresses(ips),
+		notAfter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error generating API server certificate: %w", err)
+	}
+
+	kubeletClient, err := x509.NewCertficateAndKey(k8sCA, k8sKey,
+		x509.RSA(true),
+		x509.CommonName(constants.KubernetesAdminCertCommonName),
+		x509.Organization(constants.KubernetesAdminCertOrganization),
+		notAfter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error generating API server kubelet client certificate: %w", err)
+	}
+
+	frontProxy, err := x509.NewCertficateAndKey(aggregatorCA, aggregatorKey,
+		x509.RSA(true),
+		x509.CommonName("front-proxy-client"),
+		notAfter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error generating front proxy client certificate: %w", err)
+	}
+
+	etcdClient, err := x509.NewCertficateAndKey(etcdCA, etcdKey,
+		x509.RSA(true),
+		x509.CommonName("kube-apiserver"),
+		x509.Organization("etcd"),
+		notAfter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error generating etcd client certificate: %w", err)
+	}
+
+	tpl, err := template.New("encryptionconfig").Parse(encryptionConfigTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var encryptionConfig bytes.Buffer
+
+	if err = tpl.Execute(&encryptionConfig, struct{ AESCBCEncryptionSecret string }{config.AESCBCEncryptionSecret()}); err != nil {
+		return nil, fmt.Errorf("error rendering encryption config: %w", err)
+	}
+
+	var controllerManagerKubeconfig, schedulerKubeconfig bytes.Buffer
+
+	if err = kubeconfig.GenerateLocal(config, "system:"+KubeControllerManager, &controllerManagerKubeconfig); err != nil {
+		return nil, fmt.Errorf("error generating controller manager kubeconfig: %w", err)
+	}
+
+	if err = kubeconfig.GenerateLocal(config, "system:"+KubeScheduler, &schedulerKubeconfig); err != nil {
+		return nil, fmt.Errorf("error generating scheduler kubeconfig: %w", err)
+	}
+
+	result := secrets{
+		KubeAPIServer: {
+			"ca.crt":                       config.CA().Crt,
+			"apiserver.crt":                apiServer.Crt,
+			"apiserver.key":                apiServer.Key,
+			"apiserver-kubelet-client.crt": kubeletClient.Crt,
+			"apiserver-kubelet-client.key": kubeletClient.Key,
+			"front-proxy-ca.crt":           config.AggregatorCA().Crt,
+			"front-proxy-client.crt":       frontProxy.Crt,
+			"front-proxy-client.key":       frontProxy.Key,
+			"etcd-client-ca.crt":           config.Etcd().CA().Crt,
+			"etcd-client.crt":              etcdClient.Crt,
+			"etcd-client.key":              etcdClient.Key,
+			"service-account.pub":          serviceAccountPub,
+			"service-account.key":          config.ServiceAccountKey(),
+			"encryptionconfig.yaml":        encryptionConfig.Bytes(),
+			"auditpolicy.yaml":             []byte(auditPolicy),
+		},
+		KubeControllerManager: {
+			"ca.crt":              config.CA().Crt,
+			"ca.key":              config.CA().Key,
+			"service-account.key": config.ServiceAccountKey(),
+			"kubeconfig":          controllerManagerKubeconfig.Bytes(),
+		},
+		KubeScheduler: {
+			"kubeconfig": schedulerKubeconfig.Bytes(),
+		},
+	}
+
+	for name, contents := range config.ControllerManager().ExtraConfig() {
+		if _, ok := result[KubeControllerManager][name]; ok {
+			return nil, fmt.Errorf("controller manager extra config %q conflicts with the generated secrets", name)
+		}
+
+		result[KubeControllerManager][name] = []byte(contents)
+	}
+
+	if config.Scheduler().Config() != nil {
+		result[KubeScheduler]["config.yaml"], err = schedulerConfig(config)
+		if err != nil {
+			return nil, fmt.Errorf("error generating scheduler config: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// schedulerConfig renders the scheduler component config from the machine configuration.
+//
+// Flags are ignored by the scheduler once the config file is specified, so the settings
+// passed as flags otherwise are set in the component config.
+func schedulerConfig(config config.ClusterConfig) ([]byte, error) {
+	cfg := map[string]interface{}{
+		"apiVersion":      "kubescheduler.config.k8s.io/v1beta1",
+		"kind":            "KubeSchedulerConfiguration",
+		"enableProfiling": false,
+	}
+
+	for k, v := range config.Scheduler().Config() {
+		cfg[k] = v
+	}
+
+	clientConnection := map[string]interface{}{}
+
+	if existing, ok := cfg["clientConnection"].(map[string]interface{}); ok {
+		for k, v := range existing {
+			clientConnection[k] = v
+		}
+	}
+
+	clientConnection["kubeconfig"] = secretPath("kubeconfig")
+	cfg["clientConnection"] = clientConnection
+
+	return yaml.Marshal(cfg)
+}
+
+func apiServerSANs(config config.ClusterConfig) (dnsNames []string, ips []net.IP, err error) {
+	for _, san := range append([]string{config.Endpoint().Hostname()}, config.CertSANs()...) {
+		if ip := net.ParseIP(san); ip != nil {
+			ips = append(ips, ip)
+		} else {
+			dnsNames = append(dnsNames, san)
+		}
+	}
+
+	for _, serviceCIDR := range strings.Split(config.Network().ServiceCIDR(), ",") {
+		var cidr *net.IPNet
+
+		_, cidr, err = net.ParseCIDR(serviceCIDR)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %q as a CIDR: %w", serviceCIDR, err)
+		}
+
+		var ip net.IP
+
+		ip, err = tnet.NthIPInNetwork(cidr, 1)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to calculate API service IP: %w", err)
+		}
+
+		ips = append(ips, ip)
+	}
+
+	dnsNames = append(dnsNames,
+		"localhost",
+		"kubernetes",
+		"kubernetes.default",
+		"kubernetes.default.svc",
+		"kubernetes.default.svc."+config.Network().DNSDomain(),
+	)
+
+	ips = append(ips, net.ParseIP("127.0.0.1"), net.ParseIP("::1"))
+
+	return dnsNames, ips, nil
+}
+
+func publicKeyPEM(keyPEM []byte) ([]byte, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM encoded key")
+	}
+
+	key, err := stdlibx509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := stdlibx509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: der,
+	}), nil
+}
@@ -0,0 +1,207 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package csrapprover approves kubelet serving certificate signing requests of the Talos nodes.
+//
+// Kubernetes doesn't approve kubelet serving certificates automatically, as the node could request
+// a certificate for any address. Requests are approved only if the node is a known cluster member,
+// and the certificate subject alternative names match the hostname and the addresses published
+// by the node via the cluster discovery.
+package csrapprover
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/talos-systems/talos/internal/pkg/discovery"
+)
+
+const (
+	nodeUserPrefix = "system:node:"
+	nodesGroup     = "system:nodes"
+)
+
+var allowedUsages = map[certificatesv1.KeyUsage]bool{
+	certificatesv1.UsageDigitalSignature: true,
+	certificatesv1.UsageKeyEncipherment:  true,
+	certificatesv1.UsageServerAuth:       true,
+}
+
+// Verify checks that the kubelet serving certificate signing request matches the identity of the cluster member.
+//
+//nolint: gocyclo
+func Verify(csr *certificatesv1.CertificateSigningRequest, members []*discovery.Member) error {
+	if csr.Spec.SignerName != certificatesv1.KubeletServingSignerName {
+		return fmt.Errorf("unexpected signer %q", csr.Spec.SignerName)
+	}
+
+	if !strings.HasPrefix(csr.Spec.Username, nodeUserPrefix) {
+		return fmt.Errorf("request is not submitted by a node: %q", csr.Spec.Username)
+	}
+
+	nodeName := strings.TrimPrefix(csr.Spec.Username, nodeUserPrefix)
+
+	if !contains(csr.Spec.Groups, nodesGroup) {
+		return fmt.Errorf("requestor is not in the %q group", nodesGroup)
+	}
+
+	hasServerAuth := false
+
+	for _, usage := range csr.Spec.Usages {
+		if !allowedUsages[usage] {
+			return fmt.Errorf("usage %q is not allowed", usage)
+		}
+
+		hasServerAuth = hasServerAuth || usage == certificatesv1.UsageServerAuth
+	}
+
+	if !hasServerAuth {
+		return fmt.Errorf("usage %q is missing", certificatesv1.UsageServerAuth)
+	}
+
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return errors.New("failed to decode PEM encoded certificate request")
+	}
+
+	req, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate request: %w", err)
+	}
+
+	if req.Subject.CommonName != csr.Spec.Username {
+		return fmt.Errorf("common name %q doesn't match the requestor", req.Subject.CommonName)
+	}
+
+	if len(req.Subject.Organization) != 1 || req.Subject.Organization[0] != nodesGroup {
+		return fmt.Errorf("organization %q is not allowed", req.Subject.Organization)
+	}
+
+	if len(req.EmailAddresses) > 0 || len(req.URIs) > 0 {
+		return errors.New("email and URI subject alternative names are not allowed")
+	}
+
+	var member *discovery.Member
+
+	for _, m := range members {
+		if strings.EqualFold(m.Hostname, nodeName) {
+			member = m
+
+			break
+		}
+	}
+
+	if member == nil {
+		return fmt.Errorf("node %q is not a known cluster member", nodeName)
+	}
+
+	for _, name := range req.DNSNames {
+		if !strings.EqualFold(name, nodeName) && !strings.EqualFold(name, member.Hostname) {
+			return fmt.Errorf("DNS name %q doesn't match the node hostname", name)
+		}
+	}
+
+	for _, ip := range req.IPAddresses {
+		if !containsIP(member.Addresses, ip) {
+			return fmt.Errorf("address %s is not published by the node", ip)
+		}
+	}
+
+	return nil
+}
+
+// Result describes the outcome of the approval run for a single certificate signing request.
+type Result struct {
+	Name     string
+	Approved bool
+	// Error is the reason the request wasn't approved.
+	Error error
+}
+
+// MembersFunc returns the list of the cluster members.
+type MembersFunc func(ctx context.Context) ([]*discovery.Member, error)
+
+// Run approves pending kubelet serving certificate signing requests which pass the verification.
+//
+// Cluster members are only fetched if there are pending requests. Requests which fail the verification
+// are not denied, as the node might not be published yet via the cluster discovery.
+func Run(ctx context.Context, client kubernetes.Interface, membersFunc MembersFunc) ([]Result, error) {
+	list, err := client.CertificatesV1().CertificateSigningRequests().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing certificate signing requests: %w", err)
+	}
+
+	var (
+		results []Result
+		members []*discovery.Member
+	)
+
+	for i := range list.Items {
+		csr := &list.Items[i]
+
+		if csr.Spec.SignerName != certificatesv1.KubeletServingSignerName || len(csr.Status.Conditions) > 0 {
+			continue
+		}
+
+		if members == nil {
+			if members, err = membersFunc(ctx); err != nil {
+				return nil, fmt.Errorf("error listing cluster members: %w", err)
+			}
+		}
+
+		result := Result{
+			Name: csr.Name,
+		}
+
+		if result.Error = Verify(csr, members); result.Error == nil {
+			csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+				Type:           certificatesv1.CertificateApproved,
+				Status:         corev1.ConditionTrue,
+				Reason:         "TalosNodeApproved",
+				Message:        "kubelet serving certificate of the Talos node is approved",
+				LastUpdateTime: metav1.Now(),
+			})
+
+			if _, err = client.CertificatesV1().CertificateSigningRequests().UpdateApproval(ctx, csr.Name, csr, metav1.UpdateOptions{}); err != nil {
+				result.Error = fmt.Errorf("error approving certificate signing request: %w", err)
+			} else {
+				result.Approved = true
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsIP(addresses []string, ip net.IP) bool {
+	for _, address := range addresses {
+		if parsed := net.ParseIP(address); parsed != nil && parsed.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,128 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package csrapprover_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/talos-systems/talos/internal/pkg/csrapprover"
+	"github.com/talos-systems/talos/internal/pkg/discovery"
+)
+
+var members = []*discovery.Member{
+	{
+		ID:        "a",
+		Hostname:  "node-a",
+		Addresses: []string{"172.20.0.2", "10.5.0.2"},
+	},
+}
+
+func newCSR(t *testing.T, name, nodeName string, ips ...string) *certificatesv1.CertificateSigningRequest {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   "system:node:" + nodeName,
+			Organization: []string{"system:nodes"},
+		},
+		DNSNames: []string{nodeName},
+	}
+
+	for _, ip := range ips {
+		template.IPAddresses = append(template.IPAddresses, net.ParseIP(ip))
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	require.NoError(t, err)
+
+	return &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}),
+			SignerName: certificatesv1.KubeletServingSignerName,
+			Usages: []certificatesv1.KeyUsage{
+				certificatesv1.UsageDigitalSignature,
+				certificatesv1.UsageKeyEncipherment,
+				certificatesv1.UsageServerAuth,
+			},
+			Username: "system:node:" + nodeName,
+			Groups:   []string{"system:nodes", "system:authenticated"},
+		},
+	}
+}
+
+func TestVerify(t *testing.T) {
+	assert.NoError(t, csrapprover.Verify(newCSR(t, "csr", "node-a", "10.5.0.2"), members))
+
+	assert.EqualError(t, csrapprover.Verify(newCSR(t, "csr", "node-a", "10.5.0.3"), members),
+		"address 10.5.0.3 is not published by the node")
+	assert.EqualError(t, csrapprover.Verify(newCSR(t, "csr", "node-b", "10.5.0.2"), members),
+		`node "node-b" is not a known cluster member`)
+
+	csr := newCSR(t, "csr", "node-a", "10.5.0.2")
+	csr.Spec.Username = "system:node:node-b"
+	assert.EqualError(t, csrapprover.Verify(csr, members), `common name "system:node:node-a" doesn't match the requestor`)
+
+	csr = newCSR(t, "csr", "node-a", "10.5.0.2")
+	csr.Spec.Usages = append(csr.Spec.Usages, certificatesv1.UsageClientAuth)
+	assert.EqualError(t, csrapprover.Verify(csr, members), `usage "client auth" is not allowed`)
+
+	csr = newCSR(t, "csr", "node-a", "10.5.0.2")
+	csr.Spec.Groups = []string{"system:authenticated"}
+	assert.EqualError(t, csrapprover.Verify(csr, members), `requestor is not in the "system:nodes" group`)
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+
+	approved := newCSR(t, "approved", "node-a", "10.5.0.2")
+	approved.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{
+		{Type: certificatesv1.CertificateApproved},
+	}
+
+	client := fake.NewSimpleClientset(
+		newCSR(t, "valid", "node-a", "172.20.0.2", "10.5.0.2"),
+		newCSR(t, "unknown", "node-b", "10.5.0.3"),
+		approved,
+	)
+
+	results, err := csrapprover.Run(ctx, client, func(context.Context) ([]*discovery.Member, error) {
+		return members, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	for _, result := range results {
+		switch result.Name {
+		case "valid":
+			assert.True(t, result.Approved)
+			assert.NoError(t, result.Error)
+		case "unknown":
+			assert.False(t, result.Approved)
+			assert.Error(t, result.Error)
+		default:
+			t.Fatalf("unexpected result %q", result.Name)
+		}
+	}
+
+	csr, err := client.CertificatesV1().CertificateSigningRequests().Get(ctx, "valid", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, csr.Status.Conditions, 1)
+	assert.Equal(t, certificatesv1.CertificateApproved, csr.Status.Conditions[0].Type)
+}
@@ -0,0 +1,227 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package discovery implements cluster membership discovery.
+//
+// Each node publishes its identity and addresses to the registries enabled in the
+// machine configuration, and the list of cluster members is built by merging the data
+// found in all the registries.
+package discovery
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	talosnet "github.com/talos-systems/net"
+
+	"github.com/talos-systems/talos/pkg/machinery/config"
+)
+
+// Member describes a cluster member.
+type Member struct {
+	ID          string   `json:"id"`
+	Hostname    string   `json:"hostname"`
+	MachineType string   `json:"machineType"`
+	Addresses   []string `json:"addresses"`
+
+	// Registries is the list of the registries which reported the member.
+	Registries []string `json:"-"`
+}
+
+// Registry publishes and discovers cluster members.
+type Registry interface {
+	// Name returns the registry name.
+	Name() string
+	// Register publishes (or refreshes) the information about the member.
+	Register(ctx context.Context, member *Member) error
+	// Members returns the list of members found in the registry.
+	Members(ctx context.Context) ([]*Member, error)
+}
+
+// Registries returns the registries enabled in the machine configuration.
+func Registries(cfg config.Provider) []Registry {
+	var registries []Registry
+
+	discovery := cfg.Cluster().Discovery()
+
+	if discovery.Registries().Kubernetes().Enabled() {
+		registries = append(registries, &KubernetesRegistry{})
+	}
+
+	if discovery.Registries().Service().Enabled() {
+		registries = append(registries, &ServiceRegistry{
+			Endpoint:  discovery.Registries().Service().Endpoint(),
+			ClusterID: ClusterID(cfg),
+		})
+	}
+
+	return registries
+}
+
+// ClusterID returns the cluster identifier derived from the cluster CA certificate.
+//
+// Cluster ID is shared by all the members of the cluster, and it doesn't disclose any secrets.
+func ClusterID(cfg config.Provider) string {
+	var crt []byte
+
+	if ca := cfg.Cluster().CA(); ca != nil {
+		crt = ca.Crt
+	}
+
+	hash := sha256.Sum256(crt)
+
+	return hex.EncodeToString(hash[:])
+}
+
+// LocalMember returns the member information for the local node.
+func LocalMember(cfg config.Provider, id string) (*Member, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	ips, err := talosnet.IPAddrs()
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]string, 0, len(ips))
+
+	for _, ip := range ips {
+		addresses = append(addresses, ip.String())
+	}
+
+	return &Member{
+		ID:          id,
+		Hostname:    hostname,
+		MachineType: cfg.Machine().Type().String(),
+		Addresses:   addresses,
+	}, nil
+}
+
+// Identity returns the node identity stored at the path.
+//
+// If there's no identity yet, new random identity is generated and stored.
+func Identity(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err == nil {
+		return strings.TrimSpace(string(b)), nil
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("error reading node identity: %w", err)
+	}
+
+	buf := make([]byte, 16)
+
+	if _, err = rand.Read(buf); err != nil {
+		return "", fmt.Errorf("error generating node identity: %w", err)
+	}
+
+	id := hex.EncodeToString(buf)
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", err
+	}
+
+	if err = ioutil.WriteFile(path, []byte(id), 0o600); err != nil {
+		return "", fmt.Errorf("error storing node identity: %w", err)
+	}
+
+	return id, nil
+}
+
+// Register publishes the member information in all the registries.
+func Register(ctx context.Context, member *Member, registries ...Registry) error {
+	var result *multierror.Error
+
+	for _, registry := range registries {
+		if err := registry.Register(ctx, member); err != nil {
+			result = multierror.Append(result, fmt.Errorf("error registering in %q registry: %w", registry.Name(), err))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// Members returns the cluster members found in all the registries.
+//
+// Members are merged by the node identity. Errors from the registries are returned along
+// with the members found in the registries which responded.
+func Members(ctx context.Context, registries ...Registry) ([]*Member, error) {
+	var result *multierror.Error
+
+	members := map[string]*Member{}
+
+	for _, registry := range registries {
+		found, err := registry.Members(ctx)
+		if err != nil {
+			result = multierror.Append(result, fmt.Errorf("error listing %q registry: %w", registry.Name(), err))
+
+			continue
+		}
+
+		for _, member := range found {
+			merged, ok := members[member.ID]
+			if !ok {
+				merged = &Member{
+					ID:          member.ID,
+					Hostname:    member.Hostname,
+					MachineType: member.MachineType,
+				}
+
+				members[member.ID] = merged
+			}
+
+			merged.Addresses = mergeAddresses(merged.Addresses, member.Addresses)
+			merged.Registries = append(merged.Registries, registry.Name())
+		}
+	}
+
+	list := make([]*Member, 0, len(members))
+
+	for _, member := range members {
+		list = append(list, member)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Hostname != list[j].Hostname {
+			return list[i].Hostname < list[j].Hostname
+		}
+
+		return list[i].ID < list[j].ID
+	})
+
+	return list, result.ErrorOrNil()
+}
+
+func mergeAddresses(addresses, other []string) []string {
+	for _, addr := range other {
+		found := false
+
+		for _, existing := range addresses {
+			if existing == addr {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			addresses = append(addresses, addr)
+		}
+	}
+
+	return addresses
+}
@@ -0,0 +1,187 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package discovery_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/talos-systems/talos/internal/pkg/discovery"
+)
+
+// fakeService implements the discovery service protocol in memory.
+type fakeService struct {
+	mu      sync.Mutex
+	members map[string]map[string]*discovery.Member
+}
+
+func (s *fakeService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// /v1/clusters/<cluster>/members[/<member>]
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 4 || parts[0] != "v1" || parts[1] != "clusters" || parts[3] != "members" {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	cluster := parts[2]
+
+	switch {
+	case r.Method == http.MethodPut && len(parts) == 5:
+		var member discovery.Member
+
+		if err := json.NewDecoder(r.Body).Decode(&member); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if s.members[cluster] == nil {
+			s.members[cluster] = map[string]*discovery.Member{}
+		}
+
+		s.members[cluster][parts[4]] = &member
+	case r.Method == http.MethodGet && len(parts) == 4:
+		resp := struct {
+			Members []*discovery.Member `json:"members"`
+		}{}
+
+		for _, member := range s.members[cluster] {
+			resp.Members = append(resp.Members, member)
+		}
+
+		json.NewEncoder(w).Encode(resp) //nolint: errcheck
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestServiceRegistry(t *testing.T) {
+	srv := httptest.NewServer(&fakeService{members: map[string]map[string]*discovery.Member{}})
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	registry := &discovery.ServiceRegistry{Endpoint: srv.URL + "/", ClusterID: "cluster1"}
+	otherCluster := &discovery.ServiceRegistry{Endpoint: srv.URL, ClusterID: "cluster2"}
+
+	require.NoError(t, registry.Register(ctx, &discovery.Member{ID: "a", Hostname: "node-a", MachineType: "init", Addresses: []string{"10.5.0.2"}}))
+	require.NoError(t, registry.Register(ctx, &discovery.Member{ID: "b", Hostname: "node-b", MachineType: "join", Addresses: []string{"10.5.0.3"}}))
+	require.NoError(t, otherCluster.Register(ctx, &discovery.Member{ID: "c", Hostname: "node-c", MachineType: "join"}))
+
+	members, err := discovery.Members(ctx, registry)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+
+	assert.Equal(t, "node-a", members[0].Hostname)
+	assert.Equal(t, []string{"10.5.0.2"}, members[0].Addresses)
+	assert.Equal(t, []string{"service"}, members[0].Registries)
+	assert.Equal(t, "node-b", members[1].Hostname)
+	assert.Equal(t, "join", members[1].MachineType)
+}
+
+func TestKubernetesRegistry(t *testing.T) {
+	ctx := context.Background()
+
+	client := fake.NewSimpleClientset(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+			Status: corev1.NodeStatus{
+				Addresses: []corev1.NodeAddress{
+					{Type: corev1.NodeInternalIP, Address: "10.5.0.2"},
+					{Type: corev1.NodeHostName, Address: "node-a"},
+				},
+			},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-b"},
+		},
+	)
+
+	registry := &discovery.KubernetesRegistry{Client: client}
+
+	require.NoError(t, registry.Register(ctx, &discovery.Member{ID: "a", Hostname: "node-a", MachineType: "init", Addresses: []string{"172.20.0.2", "10.5.0.2"}}))
+
+	// node-b is not registered, so it's skipped
+	members, err := registry.Members(ctx)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+
+	assert.Equal(t, "a", members[0].ID)
+	assert.Equal(t, "node-a", members[0].Hostname)
+	assert.Equal(t, "init", members[0].MachineType)
+	assert.Equal(t, []string{"172.20.0.2", "10.5.0.2"}, members[0].Addresses)
+}
+
+type staticRegistry struct {
+	name    string
+	members []*discovery.Member
+}
+
+func (r *staticRegistry) Name() string { return r.name }
+
+func (r *staticRegistry) Register(context.Context, *discovery.Member) error { return nil }
+
+func (r *staticRegistry) Members(context.Context) ([]*discovery.Member, error) { return r.members, nil }
+
+func TestMembersMerge(t *testing.T) {
+	members, err := discovery.Members(context.Background(),
+		&staticRegistry{
+			name: "one",
+			members: []*discovery.Member{
+				{ID: "b", Hostname: "node-b", Addresses: []string{"10.5.0.3"}},
+				{ID: "a", Hostname: "node-a", Addresses: []string{"10.5.0.2"}},
+			},
+		},
+		&staticRegistry{
+			name: "two",
+			members: []*discovery.Member{
+				{ID: "a", Hostname: "node-a", Addresses: []string{"10.5.0.2", "192.168.0.2"}},
+			},
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+
+	assert.Equal(t, "a", members[0].ID)
+	assert.Equal(t, []string{"10.5.0.2", "192.168.0.2"}, members[0].Addresses)
+	assert.Equal(t, []string{"one", "two"}, members[0].Registries)
+	assert.Equal(t, "b", members[1].ID)
+	assert.Equal(t, []string{"one"}, members[1].Registries)
+}
+
+func TestIdentity(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	path := filepath.Join(dir, "node-identity")
+
+	id, err := discovery.Identity(path)
+	require.NoError(t, err)
+	assert.Len(t, id, 32)
+
+	id2, err := discovery.Identity(path)
+	require.NoError(t, err)
+	assert.Equal(t, id, id2)
+}
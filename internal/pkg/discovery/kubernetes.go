@@ -0,0 +1,122 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	k8s "github.com/talos-systems/talos/pkg/kubernetes"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// KubernetesRegistry stores the member information as annotations on the Kubernetes Node resources.
+//
+// Kubelet credentials are used to access the Kubernetes API, so the registry is available only
+// once the node is registered in Kubernetes.
+type KubernetesRegistry struct {
+	// Client overrides the default client built from the kubelet kubeconfig.
+	Client kubernetes.Interface
+}
+
+// Name implements the Registry interface.
+func (r *KubernetesRegistry) Name() string {
+	return "kubernetes"
+}
+
+func (r *KubernetesRegistry) client() (kubernetes.Interface, error) {
+	if r.Client != nil {
+		return r.Client, nil
+	}
+
+	client, err := k8s.NewClientFromKubeletKubeconfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// Register implements the Registry interface.
+//
+// Node name is expected to match the hostname.
+func (r *KubernetesRegistry) Register(ctx context.Context, member *Member) error {
+	client, err := r.client()
+	if err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				constants.AnnotationNodeID:          member.ID,
+				constants.AnnotationNodeMachineType: member.MachineType,
+				constants.AnnotationNodeAddresses:   strings.Join(member.Addresses, ","),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = client.CoreV1().Nodes().Patch(ctx, member.Hostname, types.MergePatchType, patch, metav1.PatchOptions{})
+
+	return err
+}
+
+// Members implements the Registry interface.
+//
+// Nodes which don't have Talos annotations are skipped.
+func (r *KubernetesRegistry) Members(ctx context.Context) ([]*Member, error) {
+	client, err := r.client()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]*Member, 0, len(nodes.Items))
+
+	for _, node := range nodes.Items {
+		id, ok := node.Annotations[constants.AnnotationNodeID]
+		if !ok {
+			continue
+		}
+
+		members = append(members, &Member{
+			ID:          id,
+			Hostname:    node.Name,
+			MachineType: node.Annotations[constants.AnnotationNodeMachineType],
+			Addresses:   nodeAddresses(&node),
+		})
+	}
+
+	return members, nil
+}
+
+func nodeAddresses(node *corev1.Node) []string {
+	var addresses []string
+
+	if annotation := node.Annotations[constants.AnnotationNodeAddresses]; annotation != "" {
+		addresses = strings.Split(annotation, ",")
+	}
+
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP || address.Type == corev1.NodeExternalIP {
+			addresses = mergeAddresses(addresses, []string{address.Address})
+		}
+	}
+
+	return addresses
+}
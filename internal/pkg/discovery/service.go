@@ -0,0 +1,125 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+)
+
+// ServiceRegistry publishes the member information to the external discovery service.
+//
+// Members are registered with `PUT <endpoint>/v1/clusters/<cluster ID>/members/<node ID>`,
+// and the list of members is fetched with `GET <endpoint>/v1/clusters/<cluster ID>/members`.
+// Discovery service is expected to expire the members which weren't refreshed for a while.
+type ServiceRegistry struct {
+	Endpoint  string
+	ClusterID string
+
+	// Client overrides the default HTTP client.
+	Client *http.Client
+}
+
+type serviceMembersResponse struct {
+	Members []*Member `json:"members"`
+}
+
+// Name implements the Registry interface.
+func (r *ServiceRegistry) Name() string {
+	return "service"
+}
+
+func (r *ServiceRegistry) url(elem ...string) (string, error) {
+	u, err := url.Parse(r.Endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	u.Path = path.Join(append([]string{u.Path, "v1", "clusters", url.PathEscape(r.ClusterID), "members"}, elem...)...)
+
+	return u.String(), nil
+}
+
+func (r *ServiceRegistry) do(req *http.Request) ([]byte, error) {
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint: errcheck
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected response from discovery service: %s", resp.Status)
+	}
+
+	return body, nil
+}
+
+// Register implements the Registry interface.
+func (r *ServiceRegistry) Register(ctx context.Context, member *Member) error {
+	u, err := r.url(url.PathEscape(member.ID))
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(member)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = r.do(req)
+
+	return err
+}
+
+// Members implements the Registry interface.
+func (r *ServiceRegistry) Members(ctx context.Context) ([]*Member, error) {
+	u, err := r.url()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := r.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp serviceMembersResponse
+
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("error decoding discovery service response: %w", err)
+	}
+
+	return resp.Members, nil
+}
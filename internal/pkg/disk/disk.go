@@ -0,0 +1,294 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package disk discovers the disks of the machine.
+//
+// Disks are listed from sysfs. NVMe namespaces are listed once (per-controller paths of the multipath
+// namespaces are hidden), the paths to the same FC, SAS or iSCSI LUN are grouped into a single disk,
+// and the assembled device-mapper multipath devices are listed instead of their paths.
+package disk
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SysfsPath is the path to the sysfs mount.
+var SysfsPath = "/sys"
+
+// Disk transports.
+const (
+	TransportNVMe   = "nvme"
+	TransportFC     = "fc"
+	TransportSAS    = "sas"
+	TransportISCSI  = "iscsi"
+	TransportVirtio = "virtio"
+	TransportUSB    = "usb"
+	TransportATA    = "ata"
+	TransportMMC    = "mmc"
+)
+
+// multipathTransports are the transports which might have several paths to the same LUN.
+var multipathTransports = map[string]bool{
+	TransportFC:    true,
+	TransportSAS:   true,
+	TransportISCSI: true,
+}
+
+// skipPrefixes are the block devices which are not disks.
+var skipPrefixes = []string{"sg", "sr", "loop", "md", "ram", "zram", "nbd"}
+
+// nvmePathRegexp matches the per-controller paths of the NVMe multipath namespace (e.g. `nvme0c1n1`).
+var nvmePathRegexp = regexp.MustCompile(`^nvme\d+c\d+n\d+$`)
+
+// Disk describes a disk.
+type Disk struct {
+	// DeviceName is the device to be used for the disk (e.g. `/dev/sda`, `/dev/nvme0n1` or `/dev/mapper/mpatha`).
+	DeviceName string
+	// Size is the disk size in bytes.
+	Size uint64
+	// Model is the disk model.
+	Model string
+	// Serial is the disk serial number.
+	Serial string
+	// WWID is the World Wide Identifier of the disk (SCSI VPD page 0x83 or NVMe namespace identifier).
+	WWID string
+	// Transport is the bus the disk is attached to (e.g. `nvme` or `fc`).
+	Transport string
+	// Namespace is the NVMe namespace ID.
+	Namespace uint32
+	// Paths are the devices of all the paths to the multipath disk, it is empty for the single path disks.
+	Paths []string
+}
+
+// List returns the disks of the machine.
+func List() ([]*Disk, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(SysfsPath, "block"))
+	if err != nil {
+		return nil, err
+	}
+
+	disks := []*Disk{}
+	byWWID := map[string]*Disk{}
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if skip(name) {
+			continue
+		}
+
+		if strings.HasPrefix(name, "dm-") {
+			if isMultipath(name) {
+				disks = append(disks, readMultipath(name))
+			}
+
+			continue
+		}
+
+		// the path is listed as part of the assembled multipath device
+		if MultipathHolder(name) != "" {
+			continue
+		}
+
+		disk, err := readDisk(name)
+		if err != nil {
+			continue
+		}
+
+		if disk.WWID != "" && multipathTransports[disk.Transport] {
+			if d, ok := byWWID[disk.WWID]; ok {
+				d.Paths = append(d.Paths, disk.DeviceName)
+
+				continue
+			}
+
+			disk.Paths = []string{disk.DeviceName}
+			byWWID[disk.WWID] = disk
+		}
+
+		disks = append(disks, disk)
+	}
+
+	for _, disk := range disks {
+		if len(disk.Paths) == 1 {
+			disk.Paths = nil
+		}
+	}
+
+	return disks, nil
+}
+
+// MultipathHolder returns the device-mapper multipath device (e.g. `/dev/mapper/mpatha`) which uses the disk as the path.
+//
+// Empty string is returned if the disk is not a path of the assembled multipath device.
+func MultipathHolder(device string) string {
+	name := filepath.Base(device)
+
+	holders, err := ioutil.ReadDir(filepath.Join(SysfsPath, "block", name, "holders"))
+	if err != nil {
+		return ""
+	}
+
+	for _, holder := range holders {
+		if isMultipath(holder.Name()) {
+			return mapperName(holder.Name())
+		}
+	}
+
+	return ""
+}
+
+func skip(name string) bool {
+	for _, prefix := range skipPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return nvmePathRegexp.MatchString(name)
+}
+
+func isMultipath(name string) bool {
+	uuid, err := readString(filepath.Join(SysfsPath, "block", name, "dm", "uuid"))
+
+	return err == nil && strings.HasPrefix(uuid, "mpath-")
+}
+
+func mapperName(name string) string {
+	dmName, err := readString(filepath.Join(SysfsPath, "block", name, "dm", "name"))
+	if err != nil {
+		return filepath.Join("/dev", name)
+	}
+
+	return filepath.Join("/dev/mapper", dmName)
+}
+
+func readDisk(name string) (*Disk, error) {
+	dir := filepath.Join(SysfsPath, "block", name)
+
+	size, err := readSize(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	disk := &Disk{
+		DeviceName: filepath.Join("/dev", name),
+		Size:       size,
+		Transport:  transport(dir),
+	}
+
+	// nolint: errcheck
+	disk.Model, _ = readString(filepath.Join(dir, "device", "model"))
+	// nolint: errcheck
+	disk.Serial, _ = readString(filepath.Join(dir, "device", "serial"))
+
+	if disk.Transport == TransportNVMe {
+		// nolint: errcheck
+		disk.WWID, _ = readString(filepath.Join(dir, "wwid"))
+
+		if nsid, err := readString(filepath.Join(dir, "nsid")); err == nil {
+			if n, err := strconv.ParseUint(nsid, 10, 32); err == nil {
+				disk.Namespace = uint32(n)
+			}
+		}
+	} else {
+		// nolint: errcheck
+		disk.WWID, _ = readString(filepath.Join(dir, "device", "wwid"))
+	}
+
+	return disk, nil
+}
+
+func readMultipath(name string) *Disk {
+	dir := filepath.Join(SysfsPath, "block", name)
+
+	disk := &Disk{
+		DeviceName: mapperName(name),
+	}
+
+	// nolint: errcheck
+	disk.Size, _ = readSize(dir)
+
+	uuid, _ := readString(filepath.Join(dir, "dm", "uuid")) //nolint: errcheck
+	disk.WWID = strings.TrimPrefix(uuid, "mpath-")
+
+	slaves, err := ioutil.ReadDir(filepath.Join(dir, "slaves"))
+	if err == nil {
+		for _, slave := range slaves {
+			disk.Paths = append(disk.Paths, filepath.Join("/dev", slave.Name()))
+		}
+
+		sort.Strings(disk.Paths)
+	}
+
+	if len(disk.Paths) > 0 {
+		if path, err := readDisk(filepath.Base(disk.Paths[0])); err == nil {
+			disk.Model = path.Model
+			disk.Serial = path.Serial
+			disk.Transport = path.Transport
+		}
+	}
+
+	return disk
+}
+
+// transport detects the disk transport from the sysfs device path.
+func transport(dir string) string {
+	path, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case strings.Contains(path, "/nvme"):
+		return TransportNVMe
+	case strings.Contains(path, "/rport-"):
+		return TransportFC
+	case strings.Contains(path, "/end_device-"):
+		return TransportSAS
+	case strings.Contains(path, "/session"):
+		return TransportISCSI
+	case strings.Contains(path, "/usb"):
+		return TransportUSB
+	case strings.Contains(path, "/ata"):
+		return TransportATA
+	case strings.Contains(path, "/mmc"):
+		return TransportMMC
+	case strings.Contains(path, "/virtio"):
+		return TransportVirtio
+	default:
+		return ""
+	}
+}
+
+// readSize returns the size of the block device in bytes.
+//
+// sysfs reports the size in 512-byte sectors regardless of the logical block size.
+func readSize(dir string) (uint64, error) {
+	s, err := readString(filepath.Join(dir, "size"))
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return size * 512, nil
+}
+
+func readString(path string) (string, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(contents)), nil
+}
@@ -0,0 +1,145 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package disk_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/disk"
+)
+
+// addBlockDevice creates the device in the sysfs devices tree and links it to /sys/block.
+func addBlockDevice(t *testing.T, devpath string, files map[string]string) {
+	dir := filepath.Join(disk.SysfsPath, devpath)
+
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(contents+"\n"), 0o644))
+	}
+
+	require.NoError(t, os.MkdirAll(filepath.Join(disk.SysfsPath, "block"), 0o755))
+	require.NoError(t, os.Symlink(dir, filepath.Join(disk.SysfsPath, "block", filepath.Base(devpath))))
+}
+
+func link(t *testing.T, from, to string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(disk.SysfsPath, from)), 0o755))
+	require.NoError(t, os.Symlink(filepath.Join(disk.SysfsPath, to), filepath.Join(disk.SysfsPath, from)))
+}
+
+func TestList(t *testing.T) {
+	oldPath := disk.SysfsPath
+	disk.SysfsPath = t.TempDir()
+
+	defer func() { disk.SysfsPath = oldPath }()
+
+	// NVMe multipath namespace: nvme0n1 is the namespace, nvme0c0n1 and nvme0c1n1 are the hidden paths
+	addBlockDevice(t, "devices/virtual/nvme-subsystem/nvme-subsys0/nvme0n1", map[string]string{
+		"size":         "2097152",
+		"wwid":         "eui.0025388b71b0a5d2",
+		"nsid":         "1",
+		"device/model": "Samsung SSD 970 EVO",
+	})
+	addBlockDevice(t, "devices/pci0000:00/0000:00:01.0/nvme/nvme0/nvme0c0n1", map[string]string{"size": "2097152"})
+	addBlockDevice(t, "devices/pci0000:00/0000:00:02.0/nvme/nvme1/nvme0c1n1", map[string]string{"size": "2097152"})
+
+	// FC LUN with two paths, without the multipath device
+	for _, path := range []string{
+		"devices/pci0000:00/0000:00:03.0/host1/rport-1:0-0/target1:0:0/1:0:0:1/block/sdb",
+		"devices/pci0000:00/0000:00:03.1/host2/rport-2:0-0/target2:0:0/2:0:0:1/block/sdc",
+	} {
+		addBlockDevice(t, path, map[string]string{
+			"size":         "4194304",
+			"device/model": "LUN C-Mode",
+			"device/wwid":  "naa.600a098038303053",
+		})
+	}
+
+	// SAS LUN with two paths assembled into the multipath device
+	for _, path := range []string{
+		"devices/pci0000:00/0000:00:04.0/host3/port-3:0/end_device-3:0/target3:0:0/3:0:0:0/block/sdd",
+		"devices/pci0000:00/0000:00:04.0/host3/port-3:1/end_device-3:1/target3:0:1/3:0:1:0/block/sde",
+	} {
+		addBlockDevice(t, path, map[string]string{
+			"size":         "8388608",
+			"device/model": "MSA 2040 SAS",
+			"device/wwid":  "naa.600c0ff0001e3d2b",
+		})
+	}
+
+	addBlockDevice(t, "devices/virtual/block/dm-0", map[string]string{
+		"size":    "8388608",
+		"dm/name": "mpatha",
+		"dm/uuid": "mpath-3600c0ff0001e3d2b",
+	})
+
+	for _, path := range []string{"sdd", "sde"} {
+		link(t, filepath.Join("block", path, "holders", "dm-0"), "block/dm-0")
+		link(t, filepath.Join("block", "dm-0", "slaves", path), filepath.Join("block", path))
+	}
+
+	// LVM volume is not a disk
+	addBlockDevice(t, "devices/virtual/block/dm-1", map[string]string{
+		"size":    "1024",
+		"dm/name": "vg0-lv0",
+		"dm/uuid": "LVM-abcdef",
+	})
+
+	// single path SATA disk, and devices which are not disks
+	addBlockDevice(t, "devices/pci0000:00/0000:00:1f.2/ata1/host0/target0:0:0/0:0:0:0/block/sda", map[string]string{
+		"size":         "1048576",
+		"device/model": "INTEL SSDSC2KB48",
+		"device/wwid":  "t10.ATA     INTEL SSDSC2KB48",
+	})
+	addBlockDevice(t, "devices/virtual/block/loop0", map[string]string{"size": "0"})
+	addBlockDevice(t, "devices/pci0000:00/0000:00:1f.2/ata2/host4/target4:0:0/4:0:0:0/block/sr0", map[string]string{"size": "0"})
+
+	disks, err := disk.List()
+	require.NoError(t, err)
+
+	assert.Equal(t, []*disk.Disk{
+		{
+			DeviceName: "/dev/mapper/mpatha",
+			Size:       4 << 30,
+			Model:      "MSA 2040 SAS",
+			WWID:       "3600c0ff0001e3d2b",
+			Transport:  disk.TransportSAS,
+			Paths:      []string{"/dev/sdd", "/dev/sde"},
+		},
+		{
+			DeviceName: "/dev/nvme0n1",
+			Size:       1 << 30,
+			Model:      "Samsung SSD 970 EVO",
+			WWID:       "eui.0025388b71b0a5d2",
+			Transport:  disk.TransportNVMe,
+			Namespace:  1,
+		},
+		{
+			DeviceName: "/dev/sda",
+			Size:       512 << 20,
+			Model:      "INTEL SSDSC2KB48",
+			WWID:       "t10.ATA     INTEL SSDSC2KB48",
+			Transport:  disk.TransportATA,
+		},
+		{
+			DeviceName: "/dev/sdb",
+			Size:       2 << 30,
+			Model:      "LUN C-Mode",
+			WWID:       "naa.600a098038303053",
+			Transport:  disk.TransportFC,
+			Paths:      []string{"/dev/sdb", "/dev/sdc"},
+		},
+	}, disks)
+
+	assert.Equal(t, "/dev/mapper/mpatha", disk.MultipathHolder("/dev/sdd"))
+	assert.Empty(t, disk.MultipathHolder("/dev/sdb"))
+}
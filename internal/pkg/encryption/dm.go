@@ -0,0 +1,235 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package encryption
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// createCryptDevice maps the encrypted partition, it is replaced in the tests.
+var createCryptDevice = dmCreateCrypt
+
+// removeDevice removes the mapping, it is replaced in the tests.
+var removeDevice = dmRemove
+
+const dmControl = "/dev/mapper/control"
+
+// Device-mapper ioctls are _IOWR(0xfd, nr, struct dm_ioctl).
+const (
+	// dmDevCreate is DM_DEV_CREATE.
+	dmDevCreate = 0xc138fd03
+	// dmDevRemove is DM_DEV_REMOVE.
+	dmDevRemove = 0xc138fd04
+	// dmDevSuspend is DM_DEV_SUSPEND, it resumes the device if DM_SUSPEND_FLAG is not set.
+	dmDevSuspend = 0xc138fd06
+	// dmTableLoad is DM_TABLE_LOAD.
+	dmTableLoad = 0xc138fd09
+)
+
+// dmSecureDataFlag is DM_SECURE_DATA_FLAG: the kernel wipes the buffers holding the table (and the key).
+const dmSecureDataFlag = 1 << 15
+
+// blkGetSize64 is BLKGETSIZE64: _IOR(0x12, 114, size_t).
+const blkGetSize64 = 0x80081272
+
+// dmIoctl is struct dm_ioctl from linux/dm-ioctl.h.
+type dmIoctl struct {
+	version     [3]uint32
+	dataSize    uint32
+	dataStart   uint32
+	targetCount uint32
+	openCount   int32
+	flags       uint32
+	eventNr     uint32
+	padding     uint32
+	dev         uint64
+	name        [128]byte
+	uuid        [129]byte
+	data        [7]byte
+}
+
+// dmTargetSpec is struct dm_target_spec from linux/dm-ioctl.h.
+type dmTargetSpec struct {
+	sectorStart uint64
+	length      uint64
+	status      int32
+	next        uint32
+	targetType  [16]byte
+}
+
+func newDMIoctl(name string, flags uint32) (*dmIoctl, error) {
+	io := &dmIoctl{
+		version:   [3]uint32{4, 0, 0},
+		dataSize:  uint32(unsafe.Sizeof(dmIoctl{})),
+		dataStart: uint32(unsafe.Sizeof(dmIoctl{})),
+		flags:     flags,
+	}
+
+	if len(name) >= len(io.name) {
+		return nil, fmt.Errorf("device name %q is too long", name)
+	}
+
+	copy(io.name[:], name)
+
+	return io, nil
+}
+
+func dmCall(req uintptr, buf []byte) error {
+	f, err := os.OpenFile(dmControl, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer f.Close()
+
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), req, uintptr(unsafe.Pointer(&buf[0]))); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+func dmCallIoctl(req uintptr, io *dmIoctl) error {
+	buf := (*[unsafe.Sizeof(dmIoctl{})]byte)(unsafe.Pointer(io))[:]
+
+	return dmCall(req, buf)
+}
+
+// dmCreateCrypt creates the dm-crypt device `name` on top of the partition.
+func dmCreateCrypt(name, path string, header *Header, volumeKey []byte) error {
+	size, err := blockDeviceSize(path)
+	if err != nil {
+		return err
+	}
+
+	sectors := size / SectorSize
+
+	if sectors <= header.DataOffset {
+		return fmt.Errorf("partition is too small: %d sectors", sectors)
+	}
+
+	io, err := newDMIoctl(name, 0)
+	if err != nil {
+		return err
+	}
+
+	if err = dmCallIoctl(dmDevCreate, io); err != nil {
+		return fmt.Errorf("error creating device: %w", err)
+	}
+
+	dev := io.dev
+
+	if err = dmLoadCryptTable(name, path, header, sectors-header.DataOffset, volumeKey); err != nil {
+		dmRemove(name) //nolint: errcheck
+
+		return fmt.Errorf("error loading table: %w", err)
+	}
+
+	if io, err = newDMIoctl(name, 0); err != nil {
+		return err
+	}
+
+	if err = dmCallIoctl(dmDevSuspend, io); err != nil {
+		dmRemove(name) //nolint: errcheck
+
+		return fmt.Errorf("error resuming device: %w", err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(MappedPath(name)), 0o755); err != nil {
+		return err
+	}
+
+	err = unix.Mknod(MappedPath(name), unix.S_IFBLK|0o600, int(unix.Mkdev(unix.Major(dev), unix.Minor(dev))))
+	if err != nil && err != unix.EEXIST {
+		return fmt.Errorf("error creating device node: %w", err)
+	}
+
+	return nil
+}
+
+func dmLoadCryptTable(name, path string, header *Header, length uint64, volumeKey []byte) error {
+	io, err := newDMIoctl(name, dmSecureDataFlag)
+	if err != nil {
+		return err
+	}
+
+	key := make([]byte, hex.EncodedLen(len(volumeKey)))
+	hex.Encode(key, volumeKey)
+
+	defer zero(key)
+
+	// <cipher> <key> <iv_offset> <device path> <offset>
+	params := bytes.Join([][]byte{
+		[]byte(header.Cipher),
+		key,
+		[]byte("0"),
+		[]byte(path),
+		[]byte(fmt.Sprintf("%d", header.DataOffset)),
+	}, []byte(" "))
+
+	defer zero(params)
+
+	spec := dmTargetSpec{
+		length: length,
+	}
+
+	copy(spec.targetType[:], "crypt")
+
+	specSize := int(unsafe.Sizeof(spec))
+	// parameters are NUL-terminated, the next target spec (if any) is aligned to 8 bytes
+	specSize += (len(params) + 1 + 7) &^ 7
+
+	spec.next = uint32(specSize)
+
+	io.targetCount = 1
+	io.dataSize += uint32(specSize)
+
+	// buffer is allocated once, so that no copies of the key are left behind
+	buf := make([]byte, io.dataSize)
+
+	defer zero(buf)
+
+	n := copy(buf, (*[unsafe.Sizeof(dmIoctl{})]byte)(unsafe.Pointer(io))[:])
+	n += copy(buf[n:], (*[unsafe.Sizeof(spec)]byte)(unsafe.Pointer(&spec))[:])
+	copy(buf[n:], params)
+
+	return dmCall(dmTableLoad, buf)
+}
+
+// dmRemove removes the device-mapper device.
+func dmRemove(name string) error {
+	io, err := newDMIoctl(name, 0)
+	if err != nil {
+		return err
+	}
+
+	return dmCallIoctl(dmDevRemove, io)
+}
+
+func blockDeviceSize(path string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+
+	//nolint: errcheck
+	defer f.Close()
+
+	var size uint64
+
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), blkGetSize64, uintptr(unsafe.Pointer(&size))); errno != 0 {
+		return 0, fmt.Errorf("error getting size of %q: %w", path, errno)
+	}
+
+	return size, nil
+}
@@ -0,0 +1,326 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package encryption implements the system partition encryption with dm-crypt.
+//
+// Encrypted partition starts with the header which holds the key slots: the random volume key
+// is stored in each slot protected by the key provider (e.g. sealed to the TPM PCR values).
+// The data follows the header, it is mapped with dm-crypt as `/dev/mapper/<name>`.
+package encryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Magic identifies the header of the encrypted partition.
+var Magic = []byte("TALOSENC")
+
+// HeaderSize is the size of the area reserved for the header, the data starts right after it.
+const HeaderSize = 1024 * 1024
+
+// SectorSize is the dm-crypt sector size.
+const SectorSize = 512
+
+// Cipher is the dm-crypt cipher of the encrypted partitions.
+const Cipher = "aes-xts-plain64"
+
+// KeySize is the size of the volume key (AES-256 in XTS mode).
+const KeySize = 64
+
+const headerVersion = 1
+
+// ErrNotEncrypted is returned when the partition doesn't have the encryption header.
+var ErrNotEncrypted = errors.New("partition is not encrypted")
+
+// Header is the metadata of the encrypted partition.
+type Header struct {
+	Cipher     string  `json:"cipher"`
+	KeySize    int     `json:"keySize"`
+	DataOffset uint64  `json:"dataOffset"` // in sectors
+	Salt       []byte  `json:"salt"`
+	Digest     []byte  `json:"digest"` // SHA-256 of the salt and the volume key
+	Slots      []*Slot `json:"slots"`
+}
+
+// Slot is the volume key protected by the key provider.
+type Slot struct {
+	Slot int      `json:"slot"`
+	TPM  *TPMSlot `json:"tpm,omitempty"`
+}
+
+// Key provides the key slot of the encrypted partition.
+type Key interface {
+	// Slot returns the key slot number.
+	Slot() int
+	// Seal protects the volume key, the result is stored in the key slot.
+	Seal(volumeKey []byte) (*Slot, error)
+	// Unseal recovers the volume key from the key slot.
+	Unseal(slot *Slot) ([]byte, error)
+}
+
+// Format writes the header of the encrypted partition with the new random volume key
+// protected by each key.
+//
+// Previous contents of the partition become inaccessible.
+func Format(path string, keys []Key) error {
+	if len(keys) == 0 {
+		return errors.New("at least one key is required")
+	}
+
+	volumeKey := make([]byte, KeySize)
+
+	if _, err := io.ReadFull(rand.Reader, volumeKey); err != nil {
+		return err
+	}
+
+	defer zero(volumeKey)
+
+	salt := make([]byte, sha256.Size)
+
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return err
+	}
+
+	header := &Header{
+		Cipher:     Cipher,
+		KeySize:    KeySize,
+		DataOffset: HeaderSize / SectorSize,
+		Salt:       salt,
+		Digest:     keyDigest(salt, volumeKey),
+	}
+
+	slots := map[int]struct{}{}
+
+	for _, key := range keys {
+		if _, ok := slots[key.Slot()]; ok {
+			return fmt.Errorf("duplicate key slot %d", key.Slot())
+		}
+
+		slots[key.Slot()] = struct{}{}
+
+		slot, err := key.Seal(volumeKey)
+		if err != nil {
+			return fmt.Errorf("error sealing the key slot %d: %w", key.Slot(), err)
+		}
+
+		slot.Slot = key.Slot()
+
+		header.Slots = append(header.Slots, slot)
+	}
+
+	return WriteHeader(path, header)
+}
+
+// WriteHeader writes the header to the beginning of the partition.
+//
+// The whole header area is overwritten, so that the previous filesystem is not detected.
+func WriteHeader(path string, header *Header) error {
+	metadata, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	checksum := sha256.Sum256(metadata)
+
+	var buf bytes.Buffer
+
+	buf.Write(Magic)
+	binary.Write(&buf, binary.BigEndian, uint32(headerVersion)) //nolint: errcheck
+	binary.Write(&buf, binary.BigEndian, uint32(len(metadata))) //nolint: errcheck
+	buf.Write(metadata)
+	buf.Write(checksum[:])
+
+	if buf.Len() > HeaderSize {
+		return fmt.Errorf("header is too large: %d bytes", buf.Len())
+	}
+
+	buf.Write(make([]byte, HeaderSize-buf.Len()))
+
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer f.Close()
+
+	if _, err = f.WriteAt(buf.Bytes(), 0); err != nil {
+		return err
+	}
+
+	if err = f.Sync(); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// ReadHeader reads the header of the encrypted partition.
+//
+// ErrNotEncrypted is returned if the partition doesn't start with the header.
+func ReadHeader(path string) (*Header, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint: errcheck
+	defer f.Close()
+
+	prefix := make([]byte, len(Magic)+8)
+
+	if _, err = io.ReadFull(f, prefix); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrNotEncrypted
+		}
+
+		return nil, err
+	}
+
+	if !bytes.Equal(prefix[:len(Magic)], Magic) {
+		return nil, ErrNotEncrypted
+	}
+
+	version := binary.BigEndian.Uint32(prefix[len(Magic):])
+	size := binary.BigEndian.Uint32(prefix[len(Magic)+4:])
+
+	if version != headerVersion {
+		return nil, fmt.Errorf("unsupported encryption header version %d", version)
+	}
+
+	if int(size) > HeaderSize-len(prefix)-sha256.Size {
+		return nil, fmt.Errorf("encryption header is corrupted: metadata size %d", size)
+	}
+
+	buf := make([]byte, int(size)+sha256.Size)
+
+	if _, err = io.ReadFull(f, buf); err != nil {
+		return nil, err
+	}
+
+	metadata, checksum := buf[:size], buf[size:]
+
+	if expected := sha256.Sum256(metadata); !bytes.Equal(expected[:], checksum) {
+		return nil, errors.New("encryption header is corrupted: checksum mismatch")
+	}
+
+	var header Header
+
+	if err = json.Unmarshal(metadata, &header); err != nil {
+		return nil, fmt.Errorf("encryption header is corrupted: %w", err)
+	}
+
+	return &header, nil
+}
+
+// UnsealKey recovers the volume key from the first key slot which can be unsealed.
+func UnsealKey(header *Header, keys []Key) ([]byte, error) {
+	var result *multierror.Error
+
+	for _, key := range keys {
+		for _, slot := range header.Slots {
+			if slot.Slot != key.Slot() {
+				continue
+			}
+
+			volumeKey, err := key.Unseal(slot)
+			if err != nil {
+				result = multierror.Append(result, fmt.Errorf("key slot %d: %w", slot.Slot, err))
+
+				continue
+			}
+
+			if subtle.ConstantTimeCompare(keyDigest(header.Salt, volumeKey), header.Digest) != 1 {
+				zero(volumeKey)
+
+				result = multierror.Append(result, fmt.Errorf("key slot %d: volume key digest mismatch", slot.Slot))
+
+				continue
+			}
+
+			return volumeKey, nil
+		}
+	}
+
+	if result == nil {
+		return nil, errors.New("no key slots match the configured keys")
+	}
+
+	return nil, fmt.Errorf("failed to unseal the volume key: %w", result.ErrorOrNil())
+}
+
+// Open unlocks the encrypted partition and maps it as `/dev/mapper/<name>`.
+//
+// Open returns the path of the mapped device.
+func Open(path, name string, keys []Key) (string, error) {
+	header, err := ReadHeader(path)
+	if err != nil {
+		return "", err
+	}
+
+	volumeKey, err := UnsealKey(header, keys)
+	if err != nil {
+		return "", err
+	}
+
+	defer zero(volumeKey)
+
+	if err = createCryptDevice(name, path, header, volumeKey); err != nil {
+		return "", fmt.Errorf("error mapping %q: %w", path, err)
+	}
+
+	return MappedPath(name), nil
+}
+
+// Close removes the mapping of the encrypted partition.
+func Close(name string) error {
+	if err := removeDevice(name); err != nil {
+		return fmt.Errorf("error removing mapping %q: %w", name, err)
+	}
+
+	if err := os.Remove(MappedPath(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// MappedPath returns the path of the mapped device.
+func MappedPath(name string) string {
+	return filepath.Join("/dev/mapper", name)
+}
+
+// IsMapped checks whether the encrypted partition is mapped.
+func IsMapped(name string) bool {
+	_, err := os.Stat(MappedPath(name))
+
+	return err == nil
+}
+
+func keyDigest(salt, volumeKey []byte) []byte {
+	h := sha256.New()
+
+	h.Write(salt)      //nolint: errcheck
+	h.Write(volumeKey) //nolint: errcheck
+
+	return h.Sum(nil)
+}
+
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,211 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package encryption
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/tpm2"
+)
+
+// xorKey protects the volume key by XOR with the pad, it stands for the TPM in the tests.
+type xorKey struct {
+	slot int
+	pad  byte
+}
+
+func (k *xorKey) Slot() int {
+	return k.slot
+}
+
+func (k *xorKey) Seal(volumeKey []byte) (*Slot, error) {
+	return &Slot{
+		TPM: &TPMSlot{
+			Private: k.xor(volumeKey),
+		},
+	}, nil
+}
+
+func (k *xorKey) Unseal(slot *Slot) ([]byte, error) {
+	if k.pad == 0 {
+		return nil, errors.New("policy check failed")
+	}
+
+	return k.xor(slot.TPM.Private), nil
+}
+
+func (k *xorKey) xor(b []byte) []byte {
+	out := make([]byte, len(b))
+
+	for i := range b {
+		out[i] = b[i] ^ k.pad
+	}
+
+	return out
+}
+
+func partition(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "partition")
+
+	require.NoError(t, ioutil.WriteFile(path, bytes.Repeat([]byte{0xaa}, 2*HeaderSize), 0o600))
+
+	return path
+}
+
+func TestNotEncrypted(t *testing.T) {
+	_, err := ReadHeader(partition(t))
+	assert.True(t, errors.Is(err, ErrNotEncrypted))
+
+	empty := filepath.Join(t.TempDir(), "empty")
+	require.NoError(t, ioutil.WriteFile(empty, nil, 0o600))
+
+	_, err = ReadHeader(empty)
+	assert.True(t, errors.Is(err, ErrNotEncrypted))
+}
+
+func TestFormat(t *testing.T) {
+	path := partition(t)
+
+	require.NoError(t, Format(path, []Key{&xorKey{slot: 0, pad: 0x11}, &xorKey{slot: 1, pad: 0x22}}))
+
+	header, err := ReadHeader(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, Cipher, header.Cipher)
+	assert.Equal(t, KeySize, header.KeySize)
+	assert.EqualValues(t, HeaderSize/SectorSize, header.DataOffset)
+	require.Len(t, header.Slots, 2)
+	assert.Equal(t, 0, header.Slots[0].Slot)
+	assert.Equal(t, 1, header.Slots[1].Slot)
+
+	key0, err := UnsealKey(header, []Key{&xorKey{slot: 0, pad: 0x11}})
+	require.NoError(t, err)
+
+	key1, err := UnsealKey(header, []Key{&xorKey{slot: 1, pad: 0x22}})
+	require.NoError(t, err)
+
+	assert.Len(t, key0, KeySize)
+	assert.Equal(t, key0, key1)
+
+	// the whole header area is overwritten, the data is intact
+	contents, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, bytes.Repeat([]byte{0xaa}, HeaderSize), contents[HeaderSize:])
+	assert.NotContains(t, string(contents[:HeaderSize]), string([]byte{0xaa, 0xaa, 0xaa, 0xaa}))
+
+	// formatting again generates the new volume key
+	require.NoError(t, Format(path, []Key{&xorKey{slot: 0, pad: 0x11}}))
+
+	header, err = ReadHeader(path)
+	require.NoError(t, err)
+
+	key, err := UnsealKey(header, []Key{&xorKey{slot: 0, pad: 0x11}})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, key0, key)
+}
+
+func TestFormatDuplicateSlot(t *testing.T) {
+	assert.EqualError(t, Format(partition(t), []Key{&xorKey{slot: 1, pad: 1}, &xorKey{slot: 1, pad: 2}}), "duplicate key slot 1")
+	assert.EqualError(t, Format(partition(t), nil), "at least one key is required")
+}
+
+func TestUnsealKeyFailure(t *testing.T) {
+	path := partition(t)
+
+	require.NoError(t, Format(path, []Key{&xorKey{slot: 0, pad: 0x11}}))
+
+	header, err := ReadHeader(path)
+	require.NoError(t, err)
+
+	// wrong key
+	_, err = UnsealKey(header, []Key{&xorKey{slot: 0, pad: 0x12}})
+	assert.Contains(t, err.Error(), "key slot 0: volume key digest mismatch")
+
+	// unseal error
+	_, err = UnsealKey(header, []Key{&xorKey{slot: 0}})
+	assert.Contains(t, err.Error(), "key slot 0: policy check failed")
+
+	// no matching slots
+	_, err = UnsealKey(header, []Key{&xorKey{slot: 3, pad: 0x11}})
+	assert.EqualError(t, err, "no key slots match the configured keys")
+
+	// falls back to the next key
+	_, err = UnsealKey(header, []Key{&xorKey{slot: 0}, &xorKey{slot: 0, pad: 0x11}})
+	assert.NoError(t, err)
+}
+
+func TestReadHeaderCorrupted(t *testing.T) {
+	path := partition(t)
+
+	require.NoError(t, Format(path, []Key{&xorKey{slot: 0, pad: 0x11}}))
+
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	require.NoError(t, err)
+
+	_, err = f.WriteAt([]byte("X"), int64(len(Magic)+8+2))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	_, err = ReadHeader(path)
+	assert.EqualError(t, err, "encryption header is corrupted: checksum mismatch")
+}
+
+func TestOpen(t *testing.T) {
+	path := partition(t)
+
+	require.NoError(t, Format(path, []Key{&xorKey{slot: 0, pad: 0x11}}))
+
+	oldCreate := createCryptDevice
+
+	t.Cleanup(func() { createCryptDevice = oldCreate })
+
+	var mappedKey []byte
+
+	createCryptDevice = func(name, devPath string, header *Header, volumeKey []byte) error {
+		assert.Equal(t, "ephemeral", name)
+		assert.Equal(t, path, devPath)
+
+		mappedKey = append([]byte(nil), volumeKey...)
+
+		return nil
+	}
+
+	mapped, err := Open(path, "ephemeral", []Key{&xorKey{slot: 0, pad: 0x11}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/dev/mapper/ephemeral", mapped)
+	assert.Len(t, mappedKey, KeySize)
+
+	_, err = Open(path, "ephemeral", []Key{&xorKey{slot: 0, pad: 0x12}})
+	assert.Error(t, err)
+}
+
+func TestTPMKeyErrors(t *testing.T) {
+	key := NewTPMKey(2, []int{7})
+	key.open = func() (*tpm2.TPM, error) {
+		return nil, tpm2.ErrNotFound
+	}
+
+	assert.Equal(t, 2, key.Slot())
+
+	_, err := key.Seal([]byte("key"))
+	assert.True(t, errors.Is(err, tpm2.ErrNotFound))
+
+	_, err = key.Unseal(&Slot{Slot: 2, TPM: &TPMSlot{PCRs: []int{7}}})
+	assert.True(t, errors.Is(err, tpm2.ErrNotFound))
+
+	_, err = key.Unseal(&Slot{Slot: 2})
+	assert.EqualError(t, err, "key slot is not sealed to the TPM")
+}
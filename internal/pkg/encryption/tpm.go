@@ -0,0 +1,92 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package encryption
+
+import (
+	"errors"
+
+	"github.com/talos-systems/talos/internal/pkg/tpm2"
+)
+
+// TPMSlot is the volume key sealed to the TPM PCR values.
+type TPMSlot struct {
+	PCRs    []int  `json:"pcrs"`
+	Public  []byte `json:"public"`
+	Private []byte `json:"private"`
+}
+
+// TPMKey seals the volume key to the current values of the TPM PCRs.
+//
+// The volume key can be unsealed only by the same TPM and only if the PCRs have the same values,
+// e.g. PCR 7 changes when SecureBoot is disabled or the SecureBoot keys are changed.
+type TPMKey struct {
+	slot int
+	pcrs []int
+
+	// open is replaced in the tests.
+	open func() (*tpm2.TPM, error)
+}
+
+// NewTPMKey initializes and returns a TPMKey.
+func NewTPMKey(slot int, pcrs []int) *TPMKey {
+	return &TPMKey{
+		slot: slot,
+		pcrs: pcrs,
+		open: tpm2.Open,
+	}
+}
+
+// Slot implements the Key interface.
+func (k *TPMKey) Slot() int {
+	return k.slot
+}
+
+// Seal implements the Key interface.
+func (k *TPMKey) Seal(volumeKey []byte) (*Slot, error) {
+	t, err := k.open()
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint: errcheck
+	defer t.Close()
+
+	obj, err := t.Seal(k.pcrs, volumeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Slot{
+		TPM: &TPMSlot{
+			PCRs:    obj.PCRs,
+			Public:  obj.Public,
+			Private: obj.Private,
+		},
+	}, nil
+}
+
+// Unseal implements the Key interface.
+//
+// The volume key is unsealed with the PCRs recorded in the slot, so that the key can be unsealed
+// even if the configured PCRs were changed after the partition was encrypted.
+func (k *TPMKey) Unseal(slot *Slot) ([]byte, error) {
+	if slot.TPM == nil {
+		return nil, errors.New("key slot is not sealed to the TPM")
+	}
+
+	t, err := k.open()
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint: errcheck
+	defer t.Close()
+
+	return t.Unseal(&tpm2.SealedObject{
+		PCRs:    slot.TPM.PCRs,
+		Public:  slot.TPM.Public,
+		Private: slot.TPM.Private,
+	})
+}
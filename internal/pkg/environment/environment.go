@@ -0,0 +1,130 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package environment builds the environment variables of machined and the system services.
+package environment
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	tnet "github.com/talos-systems/net"
+
+	"github.com/talos-systems/talos/pkg/machinery/config"
+)
+
+// Get returns the environment variables from the machine config (`machine.env` and `machine.proxy`) in the `KEY=value` format.
+func Get(cfg config.Provider) []string {
+	env := []string{}
+
+	for key, val := range cfg.Machine().Env() {
+		env = append(env, fmt.Sprintf("%s=%s", key, val))
+	}
+
+	// node addresses are best effort, the cluster subnets cover most of the traffic
+	addrs, _ := tnet.IPAddrs() //nolint: errcheck
+
+	env = append(env, Proxy(cfg, addrs)...)
+
+	sort.Strings(env)
+
+	return env
+}
+
+// Proxy returns the proxy environment variables.
+//
+// Both the uppercase and the lowercase variables are set, as the tools disagree on which ones they read.
+func Proxy(cfg config.Provider, addrs []net.IP) []string {
+	proxy := cfg.Machine().Proxy()
+
+	if proxy.HTTPProxy() == "" && proxy.HTTPSProxy() == "" {
+		return nil
+	}
+
+	var env []string
+
+	set := func(key, val string) {
+		if val != "" {
+			env = append(env, fmt.Sprintf("%s=%s", strings.ToUpper(key), val), fmt.Sprintf("%s=%s", key, val))
+		}
+	}
+
+	set("http_proxy", proxy.HTTPProxy())
+	set("https_proxy", proxy.HTTPSProxy())
+	set("no_proxy", strings.Join(NoProxy(cfg, addrs), ","))
+
+	return env
+}
+
+// NoProxy returns the hosts, domains and subnets which bypass the proxy.
+//
+// The automatic entries are the loopback addresses, the cluster pod and service subnets and domains,
+// the control plane endpoint, the subnets of the static addresses and the node addresses.
+func NoProxy(cfg config.Provider, addrs []net.IP) []string {
+	var entries []string
+
+	seen := map[string]struct{}{}
+
+	add := func(entry string) {
+		entry = strings.TrimSpace(entry)
+
+		if _, ok := seen[entry]; ok || entry == "" {
+			return
+		}
+
+		seen[entry] = struct{}{}
+
+		entries = append(entries, entry)
+	}
+
+	for _, entry := range cfg.Machine().Proxy().NoProxy() {
+		add(entry)
+	}
+
+	if !cfg.Machine().Proxy().AutoNoProxy() {
+		return entries
+	}
+
+	for _, entry := range []string{"localhost", "127.0.0.1", "::1"} {
+		add(entry)
+	}
+
+	cluster := cfg.Cluster()
+
+	for _, cidr := range strings.Split(cluster.Network().PodCIDR(), ",") {
+		add(cidr)
+	}
+
+	for _, cidr := range strings.Split(cluster.Network().ServiceCIDR(), ",") {
+		add(cidr)
+	}
+
+	add(".svc")
+
+	if domain := cluster.Network().DNSDomain(); domain != "" {
+		add("." + domain)
+	}
+
+	if endpoint := cluster.Endpoint(); endpoint != nil {
+		add(endpoint.Hostname())
+	}
+
+	for _, device := range cfg.Machine().Network().Devices() {
+		if device.CIDR() == "" {
+			continue
+		}
+
+		if _, network, err := net.ParseCIDR(device.CIDR()); err == nil {
+			add(network.String())
+		}
+	}
+
+	for _, addr := range addrs {
+		add(addr.String())
+	}
+
+	return entries
+}
@@ -0,0 +1,83 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package environment_test
+
+import (
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/talos-systems/talos/internal/pkg/environment"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+)
+
+func newConfig(proxy *v1alpha1.MachineProxyConfig) *v1alpha1.Config {
+	endpoint, _ := url.Parse("https://cp.example.com:6443") //nolint: errcheck
+
+	return &v1alpha1.Config{
+		MachineConfig: &v1alpha1.MachineConfig{
+			MachineEnv: v1alpha1.Env{
+				"GRPC_GO_LOG_SEVERITY_LEVEL": "error",
+			},
+			MachineNetwork: &v1alpha1.NetworkConfig{
+				NetworkInterfaces: []*v1alpha1.Device{
+					{
+						DeviceInterface: "eth0",
+						DeviceCIDR:      "192.168.2.5/24",
+					},
+					{
+						DeviceInterface: "eth1",
+						DeviceDHCP:      true,
+					},
+				},
+			},
+			MachineProxy: proxy,
+		},
+		ClusterConfig: &v1alpha1.ClusterConfig{
+			ControlPlane: &v1alpha1.ControlPlaneConfig{
+				Endpoint: &v1alpha1.Endpoint{URL: endpoint},
+			},
+			ClusterNetwork: &v1alpha1.ClusterNetworkConfig{
+				DNSDomain:     "cluster.local",
+				PodSubnet:     []string{"10.244.0.0/16"},
+				ServiceSubnet: []string{"10.96.0.0/12"},
+			},
+		},
+	}
+}
+
+func TestGet(t *testing.T) {
+	assert.Equal(t, []string{"GRPC_GO_LOG_SEVERITY_LEVEL=error"}, environment.Get(newConfig(nil)))
+}
+
+func TestProxy(t *testing.T) {
+	cfg := newConfig(&v1alpha1.MachineProxyConfig{
+		ProxyHTTPSProxy: "http://proxy.example.com:3128",
+		ProxyNoProxy:    []string{".corp.example.com"},
+	})
+
+	noProxy := ".corp.example.com,localhost,127.0.0.1,::1,10.244.0.0/16,10.96.0.0/12,.svc,.cluster.local,cp.example.com,192.168.2.0/24,192.168.2.5"
+
+	assert.Equal(t, []string{
+		"HTTPS_PROXY=http://proxy.example.com:3128",
+		"https_proxy=http://proxy.example.com:3128",
+		"NO_PROXY=" + noProxy,
+		"no_proxy=" + noProxy,
+	}, environment.Proxy(cfg, []net.IP{net.ParseIP("192.168.2.5")}))
+}
+
+func TestNoProxy(t *testing.T) {
+	assert.Nil(t, environment.Proxy(newConfig(nil), nil))
+
+	cfg := newConfig(&v1alpha1.MachineProxyConfig{
+		ProxyHTTPProxy:          "http://proxy.example.com:3128",
+		ProxyNoProxy:            []string{"10.0.0.0/8", "10.0.0.0/8"},
+		ProxyDisableAutoNoProxy: true,
+	})
+
+	assert.Equal(t, []string{"10.0.0.0/8"}, environment.NoProxy(cfg, []net.IP{net.ParseIP("10.5.0.2")}))
+}
@@ -0,0 +1,250 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package maintenance implements the periodic etcd maintenance: defragmentation and alarm management.
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.etcd.io/etcd/clientv3"
+	"go.etcd.io/etcd/etcdserver/etcdserverpb"
+
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// Client is the subset of the etcd client API used by the maintenance.
+type Client interface {
+	MemberList(ctx context.Context) (*clientv3.MemberListResponse, error)
+	Status(ctx context.Context, endpoint string) (*clientv3.StatusResponse, error)
+	Defragment(ctx context.Context, endpoint string) (*clientv3.DefragmentResponse, error)
+	AlarmList(ctx context.Context) (*clientv3.AlarmResponse, error)
+	AlarmDisarm(ctx context.Context, m *clientv3.AlarmMember) (*clientv3.AlarmResponse, error)
+}
+
+// Settings are the thresholds which trigger the member defragmentation.
+type Settings struct {
+	// MinDBSize is the database size below which the member is not defragmented.
+	MinDBSize int64
+	// FragmentationThreshold is the percentage of the free space in the database which triggers the defragmentation.
+	FragmentationThreshold int
+}
+
+// NewSettings builds the maintenance settings from the machine configuration.
+func NewSettings(config config.EtcdMaintenance) Settings {
+	return Settings{
+		MinDBSize:              int64(config.MinDBSize()),
+		FragmentationThreshold: config.FragmentationThreshold(),
+	}
+}
+
+// NeedsDefragmentation returns true if the member database should be defragmented.
+func (s Settings) NeedsDefragmentation(dbSize, dbSizeInUse int64) bool {
+	if dbSize < s.MinDBSize || dbSize == 0 {
+		return false
+	}
+
+	return (dbSize-dbSizeInUse)*100 >= int64(s.FragmentationThreshold)*dbSize
+}
+
+// MemberStatus is the state of the etcd member as seen by the last maintenance run.
+type MemberStatus struct {
+	ID          uint64
+	Name        string
+	Leader      bool
+	DBSize      int64
+	DBSizeInUse int64
+	// LastDefragmented is the time of the last defragmentation performed by the maintenance.
+	LastDefragmented time.Time
+	Error            string
+}
+
+// Report is the result of the maintenance run.
+type Report struct {
+	LastRun time.Time
+	// Skipped is set if the node doesn't host the etcd leader, so the members were not defragmented.
+	Skipped bool
+	Members []MemberStatus
+	// Alarms are the alarms active after the run, e.g. `memberID:NOSPACE`.
+	Alarms []string
+	Error  string
+}
+
+// Status holds the report of the last maintenance run.
+type Status struct {
+	mu     sync.Mutex
+	report Report
+}
+
+// Report returns the last maintenance report.
+func (s *Status) Report() Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.report
+}
+
+// Update replaces the maintenance report.
+func (s *Status) Update(report Report) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.report = report
+}
+
+// Run performs a single maintenance pass.
+//
+// Only the member which hosts the leader does the maintenance: followers are defragmented first,
+// and the leader is defragmented last, as the defragmentation blocks the member for a while.
+// Members with the `NOSPACE` alarm are defragmented regardless of the thresholds, and the alarms
+// are cleared afterwards.
+//
+// The report is returned even if the maintenance failed, previous report is used to keep
+// the time of the last defragmentation.
+//
+//nolint: gocyclo
+func Run(ctx context.Context, client Client, settings Settings, previous Report) (Report, error) {
+	report := Report{
+		LastRun: time.Now(),
+	}
+
+	lastDefragmented := map[uint64]time.Time{}
+
+	for _, member := range previous.Members {
+		lastDefragmented[member.ID] = member.LastDefragmented
+	}
+
+	list, err := client.MemberList(ctx)
+	if err != nil {
+		return report, fmt.Errorf("error listing etcd members: %w", err)
+	}
+
+	alarms, err := client.AlarmList(ctx)
+	if err != nil {
+		return report, fmt.Errorf("error listing etcd alarms: %w", err)
+	}
+
+	noSpace := map[uint64]bool{}
+
+	for _, alarm := range alarms.Alarms {
+		if alarm.Alarm == etcdserverpb.AlarmType_NOSPACE {
+			noSpace[alarm.MemberID] = true
+		}
+	}
+
+	var (
+		leaderID  uint64
+		endpoints = map[uint64]string{}
+	)
+
+	for _, member := range list.Members {
+		status := MemberStatus{
+			ID:               member.ID,
+			Name:             member.Name,
+			LastDefragmented: lastDefragmented[member.ID],
+		}
+
+		if len(member.ClientURLs) == 0 {
+			// member was added, but not started yet
+			status.Error = "member has no client URLs"
+			report.Members = append(report.Members, status)
+
+			continue
+		}
+
+		endpoints[member.ID] = member.ClientURLs[0]
+
+		resp, err := client.Status(ctx, member.ClientURLs[0])
+		if err != nil {
+			status.Error = err.Error()
+		} else {
+			leaderID = resp.Leader
+			status.DBSize = resp.DbSize
+			status.DBSizeInUse = resp.DbSizeInUse
+		}
+
+		report.Members = append(report.Members, status)
+	}
+
+	localID := list.Header.GetMemberId()
+
+	if leaderID == 0 || leaderID != localID {
+		report.Skipped = true
+		report.Alarms = formatAlarms(alarms.Alarms)
+
+		return report, nil
+	}
+
+	for i := range report.Members {
+		report.Members[i].Leader = report.Members[i].ID == leaderID
+	}
+
+	// followers first, then the leader
+	for _, leader := range []bool{false, true} {
+		for i := range report.Members {
+			member := &report.Members[i]
+
+			if member.Leader != leader || member.Error != "" {
+				continue
+			}
+
+			if !noSpace[member.ID] && !settings.NeedsDefragmentation(member.DBSize, member.DBSizeInUse) {
+				continue
+			}
+
+			if err = defragment(ctx, client, endpoints[member.ID]); err != nil {
+				return report, fmt.Errorf("error defragmenting etcd member %q: %w", member.Name, err)
+			}
+
+			member.LastDefragmented = time.Now()
+
+			if resp, err := client.Status(ctx, endpoints[member.ID]); err == nil {
+				member.DBSize = resp.DbSize
+				member.DBSizeInUse = resp.DbSizeInUse
+			}
+		}
+	}
+
+	for _, alarm := range alarms.Alarms {
+		if alarm.Alarm != etcdserverpb.AlarmType_NOSPACE {
+			continue
+		}
+
+		if _, err = client.AlarmDisarm(ctx, (*clientv3.AlarmMember)(alarm)); err != nil {
+			return report, fmt.Errorf("error clearing etcd alarm: %w", err)
+		}
+	}
+
+	alarms, err = client.AlarmList(ctx)
+	if err != nil {
+		return report, fmt.Errorf("error listing etcd alarms: %w", err)
+	}
+
+	report.Alarms = formatAlarms(alarms.Alarms)
+
+	return report, nil
+}
+
+func defragment(ctx context.Context, client Client, endpoint string) error {
+	ctx, cancel := context.WithTimeout(ctx, constants.EtcdMaintenanceDefragmentTimeout)
+	defer cancel()
+
+	_, err := client.Defragment(ctx, endpoint)
+
+	return err
+}
+
+func formatAlarms(alarms []*etcdserverpb.AlarmMember) []string {
+	result := make([]string, 0, len(alarms))
+
+	for _, alarm := range alarms {
+		result = append(result, fmt.Sprintf("%x:%s", alarm.MemberID, alarm.Alarm))
+	}
+
+	return result
+}
@@ -0,0 +1,175 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package maintenance_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/clientv3"
+	"go.etcd.io/etcd/etcdserver/etcdserverpb"
+
+	"github.com/talos-systems/talos/internal/pkg/etcd/maintenance"
+)
+
+type member struct {
+	id          uint64
+	name        string
+	dbSize      int64
+	dbSizeInUse int64
+}
+
+type mockClient struct {
+	local   uint64
+	leader  uint64
+	members []*member
+	alarms  []*etcdserverpb.AlarmMember
+
+	defragmented []string
+}
+
+func (c *mockClient) endpoint(m *member) string {
+	return "https://" + m.name + ":2379"
+}
+
+func (c *mockClient) MemberList(ctx context.Context) (*clientv3.MemberListResponse, error) {
+	resp := &clientv3.MemberListResponse{
+		Header: &etcdserverpb.ResponseHeader{
+			MemberId: c.local,
+		},
+	}
+
+	for _, m := range c.members {
+		resp.Members = append(resp.Members, &etcdserverpb.Member{
+			ID:         m.id,
+			Name:       m.name,
+			ClientURLs: []string{c.endpoint(m)},
+		})
+	}
+
+	return resp, nil
+}
+
+func (c *mockClient) Status(ctx context.Context, endpoint string) (*clientv3.StatusResponse, error) {
+	for _, m := range c.members {
+		if c.endpoint(m) == endpoint {
+			return &clientv3.StatusResponse{
+				Leader:      c.leader,
+				DbSize:      m.dbSize,
+				DbSizeInUse: m.dbSizeInUse,
+			}, nil
+		}
+	}
+
+	return nil, context.DeadlineExceeded
+}
+
+func (c *mockClient) Defragment(ctx context.Context, endpoint string) (*clientv3.DefragmentResponse, error) {
+	for _, m := range c.members {
+		if c.endpoint(m) == endpoint {
+			m.dbSize = m.dbSizeInUse
+			c.defragmented = append(c.defragmented, m.name)
+		}
+	}
+
+	return &clientv3.DefragmentResponse{}, nil
+}
+
+func (c *mockClient) AlarmList(ctx context.Context) (*clientv3.AlarmResponse, error) {
+	return &clientv3.AlarmResponse{
+		Alarms: c.alarms,
+	}, nil
+}
+
+func (c *mockClient) AlarmDisarm(ctx context.Context, am *clientv3.AlarmMember) (*clientv3.AlarmResponse, error) {
+	alarms := []*etcdserverpb.AlarmMember{}
+
+	for _, alarm := range c.alarms {
+		if alarm.MemberID != am.MemberID || alarm.Alarm != am.Alarm {
+			alarms = append(alarms, alarm)
+		}
+	}
+
+	c.alarms = alarms
+
+	return &clientv3.AlarmResponse{}, nil
+}
+
+func newMockClient(local uint64) *mockClient {
+	return &mockClient{
+		local:  local,
+		leader: 1,
+		members: []*member{
+			{id: 1, name: "leader", dbSize: 200 * 1000 * 1000, dbSizeInUse: 50 * 1000 * 1000},
+			{id: 2, name: "follower-fragmented", dbSize: 200 * 1000 * 1000, dbSizeInUse: 60 * 1000 * 1000},
+			{id: 3, name: "follower-small", dbSize: 50 * 1000 * 1000, dbSizeInUse: 10 * 1000 * 1000},
+			{id: 4, name: "follower-compact", dbSize: 200 * 1000 * 1000, dbSizeInUse: 190 * 1000 * 1000},
+		},
+	}
+}
+
+var settings = maintenance.Settings{
+	MinDBSize:              100 * 1000 * 1000,
+	FragmentationThreshold: 50,
+}
+
+func TestNeedsDefragmentation(t *testing.T) {
+	assert.True(t, settings.NeedsDefragmentation(200*1000*1000, 100*1000*1000))
+	assert.False(t, settings.NeedsDefragmentation(200*1000*1000, 100*1000*1000+1))
+	assert.False(t, settings.NeedsDefragmentation(50*1000*1000, 0))
+	assert.False(t, maintenance.Settings{}.NeedsDefragmentation(0, 0))
+}
+
+func TestRunLeader(t *testing.T) {
+	client := newMockClient(1)
+
+	report, err := maintenance.Run(context.Background(), client, settings, maintenance.Report{})
+	require.NoError(t, err)
+
+	assert.False(t, report.Skipped)
+	assert.Equal(t, []string{"follower-fragmented", "leader"}, client.defragmented)
+
+	require.Len(t, report.Members, 4)
+	assert.True(t, report.Members[0].Leader)
+	assert.False(t, report.Members[0].LastDefragmented.IsZero())
+	assert.Equal(t, int64(50*1000*1000), report.Members[0].DBSize)
+	assert.True(t, report.Members[2].LastDefragmented.IsZero())
+}
+
+func TestRunFollower(t *testing.T) {
+	client := newMockClient(2)
+
+	lastDefragmented := time.Now().Add(-time.Hour)
+
+	report, err := maintenance.Run(context.Background(), client, settings, maintenance.Report{
+		Members: []maintenance.MemberStatus{
+			{ID: 2, LastDefragmented: lastDefragmented},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, report.Skipped)
+	assert.Empty(t, client.defragmented)
+
+	require.Len(t, report.Members, 4)
+	assert.Equal(t, lastDefragmented, report.Members[1].LastDefragmented)
+}
+
+func TestRunNoSpaceAlarm(t *testing.T) {
+	client := newMockClient(1)
+	client.alarms = []*etcdserverpb.AlarmMember{
+		{MemberID: 4, Alarm: etcdserverpb.AlarmType_NOSPACE},
+		{MemberID: 3, Alarm: etcdserverpb.AlarmType_CORRUPT},
+	}
+
+	report, err := maintenance.Run(context.Background(), client, settings, maintenance.Report{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"follower-fragmented", "follower-compact", "leader"}, client.defragmented)
+	assert.Equal(t, []string{"3:CORRUPT"}, report.Alarms)
+}
@@ -0,0 +1,38 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package hardware collects the hardware inventory of the machine.
+//
+// Inventory is built from the SMBIOS tables (system, BIOS, baseboard and memory modules)
+// and sysfs (PCI devices, CPU and NUMA topology).
+package hardware
+
+import (
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// SysfsPath is the path to the sysfs mount.
+var SysfsPath = "/sys"
+
+func readString(path string) (string, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(contents)), nil
+}
+
+func readUint32(path string) (uint32, error) {
+	s, err := readString(path)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := strconv.ParseUint(s, 10, 32)
+
+	return uint32(v), err
+}
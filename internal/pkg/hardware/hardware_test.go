@@ -0,0 +1,182 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package hardware_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/hardware"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(contents+"\n"), 0o644))
+	}
+}
+
+func setupSysfs(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	oldPath := hardware.SysfsPath
+	hardware.SysfsPath = dir
+
+	return func() {
+		hardware.SysfsPath = oldPath
+
+		require.NoError(t, os.RemoveAll(dir))
+	}
+}
+
+func TestParseMemoryDevice(t *testing.T) {
+	formatted := make([]byte, 0x24)
+	formatted[0x08], formatted[0x09] = 0x00, 0x40 // 16384 MiB
+	formatted[0x0c] = 1                           // locator
+	formatted[0x0d] = 2                           // bank locator
+	formatted[0x0e] = 0x1a                        // DDR4
+	formatted[0x11], formatted[0x12] = 0x60, 0x09 // 2400 MT/s
+	formatted[0x13] = 3                           // manufacturer
+	formatted[0x14] = 4                           // serial number
+	formatted[0x16] = 5                           // part number
+
+	module, ok := hardware.ParseMemoryDevice(formatted, []string{"DIMM 0", "BANK 0", "Samsung", "1234", "M393A2K40BB1"})
+	require.True(t, ok)
+
+	assert.Equal(t, hardware.MemoryModule{
+		Locator:      "DIMM 0",
+		BankLocator:  "BANK 0",
+		Size:         16 << 30,
+		Type:         "DDR4",
+		Speed:        2400,
+		Manufacturer: "Samsung",
+		SerialNumber: "1234",
+		PartNumber:   "M393A2K40BB1",
+	}, module)
+
+	// extended size
+	formatted[0x08], formatted[0x09] = 0xff, 0x7f
+	formatted[0x18], formatted[0x19] = 0x00, 0x00
+	formatted[0x1a], formatted[0x1b] = 0x02, 0x00 // 128 GiB
+
+	module, ok = hardware.ParseMemoryDevice(formatted, nil)
+	require.True(t, ok)
+	assert.EqualValues(t, 128<<30, module.Size)
+	assert.Empty(t, module.Locator)
+
+	// empty slot
+	formatted[0x08], formatted[0x09] = 0, 0
+
+	_, ok = hardware.ParseMemoryDevice(formatted, nil)
+	assert.False(t, ok)
+
+	_, ok = hardware.ParseMemoryDevice(formatted[:4], nil)
+	assert.False(t, ok)
+}
+
+func TestPCIDevices(t *testing.T) {
+	defer setupSysfs(t)()
+
+	root := filepath.Join(hardware.SysfsPath, "devices", "pci0000:00")
+
+	writeFiles(t, root, map[string]string{
+		"0000:00:01.0/class":                  "0x060400",
+		"0000:00:01.0/vendor":                 "0x8086",
+		"0000:00:01.0/device":                 "0x1901",
+		"0000:00:01.0/numa_node":              "-1",
+		"0000:00:01.0/0000:01:00.0/class":     "0x010802",
+		"0000:00:01.0/0000:01:00.0/vendor":    "0x144d",
+		"0000:00:01.0/0000:01:00.0/device":    "0xa808",
+		"0000:00:01.0/0000:01:00.0/numa_node": "0",
+	})
+
+	busPath := filepath.Join(hardware.SysfsPath, "bus", "pci", "devices")
+	driverPath := filepath.Join(hardware.SysfsPath, "bus", "pci", "drivers", "nvme")
+
+	require.NoError(t, os.MkdirAll(busPath, 0o755))
+	require.NoError(t, os.MkdirAll(driverPath, 0o755))
+	require.NoError(t, os.Symlink(filepath.Join(root, "0000:00:01.0"), filepath.Join(busPath, "0000:00:01.0")))
+	require.NoError(t, os.Symlink(filepath.Join(root, "0000:00:01.0", "0000:01:00.0"), filepath.Join(busPath, "0000:01:00.0")))
+	require.NoError(t, os.Symlink(driverPath, filepath.Join(root, "0000:00:01.0", "0000:01:00.0", "driver")))
+
+	devices, err := hardware.PCIDevices()
+	require.NoError(t, err)
+
+	assert.Equal(t, []hardware.PCIDevice{
+		{
+			Address:  "0000:00:01.0",
+			Class:    "0x060400",
+			VendorID: "0x8086",
+			DeviceID: "0x1901",
+			NUMANode: -1,
+		},
+		{
+			Address:  "0000:01:00.0",
+			Parent:   "0000:00:01.0",
+			Class:    "0x010802",
+			VendorID: "0x144d",
+			DeviceID: "0xa808",
+			Driver:   "nvme",
+			NUMANode: 0,
+		},
+	}, devices)
+}
+
+func TestTopology(t *testing.T) {
+	defer setupSysfs(t)()
+
+	writeFiles(t, filepath.Join(hardware.SysfsPath, "devices", "system"), map[string]string{
+		"node/node0/cpulist":                    "0,2",
+		"node/node0/meminfo":                    "Node 0 MemTotal:       1024 kB\nNode 0 MemFree:        512 kB",
+		"node/node1/cpulist":                    "1,3",
+		"node/node1/meminfo":                    "Node 1 MemTotal:       2048 kB",
+		"cpu/online":                            "0-3",
+		"cpu/cpu0/topology/physical_package_id": "0",
+		"cpu/cpu0/topology/core_id":             "0",
+		"cpu/cpu1/topology/physical_package_id": "1",
+		"cpu/cpu1/topology/core_id":             "0",
+		"cpu/cpu2/topology/physical_package_id": "0",
+		"cpu/cpu2/topology/core_id":             "1",
+		"cpu/cpu3/topology/physical_package_id": "1",
+		"cpu/cpu3/topology/core_id":             "1",
+	})
+
+	nodes, err := hardware.NUMANodes()
+	require.NoError(t, err)
+
+	assert.Equal(t, []hardware.NUMANode{
+		{ID: 0, CPUs: []uint32{0, 2}, MemoryTotal: 1 << 20},
+		{ID: 1, CPUs: []uint32{1, 3}, MemoryTotal: 2 << 20},
+	}, nodes)
+
+	cpus, err := hardware.CPUs(nodes)
+	require.NoError(t, err)
+
+	assert.Equal(t, []hardware.CPU{
+		{ID: 0, PackageID: 0, CoreID: 0, NUMANode: 0},
+		{ID: 1, PackageID: 1, CoreID: 0, NUMANode: 1},
+		{ID: 2, PackageID: 0, CoreID: 1, NUMANode: 0},
+		{ID: 3, PackageID: 1, CoreID: 1, NUMANode: 1},
+	}, cpus)
+}
+
+func TestCPUList(t *testing.T) {
+	cpus, err := hardware.ParseCPUList("0-3,8,10-11")
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint32{0, 1, 2, 3, 8, 10, 11}, cpus)
+	assert.Equal(t, "0-3,8,10-11", hardware.FormatCPUList(cpus))
+
+	_, err = hardware.ParseCPUList("0-a")
+	assert.Error(t, err)
+}
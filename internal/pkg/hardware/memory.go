@@ -0,0 +1,128 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package hardware
+
+import (
+	"encoding/binary"
+
+	"github.com/talos-systems/go-smbios/smbios"
+)
+
+// smbiosMemoryDevice is the SMBIOS structure type of the memory device.
+const smbiosMemoryDevice = 17
+
+// MemoryModule describes a memory module installed in the machine.
+type MemoryModule struct {
+	Locator      string
+	BankLocator  string
+	Size         uint64
+	Type         string
+	Speed        uint32
+	Manufacturer string
+	SerialNumber string
+	PartNumber   string
+}
+
+var memoryTypes = map[byte]string{
+	0x01: "Other",
+	0x02: "Unknown",
+	0x03: "DRAM",
+	0x07: "RAM",
+	0x0f: "SDRAM",
+	0x12: "DDR",
+	0x13: "DDR2",
+	0x14: "DDR2 FB-DIMM",
+	0x18: "DDR3",
+	0x1a: "DDR4",
+	0x1b: "LPDDR",
+	0x1c: "LPDDR2",
+	0x1d: "LPDDR3",
+	0x1e: "LPDDR4",
+	0x22: "DDR5",
+	0x23: "LPDDR5",
+}
+
+// MemoryModules returns the memory modules from the SMBIOS memory device structures.
+//
+// Empty slots are skipped.
+func MemoryModules(s *smbios.Smbios) []MemoryModule {
+	var modules []MemoryModule
+
+	for _, structure := range s.Structures {
+		if structure.Header.Type != smbiosMemoryDevice {
+			continue
+		}
+
+		if module, ok := ParseMemoryDevice(structure.Formatted, structure.Strings); ok {
+			modules = append(modules, module)
+		}
+	}
+
+	return modules
+}
+
+// ParseMemoryDevice parses the formatted area and the strings of the SMBIOS memory device structure.
+//
+// Formatted area doesn't include the structure header, so the offsets are shifted
+// by four bytes compared to the SMBIOS specification.
+// Second return value is false if the slot is empty or the structure is truncated.
+func ParseMemoryDevice(formatted []byte, strs []string) (MemoryModule, bool) {
+	if len(formatted) < 0x0f {
+		return MemoryModule{}, false
+	}
+
+	str := func(offset int) string {
+		if offset >= len(formatted) {
+			return ""
+		}
+
+		idx := int(formatted[offset])
+		if idx == 0 || idx > len(strs) {
+			return ""
+		}
+
+		return strs[idx-1]
+	}
+
+	var size uint64
+
+	switch rawSize := binary.LittleEndian.Uint16(formatted[0x08:]); {
+	case rawSize == 0:
+		// slot is empty
+		return MemoryModule{}, false
+	case rawSize == 0xffff:
+		// size is unknown
+	case rawSize == 0x7fff && len(formatted) >= 0x1c:
+		// extended size in MiB
+		size = uint64(binary.LittleEndian.Uint32(formatted[0x18:])&0x7fffffff) << 20
+	case rawSize&0x8000 != 0:
+		// size in KiB
+		size = uint64(rawSize&0x7fff) << 10
+	default:
+		// size in MiB
+		size = uint64(rawSize) << 20
+	}
+
+	module := MemoryModule{
+		Locator:      str(0x0c),
+		BankLocator:  str(0x0d),
+		Size:         size,
+		Type:         memoryTypes[formatted[0x0e]],
+		Manufacturer: str(0x13),
+		SerialNumber: str(0x14),
+		PartNumber:   str(0x16),
+	}
+
+	if module.Type == "" {
+		module.Type = "Unknown"
+	}
+
+	// speed was added in SMBIOS 2.3
+	if len(formatted) >= 0x13 {
+		module.Speed = uint32(binary.LittleEndian.Uint16(formatted[0x11:]))
+	}
+
+	return module, true
+}
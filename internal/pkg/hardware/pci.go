@@ -0,0 +1,112 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package hardware
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// PCIDevice describes a device on the PCI bus.
+type PCIDevice struct {
+	// Address is the PCI address of the device (domain:bus:slot.function).
+	Address string
+	// Parent is the address of the upstream bridge, empty for the devices on the root bus.
+	Parent string
+
+	Class             string
+	VendorID          string
+	DeviceID          string
+	SubsystemVendorID string
+	SubsystemDeviceID string
+	Driver            string
+	// NUMANode is -1 if the device is not bound to a NUMA node.
+	NUMANode int32
+}
+
+// PCIDevices returns the PCI devices sorted by the address.
+func PCIDevices() ([]PCIDevice, error) {
+	devicesPath := filepath.Join(SysfsPath, "bus", "pci", "devices")
+
+	entries, err := ioutil.ReadDir(devicesPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	known := make(map[string]struct{}, len(entries))
+
+	for _, entry := range entries {
+		known[entry.Name()] = struct{}{}
+	}
+
+	devices := make([]PCIDevice, 0, len(entries))
+
+	for _, entry := range entries {
+		var device PCIDevice
+
+		if device, err = readPCIDevice(filepath.Join(devicesPath, entry.Name()), known); err != nil {
+			return nil, err
+		}
+
+		devices = append(devices, device)
+	}
+
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Address < devices[j].Address })
+
+	return devices, nil
+}
+
+func readPCIDevice(path string, known map[string]struct{}) (PCIDevice, error) {
+	device := PCIDevice{
+		Address:  filepath.Base(path),
+		NUMANode: -1,
+	}
+
+	// devices are symlinks into the /sys/devices tree, which reflects the bus hierarchy
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return device, err
+	}
+
+	if parent := filepath.Base(filepath.Dir(resolved)); parent != device.Address {
+		if _, ok := known[parent]; ok {
+			device.Parent = parent
+		}
+	}
+
+	for _, attr := range []struct {
+		name string
+		dest *string
+	}{
+		{"class", &device.Class},
+		{"vendor", &device.VendorID},
+		{"device", &device.DeviceID},
+		{"subsystem_vendor", &device.SubsystemVendorID},
+		{"subsystem_device", &device.SubsystemDeviceID},
+	} {
+		if *attr.dest, err = readString(filepath.Join(path, attr.name)); err != nil && !os.IsNotExist(err) {
+			return device, err
+		}
+	}
+
+	if driver, e := os.Readlink(filepath.Join(path, "driver")); e == nil {
+		device.Driver = filepath.Base(driver)
+	}
+
+	if numaNode, e := readString(filepath.Join(path, "numa_node")); e == nil {
+		if v, parseErr := strconv.ParseInt(numaNode, 10, 32); parseErr == nil {
+			device.NUMANode = int32(v)
+		}
+	}
+
+	return device, nil
+}
@@ -0,0 +1,209 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package hardware
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NUMANode describes a NUMA node.
+type NUMANode struct {
+	ID   uint32
+	CPUs []uint32
+	// MemoryTotal is in bytes.
+	MemoryTotal uint64
+}
+
+// CPU describes the topology of a logical CPU.
+type CPU struct {
+	ID        uint32
+	PackageID uint32
+	CoreID    uint32
+	// NUMANode is -1 if NUMA is not available.
+	NUMANode int32
+}
+
+// NUMANodes returns the NUMA nodes sorted by ID.
+func NUMANodes() ([]NUMANode, error) {
+	paths, err := filepath.Glob(filepath.Join(SysfsPath, "devices", "system", "node", "node[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]NUMANode, 0, len(paths))
+
+	for _, path := range paths {
+		var id uint64
+
+		if id, err = strconv.ParseUint(strings.TrimPrefix(filepath.Base(path), "node"), 10, 32); err != nil {
+			continue
+		}
+
+		node := NUMANode{
+			ID: uint32(id),
+		}
+
+		var cpuList string
+
+		if cpuList, err = readString(filepath.Join(path, "cpulist")); err != nil {
+			return nil, err
+		}
+
+		if node.CPUs, err = ParseCPUList(cpuList); err != nil {
+			return nil, err
+		}
+
+		if node.MemoryTotal, err = nodeMemoryTotal(filepath.Join(path, "meminfo")); err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	return nodes, nil
+}
+
+// CPUs returns the topology of the online logical CPUs sorted by ID.
+//
+// NUMA node of the CPU is looked up in the nodes returned by NUMANodes.
+func CPUs(nodes []NUMANode) ([]CPU, error) {
+	cpuPath := filepath.Join(SysfsPath, "devices", "system", "cpu")
+
+	online, err := readString(filepath.Join(cpuPath, "online"))
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := ParseCPUList(online)
+	if err != nil {
+		return nil, err
+	}
+
+	cpuNodes := map[uint32]int32{}
+
+	for _, node := range nodes {
+		for _, id := range node.CPUs {
+			cpuNodes[id] = int32(node.ID)
+		}
+	}
+
+	cpus := make([]CPU, 0, len(ids))
+
+	for _, id := range ids {
+		cpu := CPU{
+			ID:       id,
+			NUMANode: -1,
+		}
+
+		topologyPath := filepath.Join(cpuPath, fmt.Sprintf("cpu%d", id), "topology")
+
+		if cpu.PackageID, err = readUint32(filepath.Join(topologyPath, "physical_package_id")); err != nil {
+			return nil, err
+		}
+
+		if cpu.CoreID, err = readUint32(filepath.Join(topologyPath, "core_id")); err != nil {
+			return nil, err
+		}
+
+		if node, ok := cpuNodes[id]; ok {
+			cpu.NUMANode = node
+		}
+
+		cpus = append(cpus, cpu)
+	}
+
+	return cpus, nil
+}
+
+// ParseCPUList parses the kernel CPU list format, e.g. '0-3,8-11'.
+func ParseCPUList(s string) ([]uint32, error) {
+	var cpus []uint32
+
+	if s == "" {
+		return cpus, nil
+	}
+
+	for _, r := range strings.Split(s, ",") {
+		bounds := strings.SplitN(r, "-", 2)
+
+		start, err := strconv.ParseUint(bounds[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing CPU list %q: %w", s, err)
+		}
+
+		end := start
+
+		if len(bounds) == 2 {
+			if end, err = strconv.ParseUint(bounds[1], 10, 32); err != nil {
+				return nil, fmt.Errorf("error parsing CPU list %q: %w", s, err)
+			}
+		}
+
+		for cpu := start; cpu <= end; cpu++ {
+			cpus = append(cpus, uint32(cpu))
+		}
+	}
+
+	return cpus, nil
+}
+
+// FormatCPUList formats sorted list of CPUs in the kernel CPU list format.
+func FormatCPUList(cpus []uint32) string {
+	var ranges []string
+
+	for i := 0; i < len(cpus); {
+		j := i
+
+		for j+1 < len(cpus) && cpus[j+1] == cpus[j]+1 {
+			j++
+		}
+
+		if i == j {
+			ranges = append(ranges, strconv.FormatUint(uint64(cpus[i]), 10))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", cpus[i], cpus[j]))
+		}
+
+		i = j + 1
+	}
+
+	return strings.Join(ranges, ",")
+}
+
+// nodeMemoryTotal parses the per-node meminfo, e.g. 'Node 0 MemTotal:       16314116 kB'.
+func nodeMemoryTotal(path string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+
+	// nolint: errcheck
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		if len(fields) == 5 && fields[2] == "MemTotal:" {
+			kb, err := strconv.ParseUint(fields[3], 10, 64)
+			if err != nil {
+				return 0, err
+			}
+
+			return kb << 10, nil
+		}
+	}
+
+	return 0, scanner.Err()
+}
@@ -0,0 +1,89 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package irq sets the CPU affinity of the hardware interrupts.
+package irq
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ProcPath is the root of the IRQ procfs tree.
+var ProcPath = "/proc/irq"
+
+// Mask formats the CPUs as the kernel CPU mask: hex 32-bit words separated with commas, most significant word first.
+func Mask(cpus []uint32) string {
+	var words []uint32
+
+	for _, cpu := range cpus {
+		word := int(cpu / 32)
+
+		for len(words) <= word {
+			words = append(words, 0)
+		}
+
+		words[word] |= 1 << (cpu % 32)
+	}
+
+	if len(words) == 0 {
+		return "0"
+	}
+
+	parts := make([]string, len(words))
+
+	for i, word := range words {
+		format := "%08x"
+		if i == len(words)-1 {
+			format = "%x"
+		}
+
+		parts[len(words)-1-i] = fmt.Sprintf(format, word)
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// SetAffinity sets the default IRQ affinity and the affinity of the existing interrupts to the CPUs.
+//
+// Some interrupts (e.g. per-CPU timers) can't be moved, they are skipped and their number is returned.
+func SetAffinity(cpus []uint32) (skipped int, err error) {
+	mask := []byte(Mask(cpus))
+
+	if err = ioutil.WriteFile(filepath.Join(ProcPath, "default_smp_affinity"), mask, 0o644); err != nil {
+		return 0, fmt.Errorf("error setting default IRQ affinity: %w", err)
+	}
+
+	infos, err := ioutil.ReadDir(ProcPath)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+
+		if _, err = strconv.ParseUint(info.Name(), 10, 32); err != nil {
+			continue
+		}
+
+		if err = ioutil.WriteFile(filepath.Join(ProcPath, info.Name(), "smp_affinity"), mask, 0o644); err != nil {
+			if errors.Is(err, syscall.EIO) || errors.Is(err, syscall.EINVAL) {
+				skipped++
+
+				continue
+			}
+
+			return skipped, fmt.Errorf("error setting affinity of IRQ %s: %w", info.Name(), err)
+		}
+	}
+
+	return skipped, nil
+}
@@ -0,0 +1,77 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package irq_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/kernel/irq"
+)
+
+func TestMask(t *testing.T) {
+	for _, tt := range []struct {
+		cpus     []uint32
+		expected string
+	}{
+		{
+			expected: "0",
+		},
+		{
+			cpus:     []uint32{0, 1},
+			expected: "3",
+		},
+		{
+			cpus:     []uint32{0, 31},
+			expected: "80000001",
+		},
+		{
+			cpus:     []uint32{0, 32, 33},
+			expected: "3,00000001",
+		},
+		{
+			cpus:     []uint32{64},
+			expected: "1,00000000,00000000",
+		},
+	} {
+		assert.Equal(t, tt.expected, irq.Mask(tt.cpus))
+	}
+}
+
+func TestSetAffinity(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	for _, name := range []string{"0", "24"} {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, name), 0o755))
+	}
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "other"), 0o755))
+
+	defer func(path string) {
+		irq.ProcPath = path
+	}(irq.ProcPath)
+
+	irq.ProcPath = dir
+
+	skipped, err := irq.SetAffinity([]uint32{0, 1})
+	require.NoError(t, err)
+	assert.Equal(t, 0, skipped)
+
+	for _, path := range []string{"default_smp_affinity", "0/smp_affinity", "24/smp_affinity"} {
+		contents, err := ioutil.ReadFile(filepath.Join(dir, path))
+		require.NoError(t, err)
+		assert.Equal(t, "3", string(contents))
+	}
+
+	assert.NoFileExists(t, filepath.Join(dir, "other", "smp_affinity"))
+}
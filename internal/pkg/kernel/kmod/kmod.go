@@ -0,0 +1,175 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package kmod loads kernel modules.
+package kmod
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// ModulesPath is the root of the kernel modules tree.
+var ModulesPath = "/lib/modules"
+
+// Load loads the kernel module with the parameters.
+//
+// Module is either the name of the module shipped with the running kernel (dependencies
+// of the module are loaded first), or an absolute path to the module file.
+// Modules which are already loaded are skipped.
+func Load(module string, parameters []string) error {
+	var uname unix.Utsname
+
+	if err := unix.Uname(&uname); err != nil {
+		return err
+	}
+
+	paths, err := Resolve(filepath.Join(ModulesPath, unix.ByteSliceToString(uname.Release[:])), module)
+	if err != nil {
+		return err
+	}
+
+	for i, path := range paths {
+		var params string
+
+		if i == len(paths)-1 {
+			params = strings.Join(parameters, " ")
+		}
+
+		if err = load(path, params); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Resolve returns the module files to load in order: dependencies first, the module itself last.
+//
+// Modules are looked up in the modules.dep file in the modules directory of the kernel release.
+// Nothing needs to be loaded for the modules built into the kernel (as listed in modules.builtin).
+func Resolve(dir, module string) ([]string, error) {
+	if filepath.IsAbs(module) {
+		return []string{module}, nil
+	}
+
+	f, err := os.Open(filepath.Join(dir, "modules.dep"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading modules index: %w", err)
+	}
+
+	// nolint: errcheck
+	defer f.Close()
+
+	name := normalize(module)
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		// kernel/drivers/gpu/drm/drm_kms_helper.ko: kernel/drivers/gpu/drm/drm.ko
+		line := strings.SplitN(scanner.Text(), ":", 2)
+		if len(line) != 2 || moduleName(line[0]) != name {
+			continue
+		}
+
+		deps := strings.Fields(line[1])
+		paths := make([]string, 0, len(deps)+1)
+
+		// dependencies are listed in the reverse load order
+		for i := len(deps) - 1; i >= 0; i-- {
+			paths = append(paths, modulePath(dir, deps[i]))
+		}
+
+		return append(paths, modulePath(dir, line[0])), nil
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	builtin, err := isBuiltin(dir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if builtin {
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("module %q not found in %s", module, dir)
+}
+
+func isBuiltin(dir, name string) (bool, error) {
+	f, err := os.Open(filepath.Join(dir, "modules.builtin"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	// nolint: errcheck
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		if moduleName(scanner.Text()) == name {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+func load(path, params string) error {
+	if filepath.Ext(path) != ".ko" {
+		return fmt.Errorf("error loading module %q: compressed modules are not supported", path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error loading module: %w", err)
+	}
+
+	// nolint: errcheck
+	defer f.Close()
+
+	if err = unix.FinitModule(int(f.Fd()), params, 0); err != nil && !errors.Is(err, unix.EEXIST) {
+		return fmt.Errorf("error loading module %q: %w", path, err)
+	}
+
+	return nil
+}
+
+func modulePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
+
+// moduleName returns the normalized module name for the path, e.g. 'nvidia_uvm' for 'kernel/nvidia-uvm.ko.xz'.
+func moduleName(path string) string {
+	name := filepath.Base(path)
+
+	if idx := strings.Index(name, ".ko"); idx != -1 {
+		name = name[:idx]
+	}
+
+	return normalize(name)
+}
+
+// normalize the module name, as dashes and underscores are interchangeable.
+func normalize(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
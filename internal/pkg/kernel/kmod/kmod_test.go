@@ -0,0 +1,96 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kmod_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/kernel/kmod"
+)
+
+func TestResolve(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "modules.dep"), []byte(`kernel/drivers/gpu/drm/drm.ko:
+kernel/drivers/gpu/drm/drm_kms_helper.ko: kernel/drivers/gpu/drm/drm.ko
+kernel/drivers/gpu/drm/i915/i915.ko: kernel/drivers/gpu/drm/drm_kms_helper.ko kernel/drivers/gpu/drm/drm.ko
+kernel/drivers/net/ethernet/intel/e1000e/e1000e.ko.xz:
+extra/nvidia-uvm.ko: /var/lib/modules/nvidia.ko
+`), 0o644))
+
+	for _, tt := range []struct {
+		module   string
+		expected []string
+	}{
+		{
+			module:   "drm",
+			expected: []string{"kernel/drivers/gpu/drm/drm.ko"},
+		},
+		{
+			module: "i915",
+			expected: []string{
+				"kernel/drivers/gpu/drm/drm.ko",
+				"kernel/drivers/gpu/drm/drm_kms_helper.ko",
+				"kernel/drivers/gpu/drm/i915/i915.ko",
+			},
+		},
+		{
+			module:   "drm-kms-helper",
+			expected: []string{"kernel/drivers/gpu/drm/drm.ko", "kernel/drivers/gpu/drm/drm_kms_helper.ko"},
+		},
+		{
+			module:   "e1000e",
+			expected: []string{"kernel/drivers/net/ethernet/intel/e1000e/e1000e.ko.xz"},
+		},
+		{
+			module:   "nvidia_uvm",
+			expected: []string{"/var/lib/modules/nvidia.ko", "extra/nvidia-uvm.ko"},
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.module, func(t *testing.T) {
+			paths, err := kmod.Resolve(dir, tt.module)
+			require.NoError(t, err)
+
+			expected := make([]string, len(tt.expected))
+
+			for i, path := range tt.expected {
+				if filepath.IsAbs(path) {
+					expected[i] = path
+				} else {
+					expected[i] = filepath.Join(dir, path)
+				}
+			}
+
+			assert.Equal(t, expected, paths)
+		})
+	}
+
+	paths, err := kmod.Resolve(dir, "/var/lib/modules/nvidia.ko")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/var/lib/modules/nvidia.ko"}, paths)
+
+	_, err = kmod.Resolve(dir, "nouveau")
+	assert.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "modules.builtin"), []byte("kernel/fs/nfs/nfs.ko\nkernel/fs/nfs/nfsv4.ko\n"), 0o644))
+
+	paths, err = kmod.Resolve(dir, "nfsv4")
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+
+	_, err = kmod.Resolve(dir, "nouveau")
+	assert.Error(t, err)
+}
@@ -5,6 +5,7 @@
 package kmsg
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -78,6 +79,27 @@ type Message struct {
 	Message        string
 }
 
+// MarshalJSON implements json.Marshaler.
+//
+// Facility and priority are encoded as strings.
+func (m Message) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Facility  string    `json:"facility"`
+		Priority  string    `json:"priority"`
+		Sequence  int64     `json:"sequence"`
+		Clock     int64     `json:"clock"`
+		Timestamp time.Time `json:"timestamp"`
+		Message   string    `json:"message"`
+	}{
+		Facility:  m.Facility.String(),
+		Priority:  m.Priority.String(),
+		Sequence:  m.SequenceNumber,
+		Clock:     m.Clock,
+		Timestamp: m.Timestamp,
+		Message:   m.Message,
+	})
+}
+
 // ParseMessage parses internal kernel log format.
 //
 // Reference: https://www.kernel.org/doc/Documentation/ABI/testing/dev-kmsg
@@ -5,10 +5,12 @@
 package kmsg_test
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/talos-systems/talos/internal/pkg/kmsg"
 )
@@ -58,3 +60,13 @@ func TestParseMessage(t *testing.T) {
 		assert.Equal(t, testCase.expected, message)
 	}
 }
+
+func TestMessageMarshalJSON(t *testing.T) {
+	message, err := kmsg.ParseMessage(`6,339,5140900,-;NET: Registered protocol family 10`, time.Unix(0, 0).UTC())
+	require.NoError(t, err)
+
+	b, err := json.Marshal(message)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"facility":"kern","priority":"info","sequence":339,"clock":5140900,"timestamp":"1970-01-01T00:00:05.1409Z","message":"NET: Registered protocol family 10"}`, string(b))
+}
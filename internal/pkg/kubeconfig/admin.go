@@ -17,7 +17,7 @@ import (
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
-const adminKubeConfigTemplate = `apiVersion: v1
+const kubeConfigTemplate = `apiVersion: v1
 kind: Config
 clusters:
 - name: {{ .Cluster }}
@@ -25,58 +25,102 @@ clusters:
     server: {{ .Server }}
     certificate-authority-data: {{ .CACert }}
 users:
-- name: admin@{{ .Cluster }}
+- name: {{ .User }}@{{ .Cluster }}
   user:
-    client-certificate-data: {{ .AdminCert }}
-    client-key-data: {{ .AdminKey }}
+    client-certificate-data: {{ .ClientCert }}
+    client-key-data: {{ .ClientKey }}
 contexts:
 - context:
     cluster: {{ .Cluster }}
     namespace: default
-    user: admin@{{ .Cluster }}
-  name: admin@{{ .Cluster }}
-current-context: admin@{{ .Cluster }}
+    user: {{ .User }}@{{ .Cluster }}
+  name: {{ .User }}@{{ .Cluster }}
+current-context: {{ .User }}@{{ .Cluster }}
 `
 
 // GenerateAdmin generates admin kubeconfig for the cluster.
 func GenerateAdmin(config config.ClusterConfig, out io.Writer) error {
-	tpl, err := template.New("kubeconfig").Parse(adminKubeConfigTemplate)
-	if err != nil {
-		return fmt.Errorf("error parsing kubeconfig template: %w", err)
-	}
+	return generate(config, "admin", config.Endpoint().String(), out,
+		x509.CommonName(constants.KubernetesAdminCertCommonName),
+		x509.Organization(constants.KubernetesAdminCertOrganization),
+		x509.NotAfter(time.Now().Add(config.AdminKubeconfig().CertLifetime())))
+}
 
-	k8sCA, err := config.CA().GetCert()
-	if err != nil {
-		return fmt.Errorf("error getting Kubernetes CA certificate: %w", err)
+// GenerateAdminCertificate generates admin client certificate for the cluster which is valid for the ttl.
+//
+// Certificate is used by the clients which fetch the credentials on demand instead of embedding them into the kubeconfig.
+func GenerateAdminCertificate(config config.ClusterConfig, ttl time.Duration) (*x509.PEMEncodedCertificateAndKey, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("certificate TTL should be positive")
 	}
 
-	k8sKey, err := config.CA().GetRSAKey()
-	if err != nil {
-		return fmt.Errorf("error parsing Kubernetes key: %w", err)
+	if ttl > config.AdminKubeconfig().CertLifetime() {
+		return nil, fmt.Errorf("certificate TTL %s exceeds the admin kubeconfig certificate lifetime %s", ttl, config.AdminKubeconfig().CertLifetime())
 	}
 
-	adminCert, err := x509.NewCertficateAndKey(k8sCA, k8sKey,
-		x509.RSA(true),
+	return generateCertificate(config,
 		x509.CommonName(constants.KubernetesAdminCertCommonName),
 		x509.Organization(constants.KubernetesAdminCertOrganization),
-		x509.NotAfter(time.Now().Add(config.AdminKubeconfig().CertLifetime())))
+		x509.NotAfter(time.Now().Add(ttl)))
+}
+
+// GenerateLocal generates kubeconfig for the control plane component running on the node.
+//
+// Kubeconfig points to the API server on the same node, and the component is authenticated
+// with the client certificate for the user passed.
+func GenerateLocal(config config.ClusterConfig, user string, out io.Writer) error {
+	server := fmt.Sprintf("https://127.0.0.1:%d", config.LocalAPIServerPort())
+
+	return generate(config, user, server, out,
+		x509.CommonName(user),
+		x509.NotAfter(time.Now().Add(constants.KubernetesControlPlaneCertLifetime)))
+}
+
+func generate(config config.ClusterConfig, user, server string, out io.Writer, certOptions ...x509.Option) error {
+	tpl, err := template.New("kubeconfig").Parse(kubeConfigTemplate)
 	if err != nil {
-		return fmt.Errorf("error generating admin certificate: %w", err)
+		return fmt.Errorf("error parsing kubeconfig template: %w", err)
+	}
+
+	clientCert, err := generateCertificate(config, certOptions...)
+	if err != nil {
+		return err
 	}
 
 	input := struct {
-		Cluster   string
-		CACert    string
-		AdminCert string
-		AdminKey  string
-		Server    string
+		Cluster    string
+		CACert     string
+		User       string
+		ClientCert string
+		ClientKey  string
+		Server     string
 	}{
-		Cluster:   config.Name(),
-		CACert:    base64.StdEncoding.EncodeToString(config.CA().Crt),
-		AdminCert: base64.StdEncoding.EncodeToString(adminCert.Crt),
-		AdminKey:  base64.StdEncoding.EncodeToString(adminCert.Key),
-		Server:    config.Endpoint().String(),
+		Cluster:    config.Name(),
+		CACert:     base64.StdEncoding.EncodeToString(config.CA().Crt),
+		User:       user,
+		ClientCert: base64.StdEncoding.EncodeToString(clientCert.Crt),
+		ClientKey:  base64.StdEncoding.EncodeToString(clientCert.Key),
+		Server:     server,
 	}
 
 	return tpl.Execute(out, input)
 }
+
+func generateCertificate(config config.ClusterConfig, certOptions ...x509.Option) (*x509.PEMEncodedCertificateAndKey, error) {
+	k8sCA, err := config.CA().GetCert()
+	if err != nil {
+		return nil, fmt.Errorf("error getting Kubernetes CA certificate: %w", err)
+	}
+
+	k8sKey, err := config.CA().GetRSAKey()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Kubernetes key: %w", err)
+	}
+
+	clientCert, err := x509.NewCertficateAndKey(k8sCA, k8sKey, append([]x509.Option{x509.RSA(true)}, certOptions...)...)
+	if err != nil {
+		return nil, fmt.Errorf("error generating client certificate: %w", err)
+	}
+
+	return clientCert, nil
+}
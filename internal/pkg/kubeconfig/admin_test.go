@@ -17,6 +17,7 @@ import (
 
 	"github.com/talos-systems/talos/internal/pkg/kubeconfig"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
 type AdminSuite struct {
@@ -57,6 +58,59 @@ func (suite *AdminSuite) TestGenerate() {
 	suite.Assert().NoError(clientcmd.ConfirmUsable(*config, fmt.Sprintf("admin@%s", cfg.ClusterName)))
 }
 
+func (suite *AdminSuite) TestGenerateAdminCertificate() {
+	ca, err := x509.NewSelfSignedCertificateAuthority(x509.RSA(true))
+	suite.Require().NoError(err)
+
+	cfg := &v1alpha1.ClusterConfig{
+		ClusterName: "talos1",
+		ClusterCA: &x509.PEMEncodedCertificateAndKey{
+			Crt: ca.CrtPEM,
+			Key: ca.KeyPEM,
+		},
+		AdminKubeconfigConfig: v1alpha1.AdminKubeconfigConfig{
+			AdminKubeconfigCertLifetime: time.Hour,
+		},
+	}
+
+	cert, err := kubeconfig.GenerateAdminCertificate(cfg, 10*time.Minute)
+	suite.Require().NoError(err)
+
+	crt, err := cert.GetCert()
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal([]string{constants.KubernetesAdminCertOrganization}, crt.Subject.Organization)
+	suite.Assert().WithinDuration(time.Now().Add(10*time.Minute), crt.NotAfter, time.Minute)
+	suite.Assert().NoError(crt.CheckSignatureFrom(ca.Crt))
+
+	_, err = kubeconfig.GenerateAdminCertificate(cfg, 2*time.Hour)
+	suite.Assert().EqualError(err, "certificate TTL 2h0m0s exceeds the admin kubeconfig certificate lifetime 1h0m0s")
+}
+
+func (suite *AdminSuite) TestGenerateLocal() {
+	ca, err := x509.NewSelfSignedCertificateAuthority(x509.RSA(true))
+	suite.Require().NoError(err)
+
+	cfg := &v1alpha1.ClusterConfig{
+		ClusterName: "talos1",
+		ClusterCA: &x509.PEMEncodedCertificateAndKey{
+			Crt: ca.CrtPEM,
+			Key: ca.KeyPEM,
+		},
+		ControlPlane: &v1alpha1.ControlPlaneConfig{},
+	}
+
+	var buf bytes.Buffer
+
+	suite.Require().NoError(kubeconfig.GenerateLocal(cfg, "system:kube-scheduler", &buf))
+
+	config, err := clientcmd.Load(buf.Bytes())
+	suite.Require().NoError(err)
+
+	suite.Assert().NoError(clientcmd.ConfirmUsable(*config, fmt.Sprintf("system:kube-scheduler@%s", cfg.ClusterName)))
+	suite.Assert().Equal("https://127.0.0.1:6443", config.Clusters[cfg.ClusterName].Server)
+}
+
 func TestAdminSuite(t *testing.T) {
 	suite.Run(t, new(AdminSuite))
 }
@@ -0,0 +1,29 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kubeconfig
+
+import (
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ExecCredentialAPIVersion is the API version of the exec credential plugin protocol.
+const ExecCredentialAPIVersion = "client.authentication.k8s.io/v1beta1"
+
+// UseExecCredentials replaces the embedded client certificates with the exec credential plugin.
+//
+// Kubernetes clients invoke the plugin to fetch the short-lived client certificate on demand,
+// so that the kubeconfig doesn't contain long-lived credentials.
+func UseExecCredentials(config *clientcmdapi.Config, command string, args []string) {
+	for name := range config.AuthInfos {
+		config.AuthInfos[name] = &clientcmdapi.AuthInfo{
+			Exec: &clientcmdapi.ExecConfig{
+				APIVersion:  ExecCredentialAPIVersion,
+				Command:     command,
+				Args:        append([]string(nil), args...),
+				InstallHint: "talosctl is required to fetch the Kubernetes credentials",
+			},
+		}
+	}
+}
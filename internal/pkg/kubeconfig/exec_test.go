@@ -0,0 +1,36 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kubeconfig_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/talos-systems/talos/internal/pkg/kubeconfig"
+)
+
+func TestUseExecCredentials(t *testing.T) {
+	config := newTalosConfig()
+
+	kubeconfig.UseExecCredentials(config, "talosctl", []string{"kubernetes-credentials", "--nodes", "10.5.0.2"})
+
+	require.Len(t, config.AuthInfos, 1)
+
+	authInfo := config.AuthInfos["admin@talos"]
+	require.NotNil(t, authInfo)
+	require.NotNil(t, authInfo.Exec)
+
+	assert.Empty(t, authInfo.ClientCertificate)
+	assert.Empty(t, authInfo.ClientCertificateData)
+	assert.Empty(t, authInfo.ClientKeyData)
+	assert.Equal(t, kubeconfig.ExecCredentialAPIVersion, authInfo.Exec.APIVersion)
+	assert.Equal(t, "talosctl", authInfo.Exec.Command)
+	assert.Equal(t, []string{"kubernetes-credentials", "--nodes", "10.5.0.2"}, authInfo.Exec.Args)
+
+	assert.NoError(t, clientcmd.ConfirmUsable(*config, "admin@talos"))
+}
@@ -7,6 +7,9 @@ package kubeconfig
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 
@@ -61,6 +64,7 @@ func (merger *Merger) Merge(config *clientcmdapi.Config, options MergeOptions) e
 	mappedClusters := map[string]string{}
 	mappedAuthInfos := map[string]string{}
 	mappedContexts := map[string]string{}
+	mergedContexts := map[string]*clientcmdapi.Context{}
 
 	for name, newCluster := range config.Clusters {
 		mergedName := name
@@ -129,26 +133,30 @@ func (merger *Merger) Merge(config *clientcmdapi.Config, options MergeOptions) e
 			mergedName = options.ForceContextName
 		}
 
-		oldContext, exists := merger.Clusters[mergedName]
+		contextCopy := *newContext
+		contextCopy.LocationOfOrigin = ""
+		contextCopy.AuthInfo = mappedName(mappedAuthInfos, contextCopy.AuthInfo)
+		contextCopy.Cluster = mappedName(mappedClusters, contextCopy.Cluster)
 
-		newContext.LocationOfOrigin = ""
+		oldContext, exists := merger.Contexts[mergedName]
 
 		if oldContext != nil {
 			oldContext.LocationOfOrigin = ""
 		}
 
-		if exists && !reflect.DeepEqual(oldContext, newContext) {
-			decision, err := options.ConflictHandler(Cluster, name)
+		if exists && !reflect.DeepEqual(oldContext, &contextCopy) {
+			decision, err := options.ConflictHandler(Context, mergedName)
 			if err != nil {
 				return err
 			}
 
 			if decision == RenameDecision {
-				mergedName = merger.rename(Cluster, mergedName)
+				mergedName = merger.rename(Context, mergedName)
 			}
 		}
 
 		mappedContexts[name] = mergedName
+		mergedContexts[name] = &contextCopy
 	}
 
 	for name, cluster := range config.Clusters {
@@ -171,19 +179,14 @@ func (merger *Merger) Merge(config *clientcmdapi.Config, options MergeOptions) e
 		merger.AuthInfos[newName] = authInfo
 	}
 
-	for name, context := range config.Contexts {
-		contextCopy := *context
-
+	for name := range config.Contexts {
 		newName := mappedContexts[name]
 
 		if newName != name {
 			fmt.Fprintf(options.OutputWriter, "renamed context %q -> %q\n", name, newName)
 		}
 
-		contextCopy.AuthInfo = mappedAuthInfos[contextCopy.AuthInfo]
-		contextCopy.Cluster = mappedClusters[contextCopy.Cluster]
-
-		merger.Contexts[newName] = &contextCopy
+		merger.Contexts[newName] = mergedContexts[name]
 
 		if options.ActivateContext {
 			merger.CurrentContext = newName
@@ -193,6 +196,15 @@ func (merger *Merger) Merge(config *clientcmdapi.Config, options MergeOptions) e
 	return nil
 }
 
+// mappedName returns the new name of the component, or the name itself if the component wasn't merged.
+func mappedName(mapping map[string]string, name string) string {
+	if newName, ok := mapping[name]; ok {
+		return newName
+	}
+
+	return name
+}
+
 // rename the config component until it gets unique.
 func (merger *Merger) rename(component ConfigComponent, name string) (newName string) {
 	i := 0
@@ -220,6 +232,30 @@ func (merger *Merger) rename(component ConfigComponent, name string) (newName st
 }
 
 // Write the kubeconfig back to the file.
+//
+// The file is replaced atomically, so that the existing kubeconfig is not lost if the write fails.
 func (merger *Merger) Write(path string) error {
-	return clientcmd.WriteToFile(clientcmdapi.Config(*merger), path)
+	data, err := clientcmd.Write(clientcmdapi.Config(*merger))
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmpFile.Name()) //nolint: errcheck
+
+	if _, err = tmpFile.Write(data); err != nil {
+		tmpFile.Close() //nolint: errcheck
+
+		return err
+	}
+
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), path)
 }
@@ -357,6 +357,80 @@ func TestMerger(t *testing.T) {
 				OutputWriter:    os.Stdout,
 			},
 		},
+		{ // MergeContextConflict
+			name: "MergeContextConflict",
+			initial: clientcmdapi.Config{
+				AuthInfos: map[string]*clientcmdapi.AuthInfo{
+					"fiz": {
+						ClientCertificate: "cert1",
+					},
+				},
+				Clusters: map[string]*clientcmdapi.Cluster{
+					"buzz": {
+						Server: "example.com",
+					},
+				},
+				Contexts: map[string]*clientcmdapi.Context{
+					"foo@bar": {
+						Cluster:  "buzz",
+						AuthInfo: "fiz",
+					},
+				},
+				CurrentContext: "foo@bar",
+			},
+			new: clientcmdapi.Config{
+				AuthInfos: map[string]*clientcmdapi.AuthInfo{
+					"foo@bar": {
+						ClientCertificate: "cert2",
+					},
+				},
+				Clusters: map[string]*clientcmdapi.Cluster{
+					"bar": {
+						Server: "another.com",
+					},
+				},
+				Contexts: map[string]*clientcmdapi.Context{
+					"foo@bar": {
+						Cluster:  "bar",
+						AuthInfo: "foo@bar",
+					},
+				},
+			},
+			expected: clientcmdapi.Config{
+				AuthInfos: map[string]*clientcmdapi.AuthInfo{
+					"fiz": {
+						ClientCertificate: "cert1",
+					},
+					"foo@bar": {
+						ClientCertificate: "cert2",
+					},
+				},
+				Clusters: map[string]*clientcmdapi.Cluster{
+					"buzz": {
+						Server: "example.com",
+					},
+					"bar": {
+						Server: "another.com",
+					},
+				},
+				Contexts: map[string]*clientcmdapi.Context{
+					"foo@bar": {
+						Cluster:  "buzz",
+						AuthInfo: "fiz",
+					},
+					"foo@bar-1": {
+						Cluster:  "bar",
+						AuthInfo: "foo@bar",
+					},
+				},
+				CurrentContext: "foo@bar-1",
+			},
+			options: kubeconfig.MergeOptions{
+				ActivateContext: true,
+				ConflictHandler: renameAlways,
+				OutputWriter:    os.Stdout,
+			},
+		},
 	} {
 		tt := tt
 
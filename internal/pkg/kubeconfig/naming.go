@@ -0,0 +1,112 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kubeconfig
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Default naming templates, they match the names in the kubeconfig generated by Talos.
+const (
+	DefaultClusterNameTemplate = "{{ .Cluster }}"
+	DefaultContextNameTemplate = "{{ .User }}@{{ .Cluster }}"
+)
+
+// NameTemplateInput is the input of the cluster and context naming templates.
+type NameTemplateInput struct {
+	// Cluster is the name of the cluster, for the context name template it is the result of the cluster name template.
+	Cluster string
+	// User is the name of the user without the cluster suffix.
+	User string
+}
+
+// Rename applies the naming templates to the kubeconfig generated by Talos.
+//
+// Auth infos are always named as `<user>@<cluster>`, so that the auth infos of different clusters
+// don't clash when merged into a single kubeconfig.
+func Rename(config *clientcmdapi.Config, clusterTemplate, contextTemplate string) error {
+	clusterTpl, err := template.New("cluster").Parse(clusterTemplate)
+	if err != nil {
+		return fmt.Errorf("error parsing cluster name template: %w", err)
+	}
+
+	contextTpl, err := template.New("context").Parse(contextTemplate)
+	if err != nil {
+		return fmt.Errorf("error parsing context name template: %w", err)
+	}
+
+	clusters := map[string]*clientcmdapi.Cluster{}
+	authInfos := map[string]*clientcmdapi.AuthInfo{}
+	contexts := map[string]*clientcmdapi.Context{}
+	mappedContexts := map[string]string{}
+
+	for name, context := range config.Contexts {
+		user := strings.TrimSuffix(context.AuthInfo, "@"+context.Cluster)
+
+		clusterName, err := executeNameTemplate(clusterTpl, NameTemplateInput{
+			Cluster: context.Cluster,
+			User:    user,
+		})
+		if err != nil {
+			return err
+		}
+
+		contextName, err := executeNameTemplate(contextTpl, NameTemplateInput{
+			Cluster: clusterName,
+			User:    user,
+		})
+		if err != nil {
+			return err
+		}
+
+		authInfoName := user + "@" + clusterName
+
+		if _, exists := contexts[contextName]; exists {
+			return fmt.Errorf("duplicate context name %q", contextName)
+		}
+
+		if cluster, ok := config.Clusters[context.Cluster]; ok {
+			clusters[clusterName] = cluster
+		}
+
+		if authInfo, ok := config.AuthInfos[context.AuthInfo]; ok {
+			authInfos[authInfoName] = authInfo
+		}
+
+		contextCopy := *context
+		contextCopy.Cluster = clusterName
+		contextCopy.AuthInfo = authInfoName
+
+		contexts[contextName] = &contextCopy
+		mappedContexts[name] = contextName
+	}
+
+	config.Clusters = clusters
+	config.AuthInfos = authInfos
+	config.Contexts = contexts
+	config.CurrentContext = mappedContexts[config.CurrentContext]
+
+	return nil
+}
+
+func executeNameTemplate(tpl *template.Template, input NameTemplateInput) (string, error) {
+	var buf bytes.Buffer
+
+	if err := tpl.Execute(&buf, input); err != nil {
+		return "", fmt.Errorf("error executing %s name template: %w", tpl.Name(), err)
+	}
+
+	name := strings.TrimSpace(buf.String())
+	if name == "" {
+		return "", fmt.Errorf("%s name template produced an empty name", tpl.Name())
+	}
+
+	return name, nil
+}
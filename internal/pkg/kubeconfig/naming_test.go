@@ -0,0 +1,72 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kubeconfig_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/talos-systems/talos/internal/pkg/kubeconfig"
+)
+
+func newTalosConfig() *clientcmdapi.Config {
+	return &clientcmdapi.Config{
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"admin@talos": {
+				ClientCertificate: "cert",
+			},
+		},
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"talos": {
+				Server: "https://10.5.0.2:6443",
+			},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			"admin@talos": {
+				Cluster:   "talos",
+				AuthInfo:  "admin@talos",
+				Namespace: "default",
+			},
+		},
+		CurrentContext: "admin@talos",
+	}
+}
+
+func TestRename(t *testing.T) {
+	config := newTalosConfig()
+
+	require.NoError(t, kubeconfig.Rename(config, kubeconfig.DefaultClusterNameTemplate, kubeconfig.DefaultContextNameTemplate))
+	assert.Equal(t, newTalosConfig(), config)
+
+	config = newTalosConfig()
+
+	require.NoError(t, kubeconfig.Rename(config, "prod-{{ .Cluster }}", "{{ .Cluster }}"))
+
+	assert.Equal(t, map[string]*clientcmdapi.Cluster{
+		"prod-talos": {
+			Server: "https://10.5.0.2:6443",
+		},
+	}, config.Clusters)
+	assert.Equal(t, map[string]*clientcmdapi.AuthInfo{
+		"admin@prod-talos": {
+			ClientCertificate: "cert",
+		},
+	}, config.AuthInfos)
+	assert.Equal(t, map[string]*clientcmdapi.Context{
+		"prod-talos": {
+			Cluster:   "prod-talos",
+			AuthInfo:  "admin@prod-talos",
+			Namespace: "default",
+		},
+	}, config.Contexts)
+	assert.Equal(t, "prod-talos", config.CurrentContext)
+
+	assert.EqualError(t, kubeconfig.Rename(newTalosConfig(), "{{ .Cluster }}", "{{ .Missing }}"),
+		`error executing context name template: template: context:1:3: executing "context" at <.Missing>: can't evaluate field Missing in type kubeconfig.NameTemplateInput`)
+	assert.EqualError(t, kubeconfig.Rename(newTalosConfig(), "{{ .Cluster }}", " "), "context name template produced an empty name")
+}
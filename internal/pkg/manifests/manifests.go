@@ -0,0 +1,141 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package manifests reconciles the Talos-owned bootstrap manifests (CoreDNS, kube-proxy, flannel, PSP and bootstrap RBAC)
+// with the Kubernetes cluster.
+package manifests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.etcd.io/etcd/clientv3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// Manifest is a rendered bootstrap manifest.
+type Manifest struct {
+	Name     string
+	Contents []byte
+}
+
+// Object is a Kubernetes object from a manifest.
+type Object struct {
+	*unstructured.Unstructured
+
+	Manifest string
+}
+
+// Objects decodes the (multi-document) manifest into the objects, empty documents are skipped.
+func (m Manifest) Objects() ([]Object, error) {
+	var objects []Object
+
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(m.Contents), 4096)
+
+	for {
+		var obj map[string]interface{}
+
+		if err := decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objects, nil
+			}
+
+			return nil, fmt.Errorf("error decoding manifest %q: %w", m.Name, err)
+		}
+
+		if len(obj) == 0 {
+			continue
+		}
+
+		u := &unstructured.Unstructured{Object: obj}
+
+		if u.GetKind() == "" || u.GetName() == "" {
+			return nil, fmt.Errorf("object in manifest %q should have kind and name", m.Name)
+		}
+
+		objects = append(objects, Object{Unstructured: u, Manifest: m.Name})
+	}
+}
+
+// ReadDir reads the manifests from the directory, sorted by name.
+//
+// Hidden files are skipped, as they are used to write the manifests atomically.
+func ReadDir(dir string) ([]Manifest, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var manifests []Manifest
+
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+
+		contents, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		manifests = append(manifests, Manifest{Name: file.Name(), Contents: contents})
+	}
+
+	return manifests, nil
+}
+
+// Import moves the manifests from the directory to etcd, so that they are available to all the control plane nodes.
+func Import(ctx context.Context, client *clientv3.Client, dir string) error {
+	manifests, err := ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		if _, err = client.Put(clientv3.WithRequireLeader(ctx), constants.EtcdTalosManifestsKey+"/"+manifest.Name, string(manifest.Contents)); err != nil {
+			return fmt.Errorf("error storing manifest %q: %w", manifest.Name, err)
+		}
+
+		if err = os.Remove(filepath.Join(dir, manifest.Name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Load lists the manifests stored in etcd, sorted by name.
+func Load(ctx context.Context, client *clientv3.Client) ([]Manifest, error) {
+	prefix := constants.EtcdTalosManifestsKey + "/"
+
+	resp, err := client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := make([]Manifest, 0, len(resp.Kvs))
+
+	for _, kv := range resp.Kvs {
+		manifests = append(manifests, Manifest{
+			Name:     strings.TrimPrefix(string(kv.Key), prefix),
+			Contents: kv.Value,
+		})
+	}
+
+	return manifests, nil
+}
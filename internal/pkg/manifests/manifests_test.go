@@ -0,0 +1,97 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/talos-systems/talos/internal/pkg/manifests"
+)
+
+const coreDNS = `---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: coredns
+  namespace: kube-system
+---
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: system:coredns
+`
+
+func TestObjects(t *testing.T) {
+	objects, err := manifests.Manifest{Name: "coredns.yaml", Contents: []byte(coreDNS)}.Objects()
+	require.NoError(t, err)
+
+	require.Len(t, objects, 2)
+	assert.Equal(t, "coredns.yaml", objects[0].Manifest)
+	assert.Equal(t, "ServiceAccount", objects[0].GetKind())
+	assert.Equal(t, "kube-system", objects[0].GetNamespace())
+	assert.Equal(t, "rbac.authorization.k8s.io/v1", objects[1].GetAPIVersion())
+	assert.Equal(t, "system:coredns", objects[1].GetName())
+
+	_, err = manifests.Manifest{Name: "broken.yaml", Contents: []byte("apiVersion: v1\nkind: ConfigMap\n")}.Objects()
+	assert.Error(t, err)
+}
+
+func TestReadDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "kube-proxy.yaml"), []byte("b"), 0o600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "coredns.yaml"), []byte("a"), 0o600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".flannel.yaml"), []byte("c"), 0o600))
+
+	list, err := manifests.ReadDir(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []manifests.Manifest{
+		{Name: "coredns.yaml", Contents: []byte("a")},
+		{Name: "kube-proxy.yaml", Contents: []byte("b")},
+	}, list)
+
+	list, err = manifests.ReadDir(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestDrifted(t *testing.T) {
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":            "coredns",
+			"resourceVersion": "10",
+			"labels": map[string]interface{}{
+				"extra": "label",
+			},
+		},
+		"data": map[string]interface{}{
+			"Corefile": ".:53 {}",
+		},
+	}}
+
+	applied := live.DeepCopy()
+	applied.SetResourceVersion("11")
+	applied.SetManagedFields(nil)
+
+	assert.False(t, manifests.Drifted(live, applied))
+
+	unstructured.SetNestedField(applied.Object, ".:53 { log }", "data", "Corefile") //nolint: errcheck
+
+	assert.True(t, manifests.Drifted(live, applied))
+}
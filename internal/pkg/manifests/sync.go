@@ -0,0 +1,247 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// Sync states of the objects.
+const (
+	// StateCreated means that the object didn't exist and was created.
+	StateCreated = "Created"
+	// StateInSync means that the object matches the manifest.
+	StateInSync = "InSync"
+	// StateDrifted means that the object was changed in the cluster and was reconciled back to the manifest.
+	StateDrifted = "Drifted"
+	// StateError means that the object couldn't be synced.
+	StateError = "Error"
+)
+
+// ObjectStatus is the sync status of an object.
+type ObjectStatus struct {
+	Manifest   string
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	State      string
+	Error      string
+	LastSync   time.Time
+	// Contents is the object from the manifest in YAML.
+	Contents []byte
+}
+
+// Syncer applies the objects with the server-side apply.
+type Syncer struct {
+	client dynamic.Interface
+	mapper *restmapper.DeferredDiscoveryRESTMapper
+}
+
+// NewSyncer creates a new Syncer.
+func NewSyncer(config *rest.Config) (*Syncer, error) {
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	dc, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Syncer{
+		client: client,
+		mapper: restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc)),
+	}, nil
+}
+
+// Sync reconciles the manifests and returns the status of every object.
+//
+// The objects are applied in the manifest order, a failure to apply an object doesn't stop the sync.
+func (s *Syncer) Sync(ctx context.Context, manifests []Manifest) []ObjectStatus {
+	var statuses []ObjectStatus
+
+	for _, manifest := range manifests {
+		objects, err := manifest.Objects()
+		if err != nil {
+			statuses = append(statuses, ObjectStatus{
+				Manifest: manifest.Name,
+				State:    StateError,
+				Error:    err.Error(),
+				LastSync: time.Now(),
+			})
+
+			continue
+		}
+
+		for _, obj := range objects {
+			statuses = append(statuses, s.syncObject(ctx, obj))
+		}
+	}
+
+	return statuses
+}
+
+func (s *Syncer) syncObject(ctx context.Context, obj Object) ObjectStatus {
+	status := ObjectStatus{
+		Manifest:   obj.Manifest,
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		LastSync:   time.Now(),
+	}
+
+	// nolint: errcheck
+	status.Contents, _ = yaml.Marshal(obj.Object)
+
+	state, err := s.apply(ctx, obj)
+	if err != nil {
+		status.State = StateError
+		status.Error = err.Error()
+
+		return status
+	}
+
+	status.State = state
+
+	return status
+}
+
+func (s *Syncer) apply(ctx context.Context, obj Object) (string, error) {
+	gvk := obj.GroupVersionKind()
+
+	mapping, err := s.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			// the kind might be registered later (e.g. by a CRD), so the discovery is refreshed on the next sync
+			s.mapper.Reset()
+		}
+
+		return "", err
+	}
+
+	var resource dynamic.ResourceInterface = s.client.Resource(mapping.Resource)
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		namespace := obj.GetNamespace()
+		if namespace == "" {
+			namespace = metav1.NamespaceDefault
+		}
+
+		resource = s.client.Resource(mapping.Resource).Namespace(namespace)
+	}
+
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return "", err
+	}
+
+	force := true
+
+	state := StateCreated
+
+	live, err := resource.Get(ctx, obj.GetName(), metav1.GetOptions{})
+
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return "", err
+	default:
+		// the dry-run apply shows the object as it would be after the apply
+		var applied *unstructured.Unstructured
+
+		applied, err = resource.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+			FieldManager: constants.ManifestSyncFieldManager,
+			Force:        &force,
+			DryRun:       []string{metav1.DryRunAll},
+		})
+		if err != nil {
+			return "", err
+		}
+
+		if !Drifted(live, applied) {
+			return StateInSync, nil
+		}
+
+		state = StateDrifted
+	}
+
+	if _, err = resource.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+		FieldManager: constants.ManifestSyncFieldManager,
+		Force:        &force,
+	}); err != nil {
+		return "", err
+	}
+
+	return state, nil
+}
+
+// Drifted compares the live object with the result of the dry-run apply of the manifest.
+//
+// Any difference except for the bookkeeping metadata means that the object has drifted from the manifest.
+// Fields which are not set in the manifest (e.g. extra labels added by other managers) are not a drift.
+func Drifted(live, applied *unstructured.Unstructured) bool {
+	return !equality.Semantic.DeepEqual(withoutBookkeeping(live), withoutBookkeeping(applied))
+}
+
+func withoutBookkeeping(obj *unstructured.Unstructured) map[string]interface{} {
+	obj = obj.DeepCopy()
+
+	for _, field := range []string{"managedFields", "resourceVersion", "generation"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+
+	return obj.Object
+}
+
+// Report is the result of the manifest sync.
+type Report struct {
+	LastSync time.Time
+	Objects  []ObjectStatus
+	// Error is set if the manifests couldn't be synced at all, e.g. the cluster is not reachable.
+	Error string
+}
+
+// Status holds the report of the last manifest sync.
+type Status struct {
+	mu     sync.Mutex
+	report Report
+}
+
+// Report returns the last sync report.
+func (s *Status) Report() Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.report
+}
+
+// Update replaces the sync report.
+func (s *Status) Update(report Report) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.report = report
+}
@@ -0,0 +1,193 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package measure implements measured boot of the Talos installation.
+package measure
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/talos-systems/go-procfs/procfs"
+
+	"github.com/talos-systems/talos/internal/pkg/secureboot"
+	"github.com/talos-systems/talos/pkg/attestation"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// Extender extends the PCR with the digest.
+type Extender interface {
+	PCRExtend(pcr int, digest []byte) error
+}
+
+// Measurer extends the PCR with the measurements and keeps the event log.
+type Measurer struct {
+	extender Extender
+	pcr      int
+
+	Events []attestation.Event
+}
+
+// NewMeasurer initializes and returns a Measurer.
+func NewMeasurer(extender Extender, pcr int) *Measurer {
+	return &Measurer{
+		extender: extender,
+		pcr:      pcr,
+	}
+}
+
+// Measure extends the PCR with the SHA-256 digest of the data.
+func (m *Measurer) Measure(typ, description string, r io.Reader) error {
+	h := sha256.New()
+
+	if _, err := io.Copy(h, r); err != nil {
+		return fmt.Errorf("error measuring %s: %w", typ, err)
+	}
+
+	digest := h.Sum(nil)
+
+	if err := m.extender.PCRExtend(m.pcr, digest); err != nil {
+		return fmt.Errorf("error extending PCR %d with %s: %w", m.pcr, typ, err)
+	}
+
+	m.Events = append(m.Events, attestation.Event{
+		PCR:         m.pcr,
+		Type:        typ,
+		Digest:      digest,
+		Description: description,
+	})
+
+	return nil
+}
+
+// MeasureFile extends the PCR with the SHA-256 digest of the file contents.
+func (m *Measurer) MeasureFile(typ, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer f.Close()
+
+	return m.Measure(typ, path, f)
+}
+
+// WriteLog writes the event log to the file.
+func (m *Measurer) WriteLog(path string) error {
+	b, err := json.Marshal(m.Events)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, b, 0o600)
+}
+
+// ReadLog reads the event log written by WriteLog.
+//
+// Missing log means that nothing was measured.
+func ReadLog(path string) ([]attestation.Event, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var events []attestation.Event
+
+	if err = json.Unmarshal(b, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Asset is a measured boot asset.
+type Asset struct {
+	Type string
+	Path string
+}
+
+// loaderEntrySelectedVariable is set by systemd-boot to the name of the booted entry.
+const loaderEntrySelectedVariable = "LoaderEntrySelected-4a67b082-0a4c-41cf-b6c7-440b29bb8c4f"
+
+// BootAssets returns the boot assets of the booted installation.
+//
+// GRUB installations are booted with the kernel and initramfs from the boot partition,
+// the label is derived from the initramfs path on the kernel command line.
+// systemd-boot installations are booted from the unified kernel image on the EFI partition,
+// which is reported by systemd-boot in the EFI variable.
+// No assets are returned if the machine is booted by other means (e.g. PXE or ISO).
+func BootAssets(cmdline *procfs.Cmdline) ([]Asset, error) {
+	if initrd := cmdline.Get("initrd").First(); initrd != nil {
+		label := strings.Trim(filepath.Dir(*initrd), "/")
+
+		if label != "" && !strings.Contains(label, "/") {
+			return []Asset{
+				{
+					Type: attestation.EventKernel,
+					Path: filepath.Join(constants.BootMountPoint, label, constants.KernelAsset),
+				},
+				{
+					Type: attestation.EventInitramfs,
+					Path: filepath.Join(constants.BootMountPoint, label, constants.InitramfsAsset),
+				},
+			}, nil
+		}
+	}
+
+	entry, err := readStringVariable(filepath.Join(secureboot.EFIVarsPath, loaderEntrySelectedVariable))
+	if err != nil {
+		return nil, err
+	}
+
+	if entry == "" || strings.ContainsAny(entry, "/\\") {
+		return nil, nil
+	}
+
+	return []Asset{
+		{
+			Type: attestation.EventUKI,
+			Path: filepath.Join(constants.EFIMountPoint, "EFI", "Linux", entry),
+		},
+	}, nil
+}
+
+// readStringVariable reads UTF-16 EFI variable, missing variable is returned as empty string.
+func readStringVariable(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	// efivarfs prefixes the contents with 4 bytes of attributes
+	if len(b) < 4 || len(b)%2 != 0 {
+		return "", fmt.Errorf("unexpected EFI variable size %d", len(b))
+	}
+
+	b = b[4:]
+
+	u := make([]uint16, 0, len(b)/2)
+
+	for i := 0; i < len(b); i += 2 {
+		u = append(u, uint16(b[i])|uint16(b[i+1])<<8)
+	}
+
+	return strings.TrimRight(string(utf16.Decode(u)), "\x00"), nil
+}
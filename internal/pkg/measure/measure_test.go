@@ -0,0 +1,128 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package measure_test
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/talos-systems/go-procfs/procfs"
+
+	"github.com/talos-systems/talos/internal/pkg/measure"
+	"github.com/talos-systems/talos/internal/pkg/secureboot"
+	"github.com/talos-systems/talos/pkg/attestation"
+)
+
+// fakeExtender keeps the PCR values in memory.
+type fakeExtender struct {
+	pcrs map[int][]byte
+}
+
+func (f *fakeExtender) PCRExtend(pcr int, digest []byte) error {
+	value, ok := f.pcrs[pcr]
+	if !ok {
+		value = make([]byte, sha256.Size)
+	}
+
+	f.pcrs[pcr] = attestation.Extend(value, digest)
+
+	return nil
+}
+
+type MeasureSuite struct {
+	suite.Suite
+
+	dir string
+
+	efiVarsPath string
+}
+
+func (suite *MeasureSuite) SetupTest() {
+	var err error
+
+	suite.dir, err = ioutil.TempDir("", "talos")
+	suite.Require().NoError(err)
+
+	suite.efiVarsPath = secureboot.EFIVarsPath
+	secureboot.EFIVarsPath = suite.dir
+}
+
+func (suite *MeasureSuite) TearDownTest() {
+	secureboot.EFIVarsPath = suite.efiVarsPath
+
+	suite.Require().NoError(os.RemoveAll(suite.dir))
+}
+
+func (suite *MeasureSuite) TestMeasure() {
+	extender := &fakeExtender{pcrs: map[int][]byte{}}
+
+	m := measure.NewMeasurer(extender, 11)
+
+	suite.Require().NoError(m.Measure(attestation.EventCmdline, "/proc/cmdline", strings.NewReader("talos.platform=metal")))
+
+	path := filepath.Join(suite.dir, "config.yaml")
+	suite.Require().NoError(ioutil.WriteFile(path, []byte("version: v1alpha1"), 0o600))
+
+	suite.Require().NoError(m.MeasureFile(attestation.EventConfig, path))
+
+	suite.Require().Len(m.Events, 2)
+
+	digest := sha256.Sum256([]byte("talos.platform=metal"))
+	suite.Assert().Equal(attestation.Event{PCR: 11, Type: attestation.EventCmdline, Digest: digest[:], Description: "/proc/cmdline"}, m.Events[0])
+
+	suite.Assert().Equal(extender.pcrs, attestation.Replay(m.Events))
+
+	logPath := filepath.Join(suite.dir, "measurements.json")
+	suite.Require().NoError(m.WriteLog(logPath))
+
+	events, err := measure.ReadLog(logPath)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(m.Events, events)
+
+	events, err = measure.ReadLog(filepath.Join(suite.dir, "missing.json"))
+	suite.Require().NoError(err)
+	suite.Assert().Empty(events)
+}
+
+func (suite *MeasureSuite) TestBootAssetsGrub() {
+	assets, err := measure.BootAssets(procfs.NewCmdline("talos.platform=metal initrd=/B/initramfs.xz"))
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal([]measure.Asset{
+		{Type: attestation.EventKernel, Path: "/boot/B/vmlinuz"},
+		{Type: attestation.EventInitramfs, Path: "/boot/B/initramfs.xz"},
+	}, assets)
+}
+
+func (suite *MeasureSuite) TestBootAssetsSDBoot() {
+	assets, err := measure.BootAssets(procfs.NewCmdline("talos.platform=metal"))
+	suite.Require().NoError(err)
+	suite.Assert().Empty(assets)
+
+	// attributes followed by UTF-16LE "Talos-A.efi\0"
+	contents := []byte{0x06, 0, 0, 0}
+
+	for _, c := range "Talos-A.efi\x00" {
+		contents = append(contents, byte(c), 0)
+	}
+
+	suite.Require().NoError(ioutil.WriteFile(filepath.Join(suite.dir, "LoaderEntrySelected-4a67b082-0a4c-41cf-b6c7-440b29bb8c4f"), contents, 0o600))
+
+	assets, err = measure.BootAssets(procfs.NewCmdline("talos.platform=metal"))
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal([]measure.Asset{
+		{Type: attestation.EventUKI, Path: "/boot/EFI/EFI/Linux/Talos-A.efi"},
+	}, assets)
+}
+
+func TestMeasureSuite(t *testing.T) {
+	suite.Run(t, new(MeasureSuite))
+}
@@ -0,0 +1,219 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package mgmttunnel implements the management WireGuard tunnel to the provisioning server.
+//
+// Node generates a WireGuard key pair on every boot and registers the public key with the provisioning
+// server API. Provisioning server responds with the tunnel settings: the server WireGuard endpoint and public key,
+// and the address assigned to the node in the management network. Talos API of the node is reachable
+// from the management network, persistent keepalives keep the tunnel open for nodes behind NAT.
+package mgmttunnel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/jsimonetti/rtnetlink"
+	"github.com/jsimonetti/rtnetlink/rtnl"
+	"github.com/talos-systems/go-procfs/procfs"
+	"golang.org/x/sys/unix"
+	"golang.zx2c4.com/wireguard/wgctrl"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// ProvisionRequest is sent to the provisioning server API.
+type ProvisionRequest struct {
+	NodeID    string `json:"nodeId"`
+	PublicKey string `json:"publicKey"`
+}
+
+// ProvisionResponse is returned by the provisioning server API.
+type ProvisionResponse struct {
+	// ServerEndpoint is the WireGuard endpoint of the server (host:port).
+	ServerEndpoint string `json:"serverEndpoint"`
+	// ServerPublicKey is the WireGuard public key of the server.
+	ServerPublicKey string `json:"serverPublicKey"`
+	// NodeAddress is the address assigned to the node in CIDR notation, the prefix defines the management network.
+	NodeAddress string `json:"nodeAddress"`
+}
+
+// Tunnel is the parsed management tunnel configuration.
+type Tunnel struct {
+	ServerEndpoint  *net.UDPAddr
+	ServerPublicKey wgtypes.Key
+	NodeAddress     *net.IPNet
+	Network         *net.IPNet
+}
+
+// APIURL returns the provisioning server API URL.
+//
+// Machine configuration takes precedence over the kernel argument.
+func APIURL(cfg config.Provider) string {
+	if cfg != nil {
+		if apiURL := cfg.Machine().Features().ManagementTunnel().APIURL(); apiURL != "" {
+			return apiURL
+		}
+	}
+
+	if option := procfs.ProcCmdline().Get(constants.KernelParamManagementTunnel).First(); option != nil {
+		return *option
+	}
+
+	return ""
+}
+
+// Provision registers the node public key with the provisioning server.
+func Provision(ctx context.Context, apiURL, nodeID string, publicKey wgtypes.Key) (*Tunnel, error) {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	u.Path = path.Join(u.Path, "v1", "provision")
+
+	body, err := json.Marshal(&ProvisionRequest{
+		NodeID:    nodeID,
+		PublicKey: publicKey.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint: errcheck
+	defer resp.Body.Close()
+
+	body, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response from provisioning server: %s", resp.Status)
+	}
+
+	var provisionResp ProvisionResponse
+
+	if err = json.Unmarshal(body, &provisionResp); err != nil {
+		return nil, fmt.Errorf("error decoding provisioning server response: %w", err)
+	}
+
+	return provisionResp.Parse()
+}
+
+// Parse validates the provisioning server response.
+func (resp *ProvisionResponse) Parse() (*Tunnel, error) {
+	var (
+		tunnel Tunnel
+		err    error
+	)
+
+	if tunnel.ServerEndpoint, err = net.ResolveUDPAddr("udp", resp.ServerEndpoint); err != nil {
+		return nil, fmt.Errorf("invalid server endpoint %q: %w", resp.ServerEndpoint, err)
+	}
+
+	if tunnel.ServerPublicKey, err = wgtypes.ParseKey(resp.ServerPublicKey); err != nil {
+		return nil, fmt.Errorf("invalid server public key: %w", err)
+	}
+
+	var ip net.IP
+
+	if ip, tunnel.Network, err = net.ParseCIDR(resp.NodeAddress); err != nil {
+		return nil, fmt.Errorf("invalid node address %q: %w", resp.NodeAddress, err)
+	}
+
+	tunnel.NodeAddress = &net.IPNet{
+		IP:   ip,
+		Mask: tunnel.Network.Mask,
+	}
+
+	return &tunnel, nil
+}
+
+// Up creates the WireGuard link and configures it for the tunnel.
+//
+// Existing link with the same name is replaced.
+func Up(linkName string, privateKey wgtypes.Key, tunnel *Tunnel) error {
+	conn, err := rtnl.Dial(nil)
+	if err != nil {
+		return err
+	}
+
+	// nolint: errcheck
+	defer conn.Close()
+
+	if existing, err := net.InterfaceByName(linkName); err == nil {
+		if err = conn.Conn.Link.Delete(uint32(existing.Index)); err != nil {
+			return fmt.Errorf("error removing existing link %q: %w", linkName, err)
+		}
+	}
+
+	if err = conn.Conn.Link.New(&rtnetlink.LinkMessage{
+		Family: unix.AF_UNSPEC,
+		Attributes: &rtnetlink.LinkAttributes{
+			Name: linkName,
+			Info: &rtnetlink.LinkInfo{Kind: "wireguard"},
+		},
+	}); err != nil {
+		return fmt.Errorf("error creating link %q: %w", linkName, err)
+	}
+
+	wgClient, err := wgctrl.New()
+	if err != nil {
+		return err
+	}
+
+	// nolint: errcheck
+	defer wgClient.Close()
+
+	keepalive := constants.ManagementTunnelKeepaliveInterval
+
+	if err = wgClient.ConfigureDevice(linkName, wgtypes.Config{
+		PrivateKey:   &privateKey,
+		ReplacePeers: true,
+		Peers: []wgtypes.PeerConfig{
+			{
+				PublicKey:                   tunnel.ServerPublicKey,
+				Endpoint:                    tunnel.ServerEndpoint,
+				PersistentKeepaliveInterval: &keepalive,
+				ReplaceAllowedIPs:           true,
+				AllowedIPs:                  []net.IPNet{*tunnel.Network},
+			},
+		},
+	}); err != nil {
+		return fmt.Errorf("error configuring wireguard device %q: %w", linkName, err)
+	}
+
+	link, err := net.InterfaceByName(linkName)
+	if err != nil {
+		return err
+	}
+
+	if err = conn.AddrAdd(link, tunnel.NodeAddress); err != nil {
+		return fmt.Errorf("error adding address %s to %q: %w", tunnel.NodeAddress, linkName, err)
+	}
+
+	return conn.LinkUp(link)
+}
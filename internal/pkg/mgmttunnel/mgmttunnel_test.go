@@ -0,0 +1,77 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mgmttunnel_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	"github.com/talos-systems/talos/internal/pkg/mgmttunnel"
+)
+
+func TestProvision(t *testing.T) {
+	serverKey, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	nodeKey, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/provision" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		var req mgmttunnel.ProvisionRequest
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.NodeID != "node1" || req.PublicKey != nodeKey.PublicKey().String() {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		json.NewEncoder(w).Encode(&mgmttunnel.ProvisionResponse{ //nolint: errcheck
+			ServerEndpoint:  "127.0.0.1:51821",
+			ServerPublicKey: serverKey.PublicKey().String(),
+			NodeAddress:     "fdae:41e4:649b:9303::2/64",
+		})
+	}))
+	defer srv.Close()
+
+	tunnel, err := mgmttunnel.Provision(context.Background(), srv.URL+"/api/", "node1", nodeKey.PublicKey())
+	require.NoError(t, err)
+
+	assert.Equal(t, "127.0.0.1:51821", tunnel.ServerEndpoint.String())
+	assert.Equal(t, serverKey.PublicKey(), tunnel.ServerPublicKey)
+	assert.Equal(t, "fdae:41e4:649b:9303::2/64", tunnel.NodeAddress.String())
+	assert.Equal(t, "fdae:41e4:649b:9303::/64", tunnel.Network.String())
+
+	_, err = mgmttunnel.Provision(context.Background(), srv.URL+"/api/", "node2", nodeKey.PublicKey())
+	assert.EqualError(t, err, "unexpected response from provisioning server: 400 Bad Request")
+}
+
+func TestParse(t *testing.T) {
+	_, err := (&mgmttunnel.ProvisionResponse{
+		ServerEndpoint:  "127.0.0.1:51821",
+		ServerPublicKey: "foo",
+		NodeAddress:     "fdae:41e4:649b:9303::2/64",
+	}).Parse()
+	assert.Error(t, err)
+
+	_, err = (&mgmttunnel.ProvisionResponse{
+		ServerEndpoint:  "127.0.0.1:51821",
+		ServerPublicKey: "2BZrX5gGk8S7rNNoKz0NaQNyLJyE9ZEeGtHeVdmTGWc=",
+		NodeAddress:     "fdae:41e4:649b:9303::2",
+	}).Parse()
+	assert.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -305,13 +306,19 @@ func (p *Point) Move(prefix string) (err error) {
 }
 
 // ResizePartition resizes a partition to the maximum size allowed.
+//
+// The partition should be the last one on the disk.
 func (p *Point) ResizePartition() (resized bool, err error) {
-	var devname string
+	var devname, partno string
 
 	if devname, err = util.DevnameFromPartname(p.Source()); err != nil {
 		return false, err
 	}
 
+	if partno, err = util.PartNo(p.Source()); err != nil {
+		return false, err
+	}
+
 	bd, err := blockdevice.Open("/dev/" + devname)
 	if err != nil {
 		return false, fmt.Errorf("error opening block device %q: %w", devname, err)
@@ -330,7 +337,7 @@ func (p *Point) ResizePartition() (resized bool, err error) {
 	}
 
 	for _, partition := range pt.Partitions().Items() {
-		if partition.Name == constants.EphemeralPartitionLabel {
+		if strconv.Itoa(int(partition.Number)) == partno {
 			resized, err := pt.Resize(partition)
 			if err != nil {
 				return false, err
@@ -350,8 +357,16 @@ func (p *Point) ResizePartition() (resized bool, err error) {
 }
 
 // GrowFilesystem grows a partition's filesystem to the maximum size allowed.
-// NB: An XFS partition MUST be mounted, or this will fail.
+// NB: The partition MUST be mounted, or this will fail.
 func (p *Point) GrowFilesystem() (err error) {
+	if p.fstype == "ext4" {
+		if err = makefs.Ext4Grow(p.Source()); err != nil {
+			return fmt.Errorf("resize2fs: %w", err)
+		}
+
+		return nil
+	}
+
 	if err = makefs.XFSGrow(p.Target()); err != nil {
 		return fmt.Errorf("xfs_growfs: %w", err)
 	}
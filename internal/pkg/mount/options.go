@@ -13,6 +13,7 @@ type Options struct {
 	Resize        bool
 	Overlay       bool
 	SkipIfMounted bool
+	ProjectQuota  bool
 }
 
 // Option is the functional option func.
@@ -62,6 +63,14 @@ func WithOverlay(o bool) Option {
 	}
 }
 
+// WithProjectQuota indicates that the filesystem should be mounted with the
+// project quotas enabled.
+func WithProjectQuota(o bool) Option {
+	return func(args *Options) {
+		args.ProjectQuota = o
+	}
+}
+
 // NewDefaultOptions initializes a Options struct with default values.
 func NewDefaultOptions(setters ...Option) *Options {
 	opts := &Options{
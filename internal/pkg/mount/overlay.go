@@ -16,6 +16,8 @@ func OverlayMountPoints() (mountpoints *Points, err error) {
 	overlays := []string{
 		"/etc/kubernetes",
 		"/etc/cni",
+		"/etc/cri/conf.d",
+		"/etc/ssl/certs",
 		"/usr/libexec/kubernetes",
 		"/usr/etc/udev",
 		"/opt",
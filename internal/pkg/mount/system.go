@@ -7,10 +7,14 @@ package mount
 import (
 	"fmt"
 	"log"
+	"path/filepath"
 
+	"github.com/talos-systems/go-blockdevice/blockdevice"
 	"github.com/talos-systems/go-blockdevice/blockdevice/probe"
+	"github.com/talos-systems/go-blockdevice/blockdevice/util"
 	"golang.org/x/sys/unix"
 
+	"github.com/talos-systems/talos/internal/pkg/encryption"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
@@ -22,7 +26,7 @@ import (
 func SystemMountPointsForDevice(devpath string) (mountpoints *Points, err error) {
 	mountpoints = NewMountPoints()
 
-	for _, name := range []string{constants.EphemeralPartitionLabel, constants.BootPartitionLabel, constants.EFIPartitionLabel, constants.StatePartitionLabel} {
+	for _, name := range []string{constants.EphemeralPartitionLabel, constants.BootPartitionLabel, constants.EFIPartitionLabel, constants.StatePartitionLabel, constants.ImageCachePartitionLabel} {
 		var target string
 
 		switch name {
@@ -34,6 +38,8 @@ func SystemMountPointsForDevice(devpath string) (mountpoints *Points, err error)
 			target = constants.EFIMountPoint
 		case constants.StatePartitionLabel:
 			target = constants.StateMountPoint
+		case constants.ImageCachePartitionLabel:
+			target = constants.ImageCacheMountPoint
 		}
 
 		var dev *probe.ProbedBlockDevice
@@ -46,6 +52,16 @@ func SystemMountPointsForDevice(devpath string) (mountpoints *Points, err error)
 				continue
 			}
 
+			if name == constants.ImageCachePartitionLabel {
+				// The image cache is optional.
+				continue
+			}
+
+			if name == constants.EphemeralPartitionLabel && isEncrypted(devpath, name) {
+				// The encrypted EPHEMERAL partition is not used by the installer.
+				continue
+			}
+
 			return nil, fmt.Errorf("probe device for filesystem %s: %w", name, err)
 		}
 
@@ -59,6 +75,39 @@ func SystemMountPointsForDevice(devpath string) (mountpoints *Points, err error)
 	return mountpoints, nil
 }
 
+// isEncrypted checks whether the partition with the specified name on the device is encrypted.
+func isEncrypted(devpath, name string) bool {
+	bd, err := blockdevice.Open(devpath)
+	if err != nil {
+		return false
+	}
+
+	// nolint: errcheck
+	defer bd.Close()
+
+	pt, err := bd.PartitionTable()
+	if err != nil {
+		return false
+	}
+
+	for _, part := range pt.Partitions().Items() {
+		if part.Name != name {
+			continue
+		}
+
+		partpath, err := util.PartPath(filepath.Base(devpath), int(part.Number))
+		if err != nil {
+			return false
+		}
+
+		_, err = encryption.ReadHeader(partpath)
+
+		return err == nil
+	}
+
+	return false
+}
+
 // SystemMountPointForLabel returns a mount point for the specified device and label.
 func SystemMountPointForLabel(label string, opts ...Option) (mountpoint *Point, err error) {
 	var target string
@@ -67,13 +116,16 @@ func SystemMountPointForLabel(label string, opts ...Option) (mountpoint *Point,
 	case constants.EphemeralPartitionLabel:
 		target = constants.EphemeralMountPoint
 
-		opts = append(opts, WithResize(true))
+		// resize by default, but allow to override it
+		opts = append([]Option{WithResize(true)}, opts...)
 	case constants.BootPartitionLabel:
 		target = constants.BootMountPoint
 	case constants.EFIPartitionLabel:
 		target = constants.EFIMountPoint
 	case constants.StatePartitionLabel:
 		target = constants.StateMountPoint
+	case constants.ImageCachePartitionLabel:
+		target = constants.ImageCacheMountPoint
 	default:
 		return nil, fmt.Errorf("unknown label: %q", label)
 	}
@@ -81,8 +133,8 @@ func SystemMountPointForLabel(label string, opts ...Option) (mountpoint *Point,
 	var dev *probe.ProbedBlockDevice
 
 	if dev, err = probe.GetDevWithFileSystemLabel(label); err != nil {
-		// A boot partitition is not required.
-		if label == constants.BootPartitionLabel {
+		// A boot partitition and an image cache are not required.
+		if label == constants.BootPartitionLabel || label == constants.ImageCachePartitionLabel {
 			return nil, nil
 		}
 
@@ -94,6 +146,10 @@ func SystemMountPointForLabel(label string, opts ...Option) (mountpoint *Point,
 
 	mountpoint = NewMountPoint(dev.Path, target, dev.SuperBlock.Type(), unix.MS_NOATIME, "", opts...)
 
+	if mountpoint.ProjectQuota {
+		mountpoint.data = "prjquota"
+	}
+
 	return mountpoint, nil
 }
 
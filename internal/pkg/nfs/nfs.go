@@ -0,0 +1,25 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package nfs provides the NFS client configuration.
+package nfs
+
+import (
+	"fmt"
+)
+
+// KernelModules is the list of NFS client modules loaded on boot.
+//
+// Kernel can't load filesystem modules on demand, as there is no modprobe in the rootfs.
+var KernelModules = []string{"nfs", "nfsv3", "nfsv4"}
+
+// IdmapdConfig renders the idmapd.conf for the NFSv4 ID mapping domain.
+func IdmapdConfig(domain string) []byte {
+	return []byte(fmt.Sprintf("[General]\nDomain = %s\n", domain))
+}
+
+// MountConfig renders the nfsmount.conf with the default protocol version.
+func MountConfig(version string) []byte {
+	return []byte(fmt.Sprintf("[ NFSMount_Global_Options ]\nDefaultvers=%s\n", version))
+}
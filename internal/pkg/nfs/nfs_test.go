@@ -0,0 +1,21 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package nfs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/talos-systems/talos/internal/pkg/nfs"
+)
+
+func TestIdmapdConfig(t *testing.T) {
+	assert.Equal(t, "[General]\nDomain = example.com\n", string(nfs.IdmapdConfig("example.com")))
+}
+
+func TestMountConfig(t *testing.T) {
+	assert.Equal(t, "[ NFSMount_Global_Options ]\nDefaultvers=4.1\n", string(nfs.MountConfig("4.1")))
+}
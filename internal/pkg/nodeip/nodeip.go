@@ -0,0 +1,86 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package nodeip selects the node addresses advertised to Kubernetes.
+package nodeip
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// FilterIPs returns the addresses which match the subnets.
+//
+// Subnets prefixed with `!` exclude the matching addresses. If there are only
+// excluding subnets, all the addresses which are not excluded are returned.
+func FilterIPs(ips []net.IP, subnets []string) ([]net.IP, error) {
+	var include, exclude []*net.IPNet
+
+	for _, subnet := range subnets {
+		negate := strings.HasPrefix(subnet, "!")
+
+		_, network, err := net.ParseCIDR(strings.TrimPrefix(subnet, "!"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse subnet %q: %w", subnet, err)
+		}
+
+		if negate {
+			exclude = append(exclude, network)
+		} else {
+			include = append(include, network)
+		}
+	}
+
+	result := []net.IP{}
+
+	for _, ip := range ips {
+		if len(include) > 0 && !contains(include, ip) {
+			continue
+		}
+
+		if contains(exclude, ip) {
+			continue
+		}
+
+		result = append(result, ip)
+	}
+
+	return result, nil
+}
+
+// NodeIPs picks the first IPv4 and the first IPv6 address of the list.
+func NodeIPs(ips []net.IP) []net.IP {
+	var ipv4, ipv6 net.IP
+
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			if ipv4 == nil {
+				ipv4 = ip
+			}
+		} else if ipv6 == nil {
+			ipv6 = ip
+		}
+	}
+
+	result := []net.IP{}
+
+	for _, ip := range []net.IP{ipv4, ipv6} {
+		if ip != nil {
+			result = append(result, ip)
+		}
+	}
+
+	return result
+}
+
+func contains(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,71 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package nodeip_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/nodeip"
+)
+
+func parseIPs(addrs ...string) []net.IP {
+	ips := make([]net.IP, len(addrs))
+
+	for i, addr := range addrs {
+		ips[i] = net.ParseIP(addr)
+	}
+
+	return ips
+}
+
+func TestFilterIPs(t *testing.T) {
+	ips := parseIPs("10.0.0.2", "192.168.1.10", "172.16.0.5", "fd00::2", "2001:db8::10")
+
+	for _, tt := range []struct {
+		name     string
+		subnets  []string
+		expected []net.IP
+	}{
+		{
+			name:     "no subnets",
+			expected: ips,
+		},
+		{
+			name:     "include",
+			subnets:  []string{"192.168.0.0/16", "2001:db8::/32"},
+			expected: parseIPs("192.168.1.10", "2001:db8::10"),
+		},
+		{
+			name:     "exclude",
+			subnets:  []string{"!10.0.0.0/8", "!fd00::/8"},
+			expected: parseIPs("192.168.1.10", "172.16.0.5", "2001:db8::10"),
+		},
+		{
+			name:     "include and exclude",
+			subnets:  []string{"0.0.0.0/0", "!172.16.0.0/12", "!10.0.0.0/8"},
+			expected: parseIPs("192.168.1.10"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := nodeip.FilterIPs(ips, tt.subnets)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	_, err := nodeip.FilterIPs(ips, []string{"192.168.0.0"})
+	assert.Error(t, err)
+}
+
+func TestNodeIPs(t *testing.T) {
+	assert.Equal(t, parseIPs("192.168.1.10", "2001:db8::10"), nodeip.NodeIPs(parseIPs("2001:db8::10", "192.168.1.10", "10.0.0.2", "fd00::2")))
+	assert.Equal(t, parseIPs("10.0.0.2"), nodeip.NodeIPs(parseIPs("10.0.0.2", "10.0.0.3")))
+	assert.Empty(t, nodeip.NodeIPs(nil))
+}
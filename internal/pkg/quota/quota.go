@@ -0,0 +1,94 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package quota manages the XFS project quotas.
+package quota
+
+import (
+	"fmt"
+	"hash/crc32"
+	"math"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/talos-systems/talos/pkg/cmd"
+)
+
+// run executes the commands, it is replaced in the tests.
+var run = cmd.Run
+
+// project returns the project ID and the extended flags of the directory, it is replaced in the tests.
+var project = getProject
+
+// fsIOCFSGetXAttr is FS_IOC_FSGETXATTR: _IOR('X', 31, struct fsxattr).
+const fsIOCFSGetXAttr = 0x801c581f
+
+// xflagProjInherit is FS_XFLAG_PROJINHERIT: new files inherit the project ID of the directory.
+const xflagProjInherit = 0x200
+
+// fsxattr is struct fsxattr from linux/fs.h.
+type fsxattr struct {
+	xflags     uint32
+	extsize    uint32
+	nextents   uint32
+	projid     uint32
+	cowextsize uint32
+	pad        [8]byte
+}
+
+// ProjectID returns the project ID for the directory.
+//
+// Project ID is derived from the path, so that it doesn't change when the quotas are reordered.
+func ProjectID(path string) uint32 {
+	return crc32.ChecksumIEEE([]byte(path))%math.MaxInt32 + 1
+}
+
+// SetProjectQuota assigns the directory tree to the project and sets the hard limit on the disk usage of the project.
+//
+// The mountpoint is the mount point of the XFS filesystem with the project quotas enabled.
+// The directory tree is assigned to the project only once, as the new files inherit the project of the directory.
+func SetProjectQuota(mountpoint, path string, limit uint64) error {
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return err
+	}
+
+	id := ProjectID(path)
+
+	projid, xflags, err := project(path)
+	if err != nil {
+		return fmt.Errorf("error reading project of %q: %w", path, err)
+	}
+
+	if projid != id || xflags&xflagProjInherit == 0 {
+		if _, err = run("xfs_quota", "-x", "-c", fmt.Sprintf("project -s -p %s %d", path, id), mountpoint); err != nil {
+			return fmt.Errorf("error assigning %q to project %d: %w", path, id, err)
+		}
+	}
+
+	if _, err = run("xfs_quota", "-x", "-c", fmt.Sprintf("limit -p bhard=%d %d", limit, id), mountpoint); err != nil {
+		return fmt.Errorf("error setting limit for %q: %w", path, err)
+	}
+
+	return nil
+}
+
+func getProject(path string) (projid, xflags uint32, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// nolint: errcheck
+	defer f.Close()
+
+	var attr fsxattr
+
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), fsIOCFSGetXAttr, uintptr(unsafe.Pointer(&attr))); errno != 0 {
+		return 0, 0, errno
+	}
+
+	return attr.projid, attr.xflags, nil
+}
@@ -0,0 +1,64 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package quota
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockQuota records the commands and reports the project of the directories.
+func mockQuota(t *testing.T, projid, xflags uint32) *[]string {
+	var commands []string
+
+	oldRun, oldProject := run, project
+
+	t.Cleanup(func() { run, project = oldRun, oldProject })
+
+	run = func(name string, args ...string) (string, error) {
+		commands = append(commands, strings.Join(append([]string{name}, args...), " "))
+
+		return "", nil
+	}
+
+	project = func(string) (uint32, uint32, error) {
+		return projid, xflags, nil
+	}
+
+	return &commands
+}
+
+func TestProjectID(t *testing.T) {
+	assert.Equal(t, ProjectID("/var/log"), ProjectID("/var/log"))
+	assert.NotEqual(t, ProjectID("/var/log"), ProjectID("/var/lib/containerd"))
+	assert.NotZero(t, ProjectID(""))
+}
+
+func TestSetProjectQuota(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "containerd")
+	id := ProjectID(path)
+
+	commands := mockQuota(t, 0, 0)
+
+	require.NoError(t, SetProjectQuota("/var", path, 1000))
+	assert.DirExists(t, path)
+	assert.Equal(t, []string{
+		fmt.Sprintf("xfs_quota -x -c project -s -p %s %d /var", path, id),
+		fmt.Sprintf("xfs_quota -x -c limit -p bhard=1000 %d /var", id),
+	}, *commands)
+
+	// directory is already assigned to the project
+	commands = mockQuota(t, id, xflagProjInherit)
+
+	require.NoError(t, SetProjectQuota("/var", path, 2000))
+	assert.Equal(t, []string{
+		fmt.Sprintf("xfs_quota -x -c limit -p bhard=2000 %d /var", id),
+	}, *commands)
+}
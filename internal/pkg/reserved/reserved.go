@@ -0,0 +1,104 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package reserved computes the node resources the kubelet reserves for the system and Kubernetes daemons.
+package reserved
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// SystemCPU is the CPU reserved for the Talos system services.
+	SystemCPU = "50m"
+	// SystemMemory is the memory reserved for the Talos system services.
+	SystemMemory = "256Mi"
+
+	mebibyte = 1024 * 1024
+	gibibyte = 1024 * mebibyte
+)
+
+// step is the share of the resource reserved up to the limit.
+type step struct {
+	limit float64
+	share float64
+}
+
+// cpuSteps follow the GKE kube-reserved CPU formula (in cores).
+var cpuSteps = []step{
+	{limit: 1, share: 0.06},
+	{limit: 2, share: 0.01},
+	{limit: 4, share: 0.005},
+	{limit: math.Inf(1), share: 0.0025},
+}
+
+// memorySteps follow the GKE kube-reserved memory formula (in GiB).
+var memorySteps = []step{
+	{limit: 4, share: 0.25},
+	{limit: 8, share: 0.2},
+	{limit: 16, share: 0.1},
+	{limit: 128, share: 0.06},
+	{limit: math.Inf(1), share: 0.02},
+}
+
+// minKubeMemory is reserved on the nodes with less than 1 GiB of memory.
+const minKubeMemory = 255 * mebibyte
+
+// System returns the resources reserved for the Talos system services.
+func System() map[string]string {
+	return map[string]string{
+		"cpu":    SystemCPU,
+		"memory": SystemMemory,
+	}
+}
+
+// Kube returns the resources reserved for the kubelet and the container runtime
+// based on the number of CPUs and the memory of the node (in bytes).
+func Kube(cpus int, memory uint64) map[string]string {
+	cpu := reserve(cpuSteps, float64(cpus))
+
+	mem := uint64(minKubeMemory)
+	if memory >= gibibyte {
+		mem = uint64(reserve(memorySteps, float64(memory)/gibibyte) * gibibyte)
+	}
+
+	return map[string]string{
+		"cpu":    fmt.Sprintf("%dm", int64(math.Round(cpu*1000))),
+		"memory": fmt.Sprintf("%dMi", mem/mebibyte),
+	}
+}
+
+// Merge returns the defaults with the overrides applied.
+func Merge(defaults, overrides map[string]string) map[string]string {
+	result := make(map[string]string, len(defaults)+len(overrides))
+
+	for name, value := range defaults {
+		result[name] = value
+	}
+
+	for name, value := range overrides {
+		result[name] = value
+	}
+
+	return result
+}
+
+func reserve(steps []step, total float64) float64 {
+	var (
+		reserved float64
+		lower    float64
+	)
+
+	for _, s := range steps {
+		if total <= lower {
+			break
+		}
+
+		reserved += (math.Min(total, s.limit) - lower) * s.share
+		lower = s.limit
+	}
+
+	return reserved
+}
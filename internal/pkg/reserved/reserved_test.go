@@ -0,0 +1,62 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package reserved_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/talos-systems/talos/internal/pkg/reserved"
+)
+
+const gibibyte = 1024 * 1024 * 1024
+
+func TestKube(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		cpus     int
+		memory   uint64
+		expected map[string]string
+	}{
+		{
+			name:     "small",
+			cpus:     1,
+			memory:   gibibyte / 2,
+			expected: map[string]string{"cpu": "60m", "memory": "255Mi"},
+		},
+		{
+			name:     "medium",
+			cpus:     2,
+			memory:   4 * gibibyte,
+			expected: map[string]string{"cpu": "70m", "memory": "1024Mi"},
+		},
+		{
+			name:     "large",
+			cpus:     4,
+			memory:   8 * gibibyte,
+			expected: map[string]string{"cpu": "80m", "memory": "1843Mi"},
+		},
+		{
+			name:     "xlarge",
+			cpus:     8,
+			memory:   32 * gibibyte,
+			expected: map[string]string{"cpu": "90m", "memory": "3645Mi"},
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, reserved.Kube(tt.cpus, tt.memory))
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	assert.Equal(t,
+		map[string]string{"cpu": "100m", "memory": "256Mi", "pid": "1000"},
+		reserved.Merge(reserved.System(), map[string]string{"cpu": "100m", "pid": "1000"}),
+	)
+}
@@ -0,0 +1,151 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package schedule implements delayed execution of the disruptive machine actions.
+//
+// Action might be delayed until the specific point in time (not before) and/or
+// restricted to the daily maintenance window. Pending actions are kept in memory,
+// so they don't survive a reboot of the machine.
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Window is a daily maintenance window in UTC.
+type Window struct {
+	// Start is the offset of the window start from the midnight.
+	Start time.Duration
+	// Duration is the length of the window.
+	Duration time.Duration
+}
+
+// ParseWindow parses window start in HH:MM format and window duration in Go time.Duration format.
+func ParseWindow(start, duration string) (*Window, error) {
+	startTime, err := time.Parse("15:04", start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maintenance window start %q: %w", start, err)
+	}
+
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maintenance window duration %q: %w", duration, err)
+	}
+
+	if d <= 0 || d > 24*time.Hour {
+		return nil, fmt.Errorf("maintenance window duration should be in range (0, 24h]: %s", d)
+	}
+
+	return &Window{
+		Start:    time.Duration(startTime.Hour())*time.Hour + time.Duration(startTime.Minute())*time.Minute,
+		Duration: d,
+	}, nil
+}
+
+// Next returns the earliest time not before now and notBefore which falls into the window.
+//
+// Zero notBefore and nil window are ignored.
+func Next(now, notBefore time.Time, window *Window) time.Time {
+	t := now.UTC()
+
+	if notBefore.After(t) {
+		t = notBefore.UTC()
+	}
+
+	if window == nil {
+		return t
+	}
+
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	// window which started yesterday might still be open
+	for day := -1; day <= 1; day++ {
+		start := midnight.AddDate(0, 0, day).Add(window.Start)
+
+		if start.After(t) {
+			return start
+		}
+
+		if t.Before(start.Add(window.Duration)) {
+			return t
+		}
+	}
+
+	// not reachable, window always starts within the next day
+	return t
+}
+
+// Action is a pending scheduled action.
+type Action struct {
+	ID        string
+	Name      string
+	CreatedAt time.Time
+	RunAt     time.Time
+}
+
+// Scheduler keeps track of the pending actions.
+//
+// Zero value is ready to use.
+type Scheduler struct {
+	mu      sync.Mutex
+	actions map[string]Action
+	lastID  int
+}
+
+// Schedule runs f at the specified time.
+func (s *Scheduler) Schedule(name string, runAt time.Time, f func()) Action {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.actions == nil {
+		s.actions = map[string]Action{}
+	}
+
+	s.lastID++
+
+	action := Action{
+		ID:        strconv.Itoa(s.lastID),
+		Name:      name,
+		CreatedAt: time.Now().UTC(),
+		RunAt:     runAt.UTC(),
+	}
+
+	s.actions[action.ID] = action
+
+	time.AfterFunc(time.Until(runAt), func() {
+		s.mu.Lock()
+		delete(s.actions, action.ID)
+		s.mu.Unlock()
+
+		f()
+	})
+
+	return action
+}
+
+// Pending returns the list of pending actions ordered by the run time.
+func (s *Scheduler) Pending() []Action {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	actions := make([]Action, 0, len(s.actions))
+
+	for _, action := range s.actions {
+		actions = append(actions, action)
+	}
+
+	sort.Slice(actions, func(i, j int) bool {
+		if actions[i].RunAt.Equal(actions[j].RunAt) {
+			return actions[i].CreatedAt.Before(actions[j].CreatedAt)
+		}
+
+		return actions[i].RunAt.Before(actions[j].RunAt)
+	})
+
+	return actions
+}
@@ -0,0 +1,125 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/schedule"
+)
+
+func TestParseWindow(t *testing.T) {
+	window, err := schedule.ParseWindow("22:30", "3h")
+	require.NoError(t, err)
+
+	assert.Equal(t, 22*time.Hour+30*time.Minute, window.Start)
+	assert.Equal(t, 3*time.Hour, window.Duration)
+
+	_, err = schedule.ParseWindow("25:00", "1h")
+	assert.Error(t, err)
+
+	_, err = schedule.ParseWindow("02:00", "25h")
+	assert.Error(t, err)
+
+	_, err = schedule.ParseWindow("02:00", "")
+	assert.Error(t, err)
+}
+
+func TestNext(t *testing.T) {
+	now := time.Date(2020, 12, 1, 12, 0, 0, 0, time.UTC)
+
+	window, err := schedule.ParseWindow("22:30", "3h")
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name      string
+		now       time.Time
+		notBefore time.Time
+		window    *schedule.Window
+		expected  time.Time
+	}{
+		{
+			name:     "immediate",
+			now:      now,
+			expected: now,
+		},
+		{
+			name:      "not before in the past",
+			now:       now,
+			notBefore: now.Add(-time.Hour),
+			expected:  now,
+		},
+		{
+			name:      "not before",
+			now:       now,
+			notBefore: now.Add(time.Hour),
+			expected:  now.Add(time.Hour),
+		},
+		{
+			name:     "before window",
+			now:      now,
+			window:   window,
+			expected: time.Date(2020, 12, 1, 22, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "within window",
+			now:      time.Date(2020, 12, 1, 23, 0, 0, 0, time.UTC),
+			window:   window,
+			expected: time.Date(2020, 12, 1, 23, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "within window after midnight",
+			now:      time.Date(2020, 12, 2, 1, 0, 0, 0, time.UTC),
+			window:   window,
+			expected: time.Date(2020, 12, 2, 1, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "after window",
+			now:      time.Date(2020, 12, 2, 1, 30, 0, 0, time.UTC),
+			window:   window,
+			expected: time.Date(2020, 12, 2, 22, 30, 0, 0, time.UTC),
+		},
+		{
+			name:      "not before and window",
+			now:       now,
+			notBefore: time.Date(2020, 12, 3, 0, 15, 0, 0, time.UTC),
+			window:    window,
+			expected:  time.Date(2020, 12, 3, 0, 15, 0, 0, time.UTC),
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, schedule.Next(tt.now, tt.notBefore, tt.window))
+		})
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	var s schedule.Scheduler
+
+	done := make(chan struct{})
+
+	later := s.Schedule("reboot", time.Now().Add(time.Hour), func() {})
+	s.Schedule("upgrade", time.Now().Add(50*time.Millisecond), func() { close(done) })
+
+	pending := s.Pending()
+	require.Len(t, pending, 2)
+	assert.Equal(t, "upgrade", pending[0].Name)
+	assert.Equal(t, "reboot", pending[1].Name)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("action wasn't run")
+	}
+
+	assert.Eventually(t, func() bool { return len(s.Pending()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, later, s.Pending()[0])
+}
@@ -0,0 +1,283 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secureboot
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Signer signs the EFI binaries and the EFI variable updates.
+//
+// UEFI firmware supports only RSA keys, so the signer key should be RSA (2048 bits is the safest choice).
+type Signer struct {
+	Certificate *x509.Certificate
+	Key         *rsa.PrivateKey
+}
+
+// LoadSigner loads the PEM-encoded certificate and the private key (PKCS#1 or PKCS#8).
+func LoadSigner(certPath, keyPath string) (*Signer, error) {
+	certPEM, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPEM, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode certificate %q", certPath)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing certificate %q: %w", certPath, err)
+	}
+
+	if block, _ = pem.Decode(keyPEM); block == nil {
+		return nil, fmt.Errorf("failed to decode private key %q", keyPath)
+	}
+
+	var key interface{}
+
+	if key, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+		if key, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+			return nil, fmt.Errorf("error parsing private key %q: %w", keyPath, err)
+		}
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %q is not an RSA key, UEFI supports only RSA keys", keyPath)
+	}
+
+	if pub, ok := cert.PublicKey.(*rsa.PublicKey); !ok || pub.N.Cmp(rsaKey.N) != 0 || pub.E != rsaKey.E {
+		return nil, fmt.Errorf("private key %q doesn't match certificate %q", keyPath, certPath)
+	}
+
+	return &Signer{
+		Certificate: cert,
+		Key:         rsaKey,
+	}, nil
+}
+
+func (s *Signer) sign(digest []byte) ([]byte, error) {
+	return rsa.SignPKCS1v15(rand.Reader, s.Key, crypto.SHA256, digest)
+}
+
+func checkSignature(cert *x509.Certificate, digest, signature []byte) error {
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("signer certificate doesn't have an RSA key")
+	}
+
+	return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, signature)
+}
+
+func sha256Sum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+
+	return sum[:]
+}
+
+// spcIndirectDataContent is the Authenticode signed content: the digest of the PE image.
+type spcIndirectDataContent struct {
+	Data          spcAttributeTypeAndOptionalValue
+	MessageDigest digestInfo
+}
+
+type spcAttributeTypeAndOptionalValue struct {
+	Type  asn1.ObjectIdentifier
+	Value spcPEImageData
+}
+
+type spcPEImageData struct {
+	Flags asn1.BitString
+	File  asn1.RawValue `asn1:"optional"`
+}
+
+type digestInfo struct {
+	DigestAlgorithm pkix.AlgorithmIdentifier
+	Digest          []byte
+}
+
+// obsoleteFile is the SpcLink [0] { file [2] { unicode [0] "<<<Obsolete>>>" } } written by the signing tools.
+var obsoleteFile = explicit(0, mustMarshal(explicit(2, mustMarshal(asn1.RawValue{
+	Class: asn1.ClassContextSpecific,
+	Tag:   0,
+	Bytes: []byte("\x00<\x00<\x00<\x00O\x00b\x00s\x00o\x00l\x00e\x00t\x00e\x00>\x00>\x00>"),
+}))))
+
+func mustMarshal(v interface{}) []byte {
+	b, err := asn1.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+// SignPE signs the PE image (EFI binary) with the Authenticode signature.
+//
+// Existing signatures of the image are replaced.
+func (s *Signer) SignPE(image []byte) ([]byte, error) {
+	return appendSignature(image, func(digest []byte) ([]byte, error) {
+		content, err := asn1.Marshal(spcIndirectDataContent{
+			Data: spcAttributeTypeAndOptionalValue{
+				Type: oidSPCPEImageDataObject,
+				Value: spcPEImageData{
+					File: obsoleteFile,
+				},
+			},
+			MessageDigest: digestInfo{
+				DigestAlgorithm: sha256Algorithm,
+				Digest:          digest,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		signed, err := s.signPKCS7(oidSPCIndirectData, content, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return wrapContentInfo(signed)
+	})
+}
+
+// VerifyPE verifies that the PE image is signed by the certificate which chains up to the
+// certificates in the signature database db, the way the firmware does it before
+// booting the image.
+//
+// Image is rejected if its digest or any certificate of the chain is in the forbidden
+// signature database dbx.
+func VerifyPE(image []byte, db, dbx *SignatureDatabase) error {
+	img, err := parsePE(image)
+	if err != nil {
+		return err
+	}
+
+	digest := img.digest()
+
+	if dbx.containsHash(digest) {
+		return errors.New("image digest is forbidden by dbx")
+	}
+
+	signatures, err := img.certificates()
+	if err != nil {
+		return err
+	}
+
+	if len(signatures) == 0 {
+		return errors.New("image is not signed")
+	}
+
+	var result *multierror.Error
+
+	// firmware accepts the image if any of the signatures is valid
+	for _, signature := range signatures {
+		if err = verifyAuthenticode(signature, digest, db, dbx); err == nil {
+			return nil
+		}
+
+		result = multierror.Append(result, err)
+	}
+
+	return result.ErrorOrNil()
+}
+
+func verifyAuthenticode(signature, digest []byte, db, dbx *SignatureDatabase) error {
+	sd, err := parsePKCS7(signature)
+	if err != nil {
+		return err
+	}
+
+	if !sd.ContentInfo.ContentType.Equal(oidSPCIndirectData) {
+		return fmt.Errorf("unexpected signed content type %s", sd.ContentInfo.ContentType)
+	}
+
+	var content spcIndirectDataContent
+
+	if _, err = asn1.Unmarshal(sd.ContentInfo.Content.Bytes, &content); err != nil {
+		return fmt.Errorf("error parsing Authenticode content: %w", err)
+	}
+
+	if !content.MessageDigest.DigestAlgorithm.Algorithm.Equal(oidSHA256) {
+		return fmt.Errorf("unsupported image digest algorithm %s", content.MessageDigest.DigestAlgorithm.Algorithm)
+	}
+
+	if !bytes.Equal(content.MessageDigest.Digest, digest) {
+		return errors.New("image digest doesn't match the signature")
+	}
+
+	signer, certs, err := sd.signer()
+	if err != nil {
+		return err
+	}
+
+	if err = sd.verifySignature(signer, nil); err != nil {
+		return fmt.Errorf("invalid signature of %q: %w", signer.Subject, err)
+	}
+
+	return verifyChain(signer, certs, db, dbx)
+}
+
+// verifyChain checks that the certificate chain of the signer ends with a certificate in db.
+//
+// Firmware treats any certificate in db as the trust anchor and doesn't check the validity period.
+func verifyChain(signer *x509.Certificate, intermediates []*x509.Certificate, db, dbx *SignatureDatabase) error {
+	const maxDepth = 8
+
+	cert := signer
+
+	for depth := 0; depth < maxDepth; depth++ {
+		if dbx.containsCertificate(cert) {
+			return fmt.Errorf("certificate %q is forbidden by dbx", cert.Subject)
+		}
+
+		if db.containsCertificate(cert) {
+			return nil
+		}
+
+		var parent *x509.Certificate
+
+		for _, candidate := range append(append([]*x509.Certificate(nil), db.Certificates...), intermediates...) {
+			if candidate.Equal(cert) {
+				continue
+			}
+
+			if cert.CheckSignatureFrom(candidate) == nil {
+				parent = candidate
+
+				break
+			}
+		}
+
+		if parent == nil {
+			return fmt.Errorf("certificate %q doesn't chain up to the certificates in db", signer.Subject)
+		}
+
+		cert = parent
+	}
+
+	return fmt.Errorf("certificate chain of %q is too long", signer.Subject)
+}
@@ -0,0 +1,186 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secureboot_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/secureboot"
+)
+
+// testImage builds a minimal PE32+ image with a single section.
+func testImage() []byte {
+	const (
+		peOffset      = 0x80
+		optionalSize  = 240
+		sizeOfHeaders = 0x200
+		sectionSize   = 0x200
+	)
+
+	image := make([]byte, sizeOfHeaders+sectionSize)
+
+	copy(image, "MZ")
+	binary.LittleEndian.PutUint32(image[0x3c:], peOffset)
+	copy(image[peOffset:], "PE\x00\x00")
+
+	coff := peOffset + 4
+	binary.LittleEndian.PutUint16(image[coff:], 0x8664)
+	binary.LittleEndian.PutUint16(image[coff+2:], 1)
+	binary.LittleEndian.PutUint16(image[coff+16:], optionalSize)
+
+	optional := coff + 20
+	binary.LittleEndian.PutUint16(image[optional:], 0x20b)
+	binary.LittleEndian.PutUint32(image[optional+60:], sizeOfHeaders)
+	binary.LittleEndian.PutUint32(image[optional+108:], 16)
+
+	section := optional + optionalSize
+	copy(image[section:], ".text")
+	binary.LittleEndian.PutUint32(image[section+16:], sectionSize)
+	binary.LittleEndian.PutUint32(image[section+20:], sizeOfHeaders)
+
+	for i := sizeOfHeaders; i < len(image); i++ {
+		image[i] = byte(i)
+	}
+
+	return image
+}
+
+func newCertificate(t *testing.T, name string, parent *secureboot.Signer) *secureboot.Signer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+
+	issuer, issuerKey := template, key
+
+	if parent != nil {
+		issuer, issuerKey = parent.Certificate, parent.Key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, issuerKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &secureboot.Signer{
+		Certificate: cert,
+		Key:         key,
+	}
+}
+
+func TestSignVerifyPE(t *testing.T) {
+	ca := newCertificate(t, "Talos Test CA", nil)
+	signer := newCertificate(t, "Talos Test Signing Key", ca)
+	other := newCertificate(t, "Other", nil)
+
+	image := testImage()
+
+	signed, err := signer.SignPE(image)
+	require.NoError(t, err)
+
+	// only the checksum and the certificate table directory are updated in the headers
+	assert.True(t, bytes.Equal(image[0x200:], signed[0x200:len(image)]))
+
+	// signer certificate in db
+	assert.NoError(t, secureboot.VerifyPE(signed, &secureboot.SignatureDatabase{Certificates: []*x509.Certificate{signer.Certificate}}, nil))
+
+	// issuer of the signer certificate in db
+	assert.NoError(t, secureboot.VerifyPE(signed, &secureboot.SignatureDatabase{Certificates: []*x509.Certificate{other.Certificate, ca.Certificate}}, nil))
+
+	err = secureboot.VerifyPE(signed, &secureboot.SignatureDatabase{Certificates: []*x509.Certificate{other.Certificate}}, nil)
+	assert.EqualError(t, err, "1 error occurred:\n\t* certificate \"CN=Talos Test Signing Key\" doesn't chain up to the certificates in db\n\n")
+
+	db := &secureboot.SignatureDatabase{Certificates: []*x509.Certificate{ca.Certificate}}
+
+	err = secureboot.VerifyPE(signed, db, &secureboot.SignatureDatabase{Certificates: []*x509.Certificate{signer.Certificate}})
+	assert.EqualError(t, err, "1 error occurred:\n\t* certificate \"CN=Talos Test Signing Key\" is forbidden by dbx\n\n")
+
+	// Authenticode digest of the unsigned image (the image is 8 bytes aligned, so it's not padded)
+	h := sha256.New()
+	h.Write(image[:0x84+20+64])
+	h.Write(image[0x84+20+68 : 0x84+20+112+32])
+	h.Write(image[0x84+20+112+40:])
+
+	err = secureboot.VerifyPE(signed, db, &secureboot.SignatureDatabase{Hashes: [][]byte{h.Sum(nil)}})
+	assert.EqualError(t, err, "image digest is forbidden by dbx")
+
+	assert.EqualError(t, secureboot.VerifyPE(image, db, nil), "image is not signed")
+
+	// modified section
+	tampered := append([]byte(nil), signed...)
+	tampered[0x300]++
+
+	err = secureboot.VerifyPE(tampered, db, nil)
+	assert.EqualError(t, err, "1 error occurred:\n\t* image digest doesn't match the signature\n\n")
+
+	// signing again replaces the signature
+	resigned, err := other.SignPE(signed)
+	require.NoError(t, err)
+
+	assert.Error(t, secureboot.VerifyPE(resigned, db, nil))
+	assert.NoError(t, secureboot.VerifyPE(resigned, &secureboot.SignatureDatabase{Certificates: []*x509.Certificate{other.Certificate}}, nil))
+}
+
+func TestSignPEInvalid(t *testing.T) {
+	signer := newCertificate(t, "Talos Test Signing Key", nil)
+
+	_, err := signer.SignPE([]byte("not an image"))
+	assert.EqualError(t, err, "not a PE image")
+}
+
+func TestLoadSigner(t *testing.T) {
+	signer := newCertificate(t, "Talos Test Signing Key", nil)
+	other := newCertificate(t, "Other", nil)
+
+	dir := t.TempDir()
+
+	certPath := filepath.Join(dir, "db.crt")
+	keyPath := filepath.Join(dir, "db.key")
+	otherKeyPath := filepath.Join(dir, "other.key")
+
+	require.NoError(t, ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: signer.Certificate.Raw}), 0o600))
+	require.NoError(t, ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(signer.Key)}), 0o600))
+
+	otherKey, err := x509.MarshalPKCS8PrivateKey(other.Key)
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(otherKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: otherKey}), 0o600))
+
+	loaded, err := secureboot.LoadSigner(certPath, keyPath)
+	require.NoError(t, err)
+
+	assert.True(t, loaded.Certificate.Equal(signer.Certificate))
+	assert.Equal(t, signer.Key.D, loaded.Key.D)
+
+	_, err = secureboot.LoadSigner(certPath, otherKeyPath)
+	assert.EqualError(t, err, "private key \""+otherKeyPath+"\" doesn't match certificate \""+certPath+"\"")
+}
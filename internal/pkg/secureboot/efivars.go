@@ -0,0 +1,426 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secureboot
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// GUID is the EFI GUID in the binary (mixed-endian) form.
+type GUID [16]byte
+
+// ParseGUID parses the GUID in the canonical text form.
+func ParseGUID(s string) (GUID, error) {
+	var guid GUID
+
+	b, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil || len(b) != len(guid) || strings.Count(s, "-") != 4 {
+		return guid, fmt.Errorf("invalid GUID %q", s)
+	}
+
+	// first three fields are little-endian
+	guid[0], guid[1], guid[2], guid[3] = b[3], b[2], b[1], b[0]
+	guid[4], guid[5] = b[5], b[4]
+	guid[6], guid[7] = b[7], b[6]
+	copy(guid[8:], b[8:])
+
+	return guid, nil
+}
+
+func mustParseGUID(s string) GUID {
+	guid, err := ParseGUID(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return guid
+}
+
+// String implements fmt.Stringer.
+func (g GUID) String() string {
+	return fmt.Sprintf("%02x%02x%02x%02x-%02x%02x-%02x%02x-%x-%x", g[3], g[2], g[1], g[0], g[5], g[4], g[7], g[6], g[8:10], g[10:])
+}
+
+// Well-known GUIDs.
+var (
+	// GlobalVariableGUID is the vendor GUID of PK and KEK.
+	GlobalVariableGUID = mustParseGUID(efiGlobalVariableGUID)
+	// ImageSecurityDatabaseGUID is the vendor GUID of db and dbx.
+	ImageSecurityDatabaseGUID = mustParseGUID("d719b2cb-3d3a-4596-a3bc-dad00e67656f")
+	// OwnerGUID is the signature owner of the Talos SecureBoot keys.
+	OwnerGUID = mustParseGUID("6d2ad4b3-6b4a-4e3f-9c1b-7c8e3b2a9f10")
+
+	certX509GUID   = mustParseGUID("a5c059a1-94e4-4aa7-87b5-ab155c2bf072")
+	certSHA256GUID = mustParseGUID("c1c41626-504c-4092-aca9-41f936934328")
+	certPKCS7GUID  = mustParseGUID("4aafd29d-68df-49ee-8aa9-347d375665a7")
+)
+
+// SecureBoot variables.
+const (
+	PK  = "PK"
+	KEK = "KEK"
+	DB  = "db"
+	DBX = "dbx"
+)
+
+// EFI variable attributes.
+const (
+	attrNonVolatile                       = 0x1
+	attrBootServiceAccess                 = 0x2
+	attrRuntimeAccess                     = 0x4
+	attrTimeBasedAuthenticatedWriteAccess = 0x20
+
+	authenticatedVariableAttrs = attrNonVolatile | attrBootServiceAccess | attrRuntimeAccess | attrTimeBasedAuthenticatedWriteAccess
+)
+
+// signatureListHeaderSize is the size of EFI_SIGNATURE_LIST without the signature header.
+const signatureListHeaderSize = 16 + 4 + 4 + 4
+
+// SignatureDatabase is the contents of the signature database (db, dbx) or of KEK and PK.
+type SignatureDatabase struct {
+	Certificates []*x509.Certificate
+	Hashes       [][]byte
+}
+
+// ParseSignatureDatabase parses the list of EFI_SIGNATURE_LIST structures.
+//
+// Signature types other than X.509 certificates and SHA-256 hashes are skipped.
+func ParseSignatureDatabase(data []byte) (*SignatureDatabase, error) {
+	db := &SignatureDatabase{}
+
+	for len(data) > 0 {
+		if len(data) < signatureListHeaderSize {
+			return nil, errors.New("signature list is truncated")
+		}
+
+		var signatureType GUID
+
+		copy(signatureType[:], data)
+
+		listSize := int(binary.LittleEndian.Uint32(data[16:]))
+		headerSize := int(binary.LittleEndian.Uint32(data[20:]))
+		signatureSize := int(binary.LittleEndian.Uint32(data[24:]))
+
+		if listSize > len(data) || signatureListHeaderSize+headerSize > listSize || signatureSize <= 16 ||
+			(listSize-signatureListHeaderSize-headerSize)%signatureSize != 0 {
+			return nil, errors.New("signature list is malformed")
+		}
+
+		for signatures := data[signatureListHeaderSize+headerSize : listSize]; len(signatures) > 0; signatures = signatures[signatureSize:] {
+			// each signature starts with the owner GUID
+			signature := signatures[16:signatureSize]
+
+			switch signatureType {
+			case certX509GUID:
+				cert, err := x509.ParseCertificate(signature)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing signature list certificate: %w", err)
+				}
+
+				db.Certificates = append(db.Certificates, cert)
+			case certSHA256GUID:
+				db.Hashes = append(db.Hashes, append([]byte(nil), signature...))
+			}
+		}
+
+		data = data[listSize:]
+	}
+
+	return db, nil
+}
+
+// Marshal encodes the signature database as the list of EFI_SIGNATURE_LIST structures owned by the owner.
+func (db *SignatureDatabase) Marshal(owner GUID) []byte {
+	var buf bytes.Buffer
+
+	writeList := func(signatureType GUID, signatures ...[]byte) {
+		signatureSize := 16 + len(signatures[0])
+
+		buf.Write(signatureType[:])
+		binary.Write(&buf, binary.LittleEndian, uint32(signatureListHeaderSize+len(signatures)*signatureSize)) //nolint: errcheck
+		binary.Write(&buf, binary.LittleEndian, uint32(0))                                                     //nolint: errcheck
+		binary.Write(&buf, binary.LittleEndian, uint32(signatureSize))                                         //nolint: errcheck
+
+		for _, signature := range signatures {
+			buf.Write(owner[:])
+			buf.Write(signature)
+		}
+	}
+
+	// certificates have different sizes, so each one is stored in its own list
+	for _, cert := range db.Certificates {
+		writeList(certX509GUID, cert.Raw)
+	}
+
+	if len(db.Hashes) > 0 {
+		writeList(certSHA256GUID, db.Hashes...)
+	}
+
+	return buf.Bytes()
+}
+
+func (db *SignatureDatabase) containsCertificate(cert *x509.Certificate) bool {
+	if db == nil {
+		return false
+	}
+
+	for _, c := range db.Certificates {
+		if c.Equal(cert) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (db *SignatureDatabase) containsHash(digest []byte) bool {
+	if db == nil {
+		return false
+	}
+
+	for _, h := range db.Hashes {
+		if bytes.Equal(h, digest) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func variablePath(name string, vendor GUID) string {
+	return filepath.Join(EFIVarsPath, name+"-"+vendor.String())
+}
+
+// ReadSignatureDatabase reads the signature database variable (db, dbx, KEK or PK).
+//
+// Missing variable is returned as the empty database.
+func ReadSignatureDatabase(name string) (*SignatureDatabase, error) {
+	vendor := ImageSecurityDatabaseGUID
+	if name == PK || name == KEK {
+		vendor = GlobalVariableGUID
+	}
+
+	data, err := ioutil.ReadFile(variablePath(name, vendor))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &SignatureDatabase{}, nil
+		}
+
+		return nil, fmt.Errorf("error reading EFI variable %q: %w", name, err)
+	}
+
+	// efivarfs prepends the variable attributes
+	if len(data) < 4 {
+		return nil, fmt.Errorf("EFI variable %q is truncated", name)
+	}
+
+	db, err := ParseSignatureDatabase(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("error parsing EFI variable %q: %w", name, err)
+	}
+
+	return db, nil
+}
+
+// efiTime encodes the EFI_TIME structure.
+func efiTime(t time.Time) []byte {
+	t = t.UTC()
+
+	b := make([]byte, 16)
+	binary.LittleEndian.PutUint16(b, uint16(t.Year()))
+	b[2] = byte(t.Month())
+	b[3] = byte(t.Day())
+	b[4] = byte(t.Hour())
+	b[5] = byte(t.Minute())
+	b[6] = byte(t.Second())
+	// pad, nanosecond, time zone, daylight and pad should be zero for the authenticated variables
+
+	return b
+}
+
+// SignVariable creates the time-based authenticated update of the EFI variable
+// (EFI_VARIABLE_AUTHENTICATION_2 followed by the data).
+//
+// Updates of PK are signed by PK (self-signed in setup mode), updates of KEK are signed by PK,
+// and updates of db and dbx are signed by KEK.
+func (s *Signer) SignVariable(name string, vendor GUID, data []byte, timestamp time.Time) ([]byte, error) {
+	ts := efiTime(timestamp)
+
+	var signed bytes.Buffer
+
+	for _, c := range utf16.Encode([]rune(name)) {
+		binary.Write(&signed, binary.LittleEndian, c) //nolint: errcheck
+	}
+
+	signed.Write(vendor[:])
+	binary.Write(&signed, binary.LittleEndian, uint32(authenticatedVariableAttrs)) //nolint: errcheck
+	signed.Write(ts)
+	signed.Write(data)
+
+	signature, err := s.signPKCS7(oidData, nil, sha256Sum(signed.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+
+	out.Write(ts)
+	binary.Write(&out, binary.LittleEndian, uint32(winCertificateHeader+16+len(signature))) //nolint: errcheck
+	binary.Write(&out, binary.LittleEndian, uint16(winCertRevision))                        //nolint: errcheck
+	binary.Write(&out, binary.LittleEndian, uint16(winCertTypeEFIGUID))                     //nolint: errcheck
+	out.Write(certPKCS7GUID[:])
+	out.Write(signature)
+	out.Write(data)
+
+	return out.Bytes(), nil
+}
+
+// VerifyVariable verifies the authenticated update of the EFI variable created with SignVariable
+// against the certificates of the signer database and returns the variable data.
+func VerifyVariable(name string, vendor GUID, update []byte, signers *SignatureDatabase) ([]byte, error) {
+	data, signature, err := splitAuthenticatedVariable(update)
+	if err != nil {
+		return nil, err
+	}
+
+	var signed bytes.Buffer
+
+	for _, c := range utf16.Encode([]rune(name)) {
+		binary.Write(&signed, binary.LittleEndian, c) //nolint: errcheck
+	}
+
+	signed.Write(vendor[:])
+	binary.Write(&signed, binary.LittleEndian, uint32(authenticatedVariableAttrs)) //nolint: errcheck
+	signed.Write(update[:16])
+	signed.Write(data)
+
+	sd, err := parsePKCS7(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, certs, err := sd.signer()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = sd.verifySignature(signer, sha256Sum(signed.Bytes())); err != nil {
+		return nil, fmt.Errorf("invalid signature of %q update: %w", name, err)
+	}
+
+	if err = verifyChain(signer, certs, signers, nil); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// splitAuthenticatedVariable splits the authenticated update into the variable data and the PKCS#7 signature.
+func splitAuthenticatedVariable(update []byte) (data, signature []byte, err error) {
+	const offset = 16 + winCertificateHeader + 16
+
+	if len(update) < offset {
+		return nil, nil, errors.New("authenticated variable is truncated")
+	}
+
+	length := int(binary.LittleEndian.Uint32(update[16:]))
+
+	if binary.LittleEndian.Uint16(update[22:]) != winCertTypeEFIGUID || !bytes.Equal(update[24:40], certPKCS7GUID[:]) {
+		return nil, nil, errors.New("authenticated variable is not signed with PKCS#7")
+	}
+
+	if length < winCertificateHeader+16 || 16+length > len(update) {
+		return nil, nil, errors.New("authenticated variable is truncated")
+	}
+
+	return update[16+length:], update[offset : 16+length], nil
+}
+
+// ParseVariableUpdate returns the signature database of the authenticated variable update.
+func ParseVariableUpdate(update []byte) (*SignatureDatabase, error) {
+	data, _, err := splitAuthenticatedVariable(update)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseSignatureDatabase(data)
+}
+
+// EnrollKeys writes the authenticated updates of db, KEK and PK.
+//
+// The firmware should be in setup mode. PK is written last, as enrolling PK exits setup mode,
+// so the firmware starts enforcing SecureBoot on the next boot.
+func EnrollKeys(db, kek, pk []byte) error {
+	for _, v := range []struct {
+		name   string
+		vendor GUID
+		update []byte
+	}{
+		{DB, ImageSecurityDatabaseGUID, db},
+		{KEK, GlobalVariableGUID, kek},
+		{PK, GlobalVariableGUID, pk},
+	} {
+		if err := writeVariable(variablePath(v.name, v.vendor), authenticatedVariableAttrs, v.update); err != nil {
+			return fmt.Errorf("error writing EFI variable %q: %w", v.name, err)
+		}
+	}
+
+	return nil
+}
+
+// fsImmutableFlag is FS_IMMUTABLE_FL.
+const fsImmutableFlag = 0x10
+
+// writeVariable writes the EFI variable via efivarfs.
+//
+// efivarfs marks existing SecureBoot variables immutable, so the flag is cleared first.
+// The variable is written with a single write call, as efivarfs requires it.
+func writeVariable(path string, attrs uint32, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+
+	//nolint: errcheck
+	defer f.Close()
+
+	var flags int
+
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), unix.FS_IOC_GETFLAGS, uintptr(unsafe.Pointer(&flags))); errno != 0 {
+		return fmt.Errorf("error getting file flags: %w", errno)
+	}
+
+	if flags&fsImmutableFlag != 0 {
+		flags &^= fsImmutableFlag
+
+		if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), unix.FS_IOC_SETFLAGS, uintptr(unsafe.Pointer(&flags))); errno != 0 {
+			return fmt.Errorf("error clearing immutable flag: %w", errno)
+		}
+	}
+
+	buf := make([]byte, 4+len(data))
+	binary.LittleEndian.PutUint32(buf, attrs)
+	copy(buf[4:], data)
+
+	if _, err = f.Write(buf); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,111 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secureboot_test
+
+import (
+	"crypto/x509"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/secureboot"
+)
+
+func TestGUID(t *testing.T) {
+	guid, err := secureboot.ParseGUID("d719b2cb-3d3a-4596-a3bc-dad00e67656f")
+	require.NoError(t, err)
+
+	assert.Equal(t, secureboot.ImageSecurityDatabaseGUID, guid)
+	assert.Equal(t, []byte{0xcb, 0xb2, 0x19, 0xd7, 0x3a, 0x3d, 0x96, 0x45, 0xa3, 0xbc, 0xda, 0xd0, 0x0e, 0x67, 0x65, 0x6f}, guid[:])
+	assert.Equal(t, "d719b2cb-3d3a-4596-a3bc-dad00e67656f", guid.String())
+
+	_, err = secureboot.ParseGUID("d719b2cb3d3a4596a3bcdad00e67656f")
+	assert.Error(t, err)
+}
+
+func TestSignatureDatabase(t *testing.T) {
+	signer := newCertificate(t, "Talos Test Signing Key", nil)
+	other := newCertificate(t, "Other", nil)
+
+	db := &secureboot.SignatureDatabase{
+		Certificates: []*x509.Certificate{signer.Certificate, other.Certificate},
+		Hashes:       [][]byte{make([]byte, 32), append(make([]byte, 31), 1)},
+	}
+
+	parsed, err := secureboot.ParseSignatureDatabase(db.Marshal(secureboot.OwnerGUID))
+	require.NoError(t, err)
+
+	require.Len(t, parsed.Certificates, 2)
+	assert.True(t, parsed.Certificates[0].Equal(signer.Certificate))
+	assert.True(t, parsed.Certificates[1].Equal(other.Certificate))
+	assert.Equal(t, db.Hashes, parsed.Hashes)
+
+	_, err = secureboot.ParseSignatureDatabase(db.Marshal(secureboot.OwnerGUID)[:100])
+	assert.EqualError(t, err, "signature list is malformed")
+}
+
+func TestReadSignatureDatabase(t *testing.T) {
+	oldPath := secureboot.EFIVarsPath
+
+	t.Cleanup(func() { secureboot.EFIVarsPath = oldPath })
+
+	secureboot.EFIVarsPath = t.TempDir()
+
+	signer := newCertificate(t, "Talos Test Signing Key", nil)
+
+	data := append([]byte{0x27, 0, 0, 0}, (&secureboot.SignatureDatabase{Certificates: []*x509.Certificate{signer.Certificate}}).Marshal(secureboot.OwnerGUID)...)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(secureboot.EFIVarsPath, "db-d719b2cb-3d3a-4596-a3bc-dad00e67656f"), data, 0o600))
+
+	db, err := secureboot.ReadSignatureDatabase(secureboot.DB)
+	require.NoError(t, err)
+
+	require.Len(t, db.Certificates, 1)
+	assert.True(t, db.Certificates[0].Equal(signer.Certificate))
+
+	dbx, err := secureboot.ReadSignatureDatabase(secureboot.DBX)
+	require.NoError(t, err)
+
+	assert.Empty(t, dbx.Certificates)
+	assert.Empty(t, dbx.Hashes)
+}
+
+func TestSignVariable(t *testing.T) {
+	pk := newCertificate(t, "Talos Test PK", nil)
+	kek := newCertificate(t, "Talos Test KEK", nil)
+
+	data := (&secureboot.SignatureDatabase{Certificates: []*x509.Certificate{kek.Certificate}}).Marshal(secureboot.OwnerGUID)
+
+	update, err := pk.SignVariable(secureboot.KEK, secureboot.GlobalVariableGUID, data, time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	// EFI_TIME
+	assert.Equal(t, []byte{0xe4, 0x07, 12, 1, 10, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, update[:16])
+
+	pkDB := &secureboot.SignatureDatabase{Certificates: []*x509.Certificate{pk.Certificate}}
+
+	verified, err := secureboot.VerifyVariable(secureboot.KEK, secureboot.GlobalVariableGUID, update, pkDB)
+	require.NoError(t, err)
+
+	assert.Equal(t, data, verified)
+
+	parsed, err := secureboot.ParseVariableUpdate(update)
+	require.NoError(t, err)
+
+	require.Len(t, parsed.Certificates, 1)
+	assert.True(t, parsed.Certificates[0].Equal(kek.Certificate))
+
+	// signature covers the variable name
+	_, err = secureboot.VerifyVariable(secureboot.PK, secureboot.GlobalVariableGUID, update, pkDB)
+	assert.Error(t, err)
+
+	// signer is not trusted
+	_, err = secureboot.VerifyVariable(secureboot.KEK, secureboot.GlobalVariableGUID, update, &secureboot.SignatureDatabase{Certificates: []*x509.Certificate{kek.Certificate}})
+	assert.EqualError(t, err, "certificate \"CN=Talos Test PK\" doesn't chain up to the certificates in db")
+}
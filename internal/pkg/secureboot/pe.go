@@ -0,0 +1,276 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secureboot
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// PE/COFF offsets used by the Authenticode image hash.
+const (
+	peSignatureOffset   = 0x3c
+	coffHeaderSize      = 20
+	optionalMagicPE32   = 0x10b
+	optionalMagicPE32P  = 0x20b
+	checksumOffset      = 64
+	sizeOfHeadersOffset = 60
+	sectionHeaderSize   = 40
+
+	// certificate table is the fifth data directory.
+	certTableIndex = 4
+)
+
+// WIN_CERTIFICATE constants.
+const (
+	winCertRevision      = 0x0200
+	winCertTypePKCS      = 0x0002
+	winCertTypeEFIGUID   = 0x0ef1
+	winCertificateHeader = 8
+)
+
+// peImage describes the parts of the PE image which are excluded from the Authenticode hash.
+type peImage struct {
+	data []byte
+
+	checksum      int
+	certDirectory int
+	sizeOfHeaders int
+
+	// sections is the list of the raw data ranges of the sections sorted by the offset.
+	sections [][2]int
+
+	certTableOffset int
+	certTableSize   int
+}
+
+func parsePE(data []byte) (*peImage, error) {
+	if len(data) < peSignatureOffset+4 || data[0] != 'M' || data[1] != 'Z' {
+		return nil, errors.New("not a PE image")
+	}
+
+	peOffset := int(binary.LittleEndian.Uint32(data[peSignatureOffset:]))
+
+	if peOffset+4+coffHeaderSize > len(data) || string(data[peOffset:peOffset+4]) != "PE\x00\x00" {
+		return nil, errors.New("not a PE image")
+	}
+
+	coff := peOffset + 4
+	numSections := int(binary.LittleEndian.Uint16(data[coff+2:]))
+	optionalSize := int(binary.LittleEndian.Uint16(data[coff+16:]))
+	optional := coff + coffHeaderSize
+
+	if optional+optionalSize > len(data) || optionalSize < 2 {
+		return nil, errors.New("PE optional header is truncated")
+	}
+
+	var directories, numDirectoriesOffset int
+
+	switch magic := binary.LittleEndian.Uint16(data[optional:]); magic {
+	case optionalMagicPE32:
+		numDirectoriesOffset, directories = 92, 96
+	case optionalMagicPE32P:
+		numDirectoriesOffset, directories = 108, 112
+	default:
+		return nil, fmt.Errorf("unsupported PE optional header magic 0x%x", magic)
+	}
+
+	if directories > optionalSize {
+		return nil, errors.New("PE optional header is truncated")
+	}
+
+	if numDirectories := int(binary.LittleEndian.Uint32(data[optional+numDirectoriesOffset:])); numDirectories <= certTableIndex {
+		return nil, errors.New("PE image doesn't have the certificate table directory")
+	}
+
+	img := &peImage{
+		data:          data,
+		checksum:      optional + checksumOffset,
+		certDirectory: optional + directories + certTableIndex*8,
+		sizeOfHeaders: int(binary.LittleEndian.Uint32(data[optional+sizeOfHeadersOffset:])),
+	}
+
+	if img.certDirectory+8 > optional+optionalSize || img.sizeOfHeaders > len(data) {
+		return nil, errors.New("PE optional header is truncated")
+	}
+
+	img.certTableOffset = int(binary.LittleEndian.Uint32(data[img.certDirectory:]))
+	img.certTableSize = int(binary.LittleEndian.Uint32(data[img.certDirectory+4:]))
+
+	if img.certTableSize != 0 && (img.certTableOffset < img.sizeOfHeaders || img.certTableOffset+img.certTableSize != len(data)) {
+		return nil, errors.New("PE certificate table is not at the end of the image")
+	}
+
+	sections := optional + optionalSize
+
+	if sections+numSections*sectionHeaderSize > len(data) {
+		return nil, errors.New("PE section table is truncated")
+	}
+
+	for i := 0; i < numSections; i++ {
+		header := data[sections+i*sectionHeaderSize:]
+
+		size := int(binary.LittleEndian.Uint32(header[16:]))
+		offset := int(binary.LittleEndian.Uint32(header[20:]))
+
+		if size == 0 {
+			continue
+		}
+
+		if offset+size > len(data) {
+			return nil, fmt.Errorf("PE section %d is truncated", i)
+		}
+
+		img.sections = append(img.sections, [2]int{offset, offset + size})
+	}
+
+	sort.Slice(img.sections, func(i, j int) bool { return img.sections[i][0] < img.sections[j][0] })
+
+	return img, nil
+}
+
+// end returns the end of the signed data: the certificate table is excluded.
+func (img *peImage) end() int {
+	if img.certTableSize != 0 {
+		return img.certTableOffset
+	}
+
+	return len(img.data)
+}
+
+// digest calculates the Authenticode SHA-256 hash of the image.
+//
+// The hash covers the headers (except for the checksum and the certificate table directory entry),
+// the sections in the order of their file offsets, and the data after the sections except for the
+// certificate table.
+func (img *peImage) digest() []byte {
+	h := sha256.New()
+
+	h.Write(img.data[:img.checksum])                           //nolint: errcheck
+	h.Write(img.data[img.checksum+4 : img.certDirectory])      //nolint: errcheck
+	h.Write(img.data[img.certDirectory+8 : img.sizeOfHeaders]) //nolint: errcheck
+
+	hashed := img.sizeOfHeaders
+
+	for _, section := range img.sections {
+		h.Write(img.data[section[0]:section[1]]) //nolint: errcheck
+
+		hashed += section[1] - section[0]
+	}
+
+	if hashed < img.end() {
+		h.Write(img.data[hashed:img.end()]) //nolint: errcheck
+	}
+
+	return h.Sum(nil)
+}
+
+// certificates returns the PKCS#7 signatures of the certificate table.
+func (img *peImage) certificates() ([][]byte, error) {
+	var signatures [][]byte
+
+	table := img.data[img.end():]
+
+	for len(table) > 0 {
+		if len(table) < winCertificateHeader {
+			return nil, errors.New("PE certificate table is truncated")
+		}
+
+		length := int(binary.LittleEndian.Uint32(table))
+		revision := binary.LittleEndian.Uint16(table[4:])
+		certType := binary.LittleEndian.Uint16(table[6:])
+
+		if length < winCertificateHeader || length > len(table) {
+			return nil, errors.New("PE certificate table is truncated")
+		}
+
+		if revision == winCertRevision && certType == winCertTypePKCS {
+			signatures = append(signatures, table[winCertificateHeader:length])
+		}
+
+		// entries are aligned to 8 bytes
+		length = align8(length)
+		if length > len(table) {
+			break
+		}
+
+		table = table[length:]
+	}
+
+	return signatures, nil
+}
+
+// appendSignature replaces the certificate table of the image with the signature.
+//
+// The image is padded to 8 bytes before hashing, so the signature is created with sign callback
+// for the digest of the padded image.
+func appendSignature(data []byte, sign func(digest []byte) ([]byte, error)) ([]byte, error) {
+	img, err := parsePE(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// existing signatures are dropped
+	out := append([]byte(nil), data[:img.end()]...)
+	out = append(out, make([]byte, align8(len(out))-len(out))...)
+
+	binary.LittleEndian.PutUint64(out[img.certDirectory:], 0)
+
+	if img, err = parsePE(out); err != nil {
+		return nil, err
+	}
+
+	signature, err := sign(img.digest())
+	if err != nil {
+		return nil, err
+	}
+
+	length := align8(winCertificateHeader + len(signature))
+
+	entry := make([]byte, length)
+	binary.LittleEndian.PutUint32(entry, uint32(length))
+	binary.LittleEndian.PutUint16(entry[4:], winCertRevision)
+	binary.LittleEndian.PutUint16(entry[6:], winCertTypePKCS)
+	copy(entry[winCertificateHeader:], signature)
+
+	binary.LittleEndian.PutUint32(out[img.certDirectory:], uint32(len(out)))
+	binary.LittleEndian.PutUint32(out[img.certDirectory+4:], uint32(length))
+
+	out = append(out, entry...)
+
+	binary.LittleEndian.PutUint32(out[img.checksum:], peChecksum(out, img.checksum))
+
+	return out, nil
+}
+
+// peChecksum calculates the PE image checksum, the checksum field itself is skipped.
+func peChecksum(data []byte, checksumOffset int) uint32 {
+	var sum uint64
+
+	for i := 0; i < len(data); i += 2 {
+		if i == checksumOffset || i == checksumOffset+2 {
+			continue
+		}
+
+		word := uint64(data[i])
+		if i+1 < len(data) {
+			word |= uint64(data[i+1]) << 8
+		}
+
+		sum += word
+		sum = (sum & 0xffff) + (sum >> 16)
+	}
+
+	sum = (sum & 0xffff) + (sum >> 16)
+
+	return uint32(sum) + uint32(len(data))
+}
+
+func align8(n int) int {
+	return (n + 7) &^ 7
+}
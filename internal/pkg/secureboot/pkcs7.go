@@ -0,0 +1,274 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secureboot
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// PKCS#7 and Authenticode object identifiers.
+var (
+	oidData                 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData           = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidContentType          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidMessageDigest        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidSHA256               = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidRSAEncryption        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidSPCIndirectData      = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 1, 4}
+	oidSPCPEImageDataObject = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 1, 15}
+)
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	// Content is [0] EXPLICIT, it is wrapped and unwrapped by hand, as encoding/asn1
+	// doesn't apply the explicit tag to asn1.RawValue on marshaling.
+	Content asn1.RawValue `asn1:"optional,tag:0"`
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	ContentInfo      contentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+type issuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type signerInfo struct {
+	Version                   int
+	IssuerAndSerialNumber     issuerAndSerialNumber
+	DigestAlgorithm           pkix.AlgorithmIdentifier
+	AuthenticatedAttributes   asn1.RawValue `asn1:"optional,tag:0"`
+	DigestEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedDigest           []byte
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue
+}
+
+var (
+	sha256Algorithm = pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue}
+	rsaAlgorithm    = pkix.AlgorithmIdentifier{Algorithm: oidRSAEncryption, Parameters: asn1.NullRawValue}
+)
+
+func explicit(tag int, der []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: tag, IsCompound: true, Bytes: der}
+}
+
+// marshalSet encodes the elements as DER SET OF: elements are sorted by their encoding.
+func marshalSet(elements ...[]byte) []byte {
+	sort.Slice(elements, func(i, j int) bool { return bytes.Compare(elements[i], elements[j]) < 0 })
+
+	return bytes.Join(elements, nil)
+}
+
+func newAttribute(oid asn1.ObjectIdentifier, value interface{}) ([]byte, error) {
+	der, err := asn1.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(attribute{
+		Type:   oid,
+		Values: asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: der},
+	})
+}
+
+// signPKCS7 creates the PKCS#7 SignedData signed with the SHA-256 digest.
+//
+// If the content is nil, the signature is detached: the digest of the signed data is passed in,
+// and the signature is made over the digest directly (no authenticated attributes).
+// Otherwise the content is embedded, and the signature is made over the authenticated attributes
+// which hold the content type and the digest of the content value.
+func (s *Signer) signPKCS7(contentType asn1.ObjectIdentifier, content, digest []byte) ([]byte, error) {
+	info := signerInfo{
+		Version: 1,
+		IssuerAndSerialNumber: issuerAndSerialNumber{
+			Issuer:       asn1.RawValue{FullBytes: s.Certificate.RawIssuer},
+			SerialNumber: s.Certificate.SerialNumber,
+		},
+		DigestAlgorithm:           sha256Algorithm,
+		DigestEncryptionAlgorithm: rsaAlgorithm,
+	}
+
+	toSign := digest
+
+	if content != nil {
+		value, err := contentValue(content)
+		if err != nil {
+			return nil, err
+		}
+
+		contentDigest := sha256Sum(value)
+
+		contentTypeAttr, err := newAttribute(oidContentType, contentType)
+		if err != nil {
+			return nil, err
+		}
+
+		digestAttr, err := newAttribute(oidMessageDigest, contentDigest)
+		if err != nil {
+			return nil, err
+		}
+
+		attrs := marshalSet(contentTypeAttr, digestAttr)
+
+		info.AuthenticatedAttributes = asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: attrs}
+
+		// attributes are signed as SET OF rather than as [0] IMPLICIT
+		signed, err := asn1.Marshal(asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: attrs})
+		if err != nil {
+			return nil, err
+		}
+
+		toSign = sha256Sum(signed)
+	}
+
+	signature, err := s.sign(toSign)
+	if err != nil {
+		return nil, err
+	}
+
+	info.EncryptedDigest = signature
+
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{sha256Algorithm},
+		ContentInfo: contentInfo{
+			ContentType: contentType,
+		},
+		Certificates: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: s.Certificate.Raw},
+		SignerInfos:  []signerInfo{info},
+	}
+
+	if content != nil {
+		sd.ContentInfo.Content = explicit(0, content)
+	}
+
+	return asn1.Marshal(sd)
+}
+
+// wrapContentInfo wraps the SignedData into the ContentInfo.
+//
+// Authenticode signatures are ContentInfo, while the signatures of the EFI variable updates
+// are bare SignedData.
+func wrapContentInfo(signed []byte) ([]byte, error) {
+	return asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content:     explicit(0, signed),
+	})
+}
+
+// contentValue returns the value of the DER element without the tag and length.
+func contentValue(der []byte) ([]byte, error) {
+	var raw asn1.RawValue
+
+	if _, err := asn1.Unmarshal(der, &raw); err != nil {
+		return nil, err
+	}
+
+	return raw.Bytes, nil
+}
+
+// parsePKCS7 parses the PKCS#7 SignedData, optionally wrapped into the ContentInfo.
+//
+// Trailing data is ignored, as the PE certificate table entries are padded to 8 bytes.
+func parsePKCS7(der []byte) (*signedData, error) {
+	var ci contentInfo
+
+	if _, err := asn1.Unmarshal(der, &ci); err == nil && ci.ContentType.Equal(oidSignedData) {
+		der = ci.Content.Bytes
+	}
+
+	var sd signedData
+
+	if _, err := asn1.Unmarshal(der, &sd); err != nil {
+		return nil, fmt.Errorf("error parsing PKCS#7 signed data: %w", err)
+	}
+
+	return &sd, nil
+}
+
+// signer returns the signer certificate and the certificates embedded into the signed data.
+func (sd *signedData) signer() (*x509.Certificate, []*x509.Certificate, error) {
+	if len(sd.SignerInfos) != 1 {
+		return nil, nil, fmt.Errorf("expected exactly one signer, got %d", len(sd.SignerInfos))
+	}
+
+	certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing PKCS#7 certificates: %w", err)
+	}
+
+	info := sd.SignerInfos[0]
+
+	for _, cert := range certs {
+		if bytes.Equal(cert.RawIssuer, info.IssuerAndSerialNumber.Issuer.FullBytes) && cert.SerialNumber.Cmp(info.IssuerAndSerialNumber.SerialNumber) == 0 {
+			return cert, certs, nil
+		}
+	}
+
+	return nil, nil, errors.New("signer certificate is not found in PKCS#7 signed data")
+}
+
+// verifySignature verifies the signature of the signer over the content (or the digest of the detached content).
+func (sd *signedData) verifySignature(signer *x509.Certificate, digest []byte) error {
+	info := sd.SignerInfos[0]
+
+	if !info.DigestAlgorithm.Algorithm.Equal(oidSHA256) {
+		return fmt.Errorf("unsupported digest algorithm %s", info.DigestAlgorithm.Algorithm)
+	}
+
+	if info.AuthenticatedAttributes.Bytes == nil {
+		return checkSignature(signer, digest, info.EncryptedDigest)
+	}
+
+	content, err := contentValue(sd.ContentInfo.Content.Bytes)
+	if err != nil {
+		return err
+	}
+
+	var contentDigest []byte
+
+	rest := info.AuthenticatedAttributes.Bytes
+
+	for len(rest) > 0 {
+		var attr attribute
+
+		if rest, err = asn1.Unmarshal(rest, &attr); err != nil {
+			return fmt.Errorf("error parsing authenticated attributes: %w", err)
+		}
+
+		if attr.Type.Equal(oidMessageDigest) {
+			if _, err = asn1.Unmarshal(attr.Values.Bytes, &contentDigest); err != nil {
+				return fmt.Errorf("error parsing message digest: %w", err)
+			}
+		}
+	}
+
+	if !bytes.Equal(contentDigest, sha256Sum(content)) {
+		return errors.New("message digest doesn't match the signed content")
+	}
+
+	signed, err := asn1.Marshal(asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: info.AuthenticatedAttributes.Bytes})
+	if err != nil {
+		return err
+	}
+
+	return checkSignature(signer, sha256Sum(signed), info.EncryptedDigest)
+}
@@ -0,0 +1,81 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package secureboot provides access to the UEFI SecureBoot state.
+package secureboot
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// EFIVarsPath is the path to the efivarfs mount.
+var EFIVarsPath = "/sys/firmware/efi/efivars"
+
+// efiGlobalVariableGUID is the GUID of the global UEFI variables.
+const efiGlobalVariableGUID = "8be4df61-93ca-11d2-aa0d-00e098032b8c"
+
+// State describes the UEFI SecureBoot state.
+type State struct {
+	// EFI is true when the machine was booted in UEFI mode.
+	EFI bool
+	// Enabled is true when the firmware enforces SecureBoot.
+	Enabled bool
+	// SetupMode is true when the platform key is not enrolled, so the keys can be enrolled.
+	SetupMode bool
+}
+
+// GetState reads the SecureBoot state from the EFI variables.
+func GetState() (State, error) {
+	var state State
+
+	if _, err := os.Stat(EFIVarsPath); err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+
+		return state, err
+	}
+
+	state.EFI = true
+
+	var err error
+
+	if state.Enabled, err = readBoolVariable("SecureBoot"); err != nil {
+		return state, err
+	}
+
+	if state.SetupMode, err = readBoolVariable("SetupMode"); err != nil {
+		return state, err
+	}
+
+	return state, nil
+}
+
+func readBoolVariable(name string) (bool, error) {
+	data, err := ioutil.ReadFile(filepath.Join(EFIVarsPath, name+"-"+efiGlobalVariableGUID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("error reading EFI variable %q: %w", name, err)
+	}
+
+	return ParseBoolVariable(data)
+}
+
+// ParseBoolVariable parses contents of the boolean EFI variable as exposed by efivarfs.
+//
+// First four bytes are the variable attributes followed by the variable value.
+func ParseBoolVariable(data []byte) (bool, error) {
+	if len(data) != 5 {
+		return false, errors.New("unexpected EFI variable size")
+	}
+
+	return data[4] == 1, nil
+}
@@ -0,0 +1,51 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secureboot_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/secureboot"
+)
+
+func TestParseBoolVariable(t *testing.T) {
+	enabled, err := secureboot.ParseBoolVariable([]byte{6, 0, 0, 0, 1})
+	require.NoError(t, err)
+	assert.True(t, enabled)
+
+	enabled, err = secureboot.ParseBoolVariable([]byte{6, 0, 0, 0, 0})
+	require.NoError(t, err)
+	assert.False(t, enabled)
+
+	_, err = secureboot.ParseBoolVariable([]byte{6, 0, 0})
+	assert.Error(t, err)
+}
+
+func TestGetState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "efivars")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	secureboot.EFIVarsPath = filepath.Join(dir, "missing")
+
+	state, err := secureboot.GetState()
+	require.NoError(t, err)
+	assert.Equal(t, secureboot.State{}, state)
+
+	secureboot.EFIVarsPath = dir
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "SecureBoot-8be4df61-93ca-11d2-aa0d-00e098032b8c"), []byte{6, 0, 0, 0, 1}, 0o644))
+
+	state, err = secureboot.GetState()
+	require.NoError(t, err)
+	assert.Equal(t, secureboot.State{EFI: true, Enabled: true}, state)
+}
@@ -0,0 +1,830 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package tpm2 implements the subset of TPM 2.0 commands required for measured boot, attestation
+// and sealing the secrets to the PCR values.
+//
+// Commands are sent to the kernel TPM device directly, only password (empty) authorization
+// sessions and PCR policy sessions are supported.
+package tpm2
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+)
+
+// Devices is the list of TPM devices in the order of preference.
+//
+// In-kernel resource manager allows concurrent access to the TPM.
+var Devices = []string{"/dev/tpmrm0", "/dev/tpm0"}
+
+// ErrNotFound is returned when the TPM device is not available.
+var ErrNotFound = errors.New("TPM device not found")
+
+// PCRCount is the number of PCRs in the TPM PC client profile.
+const PCRCount = 24
+
+// DigestSize is the size of SHA-256 digest.
+const DigestSize = 32
+
+const (
+	tagNoSessions uint16 = 0x8001
+	tagSessions   uint16 = 0x8002
+
+	ccCreatePrimary    uint32 = 0x131
+	ccCreate           uint32 = 0x153
+	ccLoad             uint32 = 0x157
+	ccQuote            uint32 = 0x158
+	ccUnseal           uint32 = 0x15e
+	ccFlushContext     uint32 = 0x165
+	ccStartAuthSession uint32 = 0x176
+	ccPCRRead          uint32 = 0x17e
+	ccPolicyPCR        uint32 = 0x17f
+	ccPCRExtend        uint32 = 0x182
+
+	rhOwner       uint32 = 0x40000001
+	rhNull        uint32 = 0x40000007
+	rhEndorsement uint32 = 0x4000000b
+	rsPassword    uint32 = 0x40000009
+
+	algAES       uint16 = 0x0006
+	algKeyedHash uint16 = 0x0008
+	algECC       uint16 = 0x0023
+	algSHA256    uint16 = 0x000b
+	algNull      uint16 = 0x0010
+	algECDSA     uint16 = 0x0018
+	algCFB       uint16 = 0x0043
+
+	eccNISTP256 uint16 = 0x0003
+
+	sessionPolicy uint8 = 0x01
+
+	// fixedTPM | fixedParent | sensitiveDataOrigin | userWithAuth | restricted | sign.
+	akAttributes uint32 = 0x00050072
+	// fixedTPM | fixedParent | sensitiveDataOrigin | userWithAuth | noDA | restricted | decrypt.
+	srkAttributes uint32 = 0x00030472
+	// fixedTPM | fixedParent | noDA, the sealed object can be only unsealed with the policy session.
+	sealedAttributes uint32 = 0x00000412
+
+	// MaxSealedSize is the maximum size of the data which can be sealed.
+	MaxSealedSize = 128
+
+	headerSize = 10
+)
+
+// ResponseError is the TPM response code of the failed command.
+type ResponseError struct {
+	Command uint32
+	Code    uint32
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("TPM command 0x%x failed with response code 0x%x", e.Command, e.Code)
+}
+
+// TPM is the connection to the TPM device.
+type TPM struct {
+	rw io.ReadWriteCloser
+}
+
+// Open opens the first available TPM device.
+func Open() (*TPM, error) {
+	for _, path := range Devices {
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err == nil {
+			return New(f), nil
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// New wraps the TPM device (or simulator) transport.
+func New(rw io.ReadWriteCloser) *TPM {
+	return &TPM{rw: rw}
+}
+
+// Close the TPM device.
+func (t *TPM) Close() error {
+	return t.rw.Close()
+}
+
+// PCRExtend extends the PCR SHA-256 bank with the digest.
+func (t *TPM) PCRExtend(pcr int, digest []byte) error {
+	if len(digest) != DigestSize {
+		return fmt.Errorf("unexpected digest size %d", len(digest))
+	}
+
+	var params bytes.Buffer
+
+	write(&params, uint32(1), algSHA256)
+	params.Write(digest)
+
+	_, err := t.execute(ccPCRExtend, []uint32{uint32(pcr)}, true, params.Bytes())
+
+	return err
+}
+
+// PCRRead reads the values of the PCRs from SHA-256 bank.
+func (t *TPM) PCRRead(pcrs []int) (map[int][]byte, error) {
+	values := map[int][]byte{}
+
+	pending := append([]int(nil), pcrs...)
+	sort.Ints(pending)
+
+	// TPM might return less digests than requested, so repeat until all PCRs are read
+	for len(pending) > 0 {
+		selection, err := marshalPCRSelection(pending)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.execute(ccPCRRead, nil, false, selection)
+		if err != nil {
+			return nil, err
+		}
+
+		r := bytes.NewReader(resp)
+
+		var updateCounter uint32
+
+		if err = read(r, &updateCounter); err != nil {
+			return nil, err
+		}
+
+		selected, err := unmarshalPCRSelection(r)
+		if err != nil {
+			return nil, err
+		}
+
+		var count uint32
+
+		if err = read(r, &count); err != nil {
+			return nil, err
+		}
+
+		if int(count) != len(selected) || count == 0 {
+			return nil, fmt.Errorf("unexpected number of PCR values: %d", count)
+		}
+
+		for _, pcr := range selected {
+			var digest []byte
+
+			if digest, err = readSized(r); err != nil {
+				return nil, err
+			}
+
+			values[pcr] = digest
+		}
+
+		remaining := pending[:0]
+
+		for _, pcr := range pending {
+			if _, ok := values[pcr]; !ok {
+				remaining = append(remaining, pcr)
+			}
+		}
+
+		pending = remaining
+	}
+
+	return values, nil
+}
+
+// CreateAttestationKey creates the primary restricted ECDSA P-256 signing key in the endorsement hierarchy.
+//
+// The key is derived from the endorsement primary seed, so the same key is returned on every call
+// until the TPM is cleared. The key should be flushed with FlushContext after use.
+func (t *TPM) CreateAttestationKey() (uint32, *ecdsa.PublicKey, error) {
+	var public bytes.Buffer
+
+	write(&public, algECC, algSHA256, akAttributes)
+	write(&public, uint16(0))            // authPolicy
+	write(&public, algNull)              // symmetric
+	write(&public, algECDSA, algSHA256)  // scheme
+	write(&public, eccNISTP256)          // curveID
+	write(&public, algNull)              // kdf
+	write(&public, uint16(0), uint16(0)) // unique
+
+	handle, outPublic, err := t.createPrimary(rhEndorsement, public.Bytes())
+	if err != nil {
+		return 0, nil, err
+	}
+
+	pub, err := unmarshalECCPublic(outPublic)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return handle, pub, nil
+}
+
+// createStorageKey creates the primary ECC P-256 storage key in the owner hierarchy.
+//
+// The key is derived from the owner primary seed, so the objects created under it can be loaded
+// on every boot until the TPM is cleared.
+func (t *TPM) createStorageKey() (uint32, error) {
+	var public bytes.Buffer
+
+	write(&public, algECC, algSHA256, srkAttributes)
+	write(&public, uint16(0))                   // authPolicy
+	write(&public, algAES, uint16(128), algCFB) // symmetric
+	write(&public, algNull)                     // scheme
+	write(&public, eccNISTP256)                 // curveID
+	write(&public, algNull)                     // kdf
+	write(&public, uint16(0), uint16(0))        // unique
+
+	handle, _, err := t.createPrimary(rhOwner, public.Bytes())
+
+	return handle, err
+}
+
+func (t *TPM) createPrimary(hierarchy uint32, public []byte) (uint32, []byte, error) {
+	var params bytes.Buffer
+
+	write(&params, uint16(4), uint16(0), uint16(0)) // inSensitive
+	writeSized(&params, public)                     // inPublic
+	write(&params, uint16(0))                       // outsideInfo
+	write(&params, uint32(0))                       // creationPCR
+
+	resp, err := t.execute(ccCreatePrimary, []uint32{hierarchy}, true, params.Bytes())
+	if err != nil {
+		return 0, nil, err
+	}
+
+	r := bytes.NewReader(resp)
+
+	var handle, paramSize uint32
+
+	if err = read(r, &handle, &paramSize); err != nil {
+		return 0, nil, err
+	}
+
+	outPublic, err := readSized(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return handle, outPublic, nil
+}
+
+// SealedObject is the data sealed to the PCR values.
+//
+// Public and Private are the TPM2B_PUBLIC and TPM2B_PRIVATE contents of the sealed object,
+// they can be stored outside of the TPM: the private part is encrypted with the storage key.
+type SealedObject struct {
+	Public  []byte
+	Private []byte
+	PCRs    []int
+}
+
+// Seal seals the data to the current values of the PCRs.
+//
+// The data can be unsealed only by the same TPM and only while the PCRs have the same values.
+func (t *TPM) Seal(pcrs []int, data []byte) (*SealedObject, error) {
+	if len(data) == 0 || len(data) > MaxSealedSize {
+		return nil, fmt.Errorf("unexpected sealed data size %d", len(data))
+	}
+
+	values, err := t.PCRRead(pcrs)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := PolicyPCRDigest(pcrs, values)
+	if err != nil {
+		return nil, err
+	}
+
+	srk, err := t.createStorageKey()
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint: errcheck
+	defer t.FlushContext(srk)
+
+	var sensitive bytes.Buffer
+
+	write(&sensitive, uint16(0)) // userAuth
+	writeSized(&sensitive, data)
+
+	var public bytes.Buffer
+
+	write(&public, algKeyedHash, algSHA256, sealedAttributes)
+	writeSized(&public, policy)
+	write(&public, algNull)   // scheme
+	write(&public, uint16(0)) // unique
+
+	var params bytes.Buffer
+
+	writeSized(&params, sensitive.Bytes()) // inSensitive
+	writeSized(&params, public.Bytes())    // inPublic
+	write(&params, uint16(0))              // outsideInfo
+	write(&params, uint32(0))              // creationPCR
+
+	resp, err := t.execute(ccCreate, []uint32{srk}, true, params.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	r := bytes.NewReader(resp)
+
+	var paramSize uint32
+
+	if err = read(r, &paramSize); err != nil {
+		return nil, err
+	}
+
+	obj := &SealedObject{
+		PCRs: append([]int(nil), pcrs...),
+	}
+
+	if obj.Private, err = readSized(r); err != nil {
+		return nil, err
+	}
+
+	if obj.Public, err = readSized(r); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// Unseal returns the sealed data if the PCRs have the same values as when the data was sealed.
+func (t *TPM) Unseal(obj *SealedObject) ([]byte, error) {
+	srk, err := t.createStorageKey()
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint: errcheck
+	defer t.FlushContext(srk)
+
+	var params bytes.Buffer
+
+	writeSized(&params, obj.Private)
+	writeSized(&params, obj.Public)
+
+	resp, err := t.execute(ccLoad, []uint32{srk}, true, params.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	var handle uint32
+
+	if err = read(bytes.NewReader(resp), &handle); err != nil {
+		return nil, err
+	}
+
+	//nolint: errcheck
+	defer t.FlushContext(handle)
+
+	session, err := t.startPolicySession()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := t.unsealWithPolicy(handle, session, obj.PCRs)
+	if err != nil {
+		// the session is flushed by the TPM only if the command succeeds
+		t.FlushContext(session) //nolint: errcheck
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (t *TPM) startPolicySession() (uint32, error) {
+	nonce := make([]byte, DigestSize)
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return 0, err
+	}
+
+	var params bytes.Buffer
+
+	writeSized(&params, nonce)    // nonceCaller
+	write(&params, uint16(0))     // encryptedSalt
+	write(&params, sessionPolicy) // sessionType
+	write(&params, algNull)       // symmetric
+	write(&params, algSHA256)     // authHash
+
+	resp, err := t.execute(ccStartAuthSession, []uint32{rhNull, rhNull}, false, params.Bytes())
+	if err != nil {
+		return 0, err
+	}
+
+	var session uint32
+
+	if err = read(bytes.NewReader(resp), &session); err != nil {
+		return 0, err
+	}
+
+	return session, nil
+}
+
+func (t *TPM) unsealWithPolicy(handle, session uint32, pcrs []int) ([]byte, error) {
+	selection, err := marshalPCRSelection(pcrs)
+	if err != nil {
+		return nil, err
+	}
+
+	var params bytes.Buffer
+
+	write(&params, uint16(0)) // pcrDigest, TPM uses the current values
+	params.Write(selection)
+
+	if _, err = t.execute(ccPolicyPCR, []uint32{session}, false, params.Bytes()); err != nil {
+		return nil, err
+	}
+
+	// TPMS_AUTH_COMMAND: policy session, empty nonce, session is closed after the command, empty HMAC
+	var auth bytes.Buffer
+
+	write(&auth, session, uint16(0), uint8(0), uint16(0))
+
+	resp, err := t.run(ccUnseal, []uint32{handle}, auth.Bytes(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r := bytes.NewReader(resp)
+
+	var paramSize uint32
+
+	if err = read(r, &paramSize); err != nil {
+		return nil, err
+	}
+
+	return readSized(r)
+}
+
+// PolicyPCRDigest computes the digest of the policy which allows the use of the object
+// only while the PCRs have the specified values.
+func PolicyPCRDigest(pcrs []int, values map[int][]byte) ([]byte, error) {
+	selection, err := marshalPCRSelection(pcrs)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := append([]int(nil), pcrs...)
+	sort.Ints(sorted)
+
+	// PCR values are concatenated in the order of the selection bitmap
+	pcrDigest := sha256.New()
+
+	for _, pcr := range sorted {
+		value, ok := values[pcr]
+		if !ok {
+			return nil, fmt.Errorf("missing value of PCR %d", pcr)
+		}
+
+		pcrDigest.Write(value) //nolint: errcheck
+	}
+
+	var buf bytes.Buffer
+
+	buf.Write(make([]byte, DigestSize)) // initial policy digest
+	write(&buf, ccPolicyPCR)
+	buf.Write(selection)
+	buf.Write(pcrDigest.Sum(nil))
+
+	digest := sha256.Sum256(buf.Bytes())
+
+	return digest[:], nil
+}
+
+// Quote signs the values of the PCRs with the attestation key.
+//
+// Quote returns TPMS_ATTEST structure and its ASN.1 DER encoded ECDSA signature.
+func (t *TPM) Quote(handle uint32, nonce []byte, pcrs []int) (quoted, signature []byte, err error) {
+	selection, err := marshalPCRSelection(pcrs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var params bytes.Buffer
+
+	writeSized(&params, nonce)
+	write(&params, algNull) // use the key scheme
+	params.Write(selection)
+
+	resp, err := t.execute(ccQuote, []uint32{handle}, true, params.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := bytes.NewReader(resp)
+
+	var paramSize uint32
+
+	if err = read(r, &paramSize); err != nil {
+		return nil, nil, err
+	}
+
+	if quoted, err = readSized(r); err != nil {
+		return nil, nil, err
+	}
+
+	var sigAlg, hashAlg uint16
+
+	if err = read(r, &sigAlg, &hashAlg); err != nil {
+		return nil, nil, err
+	}
+
+	if sigAlg != algECDSA {
+		return nil, nil, fmt.Errorf("unexpected signature algorithm 0x%x", sigAlg)
+	}
+
+	sigR, err := readSized(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sigS, err := readSized(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signature, err = asn1.Marshal(struct {
+		R, S *big.Int
+	}{
+		R: new(big.Int).SetBytes(sigR),
+		S: new(big.Int).SetBytes(sigS),
+	})
+
+	return quoted, signature, err
+}
+
+// FlushContext removes the transient object from the TPM.
+func (t *TPM) FlushContext(handle uint32) error {
+	var params bytes.Buffer
+
+	write(&params, handle)
+
+	_, err := t.execute(ccFlushContext, nil, false, params.Bytes())
+
+	return err
+}
+
+// execute sends the command to the TPM and returns the response without the header.
+//
+// Commands with authorization are sent with the empty password session for each handle,
+// and the response authorization area is stripped.
+func (t *TPM) execute(cc uint32, handles []uint32, auth bool, params []byte) ([]byte, error) {
+	if !auth {
+		return t.run(cc, handles, nil, params)
+	}
+
+	var authArea bytes.Buffer
+
+	for range handles {
+		// TPMS_AUTH_COMMAND: password session, empty nonce, continueSession, empty password
+		write(&authArea, rsPassword, uint16(0), uint8(1), uint16(0))
+	}
+
+	return t.run(cc, handles, authArea.Bytes(), params)
+}
+
+// run sends the command with the authorization area (if any) to the TPM and returns the response without the header.
+func (t *TPM) run(cc uint32, handles []uint32, authArea, params []byte) ([]byte, error) {
+	var body bytes.Buffer
+
+	for _, handle := range handles {
+		write(&body, handle)
+	}
+
+	tag := tagNoSessions
+
+	if authArea != nil {
+		tag = tagSessions
+
+		write(&body, uint32(len(authArea)))
+		body.Write(authArea)
+	}
+
+	body.Write(params)
+
+	var cmd bytes.Buffer
+
+	write(&cmd, tag, uint32(headerSize+body.Len()), cc)
+	cmd.Write(body.Bytes())
+
+	if _, err := t.rw.Write(cmd.Bytes()); err != nil {
+		return nil, fmt.Errorf("error sending TPM command: %w", err)
+	}
+
+	buf := make([]byte, 4096)
+
+	n, err := t.rw.Read(buf)
+	if err != nil {
+		return nil, fmt.Errorf("error reading TPM response: %w", err)
+	}
+
+	if n < headerSize {
+		return nil, fmt.Errorf("TPM response is too short: %d bytes", n)
+	}
+
+	var (
+		respTag  uint16
+		respSize uint32
+		code     uint32
+	)
+
+	if err = read(bytes.NewReader(buf[:headerSize]), &respTag, &respSize, &code); err != nil {
+		return nil, err
+	}
+
+	if code != 0 {
+		return nil, &ResponseError{Command: cc, Code: code}
+	}
+
+	if int(respSize) != n {
+		return nil, fmt.Errorf("TPM response size mismatch: %d != %d", respSize, n)
+	}
+
+	return buf[headerSize:n], nil
+}
+
+func marshalPCRSelection(pcrs []int) ([]byte, error) {
+	bitmap := make([]byte, PCRCount/8)
+
+	for _, pcr := range pcrs {
+		if pcr < 0 || pcr >= PCRCount {
+			return nil, fmt.Errorf("invalid PCR %d", pcr)
+		}
+
+		bitmap[pcr/8] |= 1 << (pcr % 8)
+	}
+
+	var buf bytes.Buffer
+
+	write(&buf, uint32(1), algSHA256, uint8(len(bitmap)))
+	buf.Write(bitmap)
+
+	return buf.Bytes(), nil
+}
+
+// unmarshalPCRSelection returns the sorted list of selected SHA-256 PCRs.
+func unmarshalPCRSelection(r io.Reader) ([]int, error) {
+	var count uint32
+
+	if err := read(r, &count); err != nil {
+		return nil, err
+	}
+
+	var pcrs []int
+
+	for i := uint32(0); i < count; i++ {
+		var (
+			hashAlg uint16
+			size    uint8
+		)
+
+		if err := read(r, &hashAlg, &size); err != nil {
+			return nil, err
+		}
+
+		bitmap := make([]byte, size)
+
+		if _, err := io.ReadFull(r, bitmap); err != nil {
+			return nil, err
+		}
+
+		if hashAlg != algSHA256 {
+			continue
+		}
+
+		for pcr := 0; pcr < int(size)*8; pcr++ {
+			if bitmap[pcr/8]&(1<<(pcr%8)) != 0 {
+				pcrs = append(pcrs, pcr)
+			}
+		}
+	}
+
+	return pcrs, nil
+}
+
+func unmarshalECCPublic(b []byte) (*ecdsa.PublicKey, error) {
+	r := bytes.NewReader(b)
+
+	var (
+		typ, nameAlg uint16
+		attributes   uint32
+	)
+
+	if err := read(r, &typ, &nameAlg, &attributes); err != nil {
+		return nil, err
+	}
+
+	if typ != algECC {
+		return nil, fmt.Errorf("unexpected key type 0x%x", typ)
+	}
+
+	if _, err := readSized(r); err != nil { // authPolicy
+		return nil, err
+	}
+
+	var symmetric, scheme uint16
+
+	if err := read(r, &symmetric); err != nil {
+		return nil, err
+	}
+
+	if symmetric != algNull {
+		return nil, fmt.Errorf("unexpected symmetric algorithm 0x%x", symmetric)
+	}
+
+	if err := read(r, &scheme); err != nil {
+		return nil, err
+	}
+
+	if scheme != algNull {
+		var schemeHash uint16
+
+		if err := read(r, &schemeHash); err != nil {
+			return nil, err
+		}
+	}
+
+	var curve, kdf uint16
+
+	if err := read(r, &curve, &kdf); err != nil {
+		return nil, err
+	}
+
+	if curve != eccNISTP256 || kdf != algNull {
+		return nil, fmt.Errorf("unexpected curve 0x%x", curve)
+	}
+
+	x, err := readSized(r)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := readSized(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}, nil
+}
+
+func write(buf *bytes.Buffer, values ...interface{}) {
+	for _, v := range values {
+		binary.Write(buf, binary.BigEndian, v) //nolint: errcheck
+	}
+}
+
+func writeSized(buf *bytes.Buffer, b []byte) {
+	write(buf, uint16(len(b)))
+	buf.Write(b)
+}
+
+func read(r io.Reader, values ...interface{}) error {
+	for _, v := range values {
+		if err := binary.Read(r, binary.BigEndian, v); err != nil {
+			return fmt.Errorf("error parsing TPM response: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func readSized(r io.Reader) ([]byte, error) {
+	var size uint16
+
+	if err := read(r, &size); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, size)
+
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, fmt.Errorf("error parsing TPM response: %w", err)
+	}
+
+	return b, nil
+}
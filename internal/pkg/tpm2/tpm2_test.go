@@ -0,0 +1,295 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package tpm2_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/talos-systems/talos/internal/pkg/tpm2"
+)
+
+// fakeTPM records the commands and replies with the canned responses.
+type fakeTPM struct {
+	commands  [][]byte
+	responses [][]byte
+}
+
+func (f *fakeTPM) Write(b []byte) (int, error) {
+	f.commands = append(f.commands, append([]byte(nil), b...))
+
+	return len(b), nil
+}
+
+func (f *fakeTPM) Read(b []byte) (int, error) {
+	resp := f.responses[0]
+	f.responses = f.responses[1:]
+
+	return copy(b, resp), nil
+}
+
+func (f *fakeTPM) Close() error {
+	return nil
+}
+
+func response(tag uint16, code uint32, body ...interface{}) []byte {
+	var buf bytes.Buffer
+
+	for _, v := range body {
+		if b, ok := v.([]byte); ok {
+			buf.Write(b)
+		} else {
+			binary.Write(&buf, binary.BigEndian, v) //nolint: errcheck
+		}
+	}
+
+	var resp bytes.Buffer
+
+	binary.Write(&resp, binary.BigEndian, tag)                  //nolint: errcheck
+	binary.Write(&resp, binary.BigEndian, uint32(10+buf.Len())) //nolint: errcheck
+	binary.Write(&resp, binary.BigEndian, code)                 //nolint: errcheck
+	resp.Write(buf.Bytes())
+
+	return resp.Bytes()
+}
+
+func sized(b []byte) []byte {
+	return append([]byte{byte(len(b) >> 8), byte(len(b))}, b...)
+}
+
+// emptyAuth is the response authorization area of the password session.
+var emptyAuth = []byte{0, 0, 1, 0, 0}
+
+type TPMSuite struct {
+	suite.Suite
+
+	fake *fakeTPM
+	tpm  *tpm2.TPM
+}
+
+func (suite *TPMSuite) SetupTest() {
+	suite.fake = &fakeTPM{}
+	suite.tpm = tpm2.New(suite.fake)
+}
+
+func (suite *TPMSuite) TestPCRExtend() {
+	digest := sha256.Sum256([]byte("talos"))
+
+	suite.fake.responses = append(suite.fake.responses, response(0x8002, 0, uint32(0), emptyAuth))
+
+	suite.Require().NoError(suite.tpm.PCRExtend(11, digest[:]))
+
+	suite.Require().Len(suite.fake.commands, 1)
+	suite.Assert().Equal(
+		"80020000004100000182"+"0000000b"+"00000009"+"400000090000010000"+"00000001000b"+hex.EncodeToString(digest[:]),
+		hex.EncodeToString(suite.fake.commands[0]),
+	)
+
+	suite.Assert().EqualError(suite.tpm.PCRExtend(11, []byte("short")), "unexpected digest size 5")
+}
+
+func (suite *TPMSuite) TestPCRRead() {
+	pcr0 := bytes.Repeat([]byte{0xaa}, 32)
+	pcr11 := bytes.Repeat([]byte{0xbb}, 32)
+
+	suite.fake.responses = append(suite.fake.responses,
+		response(0x8001, 0, uint32(5), uint32(1), uint16(0x000b), uint8(3), []byte{0x01, 0x08, 0x00}, uint32(2), sized(pcr0), sized(pcr11)),
+	)
+
+	values, err := suite.tpm.PCRRead([]int{11, 0})
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal(map[int][]byte{0: pcr0, 11: pcr11}, values)
+	suite.Assert().Equal("8001"+"00000014"+"0000017e"+"00000001000b03010800", hex.EncodeToString(suite.fake.commands[0]))
+}
+
+func (suite *TPMSuite) TestPCRReadPartial() {
+	pcr0 := bytes.Repeat([]byte{0xaa}, 32)
+	pcr11 := bytes.Repeat([]byte{0xbb}, 32)
+
+	suite.fake.responses = append(suite.fake.responses,
+		response(0x8001, 0, uint32(5), uint32(1), uint16(0x000b), uint8(3), []byte{0x01, 0x00, 0x00}, uint32(1), sized(pcr0)),
+		response(0x8001, 0, uint32(5), uint32(1), uint16(0x000b), uint8(3), []byte{0x00, 0x08, 0x00}, uint32(1), sized(pcr11)),
+	)
+
+	values, err := suite.tpm.PCRRead([]int{0, 11})
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal(map[int][]byte{0: pcr0, 11: pcr11}, values)
+	suite.Assert().Len(suite.fake.commands, 2)
+}
+
+func (suite *TPMSuite) TestResponseError() {
+	suite.fake.responses = append(suite.fake.responses, response(0x8001, 0x101))
+
+	_, err := suite.tpm.PCRRead([]int{0})
+	suite.Assert().EqualError(err, "TPM command 0x17e failed with response code 0x101")
+}
+
+func (suite *TPMSuite) TestAttestationKeyAndQuote() {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+
+	var public bytes.Buffer
+
+	binary.Write(&public, binary.BigEndian, []uint16{0x0023, 0x000b})                            //nolint: errcheck
+	binary.Write(&public, binary.BigEndian, uint32(0x00050072))                                  //nolint: errcheck
+	binary.Write(&public, binary.BigEndian, []uint16{0, 0x0010, 0x0018, 0x000b, 0x0003, 0x0010}) //nolint: errcheck
+	public.Write(sized(key.X.Bytes()))
+	public.Write(sized(key.Y.Bytes()))
+
+	suite.fake.responses = append(suite.fake.responses,
+		response(0x8002, 0, uint32(0x80000000), uint32(0), sized(public.Bytes()), emptyAuth),
+	)
+
+	handle, pub, err := suite.tpm.CreateAttestationKey()
+	suite.Require().NoError(err)
+
+	suite.Assert().EqualValues(0x80000000, handle)
+	suite.Assert().Equal(key.PublicKey.X, pub.X)
+	suite.Assert().Equal(key.PublicKey.Y, pub.Y)
+
+	quoted := []byte("attest")
+	hash := sha256.Sum256(quoted)
+
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	suite.Require().NoError(err)
+
+	suite.fake.responses = append(suite.fake.responses,
+		response(0x8002, 0, uint32(0), sized(quoted), uint16(0x0018), uint16(0x000b), sized(r.Bytes()), sized(s.Bytes()), emptyAuth),
+	)
+
+	attest, signature, err := suite.tpm.Quote(handle, []byte("nonce"), []int{0, 11})
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal(quoted, attest)
+
+	var sig struct {
+		R, S *big.Int
+	}
+
+	_, err = asn1.Unmarshal(signature, &sig)
+	suite.Require().NoError(err)
+
+	suite.Assert().True(ecdsa.Verify(&key.PublicKey, hash[:], sig.R, sig.S))
+}
+
+func (suite *TPMSuite) TestSealUnseal() {
+	pcr7 := bytes.Repeat([]byte{0x77}, 32)
+
+	policy, err := tpm2.PolicyPCRDigest([]int{7}, map[int][]byte{7: pcr7})
+	suite.Require().NoError(err)
+
+	// PolicyPCR digest is computed over the concatenated PCR values
+	pcrDigest := sha256.Sum256(pcr7)
+	expectedPolicy := sha256.Sum256(append(append(make([]byte, 32), []byte{0, 0, 1, 0x7f, 0, 0, 0, 1, 0, 0x0b, 3, 0x80, 0, 0}...), pcrDigest[:]...))
+	suite.Assert().Equal(expectedPolicy[:], policy)
+
+	suite.fake.responses = append(suite.fake.responses,
+		response(0x8001, 0, uint32(5), uint32(1), uint16(0x000b), uint8(3), []byte{0x80, 0x00, 0x00}, uint32(1), sized(pcr7)),
+		response(0x8002, 0, uint32(0x80000000), uint32(0), sized([]byte("srk")), emptyAuth),
+		response(0x8002, 0, uint32(0), sized([]byte("private")), sized([]byte("public")), emptyAuth),
+		response(0x8001, 0),
+	)
+
+	obj, err := suite.tpm.Seal([]int{7}, []byte("secret"))
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal(&tpm2.SealedObject{
+		Public:  []byte("public"),
+		Private: []byte("private"),
+		PCRs:    []int{7},
+	}, obj)
+
+	suite.Require().Len(suite.fake.commands, 4)
+
+	// CreatePrimary in the owner hierarchy
+	suite.Assert().Equal("00000131"+"40000001", hex.EncodeToString(suite.fake.commands[1][6:14]))
+
+	// Create under the storage key with the PCR policy and without userWithAuth
+	create := hex.EncodeToString(suite.fake.commands[2])
+	suite.Assert().Equal("00000153"+"80000000", create[12:28])
+	suite.Assert().Contains(create, "0008000b00000412"+"0020"+hex.EncodeToString(policy)+"0010"+"0000")
+	suite.Assert().Contains(create, "0000"+hex.EncodeToString(sized([]byte("secret"))))
+
+	// FlushContext of the storage key
+	suite.Assert().Equal("80010000000e0000016580000000", hex.EncodeToString(suite.fake.commands[3]))
+
+	suite.fake.commands = nil
+	suite.fake.responses = append(suite.fake.responses,
+		response(0x8002, 0, uint32(0x80000000), uint32(0), sized([]byte("srk")), emptyAuth),
+		response(0x8002, 0, uint32(0x80000001), uint32(0), sized([]byte("name")), emptyAuth),
+		response(0x8001, 0, uint32(0x03000000), sized(make([]byte, 32))),
+		response(0x8001, 0),
+		response(0x8002, 0, uint32(8), sized([]byte("secret")), []byte{0, 0, 0, 0, 0}),
+		response(0x8001, 0),
+		response(0x8001, 0),
+	)
+
+	data, err := suite.tpm.Unseal(obj)
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal([]byte("secret"), data)
+
+	suite.Require().Len(suite.fake.commands, 7)
+
+	// StartAuthSession: policy session, no salt and no bind
+	suite.Assert().Equal("00000176"+"40000007"+"40000007", hex.EncodeToString(suite.fake.commands[2][6:18]))
+	suite.Assert().Equal("01"+"0010"+"000b", hex.EncodeToString(suite.fake.commands[2][len(suite.fake.commands[2])-5:]))
+
+	// PolicyPCR with the current PCR values
+	suite.Assert().Equal("8001"+"0000001a"+"0000017f"+"03000000"+"0000"+"00000001000b03800000", hex.EncodeToString(suite.fake.commands[3]))
+
+	// Unseal authorized with the policy session
+	suite.Assert().Equal("8002"+"0000001b"+"0000015e"+"80000001"+"00000009"+"03000000"+"0000"+"00"+"0000", hex.EncodeToString(suite.fake.commands[4]))
+
+	// sealed object and the storage key are flushed, the session is closed by the TPM
+	suite.Assert().Equal("80010000000e0000016580000001", hex.EncodeToString(suite.fake.commands[5]))
+	suite.Assert().Equal("80010000000e0000016580000000", hex.EncodeToString(suite.fake.commands[6]))
+}
+
+func (suite *TPMSuite) TestUnsealPolicyFailure() {
+	suite.fake.responses = append(suite.fake.responses,
+		response(0x8002, 0, uint32(0x80000000), uint32(0), sized([]byte("srk")), emptyAuth),
+		response(0x8002, 0, uint32(0x80000001), uint32(0), sized([]byte("name")), emptyAuth),
+		response(0x8001, 0, uint32(0x03000000), sized(make([]byte, 32))),
+		response(0x8001, 0),
+		response(0x8001, 0x99d), // TPM_RC_POLICY_FAIL
+		response(0x8001, 0),
+		response(0x8001, 0),
+		response(0x8001, 0),
+	)
+
+	_, err := suite.tpm.Unseal(&tpm2.SealedObject{Public: []byte("public"), Private: []byte("private"), PCRs: []int{7}})
+	suite.Require().EqualError(err, "TPM command 0x15e failed with response code 0x99d")
+
+	suite.Require().Len(suite.fake.commands, 8)
+
+	// the session is flushed along with the sealed object and the storage key
+	suite.Assert().Equal("80010000000e0000016503000000", hex.EncodeToString(suite.fake.commands[5]))
+	suite.Assert().Equal("80010000000e0000016580000001", hex.EncodeToString(suite.fake.commands[6]))
+	suite.Assert().Equal("80010000000e0000016580000000", hex.EncodeToString(suite.fake.commands[7]))
+}
+
+func (suite *TPMSuite) TestSealSize() {
+	_, err := suite.tpm.Seal([]int{7}, make([]byte, tpm2.MaxSealedSize+1))
+	suite.Assert().EqualError(err, "unexpected sealed data size 129")
+
+	suite.Assert().Empty(suite.fake.commands)
+}
+
+func TestTPMSuite(t *testing.T) {
+	suite.Run(t, new(TPMSuite))
+}
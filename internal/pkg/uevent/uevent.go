@@ -0,0 +1,151 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package uevent implements the listener for the kernel and udev device events.
+package uevent
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Netlink multicast groups of the device events.
+const (
+	// GroupKernel receives the events as they are sent by the kernel.
+	GroupKernel = 1
+	// GroupUdev receives the events after they are processed by udevd (device nodes and symlinks exist).
+	GroupUdev = 2
+)
+
+// udevMagic is the magic of the udev netlink message header (libudev-monitor.c).
+const udevMagic = 0xfeedcafe
+
+var udevPrefix = []byte("libudev\x00")
+
+// receiveBufferSize is big enough to hold the burst of events on the disk partitioning.
+const receiveBufferSize = 1024 * 1024
+
+// messageBufferSize is the maximum size of a single event message.
+const messageBufferSize = 16 * 1024
+
+// Event is the device event.
+type Event struct {
+	Action     string
+	DevPath    string
+	Subsystem  string
+	Properties map[string]string
+}
+
+// Listener receives the device events.
+type Listener struct {
+	f   *os.File
+	buf []byte
+}
+
+// Listen subscribes to the device events of the netlink multicast group.
+func Listen(group uint32) (*Listener, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC|unix.SOCK_NONBLOCK, unix.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return nil, fmt.Errorf("error opening uevent socket: %w", err)
+	}
+
+	// nolint: errcheck
+	unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_RCVBUFFORCE, receiveBufferSize)
+
+	if err = unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: group}); err != nil {
+		// nolint: errcheck
+		unix.Close(fd)
+
+		return nil, fmt.Errorf("error binding uevent socket: %w", err)
+	}
+
+	// non-blocking file descriptor is registered in the runtime poller, so Close unblocks Read
+	return &Listener{
+		f:   os.NewFile(uintptr(fd), "uevent"),
+		buf: make([]byte, messageBufferSize),
+	}, nil
+}
+
+// Read blocks until the next event is received.
+func (l *Listener) Read() (*Event, error) {
+	for {
+		n, err := l.f.Read(l.buf)
+		if err != nil {
+			return nil, err
+		}
+
+		event, err := Parse(l.buf[:n])
+		if err != nil {
+			// skip malformed messages
+			continue
+		}
+
+		return event, nil
+	}
+}
+
+// Close stops the listener.
+func (l *Listener) Close() error {
+	return l.f.Close()
+}
+
+// Parse decodes the kernel (`ACTION@DEVPATH` header) or udev (`libudev` header) event message.
+func Parse(msg []byte) (*Event, error) {
+	var properties []byte
+
+	if bytes.HasPrefix(msg, udevPrefix) {
+		if len(msg) < 24 {
+			return nil, errors.New("udev message is too short")
+		}
+
+		if binary.BigEndian.Uint32(msg[8:12]) != udevMagic {
+			return nil, errors.New("udev message magic mismatch")
+		}
+
+		off := binary.LittleEndian.Uint32(msg[16:20])
+		length := binary.LittleEndian.Uint32(msg[20:24])
+
+		if uint64(off)+uint64(length) > uint64(len(msg)) {
+			return nil, errors.New("udev message properties are out of bounds")
+		}
+
+		properties = msg[off : off+length]
+	} else {
+		header := bytes.IndexByte(msg, 0)
+		if header < 0 || !bytes.Contains(msg[:header], []byte("@")) {
+			return nil, errors.New("kernel message header is missing")
+		}
+
+		properties = msg[header+1:]
+	}
+
+	event := &Event{
+		Properties: map[string]string{},
+	}
+
+	for _, property := range bytes.Split(properties, []byte{0}) {
+		kv := strings.SplitN(string(property), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		event.Properties[kv[0]] = kv[1]
+	}
+
+	event.Action = event.Properties["ACTION"]
+	event.DevPath = event.Properties["DEVPATH"]
+	event.Subsystem = event.Properties["SUBSYSTEM"]
+
+	if event.Action == "" || event.DevPath == "" {
+		return nil, errors.New("event is missing ACTION or DEVPATH")
+	}
+
+	return event, nil
+}
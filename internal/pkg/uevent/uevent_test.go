@@ -0,0 +1,61 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package uevent_test
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/uevent"
+)
+
+const properties = "ACTION=add\x00DEVPATH=/devices/pci0000:00/0000:00:05.0/virtio2/block/vdb\x00SUBSYSTEM=block\x00DEVNAME=vdb\x00DEVTYPE=disk\x00SEQNUM=2138\x00"
+
+func TestParseKernel(t *testing.T) {
+	event, err := uevent.Parse([]byte("add@/devices/pci0000:00/0000:00:05.0/virtio2/block/vdb\x00" + properties))
+	require.NoError(t, err)
+
+	assert.Equal(t, "add", event.Action)
+	assert.Equal(t, "/devices/pci0000:00/0000:00:05.0/virtio2/block/vdb", event.DevPath)
+	assert.Equal(t, "block", event.Subsystem)
+	assert.Equal(t, "vdb", event.Properties["DEVNAME"])
+	assert.Equal(t, "disk", event.Properties["DEVTYPE"])
+}
+
+func TestParseUdev(t *testing.T) {
+	const headerSize = 40
+
+	msg := make([]byte, headerSize)
+	copy(msg, "libudev\x00")
+	binary.BigEndian.PutUint32(msg[8:], 0xfeedcafe)
+	binary.LittleEndian.PutUint32(msg[12:], headerSize)
+	binary.LittleEndian.PutUint32(msg[16:], headerSize)
+	binary.LittleEndian.PutUint32(msg[20:], uint32(len(properties)+len("ID_MODEL=QEMU_HARDDISK\x00")))
+
+	msg = append(msg, properties+"ID_MODEL=QEMU_HARDDISK\x00"...)
+
+	event, err := uevent.Parse(msg)
+	require.NoError(t, err)
+
+	assert.Equal(t, "add", event.Action)
+	assert.Equal(t, "block", event.Subsystem)
+	assert.Equal(t, "QEMU_HARDDISK", event.Properties["ID_MODEL"])
+}
+
+func TestParseMalformed(t *testing.T) {
+	for _, msg := range []string{
+		"",
+		"libudev\x00",
+		strings.Repeat("x", 64),
+		"add@/devices/virtual/block/loop0\x00SUBSYSTEM=block\x00",
+	} {
+		_, err := uevent.Parse([]byte(msg))
+		assert.Error(t, err, "%q", msg)
+	}
+}
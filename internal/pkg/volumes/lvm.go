@@ -0,0 +1,63 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package volumes
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+const lvm = "/sbin/lvm"
+
+// LogicalVolumeDevice returns the path to the LVM logical volume device.
+func LogicalVolumeDevice(vg, lv string) string {
+	return filepath.Join("/dev", vg, lv)
+}
+
+// EnsureVolumeGroup creates the volume group on the physical volumes.
+//
+// Existing volume group is activated.
+func EnsureVolumeGroup(name string, pvs []string) error {
+	if _, err := run(lvm, "vgs", name); err == nil {
+		if _, err = run(lvm, "vgchange", "-ay", name); err != nil {
+			return fmt.Errorf("error activating volume group %q: %w", name, err)
+		}
+
+		return nil
+	}
+
+	if _, err := run(lvm, append([]string{"pvcreate", "-y"}, pvs...)...); err != nil {
+		return fmt.Errorf("error creating physical volumes for volume group %q: %w", name, err)
+	}
+
+	if _, err := run(lvm, append([]string{"vgcreate", name}, pvs...)...); err != nil {
+		return fmt.Errorf("error creating volume group %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// EnsureLogicalVolume creates the logical volume in the volume group.
+//
+// Logical volume of size 0 occupies the remaining free space of the volume group.
+func EnsureLogicalVolume(vg, name string, size uint64) (created bool, err error) {
+	if _, err = run(lvm, "lvs", vg+"/"+name); err == nil {
+		return false, nil
+	}
+
+	args := []string{"lvcreate", "-y", "-n", name}
+
+	if size == 0 {
+		args = append(args, "-l", "100%FREE")
+	} else {
+		args = append(args, "-L", fmt.Sprintf("%db", size))
+	}
+
+	if _, err = run(lvm, append(args, vg)...); err != nil {
+		return false, fmt.Errorf("error creating logical volume %q: %w", vg+"/"+name, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,53 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package volumes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const mdadm = "/sbin/mdadm"
+
+// RAIDDevice returns the path to the md-RAID array device.
+func RAIDDevice(name string) string {
+	return filepath.Join("/dev/md", name)
+}
+
+// EnsureRAID assembles the md-RAID array.
+//
+// Array is created if none of the member devices has the md-RAID superblock.
+func EnsureRAID(name, level string, devices []string) (created bool, err error) {
+	device := RAIDDevice(name)
+
+	if _, err = os.Stat(device); err == nil {
+		return false, nil
+	}
+
+	for _, member := range devices {
+		if _, err = run(mdadm, "--examine", member); err == nil {
+			if _, err = run(mdadm, append([]string{"--assemble", device, "--run"}, devices...)...); err != nil {
+				return false, fmt.Errorf("error assembling RAID array %q: %w", name, err)
+			}
+
+			return false, nil
+		}
+	}
+
+	args := []string{
+		"--create", device,
+		"--run",
+		"--metadata=1.2",
+		"--level=" + level,
+		fmt.Sprintf("--raid-devices=%d", len(devices)),
+	}
+
+	if _, err = run(mdadm, append(args, devices...)...); err != nil {
+		return false, fmt.Errorf("error creating RAID array %q: %w", name, err)
+	}
+
+	return true, nil
+}
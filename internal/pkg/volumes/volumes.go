@@ -0,0 +1,125 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package volumes implements md-RAID arrays, LVM volume groups and logical volumes for the user disks.
+package volumes
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/talos-systems/go-blockdevice/blockdevice/probe"
+	"golang.org/x/sys/unix"
+
+	"github.com/talos-systems/talos/pkg/cmd"
+	"github.com/talos-systems/talos/pkg/makefs"
+)
+
+// run executes the commands, it is replaced in the tests.
+var run = cmd.Run
+
+// Filesystem types of the user volumes.
+const (
+	FilesystemXFS  = "xfs"
+	FilesystemExt4 = "ext4"
+	FilesystemNone = "none"
+)
+
+// Format creates the filesystem on the device.
+func Format(device, filesystem string) error {
+	switch filesystem {
+	case FilesystemXFS:
+		return makefs.XFS(device, makefs.WithForce(true))
+	case FilesystemExt4:
+		return makefs.Ext4(device, makefs.WithForce(true))
+	case FilesystemNone:
+		return nil
+	default:
+		return fmt.Errorf("unsupported filesystem %q", filesystem)
+	}
+}
+
+// HasFilesystem checks whether the device contains a filesystem.
+func HasFilesystem(device string) (bool, error) {
+	sb, err := probe.FileSystem(device)
+	if err != nil {
+		return false, err
+	}
+
+	if sb != nil {
+		return true, nil
+	}
+
+	return isExt(device)
+}
+
+// isExt checks the ext2/3/4 superblock magic.
+func isExt(device string) (bool, error) {
+	f, err := os.Open(device)
+	if err != nil {
+		return false, err
+	}
+
+	// nolint: errcheck
+	defer f.Close()
+
+	magic := make([]byte, 2)
+
+	if _, err = f.ReadAt(magic, 1024+0x38); err != nil {
+		if err == io.EOF {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return binary.LittleEndian.Uint16(magic) == 0xef53, nil
+}
+
+// mountFlags maps the mount options to the mount flags.
+var mountFlags = map[string]uintptr{
+	"ro":         unix.MS_RDONLY,
+	"nodev":      unix.MS_NODEV,
+	"noexec":     unix.MS_NOEXEC,
+	"nosuid":     unix.MS_NOSUID,
+	"noatime":    unix.MS_NOATIME,
+	"nodiratime": unix.MS_NODIRATIME,
+	"relatime":   unix.MS_RELATIME,
+	"sync":       unix.MS_SYNCHRONOUS,
+}
+
+// ParseMountOptions splits the mount options into the mount flags and the filesystem specific data.
+//
+// noatime is used if no atime options are specified.
+func ParseMountOptions(options []string) (flags uintptr, data string) {
+	var (
+		extra []string
+		atime bool
+	)
+
+	for _, option := range options {
+		if flag, ok := mountFlags[option]; ok {
+			flags |= flag
+
+			continue
+		}
+
+		if option == "atime" || option == "strictatime" {
+			atime = true
+
+			continue
+		}
+
+		extra = append(extra, option)
+	}
+
+	if !atime && flags&unix.MS_RELATIME == 0 {
+		flags |= unix.MS_NOATIME
+	}
+
+	return flags, strings.Join(extra, ",")
+}
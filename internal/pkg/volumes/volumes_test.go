@@ -0,0 +1,138 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package volumes
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+// mockRun records the commands, commands with the prefixes from the failures fail.
+func mockRun(t *testing.T, failures ...string) *[]string {
+	var commands []string
+
+	oldRun := run
+
+	t.Cleanup(func() { run = oldRun })
+
+	run = func(name string, args ...string) (string, error) {
+		command := strings.Join(append([]string{name}, args...), " ")
+		commands = append(commands, command)
+
+		for _, failure := range failures {
+			if strings.HasPrefix(command, failure) {
+				return "", errors.New("failed")
+			}
+		}
+
+		return "", nil
+	}
+
+	return &commands
+}
+
+func TestEnsureRAID(t *testing.T) {
+	commands := mockRun(t, "/sbin/mdadm --examine")
+
+	created, err := EnsureRAID("data", "raid1", []string{"/dev/sdb", "/dev/sdc"})
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, []string{
+		"/sbin/mdadm --examine /dev/sdb",
+		"/sbin/mdadm --examine /dev/sdc",
+		"/sbin/mdadm --create /dev/md/data --run --metadata=1.2 --level=raid1 --raid-devices=2 /dev/sdb /dev/sdc",
+	}, *commands)
+
+	commands = mockRun(t, "/sbin/mdadm --examine /dev/sdb")
+
+	created, err = EnsureRAID("data", "raid1", []string{"/dev/sdb", "/dev/sdc"})
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, []string{
+		"/sbin/mdadm --examine /dev/sdb",
+		"/sbin/mdadm --examine /dev/sdc",
+		"/sbin/mdadm --assemble /dev/md/data --run /dev/sdb /dev/sdc",
+	}, *commands)
+}
+
+func TestEnsureVolumeGroup(t *testing.T) {
+	commands := mockRun(t, "/sbin/lvm vgs", "/sbin/lvm lvs")
+
+	require.NoError(t, EnsureVolumeGroup("local", []string{"/dev/md/data", "/dev/sdd"}))
+
+	created, err := EnsureLogicalVolume("local", "pv1", 1<<30)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	created, err = EnsureLogicalVolume("local", "pv2", 0)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	assert.Equal(t, []string{
+		"/sbin/lvm vgs local",
+		"/sbin/lvm pvcreate -y /dev/md/data /dev/sdd",
+		"/sbin/lvm vgcreate local /dev/md/data /dev/sdd",
+		"/sbin/lvm lvs local/pv1",
+		"/sbin/lvm lvcreate -y -n pv1 -L 1073741824b local",
+		"/sbin/lvm lvs local/pv2",
+		"/sbin/lvm lvcreate -y -n pv2 -l 100%FREE local",
+	}, *commands)
+
+	commands = mockRun(t)
+
+	require.NoError(t, EnsureVolumeGroup("local", []string{"/dev/md/data"}))
+
+	created, err = EnsureLogicalVolume("local", "pv1", 1<<30)
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	assert.Equal(t, []string{
+		"/sbin/lvm vgs local",
+		"/sbin/lvm vgchange -ay local",
+		"/sbin/lvm lvs local/pv1",
+	}, *commands)
+}
+
+func TestHasFilesystem(t *testing.T) {
+	f, err := ioutil.TempFile("", "talos")
+	require.NoError(t, err)
+
+	defer os.Remove(f.Name()) //nolint: errcheck
+
+	require.NoError(t, f.Truncate(1<<20))
+
+	ok, err := HasFilesystem(f.Name())
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = f.WriteAt([]byte{0x53, 0xef}, 1024+0x38)
+	require.NoError(t, err)
+
+	require.NoError(t, f.Close())
+
+	ok, err = HasFilesystem(f.Name())
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestParseMountOptions(t *testing.T) {
+	flags, data := ParseMountOptions(nil)
+	assert.EqualValues(t, unix.MS_NOATIME, flags)
+	assert.Empty(t, data)
+
+	flags, data = ParseMountOptions([]string{"nodev", "relatime", "discard", "nouuid"})
+	assert.EqualValues(t, unix.MS_NODEV|unix.MS_RELATIME, flags)
+	assert.Equal(t, "discard,nouuid", data)
+
+	flags, _ = ParseMountOptions([]string{"atime", "ro"})
+	assert.EqualValues(t, unix.MS_RDONLY, flags)
+}
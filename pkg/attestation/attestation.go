@@ -0,0 +1,300 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package attestation verifies TPM attestation evidence of Talos nodes.
+//
+// Talos measures the booted installation (kernel, initramfs, kernel command line and
+// machine configuration) into the TPM PCR. Evidence consists of the TPM quote of PCR values
+// signed by the attestation key (AK) and the log of measurements which allows to replay the PCR value.
+package attestation
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+)
+
+// Event types.
+const (
+	EventKernel    = "kernel"
+	EventInitramfs = "initramfs"
+	EventUKI       = "uki"
+	EventCmdline   = "cmdline"
+	EventConfig    = "config"
+)
+
+// Event is a single measurement extended into the PCR.
+type Event struct {
+	PCR         int    `json:"pcr"`
+	Type        string `json:"type"`
+	Digest      []byte `json:"digest"`
+	Description string `json:"description,omitempty"`
+}
+
+// Evidence is the attestation evidence of the node.
+type Evidence struct {
+	// Quote is the TPMS_ATTEST structure.
+	Quote []byte `json:"quote"`
+	// Signature is ASN.1 DER encoded ECDSA signature of the quote.
+	Signature []byte `json:"signature"`
+	// AKPublic is PKIX DER encoded public attestation key.
+	AKPublic []byte `json:"akPublic"`
+	// PCRs are the quoted PCR values (SHA-256 bank).
+	PCRs map[int][]byte `json:"pcrs"`
+	// Events is the log of measurements.
+	Events []Event `json:"events"`
+}
+
+// Quote is the parsed quote.
+type Quote struct {
+	Nonce     []byte
+	PCRs      []int
+	PCRDigest []byte
+}
+
+const (
+	tpmGeneratedValue = 0xff544347
+	tpmSTAttestQuote  = 0x8018
+	algSHA256         = 0x000b
+)
+
+// Extend returns the PCR value extended with the digest.
+func Extend(value, digest []byte) []byte {
+	h := sha256.New()
+	h.Write(value)  //nolint: errcheck
+	h.Write(digest) //nolint: errcheck
+
+	return h.Sum(nil)
+}
+
+// Replay computes the PCR values from the event log.
+func Replay(events []Event) map[int][]byte {
+	values := map[int][]byte{}
+
+	for _, event := range events {
+		value, ok := values[event.PCR]
+		if !ok {
+			value = make([]byte, sha256.Size)
+		}
+
+		values[event.PCR] = Extend(value, event.Digest)
+	}
+
+	return values
+}
+
+// AKFingerprint returns the fingerprint of the attestation key.
+//
+// Attestation key is stable until the TPM is cleared, so the fingerprint is used to identify the node.
+func AKFingerprint(akPublic []byte) string {
+	sum := sha256.Sum256(akPublic)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseQuote parses the TPMS_ATTEST structure of the quote.
+//
+// nolint: gocyclo
+func ParseQuote(b []byte) (*Quote, error) {
+	r := bytes.NewReader(b)
+
+	var (
+		magic uint32
+		typ   uint16
+	)
+
+	if err := read(r, &magic, &typ); err != nil {
+		return nil, err
+	}
+
+	if magic != tpmGeneratedValue {
+		return nil, fmt.Errorf("quote is not generated by the TPM")
+	}
+
+	if typ != tpmSTAttestQuote {
+		return nil, fmt.Errorf("unexpected attestation type 0x%x", typ)
+	}
+
+	// qualifiedSigner
+	if _, err := readSized(r); err != nil {
+		return nil, err
+	}
+
+	quote := &Quote{}
+
+	var err error
+
+	if quote.Nonce, err = readSized(r); err != nil {
+		return nil, err
+	}
+
+	// clockInfo and firmwareVersion
+	var (
+		clock, firmwareVersion   uint64
+		resetCount, restartCount uint32
+		safe                     uint8
+	)
+
+	if err = read(r, &clock, &resetCount, &restartCount, &safe, &firmwareVersion); err != nil {
+		return nil, err
+	}
+
+	var count uint32
+
+	if err = read(r, &count); err != nil {
+		return nil, err
+	}
+
+	for i := uint32(0); i < count; i++ {
+		var (
+			hashAlg uint16
+			size    uint8
+		)
+
+		if err = read(r, &hashAlg, &size); err != nil {
+			return nil, err
+		}
+
+		bitmap := make([]byte, size)
+
+		if _, err = io.ReadFull(r, bitmap); err != nil {
+			return nil, err
+		}
+
+		if hashAlg != algSHA256 {
+			return nil, fmt.Errorf("unexpected PCR bank 0x%x", hashAlg)
+		}
+
+		for pcr := 0; pcr < int(size)*8; pcr++ {
+			if bitmap[pcr/8]&(1<<(pcr%8)) != 0 {
+				quote.PCRs = append(quote.PCRs, pcr)
+			}
+		}
+	}
+
+	if quote.PCRDigest, err = readSized(r); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// Verify the evidence against the nonce.
+//
+// Verify checks that the quote is signed by the attestation key, it's fresh (contains the nonce),
+// PCR values match the quote, and the event log replays to the quoted PCR values.
+// Verify doesn't establish trust in the attestation key itself, the caller should check that the key
+// belongs to the node (e.g. by comparing the key fingerprint with the one recorded at enrollment).
+//
+// nolint: gocyclo
+func Verify(evidence *Evidence, nonce []byte) error {
+	key, err := x509.ParsePKIXPublicKey(evidence.AKPublic)
+	if err != nil {
+		return fmt.Errorf("error parsing attestation key: %w", err)
+	}
+
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("unexpected attestation key type %T", key)
+	}
+
+	var sig struct {
+		R, S *big.Int
+	}
+
+	if _, err = asn1.Unmarshal(evidence.Signature, &sig); err != nil {
+		return fmt.Errorf("error parsing signature: %w", err)
+	}
+
+	hash := sha256.Sum256(evidence.Quote)
+
+	if !ecdsa.Verify(ecdsaKey, hash[:], sig.R, sig.S) {
+		return errors.New("quote signature is invalid")
+	}
+
+	quote, err := ParseQuote(evidence.Quote)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(quote.Nonce, nonce) {
+		return errors.New("quote nonce mismatch")
+	}
+
+	if len(quote.PCRs) != len(evidence.PCRs) {
+		return fmt.Errorf("quoted PCRs %v don't match PCR values", quote.PCRs)
+	}
+
+	h := sha256.New()
+
+	for _, pcr := range quote.PCRs {
+		value, ok := evidence.PCRs[pcr]
+		if !ok {
+			return fmt.Errorf("value of quoted PCR %d is missing", pcr)
+		}
+
+		h.Write(value) //nolint: errcheck
+	}
+
+	if !bytes.Equal(h.Sum(nil), quote.PCRDigest) {
+		return errors.New("PCR values don't match the quote")
+	}
+
+	replayed := Replay(evidence.Events)
+
+	pcrs := make([]int, 0, len(replayed))
+	for pcr := range replayed {
+		pcrs = append(pcrs, pcr)
+	}
+
+	sort.Ints(pcrs)
+
+	for _, pcr := range pcrs {
+		value, ok := evidence.PCRs[pcr]
+		if !ok {
+			return fmt.Errorf("PCR %d of the event log is not quoted", pcr)
+		}
+
+		if !bytes.Equal(value, replayed[pcr]) {
+			return fmt.Errorf("event log doesn't match the value of PCR %d", pcr)
+		}
+	}
+
+	return nil
+}
+
+func read(r io.Reader, values ...interface{}) error {
+	for _, v := range values {
+		if err := binary.Read(r, binary.BigEndian, v); err != nil {
+			return fmt.Errorf("error parsing quote: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func readSized(r io.Reader) ([]byte, error) {
+	var size uint16
+
+	if err := read(r, &size); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, size)
+
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, fmt.Errorf("error parsing quote: %w", err)
+	}
+
+	return b, nil
+}
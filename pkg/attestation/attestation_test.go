@@ -0,0 +1,158 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package attestation_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/talos-systems/talos/pkg/attestation"
+)
+
+type AttestationSuite struct {
+	suite.Suite
+
+	key    *ecdsa.PrivateKey
+	nonce  []byte
+	events []attestation.Event
+}
+
+func (suite *AttestationSuite) SetupTest() {
+	var err error
+
+	suite.key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+
+	suite.nonce = []byte("0123456789abcdef")
+
+	suite.events = nil
+
+	for _, typ := range []string{attestation.EventKernel, attestation.EventInitramfs, attestation.EventCmdline, attestation.EventConfig} {
+		digest := sha256.Sum256([]byte(typ))
+
+		suite.events = append(suite.events, attestation.Event{
+			PCR:    11,
+			Type:   typ,
+			Digest: digest[:],
+		})
+	}
+}
+
+// quote builds TPMS_ATTEST structure for the PCRs 0 and 11.
+func (suite *AttestationSuite) quote(nonce []byte, pcrs map[int][]byte) []byte {
+	var buf bytes.Buffer
+
+	write := func(values ...interface{}) {
+		for _, v := range values {
+			suite.Require().NoError(binary.Write(&buf, binary.BigEndian, v))
+		}
+	}
+
+	sized := func(b []byte) {
+		write(uint16(len(b)))
+		buf.Write(b)
+	}
+
+	write(uint32(0xff544347), uint16(0x8018))
+	sized([]byte("signer"))
+	sized(nonce)
+	write(uint64(1000), uint32(1), uint32(0), uint8(1), uint64(42))
+	write(uint32(1), uint16(0x000b), uint8(3), []byte{0x01, 0x08, 0x00})
+
+	h := sha256.New()
+	h.Write(pcrs[0])  //nolint: errcheck
+	h.Write(pcrs[11]) //nolint: errcheck
+	sized(h.Sum(nil))
+
+	return buf.Bytes()
+}
+
+func (suite *AttestationSuite) evidence() *attestation.Evidence {
+	pcrs := map[int][]byte{
+		0:  bytes.Repeat([]byte{0xaa}, sha256.Size),
+		11: attestation.Replay(suite.events)[11],
+	}
+
+	quote := suite.quote(suite.nonce, pcrs)
+	hash := sha256.Sum256(quote)
+
+	r, s, err := ecdsa.Sign(rand.Reader, suite.key, hash[:])
+	suite.Require().NoError(err)
+
+	signature, err := asn1.Marshal(struct{ R, S interface{} }{r, s})
+	suite.Require().NoError(err)
+
+	akPublic, err := x509.MarshalPKIXPublicKey(&suite.key.PublicKey)
+	suite.Require().NoError(err)
+
+	return &attestation.Evidence{
+		Quote:     quote,
+		Signature: signature,
+		AKPublic:  akPublic,
+		PCRs:      pcrs,
+		Events:    suite.events,
+	}
+}
+
+func (suite *AttestationSuite) TestParseQuote() {
+	quote, err := attestation.ParseQuote(suite.evidence().Quote)
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal(suite.nonce, quote.Nonce)
+	suite.Assert().Equal([]int{0, 11}, quote.PCRs)
+	suite.Assert().Len(quote.PCRDigest, sha256.Size)
+
+	_, err = attestation.ParseQuote([]byte("garbage"))
+	suite.Assert().Error(err)
+}
+
+func (suite *AttestationSuite) TestVerify() {
+	suite.Require().NoError(attestation.Verify(suite.evidence(), suite.nonce))
+}
+
+func (suite *AttestationSuite) TestVerifyNonce() {
+	suite.Assert().EqualError(attestation.Verify(suite.evidence(), []byte("stale")), "quote nonce mismatch")
+}
+
+func (suite *AttestationSuite) TestVerifySignature() {
+	evidence := suite.evidence()
+	evidence.Quote[len(evidence.Quote)-1] ^= 0xff
+
+	suite.Assert().EqualError(attestation.Verify(evidence, suite.nonce), "quote signature is invalid")
+}
+
+func (suite *AttestationSuite) TestVerifyPCRValues() {
+	evidence := suite.evidence()
+	evidence.PCRs[0] = bytes.Repeat([]byte{0xbb}, sha256.Size)
+
+	suite.Assert().EqualError(attestation.Verify(evidence, suite.nonce), "PCR values don't match the quote")
+}
+
+func (suite *AttestationSuite) TestVerifyEventLog() {
+	evidence := suite.evidence()
+	evidence.Events = evidence.Events[:len(evidence.Events)-1]
+
+	suite.Assert().EqualError(attestation.Verify(evidence, suite.nonce), "event log doesn't match the value of PCR 11")
+}
+
+func (suite *AttestationSuite) TestAKFingerprint() {
+	evidence := suite.evidence()
+
+	suite.Assert().Len(attestation.AKFingerprint(evidence.AKPublic), 64)
+	suite.Assert().Equal(attestation.AKFingerprint(evidence.AKPublic), attestation.AKFingerprint(suite.evidence().AKPublic))
+}
+
+func TestAttestationSuite(t *testing.T) {
+	suite.Run(t, new(AttestationSuite))
+}
@@ -43,7 +43,7 @@ type Source = *os.File
 // NewChunker initializes a Chunker with default values.
 func NewChunker(ctx context.Context, source Source, setters ...Option) chunker.Chunker {
 	opts := &Options{
-		Size: 1024,
+		Size: stream.DefaultSize,
 	}
 
 	for _, setter := range setters {
@@ -8,10 +8,31 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sync"
 
 	"github.com/talos-systems/talos/pkg/chunker"
 )
 
+// DefaultSize is the default chunk size.
+const DefaultSize = 1024
+
+// bufPool holds the buffers of the default size.
+var bufPool = sync.Pool{
+	New: func() interface{} {
+		return make([]byte, DefaultSize)
+	},
+}
+
+// Release returns the chunk buffer to the pool.
+//
+// Chunk should not be used after the call. Releasing chunks is optional,
+// but it reduces allocations for long-running streams.
+func Release(chunk []byte) {
+	if cap(chunk) == DefaultSize {
+		bufPool.Put(chunk[:DefaultSize]) //nolint: staticcheck
+	}
+}
+
 // Options is the functional options struct.
 type Options struct {
 	Size int
@@ -43,7 +64,7 @@ type Source interface {
 // NewChunker initializes a Chunker with default values.
 func NewChunker(ctx context.Context, source Source, setters ...Option) chunker.Chunker {
 	opts := &Options{
-		Size: 1024,
+		Size: DefaultSize,
 	}
 
 	for _, setter := range setters {
@@ -67,7 +88,13 @@ func (c *Stream) Read() <-chan []byte {
 		// nolint: errcheck
 		defer c.source.Close()
 
-		buf := make([]byte, c.options.Size)
+		var buf []byte
+
+		// buf is owned by the chunker until it's sent, so it can go back to the pool
+		// when the stream ends or the consumer goes away.
+		defer func() {
+			Release(buf)
+		}()
 
 		for {
 			select {
@@ -76,24 +103,26 @@ func (c *Stream) Read() <-chan []byte {
 			default:
 			}
 
+			// Get a new buffer for every chunk, as the consumer owns the chunk until it's released.
+			if buf == nil {
+				buf = c.getBuffer()
+			}
+
 			n, err := c.source.Read(buf)
 			if err != nil {
 				if err != io.EOF {
 					fmt.Printf("read error: %s\n", err.Error())
 				}
 
-				break
+				return
 			}
 
 			if n != 0 {
-				// Copy the buffer since we will modify it in the next loop.
-				b := make([]byte, n)
-				copy(b, buf[:n])
-
 				select {
 				case <-c.ctx.Done():
 					return
-				case ch <- b:
+				case ch <- buf[:n]:
+					buf = nil
 				}
 			}
 		}
@@ -101,3 +130,11 @@ func (c *Stream) Read() <-chan []byte {
 
 	return ch
 }
+
+func (c *Stream) getBuffer() []byte {
+	if c.options.Size == DefaultSize {
+		return bufPool.Get().([]byte)
+	}
+
+	return make([]byte, c.options.Size)
+}
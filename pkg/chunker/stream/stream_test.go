@@ -5,8 +5,10 @@
 package stream_test
 
 import (
+	"bytes"
 	"context"
 	"io"
+	"io/ioutil"
 	"testing"
 	"time"
 
@@ -130,6 +132,90 @@ func (suite *StreamChunkerSuite) TestStreamingCancel() {
 	suite.Require().Equal([]byte("abcdefghijklmno"), <-combinedCh)
 }
 
+type closeNotifier struct {
+	io.Reader
+
+	closed chan struct{}
+}
+
+func (c *closeNotifier) Close() error {
+	close(c.closed)
+
+	return nil
+}
+
+func (suite *StreamChunkerSuite) TestStreamingAbort() {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	source := &closeNotifier{
+		Reader: suite.reader,
+		closed: make(chan struct{}),
+	}
+
+	chunker := stream.NewChunker(ctx, source)
+
+	chunksCh := chunker.Read()
+
+	// nolint: errcheck
+	suite.writer.Write([]byte("abc"))
+
+	first := <-chunksCh
+	suite.Require().Equal([]byte("abc"), first)
+
+	// nolint: errcheck
+	suite.writer.Write([]byte("def"))
+
+	second := <-chunksCh
+	suite.Require().Equal([]byte("def"), second)
+
+	stream.Release(second)
+
+	// nolint: errcheck
+	suite.writer.Write([]byte("ghi"))
+
+	third := <-chunksCh
+	suite.Require().Equal([]byte("ghi"), third)
+
+	// chunk in the channel buffer
+	// nolint: errcheck
+	suite.writer.Write([]byte("jkl"))
+	// chunker is blocked sending the chunk
+	// nolint: errcheck
+	suite.writer.Write([]byte("mno"))
+
+	// consumer goes away without reading the rest of the stream
+	ctxCancel()
+
+	select {
+	case <-source.closed:
+	case <-time.After(time.Second):
+		suite.FailNow("source wasn't closed")
+	}
+
+	// chunks which weren't released are not reused
+	suite.Require().Equal([]byte("abc"), first)
+	suite.Require().Equal([]byte("ghi"), third)
+
+	stream.Release(first)
+	stream.Release(third)
+}
+
 func TestStreamChunkerSuite(t *testing.T) {
 	suite.Run(t, new(StreamChunkerSuite))
 }
+
+func BenchmarkStream(b *testing.B) {
+	data := bytes.Repeat([]byte("2021/01/01 00:00:00 machined: some log line\n"), 4096)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+
+	for i := 0; i < b.N; i++ {
+		chunker := stream.NewChunker(context.Background(), ioutil.NopCloser(bytes.NewReader(data)))
+
+		for chunk := range chunker.Read() {
+			stream.Release(chunk)
+		}
+	}
+}
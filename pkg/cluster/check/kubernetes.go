@@ -13,6 +13,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 
 	"github.com/talos-systems/talos/pkg/cluster"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
@@ -71,12 +72,15 @@ func K8sFullControlPlaneAssertion(ctx context.Context, cluster ClusterInfo) erro
 
 	var actualNodes []string
 
+	nodeAddresses := map[string]string{}
+
 	for _, node := range nodes.Items {
 		for label := range node.Labels {
 			if label == constants.LabelNodeRoleMaster {
 				for _, nodeAddress := range node.Status.Addresses {
 					if nodeAddress.Type == v1.NodeInternalIP {
 						actualNodes = append(actualNodes, nodeAddress.Address)
+						nodeAddresses[node.Name] = nodeAddress.Address
 
 						break
 					}
@@ -105,6 +109,10 @@ func K8sFullControlPlaneAssertion(ctx context.Context, cluster ClusterInfo) erro
 		return err
 	}
 
+	if len(daemonsets.Items) == 0 {
+		return k8sStaticPodsAssertion(ctx, clientset, expectedNodes, nodeAddresses)
+	}
+
 	for _, ds := range daemonsets.Items {
 		if ds.Status.CurrentNumberScheduled != ds.Status.DesiredNumberScheduled {
 			return fmt.Errorf("expected current number scheduled for %s to be %d, got %d", ds.GetName(), ds.Status.DesiredNumberScheduled, ds.Status.CurrentNumberScheduled)
@@ -164,6 +172,51 @@ func K8sFullControlPlaneAssertion(ctx context.Context, cluster ClusterInfo) erro
 	return nil
 }
 
+// k8sStaticPodsAssertion checks whether the control plane static pods are running and ready on all the master nodes.
+func k8sStaticPodsAssertion(ctx context.Context, clientset *kubernetes.Clientset, expectedNodes []string, nodeAddresses map[string]string) error {
+	for _, component := range []string{"kube-apiserver", "kube-scheduler", "kube-controller-manager"} {
+		pods, err := clientset.CoreV1().Pods("kube-system").List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("k8s-app = %s", component),
+		})
+		if err != nil {
+			return fmt.Errorf("error listing pods for %s: %w", component, err)
+		}
+
+		var (
+			actualNodes  []string
+			notReadyPods []string
+		)
+
+		for _, pod := range pods.Items {
+			if address, ok := nodeAddresses[pod.Spec.NodeName]; ok {
+				actualNodes = append(actualNodes, address)
+			}
+
+			for _, cond := range pod.Status.Conditions {
+				if cond.Type == v1.PodReady {
+					if cond.Status != v1.ConditionTrue {
+						notReadyPods = append(notReadyPods, pod.Name)
+
+						break
+					}
+				}
+			}
+		}
+
+		sort.Strings(actualNodes)
+
+		if !reflect.DeepEqual(expectedNodes, actualNodes) {
+			return fmt.Errorf("expected %s pods on %v nodes, but got %v nodes", component, expectedNodes, actualNodes)
+		}
+
+		if len(notReadyPods) > 0 {
+			return fmt.Errorf("some pods are not ready for %s: %v", component, notReadyPods)
+		}
+	}
+
+	return nil
+}
+
 // K8sAllNodesReadyAssertion checks whether all the nodes are Ready.
 func K8sAllNodesReadyAssertion(ctx context.Context, cluster cluster.K8sProvider) error {
 	clientset, err := cluster.K8sClient(ctx)
@@ -270,3 +323,37 @@ func K8sPodReadyAssertion(ctx context.Context, cluster cluster.K8sProvider, name
 
 	return fmt.Errorf("some pods are not ready: %v", notReadyPods)
 }
+
+// DaemonSetPresent returns true if there is at least one DaemonSet matching given label selector.
+func DaemonSetPresent(ctx context.Context, cluster cluster.K8sProvider, namespace, labelSelector string) (bool, error) {
+	clientset, err := cluster.K8sClient(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	dss, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return len(dss.Items) > 0, nil
+}
+
+// DeploymentPresent returns true if there is at least one Deployment matching given label selector.
+func DeploymentPresent(ctx context.Context, cluster cluster.K8sProvider, namespace, labelSelector string) (bool, error) {
+	clientset, err := cluster.K8sClient(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return len(deployments.Items) > 0, nil
+}
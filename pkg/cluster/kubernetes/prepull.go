@@ -0,0 +1,83 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	criconstants "github.com/containerd/cri/pkg/constants"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/talos-systems/talos/pkg/machinery/client"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// prePullImages pulls the images of the target version on every node before any component is updated,
+// so that the upgrade is aborted early if the images can't be pulled.
+//
+// Control plane images are pulled on the control plane nodes, kube-proxy image (if it's in the list of daemonsets)
+// is pulled on all the nodes.
+//
+//nolint: gocyclo
+func prePullImages(ctx context.Context, cluster UpgradeProvider, clientset *kubernetes.Clientset, daemonsets []string, options UpgradeOptions) error {
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error listing nodes: %w", err)
+	}
+
+	c, err := cluster.Client()
+	if err != nil {
+		return fmt.Errorf("error building Talos API client: %w", err)
+	}
+
+	for _, node := range nodes.Items {
+		addr := nodeInternalIP(&node)
+		if addr == "" {
+			return fmt.Errorf("node %q has no internal IP", node.Name)
+		}
+
+		_, controlPlane := node.Labels[constants.LabelNodeRoleMaster]
+
+		for _, component := range daemonsets {
+			if component != kubeProxy && !controlPlane {
+				continue
+			}
+
+			image, err := componentImage(component, options)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("pulling %q on node %q\n", image, node.Name)
+
+			if _, err = c.ImagePull(client.WithNodes(ctx, addr), criconstants.K8sContainerdNamespace, image); err != nil {
+				if status.Code(err) == codes.Unimplemented {
+					fmt.Printf("skipping images pre-pull, as node %q doesn't support it\n", node.Name)
+
+					return nil
+				}
+
+				return fmt.Errorf("error pulling %q on node %q: %w", image, node.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func nodeInternalIP(node *corev1.Node) string {
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			return address.Address
+		}
+	}
+
+	return ""
+}
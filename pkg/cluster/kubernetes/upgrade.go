@@ -49,10 +49,20 @@ type UpgradeOptions struct {
 
 type daemonsetUpdater func(ds string, daemonset *appsv1.DaemonSet) error
 
+// UpgradeProvider are the cluster interfaces required by the upgrade.
+type UpgradeProvider interface {
+	cluster.ClientProvider
+	cluster.K8sProvider
+}
+
 // Upgrade the Kubernetes control plane.
 //
 //nolint: gocyclo
-func Upgrade(ctx context.Context, cluster cluster.K8sProvider, options UpgradeOptions) error {
+func Upgrade(ctx context.Context, cluster UpgradeProvider, options UpgradeOptions) error {
+	if err := checkSelfHostedControlPlane(ctx, cluster); err != nil {
+		return err
+	}
+
 	switch {
 	case strings.HasPrefix(options.FromVersion, "1.18.") && strings.HasPrefix(options.ToVersion, "1.19."):
 		return hyperkubeUpgrade(ctx, cluster, options)
@@ -82,12 +92,21 @@ func Upgrade(ctx context.Context, cluster cluster.K8sProvider, options UpgradeOp
 }
 
 // hyperkubeUpgrade upgrades from hyperkube-based to distroless images in 1.19.
-func hyperkubeUpgrade(ctx context.Context, cluster cluster.K8sProvider, options UpgradeOptions) error {
+func hyperkubeUpgrade(ctx context.Context, cluster UpgradeProvider, options UpgradeOptions) error {
 	clientset, err := cluster.K8sClient(ctx)
 	if err != nil {
 		return fmt.Errorf("error building K8s client: %w", err)
 	}
 
+	daemonsets, err := upgradeDaemonsets(ctx, clientset)
+	if err != nil {
+		return err
+	}
+
+	if err = prePullImages(ctx, cluster, clientset, daemonsets, options); err != nil {
+		return err
+	}
+
 	if err = podCheckpointerGracePeriod(ctx, clientset, "0m"); err != nil {
 		return fmt.Errorf("error setting pod-checkpointer grace period: %w", err)
 	}
@@ -97,8 +116,6 @@ func hyperkubeUpgrade(ctx context.Context, cluster cluster.K8sProvider, options
 	fmt.Printf("sleeping %s to let the pod-checkpointer self-checkpoint be updated\n", graceTimeout.String())
 	time.Sleep(graceTimeout)
 
-	daemonsets := []string{kubeAPIServer, kubeControllerManager, kubeScheduler, kubeProxy}
-
 	for _, ds := range daemonsets {
 		if err = hyperkubeUpgradeDs(ctx, clientset, ds, options); err != nil {
 			return fmt.Errorf("failed updating daemonset %q: %w", ds, err)
@@ -112,6 +129,48 @@ func hyperkubeUpgrade(ctx context.Context, cluster cluster.K8sProvider, options
 	return nil
 }
 
+// checkSelfHostedControlPlane verifies that the control plane is self-hosted, as the upgrade updates the control plane daemonsets.
+//
+// Control plane running as static pods is upgraded by updating the images in the machine configuration.
+func checkSelfHostedControlPlane(ctx context.Context, cluster UpgradeProvider) error {
+	clientset, err := cluster.K8sClient(ctx)
+	if err != nil {
+		return fmt.Errorf("error building K8s client: %w", err)
+	}
+
+	_, err = clientset.AppsV1().DaemonSets(namespace).Get(ctx, kubeAPIServer, metav1.GetOptions{})
+
+	switch {
+	case err == nil:
+		return nil
+	case apierrors.IsNotFound(err):
+		return fmt.Errorf("daemonset %q is not found: control plane is running as static pods, "+
+			"update the images in the machine configuration of the control plane nodes instead", kubeAPIServer)
+	default:
+		return fmt.Errorf("error fetching daemonset %q: %w", kubeAPIServer, err)
+	}
+}
+
+// upgradeDaemonsets returns the list of daemonsets to upgrade.
+//
+// kube-proxy might be disabled in the cluster config, so it's skipped if it's not deployed.
+func upgradeDaemonsets(ctx context.Context, clientset *kubernetes.Clientset) ([]string, error) {
+	daemonsets := []string{kubeAPIServer, kubeControllerManager, kubeScheduler}
+
+	_, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, kubeProxy, metav1.GetOptions{})
+
+	switch {
+	case err == nil:
+		daemonsets = append(daemonsets, kubeProxy)
+	case apierrors.IsNotFound(err):
+		fmt.Printf("skipping %q, as daemonset is not deployed\n", kubeProxy)
+	default:
+		return nil, fmt.Errorf("error fetching daemonset %q: %w", kubeProxy, err)
+	}
+
+	return daemonsets, nil
+}
+
 //nolint: gocyclo
 func updateDaemonset(ctx context.Context, clientset *kubernetes.Clientset, ds string, updateFunc func(daemonset *appsv1.DaemonSet) error) error {
 	daemonset, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, ds, metav1.GetOptions{})
@@ -234,19 +293,13 @@ func hyperkubeUpgradeDs(ctx context.Context, clientset *kubernetes.Clientset, ds
 			}
 		}
 
-		switch ds {
-		case kubeAPIServer:
-			daemonset.Spec.Template.Spec.Containers[0].Image = fmt.Sprintf("%s-%s:v%s", constants.KubernetesAPIServerImage, options.Architecture, options.ToVersion)
-		case kubeControllerManager:
-			daemonset.Spec.Template.Spec.Containers[0].Image = fmt.Sprintf("%s-%s:v%s", constants.KubernetesControllerManagerImage, options.Architecture, options.ToVersion)
-		case kubeScheduler:
-			daemonset.Spec.Template.Spec.Containers[0].Image = fmt.Sprintf("%s-%s:v%s", constants.KubernetesSchedulerImage, options.Architecture, options.ToVersion)
-		case kubeProxy:
-			daemonset.Spec.Template.Spec.Containers[0].Image = fmt.Sprintf("%s-%s:v%s", constants.KubernetesProxyImage, options.Architecture, options.ToVersion)
-		default:
-			return fmt.Errorf("failed to build new image spec")
+		image, err := componentImage(ds, options)
+		if err != nil {
+			return err
 		}
 
+		daemonset.Spec.Template.Spec.Containers[0].Image = image
+
 		if ds == kubeAPIServer {
 			if daemonset.Spec.Template.Annotations == nil {
 				daemonset.Spec.Template.Annotations = make(map[string]string)
@@ -265,6 +318,22 @@ func hyperkubeUpgradeDs(ctx context.Context, clientset *kubernetes.Clientset, ds
 	})
 }
 
+// componentImage returns the image of the control plane component for the target version.
+func componentImage(ds string, options UpgradeOptions) (string, error) {
+	switch ds {
+	case kubeAPIServer:
+		return fmt.Sprintf("%s-%s:v%s", constants.KubernetesAPIServerImage, options.Architecture, options.ToVersion), nil
+	case kubeControllerManager:
+		return fmt.Sprintf("%s-%s:v%s", constants.KubernetesControllerManagerImage, options.Architecture, options.ToVersion), nil
+	case kubeScheduler:
+		return fmt.Sprintf("%s-%s:v%s", constants.KubernetesSchedulerImage, options.Architecture, options.ToVersion), nil
+	case kubeProxy:
+		return fmt.Sprintf("%s-%s:v%s", constants.KubernetesProxyImage, options.Architecture, options.ToVersion), nil
+	default:
+		return "", fmt.Errorf("failed to build new image spec")
+	}
+}
+
 func serviceAccountSecretsUpdate(ctx context.Context, cluster cluster.K8sProvider) error {
 	const serviceAccountKey = "service-account.key"
 
@@ -6,6 +6,7 @@ package download
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
@@ -19,8 +20,10 @@ import (
 const b64 = "base64"
 
 type downloadOptions struct {
-	Headers map[string]string
-	Format  string
+	Headers   map[string]string
+	Format    string
+	TLSConfig *tls.Config
+	Timeout   time.Duration
 
 	ErrorOnNotFound      error
 	ErrorOnEmptyResponse error
@@ -32,6 +35,7 @@ type Option func(*downloadOptions)
 func downloadDefaults() *downloadOptions {
 	return &downloadOptions{
 		Headers: make(map[string]string),
+		Timeout: 180 * time.Second,
 	}
 }
 
@@ -57,6 +61,20 @@ func WithHeaders(headers map[string]string) Option {
 	}
 }
 
+// WithTLSConfig specifies the TLS configuration (e.g. client certificate or CA) to use for HTTPS downloads.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(d *downloadOptions) {
+		d.TLSConfig = cfg
+	}
+}
+
+// WithTimeout specifies the total time to retry the download for.
+func WithTimeout(timeout time.Duration) Option {
+	return func(d *downloadOptions) {
+		d.Timeout = timeout
+	}
+}
+
 // WithErrorOnNotFound provides specific error to return when response has HTTP 404 error.
 func WithErrorOnNotFound(e error) Option {
 	return func(d *downloadOptions) {
@@ -99,14 +117,23 @@ func Download(ctx context.Context, endpoint string, opts ...Option) (b []byte, e
 		req.Header.Set(k, v)
 	}
 
-	err = retry.Exponential(180*time.Second, retry.WithUnits(time.Second), retry.WithJitter(time.Second), retry.WithErrorLogging(true)).Retry(func() error {
+	client := &http.Client{}
+
+	if dlOpts.TLSConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = dlOpts.TLSConfig
+
+		client.Transport = transport
+	}
+
+	err = retry.Exponential(dlOpts.Timeout, retry.WithUnits(time.Second), retry.WithJitter(time.Second), retry.WithErrorLogging(true)).Retry(func() error {
 		select {
 		case <-ctx.Done():
 			return retry.UnexpectedError(context.Canceled)
 		default:
 		}
 
-		b, err = download(req, dlOpts)
+		b, err = download(client, req, dlOpts)
 
 		return err
 	})
@@ -128,9 +155,7 @@ func Download(ctx context.Context, endpoint string, opts ...Option) (b []byte, e
 	return b, nil
 }
 
-func download(req *http.Request, dlOpts *downloadOptions) (data []byte, err error) {
-	client := &http.Client{}
-
+func download(client *http.Client, req *http.Request, dlOpts *downloadOptions) (data []byte, err error) {
 	resp, err := client.Do(req)
 	if err != nil {
 		return data, retry.ExpectedError(err)
@@ -143,7 +168,7 @@ func download(req *http.Request, dlOpts *downloadOptions) (data []byte, err erro
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return data, retry.ExpectedError(fmt.Errorf("failed to download config, received %d", resp.StatusCode))
+		return data, retry.ExpectedError(fmt.Errorf("failed to download config, received %d %s", resp.StatusCode, http.StatusText(resp.StatusCode)))
 	}
 
 	data, err = ioutil.ReadAll(resp.Body)
@@ -32,6 +32,8 @@ type Registrator interface {
 type Options struct {
 	Port               int
 	SocketPath         string
+	SocketMode         os.FileMode
+	SocketGID          int
 	Network            string
 	Config             *tls.Config
 	LogPrefix          string
@@ -58,6 +60,14 @@ func SocketPath(o string) Option {
 	}
 }
 
+// SocketPermissions sets the file mode and the owning group of the listen unix file socket.
+func SocketPermissions(mode os.FileMode, gid int) Option {
+	return func(args *Options) {
+		args.SocketMode = mode
+		args.SocketGID = gid
+	}
+}
+
 // Network sets the network type of the listener.
 func Network(o string) Option {
 	return func(args *Options) {
@@ -187,7 +197,24 @@ func NewListener(setters ...Option) (net.Listener, error) {
 		return nil, fmt.Errorf("unknown network: %s", opts.Network)
 	}
 
-	return net.Listen(opts.Network, address)
+	listener, err := net.Listen(opts.Network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Network == "unix" && opts.SocketMode != 0 {
+		if err = os.Chown(address, 0, opts.SocketGID); err == nil {
+			err = os.Chmod(address, opts.SocketMode)
+		}
+
+		if err != nil {
+			listener.Close() //nolint: errcheck
+
+			return nil, fmt.Errorf("error setting permissions of the file socket: %w", err)
+		}
+	}
+
+	return listener, nil
 }
 
 // ListenAndServe configures TLS for mutual authtentication by loading the CA into a
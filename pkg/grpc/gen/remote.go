@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math/rand"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -16,21 +17,69 @@ import (
 
 	"github.com/talos-systems/talos/pkg/grpc/middleware/auth/basic"
 	securityapi "github.com/talos-systems/talos/pkg/machinery/api/security"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
 // RemoteGenerator represents the OS identity generator.
 type RemoteGenerator struct {
-	client securityapi.SecurityServiceClient
-	conn   *grpc.ClientConn
-	done   chan struct{}
+	client  securityapi.SecurityServiceClient
+	conn    *grpc.ClientConn
+	done    chan struct{}
+	options *RemoteGeneratorOptions
+}
+
+// RemoteGeneratorOptions configures certificate request retries.
+type RemoteGeneratorOptions struct {
+	RetryInterval    time.Duration
+	MaxRetryInterval time.Duration
+	RetryJitter      time.Duration
+	RetryTimeout     time.Duration
+}
+
+// RemoteGeneratorOption is the functional option func.
+type RemoteGeneratorOption func(*RemoteGeneratorOptions)
+
+// WithRetryInterval sets the initial and the maximum interval between certificate request attempts.
+//
+// Interval is doubled after each failed attempt until it reaches the maximum.
+func WithRetryInterval(initial, max time.Duration) RemoteGeneratorOption {
+	return func(o *RemoteGeneratorOptions) {
+		o.RetryInterval = initial
+		o.MaxRetryInterval = max
+	}
+}
+
+// WithRetryJitter sets the maximum random delay added to each certificate request attempt.
+func WithRetryJitter(jitter time.Duration) RemoteGeneratorOption {
+	return func(o *RemoteGeneratorOptions) {
+		o.RetryJitter = jitter
+	}
+}
+
+// WithRetryTimeout sets the timeout to get the certificate.
+func WithRetryTimeout(timeout time.Duration) RemoteGeneratorOption {
+	return func(o *RemoteGeneratorOptions) {
+		o.RetryTimeout = timeout
+	}
 }
 
 // NewRemoteGenerator initializes a RemoteGenerator with a preconfigured grpc.ClientConn.
-func NewRemoteGenerator(token string, endpoints []string, port int) (g *RemoteGenerator, err error) {
+func NewRemoteGenerator(token string, endpoints []string, port int, setters ...RemoteGeneratorOption) (g *RemoteGenerator, err error) {
 	if len(endpoints) == 0 {
 		return nil, fmt.Errorf("at least one root of trust endpoint is required")
 	}
 
+	options := &RemoteGeneratorOptions{
+		RetryInterval:    constants.TrustdDefaultRetryInterval,
+		MaxRetryInterval: constants.TrustdDefaultMaxRetryInterval,
+		RetryJitter:      constants.TrustdDefaultRetryJitter,
+		RetryTimeout:     constants.TrustdDefaultRetryTimeout,
+	}
+
+	for _, setter := range setters {
+		setter(options)
+	}
+
 	creds := basic.NewTokenCredentials(token)
 
 	// Loop through trustd endpoints and attempt to download PKI
@@ -50,9 +99,10 @@ func NewRemoteGenerator(token string, endpoints []string, port int) (g *RemoteGe
 		client := securityapi.NewSecurityServiceClient(conn)
 
 		g := &RemoteGenerator{
-			client: client,
-			conn:   conn,
-			done:   make(chan struct{}),
+			client:  client,
+			conn:    conn,
+			done:    make(chan struct{}),
+			options: options,
 		}
 
 		return g, nil
@@ -96,11 +146,17 @@ func (g *RemoteGenerator) Close() error {
 	return g.conn.Close()
 }
 
+// poll requests the certificate with exponential backoff.
+//
+// Each attempt (including the first one) is delayed by the random jitter, so that
+// machines booting simultaneously don't hit trustd at the same time.
 func (g *RemoteGenerator) poll(in *securityapi.CertificateRequest) (ca, crt []byte, err error) {
-	timeout := time.NewTimer(time.Minute * 5)
+	timeout := time.NewTimer(g.options.RetryTimeout)
 	defer timeout.Stop()
 
-	tick := time.NewTicker(time.Second * 5)
+	interval := time.Duration(0)
+
+	tick := time.NewTimer(g.jitter())
 	defer tick.Stop()
 
 	for {
@@ -112,7 +168,11 @@ func (g *RemoteGenerator) poll(in *securityapi.CertificateRequest) (ca, crt []by
 
 			resp, err = g.Certificate(in)
 			if err != nil {
-				log.Println(err)
+				interval = g.nextInterval(interval)
+
+				log.Printf("certificate request failed, retrying in %s: %s", interval, err)
+
+				tick.Reset(interval + g.jitter())
 
 				continue
 			}
@@ -126,3 +186,25 @@ func (g *RemoteGenerator) poll(in *securityapi.CertificateRequest) (ca, crt []by
 		}
 	}
 }
+
+func (g *RemoteGenerator) nextInterval(interval time.Duration) time.Duration {
+	if interval == 0 {
+		return g.options.RetryInterval
+	}
+
+	interval *= 2
+
+	if interval > g.options.MaxRetryInterval {
+		interval = g.options.MaxRetryInterval
+	}
+
+	return interval
+}
+
+func (g *RemoteGenerator) jitter() time.Duration {
+	if g.options.RetryJitter <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(g.options.RetryJitter)))
+}
@@ -0,0 +1,489 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package authz provides grpc role-based authorization middleware.
+package authz
+
+import (
+	"context"
+	"crypto/x509"
+	"log"
+	"net"
+	"strings"
+
+	talosx509 "github.com/talos-systems/crypto/x509"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// Rule allows the set of methods on the set of nodes for the set of roles.
+//
+// Empty list of nodes allows the methods on all nodes.
+type Rule struct {
+	Roles   []string
+	Methods []string
+	Nodes   []string
+}
+
+// Policy restricts the set of methods to the set of client subjects and roles.
+//
+// Empty lists of subjects and roles disable the methods for all clients.
+type Policy struct {
+	Methods  []string
+	Subjects []string
+	Roles    []string
+}
+
+// Authorizer checks API calls against the policies and the list of rules.
+//
+// Methods matched by the policies are only allowed for the clients permitted by the matching policies.
+//
+// Clients are identified by the client certificate subject organizations (roles).
+// If none of the client roles is mentioned in the rules, the client is not restricted.
+//
+// Interceptors pass the client roles and identity to the backends in the metadata.
+type Authorizer struct {
+	rules      []Rule
+	policies   []Policy
+	localNodes func() ([]string, error)
+	logger     *log.Logger
+	local      bool
+}
+
+// NewAuthorizer creates new Authorizer.
+//
+// Function localNodes should return addresses of the node the server is running on,
+// it is used to authorize calls which are not proxied to other nodes: the call is allowed
+// if any of the addresses is allowed by the rules.
+func NewAuthorizer(rules []Rule, policies []Policy, localNodes func() ([]string, error), logger *log.Logger) *Authorizer {
+	return &Authorizer{
+		rules:      rules,
+		policies:   policies,
+		localNodes: localNodes,
+		logger:     logger,
+	}
+}
+
+// NewLocalAuthorizer creates new Authorizer for the local API socket.
+//
+// Clients of the local API socket have no certificates, they all get LocalIdentity,
+// and they are only allowed to call the methods on the local node.
+// Destructive methods are denied unless the rules for the local role allow them.
+func NewLocalAuthorizer(rules []Rule, policies []Policy, localNodes func() ([]string, error), logger *log.Logger) *Authorizer {
+	authorizer := NewAuthorizer(rules, policies, localNodes, logger)
+	authorizer.local = true
+
+	return authorizer
+}
+
+// LocalIdentity is the identity of the clients connected to the local API socket.
+var LocalIdentity = Identity{
+	Subject: constants.LocalAPISubject,
+	Roles:   []string{constants.RoleLocal},
+}
+
+// destructiveMethods change the configuration or the state of the node, or disrupt the workloads.
+//
+// Clients of the local API socket are only allowed to call them if a rule explicitly allows them.
+var destructiveMethods = []string{
+	"/machine.MachineService/ApplyConfiguration",
+	"/machine.MachineService/Bootstrap",
+	"/machine.MachineService/ConfigRollback",
+	"/machine.MachineService/EtcdForfeitLeadership",
+	"/machine.MachineService/EtcdLeaveCluster",
+	"/machine.MachineService/Grow",
+	"/machine.MachineService/ImagePull",
+	"/machine.MachineService/ImageRemove",
+	"/machine.MachineService/MetaDelete",
+	"/machine.MachineService/MetaWrite",
+	"/machine.MachineService/Reboot",
+	"/machine.MachineService/Recover",
+	"/machine.MachineService/Reset",
+	"/machine.MachineService/ResetStream",
+	"/machine.MachineService/Restart",
+	"/machine.MachineService/Rollback",
+	"/machine.MachineService/SequencePause",
+	"/machine.MachineService/SequenceResume",
+	"/machine.MachineService/ServiceReload",
+	"/machine.MachineService/ServiceRestart",
+	"/machine.MachineService/ServiceStart",
+	"/machine.MachineService/ServiceStop",
+	"/machine.MachineService/Shutdown",
+	"/machine.MachineService/Upgrade",
+}
+
+// UnaryInterceptor returns grpc UnaryServerInterceptor.
+func (a *Authorizer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = withIdentity(ctx, a.identity(ctx))
+
+		if err := a.Authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor returns grpc StreamServerInterceptor.
+func (a *Authorizer) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		stream = &serverStream{
+			ServerStream: stream,
+			ctx:          withIdentity(stream.Context(), a.identity(stream.Context())),
+		}
+
+		if err := a.Authorize(stream.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, stream)
+	}
+}
+
+// serverStream overrides the context of the stream.
+type serverStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// Authorize checks whether the client is allowed to call the method on the target nodes.
+func (a *Authorizer) Authorize(ctx context.Context, fullMethod string) error {
+	identity := a.identity(ctx)
+
+	if err := a.authorizePolicy(identity, fullMethod); err != nil {
+		return err
+	}
+
+	if a.local {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		if _, ok := md["nodes"]; ok {
+			return status.Error(codes.PermissionDenied, "local API socket only serves the local node")
+		}
+	}
+
+	roles := identity.Roles
+
+	var rules []Rule
+
+	for _, rule := range a.rules {
+		if intersects(rule.Roles, roles) {
+			rules = append(rules, rule)
+		}
+	}
+
+	if len(rules) == 0 {
+		if a.local && matchMethod(destructiveMethods, fullMethod) {
+			a.logger.Printf("authz: denied [%s] for roles %v on the local API socket", fullMethod, roles)
+
+			return status.Errorf(codes.PermissionDenied, "%s is not allowed on the local API socket unless the rules allow it for roles %v", fullMethod, roles)
+		}
+
+		// client is not restricted
+		return nil
+	}
+
+	targets, err := a.targets(ctx)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	for _, target := range targets {
+		if !allowed(rules, fullMethod, target) {
+			a.logger.Printf("authz: denied [%s] for roles %v on node %v", fullMethod, roles, target)
+
+			return status.Errorf(codes.PermissionDenied, "roles %v are not allowed to call %s on node %v", roles, fullMethod, target)
+		}
+	}
+
+	a.logger.Printf("authz: allowed [%s] for roles %v on nodes %v", fullMethod, roles, targets)
+
+	return nil
+}
+
+// authorizePolicy checks whether the client is allowed to call the method by the policies.
+func (a *Authorizer) authorizePolicy(identity Identity, fullMethod string) error {
+	matched := false
+
+	for _, policy := range a.policies {
+		if !matchMethod(policy.Methods, fullMethod) {
+			continue
+		}
+
+		matched = true
+
+		if intersects(policy.Subjects, []string{identity.Subject}) || intersects(policy.Roles, identity.Roles) {
+			return nil
+		}
+	}
+
+	if !matched {
+		return nil
+	}
+
+	a.logger.Printf("authz: denied [%s] by policy for subject %q with roles %v", fullMethod, identity.Subject, identity.Roles)
+
+	return status.Errorf(codes.PermissionDenied, "%s is not allowed by the API authorization policy", fullMethod)
+}
+
+// identity returns the identity of the client which made the request.
+func (a *Authorizer) identity(ctx context.Context) Identity {
+	if a.local {
+		return LocalIdentity
+	}
+
+	return ClientIdentity(ctx)
+}
+
+// Roles returns the list of roles of the client certificate.
+func Roles(ctx context.Context) []string {
+	cert := peerCertificate(ctx)
+	if cert == nil {
+		return nil
+	}
+
+	return cert.Subject.Organization
+}
+
+// Identity identifies the client which made the request.
+type Identity struct {
+	// Subject is the common name of the client certificate.
+	Subject string
+	// Fingerprint is the SPKI fingerprint of the client certificate.
+	Fingerprint string
+	// Roles are the roles of the client.
+	Roles []string
+}
+
+// ClientIdentity returns the identity of the client which made the request.
+//
+// Requests proxied by apid of other nodes carry the identity of the client in the metadata, see ClientRoles.
+func ClientIdentity(ctx context.Context) Identity {
+	cert := peerCertificate(ctx)
+	if cert == nil {
+		return Identity{}
+	}
+
+	if intersects(cert.Subject.Organization, []string{constants.RoleImpersonator}) {
+		return IdentityFromMetadata(ctx)
+	}
+
+	return Identity{
+		Subject:     cert.Subject.CommonName,
+		Fingerprint: talosx509.SPKIFingerprint(cert).String(),
+		Roles:       cert.Subject.Organization,
+	}
+}
+
+// IdentityFromMetadata returns the identity of the client passed by apid in the metadata.
+//
+// Only the services behind apid should trust the identity from the metadata.
+func IdentityFromMetadata(ctx context.Context) Identity {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	return Identity{
+		Subject:     first(md.Get(constants.APIAuthzSubjectMetadataKey)),
+		Fingerprint: first(md.Get(constants.APIAuthzFingerprintMetadataKey)),
+		Roles:       md.Get(constants.APIAuthzRoleMetadataKey),
+	}
+}
+
+func peerCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil
+	}
+
+	switch {
+	case len(tlsInfo.State.VerifiedChains) > 0 && len(tlsInfo.State.VerifiedChains[0]) > 0:
+		return tlsInfo.State.VerifiedChains[0][0]
+	case len(tlsInfo.State.PeerCertificates) > 0:
+		return tlsInfo.State.PeerCertificates[0]
+	default:
+		return nil
+	}
+}
+
+// ClientRoles returns the list of roles of the client which made the request.
+//
+// Requests proxied by apid of other nodes (certificates with the impersonator role) carry the roles
+// of the client in the metadata.
+func ClientRoles(ctx context.Context) []string {
+	roles := Roles(ctx)
+
+	if !intersects(roles, []string{constants.RoleImpersonator}) {
+		return roles
+	}
+
+	return RolesFromMetadata(ctx)
+}
+
+// RolesFromMetadata returns the list of roles of the client passed by apid in the metadata.
+//
+// Only the services behind apid should trust the roles from the metadata.
+func RolesFromMetadata(ctx context.Context) []string {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	return md.Get(constants.APIAuthzRoleMetadataKey)
+}
+
+// IsAdmin checks whether the roles grant access to the secrets.
+//
+// Clients without roles (certificates issued before the roles were introduced) are not restricted.
+func IsAdmin(roles []string) bool {
+	return len(roles) == 0 || intersects(roles, []string{constants.RoleAdmin})
+}
+
+// withIdentity replaces the identity and roles in the incoming metadata with the ones of the client,
+// so that they are passed along with the proxied request and can't be forged by the client.
+func withIdentity(ctx context.Context, identity Identity) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+
+	for _, key := range []string{constants.APIAuthzRoleMetadataKey, constants.APIAuthzSubjectMetadataKey, constants.APIAuthzFingerprintMetadataKey} {
+		delete(md, key)
+	}
+
+	if len(identity.Roles) > 0 {
+		md.Set(constants.APIAuthzRoleMetadataKey, identity.Roles...)
+	}
+
+	if identity.Subject != "" {
+		md.Set(constants.APIAuthzSubjectMetadataKey, identity.Subject)
+	}
+
+	if identity.Fingerprint != "" {
+		md.Set(constants.APIAuthzFingerprintMetadataKey, identity.Fingerprint)
+	}
+
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+// targets returns the list of nodes the call is going to be executed on.
+//
+// Each node is identified by the list of its addresses: the local node might have several addresses,
+// and the call is allowed on it if any of the addresses is allowed.
+func (a *Authorizer) targets(ctx context.Context) ([][]string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if _, proxied := md["proxyfrom"]; !proxied {
+		if nodes, ok := md["nodes"]; ok {
+			targets := make([][]string, 0, len(nodes))
+
+			for _, node := range nodes {
+				targets = append(targets, []string{node})
+			}
+
+			return targets, nil
+		}
+	}
+
+	addresses, err := a.localNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	return [][]string{addresses}, nil
+}
+
+func allowed(rules []Rule, fullMethod string, target []string) bool {
+	for _, rule := range rules {
+		if !matchMethod(rule.Methods, fullMethod) {
+			continue
+		}
+
+		for _, address := range target {
+			if matchNode(rule.Nodes, address) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func matchMethod(methods []string, fullMethod string) bool {
+	for _, method := range methods {
+		if strings.HasSuffix(method, "*") {
+			if strings.HasPrefix(fullMethod, strings.TrimSuffix(method, "*")) {
+				return true
+			}
+
+			continue
+		}
+
+		if method == fullMethod {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchNode(nodes []string, target string) bool {
+	if len(nodes) == 0 {
+		return true
+	}
+
+	ip := net.ParseIP(target)
+
+	for _, node := range nodes {
+		if ip != nil {
+			if _, network, err := net.ParseCIDR(node); err == nil {
+				if network.Contains(ip) {
+					return true
+				}
+
+				continue
+			}
+
+			if nodeIP := net.ParseIP(node); nodeIP != nil && nodeIP.Equal(ip) {
+				return true
+			}
+		}
+
+		if node == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func intersects(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,608 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package authz_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/talos-systems/talos/pkg/grpc/middleware/authz"
+)
+
+func peerContext(md metadata.MD, roles ...string) context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	return peer.NewContext(ctx, &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{
+					{
+						{
+							Subject: pkix.Name{
+								Organization: roles,
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAuthorize(t *testing.T) {
+	authorizer := authz.NewAuthorizer(
+		[]authz.Rule{
+			{
+				Roles:   []string{"tenant-a"},
+				Methods: []string{"/machine.MachineService/Logs", "/machine.MachineService/Events"},
+				Nodes:   []string{"10.5.0.0/24", "worker-1"},
+			},
+			{
+				Roles:   []string{"tenant-b"},
+				Methods: []string{"/machine.MachineService/*"},
+			},
+		},
+		nil,
+		func() ([]string, error) {
+			return []string{"10.5.0.2"}, nil
+		},
+		log.New(ioutil.Discard, "", 0),
+	)
+
+	for _, test := range []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		expected codes.Code
+	}{
+		{
+			name:     "unrestricted",
+			ctx:      peerContext(metadata.MD{}, "os:admin"),
+			method:   "/machine.MachineService/Reset",
+			expected: codes.OK,
+		},
+		{
+			name:     "no roles",
+			ctx:      peerContext(metadata.MD{}),
+			method:   "/machine.MachineService/Reset",
+			expected: codes.OK,
+		},
+		{
+			name:     "allowed local",
+			ctx:      peerContext(metadata.MD{}, "tenant-a"),
+			method:   "/machine.MachineService/Logs",
+			expected: codes.OK,
+		},
+		{
+			name:     "denied method",
+			ctx:      peerContext(metadata.MD{}, "tenant-a"),
+			method:   "/machine.MachineService/Reset",
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:     "allowed nodes",
+			ctx:      peerContext(metadata.Pairs("nodes", "10.5.0.3", "nodes", "worker-1"), "tenant-a"),
+			method:   "/machine.MachineService/Events",
+			expected: codes.OK,
+		},
+		{
+			name:     "denied node",
+			ctx:      peerContext(metadata.Pairs("nodes", "10.5.0.3", "nodes", "10.6.0.3"), "tenant-a"),
+			method:   "/machine.MachineService/Events",
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:     "proxyfrom ignores nodes",
+			ctx:      peerContext(metadata.Pairs("nodes", "10.6.0.3", "proxyfrom", "10.6.0.3"), "tenant-a"),
+			method:   "/machine.MachineService/Events",
+			expected: codes.OK,
+		},
+		{
+			name:     "prefix",
+			ctx:      peerContext(metadata.Pairs("nodes", "10.6.0.3"), "tenant-b"),
+			method:   "/machine.MachineService/Reboot",
+			expected: codes.OK,
+		},
+		{
+			name:     "prefix denied",
+			ctx:      peerContext(metadata.MD{}, "tenant-b"),
+			method:   "/cluster.ClusterService/HealthCheck",
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:     "impersonated",
+			ctx:      peerContext(metadata.Pairs("talos-role", "tenant-b", "proxyfrom", "10.5.0.3"), "os:impersonator"),
+			method:   "/cluster.ClusterService/HealthCheck",
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:     "forged roles",
+			ctx:      peerContext(metadata.Pairs("talos-role", "os:admin"), "tenant-b"),
+			method:   "/cluster.ClusterService/HealthCheck",
+			expected: codes.PermissionDenied,
+		},
+	} {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			err := authorizer.Authorize(test.ctx, test.method)
+
+			assert.Equal(t, test.expected, status.Code(err))
+		})
+	}
+}
+
+func TestAuthorizeMultiHomed(t *testing.T) {
+	authorizer := authz.NewAuthorizer(
+		[]authz.Rule{
+			{
+				Roles:   []string{"tenant-a"},
+				Methods: []string{"/machine.MachineService/Logs"},
+				Nodes:   []string{"10.5.0.0/24"},
+			},
+		},
+		nil,
+		func() ([]string, error) {
+			return []string{"192.168.1.2", "10.5.0.2", "fd00::2"}, nil
+		},
+		log.New(ioutil.Discard, "", 0),
+	)
+
+	for _, test := range []struct {
+		name     string
+		ctx      context.Context
+		expected codes.Code
+	}{
+		{
+			name:     "local node",
+			ctx:      peerContext(metadata.MD{}, "tenant-a"),
+			expected: codes.OK,
+		},
+		{
+			name:     "proxied to local node",
+			ctx:      peerContext(metadata.Pairs("talos-role", "tenant-a", "proxyfrom", "10.5.0.3"), "os:impersonator"),
+			expected: codes.OK,
+		},
+		{
+			name:     "other address of the local node",
+			ctx:      peerContext(metadata.Pairs("nodes", "192.168.1.2"), "tenant-a"),
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:     "allowed address of the local node",
+			ctx:      peerContext(metadata.Pairs("nodes", "10.5.0.2"), "tenant-a"),
+			expected: codes.OK,
+		},
+	} {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			err := authorizer.Authorize(test.ctx, "/machine.MachineService/Logs")
+
+			assert.Equal(t, test.expected, status.Code(err))
+		})
+	}
+
+	notAllowed := authz.NewAuthorizer(
+		[]authz.Rule{
+			{
+				Roles:   []string{"tenant-a"},
+				Methods: []string{"/machine.MachineService/Logs"},
+				Nodes:   []string{"10.6.0.0/24"},
+			},
+		},
+		nil,
+		func() ([]string, error) {
+			return []string{"192.168.1.2", "10.5.0.2"}, nil
+		},
+		log.New(ioutil.Discard, "", 0),
+	)
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(notAllowed.Authorize(peerContext(metadata.MD{}, "tenant-a"), "/machine.MachineService/Logs")))
+}
+
+func subjectContext(md metadata.MD, subject string, roles ...string) context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	return peer.NewContext(ctx, &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{
+					{
+						{
+							Subject: pkix.Name{
+								CommonName:   subject,
+								Organization: roles,
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAuthorizePolicy(t *testing.T) {
+	authorizer := authz.NewAuthorizer(
+		[]authz.Rule{
+			{
+				Roles:   []string{"tenant-a"},
+				Methods: []string{"/machine.MachineService/*"},
+			},
+		},
+		[]authz.Policy{
+			{
+				Methods:  []string{"/machine.MachineService/Reset", "/machine.MachineService/ResetStream"},
+				Subjects: []string{"ops-admin"},
+			},
+			{
+				Methods: []string{"/machine.MachineService/Reset"},
+				Roles:   []string{"tenant-a"},
+			},
+			{
+				Methods: []string{"/machine.MachineService/Copy"},
+			},
+		},
+		func() ([]string, error) {
+			return []string{"10.5.0.2"}, nil
+		},
+		log.New(ioutil.Discard, "", 0),
+	)
+
+	for _, test := range []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		expected codes.Code
+	}{
+		{
+			name:     "not covered by policy",
+			ctx:      subjectContext(metadata.MD{}, "alice", "os:admin"),
+			method:   "/machine.MachineService/Reboot",
+			expected: codes.OK,
+		},
+		{
+			name:     "allowed subject",
+			ctx:      subjectContext(metadata.MD{}, "ops-admin", "os:admin"),
+			method:   "/machine.MachineService/ResetStream",
+			expected: codes.OK,
+		},
+		{
+			name:     "denied subject",
+			ctx:      subjectContext(metadata.MD{}, "alice", "os:admin"),
+			method:   "/machine.MachineService/ResetStream",
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:     "allowed role",
+			ctx:      subjectContext(metadata.MD{}, "bob", "tenant-a"),
+			method:   "/machine.MachineService/Reset",
+			expected: codes.OK,
+		},
+		{
+			name:     "disabled",
+			ctx:      subjectContext(metadata.MD{}, "ops-admin", "os:admin"),
+			method:   "/machine.MachineService/Copy",
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:     "client without roles",
+			ctx:      subjectContext(metadata.MD{}, "alice"),
+			method:   "/machine.MachineService/Reset",
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:     "impersonated",
+			ctx:      subjectContext(metadata.Pairs("talos-subject", "ops-admin", "talos-role", "os:admin", "proxyfrom", "10.5.0.3"), "apid", "os:impersonator"),
+			method:   "/machine.MachineService/Reset",
+			expected: codes.OK,
+		},
+		{
+			name:     "forged subject",
+			ctx:      subjectContext(metadata.Pairs("talos-subject", "ops-admin"), "alice", "os:admin"),
+			method:   "/machine.MachineService/Reset",
+			expected: codes.PermissionDenied,
+		},
+	} {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			err := authorizer.Authorize(test.ctx, test.method)
+
+			assert.Equal(t, test.expected, status.Code(err))
+		})
+	}
+}
+
+func TestLocalAuthorizer(t *testing.T) {
+	authorizer := authz.NewLocalAuthorizer(
+		[]authz.Rule{
+			{
+				Roles:   []string{"os:local"},
+				Methods: []string{"/machine.MachineService/Version", "/machine.MachineService/Upgrade"},
+			},
+		},
+		[]authz.Policy{
+			{
+				Methods: []string{"/machine.MachineService/Upgrade"},
+				Roles:   []string{"os:admin"},
+			},
+		},
+		func() ([]string, error) {
+			return []string{"10.5.0.2"}, nil
+		},
+		log.New(ioutil.Discard, "", 0),
+	)
+
+	for _, test := range []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		expected codes.Code
+	}{
+		{
+			name:     "allowed",
+			ctx:      metadata.NewIncomingContext(context.Background(), metadata.MD{}),
+			method:   "/machine.MachineService/Version",
+			expected: codes.OK,
+		},
+		{
+			name:     "denied by rules",
+			ctx:      metadata.NewIncomingContext(context.Background(), metadata.MD{}),
+			method:   "/machine.MachineService/Reset",
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:     "denied by policy",
+			ctx:      metadata.NewIncomingContext(context.Background(), metadata.Pairs("talos-role", "os:admin")),
+			method:   "/machine.MachineService/Upgrade",
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:     "other nodes",
+			ctx:      metadata.NewIncomingContext(context.Background(), metadata.Pairs("nodes", "10.5.0.3")),
+			method:   "/machine.MachineService/Version",
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:     "certificate is ignored",
+			ctx:      subjectContext(metadata.MD{}, "ops-admin", "os:admin"),
+			method:   "/machine.MachineService/Reset",
+			expected: codes.PermissionDenied,
+		},
+	} {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			err := authorizer.Authorize(test.ctx, test.method)
+
+			assert.Equal(t, test.expected, status.Code(err))
+		})
+	}
+
+	var identity authz.Identity
+
+	_, err := authorizer.UnaryInterceptor()(metadata.NewIncomingContext(context.Background(), metadata.Pairs("talos-role", "os:admin")), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/machine.MachineService/Version"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			identity = authz.IdentityFromMetadata(ctx)
+
+			return nil, nil
+		})
+
+	assert.NoError(t, err)
+	assert.Equal(t, authz.Identity{Subject: "local", Roles: []string{"os:local"}}, identity)
+}
+
+func TestLocalAuthorizerDestructive(t *testing.T) {
+	localNodes := func() ([]string, error) {
+		return []string{"10.5.0.2"}, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{})
+
+	unrestricted := authz.NewLocalAuthorizer(nil, nil, localNodes, log.New(ioutil.Discard, "", 0))
+
+	for _, method := range []string{
+		"/machine.MachineService/Version",
+		"/machine.MachineService/ServiceList",
+		"/machine.MachineService/Logs",
+		"/cluster.ClusterService/HealthCheck",
+	} {
+		assert.NoError(t, unrestricted.Authorize(ctx, method), method)
+	}
+
+	for _, method := range []string{
+		"/machine.MachineService/ApplyConfiguration",
+		"/machine.MachineService/Bootstrap",
+		"/machine.MachineService/Reboot",
+		"/machine.MachineService/Reset",
+		"/machine.MachineService/ResetStream",
+		"/machine.MachineService/SequencePause",
+		"/machine.MachineService/ServiceStop",
+		"/machine.MachineService/Shutdown",
+		"/machine.MachineService/Upgrade",
+	} {
+		assert.Equal(t, codes.PermissionDenied, status.Code(unrestricted.Authorize(ctx, method)), method)
+	}
+
+	// destructive methods are not restricted for the clients with certificates
+	assert.NoError(t, authz.NewAuthorizer(nil, nil, localNodes, log.New(ioutil.Discard, "", 0)).Authorize(
+		peerContext(metadata.MD{}, "tenant-a"), "/machine.MachineService/Reset"))
+
+	upgrade := authz.NewLocalAuthorizer(
+		[]authz.Rule{
+			{
+				Roles:   []string{"os:local"},
+				Methods: []string{"/machine.MachineService/Version", "/machine.MachineService/Upgrade"},
+			},
+		},
+		nil, localNodes, log.New(ioutil.Discard, "", 0),
+	)
+
+	assert.NoError(t, upgrade.Authorize(ctx, "/machine.MachineService/Upgrade"))
+	assert.Equal(t, codes.PermissionDenied, status.Code(upgrade.Authorize(ctx, "/machine.MachineService/Reset")))
+
+	all := authz.NewLocalAuthorizer(
+		[]authz.Rule{
+			{
+				Roles:   []string{"os:local"},
+				Methods: []string{"/machine.MachineService/*"},
+			},
+		},
+		nil, localNodes, log.New(ioutil.Discard, "", 0),
+	)
+
+	assert.NoError(t, all.Authorize(ctx, "/machine.MachineService/Reset"))
+	assert.Equal(t, codes.PermissionDenied, status.Code(all.Authorize(ctx, "/cluster.ClusterService/HealthCheck")))
+
+	// rules for the other roles don't grant the destructive methods to the local role
+	other := authz.NewLocalAuthorizer(
+		[]authz.Rule{
+			{
+				Roles:   []string{"tenant-a"},
+				Methods: []string{"/machine.MachineService/*"},
+			},
+		},
+		nil, localNodes, log.New(ioutil.Discard, "", 0),
+	)
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(other.Authorize(ctx, "/machine.MachineService/Upgrade")))
+}
+
+type fakeStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *fakeStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamInterceptorRoles(t *testing.T) {
+	authorizer := authz.NewAuthorizer(nil, nil, nil, log.New(ioutil.Discard, "", 0))
+
+	for _, test := range []struct {
+		name     string
+		ctx      context.Context
+		expected []string
+	}{
+		{
+			name:     "client",
+			ctx:      peerContext(metadata.Pairs("talos-role", "os:admin"), "tenant-a"),
+			expected: []string{"tenant-a"},
+		},
+		{
+			name:     "client without roles",
+			ctx:      peerContext(metadata.Pairs("talos-role", "tenant-a")),
+			expected: nil,
+		},
+		{
+			name:     "impersonated",
+			ctx:      peerContext(metadata.Pairs("talos-role", "os:admin"), "os:impersonator"),
+			expected: []string{"os:admin"},
+		},
+	} {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			var roles []string
+
+			err := authorizer.StreamInterceptor()(nil, &fakeStream{ctx: test.ctx}, &grpc.StreamServerInfo{FullMethod: "/machine.MachineService/Version"},
+				func(srv interface{}, stream grpc.ServerStream) error {
+					roles = authz.RolesFromMetadata(stream.Context())
+
+					return nil
+				})
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, roles)
+		})
+	}
+}
+
+func TestIsAdmin(t *testing.T) {
+	assert.True(t, authz.IsAdmin(nil))
+	assert.True(t, authz.IsAdmin([]string{"os:admin", "tenant-a"}))
+	assert.False(t, authz.IsAdmin([]string{"tenant-a"}))
+}
+
+func TestUnaryInterceptorIdentity(t *testing.T) {
+	authorizer := authz.NewAuthorizer(nil, nil, nil, log.New(ioutil.Discard, "", 0))
+
+	for _, test := range []struct {
+		name     string
+		ctx      context.Context
+		expected authz.Identity
+	}{
+		{
+			name: "client",
+			ctx: peer.NewContext(metadata.NewIncomingContext(context.Background(), metadata.Pairs("talos-subject", "forged")), &peer.Peer{
+				AuthInfo: credentials.TLSInfo{
+					State: tls.ConnectionState{
+						PeerCertificates: []*x509.Certificate{
+							{
+								Subject: pkix.Name{
+									CommonName:   "alice",
+									Organization: []string{"os:admin"},
+								},
+							},
+						},
+					},
+				},
+			}),
+			expected: authz.Identity{
+				Subject:     "alice",
+				Fingerprint: "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=",
+				Roles:       []string{"os:admin"},
+			},
+		},
+		{
+			name: "impersonated",
+			ctx: peerContext(metadata.Pairs("talos-subject", "alice", "talos-fingerprint", "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=", "talos-role", "tenant-a"),
+				"os:impersonator"),
+			expected: authz.Identity{
+				Subject:     "alice",
+				Fingerprint: "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=",
+				Roles:       []string{"tenant-a"},
+			},
+		},
+		{
+			name:     "no peer",
+			ctx:      metadata.NewIncomingContext(context.Background(), metadata.Pairs("talos-subject", "forged")),
+			expected: authz.Identity{},
+		},
+	} {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			var identity authz.Identity
+
+			_, err := authorizer.UnaryInterceptor()(test.ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/machine.MachineService/Version"},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					identity = authz.IdentityFromMetadata(ctx)
+
+					return nil, nil
+				})
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, identity)
+		})
+	}
+}
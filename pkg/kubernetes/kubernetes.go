@@ -77,31 +77,28 @@ func NewForConfig(config *restclient.Config) (client *Client, err error) {
 //
 // nolint: interfacer
 func NewClientFromPKI(ca, crt, key []byte, endpoint *url.URL) (client *Client, err error) {
-	tlsClientConfig := restclient.TLSClientConfig{
-		CAData:   ca,
-		CertData: crt,
-		KeyData:  key,
-	}
+	return NewForConfig(restConfigFromPKI(ca, crt, key, endpoint))
+}
 
-	config := &restclient.Config{
-		Host:            endpoint.String(),
-		TLSClientConfig: tlsClientConfig,
-		Timeout:         30 * time.Second,
+// NewTemporaryClientFromPKI initializes a Kubernetes client using a certificate
+// with a TTL of 10 minutes.
+func NewTemporaryClientFromPKI(ca *x509.PEMEncodedCertificateAndKey, endpoint *url.URL) (client *Client, err error) {
+	config, err := NewTemporaryRESTConfigFromPKI(ca, endpoint)
+	if err != nil {
+		return nil, err
 	}
 
-	var clientset *kubernetes.Clientset
-
-	clientset, err = kubernetes.NewForConfig(config)
+	h, err := NewForConfig(config)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to create client: %w", err)
 	}
 
-	return &Client{clientset}, nil
+	return h, nil
 }
 
-// NewTemporaryClientFromPKI initializes a Kubernetes client using a certificate
+// NewTemporaryRESTConfigFromPKI initializes a Kubernetes client config using a certificate
 // with a TTL of 10 minutes.
-func NewTemporaryClientFromPKI(ca *x509.PEMEncodedCertificateAndKey, endpoint *url.URL) (client *Client, err error) {
+func NewTemporaryRESTConfigFromPKI(ca *x509.PEMEncodedCertificateAndKey, endpoint *url.URL) (*restclient.Config, error) {
 	opts := []x509.Option{
 		x509.RSA(true),
 		x509.CommonName("admin"),
@@ -134,12 +131,19 @@ func NewTemporaryClientFromPKI(ca *x509.PEMEncodedCertificateAndKey, endpoint *u
 		return nil, fmt.Errorf("failed to create certificate from CSR: %w", err)
 	}
 
-	h, err := NewClientFromPKI(ca.Crt, crt.X509CertificatePEM, key.KeyPEM, endpoint)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
-	}
+	return restConfigFromPKI(ca.Crt, crt.X509CertificatePEM, key.KeyPEM, endpoint), nil
+}
 
-	return h, nil
+func restConfigFromPKI(ca, crt, key []byte, endpoint *url.URL) *restclient.Config {
+	return &restclient.Config{
+		Host: endpoint.String(),
+		TLSClientConfig: restclient.TLSClientConfig{
+			CAData:   ca,
+			CertData: crt,
+			KeyData:  key,
+		},
+		Timeout: 30 * time.Second,
+	}
 }
 
 // MasterIPs returns a list of control plane endpoints (IP addresses).
@@ -282,12 +286,12 @@ func (h *Client) WaitUntilReady(name string) error {
 }
 
 // CordonAndDrain cordons and drains a node in one call.
-func (h *Client) CordonAndDrain(node string) (err error) {
+func (h *Client) CordonAndDrain(ctx context.Context, node string) (err error) {
 	if err = h.Cordon(node); err != nil {
 		return err
 	}
 
-	return h.Drain(node)
+	return h.Drain(ctx, node)
 }
 
 const (
@@ -360,12 +364,14 @@ func (h *Client) Uncordon(name string, force bool) error {
 }
 
 // Drain evicts all pods on a given node.
-func (h *Client) Drain(node string) error {
+//
+// Drain is aborted when the context is canceled, ctx.Err() is returned in that case.
+func (h *Client) Drain(ctx context.Context, node string) error {
 	opts := metav1.ListOptions{
 		FieldSelector: fields.SelectorFromSet(fields.Set{"spec.nodeName": node}).String(),
 	}
 
-	pods, err := h.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), opts)
+	pods, err := h.CoreV1().Pods(metav1.NamespaceAll).List(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("cannot get pods for node %s: %w", node, err)
 	}
@@ -388,7 +394,7 @@ func (h *Client) Drain(node string) error {
 				}
 			}
 
-			if err := h.evict(p, int64(60)); err != nil {
+			if err := h.evict(ctx, p, int64(60)); err != nil {
 				log.Printf("WARNING: failed to evict pod: %v", err)
 			}
 		}(pod)
@@ -396,36 +402,43 @@ func (h *Client) Drain(node string) error {
 
 	wg.Wait()
 
-	return nil
+	return ctx.Err()
 }
 
-func (h *Client) evict(p corev1.Pod, gracePeriod int64) error {
+func (h *Client) evict(ctx context.Context, p corev1.Pod, gracePeriod int64) error {
 	for {
 		pol := &policy.Eviction{
 			ObjectMeta:    metav1.ObjectMeta{Namespace: p.GetNamespace(), Name: p.GetName()},
 			DeleteOptions: &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod},
 		}
-		err := h.CoreV1().Pods(p.GetNamespace()).Evict(context.TODO(), pol)
+		err := h.CoreV1().Pods(p.GetNamespace()).Evict(ctx, pol)
 
 		switch {
+		case ctx.Err() != nil:
+			return fmt.Errorf("failed to evict pod %s/%s: %w", p.GetNamespace(), p.GetName(), ctx.Err())
 		case apierrors.IsTooManyRequests(err):
-			time.Sleep(5 * time.Second)
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
+			}
 		case apierrors.IsNotFound(err):
 			return nil
 		case err != nil:
 			return fmt.Errorf("failed to evict pod %s/%s: %w", p.GetNamespace(), p.GetName(), err)
 		default:
-			if err = h.waitForPodDeleted(&p); err != nil {
+			if err = h.waitForPodDeleted(ctx, &p); err != nil {
 				return fmt.Errorf("failed waiting on pod %s/%s to be deleted: %w", p.GetNamespace(), p.GetName(), err)
 			}
 		}
 	}
 }
 
-func (h *Client) waitForPodDeleted(p *corev1.Pod) error {
+func (h *Client) waitForPodDeleted(ctx context.Context, p *corev1.Pod) error {
 	return retry.Constant(time.Minute, retry.WithUnits(3*time.Second)).Retry(func() error {
-		pod, err := h.CoreV1().Pods(p.GetNamespace()).Get(context.TODO(), p.GetName(), metav1.GetOptions{})
+		pod, err := h.CoreV1().Pods(p.GetNamespace()).Get(ctx, p.GetName(), metav1.GetOptions{})
 		switch {
+		case ctx.Err() != nil:
+			return retry.UnexpectedError(ctx.Err())
 		case apierrors.IsNotFound(err):
 			return nil
 		case err != nil:
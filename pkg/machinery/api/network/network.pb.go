@@ -648,6 +648,135 @@ func (x *Interface) GetIpaddress() []string {
 	return nil
 }
 
+type StatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Messages []*Status `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+}
+
+func (x *StatusResponse) Reset() {
+	*x = StatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_network_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusResponse) ProtoMessage() {}
+
+func (x *StatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_network_network_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
+func (*StatusResponse) Descriptor() ([]byte, []int) {
+	return file_network_network_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StatusResponse) GetMessages() []*Status {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+// Status describes the effective hostname and resolvers, and where they came from.
+type Status struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Metadata *common.Metadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Hostname string           `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	// HostnameSource is one of: config, kernel, platform, dhcp, static, default
+	HostnameSource string   `protobuf:"bytes,3,opt,name=hostname_source,json=hostnameSource,proto3" json:"hostname_source,omitempty"`
+	Resolvers      []string `protobuf:"bytes,4,rep,name=resolvers,proto3" json:"resolvers,omitempty"`
+	// ResolversSource is one of: config, dhcp, static, default
+	ResolversSource string `protobuf:"bytes,5,opt,name=resolvers_source,json=resolversSource,proto3" json:"resolvers_source,omitempty"`
+}
+
+func (x *Status) Reset() {
+	*x = Status{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_network_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Status) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Status) ProtoMessage() {}
+
+func (x *Status) ProtoReflect() protoreflect.Message {
+	mi := &file_network_network_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Status.ProtoReflect.Descriptor instead.
+func (*Status) Descriptor() ([]byte, []int) {
+	return file_network_network_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Status) GetMetadata() *common.Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Status) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *Status) GetHostnameSource() string {
+	if x != nil {
+		return x.HostnameSource
+	}
+	return ""
+}
+
+func (x *Status) GetResolvers() []string {
+	if x != nil {
+		return x.Resolvers
+	}
+	return nil
+}
+
+func (x *Status) GetResolversSource() string {
+	if x != nil {
+		return x.ResolversSource
+	}
+	return ""
+}
+
 var File_network_network_proto protoreflect.FileDescriptor
 
 var file_network_network_proto_rawDesc = []byte{
@@ -707,55 +836,75 @@ var file_network_network_proto_rawDesc = []byte{
 	0x6f, 0x72, 0x6b, 0x2e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x46, 0x6c, 0x61,
 	0x67, 0x73, 0x52, 0x05, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x70, 0x61,
 	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x69, 0x70,
-	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x2a, 0x51, 0x0a, 0x0d, 0x41, 0x64, 0x64, 0x72, 0x65,
-	0x73, 0x73, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x46, 0x5f, 0x55,
-	0x4e, 0x53, 0x50, 0x45, 0x43, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x46, 0x5f, 0x49, 0x4e,
-	0x45, 0x54, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x50, 0x56, 0x34, 0x10, 0x02, 0x12, 0x0c,
-	0x0a, 0x08, 0x41, 0x46, 0x5f, 0x49, 0x4e, 0x45, 0x54, 0x36, 0x10, 0x0a, 0x12, 0x08, 0x0a, 0x04,
-	0x49, 0x50, 0x56, 0x36, 0x10, 0x0a, 0x1a, 0x02, 0x10, 0x01, 0x2a, 0xaf, 0x02, 0x0a, 0x0d, 0x52,
-	0x6f, 0x75, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x11, 0x0a, 0x0d,
-	0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x10, 0x00, 0x12,
-	0x13, 0x0a, 0x0f, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x52, 0x45, 0x44, 0x49, 0x52, 0x45,
-	0x43, 0x54, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x4b,
-	0x45, 0x52, 0x4e, 0x45, 0x4c, 0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x52, 0x54, 0x50, 0x52, 0x4f,
-	0x54, 0x5f, 0x42, 0x4f, 0x4f, 0x54, 0x10, 0x03, 0x12, 0x11, 0x0a, 0x0d, 0x52, 0x54, 0x50, 0x52,
-	0x4f, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x49, 0x43, 0x10, 0x04, 0x12, 0x10, 0x0a, 0x0c, 0x52,
-	0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x47, 0x41, 0x54, 0x45, 0x44, 0x10, 0x08, 0x12, 0x0d, 0x0a,
-	0x09, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x52, 0x41, 0x10, 0x09, 0x12, 0x0e, 0x0a, 0x0a,
-	0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x4d, 0x52, 0x54, 0x10, 0x0a, 0x12, 0x10, 0x0a, 0x0c,
-	0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x5a, 0x45, 0x42, 0x52, 0x41, 0x10, 0x0b, 0x12, 0x0f,
-	0x0a, 0x0b, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x42, 0x49, 0x52, 0x44, 0x10, 0x0c, 0x12,
-	0x13, 0x0a, 0x0f, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x44, 0x4e, 0x52, 0x4f, 0x55, 0x54,
-	0x45, 0x44, 0x10, 0x0d, 0x12, 0x0f, 0x0a, 0x0b, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x58,
-	0x4f, 0x52, 0x50, 0x10, 0x0e, 0x12, 0x0e, 0x0a, 0x0a, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f,
-	0x4e, 0x54, 0x4b, 0x10, 0x0f, 0x12, 0x0f, 0x0a, 0x0b, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f,
-	0x44, 0x48, 0x43, 0x50, 0x10, 0x10, 0x12, 0x12, 0x0a, 0x0e, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54,
-	0x5f, 0x4d, 0x52, 0x4f, 0x55, 0x54, 0x45, 0x44, 0x10, 0x11, 0x12, 0x10, 0x0a, 0x0c, 0x52, 0x54,
-	0x50, 0x52, 0x4f, 0x54, 0x5f, 0x42, 0x41, 0x42, 0x45, 0x4c, 0x10, 0x2a, 0x2a, 0x83, 0x01, 0x0a,
-	0x0e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12,
-	0x10, 0x0a, 0x0c, 0x46, 0x4c, 0x41, 0x47, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10,
-	0x00, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x4c, 0x41, 0x47, 0x5f, 0x55, 0x50, 0x10, 0x01, 0x12, 0x12,
-	0x0a, 0x0e, 0x46, 0x4c, 0x41, 0x47, 0x5f, 0x42, 0x52, 0x4f, 0x41, 0x44, 0x43, 0x41, 0x53, 0x54,
-	0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x46, 0x4c, 0x41, 0x47, 0x5f, 0x4c, 0x4f, 0x4f, 0x50, 0x42,
-	0x41, 0x43, 0x4b, 0x10, 0x03, 0x12, 0x17, 0x0a, 0x13, 0x46, 0x4c, 0x41, 0x47, 0x5f, 0x50, 0x4f,
-	0x49, 0x4e, 0x54, 0x5f, 0x54, 0x4f, 0x5f, 0x50, 0x4f, 0x49, 0x4e, 0x54, 0x10, 0x04, 0x12, 0x12,
-	0x0a, 0x0e, 0x46, 0x4c, 0x41, 0x47, 0x5f, 0x4d, 0x55, 0x4c, 0x54, 0x49, 0x43, 0x41, 0x53, 0x54,
-	0x10, 0x05, 0x32, 0x8e, 0x01, 0x0a, 0x0e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x39, 0x0a, 0x06, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x12,
-	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x17, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72,
-	0x6b, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x41, 0x0a, 0x0a, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x73, 0x12, 0x16,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1b, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
-	0x2e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x42, 0x59, 0x0a, 0x0f, 0x63, 0x6f, 0x6d, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f,
-	0x72, 0x6b, 0x2e, 0x61, 0x70, 0x69, 0x42, 0x0a, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41,
-	0x70, 0x69, 0x50, 0x01, 0x5a, 0x38, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2f, 0x74, 0x61, 0x6c, 0x6f, 0x73, 0x2d, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x2f, 0x74,
-	0x61, 0x6c, 0x6f, 0x73, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65,
-	0x72, 0x79, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x3d, 0x0a, 0x0e, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x08, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x08, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x22, 0xc4, 0x01, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x2c, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x4d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12,
+	0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x68,
+	0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x53, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65,
+	0x72, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x73, 0x5f,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x65,
+	0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x73, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x2a, 0x51, 0x0a,
+	0x0d, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x12, 0x0d,
+	0x0a, 0x09, 0x41, 0x46, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x10, 0x00, 0x12, 0x0b, 0x0a,
+	0x07, 0x41, 0x46, 0x5f, 0x49, 0x4e, 0x45, 0x54, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x50,
+	0x56, 0x34, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x46, 0x5f, 0x49, 0x4e, 0x45, 0x54, 0x36,
+	0x10, 0x0a, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x50, 0x56, 0x36, 0x10, 0x0a, 0x1a, 0x02, 0x10, 0x01,
+	0x2a, 0xaf, 0x02, 0x0a, 0x0d, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63,
+	0x6f, 0x6c, 0x12, 0x11, 0x0a, 0x0d, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f,
+	0x52, 0x45, 0x44, 0x49, 0x52, 0x45, 0x43, 0x54, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x52, 0x54,
+	0x50, 0x52, 0x4f, 0x54, 0x5f, 0x4b, 0x45, 0x52, 0x4e, 0x45, 0x4c, 0x10, 0x02, 0x12, 0x0f, 0x0a,
+	0x0b, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x42, 0x4f, 0x4f, 0x54, 0x10, 0x03, 0x12, 0x11,
+	0x0a, 0x0d, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x49, 0x43, 0x10,
+	0x04, 0x12, 0x10, 0x0a, 0x0c, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x47, 0x41, 0x54, 0x45,
+	0x44, 0x10, 0x08, 0x12, 0x0d, 0x0a, 0x09, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x52, 0x41,
+	0x10, 0x09, 0x12, 0x0e, 0x0a, 0x0a, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x4d, 0x52, 0x54,
+	0x10, 0x0a, 0x12, 0x10, 0x0a, 0x0c, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x5a, 0x45, 0x42,
+	0x52, 0x41, 0x10, 0x0b, 0x12, 0x0f, 0x0a, 0x0b, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x42,
+	0x49, 0x52, 0x44, 0x10, 0x0c, 0x12, 0x13, 0x0a, 0x0f, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f,
+	0x44, 0x4e, 0x52, 0x4f, 0x55, 0x54, 0x45, 0x44, 0x10, 0x0d, 0x12, 0x0f, 0x0a, 0x0b, 0x52, 0x54,
+	0x50, 0x52, 0x4f, 0x54, 0x5f, 0x58, 0x4f, 0x52, 0x50, 0x10, 0x0e, 0x12, 0x0e, 0x0a, 0x0a, 0x52,
+	0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x4e, 0x54, 0x4b, 0x10, 0x0f, 0x12, 0x0f, 0x0a, 0x0b, 0x52,
+	0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x44, 0x48, 0x43, 0x50, 0x10, 0x10, 0x12, 0x12, 0x0a, 0x0e,
+	0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x4d, 0x52, 0x4f, 0x55, 0x54, 0x45, 0x44, 0x10, 0x11,
+	0x12, 0x10, 0x0a, 0x0c, 0x52, 0x54, 0x50, 0x52, 0x4f, 0x54, 0x5f, 0x42, 0x41, 0x42, 0x45, 0x4c,
+	0x10, 0x2a, 0x2a, 0x83, 0x01, 0x0a, 0x0e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65,
+	0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x10, 0x0a, 0x0c, 0x46, 0x4c, 0x41, 0x47, 0x5f, 0x55, 0x4e,
+	0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x4c, 0x41, 0x47, 0x5f,
+	0x55, 0x50, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x46, 0x4c, 0x41, 0x47, 0x5f, 0x42, 0x52, 0x4f,
+	0x41, 0x44, 0x43, 0x41, 0x53, 0x54, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x46, 0x4c, 0x41, 0x47,
+	0x5f, 0x4c, 0x4f, 0x4f, 0x50, 0x42, 0x41, 0x43, 0x4b, 0x10, 0x03, 0x12, 0x17, 0x0a, 0x13, 0x46,
+	0x4c, 0x41, 0x47, 0x5f, 0x50, 0x4f, 0x49, 0x4e, 0x54, 0x5f, 0x54, 0x4f, 0x5f, 0x50, 0x4f, 0x49,
+	0x4e, 0x54, 0x10, 0x04, 0x12, 0x12, 0x0a, 0x0e, 0x46, 0x4c, 0x41, 0x47, 0x5f, 0x4d, 0x55, 0x4c,
+	0x54, 0x49, 0x43, 0x41, 0x53, 0x54, 0x10, 0x05, 0x32, 0xc9, 0x01, 0x0a, 0x0e, 0x4e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x39, 0x0a, 0x06, 0x52,
+	0x6f, 0x75, 0x74, 0x65, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x17, 0x2e,
+	0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x0a, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66,
+	0x61, 0x63, 0x65, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1b, 0x2e, 0x6e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39, 0x0a, 0x06, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x17, 0x2e, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x42, 0x59, 0x0a, 0x0f, 0x63, 0x6f, 0x6d, 0x2e, 0x6e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x2e, 0x61, 0x70, 0x69, 0x42, 0x0a, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x41, 0x70, 0x69, 0x50, 0x01, 0x5a, 0x38, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x74, 0x61, 0x6c, 0x6f, 0x73, 0x2d, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x2f,
+	0x74, 0x61, 0x6c, 0x6f, 0x73, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e,
+	0x65, 0x72, 0x79, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -772,7 +921,7 @@ func file_network_network_proto_rawDescGZIP() []byte {
 
 var (
 	file_network_network_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-	file_network_network_proto_msgTypes  = make([]protoimpl.MessageInfo, 6)
+	file_network_network_proto_msgTypes  = make([]protoimpl.MessageInfo, 8)
 	file_network_network_proto_goTypes   = []interface{}{
 		(AddressFamily)(0),         // 0: network.AddressFamily
 		(RouteProtocol)(0),         // 1: network.RouteProtocol
@@ -783,30 +932,36 @@ var (
 		(*InterfacesResponse)(nil), // 6: network.InterfacesResponse
 		(*Interfaces)(nil),         // 7: network.Interfaces
 		(*Interface)(nil),          // 8: network.Interface
-		(*common.Metadata)(nil),    // 9: common.Metadata
-		(*empty.Empty)(nil),        // 10: google.protobuf.Empty
+		(*StatusResponse)(nil),     // 9: network.StatusResponse
+		(*Status)(nil),             // 10: network.Status
+		(*common.Metadata)(nil),    // 11: common.Metadata
+		(*empty.Empty)(nil),        // 12: google.protobuf.Empty
 	}
 )
 
 var file_network_network_proto_depIdxs = []int32{
 	4,  // 0: network.RoutesResponse.messages:type_name -> network.Routes
-	9,  // 1: network.Routes.metadata:type_name -> common.Metadata
+	11, // 1: network.Routes.metadata:type_name -> common.Metadata
 	5,  // 2: network.Routes.routes:type_name -> network.Route
 	0,  // 3: network.Route.family:type_name -> network.AddressFamily
 	1,  // 4: network.Route.protocol:type_name -> network.RouteProtocol
 	7,  // 5: network.InterfacesResponse.messages:type_name -> network.Interfaces
-	9,  // 6: network.Interfaces.metadata:type_name -> common.Metadata
+	11, // 6: network.Interfaces.metadata:type_name -> common.Metadata
 	8,  // 7: network.Interfaces.interfaces:type_name -> network.Interface
 	2,  // 8: network.Interface.flags:type_name -> network.InterfaceFlags
-	10, // 9: network.NetworkService.Routes:input_type -> google.protobuf.Empty
-	10, // 10: network.NetworkService.Interfaces:input_type -> google.protobuf.Empty
-	3,  // 11: network.NetworkService.Routes:output_type -> network.RoutesResponse
-	6,  // 12: network.NetworkService.Interfaces:output_type -> network.InterfacesResponse
-	11, // [11:13] is the sub-list for method output_type
-	9,  // [9:11] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	10, // 9: network.StatusResponse.messages:type_name -> network.Status
+	11, // 10: network.Status.metadata:type_name -> common.Metadata
+	12, // 11: network.NetworkService.Routes:input_type -> google.protobuf.Empty
+	12, // 12: network.NetworkService.Interfaces:input_type -> google.protobuf.Empty
+	12, // 13: network.NetworkService.Status:input_type -> google.protobuf.Empty
+	3,  // 14: network.NetworkService.Routes:output_type -> network.RoutesResponse
+	6,  // 15: network.NetworkService.Interfaces:output_type -> network.InterfacesResponse
+	9,  // 16: network.NetworkService.Status:output_type -> network.StatusResponse
+	14, // [14:17] is the sub-list for method output_type
+	11, // [11:14] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_network_network_proto_init() }
@@ -887,6 +1042,30 @@ func file_network_network_proto_init() {
 				return nil
 			}
 		}
+		file_network_network_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_network_network_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Status); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -894,7 +1073,7 @@ func file_network_network_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_network_network_proto_rawDesc,
 			NumEnums:      3,
-			NumMessages:   6,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -925,6 +1104,7 @@ const _ = grpc.SupportPackageIsVersion6
 type NetworkServiceClient interface {
 	Routes(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*RoutesResponse, error)
 	Interfaces(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*InterfacesResponse, error)
+	Status(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*StatusResponse, error)
 }
 
 type networkServiceClient struct {
@@ -953,10 +1133,20 @@ func (c *networkServiceClient) Interfaces(ctx context.Context, in *empty.Empty,
 	return out, nil
 }
 
+func (c *networkServiceClient) Status(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*StatusResponse, error) {
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, "/network.NetworkService/Status", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NetworkServiceServer is the server API for NetworkService service.
 type NetworkServiceServer interface {
 	Routes(context.Context, *empty.Empty) (*RoutesResponse, error)
 	Interfaces(context.Context, *empty.Empty) (*InterfacesResponse, error)
+	Status(context.Context, *empty.Empty) (*StatusResponse, error)
 }
 
 // UnimplementedNetworkServiceServer can be embedded to have forward compatible implementations.
@@ -971,6 +1161,10 @@ func (*UnimplementedNetworkServiceServer) Interfaces(context.Context, *empty.Emp
 	return nil, status.Errorf(codes.Unimplemented, "method Interfaces not implemented")
 }
 
+func (*UnimplementedNetworkServiceServer) Status(context.Context, *empty.Empty) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
+
 func RegisterNetworkServiceServer(s *grpc.Server, srv NetworkServiceServer) {
 	s.RegisterService(&_NetworkService_serviceDesc, srv)
 }
@@ -1011,6 +1205,24 @@ func _NetworkService_Interfaces_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NetworkService_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServiceServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/network.NetworkService/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServiceServer).Status(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _NetworkService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "network.NetworkService",
 	HandlerType: (*NetworkServiceServer)(nil),
@@ -1023,6 +1235,10 @@ var _NetworkService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Interfaces",
 			Handler:    _NetworkService_Interfaces_Handler,
 		},
+		{
+			MethodName: "Status",
+			Handler:    _NetworkService_Status_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "network/network.proto",
@@ -296,6 +296,7 @@ func NewClient(cfg *tls.Config, endpoints []string, port int, opts ...grpc.DialO
 	c.TimeClient = timeapi.NewTimeServiceClient(c.conn)
 	c.NetworkClient = networkapi.NewNetworkServiceClient(c.conn)
 	c.ClusterClient = clusterapi.NewClusterServiceClient(c.conn)
+	c.StorageClient = storageapi.NewStorageServiceClient(c.conn)
 
 	return c, nil
 }
@@ -381,7 +382,73 @@ func (c *Client) GenerateConfiguration(ctx context.Context, req *machineapi.Gene
 
 // Disks returns the list of block devices.
 func (c *Client) Disks(ctx context.Context, callOptions ...grpc.CallOption) (resp *storageapi.DisksResponse, err error) {
-	return c.StorageClient.Disks(ctx, &empty.Empty{}, callOptions...)
+	resp, err = c.StorageClient.Disks(ctx, &empty.Empty{}, callOptions...)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*storageapi.DisksResponse) //nolint: errcheck
+
+	return
+}
+
+// Partitions returns the list of partitions on the block devices.
+func (c *Client) Partitions(ctx context.Context, callOptions ...grpc.CallOption) (resp *storageapi.PartitionsResponse, err error) {
+	resp, err = c.StorageClient.Partitions(ctx, &empty.Empty{}, callOptions...)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*storageapi.PartitionsResponse) //nolint: errcheck
+
+	return
+}
+
+// StorageMounts returns the list of mounted block devices with the filesystem usage.
+func (c *Client) StorageMounts(ctx context.Context, callOptions ...grpc.CallOption) (resp *storageapi.MountsResponse, err error) {
+	resp, err = c.StorageClient.Mounts(ctx, &empty.Empty{}, callOptions...)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*storageapi.MountsResponse) //nolint: errcheck
+
+	return
+}
+
+// WatchBlockDevices streams the block device events (e.g. disks being hot-plugged).
+func (c *Client) WatchBlockDevices(ctx context.Context, callOptions ...grpc.CallOption) (storageapi.StorageService_WatchClient, error) {
+	return c.StorageClient.Watch(ctx, &empty.Empty{}, callOptions...)
+}
+
+// Grow grows the EPHEMERAL partition to the end of the disk.
+func (c *Client) Grow(ctx context.Context, callOptions ...grpc.CallOption) (resp *machineapi.GrowResponse, err error) {
+	resp, err = c.MachineClient.Grow(ctx, &empty.Empty{}, callOptions...)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.GrowResponse) //nolint: errcheck
+
+	return
+}
+
+// SequencePause pauses the sequences before their next phase.
+func (c *Client) SequencePause(ctx context.Context, callOptions ...grpc.CallOption) (resp *machineapi.SequencePauseResponse, err error) {
+	resp, err = c.MachineClient.SequencePause(ctx, &empty.Empty{}, callOptions...)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.SequencePauseResponse) //nolint: errcheck
+
+	return
+}
+
+// SequenceResume resumes the paused sequences.
+func (c *Client) SequenceResume(ctx context.Context, callOptions ...grpc.CallOption) (resp *machineapi.SequenceResumeResponse, err error) {
+	resp, err = c.MachineClient.SequenceResume(ctx, &empty.Empty{}, callOptions...)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.SequenceResumeResponse) //nolint: errcheck
+
+	return
 }
 
 // Stats implements the proto.MachineServiceClient interface.
@@ -419,6 +486,55 @@ func (c *Client) Containers(ctx context.Context, namespace string, driver common
 	return
 }
 
+// ImageList implements the proto.MachineServiceClient interface.
+func (c *Client) ImageList(ctx context.Context, namespace string, callOptions ...grpc.CallOption) (resp *machineapi.ImageListResponse, err error) {
+	resp, err = c.MachineClient.ImageList(
+		ctx,
+		&machineapi.ImageListRequest{
+			Namespace: namespace,
+		},
+		callOptions...,
+	)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.ImageListResponse) //nolint: errcheck
+
+	return
+}
+
+// ImagePull implements the proto.MachineServiceClient interface.
+func (c *Client) ImagePull(ctx context.Context, namespace, reference string, callOptions ...grpc.CallOption) (resp *machineapi.ImagePullResponse, err error) {
+	resp, err = c.MachineClient.ImagePull(
+		ctx,
+		&machineapi.ImagePullRequest{
+			Namespace: namespace,
+			Reference: reference,
+		},
+		callOptions...,
+	)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.ImagePullResponse) //nolint: errcheck
+
+	return
+}
+
+// ImageRemove implements the proto.MachineServiceClient interface.
+func (c *Client) ImageRemove(ctx context.Context, namespace, reference string, callOptions ...grpc.CallOption) (err error) {
+	resp, err := c.MachineClient.ImageRemove(ctx, &machineapi.ImageRemoveRequest{
+		Namespace: namespace,
+		Reference: reference,
+	}, callOptions...)
+
+	if err == nil {
+		_, err = FilterMessages(resp, err)
+	}
+
+	return
+}
+
 // Restart implements the proto.MachineServiceClient interface.
 func (c *Client) Restart(ctx context.Context, namespace string, driver common.ContainerDriver, id string, callOptions ...grpc.CallOption) (err error) {
 	resp, err := c.MachineClient.Restart(ctx, &machineapi.RestartRequest{
@@ -458,7 +574,12 @@ func (c *Client) ResetGeneric(ctx context.Context, req *machineapi.ResetRequest)
 
 // Reboot implements the proto.MachineServiceClient interface.
 func (c *Client) Reboot(ctx context.Context) (err error) {
-	resp, err := c.MachineClient.Reboot(ctx, &empty.Empty{})
+	return c.RebootGeneric(ctx, &machineapi.RebootRequest{})
+}
+
+// RebootGeneric implements the proto.MachineServiceClient interface.
+func (c *Client) RebootGeneric(ctx context.Context, req *machineapi.RebootRequest) (err error) {
+	resp, err := c.MachineClient.Reboot(ctx, req)
 
 	if err == nil {
 		_, err = FilterMessages(resp, err)
@@ -502,7 +623,12 @@ func (c *Client) Bootstrap(ctx context.Context) (err error) {
 
 // Shutdown implements the proto.MachineServiceClient interface.
 func (c *Client) Shutdown(ctx context.Context) (err error) {
-	resp, err := c.MachineClient.Shutdown(ctx, &empty.Empty{})
+	return c.ShutdownGeneric(ctx, &machineapi.ShutdownRequest{})
+}
+
+// ShutdownGeneric implements the proto.MachineServiceClient interface.
+func (c *Client) ShutdownGeneric(ctx context.Context, req *machineapi.ShutdownRequest) (err error) {
+	resp, err := c.MachineClient.Shutdown(ctx, req)
 
 	if err == nil {
 		_, err = FilterMessages(resp, err)
@@ -513,12 +639,17 @@ func (c *Client) Shutdown(ctx context.Context) (err error) {
 
 // Dmesg implements the proto.MachineServiceClient interface.
 func (c *Client) Dmesg(ctx context.Context, follow, tail bool) (machineapi.MachineService_DmesgClient, error) {
-	return c.MachineClient.Dmesg(ctx, &machineapi.DmesgRequest{
+	return c.DmesgGeneric(ctx, &machineapi.DmesgRequest{
 		Follow: follow,
 		Tail:   tail,
 	})
 }
 
+// DmesgGeneric implements the proto.MachineServiceClient interface.
+func (c *Client) DmesgGeneric(ctx context.Context, req *machineapi.DmesgRequest) (machineapi.MachineService_DmesgClient, error) {
+	return c.MachineClient.Dmesg(ctx, req)
+}
+
 // Logs implements the proto.MachineServiceClient interface.
 func (c *Client) Logs(ctx context.Context, namespace string, driver common.ContainerDriver, id string, follow bool, tailLines int32) (stream machineapi.MachineService_LogsClient, err error) {
 	stream, err = c.MachineClient.Logs(ctx, &machineapi.LogsRequest{
@@ -547,6 +678,51 @@ func (c *Client) Version(ctx context.Context, callOptions ...grpc.CallOption) (r
 	return
 }
 
+// BMCInfo returns the network configuration of the machine BMC.
+func (c *Client) BMCInfo(ctx context.Context, callOptions ...grpc.CallOption) (resp *machineapi.BMCInfoResponse, err error) {
+	resp, err = c.MachineClient.BMCInfo(
+		ctx,
+		&empty.Empty{},
+		callOptions...,
+	)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.BMCInfoResponse) //nolint: errcheck
+
+	return
+}
+
+// HardwareInventory returns the hardware inventory: SMBIOS data, memory modules, PCI devices and CPU topology.
+func (c *Client) HardwareInventory(ctx context.Context, callOptions ...grpc.CallOption) (resp *machineapi.HardwareInventoryResponse, err error) {
+	resp, err = c.MachineClient.HardwareInventory(
+		ctx,
+		&empty.Empty{},
+		callOptions...,
+	)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.HardwareInventoryResponse) //nolint: errcheck
+
+	return
+}
+
+// SystemInfo returns the system information: hostname, machine ID, platform, versions and hardware.
+func (c *Client) SystemInfo(ctx context.Context, callOptions ...grpc.CallOption) (resp *machineapi.SystemInfoResponse, err error) {
+	resp, err = c.MachineClient.SystemInfo(
+		ctx,
+		&empty.Empty{},
+		callOptions...,
+	)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.SystemInfoResponse) //nolint: errcheck
+
+	return
+}
+
 // Routes implements the networkdproto.NetworkClient interface.
 func (c *Client) Routes(ctx context.Context, callOptions ...grpc.CallOption) (resp *networkapi.RoutesResponse, err error) {
 	resp, err = c.NetworkClient.Routes(
@@ -562,6 +738,21 @@ func (c *Client) Routes(ctx context.Context, callOptions ...grpc.CallOption) (re
 	return
 }
 
+// NetworkStatus returns the effective hostname and resolvers, and where they came from.
+func (c *Client) NetworkStatus(ctx context.Context, callOptions ...grpc.CallOption) (resp *networkapi.StatusResponse, err error) {
+	resp, err = c.NetworkClient.Status(
+		ctx,
+		&empty.Empty{},
+		callOptions...,
+	)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*networkapi.StatusResponse) //nolint: errcheck
+
+	return
+}
+
 // Interfaces implements the proto.MachineServiceClient interface.
 func (c *Client) Interfaces(ctx context.Context, callOptions ...grpc.CallOption) (resp *networkapi.InterfacesResponse, err error) {
 	resp, err = c.NetworkClient.Interfaces(
@@ -581,7 +772,7 @@ func (c *Client) Interfaces(ctx context.Context, callOptions ...grpc.CallOption)
 func (c *Client) Processes(ctx context.Context, callOptions ...grpc.CallOption) (resp *machineapi.ProcessesResponse, err error) {
 	resp, err = c.MachineClient.Processes(
 		ctx,
-		&empty.Empty{},
+		&machineapi.ProcessesRequest{},
 		callOptions...,
 	)
 
@@ -607,6 +798,196 @@ func (c *Client) Memory(ctx context.Context, callOptions ...grpc.CallOption) (re
 	return
 }
 
+// Certificates implements the proto.MachineServiceClient interface.
+func (c *Client) Certificates(ctx context.Context, callOptions ...grpc.CallOption) (resp *machineapi.CertificatesResponse, err error) {
+	resp, err = c.MachineClient.Certificates(
+		ctx,
+		&empty.Empty{},
+		callOptions...,
+	)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.CertificatesResponse) //nolint: errcheck
+
+	return
+}
+
+// GenerateClientConfiguration implements proto.MachineServiceClient interface.
+func (c *Client) GenerateClientConfiguration(ctx context.Context, req *machineapi.GenerateClientConfigurationRequest, callOptions ...grpc.CallOption) (resp *machineapi.GenerateClientConfigurationResponse, err error) {
+	resp, err = c.MachineClient.GenerateClientConfiguration(ctx, req, callOptions...)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.GenerateClientConfigurationResponse) //nolint: errcheck
+
+	return
+}
+
+// KubernetesCredentials implements proto.MachineServiceClient interface.
+func (c *Client) KubernetesCredentials(ctx context.Context, req *machineapi.KubernetesCredentialsRequest, callOptions ...grpc.CallOption) (resp *machineapi.KubernetesCredentialsResponse, err error) {
+	resp, err = c.MachineClient.KubernetesCredentials(ctx, req, callOptions...)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.KubernetesCredentialsResponse) //nolint: errcheck
+
+	return
+}
+
+// ConfigHistory implements the proto.MachineServiceClient interface.
+func (c *Client) ConfigHistory(ctx context.Context, callOptions ...grpc.CallOption) (resp *machineapi.ConfigHistoryResponse, err error) {
+	resp, err = c.MachineClient.ConfigHistory(
+		ctx,
+		&empty.Empty{},
+		callOptions...,
+	)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.ConfigHistoryResponse) //nolint: errcheck
+
+	return
+}
+
+// ConfigRollback implements the proto.MachineServiceClient interface.
+func (c *Client) ConfigRollback(ctx context.Context, req *machineapi.ConfigRollbackRequest, callOptions ...grpc.CallOption) (resp *machineapi.ConfigRollbackResponse, err error) {
+	resp, err = c.MachineClient.ConfigRollback(ctx, req, callOptions...)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.ConfigRollbackResponse) //nolint: errcheck
+
+	return
+}
+
+// ClusterMembers implements the proto.MachineServiceClient interface.
+func (c *Client) ClusterMembers(ctx context.Context, callOptions ...grpc.CallOption) (resp *machineapi.ClusterMembersResponse, err error) {
+	resp, err = c.MachineClient.ClusterMembers(
+		ctx,
+		&empty.Empty{},
+		callOptions...,
+	)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.ClusterMembersResponse) //nolint: errcheck
+
+	return
+}
+
+// Manifests returns the sync status of the Talos-owned bootstrap manifests.
+func (c *Client) Manifests(ctx context.Context, callOptions ...grpc.CallOption) (resp *machineapi.ManifestsResponse, err error) {
+	resp, err = c.MachineClient.Manifests(
+		ctx,
+		&empty.Empty{},
+		callOptions...,
+	)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.ManifestsResponse) //nolint: errcheck
+
+	return
+}
+
+// PendingActions implements the proto.MachineServiceClient interface.
+func (c *Client) PendingActions(ctx context.Context, callOptions ...grpc.CallOption) (resp *machineapi.PendingActionsResponse, err error) {
+	resp, err = c.MachineClient.PendingActions(
+		ctx,
+		&empty.Empty{},
+		callOptions...,
+	)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.PendingActionsResponse) //nolint: errcheck
+
+	return
+}
+
+// Attest implements the proto.MachineServiceClient interface.
+func (c *Client) Attest(ctx context.Context, nonce []byte, pcrs []uint32, callOptions ...grpc.CallOption) (resp *machineapi.AttestResponse, err error) {
+	resp, err = c.MachineClient.Attest(ctx, &machineapi.AttestRequest{
+		Nonce: nonce,
+		Pcrs:  pcrs,
+	}, callOptions...)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.AttestResponse) //nolint: errcheck
+
+	return
+}
+
+// AuditLog implements the proto.MachineServiceClient interface.
+func (c *Client) AuditLog(ctx context.Context, tailEvents int32, callOptions ...grpc.CallOption) (resp *machineapi.AuditLogResponse, err error) {
+	resp, err = c.MachineClient.AuditLog(ctx, &machineapi.AuditLogRequest{
+		TailEvents: tailEvents,
+	}, callOptions...)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.AuditLogResponse) //nolint: errcheck
+
+	return
+}
+
+// MachineConfigRead implements the proto.MachineServiceClient interface.
+func (c *Client) MachineConfigRead(ctx context.Context, redacted bool, callOptions ...grpc.CallOption) (resp *machineapi.MachineConfigReadResponse, err error) {
+	resp, err = c.MachineClient.MachineConfigRead(ctx, &machineapi.MachineConfigReadRequest{
+		Redacted: redacted,
+	}, callOptions...)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.MachineConfigReadResponse) //nolint: errcheck
+
+	return
+}
+
+// MetaWrite implements the proto.MachineServiceClient interface.
+func (c *Client) MetaWrite(ctx context.Context, key uint32, value []byte, callOptions ...grpc.CallOption) (resp *machineapi.MetaWriteResponse, err error) {
+	resp, err = c.MachineClient.MetaWrite(ctx, &machineapi.MetaWriteRequest{
+		Key:   key,
+		Value: value,
+	}, callOptions...)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.MetaWriteResponse) //nolint: errcheck
+
+	return
+}
+
+// MetaDelete implements the proto.MachineServiceClient interface.
+func (c *Client) MetaDelete(ctx context.Context, key uint32, callOptions ...grpc.CallOption) (resp *machineapi.MetaDeleteResponse, err error) {
+	resp, err = c.MachineClient.MetaDelete(ctx, &machineapi.MetaDeleteRequest{
+		Key: key,
+	}, callOptions...)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.MetaDeleteResponse) //nolint: errcheck
+
+	return
+}
+
+// EtcdMaintenanceStatus implements the proto.MachineServiceClient interface.
+func (c *Client) EtcdMaintenanceStatus(ctx context.Context, callOptions ...grpc.CallOption) (resp *machineapi.EtcdMaintenanceStatusResponse, err error) {
+	resp, err = c.MachineClient.EtcdMaintenanceStatus(
+		ctx,
+		&empty.Empty{},
+		callOptions...,
+	)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.EtcdMaintenanceStatusResponse) //nolint: errcheck
+
+	return
+}
+
 // Mounts implements the proto.MachineServiceClient interface.
 func (c *Client) Mounts(ctx context.Context, callOptions ...grpc.CallOption) (resp *machineapi.MountsResponse, err error) {
 	resp, err = c.MachineClient.Mounts(
@@ -647,13 +1028,18 @@ func (c *Client) Copy(ctx context.Context, rootPath string) (io.ReadCloser, <-ch
 // Upgrade initiates a Talos upgrade ... and implements the proto.MachineServiceClient
 // interface.
 func (c *Client) Upgrade(ctx context.Context, image string, preserve, stage bool, callOptions ...grpc.CallOption) (resp *machineapi.UpgradeResponse, err error) {
+	return c.UpgradeGeneric(ctx, &machineapi.UpgradeRequest{
+		Image:    image,
+		Preserve: preserve,
+		Stage:    stage,
+	}, callOptions...)
+}
+
+// UpgradeGeneric initiates a Talos upgrade with the specified request.
+func (c *Client) UpgradeGeneric(ctx context.Context, req *machineapi.UpgradeRequest, callOptions ...grpc.CallOption) (resp *machineapi.UpgradeResponse, err error) {
 	resp, err = c.MachineClient.Upgrade(
 		ctx,
-		&machineapi.UpgradeRequest{
-			Image:    image,
-			Preserve: preserve,
-			Stage:    stage,
-		},
+		req,
 		callOptions...,
 	)
 
@@ -771,6 +1157,21 @@ func (c *Client) ServiceRestart(ctx context.Context, id string, callOptions ...g
 	return
 }
 
+// ServiceReload reloads a service configuration.
+func (c *Client) ServiceReload(ctx context.Context, id string, callOptions ...grpc.CallOption) (resp *machineapi.ServiceReloadResponse, err error) {
+	resp, err = c.MachineClient.ServiceReload(
+		ctx,
+		&machineapi.ServiceReloadRequest{Id: id},
+		callOptions...,
+	)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.ServiceReloadResponse) //nolint: errcheck
+
+	return
+}
+
 // Time returns the time.
 func (c *Client) Time(ctx context.Context, callOptions ...grpc.CallOption) (resp *timeapi.TimeResponse, err error) {
 	resp, err = c.TimeClient.Time(
@@ -803,7 +1204,14 @@ func (c *Client) TimeCheck(ctx context.Context, server string, callOptions ...gr
 
 // Read reads a file.
 func (c *Client) Read(ctx context.Context, path string) (io.ReadCloser, <-chan error, error) {
-	stream, err := c.MachineClient.Read(ctx, &machineapi.ReadRequest{Path: path})
+	return c.ReadFromOffset(ctx, path, 0)
+}
+
+// ReadFromOffset reads a file starting at the specified offset.
+//
+// It is used to resume interrupted transfers of big files.
+func (c *Client) ReadFromOffset(ctx context.Context, path string, offset int64) (io.ReadCloser, <-chan error, error) {
+	stream, err := c.MachineClient.Read(ctx, &machineapi.ReadRequest{Path: path, Offset: offset})
 	if err != nil {
 		return nil, nil, err
 	}
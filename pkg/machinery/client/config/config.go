@@ -6,12 +6,14 @@ package config
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 
+	"github.com/talos-systems/crypto/x509"
 	yaml "gopkg.in/yaml.v3"
 )
 
@@ -21,6 +23,21 @@ type Config struct {
 	Contexts map[string]*Context `yaml:"contexts"`
 }
 
+// NewConfig returns the client configuration with a single context.
+func NewConfig(contextName string, endpoints []string, caBytes []byte, client *x509.PEMEncodedCertificateAndKey) *Config {
+	return &Config{
+		Context: contextName,
+		Contexts: map[string]*Context{
+			contextName: {
+				Endpoints: endpoints,
+				CA:        base64.StdEncoding.EncodeToString(caBytes),
+				Crt:       base64.StdEncoding.EncodeToString(client.Crt),
+				Key:       base64.StdEncoding.EncodeToString(client.Key),
+			},
+		},
+	}
+}
+
 func (c *Config) upgrade() {
 	for _, ctx := range c.Contexts {
 		ctx.upgrade()
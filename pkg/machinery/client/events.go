@@ -6,12 +6,15 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"sync"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
@@ -65,15 +68,52 @@ type Event struct {
 }
 
 // EventsWatch wraps Events by providing more simple interface.
-//
-//nolint: gocyclo
 func (c *Client) EventsWatch(ctx context.Context, watchFunc func(<-chan Event), opts ...EventsOptionFunc) error {
 	stream, err := c.Events(ctx, opts...)
 	if err != nil {
 		return fmt.Errorf("error fetching events: %s", err)
 	}
 
-	if err = stream.CloseSend(); err != nil {
+	return watchEvents(ctx, stream, watchFunc, nil)
+}
+
+// ResetStream resets the node and returns the stream of the reset sequence events.
+func (c *Client) ResetStream(ctx context.Context, req *machineapi.ResetRequest) (stream machineapi.MachineService_ResetStreamClient, err error) {
+	return c.MachineClient.ResetStream(ctx, req)
+}
+
+// ResetWatch wraps ResetStream by providing the same interface as EventsWatch.
+//
+// Errors returned by the nodes are collected as NodeError. The node closes the connection when
+// it shuts down or reboots, so the watch might finish with an error even if the reset was successful.
+func (c *Client) ResetWatch(ctx context.Context, req *machineapi.ResetRequest, watchFunc func(<-chan Event)) error {
+	stream, err := c.ResetStream(ctx, req)
+	if err != nil {
+		return fmt.Errorf("error resetting: %s", err)
+	}
+
+	var multiErr *multierror.Error
+
+	if err = watchEvents(ctx, stream, watchFunc, func(nodeErr *NodeError) {
+		multiErr = multierror.Append(multiErr, nodeErr)
+	}); err != nil {
+		multiErr = multierror.Append(multiErr, err)
+	}
+
+	return multiErr.ErrorOrNil()
+}
+
+type eventsStream interface {
+	grpc.ClientStream
+	Recv() (*machineapi.Event, error)
+}
+
+// watchEvents decodes the events from the stream, errors returned by the nodes are passed to
+// nodeErrorFunc (if set).
+//
+//nolint: gocyclo
+func watchEvents(ctx context.Context, stream eventsStream, watchFunc func(<-chan Event), nodeErrorFunc func(*NodeError)) error {
+	if err := stream.CloseSend(); err != nil {
 		return err
 	}
 
@@ -104,6 +144,23 @@ func (c *Client) EventsWatch(ctx context.Context, watchFunc func(<-chan Event),
 			return fmt.Errorf("failed to watch events: %w", err)
 		}
 
+		if event.GetMetadata().GetError() != "" {
+			if nodeErrorFunc != nil {
+				rpcError := errors.New(event.GetMetadata().GetError())
+
+				if event.GetMetadata().GetStatus() != nil {
+					rpcError = status.FromProto(event.GetMetadata().GetStatus()).Err()
+				}
+
+				nodeErrorFunc(&NodeError{
+					Node: event.GetMetadata().GetHostname(),
+					Err:  rpcError,
+				})
+			}
+
+			continue
+		}
+
 		typeURL := event.GetData().GetTypeUrl()
 
 		var msg proto.Message
@@ -113,6 +170,8 @@ func (c *Client) EventsWatch(ctx context.Context, watchFunc func(<-chan Event),
 			&machineapi.PhaseEvent{},
 			&machineapi.TaskEvent{},
 			&machineapi.ServiceStateEvent{},
+			&machineapi.ConfigLoadErrorEvent{},
+			&machineapi.AuditEvent{},
 		} {
 			if typeURL == "talos/runtime/"+string(eventType.ProtoReflect().Descriptor().FullName()) {
 				msg = eventType
@@ -11,14 +11,26 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 
 	"github.com/talos-systems/talos/pkg/machinery/config"
 	"github.com/talos-systems/talos/pkg/machinery/config/decoder"
+	"github.com/talos-systems/talos/pkg/machinery/config/secretref"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
 )
 
 // newConfig initializes and returns a Configurator.
 func newConfig(source []byte) (config config.Provider, err error) {
+	// secret references are resolved by the client, they should never reach the node
+	references, err := secretref.Find(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(references) > 0 {
+		return nil, fmt.Errorf("config contains unresolved secret references: %s", strings.Join(references, ", "))
+	}
+
 	dec := decoder.NewDecoder(source)
 
 	manifests, err := dec.Decode()
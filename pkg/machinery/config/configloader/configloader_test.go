@@ -26,7 +26,12 @@ func (suite *Suite) TestNew() {
 	for _, t := range []struct {
 		source      []byte
 		errExpected bool
-	}{} {
+	}{
+		{
+			source:      []byte("version: v1alpha1\nmachine:\n  token: ref+env://MACHINE_TOKEN\n"),
+			errExpected: true,
+		},
+	} {
 		_, err := newConfig(t.source)
 
 		if t.errExpected {
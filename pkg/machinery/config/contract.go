@@ -0,0 +1,106 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// VersionContract describes Talos version to generate config for.
+//
+// Config is generated to be compatible with the specified version: older Talos versions
+// reject configs which contain unknown keys.
+type VersionContract struct {
+	Major int
+	Minor int
+}
+
+// Well-known Talos version contracts.
+var (
+	// TalosVersionCurrent is nil, which means the latest Talos version.
+	TalosVersionCurrent = (*VersionContract)(nil)
+	TalosVersion0_8     = &VersionContract{0, 8}
+	TalosVersion0_7     = &VersionContract{0, 7}
+)
+
+var versionRegexp = regexp.MustCompile(`^v?(\d+)\.(\d+)($|\.)`)
+
+// ParseContractFromVersion parses Talos version into VersionContract.
+//
+// Only major and minor version numbers are taken into account, e.g. `v0.7.1` and `0.7` parse into the same contract.
+func ParseContractFromVersion(version string) (*VersionContract, error) {
+	matches := versionRegexp.FindStringSubmatch(version)
+	if len(matches) < 3 {
+		return nil, fmt.Errorf("error parsing version %q", version)
+	}
+
+	var contract VersionContract
+
+	contract.Major, _ = strconv.Atoi(matches[1]) //nolint: errcheck
+	contract.Minor, _ = strconv.Atoi(matches[2]) //nolint: errcheck
+
+	return &contract, nil
+}
+
+// String returns string representation of the contract.
+func (contract *VersionContract) String() string {
+	if contract == nil {
+		return "current"
+	}
+
+	return fmt.Sprintf("v%d.%d", contract.Major, contract.Minor)
+}
+
+// Greater compares contract to another contract.
+//
+// Current (nil) contract is greater than any other contract.
+func (contract *VersionContract) Greater(other *VersionContract) bool {
+	if contract == nil {
+		return other != nil
+	}
+
+	if other == nil {
+		return false
+	}
+
+	return contract.Major > other.Major || (contract.Major == other.Major && contract.Minor > other.Minor)
+}
+
+// SupportsMachineFeatures returns true if version of Talos supports `machine.features` config section.
+func (contract *VersionContract) SupportsMachineFeatures() bool {
+	return contract.Greater(TalosVersion0_7)
+}
+
+// SupportsMachineLogging returns true if version of Talos supports `machine.logging` config section.
+func (contract *VersionContract) SupportsMachineLogging() bool {
+	return contract.Greater(TalosVersion0_7)
+}
+
+// SupportsJoinThrottle returns true if version of Talos supports `cluster.joinThrottle` config section.
+func (contract *VersionContract) SupportsJoinThrottle() bool {
+	return contract.Greater(TalosVersion0_7)
+}
+
+// SupportsRegistryCredentialProviders returns true if version of Talos supports registry credential providers.
+func (contract *VersionContract) SupportsRegistryCredentialProviders() bool {
+	return contract.Greater(TalosVersion0_7)
+}
+
+// SupportsClusterDiscovery returns true if version of Talos supports `cluster.discovery` config section.
+func (contract *VersionContract) SupportsClusterDiscovery() bool {
+	return contract.Greater(TalosVersion0_7)
+}
+
+// SupportsKubeletServerCertRotation returns true if version of Talos supports `machine.kubelet.serverCertRotation`.
+func (contract *VersionContract) SupportsKubeletServerCertRotation() bool {
+	return contract.Greater(TalosVersion0_7)
+}
+
+// SupportsStaticPodControlPlane returns true if version of Talos runs the control plane as static pods.
+func (contract *VersionContract) SupportsStaticPodControlPlane() bool {
+	return contract.Greater(TalosVersion0_7)
+}
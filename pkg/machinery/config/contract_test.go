@@ -0,0 +1,79 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/pkg/machinery/config"
+)
+
+func TestContractParseVersion(t *testing.T) {
+	t.Parallel()
+
+	for v, expected := range map[string]*config.VersionContract{
+		"v0.8":                      config.TalosVersion0_8,
+		"v0.8.":                     config.TalosVersion0_8,
+		"v0.8.1":                    config.TalosVersion0_8,
+		"v0.88":                     {0, 88},
+		"v0.8.3-alpha.4":            config.TalosVersion0_8,
+		"v0.8.0-alpha.2-12-g1a2b3c": config.TalosVersion0_8,
+		"0.7":                       config.TalosVersion0_7,
+		"v1.0":                      {1, 0},
+	} {
+		v, expected := v, expected
+
+		t.Run(v, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := config.ParseContractFromVersion(v)
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+	}
+
+	for _, v := range []string{"", "latest", "v0", "v0.x"} {
+		_, err := config.ParseContractFromVersion(v)
+		assert.Error(t, err, v)
+	}
+}
+
+func TestContractGreater(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, config.TalosVersion0_8.Greater(config.TalosVersion0_7))
+	assert.True(t, config.TalosVersionCurrent.Greater(config.TalosVersion0_8))
+	assert.True(t, (&config.VersionContract{1, 0}).Greater(config.TalosVersion0_8))
+
+	assert.False(t, config.TalosVersion0_7.Greater(config.TalosVersion0_8))
+	assert.False(t, config.TalosVersion0_7.Greater(config.TalosVersion0_7))
+	assert.False(t, config.TalosVersion0_8.Greater(config.TalosVersionCurrent))
+	assert.False(t, config.TalosVersionCurrent.Greater(config.TalosVersionCurrent))
+}
+
+func TestContractFeatures(t *testing.T) {
+	t.Parallel()
+
+	for _, contract := range []*config.VersionContract{config.TalosVersionCurrent, config.TalosVersion0_8} {
+		assert.True(t, contract.SupportsMachineFeatures())
+		assert.True(t, contract.SupportsMachineLogging())
+		assert.True(t, contract.SupportsJoinThrottle())
+		assert.True(t, contract.SupportsRegistryCredentialProviders())
+		assert.True(t, contract.SupportsClusterDiscovery())
+		assert.True(t, contract.SupportsStaticPodControlPlane())
+		assert.True(t, contract.SupportsKubeletServerCertRotation())
+	}
+
+	assert.False(t, config.TalosVersion0_7.SupportsMachineFeatures())
+	assert.False(t, config.TalosVersion0_7.SupportsMachineLogging())
+	assert.False(t, config.TalosVersion0_7.SupportsJoinThrottle())
+	assert.False(t, config.TalosVersion0_7.SupportsRegistryCredentialProviders())
+	assert.False(t, config.TalosVersion0_7.SupportsClusterDiscovery())
+	assert.False(t, config.TalosVersion0_7.SupportsStaticPodControlPlane())
+	assert.False(t, config.TalosVersion0_7.SupportsKubeletServerCertRotation())
+}
@@ -160,7 +160,7 @@ func addComments(node *yaml.Node, doc *Doc, comments ...int) {
 }
 
 // nolint:gocyclo
-func renderExample(key string, doc *Doc) string {
+func renderExample(key string, doc *Doc, options *Options) string {
 	if doc == nil {
 		return ""
 	}
@@ -181,19 +181,19 @@ func renderExample(key string, doc *Doc) string {
 		defaultValue := v.Interface()
 		populateExamples(defaultValue, i)
 
-		node, err := toYamlNode(defaultValue)
+		node, err := toYamlNode(defaultValue, options)
 		if err != nil {
 			continue
 		}
 
 		node, err = toYamlNode(map[string]*yaml.Node{
 			key: node,
-		})
+		}, options)
 		if err != nil {
 			continue
 		}
 
-		if i == 0 {
+		if i == 0 && options.Comments.enabled(CommentsDocs) {
 			addComments(node, doc, HeadComment, LineComment)
 		}
 
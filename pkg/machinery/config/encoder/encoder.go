@@ -14,24 +14,28 @@ import (
 
 // Encoder implements config encoder.
 type Encoder struct {
-	value interface{}
+	value   interface{}
+	options *Options
 }
 
 // NewEncoder initializes and returns an `Encoder`.
-func NewEncoder(value interface{}) *Encoder {
+func NewEncoder(value interface{}, opts ...Option) *Encoder {
 	return &Encoder{
-		value: value,
+		value:   value,
+		options: newOptions(opts...),
 	}
 }
 
 // Encode convert value to yaml.
 func (e *Encoder) Encode() ([]byte, error) {
-	node, err := toYamlNode(e.value)
+	node, err := toYamlNode(e.value, e.options)
 	if err != nil {
 		return nil, err
 	}
 
-	addComments(node, getDoc(e.value), HeadComment, LineComment)
+	if e.options.Comments.enabled(CommentsDocs) {
+		addComments(node, getDoc(e.value), HeadComment, LineComment)
+	}
 
 	// special handling for case when we get an empty output
 	if node.Kind == yaml.MappingNode && len(node.Content) == 0 && node.FootComment != "" {
@@ -75,7 +79,7 @@ func isSet(value reflect.Value) bool {
 }
 
 //nolint:gocyclo
-func toYamlNode(in interface{}) (*yaml.Node, error) {
+func toYamlNode(in interface{}, options *Options) (*yaml.Node, error) {
 	node := &yaml.Node{}
 
 	// do not wrap yaml.Node into yaml.Node
@@ -174,10 +178,14 @@ func toYamlNode(in interface{}) (*yaml.Node, error) {
 			}
 
 			if !defined {
-				example := renderExample(fieldName, fieldDoc)
+				example := renderExample(fieldName, fieldDoc, options)
 
 				if example != "" {
-					examples = append(examples, example)
+					// fields with examples are never rendered with the zero value, even if examples are disabled
+					if options.Comments.enabled(CommentsExamples) {
+						examples = append(examples, example)
+					}
+
 					skip = true
 				}
 			}
@@ -189,7 +197,7 @@ func toYamlNode(in interface{}) (*yaml.Node, error) {
 
 			if !skip {
 				if inline {
-					child, err := toYamlNode(value)
+					child, err := toYamlNode(value, options)
 					if err != nil {
 						return nil, err
 					}
@@ -197,7 +205,7 @@ func toYamlNode(in interface{}) (*yaml.Node, error) {
 					if child.Kind == yaml.MappingNode || child.Kind == yaml.SequenceNode {
 						appendNodes(node, child.Content...)
 					}
-				} else if err := addToMap(node, fieldDoc, fieldName, value, style); err != nil {
+				} else if err := addToMap(node, fieldDoc, fieldName, value, style, options); err != nil {
 					return nil, err
 				}
 			}
@@ -225,7 +233,7 @@ func toYamlNode(in interface{}) (*yaml.Node, error) {
 			element := v.MapIndex(k)
 			value := element.Interface()
 
-			if err := addToMap(node, nil, k.Interface(), value, 0); err != nil {
+			if err := addToMap(node, nil, k.Interface(), value, 0, options); err != nil {
 				return nil, err
 			}
 		}
@@ -238,7 +246,7 @@ func toYamlNode(in interface{}) (*yaml.Node, error) {
 
 			var err error
 
-			nodes[i], err = toYamlNode(element.Interface())
+			nodes[i], err = toYamlNode(element.Interface(), options)
 			if err != nil {
 				return nil, err
 			}
@@ -261,21 +269,23 @@ func appendNodes(dest *yaml.Node, nodes ...*yaml.Node) {
 	dest.Content = append(dest.Content, nodes...)
 }
 
-func addToMap(dest *yaml.Node, doc *Doc, fieldName, in interface{}, style yaml.Style) error {
-	key, err := toYamlNode(fieldName)
+func addToMap(dest *yaml.Node, doc *Doc, fieldName, in interface{}, style yaml.Style, options *Options) error {
+	key, err := toYamlNode(fieldName, options)
 	if err != nil {
 		return err
 	}
 
-	value, err := toYamlNode(in)
+	value, err := toYamlNode(in, options)
 	if err != nil {
 		return err
 	}
 
 	value.Style = style
 
-	addComments(key, doc, HeadComment, FootComment)
-	addComments(value, doc, LineComment)
+	if options.Comments.enabled(CommentsDocs) {
+		addComments(key, doc, HeadComment, FootComment)
+		addComments(value, doc, LineComment)
+	}
 
 	// override head comment with line comment for non-scalar nodes
 	if value.Kind != yaml.ScalarNode {
@@ -165,6 +165,7 @@ func (suite *EncoderSuite) TestRun() {
 		value        interface{}
 		expectedYAML string
 		incompatible bool
+		options      []encoder.Option
 	}{
 		{
 			name:  "default struct",
@@ -332,10 +333,55 @@ mixed_in: a # was inlined
 `,
 			incompatible: true,
 		},
+		{
+			name: "comments disabled",
+			value: &FakeConfig{
+				Machine{
+					State: 1000,
+				},
+			},
+			expectedYAML: `machine:
+    state: 1000
+`,
+			incompatible: true,
+			options:      []encoder.Option{encoder.WithComments(encoder.CommentsDisabled)},
+		},
+		{
+			name: "examples without docs",
+			value: &FakeConfig{
+				Machine{
+					State: 1000,
+				},
+			},
+			expectedYAML: `machine:
+    state: 1000
+    ` + `
+    # config:
+    #     version: 0.0.2
+    #     capabilities:
+    #         - reboot
+    #         - upgrade
+`,
+			incompatible: true,
+			options:      []encoder.Option{encoder.WithComments(encoder.CommentsExamples)},
+		},
+		{
+			name: "docs without examples",
+			value: &FakeConfig{
+				Machine{
+					State: 1000,
+				},
+			},
+			expectedYAML: `machine:
+    state: 1000
+`,
+			incompatible: true,
+			options:      []encoder.Option{encoder.WithComments(encoder.CommentsDocs)},
+		},
 	}
 
 	for _, test := range tests {
-		encoder := encoder.NewEncoder(test.value)
+		encoder := encoder.NewEncoder(test.value, test.options...)
 		data, err := encoder.Encode()
 		suite.Assert().NoError(err)
 
@@ -0,0 +1,50 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package encoder
+
+// CommentsFlags selects the comments rendered by the encoder.
+type CommentsFlags int
+
+const (
+	// CommentsDisabled renders no comments.
+	CommentsDisabled CommentsFlags = 0
+	// CommentsDocs renders documentation comments.
+	CommentsDocs CommentsFlags = 1 << 0
+	// CommentsExamples renders commented out examples for the fields which are not set.
+	CommentsExamples CommentsFlags = 1 << 1
+	// CommentsAll renders all comments.
+	CommentsAll = CommentsDocs | CommentsExamples
+)
+
+func (f CommentsFlags) enabled(flag CommentsFlags) bool {
+	return f&flag == flag
+}
+
+// Options defines encoder options.
+type Options struct {
+	Comments CommentsFlags
+}
+
+// Option gives ability to alter encoder options.
+type Option func(*Options)
+
+// WithComments sets the comments rendered by the encoder.
+func WithComments(flags CommentsFlags) Option {
+	return func(o *Options) {
+		o.Comments = flags
+	}
+}
+
+func newOptions(opts ...Option) *Options {
+	res := &Options{
+		Comments: CommentsAll,
+	}
+
+	for _, o := range opts {
+		o(res)
+	}
+
+	return res
+}
@@ -14,6 +14,7 @@ import (
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/talos-systems/crypto/x509"
 
+	"github.com/talos-systems/talos/pkg/machinery/config/encoder"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
 )
 
@@ -21,6 +22,7 @@ import (
 type Provider interface {
 	Version() string
 	Debug() bool
+	// Deprecated: machine config is always persisted.
 	Persist() bool
 	Machine() MachineConfig
 	Cluster() ClusterConfig
@@ -28,6 +30,8 @@ type Provider interface {
 	ApplyDynamicConfig(context.Context, DynamicConfigProvider) error
 	String() (string, error)
 	Bytes() ([]byte, error)
+	EncodeString(encoderOptions ...encoder.Option) (string, error)
+	EncodeBytes(encoderOptions ...encoder.Option) ([]byte, error)
 }
 
 // MachineConfig defines the requirements for a config that pertains to machine
@@ -37,6 +41,9 @@ type MachineConfig interface {
 	Security() Security
 	Network() MachineNetwork
 	Disks() []Disk
+	RAIDs() []RAID
+	VolumeGroups() []VolumeGroup
+	EphemeralQuotas() []EphemeralQuota
 	Time() Time
 	Env() Env
 	Files() ([]File, error)
@@ -44,6 +51,55 @@ type MachineConfig interface {
 	Kubelet() Kubelet
 	Sysctls() map[string]string
 	Registries() Registries
+	Logging() Logging
+	Features() Features
+	BMC() BMC
+	SystemDiskEncryption() SystemDiskEncryption
+	Kernel() Kernel
+	CRI() CRI
+	ExtensionServices() []ExtensionService
+	ServiceResources(string) ServiceResources
+	Proxy() MachineProxy
+}
+
+// MachineProxy defines the requirements for a config that pertains to the HTTP proxy settings.
+type MachineProxy interface {
+	HTTPProxy() string
+	HTTPSProxy() string
+	NoProxy() []string
+	AutoNoProxy() bool
+}
+
+// ServiceResources defines the requirements for a config that pertains to
+// the resource limits of a system service.
+type ServiceResources interface {
+	CPUShares() uint64
+	CPULimit() float64
+	MemoryLimit() uint64
+	MemoryReservation() uint64
+	OOMScoreAdj() int
+}
+
+// ExtensionService defines the requirements for a config that pertains to
+// the operator-supplied system service.
+type ExtensionService interface {
+	Name() string
+	Image() string
+	Args() []string
+	Env() map[string]string
+	Mounts() []specs.Mount
+	Restart() string
+	DependsOn() []string
+	Security() ExtensionServiceSecurity
+}
+
+// ExtensionServiceSecurity defines the requirements for a config that
+// pertains to the extension service container security settings.
+type ExtensionServiceSecurity interface {
+	Privileged() bool
+	HostNetwork() bool
+	Capabilities() []string
+	WritableRootfs() bool
 }
 
 // Disk represents the options available for partitioning, formatting, and
@@ -57,6 +113,39 @@ type Disk interface {
 type Partition interface {
 	Size() uint64
 	MountPoint() string
+	Filesystem() string
+	MountOptions() []string
+	Label() string
+	Grow() bool
+}
+
+// RAID represents the options for assembling the md-RAID array.
+type RAID interface {
+	Name() string
+	Level() string
+	Devices() []string
+}
+
+// VolumeGroup represents the options for the LVM volume group.
+type VolumeGroup interface {
+	Name() string
+	PhysicalVolumes() []string
+	LogicalVolumes() []LogicalVolume
+}
+
+// LogicalVolume represents the options for the LVM logical volume.
+type LogicalVolume interface {
+	Name() string
+	Size() uint64
+	Filesystem() string
+	MountPoint() string
+	MountOptions() []string
+}
+
+// EphemeralQuota represents the disk usage limit for the directory on the EPHEMERAL partition.
+type EphemeralQuota interface {
+	Path() string
+	Limit() uint64
 }
 
 // Env represents a set of environment variables.
@@ -65,11 +154,20 @@ type Env = map[string]string
 // File represents a file to write to disk.
 type File interface {
 	Content() string
+	ContentFrom() FileContentSource
 	Permissions() os.FileMode
 	Path() string
 	Op() string
 }
 
+// FileContentSource defines the requirements for a config that pertains to the
+// remote source of the file contents.
+type FileContentSource interface {
+	URL() string
+	SHA256() string
+	Headers() map[string]string
+}
+
 // Install defines the requirements for a config that pertains to install
 // related options.
 type Install interface {
@@ -78,6 +176,18 @@ type Install interface {
 	ExtraKernelArgs() []string
 	Zero() bool
 	WithBootloader() bool
+	EphemeralDisk() string
+	EphemeralSize() uint64
+	Console() InstallConsole
+	EnrollSecureBootKeys() bool
+}
+
+// InstallConsole defines the requirements for a config that pertains to console output
+// of the installed system.
+type InstallConsole interface {
+	Serial() string
+	BaudRate() int
+	DisableVGA() bool
 }
 
 // Security defines the requirements for a config that pertains to security
@@ -182,6 +292,44 @@ type Kubelet interface {
 	Image() string
 	ExtraArgs() map[string]string
 	ExtraMounts() []specs.Mount
+	DrainTimeout() time.Duration
+	CredentialProviders() []KubeletCredentialProvider
+	NodeIP() KubeletNodeIP
+	ServerCertRotation() bool
+	SystemReserved() map[string]string
+	KubeReserved() map[string]string
+	CPUManagerPolicy() string
+	ReservedCPUs() string
+	TopologyManagerPolicy() string
+	TopologyManagerScope() string
+	MemoryManager() KubeletMemoryManager
+}
+
+// KubeletMemoryManager defines the kubelet memory manager configuration.
+type KubeletMemoryManager interface {
+	Policy() string
+	ReservedMemory() []KubeletReservedMemory
+}
+
+// KubeletReservedMemory defines the memory reserved on a NUMA node.
+type KubeletReservedMemory interface {
+	NUMANode() uint32
+	Memory() string
+}
+
+// KubeletNodeIP defines the way the kubelet node IP is selected.
+type KubeletNodeIP interface {
+	ValidSubnets() []string
+}
+
+// KubeletCredentialProvider represents the kubelet exec credential provider plugin.
+type KubeletCredentialProvider interface {
+	Name() string
+	MatchImages() []string
+	DefaultCacheDuration() time.Duration
+	APIVersion() string
+	Args() []string
+	Env() Env
 }
 
 // Registries defines the configuration for image fetching.
@@ -209,6 +357,14 @@ type RegistryAuthConfig interface {
 	Password() string
 	Auth() string
 	IdentityToken() string
+	Provider() RegistryCredentialProvider
+}
+
+// RegistryCredentialProvider specifies an external command which provides registry credentials.
+type RegistryCredentialProvider interface {
+	Exec() string
+	Args() []string
+	Env() map[string]string
 }
 
 // RegistryTLSConfig specifies TLS config for HTTPS registries.
@@ -219,6 +375,185 @@ type RegistryTLSConfig interface {
 	GetTLSConfig() (*tls.Config, error)
 }
 
+// Logging defines the requirements for a config that pertains to logging
+// related options.
+type Logging interface {
+	Console() ConsoleLogging
+	Persistence() LogPersistence
+}
+
+// LogPersistence defines the requirements for a config that pertains to
+// persistent service logs.
+type LogPersistence interface {
+	Enabled() bool
+	MaxSize() int64
+	MaxFiles() int
+}
+
+// ConsoleLogging defines the requirements for a config that pertains to
+// console output.
+type ConsoleLogging interface {
+	Mode() string
+	Device() string
+	RateLimit() int
+}
+
+// Kernel defines the requirements for a config that pertains to the kernel
+// related options.
+type Kernel interface {
+	Modules() []KernelModule
+	IsolatedCPUs() string
+	NohzFullCPUs() string
+	IRQAffinityCPUs() string
+}
+
+// KernelModule defines the requirements for a config that pertains to the
+// kernel module loaded on boot.
+type KernelModule interface {
+	Name() string
+	Parameters() []string
+}
+
+// CRI defines the requirements for a config that pertains to the CRI
+// plugin of containerd.
+type CRI interface {
+	Runtimes() map[string]CRIRuntime
+}
+
+// CRIRuntime defines the requirements for a config that pertains to the
+// CRI runtime handler.
+type CRIRuntime interface {
+	RuntimeType() string
+	BinaryName() string
+}
+
+// BMC defines the requirements for a config that pertains to the BMC
+// network settings.
+type BMC interface {
+	Configured() bool
+	Channel() uint8
+	DHCP() bool
+	Address() string
+	Gateway() string
+}
+
+// SystemDiskEncryption defines the requirements for a config that pertains to
+// the system partitions encryption.
+type SystemDiskEncryption interface {
+	Get(label string) Encryption
+}
+
+// Encryption defines the requirements for a config that pertains to the
+// encryption of a system partition.
+type Encryption interface {
+	Keys() []EncryptionKey
+}
+
+// EncryptionKey defines the requirements for a config that pertains to the
+// key slot of an encrypted partition.
+type EncryptionKey interface {
+	Slot() int
+	TPM() EncryptionKeyTPM
+}
+
+// EncryptionKeyTPM defines the requirements for a config that pertains to the
+// key sealed to the TPM.
+type EncryptionKeyTPM interface {
+	PCRs() []int
+}
+
+// Features defines the requirements for a config that pertains to optional
+// Talos features.
+type Features interface {
+	RBAC() RBAC
+	ImageVerification() ImageVerification
+	ConfigStorage() ConfigStorage
+	ManagementTunnel() ManagementTunnel
+	ISCSI() ISCSI
+	NFSMounts() NFSMounts
+	APIAuthorizationPolicy() APIAuthorizationPolicy
+	LocalAPIAccess() LocalAPIAccess
+	KubernetesTalosAPIAccess() KubernetesTalosAPIAccess
+}
+
+// KubernetesTalosAPIAccess defines the requirements for a config that pertains
+// to the Talos API access from Kubernetes service accounts.
+type KubernetesTalosAPIAccess interface {
+	Enabled() bool
+	AllowedRoles() []string
+	AllowedKubernetesNamespaces() []string
+}
+
+// LocalAPIAccess defines the requirements for a config that pertains to the
+// local Talos API socket.
+type LocalAPIAccess interface {
+	Enabled() bool
+	GroupID() int
+}
+
+// APIAuthorizationPolicy defines the requirements for a config that pertains
+// to the Talos API authorization policy.
+type APIAuthorizationPolicy interface {
+	Rules() []APIAuthorizationPolicyRule
+}
+
+// APIAuthorizationPolicyRule represents a single Talos API authorization
+// policy rule.
+type APIAuthorizationPolicyRule interface {
+	Methods() []string
+	AllowedSubjects() []string
+	AllowedRoles() []string
+}
+
+// NFSMounts defines the requirements for a config that pertains to the NFS
+// client.
+type NFSMounts interface {
+	Enabled() bool
+	IdmapdDomain() string
+	DefaultVersion() string
+}
+
+// ISCSI defines the requirements for a config that pertains to the iSCSI
+// initiator.
+type ISCSI interface {
+	Enabled() bool
+	InitiatorName() string
+}
+
+// ManagementTunnel defines the requirements for a config that pertains to
+// the management tunnel.
+type ManagementTunnel interface {
+	APIURL() string
+}
+
+// ConfigStorage defines the requirements for a config that pertains to
+// machine configuration storage.
+type ConfigStorage interface {
+	Backend() string
+	URL() string
+}
+
+// RBAC defines the requirements for a config that pertains to Talos API
+// access control.
+type RBAC interface {
+	Rules() []RBACRule
+}
+
+// RBACRule represents a single Talos API access rule.
+type RBACRule interface {
+	Roles() []string
+	Methods() []string
+	Nodes() []string
+}
+
+// ImageVerification defines the requirements for a config that pertains to
+// container image signature verification.
+type ImageVerification interface {
+	Enabled() bool
+	PublicKeys() []string
+	Images() []string
+}
+
 // ClusterConfig defines the requirements for a config that pertains to cluster
 // related options.
 type ClusterConfig interface {
@@ -231,6 +566,8 @@ type ClusterConfig interface {
 	Token() Token
 	CertSANs() []string
 	CA() *x509.PEMEncodedCertificateAndKey
+	AggregatorCA() *x509.PEMEncodedCertificateAndKey
+	ServiceAccountKey() []byte
 	AESCBCEncryptionSecret() string
 	Config(machine.Type) (string, error)
 	Etcd() Etcd
@@ -238,10 +575,54 @@ type ClusterConfig interface {
 	LocalAPIServerPort() int
 	PodCheckpointer() PodCheckpointer
 	CoreDNS() CoreDNS
+	ExternalCloudProvider() ExternalCloudProvider
 	ExtraManifestURLs() []string
 	ExtraManifestHeaderMap() map[string]string
+	ExtraManifestChecksumMap() map[string]string
+	ExtraManifestTemplates() bool
+	ExtraManifestValueMap() map[string]string
 	AdminKubeconfig() AdminKubeconfig
 	ScheduleOnMasters() bool
+	JoinThrottle() JoinThrottle
+	Discovery() Discovery
+}
+
+// JoinThrottle defines the requirements for a config that pertains to
+// throttling of the machines joining the cluster.
+type JoinThrottle interface {
+	MaxConcurrentRequests() int
+	MaxQueuedRequests() int
+	RetryInterval() time.Duration
+	MaxRetryInterval() time.Duration
+	RetryJitter() time.Duration
+	RetryTimeout() time.Duration
+}
+
+// Discovery defines the requirements for a config that pertains to cluster
+// membership discovery.
+type Discovery interface {
+	Enabled() bool
+	Registries() DiscoveryRegistries
+}
+
+// DiscoveryRegistries defines the requirements for a config that pertains to
+// the registries used for cluster membership discovery.
+type DiscoveryRegistries interface {
+	Kubernetes() KubernetesRegistry
+	Service() ServiceRegistry
+}
+
+// KubernetesRegistry defines the requirements for a config that pertains to
+// the Kubernetes discovery registry.
+type KubernetesRegistry interface {
+	Enabled() bool
+}
+
+// ServiceRegistry defines the requirements for a config that pertains to
+// the discovery service registry.
+type ServiceRegistry interface {
+	Enabled() bool
+	Endpoint() string
 }
 
 // ClusterNetwork defines the requirements for a config that pertains to cluster
@@ -258,6 +639,25 @@ type ClusterNetwork interface {
 type CNI interface {
 	Name() string
 	URLs() []string
+	Flannel() FlannelCNI
+}
+
+// FlannelCNI defines the settings of the Talos-managed Flannel CNI.
+type FlannelCNI interface {
+	Backend() string
+	MTU() int
+	Interfaces() []string
+	InterfaceRegexps() []string
+	ExtraArgs() map[string]string
+	Image() string
+}
+
+// ExternalCloudProvider defines settings for external cloud provider.
+type ExternalCloudProvider interface {
+	// Enabled returns true if external cloud provider is enabled.
+	Enabled() bool
+	// ManifestURLs returns external cloud provider manifest URLs if it is enabled.
+	ManifestURLs() []string
 }
 
 // APIServer defines the requirements for a config that pertains to apiserver related
@@ -265,6 +665,11 @@ type CNI interface {
 type APIServer interface {
 	Image() string
 	ExtraArgs() map[string]string
+	DisablePodSecurityPolicy() bool
+	// ServiceAccountIssuer returns the service account issuer URL, empty value means the control plane endpoint.
+	ServiceAccountIssuer() string
+	ServiceAccountJWKSURI() string
+	ServiceAccountExtraAudiences() []string
 }
 
 // ControllerManager defines the requirements for a config that pertains to controller manager related
@@ -272,11 +677,15 @@ type APIServer interface {
 type ControllerManager interface {
 	Image() string
 	ExtraArgs() map[string]string
+	ExtraConfig() map[string]string
 }
 
 // Proxy defines the requirements for a config that pertains to the kube-proxy
 // options.
 type Proxy interface {
+	// Enabled indicates whether kube-proxy should be deployed.
+	Enabled() bool
+
 	Image() string
 
 	// Mode indicates the proxy mode for kube-proxy.  By default, this is `iptables`.  Other options include `ipvs`.
@@ -291,6 +700,7 @@ type Proxy interface {
 type Scheduler interface {
 	Image() string
 	ExtraArgs() map[string]string
+	Config() map[string]interface{}
 }
 
 // Etcd defines the requirements for a config that pertains to etcd related
@@ -299,6 +709,18 @@ type Etcd interface {
 	Image() string
 	CA() *x509.PEMEncodedCertificateAndKey
 	ExtraArgs() map[string]string
+	AdvertisedSubnets() []string
+	ListenSubnets() []string
+	Maintenance() EtcdMaintenance
+}
+
+// EtcdMaintenance defines the requirements for a config that pertains to the periodic
+// etcd maintenance: defragmentation and alarm management.
+type EtcdMaintenance interface {
+	Enabled() bool
+	Interval() time.Duration
+	MinDBSize() uint64
+	FragmentationThreshold() int
 }
 
 // Token defines the requirements for a config that pertains to Kubernetes
@@ -317,7 +739,18 @@ type PodCheckpointer interface {
 // CoreDNS defines the requirements for a config that pertains to bootkube
 // coredns options.
 type CoreDNS interface {
+	Enabled() bool
 	Image() string
+	Replicas() int
+	ServiceIPs() []string
+	ForwardZones() []CoreDNSForwardZone
+	ExtraCorefile() string
+}
+
+// CoreDNSForwardZone defines the zone forwarded to the specific upstream DNS servers.
+type CoreDNSForwardZone interface {
+	Zone() string
+	Upstreams() []string
 }
 
 // AdminKubeconfig defines settings for admin kubeconfig.
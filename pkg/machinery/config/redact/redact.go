@@ -0,0 +1,127 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package redact strips the secrets from the machine configuration.
+package redact
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted replaces the values of the secret fields.
+const Redacted = "******"
+
+// SecretFields are the paths of the machine configuration fields which hold the secrets.
+//
+// Path elements are separated with dots, `*` matches any map key or list item.
+var SecretFields = []string{
+	"machine.token",
+	"machine.ca.key",
+	"machine.registries.config.*.auth.password",
+	"machine.registries.config.*.auth.auth",
+	"machine.registries.config.*.auth.identityToken",
+	"machine.registries.config.*.tls.clientIdentity.key",
+	"cluster.token",
+	"cluster.aescbcEncryptionSecret",
+	"cluster.ca.key",
+	"cluster.aggregatorCA.key",
+	"cluster.serviceAccount.key",
+	"cluster.etcd.ca.key",
+}
+
+// Redact replaces the values of the secret fields in the machine configuration with Redacted.
+//
+// Empty values are kept as is, so that the redacted configuration shows which secrets are set.
+func Redact(source []byte) ([]byte, error) {
+	patterns := make([][]string, len(SecretFields))
+
+	for i, field := range SecretFields {
+		patterns[i] = strings.Split(field, ".")
+	}
+
+	var buf bytes.Buffer
+
+	dec := yaml.NewDecoder(bytes.NewReader(source))
+	enc := yaml.NewEncoder(&buf)
+
+	for {
+		var doc yaml.Node
+
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, fmt.Errorf("decode error: %w", err)
+		}
+
+		redact(&doc, nil, patterns)
+
+		if err := enc.Encode(&doc); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func redact(node *yaml.Node, path []string, patterns [][]string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			redact(child, path, patterns)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			redact(node.Content[i+1], append(path[:len(path):len(path)], node.Content[i].Value), patterns)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			redact(child, append(path[:len(path):len(path)], fmt.Sprint(i)), patterns)
+		}
+	case yaml.ScalarNode:
+		if node.Value == "" || node.Tag == "!!null" || !match(path, patterns) {
+			return
+		}
+
+		node.Value = Redacted
+		node.Tag = "!!str"
+		node.Style = 0
+	case yaml.AliasNode:
+	}
+}
+
+func match(path []string, patterns [][]string) bool {
+	for _, pattern := range patterns {
+		if len(pattern) != len(path) {
+			continue
+		}
+
+		matched := true
+
+		for i := range pattern {
+			if pattern[i] != "*" && pattern[i] != path[i] {
+				matched = false
+
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
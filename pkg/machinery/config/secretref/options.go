@@ -0,0 +1,67 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secretref
+
+import (
+	"os"
+	"os/exec"
+)
+
+// Options defines resolver options.
+type Options struct {
+	// BaseDir is the directory relative SOPS file paths are resolved against.
+	BaseDir string
+	// LookupEnv looks up the environment variable.
+	LookupEnv func(name string) (string, bool)
+	// Decrypt returns the decrypted contents of the SOPS file.
+	Decrypt func(path string) ([]byte, error)
+}
+
+// Option gives ability to alter resolver options.
+type Option func(*Options)
+
+// WithBaseDir sets the directory relative SOPS file paths are resolved against.
+func WithBaseDir(dir string) Option {
+	return func(o *Options) {
+		o.BaseDir = dir
+	}
+}
+
+// WithLookupEnv overrides the environment variable lookup.
+func WithLookupEnv(f func(name string) (string, bool)) Option {
+	return func(o *Options) {
+		o.LookupEnv = f
+	}
+}
+
+// WithDecrypt overrides the SOPS file decryption.
+func WithDecrypt(f func(path string) ([]byte, error)) Option {
+	return func(o *Options) {
+		o.Decrypt = f
+	}
+}
+
+func newOptions(opts ...Option) *Options {
+	res := &Options{
+		BaseDir:   ".",
+		LookupEnv: os.LookupEnv,
+		Decrypt:   sopsDecrypt,
+	}
+
+	for _, o := range opts {
+		o(res)
+	}
+
+	return res
+}
+
+// sopsDecrypt decrypts the file with the sops binary, which picks up the keys (PGP, age, cloud KMS)
+// the same way as when the file is edited.
+func sopsDecrypt(path string) ([]byte, error) {
+	cmd := exec.Command("sops", "--decrypt", path)
+	cmd.Stderr = os.Stderr
+
+	return cmd.Output()
+}
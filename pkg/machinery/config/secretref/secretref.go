@@ -0,0 +1,291 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package secretref resolves references to external secrets in the machine configuration.
+//
+// Any string value of the configuration can be replaced with a reference, so that the configuration
+// can be kept in git without the secrets:
+//
+//     ref+env://TALOS_CLUSTER_TOKEN                    value of the environment variable
+//     ref+sops://secrets.yaml#/cluster/token           value from the SOPS-encrypted document
+//     ref+sops://ca.key.enc                            contents of the SOPS-encrypted file
+//
+// References are resolved by the client (talosctl) before the configuration is sent to the node,
+// the node rejects the configuration with unresolved references.
+package secretref
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Reference prefixes.
+const (
+	EnvPrefix  = "ref+env://"
+	SOPSPrefix = "ref+sops://"
+)
+
+// IsReference checks whether the value is a secret reference.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, EnvPrefix) || strings.HasPrefix(value, SOPSPrefix)
+}
+
+// Find returns the paths of the values which are secret references.
+func Find(source []byte) ([]string, error) {
+	docs, err := decode(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+
+	for _, doc := range docs {
+		if err = walk(doc, "", func(path string, node *yaml.Node) error {
+			paths = append(paths, path)
+
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return paths, nil
+}
+
+// Resolve replaces the secret references in the configuration with the secret values.
+//
+// Configuration without references is returned as is.
+func Resolve(source []byte, opts ...Option) ([]byte, error) {
+	options := newOptions(opts...)
+
+	docs, err := decode(source)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &resolver{
+		options:   options,
+		decrypted: map[string][]byte{},
+	}
+
+	found := false
+
+	for _, doc := range docs {
+		if err = walk(doc, "", func(path string, node *yaml.Node) error {
+			found = true
+
+			value, e := r.resolve(node.Value)
+			if e != nil {
+				return fmt.Errorf("error resolving %q: %w", path, e)
+			}
+
+			node.Value = value
+			node.Tag = "!!str"
+
+			if strings.Contains(value, "\n") {
+				node.Style = yaml.LiteralStyle
+			} else {
+				node.Style = 0
+			}
+
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if !found {
+		return source, nil
+	}
+
+	var buf bytes.Buffer
+
+	enc := yaml.NewEncoder(&buf)
+
+	for _, doc := range docs {
+		if err = enc.Encode(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+type resolver struct {
+	options *Options
+
+	decrypted map[string][]byte
+}
+
+func (r *resolver) resolve(ref string) (string, error) {
+	switch {
+	case strings.HasPrefix(ref, EnvPrefix):
+		name := strings.TrimPrefix(ref, EnvPrefix)
+
+		if name == "" {
+			return "", errors.New("environment variable name is empty")
+		}
+
+		value, ok := r.options.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %q is not set", name)
+		}
+
+		return value, nil
+	case strings.HasPrefix(ref, SOPSPrefix):
+		path := strings.TrimPrefix(ref, SOPSPrefix)
+
+		var pointer string
+
+		if idx := strings.Index(path, "#"); idx != -1 {
+			path, pointer = path[:idx], path[idx+1:]
+		}
+
+		if path == "" {
+			return "", errors.New("SOPS file path is empty")
+		}
+
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(r.options.BaseDir, path)
+		}
+
+		contents, ok := r.decrypted[path]
+		if !ok {
+			var err error
+
+			contents, err = r.options.Decrypt(path)
+			if err != nil {
+				return "", fmt.Errorf("error decrypting %q: %w", path, err)
+			}
+
+			r.decrypted[path] = contents
+		}
+
+		if pointer == "" {
+			return strings.TrimRight(string(contents), "\r\n"), nil
+		}
+
+		return lookup(contents, pointer)
+	default:
+		return "", fmt.Errorf("unsupported reference %q", ref)
+	}
+}
+
+// lookup returns the string value of the decrypted document at the path (e.g. `/registries/0/password`).
+func lookup(contents []byte, pointer string) (string, error) {
+	var node yaml.Node
+
+	if err := yaml.Unmarshal(contents, &node); err != nil {
+		return "", fmt.Errorf("error decoding decrypted document: %w", err)
+	}
+
+	current := &node
+
+	if current.Kind == yaml.DocumentNode && len(current.Content) > 0 {
+		current = current.Content[0]
+	}
+
+	for _, key := range strings.Split(strings.Trim(pointer, "/"), "/") {
+		if key == "" {
+			continue
+		}
+
+		var next *yaml.Node
+
+		switch current.Kind { //nolint: exhaustive
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(current.Content); i += 2 {
+				if current.Content[i].Value == key {
+					next = current.Content[i+1]
+
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if idx, err := strconv.Atoi(key); err == nil && idx >= 0 && idx < len(current.Content) {
+				next = current.Content[idx]
+			}
+		}
+
+		if next == nil {
+			return "", fmt.Errorf("path %q not found in the decrypted document", pointer)
+		}
+
+		current = next
+	}
+
+	if current.Kind != yaml.ScalarNode {
+		return "", fmt.Errorf("value at path %q is not a scalar", pointer)
+	}
+
+	return current.Value, nil
+}
+
+func decode(source []byte) ([]*yaml.Node, error) {
+	var docs []*yaml.Node
+
+	dec := yaml.NewDecoder(bytes.NewReader(source))
+
+	for {
+		var doc yaml.Node
+
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return docs, nil
+			}
+
+			return nil, fmt.Errorf("decode error: %w", err)
+		}
+
+		docs = append(docs, &doc)
+	}
+}
+
+// walk calls f for every string value which is a secret reference, path is the dotted path of the value.
+func walk(node *yaml.Node, path string, f func(path string, node *yaml.Node) error) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			if err := walk(child, path, f); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+
+			if path != "" {
+				key = path + "." + key
+			}
+
+			if err := walk(node.Content[i+1], key, f); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			if err := walk(child, fmt.Sprintf("%s[%d]", path, i), f); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		if node.Tag == "!!str" && IsReference(node.Value) {
+			return f(path, node)
+		}
+	case yaml.AliasNode:
+	}
+
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/talos-systems/crypto/x509"
 	tnet "github.com/talos-systems/net"
 
@@ -47,9 +48,55 @@ func Config(t machine.Type, in *Input) (c *v1alpha1.Config, err error) {
 		return c, errors.New("failed to determine config type to generate")
 	}
 
+	if err = applyVersionContract(c, in.VersionContract); err != nil {
+		return c, err
+	}
+
 	return c, nil
 }
 
+// applyVersionContract verifies that the config can be consumed by the Talos version specified in the contract.
+//
+// Older Talos versions reject configs with unknown keys, so the sections which are not supported by the contract
+// version can't be used.
+func applyVersionContract(c *v1alpha1.Config, contract *config.VersionContract) error {
+	var result *multierror.Error
+
+	unsupported := func(section string) {
+		result = multierror.Append(result, fmt.Errorf("%s is not supported by Talos %s", section, contract))
+	}
+
+	if c.MachineConfig.MachineFeatures != nil && !contract.SupportsMachineFeatures() {
+		unsupported("machine.features")
+	}
+
+	if c.MachineConfig.MachineLogging != nil && !contract.SupportsMachineLogging() {
+		unsupported("machine.logging")
+	}
+
+	if c.ClusterConfig.ClusterJoinThrottle != nil && !contract.SupportsJoinThrottle() {
+		unsupported("cluster.joinThrottle")
+	}
+
+	if c.ClusterConfig.ClusterDiscoveryConfig != nil && !contract.SupportsClusterDiscovery() {
+		unsupported("cluster.discovery")
+	}
+
+	if c.MachineConfig.MachineKubelet != nil && c.MachineConfig.MachineKubelet.KubeletServerCertRotation && !contract.SupportsKubeletServerCertRotation() {
+		unsupported("machine.kubelet.serverCertRotation")
+	}
+
+	if !contract.SupportsRegistryCredentialProviders() {
+		for host, registry := range c.MachineConfig.MachineRegistries.RegistryConfig {
+			if registry.RegistryAuth != nil && registry.RegistryAuth.RegistryProvider != nil {
+				unsupported(fmt.Sprintf("credential provider for registry %q", host))
+			}
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
 // Input holds info about certs, ips, and node type.
 //
 //nolint: maligned
@@ -90,6 +137,9 @@ type Input struct {
 	Debug                    bool
 	Persist                  bool
 	AllowSchedulingOnMasters bool
+
+	// VersionContract is the Talos version to generate the config for, nil means the current version.
+	VersionContract *config.VersionContract
 }
 
 // GetAPIServerEndpoint returns the formatted host:port of the API server endpoint.
@@ -129,10 +179,12 @@ func (i *Input) GetAPIServerSANs() []string {
 
 // Certs holds the base64 encoded keys and certificates.
 type Certs struct {
-	Admin *x509.PEMEncodedCertificateAndKey
-	Etcd  *x509.PEMEncodedCertificateAndKey
-	K8s   *x509.PEMEncodedCertificateAndKey
-	OS    *x509.PEMEncodedCertificateAndKey
+	Admin             *x509.PEMEncodedCertificateAndKey
+	Etcd              *x509.PEMEncodedCertificateAndKey
+	K8s               *x509.PEMEncodedCertificateAndKey
+	K8sAggregator     *x509.PEMEncodedCertificateAndKey
+	K8sServiceAccount *v1alpha1.PEMEncodedKey
+	OS                *x509.PEMEncodedCertificateAndKey
 }
 
 // Secrets holds the sensitive kubeadm data.
@@ -186,12 +238,14 @@ func (c *SystemClock) SetFixedTimestamp(t time.Time) {
 // NewSecretsBundle creates secrets bundle generating all secrets.
 func NewSecretsBundle(clock Clock) (*SecretsBundle, error) {
 	var (
-		etcd          *x509.CertificateAuthority
-		kubernetesCA  *x509.CertificateAuthority
-		talosCA       *x509.CertificateAuthority
-		trustdInfo    *TrustdInfo
-		kubeadmTokens *Secrets
-		err           error
+		etcd           *x509.CertificateAuthority
+		kubernetesCA   *x509.CertificateAuthority
+		aggregatorCA   *x509.CertificateAuthority
+		serviceAccount *x509.RSAKey
+		talosCA        *x509.CertificateAuthority
+		trustdInfo     *TrustdInfo
+		kubeadmTokens  *Secrets
+		err            error
 	)
 
 	etcd, err = NewEtcdCA(clock.Now())
@@ -204,6 +258,16 @@ func NewSecretsBundle(clock Clock) (*SecretsBundle, error) {
 		return nil, err
 	}
 
+	aggregatorCA, err = NewAggregatorCA(clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	serviceAccount, err = x509.NewRSAKey()
+	if err != nil {
+		return nil, err
+	}
+
 	talosCA, err = NewTalosCA(clock.Now())
 	if err != nil {
 		return nil, err
@@ -247,6 +311,13 @@ func NewSecretsBundle(clock Clock) (*SecretsBundle, error) {
 				Crt: kubernetesCA.CrtPEM,
 				Key: kubernetesCA.KeyPEM,
 			},
+			K8sAggregator: &x509.PEMEncodedCertificateAndKey{
+				Crt: aggregatorCA.CrtPEM,
+				Key: aggregatorCA.KeyPEM,
+			},
+			K8sServiceAccount: &v1alpha1.PEMEncodedKey{
+				Key: serviceAccount.KeyPEM,
+			},
 			OS: &x509.PEMEncodedCertificateAndKey{
 				Crt: talosCA.CrtPEM,
 				Key: talosCA.KeyPEM,
@@ -258,9 +329,16 @@ func NewSecretsBundle(clock Clock) (*SecretsBundle, error) {
 // NewSecretsBundleFromConfig creates secrets bundle using existing config.
 func NewSecretsBundleFromConfig(clock Clock, c config.Provider) *SecretsBundle {
 	certs := &Certs{
-		K8s:  c.Cluster().CA(),
-		Etcd: c.Cluster().Etcd().CA(),
-		OS:   c.Machine().Security().CA(),
+		K8s:           c.Cluster().CA(),
+		K8sAggregator: c.Cluster().AggregatorCA(),
+		Etcd:          c.Cluster().Etcd().CA(),
+		OS:            c.Machine().Security().CA(),
+	}
+
+	if key := c.Cluster().ServiceAccountKey(); key != nil {
+		certs.K8sServiceAccount = &v1alpha1.PEMEncodedKey{
+			Key: key,
+		}
 	}
 
 	trustd := &TrustdInfo{
@@ -310,6 +388,18 @@ func NewKubernetesCA(currentTime time.Time) (ca *x509.CertificateAuthority, err
 	return x509.NewSelfSignedCertificateAuthority(opts...)
 }
 
+// NewAggregatorCA generates a CA for the Kubernetes aggregator/front-proxy.
+func NewAggregatorCA(currentTime time.Time) (ca *x509.CertificateAuthority, err error) {
+	opts := []x509.Option{
+		x509.RSA(true),
+		x509.Organization("front-proxy"),
+		x509.NotAfter(currentTime.Add(87600 * time.Hour)),
+		x509.NotBefore(currentTime),
+	}
+
+	return x509.NewSelfSignedCertificateAuthority(opts...)
+}
+
 // NewTalosCA generates a CA for the Talos PKI.
 func NewTalosCA(currentTime time.Time) (ca *x509.CertificateAuthority, err error) {
 	opts := []x509.Option{
@@ -327,32 +417,43 @@ func NewAdminCertificateAndKey(currentTime time.Time, crt, key []byte, loopback
 	ips := []net.IP{net.ParseIP(loopback)}
 
 	opts := []x509.Option{
+		x509.Organization(constants.RoleAdmin),
 		x509.IPAddresses(ips),
 		x509.NotAfter(currentTime.Add(87600 * time.Hour)),
 		x509.NotBefore(currentTime),
 	}
 
+	caCrt, caKey, err := parseCertificateAuthority(crt, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return x509.NewCertficateAndKey(caCrt, caKey, opts...)
+}
+
+// parseCertificateAuthority decodes PEM-encoded Talos CA certificate and key.
+func parseCertificateAuthority(crt, key []byte) (*stdlibx509.Certificate, interface{}, error) {
 	caPemBlock, _ := pem.Decode(crt)
 	if caPemBlock == nil {
-		return nil, errors.New("failed to decode ca cert pem")
+		return nil, nil, errors.New("failed to decode ca cert pem")
 	}
 
 	caCrt, err := stdlibx509.ParseCertificate(caPemBlock.Bytes)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	caKeyPemBlock, _ := pem.Decode(key)
 	if caKeyPemBlock == nil {
-		return nil, errors.New("failed to decode ca key pem")
+		return nil, nil, errors.New("failed to decode ca key pem")
 	}
 
 	caKey, err := stdlibx509.ParsePKCS8PrivateKey(caKeyPemBlock.Bytes)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return x509.NewCertficateAndKey(caCrt, caKey, opts...)
+	return caCrt, caKey, nil
 }
 
 // NewInput generates the sensitive data required to generate all config
@@ -429,11 +530,40 @@ func NewInput(clustername, endpoint, kubernetesVersion string, secrets *SecretsB
 		Persist:                   options.Persist,
 		AllowSchedulingOnMasters:  options.AllowSchedulingOnMasters,
 		MachineDisks:              options.MachineDisks,
+		VersionContract:           options.VersionContract,
+	}
+
+	if err = validateSubnets(input, options); err != nil {
+		return nil, err
 	}
 
 	return input, nil
 }
 
+// validateSubnets checks that the pod and service subnets don't overlap the node networks known at the generation time:
+// the control plane endpoint, the endpoint list and the network config.
+func validateSubnets(input *Input, options GenOptions) error {
+	nodeNetworks := options.NetworkConfig.NodeNetworks()
+
+	if endpointURL, err := url.Parse(input.ControlPlaneEndpoint); err == nil {
+		if network := v1alpha1.NodeAddressNetwork("control plane endpoint", endpointURL.Hostname()); network != nil {
+			nodeNetworks = append(nodeNetworks, *network)
+		}
+	}
+
+	for _, endpoint := range options.EndpointList {
+		if network := v1alpha1.NodeAddressNetwork("endpoint", endpoint); network != nil {
+			nodeNetworks = append(nodeNetworks, *network)
+		}
+	}
+
+	if err := v1alpha1.ValidateClusterSubnets(input.PodNet, input.ServiceNet, nodeNetworks); err != nil {
+		return fmt.Errorf("invalid cluster subnets: %w", err)
+	}
+
+	return nil
+}
+
 // randBytes returns a random string consisting of the characters in
 // validBootstrapTokenChars, with the length customized by the parameter.
 func randBytes(length int) (string, error) {
@@ -5,10 +5,14 @@
 package generate_test
 
 import (
+	"crypto/tls"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
 	genv1alpha1 "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
@@ -51,3 +55,66 @@ func (suite *GenerateSuite) TestGenerateTalosconfigSuccess() {
 	_, err := genv1alpha1.Talosconfig(suite.input)
 	suite.Require().NoError(err)
 }
+
+func (suite *GenerateSuite) TestNewClientCertificateAndKey() {
+	now := time.Now()
+
+	cert, err := genv1alpha1.NewClientCertificateAndKey(now, suite.input.Certs.OS, []string{"os:admin", "os:reader"}, time.Hour)
+	suite.Require().NoError(err)
+
+	crt, err := cert.GetCert()
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal([]string{"os:admin", "os:reader"}, crt.Subject.Organization)
+	suite.Assert().WithinDuration(now.Add(time.Hour), crt.NotAfter, time.Second)
+
+	ca, err := suite.input.Certs.OS.GetCert()
+	suite.Require().NoError(err)
+
+	suite.Assert().NoError(crt.CheckSignatureFrom(ca))
+
+	_, err = tls.X509KeyPair(cert.Crt, cert.Key)
+	suite.Assert().NoError(err)
+
+	_, err = genv1alpha1.NewClientCertificateAndKey(now, suite.input.Certs.OS, nil, 0)
+	suite.Assert().Error(err)
+}
+
+func (suite *GenerateSuite) TestGenerateVersionContract() {
+	secrets, err := genv1alpha1.NewSecretsBundle(genv1alpha1.NewClock())
+	suite.Require().NoError(err)
+
+	input, err := genv1alpha1.NewInput("test", "10.0.1.5", constants.DefaultKubernetesVersion, secrets,
+		genv1alpha1.WithVersionContract(config.TalosVersion0_7),
+	)
+	suite.Require().NoError(err)
+
+	for _, t := range []machine.Type{machine.TypeInit, machine.TypeControlPlane, machine.TypeJoin} {
+		var cfg *v1alpha1.Config
+
+		cfg, err = genv1alpha1.Config(t, input)
+		suite.Require().NoError(err)
+
+		suite.Assert().False(cfg.Machine().Kubelet().ServerCertRotation())
+	}
+
+	input.RegistryConfig = map[string]*v1alpha1.RegistryConfig{
+		"ghcr.io": {
+			RegistryAuth: &v1alpha1.RegistryAuthConfig{
+				RegistryProvider: &v1alpha1.RegistryCredentialProviderConfig{
+					ProviderExec: "/usr/local/bin/provider",
+				},
+			},
+		},
+	}
+
+	_, err = genv1alpha1.Config(machine.TypeJoin, input)
+	suite.Require().EqualError(err, "1 error occurred:\n\t* credential provider for registry \"ghcr.io\" is not supported by Talos v0.7\n\n")
+
+	input.VersionContract = config.TalosVersion0_8
+
+	cfg, err := genv1alpha1.Config(machine.TypeJoin, input)
+	suite.Require().NoError(err)
+
+	suite.Assert().True(cfg.Machine().Kubelet().ServerCertRotation())
+}
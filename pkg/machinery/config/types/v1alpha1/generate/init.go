@@ -81,6 +81,21 @@ func initUd(in *Input) (*v1alpha1.Config, error) {
 		AllowSchedulingOnMasters:      in.AllowSchedulingOnMasters,
 	}
 
+	if in.VersionContract.SupportsStaticPodControlPlane() {
+		cluster.ClusterAggregatorCA = in.Certs.K8sAggregator
+		cluster.ClusterServiceAccount = in.Certs.K8sServiceAccount
+	}
+
+	if in.VersionContract.SupportsClusterDiscovery() {
+		cluster.ClusterDiscoveryConfig = &v1alpha1.ClusterDiscoveryConfig{
+			DiscoveryEnabled: true,
+		}
+	}
+
+	if in.VersionContract.SupportsKubeletServerCertRotation() {
+		machine.MachineKubelet.KubeletServerCertRotation = true
+	}
+
 	config.MachineConfig = machine
 	config.ClusterConfig = cluster
 
@@ -61,6 +61,16 @@ func workerUd(in *Input) (*v1alpha1.Config, error) {
 		},
 	}
 
+	if in.VersionContract.SupportsClusterDiscovery() {
+		cluster.ClusterDiscoveryConfig = &v1alpha1.ClusterDiscoveryConfig{
+			DiscoveryEnabled: true,
+		}
+	}
+
+	if in.VersionContract.SupportsKubeletServerCertRotation() {
+		machine.MachineKubelet.KubeletServerCertRotation = true
+	}
+
 	config.MachineConfig = machine
 	config.ClusterConfig = cluster
 
@@ -5,7 +5,14 @@
 package generate
 
 import (
-	"encoding/base64"
+	"crypto/rand"
+	stdlibx509 "crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"time"
+
+	"github.com/talos-systems/crypto/x509"
 
 	"github.com/talos-systems/talos/pkg/machinery/client/config"
 )
@@ -20,15 +27,53 @@ func Talosconfig(in *Input, opts ...GenOption) (*config.Config, error) {
 		}
 	}
 
-	return &config.Config{
-		Context: in.ClusterName,
-		Contexts: map[string]*config.Context{
-			in.ClusterName: {
-				Endpoints: options.EndpointList,
-				CA:        base64.StdEncoding.EncodeToString(in.Certs.OS.Crt),
-				Crt:       base64.StdEncoding.EncodeToString(in.Certs.Admin.Crt),
-				Key:       base64.StdEncoding.EncodeToString(in.Certs.Admin.Key),
-			},
+	return config.NewConfig(in.ClusterName, options.EndpointList, in.Certs.OS.Crt, in.Certs.Admin), nil
+}
+
+// NewClientCertificateAndKey generates the Talos API client certificate and key signed by the Talos CA.
+//
+// Roles are stored as the certificate subject organizations.
+func NewClientCertificateAndKey(currentTime time.Time, ca *x509.PEMEncodedCertificateAndKey, roles []string, ttl time.Duration) (*x509.PEMEncodedCertificateAndKey, error) {
+	if ttl <= 0 {
+		return nil, errors.New("certificate TTL should be positive")
+	}
+
+	caCrt, caKey, err := parseCertificateAuthority(ca.Crt, ca.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.NewEd25519Key()
+	if err != nil {
+		return nil, err
+	}
+
+	serialNumber, err := x509.NewSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	template := &stdlibx509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: roles,
 		},
+		NotBefore:   currentTime,
+		NotAfter:    currentTime.Add(ttl),
+		KeyUsage:    stdlibx509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []stdlibx509.ExtKeyUsage{stdlibx509.ExtKeyUsageClientAuth},
+	}
+
+	crtDER, err := stdlibx509.CreateCertificate(rand.Reader, template, caCrt, key.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &x509.PEMEncodedCertificateAndKey{
+		Crt: pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: crtDER,
+		}),
+		Key: key.PrivateKeyPEM,
 	}, nil
 }
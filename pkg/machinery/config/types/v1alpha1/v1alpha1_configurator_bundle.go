@@ -12,6 +12,7 @@ import (
 
 	clientconfig "github.com/talos-systems/talos/pkg/machinery/client/config"
 	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/config/encoder"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
 )
 
@@ -45,35 +46,17 @@ func (c *ConfigBundle) TalosConfig() *clientconfig.Config {
 }
 
 // Write config files to output directory.
-func (c *ConfigBundle) Write(outputDir string, types ...machine.Type) error {
+func (c *ConfigBundle) Write(outputDir string, commentsFlags encoder.CommentsFlags, types ...machine.Type) error {
 	for _, t := range types {
 		name := strings.ToLower(t.String()) + ".yaml"
 		fullFilePath := filepath.Join(outputDir, name)
 
-		var (
-			configString string
-			err          error
-		)
-
-		switch t { //nolint: exhaustive
-		case machine.TypeInit:
-			configString, err = c.Init().String()
-			if err != nil {
-				return err
-			}
-		case machine.TypeControlPlane:
-			configString, err = c.ControlPlane().String()
-			if err != nil {
-				return err
-			}
-		case machine.TypeJoin:
-			configString, err = c.Join().String()
-			if err != nil {
-				return err
-			}
+		b, err := c.Serialize(commentsFlags, t)
+		if err != nil {
+			return err
 		}
 
-		if err = ioutil.WriteFile(fullFilePath, []byte(configString), 0o644); err != nil {
+		if err = ioutil.WriteFile(fullFilePath, b, 0o644); err != nil {
 			return err
 		}
 
@@ -82,3 +65,17 @@ func (c *ConfigBundle) Write(outputDir string, types ...machine.Type) error {
 
 	return nil
 }
+
+// Serialize returns the config for the provided machine type.
+func (c *ConfigBundle) Serialize(commentsFlags encoder.CommentsFlags, t machine.Type) ([]byte, error) {
+	switch t { //nolint: exhaustive
+	case machine.TypeInit:
+		return c.Init().EncodeBytes(encoder.WithComments(commentsFlags))
+	case machine.TypeControlPlane:
+		return c.ControlPlane().EncodeBytes(encoder.WithComments(commentsFlags))
+	case machine.TypeJoin:
+		return c.Join().EncodeBytes(encoder.WithComments(commentsFlags))
+	default:
+		return nil, fmt.Errorf("unsupported config type %v", t)
+	}
+}
@@ -33,3 +33,21 @@ func (b *Base64Bytes) UnmarshalYAML(unmarshal func(interface{}) error) error {
 func (b Base64Bytes) MarshalYAML() (interface{}, error) {
 	return base64.StdEncoding.EncodeToString(b), nil
 }
+
+// Unstructured holds an arbitrary YAML document, e.g. Kubernetes component config.
+type Unstructured map[string]interface{}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+//
+// Nested objects are decoded as plain map[string]interface{}.
+func (u *Unstructured) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var data map[string]interface{}
+
+	if err := unmarshal(&data); err != nil {
+		return err
+	}
+
+	*u = data
+
+	return nil
+}
@@ -35,3 +35,16 @@ func TestBase64Bytes(t *testing.T) {
 
 	assert.Equal(t, input.CA, decoded.CA)
 }
+
+func TestUnstructured(t *testing.T) {
+	var decoded v1alpha1.SchedulerConfig
+
+	require.NoError(t, yaml.Unmarshal([]byte(`config:
+  kind: KubeSchedulerConfiguration
+  clientConnection:
+    qps: 100
+`), &decoded))
+
+	assert.Equal(t, "KubeSchedulerConfiguration", decoded.Config()["kind"])
+	assert.Equal(t, map[string]interface{}{"qps": 100}, decoded.Config()["clientConnection"])
+}
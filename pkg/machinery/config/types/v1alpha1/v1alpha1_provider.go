@@ -41,6 +41,8 @@ func (c *Config) Debug() bool {
 }
 
 // Persist implements the config.Provider interface.
+//
+// Deprecated: machine config is always persisted.
 func (c *Config) Persist() bool {
 	return c.ConfigPersist
 }
@@ -57,7 +59,17 @@ func (c *Config) Cluster() config.ClusterConfig {
 
 // String implements the config.Provider interface.
 func (c *Config) String() (string, error) {
-	b, err := c.Bytes()
+	return c.EncodeString()
+}
+
+// Bytes implements the config.Provider interface.
+func (c *Config) Bytes() ([]byte, error) {
+	return c.EncodeBytes()
+}
+
+// EncodeString implements the config.Provider interface.
+func (c *Config) EncodeString(encoderOptions ...encoder.Option) (string, error) {
+	b, err := c.EncodeBytes(encoderOptions...)
 	if err != nil {
 		return "", err
 	}
@@ -65,11 +77,9 @@ func (c *Config) String() (string, error) {
 	return string(b), nil
 }
 
-// Bytes implements the config.Provider interface.
-func (c *Config) Bytes() (res []byte, err error) {
-	res, err = encoder.NewEncoder(c).Encode()
-
-	return
+// EncodeBytes implements the config.Provider interface.
+func (c *Config) EncodeBytes(encoderOptions ...encoder.Option) ([]byte, error) {
+	return encoder.NewEncoder(c, encoderOptions...).Encode()
 }
 
 // ApplyDynamicConfig implements the config.Provider interface.
@@ -144,6 +154,39 @@ func (m *MachineConfig) Disks() []config.Disk {
 	return disks
 }
 
+// RAIDs implements the config.Provider interface.
+func (m *MachineConfig) RAIDs() []config.RAID {
+	raids := make([]config.RAID, len(m.MachineRAIDs))
+
+	for i := 0; i < len(m.MachineRAIDs); i++ {
+		raids[i] = m.MachineRAIDs[i]
+	}
+
+	return raids
+}
+
+// VolumeGroups implements the config.Provider interface.
+func (m *MachineConfig) VolumeGroups() []config.VolumeGroup {
+	volumeGroups := make([]config.VolumeGroup, len(m.MachineVolumeGroups))
+
+	for i := 0; i < len(m.MachineVolumeGroups); i++ {
+		volumeGroups[i] = m.MachineVolumeGroups[i]
+	}
+
+	return volumeGroups
+}
+
+// EphemeralQuotas implements the config.Provider interface.
+func (m *MachineConfig) EphemeralQuotas() []config.EphemeralQuota {
+	quotas := make([]config.EphemeralQuota, len(m.MachineEphemeralQuotas))
+
+	for i := 0; i < len(m.MachineEphemeralQuotas); i++ {
+		quotas[i] = m.MachineEphemeralQuotas[i]
+	}
+
+	return quotas
+}
+
 // Network implements the config.Provider interface.
 func (m *MachineConfig) Network() config.MachineNetwork {
 	if m.MachineNetwork == nil {
@@ -218,48 +261,851 @@ func (m *MachineConfig) CA() *x509.PEMEncodedCertificateAndKey {
 	return m.MachineCA
 }
 
-// Token implements the config.Provider interface.
-func (m *MachineConfig) Token() string {
-	return m.MachineToken
+// Token implements the config.Provider interface.
+func (m *MachineConfig) Token() string {
+	return m.MachineToken
+}
+
+// CertSANs implements the config.Provider interface.
+func (m *MachineConfig) CertSANs() []string {
+	return m.MachineCertSANs
+}
+
+// Registries implements the config.Provider interface.
+func (m *MachineConfig) Registries() config.Registries {
+	return &m.MachineRegistries
+}
+
+// Logging implements the config.Provider interface.
+func (m *MachineConfig) Logging() config.Logging {
+	if m.MachineLogging == nil {
+		return &LoggingConfig{}
+	}
+
+	return m.MachineLogging
+}
+
+// Console implements the config.Logging interface.
+func (l *LoggingConfig) Console() config.ConsoleLogging {
+	if l.LoggingConsole == nil {
+		return &ConsoleLoggingConfig{}
+	}
+
+	return l.LoggingConsole
+}
+
+// Persistence implements the config.Logging interface.
+func (l *LoggingConfig) Persistence() config.LogPersistence {
+	if l.LoggingPersistence == nil {
+		return &LogPersistenceConfig{}
+	}
+
+	return l.LoggingPersistence
+}
+
+// Mode implements the config.ConsoleLogging interface.
+func (c *ConsoleLoggingConfig) Mode() string {
+	if c.ConsoleMode == "" {
+		return constants.DefaultConsoleMode
+	}
+
+	return c.ConsoleMode
+}
+
+// Device implements the config.ConsoleLogging interface.
+func (c *ConsoleLoggingConfig) Device() string {
+	if c.ConsoleDevice == "" {
+		return constants.DefaultConsoleDevice
+	}
+
+	return c.ConsoleDevice
+}
+
+// RateLimit implements the config.ConsoleLogging interface.
+func (c *ConsoleLoggingConfig) RateLimit() int {
+	return c.ConsoleRateLimit
+}
+
+// Enabled implements the config.LogPersistence interface.
+func (p *LogPersistenceConfig) Enabled() bool {
+	return p.PersistenceEnabled
+}
+
+// MaxSize implements the config.LogPersistence interface.
+func (p *LogPersistenceConfig) MaxSize() int64 {
+	if p.PersistenceMaxSize == 0 {
+		return constants.DefaultPersistentLogMaxSize
+	}
+
+	return p.PersistenceMaxSize
+}
+
+// MaxFiles implements the config.LogPersistence interface.
+func (p *LogPersistenceConfig) MaxFiles() int {
+	if p.PersistenceMaxFiles == 0 {
+		return constants.DefaultPersistentLogMaxFiles
+	}
+
+	return p.PersistenceMaxFiles
+}
+
+// Features implements the config.Provider interface.
+func (m *MachineConfig) Features() config.Features {
+	if m.MachineFeatures == nil {
+		return &FeaturesConfig{}
+	}
+
+	return m.MachineFeatures
+}
+
+// Kernel implements the config.MachineConfig interface.
+func (m *MachineConfig) Kernel() config.Kernel {
+	if m.MachineKernel == nil {
+		return &KernelConfig{}
+	}
+
+	return m.MachineKernel
+}
+
+// Modules implements the config.Kernel interface.
+func (k *KernelConfig) Modules() []config.KernelModule {
+	modules := make([]config.KernelModule, len(k.KernelModules))
+
+	for i, module := range k.KernelModules {
+		modules[i] = module
+	}
+
+	return modules
+}
+
+// IsolatedCPUs implements the config.Kernel interface.
+func (k *KernelConfig) IsolatedCPUs() string {
+	return k.KernelIsolatedCPUs
+}
+
+// NohzFullCPUs implements the config.Kernel interface.
+func (k *KernelConfig) NohzFullCPUs() string {
+	return k.KernelNohzFullCPUs
+}
+
+// IRQAffinityCPUs implements the config.Kernel interface.
+func (k *KernelConfig) IRQAffinityCPUs() string {
+	return k.KernelIRQAffinityCPUs
+}
+
+// Name implements the config.KernelModule interface.
+func (m *KernelModuleConfig) Name() string {
+	return m.ModuleName
+}
+
+// Parameters implements the config.KernelModule interface.
+func (m *KernelModuleConfig) Parameters() []string {
+	return m.ModuleParameters
+}
+
+// CRI implements the config.MachineConfig interface.
+func (m *MachineConfig) CRI() config.CRI {
+	if m.MachineCRI == nil {
+		return &CRIConfig{}
+	}
+
+	return m.MachineCRI
+}
+
+// ExtensionServices implements the config.MachineConfig interface.
+func (m *MachineConfig) ExtensionServices() []config.ExtensionService {
+	services := make([]config.ExtensionService, len(m.MachineExtensionServices))
+
+	for i := range m.MachineExtensionServices {
+		services[i] = m.MachineExtensionServices[i]
+	}
+
+	return services
+}
+
+// Proxy implements the config.MachineConfig interface.
+func (m *MachineConfig) Proxy() config.MachineProxy {
+	if m.MachineProxy == nil {
+		return &MachineProxyConfig{}
+	}
+
+	return m.MachineProxy
+}
+
+// HTTPProxy implements the config.MachineProxy interface.
+func (p *MachineProxyConfig) HTTPProxy() string {
+	return p.ProxyHTTPProxy
+}
+
+// HTTPSProxy implements the config.MachineProxy interface.
+func (p *MachineProxyConfig) HTTPSProxy() string {
+	return p.ProxyHTTPSProxy
+}
+
+// NoProxy implements the config.MachineProxy interface.
+func (p *MachineProxyConfig) NoProxy() []string {
+	return p.ProxyNoProxy
+}
+
+// AutoNoProxy implements the config.MachineProxy interface.
+func (p *MachineProxyConfig) AutoNoProxy() bool {
+	return !p.ProxyDisableAutoNoProxy
+}
+
+// ServiceResources implements the config.MachineConfig interface.
+func (m *MachineConfig) ServiceResources(service string) config.ServiceResources {
+	resources := ServiceResourcesConfig{}
+
+	if r := m.MachineServiceResources[service]; r != nil {
+		resources = *r
+	}
+
+	defaults := defaultServiceResources(service)
+
+	if resources.ResourcesCPUShares == 0 {
+		resources.ResourcesCPUShares = defaults.ResourcesCPUShares
+	}
+
+	if resources.ResourcesOOMScoreAdj == nil {
+		resources.ResourcesOOMScoreAdj = defaults.ResourcesOOMScoreAdj
+	}
+
+	return &resources
+}
+
+// defaultServiceResources returns the resources of the system service if they are not set in the config.
+//
+// etcd gets more CPU weight, and the OOM killer picks the workloads before the services which keep the node running.
+func defaultServiceResources(service string) ServiceResourcesConfig {
+	oomScoreAdj := func(adj int) *int {
+		return &adj
+	}
+
+	switch service {
+	case "etcd":
+		return ServiceResourcesConfig{
+			ResourcesCPUShares:   constants.EtcdDefaultCPUShares,
+			ResourcesOOMScoreAdj: oomScoreAdj(constants.EtcdDefaultOOMScoreAdj),
+		}
+	case "kubelet":
+		return ServiceResourcesConfig{
+			ResourcesOOMScoreAdj: oomScoreAdj(constants.KubeletDefaultOOMScoreAdj),
+		}
+	case "containerd", "cri":
+		return ServiceResourcesConfig{
+			ResourcesOOMScoreAdj: oomScoreAdj(constants.ContainerdDefaultOOMScoreAdj),
+		}
+	case "apid":
+		return ServiceResourcesConfig{
+			ResourcesOOMScoreAdj: oomScoreAdj(constants.APIDDefaultOOMScoreAdj),
+		}
+	default:
+		return ServiceResourcesConfig{}
+	}
+}
+
+// CPUShares implements the config.ServiceResources interface.
+func (r *ServiceResourcesConfig) CPUShares() uint64 {
+	return r.ResourcesCPUShares
+}
+
+// CPULimit implements the config.ServiceResources interface.
+func (r *ServiceResourcesConfig) CPULimit() float64 {
+	return r.ResourcesCPULimit
+}
+
+// MemoryLimit implements the config.ServiceResources interface.
+func (r *ServiceResourcesConfig) MemoryLimit() uint64 {
+	return uint64(r.ResourcesMemoryLimit)
+}
+
+// MemoryReservation implements the config.ServiceResources interface.
+func (r *ServiceResourcesConfig) MemoryReservation() uint64 {
+	return uint64(r.ResourcesMemoryReservation)
+}
+
+// OOMScoreAdj implements the config.ServiceResources interface.
+func (r *ServiceResourcesConfig) OOMScoreAdj() int {
+	if r.ResourcesOOMScoreAdj == nil {
+		return 0
+	}
+
+	return *r.ResourcesOOMScoreAdj
+}
+
+// Name implements the config.ExtensionService interface.
+func (s *ExtensionServiceConfig) Name() string {
+	return s.ServiceName
+}
+
+// Image implements the config.ExtensionService interface.
+func (s *ExtensionServiceConfig) Image() string {
+	return s.ServiceImage
+}
+
+// Args implements the config.ExtensionService interface.
+func (s *ExtensionServiceConfig) Args() []string {
+	return s.ServiceArgs
+}
+
+// Env implements the config.ExtensionService interface.
+func (s *ExtensionServiceConfig) Env() map[string]string {
+	return s.ServiceEnv
+}
+
+// Mounts implements the config.ExtensionService interface.
+func (s *ExtensionServiceConfig) Mounts() []specs.Mount {
+	return s.ServiceMounts
+}
+
+// Restart implements the config.ExtensionService interface.
+func (s *ExtensionServiceConfig) Restart() string {
+	if s.ServiceRestart == "" {
+		return constants.ExtensionServiceRestartAlways
+	}
+
+	return s.ServiceRestart
+}
+
+// DependsOn implements the config.ExtensionService interface.
+func (s *ExtensionServiceConfig) DependsOn() []string {
+	return s.ServiceDependsOn
+}
+
+// Security implements the config.ExtensionService interface.
+func (s *ExtensionServiceConfig) Security() config.ExtensionServiceSecurity {
+	if s.ServiceSecurity == nil {
+		return &ExtensionServiceSecurityConfig{}
+	}
+
+	return s.ServiceSecurity
+}
+
+// Privileged implements the config.ExtensionServiceSecurity interface.
+func (s *ExtensionServiceSecurityConfig) Privileged() bool {
+	return s.SecurityPrivileged
+}
+
+// HostNetwork implements the config.ExtensionServiceSecurity interface.
+func (s *ExtensionServiceSecurityConfig) HostNetwork() bool {
+	return s.SecurityHostNetwork
+}
+
+// Capabilities implements the config.ExtensionServiceSecurity interface.
+func (s *ExtensionServiceSecurityConfig) Capabilities() []string {
+	return s.SecurityCapabilities
+}
+
+// WritableRootfs implements the config.ExtensionServiceSecurity interface.
+func (s *ExtensionServiceSecurityConfig) WritableRootfs() bool {
+	return s.SecurityWritableRootfs
+}
+
+// Runtimes implements the config.CRI interface.
+func (c *CRIConfig) Runtimes() map[string]config.CRIRuntime {
+	runtimes := make(map[string]config.CRIRuntime, len(c.CRIRuntimes))
+
+	for name, runtime := range c.CRIRuntimes {
+		runtimes[name] = runtime
+	}
+
+	return runtimes
+}
+
+// RuntimeType implements the config.CRIRuntime interface.
+func (r *CRIRuntimeConfig) RuntimeType() string {
+	if r.CRIRuntimeType == "" {
+		return constants.DefaultCRIRuntimeType
+	}
+
+	return r.CRIRuntimeType
+}
+
+// BinaryName implements the config.CRIRuntime interface.
+func (r *CRIRuntimeConfig) BinaryName() string {
+	return r.RuntimeBinaryName
+}
+
+// BMC implements the config.MachineConfig interface.
+func (m *MachineConfig) BMC() config.BMC {
+	if m.MachineBMC == nil {
+		return &BMCConfig{}
+	}
+
+	return m.MachineBMC
+}
+
+// Configured implements the config.BMC interface.
+func (b *BMCConfig) Configured() bool {
+	return b.BMCDHCP || b.BMCAddress != ""
+}
+
+// Channel implements the config.BMC interface.
+func (b *BMCConfig) Channel() uint8 {
+	if b.BMCChannel == 0 {
+		return constants.DefaultBMCChannel
+	}
+
+	return b.BMCChannel
+}
+
+// DHCP implements the config.BMC interface.
+func (b *BMCConfig) DHCP() bool {
+	return b.BMCDHCP
+}
+
+// Address implements the config.BMC interface.
+func (b *BMCConfig) Address() string {
+	return b.BMCAddress
+}
+
+// Gateway implements the config.BMC interface.
+func (b *BMCConfig) Gateway() string {
+	return b.BMCGateway
+}
+
+// SystemDiskEncryption implements the config.MachineConfig interface.
+func (m *MachineConfig) SystemDiskEncryption() config.SystemDiskEncryption {
+	if m.MachineSystemDiskEncryption == nil {
+		return &SystemDiskEncryptionConfig{}
+	}
+
+	return m.MachineSystemDiskEncryption
+}
+
+// Get implements the config.SystemDiskEncryption interface.
+func (e *SystemDiskEncryptionConfig) Get(label string) config.Encryption {
+	if label == constants.EphemeralPartitionLabel && e.EphemeralPartition != nil {
+		return e.EphemeralPartition
+	}
+
+	return nil
+}
+
+// Keys implements the config.Encryption interface.
+func (e *EncryptionConfig) Keys() []config.EncryptionKey {
+	keys := make([]config.EncryptionKey, len(e.EncryptionKeys))
+
+	for i, key := range e.EncryptionKeys {
+		keys[i] = key
+	}
+
+	return keys
+}
+
+// Slot implements the config.EncryptionKey interface.
+func (k *EncryptionKey) Slot() int {
+	return k.KeySlot
+}
+
+// TPM implements the config.EncryptionKey interface.
+func (k *EncryptionKey) TPM() config.EncryptionKeyTPM {
+	if k.KeyTPM == nil {
+		return nil
+	}
+
+	return k.KeyTPM
+}
+
+// PCRs implements the config.EncryptionKeyTPM interface.
+func (t *EncryptionKeyTPM) PCRs() []int {
+	if len(t.TPMPCRs) == 0 {
+		return []int{constants.DefaultEncryptionPCR}
+	}
+
+	return t.TPMPCRs
+}
+
+// RBAC implements the config.Features interface.
+func (f *FeaturesConfig) RBAC() config.RBAC {
+	if f.FeaturesRBAC == nil {
+		return &RBACConfig{}
+	}
+
+	return f.FeaturesRBAC
+}
+
+// ImageVerification implements the config.Features interface.
+func (f *FeaturesConfig) ImageVerification() config.ImageVerification {
+	if f.FeaturesImageVerification == nil {
+		return &ImageVerificationConfig{}
+	}
+
+	return f.FeaturesImageVerification
+}
+
+// ConfigStorage implements the config.Features interface.
+func (f *FeaturesConfig) ConfigStorage() config.ConfigStorage {
+	if f.FeaturesConfigStorage == nil {
+		return &ConfigStorageConfig{}
+	}
+
+	return f.FeaturesConfigStorage
+}
+
+// Backend implements the config.ConfigStorage interface.
+func (s *ConfigStorageConfig) Backend() string {
+	if s.StorageBackend == "" {
+		return constants.ConfigStorageBackendState
+	}
+
+	return s.StorageBackend
+}
+
+// URL implements the config.ConfigStorage interface.
+func (s *ConfigStorageConfig) URL() string {
+	return s.StorageURL
+}
+
+// ManagementTunnel implements the config.Features interface.
+func (f *FeaturesConfig) ManagementTunnel() config.ManagementTunnel {
+	if f.FeaturesManagementTunnel == nil {
+		return &ManagementTunnelConfig{}
+	}
+
+	return f.FeaturesManagementTunnel
+}
+
+// APIURL implements the config.ManagementTunnel interface.
+func (t *ManagementTunnelConfig) APIURL() string {
+	return t.TunnelAPIURL
+}
+
+// ISCSI implements the config.Features interface.
+func (f *FeaturesConfig) ISCSI() config.ISCSI {
+	if f.FeaturesISCSI == nil {
+		return &ISCSIConfig{}
+	}
+
+	return f.FeaturesISCSI
+}
+
+// Enabled implements the config.ISCSI interface.
+func (i *ISCSIConfig) Enabled() bool {
+	return i.ISCSIEnabled
+}
+
+// InitiatorName implements the config.ISCSI interface.
+func (i *ISCSIConfig) InitiatorName() string {
+	return i.ISCSIInitiatorName
+}
+
+// NFSMounts implements the config.Features interface.
+func (f *FeaturesConfig) NFSMounts() config.NFSMounts {
+	if f.FeaturesNFSMounts == nil {
+		return &NFSMountsConfig{}
+	}
+
+	return f.FeaturesNFSMounts
+}
+
+// Enabled implements the config.NFSMounts interface.
+func (n *NFSMountsConfig) Enabled() bool {
+	return n.NFSEnabled
+}
+
+// IdmapdDomain implements the config.NFSMounts interface.
+func (n *NFSMountsConfig) IdmapdDomain() string {
+	return n.NFSIdmapdDomain
+}
+
+// DefaultVersion implements the config.NFSMounts interface.
+func (n *NFSMountsConfig) DefaultVersion() string {
+	return n.NFSDefaultVersion
+}
+
+// APIAuthorizationPolicy implements the config.Features interface.
+func (f *FeaturesConfig) APIAuthorizationPolicy() config.APIAuthorizationPolicy {
+	if f.FeaturesAPIAuthorizationPolicy == nil {
+		return &APIAuthorizationPolicyConfig{}
+	}
+
+	return f.FeaturesAPIAuthorizationPolicy
+}
+
+// Rules implements the config.APIAuthorizationPolicy interface.
+func (p *APIAuthorizationPolicyConfig) Rules() []config.APIAuthorizationPolicyRule {
+	rules := make([]config.APIAuthorizationPolicyRule, len(p.PolicyRules))
+
+	for i := range p.PolicyRules {
+		rules[i] = p.PolicyRules[i]
+	}
+
+	return rules
+}
+
+// Methods implements the config.APIAuthorizationPolicyRule interface.
+func (r *APIAuthorizationPolicyRule) Methods() []string {
+	return r.RuleMethods
+}
+
+// AllowedSubjects implements the config.APIAuthorizationPolicyRule interface.
+func (r *APIAuthorizationPolicyRule) AllowedSubjects() []string {
+	return r.RuleAllowedSubjects
+}
+
+// AllowedRoles implements the config.APIAuthorizationPolicyRule interface.
+func (r *APIAuthorizationPolicyRule) AllowedRoles() []string {
+	return r.RuleAllowedRoles
+}
+
+// LocalAPIAccess implements the config.Features interface.
+func (f *FeaturesConfig) LocalAPIAccess() config.LocalAPIAccess {
+	if f.FeaturesLocalAPIAccess == nil {
+		return &LocalAPIAccessConfig{}
+	}
+
+	return f.FeaturesLocalAPIAccess
+}
+
+// Enabled implements the config.LocalAPIAccess interface.
+func (l *LocalAPIAccessConfig) Enabled() bool {
+	return l.LocalEnabled
+}
+
+// GroupID implements the config.LocalAPIAccess interface.
+func (l *LocalAPIAccessConfig) GroupID() int {
+	return l.LocalGroupID
+}
+
+// KubernetesTalosAPIAccess implements the config.Features interface.
+func (f *FeaturesConfig) KubernetesTalosAPIAccess() config.KubernetesTalosAPIAccess {
+	if f.FeaturesKubernetesTalosAPIAccess == nil {
+		return &KubernetesTalosAPIAccessConfig{}
+	}
+
+	return f.FeaturesKubernetesTalosAPIAccess
+}
+
+// Enabled implements the config.KubernetesTalosAPIAccess interface.
+func (a *KubernetesTalosAPIAccessConfig) Enabled() bool {
+	return a.AccessEnabled
+}
+
+// AllowedRoles implements the config.KubernetesTalosAPIAccess interface.
+func (a *KubernetesTalosAPIAccessConfig) AllowedRoles() []string {
+	return a.AccessAllowedRoles
+}
+
+// AllowedKubernetesNamespaces implements the config.KubernetesTalosAPIAccess interface.
+func (a *KubernetesTalosAPIAccessConfig) AllowedKubernetesNamespaces() []string {
+	return a.AccessAllowedKubernetesNamespaces
+}
+
+// Enabled implements the config.ImageVerification interface.
+func (v *ImageVerificationConfig) Enabled() bool {
+	return len(v.VerificationPublicKeys) > 0
+}
+
+// PublicKeys implements the config.ImageVerification interface.
+func (v *ImageVerificationConfig) PublicKeys() []string {
+	return v.VerificationPublicKeys
+}
+
+// Images implements the config.ImageVerification interface.
+func (v *ImageVerificationConfig) Images() []string {
+	return v.VerificationImages
+}
+
+// Rules implements the config.RBAC interface.
+func (r *RBACConfig) Rules() []config.RBACRule {
+	rules := make([]config.RBACRule, len(r.RBACRules))
+
+	for i := range r.RBACRules {
+		rules[i] = r.RBACRules[i]
+	}
+
+	return rules
+}
+
+// Roles implements the config.RBACRule interface.
+func (r *RBACRule) Roles() []string {
+	return r.RuleRoles
+}
+
+// Methods implements the config.RBACRule interface.
+func (r *RBACRule) Methods() []string {
+	return r.RuleMethods
+}
+
+// Nodes implements the config.RBACRule interface.
+func (r *RBACRule) Nodes() []string {
+	return r.RuleNodes
+}
+
+// Image implements the config.Provider interface.
+func (k *KubeletConfig) Image() string {
+	image := k.KubeletImage
+
+	if image == "" {
+		image = fmt.Sprintf("%s:v%s", constants.KubeletImage, constants.DefaultKubernetesVersion)
+	}
+
+	return image
+}
+
+// ExtraArgs implements the config.Provider interface.
+func (k *KubeletConfig) ExtraArgs() map[string]string {
+	if k == nil {
+		k = &KubeletConfig{}
+	}
+
+	if k.KubeletExtraArgs == nil {
+		k.KubeletExtraArgs = make(map[string]string)
+	}
+
+	return k.KubeletExtraArgs
+}
+
+// ExtraMounts implements the config.Provider interface.
+func (k *KubeletConfig) ExtraMounts() []specs.Mount {
+	return k.KubeletExtraMounts
+}
+
+// DrainTimeout implements the config.Provider interface.
+func (k *KubeletConfig) DrainTimeout() time.Duration {
+	if k.KubeletDrainTimeout == 0 {
+		return constants.DefaultDrainTimeout
+	}
+
+	return k.KubeletDrainTimeout
+}
+
+// CredentialProviders implements the config.Provider interface.
+func (k *KubeletConfig) CredentialProviders() []config.KubeletCredentialProvider {
+	if k.KubeletCredentialProviderConfig == nil {
+		return nil
+	}
+
+	providers := make([]config.KubeletCredentialProvider, len(k.KubeletCredentialProviderConfig.CredentialProviders))
+
+	for i := 0; i < len(k.KubeletCredentialProviderConfig.CredentialProviders); i++ {
+		providers[i] = k.KubeletCredentialProviderConfig.CredentialProviders[i]
+	}
+
+	return providers
+}
+
+// NodeIP implements the config.Provider interface.
+func (k *KubeletConfig) NodeIP() config.KubeletNodeIP {
+	if k.KubeletNodeIP == nil {
+		return &KubeletNodeIPConfig{}
+	}
+
+	return k.KubeletNodeIP
+}
+
+// ServerCertRotation implements the config.Provider interface.
+func (k *KubeletConfig) ServerCertRotation() bool {
+	return k.KubeletServerCertRotation
+}
+
+// SystemReserved implements the config.Kubelet interface.
+func (k *KubeletConfig) SystemReserved() map[string]string {
+	return k.KubeletSystemReserved
+}
+
+// KubeReserved implements the config.Kubelet interface.
+func (k *KubeletConfig) KubeReserved() map[string]string {
+	return k.KubeletKubeReserved
+}
+
+// CPUManagerPolicy implements the config.Kubelet interface.
+func (k *KubeletConfig) CPUManagerPolicy() string {
+	return k.KubeletCPUManagerPolicy
+}
+
+// ReservedCPUs implements the config.Kubelet interface.
+func (k *KubeletConfig) ReservedCPUs() string {
+	return k.KubeletReservedCPUs
+}
+
+// TopologyManagerPolicy implements the config.Kubelet interface.
+func (k *KubeletConfig) TopologyManagerPolicy() string {
+	return k.KubeletTopologyManagerPolicy
+}
+
+// TopologyManagerScope implements the config.Kubelet interface.
+func (k *KubeletConfig) TopologyManagerScope() string {
+	return k.KubeletTopologyManagerScope
+}
+
+// MemoryManager implements the config.Kubelet interface.
+func (k *KubeletConfig) MemoryManager() config.KubeletMemoryManager {
+	if k.KubeletMemoryManager == nil {
+		return &KubeletMemoryManagerConfig{}
+	}
+
+	return k.KubeletMemoryManager
+}
+
+// Policy implements the config.KubeletMemoryManager interface.
+func (m *KubeletMemoryManagerConfig) Policy() string {
+	return m.MemoryManagerPolicy
+}
+
+// ReservedMemory implements the config.KubeletMemoryManager interface.
+func (m *KubeletMemoryManagerConfig) ReservedMemory() []config.KubeletReservedMemory {
+	reserved := make([]config.KubeletReservedMemory, len(m.MemoryManagerReservedMemory))
+
+	for i := range m.MemoryManagerReservedMemory {
+		reserved[i] = m.MemoryManagerReservedMemory[i]
+	}
+
+	return reserved
+}
+
+// NUMANode implements the config.KubeletReservedMemory interface.
+func (r *KubeletReservedMemoryConfig) NUMANode() uint32 {
+	return r.ReservedNUMANode
+}
+
+// Memory implements the config.KubeletReservedMemory interface.
+func (r *KubeletReservedMemoryConfig) Memory() string {
+	return r.ReservedMemory
 }
 
-// CertSANs implements the config.Provider interface.
-func (m *MachineConfig) CertSANs() []string {
-	return m.MachineCertSANs
+// ValidSubnets implements the config.KubeletNodeIP interface.
+func (n *KubeletNodeIPConfig) ValidSubnets() []string {
+	return n.KubeletNodeIPValidSubnets
 }
 
-// Registries implements the config.Provider interface.
-func (m *MachineConfig) Registries() config.Registries {
-	return &m.MachineRegistries
+// Name implements the config.KubeletCredentialProvider interface.
+func (p *KubeletCredentialProvider) Name() string {
+	return p.ProviderName
 }
 
-// Image implements the config.Provider interface.
-func (k *KubeletConfig) Image() string {
-	image := k.KubeletImage
+// MatchImages implements the config.KubeletCredentialProvider interface.
+func (p *KubeletCredentialProvider) MatchImages() []string {
+	return p.ProviderMatchImages
+}
 
-	if image == "" {
-		image = fmt.Sprintf("%s:v%s", constants.KubeletImage, constants.DefaultKubernetesVersion)
+// DefaultCacheDuration implements the config.KubeletCredentialProvider interface.
+func (p *KubeletCredentialProvider) DefaultCacheDuration() time.Duration {
+	if p.ProviderDefaultCacheDuration == 0 {
+		return constants.DefaultKubeletCredentialProviderCacheDuration
 	}
 
-	return image
+	return p.ProviderDefaultCacheDuration
 }
 
-// ExtraArgs implements the config.Provider interface.
-func (k *KubeletConfig) ExtraArgs() map[string]string {
-	if k == nil {
-		k = &KubeletConfig{}
+// APIVersion implements the config.KubeletCredentialProvider interface.
+func (p *KubeletCredentialProvider) APIVersion() string {
+	if p.ProviderAPIVersion == "" {
+		return constants.DefaultKubeletCredentialProviderAPIVersion
 	}
 
-	if k.KubeletExtraArgs == nil {
-		k.KubeletExtraArgs = make(map[string]string)
-	}
+	return p.ProviderAPIVersion
+}
 
-	return k.KubeletExtraArgs
+// Args implements the config.KubeletCredentialProvider interface.
+func (p *KubeletCredentialProvider) Args() []string {
+	return p.ProviderArgs
 }
 
-// ExtraMounts implements the config.Provider interface.
-func (k *KubeletConfig) ExtraMounts() []specs.Mount {
-	return k.KubeletExtraMounts
+// Env implements the config.KubeletCredentialProvider interface.
+func (p *KubeletCredentialProvider) Env() config.Env {
+	return p.ProviderEnv
 }
 
 // Name implements the config.Provider interface.
@@ -283,6 +1129,10 @@ func (c *ClusterConfig) LocalAPIServerPort() int {
 
 // CertSANs implements the config.Provider interface.
 func (c *ClusterConfig) CertSANs() []string {
+	if c.APIServerConfig == nil {
+		return nil
+	}
+
 	return c.APIServerConfig.CertSANs
 }
 
@@ -291,6 +1141,20 @@ func (c *ClusterConfig) CA() *x509.PEMEncodedCertificateAndKey {
 	return c.ClusterCA
 }
 
+// AggregatorCA implements the config.Provider interface.
+func (c *ClusterConfig) AggregatorCA() *x509.PEMEncodedCertificateAndKey {
+	return c.ClusterAggregatorCA
+}
+
+// ServiceAccountKey implements the config.Provider interface.
+func (c *ClusterConfig) ServiceAccountKey() []byte {
+	if c.ClusterServiceAccount == nil {
+		return nil
+	}
+
+	return c.ClusterServiceAccount.Key
+}
+
 // AESCBCEncryptionSecret implements the config.Provider interface.
 func (c *ClusterConfig) AESCBCEncryptionSecret() string {
 	return c.ClusterAESCBCEncryptionSecret
@@ -326,6 +1190,26 @@ func (a *APIServerConfig) ExtraArgs() map[string]string {
 	return a.ExtraArgsConfig
 }
 
+// DisablePodSecurityPolicy implements the config.Provider interface.
+func (a *APIServerConfig) DisablePodSecurityPolicy() bool {
+	return a.DisablePodSecurityPolicyConfig
+}
+
+// ServiceAccountIssuer implements the config.Provider interface.
+func (a *APIServerConfig) ServiceAccountIssuer() string {
+	return a.ServiceAccountIssuerConfig
+}
+
+// ServiceAccountJWKSURI implements the config.Provider interface.
+func (a *APIServerConfig) ServiceAccountJWKSURI() string {
+	return a.ServiceAccountJWKSURIConfig
+}
+
+// ServiceAccountExtraAudiences implements the config.Provider interface.
+func (a *APIServerConfig) ServiceAccountExtraAudiences() []string {
+	return a.ServiceAccountExtraAudiencesConfig
+}
+
 // ControllerManager implements the config.Provider interface.
 func (c *ClusterConfig) ControllerManager() config.ControllerManager {
 	if c.ControllerManagerConfig == nil {
@@ -351,6 +1235,11 @@ func (c *ControllerManagerConfig) ExtraArgs() map[string]string {
 	return c.ExtraArgsConfig
 }
 
+// ExtraConfig implements the config.Provider interface.
+func (c *ControllerManagerConfig) ExtraConfig() map[string]string {
+	return c.ExtraConfigConfig
+}
+
 // Proxy implements the config.Provider interface.
 func (c *ClusterConfig) Proxy() config.Proxy {
 	if c.ProxyConfig == nil {
@@ -360,6 +1249,11 @@ func (c *ClusterConfig) Proxy() config.Proxy {
 	return c.ProxyConfig
 }
 
+// Enabled implements the config.Provider interface.
+func (p *ProxyConfig) Enabled() bool {
+	return !p.Disabled
+}
+
 // Image implements the config.Provider interface.
 func (p *ProxyConfig) Image() string {
 	image := p.ContainerImage
@@ -404,6 +1298,115 @@ func (c *ClusterConfig) ScheduleOnMasters() bool {
 	return c.AllowSchedulingOnMasters
 }
 
+// JoinThrottle implements the config.Provider interface.
+func (c *ClusterConfig) JoinThrottle() config.JoinThrottle {
+	if c.ClusterJoinThrottle == nil {
+		return &JoinThrottleConfig{}
+	}
+
+	return c.ClusterJoinThrottle
+}
+
+// Discovery implements the config.Provider interface.
+func (c *ClusterConfig) Discovery() config.Discovery {
+	if c.ClusterDiscoveryConfig == nil {
+		return &ClusterDiscoveryConfig{}
+	}
+
+	return c.ClusterDiscoveryConfig
+}
+
+// Enabled implements the config.Discovery interface.
+func (d *ClusterDiscoveryConfig) Enabled() bool {
+	return d.DiscoveryEnabled
+}
+
+// Registries implements the config.Discovery interface.
+func (d *ClusterDiscoveryConfig) Registries() config.DiscoveryRegistries {
+	return &d.DiscoveryRegistries
+}
+
+// Kubernetes implements the config.DiscoveryRegistries interface.
+func (r *DiscoveryRegistriesConfig) Kubernetes() config.KubernetesRegistry {
+	return &r.RegistryKubernetes
+}
+
+// Service implements the config.DiscoveryRegistries interface.
+func (r *DiscoveryRegistriesConfig) Service() config.ServiceRegistry {
+	return &r.RegistryService
+}
+
+// Enabled implements the config.KubernetesRegistry interface.
+func (r *RegistryKubernetesConfig) Enabled() bool {
+	return !r.RegistryDisabled
+}
+
+// Enabled implements the config.ServiceRegistry interface.
+//
+// Discovery service registry is enabled only if the endpoint is set.
+func (r *RegistryServiceConfig) Enabled() bool {
+	return !r.RegistryDisabled && r.RegistryEndpoint != ""
+}
+
+// Endpoint implements the config.ServiceRegistry interface.
+func (r *RegistryServiceConfig) Endpoint() string {
+	return r.RegistryEndpoint
+}
+
+// MaxConcurrentRequests implements the config.JoinThrottle interface.
+func (j *JoinThrottleConfig) MaxConcurrentRequests() int {
+	if j.JoinMaxConcurrentRequests == 0 {
+		return constants.TrustdDefaultMaxConcurrentRequests
+	}
+
+	return j.JoinMaxConcurrentRequests
+}
+
+// MaxQueuedRequests implements the config.JoinThrottle interface.
+func (j *JoinThrottleConfig) MaxQueuedRequests() int {
+	if j.JoinMaxQueuedRequests == 0 {
+		return constants.TrustdDefaultMaxQueuedRequests
+	}
+
+	return j.JoinMaxQueuedRequests
+}
+
+// RetryInterval implements the config.JoinThrottle interface.
+func (j *JoinThrottleConfig) RetryInterval() time.Duration {
+	if j.JoinRetryInterval == 0 {
+		return constants.TrustdDefaultRetryInterval
+	}
+
+	return j.JoinRetryInterval
+}
+
+// MaxRetryInterval implements the config.JoinThrottle interface.
+func (j *JoinThrottleConfig) MaxRetryInterval() time.Duration {
+	if j.JoinMaxRetryInterval == 0 {
+		return constants.TrustdDefaultMaxRetryInterval
+	}
+
+	return j.JoinMaxRetryInterval
+}
+
+// RetryJitter implements the config.JoinThrottle interface.
+func (j *JoinThrottleConfig) RetryJitter() time.Duration {
+	if j.JoinRetryJitter == 0 {
+		return constants.TrustdDefaultRetryJitter
+	}
+
+	return j.JoinRetryJitter
+}
+
+// RetryTimeout implements the config.JoinThrottle interface.
+func (j *JoinThrottleConfig) RetryTimeout() time.Duration {
+	if j.JoinRetryTimeout == 0 {
+		return constants.TrustdDefaultRetryTimeout
+	}
+
+	return j.JoinRetryTimeout
+}
+
 // Image implements the config.Provider interface.
 func (s *SchedulerConfig) Image() string {
 	image := s.ContainerImage
@@ -420,6 +1423,11 @@ func (s *SchedulerConfig) ExtraArgs() map[string]string {
 	return s.ExtraArgsConfig
 }
 
+// Config implements the config.Provider interface.
+func (s *SchedulerConfig) Config() map[string]interface{} {
+	return s.SchedulerConfigConfig
+}
+
 // Etcd implements the config.Provider interface.
 func (c *ClusterConfig) Etcd() config.Etcd {
 	return c.EtcdConfig
@@ -455,6 +1463,57 @@ func (e *EtcdConfig) ExtraArgs() map[string]string {
 	return e.EtcdExtraArgs
 }
 
+// AdvertisedSubnets implements the config.Etcd interface.
+func (e *EtcdConfig) AdvertisedSubnets() []string {
+	return e.EtcdAdvertisedSubnets
+}
+
+// ListenSubnets implements the config.Etcd interface.
+func (e *EtcdConfig) ListenSubnets() []string {
+	return e.EtcdListenSubnets
+}
+
+// Maintenance implements the config.Etcd interface.
+func (e *EtcdConfig) Maintenance() config.EtcdMaintenance {
+	if e.EtcdMaintenance == nil {
+		return &EtcdMaintenanceConfig{}
+	}
+
+	return e.EtcdMaintenance
+}
+
+// Enabled implements the config.EtcdMaintenance interface.
+func (m *EtcdMaintenanceConfig) Enabled() bool {
+	return m.MaintenanceEnabled
+}
+
+// Interval implements the config.EtcdMaintenance interface.
+func (m *EtcdMaintenanceConfig) Interval() time.Duration {
+	if m.MaintenanceInterval == 0 {
+		return constants.EtcdMaintenanceDefaultInterval
+	}
+
+	return m.MaintenanceInterval
+}
+
+// MinDBSize implements the config.EtcdMaintenance interface.
+func (m *EtcdMaintenanceConfig) MinDBSize() uint64 {
+	if m.MaintenanceMinDBSize == 0 {
+		return constants.EtcdMaintenanceDefaultMinDBSize
+	}
+
+	return uint64(m.MaintenanceMinDBSize)
+}
+
+// FragmentationThreshold implements the config.EtcdMaintenance interface.
+func (m *EtcdMaintenanceConfig) FragmentationThreshold() int {
+	if m.MaintenanceFragmentationThreshold == 0 {
+		return constants.EtcdMaintenanceDefaultFragmentationThreshold
+	}
+
+	return m.MaintenanceFragmentationThreshold
+}
+
 // Mirrors implements the Registries interface.
 func (r *RegistriesConfig) Mirrors() map[string]config.RegistryMirrorConfig {
 	mirrors := make(map[string]config.RegistryMirrorConfig, len(r.RegistryMirrors))
@@ -515,6 +1574,30 @@ func (r *RegistryAuthConfig) IdentityToken() string {
 	return r.RegistryIdentityToken
 }
 
+// Provider implements the Registries interface.
+func (r *RegistryAuthConfig) Provider() config.RegistryCredentialProvider {
+	if r.RegistryProvider == nil {
+		return nil
+	}
+
+	return r.RegistryProvider
+}
+
+// Exec implements the Registries interface.
+func (p *RegistryCredentialProviderConfig) Exec() string {
+	return p.ProviderExec
+}
+
+// Args implements the Registries interface.
+func (p *RegistryCredentialProviderConfig) Args() []string {
+	return p.ProviderArgs
+}
+
+// Env implements the Registries interface.
+func (p *RegistryCredentialProviderConfig) Env() map[string]string {
+	return p.ProviderEnv
+}
+
 // ClientIdentity implements the Registries interface.
 func (r *RegistryTLSConfig) ClientIdentity() *x509.PEMEncodedCertificateAndKey {
 	return r.TLSClientIdentity
@@ -633,6 +1716,29 @@ func (c *ClusterConfig) ServiceCIDR() string {
 	return strings.Join(c.ClusterNetwork.ServiceSubnet, ",")
 }
 
+// ExternalCloudProvider implements the config.Provider interface.
+func (c *ClusterConfig) ExternalCloudProvider() config.ExternalCloudProvider {
+	if c.ExternalCloudProviderConfig == nil {
+		return &ExternalCloudProviderConfig{}
+	}
+
+	return c.ExternalCloudProviderConfig
+}
+
+// Enabled implements the config.ExternalCloudProvider interface.
+func (ecp *ExternalCloudProviderConfig) Enabled() bool {
+	return ecp.ExternalEnabled
+}
+
+// ManifestURLs implements the config.ExternalCloudProvider interface.
+func (ecp *ExternalCloudProviderConfig) ManifestURLs() []string {
+	if !ecp.ExternalEnabled {
+		return nil
+	}
+
+	return ecp.ExternalManifests
+}
+
 // ExtraManifestURLs implements the config.Provider interface.
 func (c *ClusterConfig) ExtraManifestURLs() []string {
 	return c.ExtraManifests
@@ -643,6 +1749,21 @@ func (c *ClusterConfig) ExtraManifestHeaderMap() map[string]string {
 	return c.ExtraManifestHeaders
 }
 
+// ExtraManifestChecksumMap implements the config.Provider interface.
+func (c *ClusterConfig) ExtraManifestChecksumMap() map[string]string {
+	return c.ExtraManifestChecksums
+}
+
+// ExtraManifestTemplates implements the config.Provider interface.
+func (c *ClusterConfig) ExtraManifestTemplates() bool {
+	return c.ExtraManifestTemplatesEnabled
+}
+
+// ExtraManifestValueMap implements the config.Provider interface.
+func (c *ClusterConfig) ExtraManifestValueMap() map[string]string {
+	return c.ExtraManifestValues
+}
+
 // PodCheckpointer implements the config.Provider interface.
 func (c *ClusterConfig) PodCheckpointer() config.PodCheckpointer {
 	if c.PodCheckpointerConfig == nil {
@@ -671,6 +1792,49 @@ func (c *CNIConfig) URLs() []string {
 	return c.CNIUrls
 }
 
+// Flannel implements the config.Provider interface.
+func (c *CNIConfig) Flannel() config.FlannelCNI {
+	if c.CNIFlannel == nil {
+		return &FlannelCNIConfig{}
+	}
+
+	return c.CNIFlannel
+}
+
+// Backend implements the config.Provider interface.
+func (f *FlannelCNIConfig) Backend() string {
+	if f.FlannelBackend == "" {
+		return constants.FlannelBackendVXLAN
+	}
+
+	return f.FlannelBackend
+}
+
+// MTU implements the config.Provider interface.
+func (f *FlannelCNIConfig) MTU() int {
+	return f.FlannelMTU
+}
+
+// Interfaces implements the config.Provider interface.
+func (f *FlannelCNIConfig) Interfaces() []string {
+	return f.FlannelInterfaces
+}
+
+// InterfaceRegexps implements the config.Provider interface.
+func (f *FlannelCNIConfig) InterfaceRegexps() []string {
+	return f.FlannelInterfaceRegexps
+}
+
+// ExtraArgs implements the config.Provider interface.
+func (f *FlannelCNIConfig) ExtraArgs() map[string]string {
+	return f.FlannelExtraArgs
+}
+
+// Image implements the config.Provider interface.
+func (f *FlannelCNIConfig) Image() string {
+	return f.FlannelImage
+}
+
 // Hostname implements the config.Provider interface.
 func (n *NetworkConfig) Hostname() string {
 	return n.NetworkHostname
@@ -1010,6 +2174,54 @@ func (i *InstallConfig) WithBootloader() bool {
 	return i.InstallBootloader
 }
 
+// EphemeralDisk implements the config.Provider interface.
+func (i *InstallConfig) EphemeralDisk() string {
+	return i.InstallEphemeralDisk
+}
+
+// EphemeralSize implements the config.Provider interface.
+func (i *InstallConfig) EphemeralSize() uint64 {
+	return uint64(i.InstallEphemeralSize)
+}
+
+// Console implements the config.Provider interface.
+func (i *InstallConfig) Console() config.InstallConsole {
+	if i.InstallConsole == nil {
+		return &InstallConsoleConfig{}
+	}
+
+	return i.InstallConsole
+}
+
+// EnrollSecureBootKeys implements the config.Provider interface.
+func (i *InstallConfig) EnrollSecureBootKeys() bool {
+	return i.InstallEnrollSecureBootKeys
+}
+
+// Serial implements the config.InstallConsole interface.
+func (c *InstallConsoleConfig) Serial() string {
+	return c.ConsoleSerial
+}
+
+// BaudRate implements the config.InstallConsole interface.
+func (c *InstallConsoleConfig) BaudRate() int {
+	if c.ConsoleBaudRate == 0 {
+		return constants.DefaultSerialBaudRate
+	}
+
+	return c.ConsoleBaudRate
+}
+
+// DisableVGA implements the config.InstallConsole interface.
+func (c *InstallConsoleConfig) DisableVGA() bool {
+	return c.ConsoleDisableVGA
+}
+
+// Enabled implements the config.Provider interface.
+func (c *CoreDNS) Enabled() bool {
+	return !c.CoreDNSDisabled
+}
+
 // Image implements the config.Provider interface.
 func (c *CoreDNS) Image() string {
 	coreDNSImage := fmt.Sprintf("%s:%s", constants.CoreDNSImage, constants.DefaultCoreDNSVersion)
@@ -1021,6 +2233,42 @@ func (c *CoreDNS) Image() string {
 	return coreDNSImage
 }
 
+// Replicas implements the config.Provider interface.
+func (c *CoreDNS) Replicas() int {
+	return c.CoreDNSReplicas
+}
+
+// ServiceIPs implements the config.Provider interface.
+func (c *CoreDNS) ServiceIPs() []string {
+	return c.CoreDNSServiceIPs
+}
+
+// ForwardZones implements the config.Provider interface.
+func (c *CoreDNS) ForwardZones() []config.CoreDNSForwardZone {
+	zones := make([]config.CoreDNSForwardZone, len(c.CoreDNSForwardZones))
+
+	for i := range c.CoreDNSForwardZones {
+		zones[i] = &c.CoreDNSForwardZones[i]
+	}
+
+	return zones
+}
+
+// ExtraCorefile implements the config.Provider interface.
+func (c *CoreDNS) ExtraCorefile() string {
+	return c.CoreDNSExtraCorefile
+}
+
+// Zone implements the config.Provider interface.
+func (z *CoreDNSForwardZone) Zone() string {
+	return z.ForwardZone
+}
+
+// Upstreams implements the config.Provider interface.
+func (z *CoreDNSForwardZone) Upstreams() []string {
+	return z.ForwardUpstreams
+}
+
 // Image implements the config.Provider interface.
 func (p *PodCheckpointer) Image() string {
 	return p.PodCheckpointerImage
@@ -1045,6 +2293,30 @@ func (f *MachineFile) Content() string {
 	return f.FileContent
 }
 
+// ContentFrom implements the config.Provider interface.
+func (f *MachineFile) ContentFrom() config.FileContentSource {
+	if f.FileContentFrom == nil {
+		return &MachineFileContentSource{}
+	}
+
+	return f.FileContentFrom
+}
+
+// URL implements the config.Provider interface.
+func (s *MachineFileContentSource) URL() string {
+	return s.SourceURL
+}
+
+// SHA256 implements the config.Provider interface.
+func (s *MachineFileContentSource) SHA256() string {
+	return s.SourceSHA256
+}
+
+// Headers implements the config.Provider interface.
+func (s *MachineFileContentSource) Headers() map[string]string {
+	return s.SourceHeaders
+}
+
 // Permissions implements the config.Provider interface.
 func (f *MachineFile) Permissions() os.FileMode {
 	return os.FileMode(f.FilePermissions)
@@ -1085,3 +2357,102 @@ func (p *DiskPartition) Size() uint64 {
 func (p *DiskPartition) MountPoint() string {
 	return p.DiskMountPoint
 }
+
+// Filesystem implements the config.Provider interface.
+func (p *DiskPartition) Filesystem() string {
+	if p.DiskFilesystem == "" {
+		return constants.DefaultUserDiskFilesystem
+	}
+
+	return p.DiskFilesystem
+}
+
+// MountOptions implements the config.Provider interface.
+func (p *DiskPartition) MountOptions() []string {
+	return p.DiskMountOptions
+}
+
+// Label implements the config.Provider interface.
+func (p *DiskPartition) Label() string {
+	return p.DiskLabel
+}
+
+// Grow implements the config.Provider interface.
+func (p *DiskPartition) Grow() bool {
+	return p.DiskGrow
+}
+
+// Name implements the config.RAID interface.
+func (r *RAIDConfig) Name() string {
+	return r.RAIDName
+}
+
+// Level implements the config.RAID interface.
+func (r *RAIDConfig) Level() string {
+	return r.RAIDLevel
+}
+
+// Devices implements the config.RAID interface.
+func (r *RAIDConfig) Devices() []string {
+	return r.RAIDDevices
+}
+
+// Name implements the config.VolumeGroup interface.
+func (vg *VolumeGroupConfig) Name() string {
+	return vg.VGName
+}
+
+// PhysicalVolumes implements the config.VolumeGroup interface.
+func (vg *VolumeGroupConfig) PhysicalVolumes() []string {
+	return vg.VGPhysicalVolumes
+}
+
+// LogicalVolumes implements the config.VolumeGroup interface.
+func (vg *VolumeGroupConfig) LogicalVolumes() []config.LogicalVolume {
+	volumes := make([]config.LogicalVolume, len(vg.VGLogicalVolumes))
+
+	for i := 0; i < len(vg.VGLogicalVolumes); i++ {
+		volumes[i] = vg.VGLogicalVolumes[i]
+	}
+
+	return volumes
+}
+
+// Name implements the config.LogicalVolume interface.
+func (lv *LogicalVolumeConfig) Name() string {
+	return lv.LVName
+}
+
+// Size implements the config.LogicalVolume interface.
+func (lv *LogicalVolumeConfig) Size() uint64 {
+	return uint64(lv.LVSize)
+}
+
+// Filesystem implements the config.LogicalVolume interface.
+func (lv *LogicalVolumeConfig) Filesystem() string {
+	if lv.LVFilesystem == "" {
+		return constants.DefaultUserDiskFilesystem
+	}
+
+	return lv.LVFilesystem
+}
+
+// MountPoint implements the config.LogicalVolume interface.
+func (lv *LogicalVolumeConfig) MountPoint() string {
+	return lv.LVMountPoint
+}
+
+// MountOptions implements the config.LogicalVolume interface.
+func (lv *LogicalVolumeConfig) MountOptions() []string {
+	return lv.LVMountOptions
+}
+
+// Path implements the config.EphemeralQuota interface.
+func (q *EphemeralQuotaConfig) Path() string {
+	return q.QuotaPath
+}
+
+// Limit implements the config.EphemeralQuota interface.
+func (q *EphemeralQuotaConfig) Limit() uint64 {
+	return uint64(q.QuotaLimit)
+}
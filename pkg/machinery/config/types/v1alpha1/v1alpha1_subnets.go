@@ -0,0 +1,188 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha1
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// NodeNetwork is a network the node is attached to (or a single node address).
+type NodeNetwork struct {
+	// Source describes where the network comes from, e.g. `interface eth0`.
+	Source  string
+	Network *net.IPNet
+}
+
+// reservedSubnets are the ranges used by the cloud providers for the metadata services,
+// the pod and service subnets overlapping them break the access to the metadata.
+var reservedSubnets = []struct {
+	name   string
+	subnet string
+}{
+	{"link-local (cloud metadata service)", "169.254.0.0/16"},
+	{"Alibaba Cloud metadata service", "100.100.100.200/32"},
+	{"AWS IPv6 metadata service", "fd00:ec2::254/128"},
+	{"IPv6 link-local", "fe80::/10"},
+}
+
+// ValidateClusterSubnets checks that the pod and service subnets don't overlap each other,
+// the node networks and the ranges reserved for the cloud metadata services.
+//
+// Overlapping subnets produce clusters which mostly work, but fail to route the traffic
+// to some of the pods, services or nodes.
+//
+//nolint: gocyclo
+func ValidateClusterSubnets(podSubnets, serviceSubnets []string, nodeNetworks []NodeNetwork) error {
+	var result *multierror.Error
+
+	type clusterSubnet struct {
+		kind    string
+		network *net.IPNet
+	}
+
+	var subnets []clusterSubnet
+
+	for _, s := range []struct {
+		kind    string
+		subnets []string
+	}{
+		{"pod", podSubnets},
+		{"service", serviceSubnets},
+	} {
+		for _, subnet := range s.subnets {
+			_, network, err := net.ParseCIDR(strings.TrimSpace(subnet))
+			if err != nil {
+				result = multierror.Append(result, fmt.Errorf("%s subnet %q is invalid: %w", s.kind, subnet, err))
+
+				continue
+			}
+
+			subnets = append(subnets, clusterSubnet{kind: s.kind, network: network})
+		}
+	}
+
+	for i := range subnets {
+		for j := i + 1; j < len(subnets); j++ {
+			if overlaps(subnets[i].network, subnets[j].network) {
+				result = multierror.Append(result, fmt.Errorf("%s subnet %q overlaps with %s subnet %q",
+					subnets[i].kind, subnets[i].network, subnets[j].kind, subnets[j].network))
+			}
+		}
+
+		for _, nodeNetwork := range nodeNetworks {
+			if overlaps(subnets[i].network, nodeNetwork.Network) {
+				result = multierror.Append(result, fmt.Errorf("%s subnet %q overlaps with the node network %q (%s)",
+					subnets[i].kind, subnets[i].network, nodeNetwork.Network, nodeNetwork.Source))
+			}
+		}
+
+		for _, reserved := range reservedSubnets {
+			_, network, _ := net.ParseCIDR(reserved.subnet) //nolint: errcheck
+
+			if overlaps(subnets[i].network, network) {
+				result = multierror.Append(result, fmt.Errorf("%s subnet %q overlaps with the %s range %q",
+					subnets[i].kind, subnets[i].network, reserved.name, network))
+			}
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// NodeAddressNetwork returns the single address network for the IP address, or nil if the address is not an IP.
+func NodeAddressNetwork(source, address string) *NodeNetwork {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil
+	}
+
+	bits := 8 * net.IPv4len
+
+	if ip.To4() == nil {
+		bits = 8 * net.IPv6len
+	} else {
+		ip = ip.To4()
+	}
+
+	return &NodeNetwork{
+		Source:  source,
+		Network: &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)},
+	}
+}
+
+// NodeNetworks returns the node networks defined in the network config: the interface and VLAN addresses
+// and the routed networks (except for the default routes).
+func (n *NetworkConfig) NodeNetworks() []NodeNetwork {
+	var networks []NodeNetwork
+
+	if n == nil {
+		return networks
+	}
+
+	addNetwork := func(source, cidr string) {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			// invalid addresses are reported by the device checks
+			return
+		}
+
+		if ones, _ := network.Mask.Size(); ones == 0 {
+			return
+		}
+
+		networks = append(networks, NodeNetwork{Source: source, Network: network})
+	}
+
+	for _, device := range n.NetworkInterfaces {
+		source := fmt.Sprintf("interface %s", device.Interface())
+
+		if device.CIDR() != "" {
+			addNetwork(source, device.CIDR())
+		}
+
+		for _, route := range device.Routes() {
+			addNetwork(fmt.Sprintf("route via %s on interface %s", route.Gateway(), device.Interface()), route.Network())
+		}
+
+		for _, vlan := range device.Vlans() {
+			vlanSource := fmt.Sprintf("VLAN %d on interface %s", vlan.ID(), device.Interface())
+
+			if vlan.CIDR() != "" {
+				addNetwork(vlanSource, vlan.CIDR())
+			}
+
+			for _, route := range vlan.Routes() {
+				addNetwork(fmt.Sprintf("route via %s on %s", route.Gateway(), vlanSource), route.Network())
+			}
+		}
+	}
+
+	return networks
+}
+
+// nodeNetworks returns the node networks defined in the config and the control plane endpoint address.
+func (c *Config) nodeNetworks() []NodeNetwork {
+	var networks []NodeNetwork
+
+	if c.MachineConfig != nil && c.MachineConfig.MachineNetwork != nil {
+		networks = c.MachineConfig.MachineNetwork.NodeNetworks()
+	}
+
+	if c.ClusterConfig != nil && c.ClusterConfig.ControlPlane != nil && c.ClusterConfig.ControlPlane.Endpoint != nil {
+		if network := NodeAddressNetwork("control plane endpoint", c.ClusterConfig.ControlPlane.Endpoint.Hostname()); network != nil {
+			networks = append(networks, *network)
+		}
+	}
+
+	return networks
+}
+
+func overlaps(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
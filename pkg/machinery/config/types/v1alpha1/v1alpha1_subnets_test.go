@@ -0,0 +1,81 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha1_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+)
+
+func TestValidateClusterSubnets(t *testing.T) {
+	_, eth0, _ := net.ParseCIDR("192.168.0.0/24") //nolint: errcheck
+
+	nodeNetworks := []v1alpha1.NodeNetwork{
+		{Source: "interface eth0", Network: eth0},
+		*v1alpha1.NodeAddressNetwork("control plane endpoint", "10.96.0.10"),
+	}
+
+	for _, tt := range []struct {
+		name           string
+		podSubnets     []string
+		serviceSubnets []string
+		expectedErrors []string
+	}{
+		{
+			name:           "valid",
+			podSubnets:     []string{"10.244.0.0/16", "fc00:db8:10::/56"},
+			serviceSubnets: []string{"10.97.0.0/16", "fc00:db8:20::/112"},
+		},
+		{
+			name:           "invalid",
+			podSubnets:     []string{"10.244.0.0"},
+			serviceSubnets: []string{"10.97.0.0/16"},
+			expectedErrors: []string{`pod subnet "10.244.0.0" is invalid`},
+		},
+		{
+			name:           "overlapping",
+			podSubnets:     []string{"10.0.0.0/8"},
+			serviceSubnets: []string{"10.97.0.0/16"},
+			expectedErrors: []string{
+				`pod subnet "10.0.0.0/8" overlaps with service subnet "10.97.0.0/16"`,
+				`pod subnet "10.0.0.0/8" overlaps with the node network "10.96.0.10/32" (control plane endpoint)`,
+			},
+		},
+		{
+			name:           "node network",
+			podSubnets:     []string{"192.168.0.128/25"},
+			serviceSubnets: []string{"10.97.0.0/16"},
+			expectedErrors: []string{`pod subnet "192.168.0.128/25" overlaps with the node network "192.168.0.0/24" (interface eth0)`},
+		},
+		{
+			name:           "metadata service",
+			podSubnets:     []string{"10.244.0.0/16"},
+			serviceSubnets: []string{"169.254.0.0/24"},
+			expectedErrors: []string{`service subnet "169.254.0.0/24" overlaps with the link-local (cloud metadata service) range "169.254.0.0/16"`},
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := v1alpha1.ValidateClusterSubnets(tt.podSubnets, tt.serviceSubnets, nodeNetworks)
+
+			if len(tt.expectedErrors) == 0 {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			if assert.Error(t, err) {
+				for _, expected := range tt.expectedErrors {
+					assert.Contains(t, err.Error(), expected)
+				}
+			}
+		})
+	}
+}
@@ -11,39 +11,89 @@ import (
 )
 
 var (
-	ConfigDoc                  encoder.Doc
-	MachineConfigDoc           encoder.Doc
-	ClusterConfigDoc           encoder.Doc
-	KubeletConfigDoc           encoder.Doc
-	NetworkConfigDoc           encoder.Doc
-	InstallConfigDoc           encoder.Doc
-	TimeConfigDoc              encoder.Doc
-	RegistriesConfigDoc        encoder.Doc
-	PodCheckpointerDoc         encoder.Doc
-	CoreDNSDoc                 encoder.Doc
-	EndpointDoc                encoder.Doc
-	ControlPlaneConfigDoc      encoder.Doc
-	APIServerConfigDoc         encoder.Doc
-	ControllerManagerConfigDoc encoder.Doc
-	ProxyConfigDoc             encoder.Doc
-	SchedulerConfigDoc         encoder.Doc
-	EtcdConfigDoc              encoder.Doc
-	ClusterNetworkConfigDoc    encoder.Doc
-	CNIConfigDoc               encoder.Doc
-	AdminKubeconfigConfigDoc   encoder.Doc
-	MachineDiskDoc             encoder.Doc
-	DiskPartitionDoc           encoder.Doc
-	MachineFileDoc             encoder.Doc
-	ExtraHostDoc               encoder.Doc
-	DeviceDoc                  encoder.Doc
-	DHCPOptionsDoc             encoder.Doc
-	BondDoc                    encoder.Doc
-	VlanDoc                    encoder.Doc
-	RouteDoc                   encoder.Doc
-	RegistryMirrorConfigDoc    encoder.Doc
-	RegistryConfigDoc          encoder.Doc
-	RegistryAuthConfigDoc      encoder.Doc
-	RegistryTLSConfigDoc       encoder.Doc
+	ConfigDoc                           encoder.Doc
+	MachineConfigDoc                    encoder.Doc
+	MachineProxyConfigDoc               encoder.Doc
+	ClusterConfigDoc                    encoder.Doc
+	KubeletConfigDoc                    encoder.Doc
+	KubeletMemoryManagerConfigDoc       encoder.Doc
+	KubeletReservedMemoryConfigDoc      encoder.Doc
+	KubeletNodeIPConfigDoc              encoder.Doc
+	KubeletCredentialProviderConfigDoc  encoder.Doc
+	KubeletCredentialProviderDoc        encoder.Doc
+	NetworkConfigDoc                    encoder.Doc
+	InstallConfigDoc                    encoder.Doc
+	InstallConsoleConfigDoc             encoder.Doc
+	TimeConfigDoc                       encoder.Doc
+	RegistriesConfigDoc                 encoder.Doc
+	LoggingConfigDoc                    encoder.Doc
+	ConsoleLoggingConfigDoc             encoder.Doc
+	LogPersistenceConfigDoc             encoder.Doc
+	KernelConfigDoc                     encoder.Doc
+	KernelModuleConfigDoc               encoder.Doc
+	ServiceResourcesConfigDoc           encoder.Doc
+	ExtensionServiceConfigDoc           encoder.Doc
+	ExtensionServiceSecurityConfigDoc   encoder.Doc
+	CRIConfigDoc                        encoder.Doc
+	CRIRuntimeConfigDoc                 encoder.Doc
+	BMCConfigDoc                        encoder.Doc
+	SystemDiskEncryptionConfigDoc       encoder.Doc
+	EncryptionConfigDoc                 encoder.Doc
+	EncryptionKeyDoc                    encoder.Doc
+	EncryptionKeyTPMDoc                 encoder.Doc
+	FeaturesConfigDoc                   encoder.Doc
+	KubernetesTalosAPIAccessConfigDoc   encoder.Doc
+	LocalAPIAccessConfigDoc             encoder.Doc
+	NFSMountsConfigDoc                  encoder.Doc
+	ISCSIConfigDoc                      encoder.Doc
+	ManagementTunnelConfigDoc           encoder.Doc
+	ConfigStorageConfigDoc              encoder.Doc
+	ImageVerificationConfigDoc          encoder.Doc
+	RBACConfigDoc                       encoder.Doc
+	RBACRuleDoc                         encoder.Doc
+	APIAuthorizationPolicyConfigDoc     encoder.Doc
+	APIAuthorizationPolicyRuleDoc       encoder.Doc
+	PodCheckpointerDoc                  encoder.Doc
+	CoreDNSDoc                          encoder.Doc
+	CoreDNSForwardZoneDoc               encoder.Doc
+	EndpointDoc                         encoder.Doc
+	PEMEncodedKeyDoc                    encoder.Doc
+	ControlPlaneConfigDoc               encoder.Doc
+	APIServerConfigDoc                  encoder.Doc
+	ControllerManagerConfigDoc          encoder.Doc
+	ProxyConfigDoc                      encoder.Doc
+	SchedulerConfigDoc                  encoder.Doc
+	EtcdConfigDoc                       encoder.Doc
+	EtcdMaintenanceConfigDoc            encoder.Doc
+	ClusterNetworkConfigDoc             encoder.Doc
+	CNIConfigDoc                        encoder.Doc
+	FlannelCNIConfigDoc                 encoder.Doc
+	AdminKubeconfigConfigDoc            encoder.Doc
+	ExternalCloudProviderConfigDoc      encoder.Doc
+	JoinThrottleConfigDoc               encoder.Doc
+	ClusterDiscoveryConfigDoc           encoder.Doc
+	DiscoveryRegistriesConfigDoc        encoder.Doc
+	RegistryKubernetesConfigDoc         encoder.Doc
+	RegistryServiceConfigDoc            encoder.Doc
+	MachineDiskDoc                      encoder.Doc
+	DiskPartitionDoc                    encoder.Doc
+	RAIDConfigDoc                       encoder.Doc
+	VolumeGroupConfigDoc                encoder.Doc
+	LogicalVolumeConfigDoc              encoder.Doc
+	EphemeralQuotaConfigDoc             encoder.Doc
+	MachineFileDoc                      encoder.Doc
+	MachineFileContentSourceDoc         encoder.Doc
+	ExtraHostDoc                        encoder.Doc
+	DeviceDoc                           encoder.Doc
+	DHCPOptionsDoc                      encoder.Doc
+	BondDoc                             encoder.Doc
+	VlanDoc                             encoder.Doc
+	RouteDoc                            encoder.Doc
+	RegistryMirrorConfigDoc             encoder.Doc
+	RegistryConfigDoc                   encoder.Doc
+	RegistryAuthConfigDoc               encoder.Doc
+	RegistryCredentialProviderConfigDoc encoder.Doc
+	RegistryTLSConfigDoc                encoder.Doc
 )
 
 func init() {
@@ -75,7 +125,7 @@ func init() {
 	ConfigDoc.Fields[2].Name = "persist"
 	ConfigDoc.Fields[2].Type = "bool"
 	ConfigDoc.Fields[2].Note = ""
-	ConfigDoc.Fields[2].Description = "Indicates whether to pull the machine config upon every boot."
+	ConfigDoc.Fields[2].Description = "Indicates whether to pull the machine config upon every boot.\n\nDeprecated: machine config is always persisted on the system disk, and the stored config\ntakes precedence over the config source of the platform. The value is ignored."
 	ConfigDoc.Fields[2].Comments[encoder.LineComment] = "Indicates whether to pull the machine config upon every boot."
 	ConfigDoc.Fields[2].Values = []string{
 		"true",
@@ -105,7 +155,7 @@ func init() {
 			FieldName: "machine",
 		},
 	}
-	MachineConfigDoc.Fields = make([]encoder.Doc, 13)
+	MachineConfigDoc.Fields = make([]encoder.Doc, 25)
 	MachineConfigDoc.Fields[0].Name = "type"
 	MachineConfigDoc.Fields[0].Type = "string"
 	MachineConfigDoc.Fields[0].Note = ""
@@ -158,59 +208,176 @@ func init() {
 	MachineConfigDoc.Fields[6].Comments[encoder.LineComment] = "Used to partition, format and mount additional disks."
 
 	MachineConfigDoc.Fields[6].AddExample("MachineDisks list example.", machineDisksExample)
-	MachineConfigDoc.Fields[7].Name = "install"
-	MachineConfigDoc.Fields[7].Type = "InstallConfig"
+	MachineConfigDoc.Fields[7].Name = "raids"
+	MachineConfigDoc.Fields[7].Type = "[]RAIDConfig"
 	MachineConfigDoc.Fields[7].Note = ""
-	MachineConfigDoc.Fields[7].Description = "Used to provide instructions for installations."
-	MachineConfigDoc.Fields[7].Comments[encoder.LineComment] = "Used to provide instructions for installations."
-
-	MachineConfigDoc.Fields[7].AddExample("MachineInstall config usage example.", machineInstallExample)
-	MachineConfigDoc.Fields[8].Name = "files"
-	MachineConfigDoc.Fields[8].Type = "[]MachineFile"
-	MachineConfigDoc.Fields[8].Note = "Note: The specified `path` is relative to `/var`.\n"
-	MachineConfigDoc.Fields[8].Description = "Allows the addition of user specified files.\nThe value of `op` can be `create`, `overwrite`, or `append`.\nIn the case of `create`, `path` must not exist.\nIn the case of `overwrite`, and `append`, `path` must be a valid file.\nIf an `op` value of `append` is used, the existing file will be appended.\nNote that the file contents are not required to be base64 encoded."
-	MachineConfigDoc.Fields[8].Comments[encoder.LineComment] = "Allows the addition of user specified files."
-
-	MachineConfigDoc.Fields[8].AddExample("MachineFiles usage example.", machineFilesExample)
-	MachineConfigDoc.Fields[9].Name = "env"
-	MachineConfigDoc.Fields[9].Type = "Env"
+	MachineConfigDoc.Fields[7].Description = "Used to assemble md-RAID arrays from the disks and partitions.\nArrays are created only once, existing arrays are assembled on boot.\nArray `name` is available as `/dev/md/<name>`."
+	MachineConfigDoc.Fields[7].Comments[encoder.LineComment] = "Used to assemble md-RAID arrays from the disks and partitions."
+
+	MachineConfigDoc.Fields[7].AddExample("", machineRAIDsExample)
+	MachineConfigDoc.Fields[8].Name = "volumeGroups"
+	MachineConfigDoc.Fields[8].Type = "[]VolumeGroupConfig"
+	MachineConfigDoc.Fields[8].Note = ""
+	MachineConfigDoc.Fields[8].Description = "Used to create LVM volume groups and logical volumes on the disks, partitions and RAID arrays.\nVolume groups and logical volumes are created only once, existing ones are activated on boot.\nLogical volume is available as `/dev/<volume group>/<logical volume>`."
+	MachineConfigDoc.Fields[8].Comments[encoder.LineComment] = "Used to create LVM volume groups and logical volumes on the disks, partitions and RAID arrays."
+
+	MachineConfigDoc.Fields[8].AddExample("", machineVolumeGroupsExample)
+	MachineConfigDoc.Fields[9].Name = "ephemeralQuotas"
+	MachineConfigDoc.Fields[9].Type = "[]EphemeralQuotaConfig"
 	MachineConfigDoc.Fields[9].Note = ""
-	MachineConfigDoc.Fields[9].Description = "The `env` field allows for the addition of environment variables.\nAll environment variables are set on PID 1 in addition to every service."
-	MachineConfigDoc.Fields[9].Comments[encoder.LineComment] = "The `env` field allows for the addition of environment variables."
+	MachineConfigDoc.Fields[9].Description = "Used to limit the disk usage of the directories on the EPHEMERAL partition with XFS project quotas,\nso that a single workload can't fill up `/var` (e.g. container images and writable layers vs. pod logs).\nThe EPHEMERAL partition is mounted with `prjquota` if any quotas are configured."
+	MachineConfigDoc.Fields[9].Comments[encoder.LineComment] = "Used to limit the disk usage of the directories on the EPHEMERAL partition with XFS project quotas,"
 
-	MachineConfigDoc.Fields[9].AddExample("Environment variables definition examples.", machineEnvExamples[0])
+	MachineConfigDoc.Fields[9].AddExample("", machineEphemeralQuotasExample)
+	MachineConfigDoc.Fields[10].Name = "install"
+	MachineConfigDoc.Fields[10].Type = "InstallConfig"
+	MachineConfigDoc.Fields[10].Note = ""
+	MachineConfigDoc.Fields[10].Description = "Used to provide instructions for installations."
+	MachineConfigDoc.Fields[10].Comments[encoder.LineComment] = "Used to provide instructions for installations."
+
+	MachineConfigDoc.Fields[10].AddExample("MachineInstall config usage example.", machineInstallExample)
+	MachineConfigDoc.Fields[11].Name = "files"
+	MachineConfigDoc.Fields[11].Type = "[]MachineFile"
+	MachineConfigDoc.Fields[11].Note = ""
+	MachineConfigDoc.Fields[11].Description = "Allows the addition of user specified files.\nThe value of `op` can be `create`, `overwrite`, or `append`.\nIn the case of `create`, `path` must not exist.\nIn the case of `overwrite`, and `append`, `path` must be a valid file.\nIf an `op` value of `append` is used, the existing file will be appended.\nNote that the file contents are not required to be base64 encoded.\nThe files can be written in `/var` and in the overlay mounted directories\n`/etc/kubernetes/manifests`, `/etc/cri/conf.d` and `/etc/ssl/certs`, other paths are rejected."
+	MachineConfigDoc.Fields[11].Comments[encoder.LineComment] = "Allows the addition of user specified files."
+
+	MachineConfigDoc.Fields[11].AddExample("MachineFiles usage example.", machineFilesExample)
+	MachineConfigDoc.Fields[12].Name = "env"
+	MachineConfigDoc.Fields[12].Type = "Env"
+	MachineConfigDoc.Fields[12].Note = ""
+	MachineConfigDoc.Fields[12].Description = "The `env` field allows for the addition of environment variables.\nAll environment variables are set on PID 1 in addition to every service."
+	MachineConfigDoc.Fields[12].Comments[encoder.LineComment] = "The `env` field allows for the addition of environment variables."
+
+	MachineConfigDoc.Fields[12].AddExample("Environment variables definition examples.", machineEnvExamples[0])
 
-	MachineConfigDoc.Fields[9].AddExample("", machineEnvExamples[1])
+	MachineConfigDoc.Fields[12].AddExample("", machineEnvExamples[1])
 
-	MachineConfigDoc.Fields[9].AddExample("", machineEnvExamples[2])
-	MachineConfigDoc.Fields[9].Values = []string{
+	MachineConfigDoc.Fields[12].AddExample("", machineEnvExamples[2])
+	MachineConfigDoc.Fields[12].Values = []string{
 		"`GRPC_GO_LOG_VERBOSITY_LEVEL`",
 		"`GRPC_GO_LOG_SEVERITY_LEVEL`",
 		"`http_proxy`",
 		"`https_proxy`",
 		"`no_proxy`",
 	}
-	MachineConfigDoc.Fields[10].Name = "time"
-	MachineConfigDoc.Fields[10].Type = "TimeConfig"
-	MachineConfigDoc.Fields[10].Note = ""
-	MachineConfigDoc.Fields[10].Description = "Used to configure the machine's time settings."
-	MachineConfigDoc.Fields[10].Comments[encoder.LineComment] = "Used to configure the machine's time settings."
-
-	MachineConfigDoc.Fields[10].AddExample("Example configuration for cloudflare ntp server.", machineTimeExample)
-	MachineConfigDoc.Fields[11].Name = "sysctls"
-	MachineConfigDoc.Fields[11].Type = "map[string]string"
-	MachineConfigDoc.Fields[11].Note = ""
-	MachineConfigDoc.Fields[11].Description = "Used to configure the machine's sysctls."
-	MachineConfigDoc.Fields[11].Comments[encoder.LineComment] = "Used to configure the machine's sysctls."
-
-	MachineConfigDoc.Fields[11].AddExample("MachineSysctls usage example.", machineSysctlsExample)
-	MachineConfigDoc.Fields[12].Name = "registries"
-	MachineConfigDoc.Fields[12].Type = "RegistriesConfig"
-	MachineConfigDoc.Fields[12].Note = ""
-	MachineConfigDoc.Fields[12].Description = "Used to configure the machine's container image registry mirrors.\n\nAutomatically generates matching CRI configuration for registry mirrors.\n\nThe `mirrors` section allows to redirect requests for images to non-default registry,\nwhich might be local registry or caching mirror.\n\nThe `config` section provides a way to authenticate to the registry with TLS client\nidentity, provide registry CA, or authentication information.\nAuthentication information has same meaning with the corresponding field in `.docker/config.json`.\n\nSee also matching configuration for [CRI containerd plugin](https://github.com/containerd/cri/blob/master/docs/registry.md)."
-	MachineConfigDoc.Fields[12].Comments[encoder.LineComment] = "Used to configure the machine's container image registry mirrors."
-
-	MachineConfigDoc.Fields[12].AddExample("", machineConfigRegistriesExample)
+	MachineConfigDoc.Fields[13].Name = "time"
+	MachineConfigDoc.Fields[13].Type = "TimeConfig"
+	MachineConfigDoc.Fields[13].Note = ""
+	MachineConfigDoc.Fields[13].Description = "Used to configure the machine's time settings."
+	MachineConfigDoc.Fields[13].Comments[encoder.LineComment] = "Used to configure the machine's time settings."
+
+	MachineConfigDoc.Fields[13].AddExample("Example configuration for cloudflare ntp server.", machineTimeExample)
+	MachineConfigDoc.Fields[14].Name = "sysctls"
+	MachineConfigDoc.Fields[14].Type = "map[string]string"
+	MachineConfigDoc.Fields[14].Note = ""
+	MachineConfigDoc.Fields[14].Description = "Used to configure the machine's sysctls."
+	MachineConfigDoc.Fields[14].Comments[encoder.LineComment] = "Used to configure the machine's sysctls."
+
+	MachineConfigDoc.Fields[14].AddExample("MachineSysctls usage example.", machineSysctlsExample)
+	MachineConfigDoc.Fields[15].Name = "registries"
+	MachineConfigDoc.Fields[15].Type = "RegistriesConfig"
+	MachineConfigDoc.Fields[15].Note = ""
+	MachineConfigDoc.Fields[15].Description = "Used to configure the machine's container image registry mirrors.\n\nAutomatically generates matching CRI configuration for registry mirrors.\n\nThe `mirrors` section allows to redirect requests for images to non-default registry,\nwhich might be local registry or caching mirror.\n\nThe `config` section provides a way to authenticate to the registry with TLS client\nidentity, provide registry CA, or authentication information.\nAuthentication information has same meaning with the corresponding field in `.docker/config.json`.\n\nSee also matching configuration for [CRI containerd plugin](https://github.com/containerd/cri/blob/master/docs/registry.md)."
+	MachineConfigDoc.Fields[15].Comments[encoder.LineComment] = "Used to configure the machine's container image registry mirrors."
+
+	MachineConfigDoc.Fields[15].AddExample("", machineConfigRegistriesExample)
+	MachineConfigDoc.Fields[16].Name = "logging"
+	MachineConfigDoc.Fields[16].Type = "LoggingConfig"
+	MachineConfigDoc.Fields[16].Note = ""
+	MachineConfigDoc.Fields[16].Description = "Used to configure the machine's logging output."
+	MachineConfigDoc.Fields[16].Comments[encoder.LineComment] = "Used to configure the machine's logging output."
+
+	MachineConfigDoc.Fields[16].AddExample("", machineLoggingExample)
+	MachineConfigDoc.Fields[17].Name = "features"
+	MachineConfigDoc.Fields[17].Type = "FeaturesConfig"
+	MachineConfigDoc.Fields[17].Note = ""
+	MachineConfigDoc.Fields[17].Description = "Enables and configures optional Talos features."
+	MachineConfigDoc.Fields[17].Comments[encoder.LineComment] = "Enables and configures optional Talos features."
+
+	MachineConfigDoc.Fields[17].AddExample("", machineFeaturesExample)
+	MachineConfigDoc.Fields[18].Name = "bmc"
+	MachineConfigDoc.Fields[18].Type = "BMCConfig"
+	MachineConfigDoc.Fields[18].Note = ""
+	MachineConfigDoc.Fields[18].Description = "Configures the network settings of the machine BMC via the in-band IPMI interface.\nSettings are applied on boot, BMC network settings are not changed if this section is omitted."
+	MachineConfigDoc.Fields[18].Comments[encoder.LineComment] = "Configures the network settings of the machine BMC via the in-band IPMI interface."
+
+	MachineConfigDoc.Fields[18].AddExample("", machineBMCExample)
+	MachineConfigDoc.Fields[19].Name = "systemDiskEncryption"
+	MachineConfigDoc.Fields[19].Type = "SystemDiskEncryptionConfig"
+	MachineConfigDoc.Fields[19].Note = ""
+	MachineConfigDoc.Fields[19].Description = "Encrypts the system partitions with the keys sealed to the TPM.\nOnly the `EPHEMERAL` partition is supported.\n\nThe partition is wiped and encrypted on the next boot after the encryption is enabled.\nDisabling the encryption or changing the keys requires `talosctl reset --system-labels-to-wipe EPHEMERAL`."
+	MachineConfigDoc.Fields[19].Comments[encoder.LineComment] = "Encrypts the system partitions with the keys sealed to the TPM."
+
+	MachineConfigDoc.Fields[19].AddExample("", machineSystemDiskEncryptionExample)
+	MachineConfigDoc.Fields[20].Name = "kernel"
+	MachineConfigDoc.Fields[20].Type = "KernelConfig"
+	MachineConfigDoc.Fields[20].Note = ""
+	MachineConfigDoc.Fields[20].Description = "Configures the kernel modules loaded on boot and the CPU isolation."
+	MachineConfigDoc.Fields[20].Comments[encoder.LineComment] = "Configures the kernel modules loaded on boot and the CPU isolation."
+
+	MachineConfigDoc.Fields[20].AddExample("", machineKernelExample)
+	MachineConfigDoc.Fields[21].Name = "cri"
+	MachineConfigDoc.Fields[21].Type = "CRIConfig"
+	MachineConfigDoc.Fields[21].Note = ""
+	MachineConfigDoc.Fields[21].Description = "Configures the CRI plugin of containerd, e.g. additional runtime handlers."
+	MachineConfigDoc.Fields[21].Comments[encoder.LineComment] = "Configures the CRI plugin of containerd, e.g. additional runtime handlers."
+
+	MachineConfigDoc.Fields[21].AddExample("", machineCRIExample)
+	MachineConfigDoc.Fields[22].Name = "extensionServices"
+	MachineConfigDoc.Fields[22].Type = "[]ExtensionServiceConfig"
+	MachineConfigDoc.Fields[22].Note = ""
+	MachineConfigDoc.Fields[22].Description = "Additional system services run by `machined` from the OCI images, e.g. storage daemons or VPN clients.\nService IDs are prefixed with `ext-`, e.g. `talosctl service ext-vpn`."
+	MachineConfigDoc.Fields[22].Comments[encoder.LineComment] = "Additional system services run by `machined` from the OCI images, e.g. storage daemons or VPN clients."
+
+	MachineConfigDoc.Fields[22].AddExample("", machineExtensionServicesExample)
+	MachineConfigDoc.Fields[23].Name = "serviceResources"
+	MachineConfigDoc.Fields[23].Type = "map[string]ServiceResourcesConfig"
+	MachineConfigDoc.Fields[23].Note = ""
+	MachineConfigDoc.Fields[23].Description = "CPU, memory and OOM score settings of the system services.\nSupported services are `etcd`, `kubelet`, `apid`, `containerd` and `cri`.\n\nBy default etcd gets the double CPU weight, and etcd, kubelet, apid and both containerd instances get\nthe OOM score adjustments which make the OOM killer pick the workloads first."
+	MachineConfigDoc.Fields[23].Comments[encoder.LineComment] = "CPU, memory and OOM score settings of the system services."
+
+	MachineConfigDoc.Fields[23].AddExample("", machineServiceResourcesExample)
+	MachineConfigDoc.Fields[24].Name = "proxy"
+	MachineConfigDoc.Fields[24].Type = "MachineProxyConfig"
+	MachineConfigDoc.Fields[24].Note = ""
+	MachineConfigDoc.Fields[24].Description = "HTTP proxy settings of the machine: image pulls, extra manifest downloads, cluster discovery and every service.\n`NO_PROXY` is populated automatically with the cluster pod and service subnets, the control plane endpoint\nand the node addresses, so that the cluster traffic bypasses the proxy.\nThe proxy environment variables shouldn't be also set in `machine.env`."
+	MachineConfigDoc.Fields[24].Comments[encoder.LineComment] = "HTTP proxy settings of the machine: image pulls, extra manifest downloads, cluster discovery and every service."
+
+	MachineConfigDoc.Fields[24].AddExample("", machineProxyExample)
+
+	MachineProxyConfigDoc.Type = "MachineProxyConfig"
+	MachineProxyConfigDoc.Comments[encoder.LineComment] = "MachineProxyConfig represents the HTTP proxy settings."
+	MachineProxyConfigDoc.Description = "MachineProxyConfig represents the HTTP proxy settings."
+
+	MachineProxyConfigDoc.AddExample("", machineProxyExample)
+	MachineProxyConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "proxy",
+		},
+	}
+	MachineProxyConfigDoc.Fields = make([]encoder.Doc, 4)
+	MachineProxyConfigDoc.Fields[0].Name = "httpProxy"
+	MachineProxyConfigDoc.Fields[0].Type = "string"
+	MachineProxyConfigDoc.Fields[0].Note = ""
+	MachineProxyConfigDoc.Fields[0].Description = "The proxy for the HTTP requests (`HTTP_PROXY`)."
+	MachineProxyConfigDoc.Fields[0].Comments[encoder.LineComment] = "The proxy for the HTTP requests (`HTTP_PROXY`)."
+	MachineProxyConfigDoc.Fields[1].Name = "httpsProxy"
+	MachineProxyConfigDoc.Fields[1].Type = "string"
+	MachineProxyConfigDoc.Fields[1].Note = ""
+	MachineProxyConfigDoc.Fields[1].Description = "The proxy for the HTTPS requests (`HTTPS_PROXY`)."
+	MachineProxyConfigDoc.Fields[1].Comments[encoder.LineComment] = "The proxy for the HTTPS requests (`HTTPS_PROXY`)."
+	MachineProxyConfigDoc.Fields[2].Name = "noProxy"
+	MachineProxyConfigDoc.Fields[2].Type = "[]string"
+	MachineProxyConfigDoc.Fields[2].Note = ""
+	MachineProxyConfigDoc.Fields[2].Description = "Additional hosts, domains (e.g. `.corp.example.com`) and subnets which bypass the proxy."
+	MachineProxyConfigDoc.Fields[2].Comments[encoder.LineComment] = "Additional hosts, domains (e.g. `.corp.example.com`) and subnets which bypass the proxy."
+	MachineProxyConfigDoc.Fields[3].Name = "disableAutoNoProxy"
+	MachineProxyConfigDoc.Fields[3].Type = "bool"
+	MachineProxyConfigDoc.Fields[3].Note = ""
+	MachineProxyConfigDoc.Fields[3].Description = "Disables the automatic `NO_PROXY` entries, only `noProxy` is used."
+	MachineProxyConfigDoc.Fields[3].Comments[encoder.LineComment] = "Disables the automatic `NO_PROXY` entries, only `noProxy` is used."
 
 	ClusterConfigDoc.Type = "ClusterConfig"
 	ClusterConfigDoc.Comments[encoder.LineComment] = "ClusterConfig represents the cluster-wide config values."
@@ -223,7 +390,7 @@ func init() {
 			FieldName: "cluster",
 		},
 	}
-	ClusterConfigDoc.Fields = make([]encoder.Doc, 17)
+	ClusterConfigDoc.Fields = make([]encoder.Doc, 25)
 	ClusterConfigDoc.Fields[0].Name = "controlPlane"
 	ClusterConfigDoc.Fields[0].Type = "ControlPlaneConfig"
 	ClusterConfigDoc.Fields[0].Note = ""
@@ -264,93 +431,152 @@ func init() {
 	ClusterConfigDoc.Fields[5].Comments[encoder.LineComment] = "The base64 encoded root certificate authority used by Kubernetes."
 
 	ClusterConfigDoc.Fields[5].AddExample("ClusterCA example.", pemEncodedCertificateExample)
-	ClusterConfigDoc.Fields[6].Name = "apiServer"
-	ClusterConfigDoc.Fields[6].Type = "APIServerConfig"
+	ClusterConfigDoc.Fields[6].Name = "aggregatorCA"
+	ClusterConfigDoc.Fields[6].Type = "PEMEncodedCertificateAndKey"
 	ClusterConfigDoc.Fields[6].Note = ""
-	ClusterConfigDoc.Fields[6].Description = "API server specific configuration options."
-	ClusterConfigDoc.Fields[6].Comments[encoder.LineComment] = "API server specific configuration options."
+	ClusterConfigDoc.Fields[6].Description = "The base64 encoded aggregator certificate authority used by Kubernetes for front-proxy certificate generation.\n\nThis CA can be self-signed.\nIf set together with `serviceAccount`, the control plane components run as static pods managed by Talos."
+	ClusterConfigDoc.Fields[6].Comments[encoder.LineComment] = "The base64 encoded aggregator certificate authority used by Kubernetes for front-proxy certificate generation."
 
-	ClusterConfigDoc.Fields[6].AddExample("", clusterAPIServerExample)
-	ClusterConfigDoc.Fields[7].Name = "controllerManager"
-	ClusterConfigDoc.Fields[7].Type = "ControllerManagerConfig"
+	ClusterConfigDoc.Fields[6].AddExample("AggregatorCA example.", pemEncodedCertificateExample)
+	ClusterConfigDoc.Fields[7].Name = "serviceAccount"
+	ClusterConfigDoc.Fields[7].Type = "PEMEncodedKey"
 	ClusterConfigDoc.Fields[7].Note = ""
-	ClusterConfigDoc.Fields[7].Description = "Controller manager server specific configuration options."
-	ClusterConfigDoc.Fields[7].Comments[encoder.LineComment] = "Controller manager server specific configuration options."
+	ClusterConfigDoc.Fields[7].Description = "The base64 encoded private key for service account token generation."
+	ClusterConfigDoc.Fields[7].Comments[encoder.LineComment] = "The base64 encoded private key for service account token generation."
 
-	ClusterConfigDoc.Fields[7].AddExample("", clusterControllerManagerExample)
-	ClusterConfigDoc.Fields[8].Name = "proxy"
-	ClusterConfigDoc.Fields[8].Type = "ProxyConfig"
+	ClusterConfigDoc.Fields[7].AddExample("ServiceAccount example.", pemEncodedKeyExample)
+	ClusterConfigDoc.Fields[8].Name = "apiServer"
+	ClusterConfigDoc.Fields[8].Type = "APIServerConfig"
 	ClusterConfigDoc.Fields[8].Note = ""
-	ClusterConfigDoc.Fields[8].Description = "Kube-proxy server-specific configuration options"
-	ClusterConfigDoc.Fields[8].Comments[encoder.LineComment] = "Kube-proxy server-specific configuration options"
+	ClusterConfigDoc.Fields[8].Description = "API server specific configuration options."
+	ClusterConfigDoc.Fields[8].Comments[encoder.LineComment] = "API server specific configuration options."
 
-	ClusterConfigDoc.Fields[8].AddExample("", clusterProxyExample)
-	ClusterConfigDoc.Fields[9].Name = "scheduler"
-	ClusterConfigDoc.Fields[9].Type = "SchedulerConfig"
+	ClusterConfigDoc.Fields[8].AddExample("", clusterAPIServerExample)
+	ClusterConfigDoc.Fields[9].Name = "controllerManager"
+	ClusterConfigDoc.Fields[9].Type = "ControllerManagerConfig"
 	ClusterConfigDoc.Fields[9].Note = ""
-	ClusterConfigDoc.Fields[9].Description = "Scheduler server specific configuration options."
-	ClusterConfigDoc.Fields[9].Comments[encoder.LineComment] = "Scheduler server specific configuration options."
+	ClusterConfigDoc.Fields[9].Description = "Controller manager server specific configuration options."
+	ClusterConfigDoc.Fields[9].Comments[encoder.LineComment] = "Controller manager server specific configuration options."
 
-	ClusterConfigDoc.Fields[9].AddExample("", clusterSchedulerExample)
-	ClusterConfigDoc.Fields[10].Name = "etcd"
-	ClusterConfigDoc.Fields[10].Type = "EtcdConfig"
+	ClusterConfigDoc.Fields[9].AddExample("", clusterControllerManagerExample)
+	ClusterConfigDoc.Fields[10].Name = "proxy"
+	ClusterConfigDoc.Fields[10].Type = "ProxyConfig"
 	ClusterConfigDoc.Fields[10].Note = ""
-	ClusterConfigDoc.Fields[10].Description = "Etcd specific configuration options."
-	ClusterConfigDoc.Fields[10].Comments[encoder.LineComment] = "Etcd specific configuration options."
+	ClusterConfigDoc.Fields[10].Description = "Kube-proxy server-specific configuration options"
+	ClusterConfigDoc.Fields[10].Comments[encoder.LineComment] = "Kube-proxy server-specific configuration options"
 
-	ClusterConfigDoc.Fields[10].AddExample("", clusterEtcdExample)
-	ClusterConfigDoc.Fields[11].Name = "podCheckpointer"
-	ClusterConfigDoc.Fields[11].Type = "PodCheckpointer"
+	ClusterConfigDoc.Fields[10].AddExample("", clusterProxyExample)
+	ClusterConfigDoc.Fields[11].Name = "scheduler"
+	ClusterConfigDoc.Fields[11].Type = "SchedulerConfig"
 	ClusterConfigDoc.Fields[11].Note = ""
-	ClusterConfigDoc.Fields[11].Description = "Pod Checkpointer specific configuration options."
-	ClusterConfigDoc.Fields[11].Comments[encoder.LineComment] = "Pod Checkpointer specific configuration options."
+	ClusterConfigDoc.Fields[11].Description = "Scheduler server specific configuration options."
+	ClusterConfigDoc.Fields[11].Comments[encoder.LineComment] = "Scheduler server specific configuration options."
 
-	ClusterConfigDoc.Fields[11].AddExample("", clusterPodCheckpointerExample)
-	ClusterConfigDoc.Fields[12].Name = "coreDNS"
-	ClusterConfigDoc.Fields[12].Type = "CoreDNS"
+	ClusterConfigDoc.Fields[11].AddExample("", clusterSchedulerExample)
+	ClusterConfigDoc.Fields[12].Name = "etcd"
+	ClusterConfigDoc.Fields[12].Type = "EtcdConfig"
 	ClusterConfigDoc.Fields[12].Note = ""
-	ClusterConfigDoc.Fields[12].Description = "Core DNS specific configuration options."
-	ClusterConfigDoc.Fields[12].Comments[encoder.LineComment] = "Core DNS specific configuration options."
+	ClusterConfigDoc.Fields[12].Description = "Etcd specific configuration options."
+	ClusterConfigDoc.Fields[12].Comments[encoder.LineComment] = "Etcd specific configuration options."
 
-	ClusterConfigDoc.Fields[12].AddExample("", clusterCoreDNSExample)
-	ClusterConfigDoc.Fields[13].Name = "extraManifests"
-	ClusterConfigDoc.Fields[13].Type = "[]string"
+	ClusterConfigDoc.Fields[12].AddExample("", clusterEtcdExample)
+	ClusterConfigDoc.Fields[13].Name = "podCheckpointer"
+	ClusterConfigDoc.Fields[13].Type = "PodCheckpointer"
 	ClusterConfigDoc.Fields[13].Note = ""
-	ClusterConfigDoc.Fields[13].Description = "A list of urls that point to additional manifests.\nThese will get automatically deployed by bootkube."
-	ClusterConfigDoc.Fields[13].Comments[encoder.LineComment] = "A list of urls that point to additional manifests."
+	ClusterConfigDoc.Fields[13].Description = "Pod Checkpointer specific configuration options."
+	ClusterConfigDoc.Fields[13].Comments[encoder.LineComment] = "Pod Checkpointer specific configuration options."
+
+	ClusterConfigDoc.Fields[13].AddExample("", clusterPodCheckpointerExample)
+	ClusterConfigDoc.Fields[14].Name = "coreDNS"
+	ClusterConfigDoc.Fields[14].Type = "CoreDNS"
+	ClusterConfigDoc.Fields[14].Note = ""
+	ClusterConfigDoc.Fields[14].Description = "Core DNS specific configuration options."
+	ClusterConfigDoc.Fields[14].Comments[encoder.LineComment] = "Core DNS specific configuration options."
+
+	ClusterConfigDoc.Fields[14].AddExample("", clusterCoreDNSExample)
+	ClusterConfigDoc.Fields[15].Name = "externalCloudProvider"
+	ClusterConfigDoc.Fields[15].Type = "ExternalCloudProviderConfig"
+	ClusterConfigDoc.Fields[15].Note = ""
+	ClusterConfigDoc.Fields[15].Description = "External cloud provider configuration."
+	ClusterConfigDoc.Fields[15].Comments[encoder.LineComment] = "External cloud provider configuration."
+
+	ClusterConfigDoc.Fields[15].AddExample("", clusterExternalCloudProviderConfigExample)
+	ClusterConfigDoc.Fields[16].Name = "extraManifests"
+	ClusterConfigDoc.Fields[16].Type = "[]string"
+	ClusterConfigDoc.Fields[16].Note = ""
+	ClusterConfigDoc.Fields[16].Description = "A list of urls that point to additional manifests.\nThese will get automatically deployed by bootkube."
+	ClusterConfigDoc.Fields[16].Comments[encoder.LineComment] = "A list of urls that point to additional manifests."
 
-	ClusterConfigDoc.Fields[13].AddExample("", []string{
+	ClusterConfigDoc.Fields[16].AddExample("", []string{
 		"https://www.example.com/manifest1.yaml",
 		"https://www.example.com/manifest2.yaml",
 	})
-	ClusterConfigDoc.Fields[14].Name = "extraManifestHeaders"
-	ClusterConfigDoc.Fields[14].Type = "map[string]string"
-	ClusterConfigDoc.Fields[14].Note = ""
-	ClusterConfigDoc.Fields[14].Description = "A map of key value pairs that will be added while fetching the ExtraManifests."
-	ClusterConfigDoc.Fields[14].Comments[encoder.LineComment] = "A map of key value pairs that will be added while fetching the ExtraManifests."
+	ClusterConfigDoc.Fields[17].Name = "extraManifestHeaders"
+	ClusterConfigDoc.Fields[17].Type = "map[string]string"
+	ClusterConfigDoc.Fields[17].Note = ""
+	ClusterConfigDoc.Fields[17].Description = "A map of key value pairs that will be added while fetching the ExtraManifests."
+	ClusterConfigDoc.Fields[17].Comments[encoder.LineComment] = "A map of key value pairs that will be added while fetching the ExtraManifests."
 
-	ClusterConfigDoc.Fields[14].AddExample("", map[string]string{
+	ClusterConfigDoc.Fields[17].AddExample("", map[string]string{
 		"Token":       "1234567",
 		"X-ExtraInfo": "info",
 	})
-	ClusterConfigDoc.Fields[15].Name = "adminKubeconfig"
-	ClusterConfigDoc.Fields[15].Type = "AdminKubeconfigConfig"
-	ClusterConfigDoc.Fields[15].Note = ""
-	ClusterConfigDoc.Fields[15].Description = "Settings for admin kubeconfig generation.\nCertificate lifetime can be configured."
-	ClusterConfigDoc.Fields[15].Comments[encoder.LineComment] = "Settings for admin kubeconfig generation."
-
-	ClusterConfigDoc.Fields[15].AddExample("", clusterAdminKubeconfigExample)
-	ClusterConfigDoc.Fields[16].Name = "allowSchedulingOnMasters"
-	ClusterConfigDoc.Fields[16].Type = "bool"
-	ClusterConfigDoc.Fields[16].Note = ""
-	ClusterConfigDoc.Fields[16].Description = "Allows running workload on master nodes."
-	ClusterConfigDoc.Fields[16].Comments[encoder.LineComment] = "Allows running workload on master nodes."
-	ClusterConfigDoc.Fields[16].Values = []string{
+	ClusterConfigDoc.Fields[18].Name = "extraManifestChecksums"
+	ClusterConfigDoc.Fields[18].Type = "map[string]string"
+	ClusterConfigDoc.Fields[18].Note = ""
+	ClusterConfigDoc.Fields[18].Description = "SHA-256 checksums (hex-encoded) of the extra manifests by URL.\nThe manifests with the checksums set are verified after the download, the checksum is calculated before the template rendering."
+	ClusterConfigDoc.Fields[18].Comments[encoder.LineComment] = "SHA-256 checksums (hex-encoded) of the extra manifests by URL."
+
+	ClusterConfigDoc.Fields[18].AddExample("", map[string]string{
+		"https://www.example.com/manifest1.yaml": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+	})
+	ClusterConfigDoc.Fields[19].Name = "extraManifestTemplates"
+	ClusterConfigDoc.Fields[19].Type = "bool"
+	ClusterConfigDoc.Fields[19].Note = ""
+	ClusterConfigDoc.Fields[19].Description = "Render the extra manifests as Go templates, so that the same manifest can be used for multiple clusters.\nThe templates have access to the cluster name, endpoint, network settings, node labels, CA certificates\nand `extraManifestValues`, see the extra manifests guide for the details."
+	ClusterConfigDoc.Fields[19].Comments[encoder.LineComment] = "Render the extra manifests as Go templates, so that the same manifest can be used for multiple clusters."
+	ClusterConfigDoc.Fields[20].Name = "extraManifestValues"
+	ClusterConfigDoc.Fields[20].Type = "map[string]string"
+	ClusterConfigDoc.Fields[20].Note = ""
+	ClusterConfigDoc.Fields[20].Description = "Additional values available to the extra manifest templates as `.Values`.\nThe values can be secret references (e.g. `ref+sops://secrets.yaml#/license`), which are resolved by `talosctl apply-config`."
+	ClusterConfigDoc.Fields[20].Comments[encoder.LineComment] = "Additional values available to the extra manifest templates as `.Values`."
+
+	ClusterConfigDoc.Fields[20].AddExample("", map[string]string{
+		"region":     "eu-central-1",
+		"licenseKey": "ref+sops://secrets.yaml#/licenseKey",
+	})
+	ClusterConfigDoc.Fields[21].Name = "adminKubeconfig"
+	ClusterConfigDoc.Fields[21].Type = "AdminKubeconfigConfig"
+	ClusterConfigDoc.Fields[21].Note = ""
+	ClusterConfigDoc.Fields[21].Description = "Settings for admin kubeconfig generation.\nCertificate lifetime can be configured."
+	ClusterConfigDoc.Fields[21].Comments[encoder.LineComment] = "Settings for admin kubeconfig generation."
+
+	ClusterConfigDoc.Fields[21].AddExample("", clusterAdminKubeconfigExample)
+	ClusterConfigDoc.Fields[22].Name = "allowSchedulingOnMasters"
+	ClusterConfigDoc.Fields[22].Type = "bool"
+	ClusterConfigDoc.Fields[22].Note = ""
+	ClusterConfigDoc.Fields[22].Description = "Allows running workload on master nodes."
+	ClusterConfigDoc.Fields[22].Comments[encoder.LineComment] = "Allows running workload on master nodes."
+	ClusterConfigDoc.Fields[22].Values = []string{
 		"true",
 		"yes",
 		"false",
 		"no",
 	}
+	ClusterConfigDoc.Fields[23].Name = "joinThrottle"
+	ClusterConfigDoc.Fields[23].Type = "JoinThrottleConfig"
+	ClusterConfigDoc.Fields[23].Note = ""
+	ClusterConfigDoc.Fields[23].Description = "Throttling settings for the machines joining the cluster.\nControl plane nodes limit the number of certificate requests processed at once,\nwhile joining machines retry with exponential backoff and random jitter."
+	ClusterConfigDoc.Fields[23].Comments[encoder.LineComment] = "Throttling settings for the machines joining the cluster."
+
+	ClusterConfigDoc.Fields[23].AddExample("", clusterJoinThrottleExample)
+	ClusterConfigDoc.Fields[24].Name = "discovery"
+	ClusterConfigDoc.Fields[24].Type = "ClusterDiscoveryConfig"
+	ClusterConfigDoc.Fields[24].Note = ""
+	ClusterConfigDoc.Fields[24].Description = "Settings for cluster membership discovery.\nEach node registers its identity and addresses with the enabled registries,\nand the list of cluster members is built from the data published by other nodes."
+	ClusterConfigDoc.Fields[24].Comments[encoder.LineComment] = "Settings for cluster membership discovery."
+
+	ClusterConfigDoc.Fields[24].AddExample("", clusterDiscoveryExample)
 
 	KubeletConfigDoc.Type = "KubeletConfig"
 	KubeletConfigDoc.Comments[encoder.LineComment] = "KubeletConfig represents the kubelet config values."
@@ -363,7 +589,7 @@ func init() {
 			FieldName: "kubelet",
 		},
 	}
-	KubeletConfigDoc.Fields = make([]encoder.Doc, 3)
+	KubeletConfigDoc.Fields = make([]encoder.Doc, 14)
 	KubeletConfigDoc.Fields[0].Name = "image"
 	KubeletConfigDoc.Fields[0].Type = "string"
 	KubeletConfigDoc.Fields[0].Note = ""
@@ -387,6 +613,217 @@ func init() {
 	KubeletConfigDoc.Fields[2].Comments[encoder.LineComment] = "The `extraMounts` field is used to add additional mounts to the kubelet container."
 
 	KubeletConfigDoc.Fields[2].AddExample("", kubeletExtraMountsExample)
+	KubeletConfigDoc.Fields[3].Name = "drainTimeout"
+	KubeletConfigDoc.Fields[3].Type = "Duration"
+	KubeletConfigDoc.Fields[3].Note = ""
+	KubeletConfigDoc.Fields[3].Description = "Maximum time to wait for the node to be drained before a reboot, shutdown, reset or upgrade (default is 5 minutes).\nNode is cordoned and the pods are evicted, the disruptive action proceeds once the timeout expires.\nField format accepts any Go time.Duration format ('1h' for one hour, '10m' for ten minutes)."
+	KubeletConfigDoc.Fields[3].Comments[encoder.LineComment] = "Maximum time to wait for the node to be drained before a reboot, shutdown, reset or upgrade (default is 5 minutes)."
+	KubeletConfigDoc.Fields[4].Name = "credentialProviderConfig"
+	KubeletConfigDoc.Fields[4].Type = "KubeletCredentialProviderConfig"
+	KubeletConfigDoc.Fields[4].Note = ""
+	KubeletConfigDoc.Fields[4].Description = "The `credentialProviderConfig` field enables the kubelet exec credential provider plugins.\nThe plugins supply the image registry credentials (e.g. for ECR, GCR or ACR) without `imagePullSecrets`.\nThe plugin binaries are looked up in `/usr/libexec/kubernetes/kubelet-plugins/credential-provider/exec`."
+	KubeletConfigDoc.Fields[4].Comments[encoder.LineComment] = "The `credentialProviderConfig` field enables the kubelet exec credential provider plugins."
+
+	KubeletConfigDoc.Fields[4].AddExample("", kubeletCredentialProviderConfigExample)
+	KubeletConfigDoc.Fields[5].Name = "nodeIP"
+	KubeletConfigDoc.Fields[5].Type = "KubeletNodeIPConfig"
+	KubeletConfigDoc.Fields[5].Note = ""
+	KubeletConfigDoc.Fields[5].Description = "The `nodeIP` field configures the address the kubelet advertises as the Node IP (`--node-ip` flag).\nIt is used when the node has several addresses (e.g. storage or IPMI networks) to choose from."
+	KubeletConfigDoc.Fields[5].Comments[encoder.LineComment] = "The `nodeIP` field configures the address the kubelet advertises as the Node IP (`--node-ip` flag)."
+
+	KubeletConfigDoc.Fields[5].AddExample("", kubeletNodeIPExample)
+	KubeletConfigDoc.Fields[6].Name = "serverCertRotation"
+	KubeletConfigDoc.Fields[6].Type = "bool"
+	KubeletConfigDoc.Fields[6].Note = ""
+	KubeletConfigDoc.Fields[6].Description = "Enables the kubelet serving certificate rotation.\nThe kubelet requests the serving certificate signed by the Kubernetes CA instead of using a self-signed one,\nand the control plane nodes approve the certificate signing requests of the Talos nodes found by the cluster discovery.\nRequires the cluster discovery to be enabled."
+	KubeletConfigDoc.Fields[6].Comments[encoder.LineComment] = "Enables the kubelet serving certificate rotation."
+	KubeletConfigDoc.Fields[7].Name = "systemReserved"
+	KubeletConfigDoc.Fields[7].Type = "map[string]string"
+	KubeletConfigDoc.Fields[7].Note = ""
+	KubeletConfigDoc.Fields[7].Description = "The resources reserved for the OS system daemons (kubelet `systemReserved`), e.g. `cpu`, `memory`, `ephemeral-storage` and `pid`.\nTalos reserves CPU and memory for its services by default, the values set here override the defaults."
+	KubeletConfigDoc.Fields[7].Comments[encoder.LineComment] = "The resources reserved for the OS system daemons (kubelet `systemReserved`), e.g. `cpu`, `memory`, `ephemeral-storage` and `pid`."
+
+	KubeletConfigDoc.Fields[7].AddExample("", map[string]string{
+		"cpu":    "250m",
+		"memory": "512Mi",
+	})
+	KubeletConfigDoc.Fields[8].Name = "kubeReserved"
+	KubeletConfigDoc.Fields[8].Type = "map[string]string"
+	KubeletConfigDoc.Fields[8].Note = ""
+	KubeletConfigDoc.Fields[8].Description = "The resources reserved for the Kubernetes system daemons (kubelet `kubeReserved`), e.g. `cpu`, `memory`, `ephemeral-storage` and `pid`.\nBy default CPU and memory are reserved based on the node size, the values set here override the defaults."
+	KubeletConfigDoc.Fields[8].Comments[encoder.LineComment] = "The resources reserved for the Kubernetes system daemons (kubelet `kubeReserved`), e.g. `cpu`, `memory`, `ephemeral-storage` and `pid`."
+
+	KubeletConfigDoc.Fields[8].AddExample("", map[string]string{
+		"memory": "1Gi",
+	})
+	KubeletConfigDoc.Fields[9].Name = "cpuManagerPolicy"
+	KubeletConfigDoc.Fields[9].Type = "string"
+	KubeletConfigDoc.Fields[9].Note = ""
+	KubeletConfigDoc.Fields[9].Description = "The CPU manager policy of the kubelet.\nThe `static` policy assigns exclusive CPUs to the containers of the `Guaranteed` pods with integer CPU requests."
+	KubeletConfigDoc.Fields[9].Comments[encoder.LineComment] = "The CPU manager policy of the kubelet."
+	KubeletConfigDoc.Fields[9].Values = []string{
+		"none",
+		"static",
+	}
+	KubeletConfigDoc.Fields[10].Name = "reservedCPUs"
+	KubeletConfigDoc.Fields[10].Type = "string"
+	KubeletConfigDoc.Fields[10].Note = ""
+	KubeletConfigDoc.Fields[10].Description = "The CPUs reserved for the system and Kubernetes daemons (kubelet `reservedSystemCPUs`), in the kernel CPU list format.\nThe CPUs must be present on the node."
+	KubeletConfigDoc.Fields[10].Comments[encoder.LineComment] = "The CPUs reserved for the system and Kubernetes daemons (kubelet `reservedSystemCPUs`), in the kernel CPU list format."
+
+	KubeletConfigDoc.Fields[10].AddExample("", "0-1")
+	KubeletConfigDoc.Fields[11].Name = "topologyManagerPolicy"
+	KubeletConfigDoc.Fields[11].Type = "string"
+	KubeletConfigDoc.Fields[11].Note = ""
+	KubeletConfigDoc.Fields[11].Description = "The topology manager policy of the kubelet, it aligns the CPUs, memory and devices of the pods on the NUMA nodes."
+	KubeletConfigDoc.Fields[11].Comments[encoder.LineComment] = "The topology manager policy of the kubelet, it aligns the CPUs, memory and devices of the pods on the NUMA nodes."
+	KubeletConfigDoc.Fields[11].Values = []string{
+		"none",
+		"best-effort",
+		"restricted",
+		"single-numa-node",
+	}
+	KubeletConfigDoc.Fields[12].Name = "topologyManagerScope"
+	KubeletConfigDoc.Fields[12].Type = "string"
+	KubeletConfigDoc.Fields[12].Note = ""
+	KubeletConfigDoc.Fields[12].Description = "The scope of the topology manager alignment."
+	KubeletConfigDoc.Fields[12].Comments[encoder.LineComment] = "The scope of the topology manager alignment."
+	KubeletConfigDoc.Fields[12].Values = []string{
+		"container",
+		"pod",
+	}
+	KubeletConfigDoc.Fields[13].Name = "memoryManager"
+	KubeletConfigDoc.Fields[13].Type = "KubeletMemoryManagerConfig"
+	KubeletConfigDoc.Fields[13].Note = ""
+	KubeletConfigDoc.Fields[13].Description = "The memory manager configuration of the kubelet (requires Kubernetes 1.21 or later)."
+	KubeletConfigDoc.Fields[13].Comments[encoder.LineComment] = "The memory manager configuration of the kubelet (requires Kubernetes 1.21 or later)."
+
+	KubeletConfigDoc.Fields[13].AddExample("", kubeletMemoryManagerExample)
+
+	KubeletMemoryManagerConfigDoc.Type = "KubeletMemoryManagerConfig"
+	KubeletMemoryManagerConfigDoc.Comments[encoder.LineComment] = "KubeletMemoryManagerConfig represents the kubelet memory manager configuration."
+	KubeletMemoryManagerConfigDoc.Description = "KubeletMemoryManagerConfig represents the kubelet memory manager configuration."
+
+	KubeletMemoryManagerConfigDoc.AddExample("", kubeletMemoryManagerExample)
+	KubeletMemoryManagerConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "KubeletConfig",
+			FieldName: "memoryManager",
+		},
+	}
+	KubeletMemoryManagerConfigDoc.Fields = make([]encoder.Doc, 2)
+	KubeletMemoryManagerConfigDoc.Fields[0].Name = "policy"
+	KubeletMemoryManagerConfigDoc.Fields[0].Type = "string"
+	KubeletMemoryManagerConfigDoc.Fields[0].Note = ""
+	KubeletMemoryManagerConfigDoc.Fields[0].Description = "The memory manager policy, the `Static` policy guarantees the memory of the `Guaranteed` pods on the NUMA nodes."
+	KubeletMemoryManagerConfigDoc.Fields[0].Comments[encoder.LineComment] = "The memory manager policy, the `Static` policy guarantees the memory of the `Guaranteed` pods on the NUMA nodes."
+	KubeletMemoryManagerConfigDoc.Fields[0].Values = []string{
+		"None",
+		"Static",
+	}
+	KubeletMemoryManagerConfigDoc.Fields[1].Name = "reservedMemory"
+	KubeletMemoryManagerConfigDoc.Fields[1].Type = "[]KubeletReservedMemoryConfig"
+	KubeletMemoryManagerConfigDoc.Fields[1].Note = ""
+	KubeletMemoryManagerConfigDoc.Fields[1].Description = "The memory reserved on the NUMA nodes.\nThe total should be equal to the sum of the system and Kubernetes reserved memory and the hard eviction threshold (100Mi).\nIf not specified, the total is reserved on the NUMA node 0."
+	KubeletMemoryManagerConfigDoc.Fields[1].Comments[encoder.LineComment] = "The memory reserved on the NUMA nodes."
+
+	KubeletReservedMemoryConfigDoc.Type = "KubeletReservedMemoryConfig"
+	KubeletReservedMemoryConfigDoc.Comments[encoder.LineComment] = "KubeletReservedMemoryConfig represents the memory reserved on a NUMA node."
+	KubeletReservedMemoryConfigDoc.Description = "KubeletReservedMemoryConfig represents the memory reserved on a NUMA node."
+	KubeletReservedMemoryConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "KubeletMemoryManagerConfig",
+			FieldName: "reservedMemory",
+		},
+	}
+	KubeletReservedMemoryConfigDoc.Fields = make([]encoder.Doc, 2)
+	KubeletReservedMemoryConfigDoc.Fields[0].Name = "numaNode"
+	KubeletReservedMemoryConfigDoc.Fields[0].Type = "uint32"
+	KubeletReservedMemoryConfigDoc.Fields[0].Note = ""
+	KubeletReservedMemoryConfigDoc.Fields[0].Description = "The ID of the NUMA node."
+	KubeletReservedMemoryConfigDoc.Fields[0].Comments[encoder.LineComment] = "The ID of the NUMA node."
+	KubeletReservedMemoryConfigDoc.Fields[1].Name = "memory"
+	KubeletReservedMemoryConfigDoc.Fields[1].Type = "string"
+	KubeletReservedMemoryConfigDoc.Fields[1].Note = ""
+	KubeletReservedMemoryConfigDoc.Fields[1].Description = "The reserved memory, e.g. `1Gi`."
+	KubeletReservedMemoryConfigDoc.Fields[1].Comments[encoder.LineComment] = "The reserved memory, e.g. `1Gi`."
+
+	KubeletNodeIPConfigDoc.Type = "KubeletNodeIPConfig"
+	KubeletNodeIPConfigDoc.Comments[encoder.LineComment] = "KubeletNodeIPConfig represents the kubelet node IP configuration."
+	KubeletNodeIPConfigDoc.Description = "KubeletNodeIPConfig represents the kubelet node IP configuration."
+
+	KubeletNodeIPConfigDoc.AddExample("", kubeletNodeIPExample)
+	KubeletNodeIPConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "KubeletConfig",
+			FieldName: "nodeIP",
+		},
+	}
+	KubeletNodeIPConfigDoc.Fields = make([]encoder.Doc, 1)
+	KubeletNodeIPConfigDoc.Fields[0].Name = "validSubnets"
+	KubeletNodeIPConfigDoc.Fields[0].Type = "[]string"
+	KubeletNodeIPConfigDoc.Fields[0].Note = ""
+	KubeletNodeIPConfigDoc.Fields[0].Description = "The subnets to pick the kubelet node IP from.\nAddresses are excluded with the negative match prefixed with `!`, e.g. `!10.0.0.0/8`.\nThe first matching IPv4 and the first matching IPv6 address are used, so for the dual stack there should be both IPv4 and IPv6 subnets.\nIf not specified, the kubelet picks the node IP on its own."
+	KubeletNodeIPConfigDoc.Fields[0].Comments[encoder.LineComment] = "The subnets to pick the kubelet node IP from."
+
+	KubeletCredentialProviderConfigDoc.Type = "KubeletCredentialProviderConfig"
+	KubeletCredentialProviderConfigDoc.Comments[encoder.LineComment] = "KubeletCredentialProviderConfig represents the kubelet credential provider configuration."
+	KubeletCredentialProviderConfigDoc.Description = "KubeletCredentialProviderConfig represents the kubelet credential provider configuration."
+
+	KubeletCredentialProviderConfigDoc.AddExample("", kubeletCredentialProviderConfigExample)
+	KubeletCredentialProviderConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "KubeletConfig",
+			FieldName: "credentialProviderConfig",
+		},
+	}
+	KubeletCredentialProviderConfigDoc.Fields = make([]encoder.Doc, 1)
+	KubeletCredentialProviderConfigDoc.Fields[0].Name = "providers"
+	KubeletCredentialProviderConfigDoc.Fields[0].Type = "[]KubeletCredentialProvider"
+	KubeletCredentialProviderConfigDoc.Fields[0].Note = ""
+	KubeletCredentialProviderConfigDoc.Fields[0].Description = "The credential provider plugins enabled in the kubelet.\nIf several providers match the image, the credentials of the provider listed first take precedence."
+	KubeletCredentialProviderConfigDoc.Fields[0].Comments[encoder.LineComment] = "The credential provider plugins enabled in the kubelet."
+
+	KubeletCredentialProviderDoc.Type = "KubeletCredentialProvider"
+	KubeletCredentialProviderDoc.Comments[encoder.LineComment] = "KubeletCredentialProvider represents the kubelet exec credential provider plugin."
+	KubeletCredentialProviderDoc.Description = "KubeletCredentialProvider represents the kubelet exec credential provider plugin."
+	KubeletCredentialProviderDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "KubeletCredentialProviderConfig",
+			FieldName: "providers",
+		},
+	}
+	KubeletCredentialProviderDoc.Fields = make([]encoder.Doc, 6)
+	KubeletCredentialProviderDoc.Fields[0].Name = "name"
+	KubeletCredentialProviderDoc.Fields[0].Type = "string"
+	KubeletCredentialProviderDoc.Fields[0].Note = ""
+	KubeletCredentialProviderDoc.Fields[0].Description = "The name of the plugin, it should match the name of the plugin binary."
+	KubeletCredentialProviderDoc.Fields[0].Comments[encoder.LineComment] = "The name of the plugin, it should match the name of the plugin binary."
+	KubeletCredentialProviderDoc.Fields[1].Name = "matchImages"
+	KubeletCredentialProviderDoc.Fields[1].Type = "[]string"
+	KubeletCredentialProviderDoc.Fields[1].Note = ""
+	KubeletCredentialProviderDoc.Fields[1].Description = "The images the plugin is invoked for.\nEach entry is the registry domain (globs like `*.azurecr.io` are supported) with the optional port and path."
+	KubeletCredentialProviderDoc.Fields[1].Comments[encoder.LineComment] = "The images the plugin is invoked for."
+	KubeletCredentialProviderDoc.Fields[2].Name = "defaultCacheDuration"
+	KubeletCredentialProviderDoc.Fields[2].Type = "Duration"
+	KubeletCredentialProviderDoc.Fields[2].Note = ""
+	KubeletCredentialProviderDoc.Fields[2].Description = "The duration the credentials are cached for if the plugin response doesn't specify it (default is 1 minute).\nField format accepts any Go time.Duration format ('1h' for one hour, '10m' for ten minutes)."
+	KubeletCredentialProviderDoc.Fields[2].Comments[encoder.LineComment] = "The duration the credentials are cached for if the plugin response doesn't specify it (default is 1 minute)."
+	KubeletCredentialProviderDoc.Fields[3].Name = "apiVersion"
+	KubeletCredentialProviderDoc.Fields[3].Type = "string"
+	KubeletCredentialProviderDoc.Fields[3].Note = ""
+	KubeletCredentialProviderDoc.Fields[3].Description = "The version of the request passed to the plugin (default is `credentialprovider.kubelet.k8s.io/v1alpha1`)."
+	KubeletCredentialProviderDoc.Fields[3].Comments[encoder.LineComment] = "The version of the request passed to the plugin (default is `credentialprovider.kubelet.k8s.io/v1alpha1`)."
+	KubeletCredentialProviderDoc.Fields[4].Name = "args"
+	KubeletCredentialProviderDoc.Fields[4].Type = "[]string"
+	KubeletCredentialProviderDoc.Fields[4].Note = ""
+	KubeletCredentialProviderDoc.Fields[4].Description = "The arguments passed to the plugin."
+	KubeletCredentialProviderDoc.Fields[4].Comments[encoder.LineComment] = "The arguments passed to the plugin."
+	KubeletCredentialProviderDoc.Fields[5].Name = "env"
+	KubeletCredentialProviderDoc.Fields[5].Type = "Env"
+	KubeletCredentialProviderDoc.Fields[5].Note = ""
+	KubeletCredentialProviderDoc.Fields[5].Description = "The environment variables passed to the plugin."
+	KubeletCredentialProviderDoc.Fields[5].Comments[encoder.LineComment] = "The environment variables passed to the plugin."
 
 	NetworkConfigDoc.Type = "NetworkConfig"
 	NetworkConfigDoc.Comments[encoder.LineComment] = "NetworkConfig represents the machine's networking config values."
@@ -438,7 +875,7 @@ func init() {
 			FieldName: "install",
 		},
 	}
-	InstallConfigDoc.Fields = make([]encoder.Doc, 5)
+	InstallConfigDoc.Fields = make([]encoder.Doc, 9)
 	InstallConfigDoc.Fields[0].Name = "disk"
 	InstallConfigDoc.Fields[0].Type = "string"
 	InstallConfigDoc.Fields[0].Note = ""
@@ -484,6 +921,68 @@ func init() {
 		"false",
 		"no",
 	}
+	InstallConfigDoc.Fields[5].Name = "ephemeralDisk"
+	InstallConfigDoc.Fields[5].Type = "string"
+	InstallConfigDoc.Fields[5].Note = ""
+	InstallConfigDoc.Fields[5].Description = "The disk used for the EPHEMERAL partition (`/var`).\nDefaults to the installation disk."
+	InstallConfigDoc.Fields[5].Comments[encoder.LineComment] = "The disk used for the EPHEMERAL partition (`/var`)."
+
+	InstallConfigDoc.Fields[5].AddExample("", "/dev/sdb")
+	InstallConfigDoc.Fields[6].Name = "ephemeralSize"
+	InstallConfigDoc.Fields[6].Type = "DiskSize"
+	InstallConfigDoc.Fields[6].Note = ""
+	InstallConfigDoc.Fields[6].Description = "The size of the EPHEMERAL partition: either bytes or human readable representation.\nBy default the partition occupies the rest of the disk, and it is grown on boot if the disk is expanded."
+	InstallConfigDoc.Fields[6].Comments[encoder.LineComment] = "The size of the EPHEMERAL partition: either bytes or human readable representation."
+
+	InstallConfigDoc.Fields[6].AddExample("", DiskSize(100000000000))
+	InstallConfigDoc.Fields[7].Name = "console"
+	InstallConfigDoc.Fields[7].Type = "InstallConsoleConfig"
+	InstallConfigDoc.Fields[7].Note = ""
+	InstallConfigDoc.Fields[7].Description = "Console output of the installed system.\nThe installer writes matching `console=` kernel arguments and the serial terminal settings\ninto the bootloader config."
+	InstallConfigDoc.Fields[7].Comments[encoder.LineComment] = "Console output of the installed system."
+
+	InstallConfigDoc.Fields[7].AddExample("", machineInstallConsoleExample)
+	InstallConfigDoc.Fields[8].Name = "enrollSecureBootKeys"
+	InstallConfigDoc.Fields[8].Type = "bool"
+	InstallConfigDoc.Fields[8].Note = ""
+	InstallConfigDoc.Fields[8].Description = "Indicates if the SecureBoot keys of the installer image should be enrolled\nif the firmware is in the SecureBoot setup mode.\nEnrolling the keys replaces the keys of the firmware vendor."
+	InstallConfigDoc.Fields[8].Comments[encoder.LineComment] = "Indicates if the SecureBoot keys of the installer image should be enrolled"
+	InstallConfigDoc.Fields[8].Values = []string{
+		"true",
+		"yes",
+		"false",
+		"no",
+	}
+
+	InstallConsoleConfigDoc.Type = "InstallConsoleConfig"
+	InstallConsoleConfigDoc.Comments[encoder.LineComment] = "InstallConsoleConfig represents the console output options."
+	InstallConsoleConfigDoc.Description = "InstallConsoleConfig represents the console output options."
+
+	InstallConsoleConfigDoc.AddExample("", machineInstallConsoleExample)
+	InstallConsoleConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "InstallConfig",
+			FieldName: "console",
+		},
+	}
+	InstallConsoleConfigDoc.Fields = make([]encoder.Doc, 3)
+	InstallConsoleConfigDoc.Fields[0].Name = "serial"
+	InstallConsoleConfigDoc.Fields[0].Type = "string"
+	InstallConsoleConfigDoc.Fields[0].Note = ""
+	InstallConsoleConfigDoc.Fields[0].Description = "Serial console device, e.g. `ttyS0` or `ttyAMA0`.\nSerial console replaces the serial consoles of the platform, and it becomes the primary console."
+	InstallConsoleConfigDoc.Fields[0].Comments[encoder.LineComment] = "Serial console device, e.g. `ttyS0` or `ttyAMA0`."
+
+	InstallConsoleConfigDoc.Fields[0].AddExample("", "ttyS1")
+	InstallConsoleConfigDoc.Fields[1].Name = "baudRate"
+	InstallConsoleConfigDoc.Fields[1].Type = "int"
+	InstallConsoleConfigDoc.Fields[1].Note = ""
+	InstallConsoleConfigDoc.Fields[1].Description = "Serial console baud rate.\nDefaults to `115200`."
+	InstallConsoleConfigDoc.Fields[1].Comments[encoder.LineComment] = "Serial console baud rate."
+	InstallConsoleConfigDoc.Fields[2].Name = "disableVGA"
+	InstallConsoleConfigDoc.Fields[2].Type = "bool"
+	InstallConsoleConfigDoc.Fields[2].Note = ""
+	InstallConsoleConfigDoc.Fields[2].Description = "Disables the console output to the VGA console (`tty0`, `tty1`).\nRequires the serial console to be set."
+	InstallConsoleConfigDoc.Fields[2].Comments[encoder.LineComment] = "Disables the console output to the VGA console (`tty0`, `tty1`)."
 
 	TimeConfigDoc.Type = "TimeConfig"
 	TimeConfigDoc.Comments[encoder.LineComment] = "TimeConfig represents the options for configuring time on a machine."
@@ -535,6 +1034,788 @@ func init() {
 
 	RegistriesConfigDoc.Fields[1].AddExample("", machineConfigRegistryConfigExample)
 
+	LoggingConfigDoc.Type = "LoggingConfig"
+	LoggingConfigDoc.Comments[encoder.LineComment] = "LoggingConfig represents the options for configuring logging on a machine."
+	LoggingConfigDoc.Description = "LoggingConfig represents the options for configuring logging on a machine."
+
+	LoggingConfigDoc.AddExample("", machineLoggingExample)
+	LoggingConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "logging",
+		},
+	}
+	LoggingConfigDoc.Fields = make([]encoder.Doc, 2)
+	LoggingConfigDoc.Fields[0].Name = "console"
+	LoggingConfigDoc.Fields[0].Type = "ConsoleLoggingConfig"
+	LoggingConfigDoc.Fields[0].Note = ""
+	LoggingConfigDoc.Fields[0].Description = "Controls what Talos prints to the physical console."
+	LoggingConfigDoc.Fields[0].Comments[encoder.LineComment] = "Controls what Talos prints to the physical console."
+
+	LoggingConfigDoc.Fields[0].AddExample("", machineLoggingExample.LoggingConsole)
+	LoggingConfigDoc.Fields[1].Name = "persistence"
+	LoggingConfigDoc.Fields[1].Type = "LogPersistenceConfig"
+	LoggingConfigDoc.Fields[1].Note = ""
+	LoggingConfigDoc.Fields[1].Description = "Keeps a copy of the service logs on the EPHEMERAL partition, so that logs survive reboots."
+	LoggingConfigDoc.Fields[1].Comments[encoder.LineComment] = "Keeps a copy of the service logs on the EPHEMERAL partition, so that logs survive reboots."
+
+	LoggingConfigDoc.Fields[1].AddExample("", machineLoggingExample.LoggingPersistence)
+
+	ConsoleLoggingConfigDoc.Type = "ConsoleLoggingConfig"
+	ConsoleLoggingConfigDoc.Comments[encoder.LineComment] = "ConsoleLoggingConfig represents the console output options."
+	ConsoleLoggingConfigDoc.Description = "ConsoleLoggingConfig represents the console output options."
+
+	ConsoleLoggingConfigDoc.AddExample("", machineLoggingExample.LoggingConsole)
+	ConsoleLoggingConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "LoggingConfig",
+			FieldName: "console",
+		},
+	}
+	ConsoleLoggingConfigDoc.Fields = make([]encoder.Doc, 3)
+	ConsoleLoggingConfigDoc.Fields[0].Name = "mode"
+	ConsoleLoggingConfigDoc.Fields[0].Type = "string"
+	ConsoleLoggingConfigDoc.Fields[0].Note = ""
+	ConsoleLoggingConfigDoc.Fields[0].Description = "The kind of output Talos prints to the console.\n\n- `none` prints nothing except kernel emergency messages.\n- `critical` prints kernel errors and Talos failures.\n- `events` prints sequence, phase, task and service state events.\n- `full` prints all machined logs.\n\nDefaults to `full`."
+	ConsoleLoggingConfigDoc.Fields[0].Comments[encoder.LineComment] = "The kind of output Talos prints to the console."
+	ConsoleLoggingConfigDoc.Fields[0].Values = []string{
+		"none",
+		"critical",
+		"events",
+		"full",
+	}
+	ConsoleLoggingConfigDoc.Fields[1].Name = "device"
+	ConsoleLoggingConfigDoc.Fields[1].Type = "string"
+	ConsoleLoggingConfigDoc.Fields[1].Note = ""
+	ConsoleLoggingConfigDoc.Fields[1].Description = "The device Talos writes console output to, e.g. a dedicated serial port.\nDefaults to `/dev/console`."
+	ConsoleLoggingConfigDoc.Fields[1].Comments[encoder.LineComment] = "The device Talos writes console output to, e.g. a dedicated serial port."
+
+	ConsoleLoggingConfigDoc.Fields[1].AddExample("", "/dev/ttyS1")
+	ConsoleLoggingConfigDoc.Fields[2].Name = "rateLimit"
+	ConsoleLoggingConfigDoc.Fields[2].Type = "int"
+	ConsoleLoggingConfigDoc.Fields[2].Note = ""
+	ConsoleLoggingConfigDoc.Fields[2].Description = "Maximum number of lines per second written to the console device.\nLines over the limit are dropped and a summary is printed instead.\nZero disables rate limiting."
+	ConsoleLoggingConfigDoc.Fields[2].Comments[encoder.LineComment] = "Maximum number of lines per second written to the console device."
+
+	LogPersistenceConfigDoc.Type = "LogPersistenceConfig"
+	LogPersistenceConfigDoc.Comments[encoder.LineComment] = "LogPersistenceConfig represents the persistent service logs options."
+	LogPersistenceConfigDoc.Description = "LogPersistenceConfig represents the persistent service logs options."
+
+	LogPersistenceConfigDoc.AddExample("", machineLoggingExample.LoggingPersistence)
+	LogPersistenceConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "LoggingConfig",
+			FieldName: "persistence",
+		},
+	}
+	LogPersistenceConfigDoc.Fields = make([]encoder.Doc, 3)
+	LogPersistenceConfigDoc.Fields[0].Name = "enabled"
+	LogPersistenceConfigDoc.Fields[0].Type = "bool"
+	LogPersistenceConfigDoc.Fields[0].Note = ""
+	LogPersistenceConfigDoc.Fields[0].Description = "Writes service logs to `/var/log/<service>.log` in addition to the in-memory buffers.\nLogs written before the EPHEMERAL partition is mounted are copied to the files once it is mounted."
+	LogPersistenceConfigDoc.Fields[0].Comments[encoder.LineComment] = "Writes service logs to `/var/log/<service>.log` in addition to the in-memory buffers."
+	LogPersistenceConfigDoc.Fields[1].Name = "maxSize"
+	LogPersistenceConfigDoc.Fields[1].Type = "int64"
+	LogPersistenceConfigDoc.Fields[1].Note = ""
+	LogPersistenceConfigDoc.Fields[1].Description = "Maximum size of the log file in bytes, log file is rotated once it grows over the limit.\nDefaults to 5 MiB."
+	LogPersistenceConfigDoc.Fields[1].Comments[encoder.LineComment] = "Maximum size of the log file in bytes, log file is rotated once it grows over the limit."
+	LogPersistenceConfigDoc.Fields[2].Name = "maxFiles"
+	LogPersistenceConfigDoc.Fields[2].Type = "int"
+	LogPersistenceConfigDoc.Fields[2].Note = ""
+	LogPersistenceConfigDoc.Fields[2].Description = "Number of rotated log files (`<service>.log.1`, `<service>.log.2`, ...) kept for each service.\nDefaults to 3."
+	LogPersistenceConfigDoc.Fields[2].Comments[encoder.LineComment] = "Number of rotated log files (`<service>.log.1`, `<service>.log.2`, ...) kept for each service."
+
+	KernelConfigDoc.Type = "KernelConfig"
+	KernelConfigDoc.Comments[encoder.LineComment] = "KernelConfig represents the kernel options."
+	KernelConfigDoc.Description = "KernelConfig represents the kernel options."
+
+	KernelConfigDoc.AddExample("", machineKernelExample)
+	KernelConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "kernel",
+		},
+	}
+	KernelConfigDoc.Fields = make([]encoder.Doc, 4)
+	KernelConfigDoc.Fields[0].Name = "modules"
+	KernelConfigDoc.Fields[0].Type = "[]KernelModuleConfig"
+	KernelConfigDoc.Fields[0].Note = ""
+	KernelConfigDoc.Fields[0].Description = "Kernel modules to load on boot, modules are loaded in the order they are listed."
+	KernelConfigDoc.Fields[0].Comments[encoder.LineComment] = "Kernel modules to load on boot, modules are loaded in the order they are listed."
+	KernelConfigDoc.Fields[1].Name = "isolatedCPUs"
+	KernelConfigDoc.Fields[1].Type = "string"
+	KernelConfigDoc.Fields[1].Note = ""
+	KernelConfigDoc.Fields[1].Description = "CPUs isolated from the general scheduler and the kernel housekeeping (`isolcpus` kernel argument), in the kernel CPU list format.\nKubernetes CPU manager with the `static` policy can assign them exclusively to the latency-sensitive pods.\nThe kernel arguments are applied on install and upgrade."
+	KernelConfigDoc.Fields[1].Comments[encoder.LineComment] = "CPUs isolated from the general scheduler and the kernel housekeeping (`isolcpus` kernel argument), in the kernel CPU list format."
+
+	KernelConfigDoc.Fields[1].AddExample("", "2-7")
+	KernelConfigDoc.Fields[2].Name = "nohzFullCPUs"
+	KernelConfigDoc.Fields[2].Type = "string"
+	KernelConfigDoc.Fields[2].Note = ""
+	KernelConfigDoc.Fields[2].Description = "CPUs which run without the scheduling-clock ticks when they have a single task (`nohz_full` and `rcu_nocbs` kernel arguments).\nCPU 0 can't be in the list, as it keeps the timekeeping duty.\nThe kernel arguments are applied on install and upgrade."
+	KernelConfigDoc.Fields[2].Comments[encoder.LineComment] = "CPUs which run without the scheduling-clock ticks when they have a single task (`nohz_full` and `rcu_nocbs` kernel arguments)."
+
+	KernelConfigDoc.Fields[2].AddExample("", "2-7")
+	KernelConfigDoc.Fields[3].Name = "irqAffinityCPUs"
+	KernelConfigDoc.Fields[3].Type = "string"
+	KernelConfigDoc.Fields[3].Note = ""
+	KernelConfigDoc.Fields[3].Description = "CPUs which handle the hardware interrupts (`irqaffinity` kernel argument).\nThe default IRQ affinity and the affinity of the existing interrupts are also set on boot."
+	KernelConfigDoc.Fields[3].Comments[encoder.LineComment] = "CPUs which handle the hardware interrupts (`irqaffinity` kernel argument)."
+
+	KernelConfigDoc.Fields[3].AddExample("", "0-1")
+
+	KernelModuleConfigDoc.Type = "KernelModuleConfig"
+	KernelModuleConfigDoc.Comments[encoder.LineComment] = "KernelModuleConfig represents the kernel module options."
+	KernelModuleConfigDoc.Description = "KernelModuleConfig represents the kernel module options."
+	KernelModuleConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "KernelConfig",
+			FieldName: "modules",
+		},
+	}
+	KernelModuleConfigDoc.Fields = make([]encoder.Doc, 2)
+	KernelModuleConfigDoc.Fields[0].Name = "name"
+	KernelModuleConfigDoc.Fields[0].Type = "string"
+	KernelModuleConfigDoc.Fields[0].Note = ""
+	KernelModuleConfigDoc.Fields[0].Description = "Name of the module in `/lib/modules` of the running kernel, or an absolute path to the module file.\nDependencies of the named module (as listed in `modules.dep`) are loaded first."
+	KernelModuleConfigDoc.Fields[0].Comments[encoder.LineComment] = "Name of the module in `/lib/modules` of the running kernel, or an absolute path to the module file."
+	KernelModuleConfigDoc.Fields[1].Name = "parameters"
+	KernelModuleConfigDoc.Fields[1].Type = "[]string"
+	KernelModuleConfigDoc.Fields[1].Note = ""
+	KernelModuleConfigDoc.Fields[1].Description = "Module parameters, e.g. `NVreg_EnableGpuFirmware=0`."
+	KernelModuleConfigDoc.Fields[1].Comments[encoder.LineComment] = "Module parameters, e.g. `NVreg_EnableGpuFirmware=0`."
+
+	ServiceResourcesConfigDoc.Type = "ServiceResourcesConfig"
+	ServiceResourcesConfigDoc.Comments[encoder.LineComment] = "ServiceResourcesConfig represents the resource limits of a system service."
+	ServiceResourcesConfigDoc.Description = "ServiceResourcesConfig represents the resource limits of a system service."
+
+	ServiceResourcesConfigDoc.AddExample("", machineServiceResourcesExample)
+	ServiceResourcesConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "serviceResources",
+		},
+	}
+	ServiceResourcesConfigDoc.Fields = make([]encoder.Doc, 5)
+	ServiceResourcesConfigDoc.Fields[0].Name = "cpuShares"
+	ServiceResourcesConfigDoc.Fields[0].Type = "uint64"
+	ServiceResourcesConfigDoc.Fields[0].Note = ""
+	ServiceResourcesConfigDoc.Fields[0].Description = "Relative CPU weight of the service, the default weight is 1024."
+	ServiceResourcesConfigDoc.Fields[0].Comments[encoder.LineComment] = "Relative CPU weight of the service, the default weight is 1024."
+	ServiceResourcesConfigDoc.Fields[1].Name = "cpuLimit"
+	ServiceResourcesConfigDoc.Fields[1].Type = "float64"
+	ServiceResourcesConfigDoc.Fields[1].Note = ""
+	ServiceResourcesConfigDoc.Fields[1].Description = "Maximum number of CPUs the service can use, e.g. `1.5`."
+	ServiceResourcesConfigDoc.Fields[1].Comments[encoder.LineComment] = "Maximum number of CPUs the service can use, e.g. `1.5`."
+	ServiceResourcesConfigDoc.Fields[2].Name = "memoryLimit"
+	ServiceResourcesConfigDoc.Fields[2].Type = "DiskSize"
+	ServiceResourcesConfigDoc.Fields[2].Note = ""
+	ServiceResourcesConfigDoc.Fields[2].Description = "Memory limit of the service: either bytes or human readable representation."
+	ServiceResourcesConfigDoc.Fields[2].Comments[encoder.LineComment] = "Memory limit of the service: either bytes or human readable representation."
+
+	ServiceResourcesConfigDoc.Fields[2].AddExample("", DiskSize(2000000000))
+	ServiceResourcesConfigDoc.Fields[3].Name = "memoryReservation"
+	ServiceResourcesConfigDoc.Fields[3].Type = "DiskSize"
+	ServiceResourcesConfigDoc.Fields[3].Note = ""
+	ServiceResourcesConfigDoc.Fields[3].Description = "Memory soft limit of the service, the kernel reclaims memory above it first under memory pressure."
+	ServiceResourcesConfigDoc.Fields[3].Comments[encoder.LineComment] = "Memory soft limit of the service, the kernel reclaims memory above it first under memory pressure."
+	ServiceResourcesConfigDoc.Fields[4].Name = "oomScoreAdj"
+	ServiceResourcesConfigDoc.Fields[4].Type = "int"
+	ServiceResourcesConfigDoc.Fields[4].Note = ""
+	ServiceResourcesConfigDoc.Fields[4].Description = "OOM score adjustment of the service process, from -1000 (never killed) to 1000."
+	ServiceResourcesConfigDoc.Fields[4].Comments[encoder.LineComment] = "OOM score adjustment of the service process, from -1000 (never killed) to 1000."
+
+	ExtensionServiceConfigDoc.Type = "ExtensionServiceConfig"
+	ExtensionServiceConfigDoc.Comments[encoder.LineComment] = "ExtensionServiceConfig represents the operator-supplied system service."
+	ExtensionServiceConfigDoc.Description = "ExtensionServiceConfig represents the operator-supplied system service."
+
+	ExtensionServiceConfigDoc.AddExample("", machineExtensionServicesExample)
+	ExtensionServiceConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "extensionServices",
+		},
+	}
+	ExtensionServiceConfigDoc.Fields = make([]encoder.Doc, 8)
+	ExtensionServiceConfigDoc.Fields[0].Name = "name"
+	ExtensionServiceConfigDoc.Fields[0].Type = "string"
+	ExtensionServiceConfigDoc.Fields[0].Note = ""
+	ExtensionServiceConfigDoc.Fields[0].Description = "Name of the service, the service ID is the name with the `ext-` prefix."
+	ExtensionServiceConfigDoc.Fields[0].Comments[encoder.LineComment] = "Name of the service, the service ID is the name with the `ext-` prefix."
+	ExtensionServiceConfigDoc.Fields[1].Name = "image"
+	ExtensionServiceConfigDoc.Fields[1].Type = "string"
+	ExtensionServiceConfigDoc.Fields[1].Note = ""
+	ExtensionServiceConfigDoc.Fields[1].Description = "OCI image of the service, pulled to the system containerd (and verified if image verification is enabled)."
+	ExtensionServiceConfigDoc.Fields[1].Comments[encoder.LineComment] = "OCI image of the service, pulled to the system containerd (and verified if image verification is enabled)."
+	ExtensionServiceConfigDoc.Fields[2].Name = "args"
+	ExtensionServiceConfigDoc.Fields[2].Type = "[]string"
+	ExtensionServiceConfigDoc.Fields[2].Note = ""
+	ExtensionServiceConfigDoc.Fields[2].Description = "Command and arguments of the service process.\nDefaults to the entrypoint of the image."
+	ExtensionServiceConfigDoc.Fields[2].Comments[encoder.LineComment] = "Command and arguments of the service process."
+	ExtensionServiceConfigDoc.Fields[3].Name = "env"
+	ExtensionServiceConfigDoc.Fields[3].Type = "map[string]string"
+	ExtensionServiceConfigDoc.Fields[3].Note = ""
+	ExtensionServiceConfigDoc.Fields[3].Description = "Environment variables of the service process."
+	ExtensionServiceConfigDoc.Fields[3].Comments[encoder.LineComment] = "Environment variables of the service process."
+	ExtensionServiceConfigDoc.Fields[4].Name = "mounts"
+	ExtensionServiceConfigDoc.Fields[4].Type = "[]Mount"
+	ExtensionServiceConfigDoc.Fields[4].Note = ""
+	ExtensionServiceConfigDoc.Fields[4].Description = "Host paths mounted into the service container."
+	ExtensionServiceConfigDoc.Fields[4].Comments[encoder.LineComment] = "Host paths mounted into the service container."
+	ExtensionServiceConfigDoc.Fields[5].Name = "restart"
+	ExtensionServiceConfigDoc.Fields[5].Type = "string"
+	ExtensionServiceConfigDoc.Fields[5].Note = ""
+	ExtensionServiceConfigDoc.Fields[5].Description = "Restart policy of the service."
+	ExtensionServiceConfigDoc.Fields[5].Comments[encoder.LineComment] = "Restart policy of the service."
+	ExtensionServiceConfigDoc.Fields[5].Values = []string{
+		"always",
+		"untilSuccess",
+		"never",
+	}
+	ExtensionServiceConfigDoc.Fields[6].Name = "dependsOn"
+	ExtensionServiceConfigDoc.Fields[6].Type = "[]string"
+	ExtensionServiceConfigDoc.Fields[6].Note = ""
+	ExtensionServiceConfigDoc.Fields[6].Description = "IDs of the services which should be running before the service is started, e.g. `networkd` or `ext-vpn`."
+	ExtensionServiceConfigDoc.Fields[6].Comments[encoder.LineComment] = "IDs of the services which should be running before the service is started, e.g. `networkd` or `ext-vpn`."
+	ExtensionServiceConfigDoc.Fields[7].Name = "security"
+	ExtensionServiceConfigDoc.Fields[7].Type = "ExtensionServiceSecurityConfig"
+	ExtensionServiceConfigDoc.Fields[7].Note = ""
+	ExtensionServiceConfigDoc.Fields[7].Description = "Security settings of the service container."
+	ExtensionServiceConfigDoc.Fields[7].Comments[encoder.LineComment] = "Security settings of the service container."
+
+	ExtensionServiceSecurityConfigDoc.Type = "ExtensionServiceSecurityConfig"
+	ExtensionServiceSecurityConfigDoc.Comments[encoder.LineComment] = "ExtensionServiceSecurityConfig represents the extension service container security settings."
+	ExtensionServiceSecurityConfigDoc.Description = "ExtensionServiceSecurityConfig represents the extension service container security settings."
+	ExtensionServiceSecurityConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "ExtensionServiceConfig",
+			FieldName: "security",
+		},
+	}
+	ExtensionServiceSecurityConfigDoc.Fields = make([]encoder.Doc, 4)
+	ExtensionServiceSecurityConfigDoc.Fields[0].Name = "privileged"
+	ExtensionServiceSecurityConfigDoc.Fields[0].Type = "bool"
+	ExtensionServiceSecurityConfigDoc.Fields[0].Note = ""
+	ExtensionServiceSecurityConfigDoc.Fields[0].Description = "Runs the container with all capabilities and access to the host devices."
+	ExtensionServiceSecurityConfigDoc.Fields[0].Comments[encoder.LineComment] = "Runs the container with all capabilities and access to the host devices."
+	ExtensionServiceSecurityConfigDoc.Fields[1].Name = "hostNetwork"
+	ExtensionServiceSecurityConfigDoc.Fields[1].Type = "bool"
+	ExtensionServiceSecurityConfigDoc.Fields[1].Note = ""
+	ExtensionServiceSecurityConfigDoc.Fields[1].Description = "Runs the container in the host network namespace."
+	ExtensionServiceSecurityConfigDoc.Fields[1].Comments[encoder.LineComment] = "Runs the container in the host network namespace."
+	ExtensionServiceSecurityConfigDoc.Fields[2].Name = "capabilities"
+	ExtensionServiceSecurityConfigDoc.Fields[2].Type = "[]string"
+	ExtensionServiceSecurityConfigDoc.Fields[2].Note = ""
+	ExtensionServiceSecurityConfigDoc.Fields[2].Description = "Additional Linux capabilities of the container, e.g. `CAP_NET_ADMIN`."
+	ExtensionServiceSecurityConfigDoc.Fields[2].Comments[encoder.LineComment] = "Additional Linux capabilities of the container, e.g. `CAP_NET_ADMIN`."
+	ExtensionServiceSecurityConfigDoc.Fields[3].Name = "writableRootfs"
+	ExtensionServiceSecurityConfigDoc.Fields[3].Type = "bool"
+	ExtensionServiceSecurityConfigDoc.Fields[3].Note = ""
+	ExtensionServiceSecurityConfigDoc.Fields[3].Description = "Mounts the root filesystem of the container read-write, it is read-only by default."
+	ExtensionServiceSecurityConfigDoc.Fields[3].Comments[encoder.LineComment] = "Mounts the root filesystem of the container read-write, it is read-only by default."
+
+	CRIConfigDoc.Type = "CRIConfig"
+	CRIConfigDoc.Comments[encoder.LineComment] = "CRIConfig represents the CRI plugin options."
+	CRIConfigDoc.Description = "CRIConfig represents the CRI plugin options."
+
+	CRIConfigDoc.AddExample("", machineCRIExample)
+	CRIConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "cri",
+		},
+	}
+	CRIConfigDoc.Fields = make([]encoder.Doc, 1)
+	CRIConfigDoc.Fields[0].Name = "runtimes"
+	CRIConfigDoc.Fields[0].Type = "map[string]CRIRuntimeConfig"
+	CRIConfigDoc.Fields[0].Note = ""
+	CRIConfigDoc.Fields[0].Description = "Additional runtime handlers of the CRI plugin.\nThe handler name is used as the `handler` of the Kubernetes `RuntimeClass`."
+	CRIConfigDoc.Fields[0].Comments[encoder.LineComment] = "Additional runtime handlers of the CRI plugin."
+
+	CRIRuntimeConfigDoc.Type = "CRIRuntimeConfig"
+	CRIRuntimeConfigDoc.Comments[encoder.LineComment] = "CRIRuntimeConfig represents the CRI runtime handler options."
+	CRIRuntimeConfigDoc.Description = "CRIRuntimeConfig represents the CRI runtime handler options."
+	CRIRuntimeConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "CRIConfig",
+			FieldName: "runtimes",
+		},
+	}
+	CRIRuntimeConfigDoc.Fields = make([]encoder.Doc, 2)
+	CRIRuntimeConfigDoc.Fields[0].Name = "runtimeType"
+	CRIRuntimeConfigDoc.Fields[0].Type = "string"
+	CRIRuntimeConfigDoc.Fields[0].Note = ""
+	CRIRuntimeConfigDoc.Fields[0].Description = "Runtime type of the handler.\nDefaults to `io.containerd.runc.v2`."
+	CRIRuntimeConfigDoc.Fields[0].Comments[encoder.LineComment] = "Runtime type of the handler."
+	CRIRuntimeConfigDoc.Fields[1].Name = "binaryName"
+	CRIRuntimeConfigDoc.Fields[1].Type = "string"
+	CRIRuntimeConfigDoc.Fields[1].Note = ""
+	CRIRuntimeConfigDoc.Fields[1].Description = "Absolute path to the OCI runtime binary used instead of `runc`, e.g. `/usr/local/bin/nvidia-container-runtime`."
+	CRIRuntimeConfigDoc.Fields[1].Comments[encoder.LineComment] = "Absolute path to the OCI runtime binary used instead of `runc`, e.g. `/usr/local/bin/nvidia-container-runtime`."
+
+	BMCConfigDoc.Type = "BMCConfig"
+	BMCConfigDoc.Comments[encoder.LineComment] = "BMCConfig represents the BMC network options."
+	BMCConfigDoc.Description = "BMCConfig represents the BMC network options."
+
+	BMCConfigDoc.AddExample("", machineBMCExample)
+	BMCConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "bmc",
+		},
+	}
+	BMCConfigDoc.Fields = make([]encoder.Doc, 4)
+	BMCConfigDoc.Fields[0].Name = "channel"
+	BMCConfigDoc.Fields[0].Type = "uint8"
+	BMCConfigDoc.Fields[0].Note = ""
+	BMCConfigDoc.Fields[0].Description = "IPMI LAN channel of the BMC.\nDefaults to 1."
+	BMCConfigDoc.Fields[0].Comments[encoder.LineComment] = "IPMI LAN channel of the BMC."
+	BMCConfigDoc.Fields[1].Name = "dhcp"
+	BMCConfigDoc.Fields[1].Type = "bool"
+	BMCConfigDoc.Fields[1].Note = ""
+	BMCConfigDoc.Fields[1].Description = "Enables DHCP on the BMC LAN channel.\nMutually exclusive with `address`."
+	BMCConfigDoc.Fields[1].Comments[encoder.LineComment] = "Enables DHCP on the BMC LAN channel."
+	BMCConfigDoc.Fields[2].Name = "address"
+	BMCConfigDoc.Fields[2].Type = "string"
+	BMCConfigDoc.Fields[2].Note = ""
+	BMCConfigDoc.Fields[2].Description = "Static IPv4 address of the BMC in CIDR notation."
+	BMCConfigDoc.Fields[2].Comments[encoder.LineComment] = "Static IPv4 address of the BMC in CIDR notation."
+	BMCConfigDoc.Fields[3].Name = "gateway"
+	BMCConfigDoc.Fields[3].Type = "string"
+	BMCConfigDoc.Fields[3].Note = ""
+	BMCConfigDoc.Fields[3].Description = "Default gateway of the BMC, used with the static address."
+	BMCConfigDoc.Fields[3].Comments[encoder.LineComment] = "Default gateway of the BMC, used with the static address."
+
+	SystemDiskEncryptionConfigDoc.Type = "SystemDiskEncryptionConfig"
+	SystemDiskEncryptionConfigDoc.Comments[encoder.LineComment] = "SystemDiskEncryptionConfig specifies the system partitions encryption settings."
+	SystemDiskEncryptionConfigDoc.Description = "SystemDiskEncryptionConfig specifies the system partitions encryption settings."
+
+	SystemDiskEncryptionConfigDoc.AddExample("", machineSystemDiskEncryptionExample)
+	SystemDiskEncryptionConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "systemDiskEncryption",
+		},
+	}
+	SystemDiskEncryptionConfigDoc.Fields = make([]encoder.Doc, 1)
+	SystemDiskEncryptionConfigDoc.Fields[0].Name = "ephemeral"
+	SystemDiskEncryptionConfigDoc.Fields[0].Type = "EncryptionConfig"
+	SystemDiskEncryptionConfigDoc.Fields[0].Note = ""
+	SystemDiskEncryptionConfigDoc.Fields[0].Description = "Encryption settings of the `EPHEMERAL` partition."
+	SystemDiskEncryptionConfigDoc.Fields[0].Comments[encoder.LineComment] = "Encryption settings of the `EPHEMERAL` partition."
+
+	EncryptionConfigDoc.Type = "EncryptionConfig"
+	EncryptionConfigDoc.Comments[encoder.LineComment] = "EncryptionConfig represents the partition encryption settings."
+	EncryptionConfigDoc.Description = "EncryptionConfig represents the partition encryption settings."
+	EncryptionConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "SystemDiskEncryptionConfig",
+			FieldName: "ephemeral",
+		},
+	}
+	EncryptionConfigDoc.Fields = make([]encoder.Doc, 1)
+	EncryptionConfigDoc.Fields[0].Name = "keys"
+	EncryptionConfigDoc.Fields[0].Type = "[]EncryptionKey"
+	EncryptionConfigDoc.Fields[0].Note = ""
+	EncryptionConfigDoc.Fields[0].Description = "Key slots of the partition, the volume key is unlocked with the first slot which can be unsealed."
+	EncryptionConfigDoc.Fields[0].Comments[encoder.LineComment] = "Key slots of the partition, the volume key is unlocked with the first slot which can be unsealed."
+
+	EncryptionKeyDoc.Type = "EncryptionKey"
+	EncryptionKeyDoc.Comments[encoder.LineComment] = "EncryptionKey represents a key slot of the encrypted partition."
+	EncryptionKeyDoc.Description = "EncryptionKey represents a key slot of the encrypted partition."
+	EncryptionKeyDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "EncryptionConfig",
+			FieldName: "keys",
+		},
+	}
+	EncryptionKeyDoc.Fields = make([]encoder.Doc, 2)
+	EncryptionKeyDoc.Fields[0].Name = "slot"
+	EncryptionKeyDoc.Fields[0].Type = "int"
+	EncryptionKeyDoc.Fields[0].Note = ""
+	EncryptionKeyDoc.Fields[0].Description = "Key slot number, unique within the partition."
+	EncryptionKeyDoc.Fields[0].Comments[encoder.LineComment] = "Key slot number, unique within the partition."
+	EncryptionKeyDoc.Fields[1].Name = "tpm"
+	EncryptionKeyDoc.Fields[1].Type = "EncryptionKeyTPM"
+	EncryptionKeyDoc.Fields[1].Note = ""
+	EncryptionKeyDoc.Fields[1].Description = "Seals the volume key to the TPM PCR values."
+	EncryptionKeyDoc.Fields[1].Comments[encoder.LineComment] = "Seals the volume key to the TPM PCR values."
+
+	EncryptionKeyTPMDoc.Type = "EncryptionKeyTPM"
+	EncryptionKeyTPMDoc.Comments[encoder.LineComment] = "EncryptionKeyTPM represents the key sealed to the TPM."
+	EncryptionKeyTPMDoc.Description = "EncryptionKeyTPM represents the key sealed to the TPM."
+	EncryptionKeyTPMDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "EncryptionKey",
+			FieldName: "tpm",
+		},
+	}
+	EncryptionKeyTPMDoc.Fields = make([]encoder.Doc, 1)
+	EncryptionKeyTPMDoc.Fields[0].Name = "pcrs"
+	EncryptionKeyTPMDoc.Fields[0].Type = "[]int"
+	EncryptionKeyTPMDoc.Fields[0].Note = ""
+	EncryptionKeyTPMDoc.Fields[0].Description = "PCRs the key is sealed to, the key is unsealed only if the PCR values match.\nPCR 7 (SecureBoot state and keys) is used by default.\nPCR 11 additionally binds the key to the kernel, the kernel command line and the machine config\nmeasured by Talos (see the measured boot guide)."
+	EncryptionKeyTPMDoc.Fields[0].Comments[encoder.LineComment] = "PCRs the key is sealed to, the key is unsealed only if the PCR values match."
+
+	EncryptionKeyTPMDoc.Fields[0].AddExample("", []int{7, 11})
+
+	FeaturesConfigDoc.Type = "FeaturesConfig"
+	FeaturesConfigDoc.Comments[encoder.LineComment] = "FeaturesConfig describe individual Talos features that can be switched on or off."
+	FeaturesConfigDoc.Description = "FeaturesConfig describe individual Talos features that can be switched on or off."
+
+	FeaturesConfigDoc.AddExample("", machineFeaturesExample)
+	FeaturesConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "features",
+		},
+	}
+	FeaturesConfigDoc.Fields = make([]encoder.Doc, 9)
+	FeaturesConfigDoc.Fields[0].Name = "rbac"
+	FeaturesConfigDoc.Fields[0].Type = "RBACConfig"
+	FeaturesConfigDoc.Fields[0].Note = ""
+	FeaturesConfigDoc.Fields[0].Description = "Restricts Talos API access for client certificates with specific roles."
+	FeaturesConfigDoc.Fields[0].Comments[encoder.LineComment] = "Restricts Talos API access for client certificates with specific roles."
+
+	FeaturesConfigDoc.Fields[0].AddExample("", machineFeaturesExample.FeaturesRBAC)
+	FeaturesConfigDoc.Fields[1].Name = "imageVerification"
+	FeaturesConfigDoc.Fields[1].Type = "ImageVerificationConfig"
+	FeaturesConfigDoc.Fields[1].Note = ""
+	FeaturesConfigDoc.Fields[1].Description = "Verifies cosign signatures of the installer, kubelet and etcd images before running them."
+	FeaturesConfigDoc.Fields[1].Comments[encoder.LineComment] = "Verifies cosign signatures of the installer, kubelet and etcd images before running them."
+
+	FeaturesConfigDoc.Fields[1].AddExample("", machineImageVerificationExample)
+	FeaturesConfigDoc.Fields[2].Name = "configStorage"
+	FeaturesConfigDoc.Fields[2].Type = "ConfigStorageConfig"
+	FeaturesConfigDoc.Fields[2].Note = ""
+	FeaturesConfigDoc.Fields[2].Description = "Selects where the machine configuration is persisted."
+	FeaturesConfigDoc.Fields[2].Comments[encoder.LineComment] = "Selects where the machine configuration is persisted."
+
+	FeaturesConfigDoc.Fields[2].AddExample("", machineConfigStorageExample)
+	FeaturesConfigDoc.Fields[3].Name = "managementTunnel"
+	FeaturesConfigDoc.Fields[3].Type = "ManagementTunnelConfig"
+	FeaturesConfigDoc.Fields[3].Note = ""
+	FeaturesConfigDoc.Fields[3].Description = "Establishes the management WireGuard tunnel to the provisioning server on boot.\nTalos API of the node is reachable over the tunnel even if the node is behind NAT."
+	FeaturesConfigDoc.Fields[3].Comments[encoder.LineComment] = "Establishes the management WireGuard tunnel to the provisioning server on boot."
+
+	FeaturesConfigDoc.Fields[3].AddExample("", machineManagementTunnelExample)
+	FeaturesConfigDoc.Fields[4].Name = "iscsi"
+	FeaturesConfigDoc.Fields[4].Type = "ISCSIConfig"
+	FeaturesConfigDoc.Fields[4].Note = ""
+	FeaturesConfigDoc.Fields[4].Description = "Runs the iSCSI initiator daemon (`iscsid`) required by the iSCSI CSI drivers."
+	FeaturesConfigDoc.Fields[4].Comments[encoder.LineComment] = "Runs the iSCSI initiator daemon (`iscsid`) required by the iSCSI CSI drivers."
+
+	FeaturesConfigDoc.Fields[4].AddExample("", machineISCSIExample)
+	FeaturesConfigDoc.Fields[5].Name = "nfsMounts"
+	FeaturesConfigDoc.Fields[5].Type = "NFSMountsConfig"
+	FeaturesConfigDoc.Fields[5].Note = ""
+	FeaturesConfigDoc.Fields[5].Description = "Configures the NFS client used by the kubelet to mount NFS volumes."
+	FeaturesConfigDoc.Fields[5].Comments[encoder.LineComment] = "Configures the NFS client used by the kubelet to mount NFS volumes."
+
+	FeaturesConfigDoc.Fields[5].AddExample("", machineNFSMountsExample)
+	FeaturesConfigDoc.Fields[6].Name = "apiAuthorizationPolicy"
+	FeaturesConfigDoc.Fields[6].Type = "APIAuthorizationPolicyConfig"
+	FeaturesConfigDoc.Fields[6].Note = ""
+	FeaturesConfigDoc.Fields[6].Description = "Disables dangerous Talos API methods cluster-wide or restricts them to specific client identities."
+	FeaturesConfigDoc.Fields[6].Comments[encoder.LineComment] = "Disables dangerous Talos API methods cluster-wide or restricts them to specific client identities."
+
+	FeaturesConfigDoc.Fields[6].AddExample("", machineAPIAuthorizationPolicyExample)
+	FeaturesConfigDoc.Fields[7].Name = "localAPIAccess"
+	FeaturesConfigDoc.Fields[7].Type = "LocalAPIAccessConfig"
+	FeaturesConfigDoc.Fields[7].Note = ""
+	FeaturesConfigDoc.Fields[7].Description = "Exposes the Talos API of the node on the local Unix socket `/run/talos/api.sock`.\nPrivileged workloads mounting the socket can call the API of their own node without client certificates."
+	FeaturesConfigDoc.Fields[7].Comments[encoder.LineComment] = "Exposes the Talos API of the node on the local Unix socket `/run/talos/api.sock`."
+
+	FeaturesConfigDoc.Fields[7].AddExample("", machineLocalAPIAccessExample)
+	FeaturesConfigDoc.Fields[8].Name = "kubernetesTalosAPIAccess"
+	FeaturesConfigDoc.Fields[8].Type = "KubernetesTalosAPIAccessConfig"
+	FeaturesConfigDoc.Fields[8].Note = ""
+	FeaturesConfigDoc.Fields[8].Description = "Enables `trustd` on the control plane nodes to issue short-lived Talos API client certificates\nin exchange for the bound Kubernetes service account tokens."
+	FeaturesConfigDoc.Fields[8].Comments[encoder.LineComment] = "Enables `trustd` on the control plane nodes to issue short-lived Talos API client certificates"
+
+	FeaturesConfigDoc.Fields[8].AddExample("", machineKubernetesTalosAPIAccessExample)
+
+	KubernetesTalosAPIAccessConfigDoc.Type = "KubernetesTalosAPIAccessConfig"
+	KubernetesTalosAPIAccessConfigDoc.Comments[encoder.LineComment] = "KubernetesTalosAPIAccessConfig represents the Talos API access from Kubernetes service accounts."
+	KubernetesTalosAPIAccessConfigDoc.Description = "KubernetesTalosAPIAccessConfig represents the Talos API access from Kubernetes service accounts."
+
+	KubernetesTalosAPIAccessConfigDoc.AddExample("", machineKubernetesTalosAPIAccessExample)
+	KubernetesTalosAPIAccessConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "FeaturesConfig",
+			FieldName: "kubernetesTalosAPIAccess",
+		},
+	}
+	KubernetesTalosAPIAccessConfigDoc.Fields = make([]encoder.Doc, 3)
+	KubernetesTalosAPIAccessConfigDoc.Fields[0].Name = "enabled"
+	KubernetesTalosAPIAccessConfigDoc.Fields[0].Type = "bool"
+	KubernetesTalosAPIAccessConfigDoc.Fields[0].Note = ""
+	KubernetesTalosAPIAccessConfigDoc.Fields[0].Description = "Enables the service account certificate exchange."
+	KubernetesTalosAPIAccessConfigDoc.Fields[0].Comments[encoder.LineComment] = "Enables the service account certificate exchange."
+	KubernetesTalosAPIAccessConfigDoc.Fields[1].Name = "allowedRoles"
+	KubernetesTalosAPIAccessConfigDoc.Fields[1].Type = "[]string"
+	KubernetesTalosAPIAccessConfigDoc.Fields[1].Note = ""
+	KubernetesTalosAPIAccessConfigDoc.Fields[1].Description = "List of Talos API roles which can be requested by the service accounts.\nIssued certificates carry the requested roles, so they are subject to the `rbac` rules."
+	KubernetesTalosAPIAccessConfigDoc.Fields[1].Comments[encoder.LineComment] = "List of Talos API roles which can be requested by the service accounts."
+
+	KubernetesTalosAPIAccessConfigDoc.Fields[1].AddExample("", []string{"os:reader"})
+	KubernetesTalosAPIAccessConfigDoc.Fields[2].Name = "allowedKubernetesNamespaces"
+	KubernetesTalosAPIAccessConfigDoc.Fields[2].Type = "[]string"
+	KubernetesTalosAPIAccessConfigDoc.Fields[2].Note = ""
+	KubernetesTalosAPIAccessConfigDoc.Fields[2].Description = "List of Kubernetes namespaces of the service accounts allowed to request the certificates."
+	KubernetesTalosAPIAccessConfigDoc.Fields[2].Comments[encoder.LineComment] = "List of Kubernetes namespaces of the service accounts allowed to request the certificates."
+
+	KubernetesTalosAPIAccessConfigDoc.Fields[2].AddExample("", []string{"kube-system"})
+
+	LocalAPIAccessConfigDoc.Type = "LocalAPIAccessConfig"
+	LocalAPIAccessConfigDoc.Comments[encoder.LineComment] = "LocalAPIAccessConfig represents the local Talos API socket options."
+	LocalAPIAccessConfigDoc.Description = "LocalAPIAccessConfig represents the local Talos API socket options."
+
+	LocalAPIAccessConfigDoc.AddExample("", machineLocalAPIAccessExample)
+	LocalAPIAccessConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "FeaturesConfig",
+			FieldName: "localAPIAccess",
+		},
+	}
+	LocalAPIAccessConfigDoc.Fields = make([]encoder.Doc, 2)
+	LocalAPIAccessConfigDoc.Fields[0].Name = "enabled"
+	LocalAPIAccessConfigDoc.Fields[0].Type = "bool"
+	LocalAPIAccessConfigDoc.Fields[0].Note = ""
+	LocalAPIAccessConfigDoc.Fields[0].Description = "Enables the local API socket.\n\nClients connected to the socket get the `os:local` role and the `local` subject:\nthey are only allowed to call the API of the local node, secrets are redacted for them,\ndestructive methods (e.g. upgrade, reset, applying the configuration) are denied unless the `rbac` rules\nfor the `os:local` role allow them, and they can be further restricted with the `apiAuthorizationPolicy`."
+	LocalAPIAccessConfigDoc.Fields[0].Comments[encoder.LineComment] = "Enables the local API socket."
+	LocalAPIAccessConfigDoc.Fields[1].Name = "groupID"
+	LocalAPIAccessConfigDoc.Fields[1].Type = "int"
+	LocalAPIAccessConfigDoc.Fields[1].Note = ""
+	LocalAPIAccessConfigDoc.Fields[1].Description = "Group ID owning the socket, the socket is only accessible to `root` and the members of the group.\nDefaults to 0."
+	LocalAPIAccessConfigDoc.Fields[1].Comments[encoder.LineComment] = "Group ID owning the socket, the socket is only accessible to `root` and the members of the group."
+
+	NFSMountsConfigDoc.Type = "NFSMountsConfig"
+	NFSMountsConfigDoc.Comments[encoder.LineComment] = "NFSMountsConfig represents the NFS client options."
+	NFSMountsConfigDoc.Description = "NFSMountsConfig represents the NFS client options."
+
+	NFSMountsConfigDoc.AddExample("", machineNFSMountsExample)
+	NFSMountsConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "FeaturesConfig",
+			FieldName: "nfsMounts",
+		},
+	}
+	NFSMountsConfigDoc.Fields = make([]encoder.Doc, 3)
+	NFSMountsConfigDoc.Fields[0].Name = "enabled"
+	NFSMountsConfigDoc.Fields[0].Type = "bool"
+	NFSMountsConfigDoc.Fields[0].Note = ""
+	NFSMountsConfigDoc.Fields[0].Description = "Loads the NFS kernel modules on boot and configures the NFS client of the kubelet."
+	NFSMountsConfigDoc.Fields[0].Comments[encoder.LineComment] = "Loads the NFS kernel modules on boot and configures the NFS client of the kubelet."
+	NFSMountsConfigDoc.Fields[1].Name = "idmapdDomain"
+	NFSMountsConfigDoc.Fields[1].Type = "string"
+	NFSMountsConfigDoc.Fields[1].Note = ""
+	NFSMountsConfigDoc.Fields[1].Description = "NFSv4 ID mapping domain (`Domain` in `idmapd.conf`), should match the domain of the NFS server."
+	NFSMountsConfigDoc.Fields[1].Comments[encoder.LineComment] = "NFSv4 ID mapping domain (`Domain` in `idmapd.conf`), should match the domain of the NFS server."
+	NFSMountsConfigDoc.Fields[2].Name = "defaultVersion"
+	NFSMountsConfigDoc.Fields[2].Type = "string"
+	NFSMountsConfigDoc.Fields[2].Note = ""
+	NFSMountsConfigDoc.Fields[2].Description = "NFS protocol version used if the mount options don't specify one (`Defaultvers` in `nfsmount.conf`)."
+	NFSMountsConfigDoc.Fields[2].Comments[encoder.LineComment] = "NFS protocol version used if the mount options don't specify one (`Defaultvers` in `nfsmount.conf`)."
+	NFSMountsConfigDoc.Fields[2].Values = []string{
+		"3",
+		"4",
+		"4.1",
+		"4.2",
+	}
+
+	ISCSIConfigDoc.Type = "ISCSIConfig"
+	ISCSIConfigDoc.Comments[encoder.LineComment] = "ISCSIConfig represents the iSCSI initiator options."
+	ISCSIConfigDoc.Description = "ISCSIConfig represents the iSCSI initiator options."
+
+	ISCSIConfigDoc.AddExample("", machineISCSIExample)
+	ISCSIConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "FeaturesConfig",
+			FieldName: "iscsi",
+		},
+	}
+	ISCSIConfigDoc.Fields = make([]encoder.Doc, 2)
+	ISCSIConfigDoc.Fields[0].Name = "enabled"
+	ISCSIConfigDoc.Fields[0].Type = "bool"
+	ISCSIConfigDoc.Fields[0].Note = ""
+	ISCSIConfigDoc.Fields[0].Description = "Enables the `iscsid` service."
+	ISCSIConfigDoc.Fields[0].Comments[encoder.LineComment] = "Enables the `iscsid` service."
+	ISCSIConfigDoc.Fields[1].Name = "initiatorName"
+	ISCSIConfigDoc.Fields[1].Type = "string"
+	ISCSIConfigDoc.Fields[1].Note = ""
+	ISCSIConfigDoc.Fields[1].Description = "iSCSI qualified name (IQN) of the initiator.\nIf not set, the name is derived from the node identity, e.g. `iqn.2005-03.org.open-iscsi:0123456789ab`."
+	ISCSIConfigDoc.Fields[1].Comments[encoder.LineComment] = "iSCSI qualified name (IQN) of the initiator."
+
+	ManagementTunnelConfigDoc.Type = "ManagementTunnelConfig"
+	ManagementTunnelConfigDoc.Comments[encoder.LineComment] = "ManagementTunnelConfig represents the management tunnel options."
+	ManagementTunnelConfigDoc.Description = "ManagementTunnelConfig represents the management tunnel options."
+
+	ManagementTunnelConfigDoc.AddExample("", machineManagementTunnelExample)
+	ManagementTunnelConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "FeaturesConfig",
+			FieldName: "managementTunnel",
+		},
+	}
+	ManagementTunnelConfigDoc.Fields = make([]encoder.Doc, 1)
+	ManagementTunnelConfigDoc.Fields[0].Name = "apiUrl"
+	ManagementTunnelConfigDoc.Fields[0].Type = "string"
+	ManagementTunnelConfigDoc.Fields[0].Note = ""
+	ManagementTunnelConfigDoc.Fields[0].Description = "URL of the provisioning server API.\nNode registers its WireGuard public key with the provisioning server and receives the tunnel settings.\nKernel argument `talos.managementtunnel.api` is used if the URL is not set."
+	ManagementTunnelConfigDoc.Fields[0].Comments[encoder.LineComment] = "URL of the provisioning server API."
+
+	ConfigStorageConfigDoc.Type = "ConfigStorageConfig"
+	ConfigStorageConfigDoc.Comments[encoder.LineComment] = "ConfigStorageConfig represents the machine configuration storage options."
+	ConfigStorageConfigDoc.Description = "ConfigStorageConfig represents the machine configuration storage options."
+
+	ConfigStorageConfigDoc.AddExample("", machineConfigStorageExample)
+	ConfigStorageConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "FeaturesConfig",
+			FieldName: "configStorage",
+		},
+	}
+	ConfigStorageConfigDoc.Fields = make([]encoder.Doc, 2)
+	ConfigStorageConfigDoc.Fields[0].Name = "backend"
+	ConfigStorageConfigDoc.Fields[0].Type = "string"
+	ConfigStorageConfigDoc.Fields[0].Note = ""
+	ConfigStorageConfigDoc.Fields[0].Description = "Machine configuration storage backend.\n\n`state` (default) stores the configuration on the STATE partition.\n`meta` stores the configuration in the META partition, so that STATE partition holds no configuration.\n`remote` fetches the configuration from the `url` on every boot, the copy stored on the STATE partition\nis used if the remote service is not available."
+	ConfigStorageConfigDoc.Fields[0].Comments[encoder.LineComment] = "Machine configuration storage backend."
+	ConfigStorageConfigDoc.Fields[0].Values = []string{
+		"state",
+		"meta",
+		"remote",
+	}
+	ConfigStorageConfigDoc.Fields[1].Name = "url"
+	ConfigStorageConfigDoc.Fields[1].Type = "string"
+	ConfigStorageConfigDoc.Fields[1].Note = ""
+	ConfigStorageConfigDoc.Fields[1].Description = "URL of the remote configuration service (`remote` backend only)."
+	ConfigStorageConfigDoc.Fields[1].Comments[encoder.LineComment] = "URL of the remote configuration service (`remote` backend only)."
+
+	ImageVerificationConfigDoc.Type = "ImageVerificationConfig"
+	ImageVerificationConfigDoc.Comments[encoder.LineComment] = "ImageVerificationConfig represents the image signature verification options."
+	ImageVerificationConfigDoc.Description = "ImageVerificationConfig represents the image signature verification options."
+
+	ImageVerificationConfigDoc.AddExample("", machineImageVerificationExample)
+	ImageVerificationConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "FeaturesConfig",
+			FieldName: "imageVerification",
+		},
+	}
+	ImageVerificationConfigDoc.Fields = make([]encoder.Doc, 2)
+	ImageVerificationConfigDoc.Fields[0].Name = "publicKeys"
+	ImageVerificationConfigDoc.Fields[0].Type = "[]string"
+	ImageVerificationConfigDoc.Fields[0].Note = ""
+	ImageVerificationConfigDoc.Fields[0].Description = "List of PEM-encoded public keys trusted to sign the images (ECDSA keys as generated by `cosign generate-key-pair`).\nImage signature is accepted if it is verified by any of the keys.\nVerification is enabled when at least one key is configured."
+	ImageVerificationConfigDoc.Fields[0].Comments[encoder.LineComment] = "List of PEM-encoded public keys trusted to sign the images (ECDSA keys as generated by `cosign generate-key-pair`)."
+	ImageVerificationConfigDoc.Fields[1].Name = "images"
+	ImageVerificationConfigDoc.Fields[1].Type = "[]string"
+	ImageVerificationConfigDoc.Fields[1].Note = ""
+	ImageVerificationConfigDoc.Fields[1].Description = "List of image references to verify.\nTrailing `*` matches any image with the given prefix, e.g. `ghcr.io/talos-systems/*`.\nEmpty list verifies all images used by Talos: the images pulled from the registries, the images from the image cache\nand the system service images shipped with Talos (`talos/apid`, `talos/trustd`, etc.).\nSignatures of the cached and system images are looked up in the image archives they were imported from."
+	ImageVerificationConfigDoc.Fields[1].Comments[encoder.LineComment] = "List of image references to verify."
+
+	ImageVerificationConfigDoc.Fields[1].AddExample("", []string{"ghcr.io/talos-systems/*"})
+
+	RBACConfigDoc.Type = "RBACConfig"
+	RBACConfigDoc.Comments[encoder.LineComment] = "RBACConfig represents Talos API access control rules."
+	RBACConfigDoc.Description = "RBACConfig represents Talos API access control rules."
+
+	RBACConfigDoc.AddExample("", machineFeaturesExample.FeaturesRBAC)
+	RBACConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "FeaturesConfig",
+			FieldName: "rbac",
+		},
+	}
+	RBACConfigDoc.Fields = make([]encoder.Doc, 1)
+	RBACConfigDoc.Fields[0].Name = "rules"
+	RBACConfigDoc.Fields[0].Type = "[]RBACRule"
+	RBACConfigDoc.Fields[0].Note = ""
+	RBACConfigDoc.Fields[0].Description = "List of access rules.\n\nClient certificate roles are taken from the certificate subject organization.\nClients with roles which are not mentioned in any of the rules keep full access to the API,\nwhile clients with at least one matching role are only allowed to call methods on the nodes\npermitted by the matching rules.\nEvery authorization decision for the restricted clients is logged by `apid`.\n\nIndependent of the rules, secrets (e.g. in the machine configuration) are only available to the clients\nwith the `os:admin` role or without any roles."
+	RBACConfigDoc.Fields[0].Comments[encoder.LineComment] = "List of access rules."
+
+	RBACRuleDoc.Type = "RBACRule"
+	RBACRuleDoc.Comments[encoder.LineComment] = "RBACRule represents a single Talos API access rule."
+	RBACRuleDoc.Description = "RBACRule represents a single Talos API access rule."
+	RBACRuleDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "RBACConfig",
+			FieldName: "rules",
+		},
+	}
+	RBACRuleDoc.Fields = make([]encoder.Doc, 3)
+	RBACRuleDoc.Fields[0].Name = "roles"
+	RBACRuleDoc.Fields[0].Type = "[]string"
+	RBACRuleDoc.Fields[0].Note = ""
+	RBACRuleDoc.Fields[0].Description = "List of client certificate roles the rule applies to."
+	RBACRuleDoc.Fields[0].Comments[encoder.LineComment] = "List of client certificate roles the rule applies to."
+	RBACRuleDoc.Fields[1].Name = "methods"
+	RBACRuleDoc.Fields[1].Type = "[]string"
+	RBACRuleDoc.Fields[1].Note = ""
+	RBACRuleDoc.Fields[1].Description = "List of allowed full gRPC method names.\nTrailing `*` matches any method with the given prefix, e.g. `/machine.MachineService/*`."
+	RBACRuleDoc.Fields[1].Comments[encoder.LineComment] = "List of allowed full gRPC method names."
+
+	RBACRuleDoc.Fields[1].AddExample("", []string{"/machine.MachineService/Logs", "/machine.MachineService/Events"})
+	RBACRuleDoc.Fields[2].Name = "nodes"
+	RBACRuleDoc.Fields[2].Type = "[]string"
+	RBACRuleDoc.Fields[2].Note = ""
+	RBACRuleDoc.Fields[2].Description = "List of nodes (IP addresses, CIDRs or hostnames) the methods are allowed on.\nEmpty list allows all nodes."
+	RBACRuleDoc.Fields[2].Comments[encoder.LineComment] = "List of nodes (IP addresses, CIDRs or hostnames) the methods are allowed on."
+
+	RBACRuleDoc.Fields[2].AddExample("", []string{"10.5.0.0/24", "172.20.0.2"})
+
+	APIAuthorizationPolicyConfigDoc.Type = "APIAuthorizationPolicyConfig"
+	APIAuthorizationPolicyConfigDoc.Comments[encoder.LineComment] = "APIAuthorizationPolicyConfig represents the Talos API authorization policy."
+	APIAuthorizationPolicyConfigDoc.Description = "APIAuthorizationPolicyConfig represents the Talos API authorization policy."
+
+	APIAuthorizationPolicyConfigDoc.AddExample("", machineAPIAuthorizationPolicyExample)
+	APIAuthorizationPolicyConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "FeaturesConfig",
+			FieldName: "apiAuthorizationPolicy",
+		},
+	}
+	APIAuthorizationPolicyConfigDoc.Fields = make([]encoder.Doc, 1)
+	APIAuthorizationPolicyConfigDoc.Fields[0].Name = "rules"
+	APIAuthorizationPolicyConfigDoc.Fields[0].Type = "[]APIAuthorizationPolicyRule"
+	APIAuthorizationPolicyConfigDoc.Fields[0].Note = ""
+	APIAuthorizationPolicyConfigDoc.Fields[0].Description = "List of policy rules.\n\nMethods matched by at least one rule can only be called by the clients allowed by any of the matching rules,\nindependent of the client roles and `rbac` rules.\nRules without allowed subjects and roles disable the methods for all clients.\nThe policy is enforced by `apid` both for the direct and the proxied calls."
+	APIAuthorizationPolicyConfigDoc.Fields[0].Comments[encoder.LineComment] = "List of policy rules."
+
+	APIAuthorizationPolicyRuleDoc.Type = "APIAuthorizationPolicyRule"
+	APIAuthorizationPolicyRuleDoc.Comments[encoder.LineComment] = "APIAuthorizationPolicyRule represents a single Talos API authorization policy rule."
+	APIAuthorizationPolicyRuleDoc.Description = "APIAuthorizationPolicyRule represents a single Talos API authorization policy rule."
+	APIAuthorizationPolicyRuleDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "APIAuthorizationPolicyConfig",
+			FieldName: "rules",
+		},
+	}
+	APIAuthorizationPolicyRuleDoc.Fields = make([]encoder.Doc, 3)
+	APIAuthorizationPolicyRuleDoc.Fields[0].Name = "methods"
+	APIAuthorizationPolicyRuleDoc.Fields[0].Type = "[]string"
+	APIAuthorizationPolicyRuleDoc.Fields[0].Note = ""
+	APIAuthorizationPolicyRuleDoc.Fields[0].Description = "List of full gRPC method names the rule applies to.\nTrailing `*` matches any method with the given prefix, e.g. `/machine.MachineService/*`."
+	APIAuthorizationPolicyRuleDoc.Fields[0].Comments[encoder.LineComment] = "List of full gRPC method names the rule applies to."
+
+	APIAuthorizationPolicyRuleDoc.Fields[0].AddExample("", []string{"/machine.MachineService/Reset", "/machine.MachineService/ResetStream"})
+	APIAuthorizationPolicyRuleDoc.Fields[1].Name = "allowedSubjects"
+	APIAuthorizationPolicyRuleDoc.Fields[1].Type = "[]string"
+	APIAuthorizationPolicyRuleDoc.Fields[1].Note = ""
+	APIAuthorizationPolicyRuleDoc.Fields[1].Description = "List of client certificate common names allowed to call the methods."
+	APIAuthorizationPolicyRuleDoc.Fields[1].Comments[encoder.LineComment] = "List of client certificate common names allowed to call the methods."
+
+	APIAuthorizationPolicyRuleDoc.Fields[1].AddExample("", []string{"ops-admin"})
+	APIAuthorizationPolicyRuleDoc.Fields[2].Name = "allowedRoles"
+	APIAuthorizationPolicyRuleDoc.Fields[2].Type = "[]string"
+	APIAuthorizationPolicyRuleDoc.Fields[2].Note = ""
+	APIAuthorizationPolicyRuleDoc.Fields[2].Description = "List of client certificate roles allowed to call the methods."
+	APIAuthorizationPolicyRuleDoc.Fields[2].Comments[encoder.LineComment] = "List of client certificate roles allowed to call the methods."
+
+	APIAuthorizationPolicyRuleDoc.Fields[2].AddExample("", []string{"os:admin"})
+
 	PodCheckpointerDoc.Type = "PodCheckpointer"
 	PodCheckpointerDoc.Comments[encoder.LineComment] = "PodCheckpointer represents the pod-checkpointer config values."
 	PodCheckpointerDoc.Description = "PodCheckpointer represents the pod-checkpointer config values."
@@ -564,12 +1845,66 @@ func init() {
 			FieldName: "coreDNS",
 		},
 	}
-	CoreDNSDoc.Fields = make([]encoder.Doc, 1)
-	CoreDNSDoc.Fields[0].Name = "image"
-	CoreDNSDoc.Fields[0].Type = "string"
+	CoreDNSDoc.Fields = make([]encoder.Doc, 6)
+	CoreDNSDoc.Fields[0].Name = "disabled"
+	CoreDNSDoc.Fields[0].Type = "bool"
 	CoreDNSDoc.Fields[0].Note = ""
-	CoreDNSDoc.Fields[0].Description = "The `image` field is an override to the default coredns image."
-	CoreDNSDoc.Fields[0].Comments[encoder.LineComment] = "The `image` field is an override to the default coredns image."
+	CoreDNSDoc.Fields[0].Description = "Disable coredns deployment on cluster bootstrap."
+	CoreDNSDoc.Fields[0].Comments[encoder.LineComment] = "Disable coredns deployment on cluster bootstrap."
+	CoreDNSDoc.Fields[1].Name = "image"
+	CoreDNSDoc.Fields[1].Type = "string"
+	CoreDNSDoc.Fields[1].Note = ""
+	CoreDNSDoc.Fields[1].Description = "The `image` field is an override to the default coredns image."
+	CoreDNSDoc.Fields[1].Comments[encoder.LineComment] = "The `image` field is an override to the default coredns image."
+	CoreDNSDoc.Fields[2].Name = "replicas"
+	CoreDNSDoc.Fields[2].Type = "int"
+	CoreDNSDoc.Fields[2].Note = ""
+	CoreDNSDoc.Fields[2].Description = "The number of CoreDNS replicas.\nDefaults to the replica count of the bootstrap manifest."
+	CoreDNSDoc.Fields[2].Comments[encoder.LineComment] = "The number of CoreDNS replicas."
+	CoreDNSDoc.Fields[3].Name = "serviceIPs"
+	CoreDNSDoc.Fields[3].Type = "[]string"
+	CoreDNSDoc.Fields[3].Note = ""
+	CoreDNSDoc.Fields[3].Description = "Pin the DNS service IP instead of using the 10th IP of the service subnet.\nOne IP per service subnet (in the same order) is required for the dual-stack clusters.\nThe kubelet is configured with the same IPs as the cluster DNS."
+	CoreDNSDoc.Fields[3].Comments[encoder.LineComment] = "Pin the DNS service IP instead of using the 10th IP of the service subnet."
+
+	CoreDNSDoc.Fields[3].AddExample("", []string{"10.96.0.53"})
+	CoreDNSDoc.Fields[4].Name = "forwardZones"
+	CoreDNSDoc.Fields[4].Type = "[]CoreDNSForwardZone"
+	CoreDNSDoc.Fields[4].Note = ""
+	CoreDNSDoc.Fields[4].Description = "Zones forwarded to the specific upstream DNS servers (stub domains).\nEach zone is rendered as a separate server block in the Corefile."
+	CoreDNSDoc.Fields[4].Comments[encoder.LineComment] = "Zones forwarded to the specific upstream DNS servers (stub domains)."
+
+	CoreDNSDoc.Fields[4].AddExample("", clusterCoreDNSForwardZonesExample)
+	CoreDNSDoc.Fields[5].Name = "extraCorefile"
+	CoreDNSDoc.Fields[5].Type = "string"
+	CoreDNSDoc.Fields[5].Note = ""
+	CoreDNSDoc.Fields[5].Description = "Corefile snippet appended to the Corefile rendered by Talos, e.g. additional server blocks."
+	CoreDNSDoc.Fields[5].Comments[encoder.LineComment] = "Corefile snippet appended to the Corefile rendered by Talos, e.g. additional server blocks."
+
+	CoreDNSDoc.Fields[5].AddExample("", clusterCoreDNSExtraCorefileExample)
+
+	CoreDNSForwardZoneDoc.Type = "CoreDNSForwardZone"
+	CoreDNSForwardZoneDoc.Comments[encoder.LineComment] = "CoreDNSForwardZone represents the zone forwarded to the specific upstream DNS servers."
+	CoreDNSForwardZoneDoc.Description = "CoreDNSForwardZone represents the zone forwarded to the specific upstream DNS servers."
+
+	CoreDNSForwardZoneDoc.AddExample("", clusterCoreDNSForwardZonesExample)
+	CoreDNSForwardZoneDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "CoreDNS",
+			FieldName: "forwardZones",
+		},
+	}
+	CoreDNSForwardZoneDoc.Fields = make([]encoder.Doc, 2)
+	CoreDNSForwardZoneDoc.Fields[0].Name = "zone"
+	CoreDNSForwardZoneDoc.Fields[0].Type = "string"
+	CoreDNSForwardZoneDoc.Fields[0].Note = ""
+	CoreDNSForwardZoneDoc.Fields[0].Description = "The DNS zone, e.g. `corp.example.com`."
+	CoreDNSForwardZoneDoc.Fields[0].Comments[encoder.LineComment] = "The DNS zone, e.g. `corp.example.com`."
+	CoreDNSForwardZoneDoc.Fields[1].Name = "upstreams"
+	CoreDNSForwardZoneDoc.Fields[1].Type = "[]string"
+	CoreDNSForwardZoneDoc.Fields[1].Note = ""
+	CoreDNSForwardZoneDoc.Fields[1].Description = "The upstream DNS servers as IP addresses, optionally with the port."
+	CoreDNSForwardZoneDoc.Fields[1].Comments[encoder.LineComment] = "The upstream DNS servers as IP addresses, optionally with the port."
 
 	EndpointDoc.Type = "Endpoint"
 	EndpointDoc.Comments[encoder.LineComment] = "Endpoint represents the endpoint URL parsed out of the machine config."
@@ -586,6 +1921,24 @@ func init() {
 	}
 	EndpointDoc.Fields = make([]encoder.Doc, 0)
 
+	PEMEncodedKeyDoc.Type = "PEMEncodedKey"
+	PEMEncodedKeyDoc.Comments[encoder.LineComment] = "PEMEncodedKey represents a PEM encoded private key."
+	PEMEncodedKeyDoc.Description = "PEMEncodedKey represents a PEM encoded private key."
+
+	PEMEncodedKeyDoc.AddExample("ServiceAccount example.", pemEncodedKeyExample)
+	PEMEncodedKeyDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "ClusterConfig",
+			FieldName: "serviceAccount",
+		},
+	}
+	PEMEncodedKeyDoc.Fields = make([]encoder.Doc, 1)
+	PEMEncodedKeyDoc.Fields[0].Name = "key"
+	PEMEncodedKeyDoc.Fields[0].Type = "Base64Bytes"
+	PEMEncodedKeyDoc.Fields[0].Note = ""
+	PEMEncodedKeyDoc.Fields[0].Description = "The base64 encoded PEM private key."
+	PEMEncodedKeyDoc.Fields[0].Comments[encoder.LineComment] = "The base64 encoded PEM private key."
+
 	ControlPlaneConfigDoc.Type = "ControlPlaneConfig"
 	ControlPlaneConfigDoc.Comments[encoder.LineComment] = "ControlPlaneConfig represents the control plane configuration options."
 	ControlPlaneConfigDoc.Description = "ControlPlaneConfig represents the control plane configuration options."
@@ -624,7 +1977,7 @@ func init() {
 			FieldName: "apiServer",
 		},
 	}
-	APIServerConfigDoc.Fields = make([]encoder.Doc, 3)
+	APIServerConfigDoc.Fields = make([]encoder.Doc, 7)
 	APIServerConfigDoc.Fields[0].Name = "image"
 	APIServerConfigDoc.Fields[0].Type = "string"
 	APIServerConfigDoc.Fields[0].Note = ""
@@ -642,6 +1995,32 @@ func init() {
 	APIServerConfigDoc.Fields[2].Note = ""
 	APIServerConfigDoc.Fields[2].Description = "Extra certificate subject alternative names for the API server's certificate."
 	APIServerConfigDoc.Fields[2].Comments[encoder.LineComment] = "Extra certificate subject alternative names for the API server's certificate."
+	APIServerConfigDoc.Fields[3].Name = "disablePodSecurityPolicy"
+	APIServerConfigDoc.Fields[3].Type = "bool"
+	APIServerConfigDoc.Fields[3].Note = ""
+	APIServerConfigDoc.Fields[3].Description = "Disable PodSecurityPolicy in the API server and default manifests."
+	APIServerConfigDoc.Fields[3].Comments[encoder.LineComment] = "Disable PodSecurityPolicy in the API server and default manifests."
+	APIServerConfigDoc.Fields[4].Name = "serviceAccountIssuer"
+	APIServerConfigDoc.Fields[4].Type = "string"
+	APIServerConfigDoc.Fields[4].Note = ""
+	APIServerConfigDoc.Fields[4].Description = "The issuer URL of the service account tokens, defaults to the cluster control plane endpoint.\n\nThe issuer should be publicly reachable with the OIDC discovery document for the workload identity federation\n(e.g. IAM roles for service accounts).\nThe scheme of the URL should match the scheme of the control plane endpoint."
+	APIServerConfigDoc.Fields[4].Comments[encoder.LineComment] = "The issuer URL of the service account tokens, defaults to the cluster control plane endpoint."
+
+	APIServerConfigDoc.Fields[4].AddExample("", "https://oidc.example.com")
+	APIServerConfigDoc.Fields[5].Name = "serviceAccountJWKSURI"
+	APIServerConfigDoc.Fields[5].Type = "string"
+	APIServerConfigDoc.Fields[5].Note = ""
+	APIServerConfigDoc.Fields[5].Description = "The URI of the JSON Web Key Set published in the OIDC discovery document.\n\nRequired if the issuer URL doesn't serve the API server discovery endpoints."
+	APIServerConfigDoc.Fields[5].Comments[encoder.LineComment] = "The URI of the JSON Web Key Set published in the OIDC discovery document."
+
+	APIServerConfigDoc.Fields[5].AddExample("", "https://oidc.example.com/openid/v1/jwks")
+	APIServerConfigDoc.Fields[6].Name = "serviceAccountExtraAudiences"
+	APIServerConfigDoc.Fields[6].Type = "[]string"
+	APIServerConfigDoc.Fields[6].Note = ""
+	APIServerConfigDoc.Fields[6].Description = "Extra audiences accepted by the API server in the service account tokens, in addition to the issuer URL."
+	APIServerConfigDoc.Fields[6].Comments[encoder.LineComment] = "Extra audiences accepted by the API server in the service account tokens, in addition to the issuer URL."
+
+	APIServerConfigDoc.Fields[6].AddExample("", []string{"sts.amazonaws.com"})
 
 	ControllerManagerConfigDoc.Type = "ControllerManagerConfig"
 	ControllerManagerConfigDoc.Comments[encoder.LineComment] = "ControllerManagerConfig represents the kube controller manager configuration options."
@@ -654,7 +2033,7 @@ func init() {
 			FieldName: "controllerManager",
 		},
 	}
-	ControllerManagerConfigDoc.Fields = make([]encoder.Doc, 2)
+	ControllerManagerConfigDoc.Fields = make([]encoder.Doc, 3)
 	ControllerManagerConfigDoc.Fields[0].Name = "image"
 	ControllerManagerConfigDoc.Fields[0].Type = "string"
 	ControllerManagerConfigDoc.Fields[0].Note = ""
@@ -667,6 +2046,13 @@ func init() {
 	ControllerManagerConfigDoc.Fields[1].Note = ""
 	ControllerManagerConfigDoc.Fields[1].Description = "Extra arguments to supply to the controller manager."
 	ControllerManagerConfigDoc.Fields[1].Comments[encoder.LineComment] = "Extra arguments to supply to the controller manager."
+	ControllerManagerConfigDoc.Fields[2].Name = "extraConfig"
+	ControllerManagerConfigDoc.Fields[2].Type = "map[string]string"
+	ControllerManagerConfigDoc.Fields[2].Note = ""
+	ControllerManagerConfigDoc.Fields[2].Description = "Extra configuration files to supply to the controller manager.\nFiles are keyed by the file name and mounted into the controller manager static pod\nunder `/etc/kubernetes/secrets`, so that they can be referenced in `extraArgs`.\nThe controller manager doesn't support loading component config from a file, so files are not passed automatically.\nOnly supported when the control plane is run as static pods."
+	ControllerManagerConfigDoc.Fields[2].Comments[encoder.LineComment] = "Extra configuration files to supply to the controller manager."
+
+	ControllerManagerConfigDoc.Fields[2].AddExample("", clusterControllerManagerExtraConfigExample)
 
 	ProxyConfigDoc.Type = "ProxyConfig"
 	ProxyConfigDoc.Comments[encoder.LineComment] = "ProxyConfig represents the kube proxy configuration options."
@@ -679,24 +2065,29 @@ func init() {
 			FieldName: "proxy",
 		},
 	}
-	ProxyConfigDoc.Fields = make([]encoder.Doc, 3)
-	ProxyConfigDoc.Fields[0].Name = "image"
-	ProxyConfigDoc.Fields[0].Type = "string"
+	ProxyConfigDoc.Fields = make([]encoder.Doc, 4)
+	ProxyConfigDoc.Fields[0].Name = "disabled"
+	ProxyConfigDoc.Fields[0].Type = "bool"
 	ProxyConfigDoc.Fields[0].Note = ""
-	ProxyConfigDoc.Fields[0].Description = "The container image used in the kube-proxy manifest."
-	ProxyConfigDoc.Fields[0].Comments[encoder.LineComment] = "The container image used in the kube-proxy manifest."
-
-	ProxyConfigDoc.Fields[0].AddExample("", clusterProxyImageExample)
-	ProxyConfigDoc.Fields[1].Name = "mode"
+	ProxyConfigDoc.Fields[0].Description = "Disable kube-proxy deployment on cluster bootstrap."
+	ProxyConfigDoc.Fields[0].Comments[encoder.LineComment] = "Disable kube-proxy deployment on cluster bootstrap."
+	ProxyConfigDoc.Fields[1].Name = "image"
 	ProxyConfigDoc.Fields[1].Type = "string"
 	ProxyConfigDoc.Fields[1].Note = ""
-	ProxyConfigDoc.Fields[1].Description = "proxy mode of kube-proxy.\nThe default is 'iptables'."
-	ProxyConfigDoc.Fields[1].Comments[encoder.LineComment] = "proxy mode of kube-proxy."
-	ProxyConfigDoc.Fields[2].Name = "extraArgs"
-	ProxyConfigDoc.Fields[2].Type = "map[string]string"
+	ProxyConfigDoc.Fields[1].Description = "The container image used in the kube-proxy manifest."
+	ProxyConfigDoc.Fields[1].Comments[encoder.LineComment] = "The container image used in the kube-proxy manifest."
+
+	ProxyConfigDoc.Fields[1].AddExample("", clusterProxyImageExample)
+	ProxyConfigDoc.Fields[2].Name = "mode"
+	ProxyConfigDoc.Fields[2].Type = "string"
 	ProxyConfigDoc.Fields[2].Note = ""
-	ProxyConfigDoc.Fields[2].Description = "Extra arguments to supply to kube-proxy."
-	ProxyConfigDoc.Fields[2].Comments[encoder.LineComment] = "Extra arguments to supply to kube-proxy."
+	ProxyConfigDoc.Fields[2].Description = "proxy mode of kube-proxy.\nThe default is 'iptables'."
+	ProxyConfigDoc.Fields[2].Comments[encoder.LineComment] = "proxy mode of kube-proxy."
+	ProxyConfigDoc.Fields[3].Name = "extraArgs"
+	ProxyConfigDoc.Fields[3].Type = "map[string]string"
+	ProxyConfigDoc.Fields[3].Note = ""
+	ProxyConfigDoc.Fields[3].Description = "Extra arguments to supply to kube-proxy."
+	ProxyConfigDoc.Fields[3].Comments[encoder.LineComment] = "Extra arguments to supply to kube-proxy."
 
 	SchedulerConfigDoc.Type = "SchedulerConfig"
 	SchedulerConfigDoc.Comments[encoder.LineComment] = "SchedulerConfig represents the kube scheduler configuration options."
@@ -709,7 +2100,7 @@ func init() {
 			FieldName: "scheduler",
 		},
 	}
-	SchedulerConfigDoc.Fields = make([]encoder.Doc, 2)
+	SchedulerConfigDoc.Fields = make([]encoder.Doc, 3)
 	SchedulerConfigDoc.Fields[0].Name = "image"
 	SchedulerConfigDoc.Fields[0].Type = "string"
 	SchedulerConfigDoc.Fields[0].Note = ""
@@ -722,6 +2113,13 @@ func init() {
 	SchedulerConfigDoc.Fields[1].Note = ""
 	SchedulerConfigDoc.Fields[1].Description = "Extra arguments to supply to the scheduler."
 	SchedulerConfigDoc.Fields[1].Comments[encoder.LineComment] = "Extra arguments to supply to the scheduler."
+	SchedulerConfigDoc.Fields[2].Name = "config"
+	SchedulerConfigDoc.Fields[2].Type = "Unstructured"
+	SchedulerConfigDoc.Fields[2].Note = ""
+	SchedulerConfigDoc.Fields[2].Description = "Scheduler component config (`KubeSchedulerConfiguration`) to supply to the scheduler.\nTalos sets the `clientConnection.kubeconfig`, and the scheduler is started with the `--config` flag.\nOnly supported when the control plane is run as static pods."
+	SchedulerConfigDoc.Fields[2].Comments[encoder.LineComment] = "Scheduler component config (`KubeSchedulerConfiguration`) to supply to the scheduler."
+
+	SchedulerConfigDoc.Fields[2].AddExample("", clusterSchedulerConfigExample)
 
 	EtcdConfigDoc.Type = "EtcdConfig"
 	EtcdConfigDoc.Comments[encoder.LineComment] = "EtcdConfig represents the etcd configuration options."
@@ -734,7 +2132,7 @@ func init() {
 			FieldName: "etcd",
 		},
 	}
-	EtcdConfigDoc.Fields = make([]encoder.Doc, 3)
+	EtcdConfigDoc.Fields = make([]encoder.Doc, 6)
 	EtcdConfigDoc.Fields[0].Name = "image"
 	EtcdConfigDoc.Fields[0].Type = "string"
 	EtcdConfigDoc.Fields[0].Note = ""
@@ -755,6 +2153,63 @@ func init() {
 	EtcdConfigDoc.Fields[2].Description = "Extra arguments to supply to etcd.\nNote that the following args are not allowed:\n\n- `name`\n- `data-dir`\n- `initial-cluster-state`\n- `listen-peer-urls`\n- `listen-client-urls`\n- `cert-file`\n- `key-file`\n- `trusted-ca-file`\n- `peer-client-cert-auth`\n- `peer-cert-file`\n- `peer-trusted-ca-file`\n- `peer-key-file`"
 	EtcdConfigDoc.Fields[2].Comments[encoder.LineComment] = "Extra arguments to supply to etcd."
 
+	EtcdConfigDoc.Fields[3].Name = "advertisedSubnets"
+	EtcdConfigDoc.Fields[3].Type = "[]string"
+	EtcdConfigDoc.Fields[3].Note = ""
+	EtcdConfigDoc.Fields[3].Description = "The subnets to pick the etcd advertised (peer and client) address from.\nAddresses are excluded with the negative match prefixed with `!`, e.g. `!10.0.0.0/8`.\nThe first matching address is advertised.\nIf not specified, the first address of the node is advertised."
+	EtcdConfigDoc.Fields[3].Comments[encoder.LineComment] = "The subnets to pick the etcd advertised (peer and client) address from."
+
+	EtcdConfigDoc.Fields[3].AddExample("", []string{"10.0.0.0/8"})
+	EtcdConfigDoc.Fields[4].Name = "listenSubnets"
+	EtcdConfigDoc.Fields[4].Type = "[]string"
+	EtcdConfigDoc.Fields[4].Note = ""
+	EtcdConfigDoc.Fields[4].Description = "The subnets to pick the etcd listen addresses from.\nAddresses are excluded with the negative match prefixed with `!`, e.g. `!10.0.0.0/8`.\netcd listens on all the matching addresses, and the client port is also bound to the loopback address.\nIf not specified, etcd listens on all addresses."
+	EtcdConfigDoc.Fields[4].Comments[encoder.LineComment] = "The subnets to pick the etcd listen addresses from."
+
+	EtcdConfigDoc.Fields[4].AddExample("", []string{"10.0.0.0/8"})
+	EtcdConfigDoc.Fields[5].Name = "maintenance"
+	EtcdConfigDoc.Fields[5].Type = "EtcdMaintenanceConfig"
+	EtcdConfigDoc.Fields[5].Note = ""
+	EtcdConfigDoc.Fields[5].Description = "The periodic etcd maintenance: defragmentation of the members and clearing of the `NOSPACE` alarms."
+	EtcdConfigDoc.Fields[5].Comments[encoder.LineComment] = "The periodic etcd maintenance: defragmentation of the members and clearing of the `NOSPACE` alarms."
+
+	EtcdConfigDoc.Fields[5].AddExample("", clusterEtcdMaintenanceExample)
+
+	EtcdMaintenanceConfigDoc.Type = "EtcdMaintenanceConfig"
+	EtcdMaintenanceConfigDoc.Comments[encoder.LineComment] = "EtcdMaintenanceConfig represents the etcd maintenance configuration."
+	EtcdMaintenanceConfigDoc.Description = "EtcdMaintenanceConfig represents the etcd maintenance configuration."
+
+	EtcdMaintenanceConfigDoc.AddExample("", clusterEtcdMaintenanceExample)
+	EtcdMaintenanceConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "EtcdConfig",
+			FieldName: "maintenance",
+		},
+	}
+	EtcdMaintenanceConfigDoc.Fields = make([]encoder.Doc, 4)
+	EtcdMaintenanceConfigDoc.Fields[0].Name = "enabled"
+	EtcdMaintenanceConfigDoc.Fields[0].Type = "bool"
+	EtcdMaintenanceConfigDoc.Fields[0].Note = ""
+	EtcdMaintenanceConfigDoc.Fields[0].Description = "Enable the periodic etcd maintenance.\nMaintenance is run by the control plane node hosting the etcd leader: followers are defragmented first, then the leader.\n`NOSPACE` alarms are cleared after all the members are defragmented."
+	EtcdMaintenanceConfigDoc.Fields[0].Comments[encoder.LineComment] = "Enable the periodic etcd maintenance."
+	EtcdMaintenanceConfigDoc.Fields[1].Name = "interval"
+	EtcdMaintenanceConfigDoc.Fields[1].Type = "Duration"
+	EtcdMaintenanceConfigDoc.Fields[1].Note = ""
+	EtcdMaintenanceConfigDoc.Fields[1].Description = "The interval between maintenance runs, defaults to 1 hour.\nField format accepts any Go time.Duration format ('1h' for one hour, '10m' for ten minutes)."
+	EtcdMaintenanceConfigDoc.Fields[1].Comments[encoder.LineComment] = "The interval between maintenance runs, defaults to 1 hour."
+	EtcdMaintenanceConfigDoc.Fields[2].Name = "minDBSize"
+	EtcdMaintenanceConfigDoc.Fields[2].Type = "DiskSize"
+	EtcdMaintenanceConfigDoc.Fields[2].Note = ""
+	EtcdMaintenanceConfigDoc.Fields[2].Description = "The database size below which the member is not defragmented, defaults to 100MB."
+	EtcdMaintenanceConfigDoc.Fields[2].Comments[encoder.LineComment] = "The database size below which the member is not defragmented, defaults to 100MB."
+
+	EtcdMaintenanceConfigDoc.Fields[2].AddExample("Human readable representation.", DiskSize(500000000))
+	EtcdMaintenanceConfigDoc.Fields[3].Name = "fragmentationThreshold"
+	EtcdMaintenanceConfigDoc.Fields[3].Type = "int"
+	EtcdMaintenanceConfigDoc.Fields[3].Note = ""
+	EtcdMaintenanceConfigDoc.Fields[3].Description = "The percentage of the free space in the database which triggers the defragmentation, defaults to 50."
+	EtcdMaintenanceConfigDoc.Fields[3].Comments[encoder.LineComment] = "The percentage of the free space in the database which triggers the defragmentation, defaults to 50."
+
 	ClusterNetworkConfigDoc.Type = "ClusterNetworkConfig"
 	ClusterNetworkConfigDoc.Comments[encoder.LineComment] = "ClusterNetworkConfig represents kube networking configuration options."
 	ClusterNetworkConfigDoc.Description = "ClusterNetworkConfig represents kube networking configuration options."
@@ -770,7 +2225,7 @@ func init() {
 	ClusterNetworkConfigDoc.Fields[0].Name = "cni"
 	ClusterNetworkConfigDoc.Fields[0].Type = "CNIConfig"
 	ClusterNetworkConfigDoc.Fields[0].Note = ""
-	ClusterNetworkConfigDoc.Fields[0].Description = "The CNI used.\nComposed of \"name\" and \"url\".\nThe \"name\" key only supports options of \"flannel\" or \"custom\".\nURLs is only used if name is equal to \"custom\".\nURLs should point to the set of YAML files to be deployed.\nAn empty struct or any other name will default to bootkube's flannel."
+	ClusterNetworkConfigDoc.Fields[0].Description = "The CNI used.\nComposed of \"name\" and \"url\".\nThe \"name\" key supports the following options: \"flannel\", \"custom\", and \"none\".\n\"flannel\" uses Talos-managed Flannel CNI, and that's the default option.\n\"custom\" uses custom manifests that should be provided in \"urls\".\n\"none\" indicates that Talos will not manage any CNI installation."
 	ClusterNetworkConfigDoc.Fields[0].Comments[encoder.LineComment] = "The CNI used."
 
 	ClusterNetworkConfigDoc.Fields[0].AddExample("", clusterCustomCNIExample)
@@ -807,7 +2262,7 @@ func init() {
 			FieldName: "cni",
 		},
 	}
-	CNIConfigDoc.Fields = make([]encoder.Doc, 2)
+	CNIConfigDoc.Fields = make([]encoder.Doc, 3)
 	CNIConfigDoc.Fields[0].Name = "name"
 	CNIConfigDoc.Fields[0].Type = "string"
 	CNIConfigDoc.Fields[0].Note = ""
@@ -818,6 +2273,61 @@ func init() {
 	CNIConfigDoc.Fields[1].Note = ""
 	CNIConfigDoc.Fields[1].Description = "URLs containing manifests to apply for the CNI."
 	CNIConfigDoc.Fields[1].Comments[encoder.LineComment] = "URLs containing manifests to apply for the CNI."
+	CNIConfigDoc.Fields[2].Name = "flannel"
+	CNIConfigDoc.Fields[2].Type = "FlannelCNIConfig"
+	CNIConfigDoc.Fields[2].Note = ""
+	CNIConfigDoc.Fields[2].Description = "Settings of the Talos-managed Flannel CNI, only used with the `flannel` CNI."
+	CNIConfigDoc.Fields[2].Comments[encoder.LineComment] = "Settings of the Talos-managed Flannel CNI, only used with the `flannel` CNI."
+
+	CNIConfigDoc.Fields[2].AddExample("", clusterFlannelCNIExample)
+
+	FlannelCNIConfigDoc.Type = "FlannelCNIConfig"
+	FlannelCNIConfigDoc.Comments[encoder.LineComment] = "FlannelCNIConfig represents the Flannel CNI configuration options."
+	FlannelCNIConfigDoc.Description = "FlannelCNIConfig represents the Flannel CNI configuration options."
+
+	FlannelCNIConfigDoc.AddExample("", clusterFlannelCNIExample)
+	FlannelCNIConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "CNIConfig",
+			FieldName: "flannel",
+		},
+	}
+	FlannelCNIConfigDoc.Fields = make([]encoder.Doc, 6)
+	FlannelCNIConfigDoc.Fields[0].Name = "backend"
+	FlannelCNIConfigDoc.Fields[0].Type = "string"
+	FlannelCNIConfigDoc.Fields[0].Note = ""
+	FlannelCNIConfigDoc.Fields[0].Description = "The backend used to forward the pod traffic between the nodes.\nThe `wireguard` backend requires Flannel v0.15.0 or later, see `image`."
+	FlannelCNIConfigDoc.Fields[0].Comments[encoder.LineComment] = "The backend used to forward the pod traffic between the nodes."
+	FlannelCNIConfigDoc.Fields[0].Values = []string{
+		"vxlan",
+		"host-gw",
+		"wireguard",
+	}
+	FlannelCNIConfigDoc.Fields[1].Name = "mtu"
+	FlannelCNIConfigDoc.Fields[1].Type = "int"
+	FlannelCNIConfigDoc.Fields[1].Note = ""
+	FlannelCNIConfigDoc.Fields[1].Description = "The MTU of the pod network interfaces.\nDefaults to the MTU of the node interface minus the backend overhead."
+	FlannelCNIConfigDoc.Fields[1].Comments[encoder.LineComment] = "The MTU of the pod network interfaces."
+	FlannelCNIConfigDoc.Fields[2].Name = "interfaces"
+	FlannelCNIConfigDoc.Fields[2].Type = "[]string"
+	FlannelCNIConfigDoc.Fields[2].Note = ""
+	FlannelCNIConfigDoc.Fields[2].Description = "The node interfaces (names or IP addresses) to use for the inter-node traffic, the first matching one is used.\nDefaults to the interface of the default route."
+	FlannelCNIConfigDoc.Fields[2].Comments[encoder.LineComment] = "The node interfaces (names or IP addresses) to use for the inter-node traffic, the first matching one is used."
+	FlannelCNIConfigDoc.Fields[3].Name = "interfaceRegexps"
+	FlannelCNIConfigDoc.Fields[3].Type = "[]string"
+	FlannelCNIConfigDoc.Fields[3].Note = ""
+	FlannelCNIConfigDoc.Fields[3].Description = "The regular expressions to match the node interface names or IP addresses to use for the inter-node traffic.\nUsed if none of the `interfaces` is found."
+	FlannelCNIConfigDoc.Fields[3].Comments[encoder.LineComment] = "The regular expressions to match the node interface names or IP addresses to use for the inter-node traffic."
+	FlannelCNIConfigDoc.Fields[4].Name = "extraArgs"
+	FlannelCNIConfigDoc.Fields[4].Type = "map[string]string"
+	FlannelCNIConfigDoc.Fields[4].Note = ""
+	FlannelCNIConfigDoc.Fields[4].Description = "Extra arguments to supply to flanneld."
+	FlannelCNIConfigDoc.Fields[4].Comments[encoder.LineComment] = "Extra arguments to supply to flanneld."
+	FlannelCNIConfigDoc.Fields[5].Name = "image"
+	FlannelCNIConfigDoc.Fields[5].Type = "string"
+	FlannelCNIConfigDoc.Fields[5].Note = ""
+	FlannelCNIConfigDoc.Fields[5].Description = "The `image` field is an override to the default Flannel image."
+	FlannelCNIConfigDoc.Fields[5].Comments[encoder.LineComment] = "The `image` field is an override to the default Flannel image."
 
 	AdminKubeconfigConfigDoc.Type = "AdminKubeconfigConfig"
 	AdminKubeconfigConfigDoc.Comments[encoder.LineComment] = "AdminKubeconfigConfig contains admin kubeconfig settings."
@@ -837,6 +2347,166 @@ func init() {
 	AdminKubeconfigConfigDoc.Fields[0].Description = "Admin kubeconfig certificate lifetime (default is 1 year).\nField format accepts any Go time.Duration format ('1h' for one hour, '10m' for ten minutes)."
 	AdminKubeconfigConfigDoc.Fields[0].Comments[encoder.LineComment] = "Admin kubeconfig certificate lifetime (default is 1 year)."
 
+	ExternalCloudProviderConfigDoc.Type = "ExternalCloudProviderConfig"
+	ExternalCloudProviderConfigDoc.Comments[encoder.LineComment] = "ExternalCloudProviderConfig contains external cloud provider configuration."
+	ExternalCloudProviderConfigDoc.Description = "ExternalCloudProviderConfig contains external cloud provider configuration."
+
+	ExternalCloudProviderConfigDoc.AddExample("", clusterExternalCloudProviderConfigExample)
+	ExternalCloudProviderConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "ClusterConfig",
+			FieldName: "externalCloudProvider",
+		},
+	}
+	ExternalCloudProviderConfigDoc.Fields = make([]encoder.Doc, 2)
+	ExternalCloudProviderConfigDoc.Fields[0].Name = "enabled"
+	ExternalCloudProviderConfigDoc.Fields[0].Type = "bool"
+	ExternalCloudProviderConfigDoc.Fields[0].Note = ""
+	ExternalCloudProviderConfigDoc.Fields[0].Description = "Enable external cloud provider.\n\nWhen enabled, kubelet, API server and controller manager are configured with `--cloud-provider=external`,\nso that the cloud-specific control loops are run by the cloud controller manager.\nKubelet taints the node as uninitialized until the cloud controller manager initializes it."
+	ExternalCloudProviderConfigDoc.Fields[0].Comments[encoder.LineComment] = "Enable external cloud provider."
+	ExternalCloudProviderConfigDoc.Fields[0].Values = []string{
+		"true",
+		"yes",
+		"false",
+		"no",
+	}
+	ExternalCloudProviderConfigDoc.Fields[1].Name = "manifests"
+	ExternalCloudProviderConfigDoc.Fields[1].Type = "[]string"
+	ExternalCloudProviderConfigDoc.Fields[1].Note = ""
+	ExternalCloudProviderConfigDoc.Fields[1].Description = "A list of urls that point to additional manifests for an external cloud provider.\nThese will get automatically deployed by bootkube.\n\nCloud controller manager configuration (cloud-config) should be delivered with the manifests,\ne.g. as a `Secret` or a `ConfigMap` mounted into the cloud controller manager pod."
+	ExternalCloudProviderConfigDoc.Fields[1].Comments[encoder.LineComment] = "A list of urls that point to additional manifests for an external cloud provider."
+
+	ExternalCloudProviderConfigDoc.Fields[1].AddExample("", []string{
+		"https://raw.githubusercontent.com/kubernetes/cloud-provider-aws/v1.20.0-alpha.0/manifests/rbac.yaml",
+		"https://raw.githubusercontent.com/kubernetes/cloud-provider-aws/v1.20.0-alpha.0/manifests/aws-cloud-controller-manager-daemonset.yaml",
+	})
+
+	JoinThrottleConfigDoc.Type = "JoinThrottleConfig"
+	JoinThrottleConfigDoc.Comments[encoder.LineComment] = "JoinThrottleConfig represents the cluster join throttling options."
+	JoinThrottleConfigDoc.Description = "JoinThrottleConfig represents the cluster join throttling options."
+
+	JoinThrottleConfigDoc.AddExample("", clusterJoinThrottleExample)
+	JoinThrottleConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "ClusterConfig",
+			FieldName: "joinThrottle",
+		},
+	}
+	JoinThrottleConfigDoc.Fields = make([]encoder.Doc, 6)
+	JoinThrottleConfigDoc.Fields[0].Name = "maxConcurrentRequests"
+	JoinThrottleConfigDoc.Fields[0].Type = "int"
+	JoinThrottleConfigDoc.Fields[0].Note = ""
+	JoinThrottleConfigDoc.Fields[0].Description = "Maximum number of certificate requests processed concurrently by `trustd` on each control plane node.\nDefaults to 10."
+	JoinThrottleConfigDoc.Fields[0].Comments[encoder.LineComment] = "Maximum number of certificate requests processed concurrently by `trustd` on each control plane node."
+	JoinThrottleConfigDoc.Fields[1].Name = "maxQueuedRequests"
+	JoinThrottleConfigDoc.Fields[1].Type = "int"
+	JoinThrottleConfigDoc.Fields[1].Note = ""
+	JoinThrottleConfigDoc.Fields[1].Description = "Maximum number of certificate requests waiting to be processed by `trustd` on each control plane node.\nRequests over the limit are rejected, and the client retries later.\nThe queue depth is exported by `trustd` as the `trustd_join_queue_depth` Prometheus metric on `127.0.0.1:50003/metrics`.\nDefaults to 100."
+	JoinThrottleConfigDoc.Fields[1].Comments[encoder.LineComment] = "Maximum number of certificate requests waiting to be processed by `trustd` on each control plane node."
+	JoinThrottleConfigDoc.Fields[2].Name = "retryInterval"
+	JoinThrottleConfigDoc.Fields[2].Type = "Duration"
+	JoinThrottleConfigDoc.Fields[2].Note = ""
+	JoinThrottleConfigDoc.Fields[2].Description = "Initial interval between certificate request attempts on the joining machine (default is 1 second).\nThe interval is doubled after each failed attempt."
+	JoinThrottleConfigDoc.Fields[2].Comments[encoder.LineComment] = "Initial interval between certificate request attempts on the joining machine (default is 1 second)."
+	JoinThrottleConfigDoc.Fields[3].Name = "maxRetryInterval"
+	JoinThrottleConfigDoc.Fields[3].Type = "Duration"
+	JoinThrottleConfigDoc.Fields[3].Note = ""
+	JoinThrottleConfigDoc.Fields[3].Description = "Maximum interval between certificate request attempts (default is 30 seconds)."
+	JoinThrottleConfigDoc.Fields[3].Comments[encoder.LineComment] = "Maximum interval between certificate request attempts (default is 30 seconds)."
+	JoinThrottleConfigDoc.Fields[4].Name = "retryJitter"
+	JoinThrottleConfigDoc.Fields[4].Type = "Duration"
+	JoinThrottleConfigDoc.Fields[4].Note = ""
+	JoinThrottleConfigDoc.Fields[4].Description = "Maximum random delay added to each certificate request attempt (default is 5 seconds)."
+	JoinThrottleConfigDoc.Fields[4].Comments[encoder.LineComment] = "Maximum random delay added to each certificate request attempt (default is 5 seconds)."
+	JoinThrottleConfigDoc.Fields[5].Name = "retryTimeout"
+	JoinThrottleConfigDoc.Fields[5].Type = "Duration"
+	JoinThrottleConfigDoc.Fields[5].Note = ""
+	JoinThrottleConfigDoc.Fields[5].Description = "Time to wait for the certificate before giving up (default is 10 minutes)."
+	JoinThrottleConfigDoc.Fields[5].Comments[encoder.LineComment] = "Time to wait for the certificate before giving up (default is 10 minutes)."
+
+	ClusterDiscoveryConfigDoc.Type = "ClusterDiscoveryConfig"
+	ClusterDiscoveryConfigDoc.Comments[encoder.LineComment] = "ClusterDiscoveryConfig represents the cluster membership discovery options."
+	ClusterDiscoveryConfigDoc.Description = "ClusterDiscoveryConfig represents the cluster membership discovery options."
+
+	ClusterDiscoveryConfigDoc.AddExample("", clusterDiscoveryExample)
+	ClusterDiscoveryConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "ClusterConfig",
+			FieldName: "discovery",
+		},
+	}
+	ClusterDiscoveryConfigDoc.Fields = make([]encoder.Doc, 2)
+	ClusterDiscoveryConfigDoc.Fields[0].Name = "enabled"
+	ClusterDiscoveryConfigDoc.Fields[0].Type = "bool"
+	ClusterDiscoveryConfigDoc.Fields[0].Note = ""
+	ClusterDiscoveryConfigDoc.Fields[0].Description = "Enable the cluster membership discovery."
+	ClusterDiscoveryConfigDoc.Fields[0].Comments[encoder.LineComment] = "Enable the cluster membership discovery."
+	ClusterDiscoveryConfigDoc.Fields[1].Name = "registries"
+	ClusterDiscoveryConfigDoc.Fields[1].Type = "DiscoveryRegistriesConfig"
+	ClusterDiscoveryConfigDoc.Fields[1].Note = ""
+	ClusterDiscoveryConfigDoc.Fields[1].Description = "Configure registries used for cluster member discovery."
+	ClusterDiscoveryConfigDoc.Fields[1].Comments[encoder.LineComment] = "Configure registries used for cluster member discovery."
+
+	DiscoveryRegistriesConfigDoc.Type = "DiscoveryRegistriesConfig"
+	DiscoveryRegistriesConfigDoc.Comments[encoder.LineComment] = "DiscoveryRegistriesConfig struct configures cluster membership discovery registries."
+	DiscoveryRegistriesConfigDoc.Description = "DiscoveryRegistriesConfig struct configures cluster membership discovery registries."
+	DiscoveryRegistriesConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "ClusterDiscoveryConfig",
+			FieldName: "registries",
+		},
+	}
+	DiscoveryRegistriesConfigDoc.Fields = make([]encoder.Doc, 2)
+	DiscoveryRegistriesConfigDoc.Fields[0].Name = "kubernetes"
+	DiscoveryRegistriesConfigDoc.Fields[0].Type = "RegistryKubernetesConfig"
+	DiscoveryRegistriesConfigDoc.Fields[0].Note = ""
+	DiscoveryRegistriesConfigDoc.Fields[0].Description = "Kubernetes registry uses Kubernetes API server to discover cluster members and stores additional information\nas annotations on the Node resources."
+	DiscoveryRegistriesConfigDoc.Fields[0].Comments[encoder.LineComment] = "Kubernetes registry uses Kubernetes API server to discover cluster members and stores additional information"
+	DiscoveryRegistriesConfigDoc.Fields[1].Name = "service"
+	DiscoveryRegistriesConfigDoc.Fields[1].Type = "RegistryServiceConfig"
+	DiscoveryRegistriesConfigDoc.Fields[1].Note = ""
+	DiscoveryRegistriesConfigDoc.Fields[1].Description = "Service registry is using an external service to push and pull information about cluster members."
+	DiscoveryRegistriesConfigDoc.Fields[1].Comments[encoder.LineComment] = "Service registry is using an external service to push and pull information about cluster members."
+
+	RegistryKubernetesConfigDoc.Type = "RegistryKubernetesConfig"
+	RegistryKubernetesConfigDoc.Comments[encoder.LineComment] = "RegistryKubernetesConfig struct configures Kubernetes discovery registry."
+	RegistryKubernetesConfigDoc.Description = "RegistryKubernetesConfig struct configures Kubernetes discovery registry."
+	RegistryKubernetesConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "DiscoveryRegistriesConfig",
+			FieldName: "kubernetes",
+		},
+	}
+	RegistryKubernetesConfigDoc.Fields = make([]encoder.Doc, 1)
+	RegistryKubernetesConfigDoc.Fields[0].Name = "disabled"
+	RegistryKubernetesConfigDoc.Fields[0].Type = "bool"
+	RegistryKubernetesConfigDoc.Fields[0].Note = ""
+	RegistryKubernetesConfigDoc.Fields[0].Description = "Disable Kubernetes discovery registry."
+	RegistryKubernetesConfigDoc.Fields[0].Comments[encoder.LineComment] = "Disable Kubernetes discovery registry."
+
+	RegistryServiceConfigDoc.Type = "RegistryServiceConfig"
+	RegistryServiceConfigDoc.Comments[encoder.LineComment] = "RegistryServiceConfig struct configures the discovery service registry."
+	RegistryServiceConfigDoc.Description = "RegistryServiceConfig struct configures the discovery service registry."
+	RegistryServiceConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "DiscoveryRegistriesConfig",
+			FieldName: "service",
+		},
+	}
+	RegistryServiceConfigDoc.Fields = make([]encoder.Doc, 2)
+	RegistryServiceConfigDoc.Fields[0].Name = "disabled"
+	RegistryServiceConfigDoc.Fields[0].Type = "bool"
+	RegistryServiceConfigDoc.Fields[0].Note = ""
+	RegistryServiceConfigDoc.Fields[0].Description = "Disable external service discovery registry."
+	RegistryServiceConfigDoc.Fields[0].Comments[encoder.LineComment] = "Disable external service discovery registry."
+	RegistryServiceConfigDoc.Fields[1].Name = "endpoint"
+	RegistryServiceConfigDoc.Fields[1].Type = "string"
+	RegistryServiceConfigDoc.Fields[1].Note = ""
+	RegistryServiceConfigDoc.Fields[1].Description = "External service endpoint."
+	RegistryServiceConfigDoc.Fields[1].Comments[encoder.LineComment] = "External service endpoint."
+
+	RegistryServiceConfigDoc.Fields[1].AddExample("", "https://discovery.example.com/")
+
 	MachineDiskDoc.Type = "MachineDisk"
 	MachineDiskDoc.Comments[encoder.LineComment] = "MachineDisk represents the options available for partitioning, formatting, and"
 	MachineDiskDoc.Description = "MachineDisk represents the options available for partitioning, formatting, and\nmounting extra disks.\n"
@@ -869,7 +2539,7 @@ func init() {
 			FieldName: "partitions",
 		},
 	}
-	DiskPartitionDoc.Fields = make([]encoder.Doc, 2)
+	DiskPartitionDoc.Fields = make([]encoder.Doc, 6)
 	DiskPartitionDoc.Fields[0].Name = "size"
 	DiskPartitionDoc.Fields[0].Type = "DiskSize"
 	DiskPartitionDoc.Fields[0].Note = ""
@@ -884,6 +2554,162 @@ func init() {
 	DiskPartitionDoc.Fields[1].Note = ""
 	DiskPartitionDoc.Fields[1].Description = "Where to mount the partition."
 	DiskPartitionDoc.Fields[1].Comments[encoder.LineComment] = "Where to mount the partition."
+	DiskPartitionDoc.Fields[2].Name = "filesystem"
+	DiskPartitionDoc.Fields[2].Type = "string"
+	DiskPartitionDoc.Fields[2].Note = ""
+	DiskPartitionDoc.Fields[2].Description = "Filesystem of the partition, `none` leaves the partition unformatted (e.g. for RAID members and LVM physical volumes).\nDefaults to `xfs`."
+	DiskPartitionDoc.Fields[2].Comments[encoder.LineComment] = "Filesystem of the partition, `none` leaves the partition unformatted (e.g. for RAID members and LVM physical volumes)."
+	DiskPartitionDoc.Fields[2].Values = []string{
+		"xfs",
+		"ext4",
+		"none",
+	}
+	DiskPartitionDoc.Fields[3].Name = "mountOptions"
+	DiskPartitionDoc.Fields[3].Type = "[]string"
+	DiskPartitionDoc.Fields[3].Note = ""
+	DiskPartitionDoc.Fields[3].Description = "Mount options of the partition.\nOptions which are not mount flags are passed to the filesystem as is, e.g. `prjquota` enables XFS project quotas.\n`noatime` is used unless one of `atime`, `strictatime` or `relatime` is specified."
+	DiskPartitionDoc.Fields[3].Comments[encoder.LineComment] = "Mount options of the partition."
+
+	DiskPartitionDoc.Fields[3].AddExample("", []string{"nodev", "nosuid"})
+
+	DiskPartitionDoc.Fields[3].AddExample("XFS project quotas.", []string{"nodev", "prjquota"})
+	DiskPartitionDoc.Fields[4].Name = "label"
+	DiskPartitionDoc.Fields[4].Type = "string"
+	DiskPartitionDoc.Fields[4].Note = ""
+	DiskPartitionDoc.Fields[4].Description = "Label of the partition and the filesystem.\nFilesystem labels are limited to 12 characters for `xfs` and to 16 characters for `ext4`."
+	DiskPartitionDoc.Fields[4].Comments[encoder.LineComment] = "Label of the partition and the filesystem."
+	DiskPartitionDoc.Fields[5].Name = "grow"
+	DiskPartitionDoc.Fields[5].Type = "bool"
+	DiskPartitionDoc.Fields[5].Note = ""
+	DiskPartitionDoc.Fields[5].Description = "Grow the last partition and its filesystem to fill the disk on every boot (e.g. after the cloud volume is expanded)."
+	DiskPartitionDoc.Fields[5].Comments[encoder.LineComment] = "Grow the last partition and its filesystem to fill the disk on every boot (e.g. after the cloud volume is expanded)."
+
+	RAIDConfigDoc.Type = "RAIDConfig"
+	RAIDConfigDoc.Comments[encoder.LineComment] = "RAIDConfig represents the options for the md-RAID array."
+	RAIDConfigDoc.Description = "RAIDConfig represents the options for the md-RAID array."
+
+	RAIDConfigDoc.AddExample("", machineRAIDsExample)
+	RAIDConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "raids",
+		},
+	}
+	RAIDConfigDoc.Fields = make([]encoder.Doc, 3)
+	RAIDConfigDoc.Fields[0].Name = "name"
+	RAIDConfigDoc.Fields[0].Type = "string"
+	RAIDConfigDoc.Fields[0].Note = ""
+	RAIDConfigDoc.Fields[0].Description = "Name of the array."
+	RAIDConfigDoc.Fields[0].Comments[encoder.LineComment] = "Name of the array."
+	RAIDConfigDoc.Fields[1].Name = "level"
+	RAIDConfigDoc.Fields[1].Type = "string"
+	RAIDConfigDoc.Fields[1].Note = ""
+	RAIDConfigDoc.Fields[1].Description = "RAID level of the array."
+	RAIDConfigDoc.Fields[1].Comments[encoder.LineComment] = "RAID level of the array."
+	RAIDConfigDoc.Fields[1].Values = []string{
+		"raid0",
+		"raid1",
+		"raid5",
+		"raid6",
+		"raid10",
+	}
+	RAIDConfigDoc.Fields[2].Name = "devices"
+	RAIDConfigDoc.Fields[2].Type = "[]string"
+	RAIDConfigDoc.Fields[2].Note = ""
+	RAIDConfigDoc.Fields[2].Description = "Member devices of the array (disks or partitions with `filesystem: none`)."
+	RAIDConfigDoc.Fields[2].Comments[encoder.LineComment] = "Member devices of the array (disks or partitions with `filesystem: none`)."
+
+	VolumeGroupConfigDoc.Type = "VolumeGroupConfig"
+	VolumeGroupConfigDoc.Comments[encoder.LineComment] = "VolumeGroupConfig represents the options for the LVM volume group."
+	VolumeGroupConfigDoc.Description = "VolumeGroupConfig represents the options for the LVM volume group."
+
+	VolumeGroupConfigDoc.AddExample("", machineVolumeGroupsExample)
+	VolumeGroupConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "volumeGroups",
+		},
+	}
+	VolumeGroupConfigDoc.Fields = make([]encoder.Doc, 3)
+	VolumeGroupConfigDoc.Fields[0].Name = "name"
+	VolumeGroupConfigDoc.Fields[0].Type = "string"
+	VolumeGroupConfigDoc.Fields[0].Note = ""
+	VolumeGroupConfigDoc.Fields[0].Description = "Name of the volume group."
+	VolumeGroupConfigDoc.Fields[0].Comments[encoder.LineComment] = "Name of the volume group."
+	VolumeGroupConfigDoc.Fields[1].Name = "physicalVolumes"
+	VolumeGroupConfigDoc.Fields[1].Type = "[]string"
+	VolumeGroupConfigDoc.Fields[1].Note = ""
+	VolumeGroupConfigDoc.Fields[1].Description = "Physical volumes of the volume group (disks, partitions with `filesystem: none` or RAID arrays)."
+	VolumeGroupConfigDoc.Fields[1].Comments[encoder.LineComment] = "Physical volumes of the volume group (disks, partitions with `filesystem: none` or RAID arrays)."
+	VolumeGroupConfigDoc.Fields[2].Name = "logicalVolumes"
+	VolumeGroupConfigDoc.Fields[2].Type = "[]LogicalVolumeConfig"
+	VolumeGroupConfigDoc.Fields[2].Note = ""
+	VolumeGroupConfigDoc.Fields[2].Description = "Logical volumes to create in the volume group."
+	VolumeGroupConfigDoc.Fields[2].Comments[encoder.LineComment] = "Logical volumes to create in the volume group."
+
+	LogicalVolumeConfigDoc.Type = "LogicalVolumeConfig"
+	LogicalVolumeConfigDoc.Comments[encoder.LineComment] = "LogicalVolumeConfig represents the options for the LVM logical volume."
+	LogicalVolumeConfigDoc.Description = "LogicalVolumeConfig represents the options for the LVM logical volume."
+	LogicalVolumeConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "VolumeGroupConfig",
+			FieldName: "logicalVolumes",
+		},
+	}
+	LogicalVolumeConfigDoc.Fields = make([]encoder.Doc, 5)
+	LogicalVolumeConfigDoc.Fields[0].Name = "name"
+	LogicalVolumeConfigDoc.Fields[0].Type = "string"
+	LogicalVolumeConfigDoc.Fields[0].Note = ""
+	LogicalVolumeConfigDoc.Fields[0].Description = "Name of the logical volume."
+	LogicalVolumeConfigDoc.Fields[0].Comments[encoder.LineComment] = "Name of the logical volume."
+	LogicalVolumeConfigDoc.Fields[1].Name = "size"
+	LogicalVolumeConfigDoc.Fields[1].Type = "DiskSize"
+	LogicalVolumeConfigDoc.Fields[1].Note = ""
+	LogicalVolumeConfigDoc.Fields[1].Description = "Size of the logical volume: either bytes or human readable representation.\nIf `size:` is omitted, the logical volume occupies the remaining free space of the volume group."
+	LogicalVolumeConfigDoc.Fields[1].Comments[encoder.LineComment] = "Size of the logical volume: either bytes or human readable representation."
+	LogicalVolumeConfigDoc.Fields[2].Name = "filesystem"
+	LogicalVolumeConfigDoc.Fields[2].Type = "string"
+	LogicalVolumeConfigDoc.Fields[2].Note = ""
+	LogicalVolumeConfigDoc.Fields[2].Description = "Filesystem of the logical volume.\nDefaults to `xfs`."
+	LogicalVolumeConfigDoc.Fields[2].Comments[encoder.LineComment] = "Filesystem of the logical volume."
+	LogicalVolumeConfigDoc.Fields[2].Values = []string{
+		"xfs",
+		"ext4",
+		"none",
+	}
+	LogicalVolumeConfigDoc.Fields[3].Name = "mountpoint"
+	LogicalVolumeConfigDoc.Fields[3].Type = "string"
+	LogicalVolumeConfigDoc.Fields[3].Note = ""
+	LogicalVolumeConfigDoc.Fields[3].Description = "Where to mount the logical volume."
+	LogicalVolumeConfigDoc.Fields[3].Comments[encoder.LineComment] = "Where to mount the logical volume."
+	LogicalVolumeConfigDoc.Fields[4].Name = "mountOptions"
+	LogicalVolumeConfigDoc.Fields[4].Type = "[]string"
+	LogicalVolumeConfigDoc.Fields[4].Note = ""
+	LogicalVolumeConfigDoc.Fields[4].Description = "Mount options of the logical volume."
+	LogicalVolumeConfigDoc.Fields[4].Comments[encoder.LineComment] = "Mount options of the logical volume."
+
+	EphemeralQuotaConfigDoc.Type = "EphemeralQuotaConfig"
+	EphemeralQuotaConfigDoc.Comments[encoder.LineComment] = "EphemeralQuotaConfig represents the disk usage limit for the directory on the EPHEMERAL partition."
+	EphemeralQuotaConfigDoc.Description = "EphemeralQuotaConfig represents the disk usage limit for the directory on the EPHEMERAL partition."
+
+	EphemeralQuotaConfigDoc.AddExample("", machineEphemeralQuotasExample)
+	EphemeralQuotaConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "ephemeralQuotas",
+		},
+	}
+	EphemeralQuotaConfigDoc.Fields = make([]encoder.Doc, 2)
+	EphemeralQuotaConfigDoc.Fields[0].Name = "path"
+	EphemeralQuotaConfigDoc.Fields[0].Type = "string"
+	EphemeralQuotaConfigDoc.Fields[0].Note = ""
+	EphemeralQuotaConfigDoc.Fields[0].Description = "The directory under `/var`, the directory is created if it doesn't exist."
+	EphemeralQuotaConfigDoc.Fields[0].Comments[encoder.LineComment] = "The directory under `/var`, the directory is created if it doesn't exist."
+	EphemeralQuotaConfigDoc.Fields[1].Name = "limit"
+	EphemeralQuotaConfigDoc.Fields[1].Type = "DiskSize"
+	EphemeralQuotaConfigDoc.Fields[1].Note = ""
+	EphemeralQuotaConfigDoc.Fields[1].Description = "The hard limit on the disk usage of the directory: either bytes or human readable representation."
+	EphemeralQuotaConfigDoc.Fields[1].Comments[encoder.LineComment] = "The hard limit on the disk usage of the directory: either bytes or human readable representation."
 
 	MachineFileDoc.Type = "MachineFile"
 	MachineFileDoc.Comments[encoder.LineComment] = "MachineFile represents a file to write to disk."
@@ -896,33 +2722,68 @@ func init() {
 			FieldName: "files",
 		},
 	}
-	MachineFileDoc.Fields = make([]encoder.Doc, 4)
+	MachineFileDoc.Fields = make([]encoder.Doc, 5)
 	MachineFileDoc.Fields[0].Name = "content"
 	MachineFileDoc.Fields[0].Type = "string"
 	MachineFileDoc.Fields[0].Note = ""
 	MachineFileDoc.Fields[0].Description = "The contents of the file."
 	MachineFileDoc.Fields[0].Comments[encoder.LineComment] = "The contents of the file."
-	MachineFileDoc.Fields[1].Name = "permissions"
-	MachineFileDoc.Fields[1].Type = "FileMode"
+	MachineFileDoc.Fields[1].Name = "contentFrom"
+	MachineFileDoc.Fields[1].Type = "MachineFileContentSource"
 	MachineFileDoc.Fields[1].Note = ""
-	MachineFileDoc.Fields[1].Description = "The file's permissions in octal."
-	MachineFileDoc.Fields[1].Comments[encoder.LineComment] = "The file's permissions in octal."
-	MachineFileDoc.Fields[2].Name = "path"
-	MachineFileDoc.Fields[2].Type = "string"
+	MachineFileDoc.Fields[1].Description = "Fetch the contents of the file from a URL instead of inlining them in `content`.\nThe contents are downloaded (with retries) every time the files are written, and verified against the SHA-256 checksum."
+	MachineFileDoc.Fields[1].Comments[encoder.LineComment] = "Fetch the contents of the file from a URL instead of inlining them in `content`."
+
+	MachineFileDoc.Fields[1].AddExample("", machineFileContentFromExample)
+	MachineFileDoc.Fields[2].Name = "permissions"
+	MachineFileDoc.Fields[2].Type = "FileMode"
 	MachineFileDoc.Fields[2].Note = ""
-	MachineFileDoc.Fields[2].Description = "The path of the file."
-	MachineFileDoc.Fields[2].Comments[encoder.LineComment] = "The path of the file."
-	MachineFileDoc.Fields[3].Name = "op"
+	MachineFileDoc.Fields[2].Description = "The file's permissions in octal."
+	MachineFileDoc.Fields[2].Comments[encoder.LineComment] = "The file's permissions in octal."
+	MachineFileDoc.Fields[3].Name = "path"
 	MachineFileDoc.Fields[3].Type = "string"
 	MachineFileDoc.Fields[3].Note = ""
-	MachineFileDoc.Fields[3].Description = "The operation to use"
-	MachineFileDoc.Fields[3].Comments[encoder.LineComment] = "The operation to use"
-	MachineFileDoc.Fields[3].Values = []string{
+	MachineFileDoc.Fields[3].Description = "The absolute path of the file, in `/var` or in one of `/etc/kubernetes/manifests`, `/etc/cri/conf.d` and `/etc/ssl/certs`."
+	MachineFileDoc.Fields[3].Comments[encoder.LineComment] = "The absolute path of the file, in `/var` or in one of `/etc/kubernetes/manifests`, `/etc/cri/conf.d` and `/etc/ssl/certs`."
+	MachineFileDoc.Fields[4].Name = "op"
+	MachineFileDoc.Fields[4].Type = "string"
+	MachineFileDoc.Fields[4].Note = ""
+	MachineFileDoc.Fields[4].Description = "The operation to use"
+	MachineFileDoc.Fields[4].Comments[encoder.LineComment] = "The operation to use"
+	MachineFileDoc.Fields[4].Values = []string{
 		"create",
 		"append",
 		"overwrite",
 	}
 
+	MachineFileContentSourceDoc.Type = "MachineFileContentSource"
+	MachineFileContentSourceDoc.Comments[encoder.LineComment] = "MachineFileContentSource represents the remote source of the file contents."
+	MachineFileContentSourceDoc.Description = "MachineFileContentSource represents the remote source of the file contents."
+
+	MachineFileContentSourceDoc.AddExample("", machineFileContentFromExample)
+	MachineFileContentSourceDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineFile",
+			FieldName: "contentFrom",
+		},
+	}
+	MachineFileContentSourceDoc.Fields = make([]encoder.Doc, 3)
+	MachineFileContentSourceDoc.Fields[0].Name = "url"
+	MachineFileContentSourceDoc.Fields[0].Type = "string"
+	MachineFileContentSourceDoc.Fields[0].Note = ""
+	MachineFileContentSourceDoc.Fields[0].Description = "The URL to download the contents from, `http` and `https` URLs are supported."
+	MachineFileContentSourceDoc.Fields[0].Comments[encoder.LineComment] = "The URL to download the contents from, `http` and `https` URLs are supported."
+	MachineFileContentSourceDoc.Fields[1].Name = "sha256"
+	MachineFileContentSourceDoc.Fields[1].Type = "string"
+	MachineFileContentSourceDoc.Fields[1].Note = ""
+	MachineFileContentSourceDoc.Fields[1].Description = "The SHA-256 checksum of the contents (hex-encoded)."
+	MachineFileContentSourceDoc.Fields[1].Comments[encoder.LineComment] = "The SHA-256 checksum of the contents (hex-encoded)."
+	MachineFileContentSourceDoc.Fields[2].Name = "headers"
+	MachineFileContentSourceDoc.Fields[2].Type = "map[string]string"
+	MachineFileContentSourceDoc.Fields[2].Note = ""
+	MachineFileContentSourceDoc.Fields[2].Description = "Additional HTTP headers sent with the request, e.g. for the authorization."
+	MachineFileContentSourceDoc.Fields[2].Comments[encoder.LineComment] = "Additional HTTP headers sent with the request, e.g. for the authorization."
+
 	ExtraHostDoc.Type = "ExtraHost"
 	ExtraHostDoc.Comments[encoder.LineComment] = "ExtraHost represents a host entry in /etc/hosts."
 	ExtraHostDoc.Description = "ExtraHost represents a host entry in /etc/hosts."
@@ -1308,7 +3169,7 @@ func init() {
 			FieldName: "auth",
 		},
 	}
-	RegistryAuthConfigDoc.Fields = make([]encoder.Doc, 4)
+	RegistryAuthConfigDoc.Fields = make([]encoder.Doc, 5)
 	RegistryAuthConfigDoc.Fields[0].Name = "username"
 	RegistryAuthConfigDoc.Fields[0].Type = "string"
 	RegistryAuthConfigDoc.Fields[0].Note = ""
@@ -1329,6 +3190,41 @@ func init() {
 	RegistryAuthConfigDoc.Fields[3].Note = ""
 	RegistryAuthConfigDoc.Fields[3].Description = "Optional registry authentication.\nThe meaning of each field is the same with the corresponding field in .docker/config.json."
 	RegistryAuthConfigDoc.Fields[3].Comments[encoder.LineComment] = "Optional registry authentication."
+	RegistryAuthConfigDoc.Fields[4].Name = "provider"
+	RegistryAuthConfigDoc.Fields[4].Type = "RegistryCredentialProviderConfig"
+	RegistryAuthConfigDoc.Fields[4].Note = ""
+	RegistryAuthConfigDoc.Fields[4].Description = "External credential provider which exchanges the machine identity for short-lived registry credentials.\nThe provider is executed at image pull time, and the credentials are cached until they are about to expire.\nStatic credentials should not be set if the provider is used."
+	RegistryAuthConfigDoc.Fields[4].Comments[encoder.LineComment] = "External credential provider which exchanges the machine identity for short-lived registry credentials."
+
+	RegistryAuthConfigDoc.Fields[4].AddExample("", machineConfigRegistryCredentialProviderExample)
+
+	RegistryCredentialProviderConfigDoc.Type = "RegistryCredentialProviderConfig"
+	RegistryCredentialProviderConfigDoc.Comments[encoder.LineComment] = "RegistryCredentialProviderConfig specifies an external registry credential provider."
+	RegistryCredentialProviderConfigDoc.Description = "RegistryCredentialProviderConfig specifies an external registry credential provider."
+
+	RegistryCredentialProviderConfigDoc.AddExample("", machineConfigRegistryCredentialProviderExample)
+	RegistryCredentialProviderConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "RegistryAuthConfig",
+			FieldName: "provider",
+		},
+	}
+	RegistryCredentialProviderConfigDoc.Fields = make([]encoder.Doc, 3)
+	RegistryCredentialProviderConfigDoc.Fields[0].Name = "exec"
+	RegistryCredentialProviderConfigDoc.Fields[0].Type = "string"
+	RegistryCredentialProviderConfigDoc.Fields[0].Note = ""
+	RegistryCredentialProviderConfigDoc.Fields[0].Description = "Absolute path to the credential provider executable.\nThe executable should implement the kubelet credential provider protocol:\nit reads `CredentialProviderRequest` from stdin and writes `CredentialProviderResponse` to stdout."
+	RegistryCredentialProviderConfigDoc.Fields[0].Comments[encoder.LineComment] = "Absolute path to the credential provider executable."
+	RegistryCredentialProviderConfigDoc.Fields[1].Name = "args"
+	RegistryCredentialProviderConfigDoc.Fields[1].Type = "[]string"
+	RegistryCredentialProviderConfigDoc.Fields[1].Note = ""
+	RegistryCredentialProviderConfigDoc.Fields[1].Description = "Arguments to pass to the credential provider executable."
+	RegistryCredentialProviderConfigDoc.Fields[1].Comments[encoder.LineComment] = "Arguments to pass to the credential provider executable."
+	RegistryCredentialProviderConfigDoc.Fields[2].Name = "env"
+	RegistryCredentialProviderConfigDoc.Fields[2].Type = "map[string]string"
+	RegistryCredentialProviderConfigDoc.Fields[2].Note = ""
+	RegistryCredentialProviderConfigDoc.Fields[2].Description = "Environment variables to set for the credential provider executable."
+	RegistryCredentialProviderConfigDoc.Fields[2].Comments[encoder.LineComment] = "Environment variables to set for the credential provider executable."
 
 	RegistryTLSConfigDoc.Type = "RegistryTLSConfig"
 	RegistryTLSConfigDoc.Comments[encoder.LineComment] = "RegistryTLSConfig specifies TLS config for HTTPS registries."
@@ -1371,6 +3267,10 @@ func (_ MachineConfig) Doc() *encoder.Doc {
 	return &MachineConfigDoc
 }
 
+func (_ MachineProxyConfig) Doc() *encoder.Doc {
+	return &MachineProxyConfigDoc
+}
+
 func (_ ClusterConfig) Doc() *encoder.Doc {
 	return &ClusterConfigDoc
 }
@@ -1379,6 +3279,26 @@ func (_ KubeletConfig) Doc() *encoder.Doc {
 	return &KubeletConfigDoc
 }
 
+func (_ KubeletMemoryManagerConfig) Doc() *encoder.Doc {
+	return &KubeletMemoryManagerConfigDoc
+}
+
+func (_ KubeletReservedMemoryConfig) Doc() *encoder.Doc {
+	return &KubeletReservedMemoryConfigDoc
+}
+
+func (_ KubeletNodeIPConfig) Doc() *encoder.Doc {
+	return &KubeletNodeIPConfigDoc
+}
+
+func (_ KubeletCredentialProviderConfig) Doc() *encoder.Doc {
+	return &KubeletCredentialProviderConfigDoc
+}
+
+func (_ KubeletCredentialProvider) Doc() *encoder.Doc {
+	return &KubeletCredentialProviderDoc
+}
+
 func (_ NetworkConfig) Doc() *encoder.Doc {
 	return &NetworkConfigDoc
 }
@@ -1387,6 +3307,10 @@ func (_ InstallConfig) Doc() *encoder.Doc {
 	return &InstallConfigDoc
 }
 
+func (_ InstallConsoleConfig) Doc() *encoder.Doc {
+	return &InstallConsoleConfigDoc
+}
+
 func (_ TimeConfig) Doc() *encoder.Doc {
 	return &TimeConfigDoc
 }
@@ -1395,6 +3319,114 @@ func (_ RegistriesConfig) Doc() *encoder.Doc {
 	return &RegistriesConfigDoc
 }
 
+func (_ LoggingConfig) Doc() *encoder.Doc {
+	return &LoggingConfigDoc
+}
+
+func (_ ConsoleLoggingConfig) Doc() *encoder.Doc {
+	return &ConsoleLoggingConfigDoc
+}
+
+func (_ LogPersistenceConfig) Doc() *encoder.Doc {
+	return &LogPersistenceConfigDoc
+}
+
+func (_ KernelConfig) Doc() *encoder.Doc {
+	return &KernelConfigDoc
+}
+
+func (_ KernelModuleConfig) Doc() *encoder.Doc {
+	return &KernelModuleConfigDoc
+}
+
+func (_ ServiceResourcesConfig) Doc() *encoder.Doc {
+	return &ServiceResourcesConfigDoc
+}
+
+func (_ ExtensionServiceConfig) Doc() *encoder.Doc {
+	return &ExtensionServiceConfigDoc
+}
+
+func (_ ExtensionServiceSecurityConfig) Doc() *encoder.Doc {
+	return &ExtensionServiceSecurityConfigDoc
+}
+
+func (_ CRIConfig) Doc() *encoder.Doc {
+	return &CRIConfigDoc
+}
+
+func (_ CRIRuntimeConfig) Doc() *encoder.Doc {
+	return &CRIRuntimeConfigDoc
+}
+
+func (_ BMCConfig) Doc() *encoder.Doc {
+	return &BMCConfigDoc
+}
+
+func (_ SystemDiskEncryptionConfig) Doc() *encoder.Doc {
+	return &SystemDiskEncryptionConfigDoc
+}
+
+func (_ EncryptionConfig) Doc() *encoder.Doc {
+	return &EncryptionConfigDoc
+}
+
+func (_ EncryptionKey) Doc() *encoder.Doc {
+	return &EncryptionKeyDoc
+}
+
+func (_ EncryptionKeyTPM) Doc() *encoder.Doc {
+	return &EncryptionKeyTPMDoc
+}
+
+func (_ FeaturesConfig) Doc() *encoder.Doc {
+	return &FeaturesConfigDoc
+}
+
+func (_ KubernetesTalosAPIAccessConfig) Doc() *encoder.Doc {
+	return &KubernetesTalosAPIAccessConfigDoc
+}
+
+func (_ LocalAPIAccessConfig) Doc() *encoder.Doc {
+	return &LocalAPIAccessConfigDoc
+}
+
+func (_ NFSMountsConfig) Doc() *encoder.Doc {
+	return &NFSMountsConfigDoc
+}
+
+func (_ ISCSIConfig) Doc() *encoder.Doc {
+	return &ISCSIConfigDoc
+}
+
+func (_ ManagementTunnelConfig) Doc() *encoder.Doc {
+	return &ManagementTunnelConfigDoc
+}
+
+func (_ ConfigStorageConfig) Doc() *encoder.Doc {
+	return &ConfigStorageConfigDoc
+}
+
+func (_ ImageVerificationConfig) Doc() *encoder.Doc {
+	return &ImageVerificationConfigDoc
+}
+
+func (_ RBACConfig) Doc() *encoder.Doc {
+	return &RBACConfigDoc
+}
+
+func (_ RBACRule) Doc() *encoder.Doc {
+	return &RBACRuleDoc
+}
+
+func (_ APIAuthorizationPolicyConfig) Doc() *encoder.Doc {
+	return &APIAuthorizationPolicyConfigDoc
+}
+
+func (_ APIAuthorizationPolicyRule) Doc() *encoder.Doc {
+	return &APIAuthorizationPolicyRuleDoc
+}
+
 func (_ PodCheckpointer) Doc() *encoder.Doc {
 	return &PodCheckpointerDoc
 }
@@ -1403,10 +3435,18 @@ func (_ CoreDNS) Doc() *encoder.Doc {
 	return &CoreDNSDoc
 }
 
+func (_ CoreDNSForwardZone) Doc() *encoder.Doc {
+	return &CoreDNSForwardZoneDoc
+}
+
 func (_ Endpoint) Doc() *encoder.Doc {
 	return &EndpointDoc
 }
 
+func (_ PEMEncodedKey) Doc() *encoder.Doc {
+	return &PEMEncodedKeyDoc
+}
+
 func (_ ControlPlaneConfig) Doc() *encoder.Doc {
 	return &ControlPlaneConfigDoc
 }
@@ -1431,6 +3471,10 @@ func (_ EtcdConfig) Doc() *encoder.Doc {
 	return &EtcdConfigDoc
 }
 
+func (_ EtcdMaintenanceConfig) Doc() *encoder.Doc {
+	return &EtcdMaintenanceConfigDoc
+}
+
 func (_ ClusterNetworkConfig) Doc() *encoder.Doc {
 	return &ClusterNetworkConfigDoc
 }
@@ -1439,10 +3483,38 @@ func (_ CNIConfig) Doc() *encoder.Doc {
 	return &CNIConfigDoc
 }
 
+func (_ FlannelCNIConfig) Doc() *encoder.Doc {
+	return &FlannelCNIConfigDoc
+}
+
 func (_ AdminKubeconfigConfig) Doc() *encoder.Doc {
 	return &AdminKubeconfigConfigDoc
 }
 
+func (_ ExternalCloudProviderConfig) Doc() *encoder.Doc {
+	return &ExternalCloudProviderConfigDoc
+}
+
+func (_ JoinThrottleConfig) Doc() *encoder.Doc {
+	return &JoinThrottleConfigDoc
+}
+
+func (_ ClusterDiscoveryConfig) Doc() *encoder.Doc {
+	return &ClusterDiscoveryConfigDoc
+}
+
+func (_ DiscoveryRegistriesConfig) Doc() *encoder.Doc {
+	return &DiscoveryRegistriesConfigDoc
+}
+
+func (_ RegistryKubernetesConfig) Doc() *encoder.Doc {
+	return &RegistryKubernetesConfigDoc
+}
+
+func (_ RegistryServiceConfig) Doc() *encoder.Doc {
+	return &RegistryServiceConfigDoc
+}
+
 func (_ MachineDisk) Doc() *encoder.Doc {
 	return &MachineDiskDoc
 }
@@ -1451,10 +3523,30 @@ func (_ DiskPartition) Doc() *encoder.Doc {
 	return &DiskPartitionDoc
 }
 
+func (_ RAIDConfig) Doc() *encoder.Doc {
+	return &RAIDConfigDoc
+}
+
+func (_ VolumeGroupConfig) Doc() *encoder.Doc {
+	return &VolumeGroupConfigDoc
+}
+
+func (_ LogicalVolumeConfig) Doc() *encoder.Doc {
+	return &LogicalVolumeConfigDoc
+}
+
+func (_ EphemeralQuotaConfig) Doc() *encoder.Doc {
+	return &EphemeralQuotaConfigDoc
+}
+
 func (_ MachineFile) Doc() *encoder.Doc {
 	return &MachineFileDoc
 }
 
+func (_ MachineFileContentSource) Doc() *encoder.Doc {
+	return &MachineFileContentSourceDoc
+}
+
 func (_ ExtraHost) Doc() *encoder.Doc {
 	return &ExtraHostDoc
 }
@@ -1491,6 +3583,10 @@ func (_ RegistryAuthConfig) Doc() *encoder.Doc {
 	return &RegistryAuthConfigDoc
 }
 
+func (_ RegistryCredentialProviderConfig) Doc() *encoder.Doc {
+	return &RegistryCredentialProviderConfigDoc
+}
+
 func (_ RegistryTLSConfig) Doc() *encoder.Doc {
 	return &RegistryTLSConfigDoc
 }
@@ -1503,27 +3599,76 @@ func GetConfigurationDoc() *encoder.FileDoc {
 		Structs: []*encoder.Doc{
 			&ConfigDoc,
 			&MachineConfigDoc,
+			&MachineProxyConfigDoc,
 			&ClusterConfigDoc,
 			&KubeletConfigDoc,
+			&KubeletMemoryManagerConfigDoc,
+			&KubeletReservedMemoryConfigDoc,
+			&KubeletNodeIPConfigDoc,
+			&KubeletCredentialProviderConfigDoc,
+			&KubeletCredentialProviderDoc,
 			&NetworkConfigDoc,
 			&InstallConfigDoc,
+			&InstallConsoleConfigDoc,
 			&TimeConfigDoc,
 			&RegistriesConfigDoc,
+			&LoggingConfigDoc,
+			&ConsoleLoggingConfigDoc,
+			&LogPersistenceConfigDoc,
+			&KernelConfigDoc,
+			&KernelModuleConfigDoc,
+			&ServiceResourcesConfigDoc,
+			&ExtensionServiceConfigDoc,
+			&ExtensionServiceSecurityConfigDoc,
+			&CRIConfigDoc,
+			&CRIRuntimeConfigDoc,
+			&BMCConfigDoc,
+			&SystemDiskEncryptionConfigDoc,
+			&EncryptionConfigDoc,
+			&EncryptionKeyDoc,
+			&EncryptionKeyTPMDoc,
+			&FeaturesConfigDoc,
+			&KubernetesTalosAPIAccessConfigDoc,
+			&LocalAPIAccessConfigDoc,
+			&NFSMountsConfigDoc,
+			&ISCSIConfigDoc,
+			&ManagementTunnelConfigDoc,
+			&ConfigStorageConfigDoc,
+			&ImageVerificationConfigDoc,
+			&RBACConfigDoc,
+			&RBACRuleDoc,
+			&APIAuthorizationPolicyConfigDoc,
+			&APIAuthorizationPolicyRuleDoc,
 			&PodCheckpointerDoc,
 			&CoreDNSDoc,
+			&CoreDNSForwardZoneDoc,
 			&EndpointDoc,
+			&PEMEncodedKeyDoc,
 			&ControlPlaneConfigDoc,
 			&APIServerConfigDoc,
 			&ControllerManagerConfigDoc,
 			&ProxyConfigDoc,
 			&SchedulerConfigDoc,
 			&EtcdConfigDoc,
+			&EtcdMaintenanceConfigDoc,
 			&ClusterNetworkConfigDoc,
 			&CNIConfigDoc,
+			&FlannelCNIConfigDoc,
 			&AdminKubeconfigConfigDoc,
+			&ExternalCloudProviderConfigDoc,
+			&JoinThrottleConfigDoc,
+			&ClusterDiscoveryConfigDoc,
+			&DiscoveryRegistriesConfigDoc,
+			&RegistryKubernetesConfigDoc,
+			&RegistryServiceConfigDoc,
 			&MachineDiskDoc,
 			&DiskPartitionDoc,
+			&RAIDConfigDoc,
+			&VolumeGroupConfigDoc,
+			&LogicalVolumeConfigDoc,
+			&EphemeralQuotaConfigDoc,
 			&MachineFileDoc,
+			&MachineFileContentSourceDoc,
 			&ExtraHostDoc,
 			&DeviceDoc,
 			&DHCPOptionsDoc,
@@ -1533,6 +3678,7 @@ func GetConfigurationDoc() *encoder.FileDoc {
 			&RegistryMirrorConfigDoc,
 			&RegistryConfigDoc,
 			&RegistryAuthConfigDoc,
+			&RegistryCredentialProviderConfigDoc,
 			&RegistryTLSConfigDoc,
 		},
 	}
@@ -7,11 +7,17 @@
 package v1alpha1
 
 import (
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 
 	valid "github.com/asaskevich/govalidator"
 	"github.com/hashicorp/go-multierror"
@@ -70,6 +76,16 @@ func (c *Config) Validate(mode config.RuntimeMode) error {
 		result = multierror.Append(result, err)
 	}
 
+	if c.ClusterConfig != nil {
+		if err := ValidateClusterSubnets(
+			strings.Split(c.ClusterConfig.PodCIDR(), ","),
+			strings.Split(c.ClusterConfig.ServiceCIDR(), ","),
+			c.nodeNetworks(),
+		); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
 	if mode.RequiresInstall() {
 		if c.MachineConfig.MachineInstall == nil {
 			result = multierror.Append(result, fmt.Errorf("install instructions are required in %q mode", mode))
@@ -82,18 +98,36 @@ func (c *Config) Validate(mode config.RuntimeMode) error {
 		if _, err := os.Stat(c.MachineConfig.MachineInstall.InstallDisk); os.IsNotExist(err) {
 			result = multierror.Append(result, fmt.Errorf("specified install disk does not exist: %q", c.MachineConfig.MachineInstall.InstallDisk))
 		}
+
+		if c.MachineConfig.MachineInstall.InstallEphemeralDisk != "" {
+			if _, err := os.Stat(c.MachineConfig.MachineInstall.InstallEphemeralDisk); os.IsNotExist(err) {
+				result = multierror.Append(result, fmt.Errorf("specified ephemeral disk does not exist: %q", c.MachineConfig.MachineInstall.InstallEphemeralDisk))
+			}
+		}
+	}
+
+	if c.MachineConfig != nil && c.MachineConfig.MachineInstall != nil && c.MachineConfig.MachineInstall.InstallEphemeralDisk != "" {
+		for _, disk := range c.MachineConfig.MachineDisks {
+			if disk.Device() == c.MachineConfig.MachineInstall.InstallEphemeralDisk {
+				result = multierror.Append(result, fmt.Errorf("ephemeral disk %q can't be used as a machine disk", disk.Device()))
+			}
+		}
 	}
 
 	if c.Machine().Type() == machine.TypeInit {
 		switch c.Cluster().Network().CNI().Name() {
-		case "custom":
+		case constants.CustomCNI:
 			if len(c.Cluster().Network().CNI().URLs()) == 0 {
 				result = multierror.Append(result, errors.New("at least one url should be specified if using \"custom\" option for CNI"))
 			}
 		case constants.DefaultCNI:
-			// it's flannel bby
+			if err := validateFlannel(c.Cluster().Network().CNI().Flannel()); err != nil {
+				result = multierror.Append(result, err)
+			}
+		case constants.NoneCNI:
+			// nothing to validate
 		default:
-			result = multierror.Append(result, errors.New("cni name should be one of [custom,flannel]"))
+			result = multierror.Append(result, fmt.Errorf("cni name should be one of [%s,%s,%s]", constants.CustomCNI, constants.DefaultCNI, constants.NoneCNI))
 		}
 	}
 
@@ -111,10 +145,188 @@ func (c *Config) Validate(mode config.RuntimeMode) error {
 				if pt.DiskSize == 0 && i != len(disk.DiskPartitions)-1 {
 					result = multierror.Append(result, fmt.Errorf("partition for disk %q is set to occupy full disk, but it's not the last partition in the list", disk.Device()))
 				}
+
+				if err := validateUserVolume(pt.Filesystem(), pt.MountPoint()); err != nil {
+					result = multierror.Append(result, fmt.Errorf("partition %d of disk %q: %w", i+1, disk.Device(), err))
+				}
+
+				if err := validatePartitionLabel(pt.Filesystem(), pt.Label()); err != nil {
+					result = multierror.Append(result, fmt.Errorf("partition %d of disk %q: %w", i+1, disk.Device(), err))
+				}
+
+				if pt.Grow() && i != len(disk.DiskPartitions)-1 {
+					result = multierror.Append(result, fmt.Errorf("partition %d of disk %q is set to grow, but it's not the last partition in the list", i+1, disk.Device()))
+				}
+
+				if pt.Grow() && pt.Filesystem() == "none" {
+					result = multierror.Append(result, fmt.Errorf("partition %d of disk %q: unformatted partition can't be grown", i+1, disk.Device()))
+				}
+			}
+		}
+	}
+
+	for _, raid := range c.MachineConfig.MachineRAIDs {
+		if err := raid.Validate(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	for _, vg := range c.MachineConfig.MachineVolumeGroups {
+		if err := vg.Validate(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	paths := map[string]struct{}{}
+
+	for _, quota := range c.MachineConfig.MachineEphemeralQuotas {
+		if err := quota.Validate(); err != nil {
+			result = multierror.Append(result, err)
+
+			continue
+		}
+
+		if _, ok := paths[filepath.Clean(quota.QuotaPath)]; ok {
+			result = multierror.Append(result, fmt.Errorf("duplicate ephemeral quota for %q", quota.QuotaPath))
+		}
+
+		paths[filepath.Clean(quota.QuotaPath)] = struct{}{}
+	}
+
+	if c.MachineConfig.MachineLogging != nil {
+		if err := c.MachineConfig.MachineLogging.Validate(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	if err := c.MachineConfig.MachineRegistries.Validate(); err != nil {
+		result = multierror.Append(result, err)
+	}
+
+	if c.MachineConfig.MachineKubelet != nil && c.MachineConfig.MachineKubelet.KubeletDrainTimeout < 0 {
+		result = multierror.Append(result, fmt.Errorf("kubelet drain timeout can't be negative"))
+	}
+
+	if c.MachineConfig.MachineKubelet != nil && c.MachineConfig.MachineKubelet.KubeletNodeIP != nil {
+		for _, subnet := range c.MachineConfig.MachineKubelet.KubeletNodeIP.KubeletNodeIPValidSubnets {
+			if _, _, err := net.ParseCIDR(strings.TrimPrefix(subnet, "!")); err != nil {
+				result = multierror.Append(result, fmt.Errorf("kubelet node IP subnet %q is invalid: %w", subnet, err))
+			}
+		}
+	}
+
+	if c.MachineConfig.MachineKubelet != nil && c.MachineConfig.MachineKubelet.KubeletServerCertRotation &&
+		(c.ClusterConfig == nil || !c.ClusterConfig.Discovery().Enabled()) {
+		result = multierror.Append(result, fmt.Errorf("kubelet server certificate rotation requires cluster discovery to be enabled"))
+	}
+
+	if c.MachineConfig.MachineKubelet != nil {
+		if err := validateReservedResources("systemReserved", c.MachineConfig.MachineKubelet.KubeletSystemReserved); err != nil {
+			result = multierror.Append(result, err)
+		}
+
+		if err := validateReservedResources("kubeReserved", c.MachineConfig.MachineKubelet.KubeletKubeReserved); err != nil {
+			result = multierror.Append(result, err)
+		}
+
+		if err := c.MachineConfig.MachineKubelet.validateResourceManagers(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	if c.MachineConfig.MachineKubelet != nil && c.MachineConfig.MachineKubelet.KubeletCredentialProviderConfig != nil {
+		if err := c.MachineConfig.MachineKubelet.KubeletCredentialProviderConfig.Validate(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	if c.MachineConfig.MachineFeatures != nil {
+		if err := c.MachineConfig.MachineFeatures.Validate(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	if c.MachineConfig.MachineInstall != nil && c.MachineConfig.MachineInstall.InstallConsole != nil {
+		if err := c.MachineConfig.MachineInstall.InstallConsole.Validate(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	if c.MachineConfig.MachineBMC != nil {
+		if err := c.MachineConfig.MachineBMC.Validate(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	if c.MachineConfig.MachineSystemDiskEncryption != nil {
+		if err := c.MachineConfig.MachineSystemDiskEncryption.Validate(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	if c.MachineConfig.MachineKernel != nil {
+		if err := c.MachineConfig.MachineKernel.Validate(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	if c.MachineConfig.MachineCRI != nil {
+		if err := c.MachineConfig.MachineCRI.Validate(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	for _, file := range c.MachineConfig.MachineFiles {
+		if err := validateMachineFilePath(file.FilePath); err != nil {
+			result = multierror.Append(result, err)
+		}
+
+		if file.FileContentFrom == nil {
+			continue
+		}
+
+		if file.FileContent != "" {
+			result = multierror.Append(result, fmt.Errorf("file %q: content and contentFrom are mutually exclusive", file.FilePath))
+		}
+
+		if err := file.FileContentFrom.Validate(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("file %q: %w", file.FilePath, err))
+		}
+	}
+
+	if err := validateExtensionServices(c.MachineConfig.MachineExtensionServices); err != nil {
+		result = multierror.Append(result, err)
+	}
+
+	if err := validateServiceResources(c.MachineConfig.MachineServiceResources); err != nil {
+		result = multierror.Append(result, err)
+	}
+
+	if c.MachineConfig.MachineProxy != nil {
+		if err := c.MachineConfig.MachineProxy.Validate(); err != nil {
+			result = multierror.Append(result, err)
+		}
+
+		for key := range c.MachineConfig.MachineEnv {
+			switch strings.ToLower(key) {
+			case "http_proxy", "https_proxy", "no_proxy":
+				result = multierror.Append(result, fmt.Errorf("environment variable %q conflicts with the machine proxy settings", key))
 			}
 		}
 	}
 
+	if c.ClusterConfig.ClusterJoinThrottle != nil {
+		if err := c.ClusterConfig.ClusterJoinThrottle.Validate(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	if c.ClusterConfig.ClusterDiscoveryConfig != nil {
+		if err := c.ClusterConfig.ClusterDiscoveryConfig.Validate(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
 	if !valid.IsDNSName(c.ClusterConfig.ClusterNetwork.DNSDomain) {
 		result = multierror.Append(result, fmt.Errorf("%q is not a valid DNS name", c.ClusterConfig.ClusterNetwork.DNSDomain))
 	}
@@ -138,9 +350,1022 @@ func (c *ClusterConfig) Validate() error {
 		result = multierror.Append(result, fmt.Errorf("invalid controlplane endpoint: %w", err))
 	}
 
+	if (c.ClusterAggregatorCA == nil) != (c.ClusterServiceAccount == nil) {
+		result = multierror.Append(result, errors.New("aggregatorCA and serviceAccount should be either both set or both omitted"))
+	}
+
+	if c.APIServerConfig != nil {
+		if err := c.APIServerConfig.validateServiceAccount(c.ControlPlane.Endpoint.URL); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	if ecp := c.ExternalCloudProviderConfig; ecp != nil && !ecp.ExternalEnabled && len(ecp.ExternalManifests) > 0 {
+		result = multierror.Append(result, errors.New("external cloud provider is disabled, but manifests are provided"))
+	}
+
+	if c.EtcdConfig != nil {
+		for _, subnet := range append(append([]string(nil), c.EtcdConfig.EtcdAdvertisedSubnets...), c.EtcdConfig.EtcdListenSubnets...) {
+			if _, _, err := net.ParseCIDR(strings.TrimPrefix(subnet, "!")); err != nil {
+				result = multierror.Append(result, fmt.Errorf("etcd subnet %q is invalid: %w", subnet, err))
+			}
+		}
+
+		if m := c.EtcdConfig.EtcdMaintenance; m != nil {
+			if m.MaintenanceInterval < 0 {
+				result = multierror.Append(result, errors.New("etcd maintenance interval can't be negative"))
+			}
+
+			if m.MaintenanceFragmentationThreshold < 0 || m.MaintenanceFragmentationThreshold > 100 {
+				result = multierror.Append(result, fmt.Errorf("etcd maintenance fragmentation threshold should be a percentage, got %d", m.MaintenanceFragmentationThreshold))
+			}
+		}
+	}
+
+	if c.SchedulerConfig != nil {
+		if kind, ok := c.SchedulerConfig.SchedulerConfigConfig["kind"]; ok && kind != "KubeSchedulerConfiguration" {
+			result = multierror.Append(result, fmt.Errorf("scheduler config kind should be KubeSchedulerConfiguration, got %v", kind))
+		}
+	}
+
+	if c.ControllerManagerConfig != nil {
+		for name := range c.ControllerManagerConfig.ExtraConfigConfig {
+			if name == "" || name == "." || name == ".." || filepath.Base(name) != name {
+				result = multierror.Append(result, fmt.Errorf("controller manager extra config file name %q should be a plain file name", name))
+			}
+		}
+	}
+
+	if c.CoreDNSConfig != nil {
+		if err := c.CoreDNSConfig.validate(c.Network().ServiceCIDR()); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	if len(c.ExtraManifestChecksums) > 0 {
+		manifests := map[string]struct{}{}
+
+		for _, manifest := range c.ExtraManifests {
+			manifests[manifest] = struct{}{}
+		}
+
+		keys := make([]string, 0, len(c.ExtraManifestChecksums))
+
+		for manifest := range c.ExtraManifestChecksums {
+			keys = append(keys, manifest)
+		}
+
+		sort.Strings(keys)
+
+		for _, manifest := range keys {
+			if _, ok := manifests[manifest]; !ok {
+				result = multierror.Append(result, fmt.Errorf("extra manifest checksum is set for %q, which is not in the extra manifests", manifest))
+			}
+
+			if checksum := c.ExtraManifestChecksums[manifest]; !sha256Regexp.MatchString(strings.ToLower(checksum)) {
+				result = multierror.Append(result, fmt.Errorf("extra manifest checksum %q for %q should be a hex-encoded SHA-256", checksum, manifest))
+			}
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+func validateFlannel(flannel config.FlannelCNI) error {
+	var result *multierror.Error
+
+	switch flannel.Backend() {
+	case constants.FlannelBackendVXLAN, constants.FlannelBackendHostGW, constants.FlannelBackendWireguard:
+	default:
+		result = multierror.Append(result, fmt.Errorf("flannel backend should be one of [%s,%s,%s], got %q",
+			constants.FlannelBackendVXLAN, constants.FlannelBackendHostGW, constants.FlannelBackendWireguard, flannel.Backend()))
+	}
+
+	// the smallest MTU allowed for IPv6
+	if mtu := flannel.MTU(); mtu != 0 && (mtu < 1280 || mtu > 9000) {
+		result = multierror.Append(result, fmt.Errorf("flannel MTU should be in range [1280,9000], got %d", mtu))
+	}
+
+	for _, iface := range flannel.Interfaces() {
+		if iface == "" {
+			result = multierror.Append(result, errors.New("flannel interface can't be empty"))
+		}
+	}
+
+	for _, expr := range flannel.InterfaceRegexps() {
+		if _, err := regexp.Compile(expr); err != nil {
+			result = multierror.Append(result, fmt.Errorf("flannel interface regexp %q is invalid: %w", expr, err))
+		}
+	}
+
+	for arg := range flannel.ExtraArgs() {
+		switch arg {
+		case "iface", "iface-regex", "kube-subnet-mgr":
+			result = multierror.Append(result, fmt.Errorf("flannel extra arg %q is managed by Talos", arg))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+func (c *CoreDNS) validate(serviceCIDRs string) error {
+	var result *multierror.Error
+
+	if c.CoreDNSReplicas < 0 {
+		result = multierror.Append(result, fmt.Errorf("coredns replicas can't be negative: %d", c.CoreDNSReplicas))
+	}
+
+	if len(c.CoreDNSServiceIPs) > 0 {
+		cidrs := strings.Split(serviceCIDRs, ",")
+
+		if len(c.CoreDNSServiceIPs) != len(cidrs) {
+			result = multierror.Append(result, fmt.Errorf("coredns service IPs should be set for each of the %d service subnets, got %d", len(cidrs), len(c.CoreDNSServiceIPs)))
+		} else {
+			for i, serviceIP := range c.CoreDNSServiceIPs {
+				ip := net.ParseIP(serviceIP)
+				if ip == nil {
+					result = multierror.Append(result, fmt.Errorf("coredns service IP %q is invalid", serviceIP))
+
+					continue
+				}
+
+				_, cidr, err := net.ParseCIDR(cidrs[i])
+				if err != nil {
+					result = multierror.Append(result, fmt.Errorf("service subnet %q is invalid: %w", cidrs[i], err))
+
+					continue
+				}
+
+				if !cidr.Contains(ip) {
+					result = multierror.Append(result, fmt.Errorf("coredns service IP %q is not in the service subnet %q", serviceIP, cidrs[i]))
+				}
+			}
+		}
+	}
+
+	for _, zone := range c.CoreDNSForwardZones {
+		if !valid.IsDNSName(zone.ForwardZone) {
+			result = multierror.Append(result, fmt.Errorf("coredns forward zone %q is not a valid DNS name", zone.ForwardZone))
+		}
+
+		if len(zone.ForwardUpstreams) == 0 {
+			result = multierror.Append(result, fmt.Errorf("coredns forward zone %q should have at least one upstream", zone.ForwardZone))
+		}
+
+		for _, upstream := range zone.ForwardUpstreams {
+			host := upstream
+
+			if h, _, err := net.SplitHostPort(upstream); err == nil {
+				host = h
+			}
+
+			if net.ParseIP(host) == nil {
+				result = multierror.Append(result, fmt.Errorf("coredns forward zone %q upstream %q should be an IP address with an optional port", zone.ForwardZone, upstream))
+			}
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+func (a *APIServerConfig) validateServiceAccount(endpoint *url.URL) error {
+	var result *multierror.Error
+
+	if a.ServiceAccountIssuerConfig != "" {
+		issuer, err := url.Parse(a.ServiceAccountIssuerConfig)
+
+		switch {
+		case err != nil:
+			result = multierror.Append(result, fmt.Errorf("invalid service account issuer %q: %w", a.ServiceAccountIssuerConfig, err))
+		case issuer.Host == "":
+			result = multierror.Append(result, fmt.Errorf("service account issuer %q should be an absolute URL", a.ServiceAccountIssuerConfig))
+		case issuer.Scheme != endpoint.Scheme:
+			result = multierror.Append(result, fmt.Errorf("service account issuer %q scheme should match the control plane endpoint scheme %q", a.ServiceAccountIssuerConfig, endpoint.Scheme))
+		}
+	}
+
+	if a.ServiceAccountJWKSURIConfig != "" {
+		jwksURI, err := url.Parse(a.ServiceAccountJWKSURIConfig)
+
+		switch {
+		case err != nil:
+			result = multierror.Append(result, fmt.Errorf("invalid service account JWKS URI %q: %w", a.ServiceAccountJWKSURIConfig, err))
+		case jwksURI.Scheme != "https" || jwksURI.Host == "":
+			result = multierror.Append(result, fmt.Errorf("service account JWKS URI %q should be an absolute https URL", a.ServiceAccountJWKSURIConfig))
+		}
+	}
+
+	for _, audience := range a.ServiceAccountExtraAudiencesConfig {
+		if audience == "" {
+			result = multierror.Append(result, errors.New("service account audience can't be empty"))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// Validate validates the logging config.
+func (l *LoggingConfig) Validate() error {
+	var result *multierror.Error
+
+	console := l.Console()
+
+	switch console.Mode() {
+	case constants.ConsoleModeNone, constants.ConsoleModeCritical, constants.ConsoleModeEvents, constants.ConsoleModeFull:
+	default:
+		result = multierror.Append(result, fmt.Errorf("console mode should be one of [none,critical,events,full], got %q", console.Mode()))
+	}
+
+	if !strings.HasPrefix(console.Device(), "/dev/") {
+		result = multierror.Append(result, fmt.Errorf("console device should be a path under /dev, got %q", console.Device()))
+	}
+
+	if console.RateLimit() < 0 {
+		result = multierror.Append(result, fmt.Errorf("console rate limit can't be negative: %d", console.RateLimit()))
+	}
+
+	if l.LoggingPersistence != nil {
+		if l.LoggingPersistence.PersistenceMaxSize < 0 {
+			result = multierror.Append(result, fmt.Errorf("persistent log max size can't be negative: %d", l.LoggingPersistence.PersistenceMaxSize))
+		}
+
+		if l.LoggingPersistence.PersistenceMaxFiles < 0 {
+			result = multierror.Append(result, fmt.Errorf("persistent log max files can't be negative: %d", l.LoggingPersistence.PersistenceMaxFiles))
+		}
+	}
+
 	return result.ErrorOrNil()
 }
 
+// Validate validates the install console config.
+func (c *InstallConsoleConfig) Validate() error {
+	var result *multierror.Error
+
+	if c.ConsoleSerial != "" && !serialConsoleRegexp.MatchString(c.ConsoleSerial) {
+		result = multierror.Append(result, fmt.Errorf("serial console should be a serial device name like ttyS0, got %q", c.ConsoleSerial))
+	}
+
+	if c.ConsoleBaudRate < 0 {
+		result = multierror.Append(result, fmt.Errorf("serial console baud rate can't be negative"))
+	}
+
+	if c.ConsoleDisableVGA && c.ConsoleSerial == "" {
+		result = multierror.Append(result, errors.New("serial console is required if VGA console is disabled"))
+	}
+
+	return result.ErrorOrNil()
+}
+
+var serialConsoleRegexp = regexp.MustCompile(`^tty[A-Za-z]+[0-9]+$`)
+
+// Validate validates the BMC config.
+func (b *BMCConfig) Validate() error {
+	var result *multierror.Error
+
+	if b.BMCDHCP && b.BMCAddress != "" {
+		result = multierror.Append(result, errors.New("BMC dhcp and static address are mutually exclusive"))
+	}
+
+	if b.BMCAddress != "" {
+		if ip, _, err := net.ParseCIDR(b.BMCAddress); err != nil || ip.To4() == nil {
+			result = multierror.Append(result, fmt.Errorf("BMC address should be an IPv4 address in CIDR notation, got %q", b.BMCAddress))
+		}
+	}
+
+	if b.BMCGateway != "" {
+		if b.BMCAddress == "" {
+			result = multierror.Append(result, errors.New("BMC gateway requires a static address"))
+		}
+
+		if ip := net.ParseIP(b.BMCGateway); ip == nil || ip.To4() == nil {
+			result = multierror.Append(result, fmt.Errorf("BMC gateway should be an IPv4 address, got %q", b.BMCGateway))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// Validate validates the RAID config.
+func (r *RAIDConfig) Validate() error {
+	var result *multierror.Error
+
+	if r.RAIDName == "" || strings.Contains(r.RAIDName, "/") {
+		result = multierror.Append(result, fmt.Errorf("invalid RAID array name %q", r.RAIDName))
+	}
+
+	minDevices := map[string]int{
+		"raid0":  2,
+		"raid1":  2,
+		"raid5":  3,
+		"raid6":  4,
+		"raid10": 2,
+	}
+
+	min, ok := minDevices[r.RAIDLevel]
+	if !ok {
+		result = multierror.Append(result, fmt.Errorf("RAID array %q: unsupported level %q", r.RAIDName, r.RAIDLevel))
+	} else if len(r.RAIDDevices) < min {
+		result = multierror.Append(result, fmt.Errorf("RAID array %q: level %s requires at least %d devices", r.RAIDName, r.RAIDLevel, min))
+	}
+
+	return result.ErrorOrNil()
+}
+
+// Validate validates the volume group config.
+func (vg *VolumeGroupConfig) Validate() error {
+	var result *multierror.Error
+
+	if vg.VGName == "" || strings.Contains(vg.VGName, "/") {
+		result = multierror.Append(result, fmt.Errorf("invalid volume group name %q", vg.VGName))
+	}
+
+	if len(vg.VGPhysicalVolumes) == 0 {
+		result = multierror.Append(result, fmt.Errorf("volume group %q: at least one physical volume is required", vg.VGName))
+	}
+
+	for i, lv := range vg.VGLogicalVolumes {
+		if lv.LVName == "" || strings.Contains(lv.LVName, "/") {
+			result = multierror.Append(result, fmt.Errorf("volume group %q: invalid logical volume name %q", vg.VGName, lv.LVName))
+		}
+
+		if lv.LVSize == 0 && i != len(vg.VGLogicalVolumes)-1 {
+			result = multierror.Append(result, fmt.Errorf("logical volume %q is set to occupy the free space, but it's not the last logical volume in the list", lv.LVName))
+		}
+
+		if err := validateUserVolume(lv.Filesystem(), lv.MountPoint()); err != nil {
+			result = multierror.Append(result, fmt.Errorf("logical volume %q: %w", lv.LVName, err))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// Validate validates the kubelet credential provider config.
+func (c *KubeletCredentialProviderConfig) Validate() error {
+	var result *multierror.Error
+
+	names := map[string]struct{}{}
+
+	for _, provider := range c.CredentialProviders {
+		if provider.ProviderName == "" || strings.ContainsAny(provider.ProviderName, "/ \t\n") {
+			result = multierror.Append(result, fmt.Errorf("kubelet credential provider name %q should be the name of the plugin binary", provider.ProviderName))
+		}
+
+		if _, ok := names[provider.ProviderName]; ok {
+			result = multierror.Append(result, fmt.Errorf("duplicate kubelet credential provider %q", provider.ProviderName))
+		}
+
+		names[provider.ProviderName] = struct{}{}
+
+		if len(provider.ProviderMatchImages) == 0 {
+			result = multierror.Append(result, fmt.Errorf("kubelet credential provider %q: matchImages is required", provider.ProviderName))
+		}
+
+		if provider.ProviderDefaultCacheDuration < 0 {
+			result = multierror.Append(result, fmt.Errorf("kubelet credential provider %q: default cache duration can't be negative", provider.ProviderName))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// Validate validates the ephemeral quota config.
+func (q *EphemeralQuotaConfig) Validate() error {
+	var result *multierror.Error
+
+	path := filepath.Clean(q.QuotaPath)
+
+	if !filepath.IsAbs(q.QuotaPath) || path == constants.EphemeralMountPoint || !strings.HasPrefix(path, constants.EphemeralMountPoint+"/") {
+		result = multierror.Append(result, fmt.Errorf("ephemeral quota path %q should be a directory under %s", q.QuotaPath, constants.EphemeralMountPoint))
+	}
+
+	if strings.ContainsAny(q.QuotaPath, " \t\n") {
+		result = multierror.Append(result, fmt.Errorf("ephemeral quota path %q can't contain whitespace", q.QuotaPath))
+	}
+
+	if q.QuotaLimit == 0 {
+		result = multierror.Append(result, fmt.Errorf("ephemeral quota for %q: limit is required", q.QuotaPath))
+	}
+
+	return result.ErrorOrNil()
+}
+
+func validateUserVolume(filesystem, mountpoint string) error {
+	switch filesystem {
+	case "xfs", "ext4":
+		if mountpoint == "" {
+			return fmt.Errorf("mountpoint is required for %s filesystem", filesystem)
+		}
+	case "none":
+		if mountpoint != "" {
+			return errors.New("unformatted volume can't be mounted")
+		}
+	default:
+		return fmt.Errorf("unsupported filesystem %q", filesystem)
+	}
+
+	return nil
+}
+
+// partitionLabelLimits are the maximum label lengths (GPT partition names are limited to 36 characters).
+var partitionLabelLimits = map[string]int{
+	"xfs":  12,
+	"ext4": 16,
+	"none": 36,
+}
+
+// Validate validates the system disk encryption config.
+func (e *SystemDiskEncryptionConfig) Validate() error {
+	if e.EphemeralPartition == nil {
+		return nil
+	}
+
+	if err := e.EphemeralPartition.Validate(); err != nil {
+		return fmt.Errorf("%s partition encryption: %w", constants.EphemeralPartitionLabel, err)
+	}
+
+	return nil
+}
+
+// Validate validates the partition encryption config.
+func (e *EncryptionConfig) Validate() error {
+	var result *multierror.Error
+
+	if len(e.EncryptionKeys) == 0 {
+		result = multierror.Append(result, errors.New("at least one key is required"))
+	}
+
+	slots := map[int]struct{}{}
+
+	for _, key := range e.EncryptionKeys {
+		if key.KeySlot < 0 {
+			result = multierror.Append(result, fmt.Errorf("key slot %d is invalid", key.KeySlot))
+		}
+
+		if _, ok := slots[key.KeySlot]; ok {
+			result = multierror.Append(result, fmt.Errorf("duplicate key slot %d", key.KeySlot))
+		}
+
+		slots[key.KeySlot] = struct{}{}
+
+		if key.KeyTPM == nil {
+			result = multierror.Append(result, fmt.Errorf("key slot %d: key provider is required (tpm)", key.KeySlot))
+
+			continue
+		}
+
+		for _, pcr := range key.KeyTPM.TPMPCRs {
+			if pcr < 0 || pcr > 23 {
+				result = multierror.Append(result, fmt.Errorf("key slot %d: PCR %d is out of range 0-23", key.KeySlot, pcr))
+			}
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+func validatePartitionLabel(filesystem, label string) error {
+	if label == "" {
+		return nil
+	}
+
+	for _, reserved := range []string{
+		constants.EFIPartitionLabel,
+		constants.BIOSGrubPartitionLabel,
+		constants.BootPartitionLabel,
+		constants.LegacyBootPartitionLabel,
+		constants.MetaPartitionLabel,
+		constants.StatePartitionLabel,
+		constants.EphemeralPartitionLabel,
+		constants.ImageCachePartitionLabel,
+	} {
+		if strings.EqualFold(label, reserved) {
+			return fmt.Errorf("label %q is reserved", label)
+		}
+	}
+
+	if limit, ok := partitionLabelLimits[filesystem]; ok && len(label) > limit {
+		return fmt.Errorf("label %q is longer than %d characters", label, limit)
+	}
+
+	return nil
+}
+
+// Validate validates the kernel config.
+func (k *KernelConfig) Validate() error {
+	var result *multierror.Error
+
+	for _, module := range k.KernelModules {
+		if module == nil || module.ModuleName == "" {
+			result = multierror.Append(result, errors.New("kernel module name can't be empty"))
+
+			continue
+		}
+
+		if strings.Contains(module.ModuleName, "/") && !filepath.IsAbs(module.ModuleName) {
+			result = multierror.Append(result, fmt.Errorf("kernel module %q should be either a module name or an absolute path", module.ModuleName))
+		}
+	}
+
+	isolated, err := parseCPUList(k.KernelIsolatedCPUs)
+	if err != nil {
+		result = multierror.Append(result, fmt.Errorf("kernel isolated CPUs: %w", err))
+	}
+
+	nohzFull, err := parseCPUList(k.KernelNohzFullCPUs)
+	if err != nil {
+		result = multierror.Append(result, fmt.Errorf("kernel nohz_full CPUs: %w", err))
+	}
+
+	if _, ok := nohzFull[0]; ok {
+		result = multierror.Append(result, errors.New("kernel nohz_full CPUs can't include CPU 0"))
+	}
+
+	irqAffinity, err := parseCPUList(k.KernelIRQAffinityCPUs)
+	if err != nil {
+		result = multierror.Append(result, fmt.Errorf("kernel IRQ affinity CPUs: %w", err))
+	}
+
+	for cpu := range irqAffinity {
+		if _, ok := isolated[cpu]; ok {
+			result = multierror.Append(result, fmt.Errorf("kernel IRQ affinity CPU %d is isolated", cpu))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// maxCPUs is the maximum number of CPUs supported by the kernel.
+const maxCPUs = 8192
+
+// parseCPUList parses the kernel CPU list format, e.g. `0-3,8`.
+func parseCPUList(s string) (map[uint64]struct{}, error) {
+	cpus := map[uint64]struct{}{}
+
+	if s == "" {
+		return cpus, nil
+	}
+
+	for _, r := range strings.Split(s, ",") {
+		bounds := strings.SplitN(r, "-", 2)
+
+		start, err := strconv.ParseUint(bounds[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU list %q", s)
+		}
+
+		end := start
+
+		if len(bounds) == 2 {
+			if end, err = strconv.ParseUint(bounds[1], 10, 32); err != nil || end < start {
+				return nil, fmt.Errorf("invalid CPU list %q", s)
+			}
+		}
+
+		if end >= maxCPUs {
+			return nil, fmt.Errorf("CPU %d in the CPU list %q is out of range", end, s)
+		}
+
+		for cpu := start; cpu <= end; cpu++ {
+			cpus[cpu] = struct{}{}
+		}
+	}
+
+	return cpus, nil
+}
+
+// Validate validates the CRI config.
+func (c *CRIConfig) Validate() error {
+	var result *multierror.Error
+
+	for name, runtime := range c.CRIRuntimes {
+		if name == "" || name == constants.DefaultCRIRuntimeHandler {
+			result = multierror.Append(result, fmt.Errorf("CRI runtime handler name %q is reserved", name))
+		}
+
+		if runtime != nil && runtime.RuntimeBinaryName != "" && !filepath.IsAbs(runtime.RuntimeBinaryName) {
+			result = multierror.Append(result, fmt.Errorf("CRI runtime %q: binary name should be an absolute path, got %q", name, runtime.RuntimeBinaryName))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+var extensionServiceNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// validateExtensionServices validates the extension services.
+func validateExtensionServices(services []*ExtensionServiceConfig) error {
+	var result *multierror.Error
+
+	names := map[string]struct{}{}
+
+	for _, service := range services {
+		if service == nil {
+			continue
+		}
+
+		if !extensionServiceNameRegexp.MatchString(service.ServiceName) {
+			result = multierror.Append(result, fmt.Errorf("extension service name %q should consist of lowercase letters, digits and dashes", service.ServiceName))
+		}
+
+		if _, exists := names[service.ServiceName]; exists {
+			result = multierror.Append(result, fmt.Errorf("duplicate extension service %q", service.ServiceName))
+		}
+
+		names[service.ServiceName] = struct{}{}
+
+		if service.ServiceImage == "" {
+			result = multierror.Append(result, fmt.Errorf("extension service %q: image is required", service.ServiceName))
+		}
+
+		switch service.Restart() {
+		case constants.ExtensionServiceRestartAlways, constants.ExtensionServiceRestartUntilSuccess, constants.ExtensionServiceRestartNever:
+		default:
+			result = multierror.Append(result, fmt.Errorf("extension service %q: unknown restart policy %q", service.ServiceName, service.ServiceRestart))
+		}
+
+		for _, mount := range service.ServiceMounts {
+			if !filepath.IsAbs(mount.Destination) {
+				result = multierror.Append(result, fmt.Errorf("extension service %q: mount destination should be an absolute path, got %q", service.ServiceName, mount.Destination))
+			}
+		}
+
+		for _, capability := range service.Security().Capabilities() {
+			if !strings.HasPrefix(capability, "CAP_") {
+				result = multierror.Append(result, fmt.Errorf("extension service %q: capability should have CAP_ prefix, got %q", service.ServiceName, capability))
+			}
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+var serviceResourcesServices = map[string]struct{}{
+	"etcd":       {},
+	"kubelet":    {},
+	"apid":       {},
+	"containerd": {},
+	"cri":        {},
+}
+
+// validateServiceResources validates the resource limits of the system services.
+func validateServiceResources(resources map[string]*ServiceResourcesConfig) error {
+	var result *multierror.Error
+
+	services := make([]string, 0, len(resources))
+
+	for service := range resources {
+		services = append(services, service)
+	}
+
+	sort.Strings(services)
+
+	for _, service := range services {
+		r := resources[service]
+		if r == nil {
+			continue
+		}
+
+		if _, ok := serviceResourcesServices[service]; !ok {
+			result = multierror.Append(result, fmt.Errorf("service resources: unsupported service %q", service))
+		}
+
+		if r.ResourcesCPULimit < 0 {
+			result = multierror.Append(result, fmt.Errorf("service %q: CPU limit should be positive, got %v", service, r.ResourcesCPULimit))
+		}
+
+		if r.ResourcesMemoryLimit > 0 && r.ResourcesMemoryReservation > r.ResourcesMemoryLimit {
+			result = multierror.Append(result, fmt.Errorf("service %q: memory reservation %d is greater than memory limit %d", service, r.ResourcesMemoryReservation, r.ResourcesMemoryLimit))
+		}
+
+		if r.ResourcesOOMScoreAdj != nil && (*r.ResourcesOOMScoreAdj < -1000 || *r.ResourcesOOMScoreAdj > 1000) {
+			result = multierror.Append(result, fmt.Errorf("service %q: OOM score adjustment should be in range [-1000, 1000], got %d", service, *r.ResourcesOOMScoreAdj))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+var (
+	reservedResourceNames = map[string]struct{}{
+		"cpu":               {},
+		"memory":            {},
+		"ephemeral-storage": {},
+		"pid":               {},
+	}
+
+	quantityRegexp = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?(m|k|M|G|T|P|E|Ki|Mi|Gi|Ti|Pi|Ei)?$`)
+)
+
+// validateReservedResources validates the kubelet reserved resources.
+func validateReservedResources(field string, resources map[string]string) error {
+	var result *multierror.Error
+
+	names := make([]string, 0, len(resources))
+
+	for name := range resources {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := reservedResourceNames[name]; !ok {
+			result = multierror.Append(result, fmt.Errorf("kubelet %s: unsupported resource %q", field, name))
+		}
+
+		if !quantityRegexp.MatchString(resources[name]) {
+			result = multierror.Append(result, fmt.Errorf("kubelet %s: invalid quantity %q for resource %q", field, resources[name], name))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// validateResourceManagers validates the kubelet CPU, topology and memory manager settings.
+func (k *KubeletConfig) validateResourceManagers() error {
+	var result *multierror.Error
+
+	switch k.KubeletCPUManagerPolicy {
+	case "", "none", "static":
+	default:
+		result = multierror.Append(result, fmt.Errorf("kubelet CPU manager policy %q is not supported", k.KubeletCPUManagerPolicy))
+	}
+
+	if _, err := parseCPUList(k.KubeletReservedCPUs); err != nil {
+		result = multierror.Append(result, fmt.Errorf("kubelet reserved CPUs: %w", err))
+	}
+
+	switch k.KubeletTopologyManagerPolicy {
+	case "", "none", "best-effort", "restricted", "single-numa-node":
+	default:
+		result = multierror.Append(result, fmt.Errorf("kubelet topology manager policy %q is not supported", k.KubeletTopologyManagerPolicy))
+	}
+
+	switch k.KubeletTopologyManagerScope {
+	case "", "container", "pod":
+	default:
+		result = multierror.Append(result, fmt.Errorf("kubelet topology manager scope %q is not supported", k.KubeletTopologyManagerScope))
+	}
+
+	if k.KubeletMemoryManager == nil {
+		return result.ErrorOrNil()
+	}
+
+	switch k.KubeletMemoryManager.MemoryManagerPolicy {
+	case "None":
+		if len(k.KubeletMemoryManager.MemoryManagerReservedMemory) > 0 {
+			result = multierror.Append(result, errors.New("kubelet memory manager reserved memory requires the Static policy"))
+		}
+	case "Static":
+	default:
+		result = multierror.Append(result, fmt.Errorf("kubelet memory manager policy %q is not supported", k.KubeletMemoryManager.MemoryManagerPolicy))
+	}
+
+	nodes := map[uint32]struct{}{}
+
+	for _, reserved := range k.KubeletMemoryManager.MemoryManagerReservedMemory {
+		if reserved == nil {
+			continue
+		}
+
+		if _, exists := nodes[reserved.ReservedNUMANode]; exists {
+			result = multierror.Append(result, fmt.Errorf("kubelet memory manager: duplicate reserved memory for NUMA node %d", reserved.ReservedNUMANode))
+		}
+
+		nodes[reserved.ReservedNUMANode] = struct{}{}
+
+		if !quantityRegexp.MatchString(reserved.ReservedMemory) {
+			result = multierror.Append(result, fmt.Errorf("kubelet memory manager: invalid quantity %q for NUMA node %d", reserved.ReservedMemory, reserved.ReservedNUMANode))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+func validateMachineFilePath(p string) error {
+	if !filepath.IsAbs(p) || filepath.Clean(p) != p {
+		return fmt.Errorf("file %q: path should be absolute and clean", p)
+	}
+
+	for _, dir := range append([]string{"/var"}, constants.MachineFilesOverlayPaths...) {
+		if strings.HasPrefix(p, dir+"/") {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("file %q: path should be in /var or in one of %s", p, strings.Join(constants.MachineFilesOverlayPaths, ", "))
+}
+
+var sha256Regexp = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Validate validates the file content source.
+func (s *MachineFileContentSource) Validate() error {
+	var result *multierror.Error
+
+	if u, err := url.Parse(s.SourceURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		result = multierror.Append(result, fmt.Errorf("content source URL %q should be an http(s) URL", s.SourceURL))
+	}
+
+	if !sha256Regexp.MatchString(strings.ToLower(s.SourceSHA256)) {
+		result = multierror.Append(result, fmt.Errorf("content source checksum %q should be a hex-encoded SHA-256", s.SourceSHA256))
+	}
+
+	return result.ErrorOrNil()
+}
+
+// Validate validates the machine proxy config.
+func (p *MachineProxyConfig) Validate() error {
+	var result *multierror.Error
+
+	for _, proxy := range []string{p.ProxyHTTPProxy, p.ProxyHTTPSProxy} {
+		if proxy == "" {
+			continue
+		}
+
+		if u, err := url.Parse(proxy); err != nil || u.Host == "" {
+			result = multierror.Append(result, fmt.Errorf("proxy %q should be a URL, e.g. http://proxy.example.com:3128", proxy))
+		}
+	}
+
+	for _, entry := range p.ProxyNoProxy {
+		if entry == "" || strings.ContainsAny(entry, ", ") {
+			result = multierror.Append(result, fmt.Errorf("no proxy entry %q should be a single non-empty host, domain or subnet", entry))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// Validate validates the registries config.
+func (r *RegistriesConfig) Validate() error {
+	var result *multierror.Error
+
+	for host, cfg := range r.RegistryConfig {
+		if cfg == nil || cfg.RegistryAuth == nil || cfg.RegistryAuth.RegistryProvider == nil {
+			continue
+		}
+
+		auth := cfg.RegistryAuth
+
+		if auth.RegistryUsername != "" || auth.RegistryPassword != "" || auth.RegistryAuth != "" || auth.RegistryIdentityToken != "" {
+			result = multierror.Append(result, fmt.Errorf("registry %q: static credentials can't be used with the credential provider", host))
+		}
+
+		if !filepath.IsAbs(auth.RegistryProvider.ProviderExec) {
+			result = multierror.Append(result, fmt.Errorf("registry %q: credential provider exec should be an absolute path, got %q", host, auth.RegistryProvider.ProviderExec))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// Validate validates the features config.
+func (f *FeaturesConfig) Validate() error {
+	var result *multierror.Error
+
+	for i, rule := range f.RBAC().Rules() {
+		if len(rule.Roles()) == 0 {
+			result = multierror.Append(result, fmt.Errorf("rbac rule %d: at least one role is required", i))
+		}
+
+		if len(rule.Methods()) == 0 {
+			result = multierror.Append(result, fmt.Errorf("rbac rule %d: at least one method is required", i))
+		}
+
+		for _, method := range rule.Methods() {
+			if !strings.HasPrefix(method, "/") {
+				result = multierror.Append(result, fmt.Errorf("rbac rule %d: method should be a full gRPC method name, got %q", i, method))
+			}
+		}
+
+		for _, node := range rule.Nodes() {
+			if strings.Contains(node, "/") {
+				if _, _, err := net.ParseCIDR(node); err != nil {
+					result = multierror.Append(result, fmt.Errorf("rbac rule %d: invalid node CIDR %q: %w", i, node, err))
+				}
+			}
+		}
+	}
+
+	for i, rule := range f.APIAuthorizationPolicy().Rules() {
+		if len(rule.Methods()) == 0 {
+			result = multierror.Append(result, fmt.Errorf("api authorization policy rule %d: at least one method is required", i))
+		}
+
+		for _, method := range rule.Methods() {
+			if !strings.HasPrefix(method, "/") {
+				result = multierror.Append(result, fmt.Errorf("api authorization policy rule %d: method should be a full gRPC method name, got %q", i, method))
+			}
+		}
+	}
+
+	if f.LocalAPIAccess().GroupID() < 0 {
+		result = multierror.Append(result, fmt.Errorf("local API access group ID should be non-negative, got %d", f.LocalAPIAccess().GroupID()))
+	}
+
+	if access := f.KubernetesTalosAPIAccess(); access.Enabled() {
+		if len(access.AllowedRoles()) == 0 {
+			result = multierror.Append(result, errors.New("kubernetes Talos API access requires at least one allowed role"))
+		}
+
+		if len(access.AllowedKubernetesNamespaces()) == 0 {
+			result = multierror.Append(result, errors.New("kubernetes Talos API access requires at least one allowed namespace"))
+		}
+
+		for _, role := range access.AllowedRoles() {
+			if role == constants.RoleImpersonator {
+				result = multierror.Append(result, fmt.Errorf("kubernetes Talos API access can't grant %q role", role))
+			}
+		}
+	}
+
+	for i, key := range f.ImageVerification().PublicKeys() {
+		if block, _ := pem.Decode([]byte(key)); block == nil || block.Type != "PUBLIC KEY" {
+			result = multierror.Append(result, fmt.Errorf("image verification public key %d is not a PEM-encoded public key", i))
+		}
+	}
+
+	switch storage := f.ConfigStorage(); storage.Backend() {
+	case constants.ConfigStorageBackendState, constants.ConfigStorageBackendMeta:
+		if storage.URL() != "" {
+			result = multierror.Append(result, fmt.Errorf("config storage url is only supported for %q backend", constants.ConfigStorageBackendRemote))
+		}
+	case constants.ConfigStorageBackendRemote:
+		if u, err := url.Parse(storage.URL()); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			result = multierror.Append(result, fmt.Errorf("config storage %q backend requires http(s) url, got %q", constants.ConfigStorageBackendRemote, storage.URL()))
+		}
+	default:
+		result = multierror.Append(result, fmt.Errorf("unknown config storage backend %q", storage.Backend()))
+	}
+
+	if apiURL := f.ManagementTunnel().APIURL(); apiURL != "" {
+		if u, err := url.Parse(apiURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			result = multierror.Append(result, fmt.Errorf("management tunnel requires http(s) api url, got %q", apiURL))
+		}
+	}
+
+	if name := f.ISCSI().InitiatorName(); name != "" {
+		if !strings.HasPrefix(name, "iqn.") && !strings.HasPrefix(name, "eui.") && !strings.HasPrefix(name, "naa.") {
+			result = multierror.Append(result, fmt.Errorf("iSCSI initiator name should be in iqn, eui or naa format, got %q", name))
+		}
+
+		if strings.ContainsAny(name, " \t\n") {
+			result = multierror.Append(result, fmt.Errorf("iSCSI initiator name can't contain whitespace, got %q", name))
+		}
+	}
+
+	switch version := f.NFSMounts().DefaultVersion(); version {
+	case "", "3", "4", "4.0", "4.1", "4.2":
+	default:
+		result = multierror.Append(result, fmt.Errorf("unsupported NFS version %q", version))
+	}
+
+	if domain := f.NFSMounts().IdmapdDomain(); domain != "" && !valid.IsDNSName(domain) {
+		result = multierror.Append(result, fmt.Errorf("NFS idmapd domain should be a valid DNS name, got %q", domain))
+	}
+
+	return result.ErrorOrNil()
+}
+
+// Validate validates the join throttle config.
+func (j *JoinThrottleConfig) Validate() error {
+	var result *multierror.Error
+
+	if j.JoinMaxConcurrentRequests < 0 || j.JoinMaxQueuedRequests < 0 {
+		result = multierror.Append(result, fmt.Errorf("join throttle request limits can't be negative"))
+	}
+
+	if j.JoinRetryInterval < 0 || j.JoinMaxRetryInterval < 0 || j.JoinRetryJitter < 0 || j.JoinRetryTimeout < 0 {
+		result = multierror.Append(result, fmt.Errorf("join throttle intervals can't be negative"))
+	}
+
+	if j.RetryInterval() > j.MaxRetryInterval() {
+		result = multierror.Append(result, fmt.Errorf("join throttle retry interval %s is greater than max retry interval %s", j.RetryInterval(), j.MaxRetryInterval()))
+	}
+
+	return result.ErrorOrNil()
+}
+
+// Validate validates the cluster discovery config.
+func (d *ClusterDiscoveryConfig) Validate() error {
+	endpoint := d.DiscoveryRegistries.RegistryService.RegistryEndpoint
+	if endpoint == "" {
+		return nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid discovery service endpoint %q: %w", endpoint, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("discovery service endpoint %q should use http or https scheme", endpoint)
+	}
+
+	return nil
+}
+
 // ValidateNetworkDevices runs the specified validation checks specific to the
 // network devices.
 //nolint: dupl
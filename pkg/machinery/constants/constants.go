@@ -20,6 +20,35 @@ const (
 	// to the config.
 	KernelParamConfig = "talos.config"
 
+	// KernelParamManagementTunnel is the kernel parameter name for specifying the URL
+	// of the management tunnel provisioning server API.
+	KernelParamManagementTunnel = "talos.managementtunnel.api"
+
+	// ConfigLoadTimeout is the timeout for a single attempt to fetch the config.
+	ConfigLoadTimeout = 70 * time.Second
+
+	// ConfigLoadAttempts is the number of attempts to fetch the config before giving up.
+	ConfigLoadAttempts = 5
+
+	// ConfigLoadRetryInterval is the interval between the attempts to fetch the config.
+	ConfigLoadRetryInterval = 10 * time.Second
+
+	// KernelParamConfigHeader is the kernel parameter name for specifying the HTTP headers
+	// (as Name:Value) to send when downloading the config.
+	KernelParamConfigHeader = "talos.config.header"
+
+	// KernelParamConfigCA is the kernel parameter name for specifying the base64-encoded
+	// CA certificate to verify the config server certificate.
+	KernelParamConfigCA = "talos.config.ca"
+
+	// KernelParamConfigCert is the kernel parameter name for specifying the base64-encoded
+	// client certificate to use when downloading the config.
+	KernelParamConfigCert = "talos.config.cert"
+
+	// KernelParamConfigKey is the kernel parameter name for specifying the base64-encoded
+	// client certificate key to use when downloading the config.
+	KernelParamConfigKey = "talos.config.key"
+
 	// ConfigNone indicates no config is required.
 	ConfigNone = "none"
 
@@ -46,6 +75,35 @@ const (
 	// BoardRock64 is the  name of the Pine64 Rock64.
 	BoardRock64 = "rock64"
 
+	// KernelParamMaintenanceToken is the kernel parameter name for specifying the one-time token
+	// required to access the maintenance service.
+	KernelParamMaintenanceToken = "talos.maintenance.token"
+
+	// MaintenanceTokenMetadataKey is the gRPC metadata key for the maintenance service token.
+	MaintenanceTokenMetadataKey = "talos-maintenance-token"
+
+	// RoleAdmin is the Talos API role which grants access to the secrets (e.g. in the machine configuration).
+	RoleAdmin = "os:admin"
+
+	// RoleImpersonator is the Talos API role of the node certificates,
+	// it allows apid to pass the roles of the client along with the proxied requests.
+	RoleImpersonator = "os:impersonator"
+
+	// RoleLocal is the Talos API role of the clients connected to the local API socket.
+	RoleLocal = "os:local"
+
+	// LocalAPISubject is the Talos API subject of the clients connected to the local API socket.
+	LocalAPISubject = "local"
+
+	// APIAuthzRoleMetadataKey is the gRPC metadata key which carries the roles of the client.
+	APIAuthzRoleMetadataKey = "talos-role"
+
+	// APIAuthzSubjectMetadataKey is the gRPC metadata key which carries the client certificate subject common name.
+	APIAuthzSubjectMetadataKey = "talos-subject"
+
+	// APIAuthzFingerprintMetadataKey is the gRPC metadata key which carries the client certificate SPKI fingerprint.
+	APIAuthzFingerprintMetadataKey = "talos-fingerprint"
+
 	// KernelParamHostname is the kernel parameter name for specifying the
 	// hostname.
 	KernelParamHostname = "talos.hostname"
@@ -112,6 +170,85 @@ const (
 	// the data path.
 	EphemeralMountPoint = "/var"
 
+	// EphemeralMapperName is the device-mapper name of the encrypted EPHEMERAL partition.
+	EphemeralMapperName = "ephemeral"
+
+	// DefaultEncryptionPCR is the TPM PCR the encryption keys are sealed to by default (SecureBoot state).
+	DefaultEncryptionPCR = 7
+
+	// ImageCachePartitionLabel is the label of the optional partition with
+	// the container images baked into the disk image.
+	ImageCachePartitionLabel = "IMAGECACHE"
+
+	// ImageCacheMountPoint is the path to mount the image cache partition at.
+	ImageCacheMountPoint = "/system/imagecache"
+
+	// ImageCacheLabel is the containerd image label set on the images imported from the image cache.
+	ImageCacheLabel = "talos.dev/image-cache"
+
+	// ExtensionServiceRestartAlways restarts the extension service whenever it exits.
+	ExtensionServiceRestartAlways = "always"
+
+	// ExtensionServiceRestartUntilSuccess restarts the extension service until it exits successfully.
+	ExtensionServiceRestartUntilSuccess = "untilSuccess"
+
+	// ExtensionServiceRestartNever runs the extension service once.
+	ExtensionServiceRestartNever = "never"
+
+	// ExtensionServicePrefix is the prefix of the extension service IDs, it separates them from the Talos services.
+	ExtensionServicePrefix = "ext-"
+
+	// SystemServicesCgroup is the parent cgroup of the system services, a sibling of the Kubernetes pods cgroup.
+	SystemServicesCgroup = "/system"
+
+	// EtcdDefaultCPUShares is the default CPU weight of etcd, twice the default weight of the other processes.
+	EtcdDefaultCPUShares = 2048
+
+	// EtcdDefaultOOMScoreAdj keeps etcd above the guaranteed pods (-997) in the OOM killer order.
+	EtcdDefaultOOMScoreAdj = -998
+
+	// KubeletDefaultOOMScoreAdj matches the OOM score adjustment the kubelet sets for itself.
+	KubeletDefaultOOMScoreAdj = -999
+
+	// ContainerdDefaultOOMScoreAdj is the default OOM score adjustment of containerd and CRI containerd.
+	ContainerdDefaultOOMScoreAdj = -999
+
+	// APIDDefaultOOMScoreAdj is the default OOM score adjustment of apid, so that the node stays manageable.
+	APIDDefaultOOMScoreAdj = -998
+
+	// ConfigStorageBackendState stores the machine configuration on the STATE partition.
+	ConfigStorageBackendState = "state"
+
+	// ConfigStorageBackendMeta stores the machine configuration in the META partition.
+	ConfigStorageBackendMeta = "meta"
+
+	// ConfigStorageBackendRemote fetches the machine configuration from the remote service.
+	ConfigStorageBackendRemote = "remote"
+
+	// ConfigStorageRemoteTimeout is the time to retry fetching the config from the remote service before falling back to the local copy.
+	ConfigStorageRemoteTimeout = 30 * time.Second
+
+	// ManagementTunnelInterface is the name of the management tunnel WireGuard interface.
+	ManagementTunnelInterface = "mgmt0"
+
+	// ManagementTunnelProvisionTimeout is the time to retry management tunnel provisioning before continuing boot without the tunnel.
+	ManagementTunnelProvisionTimeout = 2 * time.Minute
+
+	// ManagementTunnelKeepaliveInterval is the WireGuard persistent keepalive interval which keeps NAT mappings open.
+	ManagementTunnelKeepaliveInterval = 25 * time.Second
+
+	// DiscoveryRefreshInterval is the interval between node registration refreshes in the cluster discovery registries.
+	DiscoveryRefreshInterval = time.Minute
+
+	// DiscoveryRequestTimeout is the timeout for a single cluster discovery registry request.
+	DiscoveryRequestTimeout = 15 * time.Second
+
+	// RegistryCredentialProviderTimeout is the maximum time to wait for the registry credential provider to return credentials.
+	RegistryCredentialProviderTimeout = time.Minute
+
+	// RegistryCredentialProviderDefaultCacheDuration is the time to cache registry credentials for if the provider doesn't specify it.
+	RegistryCredentialProviderDefaultCacheDuration = 5 * time.Minute
+
 	// RootMountPoint is the label of the partition to use for mounting at
 	// the root path.
 	RootMountPoint = "/"
@@ -217,21 +354,83 @@ const (
 	// RecoveryKubeconfig is the path to kubeconfig used temporarily while recovering control plane.
 	RecoveryKubeconfig = "/etc/kubernetes/kubeconfig"
 
+	// DefaultDrainTimeout is the default maximum time to wait for the node to be drained before a disruptive action.
+	DefaultDrainTimeout = 5 * time.Minute
+
 	// LabelNodeRoleMaster is the node label required by a control plane node.
 	LabelNodeRoleMaster = "node-role.kubernetes.io/master"
 
 	// LabelNodeRoleControlPlane is the node label required by a control plane node.
 	LabelNodeRoleControlPlane = "node-role.kubernetes.io/control-plane"
 
+	// AnnotationNodeID is the node annotation which holds Talos node identity.
+	AnnotationNodeID = "cluster.talos.dev/node-id"
+
+	// AnnotationNodeMachineType is the node annotation which holds Talos machine type.
+	AnnotationNodeMachineType = "cluster.talos.dev/machine-type"
+
+	// AnnotationNodeAddresses is the node annotation which holds the list of node addresses.
+	AnnotationNodeAddresses = "cluster.talos.dev/addresses"
+
 	// AssetsDirectory is the directory that contains all bootstrap assets.
 	AssetsDirectory = "/etc/kubernetes/assets"
 
 	// ManifestsDirectory is the directory that contains all static manifests.
 	ManifestsDirectory = "/etc/kubernetes/manifests"
 
+	// TalosManifestsDirectory is the directory the Talos-owned bootstrap manifests are handed over to the manifest sync service in.
+	TalosManifestsDirectory = "/etc/kubernetes/talos-manifests"
+
+	// ManifestSyncInterval is the interval between the reconciliations of the Talos-owned bootstrap manifests.
+	ManifestSyncInterval = time.Minute
+
+	// ManifestSyncClientTTL is the interval to re-create the Kubernetes client used to sync the Talos-owned bootstrap manifests.
+	ManifestSyncClientTTL = 5 * time.Minute
+
+	// ManifestSyncFieldManager is the field manager of the server-side apply of the Talos-owned bootstrap manifests.
+	ManifestSyncFieldManager = "talos"
+
+	// KubernetesControlPlaneSecretsDir is the directory with the secrets of the control plane static pods,
+	// each component gets a subdirectory.
+	KubernetesControlPlaneSecretsDir = "/etc/kubernetes/secrets"
+
+	// KubernetesControlPlaneRunUser is the user ID the control plane static pods run as.
+	KubernetesControlPlaneRunUser = 65534
+
+	// KubernetesControlPlaneCertLifetime is the lifetime of the certificates issued for the control plane static pods,
+	// certificates are re-issued on each boot.
+	KubernetesControlPlaneCertLifetime = 365 * 24 * time.Hour
+
+	// KubernetesControlPlaneCertRenewBefore is the time before the expiration when the control plane certificates are re-issued.
+	KubernetesControlPlaneCertRenewBefore = 90 * 24 * time.Hour
+
+	// KubernetesControlPlaneCertCheckInterval is the interval between the checks of the control plane certificates expiration.
+	KubernetesControlPlaneCertCheckInterval = time.Hour
+
+	// KubernetesControlPlaneRotationTimeout is the timeout to wait for the control plane static pods to be restarted with the new certificates.
+	KubernetesControlPlaneRotationTimeout = 10 * time.Minute
+
+	// KubeletCSRApproverInterval is the interval between checks for the pending kubelet serving certificate signing requests.
+	KubeletCSRApproverInterval = 30 * time.Second
+
+	// KubeletCSRApproverClientTTL is the interval to re-create the Kubernetes client used to approve the kubelet serving certificates.
+	KubeletCSRApproverClientTTL = 5 * time.Minute
+
 	// KubeletKubeconfig is the generated kubeconfig for kubelet.
 	KubeletKubeconfig = "/etc/kubernetes/kubeconfig-kubelet"
 
+	// KubeletCredentialProviderConfig is the generated CredentialProviderConfig for kubelet.
+	KubeletCredentialProviderConfig = "/etc/kubernetes/credential-provider.yaml"
+
+	// KubeletCredentialProviderBinDir is the directory of the kubelet credential provider plugin binaries.
+	KubeletCredentialProviderBinDir = "/usr/libexec/kubernetes/kubelet-plugins/credential-provider/exec"
+
+	// DefaultKubeletCredentialProviderCacheDuration is the default duration the credentials are cached for.
+	DefaultKubeletCredentialProviderCacheDuration = time.Minute
+
+	// DefaultKubeletCredentialProviderAPIVersion is the default version of the request passed to the credential provider plugin.
+	DefaultKubeletCredentialProviderAPIVersion = "credentialprovider.kubelet.k8s.io/v1alpha1"
+
 	// DefaultEtcdVersion is the default target version of etcd.
 	DefaultEtcdVersion = "v3.4.14"
 
@@ -241,6 +440,12 @@ const (
 	// EtcdTalosEtcdUpgradeMutex is the etcd mutex prefix to be used to set an etcd upgrade lock.
 	EtcdTalosEtcdUpgradeMutex = EtcdRootTalosKey + ":etcdUpgradeMutex"
 
+	// EtcdTalosControlPlaneCertsMutex is the etcd mutex prefix used to rotate the control plane certificates one node at a time.
+	EtcdTalosControlPlaneCertsMutex = EtcdRootTalosKey + ":controlPlaneCertsMutex"
+
+	// EtcdTalosManifestsKey is the etcd key prefix of the Talos-owned bootstrap manifests.
+	EtcdTalosManifestsKey = EtcdRootTalosKey + ":manifests"
+
 	// EtcdImage is the reposistory for the etcd image.
 	EtcdImage = "gcr.io/etcd-development/etcd"
 
@@ -250,9 +455,38 @@ const (
 	// EtcdDataPath is the path where etcd stores its' data.
 	EtcdDataPath = "/var/lib/etcd"
 
+	// EtcdMaintenanceDefaultInterval is the default interval between etcd maintenance runs.
+	EtcdMaintenanceDefaultInterval = time.Hour
+
+	// EtcdMaintenanceDefaultMinDBSize is the default database size below which etcd members are not defragmented.
+	EtcdMaintenanceDefaultMinDBSize = 100 * 1000 * 1000
+
+	// EtcdMaintenanceDefaultFragmentationThreshold is the default percentage of the free database space which triggers the defragmentation.
+	EtcdMaintenanceDefaultFragmentationThreshold = 50
+
+	// EtcdMaintenanceDefragmentTimeout is the timeout to defragment a single etcd member.
+	EtcdMaintenanceDefragmentTimeout = 5 * time.Minute
+
 	// ConfigPath is the path to the downloaded config.
 	ConfigPath = StateMountPoint + "/config.yaml"
 
+	// ConfigHistoryPath is the path to the directory with the previous versions of the machine configuration.
+	ConfigHistoryPath = StateMountPoint + "/config-history"
+
+	// ConfigHistoryMaxVersions is the number of the machine configuration versions kept in the history.
+	ConfigHistoryMaxVersions = 10
+
+	// AuditLogPath is the path to the log of the mutating Talos API calls.
+	AuditLogPath = StateMountPoint + "/audit.log"
+
+	// AuditLogMaxSize is the size of the audit log which triggers the rotation.
+	//
+	// Single rotated log is kept as AuditLogPath with the `.1` suffix.
+	AuditLogMaxSize = 1024 * 1024
+
+	// NodeIdentityPath is the path to the file which holds the node identity used for cluster discovery.
+	NodeIdentityPath = StateMountPoint + "/node-identity"
+
 	// MetalConfigISOLabel is the volume label for ISO based configuration.
 	MetalConfigISOLabel = "metal-iso"
 
@@ -277,9 +511,52 @@ const (
 	// ApidPort is the port for the apid service.
 	ApidPort = 50000
 
+	// ApidBackendKeepaliveTime is the interval of keepalive pings on the connections to other apid instances.
+	ApidBackendKeepaliveTime = 30 * time.Second
+
+	// ApidBackendKeepaliveTimeout is the time to wait for the keepalive ping response before closing the connection.
+	ApidBackendKeepaliveTimeout = 10 * time.Second
+
+	// ApidBackendIdleTimeout is the time after which unused connections to other apid instances are closed.
+	ApidBackendIdleTimeout = 10 * time.Minute
+
 	// TrustdPort is the port for the trustd service.
 	TrustdPort = 50001
 
+	// TrustdMetricsAddress is the address trustd serves Prometheus metrics on.
+	TrustdMetricsAddress = "127.0.0.1:50003"
+
+	// ServiceAccountTokenAudience is the audience of the Kubernetes service account tokens accepted by trustd.
+	ServiceAccountTokenAudience = "talos"
+
+	// ServiceAccountCertificateValidityDuration is the validity of the Talos API client certificates
+	// issued by trustd in exchange for the Kubernetes service account tokens.
+	ServiceAccountCertificateValidityDuration = time.Hour
+
+	// KubeletPort is the port of the kubelet API.
+	KubeletPort = 10250
+
+	// KubeletPKIDir is the directory where kubelet stores its certificates.
+	KubeletPKIDir = "/var/lib/kubelet/pki"
+
+	// TrustdDefaultMaxConcurrentRequests is the default number of certificate requests trustd processes concurrently.
+	TrustdDefaultMaxConcurrentRequests = 10
+
+	// TrustdDefaultMaxQueuedRequests is the default number of certificate requests waiting to be processed by trustd.
+	TrustdDefaultMaxQueuedRequests = 100
+
+	// TrustdDefaultRetryInterval is the default initial interval between certificate request attempts.
+	TrustdDefaultRetryInterval = time.Second
+
+	// TrustdDefaultMaxRetryInterval is the default maximum interval between certificate request attempts.
+	TrustdDefaultMaxRetryInterval = 30 * time.Second
+
+	// TrustdDefaultRetryJitter is the default maximum random delay added to certificate request attempts.
+	TrustdDefaultRetryJitter = 5 * time.Second
+
+	// TrustdDefaultRetryTimeout is the default timeout to get the certificate from trustd.
+	TrustdDefaultRetryTimeout = 10 * time.Minute
+
 	// DefaultContainerdVersion is the default container runtime version.
 	DefaultContainerdVersion = "1.4.3"
 
@@ -292,12 +569,21 @@ const (
 	// CRIContainerdConfig is the path to the config for the containerd instance that provides the CRI.
 	CRIContainerdConfig = "/etc/cri/containerd.toml"
 
+	// CRIConfdPath is the path to the directory with the additional CRI plugin config parts.
+	CRIConfdPath = "/etc/cri/conf.d"
+
+	// SSLCertsPath is the path to the directory with the trusted CA certificates.
+	SSLCertsPath = "/etc/ssl/certs"
+
 	// TalosConfigEnvVar is the environment variable for setting the Talos configuration file path.
 	TalosConfigEnvVar = "TALOSCONFIG"
 
 	// APISocketPath is the path to file socket of apid.
 	APISocketPath = SystemRunPath + "/apid/apid.sock"
 
+	// LocalAPISocketPath is the path to the local API socket exposed to the privileged workloads on the node.
+	LocalAPISocketPath = "/run/talos/api.sock"
+
 	// MachineSocketPath is the path to file socket of machine API.
 	MachineSocketPath = SystemRunPath + "/machined/machine.sock"
 
@@ -359,6 +645,18 @@ const (
 	// CustomCNI is the string to use custom CNI.
 	CustomCNI = "custom"
 
+	// NoneCNI is the string to skip deploying any CNI.
+	NoneCNI = "none"
+
+	// FlannelBackendVXLAN is the flannel VXLAN backend (the default one).
+	FlannelBackendVXLAN = "vxlan"
+
+	// FlannelBackendHostGW is the flannel backend which routes the pod traffic via the node IPs, nodes should share the L2 network.
+	FlannelBackendHostGW = "host-gw"
+
+	// FlannelBackendWireguard is the flannel backend which encrypts the pod traffic with WireGuard.
+	FlannelBackendWireguard = "wireguard"
+
 	// DefaultIPv4PodNet is the IPv4 network to be used for kubernetes Pods.
 	DefaultIPv4PodNet = "10.244.0.0/16"
 
@@ -387,8 +685,82 @@ const (
 	// NodeReadyTimeout is the timeout to wait for the node to be ready (CNI to be running).
 	// For bootstrap API, this includes time to run bootkube.
 	NodeReadyTimeout = BootkubeRunTimeout
+
+	// ConsoleModeNone disables Talos output to the console.
+	ConsoleModeNone = "none"
+
+	// ConsoleModeCritical limits console output to failures.
+	ConsoleModeCritical = "critical"
+
+	// ConsoleModeEvents limits console output to sequence, phase, task and service events.
+	ConsoleModeEvents = "events"
+
+	// ConsoleModeFull sends all machined logs to the console.
+	ConsoleModeFull = "full"
+
+	// DefaultConsoleMode is the default console output mode.
+	DefaultConsoleMode = ConsoleModeFull
+
+	// DefaultConsoleDevice is the device console output is written to.
+	DefaultConsoleDevice = "/dev/console"
+
+	// PersistentLogPath is the directory persistent service logs are written to (on the EPHEMERAL partition).
+	PersistentLogPath = "/var/log"
+
+	// DefaultPersistentLogMaxSize is the default size of the persistent service log file before it is rotated.
+	DefaultPersistentLogMaxSize = 5 * 1024 * 1024
+
+	// DefaultPersistentLogMaxFiles is the default number of rotated persistent log files kept for each service.
+	DefaultPersistentLogMaxFiles = 3
+
+	// DefaultBMCChannel is the default IPMI LAN channel of the BMC.
+	DefaultBMCChannel = 1
+
+	// DefaultCRIRuntimeHandler is the name of the default CRI runtime handler.
+	DefaultCRIRuntimeHandler = "runc"
+
+	// DefaultCRIRuntimeType is the default runtime type of the CRI runtime handlers.
+	DefaultCRIRuntimeType = "io.containerd.runc.v2"
+
+	// ISCSIDBinary is the path to the iscsid binary.
+	ISCSIDBinary = "/sbin/iscsid"
+
+	// ISCSIConfigPath is the path to the open-iscsi configuration and node database.
+	ISCSIConfigPath = "/etc/iscsi"
+
+	// ISCSIPersistentConfigPath is the writable directory bind mounted to ISCSIConfigPath.
+	ISCSIPersistentConfigPath = "/var/lib/iscsi"
+
+	// ISCSIDefaultInitiatorNamePrefix is the prefix of the generated iSCSI initiator name.
+	ISCSIDefaultInitiatorNamePrefix = "iqn.2005-03.org.open-iscsi"
+
+	// DefaultUserDiskFilesystem is the default filesystem of the user disk partitions and logical volumes.
+	DefaultUserDiskFilesystem = "xfs"
+
+	// DefaultSerialBaudRate is the default baud rate of the serial console.
+	DefaultSerialBaudRate = 115200
+
+	// NFSIdmapdConfig is the path to the generated idmapd.conf mounted into the kubelet.
+	NFSIdmapdConfig = SystemEtcPath + "/idmapd.conf"
+
+	// NFSMountConfig is the path to the generated nfsmount.conf mounted into the kubelet.
+	NFSMountConfig = SystemEtcPath + "/nfsmount.conf"
+
+	// MeasurementPCR is the TPM PCR extended with the measurements of the booted Talos installation.
+	MeasurementPCR = 11
+
+	// MeasurementLogPath is the path to the log of the boot measurements.
+	MeasurementLogPath = SystemRunPath + "/measurements.json"
 )
 
+// MachineFilesOverlayPaths is the list of the overlay mounted directories outside of /var
+// where the machine config files can be written.
+var MachineFilesOverlayPaths = []string{
+	ManifestsDirectory,
+	CRIConfdPath,
+	SSLCertsPath,
+}
+
 // See https://linux.die.net/man/3/klogctl
 //nolint: stylecheck
 const (
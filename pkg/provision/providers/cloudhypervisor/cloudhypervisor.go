@@ -0,0 +1,58 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package cloudhypervisor implements provisioner which runs Talos nodes as Cloud Hypervisor micro-VMs.
+package cloudhypervisor
+
+import (
+	"context"
+
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+	"github.com/talos-systems/talos/pkg/provision"
+	"github.com/talos-systems/talos/pkg/provision/providers/vm"
+)
+
+// Executable is the name of the Cloud Hypervisor binary.
+const Executable = "cloud-hypervisor"
+
+type provisioner struct {
+	vm.Provisioner
+}
+
+// NewProvisioner initializes cloud-hypervisor provisioner.
+func NewProvisioner(ctx context.Context) (provision.Provisioner, error) {
+	p := &provisioner{
+		vm.Provisioner{
+			Name: "cloud-hypervisor",
+		},
+	}
+
+	return p, nil
+}
+
+// Close and release resources.
+func (p *provisioner) Close() error {
+	return nil
+}
+
+// GenOptions provides a list of additional config generate options.
+func (p *provisioner) GenOptions(networkReq provision.NetworkRequest) []generate.GenOption {
+	return []generate.GenOption{
+		generate.WithInstallDisk("/dev/vda"),
+		generate.WithInstallExtraKernelArgs([]string{
+			"console=ttyS0",
+			// reboot configuration
+			"reboot=k",
+			"panic=1",
+			// Talos-specific
+			"talos.platform=metal",
+		}),
+	}
+}
+
+// GetLoadBalancers returns internal/external loadbalancer endpoints.
+func (p *provisioner) GetLoadBalancers(networkReq provision.NetworkRequest) (internalEndpoint, externalEndpoint string) {
+	// cloud-hypervisor runs loadbalancer on the bridge, which is good for both internal access, external access goes via round-robin
+	return networkReq.GatewayAddr.String(), ""
+}
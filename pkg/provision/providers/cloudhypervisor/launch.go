@@ -0,0 +1,201 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cloudhypervisor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+
+	"github.com/talos-systems/talos/pkg/provision/providers/vm"
+)
+
+// LaunchConfig is passed in to the Launch function over stdin.
+type LaunchConfig struct {
+	StatePath string
+
+	// VM options
+	DiskPaths           []string
+	VCPUCount           int64
+	MemSize             int64
+	KernelImagePath     string
+	InitrdPath          string
+	KernelArgs          string
+	BootloaderEmulation bool
+
+	// Talos config
+	Config string
+
+	// Network
+	vm.NetworkLaunchConfig
+
+	// filled by CNI invocation
+	tap vm.TapInterface
+
+	// signals
+	c chan os.Signal
+}
+
+// bootAssets returns kernel and initrd to boot the VM with.
+//
+// If bootloader emulation is enabled, and Talos is installed to the disk, assets
+// are extracted from the boot partition, otherwise assets from the launch config are used.
+func (config *LaunchConfig) bootAssets() (kernelPath, initrdPath string, cleanup func()) {
+	kernelPath, initrdPath, cleanup = config.KernelImagePath, config.InitrdPath, func() {}
+
+	if !config.BootloaderEmulation {
+		return
+	}
+
+	bootLoader, err := vm.NewBootLoader(config.DiskPaths[0])
+	if err != nil {
+		// print err but continue boot process
+		fmt.Fprintf(os.Stderr, "error initializing bootloader: %s\n", err)
+
+		return
+	}
+
+	assets, err := bootLoader.ExtractAssets()
+	if err != nil {
+		bootLoader.Close() //nolint: errcheck
+
+		fmt.Fprintf(os.Stderr, "error extracting kernel assets: %s\n", err)
+
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "successfully extracted boot assets from the disk image\n")
+
+	cleanup = func() {
+		bootLoader.Close() //nolint: errcheck
+	}
+
+	return assets.KernelPath, assets.InitrdPath, cleanup
+}
+
+// launchVM runs cloud-hypervisor with args built based on config.
+//
+// VM reboots are handled by cloud-hypervisor itself, launchVM returns when VM is powered off.
+func launchVM(config *LaunchConfig) error {
+	kernelPath, initrdPath, cleanup := config.bootAssets()
+	defer cleanup()
+
+	args := []string{
+		"--kernel", kernelPath,
+		"--initramfs", initrdPath,
+		"--cmdline", config.KernelArgs,
+		"--cpus", fmt.Sprintf("boot=%d", config.VCPUCount),
+		"--memory", fmt.Sprintf("size=%dM", config.MemSize),
+		"--net", fmt.Sprintf("tap=%s,mac=%s", config.tap.Name, config.tap.VMMAC),
+		"--rng", "src=/dev/urandom",
+		"--serial", "tty",
+		"--console", "off",
+	}
+
+	args = append(args, "--disk")
+
+	for _, disk := range config.DiskPaths {
+		args = append(args, fmt.Sprintf("path=%s", disk))
+	}
+
+	fmt.Fprintf(os.Stderr, "starting %s with args:\n%s\n", Executable, strings.Join(args, " "))
+	cmd := exec.Command(
+		Executable,
+		args...,
+	)
+
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := ns.WithNetNSPath(config.tap.NetNS.Path(), func(_ ns.NetNS) error {
+		return cmd.Start()
+	}); err != nil {
+		return err
+	}
+
+	done := make(chan error)
+
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case sig := <-config.c:
+		fmt.Fprintf(os.Stderr, "exiting VM as signal %s was received\n", sig)
+
+		if err := cmd.Process.Kill(); err != nil {
+			return fmt.Errorf("failed to kill process %w", err)
+		}
+
+		<-done
+
+		return fmt.Errorf("process stopped")
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("process exited with error %s", err)
+		}
+
+		// graceful exit
+		return nil
+	}
+}
+
+// Launch a control process around cloud-hypervisor VM manager.
+//
+// This function is invoked from 'talosctl cloud-hypervisor-launch' hidden command
+// and wraps starting, controlling and restarting 'cloud-hypervisor' VM process.
+//
+// Launch restarts VM forever until control process is stopped itself with a signal.
+//
+// Process is expected to receive configuration on stdin. Current working directory
+// should be cluster state directory, process output should be redirected to the
+// logfile in state directory.
+//
+// When signals SIGINT, SIGTERM are received, control process stops cloud-hypervisor and exits.
+func Launch() error {
+	var config LaunchConfig
+
+	ctx := context.Background()
+
+	if err := vm.ReadConfig(&config); err != nil {
+		return err
+	}
+
+	config.c = vm.ConfigureSignals()
+
+	httpServer, err := vm.NewHTTPServer(config.GatewayAddr, 0, []byte(config.Config), nil)
+	if err != nil {
+		return err
+	}
+
+	httpServer.Serve()
+	defer httpServer.Shutdown(ctx) //nolint: errcheck
+
+	// patch kernel args
+	config.KernelArgs = strings.ReplaceAll(config.KernelArgs, "{TALOS_CONFIG_URL}", fmt.Sprintf("http://%s/config.yaml", httpServer.GetAddr()))
+
+	return vm.WithCNI(ctx, config.StatePath, config.NetworkLaunchConfig, func(tap vm.TapInterface) error {
+		config.tap = tap
+
+		for {
+			if err := launchVM(&config); err != nil {
+				return err
+			}
+
+			select {
+			case sig := <-config.c:
+				fmt.Fprintf(os.Stderr, "exiting VM as signal %s was received\n", sig)
+
+				return fmt.Errorf("process stopped")
+			case <-time.After(500 * time.Millisecond): // wait a bit to prevent crash loop
+			}
+		}
+	})
+}
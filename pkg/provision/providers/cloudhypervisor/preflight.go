@@ -0,0 +1,50 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cloudhypervisor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/talos-systems/talos/pkg/cmd"
+	"github.com/talos-systems/talos/pkg/provision"
+	"github.com/talos-systems/talos/pkg/provision/providers/vm"
+)
+
+func (p *provisioner) preflightChecks(ctx context.Context, request provision.ClusterRequest, options provision.Options) error {
+	checkContext := preflightCheckContext{
+		PreflightChecks: vm.PreflightChecks{
+			Request: request,
+			Options: options,
+		},
+	}
+
+	for _, check := range []func(ctx context.Context) error{
+		checkContext.VerifyRoot,
+		checkContext.CheckKVM,
+		checkContext.cloudHypervisorExecutable,
+		checkContext.CNIDirectories,
+		checkContext.CNIBundle,
+		checkContext.CheckIptables,
+	} {
+		if err := check(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type preflightCheckContext struct {
+	vm.PreflightChecks
+}
+
+func (check *preflightCheckContext) cloudHypervisorExecutable(ctx context.Context) error {
+	if _, err := cmd.Run(Executable, "--version"); err != nil {
+		return fmt.Errorf("error running Cloud Hypervisor %q, please install it from https://github.com/cloud-hypervisor/cloud-hypervisor/releases: %w", Executable, err)
+	}
+
+	return nil
+}
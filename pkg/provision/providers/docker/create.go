@@ -61,6 +61,7 @@ func (p *provisioner) Create(ctx context.Context, request provision.ClusterReque
 				CIDR:        request.Network.CIDR,
 				GatewayAddr: request.Network.GatewayAddr,
 				MTU:         request.Network.MTU,
+				Existing:    request.Network.Existing,
 			},
 			Nodes: nodeInfo,
 		},
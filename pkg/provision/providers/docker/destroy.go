@@ -27,6 +27,10 @@ func (p *provisioner) Destroy(ctx context.Context, cluster provision.Cluster, op
 		return err
 	}
 
+	if cluster.Info().Network.Existing {
+		return nil
+	}
+
 	fmt.Println("destroying network", cluster.Info().Network.Name)
 
 	return p.destroyNetwork(ctx, cluster.Info().Network.Name)
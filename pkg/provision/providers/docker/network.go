@@ -19,7 +19,18 @@ import (
 
 // createNetwork will take a network request and check if a network with the same name + cidr exists.
 // If so, it simply returns without error and assumes we will re-use that network. Otherwise it will create a new one.
+//
+// If the request is for the existing network, the network is looked up by name and it is never created.
 func (p *provisioner) createNetwork(ctx context.Context, req provision.NetworkRequest) error {
+	if req.Existing {
+		existingNet, err := p.client.NetworkInspect(ctx, req.Name)
+		if err != nil {
+			return fmt.Errorf("error looking up existing network %q: %w", req.Name, err)
+		}
+
+		return checkNetworkCIDR(existingNet, req)
+	}
+
 	existingNet, err := p.listNetworks(ctx, req.Name)
 	if err != nil {
 		return err
@@ -27,11 +38,8 @@ func (p *provisioner) createNetwork(ctx context.Context, req provision.NetworkRe
 
 	// If named net already exists, see if we can reuse it
 	if len(existingNet) > 0 {
-		if existingNet[0].IPAM.Config[0].Subnet != req.CIDR.String() {
-			return fmt.Errorf("existing network has differing cidr: %s vs %s", existingNet[0].IPAM.Config[0].Subnet, req.CIDR.String())
-		}
 		// CIDRs match, we'll reuse
-		return nil
+		return checkNetworkCIDR(existingNet[0], req)
 	}
 
 	// Create new net
@@ -57,6 +65,18 @@ func (p *provisioner) createNetwork(ctx context.Context, req provision.NetworkRe
 	return err
 }
 
+func checkNetworkCIDR(existingNet types.NetworkResource, req provision.NetworkRequest) error {
+	if len(existingNet.IPAM.Config) == 0 {
+		return fmt.Errorf("existing network %q has no IPAM config", existingNet.Name)
+	}
+
+	if existingNet.IPAM.Config[0].Subnet != req.CIDR.String() {
+		return fmt.Errorf("existing network has differing cidr: %s vs %s", existingNet.IPAM.Config[0].Subnet, req.CIDR.String())
+	}
+
+	return nil
+}
+
 func (p *provisioner) listNetworks(ctx context.Context, name string) ([]types.NetworkResource, error) {
 	filters := filters.NewArgs()
 	filters.Add("label", "talos.owned=true")
@@ -83,6 +83,7 @@ func (p *provisioner) createNode(ctx context.Context, clusterReq provision.Clust
 			"talos.owned":        "true",
 			"talos.cluster.name": clusterReq.Name,
 			"talos.type":         nodeReq.Type.String(),
+			"talos.network.name": clusterReq.Network.Name,
 		},
 		Volumes: map[string]struct{}{
 			"/var/lib/containerd": {},
@@ -143,6 +144,10 @@ func (p *provisioner) createNode(ctx context.Context, clusterReq provision.Clust
 		networkConfig.EndpointsConfig[clusterReq.Network.Name].IPAMConfig = &network.EndpointIPAMConfig{IPv4Address: nodeReq.IP.String()}
 	}
 
+	if nodeReq.MAC != nil {
+		containerConfig.MacAddress = nodeReq.MAC.String()
+	}
+
 	// Create the container.
 	resp, err := p.client.ContainerCreate(ctx, containerConfig, hostConfig, networkConfig, nodeReq.Name)
 	if err != nil {
@@ -9,6 +9,8 @@ import (
 	"net"
 	"strconv"
 
+	"github.com/docker/docker/api/types"
+
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
 	"github.com/talos-systems/talos/pkg/provision"
 )
@@ -20,12 +22,31 @@ func (p *provisioner) Reflect(ctx context.Context, clusterName, stateDirectory s
 		},
 	}
 
+	// find nodes (containers)
+	nodes, err := p.listNodes(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
 	// find network assuming network name == cluster name
 	networks, err := p.listNetworks(ctx, clusterName)
 	if err != nil {
 		return nil, err
 	}
 
+	// network wasn't created for the cluster, so look up existing network by the name from the node labels
+	if len(networks) == 0 && len(nodes) > 0 && nodes[0].Labels["talos.network.name"] != "" {
+		var network types.NetworkResource
+
+		network, err = p.client.NetworkInspect(ctx, nodes[0].Labels["talos.network.name"])
+		if err != nil {
+			return nil, err
+		}
+
+		networks = append(networks, network)
+		res.clusterInfo.Network.Existing = true
+	}
+
 	if len(networks) > 0 {
 		network := networks[0]
 
@@ -40,20 +61,16 @@ func (p *provisioner) Reflect(ctx context.Context, clusterName, stateDirectory s
 		res.clusterInfo.Network.CIDR = *cidr
 		res.clusterInfo.Network.GatewayAddr = net.ParseIP(network.IPAM.Config[0].Gateway)
 
-		mtuStr := network.Options["com.docker.network.driver.mtu"]
-		res.clusterInfo.Network.MTU, err = strconv.Atoi(mtuStr)
+		// existing networks might not have MTU set explicitly
+		if mtuStr, ok := network.Options["com.docker.network.driver.mtu"]; ok || !res.clusterInfo.Network.Existing {
+			res.clusterInfo.Network.MTU, err = strconv.Atoi(mtuStr)
 
-		if err != nil {
-			return nil, err
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	// find nodes (containers)
-	nodes, err := p.listNodes(ctx, clusterName)
-	if err != nil {
-		return nil, err
-	}
-
 	for _, node := range nodes {
 		t, err := machine.ParseType(node.Labels["talos.type"])
 		if err != nil {
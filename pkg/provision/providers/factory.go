@@ -15,6 +15,8 @@ import (
 // Factory instantiates provision provider by name.
 func Factory(ctx context.Context, name string) (provision.Provisioner, error) {
 	switch name {
+	case "cloud-hypervisor":
+		return newCloudHypervisor(ctx)
 	case "docker":
 		return docker.NewProvisioner(ctx)
 	case "firecracker":
@@ -46,7 +46,7 @@ func (p *provisioner) Create(ctx context.Context, request provision.ClusterReque
 	fmt.Fprintln(options.LogWriter, "uncompressing kernel")
 
 	tempKernelPath := state.GetRelativePath("vmlinux")
-	if err = uncompressKernel(request.KernelPath, tempKernelPath); err != nil {
+	if err = vm.UncompressKernel(request.KernelPath, tempKernelPath); err != nil {
 		return nil, err
 	}
 
@@ -89,6 +89,7 @@ func (p *provisioner) Create(ctx context.Context, request provision.ClusterReque
 			CIDR:        request.Network.CIDR,
 			GatewayAddr: request.Network.GatewayAddr,
 			MTU:         request.Network.MTU,
+			Existing:    request.Network.Existing,
 		},
 		Nodes: nodeInfo,
 	}
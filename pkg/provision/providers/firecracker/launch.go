@@ -68,21 +68,21 @@ func Launch() error {
 		err := func() error {
 			var (
 				err        error
-				bootLoader *BootLoader
+				bootLoader *vm.BootLoader
 			)
 
 			// reset kernel/initrd assets to default values
 			// bootloader (if enabled) might overwrite them with extracted assets
 			config.FirecrackerConfig.KernelImagePath, config.FirecrackerConfig.InitrdPath = origKernelImagePath, origInitrdPath
 
-			bootLoader, err = NewBootLoader(*config.FirecrackerConfig.Drives[0].PathOnHost)
+			bootLoader, err = vm.NewBootLoader(*config.FirecrackerConfig.Drives[0].PathOnHost)
 			if err != nil {
 				// print err but continue boot process
 				fmt.Fprintf(os.Stderr, "error initializing bootloader: %s\n", err.Error())
 			} else {
 				defer bootLoader.Close() //nolint: errcheck
 
-				var assets BootAssets
+				var assets vm.BootAssets
 
 				assets, err = bootLoader.ExtractAssets()
 				if err != nil {
@@ -59,10 +59,12 @@ func (p *provisioner) Create(ctx context.Context, request provision.ClusterReque
 		return nil, fmt.Errorf("error creating loadbalancer: %w", err)
 	}
 
-	fmt.Fprintln(options.LogWriter, "creating dhcpd")
+	if !request.Network.SkipDHCP {
+		fmt.Fprintln(options.LogWriter, "creating dhcpd")
 
-	if err = p.CreateDHCPd(state, request); err != nil {
-		return nil, fmt.Errorf("error creating dhcpd: %w", err)
+		if err = p.CreateDHCPd(state, request); err != nil {
+			return nil, fmt.Errorf("error creating dhcpd: %w", err)
+		}
 	}
 
 	var nodeInfo []provision.NodeInfo
@@ -101,6 +103,7 @@ func (p *provisioner) Create(ctx context.Context, request provision.ClusterReque
 			CIDR:        request.Network.CIDR,
 			GatewayAddr: request.Network.GatewayAddr,
 			MTU:         request.Network.MTU,
+			Existing:    request.Network.Existing,
 		},
 		Nodes:      nodeInfo,
 		ExtraNodes: pxeNodeInfo,
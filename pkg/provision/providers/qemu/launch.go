@@ -8,22 +8,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"net"
 	"os"
 	"os/exec"
 	"strconv"
 	"strings"
 
-	"github.com/containernetworking/cni/libcni"
-	"github.com/containernetworking/cni/pkg/types/current"
 	"github.com/containernetworking/plugins/pkg/ns"
-	"github.com/containernetworking/plugins/pkg/testutils"
 	"github.com/google/uuid"
 	"github.com/talos-systems/go-blockdevice/blockdevice/partition/gpt"
 
-	"github.com/talos-systems/talos/pkg/provision"
-	"github.com/talos-systems/talos/pkg/provision/internal/cniutils"
 	"github.com/talos-systems/talos/pkg/provision/providers/vm"
 )
 
@@ -50,27 +43,16 @@ type LaunchConfig struct {
 	Config string
 
 	// Network
-	NetworkConfig *libcni.NetworkConfigList
-	CNI           provision.CNIConfig
-	IP            net.IP
-	CIDR          net.IPNet
-	Hostname      string
-	GatewayAddr   net.IP
-	MTU           int
-	Nameservers   []net.IP
+	vm.NetworkLaunchConfig
 
 	// PXE
-	TFTPServer       string
-	BootFilename     string
-	IPXEBootFileName string
+	BootFilename string
 
 	// API
 	APIPort int
 
 	// filled by CNI invocation
-	tapName string
-	vmMAC   string
-	ns      ns.NetNS
+	tap vm.TapInterface
 
 	// signals
 	c chan os.Signal
@@ -79,88 +61,6 @@ type LaunchConfig struct {
 	controller *Controller
 }
 
-// withCNI creates network namespace, launches CNI and passes control to the next function
-// filling config with netNS and interface details.
-func withCNI(ctx context.Context, config *LaunchConfig, f func(config *LaunchConfig) error) error {
-	// random ID for the CNI, maps to single VM
-	containerID := uuid.New().String()
-
-	cniConfig := libcni.NewCNIConfigWithCacheDir(config.CNI.BinPath, config.CNI.CacheDir, nil)
-
-	// create a network namespace
-	ns, err := testutils.NewNS()
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		ns.Close()              //nolint: errcheck
-		testutils.UnmountNS(ns) //nolint: errcheck
-	}()
-
-	ones, _ := config.CIDR.Mask.Size()
-	runtimeConf := libcni.RuntimeConf{
-		ContainerID: containerID,
-		NetNS:       ns.Path(),
-		IfName:      "veth0",
-		Args: [][2]string{
-			{"IP", fmt.Sprintf("%s/%d", config.IP, ones)},
-			{"GATEWAY", config.GatewayAddr.String()},
-		},
-	}
-
-	// attempt to clean up network in case it was deployed previously
-	err = cniConfig.DelNetworkList(ctx, config.NetworkConfig, &runtimeConf)
-	if err != nil {
-		return fmt.Errorf("error deleting CNI network: %w", err)
-	}
-
-	res, err := cniConfig.AddNetworkList(ctx, config.NetworkConfig, &runtimeConf)
-	if err != nil {
-		return fmt.Errorf("error provisioning CNI network: %w", err)
-	}
-
-	defer func() {
-		if e := cniConfig.DelNetworkList(ctx, config.NetworkConfig, &runtimeConf); e != nil {
-			log.Printf("error cleaning up CNI: %s", e)
-		}
-	}()
-
-	currentResult, err := current.NewResultFromResult(res)
-	if err != nil {
-		return fmt.Errorf("failed to parse cni result: %w", err)
-	}
-
-	vmIface, tapIface, err := cniutils.VMTapPair(currentResult, containerID)
-	if err != nil {
-		return fmt.Errorf(
-			"failed to parse VM network configuration from CNI output, ensure CNI is configured with a plugin " +
-				"that supports automatic VM network configuration such as tc-redirect-tap",
-		)
-	}
-
-	config.tapName = tapIface.Name
-	config.vmMAC = vmIface.Mac
-	config.ns = ns
-
-	// dump node IP/mac/hostname for dhcp
-	if err = vm.DumpIPAMRecord(config.StatePath, vm.IPAMRecord{
-		IP:               config.IP,
-		Netmask:          config.CIDR.Mask,
-		MAC:              vmIface.Mac,
-		Hostname:         config.Hostname,
-		Gateway:          config.GatewayAddr,
-		MTU:              config.MTU,
-		Nameservers:      config.Nameservers,
-		TFTPServer:       config.TFTPServer,
-		IPXEBootFilename: config.IPXEBootFileName,
-	}); err != nil {
-		return err
-	}
-
-	return f(config)
-}
-
 func checkPartitions(config *LaunchConfig) (bool, error) {
 	disk, err := os.Open(config.DiskPaths[0])
 	if err != nil {
@@ -200,8 +100,8 @@ func launchVM(config *LaunchConfig) error {
 		"-smp", fmt.Sprintf("cpus=%d", config.VCPUCount),
 		"-cpu", "max",
 		"-nographic",
-		"-netdev", fmt.Sprintf("tap,id=net0,ifname=%s,script=no,downscript=no", config.tapName),
-		"-device", fmt.Sprintf("virtio-net-pci,netdev=net0,mac=%s", config.vmMAC),
+		"-netdev", fmt.Sprintf("tap,id=net0,ifname=%s,script=no,downscript=no", config.tap.Name),
+		"-device", fmt.Sprintf("virtio-net-pci,netdev=net0,mac=%s", config.tap.VMMAC),
 		// TODO: uncomment the following line to get another eth interface not connected to anything
 		// "-nic", "tap,model=virtio-net-pci",
 		"-device", "virtio-rng-pci",
@@ -259,7 +159,7 @@ func launchVM(config *LaunchConfig) error {
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	if err := ns.WithNetNSPath(config.ns.Path(), func(_ ns.NetNS) error {
+	if err := ns.WithNetNSPath(config.tap.NetNS.Path(), func(_ ns.NetNS) error {
 		return cmd.Start()
 	}); err != nil {
 		return err
@@ -341,7 +241,9 @@ func Launch() error {
 	// patch kernel args
 	config.KernelArgs = strings.ReplaceAll(config.KernelArgs, "{TALOS_CONFIG_URL}", fmt.Sprintf("http://%s/config.yaml", httpServer.GetAddr()))
 
-	return withCNI(ctx, &config, func(config *LaunchConfig) error {
+	return vm.WithCNI(ctx, config.StatePath, config.NetworkLaunchConfig, func(tap vm.TapInterface) error {
+		config.tap = tap
+
 		for {
 			for config.controller.PowerState() != PoweredOn {
 				select {
@@ -354,7 +256,7 @@ func Launch() error {
 				}
 			}
 
-			if err := launchVM(config); err != nil {
+			if err := launchVM(&config); err != nil {
 				return err
 			}
 		}
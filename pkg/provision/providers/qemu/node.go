@@ -117,17 +117,20 @@ func (p *provisioner) createNode(state *vm.State, clusterReq provision.ClusterRe
 		BootloaderEnabled: opts.BootloaderEnabled,
 		NodeUUID:          nodeUUID,
 		Config:            nodeConfig,
-		NetworkConfig:     state.VMCNIConfig,
-		CNI:               clusterReq.Network.CNI,
-		CIDR:              clusterReq.Network.CIDR,
-		IP:                nodeReq.IP,
-		Hostname:          nodeReq.Name,
-		GatewayAddr:       clusterReq.Network.GatewayAddr,
-		MTU:               clusterReq.Network.MTU,
-		Nameservers:       clusterReq.Network.Nameservers,
-		TFTPServer:        nodeReq.TFTPServer,
-		IPXEBootFileName:  nodeReq.IPXEBootFilename,
-		APIPort:           apiPort,
+		NetworkLaunchConfig: vm.NetworkLaunchConfig{
+			NetworkConfig:    state.VMCNIConfig,
+			CNI:              clusterReq.Network.CNI,
+			CIDR:             clusterReq.Network.CIDR,
+			IP:               nodeReq.IP,
+			MAC:              nodeReq.MAC.String(),
+			Hostname:         nodeReq.Name,
+			GatewayAddr:      clusterReq.Network.GatewayAddr,
+			MTU:              clusterReq.Network.MTU,
+			Nameservers:      clusterReq.Network.Nameservers,
+			TFTPServer:       nodeReq.TFTPServer,
+			IPXEBootFileName: nodeReq.IPXEBootFilename,
+		},
+		APIPort: apiPort,
 	}
 
 	if !nodeReq.PXEBooted {
@@ -0,0 +1,136 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/containernetworking/cni/libcni"
+	"github.com/containernetworking/cni/pkg/types/current"
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/containernetworking/plugins/pkg/testutils"
+	"github.com/google/uuid"
+
+	"github.com/talos-systems/talos/pkg/provision"
+	"github.com/talos-systems/talos/pkg/provision/internal/cniutils"
+)
+
+// NetworkLaunchConfig describes the VM network passed to the VM launcher.
+type NetworkLaunchConfig struct {
+	NetworkConfig *libcni.NetworkConfigList
+	CNI           provision.CNIConfig
+	IP            net.IP
+	MAC           string
+	CIDR          net.IPNet
+	Hostname      string
+	GatewayAddr   net.IP
+	MTU           int
+	Nameservers   []net.IP
+
+	// PXE
+	TFTPServer       string
+	IPXEBootFileName string
+}
+
+// TapInterface describes the tap interface provisioned for the VM by the CNI.
+type TapInterface struct {
+	Name  string
+	VMMAC string
+	NetNS ns.NetNS
+}
+
+// WithCNI creates network namespace, launches CNI and passes control to the next function
+// with the details of the tap interface to be used by the VM.
+//
+// Node IP/MAC/hostname are recorded to the IPAM database in the state directory, so that DHCP server can serve them.
+func WithCNI(ctx context.Context, statePath string, config NetworkLaunchConfig, f func(tap TapInterface) error) error {
+	// random ID for the CNI, maps to single VM
+	containerID := uuid.New().String()
+
+	cniConfig := libcni.NewCNIConfigWithCacheDir(config.CNI.BinPath, config.CNI.CacheDir, nil)
+
+	// create a network namespace
+	ns, err := testutils.NewNS()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		ns.Close()              //nolint: errcheck
+		testutils.UnmountNS(ns) //nolint: errcheck
+	}()
+
+	ones, _ := config.CIDR.Mask.Size()
+	runtimeConf := libcni.RuntimeConf{
+		ContainerID: containerID,
+		NetNS:       ns.Path(),
+		IfName:      "veth0",
+		Args: [][2]string{
+			{"IP", fmt.Sprintf("%s/%d", config.IP, ones)},
+			{"GATEWAY", config.GatewayAddr.String()},
+		},
+	}
+
+	// attempt to clean up network in case it was deployed previously
+	err = cniConfig.DelNetworkList(ctx, config.NetworkConfig, &runtimeConf)
+	if err != nil {
+		return fmt.Errorf("error deleting CNI network: %w", err)
+	}
+
+	res, err := cniConfig.AddNetworkList(ctx, config.NetworkConfig, &runtimeConf)
+	if err != nil {
+		return fmt.Errorf("error provisioning CNI network: %w", err)
+	}
+
+	defer func() {
+		if e := cniConfig.DelNetworkList(ctx, config.NetworkConfig, &runtimeConf); e != nil {
+			log.Printf("error cleaning up CNI: %s", e)
+		}
+	}()
+
+	currentResult, err := current.NewResultFromResult(res)
+	if err != nil {
+		return fmt.Errorf("failed to parse cni result: %w", err)
+	}
+
+	vmIface, tapIface, err := cniutils.VMTapPair(currentResult, containerID)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to parse VM network configuration from CNI output, ensure CNI is configured with a plugin " +
+				"that supports automatic VM network configuration such as tc-redirect-tap",
+		)
+	}
+
+	tap := TapInterface{
+		Name:  tapIface.Name,
+		VMMAC: vmIface.Mac,
+		NetNS: ns,
+	}
+
+	// static MAC overrides the one picked by the CNI
+	if config.MAC != "" {
+		tap.VMMAC = config.MAC
+	}
+
+	// dump node IP/mac/hostname for dhcp
+	if err = DumpIPAMRecord(statePath, IPAMRecord{
+		IP:               config.IP,
+		Netmask:          config.CIDR.Mask,
+		MAC:              tap.VMMAC,
+		Hostname:         config.Hostname,
+		Gateway:          config.GatewayAddr,
+		MTU:              config.MTU,
+		Nameservers:      config.Nameservers,
+		TFTPServer:       config.TFTPServer,
+		IPXEBootFilename: config.IPXEBootFileName,
+	}); err != nil {
+		return err
+	}
+
+	return f(tap)
+}
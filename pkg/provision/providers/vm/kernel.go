@@ -2,7 +2,7 @@
 // License, v. 2.0. If a copy of the MPL was not distributed with this
 // file, You can obtain one at http://mozilla.org/MPL/2.0/.
 
-package firecracker
+package vm
 
 import (
 	"bufio"
@@ -13,7 +13,10 @@ import (
 	"github.com/talos-systems/talos/pkg/provision/internal/vmlinuz"
 )
 
-func uncompressKernel(srcKernelPath, dstKernelPath string) error {
+// UncompressKernel extracts compressed kernel (vmlinuz) into uncompressed image (vmlinux).
+//
+// Microvm monitors (Firecracker, Cloud Hypervisor) boot uncompressed kernel images.
+func UncompressKernel(srcKernelPath, dstKernelPath string) error {
 	srcF, err := os.Open(srcKernelPath)
 	if err != nil {
 		return fmt.Errorf("failed to open kernel asset %q: %w", srcKernelPath, err)
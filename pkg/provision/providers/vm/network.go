@@ -26,7 +26,20 @@ import (
 // CreateNetwork build bridge interface name by taking part of checksum of the network name
 // so that interface name is defined by network name, and different networks have
 // different bridge interfaces.
+//
+// If the network request is for the existing network, network name is used as the bridge interface name,
+// and the bridge interface is expected to have gateway address assigned.
 func (p *Provisioner) CreateNetwork(ctx context.Context, state *State, network provision.NetworkRequest) error {
+	if network.Existing {
+		state.BridgeName = network.Name
+
+		if err := checkExistingBridge(network); err != nil {
+			return err
+		}
+
+		return p.createVMCNIConfig(state, network)
+	}
+
 	networkNameHash := sha256.Sum256([]byte(network.Name))
 	state.BridgeName = fmt.Sprintf("%s%s", "talos", hex.EncodeToString(networkNameHash[:])[:8])
 
@@ -93,19 +106,26 @@ func (p *Provisioner) CreateNetwork(ctx context.Context, state *State, network p
 		return fmt.Errorf("error deleting bridge CNI network: %w", err)
 	}
 
-	// prepare an actual network config to be used by the VMs
-	t = template.Must(template.New("network").Parse(networkTemplate))
+	return p.createVMCNIConfig(state, network)
+}
 
-	buf.Reset()
+// createVMCNIConfig prepares an actual network config to be used by the VMs.
+func (p *Provisioner) createVMCNIConfig(state *State, network provision.NetworkRequest) error {
+	t := template.Must(template.New("network").Parse(networkTemplate))
 
-	err = t.Execute(&buf, struct {
+	var buf bytes.Buffer
+
+	err := t.Execute(&buf, struct {
 		NetworkName   string
 		InterfaceName string
 		MTU           string
+		IPMasq        bool
 	}{
 		NetworkName:   network.Name,
 		InterfaceName: state.BridgeName,
 		MTU:           strconv.Itoa(network.MTU),
+		// existing networks are expected to handle routing on their own
+		IPMasq: !network.Existing,
 	})
 	if err != nil {
 		return fmt.Errorf("error templating VM CNI config: %w", err)
@@ -118,8 +138,36 @@ func (p *Provisioner) CreateNetwork(ctx context.Context, state *State, network p
 	return nil
 }
 
+// checkExistingBridge verifies that the existing bridge interface has the gateway address assigned,
+// as the gateway address is used to run load balancer and other services for the VMs.
+func checkExistingBridge(network provision.NetworkRequest) error {
+	iface, err := net.InterfaceByName(network.Name)
+	if err != nil {
+		return fmt.Errorf("error looking up bridge interface %q: %w", network.Name, err)
+	}
+
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return fmt.Errorf("error listing addresses of the bridge interface %q: %w", network.Name, err)
+	}
+
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(network.GatewayAddr) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("bridge interface %q doesn't have gateway address %s assigned", network.Name, network.GatewayAddr)
+}
+
 // DestroyNetwork destroy bridge interface by name to clean up.
+//
+// Existing bridge interfaces are left untouched.
 func (p *Provisioner) DestroyNetwork(state *State) error {
+	if state.ClusterInfo.Network.Existing {
+		return nil
+	}
+
 	iface, err := net.InterfaceByName(state.BridgeName)
 	if err != nil {
 		return fmt.Errorf("error looking up bridge interface %q: %w", state.BridgeName, err)
@@ -161,7 +209,7 @@ const networkTemplate = `
 		{
 			"type": "bridge",
 			"bridge": "{{ .InterfaceName }}",
-			"ipMasq": true,
+			"ipMasq": {{ .IPMasq }},
 			"isGateway": true,
 			"isDefaultGateway": true,
 			"ipam": {
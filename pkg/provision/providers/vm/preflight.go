@@ -0,0 +1,132 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/coreos/go-iptables/iptables"
+	"github.com/hashicorp/go-getter"
+
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+	"github.com/talos-systems/talos/pkg/provision"
+)
+
+// PreflightChecks implements preflight checks common for VM-based provisioners.
+type PreflightChecks struct {
+	Request provision.ClusterRequest
+	Options provision.Options
+}
+
+// VerifyRoot checks that the provisioner runs as root.
+func (check *PreflightChecks) VerifyRoot(ctx context.Context) error {
+	if os.Geteuid() != 0 {
+		return fmt.Errorf("error: please run as root user (CNI requirement), we recommend running with `sudo -E`")
+	}
+
+	return nil
+}
+
+// CheckKVM checks that KVM is available.
+func (check *PreflightChecks) CheckKVM(ctx context.Context) error {
+	f, err := os.OpenFile("/dev/kvm", os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("error opening /dev/kvm, please make sure KVM support is enabled in Linux kernel: %w", err)
+	}
+
+	return f.Close()
+}
+
+// CNIDirectories creates CNI directories if they don't exist.
+func (check *PreflightChecks) CNIDirectories(ctx context.Context) error {
+	cniDirs := append(check.Request.Network.CNI.BinPath, check.Request.Network.CNI.CacheDir, check.Request.Network.CNI.ConfDir)
+
+	for _, cniDir := range cniDirs {
+		st, err := os.Stat(cniDir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return fmt.Errorf("error checking CNI directory %q: %w", cniDir, err)
+			}
+
+			fmt.Printf("creating %q\n", cniDir)
+
+			err = os.MkdirAll(cniDir, 0o777)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !st.IsDir() {
+			return fmt.Errorf("CNI path %q exists, but it's not a directory", cniDir)
+		}
+	}
+
+	return nil
+}
+
+// CNIBundle checks that the required CNI plugins are installed, downloading the CNI bundle if necessary.
+func (check *PreflightChecks) CNIBundle(ctx context.Context) error {
+	var missing bool
+
+	requiredCNIPlugins := []string{"bridge", "firewall", "static", "tc-redirect-tap"}
+
+	for _, cniPlugin := range requiredCNIPlugins {
+		missing = true
+
+		for _, binPath := range check.Request.Network.CNI.BinPath {
+			_, err := os.Stat(filepath.Join(binPath, cniPlugin))
+			if err == nil {
+				missing = false
+
+				break
+			}
+		}
+
+		if missing {
+			break
+		}
+	}
+
+	if !missing {
+		return nil
+	}
+
+	if check.Request.Network.CNI.BundleURL == "" {
+		return fmt.Errorf("error: required CNI plugins %q were not found in %q", requiredCNIPlugins, check.Request.Network.CNI.BinPath)
+	}
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	client := getter.Client{
+		Ctx:  ctx,
+		Src:  strings.ReplaceAll(check.Request.Network.CNI.BundleURL, constants.ArchVariable, check.Options.TargetArch),
+		Dst:  check.Request.Network.CNI.BinPath[0],
+		Pwd:  pwd,
+		Mode: getter.ClientModeDir,
+	}
+
+	fmt.Printf("downloading CNI bundle from %q to %q\n", client.Src, client.Dst)
+
+	return client.Get()
+}
+
+// CheckIptables checks that iptables are accessible.
+func (check *PreflightChecks) CheckIptables(ctx context.Context) error {
+	_, err := iptables.New()
+	if err != nil {
+		return fmt.Errorf("error accessing iptables: %w", err)
+	}
+
+	return nil
+}
@@ -50,6 +50,18 @@ type NetworkRequest struct {
 	MTU         int
 	Nameservers []net.IP
 
+	// Existing network is attached to instead of creating a new one.
+	//
+	// For Docker, Name is the name of the existing Docker network,
+	// for VM provisioners, Name is the name of the existing bridge interface
+	// with GatewayAddr assigned.
+	// Existing network is not removed when the cluster is destroyed.
+	Existing bool
+
+	// SkipDHCP disables DHCP server for the nodes (VM provisioners),
+	// the network is expected to provide DHCP on its own.
+	SkipDHCP bool
+
 	// CNI-specific parameters.
 	CNI CNIConfig
 }
@@ -130,6 +142,7 @@ type Disk struct {
 type NodeRequest struct {
 	Name   string
 	IP     net.IP
+	MAC    net.HardwareAddr
 	Config config.Provider
 	Type   machine.Type
 
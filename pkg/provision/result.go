@@ -39,6 +39,9 @@ type NetworkInfo struct {
 	CIDR        net.IPNet
 	GatewayAddr net.IP
 	MTU         int
+
+	// Existing network wasn't created by the provisioner, so it's not removed with the cluster.
+	Existing bool
 }
 
 // NodeInfo describes a node.
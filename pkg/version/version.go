@@ -35,6 +35,14 @@ const versionTemplate = `	Tag:         {{ .Tag }}
 	OS/Arch:     {{ .Os }}/{{ .Arch }}
 `
 
+const systemInfoTemplate = `	Hostname:    {{ .Hostname }}
+	Machine ID:  {{ .MachineId }}
+	Platform:    {{ with .Platform }}{{ .Name }} ({{ .Mode }}){{ end }}
+	Kernel:      {{ .KernelVersion }}
+	Model:       {{ with .Hardware }}{{ .Manufacturer }} {{ .ProductName }}{{ end }}
+	SecureBoot:  {{ with .SecureBoot }}{{ if not .Efi }}n/a (legacy boot){{ else if .Enabled }}enabled{{ else if .SetupMode }}disabled (setup mode){{ else }}disabled{{ end }}{{ end }}
+`
+
 // NewVersion prints verbose version information.
 func NewVersion() *machineapi.VersionInfo {
 	return &machineapi.VersionInfo{
@@ -57,6 +65,30 @@ func PrintLongVersionFromExisting(v *machineapi.VersionInfo) {
 	printLong(v)
 }
 
+// PrintSystemInfo prints verbose version information followed by the system information.
+func PrintSystemInfo(info *machineapi.SystemInfo) {
+	var wr bytes.Buffer
+
+	for _, t := range []struct {
+		text string
+		data interface{}
+	}{
+		{versionTemplate, info.Version},
+		{systemInfoTemplate, info},
+	} {
+		tmpl, err := template.New("version").Parse(t.text)
+		if err != nil {
+			return
+		}
+
+		if err = tmpl.Execute(&wr, t.data); err != nil {
+			return
+		}
+	}
+
+	fmt.Println(wr.String())
+}
+
 func printLong(v *machineapi.VersionInfo) {
 	var wr bytes.Buffer
 